@@ -0,0 +1,179 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultEventAckTimeout is the default time, in seconds, after which an
+	// event not acknowledged by the backend is sent again.
+	DefaultEventAckTimeout = 30
+
+	// maxPendingEventAcks is the maximum number of events kept until the
+	// backend acknowledges them. The oldest event is dropped when it is
+	// reached.
+	maxPendingEventAcks = 1000
+
+	// eventAckCheckInterval is the time between the checks for the events to
+	// send again.
+	eventAckCheckInterval = time.Second
+)
+
+// pendingEvent is an event not acknowledged by the backend yet.
+type pendingEvent struct {
+	payload []byte
+	sent    time.Time
+
+	// callback is the send callback of the event message, called once the
+	// event is acknowledged, or dropped.
+	callback func(error)
+}
+
+// eventAcks keeps the events sent to the backend until it acknowledges them,
+// and tells which ones to send again. It is safe for concurrent use.
+type eventAcks struct {
+	mu      sync.Mutex
+	pending map[string]*pendingEvent
+	timeout time.Duration
+}
+
+func newEventAcks(timeout time.Duration) *eventAcks {
+	return &eventAcks{
+		pending: make(map[string]*pendingEvent),
+		timeout: timeout,
+	}
+}
+
+// track returns the acked_event message of the event message, and keeps the
+// event until it is acknowledged. The send callback of the event message is
+// only called once the event is acknowledged, so the events of the spool are
+// spooled again if they are dropped.
+func (e *eventAcks) track(msg *transport.Message, now time.Time) *transport.Message {
+	id := uuid.New().String()
+
+	e.mu.Lock()
+	var dropped *pendingEvent
+	if len(e.pending) >= maxPendingEventAcks {
+		dropped = e.dropOldest()
+	}
+	e.pending[id] = &pendingEvent{payload: msg.Payload, sent: now, callback: msg.SendCallback}
+	e.mu.Unlock()
+
+	if dropped != nil && dropped.callback != nil {
+		dropped.callback(errors.New("too many events waiting for acknowledgement"))
+	}
+	return transport.NewMessage(transport.MessageTypeAckedEvent, transport.NewAckedEventPayload(id, msg.Payload))
+}
+
+// dropOldest drops the event sent the longest time ago, and returns it.
+func (e *eventAcks) dropOldest() *pendingEvent {
+	var oldest string
+	for id, event := range e.pending {
+		if oldest == "" || event.sent.Before(e.pending[oldest].sent) {
+			oldest = id
+		}
+	}
+	dropped := e.pending[oldest]
+	delete(e.pending, oldest)
+	logger.WithField("max_pending", maxPendingEventAcks).Warn("too many events waiting for acknowledgement, dropping the oldest")
+	return dropped
+}
+
+// ack removes the acknowledged event, or, if the backend could not publish
+// it, marks it to be sent again. The events rejected by the backend are
+// dropped, since sending them again can not succeed.
+func (e *eventAcks) ack(ack transport.EventAck) {
+	if ack.Rejected {
+		logger.WithFields(logrus.Fields{
+			"id":    ack.ID,
+			"error": ack.Error,
+		}).Error("backend rejected event, dropping it")
+		e.reject(ack.ID)
+		return
+	}
+
+	e.mu.Lock()
+	event, ok := e.pending[ack.ID]
+	if ok && ack.Error == "" {
+		delete(e.pending, ack.ID)
+	} else if ok {
+		event.sent = time.Time{}
+	}
+	e.mu.Unlock()
+
+	switch {
+	case !ok:
+	case ack.Error != "":
+		logger.WithFields(logrus.Fields{
+			"id":    ack.ID,
+			"error": ack.Error,
+		}).Warn("backend could not publish event, sending it again")
+	case event.callback != nil:
+		event.callback(nil)
+	}
+}
+
+// reject removes the event rejected by the backend. Sending the event again
+// can not succeed, so its send callback is called as if it was acknowledged.
+func (e *eventAcks) reject(id string) {
+	e.mu.Lock()
+	event, ok := e.pending[id]
+	delete(e.pending, id)
+	e.mu.Unlock()
+
+	if ok && event.callback != nil {
+		event.callback(nil)
+	}
+}
+
+// due returns the acked_event messages of the events to send again: the
+// events not acknowledged within the timeout, and the events the backend
+// could not publish, or all the events if all is true. They are considered
+// sent at the given time.
+func (e *eventAcks) due(now time.Time, all bool) []*transport.Message {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var msgs []*transport.Message
+	for id, event := range e.pending {
+		if !all && now.Sub(event.sent) < e.timeout {
+			continue
+		}
+		event.sent = now
+		msgs = append(msgs, transport.NewMessage(transport.MessageTypeAckedEvent, transport.NewAckedEventPayload(id, event.payload)))
+	}
+	return msgs
+}
+
+// handleEventAck is the event ack message handler.
+func (a *Agent) handleEventAck(ctx context.Context, payload []byte) error {
+	var ack transport.EventAck
+	if err := json.Unmarshal(payload, &ack); err != nil {
+		return err
+	}
+	a.eventAcks.ack(ack)
+	return nil
+}
+
+// resendEvents sends again the events not acknowledged by the backend in time,
+// or all of them if all is true.
+func (a *Agent) resendEvents(conn transport.Transport, all bool) error {
+	msgs := a.eventAcks.due(time.Now(), all)
+	if len(msgs) > 0 {
+		logger.WithField("events", len(msgs)).Info("sending unacknowledged events again")
+	}
+	for _, msg := range msgs {
+		if err := conn.Send(msg); err != nil {
+			logger.WithError(err).Error("error sending message over websocket")
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ackedEventID returns the ID of the acked_event message, and checks its event.
+func ackedEventID(t *testing.T, msg *transport.Message, event string) string {
+	t.Helper()
+	require.Equal(t, transport.MessageTypeAckedEvent, msg.Type)
+	id, payload, err := transport.SplitAckedEventPayload(msg.Payload)
+	require.NoError(t, err)
+	assert.Equal(t, event, string(payload))
+	return id
+}
+
+func TestEventAcks(t *testing.T) {
+	acks := newEventAcks(30 * time.Second)
+	now := time.Now()
+
+	var callbackErrs []error
+	msg := transport.NewMessage(transport.MessageTypeEvent, []byte("event"))
+	msg.SendCallback = func(err error) {
+		callbackErrs = append(callbackErrs, err)
+	}
+	id := ackedEventID(t, acks.track(msg, now), "event")
+
+	// The event is only sent again once the timeout expires
+	assert.Empty(t, acks.due(now.Add(10*time.Second), false))
+	due := acks.due(now.Add(30*time.Second), false)
+	require.Len(t, due, 1)
+	assert.Equal(t, id, ackedEventID(t, due[0], "event"))
+
+	// The events the backend could not publish are sent again at once
+	acks.ack(transport.EventAck{ID: id, Error: "publish buffer full"})
+	assert.Len(t, acks.due(now.Add(31*time.Second), false), 1)
+	assert.Empty(t, callbackErrs)
+
+	// All the events are sent again after reconnecting
+	assert.Len(t, acks.due(now.Add(32*time.Second), true), 1)
+
+	// The send callback is called once the event is acknowledged
+	acks.ack(transport.EventAck{ID: id})
+	assert.Equal(t, []error{nil}, callbackErrs)
+	assert.Empty(t, acks.due(now.Add(time.Hour), true))
+
+	// Unknown acks are ignored
+	acks.ack(transport.EventAck{ID: id})
+	assert.Len(t, callbackErrs, 1)
+
+	// The events rejected by the backend are dropped instead of being sent
+	// again
+	id = ackedEventID(t, acks.track(msg, now), "event")
+	acks.ack(transport.EventAck{ID: id, Error: "invalid event", Rejected: true})
+	assert.Equal(t, []error{nil, nil}, callbackErrs)
+	assert.Empty(t, acks.due(now.Add(time.Hour), true))
+}
+
+func TestEventAcksDropOldest(t *testing.T) {
+	acks := newEventAcks(30 * time.Second)
+	now := time.Now()
+
+	var dropped error
+	first := transport.NewMessage(transport.MessageTypeEvent, []byte("first"))
+	first.SendCallback = func(err error) {
+		dropped = err
+	}
+	acks.track(first, now)
+	for i := 1; i < maxPendingEventAcks; i++ {
+		acks.track(transport.NewMessage(transport.MessageTypeEvent, []byte("event")), now.Add(time.Second))
+	}
+	assert.NoError(t, dropped)
+
+	acks.track(transport.NewMessage(transport.MessageTypeEvent, []byte("last")), now.Add(time.Second))
+	assert.Error(t, dropped)
+	assert.Len(t, acks.due(now, true), maxPendingEventAcks)
+}
+
+func TestHandleEventAck(t *testing.T) {
+	a := &Agent{eventAcks: newEventAcks(time.Second)}
+	id := ackedEventID(t, a.eventAcks.track(transport.NewMessage(transport.MessageTypeEvent, []byte("event")), time.Now()), "event")
+
+	require.NoError(t, a.handleEventAck(context.Background(), []byte(`{"id": "`+id+`"}`)))
+	assert.Empty(t, a.eventAcks.due(time.Now(), true))
+	assert.Error(t, a.handleEventAck(context.Background(), []byte("{")))
+}
+
+type failingEventTransport struct {
+	transport.Transport
+	sent []*transport.Message
+}
+
+func (f *failingEventTransport) Send(msg *transport.Message) error {
+	f.sent = append(f.sent, msg)
+	if msg.Type == transport.MessageTypeKeepalive {
+		return nil
+	}
+	return errors.New("connection closed")
+}
+
+func (f *failingEventTransport) Close() error {
+	return nil
+}
+
+func TestSendLoopTrackedEventNotSpooled(t *testing.T) {
+	cfg, cleanup := FixtureConfig()
+	defer cleanup()
+	cfg.EventAcks = true
+	cfg.OfflineSpool = &OfflineSpoolConfig{MaxSize: 1 << 20}
+	ta, err := NewAgent(cfg)
+	require.NoError(t, err)
+	defer ta.offlineSpool.Close()
+	ta.protocolVersion = transport.ProtocolVersion
+
+	ta.sendq <- transport.NewMessage(transport.MessageTypeEvent, []byte("event"))
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := &failingEventTransport{}
+	assert.Error(t, ta.sendLoop(ctx, cancel, conn))
+
+	// The event that could not be sent is only sent again by the event
+	// acks, not replayed from the offline spool as well
+	require.Len(t, conn.sent, 2)
+	assert.Equal(t, transport.MessageTypeAckedEvent, conn.sent[1].Type)
+	assert.Equal(t, int64(0), ta.offlineSpool.Size())
+	assert.Len(t, ta.eventAcks.due(time.Now(), true), 1)
+}
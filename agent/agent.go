@@ -7,18 +7,19 @@ package agent
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 
 	time "github.com/echlebek/timeproxy"
 	"github.com/gogo/protobuf/proto"
 
-	"github.com/atlassian/gostatsd/pkg/statsd"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/asset"
 	"github.com/sensu/sensu-go/backend/agentd"
@@ -28,6 +29,7 @@ import (
 	"github.com/sensu/sensu-go/transport"
 	"github.com/sensu/sensu-go/util/retry"
 	utilstrings "github.com/sensu/sensu-go/util/strings"
+	"github.com/sensu/sensu-go/util/systemd"
 	"github.com/sirupsen/logrus"
 )
 
@@ -44,49 +46,167 @@ func GetDefaultAgentName() string {
 
 // An Agent receives and acts on messages from a Sensu Backend.
 type Agent struct {
+	// spoolFlushing is accessed atomically and must remain the first field
+	// of the struct for 64-bit alignment on 32-bit platforms.
+	spoolFlushing int64
+
 	allowList       []allowList
+	denyList        []denyList
 	api             *http.Server
+	artifacts       *artifactUploader
 	assetGetter     asset.Getter
 	backendSelector BackendSelector
+	backendURL      string
+	bookmarks       *eventLogBookmarks
+	checkDedup      *requestDedup
+	checkSlots      chan struct{}
+	cloudMetadata   *cloudMetadata
 	config          *Config
 	connected       bool
 	connectedMu     sync.RWMutex
 	contentType     string
 	entity          *corev2.Entity
+	entityMu        sync.Mutex
+	eventAcks       *eventAcks
+	eventFilter     *eventFilter
+	executions      *checkExecutions
 	executor        command.Executor
 	handler         *handler.MessageHandler
 	header          http.Header
 	inProgress      map[string]*corev2.CheckConfig
 	inProgressMu    *sync.Mutex
-	statsdServer    *statsd.Server
+	inventory       keepaliveInventory
+	keepaliveReset  chan struct{}
+	reconnect       chan struct{}
+	configReloader  ConfigReloader
+	localLogLevel   logrus.Level
+	managedConfig   *corev2.AgentConfig
+	protocolVersion int
+	statsdServer    *statsdServer
 	sendq           chan *transport.Message
 	systemInfo      *corev2.System
 	systemInfoMu    sync.RWMutex
 	wg              sync.WaitGroup
 	apiQueue        queue
+	offlineSpool    *offlineSpool
 	marshal         agentd.MarshalFunc
 	unmarshal       agentd.UnmarshalFunc
+	reconnects      *reconnectTracker
+	secrets         *secretsResolver
+	socketLimiters  *socketLimiters
+
+	// resumeToken is the token the session opened with the backend
+	// resumeURL can be resumed with.
+	resumeToken string
+	resumeURL   string
 }
 
 // NewAgent creates a new Agent. It returns non-nil error if there is any error
 // when creating the Agent.
 func NewAgent(config *Config) (*Agent, error) {
+	if config.HeartbeatOnly {
+		config.APICheckExecution = false
+		config.DisableAssets = true
+		config.ArtifactStore = nil
+	}
+	if config.Minimal {
+		config.DisableAPI = true
+		config.DisableAssets = true
+		config.DisableSockets = true
+		config.StatsdServer.Disable = true
+	}
+	if !statsdSupported {
+		config.StatsdServer.Disable = true
+	}
+	if config.CloudMetadata != nil {
+		if err := validateCloudProviders(config.CloudMetadata.Providers); err != nil {
+			return nil, fmt.Errorf("error creating agent: %s", err)
+		}
+	}
+	if err := corev2.ValidateContainerRuntime(config.ContainerRuntime); err != nil {
+		return nil, fmt.Errorf("error creating agent: %s", err)
+	}
+	if err := ValidateContainersConfig(config.Containers); err != nil {
+		return nil, fmt.Errorf("error creating agent: %s", err)
+	}
+	if err := ValidateKeepaliveNetworkInterfaces(config.KeepaliveNetworkInterfaces); err != nil {
+		return nil, fmt.Errorf("error creating agent: %s", err)
+	}
+	if err := ValidateSecretsConfig(config.Secrets); err != nil {
+		return nil, fmt.Errorf("error creating agent: %s", err)
+	}
+	if config.EnrollmentToken != "" {
+		configureEnrollmentFiles(config)
+	}
+	if config.LogLevel != "" {
+		level, err := logrus.ParseLevel(config.LogLevel)
+		if err != nil {
+			return nil, fmt.Errorf("error creating agent: %s", err)
+		}
+		logrus.SetLevel(level)
+	}
+
 	agent := &Agent{
-		backendSelector: &RandomBackendSelector{Backends: config.BackendURLs},
+		backendSelector: NewWeightedBackendSelector(config.BackendURLs, config.BackendWeights),
+		bookmarks:       newEventLogBookmarks(config.CacheDir),
 		connected:       false,
+		checkDedup:      newRequestDedup(checkRequestDedupWindow),
 		config:          config,
+		executions:      newCheckExecutions(),
 		executor:        command.NewExecutor(),
 		handler:         handler.NewMessageHandler(),
 		inProgress:      make(map[string]*corev2.CheckConfig),
 		inProgressMu:    &sync.Mutex{},
+		keepaliveReset:  make(chan struct{}, 1),
+		reconnect:       make(chan struct{}, 1),
+		localLogLevel:   logrus.GetLevel(),
 		sendq:           make(chan *transport.Message, 10),
 		systemInfo:      &corev2.System{},
 		unmarshal:       agentd.UnmarshalJSON,
 		marshal:         agentd.MarshalJSON,
+		reconnects:      &reconnectTracker{threshold: config.BackendReconnectThreshold},
+		secrets:         newSecretsResolver(config.Secrets),
+	}
+
+	if config.Socket != nil {
+		agent.socketLimiters = newSocketLimiters(config.Socket.RateLimit, config.Socket.BurstLimit)
+	}
+
+	if config.MaxConcurrentChecks > 0 {
+		agent.checkSlots = make(chan struct{}, config.MaxConcurrentChecks)
+	}
+
+	eventFilterConfig := EventFilterConfig{}
+	if config.EventFilter != nil {
+		eventFilterConfig = *config.EventFilter
 	}
+	agent.eventFilter = newEventFilter(eventFilterConfig)
 
-	agent.statsdServer = NewStatsdServer(agent)
+	if config.EventAcks {
+		timeout := config.EventAckTimeout
+		if timeout <= 0 {
+			timeout = DefaultEventAckTimeout
+		}
+		agent.eventAcks = newEventAcks(time.Duration(timeout) * time.Second)
+		agent.handler.AddHandler(transport.MessageTypeEventAck, agent.handleEventAck)
+	}
+
+	if config.ArtifactStore != nil {
+		artifacts, err := newArtifactUploader(*config.ArtifactStore, config.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("error creating agent: %s", err)
+		}
+		agent.artifacts = artifacts
+	}
+
+	if !config.StatsdServer.Disable {
+		agent.statsdServer = NewStatsdServer(agent)
+	}
 	agent.handler.AddHandler(corev2.CheckRequestType, agent.handleCheck)
+	agent.handler.AddHandler(corev2.AgentSpoolRequestType, agent.handleSpoolRequest)
+	agent.handler.AddHandler(corev2.AgentCommandRequestType, agent.handleCommandRequest)
+	agent.handler.AddHandler(corev2.AgentConfigType, agent.handleAgentConfig)
+	agent.handler.AddHandler(transport.MessageTypeMessageRejected, agent.handleMessageRejection)
 
 	// We don't check for errors here and let the agent get created regardless
 	// of system info status.
@@ -96,6 +216,12 @@ func NewAgent(config *Config) (*Agent, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error creating agent: %s", err)
 	}
+	if config.OfflineSpool != nil && config.OfflineSpool.MaxSize > 0 && config.CacheDir != os.DevNull {
+		agent.offlineSpool, err = newOfflineSpool(config.CacheDir, *config.OfflineSpool)
+		if err != nil {
+			return nil, fmt.Errorf("error creating agent: %s", err)
+		}
+	}
 
 	allowList, err := readAllowList(config.AllowList, ioutil.ReadFile)
 	if err != nil {
@@ -103,15 +229,34 @@ func NewAgent(config *Config) (*Agent, error) {
 	}
 	agent.allowList = allowList
 
+	denyList, err := readDenyList(config.DenyList, ioutil.ReadFile)
+	if err != nil {
+		return nil, err
+	}
+	agent.denyList = denyList
+
 	return agent, nil
 }
 
+// compression returns the compression configuration of the connections to
+// the backends.
+func (a *Agent) compression() transport.Compression {
+	return transport.Compression{
+		Enabled: a.config.BackendCompression,
+		Level:   a.config.BackendCompressionLevel,
+	}
+}
+
 func (a *Agent) sendMessage(msg *transport.Message) {
 	logger.WithFields(logrus.Fields{
 		"type":         msg.Type,
 		"content_type": a.contentType,
 		"payload_size": len(msg.Payload),
 	}).Info("sending message")
+	if a.offlineSpool != nil && !a.Connected() {
+		a.spoolOffline(msg)
+		return
+	}
 	a.sendq <- msg
 }
 
@@ -151,6 +296,10 @@ func (a *Agent) buildTransportHeaderMap() http.Header {
 	header.Set(transport.HeaderKeyNamespace, a.config.Namespace)
 	header.Set(transport.HeaderKeyUser, a.config.User)
 	header.Set(transport.HeaderKeySubscriptions, strings.Join(a.config.Subscriptions, ","))
+	header.Set(transport.HeaderKeyProtocolVersion, strconv.Itoa(transport.ProtocolVersion))
+	if a.config.HeartbeatOnly {
+		header.Set(transport.HeaderKeyHeartbeatOnly, "true")
+	}
 
 	return header
 }
@@ -164,13 +313,21 @@ func (a *Agent) buildTransportHeaderMap() http.Header {
 // 5. Start the send/receive pumps.
 // 6. Issue a keepalive immediately.
 // 7. Start refreshing system info periodically.
-// 8. Start sending periodic keepalives.
-// 9. Start the API server, shutdown the agent if doing so fails.
+// 8. Start refreshing the cloud instance metadata periodically, if enabled.
+// 9. Start sending periodic keepalives.
+// 10. Start the API server, shutdown the agent if doing so fails.
+// 11. Notify systemd that the agent is started, and ping its watchdog while
+// the agent is connected to a backend.
 func (a *Agent) Run(ctx context.Context) error {
 	defer func() {
 		if err := a.apiQueue.Close(); err != nil {
 			logger.WithError(err).Error("error closing API queue")
 		}
+		if a.offlineSpool != nil {
+			if err := a.offlineSpool.Close(); err != nil {
+				logger.WithError(err).Error("error closing offline spool")
+			}
+		}
 	}()
 	userCredentials := fmt.Sprintf("%s:%s", a.config.User, a.config.Password)
 	userCredentials = base64.StdEncoding.EncodeToString([]byte(userCredentials))
@@ -184,9 +341,16 @@ func (a *Agent) Run(ctx context.Context) error {
 	if timeout := a.config.KeepaliveTimeout; timeout < 5 {
 		return fmt.Errorf("bad keepalive timeout: %d (minimum value is 5 seconds)", timeout)
 	}
+	if err := a.compression().Validate(); err != nil {
+		return fmt.Errorf("bad backend compression: %s", err)
+	}
 
 	if !a.config.DisableAssets {
 		assetManager := asset.NewManager(a.config.CacheDir, a.getAgentEntity(), &a.wg)
+		assetManager.GC = asset.CacheGCConfig{
+			MaxSize:   int64(a.config.AssetsCacheMaxSize) * 1024 * 1024,
+			Retention: time.Duration(a.config.AssetsCacheRetention) * time.Hour,
+		}
 		var err error
 		a.assetGetter, err = assetManager.StartAssetManager(ctx)
 		if err != nil {
@@ -201,7 +365,23 @@ func (a *Agent) Run(ctx context.Context) error {
 
 	go a.connectionManager(ctx)
 	go a.refreshSystemInfoPeriodically(ctx)
+	if a.config.CloudMetadata != nil && len(a.config.CloudMetadata.Providers) > 0 {
+		go a.refreshCloudMetadataPeriodically(ctx)
+	}
+	if a.config.TLS != nil && a.config.TLSReloadInterval > 0 {
+		go a.watchTLSIdentity(ctx, time.Duration(a.config.TLSReloadInterval)*time.Second)
+	}
 	go a.handleAPIQueue(ctx)
+	if a.offlineSpool != nil {
+		go a.spoolKeepalives(ctx.Done())
+	}
+
+	systemd.Ready()
+	go systemd.Watchdog(ctx, a.alive)
+	go func() {
+		<-ctx.Done()
+		systemd.Stopping()
+	}()
 
 	a.wg.Wait()
 	return nil
@@ -209,10 +389,24 @@ func (a *Agent) Run(ctx context.Context) error {
 
 func (a *Agent) connectionManager(ctx context.Context) {
 	defer logger.Debug("shutting down connection manager")
+
+	// The agent enrolls before connecting, then renews its certificate
+	if a.config.EnrollmentToken != "" {
+		if err := a.enroll(ctx); err != nil {
+			if err == ctx.Err() {
+				return
+			}
+			log.Fatal(err)
+		}
+		go a.renewCertificatePeriodically(ctx)
+	}
 	for {
 		a.connectedMu.Lock()
 		a.connected = false
 		a.connectedMu.Unlock()
+		if a.offlineSpool != nil {
+			a.spoolSendQueue()
+		}
 
 		conn, err := a.connectWithBackoff(ctx)
 		if err != nil {
@@ -225,16 +419,29 @@ func (a *Agent) connectionManager(ctx context.Context) {
 		ctx, cancel := context.WithCancel(ctx)
 
 		// Start sending hearbeats to the backend
-		conn.Heartbeat(ctx, a.config.BackendHeartbeatInterval, a.config.BackendHeartbeatTimeout)
+		conn.Heartbeat(ctx, a.config.BackendHeartbeatInterval, a.config.BackendHeartbeatTimeout, a.config.BackendHeartbeatMaxMissed)
+
+		// Fail back to a backend with a higher weight once it is reachable
+		if a.config.BackendFailbackInterval > 0 {
+			go a.failback(ctx, cancel, a.backendURL)
+		}
 
 		a.connectedMu.Lock()
 		a.connected = true
 		a.connectedMu.Unlock()
+		systemd.Status("connected to " + a.backendURL)
+
+		// Report the outage the agent recovered from, once the send loop runs
+		if outage := a.reconnects.connected(); outage != nil {
+			go a.reportOutage(ctx, *outage)
+		}
 
 		go a.receiveLoop(ctx, cancel, conn)
 		if err := a.sendLoop(ctx, cancel, conn); err != nil && err != ctx.Err() {
 			logger.WithError(err).Error("error sending messages")
 		}
+		a.reconnects.disconnected()
+		systemd.Status("disconnected, reconnecting to the backends")
 	}
 }
 
@@ -247,6 +454,13 @@ func (a *Agent) receiveLoop(ctx context.Context, cancel context.CancelFunc, conn
 			return
 		}
 
+		// The backend is shutting down, close the connection so the
+		// connection manager reconnects to the next backend
+		if m.Type == transport.MessageTypeReconnect {
+			logger.Info("backend requested to reconnect to another backend")
+			return
+		}
+
 		go func(msg *transport.Message) {
 			logger.WithFields(logrus.Fields{
 				"type":         msg.Type,
@@ -263,13 +477,42 @@ func (a *Agent) receiveLoop(ctx context.Context, cancel context.CancelFunc, conn
 
 func (a *Agent) sendLoop(ctx context.Context, cancel context.CancelFunc, conn transport.Transport) error {
 	defer cancel()
-	keepalive := time.NewTicker(time.Duration(a.config.KeepaliveInterval) * time.Second)
-	defer keepalive.Stop()
+	keepalive := time.NewTicker(time.Duration(a.keepaliveInterval()) * time.Second)
+	defer func() {
+		keepalive.Stop()
+	}()
+	// The system inventory is sent in the first keepalive of every session
+	a.inventory.reset()
 	logger.Info("sending keepalive")
 	if err := conn.Send(a.newKeepalive()); err != nil {
 		logger.WithError(err).Error("error sending message over websocket")
 		return err
 	}
+
+	// The events are sent as acked events if the backend supports them, and
+	// the events not acknowledged before the agent reconnected are sent again
+	var ackCheck <-chan time.Time
+	acks := a.eventAcks != nil && transport.Supports(a.protocolVersion, transport.FeatureEventAck)
+	if acks {
+		ticker := time.NewTicker(eventAckCheckInterval)
+		defer ticker.Stop()
+		ackCheck = ticker.C
+		if err := a.resendEvents(conn, true); err != nil {
+			return err
+		}
+	} else if a.eventAcks != nil {
+		logger.WithField("protocol_version", a.protocolVersion).Warn("backend does not support event acknowledgements")
+	}
+
+	// The messages spooled while the agent was disconnected are sent before
+	// the new ones
+	if a.offlineSpool != nil {
+		if err := a.replayOfflineSpool(conn, acks); err != nil {
+			logger.WithError(err).Error("error replaying the offline spool")
+			return err
+		}
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -280,8 +523,29 @@ func (a *Agent) sendLoop(ctx context.Context, cancel context.CancelFunc, conn tr
 			return nil
 		case msg := <-a.sendq:
 			logger.Info("sending event")
+			tracked := acks && msg.Type == transport.MessageTypeEvent
+			if tracked {
+				msg = a.eventAcks.track(msg, time.Now())
+			}
 			if err := conn.Send(msg); err != nil {
 				logger.WithError(err).Error("error sending message over websocket")
+				// The tracked events are sent again by the event acks
+				if a.offlineSpool != nil && !tracked {
+					a.spoolOffline(msg)
+				}
+				return err
+			}
+		case <-a.reconnect:
+			// The settings sent in the handshake or the TLS identity
+			// changed, the session is opened again instead of being resumed
+			logger.Info("reconnecting to the backend with the new agent configuration")
+			a.resumeToken = ""
+			if err := conn.Close(); err != nil {
+				logger.WithError(err).Error("error closing websocket connection")
+			}
+			return nil
+		case <-ackCheck:
+			if err := a.resendEvents(conn, false); err != nil {
 				return err
 			}
 		case <-keepalive.C:
@@ -290,6 +554,16 @@ func (a *Agent) sendLoop(ctx context.Context, cancel context.CancelFunc, conn tr
 				logger.WithError(err).Error("error sending message over websocket")
 				return err
 			}
+		case <-a.keepaliveReset:
+			// The config managed by the backend changed
+			keepalive.Stop()
+			keepalive = time.NewTicker(time.Duration(a.keepaliveInterval()) * time.Second)
+			a.inventory.reset()
+			logger.Info("sending keepalive")
+			if err := conn.Send(a.newKeepalive()); err != nil {
+				logger.WithError(err).Error("error sending message over websocket")
+				return err
+			}
 		}
 	}
 }
@@ -298,18 +572,23 @@ func (a *Agent) newKeepalive() *transport.Message {
 	msg := &transport.Message{
 		Type: transport.MessageTypeKeepalive,
 	}
-	entity := a.getAgentEntity()
+	entity, inventory := a.keepaliveEntity(time.Now())
 
 	keepalive := &corev2.Event{
 		ObjectMeta: corev2.NewObjectMeta("", entity.Namespace),
 	}
+	if !inventory {
+		keepalive.Annotations = map[string]string{
+			corev2.KeepaliveInventoryAnnotation: corev2.KeepaliveInventoryOmitted,
+		}
+	}
 
 	keepalive.Check = &corev2.Check{
 		ObjectMeta: corev2.NewObjectMeta("keepalive", entity.Namespace),
-		Interval:   a.config.KeepaliveInterval,
+		Interval:   a.keepaliveInterval(),
 		Timeout:    a.config.KeepaliveTimeout,
 	}
-	keepalive.Entity = a.getAgentEntity()
+	keepalive.Entity = entity
 	keepalive.Timestamp = time.Now().Unix()
 
 	msgBytes, err := a.marshal(keepalive)
@@ -329,6 +608,15 @@ func (a *Agent) Connected() bool {
 	return a.connected
 }
 
+// alive returns an error if the agent is not connected to a backend, so that
+// systemd restarts it if it does not reconnect within the watchdog interval.
+func (a *Agent) alive() error {
+	if !a.Connected() {
+		return errors.New("the agent is not connected to a backend")
+	}
+	return nil
+}
+
 // StartAPI starts the Agent HTTP API. After attempting to start the API, if the
 // HTTP server encounters a fatal error, it will shutdown the rest of the agent.
 func (a *Agent) StartAPI(ctx context.Context) {
@@ -379,7 +667,7 @@ func (a *Agent) StartStatsd(ctx context.Context) {
 	logger.Info("starting statsd server on address: ", a.statsdServer.MetricsAddr)
 
 	go func() {
-		if err := a.statsdServer.Run(ctx); err != nil && err != ctx.Err() {
+		if err := runStatsdServer(ctx, a.statsdServer); err != nil && err != ctx.Err() {
 			logger.WithError(err).Errorf("error with statsd server on address: %s, statsd listener will not run", a.statsdServer.MetricsAddr)
 		}
 	}()
@@ -396,18 +684,58 @@ func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, er
 	}
 
 	err := backoff.Retry(func(retry int) (bool, error) {
-		url := a.backendSelector.Select()
+		url := a.selectBackend()
+		tracker, tracked := a.backendTracker()
+
+		// Every backend failed recently, wait for the first one to be
+		// available again
+		if tracked {
+			if delay := tracker.Delay(url); delay > 0 {
+				logger.WithField("backend_url", url).Infof("all backends are unavailable, retrying in %s", delay.Round(time.Millisecond))
+				select {
+				case <-ctx.Done():
+					return false, ctx.Err()
+				case <-time.After(delay):
+				}
+			}
+		}
 
 		logger.Infof("connecting to backend URL %q", url)
 		a.header.Set("Accept", agentd.ProtobufSerializationHeader)
 		logger.WithField("header", fmt.Sprintf("Accept: %s", agentd.ProtobufSerializationHeader)).Debug("setting header")
-		c, respHeader, err := transport.Connect(url, a.config.TLS, a.header, a.config.BackendHandshakeTimeout)
+		a.header.Set(transport.HeaderKeySubscriptions, strings.Join(a.subscriptions(), ","))
+		a.setResumeToken(url)
+		c, respHeader, err := a.connect(url)
+		if err != nil {
+			logger.WithError(err).Error("reconnection attempt failed")
+			a.reconnects.failure(err)
+			if tracked {
+				tracker.Failure(url)
+			}
+			return false, nil
+		}
+
+		// The backends that do not send the negotiated protocol version
+		// speak the first version
+		version, err := transport.ParseProtocolVersion(respHeader)
 		if err != nil {
 			logger.WithError(err).Error("reconnection attempt failed")
+			a.reconnects.failure(err)
+			if tracked {
+				tracker.Failure(url)
+			}
+			_ = c.Close()
 			return false, nil
 		}
+		if tracked {
+			tracker.Success(url)
+		}
+
+		a.protocolVersion = version
+		a.backendURL = url
+		a.saveResumeToken(url, respHeader)
 
-		logger.Info("successfully connected")
+		logger.WithField("protocol_version", version).Info("successfully connected")
 
 		conn = c
 
@@ -431,3 +759,12 @@ func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, er
 
 	return conn, err
 }
+
+// connect connects to the backend URL, with the gRPC transport for grpc:// and
+// grpcs:// URLs, and the WebSocket transport otherwise.
+func (a *Agent) connect(url string) (transport.Transport, http.Header, error) {
+	if transport.IsGRPCURL(url) {
+		return transport.ConnectGRPC(url, a.config.TLS, a.header, a.config.BackendHandshakeTimeout, a.config.BackendHeartbeatInterval, a.config.BackendHeartbeatTimeout)
+	}
+	return transport.Connect(url, a.config.TLS, a.header, a.config.BackendHandshakeTimeout, a.compression())
+}
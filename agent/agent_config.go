@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// handleAgentConfig applies the configuration pushed by the backend, which
+// replaces the one it pushed before.
+func (a *Agent) handleAgentConfig(ctx context.Context, payload []byte) error {
+	var config corev2.AgentConfig
+	if err := json.Unmarshal(payload, &config); err != nil {
+		return err
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	a.applyAgentConfig(&config)
+	return nil
+}
+
+// applyAgentConfig overrides the configuration of the agent with the given
+// configuration managed by the backend. The fields it leaves empty fall back to
+// the configuration of the agent.
+func (a *Agent) applyAgentConfig(config *corev2.AgentConfig) {
+	a.entityMu.Lock()
+	level := a.localLogLevel
+	if config.LogLevel != "" {
+		// The log levels are validated along with the config
+		level, _ = logrus.ParseLevel(config.LogLevel)
+	}
+	logrus.SetLevel(level)
+	a.managedConfig = config
+	// The entity is rebuilt with the managed labels and annotations
+	a.entity = nil
+	a.entityMu.Unlock()
+
+	logger.WithFields(logrus.Fields{
+		"log_level":          level.String(),
+		"keepalive_interval": a.keepaliveInterval(),
+	}).Info("applied the agent config managed by the backend")
+
+	// Send a keepalive at once, so the backend knows of the new labels and
+	// annotations and of the new keepalive interval
+	select {
+	case a.keepaliveReset <- struct{}{}:
+	default:
+	}
+}
+
+// keepaliveInterval returns the interval between the keepalives, in seconds.
+func (a *Agent) keepaliveInterval() uint32 {
+	a.entityMu.Lock()
+	defer a.entityMu.Unlock()
+	if a.managedConfig != nil && a.managedConfig.KeepaliveInterval > 0 {
+		return a.managedConfig.KeepaliveInterval
+	}
+	return a.config.KeepaliveInterval
+}
+
+// mergeLabels returns the labels or annotations of the agent, overridden by the
+// ones managed by the backend.
+func mergeLabels(local, managed map[string]string) map[string]string {
+	if len(managed) == 0 {
+		return local
+	}
+	merged := make(map[string]string, len(local)+len(managed))
+	for k, v := range local {
+		merged[k] = v
+	}
+	for k, v := range managed {
+		merged[k] = v
+	}
+	return merged
+}
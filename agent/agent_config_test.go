@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleAgentConfig(t *testing.T) {
+	defer logrus.SetLevel(logrus.GetLevel())
+
+	agent := &Agent{
+		config: &Config{
+			AgentName:         "foo",
+			Namespace:         "default",
+			KeepaliveInterval: 20,
+			Labels:            map[string]string{"region": "us-east-1", "team": "ops"},
+		},
+		keepaliveReset: make(chan struct{}, 1),
+		localLogLevel:  logrus.WarnLevel,
+		systemInfo:     &corev2.System{},
+	}
+
+	config := corev2.AgentConfig{
+		LogLevel:          "debug",
+		KeepaliveInterval: 10,
+		Labels:            map[string]string{"region": "us-west-2"},
+		Annotations:       map[string]string{"runbook": "https://example.com"},
+	}
+	payload, err := json.Marshal(config)
+	require.NoError(t, err)
+	require.NoError(t, agent.handleAgentConfig(context.Background(), payload))
+
+	assert.Equal(t, logrus.DebugLevel, logrus.GetLevel())
+	assert.Equal(t, uint32(10), agent.keepaliveInterval())
+	entity := agent.getAgentEntity()
+	assert.Equal(t, map[string]string{"region": "us-west-2", "team": "ops"}, entity.Labels)
+	assert.Equal(t, map[string]string{"runbook": "https://example.com"}, entity.Annotations)
+	assert.Len(t, agent.keepaliveReset, 1)
+
+	// The local configuration of the agent is left untouched
+	assert.Equal(t, "us-east-1", agent.config.Labels["region"])
+
+	// An empty config reverts the agent to its own configuration
+	require.NoError(t, agent.handleAgentConfig(context.Background(), []byte("{}")))
+	assert.Equal(t, logrus.WarnLevel, logrus.GetLevel())
+	assert.Equal(t, uint32(20), agent.keepaliveInterval())
+	entity = agent.getAgentEntity()
+	assert.Equal(t, map[string]string{"region": "us-east-1", "team": "ops"}, entity.Labels)
+	assert.Empty(t, entity.Annotations)
+}
+
+func TestHandleAgentConfigInvalid(t *testing.T) {
+	agent := &Agent{config: &Config{KeepaliveInterval: 20}}
+	err := agent.handleAgentConfig(context.Background(), []byte(`{"log_level": "verbose"}`))
+	assert.Error(t, err)
+	assert.Equal(t, uint32(20), agent.keepaliveInterval())
+}
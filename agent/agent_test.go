@@ -214,3 +214,42 @@ func TestInvalidAgentName_GH2022(t *testing.T) {
 	err = ta.Run(context.Background())
 	require.Error(t, err)
 }
+
+func TestNewAgentMinimal(t *testing.T) {
+	cfg, cleanup := FixtureConfig()
+	defer cleanup()
+	cfg.Minimal = true
+	ta, err := NewAgent(cfg)
+	require.NoError(t, err)
+
+	assert.True(t, ta.config.DisableAPI)
+	assert.True(t, ta.config.DisableAssets)
+	assert.True(t, ta.config.DisableSockets)
+	assert.True(t, ta.config.StatsdServer.Disable)
+	assert.Nil(t, ta.statsdServer)
+}
+
+type reconnectTransport struct {
+	transport.Transport
+	received int
+}
+
+func (r *reconnectTransport) Receive() (*transport.Message, error) {
+	r.received++
+	return transport.NewMessage(transport.MessageTypeReconnect, nil), nil
+}
+
+func TestReceiveLoopReconnect(t *testing.T) {
+	cfg, cleanup := FixtureConfig()
+	defer cleanup()
+	ta, err := NewAgent(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := &reconnectTransport{}
+	ta.receiveLoop(ctx, cancel, conn)
+
+	// The connection is closed as soon as the backend asks for a reconnection
+	assert.Equal(t, 1, conn.received)
+	assert.Error(t, ctx.Err())
+}
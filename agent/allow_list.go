@@ -4,22 +4,41 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
 	"strings"
 
 	"gopkg.in/yaml.v2"
 )
 
+// allowList is an entry of the allow list of the agent. It allows either the
+// commands of its exec and args, or the URLs matching its url, a glob pattern,
+// scraped by the agent itself.
 type allowList struct {
 	Exec      string   `yaml:"exec" json:"exec"`
 	Args      []string `yaml:"args" json:"args"`
 	Sha512    string   `yaml:"sha512" json:"sha512"`
 	EnableEnv bool     `yaml:"enable_env" json:"enable_env"`
+	URL       string   `yaml:"url" json:"url,omitempty"`
 }
 
 func readAllowList(path string, readBytes func(string) ([]byte, error)) ([]allowList, error) {
 	var allowList []allowList
+	if err := readListFile(path, readBytes, &allowList); err != nil {
+		return nil, err
+	}
+	for _, al := range allowList {
+		if err := al.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return allowList, nil
+}
+
+// readListFile unmarshals the YAML or JSON list file at path, depending on its
+// extension, into list. The list is left empty if path is empty.
+func readListFile(path string, readBytes func(string) ([]byte, error), list interface{}) error {
 	if path == "" {
-		return allowList, nil
+		return nil
 	}
 	unmarshalFuncs := map[string]func(in []byte, out interface{}) error{
 		".yaml": yaml.Unmarshal,
@@ -30,27 +49,27 @@ func readAllowList(path string, readBytes func(string) ([]byte, error)) ([]allow
 		if strings.Contains(path, ext) {
 			bytes, err := readBytes(path)
 			if err != nil {
-				return nil, err
+				return err
 			}
-			err = f(bytes, &allowList)
-			if err != nil {
-				return nil, err
-			}
-			for _, al := range allowList {
-				err = al.validate()
-				if err != nil {
-					return nil, err
-				}
-			}
-			return allowList, nil
+			return f(bytes, list)
 		}
 	}
 
-	return nil, fmt.Errorf("invalid file extension")
+	return fmt.Errorf("invalid file extension")
 }
 
 // validate returns an error if the allowList contains invalid values.
 func (al *allowList) validate() error {
+	if al.URL != "" {
+		if al.Exec != "" || len(al.Args) > 0 || al.Sha512 != "" || al.EnableEnv {
+			return errors.New("url cannot be combined with exec, args, sha512 or enable_env")
+		}
+		if _, err := path.Match(al.URL, ""); err != nil {
+			return fmt.Errorf("invalid url %q: %s", al.URL, err)
+		}
+		return nil
+	}
+
 	if al.Exec == "" {
 		return errors.New("exec cannot be empty")
 	}
@@ -64,6 +83,9 @@ func (al *allowList) validate() error {
 
 func (a *Agent) matchAllowList(command string) (allowList, bool) {
 	for _, al := range a.allowList {
+		if al.Exec == "" {
+			continue
+		}
 		remaining := command
 		if strings.Contains(command, al.Exec) {
 			remaining = strings.Replace(remaining, al.Exec, "", -1)
@@ -79,3 +101,20 @@ func (a *Agent) matchAllowList(command string) (allowList, bool) {
 	}
 	return allowList{}, false
 }
+
+// matchAllowListURL returns true if the URL matches the url of an entry of the
+// allow list of the agent, or if the agent has no allow list.
+func (a *Agent) matchAllowListURL(url string) bool {
+	if len(a.allowList) == 0 {
+		return true
+	}
+	for _, al := range a.allowList {
+		if al.URL == "" {
+			continue
+		}
+		if ok, _ := path.Match(al.URL, url); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -283,3 +283,37 @@ func TestMatchAllowList(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowListURL(t *testing.T) {
+	entries, err := readAllowList("allow_list.yaml", func(string) ([]byte, error) {
+		return []byte(`
+        - exec: my_script.sh
+          args:
+          - ""
+        - url: http://localhost:9100/*
+        `), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(entries))
+	assert.Equal(t, "http://localhost:9100/*", entries[1].URL)
+
+	agent := &Agent{}
+	assert.True(t, agent.matchAllowListURL("http://metadata.internal/latest"))
+
+	agent.allowList = entries
+	assert.True(t, agent.matchAllowListURL("http://localhost:9100/metrics"))
+	assert.False(t, agent.matchAllowListURL("http://localhost:9100/a/b"))
+	assert.False(t, agent.matchAllowListURL("http://metadata.internal/latest"))
+
+	// The url entries don't allow commands
+	_, match := agent.matchAllowList("rm -rf /")
+	assert.False(t, match)
+
+	for _, entry := range []allowList{
+		{URL: "http://localhost/*", Exec: "curl"},
+		{URL: "http://localhost/*", Args: []string{""}},
+		{URL: "http://localhost/[a-"},
+	} {
+		assert.Error(t, entry.validate(), entry.URL)
+	}
+}
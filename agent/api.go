@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sensu/lasr"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/transport"
 	"github.com/sensu/sensu-go/types"
 	"golang.org/x/time/rate"
@@ -40,7 +42,15 @@ func newServer(a *Agent) *http.Server {
 func registerRoutes(a *Agent, r *mux.Router) {
 	r.HandleFunc("/events", addEvent(a)).Methods(http.MethodPost)
 	r.HandleFunc("/healthz", healthz(a.Connected)).Methods(http.MethodGet)
+	r.HandleFunc("/reconnects", reconnectHandler(a)).Methods(http.MethodGet)
+	r.HandleFunc("/config/reload", reloadHandler(a)).Methods(http.MethodPost)
+	r.HandleFunc("/spool", spoolHandler(a, corev2.AgentSpoolInfo)).Methods(http.MethodGet)
+	r.HandleFunc("/spool", spoolHandler(a, corev2.AgentSpoolPurge)).Methods(http.MethodDelete)
+	r.HandleFunc("/spool/flush", spoolHandler(a, corev2.AgentSpoolFlush)).Methods(http.MethodPost)
 	r.Handle("/metrics", promhttp.Handler())
+	if a.config.APICheckExecution {
+		r.HandleFunc("/checks/execute", executeCheckHandler(a)).Methods(http.MethodPost)
+	}
 }
 
 // healthz returns an OK status if the agent is up and connected to a backend.
@@ -65,7 +75,10 @@ func (a *Agent) handleAPIQueue(ctx context.Context) {
 		}
 		limiter := rate.NewLimiter(limit, a.config.EventsAPIBurstLimit)
 		for {
-			if err := limiter.Wait(ctx); err != nil {
+			// The rate limit doesn't apply to the messages being flushed
+			if atomic.LoadInt64(&a.spoolFlushing) > 0 {
+				atomic.AddInt64(&a.spoolFlushing, -1)
+			} else if err := limiter.Wait(ctx); err != nil {
 				// context canceled
 				return
 			}
@@ -125,6 +138,12 @@ func addEvent(a *Agent) http.HandlerFunc {
 			return
 		}
 
+		if a.eventFilter.Filter(event) {
+			logger.Debug("event dropped by the agent event filters")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
 		payload, err := a.marshal(event)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("error marshaling check result: %s", err), http.StatusInternalServerError)
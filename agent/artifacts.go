@@ -0,0 +1,257 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultArtifactsMaxSize is the default maximum size, in bytes, of each
+	// artifact uploaded to the artifact store.
+	DefaultArtifactsMaxSize = 10 * 1024 * 1024
+
+	// maxArtifactsPerCheck is the maximum number of files uploaded for a
+	// single execution of a check, so a broad glob can't flood the store.
+	maxArtifactsPerCheck = 20
+
+	// artifactUploadTimeout is the maximum duration of an artifact upload.
+	artifactUploadTimeout = 30 * time.Second
+)
+
+// ArtifactStoreConfig contains the configuration of the object store the
+// agent uploads the output artifacts of checks to.
+type ArtifactStoreConfig struct {
+	// URL is the base URL of the artifact store. Each artifact is uploaded
+	// with a PUT request to URL/namespace/entity/check/executed/file. The
+	// credentials of the store can be provided as the user info of the URL.
+	// Artifacts are not uploaded if URL is empty.
+	URL string
+
+	// MaxSize is the maximum size, in bytes, of each artifact. Larger
+	// artifacts are truncated to MaxSize bytes.
+	MaxSize int64
+
+	// AllowedDirs are the directories the artifacts can be uploaded from,
+	// once their symbolic links are resolved. The output artifacts of the
+	// checks are set by the users who can create checks, so no artifact is
+	// uploaded if AllowedDirs is empty.
+	AllowedDirs []string
+}
+
+// artifactUploader uploads the output artifacts of checks to an artifact
+// store.
+type artifactUploader struct {
+	config      ArtifactStoreConfig
+	baseURL     *url.URL
+	client      *http.Client
+	allowedDirs []string
+}
+
+// newArtifactUploader returns an artifactUploader for the given artifact
+// store, or nil if no artifact store is configured.
+func newArtifactUploader(config ArtifactStoreConfig, tlsOptions *corev2.TLSOptions) (*artifactUploader, error) {
+	if config.URL == "" {
+		return nil, nil
+	}
+	baseURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact store url: %s", err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid artifact store url: unsupported scheme %q", baseURL.Scheme)
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultArtifactsMaxSize
+	}
+	if len(config.AllowedDirs) == 0 {
+		logger.Warning("no directory allowed for the output artifacts, they won't be uploaded")
+	}
+	allowedDirs := make([]string, 0, len(config.AllowedDirs))
+	for _, dir := range config.AllowedDirs {
+		resolved, err := resolvePath(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifacts allowed directory: %s", err)
+		}
+		allowedDirs = append(allowedDirs, resolved)
+	}
+
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if tlsOptions != nil {
+		tlsConfig, err := tlsOptions.ToClientTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &artifactUploader{
+		config:      config,
+		baseURL:     baseURL,
+		client:      &http.Client{Transport: transport, Timeout: artifactUploadTimeout},
+		allowedDirs: allowedDirs,
+	}, nil
+}
+
+// resolvePath returns the absolute path of the given path, with its symbolic
+// links resolved.
+func resolvePath(p string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(resolved)
+}
+
+// allowedPath returns the path of the matched file with its symbolic links
+// resolved, or an error if it falls outside of the allowed directories.
+func (u *artifactUploader) allowedPath(match string) (string, error) {
+	resolved, err := resolvePath(match)
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range u.allowedDirs {
+		if resolved == dir || strings.HasPrefix(resolved, dir+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", errors.New("not in an allowed artifacts directory")
+}
+
+// Upload uploads the files matching the output artifacts of the check of the
+// given event and returns their links. Files outside of the allowed
+// directories, and files that can't be uploaded, are logged and skipped.
+func (u *artifactUploader) Upload(ctx context.Context, event *corev2.Event) []string {
+	fields := logrus.Fields{
+		"namespace": event.Check.Namespace,
+		"check":     event.Check.Name,
+	}
+
+	if len(u.allowedDirs) == 0 {
+		logger.WithFields(fields).Warning("no directory allowed for the output artifacts, not uploading them")
+		return nil
+	}
+
+	var links []string
+	seen := make(map[string]struct{})
+	names := make(map[string]struct{})
+	for _, pattern := range event.Check.OutputArtifacts {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Errorf("invalid output artifact %q", pattern)
+			continue
+		}
+		for _, match := range matches {
+			// The file is opened at its resolved path rather than through
+			// the symbolic links of the match
+			resolved, err := u.allowedPath(match)
+			if err != nil {
+				logger.WithFields(fields).WithError(err).Warningf("refusing to upload output artifact %q", match)
+				continue
+			}
+			if _, ok := seen[resolved]; ok {
+				continue
+			}
+			seen[resolved] = struct{}{}
+
+			if len(links) >= maxArtifactsPerCheck {
+				logger.WithFields(fields).Warningf("too many output artifacts, only uploading the first %d", maxArtifactsPerCheck)
+				return links
+			}
+
+			// Two files from different directories may share the same name
+			name := filepath.Base(match)
+			if _, ok := names[name]; ok {
+				name = fmt.Sprintf("%d-%s", len(links), name)
+			}
+			names[name] = struct{}{}
+
+			link, err := u.upload(ctx, event, resolved, name)
+			if err != nil {
+				logger.WithFields(fields).WithError(err).Errorf("could not upload output artifact %q", match)
+				continue
+			}
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// upload uploads the file at the given path under the given name and returns
+// its link.
+func (u *artifactUploader) upload(ctx context.Context, event *corev2.Event, filePath, name string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", errors.New("not a regular file")
+	}
+	size := info.Size()
+	if size > u.config.MaxSize {
+		logger.WithField("check", event.Check.Name).Warningf(
+			"output artifact %q is larger than %d bytes and was truncated", filePath, u.config.MaxSize)
+		size = u.config.MaxSize
+	}
+
+	target := u.link(event, name)
+	req, err := http.NewRequest(http.MethodPut, target.String(), io.LimitReader(f, size))
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("artifact store responded with %s", resp.Status)
+	}
+
+	// Never leak the credentials of the store in the links of the event
+	target.User = nil
+	return target.String(), nil
+}
+
+// link returns the URL of the artifact with the given name for the given
+// event.
+func (u *artifactUploader) link(event *corev2.Event, name string) *url.URL {
+	var entity string
+	if event.Entity != nil {
+		entity = event.Entity.Name
+	}
+	segments := []string{
+		event.Check.Namespace,
+		entity,
+		event.Check.Name,
+		strconv.FormatInt(event.Check.Executed, 10),
+		name,
+	}
+
+	target := *u.baseURL
+	target.RawPath = ""
+	target.Path = path.Join(append([]string{"/", u.baseURL.Path}, segments...)...)
+	return &target
+}
@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewArtifactUploader(t *testing.T) {
+	uploader, err := newArtifactUploader(ArtifactStoreConfig{}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, uploader)
+
+	_, err = newArtifactUploader(ArtifactStoreConfig{URL: "ftp://example.com"}, nil)
+	assert.Error(t, err)
+
+	uploader, err = newArtifactUploader(ArtifactStoreConfig{URL: "https://example.com"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(DefaultArtifactsMaxSize), uploader.config.MaxSize)
+}
+
+func TestArtifactUploaderUpload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "heap.dump"), []byte("0123456789"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "trace.txt"), []byte("hops"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub.txt"), 0755))
+
+	// The files outside of the allowed directory are never uploaded, even
+	// through a symbolic link
+	outside, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(outside)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(outside, "key.txt"), []byte("secret"), 0600))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "key.txt"), filepath.Join(dir, "link.txt")))
+
+	var mu sync.Mutex
+	uploads := make(map[string]string)
+	var username string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		uploads[r.URL.Path] = string(body)
+		username, _, _ = r.BasicAuth()
+	}))
+	defer server.Close()
+
+	storeURL := strings.Replace(server.URL, "http://", "http://user:secret@", 1) + "/artifacts"
+	uploader, err := newArtifactUploader(ArtifactStoreConfig{URL: storeURL, MaxSize: 5, AllowedDirs: []string{dir}}, nil)
+	require.NoError(t, err)
+
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.Executed = 42
+	event.Check.OutputArtifacts = []string{
+		filepath.Join(dir, "*.dump"),
+		filepath.Join(dir, "*.txt"),
+		filepath.Join(dir, "missing"),
+		filepath.Join(outside, "*.txt"),
+	}
+
+	links := uploader.Upload(context.Background(), event)
+	assert.Equal(t, []string{
+		server.URL + "/artifacts/default/entity1/check1/42/heap.dump",
+		server.URL + "/artifacts/default/entity1/check1/42/trace.txt",
+	}, links)
+	assert.Equal(t, map[string]string{
+		"/artifacts/default/entity1/check1/42/heap.dump": "01234",
+		"/artifacts/default/entity1/check1/42/trace.txt": "hops",
+	}, uploads)
+	assert.Equal(t, "user", username)
+}
+
+func TestArtifactUploaderUploadError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "heap.dump"), []byte("dump"), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	uploader, err := newArtifactUploader(ArtifactStoreConfig{URL: server.URL, AllowedDirs: []string{dir}}, nil)
+	require.NoError(t, err)
+
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.OutputArtifacts = []string{filepath.Join(dir, "heap.dump")}
+	assert.Empty(t, uploader.Upload(context.Background(), event))
+}
+
+func TestArtifactUploaderNoAllowedDirs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "heap.dump"), []byte("dump"), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected upload")
+	}))
+	defer server.Close()
+
+	uploader, err := newArtifactUploader(ArtifactStoreConfig{URL: server.URL}, nil)
+	require.NoError(t, err)
+
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.OutputArtifacts = []string{filepath.Join(dir, "heap.dump")}
+	assert.Empty(t, uploader.Upload(context.Background(), event))
+}
@@ -2,6 +2,8 @@ package agent
 
 import (
 	"math/rand"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -44,3 +46,211 @@ func (b *RandomBackendSelector) Select() string {
 
 	return b.Backends[next]
 }
+
+const (
+	// DefaultBackendWeight is the weight of the backends without one.
+	DefaultBackendWeight = 1
+
+	// backendBackoffInitial is the time a backend is avoided after its first
+	// failed connection attempt.
+	backendBackoffInitial = time.Second
+
+	// backendBackoffMax is the maximum time a backend is avoided after
+	// consecutive failed connection attempts.
+	backendBackoffMax = time.Minute
+)
+
+// BackendHealth is the health of a backend, as seen by the agent.
+type BackendHealth struct {
+	// URL is the URL of the backend.
+	URL string `json:"url"`
+
+	// Weight is the weight of the backend. The agent prefers the backends
+	// with the highest weight.
+	Weight int `json:"weight"`
+
+	// Score is the health score of the backend, from 0 to 1, lowered by the
+	// failed connection attempts and raised by the successful ones.
+	Score float64 `json:"score"`
+
+	// ConsecutiveFailures is the number of failed connection attempts since
+	// the last successful one.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// RetryAt is the time before which the backend is avoided, after a failed
+	// connection attempt.
+	RetryAt int64 `json:"retry_at,omitempty"`
+}
+
+// A WeightedBackendSelector selects the available backend with the highest
+// weight, then with the highest health score. The backends with the same
+// weight and score are selected in an order shuffled once, so the agents
+// spread over them. A backend is unavailable after a failed connection
+// attempt, for an exponential backoff with jitter. If no backend is
+// available, the one available the soonest is selected.
+//
+// WeightedBackendSelector is safe for concurrent use.
+type WeightedBackendSelector struct {
+	mu       sync.Mutex
+	backends []*backendState
+	rand     *rand.Rand
+	now      func() time.Time
+}
+
+type backendState struct {
+	BackendHealth
+	rank    int
+	retryAt time.Time
+}
+
+// NewWeightedBackendSelector returns a selector of the backends, with the
+// weights given in the same order. The backends without a weight have the
+// default weight.
+func NewWeightedBackendSelector(backends []string, weights []int) *WeightedBackendSelector {
+	s := &WeightedBackendSelector{
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:  time.Now,
+	}
+	ranks := s.rand.Perm(len(backends))
+	for i, url := range backends {
+		weight := DefaultBackendWeight
+		if i < len(weights) {
+			weight = weights[i]
+		}
+		s.backends = append(s.backends, &backendState{
+			BackendHealth: BackendHealth{URL: url, Weight: weight, Score: 1},
+			rank:          ranks[i],
+		})
+	}
+	return s
+}
+
+// Select returns the backend to connect to.
+func (s *WeightedBackendSelector) Select() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var selected *backendState
+	now := s.now()
+	for _, b := range s.backends {
+		if selected == nil || s.prefers(b, selected, now) {
+			selected = b
+		}
+	}
+	if selected == nil {
+		return ""
+	}
+	return selected.URL
+}
+
+// prefers returns true if the backend a is preferred over the backend b.
+func (s *WeightedBackendSelector) prefers(a, b *backendState, now time.Time) bool {
+	aAvailable, bAvailable := !a.retryAt.After(now), !b.retryAt.After(now)
+	switch {
+	case aAvailable != bAvailable:
+		return aAvailable
+	case !aAvailable:
+		return a.retryAt.Before(b.retryAt)
+	case a.Weight != b.Weight:
+		return a.Weight > b.Weight
+	case a.Score != b.Score:
+		return a.Score > b.Score
+	default:
+		return a.rank < b.rank
+	}
+}
+
+// Delay returns the time left before the backend is available again.
+func (s *WeightedBackendSelector) Delay(url string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b := s.backend(url); b != nil {
+		if delay := b.retryAt.Sub(s.now()); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// Success records a successful connection attempt to the backend.
+func (s *WeightedBackendSelector) Success(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b := s.backend(url); b != nil {
+		b.Score += (1 - b.Score) / 2
+		b.ConsecutiveFailures = 0
+		b.retryAt = time.Time{}
+	}
+}
+
+// Failure records a failed connection attempt to the backend, which is
+// avoided until its backoff elapses.
+func (s *WeightedBackendSelector) Failure(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.backend(url)
+	if b == nil {
+		return
+	}
+	b.Score /= 2
+	b.ConsecutiveFailures++
+	backoff := backendBackoffMax
+	if b.ConsecutiveFailures <= 16 {
+		if d := backendBackoffInitial << uint(b.ConsecutiveFailures-1); d < backoff {
+			backoff = d
+		}
+	}
+	// Full jitter on the upper half of the backoff, so the agents that lost
+	// the same backend do not retry it all at once
+	backoff = backoff/2 + time.Duration(s.rand.Int63n(int64(backoff/2)+1))
+	b.retryAt = s.now().Add(backoff)
+}
+
+// Preferred returns the available backends with a higher weight than the
+// backend, i.e. the ones to fail back to, the most preferred first.
+func (s *WeightedBackendSelector) Preferred(url string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.backend(url)
+	if current == nil {
+		return nil
+	}
+	now := s.now()
+	var preferred []*backendState
+	for _, b := range s.backends {
+		if b.Weight > current.Weight && !b.retryAt.After(now) {
+			preferred = append(preferred, b)
+		}
+	}
+	sort.Slice(preferred, func(i, j int) bool {
+		return s.prefers(preferred[i], preferred[j], now)
+	})
+	urls := make([]string, 0, len(preferred))
+	for _, b := range preferred {
+		urls = append(urls, b.URL)
+	}
+	return urls
+}
+
+// Health returns the health of the backends, in their configured order.
+func (s *WeightedBackendSelector) Health() []BackendHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	health := make([]BackendHealth, 0, len(s.backends))
+	for _, b := range s.backends {
+		h := b.BackendHealth
+		if b.retryAt.After(s.now()) {
+			h.RetryAt = b.retryAt.Unix()
+		}
+		health = append(health, h)
+	}
+	return health
+}
+
+func (s *WeightedBackendSelector) backend(url string) *backendState {
+	for _, b := range s.backends {
+		if b.URL == url {
+			return b
+		}
+	}
+	return nil
+}
@@ -3,8 +3,10 @@ package agent
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBackendSelector(t *testing.T) {
@@ -27,3 +29,59 @@ func TestEmptyBackendSelector(t *testing.T) {
 	assert.Equal(t, "", selector.Select())
 	assert.Equal(t, "", selector.Select())
 }
+
+func TestWeightedBackendSelector(t *testing.T) {
+	now := time.Now()
+	selector := NewWeightedBackendSelector([]string{"remote-1", "local", "remote-2"}, []int{1, 10})
+	selector.now = func() time.Time { return now }
+
+	// The backend with the highest weight is preferred
+	assert.Equal(t, "local", selector.Select())
+	assert.Empty(t, selector.Preferred("local"))
+
+	// Fail over to the other backends while it is in backoff, the healthiest
+	// first
+	selector.Failure("local")
+	delay := selector.Delay("local")
+	assert.True(t, delay >= backendBackoffInitial/2 && delay <= backendBackoffInitial, delay)
+	selector.Failure("remote-1")
+	assert.Equal(t, "remote-2", selector.Select())
+	selector.Success("remote-2")
+	assert.Empty(t, selector.Preferred("remote-2"))
+
+	// The backoff grows exponentially and is capped
+	for i := 0; i < 20; i++ {
+		selector.Failure("local")
+	}
+	delay = selector.Delay("local")
+	assert.True(t, delay >= backendBackoffMax/2 && delay <= backendBackoffMax, delay)
+
+	// Fail back once the backoff elapsed
+	now = now.Add(backendBackoffMax)
+	assert.Equal(t, []string{"local"}, selector.Preferred("remote-2"))
+	assert.Equal(t, "local", selector.Select())
+	selector.Success("local")
+
+	health := selector.Health()
+	require.Len(t, health, 3)
+	assert.Equal(t, "local", health[1].URL)
+	assert.Equal(t, 10, health[1].Weight)
+	assert.Equal(t, 0, health[1].ConsecutiveFailures)
+	assert.True(t, health[1].Score < 1)
+	assert.Equal(t, DefaultBackendWeight, health[2].Weight)
+}
+
+func TestWeightedBackendSelectorUnavailable(t *testing.T) {
+	now := time.Now()
+	selector := NewWeightedBackendSelector([]string{"a", "b"}, nil)
+	selector.now = func() time.Time { return now }
+
+	// The backend available the soonest is selected
+	selector.Failure("a")
+	selector.Failure("a")
+	selector.Failure("b")
+	assert.Equal(t, "b", selector.Select())
+	assert.True(t, selector.Delay("b") > 0)
+
+	assert.Equal(t, "", NewWeightedBackendSelector(nil, nil).Select())
+}
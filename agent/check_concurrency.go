@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// CheckConcurrencyBypassAnnotation is the annotation of the checks executed at
+// once, without waiting for an execution slot, when the number of concurrently
+// executing checks is limited.
+const CheckConcurrencyBypassAnnotation = "sensu.io/bypass-concurrency-limit"
+
+var (
+	checksExecuting = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "sensu_agent_checks_executing",
+			Help: "Number of checks executing on the agent",
+		},
+	)
+
+	checksQueued = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "sensu_agent_checks_queued",
+			Help: "Number of checks waiting for an execution slot on the agent",
+		},
+	)
+
+	checkQueueWait = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "sensu_agent_check_queue_wait_seconds",
+			Help:    "Time the checks waited for an execution slot on the agent",
+			Buckets: []float64{0.01, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+		},
+	)
+)
+
+func init() {
+	_ = prometheus.Register(checksExecuting)
+	_ = prometheus.Register(checksQueued)
+	_ = prometheus.Register(checkQueueWait)
+}
+
+// acquireCheckSlot waits for an execution slot of the check, if the number of
+// concurrently executing checks is limited, and returns the function releasing
+// the slot. The checks with the bypass annotation don't wait. An error is
+// returned if the agent stops while the check waits.
+func (a *Agent) acquireCheckSlot(ctx context.Context, check *corev2.CheckConfig) (func(), error) {
+	if a.checkSlots == nil || check.Annotations[CheckConcurrencyBypassAnnotation] == "true" {
+		checksExecuting.Inc()
+		return checksExecuting.Dec, nil
+	}
+
+	start := time.Now()
+	select {
+	case a.checkSlots <- struct{}{}:
+	default:
+		logger.WithFields(logrus.Fields{
+			"namespace": check.Namespace,
+			"check":     check.Name,
+		}).Debug("maximum concurrent checks executing, queuing check")
+		checksQueued.Inc()
+		select {
+		case a.checkSlots <- struct{}{}:
+			checksQueued.Dec()
+		case <-ctx.Done():
+			checksQueued.Dec()
+			return nil, ctx.Err()
+		}
+	}
+	checkQueueWait.Observe(time.Since(start).Seconds())
+	checksExecuting.Inc()
+
+	return func() {
+		checksExecuting.Dec()
+		<-a.checkSlots
+	}, nil
+}
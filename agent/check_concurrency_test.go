@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireCheckSlot(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.MaxConcurrentChecks = 1
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+
+	check := corev2.FixtureCheckConfig("check")
+	release, err := agent.acquireCheckSlot(context.Background(), check)
+	require.NoError(t, err)
+
+	// The second check waits until the first one releases its slot
+	acquired := make(chan func())
+	go func() {
+		release, err := agent.acquireCheckSlot(context.Background(), check)
+		if err == nil {
+			acquired <- release
+		}
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("check executed while the maximum concurrent checks are executing")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(5 * time.Second):
+		t.Fatal("check still queued after a slot was released")
+	}
+
+	// The checks with the bypass annotation never wait
+	release, err = agent.acquireCheckSlot(context.Background(), check)
+	require.NoError(t, err)
+	defer release()
+	bypass := corev2.FixtureCheckConfig("bypass")
+	bypass.Annotations = map[string]string{CheckConcurrencyBypassAnnotation: "true"}
+	bypassRelease, err := agent.acquireCheckSlot(context.Background(), bypass)
+	require.NoError(t, err)
+	bypassRelease()
+
+	// Queued checks give up when the agent stops
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = agent.acquireCheckSlot(ctx, check)
+	assert.Error(t, err)
+}
+
+func TestAcquireCheckSlotUnlimited(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+
+	check := corev2.FixtureCheckConfig("check")
+	for i := 0; i < 10; i++ {
+		release, err := agent.acquireCheckSlot(context.Background(), check)
+		require.NoError(t, err)
+		defer release()
+	}
+}
@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"sync"
+
+	time "github.com/echlebek/timeproxy"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// checkRequestDedupWindow is the duration during which the agent remembers
+// the check requests it received, in order to discard duplicates. Duplicate
+// requests are typically received when backends reschedule checks after the
+// agent reconnects.
+const checkRequestDedupWindow = 2 * time.Minute
+
+const (
+	// checkRequestDuplicate is the reason of the discarded check requests
+	// the agent already received.
+	checkRequestDuplicate = "duplicate"
+
+	// checkRequestExpired is the reason of the discarded check requests that
+	// expired before the agent received them.
+	checkRequestExpired = "expired"
+
+	// checkRequestHeartbeatOnly is the reason of the discarded check requests
+	// received by an agent running in heartbeat-only mode.
+	checkRequestHeartbeatOnly = "heartbeat_only"
+)
+
+var checkRequestsDiscarded = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "sensu_agent_check_requests_discarded_total",
+		Help: "Number of check requests discarded by the agent, by reason",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	_ = prometheus.Register(checkRequestsDiscarded)
+}
+
+// requestDedup remembers the identifiers of recently received check requests.
+type requestDedup struct {
+	window time.Duration
+	seen   map[string]time.Time
+	mu     sync.Mutex
+}
+
+func newRequestDedup(window time.Duration) *requestDedup {
+	return &requestDedup{
+		window: window,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// Seen records the given request identifier, and returns true if it was
+// already recorded within the window. Empty identifiers, sent by backends
+// that don't identify check requests, are never considered seen.
+func (d *requestDedup) Seen(id string) bool {
+	if id == "" {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for key, received := range d.seen {
+		if now.Sub(received) >= d.window {
+			delete(d.seen, key)
+		}
+	}
+
+	if _, ok := d.seen[id]; ok {
+		return true
+	}
+	d.seen[id] = now
+	return false
+}
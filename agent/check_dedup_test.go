@@ -0,0 +1,28 @@
+package agent
+
+import (
+	"testing"
+
+	time "github.com/echlebek/timeproxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestDedup(t *testing.T) {
+	dedup := newRequestDedup(time.Hour)
+
+	assert.False(t, dedup.Seen("default/check/1"))
+	assert.True(t, dedup.Seen("default/check/1"))
+	assert.False(t, dedup.Seen("default/check/2"))
+
+	// requests without identifier are never discarded
+	assert.False(t, dedup.Seen(""))
+	assert.False(t, dedup.Seen(""))
+}
+
+func TestRequestDedupWindow(t *testing.T) {
+	dedup := newRequestDedup(time.Minute)
+
+	assert.False(t, dedup.Seen("default/check/1"))
+	mockTime.Set(mockTime.Now().Add(2 * time.Minute))
+	assert.False(t, dedup.Seen("default/check/1"))
+}
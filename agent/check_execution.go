@@ -0,0 +1,206 @@
+package agent
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// checkExecutionWait is the maximum time a request to execute a check waits
+// for its result. It stays below the write timeout of the agent API, the
+// result of the checks running longer is only sent to the backend.
+var checkExecutionWait = 10 * time.Second
+
+// errUnknownCheck is returned when the execution of a check the agent never
+// received a request for is requested through the API.
+var errUnknownCheck = errors.New("the agent never received a request for this check")
+
+// checkExecutionRequest is the body of the requests to execute a check
+// through the agent API. Either the name of a check the backend already
+// requested, or an inline check definition, is required.
+type checkExecutionRequest struct {
+	// Check is the name of the check to execute, with the last request the
+	// backend sent for it.
+	Check string `json:"check,omitempty"`
+
+	// Config is the inline definition of the check to execute.
+	Config *corev2.CheckConfig `json:"check_config,omitempty"`
+}
+
+// checkExecutions keeps the last request of every check received from the
+// backend, so they can be executed through the API, and the results awaited
+// by the API.
+type checkExecutions struct {
+	mu       sync.Mutex
+	requests map[string][]byte
+	waiters  map[string]chan *corev2.Event
+}
+
+func newCheckExecutions() *checkExecutions {
+	return &checkExecutions{
+		requests: make(map[string][]byte),
+		waiters:  make(map[string]chan *corev2.Event),
+	}
+}
+
+// remember keeps the payload of the last request received for the check.
+func (c *checkExecutions) remember(name string, payload []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests[name] = payload
+}
+
+// request returns the payload of the last request received for the check.
+func (c *checkExecutions) request(name string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	payload, ok := c.requests[name]
+	return payload, ok
+}
+
+// wait returns the channel on which the result of the check with the given
+// key is delivered, or false if its result is already awaited.
+func (c *checkExecutions) wait(key string) (<-chan *corev2.Event, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.waiters[key]; ok {
+		return nil, false
+	}
+	ch := make(chan *corev2.Event, 1)
+	c.waiters[key] = ch
+	return ch, true
+}
+
+// done stops awaiting the result of the check with the given key.
+func (c *checkExecutions) done(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.waiters, key)
+}
+
+// deliver delivers the result of a check to the API request awaiting it, if
+// any. The checks with the same key don't run concurrently, so the first
+// result delivered is the one of the requested execution.
+func (c *checkExecutions) deliver(event *corev2.Event) {
+	if c == nil || !event.HasCheck() {
+		return
+	}
+	key := checkKey(&corev2.CheckRequest{Config: &corev2.CheckConfig{
+		ObjectMeta:      event.Check.ObjectMeta,
+		ProxyEntityName: event.Check.ProxyEntityName,
+	}})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch, ok := c.waiters[key]
+	if !ok {
+		return
+	}
+	delete(c.waiters, key)
+	ch <- event
+}
+
+// authenticateAPI returns true if the request carries the credentials the
+// agent uses to connect to the backend.
+func (a *Agent) authenticateAPI(r *http.Request) bool {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	validUser := subtle.ConstantTimeCompare([]byte(user), []byte(a.config.User)) == 1
+	validPassword := subtle.ConstantTimeCompare([]byte(password), []byte(a.config.Password)) == 1
+	return validUser && validPassword
+}
+
+// newCheckExecutionRequest returns the check request to execute for the
+// given API request.
+func (a *Agent) newCheckExecutionRequest(body *checkExecutionRequest) (*corev2.CheckRequest, error) {
+	request := &corev2.CheckRequest{}
+	switch {
+	case body.Config != nil && body.Check != "":
+		return nil, errors.New("either a check name or a check definition is required, not both")
+	case body.Config != nil:
+		request.Config = body.Config
+		if request.Config.Namespace == "" {
+			request.Config.Namespace = a.config.Namespace
+		}
+	case body.Check != "":
+		payload, ok := a.executions.request(body.Check)
+		if !ok {
+			return nil, errUnknownCheck
+		}
+		if err := a.unmarshal(payload, request); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("a check name or a check definition is required")
+	}
+	if err := request.Config.Validate(); err != nil {
+		return nil, fmt.Errorf("given check is invalid: %s", err)
+	}
+	if a.config.DisableAssets && len(request.Assets) > 0 {
+		return nil, errors.New("check requested assets, but they are disabled on this agent")
+	}
+	request.Issued = time.Now().Unix()
+	return request, nil
+}
+
+// executeCheckHandler executes a check right away, and returns its result
+// once it is available. The result is also sent to the backend, like the one
+// of the checks requested by the backend.
+func executeCheckHandler(a *Agent) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.authenticateAPI(r) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="sensu-agent"`)
+			http.Error(w, "invalid agent credentials", http.StatusUnauthorized)
+			return
+		}
+
+		var body checkExecutionRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		request, err := a.newCheckExecutionRequest(&body)
+		if err == errUnknownCheck {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		key := checkKey(request)
+		if a.checkInProgress(request) {
+			http.Error(w, fmt.Sprintf("check execution still in progress: %s", key), http.StatusConflict)
+			return
+		}
+		results, ok := a.executions.wait(key)
+		if !ok {
+			http.Error(w, fmt.Sprintf("check execution still in progress: %s", key), http.StatusConflict)
+			return
+		}
+		defer a.executions.done(key)
+
+		logger.WithField("check", request.Config.Name).Info("executing check requested through the agent API")
+		go a.executeCheck(context.Background(), request, a.getAgentEntity())
+
+		timer := time.NewTimer(checkExecutionWait)
+		defer timer.Stop()
+		select {
+		case event := <-results:
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(event)
+		case <-timer.C:
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = fmt.Fprint(w, "check still running, its result will only be sent to the backend")
+		case <-r.Context().Done():
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/testing/mockexecutor"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCheckExecutionAgent(t *testing.T) (*Agent, *mux.Router, func()) {
+	t.Helper()
+	config, cleanup := FixtureConfig()
+	config.APICheckExecution = true
+	agent, err := NewAgent(config)
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	ex := &mockexecutor.MockExecutor{}
+	ex.Return(&command.ExecutionResponse{Status: 1, Output: "warning", Duration: 1}, nil)
+	agent.executor = ex
+	agent.sendq = make(chan *transport.Message, 5)
+
+	router := mux.NewRouter()
+	registerRoutes(agent, router)
+	return agent, router, cleanup
+}
+
+func executeCheckRequest(t *testing.T, agent *Agent, body interface{}, authenticate bool) *http.Request {
+	t.Helper()
+	encoded, err := json.Marshal(body)
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/checks/execute", bytes.NewReader(encoded))
+	if authenticate {
+		r.SetBasicAuth(agent.config.User, agent.config.Password)
+	}
+	return r
+}
+
+func TestExecuteCheckHandlerDisabled(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	router := mux.NewRouter()
+	registerRoutes(agent, router)
+
+	w := httptest.NewRecorder()
+	body := checkExecutionRequest{Check: "check"}
+	router.ServeHTTP(w, executeCheckRequest(t, agent, body, true))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestExecuteCheckHandler(t *testing.T) {
+	inline := corev2.FixtureCheckConfig("inline")
+	inline.RuntimeAssets = nil
+	inline.CheckHooks = nil
+	inline.Namespace = ""
+
+	tests := []struct {
+		name         string
+		body         checkExecutionRequest
+		unauthorized bool
+		wantCode     int
+		wantCheck    string
+	}{
+		{
+			name:         "without credentials",
+			body:         checkExecutionRequest{Check: "check"},
+			unauthorized: true,
+			wantCode:     http.StatusUnauthorized,
+		},
+		{
+			name:     "without check",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown check",
+			body:     checkExecutionRequest{Check: "unknown"},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:      "named check",
+			body:      checkExecutionRequest{Check: "check"},
+			wantCode:  http.StatusOK,
+			wantCheck: "check",
+		},
+		{
+			name:      "inline check",
+			body:      checkExecutionRequest{Config: inline},
+			wantCode:  http.StatusOK,
+			wantCheck: "inline",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, router, cleanup := newCheckExecutionAgent(t)
+			defer cleanup()
+
+			// The backend requested the check once
+			checkConfig := corev2.FixtureCheckConfig("check")
+			checkConfig.RuntimeAssets = nil
+			checkConfig.CheckHooks = nil
+	checkConfig.CheckHooks = nil
+			payload, err := json.Marshal(&corev2.CheckRequest{Config: checkConfig, Issued: time.Now().Unix()})
+			require.NoError(t, err)
+			agent.executions.remember("check", payload)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, executeCheckRequest(t, agent, tt.body, !tt.unauthorized))
+			require.Equal(t, tt.wantCode, w.Code, w.Body.String())
+			if tt.wantCheck == "" {
+				return
+			}
+
+			var event corev2.Event
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&event))
+			assert.Equal(t, tt.wantCheck, event.Check.Name)
+			assert.Equal(t, "warning", event.Check.Output)
+			assert.Equal(t, uint32(1), event.Check.Status)
+			assert.Equal(t, agent.config.Namespace, event.Check.Namespace)
+
+			// The result is also sent to the backend
+			select {
+			case <-agent.sendq:
+			case <-time.After(5 * time.Second):
+				t.Fatal("check result not sent to the backend")
+			}
+		})
+	}
+}
+
+func TestExecuteCheckHandlerInProgress(t *testing.T) {
+	agent, router, cleanup := newCheckExecutionAgent(t)
+	defer cleanup()
+
+	checkConfig := corev2.FixtureCheckConfig("check")
+	checkConfig.RuntimeAssets = nil
+	checkConfig.CheckHooks = nil
+	agent.addInProgress(&corev2.CheckRequest{Config: checkConfig})
+
+	w := httptest.NewRecorder()
+	body := checkExecutionRequest{Config: checkConfig}
+	router.ServeHTTP(w, executeCheckRequest(t, agent, body, true))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestHandleCheckRemembersRequest(t *testing.T) {
+	agent, _, cleanup := newCheckExecutionAgent(t)
+	defer cleanup()
+
+	checkConfig := corev2.FixtureCheckConfig("check")
+	checkConfig.RuntimeAssets = nil
+	checkConfig.CheckHooks = nil
+	payload, err := json.Marshal(&corev2.CheckRequest{Config: checkConfig, Issued: time.Now().Unix()})
+	require.NoError(t, err)
+	require.NoError(t, agent.handleCheck(context.TODO(), payload))
+
+	remembered, ok := agent.executions.request("check")
+	assert.True(t, ok)
+	assert.Equal(t, payload, remembered)
+}
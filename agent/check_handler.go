@@ -24,7 +24,6 @@ const allowListOnDenyStatus = "allow_list_on_deny_status"
 const allowListOnDenyOutput = "check command denied by the agent allow list"
 
 // handleCheck is the check message handler.
-// TODO(greg): At some point, we're going to need max parallelism.
 func (a *Agent) handleCheck(ctx context.Context, payload []byte) error {
 	request := &corev2.CheckRequest{}
 	if err := a.unmarshal(payload, request); err != nil {
@@ -33,6 +32,29 @@ func (a *Agent) handleCheck(ctx context.Context, payload []byte) error {
 		return errors.New("given check configuration appears invalid")
 	}
 
+	if a.config.HeartbeatOnly {
+		logger.WithField("check", request.Config.Name).Warn("discarding check request, the agent runs in heartbeat-only mode: ", request.ID)
+		checkRequestsDiscarded.WithLabelValues(checkRequestHeartbeatOnly).Inc()
+		return nil
+	}
+
+	if a.checkDedup.Seen(request.ID) {
+		logger.WithField("check", request.Config.Name).Info("discarding duplicate check request: ", request.ID)
+		checkRequestsDiscarded.WithLabelValues(checkRequestDuplicate).Inc()
+		return nil
+	}
+
+	// The requests queued while the agent was disconnected are stale once
+	// the next execution of their check is due
+	if request.Expired(time.Now()) {
+		logger.WithFields(logrus.Fields{
+			"check":   request.Config.Name,
+			"expires": time.Unix(request.Expires, 0),
+		}).Warn("discarding expired check request: ", request.ID)
+		checkRequestsDiscarded.WithLabelValues(checkRequestExpired).Inc()
+		return nil
+	}
+
 	checkConfig := request.Config
 	sendFailure := func(err error) {
 		check := corev2.NewCheck(checkConfig)
@@ -63,6 +85,7 @@ func (a *Agent) handleCheck(ctx context.Context, payload []byte) error {
 	}
 
 	logger.Info("scheduling check execution: ", checkConfig.Name)
+	a.executions.remember(checkConfig.Name, payload)
 
 	entity := a.getAgentEntity()
 	go a.executeCheck(ctx, request, entity)
@@ -101,10 +124,16 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 	a.addInProgress(request)
 	defer a.removeInProgress(request)
 
+	// The check stays in progress while it waits for an execution slot, so
+	// its next requests are rejected
+	release, err := a.acquireCheckSlot(ctx, request.Config)
+	if err != nil {
+		return
+	}
+	defer release()
+
 	checkAssets := request.Assets
 	checkConfig := request.Config
-	checkHooks := request.Hooks
-	hookAssets := request.HookAssets
 
 	// Before token subsitution we retain copy of the command
 	origCommand := checkConfig.Command
@@ -123,7 +152,7 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 	}
 
 	// Prepare Check
-	err := prepareCheck(checkConfig, entity)
+	err = prepareCheck(checkConfig, entity)
 	if err != nil {
 		a.sendFailure(createEvent(), fmt.Errorf("error preparing check: %s", err))
 		return
@@ -140,12 +169,62 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 		"assets":    check.RuntimeAssets,
 	}
 
+	// Prometheus scrapes are executed by the agent itself, without command
+	// or assets, and their URLs are matched against the allow list
+	if checkConfig.PrometheusScrape != nil {
+		if !a.matchAllowListURL(checkConfig.PrometheusScrape.URL) {
+			logger.WithFields(fields).Debug("prometheus scrape URL does not match agent allow list")
+			a.sendFailure(event, fmt.Errorf(allowListOnDenyOutput))
+			return
+		}
+		logger.WithFields(fields).Debug("scraping prometheus metrics for check")
+		executePrometheusScrape(ctx, event, a.matchAllowListURL)
+		event.Entity = a.getAgentEntity()
+		event.Timestamp = time.Now().Unix()
+		a.publishCheckResult(ctx, request, event, fields, false)
+		return
+	}
+
+	// Event log queries are executed by the agent itself as well
+	if checkConfig.EventLog != nil {
+		logger.WithFields(fields).WithField("channel", checkConfig.EventLog.Channel).Debug("querying the event log for check")
+		a.executeEventLogQuery(ctx, event)
+		event.Entity = a.getAgentEntity()
+		event.Timestamp = time.Now().Unix()
+		a.publishCheckResult(ctx, request, event, fields, false)
+		return
+	}
+
+	// The command of a check specifying a container is run by the container
+	// engine, in its image, and is the command the deny and allow lists are
+	// matched against
+	matchCommand := checkConfig.Command
+	var containerName string
+	if checkConfig.Container != nil {
+		if err := a.config.Containers.allow(checkConfig.Container); err != nil {
+			a.sendFailure(event, err)
+			return
+		}
+		containerName = newContainerName(checkConfig)
+		if matchCommand, err = containerCommand(checkConfig, a.config.ContainerRuntime, containerName); err != nil {
+			a.sendFailure(event, err)
+			return
+		}
+	}
+
+	// Match check against deny list, which takes precedence over the allow list
+	if deniedEntry, denied := a.matchDenyList(matchCommand); denied {
+		logger.WithFields(fields).WithField("rule", deniedEntry.String()).Warn("check denied by agent deny list")
+		a.sendFailure(event, denyListError(deniedEntry))
+		return
+	}
+
 	// Match check against allow list
 	var matchedEntry allowList
 	var match bool
 	if len(a.allowList) != 0 {
 		logger.WithFields(fields).Debug("matching check against agent allow list")
-		matchedEntry, match = a.matchAllowList(checkConfig.Command)
+		matchedEntry, match = a.matchAllowList(matchCommand)
 		if !match {
 			logger.WithFields(fields).Debug("check does not match agent allow list")
 			a.sendFailure(event, fmt.Errorf(allowListOnDenyOutput))
@@ -154,6 +233,15 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 		logger.WithFields(fields).Debug("check matches agent allow list")
 	}
 
+	// Resolve the secrets of the command and environment variables from the
+	// local providers. The check configuration keeps the placeholders, so the
+	// secrets are never sent to the backend
+	execConfig, secrets, err := a.secrets.resolveCheck(ctx, checkConfig)
+	if err != nil {
+		a.sendFailure(event, fmt.Errorf("error resolving secrets: %s", err))
+		return
+	}
+
 	// Fetch and install all assets required for check execution.
 	logger.WithFields(fields).Debug("fetching assets for check")
 	assets, err := asset.GetAll(ctx, a.assetGetter, checkAssets)
@@ -168,13 +256,23 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 		logger.WithFields(fields).Debug("disabling check env vars per the agent allow list")
 		env = environment.MergeEnvironments(os.Environ(), assets.Env())
 	} else {
-		env = environment.MergeEnvironments(os.Environ(), assets.Env(), checkConfig.EnvVars)
+		env = environment.MergeEnvironments(os.Environ(), assets.Env(), execConfig.EnvVars)
+	}
+
+	// The command executed in the container holds the resolved secrets
+	execCommand := execConfig.Command
+	if checkConfig.Container != nil {
+		logger.WithFields(fields).WithField("image", checkConfig.Container.Image).Debug("executing check in a container")
+		if execCommand, err = containerCommand(execConfig, a.config.ContainerRuntime, containerName); err != nil {
+			a.sendFailure(event, err)
+			return
+		}
 	}
 
 	// Verify sha against the allow list
 	if matchedEntry.Sha512 != "" {
 		logger.WithFields(fields).Debug("matching check sha against agent allow list")
-		path, err := lookPath(strings.Split(checkConfig.Command, " ")[0], env)
+		path, err := lookPath(strings.Split(execCommand, " ")[0], env)
 		if err != nil {
 			logger.WithFields(fields).WithError(err).Error("unable to find the executable path")
 			a.sendFailure(event, fmt.Errorf(allowListOnDenyOutput))
@@ -198,7 +296,7 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 	// are availabe when when the command is executed.
 	ex := command.ExecutionRequest{
 		Env:          env,
-		Command:      checkConfig.Command,
+		Command:      execCommand,
 		Timeout:      int(checkConfig.Timeout),
 		InProgress:   a.inProgress,
 		InProgressMu: a.inProgressMu,
@@ -219,7 +317,12 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 	if err != nil {
 		event.Check.Output = err.Error()
 	} else {
-		event.Check.Output = checkExec.Output
+		event.Check.Output = redactSecrets(checkExec.Output, secrets)
+	}
+
+	// The container keeps running once the container engine client is killed
+	if err == nil && checkExec.TimedOut && containerName != "" {
+		a.removeContainer(checkConfig, containerName, env)
 	}
 
 	event.Check.Duration = checkExec.Duration
@@ -241,9 +344,20 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 		event.Metrics.Handlers = check.OutputMetricHandlers
 	}
 
+	a.publishCheckResult(ctx, request, event, fields, true)
+}
+
+// publishCheckResult executes the hooks of the check, and sends the result of
+// the check to the backend unless the agent event filters drop it. The output
+// artifacts are only uploaded if uploadArtifacts is true, for the commands
+// that passed the deny and allow lists of the agent.
+func (a *Agent) publishCheckResult(ctx context.Context, request *corev2.CheckRequest, event *corev2.Event, fields logrus.Fields, uploadArtifacts bool) {
+	check := event.Check
+	defer a.executions.deliver(event)
+
 	// Execute hooks after we have a completely populated event object
-	if len(checkHooks) != 0 {
-		event.Check.Hooks = a.ExecuteHooks(ctx, request, event, hookAssets)
+	if len(request.Hooks) != 0 {
+		event.Check.Hooks = a.ExecuteHooks(ctx, request, event, request.HookAssets)
 	}
 
 	// The check requested that we discard its output before writing back
@@ -252,6 +366,16 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 		event.Check.Output = ""
 	}
 
+	if a.eventFilter.Filter(event) {
+		logger.WithFields(fields).Debug("check result dropped by the agent event filters")
+		return
+	}
+
+	// Upload the files produced by the check alongside its result
+	if uploadArtifacts && a.artifacts != nil && len(check.OutputArtifacts) > 0 {
+		event.Check.ArtifactLinks = a.artifacts.Upload(ctx, event)
+	}
+
 	msg, err := a.marshal(event)
 	if err != nil {
 		logger.WithError(err).Error("error marshaling check result")
@@ -290,6 +414,7 @@ func prepareCheck(cfg *corev2.CheckConfig, entity *corev2.Entity) error {
 }
 
 func (a *Agent) sendFailure(event *corev2.Event, err error) {
+	defer a.executions.deliver(event)
 	event.Check.Output = err.Error()
 	event.Check.Status = 3
 	event.Entity = a.getAgentEntity()
@@ -328,9 +453,17 @@ func extractMetrics(event *corev2.Event) []*corev2.MetricPoint {
 	case corev2.InfluxDBOutputMetricFormat:
 		transformer = transformers.ParseInflux(event)
 	case corev2.NagiosOutputMetricFormat:
-		transformer = transformers.ParseNagios(event)
+		nagios := transformers.ParseNagios(event)
+		if event.Check.Annotations[transformers.NagiosThresholdsAnnotation] == "true" {
+			annotateNagiosThresholds(event, nagios)
+		}
+		transformer = nagios
 	case corev2.OpenTSDBOutputMetricFormat:
 		transformer = transformers.ParseOpenTSDB(event)
+	case corev2.OpenTelemetryOutputMetricFormat:
+		transformer = transformers.ParseOpenTelemetry(event)
+	case corev2.StatsdOutputMetricFormat:
+		transformer = transformers.ParseStatsd(event)
 	}
 
 	if transformer == nil {
@@ -340,3 +473,22 @@ func extractMetrics(event *corev2.Event) []*corev2.MetricPoint {
 
 	return transformer.Transform()
 }
+
+// annotateNagiosThresholds annotates the check of the event with the state of
+// its Nagios perfdata metrics against their thresholds, so the filters and
+// handlers can act on them.
+func annotateNagiosThresholds(event *corev2.Event, nagios transformers.NagiosList) {
+	annotations := nagios.ThresholdAnnotations()
+	if len(annotations) == 0 {
+		return
+	}
+	// The annotations of the check are shared with its configuration
+	merged := make(map[string]string, len(event.Check.Annotations)+len(annotations))
+	for key, value := range event.Check.Annotations {
+		merged[key] = value
+	}
+	for key, value := range annotations {
+		merged[key] = value
+	}
+	event.Check.Annotations = merged
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sensu/sensu-go/agent/transformers"
 	"github.com/sensu/sensu-go/command"
 	"github.com/sensu/sensu-go/testing/mockexecutor"
 
@@ -476,6 +477,40 @@ func TestExtractMetrics(t *testing.T) {
 			metricFormat:    corev2.NagiosOutputMetricFormat,
 			expectedMetrics: nil,
 		},
+		{
+			name: "valid opentelemetry extraction",
+			event: &corev2.Event{
+				Check: &corev2.Check{
+					Output: `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"queue.depth","gauge":{"dataPoints":[{"asInt":"3","timeUnixNano":"123456789000000000"}]}}]}]}]}`,
+				},
+			},
+			metricFormat: corev2.OpenTelemetryOutputMetricFormat,
+			expectedMetrics: []*corev2.MetricPoint{
+				{
+					Name:      "queue.depth",
+					Value:     3,
+					Timestamp: 123456789,
+					Tags:      []*corev2.MetricTag{},
+				},
+			},
+		},
+		{
+			name: "valid statsd extraction",
+			event: &corev2.Event{
+				Check: &corev2.Check{
+					Output: "queue.depth:3|g|#queue:jobs|T123456789",
+				},
+			},
+			metricFormat: corev2.StatsdOutputMetricFormat,
+			expectedMetrics: []*corev2.MetricPoint{
+				{
+					Name:      "queue.depth",
+					Value:     3,
+					Timestamp: 123456789,
+					Tags:      []*corev2.MetricTag{{Name: "queue", Value: "jobs"}},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -487,6 +522,33 @@ func TestExtractMetrics(t *testing.T) {
 	}
 }
 
+func TestExtractMetricsNagiosThresholds(t *testing.T) {
+	configAnnotations := map[string]string{transformers.NagiosThresholdsAnnotation: "true"}
+	event := &corev2.Event{
+		Check: &corev2.Check{
+			ObjectMeta:         corev2.ObjectMeta{Annotations: configAnnotations},
+			Output:             "LOAD WARNING | load1=1.5;1;2;0;",
+			OutputMetricFormat: corev2.NagiosOutputMetricFormat,
+		},
+	}
+	metrics := extractMetrics(event)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, []*corev2.MetricTag{
+		{Name: "warn", Value: "1"},
+		{Name: "crit", Value: "2"},
+		{Name: "min", Value: "0"},
+	}, metrics[0].Tags)
+	assert.Equal(t, transformers.NagiosThresholdWarning, event.Check.Annotations[transformers.NagiosThresholdAnnotationPrefix+"load1"])
+
+	// The annotations of the check configuration are left unchanged
+	assert.Len(t, configAnnotations, 1)
+
+	// The thresholds only annotate the events of the checks asking for it
+	event.Check.Annotations = nil
+	extractMetrics(event)
+	assert.Empty(t, event.Check.Annotations)
+}
+
 func TestFailOnAssetCheckWithDisabledAssets(t *testing.T) {
 	config, cleanup := FixtureConfig()
 	defer cleanup()
@@ -516,3 +578,74 @@ func TestFailOnAssetCheckWithDisabledAssets(t *testing.T) {
 		t.Errorf("bad status: got %d, want %d", got, want)
 	}
 }
+
+func TestHandleCheckExpired(t *testing.T) {
+	checkConfig := corev2.FixtureCheckConfig("check")
+	request := &corev2.CheckRequest{
+		Config:  checkConfig,
+		Issued:  time.Now().Add(-time.Hour).Unix(),
+		Expires: time.Now().Add(-time.Minute).Unix(),
+	}
+	payload, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	ex := &mockexecutor.MockExecutor{}
+	agent.executor = ex
+	ex.Return(command.FixtureExecutionResponse(0, ""), nil)
+	ch := make(chan *transport.Message, 5)
+	agent.sendq = ch
+
+	// The expired request is discarded without being executed
+	require.NoError(t, agent.handleCheck(context.TODO(), payload))
+	assert.False(t, agent.checkInProgress(request))
+	select {
+	case <-ch:
+		t.Fatal("expired check request executed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The request is executed until it expires
+	request.Expires = time.Now().Add(time.Minute).Unix()
+	payload, err = json.Marshal(request)
+	require.NoError(t, err)
+	require.NoError(t, agent.handleCheck(context.TODO(), payload))
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("check request not executed")
+	}
+}
+
+func TestHandleCheckHeartbeatOnly(t *testing.T) {
+	request := &corev2.CheckRequest{Config: corev2.FixtureCheckConfig("check")}
+	payload, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.HeartbeatOnly = true
+	config.APICheckExecution = true
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	ex := &mockexecutor.MockExecutor{}
+	agent.executor = ex
+	ex.Return(command.FixtureExecutionResponse(0, ""), nil)
+	ch := make(chan *transport.Message, 5)
+	agent.sendq = ch
+
+	assert.Equal(t, "true", agent.buildTransportHeaderMap().Get(transport.HeaderKeyHeartbeatOnly))
+	assert.False(t, agent.config.APICheckExecution)
+
+	// The check request is discarded without being executed
+	require.NoError(t, agent.handleCheck(context.TODO(), payload))
+	assert.False(t, agent.checkInProgress(request))
+	select {
+	case <-ch:
+		t.Fatal("check request executed in heartbeat-only mode")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,339 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCloudMetadataRefreshInterval specifies the default interval (in
+	// seconds) between two queries of the cloud instance metadata.
+	DefaultCloudMetadataRefreshInterval = 300
+
+	// CloudProviderAWS, CloudProviderGCE and CloudProviderAzure are the cloud
+	// providers of which the agent can query the instance metadata.
+	CloudProviderAWS   = "aws"
+	CloudProviderGCE   = "gce"
+	CloudProviderAzure = "azure"
+
+	// cloudMetadataTimeout is the time allowed to each request to the
+	// instance metadata service, which is local to the instance.
+	cloudMetadataTimeout = 2 * time.Second
+
+	// cloudMetadataMaxSize is the maximum size of a response of the instance
+	// metadata service.
+	cloudMetadataMaxSize = 1 << 20
+
+	// cloudTagAnnotationPrefix prefixes the annotations of the entity holding
+	// the tags of the instance.
+	cloudTagAnnotationPrefix = "cloud_tag_"
+)
+
+// cloudMetadataURLs are the base URLs of the instance metadata services of the
+// cloud providers.
+var cloudMetadataURLs = map[string]string{
+	CloudProviderAWS:   "http://169.254.169.254",
+	CloudProviderGCE:   "http://metadata.google.internal",
+	CloudProviderAzure: "http://169.254.169.254",
+}
+
+// cloudMetadataFetchers query the instance metadata service of each cloud
+// provider.
+var cloudMetadataFetchers = map[string]func(context.Context, *http.Client, string) (*cloudMetadata, error){
+	CloudProviderAWS:   fetchAWSMetadata,
+	CloudProviderGCE:   fetchGCEMetadata,
+	CloudProviderAzure: fetchAzureMetadata,
+}
+
+// CloudMetadataConfig configures the enrichment of the agent entity with the
+// metadata of the cloud instance the agent runs on.
+type CloudMetadataConfig struct {
+	// Providers are the cloud providers of which the instance metadata
+	// service is queried, in order, until one answers: aws, gce or azure.
+	// The enrichment is disabled if empty.
+	Providers []string
+
+	// RefreshInterval is the time, in seconds, between two queries of the
+	// instance metadata. The metadata is only queried at startup if 0.
+	RefreshInterval int
+}
+
+// validateCloudProviders returns an error if a cloud provider is unknown.
+func validateCloudProviders(providers []string) error {
+	for _, provider := range providers {
+		if _, ok := cloudMetadataFetchers[provider]; !ok {
+			return fmt.Errorf("unknown cloud metadata provider %q, must be one of %q, %q or %q",
+				provider, CloudProviderAWS, CloudProviderGCE, CloudProviderAzure)
+		}
+	}
+	return nil
+}
+
+// cloudMetadata is the metadata of the cloud instance the agent runs on.
+type cloudMetadata struct {
+	Provider         string
+	InstanceID       string
+	Region           string
+	AvailabilityZone string
+	Tags             map[string]string
+}
+
+// labels returns the labels of the entity describing the instance.
+func (m *cloudMetadata) labels() map[string]string {
+	labels := map[string]string{"cloud_provider": m.Provider}
+	for key, value := range map[string]string{
+		"cloud_instance_id":       m.InstanceID,
+		"cloud_region":            m.Region,
+		"cloud_availability_zone": m.AvailabilityZone,
+	} {
+		if value != "" {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// annotations returns the annotations of the entity holding the tags of the
+// instance.
+func (m *cloudMetadata) annotations() map[string]string {
+	annotations := make(map[string]string, len(m.Tags))
+	for key, value := range m.Tags {
+		annotations[cloudTagAnnotationPrefix+key] = value
+	}
+	return annotations
+}
+
+// fetchCloudMetadata queries the instance metadata service of the providers,
+// in order, and returns the metadata of the first one answering.
+func fetchCloudMetadata(ctx context.Context, providers []string) (*cloudMetadata, error) {
+	client := &http.Client{
+		// The instance metadata service must never be reached through a proxy
+		Transport: &http.Transport{Proxy: nil},
+		Timeout:   cloudMetadataTimeout,
+	}
+	var errs []string
+	for _, provider := range providers {
+		fetch, ok := cloudMetadataFetchers[provider]
+		if !ok {
+			continue
+		}
+		metadata, err := fetch(ctx, client, cloudMetadataURLs[provider])
+		if err == nil {
+			metadata.Provider = provider
+			return metadata, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %s", provider, err))
+	}
+	return nil, errors.New(strings.Join(errs, "; "))
+}
+
+// getCloudMetadata sends a request to the instance metadata service, and
+// returns the body of its response.
+func getCloudMetadata(ctx context.Context, client *http.Client, method, rawURL string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, cloudMetadataMaxSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", method, req.URL.Path, resp.Status)
+	}
+	return body, nil
+}
+
+// fetchAWSMetadata queries the EC2 instance metadata service, with a session
+// token if IMDSv2 is available. The tags are only available if their access is
+// allowed in the metadata options of the instance.
+func fetchAWSMetadata(ctx context.Context, client *http.Client, baseURL string) (*cloudMetadata, error) {
+	header := http.Header{}
+	token, err := getCloudMetadata(ctx, client, http.MethodPut, baseURL+"/latest/api/token", http.Header{
+		"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": []string{"60"},
+	})
+	if err == nil {
+		header.Set("X-Aws-Ec2-Metadata-Token", string(token))
+	} else if _, ok := err.(*url.Error); ok {
+		// The instance metadata service is unreachable. Otherwise, only
+		// IMDSv1 is available.
+		return nil, err
+	}
+
+	body, err := getCloudMetadata(ctx, client, http.MethodGet, baseURL+"/latest/dynamic/instance-identity/document", header)
+	if err != nil {
+		return nil, err
+	}
+	var document struct {
+		InstanceID       string `json:"instanceId"`
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availabilityZone"`
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, fmt.Errorf("invalid instance identity document: %s", err)
+	}
+	metadata := &cloudMetadata{
+		InstanceID:       document.InstanceID,
+		Region:           document.Region,
+		AvailabilityZone: document.AvailabilityZone,
+		Tags:             map[string]string{},
+	}
+
+	keys, err := getCloudMetadata(ctx, client, http.MethodGet, baseURL+"/latest/meta-data/tags/instance", header)
+	if err != nil {
+		logger.WithError(err).Debug("the tags of the instance are not available")
+		return metadata, nil
+	}
+	for _, key := range strings.Fields(string(keys)) {
+		value, err := getCloudMetadata(ctx, client, http.MethodGet, baseURL+"/latest/meta-data/tags/instance/"+url.PathEscape(key), header)
+		if err != nil {
+			return nil, err
+		}
+		metadata.Tags[key] = string(value)
+	}
+	return metadata, nil
+}
+
+// fetchGCEMetadata queries the Compute Engine metadata server. The network
+// tags of the instance are its tags, with empty values.
+func fetchGCEMetadata(ctx context.Context, client *http.Client, baseURL string) (*cloudMetadata, error) {
+	body, err := getCloudMetadata(ctx, client, http.MethodGet, baseURL+"/computeMetadata/v1/instance/?recursive=true", http.Header{
+		"Metadata-Flavor": []string{"Google"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var instance struct {
+		ID   json.Number `json:"id"`
+		Zone string      `json:"zone"`
+		Tags []string    `json:"tags"`
+	}
+	if err := json.Unmarshal(body, &instance); err != nil {
+		return nil, fmt.Errorf("invalid instance metadata: %s", err)
+	}
+	// The zone is of the form projects/<project number>/zones/<zone>, and the
+	// region is the zone without its suffix
+	zone := instance.Zone[strings.LastIndex(instance.Zone, "/")+1:]
+	region := zone
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		region = zone[:i]
+	}
+	metadata := &cloudMetadata{
+		InstanceID:       instance.ID.String(),
+		Region:           region,
+		AvailabilityZone: zone,
+		Tags:             make(map[string]string, len(instance.Tags)),
+	}
+	for _, tag := range instance.Tags {
+		metadata.Tags[tag] = ""
+	}
+	return metadata, nil
+}
+
+// fetchAzureMetadata queries the Azure instance metadata service.
+func fetchAzureMetadata(ctx context.Context, client *http.Client, baseURL string) (*cloudMetadata, error) {
+	body, err := getCloudMetadata(ctx, client, http.MethodGet, baseURL+"/metadata/instance?api-version=2021-02-01", http.Header{
+		"Metadata": []string{"true"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var instance struct {
+		Compute struct {
+			VMID     string `json:"vmId"`
+			Location string `json:"location"`
+			Zone     string `json:"zone"`
+			TagsList []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"tagsList"`
+		} `json:"compute"`
+	}
+	if err := json.Unmarshal(body, &instance); err != nil {
+		return nil, fmt.Errorf("invalid instance metadata: %s", err)
+	}
+	metadata := &cloudMetadata{
+		InstanceID:       instance.Compute.VMID,
+		Region:           instance.Compute.Location,
+		AvailabilityZone: instance.Compute.Zone,
+		Tags:             make(map[string]string, len(instance.Compute.TagsList)),
+	}
+	for _, tag := range instance.Compute.TagsList {
+		metadata.Tags[tag.Name] = tag.Value
+	}
+	return metadata, nil
+}
+
+// refreshCloudMetadata queries the metadata of the cloud instance, and
+// rebuilds the entity of the agent if it changed. The previous metadata is
+// kept if the query fails.
+func (a *Agent) refreshCloudMetadata(ctx context.Context) {
+	metadata, err := fetchCloudMetadata(ctx, a.config.CloudMetadata.Providers)
+	if err != nil {
+		logger.WithError(err).Warn("could not query the cloud instance metadata")
+		return
+	}
+
+	a.entityMu.Lock()
+	changed := !reflect.DeepEqual(a.cloudMetadata, metadata)
+	if changed {
+		a.cloudMetadata = metadata
+		a.entity = nil
+	}
+	a.entityMu.Unlock()
+	if !changed {
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"provider":    metadata.Provider,
+		"instance_id": metadata.InstanceID,
+	}).Info("enriched the agent entity with the cloud instance metadata")
+	// Send a keepalive at once, so the backend knows of the new labels and
+	// annotations
+	select {
+	case a.keepaliveReset <- struct{}{}:
+	default:
+	}
+}
+
+// refreshCloudMetadataPeriodically queries the metadata of the cloud instance
+// at once, then at every refresh interval.
+func (a *Agent) refreshCloudMetadataPeriodically(ctx context.Context) {
+	defer logger.Debug("shutting down cloud metadata collector")
+	a.refreshCloudMetadata(ctx)
+	if a.config.CloudMetadata.RefreshInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(a.config.CloudMetadata.RefreshInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.refreshCloudMetadata(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withCloudMetadataURL points the instance metadata service of the provider to
+// the test server until the test ends.
+func withCloudMetadataURL(t *testing.T, provider string, handler http.Handler) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	previous := cloudMetadataURLs[provider]
+	cloudMetadataURLs[provider] = server.URL
+	t.Cleanup(func() {
+		cloudMetadataURLs[provider] = previous
+		server.Close()
+	})
+}
+
+func awsMetadataHandler(imdsv2 bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		if !imdsv2 || r.Method != http.MethodPut {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("token"))
+	})
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if imdsv2 && r.Header.Get("X-Aws-Ec2-Metadata-Token") != "token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return false
+		}
+		return true
+	}
+	mux.HandleFunc("/latest/dynamic/instance-identity/document", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			_, _ = w.Write([]byte(`{"instanceId":"i-0123456789","region":"us-west-2","availabilityZone":"us-west-2b"}`))
+		}
+	})
+	mux.HandleFunc("/latest/meta-data/tags/instance", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			_, _ = w.Write([]byte("Name\nteam"))
+		}
+	})
+	mux.HandleFunc("/latest/meta-data/tags/instance/", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		switch r.URL.Path {
+		case "/latest/meta-data/tags/instance/Name":
+			_, _ = w.Write([]byte("web-1"))
+		case "/latest/meta-data/tags/instance/team":
+			_, _ = w.Write([]byte("ops"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	return mux
+}
+
+func TestFetchCloudMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		handler  http.Handler
+		want     *cloudMetadata
+	}{
+		{
+			name:     "aws imdsv2",
+			provider: CloudProviderAWS,
+			handler:  awsMetadataHandler(true),
+			want: &cloudMetadata{
+				Provider:         CloudProviderAWS,
+				InstanceID:       "i-0123456789",
+				Region:           "us-west-2",
+				AvailabilityZone: "us-west-2b",
+				Tags:             map[string]string{"Name": "web-1", "team": "ops"},
+			},
+		},
+		{
+			name:     "aws imdsv1",
+			provider: CloudProviderAWS,
+			handler:  awsMetadataHandler(false),
+			want: &cloudMetadata{
+				Provider:         CloudProviderAWS,
+				InstanceID:       "i-0123456789",
+				Region:           "us-west-2",
+				AvailabilityZone: "us-west-2b",
+				Tags:             map[string]string{"Name": "web-1", "team": "ops"},
+			},
+		},
+		{
+			name:     "gce",
+			provider: CloudProviderGCE,
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Metadata-Flavor") != "Google" || r.URL.Path != "/computeMetadata/v1/instance/" {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(`{"id":4520031799277581759,"zone":"projects/123456/zones/us-central1-a","tags":["http-server"]}`))
+			}),
+			want: &cloudMetadata{
+				Provider:         CloudProviderGCE,
+				InstanceID:       "4520031799277581759",
+				Region:           "us-central1",
+				AvailabilityZone: "us-central1-a",
+				Tags:             map[string]string{"http-server": ""},
+			},
+		},
+		{
+			name:     "azure",
+			provider: CloudProviderAzure,
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Metadata") != "true" || r.URL.Path != "/metadata/instance" {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(`{"compute":{"vmId":"02aab8a4-74ef-476e-8182-f6d2ba4166a6","location":"westeurope","zone":"2","tagsList":[{"name":"team","value":"ops"}]}}`))
+			}),
+			want: &cloudMetadata{
+				Provider:         CloudProviderAzure,
+				InstanceID:       "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+				Region:           "westeurope",
+				AvailabilityZone: "2",
+				Tags:             map[string]string{"team": "ops"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withCloudMetadataURL(t, tt.provider, tt.handler)
+			metadata, err := fetchCloudMetadata(context.Background(), []string{tt.provider})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, metadata)
+		})
+	}
+}
+
+func TestFetchCloudMetadataFallback(t *testing.T) {
+	withCloudMetadataURL(t, CloudProviderGCE, http.NotFoundHandler())
+	withCloudMetadataURL(t, CloudProviderAWS, awsMetadataHandler(true))
+
+	metadata, err := fetchCloudMetadata(context.Background(), []string{CloudProviderGCE, CloudProviderAWS})
+	require.NoError(t, err)
+	assert.Equal(t, CloudProviderAWS, metadata.Provider)
+
+	_, err = fetchCloudMetadata(context.Background(), []string{CloudProviderGCE})
+	assert.Error(t, err)
+}
+
+func TestRefreshCloudMetadata(t *testing.T) {
+	withCloudMetadataURL(t, CloudProviderAWS, awsMetadataHandler(true))
+
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.Labels = map[string]string{"cloud_region": "override"}
+	config.CloudMetadata = &CloudMetadataConfig{Providers: []string{CloudProviderAWS}}
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+
+	// The entity is rebuilt once the metadata is known
+	assert.Empty(t, agent.getAgentEntity().Labels["cloud_instance_id"])
+	agent.refreshCloudMetadata(context.Background())
+	entity := agent.getAgentEntity()
+	assert.Equal(t, "aws", entity.Labels["cloud_provider"])
+	assert.Equal(t, "i-0123456789", entity.Labels["cloud_instance_id"])
+	assert.Equal(t, "us-west-2b", entity.Labels["cloud_availability_zone"])
+	assert.Equal(t, "override", entity.Labels["cloud_region"])
+	assert.Equal(t, "web-1", entity.Annotations["cloud_tag_Name"])
+	assert.Len(t, agent.keepaliveReset, 1)
+
+	// The entity is left unchanged if the metadata did not change
+	<-agent.keepaliveReset
+	agent.refreshCloudMetadata(context.Background())
+	assert.True(t, entity == agent.getAgentEntity())
+	assert.Len(t, agent.keepaliveReset, 0)
+}
+
+func TestNewAgentUnknownCloudProvider(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.CloudMetadata = &CloudMetadataConfig{Providers: []string{"digitalocean"}}
+	_, err := NewAgent(config)
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows/svc/debug"
+)
+
+const (
+	// eventIDService is the event ID of the service lifecycle events.
+	eventIDService = 1
+
+	// eventIDAgent is the event ID of the warnings and errors logged by the
+	// agent.
+	eventIDAgent = 2
+)
+
+// eventLogHook writes the warnings and errors logged by the agent to the
+// Windows event log, in addition to its log file.
+type eventLogHook struct {
+	log debug.Log
+}
+
+func newEventLogHook(log debug.Log) *eventLogHook {
+	return &eventLogHook{log: log}
+}
+
+// Levels returns the levels of the entries written to the event log.
+func (h *eventLogHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}
+}
+
+// Fire writes the entry to the event log.
+func (h *eventLogHook) Fire(entry *logrus.Entry) error {
+	msg, err := entry.String()
+	if err != nil {
+		return err
+	}
+	if entry.Level == logrus.WarnLevel {
+		return h.log.Warning(eventIDAgent, msg)
+	}
+	return h.log.Error(eventIDAgent, msg)
+}
@@ -10,12 +10,53 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
+	"unsafe"
 
+	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/svc"
 	"golang.org/x/sys/windows/svc/eventlog"
 	"golang.org/x/sys/windows/svc/mgr"
 )
 
+const (
+	// serviceConfigDelayedAutoStartInfo and serviceConfigFailureActionsFlag
+	// are the SERVICE_CONFIG_DELAYED_AUTO_START_INFO and
+	// SERVICE_CONFIG_FAILURE_ACTIONS_FLAG info levels of
+	// ChangeServiceConfig2.
+	serviceConfigDelayedAutoStartInfo = 3
+	serviceConfigFailureActionsFlag   = 4
+
+	// serviceRecoveryResetPeriod is the time, in seconds, without failure
+	// after which the failure count of the service is reset.
+	serviceRecoveryResetPeriod = 24 * 60 * 60
+
+	// serviceControlTimeout is the time to wait for the service to start or
+	// to stop.
+	serviceControlTimeout = 30 * time.Second
+)
+
+// serviceOptions are the settings of the installed service.
+type serviceOptions struct {
+	// StartType is one of mgr.StartAutomatic or mgr.StartManual.
+	StartType uint32
+
+	// DelayedAutoStart delays the automatic start of the service until the
+	// other automatic services are started.
+	DelayedAutoStart bool
+
+	// User and Password are the account the service runs as.
+	User     string
+	Password string
+
+	// RestartDelay is the time the service control manager waits before
+	// restarting the failed service.
+	RestartDelay time.Duration
+
+	// Start starts the service once installed.
+	Start bool
+}
+
 func exePath() (string, error) {
 	prog := os.Args[0]
 	p, err := filepath.Abs(prog)
@@ -42,7 +83,7 @@ func exePath() (string, error) {
 	return "", err
 }
 
-func installService(name, displayName, desc string, args ...string) error {
+func installService(name, displayName, desc string, opts serviceOptions, args ...string) error {
 	exepath, err := exePath()
 	if err != nil {
 		return err
@@ -60,20 +101,54 @@ func installService(name, displayName, desc string, args ...string) error {
 	s, err = m.CreateService(name, exepath, mgr.Config{
 		Description:      desc,
 		DisplayName:      displayName,
-		ServiceStartName: serviceUser,
-		StartType:        mgr.StartAutomatic,
+		ServiceStartName: opts.User,
+		Password:         opts.Password,
+		StartType:        opts.StartType,
 	}, args...)
 	if err != nil {
 		return err
 	}
 	defer s.Close()
+	if err := configureService(s, opts); err != nil {
+		s.Delete()
+		return err
+	}
 	err = eventlog.InstallAsEventCreate(name, eventlog.Error|eventlog.Warning|eventlog.Info)
 	if err != nil {
 		s.Delete()
 		return fmt.Errorf("SetupEventLogSource() failed: %s", err)
 	}
 
-	return s.Start(args...)
+	if !opts.Start {
+		return nil
+	}
+	return startService(s)
+}
+
+// configureService sets the delayed automatic start and the recovery actions
+// of the service. The service control manager restarts the service whenever
+// it fails, including when it stops with an error.
+func configureService(s *mgr.Service, opts serviceOptions) error {
+	if opts.DelayedAutoStart {
+		info := struct{ DelayedAutoStart int32 }{1}
+		if err := windows.ChangeServiceConfig2(s.Handle, serviceConfigDelayedAutoStartInfo, (*byte)(unsafe.Pointer(&info))); err != nil {
+			return fmt.Errorf("error setting the delayed automatic start: %s", err)
+		}
+	}
+
+	actions := []mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: opts.RestartDelay},
+		{Type: mgr.ServiceRestart, Delay: opts.RestartDelay},
+		{Type: mgr.ServiceRestart, Delay: opts.RestartDelay},
+	}
+	if err := s.SetRecoveryActions(actions, serviceRecoveryResetPeriod); err != nil {
+		return fmt.Errorf("error setting the recovery actions: %s", err)
+	}
+	flag := struct{ FailureActionsOnNonCrashFailures int32 }{1}
+	if err := windows.ChangeServiceConfig2(s.Handle, serviceConfigFailureActionsFlag, (*byte)(unsafe.Pointer(&flag))); err != nil {
+		return fmt.Errorf("error setting the recovery actions: %s", err)
+	}
+	return nil
 }
 
 func removeService(name string) error {
@@ -89,7 +164,7 @@ func removeService(name string) error {
 	defer s.Close()
 
 	// Attempt to stop the service, but don't return the error if it fails.
-	_, _ = s.Control(svc.Stop)
+	_ = stopService(s)
 
 	if err := s.Delete(); err != nil {
 		return fmt.Errorf("error uninstalling service: %s", err)
@@ -99,3 +174,96 @@ func removeService(name string) error {
 	}
 	return nil
 }
+
+// openService opens the installed service, and returns a function closing
+// the service and its manager.
+func openService(name string) (*mgr.Service, func(), error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := m.OpenService(name)
+	if err != nil {
+		m.Disconnect()
+		return nil, nil, fmt.Errorf("service %s is not installed", name)
+	}
+	return s, func() {
+		s.Close()
+		m.Disconnect()
+	}, nil
+}
+
+// startService starts the service and waits until it runs.
+func startService(s *mgr.Service) error {
+	if err := s.Start(); err != nil {
+		return fmt.Errorf("error starting service: %s", err)
+	}
+	return waitService(s, svc.Running)
+}
+
+// stopService stops the service and waits until it is stopped.
+func stopService(s *mgr.Service) error {
+	status, err := s.Query()
+	if err != nil {
+		return err
+	}
+	if status.State == svc.Stopped {
+		return nil
+	}
+	if _, err := s.Control(svc.Stop); err != nil {
+		return fmt.Errorf("error stopping service: %s", err)
+	}
+	return waitService(s, svc.Stopped)
+}
+
+// waitService waits until the service reaches the given state.
+func waitService(s *mgr.Service, state svc.State) error {
+	deadline := time.Now().Add(serviceControlTimeout)
+	for {
+		status, err := s.Query()
+		if err != nil {
+			return err
+		}
+		if status.State == state {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout waiting for service to reach state %s", serviceStateString(state))
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+}
+
+// serviceStateString returns the name of a service state.
+func serviceStateString(state svc.State) string {
+	switch state {
+	case svc.Stopped:
+		return "stopped"
+	case svc.StartPending:
+		return "start pending"
+	case svc.StopPending:
+		return "stop pending"
+	case svc.Running:
+		return "running"
+	case svc.ContinuePending:
+		return "continue pending"
+	case svc.PausePending:
+		return "pause pending"
+	case svc.Paused:
+		return "paused"
+	}
+	return fmt.Sprintf("unknown (%d)", state)
+}
+
+// serviceStartTypeString returns the name of a service start type.
+func serviceStartTypeString(startType uint32) string {
+	switch startType {
+	case mgr.StartAutomatic:
+		return "automatic"
+	case mgr.StartManual:
+		return "manual"
+	case mgr.StartDisabled:
+		return "disabled"
+	}
+	return fmt.Sprintf("unknown (%d)", startType)
+}
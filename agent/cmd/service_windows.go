@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -15,6 +14,10 @@ import (
 	"golang.org/x/sys/windows/svc/eventlog"
 )
 
+// serviceExitCodeError is the service specific exit code of the service when
+// the agent exits with an error.
+const serviceExitCodeError = 1
+
 var (
 	_    svc.Handler = &Service{}
 	elog debug.Log
@@ -39,9 +42,8 @@ func (s *Service) start(ctx context.Context, args []string, changes chan<- svc.S
 	go func() {
 		defer func() {
 			if e := recover(); e != nil {
-				changes <- svc.Status{State: svc.Stopped}
 				stack := runtimedebug.Stack()
-				result <- errors.New(string(stack))
+				result <- fmt.Errorf("%v: %s", e, stack)
 			}
 		}()
 		defer s.wg.Done()
@@ -49,47 +51,53 @@ func (s *Service) start(ctx context.Context, args []string, changes chan<- svc.S
 		// Start service here
 		binPath, err := exePath()
 		if err != nil {
-			panic(err)
+			result <- err
+			return
 		}
 		configFile := args[0]
 		logPath := args[1]
 		logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
 		if err != nil {
-			result <- fmt.Errorf("service quit: cant't open log file: %s", err)
+			result <- fmt.Errorf("service quit: can't open log file: %s", err)
+			return
 		}
 		defer logFile.Close()
 
-		logger := logrus.New()
-		logger.SetFormatter(&logrus.JSONFormatter{})
-		logger.SetOutput(logFile)
-		entry := logger.WithFields(logrus.Fields{
+		// The agent logs to the log file, and its warnings and errors to the
+		// event log as well
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+		logrus.SetOutput(logFile)
+		logrus.AddHook(newEventLogHook(elog))
+		logger := logrus.WithFields(logrus.Fields{
 			"component": "cmd",
 		})
 
 		args = []string{binPath, "start", "-c", configFile}
-		command := newStartCommand(ctx, args, entry)
+		command := newStartCommand(ctx, args, logger)
 		accepts := svc.AcceptShutdown | svc.AcceptStop
 		changes <- svc.Status{State: svc.Running, Accepts: accepts}
 
-		if err := command.Execute(); err != nil {
+		err = command.Execute()
+		if err != nil {
 			logger.WithError(err).Error("sensu-agent exited with error")
-			result <- err
 		}
+		result <- err
 	}()
 	return result
 }
 
 func (s *Service) Execute(_ []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
 	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	errs := s.start(ctx, s.args, changes)
-	elog, _ := eventlog.Open(serviceName)
-	defer elog.Close()
 	for {
 		select {
 		case req := <-r:
 			switch req.Cmd {
+			case svc.Interrogate:
+				changes <- req.CurrentStatus
 			case svc.Stop, svc.Shutdown:
-				elog.Info(1, "service shutting down")
+				elog.Info(eventIDService, "service shutting down")
 				changes <- svc.Status{State: svc.StopPending}
 				cancel()
 				s.wg.Wait()
@@ -97,23 +105,29 @@ func (s *Service) Execute(_ []string, r <-chan svc.ChangeRequest, changes chan<-
 				return false, 0
 			}
 		case err := <-errs:
-			elog.Error(1, fmt.Sprintf("restarting due to error (%v) %s", s.args, err))
-			s.start(ctx, s.args, changes)
+			if err == nil {
+				elog.Info(eventIDService, "sensu-agent exited")
+				return false, 0
+			}
+			// The service stops with an error, for the service control
+			// manager to apply its recovery actions
+			elog.Error(eventIDService, fmt.Sprintf("sensu-agent exited with error (%v): %s", s.args, err))
+			return true, serviceExitCodeError
 		}
 	}
-	return false, 0
 }
 
 func runService(args []string) error {
-	elog, err := eventlog.Open(serviceName)
+	var err error
+	elog, err = eventlog.Open(serviceName)
 	if err != nil {
 		return err
 	}
 	defer elog.Close()
-	elog.Info(1, fmt.Sprintf("starting %s service (%v)", serviceName, args))
+	elog.Info(eventIDService, fmt.Sprintf("starting %s service (%v)", serviceName, args))
 	if err := svc.Run(serviceName, NewService(args)); err != nil {
 		return err
 	}
-	elog.Info(1, fmt.Sprintf("%s service terminated", serviceName))
+	elog.Info(eventIDService, fmt.Sprintf("%s service terminated", serviceName))
 	return nil
 }
@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"syscall"
 
 	"github.com/sensu/sensu-go/agent"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
 	"github.com/sensu/sensu-go/util/path"
 	"github.com/sensu/sensu-go/util/url"
 	"github.com/sensu/sensu-go/version"
@@ -28,44 +33,93 @@ const (
 	// specified in backend urls
 	DefaultBackendPort = "8081"
 
-	flagAgentName                = "name"
-	flagAPIHost                  = "api-host"
-	flagAPIPort                  = "api-port"
-	flagBackendURL               = "backend-url"
-	flagCacheDir                 = "cache-dir"
-	flagConfigFile               = "config-file"
-	flagDeregister               = "deregister"
-	flagDeregistrationHandler    = "deregistration-handler"
-	flagEventsRateLimit          = "events-rate-limit"
-	flagEventsBurstLimit         = "events-burst-limit"
-	flagKeepaliveInterval        = "keepalive-interval"
-	flagKeepaliveTimeout         = "keepalive-timeout"
-	flagNamespace                = "namespace"
-	flagPassword                 = "password"
-	flagRedact                   = "redact"
-	flagSocketHost               = "socket-host"
-	flagSocketPort               = "socket-port"
-	flagStatsdDisable            = "statsd-disable"
-	flagStatsdEventHandlers      = "statsd-event-handlers"
-	flagStatsdFlushInterval      = "statsd-flush-interval"
-	flagStatsdMetricsHost        = "statsd-metrics-host"
-	flagStatsdMetricsPort        = "statsd-metrics-port"
-	flagSubscriptions            = "subscriptions"
-	flagUser                     = "user"
-	flagDisableAPI               = "disable-api"
-	flagDisableAssets            = "disable-assets"
-	flagDisableSockets           = "disable-sockets"
-	flagLogLevel                 = "log-level"
-	flagLabels                   = "labels"
-	flagAnnotations              = "annotations"
-	flagAllowList                = "allow-list"
-	flagBackendHandshakeTimeout  = "backend-handshake-timeout"
-	flagBackendHeartbeatInterval = "backend-heartbeat-interval"
-	flagBackendHeartbeatTimeout  = "backend-heartbeat-timeout"
+	flagAgentName                 = "name"
+	flagAPIHost                   = "api-host"
+	flagAPIPort                   = "api-port"
+	flagBackendURL                = "backend-url"
+	flagCacheDir                  = "cache-dir"
+	flagConfigFile                = "config-file"
+	flagDeregister                = "deregister"
+	flagDeregistrationHandler     = "deregistration-handler"
+	flagEventAcks                 = "event-acks"
+	flagEventAckTimeout           = "event-ack-timeout"
+	flagEventsRateLimit           = "events-rate-limit"
+	flagEventsBurstLimit          = "events-burst-limit"
+	flagEventsDedupWindow         = "events-dedup-window"
+	flagEventsDropOKMetricOnly    = "events-drop-ok-metric-only"
+	flagEventsSampleRate          = "events-sample-rate"
+	flagEventsFilters             = "events-filters"
+	flagKeepaliveInterval         = "keepalive-interval"
+	flagKeepaliveTimeout          = "keepalive-timeout"
+	flagKeepaliveInterfaces       = "keepalive-network-interfaces"
+	flagKeepaliveInventory        = "keepalive-inventory-interval"
+	flagNamespace                 = "namespace"
+	flagPassword                  = "password"
+	flagRedact                    = "redact"
+	flagSocketHost                = "socket-host"
+	flagSocketPort                = "socket-port"
+	flagSocketToken               = "socket-token"
+	flagSocketStrict              = "socket-strict"
+	flagSocketRateLimit           = "socket-rate-limit"
+	flagSocketBurstLimit          = "socket-burst-limit"
+	flagStatsdDisable             = "statsd-disable"
+	flagStatsdEventHandlers       = "statsd-event-handlers"
+	flagStatsdFlushInterval       = "statsd-flush-interval"
+	flagStatsdMetricsHost         = "statsd-metrics-host"
+	flagStatsdMetricsPort         = "statsd-metrics-port"
+	flagSubscriptions             = "subscriptions"
+	flagUser                      = "user"
+	flagDisableAPI                = "disable-api"
+	flagAPICheckExecution         = "api-check-execution"
+	flagDisableAssets             = "disable-assets"
+	flagAssetsCacheMaxSize        = "assets-cache-max-size"
+	flagAssetsCacheRetention      = "assets-cache-retention"
+	flagDisableSockets            = "disable-sockets"
+	flagMinimal                   = "minimal"
+	flagHeartbeatOnly             = "heartbeat-only"
+	flagLogLevel                  = "log-level"
+	flagLabels                    = "labels"
+	flagAnnotations               = "annotations"
+	flagLabelsDir                 = "labels-dir"
+	flagAnnotationsDir            = "annotations-dir"
+	flagArtifactsURL              = "artifacts-url"
+	flagArtifactsMaxSize          = "artifacts-max-size"
+	flagArtifactsAllowedDirs      = "artifacts-allowed-dirs"
+	flagOfflineSpoolMaxSize       = "offline-spool-max-size"
+	flagOfflineSpoolMaxAge        = "offline-spool-max-age"
+	flagAllowList                 = "allow-list"
+	flagDenyList                  = "deny-list"
+	flagCloudMetadata             = "cloud-metadata"
+	flagCloudMetadataInterval     = "cloud-metadata-refresh-interval"
+	flagSecretsProviders          = "secrets-providers"
+	flagSecretsFileDir            = "secrets-file-dir"
+	flagSecretsExecCommand        = "secrets-exec-command"
+	flagSecretsEnvReferences      = "secrets-env-references"
+	flagSecretsFileReferences     = "secrets-file-references"
+	flagSecretsExecReferences     = "secrets-exec-references"
+	flagMaxConcurrentChecks       = "max-concurrent-checks"
+	flagContainerRuntime          = "container-runtime"
+	flagContainerChecks           = "container-checks"
+	flagContainerImages           = "container-images"
+	flagContainerHostVolumes      = "container-host-volumes"
+	flagContainerHostNetwork      = "container-host-network"
+	flagBackendHandshakeTimeout   = "backend-handshake-timeout"
+	flagBackendHeartbeatInterval  = "backend-heartbeat-interval"
+	flagBackendHeartbeatTimeout   = "backend-heartbeat-timeout"
+	flagBackendHeartbeatMaxMissed = "backend-heartbeat-max-missed"
+	flagBackendReconnectThreshold = "backend-reconnect-threshold"
+	flagBackendWeight             = "backend-weight"
+	flagBackendFailbackInterval   = "backend-failback-interval"
+	flagBackendCompression        = "backend-websocket-compression"
+	flagBackendCompressionLevel   = "backend-websocket-compression-level"
 
 	// TLS flags
+	flagCertFile              = "cert-file"
+	flagKeyFile               = "key-file"
 	flagTrustedCAFile         = "trusted-ca-file"
 	flagInsecureSkipTLSVerify = "insecure-skip-tls-verify"
+	flagTLSReloadInterval     = "tls-reload-interval"
+	flagEnrollmentToken       = "enrollment-token"
 
 	deprecatedFlagAgentID = "id"
 )
@@ -88,6 +142,7 @@ func newVersionCommand() *cobra.Command {
 
 func newStartCommand(ctx context.Context, args []string, logger *logrus.Entry) *cobra.Command {
 	var setupErr error
+	var configFile string
 
 	cmd := &cobra.Command{
 		Use:           "start",
@@ -101,81 +156,28 @@ func newStartCommand(ctx context.Context, args []string, logger *logrus.Entry) *
 			if setupErr != nil {
 				return setupErr
 			}
-			level, err := logrus.ParseLevel(viper.GetString(flagLogLevel))
+			cfg, err := newAgentConfig(cmd)
 			if err != nil {
 				return err
 			}
-			logrus.SetLevel(level)
-
-			cfg := agent.NewConfig()
-			cfg.API.Host = viper.GetString(flagAPIHost)
-			cfg.API.Port = viper.GetInt(flagAPIPort)
-			cfg.CacheDir = viper.GetString(flagCacheDir)
-			cfg.Deregister = viper.GetBool(flagDeregister)
-			cfg.DeregistrationHandler = viper.GetString(flagDeregistrationHandler)
-			cfg.DisableAssets = viper.GetBool(flagDisableAssets)
-			cfg.EventsAPIRateLimit = rate.Limit(viper.GetFloat64(flagEventsRateLimit))
-			cfg.EventsAPIBurstLimit = viper.GetInt(flagEventsBurstLimit)
-			cfg.KeepaliveInterval = uint32(viper.GetInt(flagKeepaliveInterval))
-			cfg.KeepaliveTimeout = uint32(viper.GetInt(flagKeepaliveTimeout))
-			cfg.Namespace = viper.GetString(flagNamespace)
-			cfg.Password = viper.GetString(flagPassword)
-			cfg.Socket.Host = viper.GetString(flagSocketHost)
-			cfg.Socket.Port = viper.GetInt(flagSocketPort)
-			cfg.StatsdServer.Disable = viper.GetBool(flagStatsdDisable)
-			cfg.StatsdServer.FlushInterval = viper.GetInt(flagStatsdFlushInterval)
-			cfg.StatsdServer.Host = viper.GetString(flagStatsdMetricsHost)
-			cfg.StatsdServer.Port = viper.GetInt(flagStatsdMetricsPort)
-			cfg.StatsdServer.Handlers = viper.GetStringSlice(flagStatsdEventHandlers)
-			cfg.Labels = viper.GetStringMapString(flagLabels)
-			cfg.Annotations = viper.GetStringMapString(flagAnnotations)
-			cfg.User = viper.GetString(flagUser)
-			cfg.AllowList = viper.GetString(flagAllowList)
-			cfg.BackendHandshakeTimeout = viper.GetInt(flagBackendHandshakeTimeout)
-			cfg.BackendHeartbeatInterval = viper.GetInt(flagBackendHeartbeatInterval)
-			cfg.BackendHeartbeatTimeout = viper.GetInt(flagBackendHeartbeatTimeout)
-
-			// TLS configuration
-			cfg.TLS = &corev2.TLSOptions{}
-			cfg.TLS.TrustedCAFile = viper.GetString(flagTrustedCAFile)
-			cfg.TLS.InsecureSkipVerify = viper.GetBool(flagInsecureSkipTLSVerify)
-
-			agentName := viper.GetString(flagAgentName)
-			if agentName != "" {
-				cfg.AgentName = agentName
-			}
-
-			for _, backendURL := range viper.GetStringSlice(flagBackendURL) {
-				newURL, err := url.AppendPortIfMissing(backendURL, DefaultBackendPort)
-				if err != nil {
-					return err
-				}
-				cfg.BackendURLs = append(cfg.BackendURLs, newURL)
-			}
-
-			cfg.Redact = viper.GetStringSlice(flagRedact)
-			cfg.Subscriptions = viper.GetStringSlice(flagSubscriptions)
-
-			// Workaround for https://github.com/sensu/sensu-go/issues/2357. Detect if
-			// the flags for labels and annotations were changed. If so, use their
-			// values since flags take precedence over config
-			if flag := cmd.Flags().Lookup(flagLabels); flag != nil && flag.Changed {
-				cfg.Labels = labels
-			}
-			if flag := cmd.Flags().Lookup(flagAnnotations); flag != nil && flag.Changed {
-				cfg.Annotations = annotations
-			}
 
 			sensuAgent, err := agent.NewAgent(cfg)
 			if err != nil {
 				return err
 			}
+			sensuAgent.SetConfigReloader(func() (*agent.Config, error) {
+				if err := viper.ReadInConfig(); err != nil && configFile != "" {
+					return nil, err
+				}
+				return newAgentConfig(cmd)
+			})
+			go reloadOnSignal(ctx, sensuAgent, logger)
 
-			if !viper.GetBool(flagDisableAPI) {
+			if !cfg.DisableAPI {
 				sensuAgent.StartAPI(ctx)
 			}
 
-			if !viper.GetBool(flagDisableSockets) {
+			if !cfg.DisableSockets {
 				// Agent TCP/UDP sockets are deprecated in favor of the agent rest api
 				sensuAgent.StartSocketListeners(ctx)
 			}
@@ -191,7 +193,7 @@ func newStartCommand(ctx context.Context, args []string, logger *logrus.Entry) *
 	_ = configFlagSet.Parse(args[1:])
 
 	// Get the given config file path
-	configFile, _ := configFlagSet.GetString(flagConfigFile)
+	configFile, _ = configFlagSet.GetString(flagConfigFile)
 	configFilePath := configFile
 
 	// use the default config path if flagConfigFile was not used
@@ -212,17 +214,34 @@ func newStartCommand(ctx context.Context, args []string, logger *logrus.Entry) *
 	viper.SetDefault(flagDeregister, false)
 	viper.SetDefault(flagDeregistrationHandler, "")
 	viper.SetDefault(flagDisableAPI, false)
+	viper.SetDefault(flagAPICheckExecution, false)
 	viper.SetDefault(flagDisableSockets, false)
 	viper.SetDefault(flagDisableAssets, false)
+	viper.SetDefault(flagAssetsCacheMaxSize, 0)
+	viper.SetDefault(flagAssetsCacheRetention, 0)
+	viper.SetDefault(flagMinimal, false)
+	viper.SetDefault(flagHeartbeatOnly, false)
+	viper.SetDefault(flagEventAcks, false)
+	viper.SetDefault(flagEventAckTimeout, agent.DefaultEventAckTimeout)
 	viper.SetDefault(flagEventsRateLimit, agent.DefaultEventsAPIRateLimit)
 	viper.SetDefault(flagEventsBurstLimit, agent.DefaultEventsAPIBurstLimit)
+	viper.SetDefault(flagEventsDedupWindow, 0)
+	viper.SetDefault(flagEventsDropOKMetricOnly, false)
+	viper.SetDefault(flagEventsSampleRate, 0)
+	viper.SetDefault(flagEventsFilters, []string{})
 	viper.SetDefault(flagKeepaliveInterval, agent.DefaultKeepaliveInterval)
 	viper.SetDefault(flagKeepaliveTimeout, corev2.DefaultKeepaliveTimeout)
+	viper.SetDefault(flagKeepaliveInterfaces, agent.KeepaliveNetworkInterfacesAll)
+	viper.SetDefault(flagKeepaliveInventory, 0)
 	viper.SetDefault(flagNamespace, agent.DefaultNamespace)
 	viper.SetDefault(flagPassword, agent.DefaultPassword)
 	viper.SetDefault(flagRedact, corev2.DefaultRedactFields)
 	viper.SetDefault(flagSocketHost, agent.DefaultSocketHost)
 	viper.SetDefault(flagSocketPort, agent.DefaultSocketPort)
+	viper.SetDefault(flagSocketToken, "")
+	viper.SetDefault(flagSocketStrict, false)
+	viper.SetDefault(flagSocketRateLimit, 0)
+	viper.SetDefault(flagSocketBurstLimit, agent.DefaultSocketBurstLimit)
 	viper.SetDefault(flagStatsdDisable, agent.DefaultStatsdDisable)
 	viper.SetDefault(flagStatsdFlushInterval, agent.DefaultStatsdFlushInterval)
 	viper.SetDefault(flagStatsdMetricsHost, agent.DefaultStatsdMetricsHost)
@@ -230,12 +249,43 @@ func newStartCommand(ctx context.Context, args []string, logger *logrus.Entry) *
 	viper.SetDefault(flagStatsdEventHandlers, []string{})
 	viper.SetDefault(flagSubscriptions, []string{})
 	viper.SetDefault(flagUser, agent.DefaultUser)
+	viper.SetDefault(flagCertFile, "")
+	viper.SetDefault(flagKeyFile, "")
 	viper.SetDefault(flagTrustedCAFile, "")
 	viper.SetDefault(flagInsecureSkipTLSVerify, false)
+	viper.SetDefault(flagTLSReloadInterval, agent.DefaultTLSReloadInterval)
+	viper.SetDefault(flagEnrollmentToken, "")
 	viper.SetDefault(flagLogLevel, "warn")
 	viper.SetDefault(flagBackendHandshakeTimeout, 15)
 	viper.SetDefault(flagBackendHeartbeatInterval, 30)
 	viper.SetDefault(flagBackendHeartbeatTimeout, 45)
+	viper.SetDefault(flagBackendHeartbeatMaxMissed, 2)
+	viper.SetDefault(flagBackendReconnectThreshold, agent.DefaultBackendReconnectThreshold)
+	viper.SetDefault(flagBackendWeight, []string{})
+	viper.SetDefault(flagBackendFailbackInterval, agent.DefaultBackendFailbackInterval)
+	viper.SetDefault(flagBackendCompression, false)
+	viper.SetDefault(flagBackendCompressionLevel, transport.DefaultCompressionLevel)
+	viper.SetDefault(flagArtifactsURL, "")
+	viper.SetDefault(flagArtifactsMaxSize, agent.DefaultArtifactsMaxSize)
+	viper.SetDefault(flagArtifactsAllowedDirs, []string{})
+	viper.SetDefault(flagOfflineSpoolMaxSize, 0)
+	viper.SetDefault(flagOfflineSpoolMaxAge, 0)
+	viper.SetDefault(flagCloudMetadata, []string{})
+	viper.SetDefault(flagCloudMetadataInterval, agent.DefaultCloudMetadataRefreshInterval)
+	viper.SetDefault(flagSecretsProviders, []string{})
+	viper.SetDefault(flagSecretsFileDir, "")
+	viper.SetDefault(flagSecretsExecCommand, "")
+	viper.SetDefault(flagSecretsEnvReferences, []string{})
+	viper.SetDefault(flagSecretsFileReferences, []string{})
+	viper.SetDefault(flagSecretsExecReferences, []string{})
+	viper.SetDefault(flagMaxConcurrentChecks, 0)
+	viper.SetDefault(flagContainerRuntime, corev2.ContainerRuntimeDocker)
+	viper.SetDefault(flagContainerChecks, false)
+	viper.SetDefault(flagContainerImages, []string{})
+	viper.SetDefault(flagContainerHostVolumes, false)
+	viper.SetDefault(flagContainerHostNetwork, false)
+	viper.SetDefault(flagLabelsDir, filepath.Join(path.SystemConfigDir(), "labels.d"))
+	viper.SetDefault(flagAnnotationsDir, filepath.Join(path.SystemConfigDir(), "annotations.d"))
 
 	// Merge in config flag set so that it appears in command usage
 	cmd.Flags().AddFlagSet(configFlagSet)
@@ -250,12 +300,22 @@ func newStartCommand(ctx context.Context, args []string, logger *logrus.Entry) *
 	cmd.Flags().String(flagAPIHost, viper.GetString(flagAPIHost), "address to bind the Sensu client HTTP API to")
 	cmd.Flags().String(flagCacheDir, viper.GetString(flagCacheDir), "path to store cached data")
 	cmd.Flags().String(flagDeregistrationHandler, viper.GetString(flagDeregistrationHandler), "deregistration handler that should process the entity deregistration event.")
+	cmd.Flags().Bool(flagEventAcks, viper.GetBool(flagEventAcks), "ask the backend to acknowledge the events once published, and send the unacknowledged events again")
+	cmd.Flags().Int(flagEventAckTimeout, viper.GetInt(flagEventAckTimeout), "number of seconds after which an event not acknowledged by the backend is sent again")
 	cmd.Flags().Float64(flagEventsRateLimit, viper.GetFloat64(flagEventsRateLimit), "maximum number of events transmitted to the backend through the /events api")
 	cmd.Flags().Int(flagEventsBurstLimit, viper.GetInt(flagEventsBurstLimit), "/events api burst limit")
+	cmd.Flags().Int(flagEventsDedupWindow, viper.GetInt(flagEventsDedupWindow), "number of seconds during which identical OK check results are not transmitted to the backend (0 to disable)")
+	cmd.Flags().Bool(flagEventsDropOKMetricOnly, viper.GetBool(flagEventsDropOKMetricOnly), "do not transmit OK check results of checks that only produce metrics")
+	cmd.Flags().Int(flagEventsSampleRate, viper.GetInt(flagEventsSampleRate), "transmit only one out of every N OK check results (0 to disable)")
+	cmd.Flags().StringArray(flagEventsFilters, viper.GetStringSlice(flagEventsFilters), "rule dropping or sampling the check results matching a JavaScript expression before they are transmitted, either drop:<expression> or sample=<rate>:<expression>, e.g. 'drop:event.check.status == 0' (can be repeated, the first matching rule applies)")
 	cmd.Flags().String(flagNamespace, viper.GetString(flagNamespace), "agent namespace")
 	cmd.Flags().String(flagPassword, viper.GetString(flagPassword), "agent password")
 	cmd.Flags().StringSlice(flagRedact, viper.GetStringSlice(flagRedact), "comma-delimited customized list of fields to redact")
 	cmd.Flags().String(flagSocketHost, viper.GetString(flagSocketHost), "address to bind the Sensu client socket to")
+	cmd.Flags().String(flagSocketToken, viper.GetString(flagSocketToken), "token the messages sent to the Sensu client socket must hold in their token field")
+	cmd.Flags().Bool(flagSocketStrict, viper.GetBool(flagSocketStrict), "reject the messages sent to the Sensu client socket with unknown fields or invalid names, and describe the errors in the TCP responses")
+	cmd.Flags().Float64(flagSocketRateLimit, viper.GetFloat64(flagSocketRateLimit), "maximum number of messages per second the Sensu client socket accepts from each source address (0 is unlimited)")
+	cmd.Flags().Int(flagSocketBurstLimit, viper.GetInt(flagSocketBurstLimit), "maximum burst of messages the Sensu client socket accepts from each source address with a rate limit")
 	cmd.Flags().Bool(flagStatsdDisable, viper.GetBool(flagStatsdDisable), "disables the statsd listener and metrics server")
 	cmd.Flags().StringSlice(flagStatsdEventHandlers, viper.GetStringSlice(flagStatsdEventHandlers), "event handlers for statsd metrics, one per flag")
 	cmd.Flags().Int(flagStatsdFlushInterval, viper.GetInt(flagStatsdFlushInterval), "number of seconds between statsd flush")
@@ -265,18 +325,57 @@ func newStartCommand(ctx context.Context, args []string, logger *logrus.Entry) *
 	cmd.Flags().String(flagUser, viper.GetString(flagUser), "agent user")
 	cmd.Flags().StringSlice(flagBackendURL, viper.GetStringSlice(flagBackendURL), "ws/wss URL of Sensu backend server (to specify multiple backends use this flag multiple times)")
 	cmd.Flags().Uint32(flagKeepaliveTimeout, uint32(viper.GetInt(flagKeepaliveTimeout)), "number of seconds until agent is considered dead by backend")
+	cmd.Flags().String(flagKeepaliveInterfaces, viper.GetString(flagKeepaliveInterfaces), fmt.Sprintf("network interfaces of the entity sent in the keepalives, one of %v", agent.KeepaliveNetworkInterfaces))
+	cmd.Flags().Int(flagKeepaliveInventory, viper.GetInt(flagKeepaliveInventory), "minimum number of seconds between two keepalives sending the system inventory of the entity, the others only send a heartbeat (0 to send it in every keepalive)")
 	cmd.Flags().Bool(flagDisableAPI, viper.GetBool(flagDisableAPI), "disable the Agent HTTP API")
+	cmd.Flags().Bool(flagAPICheckExecution, viper.GetBool(flagAPICheckExecution), "enable the execution of checks through the Agent HTTP API, authenticated with the agent user and password")
 	cmd.Flags().Bool(flagDisableAssets, viper.GetBool(flagDisableAssets), "disable check assets on this agent")
+	cmd.Flags().Int(flagAssetsCacheMaxSize, viper.GetInt(flagAssetsCacheMaxSize), "maximum size of the asset cache in megabytes, the least recently used assets being evicted (0 for no limit)")
+	cmd.Flags().Int(flagAssetsCacheRetention, viper.GetInt(flagAssetsCacheRetention), "number of hours after its last use after which an asset is evicted from the cache (0 to keep the assets)")
 	cmd.Flags().Bool(flagDisableSockets, viper.GetBool(flagDisableSockets), "disable the Agent TCP and UDP event sockets")
+	cmd.Flags().Bool(flagMinimal, viper.GetBool(flagMinimal), "run the agent with a reduced feature set, disabling the API, event sockets, statsd and assets")
+	cmd.Flags().Bool(flagHeartbeatOnly, viper.GetBool(flagHeartbeatOnly), "register the entity and send keepalives only, never executing checks or remote commands")
+	cmd.Flags().String(flagCertFile, viper.GetString(flagCertFile), "TLS client certificate in PEM format, to authenticate with backends requiring client certificates")
+	cmd.Flags().String(flagKeyFile, viper.GetString(flagKeyFile), "TLS client certificate key in PEM format")
 	cmd.Flags().String(flagTrustedCAFile, viper.GetString(flagTrustedCAFile), "TLS CA certificate bundle in PEM format")
 	cmd.Flags().Bool(flagInsecureSkipTLSVerify, viper.GetBool(flagInsecureSkipTLSVerify), "skip TLS verification (not recommended!)")
+	cmd.Flags().Int(flagTLSReloadInterval, viper.GetInt(flagTLSReloadInterval), "number of seconds between two reads of the TLS certificate, key and CA files, the agent reconnects to the backend once they were renewed (0 to disable)")
+	cmd.Flags().String(flagEnrollmentToken, viper.GetString(flagEnrollmentToken), "one-time token with which the agent obtains a TLS client certificate from the backend, written to --cert-file and --key-file or to the cache directory, and renews it")
+	cmd.Flags().String(flagArtifactsURL, viper.GetString(flagArtifactsURL), "base URL of the object store the output artifacts of checks are uploaded to")
+	cmd.Flags().Int64(flagArtifactsMaxSize, viper.GetInt64(flagArtifactsMaxSize), "maximum size in bytes of each check output artifact, larger artifacts are truncated")
+	cmd.Flags().StringSlice(flagArtifactsAllowedDirs, viper.GetStringSlice(flagArtifactsAllowedDirs), "directories the output artifacts of checks can be uploaded from, once their symbolic links are resolved (no artifact is uploaded if empty)")
+	cmd.Flags().Int64(flagOfflineSpoolMaxSize, viper.GetInt64(flagOfflineSpoolMaxSize), "maximum size in bytes of the events and keepalives spooled to disk while the agent is disconnected, replayed in order once connected, the oldest are dropped when full (0 to disable)")
+	cmd.Flags().Int(flagOfflineSpoolMaxAge, viper.GetInt(flagOfflineSpoolMaxAge), "number of seconds after which a spooled event or keepalive is dropped instead of being replayed (0 for no limit)")
+	cmd.Flags().StringSlice(flagCloudMetadata, viper.GetStringSlice(flagCloudMetadata), "cloud providers of which the instance metadata is queried, in order, to add the instance ID, region, availability zone and tags to the entity [aws, gce, azure] (disabled if empty)")
+	cmd.Flags().Int(flagCloudMetadataInterval, viper.GetInt(flagCloudMetadataInterval), "number of seconds between two queries of the cloud instance metadata (0 to only query it at startup)")
+	cmd.Flags().StringSlice(flagSecretsProviders, viper.GetStringSlice(flagSecretsProviders), "providers the ${secret:<provider>:<reference>} placeholders of the check commands and environment variables are resolved from when the checks are executed [env, file, exec] (disabled if empty)")
+	cmd.Flags().String(flagSecretsFileDir, viper.GetString(flagSecretsFileDir), "directory holding the secrets of the file provider, the references being relative to it")
+	cmd.Flags().String(flagSecretsExecCommand, viper.GetString(flagSecretsExecCommand), "command run by the exec secrets provider, with the secret reference on its standard input and in SENSU_SECRET_REFERENCE, its output being the secret")
+	cmd.Flags().StringSlice(flagSecretsEnvReferences, viper.GetStringSlice(flagSecretsEnvReferences), "patterns of the environment variables the env secrets provider resolves (e.g. SENSU_SECRET_*), every other variable being denied")
+	cmd.Flags().StringSlice(flagSecretsFileReferences, viper.GetStringSlice(flagSecretsFileReferences), "patterns of the files the file secrets provider resolves, relative to the secrets directory, every other file being denied")
+	cmd.Flags().StringSlice(flagSecretsExecReferences, viper.GetStringSlice(flagSecretsExecReferences), "patterns of the references the exec secrets provider resolves, every other reference being denied")
+	cmd.Flags().Int(flagMaxConcurrentChecks, viper.GetInt(flagMaxConcurrentChecks), "maximum number of checks executing at the same time, the other checks are queued (0 for no limit)")
+	cmd.Flags().String(flagContainerRuntime, viper.GetString(flagContainerRuntime), "container engine executing the commands of the checks specifying a container without a runtime [docker, podman, nerdctl]")
+	cmd.Flags().Bool(flagContainerChecks, viper.GetBool(flagContainerChecks), "execute the checks specifying a container, which fail otherwise")
+	cmd.Flags().StringSlice(flagContainerImages, viper.GetStringSlice(flagContainerImages), "comma-delimited list of glob patterns of the images the checks can be executed in (any image if empty)")
+	cmd.Flags().Bool(flagContainerHostVolumes, viper.GetBool(flagContainerHostVolumes), "allow the checks to mount paths of the host in their container")
+	cmd.Flags().Bool(flagContainerHostNetwork, viper.GetBool(flagContainerHostNetwork), "allow the checks to attach their container to the network of the host or of another container")
 	cmd.Flags().String(flagLogLevel, viper.GetString(flagLogLevel), "logging level [panic, fatal, error, warn, info, debug]")
 	cmd.Flags().StringToStringVar(&labels, flagLabels, nil, "entity labels map")
 	cmd.Flags().StringToStringVar(&annotations, flagAnnotations, nil, "entity annotations map")
+	cmd.Flags().String(flagLabelsDir, viper.GetString(flagLabelsDir), "directory of drop-in files of entity labels, key=value lines or YAML maps in .yml files, read at startup and on reload (the labels map takes precedence)")
+	cmd.Flags().String(flagAnnotationsDir, viper.GetString(flagAnnotationsDir), "directory of drop-in files of entity annotations, key=value lines or YAML maps in .yml files, read at startup and on reload (the annotations map takes precedence)")
 	cmd.Flags().String(flagAllowList, viper.GetString(flagAllowList), "path to agent execution allow list configuration file")
+	cmd.Flags().String(flagDenyList, viper.GetString(flagDenyList), "path to agent execution deny list configuration file, taking precedence over the allow list")
 	cmd.Flags().Int(flagBackendHandshakeTimeout, viper.GetInt(flagBackendHandshakeTimeout), "number of seconds the agent should wait when negotiating a new WebSocket connection")
 	cmd.Flags().Int(flagBackendHeartbeatInterval, viper.GetInt(flagBackendHeartbeatInterval), "interval at which the agent should send heartbeats to the backend")
 	cmd.Flags().Int(flagBackendHeartbeatTimeout, viper.GetInt(flagBackendHeartbeatTimeout), "number of seconds the agent should wait for a response to a hearbeat")
+	cmd.Flags().Int(flagBackendHeartbeatMaxMissed, viper.GetInt(flagBackendHeartbeatMaxMissed), "number of consecutive heartbeats without response after which the agent closes the connection with the backend (0 to only rely on the heartbeat timeout)")
+	cmd.Flags().Int(flagBackendReconnectThreshold, viper.GetInt(flagBackendReconnectThreshold), "number of consecutive failed connection attempts after which an event reporting the outage is sent once reconnected (0 to disable)")
+	cmd.Flags().StringSlice(flagBackendWeight, viper.GetStringSlice(flagBackendWeight), "weight of the backend URL given at the same position, the available backend with the highest weight is preferred (to specify multiple weights use this flag multiple times)")
+	cmd.Flags().Int(flagBackendFailbackInterval, viper.GetInt(flagBackendFailbackInterval), "number of seconds between the checks for a backend with a higher weight being reachable again (0 to disable)")
+	cmd.Flags().Bool(flagBackendCompression, viper.GetBool(flagBackendCompression), "compress the messages exchanged with the backend, if it supports the WebSocket permessage-deflate extension")
+	cmd.Flags().Int(flagBackendCompressionLevel, viper.GetInt(flagBackendCompressionLevel), "compression level of the messages sent to the backend, from -2 (Huffman coding only) to 9 (best compression)")
 
 	cmd.Flags().SetNormalizeFunc(aliasNormalizeFunc(logger))
 
@@ -290,6 +389,190 @@ func newStartCommand(ctx context.Context, args []string, logger *logrus.Entry) *
 	return cmd
 }
 
+// newAgentConfig returns the configuration of the agent, read from the flags
+// of the command and the configuration file.
+func newAgentConfig(cmd *cobra.Command) (*agent.Config, error) {
+	if _, err := logrus.ParseLevel(viper.GetString(flagLogLevel)); err != nil {
+		return nil, err
+	}
+
+	cfg := agent.NewConfig()
+	cfg.LogLevel = viper.GetString(flagLogLevel)
+	cfg.API.Host = viper.GetString(flagAPIHost)
+	cfg.API.Port = viper.GetInt(flagAPIPort)
+	cfg.ArtifactStore.URL = viper.GetString(flagArtifactsURL)
+	cfg.ArtifactStore.MaxSize = viper.GetInt64(flagArtifactsMaxSize)
+	cfg.ArtifactStore.AllowedDirs = viper.GetStringSlice(flagArtifactsAllowedDirs)
+	cfg.OfflineSpool.MaxSize = viper.GetInt64(flagOfflineSpoolMaxSize)
+	cfg.OfflineSpool.MaxAge = viper.GetInt(flagOfflineSpoolMaxAge)
+	cfg.CloudMetadata.Providers = viper.GetStringSlice(flagCloudMetadata)
+	cfg.CloudMetadata.RefreshInterval = viper.GetInt(flagCloudMetadataInterval)
+	cfg.Secrets.Providers = viper.GetStringSlice(flagSecretsProviders)
+	cfg.Secrets.FileDir = viper.GetString(flagSecretsFileDir)
+	cfg.Secrets.ExecCommand = viper.GetString(flagSecretsExecCommand)
+	cfg.Secrets.EnvReferences = viper.GetStringSlice(flagSecretsEnvReferences)
+	cfg.Secrets.FileReferences = viper.GetStringSlice(flagSecretsFileReferences)
+	cfg.Secrets.ExecReferences = viper.GetStringSlice(flagSecretsExecReferences)
+	cfg.CacheDir = viper.GetString(flagCacheDir)
+	cfg.Deregister = viper.GetBool(flagDeregister)
+	cfg.DeregistrationHandler = viper.GetString(flagDeregistrationHandler)
+	cfg.DisableAPI = viper.GetBool(flagDisableAPI)
+	cfg.APICheckExecution = viper.GetBool(flagAPICheckExecution)
+	cfg.DisableAssets = viper.GetBool(flagDisableAssets)
+	cfg.AssetsCacheMaxSize = viper.GetInt(flagAssetsCacheMaxSize)
+	cfg.AssetsCacheRetention = viper.GetInt(flagAssetsCacheRetention)
+	cfg.DisableSockets = viper.GetBool(flagDisableSockets)
+	cfg.EventAcks = viper.GetBool(flagEventAcks)
+	cfg.EventAckTimeout = viper.GetInt(flagEventAckTimeout)
+	cfg.EventsAPIRateLimit = rate.Limit(viper.GetFloat64(flagEventsRateLimit))
+	cfg.EventsAPIBurstLimit = viper.GetInt(flagEventsBurstLimit)
+	cfg.EventFilter.DedupWindow = viper.GetInt(flagEventsDedupWindow)
+	cfg.EventFilter.DropOKMetricOnly = viper.GetBool(flagEventsDropOKMetricOnly)
+	cfg.EventFilter.SampleRate = viper.GetInt(flagEventsSampleRate)
+	cfg.KeepaliveInterval = uint32(viper.GetInt(flagKeepaliveInterval))
+	cfg.KeepaliveTimeout = uint32(viper.GetInt(flagKeepaliveTimeout))
+	cfg.KeepaliveNetworkInterfaces = viper.GetString(flagKeepaliveInterfaces)
+	cfg.KeepaliveInventoryInterval = viper.GetInt(flagKeepaliveInventory)
+	cfg.Minimal = viper.GetBool(flagMinimal)
+	cfg.HeartbeatOnly = viper.GetBool(flagHeartbeatOnly)
+	cfg.MaxConcurrentChecks = viper.GetInt(flagMaxConcurrentChecks)
+	cfg.ContainerRuntime = viper.GetString(flagContainerRuntime)
+	cfg.Containers.Enabled = viper.GetBool(flagContainerChecks)
+	cfg.Containers.Images = viper.GetStringSlice(flagContainerImages)
+	cfg.Containers.HostVolumes = viper.GetBool(flagContainerHostVolumes)
+	cfg.Containers.HostNetwork = viper.GetBool(flagContainerHostNetwork)
+	cfg.Namespace = viper.GetString(flagNamespace)
+	cfg.Password = viper.GetString(flagPassword)
+	cfg.Socket.Host = viper.GetString(flagSocketHost)
+	cfg.Socket.Port = viper.GetInt(flagSocketPort)
+	cfg.Socket.Token = viper.GetString(flagSocketToken)
+	cfg.Socket.Strict = viper.GetBool(flagSocketStrict)
+	cfg.Socket.RateLimit = rate.Limit(viper.GetFloat64(flagSocketRateLimit))
+	cfg.Socket.BurstLimit = viper.GetInt(flagSocketBurstLimit)
+	cfg.StatsdServer.Disable = viper.GetBool(flagStatsdDisable)
+	cfg.StatsdServer.FlushInterval = viper.GetInt(flagStatsdFlushInterval)
+	cfg.StatsdServer.Host = viper.GetString(flagStatsdMetricsHost)
+	cfg.StatsdServer.Port = viper.GetInt(flagStatsdMetricsPort)
+	cfg.StatsdServer.Handlers = viper.GetStringSlice(flagStatsdEventHandlers)
+	cfg.Labels = viper.GetStringMapString(flagLabels)
+	cfg.Annotations = viper.GetStringMapString(flagAnnotations)
+	cfg.User = viper.GetString(flagUser)
+	cfg.AllowList = viper.GetString(flagAllowList)
+	cfg.DenyList = viper.GetString(flagDenyList)
+	cfg.BackendHandshakeTimeout = viper.GetInt(flagBackendHandshakeTimeout)
+	cfg.BackendHeartbeatInterval = viper.GetInt(flagBackendHeartbeatInterval)
+	cfg.BackendHeartbeatTimeout = viper.GetInt(flagBackendHeartbeatTimeout)
+	cfg.BackendHeartbeatMaxMissed = viper.GetInt(flagBackendHeartbeatMaxMissed)
+	cfg.BackendReconnectThreshold = viper.GetInt(flagBackendReconnectThreshold)
+	cfg.BackendFailbackInterval = viper.GetInt(flagBackendFailbackInterval)
+	cfg.BackendCompression = viper.GetBool(flagBackendCompression)
+	cfg.BackendCompressionLevel = viper.GetInt(flagBackendCompressionLevel)
+
+	// TLS configuration
+	cfg.TLS = &corev2.TLSOptions{}
+	cfg.TLS.CertFile = viper.GetString(flagCertFile)
+	cfg.TLS.KeyFile = viper.GetString(flagKeyFile)
+	cfg.TLS.TrustedCAFile = viper.GetString(flagTrustedCAFile)
+	cfg.TLS.InsecureSkipVerify = viper.GetBool(flagInsecureSkipTLSVerify)
+	cfg.TLSReloadInterval = viper.GetInt(flagTLSReloadInterval)
+	cfg.EnrollmentToken = viper.GetString(flagEnrollmentToken)
+
+	agentName := viper.GetString(flagAgentName)
+	if agentName != "" {
+		cfg.AgentName = agentName
+	}
+
+	for _, backendURL := range viper.GetStringSlice(flagBackendURL) {
+		newURL, err := url.AppendPortIfMissing(backendURL, DefaultBackendPort)
+		if err != nil {
+			return nil, err
+		}
+		cfg.BackendURLs = append(cfg.BackendURLs, newURL)
+	}
+	// The expressions of the event filters may hold commas, so the flag is a
+	// string array, which viper does not read
+	filters := viper.GetStringSlice(flagEventsFilters)
+	if flag := cmd.Flags().Lookup(flagEventsFilters); flag != nil && flag.Changed {
+		filters, _ = cmd.Flags().GetStringArray(flagEventsFilters)
+	}
+	for _, filter := range filters {
+		rule, err := agent.ParseEventFilterRule(filter)
+		if err != nil {
+			return nil, err
+		}
+		cfg.EventFilter.Rules = append(cfg.EventFilter.Rules, rule)
+	}
+	for _, backendWeight := range viper.GetStringSlice(flagBackendWeight) {
+		weight, err := strconv.Atoi(backendWeight)
+		if err != nil || weight < 1 {
+			return nil, fmt.Errorf("invalid backend weight %q, it must be a positive integer", backendWeight)
+		}
+		cfg.BackendWeights = append(cfg.BackendWeights, weight)
+	}
+	if len(cfg.BackendWeights) > len(cfg.BackendURLs) {
+		return nil, fmt.Errorf("%d backend weights given for %d backend URLs", len(cfg.BackendWeights), len(cfg.BackendURLs))
+	}
+
+	cfg.Redact = viper.GetStringSlice(flagRedact)
+	cfg.Subscriptions = viper.GetStringSlice(flagSubscriptions)
+
+	// Workaround for https://github.com/sensu/sensu-go/issues/2357. Detect if
+	// the flags for labels and annotations were changed. If so, use their
+	// values since flags take precedence over config
+	if flag := cmd.Flags().Lookup(flagLabels); flag != nil && flag.Changed {
+		cfg.Labels = labels
+	}
+	if flag := cmd.Flags().Lookup(flagAnnotations); flag != nil && flag.Changed {
+		cfg.Annotations = annotations
+	}
+
+	// The labels and annotations of the drop-in files are merged with the
+	// configured ones, which take precedence
+	var err error
+	if cfg.Labels, err = mergeMetadataDir(cfg.Labels, viper.GetString(flagLabelsDir)); err != nil {
+		return nil, fmt.Errorf("error reading the labels directory: %s", err)
+	}
+	if cfg.Annotations, err = mergeMetadataDir(cfg.Annotations, viper.GetString(flagAnnotationsDir)); err != nil {
+		return nil, fmt.Errorf("error reading the annotations directory: %s", err)
+	}
+
+	return cfg, nil
+}
+
+// mergeMetadataDir merges the labels or the annotations of the drop-in files of
+// dir with the given ones, which take precedence.
+func mergeMetadataDir(metadata map[string]string, dir string) (map[string]string, error) {
+	merged, err := agent.ReadMetadataDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(merged) == 0 {
+		return metadata, nil
+	}
+	for key, value := range metadata {
+		merged[key] = value
+	}
+	return merged, nil
+}
+
+// reloadOnSignal reloads the configuration of the agent whenever it receives
+// SIGHUP, until the context is canceled.
+func reloadOnSignal(ctx context.Context, sensuAgent *agent.Agent, logger *logrus.Entry) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if _, err := sensuAgent.ReloadConfig(); err != nil {
+				logger.WithError(err).Error("error reloading the agent configuration")
+			}
+		}
+	}
+}
+
 func aliasNormalizeFunc(logger *logrus.Entry) func(*pflag.FlagSet, string) pflag.NormalizedName {
 	return func(f *pflag.FlagSet, name string) pflag.NormalizedName {
 		// Wait until the command-line flags have been parsed
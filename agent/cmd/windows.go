@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/sensu/sensu-go/util/path"
 	"github.com/spf13/cobra"
+	"golang.org/x/sys/windows/svc/mgr"
 )
 
 const (
@@ -18,11 +20,21 @@ const (
 	serviceDescription = "The monitoring agent for sensu-go (https://sensu.io)"
 	serviceUser        = "LocalSystem"
 
-	flagLogPath = "log-file"
+	flagLogPath             = "log-file"
+	flagServiceStartType    = "start-type"
+	flagServiceUser         = "user"
+	flagServicePassword     = "password"
+	flagServiceRestartDelay = "restart-delay"
+	flagServiceNoStart      = "no-start"
+
+	serviceStartTypeAutomatic        = "automatic"
+	serviceStartTypeDelayedAutomatic = "delayed-automatic"
+	serviceStartTypeManual           = "manual"
 )
 
 // NewWindowsServiceCommand creates a cobra command that offers subcommands
-// for installing, uninstalling and running sensu-agent as a windows service.
+// for installing, uninstalling, controlling and running sensu-agent as a
+// windows service.
 func NewWindowsServiceCommand() *cobra.Command {
 	command := &cobra.Command{
 		Use:   "service",
@@ -31,13 +43,17 @@ func NewWindowsServiceCommand() *cobra.Command {
 
 	command.AddCommand(NewWindowsInstallServiceCommand())
 	command.AddCommand(NewWindowsUninstallServiceCommand())
+	command.AddCommand(NewWindowsStartServiceCommand())
+	command.AddCommand(NewWindowsStopServiceCommand())
+	command.AddCommand(NewWindowsStatusServiceCommand())
 	command.AddCommand(NewWindowsRunServiceCommand())
 
 	return command
 }
 
 // NewWindowsInstallServiceCommand creates a cobra command that installs a
-// sensu-agent service in Windows.
+// sensu-agent service in Windows. The service control manager restarts the
+// service when it fails, and the service logs its errors to the event log.
 func NewWindowsInstallServiceCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:           "install",
@@ -45,12 +61,13 @@ func NewWindowsInstallServiceCommand() *cobra.Command {
 		SilenceErrors: true,
 		SilenceUsage:  true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			configFile := cmd.Flag(flagConfigFile).Value.String()
-			p, err := filepath.Abs(configFile)
+			// The service runs from another working directory, the paths
+			// are made absolute
+			configFile, err := filepath.Abs(cmd.Flag(flagConfigFile).Value.String())
 			if err != nil {
 				return fmt.Errorf("error reading config file: %s", err)
 			}
-			fi, err := os.Stat(p)
+			fi, err := os.Stat(configFile)
 			if err != nil {
 				return fmt.Errorf("error reading config file: %s", err)
 			}
@@ -58,13 +75,19 @@ func NewWindowsInstallServiceCommand() *cobra.Command {
 				return errors.New("error reading config file: not a regular file")
 			}
 
-			logFile := cmd.Flag(flagLogPath).Value.String()
-			lp, err := filepath.Abs(logFile)
+			logFile, err := filepath.Abs(cmd.Flag(flagLogPath).Value.String())
 			if err != nil {
 				return fmt.Errorf("error reading log file: %s", err)
 			}
-			os.OpenFile(lp, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-			lfi, err := os.Stat(lp)
+			if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
+				return fmt.Errorf("error creating log file directory: %s", err)
+			}
+			f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				return fmt.Errorf("error opening log file: %s", err)
+			}
+			_ = f.Close()
+			lfi, err := os.Stat(logFile)
 			if err != nil {
 				return fmt.Errorf("error reading log file: %s", err)
 			}
@@ -72,7 +95,40 @@ func NewWindowsInstallServiceCommand() *cobra.Command {
 				return errors.New("error reading log file: not a regular file")
 			}
 
-			return installService(serviceName, serviceDisplayName, serviceDescription, "service", "run", configFile, logFile)
+			opts := serviceOptions{
+				Start: true,
+			}
+			switch startType, _ := cmd.Flags().GetString(flagServiceStartType); startType {
+			case serviceStartTypeAutomatic:
+				opts.StartType = mgr.StartAutomatic
+			case serviceStartTypeDelayedAutomatic:
+				opts.StartType = mgr.StartAutomatic
+				opts.DelayedAutoStart = true
+			case serviceStartTypeManual:
+				opts.StartType = mgr.StartManual
+			default:
+				return fmt.Errorf(
+					"invalid start type %q, must be one of %q, %q or %q",
+					startType, serviceStartTypeAutomatic, serviceStartTypeDelayedAutomatic, serviceStartTypeManual,
+				)
+			}
+			opts.User, _ = cmd.Flags().GetString(flagServiceUser)
+			opts.Password, _ = cmd.Flags().GetString(flagServicePassword)
+			if opts.RestartDelay, err = cmd.Flags().GetDuration(flagServiceRestartDelay); err != nil {
+				return err
+			}
+			if opts.RestartDelay < 0 {
+				return errors.New("the restart delay must not be negative")
+			}
+			if noStart, _ := cmd.Flags().GetBool(flagServiceNoStart); noStart {
+				opts.Start = false
+			}
+
+			if err := installService(serviceName, serviceDisplayName, serviceDescription, opts, "service", "run", configFile, logFile); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "installed the %s service\n", serviceName)
+			return nil
 		},
 	}
 
@@ -81,6 +137,11 @@ func NewWindowsInstallServiceCommand() *cobra.Command {
 
 	cmd.Flags().StringP(flagConfigFile, "c", defaultConfigPath, "path to sensu-agent config file")
 	cmd.Flags().StringP(flagLogPath, "", defaultLogPath, "path to the sensu-agent log file")
+	cmd.Flags().String(flagServiceStartType, serviceStartTypeAutomatic, fmt.Sprintf("start type of the service: %s, %s or %s", serviceStartTypeAutomatic, serviceStartTypeDelayedAutomatic, serviceStartTypeManual))
+	cmd.Flags().String(flagServiceUser, serviceUser, "account the service runs as")
+	cmd.Flags().String(flagServicePassword, "", "password of the account the service runs as")
+	cmd.Flags().Duration(flagServiceRestartDelay, 10*time.Second, "time to wait before restarting the service when it fails")
+	cmd.Flags().Bool(flagServiceNoStart, false, "do not start the service once installed")
 
 	return cmd
 }
@@ -99,12 +160,95 @@ func NewWindowsUninstallServiceCommand() *cobra.Command {
 	}
 }
 
+// NewWindowsStartServiceCommand creates a cobra command that starts the
+// sensu-agent service in Windows, and waits until it runs.
+func NewWindowsStartServiceCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:           "start",
+		Short:         "start the sensu-agent service",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, closeService, err := openService(serviceName)
+			if err != nil {
+				return err
+			}
+			defer closeService()
+			return startService(s)
+		},
+	}
+}
+
+// NewWindowsStopServiceCommand creates a cobra command that stops the
+// sensu-agent service in Windows, and waits until it is stopped.
+func NewWindowsStopServiceCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:           "stop",
+		Short:         "stop the sensu-agent service",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, closeService, err := openService(serviceName)
+			if err != nil {
+				return err
+			}
+			defer closeService()
+			return stopService(s)
+		},
+	}
+}
+
+// NewWindowsStatusServiceCommand creates a cobra command that prints the
+// state and the configuration of the sensu-agent service in Windows.
+func NewWindowsStatusServiceCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:           "status",
+		Short:         "print the status of the sensu-agent service",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, closeService, err := openService(serviceName)
+			if err != nil {
+				return err
+			}
+			defer closeService()
+
+			status, err := s.Query()
+			if err != nil {
+				return err
+			}
+			config, err := s.Config()
+			if err != nil {
+				return err
+			}
+			actions, err := s.RecoveryActions()
+			if err != nil {
+				return err
+			}
+			recovery := "none"
+			if len(actions) > 0 && actions[0].Type == mgr.ServiceRestart {
+				recovery = fmt.Sprintf("restart after %s", actions[0].Delay)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Name:       %s\n", serviceName)
+			fmt.Fprintf(out, "State:      %s\n", serviceStateString(status.State))
+			fmt.Fprintf(out, "Start type: %s\n", serviceStartTypeString(config.StartType))
+			fmt.Fprintf(out, "Account:    %s\n", config.ServiceStartName)
+			fmt.Fprintf(out, "Command:    %s\n", config.BinaryPathName)
+			fmt.Fprintf(out, "Recovery:   %s\n", recovery)
+			return nil
+		},
+	}
+}
+
 func NewWindowsRunServiceCommand() *cobra.Command {
 	command := &cobra.Command{
 		Use:           "run",
 		Short:         "run the sensu-agent service (blocking)",
 		SilenceErrors: true,
 		SilenceUsage:  true,
+		Args:          cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runService(args)
 		},
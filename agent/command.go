@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/asset"
+	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+)
+
+// errCommandDenied is returned to the backend when a command does not match
+// the allow list of the agent.
+var errCommandDenied = errors.New("command denied by the agent allow list")
+
+// errHeartbeatOnly is returned to the backend when the agent runs in
+// heartbeat-only mode, which never executes commands.
+var errHeartbeatOnly = errors.New("command denied, the agent runs in heartbeat-only mode")
+
+// verifyCommand returns an error if the command can't be executed by the
+// agent. Unlike checks, commands are denied when the agent has no allow list.
+func (a *Agent) verifyCommand(cmd string) error {
+	if a.config.HeartbeatOnly {
+		return errHeartbeatOnly
+	}
+	if entry, denied := a.matchDenyList(cmd); denied {
+		return denyListError(entry)
+	}
+	if len(a.allowList) == 0 {
+		return errors.New("the agent has no allow list")
+	}
+	entry, match := a.matchAllowList(cmd)
+	if !match {
+		return errCommandDenied
+	}
+	if entry.Sha512 == "" {
+		return nil
+	}
+	path, err := lookPath(strings.Split(cmd, " ")[0], os.Environ())
+	if err != nil {
+		return fmt.Errorf("unable to find the executable path: %s", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("unable to open executable: %s", err)
+	}
+	defer file.Close()
+	verifier := asset.Sha512Verifier{}
+	if err := verifier.Verify(file, entry.Sha512); err != nil {
+		return errCommandDenied
+	}
+	return nil
+}
+
+// handleCommandRequest executes the command requested by the backend if it
+// matches the allow list of the agent. The backend is told right away whether
+// the command was accepted, and its result is sent later as an event.
+func (a *Agent) handleCommandRequest(ctx context.Context, payload []byte) error {
+	var request corev2.AgentCommandRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return err
+	}
+
+	fields := logrus.Fields{"id": request.ID, "command": request.Command}
+	response := corev2.AgentCommandResponse{ID: request.ID}
+	err := request.Validate()
+	if err == nil {
+		err = a.verifyCommand(request.Command)
+	}
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Warn("denied remote command")
+		response.Error = err.Error()
+	}
+
+	msg, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	a.sendMessage(transport.NewMessage(corev2.AgentCommandResponseType, msg))
+
+	if response.Error == "" {
+		logger.WithFields(fields).Info("executing remote command")
+		go a.executeCommand(ctx, request)
+	}
+	return nil
+}
+
+// executeCommand executes an accepted command and sends its result as an
+// event.
+func (a *Agent) executeCommand(ctx context.Context, request corev2.AgentCommandRequest) {
+	if request.Timeout == 0 {
+		request.Timeout = corev2.DefaultAgentCommandTimeout
+	}
+
+	entity := a.getAgentEntity()
+	check := &corev2.Check{
+		ObjectMeta: corev2.ObjectMeta{
+			Name:        corev2.AgentCommandCheckName,
+			Namespace:   entity.Namespace,
+			Annotations: map[string]string{corev2.AgentCommandIDAnnotation: request.ID},
+		},
+		Command:  request.Command,
+		Interval: 1,
+		Timeout:  request.Timeout,
+		Issued:   time.Now().Unix(),
+		Executed: time.Now().Unix(),
+	}
+
+	ex := command.ExecutionRequest{
+		Env:     os.Environ(),
+		Command: request.Command,
+		Timeout: int(request.Timeout),
+		Name:    corev2.AgentCommandCheckName,
+	}
+	result, err := a.executor.Execute(ctx, ex)
+	if err != nil {
+		check.Output = err.Error()
+		check.Status = 3
+	} else {
+		check.Output = result.Output
+		check.Status = uint32(result.Status)
+		check.Duration = result.Duration
+	}
+
+	event := &corev2.Event{
+		ObjectMeta: corev2.ObjectMeta{Namespace: entity.Namespace},
+		Entity:     entity,
+		Check:      check,
+		Timestamp:  time.Now().Unix(),
+	}
+
+	msg, err := a.marshal(event)
+	if err != nil {
+		logger.WithError(err).Error("error marshaling remote command result")
+		return
+	}
+	a.sendMessage(&transport.Message{
+		Type:    transport.MessageTypeEvent,
+		Payload: msg,
+	})
+}
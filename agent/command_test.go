@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/agentd"
+	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/testing/mockexecutor"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCommandTestAgent(t *testing.T, allowList []allowList) (*Agent, func()) {
+	t.Helper()
+	config, cleanup := FixtureConfig()
+	agent, err := NewAgent(config)
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	agent.allowList = allowList
+	agent.sendq = make(chan *transport.Message, 5)
+	agent.marshal = agentd.MarshalJSON
+	return agent, cleanup
+}
+
+func commandResponse(t *testing.T, agent *Agent) corev2.AgentCommandResponse {
+	t.Helper()
+	msg := <-agent.sendq
+	require.Equal(t, corev2.AgentCommandResponseType, msg.Type)
+	var response corev2.AgentCommandResponse
+	require.NoError(t, json.Unmarshal(msg.Payload, &response))
+	return response
+}
+
+func TestHandleCommandRequestDenied(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowList []allowList
+		command   string
+	}{
+		{
+			name:    "no allow list",
+			command: "uptime",
+		},
+		{
+			name:      "no match",
+			allowList: []allowList{{Exec: "uptime"}},
+			command:   "rm -rf /",
+		},
+		{
+			name:      "empty command",
+			allowList: []allowList{{Exec: "uptime"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, cleanup := newCommandTestAgent(t, tt.allowList)
+			defer cleanup()
+			request := corev2.AgentCommandRequest{
+				AgentCommand: corev2.AgentCommand{Command: tt.command},
+				ID:           "42",
+			}
+			payload, _ := json.Marshal(request)
+			require.NoError(t, agent.handleCommandRequest(context.Background(), payload))
+
+			response := commandResponse(t, agent)
+			assert.Equal(t, "42", response.ID)
+			assert.NotEmpty(t, response.Error)
+
+			// The command must not be executed
+			select {
+			case msg := <-agent.sendq:
+				t.Fatalf("unexpected message of type %s", msg.Type)
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestHandleCommandRequestHeartbeatOnly(t *testing.T) {
+	agent, cleanup := newCommandTestAgent(t, []allowList{{Exec: "uptime"}})
+	defer cleanup()
+	agent.config.HeartbeatOnly = true
+
+	request := corev2.AgentCommandRequest{
+		AgentCommand: corev2.AgentCommand{Command: "uptime"},
+		ID:           "42",
+	}
+	payload, _ := json.Marshal(request)
+	require.NoError(t, agent.handleCommandRequest(context.Background(), payload))
+
+	response := commandResponse(t, agent)
+	assert.Equal(t, errHeartbeatOnly.Error(), response.Error)
+}
+
+func TestHandleCommandRequest(t *testing.T) {
+	agent, cleanup := newCommandTestAgent(t, []allowList{{Exec: "uptime"}})
+	defer cleanup()
+	ex := &mockexecutor.MockExecutor{}
+	agent.executor = ex
+	ex.Return(command.FixtureExecutionResponse(0, "up 42 days"), nil)
+
+	request := corev2.AgentCommandRequest{
+		AgentCommand: corev2.AgentCommand{Command: "uptime"},
+		ID:           "42",
+	}
+	payload, _ := json.Marshal(request)
+	require.NoError(t, agent.handleCommandRequest(context.Background(), payload))
+
+	response := commandResponse(t, agent)
+	assert.Equal(t, "42", response.ID)
+	assert.Empty(t, response.Error)
+
+	msg := <-agent.sendq
+	require.Equal(t, transport.MessageTypeEvent, msg.Type)
+	var event corev2.Event
+	require.NoError(t, json.Unmarshal(msg.Payload, &event))
+	assert.Equal(t, corev2.AgentCommandCheckName, event.Check.Name)
+	assert.Equal(t, "42", event.Check.Annotations[corev2.AgentCommandIDAnnotation])
+	assert.Equal(t, "up 42 days", event.Check.Output)
+	assert.Equal(t, uint32(corev2.DefaultAgentCommandTimeout), event.Check.Timeout)
+}
@@ -51,6 +51,10 @@ const (
 	// DefaultSocketPort specifies the default socket port
 	DefaultSocketPort = 3030
 
+	// DefaultSocketBurstLimit specifies the default burst of messages the
+	// socket accepts from each source address with a rate limit
+	DefaultSocketBurstLimit = 10
+
 	// DefaultStatsdDisable specifies if the statsd listener is disabled
 	DefaultStatsdDisable = false
 
@@ -79,9 +83,16 @@ type Config struct {
 	// AllowList is the path to agent execution allow list configuration file.
 	AllowList string
 
+	// DenyList is the path to agent execution deny list configuration file.
+	DenyList string
+
 	// API contains the Sensu client HTTP API configuration
 	API *APIConfig
 
+	// ArtifactStore contains the configuration of the object store the output
+	// artifacts of checks are uploaded to
+	ArtifactStore *ArtifactStoreConfig
+
 	// BackendURLs is a list of URLs for the Sensu Backend. Default:
 	// ws://127.0.0.1:8081
 	BackendURLs []string
@@ -89,6 +100,19 @@ type Config struct {
 	// CacheDir path where cached data is stored
 	CacheDir string
 
+	// CloudMetadata contains the configuration of the enrichment of the agent
+	// entity with the metadata of its cloud instance
+	CloudMetadata *CloudMetadataConfig
+
+	// ContainerRuntime is the container engine executing the commands of the
+	// checks specifying a container without a runtime: docker, podman or
+	// nerdctl. Defaults to docker.
+	ContainerRuntime string
+
+	// Containers contains the configuration of the execution of the checks
+	// specifying a container, which is disabled by default.
+	Containers *ContainersConfig
+
 	// Deregister indicates whether the entity is ephemeral
 	Deregister bool
 
@@ -98,13 +122,48 @@ type Config struct {
 	// DisableAPI disables the events API
 	DisableAPI bool
 
+	// APICheckExecution enables the execution of checks through the agent
+	// API, authenticated with the credentials of the agent.
+	APICheckExecution bool
+
 	// DisableAssets stops the agent from downloading and deploying assets
 	// in check execution.
 	DisableAssets bool
 
+	// AssetsCacheMaxSize is the maximum size of the asset cache, in
+	// megabytes. The least recently used assets are evicted once it is
+	// exceeded. 0 disables the limit.
+	AssetsCacheMaxSize int
+
+	// AssetsCacheRetention is the time, in hours, after its last use after
+	// which an asset is evicted from the cache. 0 disables the eviction.
+	AssetsCacheRetention int
+
 	// DisableSockets disables the event sockets
 	DisableSockets bool
 
+	// HeartbeatOnly runs the agent for presence monitoring only: it registers
+	// its entity and sends keepalives, but never executes checks or remote
+	// commands. The backend enforces it for the session of the agent.
+	HeartbeatOnly bool
+
+	// Minimal runs the agent with a reduced feature set, for constrained hosts
+	// like IoT or edge devices. It disables the API, the event sockets, the
+	// statsd server and the asset management.
+	Minimal bool
+
+	// EventFilter contains the configuration of the filters applied to events
+	// before they are transmitted to the backend
+	EventFilter *EventFilterConfig
+
+	// EventAcks asks the backend to acknowledge the events once it published
+	// them. The events not acknowledged are sent again.
+	EventAcks bool
+
+	// EventAckTimeout is the time, in seconds, after which an event not
+	// acknowledged by the backend is sent again.
+	EventAckTimeout int
+
 	// EventsAPIRateLimit is the maximum number of events per second that will
 	// be transmitted to the backend from the events API
 	EventsAPIRateLimit rate.Limit
@@ -121,21 +180,49 @@ type Config struct {
 	// value.
 	KeepaliveTimeout uint32
 
+	// KeepaliveNetworkInterfaces selects the network interfaces of the entity
+	// sent in the keepalives: all of them, the ones with a non-loopback
+	// address, or none. All of them are sent if empty.
+	KeepaliveNetworkInterfaces string
+
+	// KeepaliveInventoryInterval is the minimum time, in seconds, between two
+	// keepalives sending the system inventory of the entity. The keepalives
+	// in between are sent without it, and the backend keeps the last one
+	// received. The inventory is sent in every keepalive if 0.
+	KeepaliveInventoryInterval int
+
 	// Labels are key-value pairs that users can provide to agent entities
 	Labels map[string]string
 
 	// Annotations are key-value pairs that users can provide to agent entities
 	Annotations map[string]string
 
+	// LogLevel is the log level of the agent, which may be overridden by the
+	// agent config managed by the backend. The current level is kept if empty.
+	LogLevel string
+
+	// MaxConcurrentChecks is the maximum number of checks executing at the
+	// same time. The other checks wait for an execution slot. 0 disables the
+	// limit.
+	MaxConcurrentChecks int
+
 	// Namespace sets the Agent's RBAC namespace identifier
 	Namespace string
 
+	// OfflineSpool contains the configuration of the spool persisting the
+	// events and keepalives of the agent while it is disconnected
+	OfflineSpool *OfflineSpoolConfig
+
 	// Password sets Agent's password
 	Password string
 
 	// Redact contains the fields to redact when marshalling the agent's entity
 	Redact []string
 
+	// Secrets contains the configuration of the providers the secret
+	// placeholders of the checks are resolved from
+	Secrets *SecretsConfig
+
 	// Socket contains the Sensu client socket configuration
 	Socket *SocketConfig
 
@@ -148,6 +235,18 @@ type Config struct {
 	// TLS sets the TLSConfig for agent TLS options
 	TLS *corev2.TLSOptions
 
+	// TLSReloadInterval is the time, in seconds, between two reads of the TLS
+	// certificate, key and trusted CA files. The agent reconnects to the
+	// backend with the new files once they changed. 0 disables the reload.
+	TLSReloadInterval int
+
+	// EnrollmentToken is the one-time token with which the agent obtains a
+	// TLS client certificate from the backend, and renews it before it
+	// expires. The certificate and its key are written to the TLS
+	// certificate and key files, or to the cache directory if they are not
+	// set.
+	EnrollmentToken string
+
 	// User sets the Agent's username
 	User string
 
@@ -161,11 +260,42 @@ type Config struct {
 	// send a heartbeat to the backend
 	BackendHeartbeatInterval int
 
+	// BackendCompression enables the compression of the messages exchanged
+	// with backends supporting the WebSocket permessage-deflate extension
+	BackendCompression bool
+
+	// BackendCompressionLevel is the compression level of the messages sent to
+	// the backend, from -2 (Huffman coding only) to 9 (best compression)
+	BackendCompressionLevel int
+
+	// BackendReconnectThreshold is the number of consecutive failed
+	// connection attempts after which the reconnect circuit opens, and an
+	// event reporting the outage is sent once the agent is connected again.
+	// 0 disables the reconnect circuit.
+	BackendReconnectThreshold int
+
+	// BackendWeights are the weights of the backend URLs, in the same order.
+	// The agent connects to the available backend with the highest weight,
+	// and fails back to it once it is reachable again. The backends without
+	// a weight have a weight of 1.
+	BackendWeights []int
+
+	// BackendFailbackInterval is the interval, in seconds, at which the agent
+	// connected to a backend checks if a backend with a higher weight is
+	// reachable again. 0 disables the fail back.
+	BackendFailbackInterval int
+
 	// BackendHeartbeatTimeout specifies the maximum time (in seconds) to wait for
 	// a response to a heartbeat from the backend.  If a timeout occurs, the agent
 	// will close the existing connection with the backend and attempt to
 	// reconnect with exponential backoff
 	BackendHeartbeatTimeout int
+
+	// BackendHeartbeatMaxMissed specifies the number of consecutive
+	// heartbeats without response after which the agent closes the
+	// connection with the backend, without waiting for the heartbeat
+	// timeout. 0 leaves it to the heartbeat timeout.
+	BackendHeartbeatMaxMissed int
 }
 
 // StatsdServerConfig contains the statsd server configuration
@@ -181,6 +311,21 @@ type StatsdServerConfig struct {
 type SocketConfig struct {
 	Host string
 	Port int
+
+	// Token is the token the messages sent to the sockets must hold, if set.
+	Token string
+
+	// Strict rejects the messages with unknown fields or invalid names, and
+	// describes why messages are invalid in the TCP socket responses.
+	Strict bool
+
+	// RateLimit is the maximum number of messages per second accepted from
+	// each source address, unlimited if 0.
+	RateLimit rate.Limit
+
+	// BurstLimit is the maximum burst of messages accepted from each source
+	// address with a rate limit.
+	BurstLimit int
 }
 
 // FixtureConfig provides a new Config object initialized with defaults for use
@@ -197,14 +342,20 @@ func FixtureConfig() (*Config, func()) {
 			Host: DefaultAPIHost,
 			Port: DefaultAPIPort,
 		},
-		BackendURLs:         []string{},
-		CacheDir:            cacheDir,
-		EventsAPIRateLimit:  DefaultEventsAPIRateLimit,
-		EventsAPIBurstLimit: DefaultEventsAPIBurstLimit,
-		KeepaliveInterval:   DefaultKeepaliveInterval,
-		KeepaliveTimeout:    corev2.DefaultKeepaliveTimeout,
-		Namespace:           DefaultNamespace,
-		Password:            DefaultPassword,
+		ArtifactStore:             &ArtifactStoreConfig{MaxSize: DefaultArtifactsMaxSize},
+		BackendURLs:               []string{},
+		BackendReconnectThreshold: DefaultBackendReconnectThreshold,
+		BackendFailbackInterval:   DefaultBackendFailbackInterval,
+		CacheDir:                  cacheDir,
+		TLSReloadInterval:         DefaultTLSReloadInterval,
+		ContainerRuntime:          corev2.ContainerRuntimeDocker,
+		EventFilter:               &EventFilterConfig{},
+		EventsAPIRateLimit:        DefaultEventsAPIRateLimit,
+		EventsAPIBurstLimit:       DefaultEventsAPIBurstLimit,
+		KeepaliveInterval:         DefaultKeepaliveInterval,
+		KeepaliveTimeout:          corev2.DefaultKeepaliveTimeout,
+		Namespace:                 DefaultNamespace,
+		Password:                  DefaultPassword,
 		Socket: &SocketConfig{
 			Host: DefaultSocketHost,
 			Port: DefaultSocketPort,
@@ -228,9 +379,15 @@ func FixtureConfig() (*Config, func()) {
 // NewConfig provides a new empty Config object
 func NewConfig() *Config {
 	c := &Config{
-		API:          &APIConfig{},
-		Socket:       &SocketConfig{},
-		StatsdServer: &StatsdServerConfig{},
+		API:           &APIConfig{},
+		ArtifactStore: &ArtifactStoreConfig{},
+		CloudMetadata: &CloudMetadataConfig{},
+		Containers:    &ContainersConfig{},
+		EventFilter:   &EventFilterConfig{},
+		OfflineSpool:  &OfflineSpoolConfig{},
+		Secrets:       &SecretsConfig{},
+		Socket:        &SocketConfig{},
+		StatsdServer:  &StatsdServerConfig{},
 	}
 	return c
 }
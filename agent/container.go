@@ -0,0 +1,190 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/google/uuid"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/command"
+)
+
+// containerCheckLabel labels the containers executing check commands with the
+// name of their check, so the ones left running once their check timed out
+// can be found.
+const containerCheckLabel = "io.sensu.check"
+
+// containerRemoveTimeout is the timeout, in seconds, of the removal of the
+// container of a check which timed out.
+const containerRemoveTimeout = 30
+
+// errContainerUnsupported is returned for the checks executed in a container
+// on the platforms whose shell the container command can't be quoted for.
+var errContainerUnsupported = errors.New("checks executed in a container are not supported on this platform")
+
+// namedVolumeRegexp matches the sources of the container volumes that are
+// volumes of the container engine rather than paths of the host.
+var namedVolumeRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ContainersConfig contains the configuration of the execution of the check
+// commands in containers.
+type ContainersConfig struct {
+	// Enabled enables the execution of the checks specifying a container,
+	// which fail otherwise.
+	Enabled bool
+
+	// Images are glob patterns matched against the image of the checks. The
+	// checks whose image matches none of them fail. Any image is allowed if
+	// empty.
+	Images []string
+
+	// HostVolumes allows the checks to mount paths of the host in their
+	// container. Only the volumes of the container engine can be mounted
+	// otherwise.
+	HostVolumes bool
+
+	// HostNetwork allows the checks to attach their container to the network
+	// of the host, or to the network namespace of another container.
+	HostNetwork bool
+}
+
+// ValidateContainersConfig returns an error if an image pattern is invalid.
+func ValidateContainersConfig(config *ContainersConfig) error {
+	if config == nil {
+		return nil
+	}
+	for _, pattern := range config.Images {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid container image pattern %q: %s", pattern, err)
+		}
+	}
+	return nil
+}
+
+// allow returns an error if the configuration does not allow a check to be
+// executed in the container.
+func (c *ContainersConfig) allow(container *corev2.CheckContainer) error {
+	if c == nil || !c.Enabled {
+		return errors.New("checks executed in a container are disabled on this agent")
+	}
+	if len(c.Images) > 0 {
+		allowed := false
+		for _, pattern := range c.Images {
+			if ok, _ := path.Match(pattern, container.Image); ok {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("container image %q is not allowed on this agent", container.Image)
+		}
+	}
+	if !c.HostVolumes {
+		for _, volume := range container.Volumes {
+			if source := strings.SplitN(volume, ":", 2)[0]; !namedVolumeRegexp.MatchString(source) {
+				return fmt.Errorf("container volume %q mounts a host path, which is not allowed on this agent", volume)
+			}
+		}
+	}
+	if !c.HostNetwork && sharesNetwork(container.Network) {
+		return fmt.Errorf("container network %q is not allowed on this agent", container.Network)
+	}
+	return nil
+}
+
+// sharesNetwork returns true if the container network is the network of the
+// host, or the network namespace of another container.
+func sharesNetwork(network string) bool {
+	return network == "host" || strings.HasPrefix(network, "container:") || strings.HasPrefix(network, "ns:")
+}
+
+// containerEngine returns the container engine executing the check, either
+// its own or the default one.
+func containerEngine(container *corev2.CheckContainer, defaultRuntime string) string {
+	if container.Runtime != "" {
+		return container.Runtime
+	}
+	return defaultRuntime
+}
+
+// newContainerName returns a unique name for the container executing the
+// check, so it can be removed if the check times out.
+func newContainerName(check *corev2.CheckConfig) string {
+	return "sensu-check-" + check.Name + "-" + uuid.New().String()
+}
+
+// containerCommand returns the command running the command of the check in
+// the named container, with the container engine of the check or the default
+// one. The command of the check is run with the shell of the image, and the
+// environment variables of the check are passed to the container by name, so
+// their values do not appear in the command line.
+func containerCommand(check *corev2.CheckConfig, defaultRuntime, name string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", errContainerUnsupported
+	}
+	container := check.Container
+	engine := containerEngine(container, defaultRuntime)
+	if engine == "" {
+		return "", errors.New("no container runtime configured")
+	}
+
+	args := []string{engine, "run", "--rm", "--name", name, "--label", containerCheckLabel + "=" + check.Name}
+	if check.Stdin {
+		args = append(args, "--interactive")
+	}
+	if container.Network != "" {
+		args = append(args, "--network", container.Network)
+	}
+	for _, volume := range container.Volumes {
+		args = append(args, "--volume", volume)
+	}
+	for _, envVar := range check.EnvVars {
+		name := strings.SplitN(envVar, "=", 2)[0]
+		args = append(args, "--env", name)
+	}
+	args = append(args, container.Image, "sh", "-c", check.Command)
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " "), nil
+}
+
+// removeContainerCommand returns the command forcibly removing the named
+// container, which keeps running once the container engine client executing
+// a check is killed on timeout.
+func removeContainerCommand(container *corev2.CheckContainer, defaultRuntime, name string) string {
+	return shellQuote(containerEngine(container, defaultRuntime)) + " rm --force " + shellQuote(name)
+}
+
+// shellQuote quotes the argument for a POSIX shell.
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=./:,@") == "" {
+		return arg
+	}
+	return "'" + strings.Replace(arg, "'", `'"'"'`, -1) + "'"
+}
+
+// removeContainer forcibly removes the named container of a check which timed
+// out.
+func (a *Agent) removeContainer(check *corev2.CheckConfig, name string, env []string) {
+	ex := command.ExecutionRequest{
+		Env:     env,
+		Command: removeContainerCommand(check.Container, a.config.ContainerRuntime, name),
+		Timeout: containerRemoveTimeout,
+		Name:    check.Name,
+	}
+	resp, err := a.executor.Execute(context.Background(), ex)
+	if err == nil && resp.Status != 0 {
+		err = errors.New(strings.TrimSpace(resp.Output))
+	}
+	if err != nil {
+		logger.WithError(err).WithField("container", name).Error("unable to remove the container of a check which timed out")
+	}
+}
@@ -0,0 +1,229 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("checks executed in a container are not supported on windows")
+	}
+
+	check := corev2.FixtureCheckConfig("disk")
+	check.Command = "check-disk -w 80 -c 'free space'"
+	check.Container = corev2.FixtureCheckContainer("alpine:3")
+
+	command, err := containerCommand(check, corev2.ContainerRuntimeDocker, "sensu-check-disk-1")
+	require.NoError(t, err)
+	assert.Equal(t, `docker run --rm --name sensu-check-disk-1 --label io.sensu.check=disk alpine:3 sh -c 'check-disk -w 80 -c '"'"'free space'"'"''`, command)
+
+	check.Stdin = true
+	check.EnvVars = []string{"TOKEN=secret", "DEBUG"}
+	check.Container.Network = "host"
+	check.Container.Volumes = []string{"/var/log:/var/log:ro"}
+	command, err = containerCommand(check, corev2.ContainerRuntimePodman, "sensu-check-disk-1")
+	require.NoError(t, err)
+	assert.Equal(t, `podman run --rm --name sensu-check-disk-1 --label io.sensu.check=disk --interactive --network host --volume /var/log:/var/log:ro --env TOKEN --env DEBUG alpine:3 sh -c 'check-disk -w 80 -c '"'"'free space'"'"''`, command)
+	assert.NotContains(t, command, "secret")
+
+	// The runtime of the check takes precedence
+	check.Container.Runtime = corev2.ContainerRuntimeNerdctl
+	command, err = containerCommand(check, corev2.ContainerRuntimePodman, "sensu-check-disk-1")
+	require.NoError(t, err)
+	assert.Regexp(t, "^nerdctl run ", command)
+	assert.Equal(t, "nerdctl rm --force sensu-check-disk-1", removeContainerCommand(check.Container, corev2.ContainerRuntimePodman, "sensu-check-disk-1"))
+
+	// There is no default runtime
+	check.Container.Runtime = ""
+	_, err = containerCommand(check, "", "sensu-check-disk-1")
+	assert.Error(t, err)
+}
+
+func TestContainersConfigAllow(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *ContainersConfig
+		container *corev2.CheckContainer
+		wantErr   bool
+	}{
+		{
+			name:      "no configuration",
+			container: &corev2.CheckContainer{Image: "alpine:3"},
+			wantErr:   true,
+		},
+		{
+			name:      "disabled",
+			config:    &ContainersConfig{},
+			container: &corev2.CheckContainer{Image: "alpine:3"},
+			wantErr:   true,
+		},
+		{
+			name:      "any image",
+			config:    &ContainersConfig{Enabled: true},
+			container: &corev2.CheckContainer{Image: "alpine:3"},
+		},
+		{
+			name:      "allowed image",
+			config:    &ContainersConfig{Enabled: true, Images: []string{"registry.example.com/*"}},
+			container: &corev2.CheckContainer{Image: "registry.example.com/checks:1.0"},
+		},
+		{
+			name:      "image not allowed",
+			config:    &ContainersConfig{Enabled: true, Images: []string{"registry.example.com/*"}},
+			container: &corev2.CheckContainer{Image: "alpine:3"},
+			wantErr:   true,
+		},
+		{
+			name:      "named volume",
+			config:    &ContainersConfig{Enabled: true},
+			container: &corev2.CheckContainer{Image: "alpine:3", Volumes: []string{"data:/data:ro"}},
+		},
+		{
+			name:      "host volume",
+			config:    &ContainersConfig{Enabled: true},
+			container: &corev2.CheckContainer{Image: "alpine:3", Volumes: []string{"/:/host"}},
+			wantErr:   true,
+		},
+		{
+			name:      "relative host volume",
+			config:    &ContainersConfig{Enabled: true},
+			container: &corev2.CheckContainer{Image: "alpine:3", Volumes: []string{"./etc:/etc"}},
+			wantErr:   true,
+		},
+		{
+			name:      "allowed host volume",
+			config:    &ContainersConfig{Enabled: true, HostVolumes: true},
+			container: &corev2.CheckContainer{Image: "alpine:3", Volumes: []string{"/var/log:/var/log:ro"}},
+		},
+		{
+			name:      "bridge network",
+			config:    &ContainersConfig{Enabled: true},
+			container: &corev2.CheckContainer{Image: "alpine:3", Network: "bridge"},
+		},
+		{
+			name:      "host network",
+			config:    &ContainersConfig{Enabled: true},
+			container: &corev2.CheckContainer{Image: "alpine:3", Network: "host"},
+			wantErr:   true,
+		},
+		{
+			name:      "container network",
+			config:    &ContainersConfig{Enabled: true},
+			container: &corev2.CheckContainer{Image: "alpine:3", Network: "container:backend"},
+			wantErr:   true,
+		},
+		{
+			name:      "allowed host network",
+			config:    &ContainersConfig{Enabled: true, HostNetwork: true},
+			container: &corev2.CheckContainer{Image: "alpine:3", Network: "host"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.allow(tt.container)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Error(t, ValidateContainersConfig(&ContainersConfig{Images: []string{"[a-"}}))
+}
+
+// recordingExecutor records the commands it executes, and returns the
+// response of the first one.
+type recordingExecutor struct {
+	mu       sync.Mutex
+	commands []string
+	response *command.ExecutionResponse
+}
+
+func (e *recordingExecutor) Execute(ctx context.Context, execution command.ExecutionRequest) (*command.ExecutionResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.commands = append(e.commands, execution.Command)
+	if len(e.commands) > 1 {
+		return command.FixtureExecutionResponse(0, ""), nil
+	}
+	return e.response, nil
+}
+
+func TestExecuteCheckInContainer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("checks executed in a container are not supported on windows")
+	}
+
+	check := corev2.FixtureCheckConfig("disk")
+	check.Command = "check-disk"
+	check.Container = corev2.FixtureCheckContainer("alpine:3")
+	request := &corev2.CheckRequest{Config: check, Issued: time.Now().Unix()}
+
+	execute := func(t *testing.T, containers *ContainersConfig, denyList string, response *command.ExecutionResponse) (*corev2.Event, []string) {
+		config, cleanup := FixtureConfig()
+		defer cleanup()
+		config.Containers = containers
+		agent, err := NewAgent(config)
+		require.NoError(t, err)
+		if denyList != "" {
+			agent.denyList, err = readDenyList("deny_list.json", func(string) ([]byte, error) {
+				return []byte(denyList), nil
+			})
+			require.NoError(t, err)
+		}
+		ch := make(chan *transport.Message, 1)
+		agent.sendq = ch
+		executor := &recordingExecutor{response: response}
+		agent.executor = executor
+
+		agent.executeCheck(context.TODO(), request, agent.getAgentEntity())
+		msg := <-ch
+		event := &corev2.Event{}
+		require.NoError(t, json.Unmarshal(msg.Payload, event))
+		return event, executor.commands
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		event, commands := execute(t, nil, "", nil)
+		assert.Equal(t, uint32(3), event.Check.Status)
+		assert.Contains(t, event.Check.Output, "disabled")
+		assert.Empty(t, commands)
+	})
+
+	t.Run("deny list matches the container command", func(t *testing.T) {
+		event, commands := execute(t, &ContainersConfig{Enabled: true}, `[{"exec": "docker"}]`, nil)
+		assert.Contains(t, event.Check.Output, denyListOnDenyOutput)
+		assert.Empty(t, commands)
+	})
+
+	t.Run("executed", func(t *testing.T) {
+		event, commands := execute(t, &ContainersConfig{Enabled: true}, "", command.FixtureExecutionResponse(0, "ok"))
+		assert.Equal(t, "ok", event.Check.Output)
+		require.Len(t, commands, 1)
+		assert.Regexp(t, "^docker run --rm --name sensu-check-disk-", commands[0])
+	})
+
+	t.Run("removed on timeout", func(t *testing.T) {
+		response := command.FixtureExecutionResponse(command.TimeoutExitStatus, command.TimeoutOutput)
+		response.Status = command.TimeoutExitStatus
+		response.TimedOut = true
+		event, commands := execute(t, &ContainersConfig{Enabled: true}, "", response)
+		assert.Equal(t, uint32(command.TimeoutExitStatus), event.Check.Status)
+		require.Len(t, commands, 2)
+		name := strings.Fields(commands[0])[4]
+		assert.Equal(t, "docker rm --force "+name, commands[1])
+	})
+}
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// denyListOnDenyOutput is the output of the checks denied by the deny list of
+// the agent.
+const denyListOnDenyOutput = "policy violation: check command denied by the agent deny list"
+
+// denyList is an entry of the deny list of the agent. The commands matching
+// either its exec or its pattern are never executed, even if they match the
+// allow list.
+type denyList struct {
+	// Exec is a path or a glob pattern matched against the executable of the
+	// command, and against its base name.
+	Exec string `yaml:"exec" json:"exec"`
+
+	// Pattern is a regular expression matched against the whole command.
+	Pattern string `yaml:"pattern" json:"pattern"`
+
+	regexp *regexp.Regexp
+}
+
+func readDenyList(path string, readBytes func(string) ([]byte, error)) ([]denyList, error) {
+	var denyList []denyList
+	if err := readListFile(path, readBytes, &denyList); err != nil {
+		return nil, err
+	}
+	for i := range denyList {
+		if err := denyList[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+	return denyList, nil
+}
+
+// validate returns an error if the denyList contains invalid values, and
+// compiles its pattern.
+func (dl *denyList) validate() error {
+	if dl.Exec == "" && dl.Pattern == "" {
+		return errors.New("exec and pattern cannot both be empty")
+	}
+
+	if dl.Exec != "" {
+		if _, err := filepath.Match(dl.Exec, ""); err != nil {
+			return fmt.Errorf("invalid exec %q: %s", dl.Exec, err)
+		}
+	}
+
+	if dl.Pattern != "" {
+		re, err := regexp.Compile(dl.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %s", dl.Pattern, err)
+		}
+		dl.regexp = re
+	}
+
+	return nil
+}
+
+// String returns the rule of the entry, reported when it denies a command.
+func (dl denyList) String() string {
+	if dl.Exec != "" {
+		return fmt.Sprintf("exec %q", dl.Exec)
+	}
+	return fmt.Sprintf("pattern %q", dl.Pattern)
+}
+
+// match returns true if the command matches the entry.
+func (dl denyList) match(command string) bool {
+	if dl.Exec != "" {
+		if fields := strings.Fields(command); len(fields) > 0 {
+			exec := fields[0]
+			if ok, _ := filepath.Match(dl.Exec, exec); ok {
+				return true
+			}
+			if ok, _ := filepath.Match(dl.Exec, filepath.Base(exec)); ok {
+				return true
+			}
+		}
+	}
+	return dl.regexp != nil && dl.regexp.MatchString(command)
+}
+
+// matchDenyList returns the first entry of the deny list of the agent matching
+// the command, if any.
+func (a *Agent) matchDenyList(command string) (denyList, bool) {
+	for _, dl := range a.denyList {
+		if dl.match(command) {
+			return dl, true
+		}
+	}
+	return denyList{}, false
+}
+
+// denyListError returns the error reported when the entry denies a command.
+func denyListError(entry denyList) error {
+	return fmt.Errorf("%s (%s)", denyListOnDenyOutput, entry)
+}
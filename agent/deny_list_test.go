@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDenyListValidYAML(t *testing.T) {
+	denyList, err := readDenyList("deny_list.yaml", func(string) ([]byte, error) {
+		return []byte(`
+        - exec: /usr/bin/*
+        - pattern: "rm\\s+-rf"
+        `), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(denyList))
+	assert.Equal(t, "/usr/bin/*", denyList[0].Exec)
+	assert.NotNil(t, denyList[1].regexp)
+}
+
+func TestDenyListValidateError(t *testing.T) {
+	for _, content := range []string{
+		`[{}]`,
+		`[{"exec": "[a-"}]`,
+		`[{"pattern": "("}]`,
+	} {
+		denyList, err := readDenyList("deny_list.json", func(string) ([]byte, error) {
+			return []byte(content), nil
+		})
+		assert.Error(t, err, content)
+		assert.Nil(t, denyList, content)
+	}
+}
+
+func TestMatchDenyList(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	agent.denyList, err = readDenyList("deny_list.json", func(string) ([]byte, error) {
+		return []byte(`[{"exec": "/usr/bin/*"}, {"exec": "nc"}, {"pattern": "rm\\s+-rf"}]`), nil
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		command string
+		denied  bool
+		rule    string
+	}{
+		{command: "/usr/bin/curl -s localhost", denied: true, rule: `exec "/usr/bin/*"`},
+		{command: "/bin/nc -l 8080", denied: true, rule: `exec "nc"`},
+		{command: "nc -l 8080", denied: true, rule: `exec "nc"`},
+		{command: "cleanup.sh && rm   -rf /tmp/x", denied: true, rule: `pattern "rm\\s+-rf"`},
+		{command: "/usr/local/bin/check-cpu.rb -w 80", denied: false},
+		{command: "ncat -l 8080", denied: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			entry, denied := agent.matchDenyList(tt.command)
+			assert.Equal(t, tt.denied, denied)
+			if tt.denied {
+				assert.Equal(t, tt.rule, entry.String())
+			}
+		})
+	}
+
+	// The deny list takes precedence over the allow list
+	agent.allowList = []allowList{{Exec: "nc", Args: []string{""}}}
+	assert.Error(t, agent.verifyCommand("nc -l 8080"))
+}
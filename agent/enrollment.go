@@ -0,0 +1,275 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/agentd"
+	"github.com/sensu/sensu-go/util/retry"
+)
+
+const (
+	// enrollmentCertFile and enrollmentKeyFile are the files of the cache
+	// directory the certificate obtained by the agent and its key are
+	// written to, if the TLS certificate and key files are not set.
+	enrollmentCertFile = "enrollment-cert.pem"
+	enrollmentKeyFile  = "enrollment-key.pem"
+
+	// enrollmentMaxBackoff is the maximum time between two enrollment
+	// attempts, and between two renewal attempts.
+	enrollmentMaxBackoff = time.Minute
+
+	// enrollmentTimeout is the time after which an enrollment request fails.
+	enrollmentTimeout = 30 * time.Second
+
+	// maxEnrollmentResponseSize is the maximum size of the enrollment
+	// responses.
+	maxEnrollmentResponseSize = 1024 * 1024
+)
+
+// configureEnrollmentFiles sets the TLS certificate and key files of the
+// configuration to files of the cache directory, unless they are both set.
+func configureEnrollmentFiles(config *Config) {
+	if config.TLS == nil {
+		config.TLS = &corev2.TLSOptions{}
+	}
+	if config.TLS.CertFile == "" || config.TLS.KeyFile == "" {
+		config.TLS.CertFile = filepath.Join(config.CacheDir, enrollmentCertFile)
+		config.TLS.KeyFile = filepath.Join(config.CacheDir, enrollmentKeyFile)
+	}
+}
+
+// enrollmentURL returns the URL of the enrollment endpoint of the backend.
+func enrollmentURL(backendURL string) (string, error) {
+	u, err := url.Parse(backendURL)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	default:
+		return "", fmt.Errorf("can't enroll with backend %q, only ws and wss backend URLs support enrollment", backendURL)
+	}
+	u.Path = agentd.EnrollmentPath
+	u.RawQuery = ""
+	return u.String(), nil
+}
+
+// renewalTime returns the time at which the certificate is renewed, once two
+// thirds of its validity elapsed.
+func renewalTime(cert *x509.Certificate) time.Time {
+	return cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore) * 2 / 3)
+}
+
+// enrolledCertificate returns the TLS client certificate of the agent, or an
+// error if its files do not hold a certificate and its key.
+func (a *Agent) enrolledCertificate() (*x509.Certificate, error) {
+	pair, err := tls.LoadX509KeyPair(a.config.TLS.CertFile, a.config.TLS.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(pair.Certificate[0])
+}
+
+// enrollmentRejectedError is returned when the backend rejects a certificate
+// request, which fails again if retried.
+type enrollmentRejectedError struct {
+	backend string
+	message string
+}
+
+func (e *enrollmentRejectedError) Error() string {
+	return fmt.Sprintf("backend %q rejected the certificate request: %s", e.backend, e.message)
+}
+
+// enroll obtains a TLS client certificate with the enrollment token, unless
+// the agent already has a valid one, e.g. obtained before it restarted. It
+// retries until it succeeds, and returns an error once the context is
+// canceled or if the backend rejects the token, which can not succeed later.
+// An expired certificate is replaced with the token, which was likely consumed
+// to obtain it, so a new token is then required.
+func (a *Agent) enroll(ctx context.Context) error {
+	cert, err := a.enrolledCertificate()
+	if err == nil && time.Now().Before(cert.NotAfter) {
+		return nil
+	}
+	expired := err == nil
+	backoff := retry.ExponentialBackoff{
+		InitialDelayInterval: time.Second,
+		MaxDelayInterval:     enrollmentMaxBackoff,
+		Multiplier:           2,
+		Ctx:                  ctx,
+	}
+	return backoff.Retry(func(retry int) (bool, error) {
+		if err := a.requestCertificate(ctx, false); err != nil {
+			if _, ok := err.(*enrollmentRejectedError); ok {
+				if expired {
+					return true, fmt.Errorf("the TLS client certificate of the agent expired on %s and the enrollment token was rejected, a new enrollment token is required: %s", cert.NotAfter, err)
+				}
+				return true, fmt.Errorf("agent enrollment failed: %s", err)
+			}
+			logger.WithError(err).Error("agent enrollment failed")
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+// renewCertificatePeriodically renews the TLS client certificate of the agent
+// once two thirds of its validity elapsed, until the context is canceled. The
+// agent reconnects with the renewed certificate once it reads it again.
+func (a *Agent) renewCertificatePeriodically(ctx context.Context) {
+	defer logger.Debug("shutting down TLS certificate renewal")
+	for {
+		delay := enrollmentMaxBackoff
+		cert, err := a.enrolledCertificate()
+		if err != nil {
+			logger.WithError(err).Error("could not read the TLS client certificate of the agent")
+		} else if delay = time.Until(renewalTime(cert)); delay <= 0 {
+			if err := a.requestCertificate(ctx, true); err == nil {
+				continue
+			}
+			logger.WithError(err).Error("could not renew the TLS client certificate of the agent")
+			delay = enrollmentMaxBackoff
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// requestCertificate requests a TLS client certificate for a new key from the
+// backend, with the enrollment token, or with the current certificate to
+// renew it, and writes them to the TLS certificate and key files.
+func (a *Agent) requestCertificate(ctx context.Context, renew bool) error {
+	backendURL := a.selectBackend()
+	endpoint, err := enrollmentURL(backendURL)
+	if err != nil {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: a.config.AgentName},
+	}, key)
+	if err != nil {
+		return err
+	}
+	enrollment := agentd.EnrollmentRequest{
+		Namespace: a.config.Namespace,
+		CSR:       string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+	}
+
+	// The certificate is presented to renew it, the token otherwise
+	opts := *a.config.TLS
+	if !renew {
+		enrollment.Token = a.config.EnrollmentToken
+		opts.CertFile, opts.KeyFile = "", ""
+	}
+	tlsConfig, err := opts.ToClientTLSConfig()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(enrollment)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{
+		Timeout: enrollmentTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxEnrollmentResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusBadRequest {
+		return &enrollmentRejectedError{backend: backendURL, message: strings.TrimSpace(string(data))}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("backend %q could not issue the certificate: %s", backendURL, strings.TrimSpace(string(data)))
+	}
+
+	var issued agentd.EnrollmentResponse
+	if err := json.Unmarshal(data, &issued); err != nil {
+		return fmt.Errorf("invalid enrollment response: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if _, err := tls.X509KeyPair([]byte(issued.Certificate), keyPEM); err != nil {
+		return fmt.Errorf("invalid certificate issued by the backend: %s", err)
+	}
+
+	// The key is written first, the certificate files are only read once
+	// they match
+	if err := writeFileAtomically(a.config.TLS.KeyFile, keyPEM, 0600); err != nil {
+		return err
+	}
+	if err := writeFileAtomically(a.config.TLS.CertFile, []byte(issued.Certificate), 0644); err != nil {
+		return err
+	}
+	message := "agent enrolled, TLS client certificate obtained"
+	if renew {
+		message = "TLS client certificate renewed"
+	}
+	logger.WithField("expiry", time.Unix(issued.Expires, 0)).Info(message)
+	return nil
+}
+
+// writeFileAtomically writes the data to a temporary file renamed to the
+// file, so it is never read partially written.
+func writeFileAtomically(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, perm); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/agentd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrollmentURL(t *testing.T) {
+	u, err := enrollmentURL("wss://backend:8081")
+	require.NoError(t, err)
+	assert.Equal(t, "https://backend:8081/enrollment", u)
+
+	u, err = enrollmentURL("ws://backend:8081/")
+	require.NoError(t, err)
+	assert.Equal(t, "http://backend:8081/enrollment", u)
+
+	_, err = enrollmentURL("grpcs://backend:8082")
+	assert.Error(t, err)
+}
+
+func TestRenewalTime(t *testing.T) {
+	now := time.Now()
+	cert := &x509.Certificate{NotBefore: now, NotAfter: now.Add(3 * time.Hour)}
+	assert.Equal(t, now.Add(2*time.Hour), renewalTime(cert))
+}
+
+func TestEnroll(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sensu-agents"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	var requests []agentd.EnrollmentRequest
+	var renewals int
+	validity := time.Hour
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, agentd.EnrollmentPath, r.URL.Path)
+		var req agentd.EnrollmentRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		if len(r.TLS.PeerCertificates) > 0 {
+			renewals++
+		} else if req.Token != "5f4dcc3b5aa765d61d8327deb882cf99" {
+			http.Error(w, "invalid enrollment token", http.StatusUnauthorized)
+			return
+		}
+		block, _ := pem.Decode([]byte(req.CSR))
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(len(requests) + 1)),
+			Subject:      pkix.Name{CommonName: csr.Subject.CommonName, OrganizationalUnit: []string{req.Namespace}},
+			NotBefore:    time.Now().Add(-time.Minute),
+			NotAfter:     time.Now().Add(validity),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, csr.PublicKey, caKey)
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(agentd.EnrollmentResponse{
+			Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+			Expires:     template.NotAfter.Unix(),
+		})
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	cacheDir, err := ioutil.TempDir("", "sensu-agent-enrollment")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.AgentName = "web-1"
+	config.CacheDir = cacheDir
+	config.TLS = &corev2.TLSOptions{InsecureSkipVerify: true}
+	config.EnrollmentToken = "invalid"
+	configureEnrollmentFiles(config)
+	agent := &Agent{
+		backendSelector: &RandomBackendSelector{Backends: []string{strings.Replace(server.URL, "https", "wss", 1)}},
+		config:          config,
+	}
+
+	// Rejected enrollment, which is not retried
+	err = agent.requestCertificate(context.Background(), false)
+	assert.IsType(t, &enrollmentRejectedError{}, err)
+	err = agent.enroll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid enrollment token")
+	_, err = agent.enrolledCertificate()
+	assert.Error(t, err)
+
+	// Enrollment with the token
+	config.EnrollmentToken = "5f4dcc3b5aa765d61d8327deb882cf99"
+	require.NoError(t, agent.enroll(context.Background()))
+	cert, err := agent.enrolledCertificate()
+	require.NoError(t, err)
+	assert.Equal(t, "web-1", cert.Subject.CommonName)
+	assert.Equal(t, []string{"default"}, cert.Subject.OrganizationalUnit)
+	require.Len(t, requests, 3)
+	assert.Equal(t, "default", requests[2].Namespace)
+
+	// The agent does not enroll again once it has a certificate
+	require.NoError(t, agent.enroll(context.Background()))
+	assert.Len(t, requests, 3)
+
+	// Renewal with the certificate
+	require.NoError(t, agent.requestCertificate(context.Background(), true))
+	assert.Equal(t, 1, renewals)
+	assert.Empty(t, requests[3].Token)
+	renewed, err := agent.enrolledCertificate()
+	require.NoError(t, err)
+	assert.NotEqual(t, cert.SerialNumber, renewed.SerialNumber)
+
+	// Once its certificate expired, the agent can not enroll again with the
+	// consumed token
+	validity = -time.Second
+	require.NoError(t, agent.requestCertificate(context.Background(), true))
+	config.EnrollmentToken = "consumed5aa765d61d8327deb882cf99"
+	err = agent.enroll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a new enrollment token is required")
+}
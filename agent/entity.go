@@ -8,10 +8,23 @@ import (
 )
 
 func (a *Agent) getAgentEntity() *types.Entity {
+	a.entityMu.Lock()
+	defer a.entityMu.Unlock()
+
 	if a.entity == nil {
 		meta := v2.NewObjectMeta(a.config.AgentName, a.config.Namespace)
 		meta.Labels = a.config.Labels
 		meta.Annotations = a.config.Annotations
+		if a.cloudMetadata != nil {
+			// The labels and annotations of the agent configuration take
+			// precedence over the cloud instance metadata
+			meta.Labels = mergeLabels(a.cloudMetadata.labels(), meta.Labels)
+			meta.Annotations = mergeLabels(a.cloudMetadata.annotations(), meta.Annotations)
+		}
+		if a.managedConfig != nil {
+			meta.Labels = mergeLabels(meta.Labels, a.managedConfig.Labels)
+			meta.Annotations = mergeLabels(meta.Annotations, a.managedConfig.Annotations)
+		}
 		e := &types.Entity{
 			EntityClass:   types.EntityAgentClass,
 			Deregister:    a.config.Deregister,
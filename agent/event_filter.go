@@ -0,0 +1,255 @@
+package agent
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	time "github.com/echlebek/timeproxy"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/js"
+	"github.com/sensu/sensu-go/types/dynamic"
+	"github.com/sensu/sensu-go/util/lru"
+)
+
+const (
+	// eventFilterDropOKMetricOnlyAnnotation is the check annotation that
+	// overrides EventFilterConfig.DropOKMetricOnly for a given check.
+	eventFilterDropOKMetricOnlyAnnotation = "agent_events_drop_ok_metric_only"
+
+	// eventFilterSampleRateAnnotation is the check annotation that overrides
+	// EventFilterConfig.SampleRate for a given check.
+	eventFilterSampleRateAnnotation = "agent_events_sample_rate"
+
+	// eventFilterDedupWindowAnnotation is the check annotation that overrides
+	// EventFilterConfig.DedupWindow for a given check.
+	eventFilterDedupWindowAnnotation = "agent_events_dedup_window"
+
+	// eventFilterRuleDrop is the action of the rules dropping the events they
+	// match.
+	eventFilterRuleDrop = "drop"
+
+	// eventFilterRuleSample is the action of the rules sampling the events
+	// they match, e.g. sample=10.
+	eventFilterRuleSample = "sample"
+
+	// eventFilterMaxStates is the number of entity checks whose last
+	// transmitted event is tracked. The least recently seen ones are
+	// forgotten, and their next event is transmitted.
+	eventFilterMaxStates = 10000
+)
+
+// EventFilterConfig contains the configuration of the filters the agent
+// applies to events before transmitting them to the backend. Each setting can
+// be overridden for a given check with check annotations. Events reporting a
+// status change are always transmitted. The dropped events don't refresh the
+// TTL of their check on the backend, so the events of the checks with a TTL
+// are transmitted at least once every half TTL.
+type EventFilterConfig struct {
+	// DropOKMetricOnly drops events with an OK status coming from checks that
+	// only produce metrics, i.e. checks that have metrics but no handlers.
+	DropOKMetricOnly bool
+
+	// SampleRate only transmits one out of every SampleRate events with an OK
+	// status for a given check. Values lower than 2 disable sampling.
+	SampleRate int
+
+	// DedupWindow drops OK events, for a given check, whose output is
+	// identical to the last transmitted event if that event was transmitted
+	// less than DedupWindow seconds ago. The non-OK events are all
+	// transmitted, so the backend keeps counting their occurrences. 0
+	// disables deduplication.
+	DedupWindow int
+
+	// Rules drop or sample the events matching their expressions. The first
+	// rule an event matches applies, rather than the other settings.
+	Rules []EventFilterRule
+}
+
+// EventFilterRule drops or samples the events matching its expression.
+type EventFilterRule struct {
+	// Expression is the JavaScript expression of the events the rule applies
+	// to, such as the expressions of the backend event filters, e.g.
+	// event.check.name == "cpu" && event.check.status == 0.
+	Expression string
+
+	// SampleRate only transmits one out of every SampleRate events of a given
+	// check matching the rule. Values lower than 2 drop all of them.
+	SampleRate int
+}
+
+// ParseEventFilterRule parses a rule of the agent event filters, which is
+// either drop:<expression> or sample=<rate>:<expression>.
+func ParseEventFilterRule(rule string) (EventFilterRule, error) {
+	parts := strings.SplitN(rule, ":", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+		return EventFilterRule{}, fmt.Errorf("invalid event filter rule %q, must be drop:<expression> or sample=<rate>:<expression>", rule)
+	}
+	result := EventFilterRule{Expression: strings.TrimSpace(parts[1])}
+
+	action := strings.TrimSpace(parts[0])
+	switch {
+	case action == eventFilterRuleDrop:
+	case strings.HasPrefix(action, eventFilterRuleSample+"="):
+		rate, err := strconv.Atoi(strings.TrimPrefix(action, eventFilterRuleSample+"="))
+		if err != nil || rate < 2 {
+			return EventFilterRule{}, fmt.Errorf("invalid event filter rule %q, the sample rate must be an integer greater than 1", rule)
+		}
+		result.SampleRate = rate
+	default:
+		return EventFilterRule{}, fmt.Errorf("invalid event filter rule %q, the action must be drop or sample=<rate>", rule)
+	}
+
+	if err := js.ParseExpressions([]string{result.Expression}); err != nil {
+		return EventFilterRule{}, fmt.Errorf("invalid event filter rule %q: %s", rule, err)
+	}
+	return result, nil
+}
+
+// eventFilterState keeps track of the last event transmitted for a check.
+type eventFilterState struct {
+	status    uint32
+	output    string
+	forwarded int64
+	skipped   int
+
+	// ruleSkipped is the number of events dropped by the sampling rules.
+	ruleSkipped int
+}
+
+// eventFilter drops events that don't need to be transmitted to the backend,
+// according to its configuration.
+type eventFilter struct {
+	config EventFilterConfig
+	states *lru.Cache
+	mu     sync.Mutex
+}
+
+func newEventFilter(config EventFilterConfig) *eventFilter {
+	return &eventFilter{
+		config: config,
+		states: lru.New(eventFilterMaxStates),
+	}
+}
+
+// Filter returns true if the event should be dropped instead of being
+// transmitted to the backend.
+func (f *eventFilter) Filter(event *corev2.Event) bool {
+	if !event.HasCheck() || event.Entity == nil {
+		return false
+	}
+	cfg := f.checkConfig(event.Check)
+	if !cfg.DropOKMetricOnly && cfg.SampleRate < 2 && cfg.DedupWindow <= 0 && len(cfg.Rules) == 0 {
+		return false
+	}
+
+	// The rules are evaluated before locking, since their expressions may
+	// be slow to evaluate
+	rule, matched := f.matchRule(event)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := path.Join(event.Entity.Name, event.Check.Name)
+	value, ok := f.states.Get(key)
+	if !ok {
+		f.forward(key, event)
+		return false
+	}
+	state := value.(*eventFilterState)
+	if state.status != event.Check.Status {
+		f.forward(key, event)
+		return false
+	}
+
+	// The check TTL must not expire on the backend
+	if ttl := int64(event.Check.Ttl); ttl > 0 && time.Now().Unix()-state.forwarded >= ttl/2 {
+		f.forward(key, event)
+		return false
+	}
+
+	if matched {
+		if rule.SampleRate < 2 {
+			return true
+		}
+		state.ruleSkipped++
+		if state.ruleSkipped < rule.SampleRate {
+			return true
+		}
+		f.forward(key, event)
+		return false
+	}
+
+	isOK := event.Check.Status == 0
+	if isOK && cfg.DropOKMetricOnly && event.HasMetrics() && len(event.Check.Handlers) == 0 {
+		return true
+	}
+
+	if isOK && cfg.DedupWindow > 0 && state.output == event.Check.Output &&
+		time.Now().Unix()-state.forwarded < int64(cfg.DedupWindow) {
+		return true
+	}
+
+	if isOK && cfg.SampleRate > 1 {
+		state.skipped++
+		if state.skipped < cfg.SampleRate {
+			return true
+		}
+	}
+
+	f.forward(key, event)
+	return false
+}
+
+// matchRule returns the first rule the event matches, if any. The rules whose
+// expressions can not be evaluated for the event don't match it.
+func (f *eventFilter) matchRule(event *corev2.Event) (EventFilterRule, bool) {
+	if len(f.config.Rules) == 0 {
+		return EventFilterRule{}, false
+	}
+	parameters := map[string]interface{}{"event": dynamic.Synthesize(event)}
+	for _, rule := range f.config.Rules {
+		match, err := js.Evaluate(rule.Expression, parameters, nil)
+		if err != nil {
+			logger.WithError(err).WithField("expression", rule.Expression).Debug("could not evaluate the event filter rule")
+			continue
+		}
+		if match {
+			return rule, true
+		}
+	}
+	return EventFilterRule{}, false
+}
+
+// forward records the event as the last transmitted event for its check.
+func (f *eventFilter) forward(key string, event *corev2.Event) {
+	f.states.Add(key, &eventFilterState{
+		status:    event.Check.Status,
+		output:    event.Check.Output,
+		forwarded: time.Now().Unix(),
+	})
+}
+
+// checkConfig returns the filter configuration for the given check, with the
+// check annotations taking precedence over the agent configuration.
+func (f *eventFilter) checkConfig(check *corev2.Check) EventFilterConfig {
+	cfg := f.config
+	if value, ok := check.Annotations[eventFilterDropOKMetricOnlyAnnotation]; ok {
+		if b, err := strconv.ParseBool(value); err == nil {
+			cfg.DropOKMetricOnly = b
+		}
+	}
+	if value, ok := check.Annotations[eventFilterSampleRateAnnotation]; ok {
+		if i, err := strconv.Atoi(value); err == nil {
+			cfg.SampleRate = i
+		}
+	}
+	if value, ok := check.Annotations[eventFilterDedupWindowAnnotation]; ok {
+		if i, err := strconv.Atoi(value); err == nil {
+			cfg.DedupWindow = i
+		}
+	}
+	return cfg
+}
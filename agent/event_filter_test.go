@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"fmt"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventFilter(t *testing.T) {
+	newEvent := func(status uint32, output string) *corev2.Event {
+		event := corev2.FixtureEvent("entity1", "check1")
+		event.Check.Status = status
+		event.Check.Output = output
+		return event
+	}
+	newMetricEvent := func(status uint32) *corev2.Event {
+		event := newEvent(status, "")
+		event.Check.Handlers = nil
+		event.Metrics = corev2.FixtureMetrics()
+		return event
+	}
+
+	testCases := []struct {
+		name   string
+		config EventFilterConfig
+		events []*corev2.Event
+		want   []bool
+	}{
+		{
+			name:   "no filters",
+			events: []*corev2.Event{newEvent(0, "ok"), newEvent(0, "ok"), newEvent(0, "ok")},
+			want:   []bool{false, false, false},
+		},
+		{
+			name:   "drop ok metric only",
+			config: EventFilterConfig{DropOKMetricOnly: true},
+			events: []*corev2.Event{newMetricEvent(0), newMetricEvent(0), newMetricEvent(2), newMetricEvent(2), newEvent(0, "ok")},
+			want:   []bool{false, true, false, false, false},
+		},
+		{
+			name:   "sample rate",
+			config: EventFilterConfig{SampleRate: 3},
+			events: []*corev2.Event{newEvent(0, "ok"), newEvent(0, "ok"), newEvent(0, "ok"), newEvent(0, "ok"), newEvent(1, "warn"), newEvent(1, "warn")},
+			want:   []bool{false, true, true, false, false, false},
+		},
+		{
+			name:   "dedup window",
+			config: EventFilterConfig{DedupWindow: 60},
+			events: []*corev2.Event{newEvent(0, "ok"), newEvent(0, "ok"), newEvent(0, "still ok"), newEvent(2, "crit")},
+			want:   []bool{false, true, false, false},
+		},
+		{
+			name:   "dedup window repeated critical",
+			config: EventFilterConfig{DedupWindow: 60},
+			events: []*corev2.Event{newEvent(2, "crit"), newEvent(2, "crit"), newEvent(2, "crit")},
+			want:   []bool{false, false, false},
+		},
+		{
+			name: "annotations override config",
+			events: func() []*corev2.Event {
+				events := []*corev2.Event{newEvent(0, "ok"), newEvent(0, "ok")}
+				for _, event := range events {
+					event.Check.Annotations = map[string]string{eventFilterSampleRateAnnotation: "10"}
+				}
+				return events
+			}(),
+			want: []bool{false, true},
+		},
+		{
+			name:   "drop rule",
+			config: EventFilterConfig{Rules: []EventFilterRule{{Expression: "event.check.status == 0"}}},
+			events: []*corev2.Event{newEvent(0, "ok"), newEvent(0, "ok"), newEvent(2, "crit"), newEvent(2, "crit"), newEvent(0, "ok"), newEvent(0, "ok")},
+			want:   []bool{false, true, false, false, false, true},
+		},
+		{
+			name:   "sample rule",
+			config: EventFilterConfig{Rules: []EventFilterRule{{Expression: "event.check.name == 'check1'", SampleRate: 2}}},
+			events: []*corev2.Event{newEvent(1, "warn"), newEvent(1, "warn"), newEvent(1, "warn"), newEvent(1, "warn")},
+			want:   []bool{false, true, false, true},
+		},
+		{
+			name: "first matching rule",
+			config: EventFilterConfig{Rules: []EventFilterRule{
+				{Expression: "event.check.output == 'keep'", SampleRate: 100},
+				{Expression: "event.entity.name == 'entity1'"},
+			}},
+			events: []*corev2.Event{newEvent(0, "ok"), newEvent(0, "keep"), newEvent(0, "ok")},
+			want:   []bool{false, true, true},
+		},
+		{
+			name:   "rule not matching",
+			config: EventFilterConfig{Rules: []EventFilterRule{{Expression: "event.check.name == 'other'"}, {Expression: "event.foo.bar == 1"}}},
+			events: []*corev2.Event{newEvent(0, "ok"), newEvent(0, "ok")},
+			want:   []bool{false, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter := newEventFilter(tc.config)
+			for i, event := range tc.events {
+				assert.Equal(t, tc.want[i], filter.Filter(event), "event %d", i)
+			}
+		})
+	}
+}
+
+func TestEventFilterCheckTTL(t *testing.T) {
+	filter := newEventFilter(EventFilterConfig{DropOKMetricOnly: true})
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.Handlers = nil
+	event.Check.Ttl = 120
+	event.Metrics = corev2.FixtureMetrics()
+
+	assert.False(t, filter.Filter(event))
+	assert.True(t, filter.Filter(event))
+
+	// An event is transmitted once the last one is older than half the TTL
+	value, _ := filter.states.Get("entity1/check1")
+	value.(*eventFilterState).forwarded -= 60
+	assert.False(t, filter.Filter(event))
+	assert.True(t, filter.Filter(event))
+}
+
+func TestEventFilterMaxStates(t *testing.T) {
+	filter := newEventFilter(EventFilterConfig{SampleRate: 10})
+	for i := 0; i < eventFilterMaxStates+10; i++ {
+		event := corev2.FixtureEvent(fmt.Sprintf("entity%d", i), "check1")
+		assert.False(t, filter.Filter(event))
+	}
+	assert.Equal(t, eventFilterMaxStates, filter.states.Len())
+}
+
+func TestParseEventFilterRule(t *testing.T) {
+	rule, err := ParseEventFilterRule("drop: event.check.status == 0")
+	assert.NoError(t, err)
+	assert.Equal(t, EventFilterRule{Expression: "event.check.status == 0"}, rule)
+
+	rule, err = ParseEventFilterRule(`sample=10:event.check.output.indexOf("a:b") >= 0`)
+	assert.NoError(t, err)
+	assert.Equal(t, EventFilterRule{Expression: `event.check.output.indexOf("a:b") >= 0`, SampleRate: 10}, rule)
+
+	for _, rule := range []string{"event.check.status == 0", "drop:", "keep:true", "sample=1:true", "sample=x:true", "drop:event.check.status =="} {
+		_, err := ParseEventFilterRule(rule)
+		assert.Error(t, err, rule)
+	}
+}
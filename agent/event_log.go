@@ -0,0 +1,271 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+const (
+	// eventLogBookmarksFile is the file of the cache directory in which the
+	// bookmarks of the event log checks are kept.
+	eventLogBookmarksFile = "event-log-bookmarks.json"
+
+	// defaultEventLogLookback is how far back the event log checks without a
+	// bookmark and without an interval, i.e. the cron checks, read the
+	// records on their first execution.
+	defaultEventLogLookback = time.Minute
+)
+
+// eventLogReader reads at most max records of the event log channel matching
+// the XPath query, the oldest first. It is only implemented on Windows.
+var eventLogReader = readEventLog
+
+// eventLogLevels are the values of the Level element of the records of the
+// event log, by level of the event log queries. The records logged with the
+// LogAlways level are displayed as information records.
+var eventLogLevels = map[string][]int{
+	corev2.EventLogLevelCritical:    {1},
+	corev2.EventLogLevelError:       {2},
+	corev2.EventLogLevelWarning:     {3},
+	corev2.EventLogLevelInformation: {0, 4},
+	corev2.EventLogLevelVerbose:     {5},
+}
+
+// eventLogRecord is a record of the event log, as rendered in XML by the
+// event log API.
+type eventLogRecord struct {
+	System struct {
+		Provider struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"Provider"`
+		EventID     uint32 `xml:"EventID"`
+		Level       int    `xml:"Level"`
+		TimeCreated struct {
+			SystemTime string `xml:"SystemTime,attr"`
+		} `xml:"TimeCreated"`
+		EventRecordID uint64 `xml:"EventRecordID"`
+		Channel       string `xml:"Channel"`
+		Computer      string `xml:"Computer"`
+	} `xml:"System"`
+
+	// Message is the formatted message of the record, if its provider could
+	// format it.
+	Message string `xml:"-"`
+}
+
+// parseEventLogRecord parses a record of the event log rendered in XML.
+func parseEventLogRecord(data string) (*eventLogRecord, error) {
+	var record eventLogRecord
+	if err := xml.Unmarshal([]byte(data), &record); err != nil {
+		return nil, fmt.Errorf("invalid event log record: %s", err)
+	}
+	return &record, nil
+}
+
+// levelName returns the name of the level of the record.
+func (r *eventLogRecord) levelName() string {
+	for name, values := range eventLogLevels {
+		for _, value := range values {
+			if r.System.Level == value {
+				return name
+			}
+		}
+	}
+	return fmt.Sprintf("level %d", r.System.Level)
+}
+
+// status returns the check status of the record: critical for the critical
+// and error records, warning for the warning records and ok otherwise.
+func (r *eventLogRecord) status() uint32 {
+	switch r.System.Level {
+	case 1, 2:
+		return 2
+	case 3:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// String returns the record on a single line.
+func (r *eventLogRecord) String() string {
+	message := strings.Join(strings.Fields(r.Message), " ")
+	return fmt.Sprintf("%s %s %s %d: %s", r.System.TimeCreated.SystemTime, r.levelName(), r.System.Provider.Name, r.System.EventID, message)
+}
+
+// eventLogXPath returns the XPath query of the records of the event log
+// matching the query, read after the bookmarked record, or, without a
+// bookmark, created during the lookback.
+func eventLogXPath(query *corev2.EventLogQuery, bookmark uint64, lookback time.Duration) string {
+	var conditions []string
+
+	var levels []string
+	for _, level := range query.Levels {
+		for _, value := range eventLogLevels[level] {
+			levels = append(levels, fmt.Sprintf("Level=%d", value))
+		}
+	}
+	if len(levels) > 0 {
+		conditions = append(conditions, "("+strings.Join(levels, " or ")+")")
+	}
+
+	var providers []string
+	for _, provider := range query.Providers {
+		providers = append(providers, fmt.Sprintf("@Name='%s'", provider))
+	}
+	if len(providers) > 0 {
+		conditions = append(conditions, "Provider["+strings.Join(providers, " or ")+"]")
+	}
+
+	var ids []string
+	for _, id := range query.EventIDs {
+		ids = append(ids, fmt.Sprintf("EventID=%d", id))
+	}
+	if len(ids) > 0 {
+		conditions = append(conditions, "("+strings.Join(ids, " or ")+")")
+	}
+
+	if bookmark > 0 {
+		conditions = append(conditions, fmt.Sprintf("EventRecordID>%d", bookmark))
+	} else if lookback > 0 {
+		conditions = append(conditions, fmt.Sprintf("TimeCreated[timediff(@SystemTime)<=%d]", lookback.Nanoseconds()/int64(time.Millisecond)))
+	}
+
+	if len(conditions) == 0 {
+		return "*"
+	}
+	return "*[System[" + strings.Join(conditions, " and ") + "]]"
+}
+
+// executeEventLogQuery reads the records of the event log matching the query
+// of the check since its last execution, and sets the output and status of
+// the event. The status is the one of the most severe record. A failed query
+// is a critical event.
+func (a *Agent) executeEventLogQuery(ctx context.Context, event *corev2.Event) {
+	check := event.Check
+	query := check.EventLog
+	key := path.Join(check.Namespace, check.Name, query.Channel)
+
+	lookback := time.Duration(check.Interval) * time.Second
+	if lookback == 0 {
+		lookback = defaultEventLogLookback
+	}
+	bookmark := a.bookmarks.get(key)
+
+	start := time.Now()
+	records, err := eventLogReader(ctx, query.Channel, eventLogXPath(query, bookmark, lookback), query.RecordsLimit())
+	check.Executed = start.Unix()
+	check.Duration = time.Since(start).Seconds()
+	if err != nil {
+		check.Output = fmt.Sprintf("error querying the %s event log: %s", query.Channel, err)
+		check.Status = 2
+		return
+	}
+
+	var output strings.Builder
+	fmt.Fprintf(&output, "%d matching records in the %s event log", len(records), query.Channel)
+	if len(records) == query.RecordsLimit() {
+		output.WriteString(", the following ones will be read by the next execution")
+	}
+	output.WriteString("\n")
+
+	check.Status = 0
+	for _, record := range records {
+		output.WriteString(record.String() + "\n")
+		if status := record.status(); status > check.Status {
+			check.Status = status
+		}
+	}
+	check.Output = output.String()
+
+	if len(records) > 0 {
+		a.bookmarks.set(key, records[len(records)-1].System.EventRecordID)
+	}
+}
+
+// eventLogBookmarks keeps the ID of the last record read by every event log
+// check, in the cache directory, so the records are not read again once the
+// agent restarts.
+type eventLogBookmarks struct {
+	mu     sync.Mutex
+	path   string
+	loaded bool
+	ids    map[string]uint64
+}
+
+func newEventLogBookmarks(cacheDir string) *eventLogBookmarks {
+	b := &eventLogBookmarks{ids: make(map[string]uint64)}
+	if cacheDir != "" && cacheDir != os.DevNull {
+		b.path = filepath.Join(cacheDir, eventLogBookmarksFile)
+	}
+	return b
+}
+
+// get returns the ID of the last record read by the check with the given
+// key, or 0 if it did not read any yet.
+func (b *eventLogBookmarks) get(key string) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.load()
+	return b.ids[key]
+}
+
+// set bookmarks the ID of the last record read by the check with the given
+// key.
+func (b *eventLogBookmarks) set(key string, id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.load()
+	b.ids[key] = id
+	b.save()
+}
+
+// load loads the bookmarks from the cache directory, the first time only.
+func (b *eventLogBookmarks) load() {
+	if b.loaded || b.path == "" {
+		return
+	}
+	b.loaded = true
+	data, err := ioutil.ReadFile(b.path)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &b.ids)
+	}
+	if err != nil {
+		logger.WithError(err).Warning("could not load the event log bookmarks, reading the records again")
+	}
+}
+
+// save saves the bookmarks in the cache directory, replacing the previous
+// ones at once.
+func (b *eventLogBookmarks) save() {
+	if b.path == "" {
+		return
+	}
+	data, err := json.Marshal(b.ids)
+	if err != nil {
+		logger.WithError(err).Warning("could not save the event log bookmarks")
+		return
+	}
+	tmp := b.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		logger.WithError(err).Warning("could not save the event log bookmarks")
+		return
+	}
+	if err := os.Rename(tmp, b.path); err != nil {
+		logger.WithError(err).Warning("could not save the event log bookmarks")
+	}
+}
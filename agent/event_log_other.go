@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package agent
+
+import (
+	"context"
+	"errors"
+)
+
+// readEventLog returns an error, the event log is only available on Windows.
+func readEventLog(ctx context.Context, channel, query string, max int) ([]*eventLogRecord, error) {
+	return nil, errors.New("the event log can only be queried on windows")
+}
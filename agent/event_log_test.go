@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEventLogRecord = `<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'>
+  <System>
+    <Provider Name='Service Control Manager' Guid='{555908d1-a6d7-4695-8e1e-26931d2012f4}'/>
+    <EventID Qualifiers='49152'>7034</EventID>
+    <Level>2</Level>
+    <TimeCreated SystemTime='2019-05-14T10:03:12.123456700Z'/>
+    <EventRecordID>4242</EventRecordID>
+    <Channel>System</Channel>
+    <Computer>web-01</Computer>
+  </System>
+</Event>`
+
+func TestEventLogXPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    *corev2.EventLogQuery
+		bookmark uint64
+		lookback time.Duration
+		want     string
+	}{
+		{
+			name:  "all records",
+			query: corev2.FixtureEventLogQuery("System"),
+			want:  "*",
+		},
+		{
+			name:     "lookback",
+			query:    corev2.FixtureEventLogQuery("System"),
+			lookback: time.Minute,
+			want:     "*[System[TimeCreated[timediff(@SystemTime)<=60000]]]",
+		},
+		{
+			name: "bookmark and filters",
+			query: &corev2.EventLogQuery{
+				Channel:   "System",
+				Levels:    []string{corev2.EventLogLevelError, corev2.EventLogLevelInformation},
+				Providers: []string{"Service Control Manager", "EventLog"},
+				EventIDs:  []uint32{7034},
+			},
+			bookmark: 42,
+			lookback: time.Minute,
+			want:     "*[System[(Level=2 or Level=0 or Level=4) and Provider[@Name='Service Control Manager' or @Name='EventLog'] and (EventID=7034) and EventRecordID>42]]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, eventLogXPath(tt.query, tt.bookmark, tt.lookback))
+		})
+	}
+}
+
+func TestParseEventLogRecord(t *testing.T) {
+	record, err := parseEventLogRecord(testEventLogRecord)
+	require.NoError(t, err)
+	record.Message = "The Spooler service terminated\r\nunexpectedly."
+	assert.Equal(t, uint64(4242), record.System.EventRecordID)
+	assert.Equal(t, uint32(2), record.status())
+	assert.Equal(t, "2019-05-14T10:03:12.123456700Z error Service Control Manager 7034: The Spooler service terminated unexpectedly.", record.String())
+
+	_, err = parseEventLogRecord("<Event>")
+	assert.Error(t, err)
+}
+
+func TestExecuteEventLogQuery(t *testing.T) {
+	defer func(reader func(context.Context, string, string, int) ([]*eventLogRecord, error)) {
+		eventLogReader = reader
+	}(eventLogReader)
+
+	cacheDir, err := ioutil.TempDir("", "sensu-agent-event-log")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	var queries []string
+	var records []*eventLogRecord
+	var readErr error
+	eventLogReader = func(ctx context.Context, channel, query string, max int) ([]*eventLogRecord, error) {
+		assert.Equal(t, "System", channel)
+		assert.Equal(t, corev2.DefaultEventLogMaxRecords, max)
+		queries = append(queries, query)
+		return records, readErr
+	}
+
+	newEvent := func() *corev2.Event {
+		check := corev2.FixtureCheckConfig("event-log")
+		check.Command = ""
+		check.EventLog = corev2.FixtureEventLogQuery("System")
+		return &corev2.Event{Check: corev2.NewCheck(check)}
+	}
+	agent := &Agent{bookmarks: newEventLogBookmarks(cacheDir)}
+
+	// No record since the last interval
+	event := newEvent()
+	agent.executeEventLogQuery(context.Background(), event)
+	assert.Equal(t, uint32(0), event.Check.Status)
+	assert.Equal(t, "0 matching records in the System event log\n", event.Check.Output)
+
+	// The most severe record sets the status
+	warning, err := parseEventLogRecord(testEventLogRecord)
+	require.NoError(t, err)
+	warning.System.Level = 3
+	warning.System.EventRecordID = 4241
+	failure, err := parseEventLogRecord(testEventLogRecord)
+	require.NoError(t, err)
+	records = []*eventLogRecord{warning, failure}
+	event = newEvent()
+	agent.executeEventLogQuery(context.Background(), event)
+	assert.Equal(t, uint32(2), event.Check.Status)
+	assert.Contains(t, event.Check.Output, "2 matching records")
+
+	// The next executions read the records after the bookmark, even once the
+	// agent restarted
+	records = nil
+	agent = &Agent{bookmarks: newEventLogBookmarks(cacheDir)}
+	event = newEvent()
+	agent.executeEventLogQuery(context.Background(), event)
+	assert.Equal(t, uint32(0), event.Check.Status)
+
+	// A failed query is critical
+	readErr = errors.New("the event log can only be queried on windows")
+	event = newEvent()
+	agent.executeEventLogQuery(context.Background(), event)
+	assert.Equal(t, uint32(2), event.Check.Status)
+	assert.Equal(t, "error querying the System event log: the event log can only be queried on windows", event.Check.Output)
+
+	assert.Equal(t, []string{
+		"*[System[TimeCreated[timediff(@SystemTime)<=60000]]]",
+		"*[System[TimeCreated[timediff(@SystemTime)<=60000]]]",
+		"*[System[EventRecordID>4242]]",
+		"*[System[EventRecordID>4242]]",
+	}, queries)
+}
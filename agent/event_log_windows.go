@@ -0,0 +1,175 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// evtQueryChannelPath queries a channel of the event log.
+	evtQueryChannelPath = 0x1
+
+	// evtQueryForwardDirection returns the oldest records first.
+	evtQueryForwardDirection = 0x100
+
+	// evtRenderEventXML renders the records in XML.
+	evtRenderEventXML = 1
+
+	// evtFormatMessageEvent formats the message of the records.
+	evtFormatMessageEvent = 1
+
+	// evtNextTimeout is the time, in milliseconds, the records are awaited
+	// from the event log service.
+	evtNextTimeout = 10000
+
+	// evtNextBatchSize is the number of records read from the event log
+	// service at once.
+	evtNextBatchSize = 16
+
+	// errorNoMoreItems is returned once all the records were read.
+	errorNoMoreItems syscall.Errno = 259
+)
+
+var (
+	wevtapi = windows.NewLazySystemDLL("wevtapi.dll")
+
+	procEvtQuery                 = wevtapi.NewProc("EvtQuery")
+	procEvtNext                  = wevtapi.NewProc("EvtNext")
+	procEvtRender                = wevtapi.NewProc("EvtRender")
+	procEvtOpenPublisherMetadata = wevtapi.NewProc("EvtOpenPublisherMetadata")
+	procEvtFormatMessage         = wevtapi.NewProc("EvtFormatMessage")
+	procEvtClose                 = wevtapi.NewProc("EvtClose")
+)
+
+// evtHandle is a handle of the event log API.
+type evtHandle uintptr
+
+func evtClose(h evtHandle) {
+	if h != 0 {
+		_, _, _ = procEvtClose.Call(uintptr(h))
+	}
+}
+
+// readEventLog reads at most max records of the event log channel matching
+// the XPath query, the oldest first, with the Windows Event Log API.
+func readEventLog(ctx context.Context, channel, query string, max int) ([]*eventLogRecord, error) {
+	channelPtr, err := windows.UTF16PtrFromString(channel)
+	if err != nil {
+		return nil, err
+	}
+	queryPtr, err := windows.UTF16PtrFromString(query)
+	if err != nil {
+		return nil, err
+	}
+	r, _, err := procEvtQuery.Call(0, uintptr(unsafe.Pointer(channelPtr)), uintptr(unsafe.Pointer(queryPtr)), evtQueryChannelPath|evtQueryForwardDirection)
+	if r == 0 {
+		return nil, fmt.Errorf("could not query the event log: %s", err)
+	}
+	results := evtHandle(r)
+	defer evtClose(results)
+
+	// The publisher metadata format the messages of the records of their
+	// provider
+	publishers := map[string]evtHandle{}
+	defer func() {
+		for _, publisher := range publishers {
+			evtClose(publisher)
+		}
+	}()
+
+	var records []*eventLogRecord
+	events := make([]evtHandle, evtNextBatchSize)
+	for len(records) < max {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		size := evtNextBatchSize
+		if max-len(records) < size {
+			size = max - len(records)
+		}
+		var returned uint32
+		r, _, err := procEvtNext.Call(uintptr(results), uintptr(size), uintptr(unsafe.Pointer(&events[0])), evtNextTimeout, 0, uintptr(unsafe.Pointer(&returned)))
+		if r == 0 {
+			if err == errorNoMoreItems {
+				break
+			}
+			return nil, fmt.Errorf("could not read the event log: %s", err)
+		}
+
+		var renderErr error
+		for _, event := range events[:returned] {
+			if renderErr == nil {
+				var record *eventLogRecord
+				if record, renderErr = renderEventLogRecord(event, publishers); renderErr == nil {
+					records = append(records, record)
+				}
+			}
+			evtClose(event)
+		}
+		if renderErr != nil {
+			return nil, renderErr
+		}
+	}
+	return records, nil
+}
+
+// renderEventLogRecord renders the record in XML, and formats its message
+// with the publisher metadata of its provider.
+func renderEventLogRecord(event evtHandle, publishers map[string]evtHandle) (*eventLogRecord, error) {
+	var used, count uint32
+	_, _, _ = procEvtRender.Call(0, uintptr(event), evtRenderEventXML, 0, 0, uintptr(unsafe.Pointer(&used)), uintptr(unsafe.Pointer(&count)))
+	if used == 0 {
+		return nil, fmt.Errorf("could not render the event log record")
+	}
+	buf := make([]uint16, used/2+1)
+	r, _, err := procEvtRender.Call(0, uintptr(event), evtRenderEventXML, uintptr(len(buf)*2), uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&used)), uintptr(unsafe.Pointer(&count)))
+	if r == 0 {
+		return nil, fmt.Errorf("could not render the event log record: %s", err)
+	}
+	record, err := parseEventLogRecord(windows.UTF16ToString(buf))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := record.System.Provider.Name
+	publisher, ok := publishers[provider]
+	if !ok {
+		publisher = openPublisherMetadata(provider)
+		publishers[provider] = publisher
+	}
+	if publisher != 0 {
+		record.Message = formatEventLogMessage(publisher, event)
+	}
+	return record, nil
+}
+
+// openPublisherMetadata opens the publisher metadata of the provider, or
+// returns 0 if the provider is not installed.
+func openPublisherMetadata(provider string) evtHandle {
+	providerPtr, err := windows.UTF16PtrFromString(provider)
+	if err != nil {
+		return 0
+	}
+	r, _, _ := procEvtOpenPublisherMetadata.Call(0, uintptr(unsafe.Pointer(providerPtr)), 0, 0, 0)
+	return evtHandle(r)
+}
+
+// formatEventLogMessage returns the message of the record, or an empty
+// string if it can not be formatted.
+func formatEventLogMessage(publisher, event evtHandle) string {
+	var used uint32
+	_, _, _ = procEvtFormatMessage.Call(uintptr(publisher), uintptr(event), 0, 0, 0, evtFormatMessageEvent, 0, 0, uintptr(unsafe.Pointer(&used)))
+	if used == 0 {
+		return ""
+	}
+	buf := make([]uint16, used)
+	r, _, _ := procEvtFormatMessage.Call(uintptr(publisher), uintptr(event), 0, 0, 0, evtFormatMessageEvent, uintptr(len(buf)), uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&used)))
+	if r == 0 {
+		return ""
+	}
+	return windows.UTF16ToString(buf)
+}
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"time"
+)
+
+// DefaultBackendFailbackInterval is the default interval, in seconds, at
+// which an agent connected to a backend checks if a backend with a higher
+// weight is reachable again.
+const DefaultBackendFailbackInterval = 30
+
+// A backendTracker is a BackendSelector keeping track of the health of its
+// backends.
+type backendTracker interface {
+	BackendSelector
+
+	// Delay returns the time left before the backend is available again.
+	Delay(url string) time.Duration
+
+	// Success records a successful connection attempt to the backend.
+	Success(url string)
+
+	// Failure records a failed connection attempt to the backend.
+	Failure(url string)
+
+	// Preferred returns the available backends to fail back to from the
+	// backend, the most preferred first.
+	Preferred(url string) []string
+
+	// Health returns the health of the backends.
+	Health() []BackendHealth
+}
+
+// backendTracker returns the backend selector of the agent, if it keeps track
+// of the health of the backends.
+func (a *Agent) backendTracker() (backendTracker, bool) {
+	a.entityMu.Lock()
+	defer a.entityMu.Unlock()
+	tracker, ok := a.backendSelector.(backendTracker)
+	return tracker, ok
+}
+
+// failback checks, every failback interval, if a backend with a higher
+// weight than the backend the agent is connected to is reachable again, and
+// if so cancels the connection, so the agent reconnects to it.
+func (a *Agent) failback(ctx context.Context, cancel context.CancelFunc, backendURL string) {
+	interval := time.Duration(a.config.BackendFailbackInterval) * time.Second
+	timeout := time.Duration(a.config.BackendHandshakeTimeout) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		tracker, ok := a.backendTracker()
+		if !ok {
+			return
+		}
+		for _, preferred := range tracker.Preferred(backendURL) {
+			if err := probeBackend(ctx, preferred, timeout); err != nil {
+				logger.WithError(err).WithField("backend_url", preferred).Debug("preferred backend still unreachable")
+				continue
+			}
+			logger.WithField("backend_url", preferred).Info("failing back to a preferred backend")
+			cancel()
+			return
+		}
+	}
+}
+
+// probeBackend returns an error if a TCP connection can't be established with
+// the backend within the timeout.
+func probeBackend(ctx context.Context, backendURL string, timeout time.Duration) error {
+	u, err := url.Parse(backendURL)
+	if err != nil {
+		return err
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailback(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	preferred := "ws://" + listener.Addr().String()
+
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.BackendFailbackInterval = 1
+	config.BackendHandshakeTimeout = 1
+	agent := &Agent{
+		backendSelector: NewWeightedBackendSelector([]string{preferred, "ws://127.0.0.1:1"}, []int{10, 1}),
+		config:          config,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		agent.failback(ctx, cancel, "ws://127.0.0.1:1")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the agent did not fail back to the preferred backend")
+	}
+	assert.Error(t, ctx.Err())
+}
+
+func TestProbeBackend(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	assert.NoError(t, probeBackend(context.Background(), "wss://"+addr, time.Second))
+
+	require.NoError(t, listener.Close())
+	assert.Error(t, probeBackend(context.Background(), "wss://"+addr, time.Second))
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
@@ -18,9 +19,11 @@ import (
 )
 
 // ExecuteHooks executes all hooks contained in a check request based on
-// the check status code of the check request
+// the check status code of the check request. The hooks run one after the
+// other, except the parallel ones which run alongside the next hooks.
 func (a *Agent) ExecuteHooks(ctx context.Context, request *corev2.CheckRequest, event *corev2.Event, assets map[string]*corev2.AssetList) []*corev2.Hook {
-	executedHooks := []*corev2.Hook{}
+	hooks := []*corev2.Hook{}
+	origCommands := []string{}
 	for _, hookList := range request.Config.CheckHooks {
 		// find the hookList with the corresponding type
 		if hookShouldExecute(hookList.Type, event.Check.Status) {
@@ -36,18 +39,47 @@ func (a *Agent) ExecuteHooks(ctx context.Context, request *corev2.CheckRequest,
 				}
 				// Do not duplicate hook execution for types that fall into both an exit
 				// code and severity (ex. 0, ok)
-				in := hookInList(hookConfig.Name, executedHooks)
+				in := hookInList(hookConfig.Name, hooks)
 				if !in {
-					hook := a.executeHook(ctx, hookConfig, event, assets)
-					// To guard against publishing sensitive/redacted client attribute values
-					// the original command value is reinstated.
-					hook.Command = origCommand
-					executedHooks = append(executedHooks, hook)
+					hooks = append(hooks, &corev2.Hook{HookConfig: *hookConfig})
+					origCommands = append(origCommands, origCommand)
 				}
 			}
 		}
 	}
-	return executedHooks
+
+	executedHooks := make([]*corev2.Hook, len(hooks))
+	var wg sync.WaitGroup
+	for i := range hooks {
+		run := func(i int) {
+			hook := a.executeHook(ctx, &hooks[i].HookConfig, event, assets)
+			if hook != nil {
+				// To guard against publishing sensitive/redacted client attribute values
+				// the original command value is reinstated.
+				hook.Command = origCommands[i]
+			}
+			executedHooks[i] = hook
+		}
+		if hooks[i].Parallel {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				run(i)
+			}(i)
+			continue
+		}
+		run(i)
+	}
+	wg.Wait()
+
+	// The hooks which could not be executed are left out
+	results := executedHooks[:0]
+	for _, hook := range executedHooks {
+		if hook != nil {
+			results = append(results, hook)
+		}
+	}
+	return results
 }
 
 func (a *Agent) executeHook(ctx context.Context, hookConfig *corev2.HookConfig, event *corev2.Event, hookAssets map[string]*corev2.AssetList) *corev2.Hook {
@@ -64,6 +96,12 @@ func (a *Agent) executeHook(ctx context.Context, hookConfig *corev2.HookConfig,
 		"assets":    hook.RuntimeAssets,
 	}
 
+	// Match hook against deny list, which takes precedence over the allow list
+	if deniedEntry, denied := a.matchDenyList(hookConfig.Command); denied {
+		logger.WithFields(fields).WithField("rule", deniedEntry.String()).Warn("hook denied by agent deny list")
+		return failedHook(hook)
+	}
+
 	// Match check against allow list
 	var matchedEntry allowList
 	var match bool
@@ -141,6 +179,12 @@ func (a *Agent) executeHook(ctx context.Context, hookConfig *corev2.HookConfig,
 		hook.Output = hookExec.Output
 	}
 
+	// Truncate the hook output if it's larger than MaxOutputSize
+	if size := hookConfig.MaxOutputSize; size > 0 && int64(len(hook.Output)) > size {
+		hook.Output = hook.Output[:size]
+		hook.Truncated = true
+	}
+
 	hook.Duration = hookExec.Duration
 	hook.Status = int32(hookExec.Status)
 
@@ -2,7 +2,10 @@ package agent
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/sensu/sensu-go/command"
 	"github.com/sensu/sensu-go/testing/mockexecutor"
@@ -10,6 +13,7 @@ import (
 	"github.com/sensu/sensu-go/transport"
 	"github.com/sensu/sensu-go/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExecuteHook(t *testing.T) {
@@ -52,6 +56,82 @@ func TestExecuteHook(t *testing.T) {
 	assert.NotZero(hook.Executed)
 	assert.Equal(int32(0), hook.Status)
 	assert.Equal("hello", hook.Output)
+	assert.False(hook.Truncated)
+
+	hookConfig.MaxOutputSize = 3
+	hook = agent.executeHook(ctx, hookConfig, evt, nil)
+
+	assert.Equal("hel", hook.Output)
+	assert.True(hook.Truncated)
+}
+
+// barrierExecutor blocks the executions of the parallel commands until all of
+// them have started, so the commands time out if they run one after the other.
+type barrierExecutor struct {
+	parallel sync.WaitGroup
+	mu       sync.Mutex
+	commands []string
+}
+
+func (e *barrierExecutor) Execute(ctx context.Context, ex command.ExecutionRequest) (*command.ExecutionResponse, error) {
+	e.mu.Lock()
+	e.commands = append(e.commands, ex.Command)
+	e.mu.Unlock()
+
+	output := ex.Command
+	if strings.HasPrefix(ex.Command, "parallel") {
+		e.parallel.Done()
+		done := make(chan struct{})
+		go func() {
+			e.parallel.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			output = "timed out"
+		}
+	}
+	return command.FixtureExecutionResponse(0, output), nil
+}
+
+func TestExecuteHooks(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ex := &barrierExecutor{}
+	ex.parallel.Add(2)
+	agent.executor = ex
+
+	newHook := func(name string, parallel bool) types.HookConfig {
+		hook := types.FixtureHookConfig(name)
+		hook.Command = name
+		hook.Parallel = parallel
+		return *hook
+	}
+	request := types.FixtureCheckRequest("check")
+	request.Config.CheckHooks = []types.HookList{
+		{Type: "non-zero", Hooks: []string{"parallel1", "sequential", "parallel2"}},
+		{Type: "critical", Hooks: []string{"parallel1"}},
+	}
+	request.Hooks = []types.HookConfig{
+		newHook("parallel1", true),
+		newHook("sequential", false),
+		newHook("parallel2", true),
+	}
+	event := types.FixtureEvent("entity", "check")
+	event.Check.Status = 2
+
+	hooks := agent.ExecuteHooks(context.Background(), request, event, nil)
+	require.Len(t, hooks, 3)
+	for i, name := range []string{"parallel1", "sequential", "parallel2"} {
+		assert.Equal(t, name, hooks[i].Name)
+		assert.Equal(t, name, hooks[i].Output)
+	}
+	assert.Len(t, ex.commands, 3, "the hooks are executed once")
 }
 
 func TestPrepareHook(t *testing.T) {
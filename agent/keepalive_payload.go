@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+)
+
+const (
+	// KeepaliveNetworkInterfacesAll sends all the network interfaces of the
+	// entity in the keepalives.
+	KeepaliveNetworkInterfacesAll = "all"
+
+	// KeepaliveNetworkInterfacesAddressed only sends the network interfaces
+	// of the entity with a non-loopback address in the keepalives.
+	KeepaliveNetworkInterfacesAddressed = "addressed"
+
+	// KeepaliveNetworkInterfacesNone sends no network interface of the
+	// entity in the keepalives.
+	KeepaliveNetworkInterfacesNone = "none"
+)
+
+// KeepaliveNetworkInterfaces are the valid selections of the network
+// interfaces sent in the keepalives.
+var KeepaliveNetworkInterfaces = []string{
+	KeepaliveNetworkInterfacesAll,
+	KeepaliveNetworkInterfacesAddressed,
+	KeepaliveNetworkInterfacesNone,
+}
+
+// ValidateKeepaliveNetworkInterfaces returns an error if the selection of the
+// network interfaces sent in the keepalives is invalid. The empty selection
+// sends all of them.
+func ValidateKeepaliveNetworkInterfaces(selection string) error {
+	if selection != "" && !utilstrings.InArray(selection, KeepaliveNetworkInterfaces) {
+		return fmt.Errorf("invalid keepalive network interfaces %q, must be one of %v", selection, KeepaliveNetworkInterfaces)
+	}
+	return nil
+}
+
+// keepaliveInventory keeps track of the last keepalive sent with the system
+// inventory of the entity. It is safe for concurrent use.
+type keepaliveInventory struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+// due returns true if the inventory must be sent in the keepalive sent now,
+// and records it as sent if so.
+func (k *keepaliveInventory) due(now time.Time, interval time.Duration) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if interval > 0 && !k.last.IsZero() && now.Sub(k.last) < interval {
+		return false
+	}
+	k.last = now
+	return true
+}
+
+// reset makes the inventory sent in the next keepalive, e.g. once the agent
+// reconnected.
+func (k *keepaliveInventory) reset() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.last = time.Time{}
+}
+
+// keepaliveEntity returns the entity sent in the keepalive, with the network
+// interfaces selected by the agent configuration, and with its system
+// inventory only if it is due. It returns false if the inventory is omitted.
+func (a *Agent) keepaliveEntity(now time.Time) (*corev2.Entity, bool) {
+	entity := *a.getAgentEntity()
+	interval := time.Duration(a.config.KeepaliveInventoryInterval) * time.Second
+	if !a.inventory.due(now, interval) {
+		entity.System = corev2.System{}
+		return &entity, false
+	}
+	switch a.config.KeepaliveNetworkInterfaces {
+	case KeepaliveNetworkInterfacesNone:
+		entity.System.Network.Interfaces = nil
+	case KeepaliveNetworkInterfacesAddressed:
+		var interfaces []corev2.NetworkInterface
+		for _, iface := range entity.System.Network.Interfaces {
+			if hasNonLoopbackAddress(iface) {
+				interfaces = append(interfaces, iface)
+			}
+		}
+		entity.System.Network.Interfaces = interfaces
+	}
+	return &entity, true
+}
+
+// hasNonLoopbackAddress returns true if the network interface has an address
+// other than a loopback address.
+func hasNonLoopbackAddress(iface corev2.NetworkInterface) bool {
+	for _, address := range iface.Addresses {
+		ip, _, err := net.ParseCIDR(address)
+		if err != nil {
+			ip = net.ParseIP(address)
+		}
+		if ip != nil && !ip.IsLoopback() {
+			return true
+		}
+	}
+	return false
+}
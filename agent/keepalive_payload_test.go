@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateKeepaliveNetworkInterfaces(t *testing.T) {
+	for _, selection := range append(KeepaliveNetworkInterfaces, "") {
+		assert.NoError(t, ValidateKeepaliveNetworkInterfaces(selection))
+	}
+	assert.Error(t, ValidateKeepaliveNetworkInterfaces("some"))
+}
+
+func TestKeepaliveEntityNetworkInterfaces(t *testing.T) {
+	interfaces := []corev2.NetworkInterface{
+		{Name: "lo", Addresses: []string{"127.0.0.1/8", "::1/128"}},
+		{Name: "eth0", Addresses: []string{"10.0.0.2/24"}},
+		{Name: "eth1"},
+	}
+	tests := []struct {
+		name      string
+		selection string
+		want      []string
+	}{
+		{name: "default", want: []string{"lo", "eth0", "eth1"}},
+		{name: "all", selection: KeepaliveNetworkInterfacesAll, want: []string{"lo", "eth0", "eth1"}},
+		{name: "addressed", selection: KeepaliveNetworkInterfacesAddressed, want: []string{"eth0"}},
+		{name: "none", selection: KeepaliveNetworkInterfacesNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity := corev2.FixtureEntity("entity")
+			entity.System.Network.Interfaces = interfaces
+			agent := &Agent{
+				config: &Config{KeepaliveNetworkInterfaces: tt.selection},
+				entity: entity,
+			}
+
+			sent, inventory := agent.keepaliveEntity(time.Now())
+			assert.True(t, inventory)
+			var names []string
+			for _, iface := range sent.System.Network.Interfaces {
+				names = append(names, iface.Name)
+			}
+			assert.Equal(t, tt.want, names)
+
+			// The entity of the agent is left untouched
+			assert.Len(t, entity.System.Network.Interfaces, 3)
+		})
+	}
+}
+
+func TestKeepaliveEntityInventoryInterval(t *testing.T) {
+	entity := corev2.FixtureEntity("entity")
+	entity.System.Hostname = "web-1"
+	agent := &Agent{
+		config: &Config{KeepaliveInventoryInterval: 60},
+		entity: entity,
+	}
+	now := time.Now()
+
+	sent, inventory := agent.keepaliveEntity(now)
+	assert.True(t, inventory)
+	assert.Equal(t, "web-1", sent.System.Hostname)
+
+	// The inventory is omitted until the interval elapsed
+	sent, inventory = agent.keepaliveEntity(now.Add(30 * time.Second))
+	assert.False(t, inventory)
+	assert.Equal(t, corev2.System{}, sent.System)
+	assert.Equal(t, "entity", sent.Name)
+	assert.Equal(t, "web-1", entity.System.Hostname)
+
+	_, inventory = agent.keepaliveEntity(now.Add(time.Minute))
+	assert.True(t, inventory)
+
+	// It is sent again once the agent reconnected
+	agent.inventory.reset()
+	_, inventory = agent.keepaliveEntity(now.Add(90 * time.Second))
+	assert.True(t, inventory)
+
+	// It is always sent without an interval
+	agent.config.KeepaliveInventoryInterval = 0
+	_, inventory = agent.keepaliveEntity(now.Add(91 * time.Second))
+	assert.True(t, inventory)
+}
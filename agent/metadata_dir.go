@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ReadMetadataDir reads the labels or the annotations of the drop-in files of
+// a directory, e.g. /etc/sensu/labels.d, in the lexical order of their names,
+// the later files overriding the keys of the earlier ones. The files with a
+// .yml or .yaml extension hold a YAML map, the others key=value lines, the
+// empty lines and the lines starting with # being ignored. The hidden files,
+// the subdirectories and a directory that does not exist are ignored.
+func ReadMetadataDir(dir string) (map[string]string, error) {
+	metadata := map[string]string{}
+	if dir == "" {
+		return metadata, nil
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return metadata, nil
+		}
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var values map[string]string
+		switch filepath.Ext(path) {
+		case ".yml", ".yaml":
+			if err := yaml.Unmarshal(b, &values); err != nil {
+				return nil, fmt.Errorf("%s: %s", path, err)
+			}
+		default:
+			if values, err = parseKeyValues(b); err != nil {
+				return nil, fmt.Errorf("%s: %s", path, err)
+			}
+		}
+		for key, value := range values {
+			if key == "" {
+				return nil, fmt.Errorf("%s: empty key", path)
+			}
+			metadata[key] = value
+		}
+	}
+	return metadata, nil
+}
+
+// parseKeyValues parses key=value lines.
+func parseKeyValues(b []byte) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %d: %q is not a key=value pair", lineNumber, line)
+		}
+		values[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMetadataDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "labels.d")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"10-puppet":      "# managed by puppet\nrole = web\n\nteam=ops=platform\n",
+		"20-cmdb.yml":    "role: frontend\nrack: 42\n",
+		".hidden":        "role=hidden\n",
+		"sub/nested.yml": "role: nested\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	metadata, err := ReadMetadataDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"role": "frontend", "team": "ops=platform", "rack": "42"}, metadata)
+
+	// A directory that does not exist is ignored
+	metadata, err = ReadMetadataDir(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, metadata)
+
+	// The invalid files are rejected
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "30-invalid"), []byte("role\n"), 0644))
+	_, err = ReadMetadataDir(dir)
+	assert.Error(t, err)
+}
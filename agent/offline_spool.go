@@ -0,0 +1,362 @@
+package agent
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/agentd"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// offlineSpoolFile is the name of the database of the offline spool, in
+	// the cache directory of the agent.
+	offlineSpoolFile = "offline-spool.db"
+
+	// offlineSpoolOpenTimeout is the time the agent waits for the lock of the
+	// offline spool database, held by another agent using the same cache
+	// directory.
+	offlineSpoolOpenTimeout = 5 * time.Second
+)
+
+// offlineSpoolBucket is the bucket of the messages in the offline spool, keyed
+// by their sequence number.
+var offlineSpoolBucket = []byte("messages")
+
+// errSpooledMessageTooLarge is returned when a message is larger than the
+// offline spool itself.
+var errSpooledMessageTooLarge = errors.New("message larger than the offline spool")
+
+// OfflineSpoolConfig configures the spool persisting, on disk, the events and
+// keepalives of the agent while it is disconnected from the backends.
+type OfflineSpoolConfig struct {
+	// MaxSize is the maximum size, in bytes, of the spooled messages. The
+	// oldest messages are dropped to make room for the new ones. 0 disables
+	// the offline spool.
+	MaxSize int64
+
+	// MaxAge is the time, in seconds, after which a spooled message is
+	// dropped instead of being sent. 0 keeps the messages until they are sent.
+	MaxAge int
+}
+
+// spooledMessage is a message persisted in the offline spool, with the
+// serialization of its payload.
+type spooledMessage struct {
+	Type        string `json:"type"`
+	ContentType string `json:"content_type"`
+	Payload     []byte `json:"payload"`
+	SpooledAt   int64  `json:"spooled_at"`
+}
+
+// offlineSpool persists messages, in order, in a bolt database. Each record is
+// prefixed with its CRC-32 checksum, so that the corrupted records are skipped
+// instead of being sent.
+type offlineSpool struct {
+	db     *bolt.DB
+	config OfflineSpoolConfig
+	now    func() time.Time
+
+	mu   sync.Mutex
+	size int64
+}
+
+// newOfflineSpool opens the offline spool in the cache directory. A spool
+// database that can't be opened is considered corrupted: it is set aside and
+// replaced by an empty one.
+func newOfflineSpool(cacheDir string, config OfflineSpoolConfig) (*offlineSpool, error) {
+	if err := os.MkdirAll(cacheDir, 0744|os.ModeDir); err != nil {
+		return nil, fmt.Errorf("error creating offline spool: %s", err)
+	}
+	path := filepath.Join(cacheDir, offlineSpoolFile)
+	options := &bolt.Options{Timeout: offlineSpoolOpenTimeout}
+	db, err := bolt.Open(path, 0600, options)
+	if err == bolt.ErrTimeout {
+		return nil, fmt.Errorf("error opening offline spool: %s", err)
+	} else if err != nil {
+		corrupted := fmt.Sprintf("%s.corrupted-%d", path, time.Now().Unix())
+		logger.WithError(err).WithField("path", corrupted).Error("offline spool corrupted, setting it aside")
+		if err := os.Rename(path, corrupted); err != nil {
+			return nil, fmt.Errorf("error setting corrupted offline spool aside: %s", err)
+		}
+		if db, err = bolt.Open(path, 0600, options); err != nil {
+			return nil, fmt.Errorf("error creating offline spool: %s", err)
+		}
+	}
+
+	s := &offlineSpool{db: db, config: config, now: time.Now}
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(offlineSpoolBucket)
+		if err != nil {
+			return err
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			s.size += int64(len(v))
+			return nil
+		})
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("error reading offline spool: %s", err)
+	}
+	return s, nil
+}
+
+// Close closes the offline spool database.
+func (s *offlineSpool) Close() error {
+	return s.db.Close()
+}
+
+// Size returns the size, in bytes, of the spooled messages.
+func (s *offlineSpool) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// Push persists the message at the end of the spool, dropping the oldest
+// messages if the spool is full. It returns the number of dropped messages.
+func (s *offlineSpool) Push(msg *transport.Message, contentType string) (int, error) {
+	record, err := encodeSpooledMessage(&spooledMessage{
+		Type:        msg.Type,
+		ContentType: contentType,
+		Payload:     msg.Payload,
+		SpooledAt:   s.now().Unix(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if int64(len(record)) > s.config.MaxSize {
+		return 0, errSpooledMessageTooLarge
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dropped := 0
+	size := s.size
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(offlineSpoolBucket)
+		for size+int64(len(record)) > s.config.MaxSize {
+			k, v := bucket.Cursor().First()
+			if k == nil {
+				break
+			}
+			size -= int64(len(v))
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+			dropped++
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		size += int64(len(record))
+		return bucket.Put(key, record)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error spooling message: %s", err)
+	}
+	s.size = size
+	return dropped, nil
+}
+
+// Replay sends the spooled messages, in order, and removes them from the
+// spool once sent. The corrupted messages, and the messages older than the
+// maximum age, are dropped. It stops at the first message that could not be
+// sent, which is kept for the next replay, and returns the number of sent and
+// dropped messages.
+func (s *offlineSpool) Replay(send func(*spooledMessage) error) (sent, dropped int, err error) {
+	for {
+		key, record, err := s.first()
+		if err != nil || key == nil {
+			return sent, dropped, err
+		}
+
+		msg, err := decodeSpooledMessage(record)
+		if err != nil {
+			logger.WithError(err).Warn("dropping corrupted message from the offline spool")
+			dropped++
+		} else if s.expired(msg) {
+			dropped++
+		} else if err := send(msg); err != nil {
+			return sent, dropped, err
+		} else {
+			sent++
+		}
+
+		if err := s.remove(key); err != nil {
+			return sent, dropped, err
+		}
+	}
+}
+
+// expired returns true if the message is older than the maximum age.
+func (s *offlineSpool) expired(msg *spooledMessage) bool {
+	if s.config.MaxAge <= 0 {
+		return false
+	}
+	maxAge := time.Duration(s.config.MaxAge) * time.Second
+	return s.now().Sub(time.Unix(msg.SpooledAt, 0)) > maxAge
+}
+
+// first returns the key and record of the oldest message, or a nil key if the
+// spool is empty.
+func (s *offlineSpool) first() (key, record []byte, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		k, v := tx.Bucket(offlineSpoolBucket).Cursor().First()
+		if k != nil {
+			key = append([]byte{}, k...)
+			record = append([]byte{}, v...)
+		}
+		return nil
+	})
+	return key, record, err
+}
+
+// remove removes the message of the given key, unless it was already dropped
+// to make room for newer messages.
+func (s *offlineSpool) remove(key []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var size int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(offlineSpoolBucket)
+		size = len(bucket.Get(key))
+		return bucket.Delete(key)
+	})
+	if err != nil {
+		return fmt.Errorf("error removing message from the offline spool: %s", err)
+	}
+	s.size -= int64(size)
+	return nil
+}
+
+func encodeSpooledMessage(msg *spooledMessage) ([]byte, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding spooled message: %s", err)
+	}
+	record := make([]byte, 4, 4+len(body))
+	binary.BigEndian.PutUint32(record, crc32.ChecksumIEEE(body))
+	return append(record, body...), nil
+}
+
+func decodeSpooledMessage(record []byte) (*spooledMessage, error) {
+	if len(record) < 4 {
+		return nil, errors.New("truncated record")
+	}
+	body := record[4:]
+	if binary.BigEndian.Uint32(record) != crc32.ChecksumIEEE(body) {
+		return nil, errors.New("checksum mismatch")
+	}
+	msg := &spooledMessage{}
+	if err := json.Unmarshal(body, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// spoolOffline persists the message in the offline spool, to be sent once the
+// agent is connected again.
+func (a *Agent) spoolOffline(msg *transport.Message) {
+	dropped, err := a.offlineSpool.Push(msg, a.contentType)
+	if err != nil {
+		logger.WithError(err).WithField("type", msg.Type).Error("could not spool message, dropping it")
+		return
+	}
+	if dropped > 0 {
+		logger.WithField("messages", dropped).Warn("offline spool full, dropped the oldest messages")
+	}
+	logger.WithFields(logrus.Fields{
+		"type":       msg.Type,
+		"spool_size": a.offlineSpool.Size(),
+	}).Debug("agent disconnected, spooled message")
+}
+
+// spoolSendQueue moves the messages left in the send queue, which could not be
+// sent before the agent disconnected, to the offline spool, so that they are
+// sent before the messages spooled after them.
+func (a *Agent) spoolSendQueue() {
+	for {
+		select {
+		case msg := <-a.sendq:
+			a.spoolOffline(msg)
+		default:
+			return
+		}
+	}
+}
+
+// spoolKeepalives spools a keepalive at every keepalive interval while the
+// agent is disconnected, so that the backend learns when the agent was alive.
+func (a *Agent) spoolKeepalives(done <-chan struct{}) {
+	ticker := time.NewTicker(time.Duration(a.keepaliveInterval()) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if !a.Connected() {
+				a.spoolOffline(a.newKeepalive())
+			}
+		}
+	}
+}
+
+// replayOfflineSpool sends the messages spooled while the agent was
+// disconnected, in order, converting their payload to the serialization
+// negotiated with the backend if needed.
+func (a *Agent) replayOfflineSpool(conn transport.Transport, acks bool) error {
+	if a.offlineSpool.Size() == 0 {
+		return nil
+	}
+	sent, dropped, err := a.offlineSpool.Replay(func(spooled *spooledMessage) error {
+		msg := &transport.Message{Type: spooled.Type, Payload: spooled.Payload}
+		if spooled.ContentType != a.contentType && (msg.Type == transport.MessageTypeEvent || msg.Type == transport.MessageTypeKeepalive) {
+			payload, err := a.convertSpooledPayload(spooled)
+			if err != nil {
+				logger.WithError(err).Warn("dropping spooled message that could not be converted")
+				return nil
+			}
+			msg.Payload = payload
+		}
+		if acks && msg.Type == transport.MessageTypeEvent {
+			msg = a.eventAcks.track(msg, time.Now())
+		}
+		return conn.Send(msg)
+	})
+	logger.WithFields(logrus.Fields{
+		"sent":    sent,
+		"dropped": dropped,
+	}).Info("replayed the offline spool")
+	return err
+}
+
+// convertSpooledPayload converts the event payload of the spooled message to
+// the serialization negotiated with the backend.
+func (a *Agent) convertSpooledPayload(spooled *spooledMessage) ([]byte, error) {
+	unmarshal := agentd.UnmarshalJSON
+	if spooled.ContentType == agentd.ProtobufSerializationHeader {
+		unmarshal = proto.Unmarshal
+	}
+	event := &corev2.Event{}
+	if err := unmarshal(spooled.Payload, event); err != nil {
+		return nil, err
+	}
+	return a.marshal(event)
+}
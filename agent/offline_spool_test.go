@@ -0,0 +1,187 @@
+package agent
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/agentd"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func newTestOfflineSpool(t *testing.T, config OfflineSpoolConfig) (*offlineSpool, string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "offline-spool")
+	require.NoError(t, err)
+	spool, err := newOfflineSpool(dir, config)
+	require.NoError(t, err)
+	return spool, dir, func() {
+		_ = spool.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func replayPayloads(t *testing.T, spool *offlineSpool) []string {
+	t.Helper()
+	var payloads []string
+	_, _, err := spool.Replay(func(msg *spooledMessage) error {
+		payloads = append(payloads, string(msg.Payload))
+		return nil
+	})
+	require.NoError(t, err)
+	return payloads
+}
+
+func TestOfflineSpoolReplayInOrder(t *testing.T) {
+	spool, dir, cleanup := newTestOfflineSpool(t, OfflineSpoolConfig{MaxSize: 1 << 20})
+	defer cleanup()
+
+	for _, payload := range []string{"1", "2", "3"} {
+		dropped, err := spool.Push(&transport.Message{Type: transport.MessageTypeEvent, Payload: []byte(payload)}, "")
+		require.NoError(t, err)
+		assert.Equal(t, 0, dropped)
+	}
+	assert.NotZero(t, spool.Size())
+
+	// The messages survive a restart of the agent
+	require.NoError(t, spool.Close())
+	spool, err := newOfflineSpool(dir, OfflineSpoolConfig{MaxSize: 1 << 20})
+	require.NoError(t, err)
+	defer spool.Close()
+
+	// The replay stops at the first message that can't be sent
+	sendErr := errors.New("disconnected")
+	sent, _, err := spool.Replay(func(msg *spooledMessage) error {
+		if string(msg.Payload) == "2" {
+			return sendErr
+		}
+		return nil
+	})
+	assert.Equal(t, sendErr, err)
+	assert.Equal(t, 1, sent)
+
+	assert.Equal(t, []string{"2", "3"}, replayPayloads(t, spool))
+	assert.Equal(t, int64(0), spool.Size())
+}
+
+func TestOfflineSpoolMaxSize(t *testing.T) {
+	msg := &transport.Message{Type: transport.MessageTypeEvent, Payload: []byte("1")}
+	record, err := encodeSpooledMessage(&spooledMessage{Type: msg.Type, Payload: msg.Payload, SpooledAt: time.Now().Unix()})
+	require.NoError(t, err)
+
+	spool, _, cleanup := newTestOfflineSpool(t, OfflineSpoolConfig{MaxSize: int64(2 * len(record))})
+	defer cleanup()
+
+	for _, payload := range []string{"1", "2", "3"} {
+		_, err := spool.Push(&transport.Message{Type: transport.MessageTypeEvent, Payload: []byte(payload)}, "")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"2", "3"}, replayPayloads(t, spool))
+
+	_, err = spool.Push(&transport.Message{Type: transport.MessageTypeEvent, Payload: make([]byte, 4*len(record))}, "")
+	assert.Equal(t, errSpooledMessageTooLarge, err)
+}
+
+func TestOfflineSpoolMaxAge(t *testing.T) {
+	spool, _, cleanup := newTestOfflineSpool(t, OfflineSpoolConfig{MaxSize: 1 << 20, MaxAge: 60})
+	defer cleanup()
+
+	now := time.Now()
+	spool.now = func() time.Time { return now.Add(-2 * time.Minute) }
+	_, err := spool.Push(&transport.Message{Type: transport.MessageTypeEvent, Payload: []byte("old")}, "")
+	require.NoError(t, err)
+	spool.now = func() time.Time { return now }
+	_, err = spool.Push(&transport.Message{Type: transport.MessageTypeEvent, Payload: []byte("new")}, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"new"}, replayPayloads(t, spool))
+}
+
+func TestOfflineSpoolCorruptedRecord(t *testing.T) {
+	spool, _, cleanup := newTestOfflineSpool(t, OfflineSpoolConfig{MaxSize: 1 << 20})
+	defer cleanup()
+
+	for _, payload := range []string{"1", "2"} {
+		_, err := spool.Push(&transport.Message{Type: transport.MessageTypeEvent, Payload: []byte(payload)}, "")
+		require.NoError(t, err)
+	}
+	err := spool.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(offlineSpoolBucket)
+		k, v := bucket.Cursor().First()
+		corrupted := append([]byte{}, v...)
+		corrupted[len(corrupted)-2] ^= 0xff
+		return bucket.Put(k, corrupted)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2"}, replayPayloads(t, spool))
+}
+
+func TestOfflineSpoolCorruptedDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "offline-spool")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, offlineSpoolFile)
+	require.NoError(t, ioutil.WriteFile(path, []byte("not a bolt database, not a bolt database"), 0600))
+
+	spool, err := newOfflineSpool(dir, OfflineSpoolConfig{MaxSize: 1 << 20})
+	require.NoError(t, err)
+	defer spool.Close()
+
+	_, err = spool.Push(&transport.Message{Type: transport.MessageTypeEvent, Payload: []byte("1")}, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, replayPayloads(t, spool))
+
+	corrupted, err := filepath.Glob(path + ".corrupted-*")
+	require.NoError(t, err)
+	assert.Len(t, corrupted, 1)
+}
+
+type sendTransport struct {
+	transport.Transport
+	sent []*transport.Message
+}
+
+func (s *sendTransport) Send(msg *transport.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestReplayOfflineSpoolConvertsPayload(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.OfflineSpool = &OfflineSpoolConfig{MaxSize: 1 << 20}
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	defer agent.offlineSpool.Close()
+
+	// The event was spooled before the agent negotiated protobuf
+	event := &corev2.Event{
+		Entity:  corev2.FixtureEntity("entity"),
+		Metrics: &corev2.Metrics{Points: []*corev2.MetricPoint{{Name: "cpu", Value: 1}}},
+	}
+	payload, err := agentd.MarshalJSON(event)
+	require.NoError(t, err)
+	agent.spoolOffline(&transport.Message{Type: transport.MessageTypeEvent, Payload: payload})
+	agent.contentType = agentd.ProtobufSerializationHeader
+	agent.marshal = proto.Marshal
+
+	conn := &sendTransport{}
+	require.NoError(t, agent.replayOfflineSpool(conn, false))
+
+	require.Len(t, conn.sent, 1)
+	replayed := &corev2.Event{}
+	require.NoError(t, proto.Unmarshal(conn.sent[0].Payload, replayed))
+	assert.Equal(t, "entity", replayed.Entity.Name)
+	assert.Equal(t, "cpu", replayed.Metrics.Points[0].Name)
+	assert.Equal(t, int64(0), agent.offlineSpool.Size())
+}
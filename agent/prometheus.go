@@ -0,0 +1,341 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+const (
+	// defaultPrometheusScrapeTimeout is the timeout of the Prometheus scrapes
+	// of the checks without a timeout.
+	defaultPrometheusScrapeTimeout = 10 * time.Second
+
+	// maxPrometheusScrapeSize is the maximum size, in bytes, of the metrics
+	// of a Prometheus scrape.
+	maxPrometheusScrapeSize = 10 * 1024 * 1024
+
+	// prometheusAcceptHeader asks for the protobuf format, and falls back to
+	// the text format, like Prometheus.
+	prometheusAcceptHeader = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,text/plain;version=0.0.4;q=0.3`
+
+	// prometheusNameLabel is the label holding the name of the metric of a
+	// sample during relabeling.
+	prometheusNameLabel = "__name__"
+
+	// prometheusLabelSeparator joins the values of the source labels of a
+	// relabeling rule.
+	prometheusLabelSeparator = ";"
+)
+
+// prometheusTransport is the transport of the Prometheus scrapes, kept apart
+// from the default one so their connections are bounded.
+var prometheusTransport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	TLSHandshakeTimeout: 5 * time.Second,
+	MaxIdleConns:        10,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// newPrometheusClient returns the client of a Prometheus scrape, which only
+// follows the redirections to allowed URLs.
+func newPrometheusClient(timeout time.Duration, allowed func(string) bool) *http.Client {
+	return &http.Client{
+		Transport: prometheusTransport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if !allowed(req.URL.String()) {
+				return fmt.Errorf("redirect to %s denied by the agent allow list", req.URL)
+			}
+			return nil
+		},
+	}
+}
+
+// prometheusSample is a sample of a Prometheus scrape. Its labels include the
+// name of its metric.
+type prometheusSample struct {
+	labels    map[string]string
+	value     float64
+	timestamp int64
+}
+
+// prometheusRelabel is a compiled relabeling rule.
+type prometheusRelabel struct {
+	corev2.PrometheusRelabel
+	regex *regexp.Regexp
+}
+
+// executePrometheusScrape executes the Prometheus scrape of the check, and
+// sets the output, status and metrics of the event. A failed scrape is a
+// critical event. The scrape only follows the redirections to the allowed
+// URLs.
+func executePrometheusScrape(ctx context.Context, event *corev2.Event, allowed func(string) bool) {
+	check := event.Check
+	timeout := defaultPrometheusScrapeTimeout
+	if check.Timeout > 0 {
+		timeout = time.Duration(check.Timeout) * time.Second
+	}
+
+	start := time.Now()
+	client := newPrometheusClient(timeout, allowed)
+	points, err := scrapePrometheus(ctx, client, check.PrometheusScrape, start)
+	check.Duration = time.Since(start).Seconds()
+	if err != nil {
+		check.Output = fmt.Sprintf("error scraping %s: %s", check.PrometheusScrape.URL, err)
+		check.Status = 2
+		return
+	}
+
+	check.Output = fmt.Sprintf("scraped %d samples from %s\n", len(points), check.PrometheusScrape.URL)
+	check.Status = 0
+	event.Metrics = &corev2.Metrics{
+		Points:   points,
+		Handlers: check.OutputMetricHandlers,
+	}
+}
+
+// scrapePrometheus scrapes the metrics of the Prometheus endpoint with the
+// client, and returns the samples of the metrics allowed by the scrape, after
+// relabeling, as metric points. The samples without a timestamp get the time
+// of the scrape. The scrape fails if the metrics are larger than
+// maxPrometheusScrapeSize.
+func scrapePrometheus(ctx context.Context, client *http.Client, scrape *corev2.PrometheusScrape, now time.Time) ([]*corev2.MetricPoint, error) {
+	rules, err := compilePrometheusRelabel(scrape.Relabel)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, scrape.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", prometheusAcceptHeader)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	// The body is read up to one byte past the maximum size, so larger
+	// metrics are told apart from truncated ones
+	body := &io.LimitedReader{R: resp.Body, N: maxPrometheusScrapeSize + 1}
+	var families []*dto.MetricFamily
+	decoder := expfmt.NewDecoder(body, expfmt.ResponseFormat(resp.Header))
+	for {
+		family := &dto.MetricFamily{}
+		err := decoder.Decode(family)
+		if body.N <= 0 {
+			return nil, fmt.Errorf("prometheus metrics larger than %d bytes", maxPrometheusScrapeSize)
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid prometheus metrics: %s", err)
+		}
+		if allowedPrometheusMetric(scrape.Metrics, family.GetName()) {
+			families = append(families, family)
+		}
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].GetName() < families[j].GetName()
+	})
+
+	points := []*corev2.MetricPoint{}
+	for _, family := range families {
+		for _, sample := range prometheusSamples(family, now.Unix()) {
+			if relabelPrometheusSample(rules, sample.labels) {
+				points = append(points, sample.point())
+			}
+		}
+	}
+	return points, nil
+}
+
+// allowedPrometheusMetric returns true if the metric matches one of the
+// allowed metrics, or if all the metrics are allowed.
+func allowedPrometheusMetric(allowed []string, name string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, pattern := range allowed {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// prometheusSamples returns the samples of the metric family. The summaries
+// and histograms are split in several samples, like in the text format.
+func prometheusSamples(family *dto.MetricFamily, now int64) []prometheusSample {
+	name := family.GetName()
+	var samples []prometheusSample
+	for _, metric := range family.Metric {
+		timestamp := now
+		if metric.TimestampMs != nil {
+			timestamp = metric.GetTimestampMs() / 1000
+		}
+		add := func(name string, value float64, extra ...string) {
+			labels := map[string]string{prometheusNameLabel: name}
+			for _, pair := range metric.Label {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			for i := 0; i+1 < len(extra); i += 2 {
+				labels[extra[i]] = extra[i+1]
+			}
+			samples = append(samples, prometheusSample{labels: labels, value: value, timestamp: timestamp})
+		}
+
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			add(name, metric.GetCounter().GetValue())
+		case dto.MetricType_GAUGE:
+			add(name, metric.GetGauge().GetValue())
+		case dto.MetricType_SUMMARY:
+			summary := metric.GetSummary()
+			for _, q := range summary.Quantile {
+				add(name, q.GetValue(), "quantile", formatPrometheusFloat(q.GetQuantile()))
+			}
+			add(name+"_sum", summary.GetSampleSum())
+			add(name+"_count", float64(summary.GetSampleCount()))
+		case dto.MetricType_HISTOGRAM:
+			histogram := metric.GetHistogram()
+			infSeen := false
+			for _, b := range histogram.Bucket {
+				if math.IsInf(b.GetUpperBound(), +1) {
+					infSeen = true
+				}
+				add(name+"_bucket", float64(b.GetCumulativeCount()), "le", formatPrometheusFloat(b.GetUpperBound()))
+			}
+			if !infSeen {
+				add(name+"_bucket", float64(histogram.GetSampleCount()), "le", "+Inf")
+			}
+			add(name+"_sum", histogram.GetSampleSum())
+			add(name+"_count", float64(histogram.GetSampleCount()))
+		default:
+			add(name, metric.GetUntyped().GetValue())
+		}
+	}
+	return samples
+}
+
+// formatPrometheusFloat formats the quantiles and bucket bounds like the text
+// format.
+func formatPrometheusFloat(f float64) string {
+	switch {
+	case math.IsInf(f, +1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// point returns the metric point of the sample. The labels starting with two
+// underscores, reserved by Prometheus, are not kept as tags.
+func (s prometheusSample) point() *corev2.MetricPoint {
+	point := &corev2.MetricPoint{
+		Name:      s.labels[prometheusNameLabel],
+		Value:     s.value,
+		Timestamp: s.timestamp,
+		Tags:      []*corev2.MetricTag{},
+	}
+	for name, value := range s.labels {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		point.Tags = append(point.Tags, &corev2.MetricTag{Name: name, Value: value})
+	}
+	sort.Slice(point.Tags, func(i, j int) bool {
+		return point.Tags[i].Name < point.Tags[j].Name
+	})
+	return point
+}
+
+func compilePrometheusRelabel(rules []corev2.PrometheusRelabel) ([]prometheusRelabel, error) {
+	compiled := make([]prometheusRelabel, 0, len(rules))
+	for _, rule := range rules {
+		regex, err := rule.Compile()
+		if err != nil {
+			return nil, fmt.Errorf("invalid relabel regex: %s", err)
+		}
+		compiled = append(compiled, prometheusRelabel{PrometheusRelabel: rule, regex: regex})
+	}
+	return compiled, nil
+}
+
+// relabelPrometheusSample applies the relabeling rules to the labels of a
+// sample, and returns false if the sample is dropped. The name of the metric
+// is never removed by the labeldrop and labelkeep actions, but a sample whose
+// name is removed by a replace action is dropped.
+func relabelPrometheusSample(rules []prometheusRelabel, labels map[string]string) bool {
+	for _, rule := range rules {
+		values := make([]string, 0, len(rule.SourceLabels))
+		for _, name := range rule.SourceLabels {
+			values = append(values, labels[name])
+		}
+		value := strings.Join(values, prometheusLabelSeparator)
+
+		switch rule.ActionOrDefault() {
+		case corev2.PrometheusRelabelReplace:
+			match := rule.regex.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+			replaced := rule.regex.ExpandString(nil, rule.ReplacementOrDefault(), value, match)
+			if len(replaced) == 0 {
+				delete(labels, rule.TargetLabel)
+			} else {
+				labels[rule.TargetLabel] = string(replaced)
+			}
+		case corev2.PrometheusRelabelKeep:
+			if !rule.regex.MatchString(value) {
+				return false
+			}
+		case corev2.PrometheusRelabelDrop:
+			if rule.regex.MatchString(value) {
+				return false
+			}
+		case corev2.PrometheusRelabelLabelDrop:
+			for name := range labels {
+				if name != prometheusNameLabel && rule.regex.MatchString(name) {
+					delete(labels, name)
+				}
+			}
+		case corev2.PrometheusRelabelLabelKeep:
+			for name := range labels {
+				if name != prometheusNameLabel && !rule.regex.MatchString(name) {
+					delete(labels, name)
+				}
+			}
+		}
+	}
+	return labels[prometheusNameLabel] != ""
+}
@@ -0,0 +1,206 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const prometheusTestMetrics = `# TYPE http_requests_total counter
+http_requests_total{code="200",method="get"} 1027 1395066363000
+http_requests_total{code="400",method="post"} 3 1395066363000
+# TYPE go_goroutines gauge
+go_goroutines 42
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 4773
+rpc_duration_seconds_sum 1.7560473e+07
+rpc_duration_seconds_count 2693
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.1"} 3
+request_duration_seconds_bucket{le="1"} 5
+request_duration_seconds_bucket{le="+Inf"} 6
+request_duration_seconds_sum 2.5
+request_duration_seconds_count 6
+`
+
+func newPrometheusTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprint(w, prometheusTestMetrics)
+	}))
+}
+
+func pointNames(points []*corev2.MetricPoint) []string {
+	names := []string{}
+	for _, point := range points {
+		names = append(names, point.Name)
+	}
+	return names
+}
+
+func pointTags(point *corev2.MetricPoint) map[string]string {
+	tags := map[string]string{}
+	for _, tag := range point.Tags {
+		tags[tag.Name] = tag.Value
+	}
+	return tags
+}
+
+func TestScrapePrometheus(t *testing.T) {
+	server := newPrometheusTestServer(t)
+	defer server.Close()
+
+	now := time.Unix(1500000000, 0)
+	scrape := corev2.FixturePrometheusScrape(server.URL)
+	points, err := scrapePrometheus(context.Background(), testPrometheusClient(), scrape, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"go_goroutines",
+		"http_requests_total",
+		"http_requests_total",
+		"request_duration_seconds_bucket",
+		"request_duration_seconds_bucket",
+		"request_duration_seconds_bucket",
+		"request_duration_seconds_sum",
+		"request_duration_seconds_count",
+		"rpc_duration_seconds",
+		"rpc_duration_seconds_sum",
+		"rpc_duration_seconds_count",
+	}, pointNames(points))
+
+	assert.Equal(t, float64(42), points[0].Value)
+	assert.Equal(t, now.Unix(), points[0].Timestamp)
+	assert.Empty(t, points[0].Tags)
+
+	assert.Equal(t, int64(1395066363), points[1].Timestamp)
+	assert.Equal(t, map[string]string{"code": "200", "method": "get"}, pointTags(points[1]))
+
+	assert.Equal(t, map[string]string{"le": "0.1"}, pointTags(points[3]))
+	assert.Equal(t, map[string]string{"le": "+Inf"}, pointTags(points[5]))
+	assert.Equal(t, float64(6), points[5].Value)
+	assert.Equal(t, map[string]string{"quantile": "0.5"}, pointTags(points[8]))
+}
+
+func TestScrapePrometheusAllowedMetrics(t *testing.T) {
+	server := newPrometheusTestServer(t)
+	defer server.Close()
+
+	scrape := corev2.FixturePrometheusScrape(server.URL)
+	scrape.Metrics = []string{"go_*", "rpc_duration_seconds"}
+	points, err := scrapePrometheus(context.Background(), testPrometheusClient(), scrape, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"go_goroutines",
+		"rpc_duration_seconds",
+		"rpc_duration_seconds_sum",
+		"rpc_duration_seconds_count",
+	}, pointNames(points))
+}
+
+func TestScrapePrometheusRelabel(t *testing.T) {
+	server := newPrometheusTestServer(t)
+	defer server.Close()
+
+	scrape := corev2.FixturePrometheusScrape(server.URL)
+	scrape.Metrics = []string{"http_requests_total"}
+	scrape.Relabel = []corev2.PrometheusRelabel{
+		{
+			SourceLabels: []string{"code"},
+			Regex:        "4..",
+			Action:       corev2.PrometheusRelabelDrop,
+		},
+		{
+			SourceLabels: []string{"method", "code"},
+			Regex:        "(.*);(.*)",
+			TargetLabel:  "request",
+			Replacement:  "${1}_${2}",
+		},
+		{
+			SourceLabels: []string{prometheusNameLabel},
+			Regex:        "http_(.*)",
+			TargetLabel:  prometheusNameLabel,
+			Replacement:  "web_$1",
+		},
+		{
+			Regex:  "code",
+			Action: corev2.PrometheusRelabelLabelDrop,
+		},
+	}
+	points, err := scrapePrometheus(context.Background(), testPrometheusClient(), scrape, time.Now())
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+
+	assert.Equal(t, "web_requests_total", points[0].Name)
+	assert.Equal(t, map[string]string{"method": "get", "request": "get_200"}, pointTags(points[0]))
+}
+
+func TestScrapePrometheusErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	event := corev2.FixtureEvent("entity", "check")
+	event.Check.Command = ""
+	event.Check.PrometheusScrape = corev2.FixturePrometheusScrape(server.URL)
+	executePrometheusScrape(context.Background(), event, func(string) bool { return true })
+	assert.Equal(t, uint32(2), event.Check.Status)
+	assert.Contains(t, event.Check.Output, "503")
+	assert.Nil(t, event.Metrics)
+}
+
+func testPrometheusClient() *http.Client {
+	return newPrometheusClient(time.Second, func(string) bool { return true })
+}
+
+func TestScrapePrometheusTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The body is written in a few large chunks
+		line := []byte("go_goroutines 42\n")
+		body := bytes.Repeat(line, maxPrometheusScrapeSize/len(line)+1)
+		for len(body) > 0 {
+			n := 1 << 20
+			if n > len(body) {
+				n = len(body)
+			}
+			if _, err := w.Write(body[:n]); err != nil {
+				return
+			}
+			body = body[n:]
+		}
+	}))
+	defer server.Close()
+
+	// Decoding the metrics is slow under the race detector
+	client := newPrometheusClient(time.Minute, func(string) bool { return true })
+	scrape := corev2.FixturePrometheusScrape(server.URL)
+	_, err := scrapePrometheus(context.Background(), client, scrape, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "larger than")
+}
+
+func TestScrapePrometheusRedirectDenied(t *testing.T) {
+	target := newPrometheusTestServer(t)
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL+"/metrics", http.StatusFound))
+	defer server.Close()
+
+	allowed := func(url string) bool { return strings.HasPrefix(url, server.URL) }
+	client := newPrometheusClient(time.Second, allowed)
+	scrape := corev2.FixturePrometheusScrape(server.URL)
+	_, err := scrapePrometheus(context.Background(), client, scrape, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "denied by the agent allow list")
+}
@@ -9,16 +9,28 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/sensu/lasr"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	bolt "go.etcd.io/bbolt"
 )
 
+const (
+	// apiQueueName is the name of the lasr queue used to spool API events.
+	apiQueueName = "api-buffer"
+
+	// purgeReceiveTimeout is how long a purge waits for the next message
+	// before it considers the queue empty.
+	purgeReceiveTimeout = 100 * time.Millisecond
+)
+
 type queue interface {
 	Close() error
-	Compact() error
+	Purge() (int, error)
 	Receive(context.Context) (*lasr.Message, error)
 	Send([]byte) (lasr.ID, error)
+	Stats() (corev2.AgentSpoolStats, error)
 }
 
 func newMemoryQueue(size int) *memoryQueue {
@@ -33,12 +45,39 @@ type memoryQueue struct {
 	queue chan *lasr.Message
 }
 
+// diskQueue is a lasr queue that can report statistics about, and purge, the
+// messages it contains.
+type diskQueue struct {
+	*lasr.Q
+	db   *bolt.DB
+	name []byte
+}
+
+// lasr stores messages waiting to be received in the ready bucket, and
+// messages received but not acknowledged yet in the unacked bucket.
+var (
+	lasrReadyBucket   = []byte("ready")
+	lasrUnackedBucket = []byte("unacked")
+)
+
 func (m *memoryQueue) Close() error {
 	return nil
 }
 
-func (m *memoryQueue) Compact() error {
-	return nil
+func (m *memoryQueue) Purge() (int, error) {
+	purged := 0
+	for {
+		select {
+		case <-m.queue:
+			purged++
+		default:
+			return purged, nil
+		}
+	}
+}
+
+func (m *memoryQueue) Stats() (corev2.AgentSpoolStats, error) {
+	return corev2.AgentSpoolStats{Messages: len(m.queue)}, nil
 }
 
 func (m *memoryQueue) Receive(ctx context.Context) (*lasr.Message, error) {
@@ -71,17 +110,133 @@ func newQueue(path string) (queue, error) {
 		return nil, fmt.Errorf("error creating api queue: %s", err)
 	}
 	queuePath := filepath.Join(path, "queue.db")
+	logger.Info("compacting api queue")
+	if err := compactQueueDB(queuePath); err != nil {
+		return nil, fmt.Errorf("error compacting api queue: %s", err)
+	}
+	logger.Info("finished api queue compaction")
 	db, err := bolt.Open(queuePath, 0644, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating api queue: %s", err)
 	}
-	queue, err := lasr.NewQ(db, "api-buffer")
+	q, err := lasr.NewQ(db, apiQueueName)
 	if err != nil {
 		return nil, fmt.Errorf("error creating api queue: %s", err)
 	}
-	logger.Info("compacting api queue")
-	defer logger.Info("finished api queue compaction")
-	return queue, queue.Compact()
+	return &diskQueue{Q: q, db: db, name: []byte(apiQueueName)}, nil
+}
+
+// compactQueueDB copies the content of the queue database into a new file that
+// replaces the original one. It is used instead of lasr's own compaction,
+// which replaces the database handle shared with the diskQueue.
+func compactQueueDB(path string) (err error) {
+	src, err := bolt.Open(path, 0644, nil)
+	if err != nil {
+		return err
+	}
+	tempPath := filepath.Join(filepath.Dir(path), ".queue.temp.db")
+	dst, err := bolt.Open(tempPath, 0600, nil)
+	if err != nil {
+		_ = src.Close()
+		return err
+	}
+	err = src.View(func(stx *bolt.Tx) error {
+		return stx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			return dst.Update(func(dtx *bolt.Tx) error {
+				bucket, err := dtx.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(bucket, b)
+			})
+		})
+	})
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := src.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	return os.Rename(tempPath, path)
+}
+
+// copyBucket recursively copies the keys, nested buckets and sequence of src
+// into dst.
+func copyBucket(dst, src *bolt.Bucket) error {
+	if err := dst.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(k, v)
+		}
+		nested, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+		return copyBucket(nested, src.Bucket(k))
+	})
+}
+
+// Close closes the queue and its database.
+func (q *diskQueue) Close() error {
+	if err := q.Q.Close(); err != nil {
+		return err
+	}
+	return q.db.Close()
+}
+
+// Purge discards the messages waiting to be received, by receiving and
+// acknowledging them until none is left, and returns how many were discarded.
+// Messages that were already received are not affected.
+func (q *diskQueue) Purge() (int, error) {
+	purged := 0
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), purgeReceiveTimeout)
+		message, err := q.Receive(ctx)
+		cancel()
+		if err == context.DeadlineExceeded {
+			return purged, nil
+		} else if err != nil {
+			return purged, err
+		}
+		if err := message.Ack(); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+}
+
+// Stats returns the number and size of the messages in the queue, including
+// the messages that were received but not acknowledged yet.
+func (q *diskQueue) Stats() (corev2.AgentSpoolStats, error) {
+	var stats corev2.AgentSpoolStats
+	err := q.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(q.name)
+		if root == nil {
+			return nil
+		}
+		for _, key := range [][]byte{lasrReadyBucket, lasrUnackedBucket} {
+			bucket := root.Bucket(key)
+			if bucket == nil {
+				continue
+			}
+			err := bucket.ForEach(func(k, v []byte) error {
+				stats.Messages++
+				stats.Bytes += len(v)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return stats, err
 }
 
 func compressMessage(message []byte) []byte {
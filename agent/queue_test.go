@@ -2,8 +2,12 @@ package agent
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"io/ioutil"
+	"os"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 )
@@ -24,6 +28,105 @@ func TestCompressionRoundTrip(t *testing.T) {
 	}
 }
 
+func TestDiskQueueStatsAndPurge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sensu-agent-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, err := newQueue(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := q.Send([]byte("message")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := q.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stats.Messages, 3; got != want {
+		t.Fatalf("bad message count: got %d, want %d", got, want)
+	}
+	if got, want := stats.Bytes, 3*len("message"); got != want {
+		t.Fatalf("bad size: got %d, want %d", got, want)
+	}
+
+	purged, err := q.Purge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := purged, 3; got != want {
+		t.Fatalf("bad purge count: got %d, want %d", got, want)
+	}
+
+	stats, err = q.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stats.Messages, 0; got != want {
+		t.Fatalf("bad message count after purge: got %d, want %d", got, want)
+	}
+
+	// The queue must remain usable after a purge
+	if _, err := q.Send([]byte("after purge")); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	message, err := q.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(message.Body), "after purge"; got != want {
+		t.Fatalf("bad message after purge: got %q, want %q", got, want)
+	}
+	if err := message.Ack(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiskQueueCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sensu-agent-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, err := newQueue(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := q.Send([]byte("message")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening the queue compacts it, and must preserve its messages
+	q, err = newQueue(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	stats, err := q.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stats.Messages, 2; got != want {
+		t.Fatalf("bad message count: got %d, want %d", got, want)
+	}
+}
+
 func BenchmarkCompressEventRoundTrip(b *testing.B) {
 	event := corev2.FixtureEvent("foo", "bar")
 	msg, _ := json.Marshal(event)
@@ -0,0 +1,184 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
+)
+
+const (
+	// DefaultBackendReconnectThreshold is the default number of consecutive
+	// failed connection attempts after which the reconnect circuit opens.
+	DefaultBackendReconnectThreshold = 5
+
+	// reconnectCheckName is the name of the check of the events reporting
+	// the connectivity problems of the agent.
+	reconnectCheckName = "agent-reconnect"
+)
+
+// ReconnectStats are the statistics of the connections of the agent to the
+// backends. The reconnect circuit opens when the number of consecutive failed
+// connection attempts reaches the reconnect threshold, and closes once the
+// agent is connected again.
+type ReconnectStats struct {
+	// Connected is true if the agent is connected to a backend.
+	Connected bool `json:"connected"`
+
+	// CircuitOpen is true if the reconnect threshold was reached since the
+	// agent lost its connection.
+	CircuitOpen bool `json:"circuit_open"`
+
+	// ConsecutiveFailures is the number of failed connection attempts since
+	// the agent lost its connection.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// TotalFailures is the number of failed connection attempts since the
+	// agent started.
+	TotalFailures int64 `json:"total_failures"`
+
+	// Disconnections is the number of times the agent lost its connection.
+	Disconnections int64 `json:"disconnections"`
+
+	// LastError is the error of the last failed connection attempt.
+	LastError string `json:"last_error,omitempty"`
+
+	// LastFailure is the time of the last failed connection attempt.
+	LastFailure int64 `json:"last_failure,omitempty"`
+
+	// FirstFailure is the time of the first failed connection attempt since
+	// the agent lost its connection.
+	FirstFailure int64 `json:"first_failure,omitempty"`
+
+	// LastConnected is the time the agent last connected to a backend.
+	LastConnected int64 `json:"last_connected,omitempty"`
+
+	// Backends is the health of the backends.
+	Backends []BackendHealth `json:"backends,omitempty"`
+}
+
+// reconnectTracker keeps track of the connection attempts of the agent.
+type reconnectTracker struct {
+	threshold int
+	mu        sync.Mutex
+	stats     ReconnectStats
+}
+
+// failure records a failed connection attempt.
+func (t *reconnectTracker) failure(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now().Unix()
+	if t.stats.ConsecutiveFailures == 0 {
+		t.stats.FirstFailure = now
+	}
+	t.stats.ConsecutiveFailures++
+	t.stats.TotalFailures++
+	t.stats.LastError = err.Error()
+	t.stats.LastFailure = now
+	if t.threshold > 0 && t.stats.ConsecutiveFailures == t.threshold {
+		t.stats.CircuitOpen = true
+		logger.WithField("failures", t.stats.ConsecutiveFailures).Warn("reconnect circuit opened, the backends are unreachable")
+	}
+}
+
+// connected records a successful connection. It returns the statistics of the
+// outage that opened the reconnect circuit, if any.
+func (t *reconnectTracker) connected() *ReconnectStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var outage *ReconnectStats
+	if t.stats.CircuitOpen {
+		stats := t.stats
+		outage = &stats
+		logger.WithField("failures", t.stats.ConsecutiveFailures).Info("reconnect circuit closed")
+	}
+	t.stats.Connected = true
+	t.stats.CircuitOpen = false
+	t.stats.ConsecutiveFailures = 0
+	t.stats.FirstFailure = 0
+	t.stats.LastConnected = time.Now().Unix()
+	return outage
+}
+
+// disconnected records the loss of the connection.
+func (t *reconnectTracker) disconnected() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stats.Connected {
+		t.stats.Connected = false
+		t.stats.Disconnections++
+	}
+}
+
+// Stats returns the connection statistics.
+func (t *reconnectTracker) Stats() ReconnectStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
+
+// reportOutage sends an event reporting the outage that opened the reconnect
+// circuit, then resolves it once the connection has been up for a keepalive
+// interval.
+func (a *Agent) reportOutage(ctx context.Context, outage ReconnectStats) {
+	output := fmt.Sprintf(
+		"agent failed to connect to the backends %d times between %s and %s, last error: %s",
+		outage.ConsecutiveFailures,
+		time.Unix(outage.FirstFailure, 0).UTC().Format(time.RFC3339),
+		time.Unix(outage.LastFailure, 0).UTC().Format(time.RFC3339),
+		outage.LastError,
+	)
+	if !a.sendReconnectEvent(1, output) {
+		return
+	}
+
+	timer := time.NewTimer(time.Duration(a.keepaliveInterval()) * time.Second)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+		a.sendReconnectEvent(0, "agent connection to the backend is stable")
+	}
+}
+
+// sendReconnectEvent sends an event about the connectivity of the agent.
+func (a *Agent) sendReconnectEvent(status uint32, output string) bool {
+	entity := a.getAgentEntity()
+	event := &corev2.Event{
+		ObjectMeta: corev2.NewObjectMeta("", entity.Namespace),
+		Entity:     entity,
+		Check: &corev2.Check{
+			ObjectMeta: corev2.NewObjectMeta(reconnectCheckName, entity.Namespace),
+			Interval:   a.keepaliveInterval(),
+			Status:     status,
+			Output:     output,
+			Executed:   time.Now().Unix(),
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	msg, err := a.marshal(event)
+	if err != nil {
+		logger.WithError(err).Error("error marshaling reconnect event")
+		return false
+	}
+	a.sendMessage(&transport.Message{Type: transport.MessageTypeEvent, Payload: msg})
+	return true
+}
+
+// reconnectHandler serves the connection statistics of the agent.
+func reconnectHandler(a *Agent) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats := a.reconnects.Stats()
+		if tracker, ok := a.backendTracker(); ok {
+			stats.Backends = tracker.Health()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(stats)
+	}
+}
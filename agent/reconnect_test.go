@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconnectTracker(t *testing.T) {
+	tracker := &reconnectTracker{threshold: 2}
+
+	tracker.failure(errors.New("connection refused"))
+	stats := tracker.Stats()
+	assert.False(t, stats.CircuitOpen)
+	assert.Equal(t, 1, stats.ConsecutiveFailures)
+	assert.Equal(t, "connection refused", stats.LastError)
+	assert.NotZero(t, stats.FirstFailure)
+
+	// The circuit is closed, so no outage is reported
+	assert.Nil(t, tracker.connected())
+	tracker.disconnected()
+
+	tracker.failure(errors.New("connection refused"))
+	tracker.failure(errors.New("i/o timeout"))
+	stats = tracker.Stats()
+	assert.True(t, stats.CircuitOpen)
+	assert.Equal(t, 2, stats.ConsecutiveFailures)
+	assert.Equal(t, int64(3), stats.TotalFailures)
+	assert.Equal(t, int64(1), stats.Disconnections)
+
+	outage := tracker.connected()
+	require.NotNil(t, outage)
+	assert.Equal(t, 2, outage.ConsecutiveFailures)
+	assert.Equal(t, "i/o timeout", outage.LastError)
+
+	stats = tracker.Stats()
+	assert.True(t, stats.Connected)
+	assert.False(t, stats.CircuitOpen)
+	assert.Zero(t, stats.ConsecutiveFailures)
+	assert.Equal(t, int64(3), stats.TotalFailures)
+}
+
+func TestReconnectTrackerDisabled(t *testing.T) {
+	tracker := &reconnectTracker{}
+	for i := 0; i < 10; i++ {
+		tracker.failure(errors.New("connection refused"))
+	}
+	assert.False(t, tracker.Stats().CircuitOpen)
+	assert.Nil(t, tracker.connected())
+}
+
+func TestReconnectHandler(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	agent.reconnects.failure(errors.New("connection refused"))
+
+	r, err := http.NewRequest("GET", "/reconnects", nil)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	registerRoutes(agent, router)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var stats ReconnectStats
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&stats))
+	assert.Equal(t, 1, stats.ConsecutiveFailures)
+	assert.Equal(t, "connection refused", stats.LastError)
+}
@@ -0,0 +1,28 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+)
+
+// handleMessageRejection is the message rejection message handler. The
+// rejected events waiting for acknowledgement are not sent again.
+func (a *Agent) handleMessageRejection(ctx context.Context, payload []byte) error {
+	var rejection transport.MessageRejection
+	if err := json.Unmarshal(payload, &rejection); err != nil {
+		return err
+	}
+	logger.WithFields(logrus.Fields{
+		"type":     rejection.Type,
+		"size":     rejection.Size,
+		"max_size": rejection.MaxSize,
+		"error":    rejection.Error,
+	}).Error("backend rejected message")
+	if rejection.EventID != "" && a.eventAcks != nil {
+		a.eventAcks.reject(rejection.EventID)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMessageRejection(t *testing.T) {
+	a := &Agent{eventAcks: newEventAcks(time.Second)}
+	var callbackErrs []error
+	msg := transport.NewMessage(transport.MessageTypeEvent, []byte("event"))
+	msg.SendCallback = func(err error) {
+		callbackErrs = append(callbackErrs, err)
+	}
+	id := ackedEventID(t, a.eventAcks.track(msg, time.Now()), "event")
+
+	// The rejected event is not sent again
+	require.NoError(t, a.handleMessageRejection(context.Background(), []byte(`{"type": "acked_event", "event_id": "`+id+`"}`)))
+	assert.Empty(t, a.eventAcks.due(time.Now(), true))
+	assert.Equal(t, []error{nil}, callbackErrs)
+
+	// The rejections of the other messages are only logged
+	require.NoError(t, a.handleMessageRejection(context.Background(), []byte(`{"type": "keepalive"}`)))
+	require.NoError(t, (&Agent{}).handleMessageRejection(context.Background(), []byte(`{"type": "acked_event", "event_id": "1"}`)))
+	assert.Error(t, a.handleMessageRejection(context.Background(), []byte("{")))
+}
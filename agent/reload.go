@@ -0,0 +1,212 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ConfigReloader returns the configuration of the agent, read again from its
+// configuration file and flags.
+type ConfigReloader func() (*Config, error)
+
+// errNoConfigReloader is returned when the agent is asked to reload its
+// configuration without a way to read it again.
+var errNoConfigReloader = errors.New("the agent configuration can't be reloaded")
+
+// ReloadResult describes the changes applied by a configuration reload.
+type ReloadResult struct {
+	// Changed are the settings changed by the reload.
+	Changed []string `json:"changed"`
+
+	// Reconnected is true if the agent reconnects to the backend, because
+	// settings sent in the handshake changed.
+	Reconnected bool `json:"reconnected"`
+
+	// RestartRequired are the settings that changed but only apply once the
+	// agent restarts.
+	RestartRequired []string `json:"restart_required,omitempty"`
+}
+
+// SetConfigReloader sets the function used to read the configuration of the
+// agent again when it is asked to reload it.
+func (a *Agent) SetConfigReloader(reloader ConfigReloader) {
+	a.entityMu.Lock()
+	defer a.entityMu.Unlock()
+	a.configReloader = reloader
+}
+
+// ReloadConfig reads the configuration of the agent again, and applies it.
+func (a *Agent) ReloadConfig() (*ReloadResult, error) {
+	a.entityMu.Lock()
+	reloader := a.configReloader
+	a.entityMu.Unlock()
+	if reloader == nil {
+		return nil, errNoConfigReloader
+	}
+	config, err := reloader()
+	if err != nil {
+		return nil, fmt.Errorf("error reading the agent configuration: %s", err)
+	}
+	return a.Reload(config)
+}
+
+// Reload applies the settings of the configuration that can change while the
+// agent runs: its subscriptions, labels, annotations, log level, backend URLs
+// and backend weights. The agent reconnects to the backend, without resuming
+// its session, only if its subscriptions, backend URLs or backend weights
+// changed, since they are part of the handshake or select the backend. The
+// other settings are left unchanged until the agent restarts.
+func (a *Agent) Reload(config *Config) (*ReloadResult, error) {
+	var level logrus.Level
+	if config.LogLevel != "" {
+		var err error
+		if level, err = logrus.ParseLevel(config.LogLevel); err != nil {
+			return nil, err
+		}
+	}
+	if len(config.BackendURLs) == 0 {
+		return nil, errors.New("at least one backend URL is required")
+	}
+
+	result := &ReloadResult{Changed: []string{}}
+	changed := func(name string, old, new interface{}) bool {
+		if reflect.DeepEqual(old, new) {
+			return false
+		}
+		result.Changed = append(result.Changed, name)
+		return true
+	}
+
+	a.entityMu.Lock()
+	if config.LogLevel == "" {
+		level = a.localLogLevel
+	}
+	if changed("subscriptions", a.config.Subscriptions, config.Subscriptions) {
+		a.config.Subscriptions = config.Subscriptions
+		result.Reconnected = true
+	}
+	if changed("labels", a.config.Labels, config.Labels) {
+		a.config.Labels = config.Labels
+	}
+	if changed("annotations", a.config.Annotations, config.Annotations) {
+		a.config.Annotations = config.Annotations
+	}
+	urlsChanged := changed("backend-url", a.config.BackendURLs, config.BackendURLs)
+	weightsChanged := changed("backend-weight", a.config.BackendWeights, config.BackendWeights)
+	if urlsChanged || weightsChanged {
+		a.config.BackendURLs = config.BackendURLs
+		a.config.BackendWeights = config.BackendWeights
+		a.backendSelector = NewWeightedBackendSelector(config.BackendURLs, config.BackendWeights)
+		result.Reconnected = true
+	}
+	if changed("log-level", a.localLogLevel, level) {
+		a.localLogLevel = level
+		// The log level managed by the backend takes precedence
+		if a.managedConfig == nil || a.managedConfig.LogLevel == "" {
+			logrus.SetLevel(level)
+		}
+	}
+	// The entity is rebuilt with the new subscriptions, labels and annotations
+	a.entity = nil
+
+	restartRequired := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"name", a.config.AgentName, config.AgentName},
+		{"namespace", a.config.Namespace, config.Namespace},
+		{"heartbeat-only", a.config.HeartbeatOnly, config.HeartbeatOnly},
+		{"user", a.config.User, config.User},
+		{"password", a.config.Password, config.Password},
+		{"cache-dir", a.config.CacheDir, config.CacheDir},
+		{"keepalive-interval", a.config.KeepaliveInterval, config.KeepaliveInterval},
+		{"keepalive-timeout", a.config.KeepaliveTimeout, config.KeepaliveTimeout},
+		{"keepalive-network-interfaces", a.config.KeepaliveNetworkInterfaces, config.KeepaliveNetworkInterfaces},
+		{"keepalive-inventory-interval", a.config.KeepaliveInventoryInterval, config.KeepaliveInventoryInterval},
+		{"tls", a.config.TLS, config.TLS},
+		{"tls-reload-interval", a.config.TLSReloadInterval, config.TLSReloadInterval},
+		{"enrollment-token", a.config.EnrollmentToken, config.EnrollmentToken},
+		{"cloud-metadata", a.config.CloudMetadata, config.CloudMetadata},
+		{"deny-list", a.config.DenyList, config.DenyList},
+		{"assets-cache-max-size", a.config.AssetsCacheMaxSize, config.AssetsCacheMaxSize},
+		{"assets-cache-retention", a.config.AssetsCacheRetention, config.AssetsCacheRetention},
+		{"max-concurrent-checks", a.config.MaxConcurrentChecks, config.MaxConcurrentChecks},
+		{"container-runtime", a.config.ContainerRuntime, config.ContainerRuntime},
+		{"containers", a.config.Containers, config.Containers},
+		{"api-check-execution", a.config.APICheckExecution, config.APICheckExecution},
+		{"backend-failback-interval", a.config.BackendFailbackInterval, config.BackendFailbackInterval},
+		{"socket", a.config.Socket, config.Socket},
+		{"secrets", a.config.Secrets, config.Secrets},
+		{"events", a.config.EventFilter, config.EventFilter},
+	}
+	for _, setting := range restartRequired {
+		if !reflect.DeepEqual(setting.old, setting.new) {
+			result.RestartRequired = append(result.RestartRequired, setting.name)
+		}
+	}
+	a.entityMu.Unlock()
+
+	fields := logrus.Fields{
+		"changed":     strings.Join(result.Changed, ","),
+		"reconnected": result.Reconnected,
+	}
+	if len(result.RestartRequired) > 0 {
+		fields["restart_required"] = strings.Join(result.RestartRequired, ",")
+		logger.WithFields(fields).Warn("reloaded the agent configuration, some settings require a restart")
+	} else {
+		logger.WithFields(fields).Info("reloaded the agent configuration")
+	}
+
+	if result.Reconnected {
+		select {
+		case a.reconnect <- struct{}{}:
+		default:
+		}
+	} else if len(result.Changed) > 0 {
+		// Send a keepalive at once, so the backend knows of the new labels
+		// and annotations
+		select {
+		case a.keepaliveReset <- struct{}{}:
+		default:
+		}
+	}
+	return result, nil
+}
+
+// subscriptions returns the subscriptions of the agent.
+func (a *Agent) subscriptions() []string {
+	a.entityMu.Lock()
+	defer a.entityMu.Unlock()
+	return a.config.Subscriptions
+}
+
+// selectBackend returns the URL of the next backend to connect to.
+func (a *Agent) selectBackend() string {
+	a.entityMu.Lock()
+	selector := a.backendSelector
+	a.entityMu.Unlock()
+	return selector.Select()
+}
+
+// reloadHandler reloads the configuration of the agent and responds with the
+// applied changes.
+func reloadHandler(a *Agent) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := a.ReloadConfig()
+		if err == errNoConfigReloader {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	}
+}
@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReloadAgent(t *testing.T) (*Agent, func()) {
+	t.Helper()
+	config, cleanup := FixtureConfig()
+	config.BackendURLs = []string{"ws://127.0.0.1:8081"}
+	config.Subscriptions = []string{"linux"}
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	level := logrus.GetLevel()
+	return agent, func() {
+		logrus.SetLevel(level)
+		cleanup()
+	}
+}
+
+func TestReloadLabels(t *testing.T) {
+	agent, cleanup := newTestReloadAgent(t)
+	defer cleanup()
+
+	reloaded := *agent.config
+	reloaded.Labels = map[string]string{"region": "us-west-2"}
+	result, err := agent.Reload(&reloaded)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"labels"}, result.Changed)
+	assert.False(t, result.Reconnected)
+	assert.Empty(t, result.RestartRequired)
+	assert.Equal(t, "us-west-2", agent.getAgentEntity().Labels["region"])
+	assert.Len(t, agent.keepaliveReset, 1)
+	assert.Len(t, agent.reconnect, 0)
+}
+
+func TestReloadReconnects(t *testing.T) {
+	tests := []struct {
+		name    string
+		reload  func(*Config)
+		changed string
+	}{
+		{
+			name:    "subscriptions",
+			reload:  func(c *Config) { c.Subscriptions = []string{"linux", "web"} },
+			changed: "subscriptions",
+		},
+		{
+			name:    "backend urls",
+			reload:  func(c *Config) { c.BackendURLs = []string{"ws://10.0.0.1:8081"} },
+			changed: "backend-url",
+		},
+		{
+			name:    "backend weights",
+			reload:  func(c *Config) { c.BackendWeights = []int{10} },
+			changed: "backend-weight",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, cleanup := newTestReloadAgent(t)
+			defer cleanup()
+
+			reloaded := *agent.config
+			tt.reload(&reloaded)
+			result, err := agent.Reload(&reloaded)
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{tt.changed}, result.Changed)
+			assert.True(t, result.Reconnected)
+			assert.Len(t, agent.reconnect, 1)
+			assert.Equal(t, reloaded.Subscriptions, agent.subscriptions())
+			assert.Equal(t, reloaded.BackendURLs[0], agent.selectBackend())
+		})
+	}
+}
+
+func TestReloadLogLevel(t *testing.T) {
+	agent, cleanup := newTestReloadAgent(t)
+	defer cleanup()
+
+	reloaded := *agent.config
+	reloaded.LogLevel = "debug"
+	result, err := agent.Reload(&reloaded)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log-level"}, result.Changed)
+	assert.Equal(t, logrus.DebugLevel, logrus.GetLevel())
+
+	reloaded.LogLevel = "verbose"
+	_, err = agent.Reload(&reloaded)
+	assert.Error(t, err)
+}
+
+func TestReloadRestartRequired(t *testing.T) {
+	agent, cleanup := newTestReloadAgent(t)
+	defer cleanup()
+
+	reloaded := *agent.config
+	reloaded.AgentName = "renamed"
+	reloaded.KeepaliveInterval = agent.config.KeepaliveInterval + 10
+	result, err := agent.Reload(&reloaded)
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Changed)
+	assert.Equal(t, []string{"name", "keepalive-interval"}, result.RestartRequired)
+	assert.NotEqual(t, "renamed", agent.config.AgentName)
+}
+
+func TestReloadHandler(t *testing.T) {
+	agent, cleanup := newTestReloadAgent(t)
+	defer cleanup()
+	handler := reloadHandler(agent)
+
+	// The agent can't read its configuration again
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/config/reload", nil))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	agent.SetConfigReloader(func() (*Config, error) {
+		return nil, errors.New("invalid configuration file")
+	})
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/config/reload", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	agent.SetConfigReloader(func() (*Config, error) {
+		reloaded := *agent.config
+		reloaded.Annotations = map[string]string{"owner": "ops"}
+		return &reloaded, nil
+	})
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/config/reload", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	result := &ReloadResult{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(result))
+	assert.Equal(t, []string{"annotations"}, result.Changed)
+}
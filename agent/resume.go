@@ -0,0 +1,26 @@
+package agent
+
+import (
+	"net/http"
+
+	"github.com/sensu/sensu-go/transport"
+)
+
+// setResumeToken sets the resume token of the last session in the headers of
+// the connection to the backend URL, if the session was opened with the same
+// backend. The agent opens a new session if the backend does not accept the
+// token.
+func (a *Agent) setResumeToken(url string) {
+	if a.resumeToken != "" && a.resumeURL == url {
+		a.header.Set(transport.HeaderKeyResumeToken, a.resumeToken)
+		return
+	}
+	a.header.Del(transport.HeaderKeyResumeToken)
+}
+
+// saveResumeToken keeps the resume token issued by the backend URL for the
+// session, if any. Tokens are single-use, so the previous one is discarded.
+func (a *Agent) saveResumeToken(url string, respHeader http.Header) {
+	a.resumeToken = respHeader.Get(transport.HeaderKeyResumeToken)
+	a.resumeURL = url
+}
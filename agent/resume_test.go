@@ -0,0 +1,32 @@
+package agent
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumeToken(t *testing.T) {
+	a := &Agent{header: http.Header{}}
+
+	// No token is sent before the first session
+	a.setResumeToken("ws://backend1:8081")
+	assert.Empty(t, a.header.Get(transport.HeaderKeyResumeToken))
+
+	respHeader := http.Header{}
+	respHeader.Set(transport.HeaderKeyResumeToken, "token")
+	a.saveResumeToken("ws://backend1:8081", respHeader)
+
+	// The token is only sent to the backend that issued it
+	a.setResumeToken("ws://backend2:8081")
+	assert.Empty(t, a.header.Get(transport.HeaderKeyResumeToken))
+	a.setResumeToken("ws://backend1:8081")
+	assert.Equal(t, "token", a.header.Get(transport.HeaderKeyResumeToken))
+
+	// The backends that do not issue tokens clear the previous one
+	a.saveResumeToken("ws://backend1:8081", http.Header{})
+	a.setResumeToken("ws://backend1:8081")
+	assert.Empty(t, a.header.Get(transport.HeaderKeyResumeToken))
+}
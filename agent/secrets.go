@@ -0,0 +1,297 @@
+package agent
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/command"
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+)
+
+const (
+	// SecretsProviderEnv resolves the secrets from the environment variables
+	// of the agent.
+	SecretsProviderEnv = "env"
+
+	// SecretsProviderFile resolves the secrets from the files of the secrets
+	// directory.
+	SecretsProviderFile = "file"
+
+	// SecretsProviderExec resolves the secrets with the output of the secrets
+	// command, which reads the secret reference on its standard input.
+	SecretsProviderExec = "exec"
+
+	// secretsExecTimeout is the time, in seconds, after which the secrets
+	// command is killed.
+	secretsExecTimeout = 10
+
+	// secretReferenceEnvVar is the environment variable holding the secret
+	// reference resolved by the secrets command.
+	secretReferenceEnvVar = "SENSU_SECRET_REFERENCE"
+)
+
+// SecretsProviders are the providers the secrets can be resolved from.
+var SecretsProviders = []string{
+	SecretsProviderEnv,
+	SecretsProviderFile,
+	SecretsProviderExec,
+}
+
+// secretPlaceholderRe matches the secret placeholders of the check commands
+// and environment variables, e.g. ${secret:env:DB_PASSWORD}.
+var secretPlaceholderRe = regexp.MustCompile(`\$\{secret:([a-z]+):([^}]+)\}`)
+
+// SecretsConfig configures the providers the agent resolves the secret
+// placeholders of the checks from, when it executes them. The secrets are
+// never sent to the backend.
+type SecretsConfig struct {
+	// Providers are the enabled providers: env, file and exec. The checks
+	// with secret placeholders fail if empty.
+	Providers []string
+
+	// FileDir is the directory holding the secrets of the file provider.
+	// The references of the file secrets are relative to it.
+	FileDir string
+
+	// ExecCommand is the command run by the exec provider, with the secret
+	// reference on its standard input and in SENSU_SECRET_REFERENCE. Its
+	// output is the secret.
+	ExecCommand string
+
+	// EnvReferences are the patterns of the environment variables the env
+	// provider resolves, e.g. SENSU_SECRET_*. Every other variable of the
+	// agent environment is denied.
+	EnvReferences []string
+
+	// FileReferences are the patterns of the files of FileDir the file
+	// provider resolves, relative to it, e.g. checks/*.
+	FileReferences []string
+
+	// ExecReferences are the patterns of the references the exec provider
+	// passes to ExecCommand.
+	ExecReferences []string
+}
+
+// references returns the patterns of the references the provider resolves.
+func (c *SecretsConfig) references(provider string) []string {
+	switch provider {
+	case SecretsProviderEnv:
+		return c.EnvReferences
+	case SecretsProviderFile:
+		return c.FileReferences
+	case SecretsProviderExec:
+		return c.ExecReferences
+	}
+	return nil
+}
+
+// allow returns true if the reference matches one of the patterns of the
+// references the provider resolves.
+func (c *SecretsConfig) allow(provider, reference string) bool {
+	for _, pattern := range c.references(provider) {
+		if ok, _ := path.Match(pattern, reference); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSecretsConfig returns an error if a secrets provider is unknown, is
+// not configured or has no allowed references.
+func ValidateSecretsConfig(config *SecretsConfig) error {
+	if config == nil {
+		return nil
+	}
+	for _, provider := range config.Providers {
+		if !utilstrings.InArray(provider, SecretsProviders) {
+			return fmt.Errorf("invalid secrets provider %q, must be one of %v", provider, SecretsProviders)
+		}
+		if len(config.references(provider)) == 0 {
+			return fmt.Errorf("the %s secrets provider requires the references it resolves", provider)
+		}
+	}
+	for _, provider := range SecretsProviders {
+		for _, pattern := range config.references(provider) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid %s secret reference pattern %q: %s", provider, pattern, err)
+			}
+		}
+	}
+	if utilstrings.InArray(SecretsProviderFile, config.Providers) && config.FileDir == "" {
+		return errors.New("the file secrets provider requires a secrets directory")
+	}
+	if utilstrings.InArray(SecretsProviderExec, config.Providers) && config.ExecCommand == "" {
+		return errors.New("the exec secrets provider requires a secrets command")
+	}
+	return nil
+}
+
+// secretsResolver resolves the secret placeholders from the enabled
+// providers.
+type secretsResolver struct {
+	config    SecretsConfig
+	executor  command.Executor
+	lookupEnv func(string) (string, bool)
+}
+
+func newSecretsResolver(config *SecretsConfig) *secretsResolver {
+	r := &secretsResolver{
+		executor:  command.NewExecutor(),
+		lookupEnv: os.LookupEnv,
+	}
+	if config != nil {
+		r.config = *config
+	}
+	return r
+}
+
+// resolveCheck returns a copy of the check configuration with the secret
+// placeholders of its command and environment variables resolved, and the
+// values of the resolved secrets. The check configuration is left untouched,
+// so the placeholders rather than the secrets are sent to the backend. The
+// secrets of the command are shell-quoted, so they are passed as single
+// arguments and never interpreted by the shell.
+func (r *secretsResolver) resolveCheck(ctx context.Context, cfg *corev2.CheckConfig) (*corev2.CheckConfig, map[string]string, error) {
+	values := map[string]string{}
+	resolved := *cfg
+
+	command, err := r.resolve(ctx, cfg.Command, values, shellQuote)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolved.Command = command
+
+	if len(cfg.EnvVars) > 0 {
+		resolved.EnvVars = make([]string, len(cfg.EnvVars))
+		for i, envVar := range cfg.EnvVars {
+			if resolved.EnvVars[i], err = r.resolve(ctx, envVar, values, nil); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return &resolved, values, nil
+}
+
+// resolve replaces the secret placeholders of s with their values, looked up
+// once per placeholder and passed through quote, if not nil.
+func (r *secretsResolver) resolve(ctx context.Context, s string, values map[string]string, quote func(string) string) (string, error) {
+	matches := secretPlaceholderRe.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		b.WriteString(s[last:match[0]])
+		placeholder := s[match[0]:match[1]]
+		value, ok := values[placeholder]
+		if !ok {
+			var err error
+			value, err = r.lookup(ctx, s[match[2]:match[3]], s[match[4]:match[5]])
+			if err != nil {
+				return "", err
+			}
+			values[placeholder] = value
+		}
+		if quote != nil {
+			value = quote(value)
+		}
+		b.WriteString(value)
+		last = match[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
+
+// lookup returns the value of the secret reference from the provider. Its
+// errors never hold the value of the secret.
+func (r *secretsResolver) lookup(ctx context.Context, provider, reference string) (string, error) {
+	if r == nil || !utilstrings.InArray(provider, r.config.Providers) {
+		return "", fmt.Errorf("secrets provider %q is not enabled", provider)
+	}
+	if provider == SecretsProviderFile {
+		reference = filepath.ToSlash(filepath.Clean(reference))
+	}
+	if !r.config.allow(provider, reference) {
+		return "", fmt.Errorf("secret %q is not allowed by the %s secrets provider", reference, provider)
+	}
+
+	switch provider {
+	case SecretsProviderEnv:
+		value, ok := r.lookupEnv(reference)
+		if !ok {
+			return "", fmt.Errorf("secret %q: environment variable not set", reference)
+		}
+		return value, nil
+	case SecretsProviderFile:
+		path := filepath.FromSlash(reference)
+		if filepath.IsAbs(path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("secret %q: the file must be within the secrets directory", reference)
+		}
+		data, err := ioutil.ReadFile(filepath.Join(r.config.FileDir, path))
+		if err != nil {
+			return "", fmt.Errorf("secret %q: could not read the file: %s", reference, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case SecretsProviderExec:
+		result, err := r.executor.Execute(ctx, command.ExecutionRequest{
+			Command: r.config.ExecCommand,
+			Env:     append(os.Environ(), secretReferenceEnvVar+"="+reference),
+			Input:   reference,
+			Timeout: secretsExecTimeout,
+		})
+		if err != nil {
+			return "", fmt.Errorf("secret %q: could not run the secrets command: %s", reference, err)
+		}
+		if result.Status != 0 {
+			return "", fmt.Errorf("secret %q: the secrets command exited with status %d", reference, result.Status)
+		}
+		return strings.TrimRight(result.Output, "\r\n"), nil
+	}
+	return "", fmt.Errorf("unknown secrets provider %q", provider)
+}
+
+// redactSecrets replaces the values of the secrets in the output of a check,
+// so they are not sent to the backend if the check prints them. Besides the
+// literal values, their lines and their base64 and URL encodings are
+// redacted; a check transforming the secrets otherwise can still leak them,
+// hence the allowed references of the providers.
+func redactSecrets(output string, values map[string]string) string {
+	for _, value := range values {
+		for _, secret := range secretForms(value) {
+			output = strings.Replace(output, secret, corev2.Redacted, -1)
+		}
+	}
+	return output
+}
+
+// secretForms returns the forms of the secret value redacted from the
+// outputs, the longest first.
+func secretForms(value string) []string {
+	if value == "" {
+		return nil
+	}
+	forms := []string{
+		base64.StdEncoding.EncodeToString([]byte(value)),
+		base64.URLEncoding.EncodeToString([]byte(value)),
+		url.QueryEscape(value),
+		value,
+	}
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" && line != value {
+			forms = append(forms, line)
+		}
+	}
+	return forms
+}
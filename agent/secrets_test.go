@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/testing/mockexecutor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSecretsConfig(t *testing.T) {
+	assert.NoError(t, ValidateSecretsConfig(nil))
+	assert.NoError(t, ValidateSecretsConfig(&SecretsConfig{}))
+	assert.NoError(t, ValidateSecretsConfig(&SecretsConfig{Providers: []string{"env"}, EnvReferences: []string{"SENSU_SECRET_*"}}))
+	assert.NoError(t, ValidateSecretsConfig(&SecretsConfig{
+		Providers:      SecretsProviders,
+		FileDir:        "/etc/sensu/secrets",
+		ExecCommand:    "get-secret",
+		EnvReferences:  []string{"API_TOKEN"},
+		FileReferences: []string{"checks/*"},
+		ExecReferences: []string{"*"},
+	}))
+	assert.Error(t, ValidateSecretsConfig(&SecretsConfig{Providers: []string{"vault"}}))
+	assert.Error(t, ValidateSecretsConfig(&SecretsConfig{Providers: []string{"file"}, FileReferences: []string{"*"}}))
+	assert.Error(t, ValidateSecretsConfig(&SecretsConfig{Providers: []string{"exec"}, ExecReferences: []string{"*"}}))
+
+	// The enabled providers require their allowed references
+	assert.Error(t, ValidateSecretsConfig(&SecretsConfig{Providers: []string{"env"}}))
+	assert.Error(t, ValidateSecretsConfig(&SecretsConfig{Providers: []string{"file"}, FileDir: "/etc/sensu/secrets"}))
+	assert.Error(t, ValidateSecretsConfig(&SecretsConfig{Providers: []string{"env"}, EnvReferences: []string{"[API"}}))
+}
+
+func TestResolveSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sensu-agent-secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "db"), []byte("hunter2\n"), 0600))
+
+	executor := &mockexecutor.MockExecutor{}
+	executor.Return(&command.ExecutionResponse{Output: "s3cr3t\n"}, nil)
+	resolver := newSecretsResolver(&SecretsConfig{
+		Providers:      []string{SecretsProviderEnv, SecretsProviderFile, SecretsProviderExec},
+		FileDir:        dir,
+		ExecCommand:    "get-secret",
+		EnvReferences:  []string{"API_TOKEN", "MISSING"},
+		FileReferences: []string{"db", "missing", "../etc/shadow", "/etc/shadow"},
+		ExecReferences: []string{"ldap/*"},
+	})
+	resolver.executor = executor
+	resolver.lookupEnv = func(name string) (string, bool) {
+		switch name {
+		case "API_TOKEN":
+			return "t0ken", true
+		case "AWS_SECRET_ACCESS_KEY":
+			return "aws", true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{name: "no placeholder", input: "check-http -u https://example.com", want: "check-http -u https://example.com"},
+		{name: "env", input: "check-api --token ${secret:env:API_TOKEN}", want: "check-api --token t0ken"},
+		{name: "file", input: "DB_PASSWORD=${secret:file:db}", want: "DB_PASSWORD=hunter2"},
+		{name: "exec", input: "check-ldap -p ${secret:exec:ldap/bind}", want: "check-ldap -p s3cr3t"},
+		{name: "several", input: "${secret:env:API_TOKEN}:${secret:file:db}", want: "t0ken:hunter2"},
+		{name: "unset env", input: "${secret:env:MISSING}", wantErr: `secret "MISSING": environment variable not set`},
+		{name: "missing file", input: "${secret:file:missing}", wantErr: `secret "missing": could not read the file`},
+		{name: "file outside the directory", input: "${secret:file:../etc/shadow}", wantErr: "the file must be within the secrets directory"},
+		{name: "absolute file", input: "${secret:file:/etc/shadow}", wantErr: "the file must be within the secrets directory"},
+		{name: "unknown provider", input: "${secret:vault:db}", wantErr: `secrets provider "vault" is not enabled`},
+		{name: "env not allowed", input: "${secret:env:AWS_SECRET_ACCESS_KEY}", wantErr: `secret "AWS_SECRET_ACCESS_KEY" is not allowed by the env secrets provider`},
+		{name: "file not allowed", input: "${secret:file:other}", wantErr: `secret "other" is not allowed by the file secrets provider`},
+		{name: "file not allowed once cleaned", input: "${secret:file:./db/../other}", wantErr: `secret "other" is not allowed by the file secrets provider`},
+		{name: "exec not allowed", input: "${secret:exec:aws/root}", wantErr: `secret "aws/root" is not allowed by the exec secrets provider`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.resolve(context.Background(), tt.input, map[string]string{}, nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// A failed secrets command does not resolve the secret
+	executor.Return(&command.ExecutionResponse{Output: "permission denied", Status: 1}, nil)
+	_, err = resolver.resolve(context.Background(), "${secret:exec:ldap/bind}", map[string]string{}, nil)
+	assert.EqualError(t, err, `secret "ldap/bind": the secrets command exited with status 1`)
+
+	// Without providers, the checks with secrets fail
+	_, err = newSecretsResolver(nil).resolve(context.Background(), "${secret:env:API_TOKEN}", map[string]string{}, nil)
+	assert.EqualError(t, err, `secrets provider "env" is not enabled`)
+}
+
+func TestResolveCheckSecrets(t *testing.T) {
+	resolver := newSecretsResolver(&SecretsConfig{Providers: []string{SecretsProviderEnv}, EnvReferences: []string{"API_*"}})
+	resolver.lookupEnv = func(name string) (string, bool) {
+		switch name {
+		case "API_TOKEN":
+			return "t0ken", true
+		case "API_PASSWORD":
+			return "p4ss; rm -rf / 'x'", true
+		}
+		return "", false
+	}
+
+	check := corev2.FixtureCheckConfig("api")
+	check.Command = "check-api --token ${secret:env:API_TOKEN}"
+	check.EnvVars = []string{"TOKEN=${secret:env:API_TOKEN}", "LANG=C"}
+
+	resolved, values, err := resolver.resolveCheck(context.Background(), check)
+	require.NoError(t, err)
+	assert.Equal(t, "check-api --token t0ken", resolved.Command)
+	assert.Equal(t, []string{"TOKEN=t0ken", "LANG=C"}, resolved.EnvVars)
+	assert.Equal(t, map[string]string{"${secret:env:API_TOKEN}": "t0ken"}, values)
+
+	// The check configuration sent back to the backend keeps the placeholders
+	assert.Equal(t, "check-api --token ${secret:env:API_TOKEN}", check.Command)
+	assert.Equal(t, "TOKEN=${secret:env:API_TOKEN}", check.EnvVars[0])
+
+	assert.Equal(t, "authenticated with REDACTED", redactSecrets("authenticated with t0ken", values))
+
+	// The secrets of the command are shell-quoted, unlike those of the
+	// environment variables
+	check.Command = "check-api --password ${secret:env:API_PASSWORD}"
+	check.EnvVars = []string{"PASSWORD=${secret:env:API_PASSWORD}"}
+	resolved, values, err = resolver.resolveCheck(context.Background(), check)
+	require.NoError(t, err)
+	assert.Equal(t, `check-api --password 'p4ss; rm -rf / '"'"'x'"'"''`, resolved.Command)
+	assert.Equal(t, []string{"PASSWORD=p4ss; rm -rf / 'x'"}, resolved.EnvVars)
+	assert.Equal(t, map[string]string{"${secret:env:API_PASSWORD}": "p4ss; rm -rf / 'x'"}, values)
+}
+
+func TestRedactSecrets(t *testing.T) {
+	values := map[string]string{"${secret:file:key}": "line1\nline2"}
+	assert.Equal(t, "REDACTED", redactSecrets("line1\nline2", values))
+	assert.Equal(t, "got REDACTED", redactSecrets("got bGluZTEKbGluZTI=", values))
+	assert.Equal(t, "got REDACTED", redactSecrets("got line1%0Aline2", values))
+	assert.Equal(t, "first REDACTED, second REDACTED", redactSecrets("first line1, second line2", values))
+	assert.Equal(t, "nothing to redact", redactSecrets("nothing to redact", map[string]string{"${secret:env:EMPTY}": ""}))
+}
@@ -0,0 +1,247 @@
+package agent
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
+	corev1 "github.com/sensu/sensu-go/types/v1"
+	"github.com/sensu/sensu-go/util/lru"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// socketResultAccepted is the result of the messages sent to the backend.
+	socketResultAccepted = "accepted"
+
+	// socketResultFiltered is the result of the messages dropped by the agent
+	// event filters.
+	socketResultFiltered = "filtered"
+
+	// socketResultInvalid is the result of the messages which are not valid
+	// check results.
+	socketResultInvalid = "invalid"
+
+	// socketResultUnauthorized is the result of the messages without the
+	// socket token.
+	socketResultUnauthorized = "unauthorized"
+
+	// socketResultRateLimited is the result of the messages dropped because
+	// their source exceeded the socket rate limit.
+	socketResultRateLimited = "rate_limited"
+)
+
+// maxSocketSources is the number of source addresses rate limited by the
+// sockets. The least recently seen ones are forgotten, and get a new limit.
+const maxSocketSources = 1024
+
+var socketMessages = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "sensu_agent_socket_messages_total",
+		Help: "Number of messages received by the agent TCP and UDP sockets, by protocol and result",
+	},
+	[]string{"protocol", "result"},
+)
+
+func init() {
+	_ = prometheus.Register(socketMessages)
+}
+
+// socketMessage is a check result sent to the agent sockets, with the token
+// authenticating its sender.
+type socketMessage struct {
+	corev1.CheckResult
+
+	// Token is the token the messages must hold if the sockets require one.
+	Token string `json:"token,omitempty"`
+}
+
+// decodeSocketMessage decodes the message received by a socket. In strict
+// mode, the message must be a single JSON object without unknown fields.
+func decodeSocketMessage(data []byte, strict bool) (*socketMessage, error) {
+	var msg socketMessage
+	if !strict {
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, err
+		}
+		return &msg, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&msg); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the check result")
+	}
+	return &msg, nil
+}
+
+// validateSocketMessage returns an error describing why the check result is
+// invalid, if it is.
+func validateSocketMessage(result *corev1.CheckResult) error {
+	if result.Name == "" {
+		return errors.New("the check name must be set")
+	}
+	if err := corev2.ValidateName(result.Name); err != nil {
+		return fmt.Errorf("invalid check name %q: %s", result.Name, err)
+	}
+	if result.Output == "" {
+		return errors.New("the check output must be set")
+	}
+	for _, source := range []string{result.Source, result.Client} {
+		if source == "" {
+			continue
+		}
+		if err := corev2.ValidateName(source); err != nil {
+			return fmt.Errorf("invalid source %q: %s", source, err)
+		}
+	}
+	if result.Source != "" && result.Client != "" && result.Source != result.Client {
+		return fmt.Errorf("the source %q and the deprecated client %q must not differ", result.Source, result.Client)
+	}
+	for _, handler := range append([]string{result.Handler}, result.Handlers...) {
+		if handler == "" {
+			continue
+		}
+		if err := corev2.ValidateName(handler); err != nil {
+			return fmt.Errorf("invalid handler %q: %s", handler, err)
+		}
+	}
+	return nil
+}
+
+// socketLimiters rate limits the messages received by the sockets from each
+// source address. It is safe for concurrent use.
+type socketLimiters struct {
+	limit    rate.Limit
+	burst    int
+	mu       sync.Mutex
+	limiters *lru.Cache
+}
+
+// newSocketLimiters returns the rate limiters of the sockets, which allow
+// all the messages if the limit is 0.
+func newSocketLimiters(limit rate.Limit, burst int) *socketLimiters {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &socketLimiters{
+		limit:    limit,
+		burst:    burst,
+		limiters: lru.New(maxSocketSources),
+	}
+}
+
+// allow returns true if the source address did not exceed the rate limit.
+func (l *socketLimiters) allow(source string, now time.Time) bool {
+	if l == nil || l.limit <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	value, ok := l.limiters.Get(source)
+	if !ok {
+		value = rate.NewLimiter(l.limit, l.burst)
+		l.limiters.Add(source, value)
+	}
+	return value.(*rate.Limiter).AllowN(now, 1)
+}
+
+// socketSource returns the source address of a socket message, without its
+// port.
+func socketSource(addr net.Addr) string {
+	if addr == nil {
+		return "unknown"
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// processSocketMessage authenticates, validates and rate limits a message
+// received by a socket, sends it to the backend if it is accepted, and returns
+// the response of the TCP socket. The source address is only logged, so that
+// the number of metric series stays bounded.
+func (a *Agent) processSocketMessage(protocol string, addr net.Addr, data []byte) string {
+	result, response := a.acceptSocketMessage(protocol, socketSource(addr), data)
+	socketMessages.WithLabelValues(protocol, result).Inc()
+	return response
+}
+
+func (a *Agent) acceptSocketMessage(protocol, source string, data []byte) (result, response string) {
+	config := a.config.Socket
+	if config == nil {
+		config = &SocketConfig{}
+	}
+	fields := logrus.Fields{
+		"protocol": protocol,
+		"source":   source,
+	}
+	invalid := func(err error) (string, string) {
+		logger.WithFields(fields).WithError(err).Error("invalid socket message")
+		if config.Strict {
+			return socketResultInvalid, "invalid: " + err.Error()
+		}
+		return socketResultInvalid, "invalid"
+	}
+
+	if !a.socketLimiters.allow(source, time.Now()) {
+		logger.WithFields(fields).Warn("socket message dropped, rate limit exceeded")
+		return socketResultRateLimited, "rate limited"
+	}
+
+	msg, err := decodeSocketMessage(data, config.Strict)
+	if err != nil {
+		return invalid(err)
+	}
+	if config.Token != "" && subtle.ConstantTimeCompare([]byte(msg.Token), []byte(config.Token)) != 1 {
+		logger.WithFields(fields).Warn("socket message rejected, invalid token")
+		return socketResultUnauthorized, "unauthorized"
+	}
+	if config.Strict {
+		if err := validateSocketMessage(&msg.CheckResult); err != nil {
+			return invalid(err)
+		}
+	}
+
+	var event corev2.Event
+	if err := translateToEvent(a, msg.CheckResult, &event); err != nil {
+		return invalid(err)
+	}
+
+	// Prepare the event by mutating it as required so it passes validation
+	if err := prepareEvent(a, &event); err != nil {
+		return invalid(err)
+	}
+
+	if a.eventFilter.Filter(&event) {
+		logger.Debug("event dropped by the agent event filters")
+		return socketResultFiltered, "ok"
+	}
+
+	payload, err := a.marshal(&event)
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("could not marshal json payload")
+		return socketResultInvalid, "invalid"
+	}
+	a.sendMessage(&transport.Message{
+		Type:    transport.MessageTypeEvent,
+		Payload: payload,
+	})
+	return socketResultAccepted, "ok"
+}
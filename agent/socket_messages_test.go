@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "github.com/sensu/sensu-go/types/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeSocketMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		strict  bool
+		wantErr bool
+	}{
+		{name: "check result", data: `{"name":"app","output":"ok","token":"secret"}`},
+		{name: "unknown field", data: `{"name":"app","output":"ok","sttus":2}`},
+		{name: "strict check result", data: `{"name":"app","output":"ok","token":"secret"}`, strict: true},
+		{name: "strict unknown field", data: `{"name":"app","output":"ok","sttus":2}`, strict: true, wantErr: true},
+		{name: "strict trailing data", data: `{"name":"app","output":"ok"} {}`, strict: true, wantErr: true},
+		{name: "invalid type", data: `{"name":"app","status":"2"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := decodeSocketMessage([]byte(tt.data), tt.strict)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "app", msg.Name)
+		})
+	}
+}
+
+func TestValidateSocketMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  corev1.CheckResult
+		wantErr string
+	}{
+		{name: "valid", result: corev1.CheckResult{Name: "app", Output: "ok", Source: "db", Handlers: []string{"slack"}}},
+		{name: "no name", result: corev1.CheckResult{Output: "ok"}, wantErr: "the check name must be set"},
+		{name: "invalid name", result: corev1.CheckResult{Name: "my app", Output: "ok"}, wantErr: `invalid check name "my app"`},
+		{name: "no output", result: corev1.CheckResult{Name: "app"}, wantErr: "the check output must be set"},
+		{name: "invalid source", result: corev1.CheckResult{Name: "app", Output: "ok", Source: "d/b"}, wantErr: `invalid source "d/b"`},
+		{name: "different client", result: corev1.CheckResult{Name: "app", Output: "ok", Source: "db", Client: "web"}, wantErr: "must not differ"},
+		{name: "invalid handler", result: corev1.CheckResult{Name: "app", Output: "ok", Handler: "sl ack"}, wantErr: `invalid handler "sl ack"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSocketMessage(&tt.result)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSocketLimiters(t *testing.T) {
+	now := time.Now()
+
+	// Without a limit, all the messages are allowed
+	var limiters *socketLimiters
+	assert.True(t, limiters.allow("127.0.0.1", now))
+	limiters = newSocketLimiters(0, 0)
+	for i := 0; i < 100; i++ {
+		assert.True(t, limiters.allow("127.0.0.1", now))
+	}
+
+	// Each source has its own limit
+	limiters = newSocketLimiters(1, 2)
+	assert.True(t, limiters.allow("127.0.0.1", now))
+	assert.True(t, limiters.allow("127.0.0.1", now))
+	assert.False(t, limiters.allow("127.0.0.1", now))
+	assert.True(t, limiters.allow("10.0.0.2", now))
+	assert.True(t, limiters.allow("127.0.0.1", now.Add(time.Second)))
+
+	// The least recently seen sources are forgotten once there are too many,
+	// even if they are all active
+	for i := 0; i < maxSocketSources+10; i++ {
+		assert.True(t, limiters.allow(fmt.Sprintf("10.1.%d.%d", i/256, i%256), now))
+	}
+	assert.Equal(t, maxSocketSources, limiters.limiters.Len())
+	_, ok := limiters.limiters.Get("10.1.0.0")
+	assert.False(t, ok)
+	_, ok = limiters.limiters.Get(fmt.Sprintf("10.1.%d.%d", (maxSocketSources+9)/256, (maxSocketSources+9)%256))
+	assert.True(t, ok)
+}
+
+func TestAcceptSocketMessage(t *testing.T) {
+	agent := &Agent{
+		config:         &Config{Socket: &SocketConfig{Token: "secret", Strict: true}},
+		socketLimiters: newSocketLimiters(1, 1),
+	}
+
+	result, response := agent.acceptSocketMessage("tcp", "127.0.0.1", []byte(`{"name":"app","output":"ok","token":"guess"}`))
+	assert.Equal(t, socketResultUnauthorized, result)
+	assert.Equal(t, "unauthorized", response)
+
+	// The rate limit applies before anything else
+	result, response = agent.acceptSocketMessage("tcp", "127.0.0.1", []byte(`{"name":"app","output":"ok","token":"secret"}`))
+	assert.Equal(t, socketResultRateLimited, result)
+	assert.Equal(t, "rate limited", response)
+
+	result, response = agent.acceptSocketMessage("tcp", "10.0.0.2", []byte(`{"name":"app","output":"ok","sttus":2}`))
+	assert.Equal(t, socketResultInvalid, result)
+	assert.Equal(t, `invalid: json: unknown field "sttus"`, response)
+
+	result, response = agent.acceptSocketMessage("tcp", "10.0.0.3", []byte(`{"name":"my app","output":"ok","token":"secret"}`))
+	assert.Equal(t, socketResultInvalid, result)
+	assert.Contains(t, response, `invalid: invalid check name "my app"`)
+
+	// Without strict mode, the reasons are only logged
+	agent.config.Socket.Strict = false
+	result, response = agent.acceptSocketMessage("udp", "10.0.0.4", []byte(`{"output":"ok","token":"secret"}`))
+	assert.Equal(t, socketResultInvalid, result)
+	assert.Equal(t, "invalid", response)
+}
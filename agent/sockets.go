@@ -9,10 +9,6 @@ import (
 	"net"
 	"regexp"
 	"time"
-
-	"github.com/sensu/sensu-go/transport"
-	"github.com/sensu/sensu-go/types"
-	corev1 "github.com/sensu/sensu-go/types/v1"
 )
 
 var (
@@ -141,37 +137,12 @@ func (a *Agent) handleTCPMessages(c net.Conn) {
 		// Check our received data for valid JSON. If we get invalid JSON at this point,
 		// read again from client, add any new message to the buffer, and parse
 		// again.
-		var event types.Event
-		var result corev1.CheckResult
-		if err = json.Unmarshal(messageBuffer.Bytes(), &result); err != nil {
+		if !json.Valid(messageBuffer.Bytes()) {
 			continue
 		}
 
-		if err = translateToEvent(a, result, &event); err != nil {
-			logger.WithError(err).Error("1.x returns \"invalid\"")
-			return
-		}
-
-		// Prepare the event by mutating it as required so it passes validation
-		if err = prepareEvent(a, &event); err != nil {
-			logger.WithError(err).Error("invalid event")
-			return
-		}
-
-		// At this point, should receive valid JSON, so send it along to the
-		// message sender.
-		payload, err := a.marshal(&event)
-		if err != nil {
-			logger.WithError(err).Error("could not marshal json payload")
-			return
-		}
-
-		tm := &transport.Message{
-			Type:    transport.MessageTypeEvent,
-			Payload: payload,
-		}
-		a.sendMessage(tm)
-		_, _ = c.Write([]byte("ok"))
+		response := a.processSocketMessage("tcp", c.RemoteAddr(), messageBuffer.Bytes())
+		_, _ = c.Write([]byte(response))
 		return
 	}
 	_, _ = c.Write([]byte("invalid"))
@@ -198,7 +169,7 @@ func (a *Agent) handleUDPMessages(ctx context.Context, c net.PacketConn) {
 	// Read everything sent from the connection to the message buffer. Any error
 	// will return. If the buffer is zero bytes, close the connection and return.
 	for {
-		bytesRead, _, err := c.ReadFrom(buf[0:])
+		bytesRead, addr, err := c.ReadFrom(buf[0:])
 		select {
 		case <-ctx.Done():
 			logger.Debug("UDP listener stopped")
@@ -224,33 +195,7 @@ func (a *Agent) handleUDPMessages(ctx context.Context, c net.PacketConn) {
 			// Check the message for valid JSON. Valid JSON payloads are passed to the
 			// message sender with the addition of the agent's entity if it is not
 			// included in the message. Any JSON errors are logged, and we return.
-			var event types.Event
-			var result corev1.CheckResult
-			if err = json.Unmarshal(buf[:bytesRead], &result); err != nil {
-				logger.WithError(err).Error("UDP Invalid event data")
-				return
-			}
-
-			if err = translateToEvent(a, result, &event); err != nil {
-				logger.WithError(err).Error("1.x returns \"invalid\"")
-				return
-			}
-
-			// Prepare the event by mutating it as required so it passes validation
-			if err = prepareEvent(a, &event); err != nil {
-				logger.WithError(err).Error("invalid event")
-				return
-			}
-
-			payload, err := a.marshal(&event)
-			if err != nil {
-				return
-			}
-			tm := &transport.Message{
-				Type:    transport.MessageTypeEvent,
-				Payload: payload,
-			}
-			a.sendMessage(tm)
+			_ = a.processSocketMessage("udp", addr, buf[:bytesRead])
 		}
 
 	}
@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
+)
+
+// spoolStats returns statistics about the spool of events received by the
+// agent API.
+func (a *Agent) spoolStats() (corev2.AgentSpoolStats, error) {
+	stats, err := a.apiQueue.Stats()
+	if err != nil {
+		return stats, fmt.Errorf("error reading the spool statistics: %s", err)
+	}
+	stats.Flushing = int(atomic.LoadInt64(&a.spoolFlushing))
+	return stats, nil
+}
+
+// flushSpool transmits the events currently in the spool without applying
+// the events API rate limit.
+func (a *Agent) flushSpool() (corev2.AgentSpoolStats, error) {
+	stats, err := a.apiQueue.Stats()
+	if err != nil {
+		return stats, fmt.Errorf("error reading the spool statistics: %s", err)
+	}
+	atomic.StoreInt64(&a.spoolFlushing, int64(stats.Messages))
+	logger.WithField("messages", stats.Messages).Info("flushing the spool")
+	return a.spoolStats()
+}
+
+// purgeSpool discards the events currently waiting in the spool.
+func (a *Agent) purgeSpool() (corev2.AgentSpoolStats, error) {
+	purged, err := a.apiQueue.Purge()
+	if err != nil {
+		return corev2.AgentSpoolStats{}, fmt.Errorf("error purging the spool: %s", err)
+	}
+	atomic.StoreInt64(&a.spoolFlushing, 0)
+	logger.WithField("messages", purged).Warn("purged the spool")
+	stats, err := a.spoolStats()
+	stats.Purged = purged
+	return stats, err
+}
+
+// spoolAction performs the given spool action.
+func (a *Agent) spoolAction(action string) (corev2.AgentSpoolStats, error) {
+	switch action {
+	case corev2.AgentSpoolFlush:
+		return a.flushSpool()
+	case corev2.AgentSpoolPurge:
+		return a.purgeSpool()
+	default:
+		return a.spoolStats()
+	}
+}
+
+// handleSpoolRequest performs the spool action requested by the backend, and
+// replies with the resulting spool statistics.
+func (a *Agent) handleSpoolRequest(ctx context.Context, payload []byte) error {
+	var request corev2.AgentSpoolRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return err
+	}
+
+	response := corev2.AgentSpoolResponse{ID: request.ID}
+	if err := corev2.ValidateAgentSpoolAction(request.Action); err != nil {
+		response.Error = err.Error()
+	} else if stats, err := a.spoolAction(request.Action); err != nil {
+		response.Error = err.Error()
+	} else {
+		response.Stats = stats
+	}
+
+	msg, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	a.sendMessage(transport.NewMessage(corev2.AgentSpoolResponseType, msg))
+	return nil
+}
+
+// spoolHandler returns a handler performing the given spool action and
+// responding with the resulting spool statistics.
+func spoolHandler(a *Agent, action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats, err := a.spoolAction(action)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(stats)
+	}
+}
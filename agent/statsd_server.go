@@ -1,12 +1,12 @@
-// Package agent is the running Sensu agent. Agents connect to a Sensu backend,
-// register their presence, subscribe to check channels, download relevant
-// check packages, execute checks, and send results to the Sensu backend via
-// the Event channel.
+// +build !minimal
+
 package agent
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -19,6 +19,13 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// statsdSupported indicates whether the agent was built with the statsd
+// server.
+const statsdSupported = true
+
+// statsdServer is the statsd server of the agent.
+type statsdServer = statsd.Server
+
 // NewStatsdServer provides a new statsd server for the sensu-agent.
 func NewStatsdServer(a *Agent) *statsd.Server {
 	c := a.config.StatsdServer
@@ -38,6 +45,60 @@ func NewStatsdServer(a *Agent) *statsd.Server {
 	return s
 }
 
+// runStatsdServer runs the statsd server until the context is done. Its socket
+// also accepts the dogstatsd distribution type, aggregated like histograms.
+func runStatsdServer(ctx context.Context, s *statsdServer) error {
+	conn, err := net.ListenPacket("udp", s.MetricsAddr)
+	return s.RunWithCustomSocket(ctx, func() (net.PacketConn, error) {
+		if err != nil {
+			return nil, err
+		}
+		return dogstatsdConn{PacketConn: conn}, nil
+	})
+}
+
+// dogstatsdConn rewrites the dogstatsd distributions it reads (name:1|d) as
+// histograms (name:1|h), which the statsd parser understands and aggregates
+// like timers.
+type dogstatsdConn struct {
+	net.PacketConn
+}
+
+// ReadFrom reads a datagram and rewrites its distributions in place.
+func (c dogstatsdConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, addr, err := c.PacketConn.ReadFrom(b)
+	if n > 0 {
+		rewriteDistributions(b[:n])
+	}
+	return n, addr, err
+}
+
+// rewriteDistributions replaces the distribution type of the metrics of the
+// datagram by the histogram type. The events and service checks are left
+// unchanged.
+func rewriteDistributions(datagram []byte) {
+	for len(datagram) > 0 {
+		line := datagram
+		if i := bytes.IndexByte(datagram, '\n'); i >= 0 {
+			line, datagram = datagram[:i], datagram[i+1:]
+		} else {
+			datagram = nil
+		}
+		if bytes.HasPrefix(line, []byte("_e{")) || bytes.HasPrefix(line, []byte("_sc|")) {
+			continue
+		}
+		// The type follows the first separator, and is followed by the
+		// sample rate or the tags, if any
+		i := bytes.IndexByte(line, '|')
+		if i < 0 || i+1 >= len(line) || line[i+1] != 'd' {
+			continue
+		}
+		if i+2 == len(line) || line[i+2] == '|' {
+			line[i+1] = 'h'
+		}
+	}
+}
+
 // NewServer will create a new statsd Server with the default configuration.
 func NewServer() *statsd.Server {
 	return &statsd.Server{
@@ -169,23 +230,21 @@ func (c Client) sendMetrics(points []*types.MetricPoint) (retErr error) {
 	return nil
 }
 
+// composeMetricTags converts the tags of a metric, e.g. the dogstatsd tags
+// env:prod,team:ops,canary, into metric tags. The value of a tag is what
+// follows its first colon, and is empty if the tag has none.
 func composeMetricTags(tagsKey string) []*types.MetricTag {
 	tagsKeys := strings.Split(tagsKey, ",")
 	var tags []*types.MetricTag
-	var name, value string
 	for _, tag := range tagsKeys {
-		tagsValues := strings.Split(tag, ":")
-		if len(tagsValues) > 1 {
-			name = tagsValues[0]
-			value = tagsValues[1]
+		if tag == "" {
+			continue
 		}
-		if tag != "" {
-			t := &types.MetricTag{
-				Name:  name,
-				Value: value,
-			}
-			tags = append(tags, t)
+		t := &types.MetricTag{Name: tag}
+		if i := strings.IndexByte(tag, ':'); i >= 0 {
+			t.Name, t.Value = tag[:i], tag[i+1:]
 		}
+		tags = append(tags, t)
 	}
 	return tags
 }
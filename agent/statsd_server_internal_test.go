@@ -0,0 +1,67 @@
+// +build !minimal
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/testing/testutil"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposeDogstatsdMetricTags(t *testing.T) {
+	tags := composeMetricTags("canary,env:prod,url:http://localhost")
+	assert.Equal(t, []*types.MetricTag{
+		{Name: "canary", Value: ""},
+		{Name: "env", Value: "prod"},
+		{Name: "url", Value: "http://localhost"},
+	}, tags)
+}
+
+func TestRewriteDistributions(t *testing.T) {
+	datagram := []byte("latency:12|d|#env:prod\nsize:3|d\nerrors:1|c|@0.5\n_e{2,4}:ab|text|d:1\nduration:7|d|@0.1|#env:dev")
+	rewriteDistributions(datagram)
+	assert.Equal(t, "latency:12|h|#env:prod\nsize:3|h\nerrors:1|c|@0.5\n_e{2,4}:ab|text|d:1\nduration:7|h|@0.1|#env:dev", string(datagram))
+}
+
+func TestReceiveDogstatsdMetrics(t *testing.T) {
+	cfg, cleanup := FixtureConfig()
+	defer cleanup()
+	ports := make([]int, 1)
+	require.NoError(t, testutil.RandomPorts(ports))
+	cfg.StatsdServer.FlushInterval = 1
+	cfg.StatsdServer.Port = ports[0]
+	ta, err := NewAgent(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ta.StartStatsd(ctx)
+	// Give the server a second to start up
+	time.Sleep(time.Second * 1)
+
+	udpClient, err := net.Dial("udp", ta.statsdServer.MetricsAddr)
+	require.NoError(t, err)
+	_, err = udpClient.Write([]byte("latency:12|d|#env:prod,canary"))
+	require.NoError(t, err)
+	require.NoError(t, udpClient.Close())
+
+	msg := <-ta.sendq
+	var event types.Event
+	require.NoError(t, json.Unmarshal(msg.Payload, &event))
+	require.NotNil(t, event.Metrics)
+	var names []string
+	for _, point := range event.Metrics.Points {
+		names = append(names, point.Name)
+		assert.Contains(t, point.Tags, &types.MetricTag{Name: "env", Value: "prod"})
+		assert.Contains(t, point.Tags, &types.MetricTag{Name: "canary", Value: ""})
+	}
+	assert.Contains(t, names, "latency.max")
+	assert.Contains(t, names, "latency.count")
+}
@@ -0,0 +1,34 @@
+// +build minimal
+
+package agent
+
+import (
+	"context"
+	"errors"
+)
+
+// statsdSupported indicates whether the agent was built with the statsd
+// server. Minimal builds leave it out to reduce the agent footprint.
+const statsdSupported = false
+
+// statsdServer stands in for the statsd server in minimal builds.
+type statsdServer struct {
+	MetricsAddr string
+}
+
+// NewStatsdServer returns a statsd server that never runs, since minimal
+// builds don't include the statsd server.
+func NewStatsdServer(a *Agent) *statsdServer {
+	return &statsdServer{}
+}
+
+// runStatsdServer returns an error, since minimal builds don't include the
+// statsd server.
+func runStatsdServer(ctx context.Context, s *statsdServer) error {
+	return s.Run(ctx)
+}
+
+// Run returns an error, since minimal builds don't include the statsd server.
+func (s *statsdServer) Run(ctx context.Context) error {
+	return errors.New("the statsd server is not included in minimal builds")
+}
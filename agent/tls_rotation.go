@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultTLSReloadInterval specifies the default interval (in seconds) between
+// two reads of the TLS certificate, key and trusted CA files of the agent.
+const DefaultTLSReloadInterval = 60
+
+var (
+	tlsCertificateExpiry = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "sensu_agent_tls_certificate_expiry_timestamp_seconds",
+			Help: "Unix time at which the TLS client certificate of the agent expires",
+		},
+	)
+
+	tlsCertificateRotations = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "sensu_agent_tls_certificate_rotations_total",
+			Help: "Number of times the agent reconnected to the backend with a renewed TLS client certificate or trusted CA",
+		},
+	)
+)
+
+func init() {
+	_ = prometheus.Register(tlsCertificateExpiry)
+	_ = prometheus.Register(tlsCertificateRotations)
+}
+
+// tlsIdentity is the TLS identity of the agent, read from its files.
+type tlsIdentity struct {
+	// fingerprint identifies the client certificate and the trusted CA
+	// bundle.
+	fingerprint []byte
+
+	// notAfter is the expiry of the client certificate, zero without client
+	// certificate.
+	notAfter time.Time
+}
+
+// readTLSIdentity reads the TLS client certificate, key and trusted CA files of
+// the agent. It returns an error if the certificate and the key don't match,
+// which happens while they are being renewed.
+func (a *Agent) readTLSIdentity() (*tlsIdentity, error) {
+	opts := a.config.TLS
+	hash := sha256.New()
+	identity := &tlsIdentity{}
+
+	if opts.CertFile != "" && opts.KeyFile != "" {
+		certPEM, err := ioutil.ReadFile(opts.CertFile)
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := ioutil.ReadFile(opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS client certificate: %s", err)
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS client certificate: %s", err)
+		}
+		_, _ = hash.Write(cert.Certificate[0])
+		identity.notAfter = leaf.NotAfter
+	}
+
+	if opts.TrustedCAFile != "" {
+		caPEM, err := ioutil.ReadFile(opts.TrustedCAFile)
+		if err != nil {
+			return nil, err
+		}
+		_, _ = hash.Write(caPEM)
+	}
+
+	identity.fingerprint = hash.Sum(nil)
+	return identity, nil
+}
+
+// watchTLSIdentity reads the TLS files of the agent at every interval, and
+// makes the agent reconnect to the backend with the new identity once they
+// were renewed. The files are only taken into account once the certificate
+// and the key match.
+func (a *Agent) watchTLSIdentity(ctx context.Context, interval time.Duration) {
+	defer logger.Debug("shutting down TLS certificate watcher")
+	current, err := a.readTLSIdentity()
+	if err != nil {
+		logger.WithError(err).Error("could not read the TLS certificate of the agent")
+	} else if !current.notAfter.IsZero() {
+		tlsCertificateExpiry.Set(float64(current.notAfter.Unix()))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			current = a.checkTLSIdentity(current, time.Now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkTLSIdentity reads the TLS identity of the agent again, and asks the
+// agent to reconnect if it changed. It returns the identity the agent now
+// uses.
+func (a *Agent) checkTLSIdentity(current *tlsIdentity, now time.Time) *tlsIdentity {
+	identity, err := a.readTLSIdentity()
+	if err != nil {
+		logger.WithError(err).Warn("could not read the TLS certificate of the agent, retrying at the next interval")
+		return current
+	}
+	if current != nil && bytes.Equal(identity.fingerprint, current.fingerprint) {
+		if !identity.notAfter.IsZero() && now.After(identity.notAfter) {
+			logger.WithField("expiry", identity.notAfter).Warn("the TLS client certificate of the agent expired and was not renewed")
+		}
+		return current
+	}
+
+	fields := logrus.Fields{}
+	if !identity.notAfter.IsZero() {
+		fields["expiry"] = identity.notAfter
+		tlsCertificateExpiry.Set(float64(identity.notAfter.Unix()))
+	}
+	logger.WithFields(fields).Info("TLS certificate renewed, reconnecting to the backend")
+	tlsCertificateRotations.Inc()
+	select {
+	case a.reconnect <- struct{}{}:
+	default:
+	}
+	return identity
+}
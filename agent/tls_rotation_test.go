@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeClientCertificate writes a self-signed certificate expiring at
+// notAfter, and its key, to the files.
+func writeClientCertificate(t *testing.T, certFile, keyFile string, notAfter time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "agent"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	if keyFile != "" {
+		require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	}
+}
+
+func TestCheckTLSIdentity(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	certFile := filepath.Join(config.CacheDir, "agent.pem")
+	keyFile := filepath.Join(config.CacheDir, "agent-key.pem")
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	writeClientCertificate(t, certFile, keyFile, expiry)
+	config.TLS = &corev2.TLSOptions{CertFile: certFile, KeyFile: keyFile}
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+
+	current, err := agent.readTLSIdentity()
+	require.NoError(t, err)
+	assert.True(t, expiry.Equal(current.notAfter))
+
+	// The agent does not reconnect while the files are unchanged
+	assert.True(t, current == agent.checkTLSIdentity(current, time.Now()))
+	assert.Len(t, agent.reconnect, 0)
+
+	// The renewed certificate is ignored until its key is renewed too
+	renewedExpiry := expiry.Add(time.Hour)
+	writeClientCertificate(t, certFile, "", renewedExpiry)
+	assert.True(t, current == agent.checkTLSIdentity(current, time.Now()))
+	assert.Len(t, agent.reconnect, 0)
+
+	// The agent reconnects once the certificate and its key were renewed
+	writeClientCertificate(t, certFile, keyFile, renewedExpiry)
+	renewed := agent.checkTLSIdentity(current, time.Now())
+	assert.True(t, renewedExpiry.Equal(renewed.notAfter))
+	assert.Len(t, agent.reconnect, 1)
+}
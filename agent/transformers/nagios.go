@@ -1,6 +1,9 @@
 package transformers
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -9,6 +12,29 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// NagiosThresholdsAnnotation is the check annotation that, set to
+	// "true", makes the agent annotate the events of the check with the
+	// state of every Nagios perfdata metric against its thresholds.
+	NagiosThresholdsAnnotation = "sensu.io/perfdata-thresholds"
+
+	// NagiosThresholdAnnotationPrefix prefixes the label of the metrics in
+	// the keys of the threshold annotations. Their values are one of
+	// NagiosThresholdOK, NagiosThresholdWarning or NagiosThresholdCritical.
+	NagiosThresholdAnnotationPrefix = "sensu.io/perfdata-threshold."
+
+	// NagiosThresholdOK is the state of a metric within its thresholds.
+	NagiosThresholdOK = "ok"
+
+	// NagiosThresholdWarning is the state of a metric outside of its warning
+	// threshold.
+	NagiosThresholdWarning = "warning"
+
+	// NagiosThresholdCritical is the state of a metric outside of its
+	// critical threshold.
+	NagiosThresholdCritical = "critical"
+)
+
 // NagiosList contains a list of Nagios metrics
 type NagiosList []Nagios
 
@@ -17,6 +43,13 @@ type Nagios struct {
 	Label     string
 	Value     float64
 	Timestamp int64
+
+	// Warn, Crit, Min and Max are the optional warning and critical
+	// threshold ranges, and minimum and maximum values, of the metric.
+	Warn string
+	Crit string
+	Min  string
+	Max  string
 }
 
 // Transform transforms a metric in Nagio perfdata format to Sensu Metric Format
@@ -29,11 +62,116 @@ func (n NagiosList) Transform() []*types.MetricPoint {
 			Timestamp: nagios.Timestamp,
 			Tags:      []*types.MetricTag{},
 		}
+		for _, tag := range []types.MetricTag{
+			{Name: "warn", Value: nagios.Warn},
+			{Name: "crit", Value: nagios.Crit},
+			{Name: "min", Value: nagios.Min},
+			{Name: "max", Value: nagios.Max},
+		} {
+			if tag.Value != "" {
+				tag := tag
+				mp.Tags = append(mp.Tags, &tag)
+			}
+		}
 		points = append(points, mp)
 	}
 	return points
 }
 
+// ThresholdAnnotations returns the state of the metrics against their
+// thresholds, keyed by their label prefixed by
+// NagiosThresholdAnnotationPrefix. The metrics without valid threshold are
+// left out.
+func (n NagiosList) ThresholdAnnotations() map[string]string {
+	annotations := map[string]string{}
+	for _, nagios := range n {
+		if state := nagios.ThresholdState(); state != "" {
+			annotations[NagiosThresholdAnnotationPrefix+nagios.Label] = state
+		}
+	}
+	return annotations
+}
+
+// ThresholdState returns the state of the metric against its critical and
+// warning thresholds, or an empty string if it has no valid threshold.
+func (n Nagios) ThresholdState() string {
+	state := ""
+	for _, threshold := range []struct {
+		spec  string
+		state string
+	}{
+		{n.Crit, NagiosThresholdCritical},
+		{n.Warn, NagiosThresholdWarning},
+	} {
+		if threshold.spec == "" {
+			continue
+		}
+		r, err := parseNagiosRange(threshold.spec)
+		if err != nil {
+			continue
+		}
+		if r.alert(n.Value) {
+			return threshold.state
+		}
+		state = NagiosThresholdOK
+	}
+	return state
+}
+
+// nagiosRange is a threshold range of the Nagios plugins, [@]start:end,
+// which alerts when the value is outside of the range, or inside of it with
+// the @ prefix.
+type nagiosRange struct {
+	start  float64
+	end    float64
+	inside bool
+}
+
+// parseNagiosRange parses a threshold range of the Nagios plugins. The start
+// is 0 when omitted, and ~ for negative infinity, and the end is positive
+// infinity when omitted.
+func parseNagiosRange(spec string) (nagiosRange, error) {
+	r := nagiosRange{end: math.Inf(1)}
+	if strings.HasPrefix(spec, "@") {
+		r.inside = true
+		spec = spec[1:]
+	}
+	if spec == "" {
+		return r, errors.New("empty nagios threshold range")
+	}
+
+	start, end := "", spec
+	if i := strings.Index(spec, ":"); i >= 0 {
+		start, end = spec[:i], spec[i+1:]
+	}
+	var err error
+	switch start {
+	case "":
+	case "~":
+		r.start = math.Inf(-1)
+	default:
+		if r.start, err = strconv.ParseFloat(start, 64); err != nil {
+			return r, fmt.Errorf("invalid nagios threshold range start: %q", start)
+		}
+	}
+	if end != "" {
+		if r.end, err = strconv.ParseFloat(end, 64); err != nil {
+			return r, fmt.Errorf("invalid nagios threshold range end: %q", end)
+		}
+	}
+	if r.start > r.end {
+		return r, fmt.Errorf("invalid nagios threshold range: %q", spec)
+	}
+	return r, nil
+}
+
+// alert returns true if the value is outside of the range, or inside of it
+// for the ranges prefixed with @.
+func (r nagiosRange) alert(value float64) bool {
+	inside := value >= r.start && value <= r.end
+	return inside == r.inside
+}
+
 // ParseNagios parses a Nagios perfdata string into a slice of Nagios struct
 func ParseNagios(event *types.Event) NagiosList {
 	var nagiosList NagiosList
@@ -62,9 +200,10 @@ func ParseNagios(event *types.Event) NagiosList {
 			// the token was just whitespace, ignore it
 			continue
 		}
-		// Clear everything after ';' and split the label and the value
-		parts := strings.Split(metric, ";")
-		parts = strings.Split(parts[0], "=")
+		// Split the label and the value from the thresholds, minimum and
+		// maximum, which follow ';'
+		fieldParts := strings.Split(metric, ";")
+		parts := strings.Split(fieldParts[0], "=")
 		if len(parts) != 2 {
 			logger.WithFields(fields).WithError(ErrMetricExtraction).Errorf("invalid nagios perfdata metric: %q", metric)
 			continue
@@ -90,6 +229,11 @@ func ParseNagios(event *types.Event) NagiosList {
 			Value:     value,
 			Timestamp: event.Check.Executed,
 		}
+		for i, field := range []*string{&n.Warn, &n.Crit, &n.Min, &n.Max} {
+			if i+1 < len(fieldParts) {
+				*field = strings.TrimSpace(fieldParts[i+1])
+			}
+		}
 		nagiosList = append(nagiosList, n)
 	}
 
@@ -123,16 +123,51 @@ func TestParseNagios(t *testing.T) {
 					Label:     "load1",
 					Value:     0.01,
 					Timestamp: 12345,
+					Warn:      "0.010",
+					Crit:      "0.010",
+					Min:       "0",
 				},
 				Nagios{
 					Label:     "load5",
 					Value:     0.04,
 					Timestamp: 12345,
+					Warn:      "0.010",
+					Crit:      "0.010",
+					Min:       "0",
 				},
 				Nagios{
 					Label:     "load15",
 					Value:     0.05,
 					Timestamp: 12345,
+					Warn:      "0.010",
+					Crit:      "0.010",
+					Min:       "0",
+				},
+			},
+		},
+		{
+			name: "thresholds, minimum and maximum",
+			event: &types.Event{
+				Check: &types.Check{
+					Executed: 12345,
+					Output:   "DISK OK | /=2643MB;5948;5958;0;5968 time=0.1s;@1:2",
+				},
+			},
+			want: NagiosList{
+				Nagios{
+					Label:     "/",
+					Value:     2643,
+					Timestamp: 12345,
+					Warn:      "5948",
+					Crit:      "5958",
+					Min:       "0",
+					Max:       "5968",
+				},
+				Nagios{
+					Label:     "time",
+					Value:     0.1,
+					Timestamp: 12345,
+					Warn:      "@1:2",
 				},
 			},
 		},
@@ -155,6 +190,7 @@ func TestTransformNagios(t *testing.T) {
 		want    []*types.MetricPoint
 	}{
 		{
+			name: "without thresholds",
 			metrics: NagiosList{
 				{
 					Label:     "percent_packet_loss",
@@ -171,6 +207,31 @@ func TestTransformNagios(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with thresholds",
+			metrics: NagiosList{
+				{
+					Label:     "disk",
+					Value:     2643,
+					Timestamp: 123456789,
+					Warn:      "5948",
+					Crit:      "5958",
+					Max:       "5968",
+				},
+			},
+			want: []*types.MetricPoint{
+				{
+					Name:      "disk",
+					Value:     2643,
+					Timestamp: 123456789,
+					Tags: []*types.MetricTag{
+						{Name: "warn", Value: "5948"},
+						{Name: "crit", Value: "5958"},
+						{Name: "max", Value: "5968"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -233,3 +294,45 @@ func TestParseAndTransformNagios(t *testing.T) {
 		})
 	}
 }
+
+func TestNagiosThresholdState(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value float64
+		warn  string
+		crit  string
+		want  string
+	}{
+		{name: "no threshold", value: 10},
+		{name: "invalid threshold", value: 10, warn: "foo"},
+		{name: "within", value: 5, warn: "10", crit: "20", want: NagiosThresholdOK},
+		{name: "above warning", value: 15, warn: "10", crit: "20", want: NagiosThresholdWarning},
+		{name: "above critical", value: 25, warn: "10", crit: "20", want: NagiosThresholdCritical},
+		{name: "below zero", value: -1, warn: "10", want: NagiosThresholdWarning},
+		{name: "below start", value: 5, warn: "10:", want: NagiosThresholdWarning},
+		{name: "negative infinity", value: -100, crit: "~:10", want: NagiosThresholdOK},
+		{name: "outside range", value: 30, crit: "10:20", want: NagiosThresholdCritical},
+		{name: "inside range", value: 15, crit: "@10:20", want: NagiosThresholdCritical},
+		{name: "outside inverted range", value: 5, crit: "@10:20", want: NagiosThresholdOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := Nagios{Label: "metric", Value: tc.value, Warn: tc.warn, Crit: tc.crit}
+			assert.Equal(t, tc.want, n.ThresholdState())
+		})
+	}
+}
+
+func TestNagiosThresholdAnnotations(t *testing.T) {
+	event := &types.Event{
+		Check: &types.Check{
+			Output: "LOAD WARNING | load1=1.5;1;2;0; load5=0.5;1;2;0; users=4",
+		},
+	}
+	annotations := ParseNagios(event).ThresholdAnnotations()
+	assert.Equal(t, map[string]string{
+		NagiosThresholdAnnotationPrefix + "load1": NagiosThresholdWarning,
+		NagiosThresholdAnnotationPrefix + "load5": NagiosThresholdOK,
+	}, annotations)
+}
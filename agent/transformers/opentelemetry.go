@@ -0,0 +1,281 @@
+package transformers
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sensu/sensu-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+// OpenTelemetryList contains a list of OpenTelemetry metric points
+type OpenTelemetryList []OpenTelemetry
+
+// OpenTelemetry contains values of an OpenTelemetry metric point
+type OpenTelemetry struct {
+	Name      string
+	Value     float64
+	TagSet    []*types.MetricTag
+	Timestamp int64
+}
+
+// Transform transforms metrics in OTLP JSON format to Sensu Metric Format
+func (o OpenTelemetryList) Transform() []*types.MetricPoint {
+	var points []*types.MetricPoint
+	for _, metric := range o {
+		mp := &types.MetricPoint{
+			Name:      metric.Name,
+			Value:     metric.Value,
+			Timestamp: metric.Timestamp,
+			Tags:      metric.TagSet,
+		}
+		points = append(points, mp)
+	}
+	return points
+}
+
+// otlpMetricsData is the OTLP JSON encoding of metrics, as written by the
+// OpenTelemetry exporters to files or to the standard output.
+type otlpMetricsData struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+
+	// InstrumentationLibraryMetrics are the scope metrics of the versions of
+	// OTLP older than 0.15
+	InstrumentationLibraryMetrics []otlpScopeMetrics `json:"instrumentationLibraryMetrics"`
+}
+
+type otlpScopeMetrics struct {
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpMetric struct {
+	Name                 string             `json:"name"`
+	Gauge                *otlpNumberData    `json:"gauge"`
+	Sum                  *otlpNumberData    `json:"sum"`
+	Histogram            *otlpHistogramData `json:"histogram"`
+	ExponentialHistogram *otlpHistogramData `json:"exponentialHistogram"`
+	Summary              *otlpSummaryData   `json:"summary"`
+}
+
+type otlpNumberData struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes   []otlpKeyValue `json:"attributes"`
+	TimeUnixNano otlpInt        `json:"timeUnixNano"`
+	AsDouble     *float64       `json:"asDouble"`
+	AsInt        *otlpInt       `json:"asInt"`
+}
+
+type otlpHistogramData struct {
+	DataPoints []otlpHistogramDataPoint `json:"dataPoints"`
+}
+
+type otlpHistogramDataPoint struct {
+	Attributes     []otlpKeyValue `json:"attributes"`
+	TimeUnixNano   otlpInt        `json:"timeUnixNano"`
+	Count          otlpInt        `json:"count"`
+	Sum            *float64       `json:"sum"`
+	BucketCounts   []otlpInt      `json:"bucketCounts"`
+	ExplicitBounds []float64      `json:"explicitBounds"`
+}
+
+type otlpSummaryData struct {
+	DataPoints []otlpSummaryDataPoint `json:"dataPoints"`
+}
+
+type otlpSummaryDataPoint struct {
+	Attributes     []otlpKeyValue `json:"attributes"`
+	TimeUnixNano   otlpInt        `json:"timeUnixNano"`
+	Count          otlpInt        `json:"count"`
+	Sum            float64        `json:"sum"`
+	QuantileValues []struct {
+		Quantile float64 `json:"quantile"`
+		Value    float64 `json:"value"`
+	} `json:"quantileValues"`
+}
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue *string  `json:"stringValue"`
+		BoolValue   *bool    `json:"boolValue"`
+		IntValue    *otlpInt `json:"intValue"`
+		DoubleValue *float64 `json:"doubleValue"`
+	} `json:"value"`
+}
+
+// otlpInt is a 64-bit integer of OTLP JSON, encoded as a string or a number.
+type otlpInt int64
+
+func (i *otlpInt) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		// Unsigned integers, such as the timestamps, may not fit
+		u, uerr := strconv.ParseUint(s, 10, 64)
+		if uerr != nil {
+			return err
+		}
+		v = int64(u)
+	}
+	*i = otlpInt(v)
+	return nil
+}
+
+// otlpTags returns the attributes as metric tags. The array and key-value list
+// attributes are left out.
+func otlpTags(attributes ...[]otlpKeyValue) []*types.MetricTag {
+	tags := []*types.MetricTag{}
+	for _, attrs := range attributes {
+		for _, attr := range attrs {
+			var value string
+			switch v := attr.Value; {
+			case v.StringValue != nil:
+				value = *v.StringValue
+			case v.BoolValue != nil:
+				value = strconv.FormatBool(*v.BoolValue)
+			case v.IntValue != nil:
+				value = strconv.FormatInt(int64(*v.IntValue), 10)
+			case v.DoubleValue != nil:
+				value = strconv.FormatFloat(*v.DoubleValue, 'g', -1, 64)
+			default:
+				continue
+			}
+			tags = append(tags, &types.MetricTag{Name: attr.Key, Value: value})
+		}
+	}
+	return tags
+}
+
+// otlpTimestamp returns the timestamp, in seconds, of a data point, or the
+// current time if the data point has none.
+func otlpTimestamp(nanos otlpInt) int64 {
+	if nanos <= 0 {
+		return time.Now().UTC().Unix()
+	}
+	return int64(nanos) / int64(time.Second)
+}
+
+// withTag returns a copy of the tags with the given tag added.
+func withTag(tags []*types.MetricTag, name, value string) []*types.MetricTag {
+	return append(append(make([]*types.MetricTag, 0, len(tags)+1), tags...), &types.MetricTag{Name: name, Value: value})
+}
+
+// ParseOpenTelemetry parses metrics in OTLP JSON format, with one or more
+// documents, into a list of OpenTelemetry structs. Histograms and summaries
+// are converted to the _count, _sum and _bucket (or quantile) metrics of
+// Prometheus, and exponential histograms to their _count and _sum only. The
+// resource attributes are added to the tags of each metric point.
+func ParseOpenTelemetry(event *types.Event) OpenTelemetryList {
+	var openTelemetryList OpenTelemetryList
+	fields := logrus.Fields{
+		"namespace": event.Check.Namespace,
+		"check":     event.Check.Name,
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(event.Check.Output))
+	for document := 0; ; document++ {
+		fields["document"] = document
+		var data otlpMetricsData
+		if err := decoder.Decode(&data); err == io.EOF {
+			break
+		} else if err != nil {
+			logger.WithFields(fields).WithError(ErrMetricExtraction).Errorf("invalid otlp json metrics: %s", err)
+			break
+		}
+
+		for _, rm := range data.ResourceMetrics {
+			for _, sm := range append(rm.ScopeMetrics, rm.InstrumentationLibraryMetrics...) {
+				for _, metric := range sm.Metrics {
+					openTelemetryList = append(openTelemetryList, otlpPoints(metric, rm.Resource.Attributes)...)
+				}
+			}
+		}
+	}
+
+	return openTelemetryList
+}
+
+// otlpPoints returns the metric points of an OTLP metric.
+func otlpPoints(metric otlpMetric, resource []otlpKeyValue) OpenTelemetryList {
+	var points OpenTelemetryList
+	add := func(name string, value float64, tags []*types.MetricTag, nanos otlpInt) {
+		points = append(points, OpenTelemetry{
+			Name:      name,
+			Value:     value,
+			TagSet:    tags,
+			Timestamp: otlpTimestamp(nanos),
+		})
+	}
+
+	var numbers []otlpNumberDataPoint
+	if metric.Gauge != nil {
+		numbers = append(numbers, metric.Gauge.DataPoints...)
+	}
+	if metric.Sum != nil {
+		numbers = append(numbers, metric.Sum.DataPoints...)
+	}
+	for _, dp := range numbers {
+		tags := otlpTags(resource, dp.Attributes)
+		switch {
+		case dp.AsDouble != nil:
+			add(metric.Name, *dp.AsDouble, tags, dp.TimeUnixNano)
+		case dp.AsInt != nil:
+			add(metric.Name, float64(*dp.AsInt), tags, dp.TimeUnixNano)
+		}
+	}
+
+	var histograms []otlpHistogramDataPoint
+	if metric.Histogram != nil {
+		histograms = append(histograms, metric.Histogram.DataPoints...)
+	}
+	if metric.ExponentialHistogram != nil {
+		for _, dp := range metric.ExponentialHistogram.DataPoints {
+			// The buckets of exponential histograms are not explicit
+			dp.BucketCounts = nil
+			histograms = append(histograms, dp)
+		}
+	}
+	for _, dp := range histograms {
+		tags := otlpTags(resource, dp.Attributes)
+		// The bucket counts of OTLP are not cumulative, unlike Prometheus
+		var cumulative int64
+		for i, count := range dp.BucketCounts {
+			cumulative += int64(count)
+			le := "+Inf"
+			if i < len(dp.ExplicitBounds) {
+				le = strconv.FormatFloat(dp.ExplicitBounds[i], 'g', -1, 64)
+			}
+			add(metric.Name+"_bucket", float64(cumulative), withTag(tags, "le", le), dp.TimeUnixNano)
+		}
+		if dp.Sum != nil {
+			add(metric.Name+"_sum", *dp.Sum, tags, dp.TimeUnixNano)
+		}
+		add(metric.Name+"_count", float64(dp.Count), tags, dp.TimeUnixNano)
+	}
+
+	if metric.Summary != nil {
+		for _, dp := range metric.Summary.DataPoints {
+			tags := otlpTags(resource, dp.Attributes)
+			for _, q := range dp.QuantileValues {
+				add(metric.Name, q.Value, withTag(tags, "quantile", strconv.FormatFloat(q.Quantile, 'g', -1, 64)), dp.TimeUnixNano)
+			}
+			add(metric.Name+"_sum", dp.Sum, tags, dp.TimeUnixNano)
+			add(metric.Name+"_count", float64(dp.Count), tags, dp.TimeUnixNano)
+		}
+	}
+
+	return points
+}
@@ -0,0 +1,108 @@
+package transformers
+
+import (
+	"testing"
+
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const otlpMetrics = `{
+  "resourceMetrics": [{
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "db"}}]},
+    "scopeMetrics": [{
+      "scope": {"name": "exporter"},
+      "metrics": [
+        {
+          "name": "db.connections",
+          "unit": "1",
+          "gauge": {"dataPoints": [
+            {"asInt": "12", "timeUnixNano": "123456789000000000", "attributes": [{"key": "pool", "value": {"stringValue": "main"}}]}
+          ]}
+        },
+        {
+          "name": "db.queries",
+          "sum": {"aggregationTemporality": 2, "isMonotonic": true, "dataPoints": [
+            {"asDouble": 1.5, "timeUnixNano": "123456789000000000", "attributes": [{"key": "primary", "value": {"boolValue": true}}]}
+          ]}
+        },
+        {
+          "name": "db.latency",
+          "histogram": {"dataPoints": [
+            {"count": "3", "sum": 0.6, "bucketCounts": ["1", "2", "0"], "explicitBounds": [0.1, 0.5], "timeUnixNano": "123456789000000000"}
+          ]}
+        },
+        {
+          "name": "db.size",
+          "summary": {"dataPoints": [
+            {"count": "2", "sum": 10, "quantileValues": [{"quantile": 0.5, "value": 4}], "timeUnixNano": "123456789000000000"}
+          ]}
+        }
+      ]
+    }]
+  }]
+}
+{"resourceMetrics": [{"instrumentationLibraryMetrics": [{"metrics": [{"name": "up", "gauge": {"dataPoints": [{"asInt": 1, "timeUnixNano": 123456789000000000}]}}]}]}]}
+`
+
+func TestParseOpenTelemetry(t *testing.T) {
+	service := &types.MetricTag{Name: "service.name", Value: "db"}
+	testCases := []struct {
+		name           string
+		metric         string
+		expectedFormat OpenTelemetryList
+	}{
+		{
+			name:   "metrics",
+			metric: otlpMetrics,
+			expectedFormat: OpenTelemetryList{
+				{Name: "db.connections", Value: 12, Timestamp: 123456789, TagSet: []*types.MetricTag{service, {Name: "pool", Value: "main"}}},
+				{Name: "db.queries", Value: 1.5, Timestamp: 123456789, TagSet: []*types.MetricTag{service, {Name: "primary", Value: "true"}}},
+				{Name: "db.latency_bucket", Value: 1, Timestamp: 123456789, TagSet: []*types.MetricTag{service, {Name: "le", Value: "0.1"}}},
+				{Name: "db.latency_bucket", Value: 3, Timestamp: 123456789, TagSet: []*types.MetricTag{service, {Name: "le", Value: "0.5"}}},
+				{Name: "db.latency_bucket", Value: 3, Timestamp: 123456789, TagSet: []*types.MetricTag{service, {Name: "le", Value: "+Inf"}}},
+				{Name: "db.latency_sum", Value: 0.6, Timestamp: 123456789, TagSet: []*types.MetricTag{service}},
+				{Name: "db.latency_count", Value: 3, Timestamp: 123456789, TagSet: []*types.MetricTag{service}},
+				{Name: "db.size", Value: 4, Timestamp: 123456789, TagSet: []*types.MetricTag{service, {Name: "quantile", Value: "0.5"}}},
+				{Name: "db.size_sum", Value: 10, Timestamp: 123456789, TagSet: []*types.MetricTag{service}},
+				{Name: "db.size_count", Value: 2, Timestamp: 123456789, TagSet: []*types.MetricTag{service}},
+				{Name: "up", Value: 1, Timestamp: 123456789, TagSet: []*types.MetricTag{}},
+			},
+		},
+		{
+			name:           "empty output",
+			metric:         "",
+			expectedFormat: OpenTelemetryList(nil),
+		},
+		{
+			name:           "invalid json",
+			metric:         "db.connections 12 123456789",
+			expectedFormat: OpenTelemetryList(nil),
+		},
+		{
+			name:   "invalid document after a valid one",
+			metric: `{"resourceMetrics": [{"scopeMetrics": [{"metrics": [{"name": "up", "gauge": {"dataPoints": [{"asInt": "1", "timeUnixNano": "123456789000000000"}]}}]}]}]} {"resourceMetrics": "none"}`,
+			expectedFormat: OpenTelemetryList{
+				{Name: "up", Value: 1, Timestamp: 123456789, TagSet: []*types.MetricTag{}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event := types.FixtureEvent("test", "test")
+			event.Check.Output = tc.metric
+			assert.Equal(t, tc.expectedFormat, ParseOpenTelemetry(event))
+		})
+	}
+}
+
+func TestTransformOpenTelemetry(t *testing.T) {
+	metrics := OpenTelemetryList{
+		{Name: "up", Value: 1, Timestamp: 123456789, TagSet: []*types.MetricTag{{Name: "service.name", Value: "db"}}},
+	}
+	expected := []*types.MetricPoint{
+		{Name: "up", Value: 1, Timestamp: 123456789, Tags: []*types.MetricTag{{Name: "service.name", Value: "db"}}},
+	}
+	assert.Equal(t, expected, metrics.Transform())
+}
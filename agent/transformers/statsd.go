@@ -0,0 +1,123 @@
+package transformers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sensu/sensu-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+// StatsdList contains a list of StatsD metrics
+type StatsdList []Statsd
+
+// Statsd contains values of a StatsD metric, with its DogStatsD tags
+type Statsd struct {
+	Name      string
+	Value     float64
+	Type      string
+	TagSet    []*types.MetricTag
+	Timestamp int64
+}
+
+// Transform transforms metrics in StatsD line format to Sensu Metric Format
+func (s StatsdList) Transform() []*types.MetricPoint {
+	var points []*types.MetricPoint
+	for _, metric := range s {
+		mp := &types.MetricPoint{
+			Name:      metric.Name,
+			Value:     metric.Value,
+			Timestamp: metric.Timestamp,
+			Tags:      metric.TagSet,
+		}
+		points = append(points, mp)
+	}
+	return points
+}
+
+// ParseStatsd parses StatsD lines, of the form
+// <name>:<value>|<type>[|@<sample rate>][|#<tag>:<value>,...][|T<timestamp>],
+// into a list of Statsd structs. The tags and timestamp are the DogStatsD
+// extensions of the format. The value of the counters is divided by their
+// sample rate.
+func ParseStatsd(event *types.Event) StatsdList {
+	var statsdList StatsdList
+	fields := logrus.Fields{
+		"namespace": event.Check.Namespace,
+		"check":     event.Check.Name,
+	}
+
+	output := strings.TrimSpace(event.Check.Output)
+	lines := strings.Split(output, "\n")
+
+OUTER:
+	for l, line := range lines {
+		fields["line"] = l
+		line = strings.TrimSpace(line)
+		sep := strings.Index(line, ":")
+		if sep < 1 {
+			logger.WithFields(fields).WithError(ErrMetricExtraction).Errorf("invalid statsd metric, a name is required: %s", line)
+			continue
+		}
+		s := Statsd{Name: line[:sep], TagSet: []*types.MetricTag{}}
+
+		parts := strings.Split(line[sep+1:], "|")
+		if len(parts) < 2 {
+			logger.WithFields(fields).WithError(ErrMetricExtraction).Errorf("invalid statsd metric, a value and a type are required: %s", line)
+			continue
+		}
+		s.Type = parts[1]
+		switch s.Type {
+		case "c", "g", "ms", "h", "d":
+		default:
+			logger.WithFields(fields).WithError(ErrMetricExtraction).Errorf("statsd metric type is not supported: %s", s.Type)
+			continue
+		}
+		f, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			logger.WithFields(fields).WithError(ErrMetricExtraction).Errorf("metric value is invalid, must be a float: %s", parts[0])
+			continue
+		}
+		s.Value = f
+
+		for _, part := range parts[2:] {
+			switch {
+			case strings.HasPrefix(part, "@"):
+				rate, err := strconv.ParseFloat(part[1:], 64)
+				if err != nil || rate <= 0 || rate > 1 {
+					logger.WithFields(fields).WithError(ErrMetricExtraction).Errorf("metric sample rate is invalid, must be a float between 0 and 1: %s", part[1:])
+					continue OUTER
+				}
+				if s.Type == "c" {
+					s.Value /= rate
+				}
+			case strings.HasPrefix(part, "#"):
+				for _, tag := range strings.Split(part[1:], ",") {
+					if tag == "" {
+						continue
+					}
+					kv := strings.SplitN(tag, ":", 2)
+					t := &types.MetricTag{Name: kv[0]}
+					if len(kv) == 2 {
+						t.Value = kv[1]
+					}
+					s.TagSet = append(s.TagSet, t)
+				}
+			case strings.HasPrefix(part, "T"):
+				t, err := strconv.ParseInt(part[1:], 10, 64)
+				if err != nil {
+					logger.WithFields(fields).WithError(ErrMetricExtraction).Errorf("metric timestamp is invalid, must be an int: %s", part[1:])
+					continue OUTER
+				}
+				s.Timestamp = t
+			}
+		}
+		if s.Timestamp == 0 {
+			s.Timestamp = time.Now().UTC().Unix()
+		}
+		statsdList = append(statsdList, s)
+	}
+
+	return statsdList
+}
@@ -0,0 +1,131 @@
+package transformers
+
+import (
+	"testing"
+
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStatsd(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		metric         string
+		expectedFormat StatsdList
+	}{
+		{
+			metric: "page.views:1|c|T123456789",
+			expectedFormat: StatsdList{
+				{
+					Name:      "page.views",
+					Value:     1,
+					Type:      "c",
+					TagSet:    []*types.MetricTag{},
+					Timestamp: 123456789,
+				},
+			},
+		},
+		{
+			metric: "page.views:1|c|@0.5|#env:prod,canary|T123456789\nfuel.level:0.5|g|T123456789\n",
+			expectedFormat: StatsdList{
+				{
+					Name:  "page.views",
+					Value: 2,
+					Type:  "c",
+					TagSet: []*types.MetricTag{
+						{Name: "env", Value: "prod"},
+						{Name: "canary", Value: ""},
+					},
+					Timestamp: 123456789,
+				},
+				{
+					Name:      "fuel.level",
+					Value:     0.5,
+					Type:      "g",
+					TagSet:    []*types.MetricTag{},
+					Timestamp: 123456789,
+				},
+			},
+		},
+		{
+			metric: "request.time:320|ms|@0.1|#url:http://localhost|T123456789",
+			expectedFormat: StatsdList{
+				{
+					Name:      "request.time",
+					Value:     320,
+					Type:      "ms",
+					TagSet:    []*types.MetricTag{{Name: "url", Value: "http://localhost"}},
+					Timestamp: 123456789,
+				},
+			},
+		},
+		{
+			metric:         "",
+			expectedFormat: StatsdList(nil),
+		},
+		{
+			metric:         "page.views|c",
+			expectedFormat: StatsdList(nil),
+		},
+		{
+			metric:         "page.views:1",
+			expectedFormat: StatsdList(nil),
+		},
+		{
+			metric:         "page.views:one|c",
+			expectedFormat: StatsdList(nil),
+		},
+		{
+			metric:         "users.uniques:1234|s",
+			expectedFormat: StatsdList(nil),
+		},
+		{
+			metric:         "page.views:1|c|@2",
+			expectedFormat: StatsdList(nil),
+		},
+		{
+			metric:         "page.views:1|c|Tnoon",
+			expectedFormat: StatsdList(nil),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.metric, func(t *testing.T) {
+			event := types.FixtureEvent("test", "test")
+			event.Check.Output = tc.metric
+			statsd := ParseStatsd(event)
+			assert.Equal(tc.expectedFormat, statsd)
+		})
+	}
+}
+
+func TestParseStatsdDefaultTimestamp(t *testing.T) {
+	event := types.FixtureEvent("test", "test")
+	event.Check.Output = "page.views:1|c"
+	statsd := ParseStatsd(event)
+	if assert.Len(t, statsd, 1) {
+		assert.NotZero(t, statsd[0].Timestamp)
+	}
+}
+
+func TestTransformStatsd(t *testing.T) {
+	statsd := StatsdList{
+		{
+			Name:      "page.views",
+			Value:     1,
+			Type:      "c",
+			TagSet:    []*types.MetricTag{{Name: "env", Value: "prod"}},
+			Timestamp: 123456789,
+		},
+	}
+	expected := []*types.MetricPoint{
+		{
+			Name:      "page.views",
+			Value:     1,
+			Timestamp: 123456789,
+			Tags:      []*types.MetricTag{{Name: "env", Value: "prod"}},
+		},
+	}
+	assert.Equal(t, expected, statsd.Transform())
+}
@@ -0,0 +1,77 @@
+package v2
+
+import "errors"
+
+const (
+	// AgentCommandRequestType is the message type string for an
+	// AgentCommandRequest.
+	AgentCommandRequestType = "agent_command_request"
+
+	// AgentCommandResponseType is the message type string for an
+	// AgentCommandResponse.
+	AgentCommandResponseType = "agent_command_response"
+
+	// AgentCommandCheckName is the name of the check of the events carrying
+	// the results of the commands executed by the agents.
+	AgentCommandCheckName = "remote-command"
+
+	// AgentCommandIDAnnotation is the annotation of the check of the events
+	// carrying the results of the commands, which holds the ID of the command.
+	AgentCommandIDAnnotation = "sensu.io/command-id"
+
+	// DefaultAgentCommandTimeout is the timeout of the commands, in seconds,
+	// when none is given.
+	DefaultAgentCommandTimeout = 60
+)
+
+// AgentCommand is a command to execute on the agent of an entity.
+type AgentCommand struct {
+	// Command is the command to execute. It must match the allow list of the
+	// agent.
+	Command string `json:"command"`
+
+	// Timeout is the timeout of the command, in seconds.
+	Timeout uint32 `json:"timeout,omitempty"`
+}
+
+// Validate returns an error if the command is invalid.
+func (c *AgentCommand) Validate() error {
+	if c.Command == "" {
+		return errors.New("command cannot be empty")
+	}
+	return nil
+}
+
+// AgentCommandRequest is sent by the backend to an agent to execute a
+// command. It is always serialized as JSON.
+type AgentCommandRequest struct {
+	AgentCommand
+
+	// ID identifies the request, and is used to route the response.
+	ID string `json:"id"`
+}
+
+// AgentCommandResponse is sent by an agent in reply to an AgentCommandRequest,
+// once the command is accepted or denied. The result of an accepted command
+// is sent later as an event. It is always serialized as JSON.
+type AgentCommandResponse struct {
+	// ID is the ID of the request.
+	ID string `json:"id"`
+
+	// Error is the reason why the command was denied, if it was.
+	Error string `json:"error,omitempty"`
+}
+
+// AgentCommandResult describes a command accepted by an agent.
+type AgentCommandResult struct {
+	// ID is the ID of the command, found in the AgentCommandIDAnnotation of
+	// the check of its event.
+	ID string `json:"id"`
+
+	// Entity is the name of the entity of the agent.
+	Entity string `json:"entity"`
+
+	// Check is the name of the check of the event carrying the result of the
+	// command.
+	Check string `json:"check"`
+}
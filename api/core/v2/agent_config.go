@@ -0,0 +1,58 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+)
+
+const (
+	// AgentConfigType is the message type string for an AgentConfig pushed by
+	// the backend to an agent.
+	AgentConfigType = "agent_config"
+)
+
+// AgentConfigLogLevels are the log levels of an AgentConfig.
+var AgentConfigLogLevels = []string{"panic", "fatal", "error", "warn", "info", "debug"}
+
+// AgentConfig is the configuration managed by the backend for the agent of an
+// entity. It is pushed to the agent when it connects and whenever it changes,
+// and overrides the configuration of the agent at runtime. The fields left
+// empty fall back to the configuration of the agent. It is always serialized
+// as JSON.
+type AgentConfig struct {
+	// LogLevel is the log level of the agent.
+	LogLevel string `json:"log_level,omitempty"`
+
+	// KeepaliveInterval is the interval between the keepalives of the agent,
+	// in seconds.
+	KeepaliveInterval uint32 `json:"keepalive_interval,omitempty"`
+
+	// Labels are added to the labels of the entity of the agent, and override
+	// the labels of the same keys.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the annotations of the entity of the agent,
+	// and override the annotations of the same keys.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Validate returns an error if the log level is unknown or a label or
+// annotation key is empty.
+func (c *AgentConfig) Validate() error {
+	if c.LogLevel != "" && !utilstrings.InArray(c.LogLevel, AgentConfigLogLevels) {
+		return fmt.Errorf(
+			"invalid log level %q, must be one of: %s",
+			c.LogLevel, strings.Join(AgentConfigLogLevels, ", "),
+		)
+	}
+	if _, ok := c.Labels[""]; ok {
+		return errors.New("label keys must not be empty")
+	}
+	if _, ok := c.Annotations[""]; ok {
+		return errors.New("annotation keys must not be empty")
+	}
+	return nil
+}
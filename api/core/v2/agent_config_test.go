@@ -0,0 +1,49 @@
+package v2
+
+import (
+	"testing"
+)
+
+func TestAgentConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  AgentConfig
+		wantErr bool
+	}{
+		{
+			name:   "empty config",
+			config: AgentConfig{},
+		},
+		{
+			name: "valid config",
+			config: AgentConfig{
+				LogLevel:          "debug",
+				KeepaliveInterval: 10,
+				Labels:            map[string]string{"region": "us-west-2"},
+				Annotations:       map[string]string{"runbook": "https://example.com"},
+			},
+		},
+		{
+			name:    "unknown log level",
+			config:  AgentConfig{LogLevel: "verbose"},
+			wantErr: true,
+		},
+		{
+			name:    "empty label key",
+			config:  AgentConfig{Labels: map[string]string{"": "foo"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty annotation key",
+			config:  AgentConfig{Annotations: map[string]string{"": "foo"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("AgentConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package v2
+
+import "fmt"
+
+const (
+	// AgentSpoolRequestType is the message type string for an
+	// AgentSpoolRequest.
+	AgentSpoolRequestType = "agent_spool_request"
+
+	// AgentSpoolResponseType is the message type string for an
+	// AgentSpoolResponse.
+	AgentSpoolResponseType = "agent_spool_response"
+
+	// AgentSpoolInfo is the spool action that only returns the spool
+	// statistics.
+	AgentSpoolInfo = "info"
+
+	// AgentSpoolFlush is the spool action that transmits the spooled events
+	// without rate limiting.
+	AgentSpoolFlush = "flush"
+
+	// AgentSpoolPurge is the spool action that discards the spooled events.
+	AgentSpoolPurge = "purge"
+)
+
+// AgentSpoolStats contains statistics about the spool of an agent, where the
+// events received by the agent API are buffered until they are transmitted to
+// the backend.
+type AgentSpoolStats struct {
+	// Messages is the number of events in the spool.
+	Messages int `json:"messages"`
+
+	// Bytes is the size of the events in the spool, once compressed.
+	Bytes int `json:"bytes"`
+
+	// Flushing is the number of events remaining to be transmitted without
+	// rate limiting, following a flush of the spool.
+	Flushing int `json:"flushing"`
+
+	// Purged is the number of events discarded by a purge of the spool.
+	Purged int `json:"purged,omitempty"`
+}
+
+// AgentSpoolRequest is sent by the backend to an agent to inspect or manage
+// its spool. It is always serialized as JSON.
+type AgentSpoolRequest struct {
+	// ID identifies the request, and is used to route the response.
+	ID string `json:"id"`
+
+	// Action is the spool action to perform.
+	Action string `json:"action"`
+}
+
+// AgentSpoolResponse is sent by an agent in reply to an AgentSpoolRequest. It
+// is always serialized as JSON.
+type AgentSpoolResponse struct {
+	// ID is the ID of the request.
+	ID string `json:"id"`
+
+	// Stats are the spool statistics, after the action was performed.
+	Stats AgentSpoolStats `json:"stats"`
+
+	// Error is the error encountered while performing the action, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// ValidateAgentSpoolAction returns an error if the action is not a valid
+// spool action.
+func ValidateAgentSpoolAction(action string) error {
+	switch action {
+	case AgentSpoolInfo, AgentSpoolFlush, AgentSpoolPurge:
+		return nil
+	}
+	return fmt.Errorf("invalid spool action %q", action)
+}
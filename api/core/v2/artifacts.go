@@ -0,0 +1,21 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// ValidateOutputArtifacts ensures that the output artifacts of a check are
+// non-empty paths or well-formed glob patterns.
+func ValidateOutputArtifacts(artifacts []string) error {
+	for _, artifact := range artifacts {
+		if artifact == "" {
+			return errors.New("output artifacts must not be empty")
+		}
+		if _, err := filepath.Match(artifact, ""); err != nil {
+			return fmt.Errorf("invalid output artifact %q: %s", artifact, err)
+		}
+	}
+	return nil
+}
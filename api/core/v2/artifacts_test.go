@@ -0,0 +1,14 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOutputArtifacts(t *testing.T) {
+	assert.NoError(t, ValidateOutputArtifacts(nil))
+	assert.NoError(t, ValidateOutputArtifacts([]string{"/tmp/heap.hprof", "/var/tmp/traceroute-*.txt"}))
+	assert.Error(t, ValidateOutputArtifacts([]string{""}))
+	assert.Error(t, ValidateOutputArtifacts([]string{"/tmp/[heap"}))
+}
@@ -4,12 +4,14 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"net/url"
 	"path"
 	"regexp"
 	"strings"
 
 	"github.com/sensu/sensu-go/js"
+	"github.com/sensu/sensu-go/types/dynamic"
 )
 
 const (
@@ -45,19 +47,44 @@ func (a *Asset) Validate() error {
 		return errors.New("namespace cannot be empty")
 	}
 
-	if a.Sha512 == "" {
+	if len(a.Builds) > 0 {
+		if a.URL != "" || a.Sha512 != "" {
+			return errors.New("URL and SHA-512 checksum must be empty when the asset has builds")
+		}
+		for i, build := range a.Builds {
+			if build == nil {
+				return fmt.Errorf("build %d cannot be empty", i)
+			}
+			if err := validateAssetSource(build.URL, build.Sha512); err != nil {
+				return fmt.Errorf("build %d: %s", i, err)
+			}
+			if err := js.ParseExpressions(build.Filters); err != nil {
+				return fmt.Errorf("build %d: %s", i, err)
+			}
+		}
+	} else if err := validateAssetSource(a.URL, a.Sha512); err != nil {
+		return err
+	}
+
+	return js.ParseExpressions(a.Filters)
+}
+
+// validateAssetSource returns an error if the URL or the checksum of an asset
+// or of one of its builds is invalid.
+func validateAssetSource(assetURL, sha512 string) error {
+	if sha512 == "" {
 		return errors.New("SHA-512 checksum cannot be empty")
 	}
 
-	if len(a.Sha512) < 128 {
+	if len(sha512) < 128 {
 		return errors.New("SHA-512 checksum must be at least 128 characters")
 	}
 
-	if a.URL == "" {
+	if assetURL == "" {
 		return errors.New("URL cannot be empty")
 	}
 
-	u, err := url.Parse(a.URL)
+	u, err := url.Parse(assetURL)
 	if err != nil {
 		return errors.New("invalid URL provided")
 	}
@@ -66,7 +93,63 @@ func (a *Asset) Validate() error {
 		return errors.New("URL must be HTTP or HTTPS")
 	}
 
-	return js.ParseExpressions(a.Filters)
+	return nil
+}
+
+// SelectBuild returns the asset with the URL, the checksum and the headers of
+// the first of its builds whose filters match the entity. The asset is
+// returned as is if it has no builds, and nil if none of them matches. The
+// filters of the asset itself are left to the caller.
+func (a *Asset) SelectBuild(entity *Entity) (*Asset, error) {
+	if len(a.Builds) == 0 {
+		return a, nil
+	}
+	for _, build := range a.Builds {
+		if build == nil {
+			continue
+		}
+		match, err := MatchAssetFilters(build.Filters, entity)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+		selected := *a
+		selected.URL = build.URL
+		selected.Sha512 = build.Sha512
+		selected.Builds = nil
+		if len(build.Headers) > 0 {
+			selected.Headers = make(map[string]string, len(a.Headers)+len(build.Headers))
+			for k, v := range a.Headers {
+				selected.Headers[k] = v
+			}
+			for k, v := range build.Headers {
+				selected.Headers[k] = v
+			}
+		}
+		return &selected, nil
+	}
+	return nil, nil
+}
+
+// MatchAssetFilters returns true if all the asset filters match the entity,
+// whose attributes are exposed as entity, e.g. entity.system.arch. Agents and
+// backends evaluate the asset filters with it.
+func MatchAssetFilters(filters []string, entity *Entity) (bool, error) {
+	if len(filters) == 0 {
+		return true, nil
+	}
+
+	params := map[string]interface{}{"entity": dynamic.Synthesize(entity)}
+	for _, filter := range filters {
+		result, err := js.Evaluate(filter, params, nil)
+		if err != nil || !result {
+			return false, err
+		}
+	}
+
+	return true, nil
 }
 
 // ValidateAssetName validates that asset's name is valid
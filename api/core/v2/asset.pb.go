@@ -38,10 +38,15 @@ type Asset struct {
 	ObjectMeta `protobuf:"bytes,8,opt,name=metadata,proto3,embedded=metadata" json:"metadata,omitempty"`
 	// Headers is a collection of key/value string pairs used as HTTP headers
 	// for asset retrieval.
-	Headers              map[string]string `protobuf:"bytes,9,rep,name=headers,proto3" json:"headers" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Headers map[string]string `protobuf:"bytes,9,rep,name=headers,proto3" json:"headers" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Builds are the builds of the asset for different platforms. The first
+	// build whose filters match the entity is installed, in addition to the
+	// filters of the asset. The URL and the checksum of the asset are left
+	// empty when it has builds.
+	Builds               []*AssetBuild `protobuf:"bytes,10,rep,name=builds,proto3" json:"builds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *Asset) Reset()         { *m = Asset{} }
@@ -77,38 +82,124 @@ func (m *Asset) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Asset proto.InternalMessageInfo
 
+// AssetBuild is a build of an asset for a platform.
+type AssetBuild struct {
+	// URL is the location of the build
+	URL string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Sha512 is the SHA-512 checksum of the build
+	Sha512 string `protobuf:"bytes,2,opt,name=sha512,proto3" json:"sha512,omitempty"`
+	// Filters are the sensu queries the entity must match for the build to be
+	// installed, e.g. entity.system.libc_type == 'musl'. If more than one
+	// filter is present the queries are joined by the "AND" operator.
+	Filters []string `protobuf:"bytes,3,rep,name=filters,proto3" json:"filters"`
+	// Headers are the HTTP headers of the build retrieval, in addition to the
+	// headers of the asset.
+	Headers              map[string]string `protobuf:"bytes,4,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *AssetBuild) Reset()         { *m = AssetBuild{} }
+func (m *AssetBuild) String() string { return proto.CompactTextString(m) }
+func (*AssetBuild) ProtoMessage()    {}
+func (*AssetBuild) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4785e5163229d617, []int{1}
+}
+func (m *AssetBuild) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AssetBuild) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AssetBuild.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AssetBuild) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssetBuild.Merge(m, src)
+}
+func (m *AssetBuild) XXX_Size() int {
+	return m.Size()
+}
+func (m *AssetBuild) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssetBuild.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssetBuild proto.InternalMessageInfo
+
+func (m *AssetBuild) GetURL() string {
+	if m != nil {
+		return m.URL
+	}
+	return ""
+}
+
+func (m *AssetBuild) GetSha512() string {
+	if m != nil {
+		return m.Sha512
+	}
+	return ""
+}
+
+func (m *AssetBuild) GetFilters() []string {
+	if m != nil {
+		return m.Filters
+	}
+	return nil
+}
+
+func (m *AssetBuild) GetHeaders() map[string]string {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Asset)(nil), "sensu.core.v2.Asset")
 	proto.RegisterMapType((map[string]string)(nil), "sensu.core.v2.Asset.HeadersEntry")
+	proto.RegisterType((*AssetBuild)(nil), "sensu.core.v2.AssetBuild")
+	proto.RegisterMapType((map[string]string)(nil), "sensu.core.v2.AssetBuild.HeadersEntry")
 }
 
 func init() { proto.RegisterFile("asset.proto", fileDescriptor_4785e5163229d617) }
 
 var fileDescriptor_4785e5163229d617 = []byte{
-	// 359 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x50, 0x4d, 0x4a, 0xc3, 0x40,
-	0x18, 0xed, 0x24, 0xf4, 0x6f, 0xa2, 0x20, 0x83, 0x48, 0xda, 0xc5, 0x24, 0x0a, 0x42, 0x16, 0x32,
-	0xa5, 0x51, 0x41, 0xba, 0xd2, 0x80, 0xd0, 0x85, 0x22, 0x04, 0xba, 0x71, 0x37, 0x69, 0xa7, 0x3f,
-	0xda, 0x74, 0x4a, 0x32, 0x09, 0xf4, 0x06, 0x1e, 0xc1, 0x65, 0x97, 0x3d, 0x82, 0x47, 0xe8, 0xb2,
-	0x27, 0x08, 0x1a, 0x77, 0x3d, 0x81, 0x4b, 0xc9, 0xa4, 0x11, 0x75, 0xf7, 0xbe, 0x37, 0xef, 0x7b,
-	0x6f, 0xde, 0x07, 0x35, 0x1a, 0x86, 0x4c, 0x90, 0x79, 0xc0, 0x05, 0x47, 0xfb, 0x21, 0x9b, 0x85,
-	0x11, 0xe9, 0xf3, 0x80, 0x91, 0xd8, 0x6e, 0x5e, 0x8c, 0x26, 0x62, 0x1c, 0x79, 0xa4, 0xcf, 0xfd,
-	0xd6, 0x88, 0x8f, 0x78, 0x4b, 0xaa, 0xbc, 0x68, 0x78, 0x1d, 0xb7, 0x89, 0x4d, 0xda, 0x92, 0x94,
-	0x9c, 0x44, 0xb9, 0x49, 0x13, 0xfa, 0x4c, 0xd0, 0x1c, 0x9f, 0xac, 0x15, 0x58, 0xbe, 0xc9, 0x02,
-	0x50, 0x03, 0xaa, 0x51, 0x30, 0xd5, 0x15, 0x13, 0x58, 0x75, 0xa7, 0x9a, 0x26, 0x86, 0xda, 0x73,
-	0xef, 0xdc, 0x8c, 0x43, 0x47, 0xb0, 0x12, 0x8e, 0xe9, 0x65, 0xdb, 0xd6, 0xd5, 0xec, 0xd5, 0xdd,
-	0x4d, 0xe8, 0x14, 0x56, 0x87, 0x93, 0xa9, 0x60, 0x41, 0xa8, 0x97, 0x4d, 0xd5, 0xaa, 0x3b, 0xda,
-	0x36, 0x31, 0x0a, 0xca, 0x2d, 0x00, 0xea, 0xc1, 0x5a, 0x96, 0x38, 0xa0, 0x82, 0xea, 0x35, 0x13,
-	0x58, 0x9a, 0xdd, 0x20, 0x7f, 0x7a, 0x90, 0x07, 0xef, 0x89, 0xf5, 0xc5, 0x3d, 0x13, 0xd4, 0xc1,
-	0xeb, 0xc4, 0x28, 0x6d, 0x12, 0x03, 0x6c, 0x13, 0x03, 0x15, 0x6b, 0x67, 0xdc, 0x9f, 0x08, 0xe6,
-	0xcf, 0xc5, 0xc2, 0xfd, 0xb1, 0x42, 0x5d, 0x58, 0x1d, 0x33, 0x3a, 0xc8, 0xd2, 0xeb, 0xa6, 0x6a,
-	0x69, 0xf6, 0xf1, 0x3f, 0x57, 0xd9, 0x8b, 0x74, 0x73, 0xcd, 0xed, 0x4c, 0x04, 0x8b, 0xfc, 0x83,
-	0xbb, 0x2d, 0xb7, 0x00, 0xcd, 0x0e, 0xdc, 0xfb, 0xad, 0x42, 0x07, 0x50, 0x7d, 0x66, 0x0b, 0x1d,
-	0xc8, 0xb2, 0x19, 0x44, 0x87, 0xb0, 0x1c, 0xd3, 0x69, 0xc4, 0xf2, 0xf3, 0xb8, 0xf9, 0xd0, 0x51,
-	0xae, 0x40, 0xa7, 0xf6, 0xb2, 0x34, 0x4a, 0xab, 0xa5, 0x01, 0x1c, 0xf3, 0xeb, 0x03, 0x83, 0x55,
-	0x8a, 0xc1, 0x5b, 0x8a, 0xc1, 0x3a, 0xc5, 0x60, 0x93, 0x62, 0xf0, 0x9e, 0x62, 0xf0, 0xfa, 0x89,
-	0x4b, 0x8f, 0x4a, 0x6c, 0x7b, 0x15, 0x79, 0xf3, 0xf3, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd9,
-	0x23, 0xf4, 0x0a, 0xd3, 0x01, 0x00, 0x00,
+	// 444 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x52, 0xcb, 0x8a, 0xd4, 0x40,
+	0x14, 0xed, 0x4a, 0xd9, 0xaf, 0x8a, 0xc2, 0x58, 0x8c, 0x92, 0xe9, 0x45, 0x55, 0x1c, 0x50, 0xb2,
+	0x90, 0x1a, 0x3a, 0x2a, 0x48, 0xaf, 0x34, 0x20, 0xce, 0x42, 0x11, 0x02, 0x83, 0xe0, 0xae, 0xd2,
+	0x5d, 0xd3, 0x1d, 0x4d, 0x26, 0x43, 0x52, 0x09, 0xf4, 0x1f, 0xb8, 0x75, 0xe7, 0x72, 0x96, 0xf3,
+	0x09, 0x7e, 0xc2, 0x2c, 0xe7, 0x0b, 0x82, 0xc6, 0x5d, 0xbe, 0x40, 0x70, 0x23, 0xa9, 0x24, 0x76,
+	0xb7, 0xd8, 0xbd, 0x99, 0xdd, 0xa9, 0x9b, 0x7b, 0xcf, 0xb9, 0xf7, 0x9c, 0x20, 0x9d, 0x27, 0x89,
+	0x90, 0xec, 0x3c, 0x8e, 0x64, 0x84, 0xef, 0x24, 0xe2, 0x2c, 0x49, 0xd9, 0x34, 0x8a, 0x05, 0xcb,
+	0xec, 0xd1, 0xd3, 0xb9, 0x2f, 0x17, 0xa9, 0xc7, 0xa6, 0x51, 0x78, 0x34, 0x8f, 0xe6, 0xd1, 0x91,
+	0xea, 0xf2, 0xd2, 0xd3, 0x17, 0xd9, 0x98, 0xd9, 0x6c, 0xac, 0x8a, 0xaa, 0xa6, 0x50, 0x4d, 0x32,
+	0x42, 0xa1, 0x90, 0xbc, 0xc6, 0x87, 0x5f, 0x20, 0xea, 0xbe, 0xac, 0x04, 0xf0, 0x01, 0x82, 0x69,
+	0x1c, 0x18, 0x9a, 0x09, 0xac, 0xa1, 0xd3, 0x2f, 0x72, 0x0a, 0x4f, 0xdc, 0x37, 0x6e, 0x55, 0xc3,
+	0xf7, 0x51, 0x2f, 0x59, 0xf0, 0x67, 0x63, 0xdb, 0x80, 0xd5, 0x57, 0xb7, 0x79, 0xe1, 0x87, 0xa8,
+	0x7f, 0xea, 0x07, 0x52, 0xc4, 0x89, 0xd1, 0x35, 0xa1, 0x35, 0x74, 0xf4, 0x32, 0xa7, 0x6d, 0xc9,
+	0x6d, 0x01, 0x3e, 0x41, 0x83, 0x4a, 0x71, 0xc6, 0x25, 0x37, 0x06, 0x26, 0xb0, 0x74, 0xfb, 0x80,
+	0x6d, 0xdc, 0xc1, 0xde, 0x79, 0x1f, 0xc5, 0x54, 0xbe, 0x15, 0x92, 0x3b, 0xe4, 0x2a, 0xa7, 0x9d,
+	0xeb, 0x9c, 0x82, 0x32, 0xa7, 0xb8, 0x1d, 0x7b, 0x1c, 0x85, 0xbe, 0x14, 0xe1, 0xb9, 0x5c, 0xba,
+	0x7f, 0xa9, 0xf0, 0x31, 0xea, 0x2f, 0x04, 0x9f, 0x55, 0xea, 0x43, 0x13, 0x5a, 0xba, 0xfd, 0xe0,
+	0x1f, 0x56, 0x75, 0x17, 0x3b, 0xae, 0x7b, 0x5e, 0x9d, 0xc9, 0x78, 0x59, 0x2f, 0xd8, 0x4c, 0xb9,
+	0x2d, 0xc0, 0xaf, 0x51, 0xcf, 0x4b, 0xfd, 0x60, 0x96, 0x18, 0xc8, 0x84, 0xff, 0x59, 0x4f, 0x11,
+	0x39, 0x55, 0x87, 0xb3, 0x5f, 0xe6, 0x74, 0xaf, 0x6e, 0x5e, 0x5b, 0xaa, 0x19, 0x1f, 0x4d, 0xd0,
+	0xed, 0x75, 0x39, 0xbc, 0x87, 0xe0, 0x27, 0xb1, 0x34, 0x80, 0x72, 0xad, 0x82, 0x78, 0x1f, 0x75,
+	0x33, 0x1e, 0xa4, 0xa2, 0xf6, 0xd9, 0xad, 0x1f, 0x13, 0xed, 0x39, 0x98, 0x0c, 0x3e, 0x5f, 0xd0,
+	0xce, 0xe5, 0x05, 0x05, 0x87, 0xbf, 0x01, 0x42, 0x2b, 0xc9, 0x36, 0x18, 0xb0, 0x33, 0x18, 0x6d,
+	0x5b, 0x30, 0x70, 0x47, 0x30, 0xef, 0x57, 0x0e, 0xde, 0x52, 0x87, 0x3f, 0xda, 0x7a, 0xf8, 0xa6,
+	0x8d, 0xf7, 0xca, 0x9c, 0xde, 0x6d, 0x46, 0xd7, 0x6c, 0x68, 0xd9, 0x6e, 0xe2, 0x83, 0x63, 0xfe,
+	0xfa, 0x41, 0xc0, 0x65, 0x41, 0xc0, 0xb7, 0x82, 0x80, 0xab, 0x82, 0x80, 0xeb, 0x82, 0x80, 0xef,
+	0x05, 0x01, 0x5f, 0x7f, 0x92, 0xce, 0x07, 0x2d, 0xb3, 0xbd, 0x9e, 0xfa, 0x75, 0x9f, 0xfc, 0x09,
+	0x00, 0x00, 0xff, 0xff, 0xb3, 0xb6, 0xc7, 0xc7, 0x1a, 0x03, 0x00, 0x00,
 }
 
 func (this *Asset) Equal(that interface{}) bool {
@@ -155,6 +246,60 @@ func (this *Asset) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.Builds) != len(that1.Builds) {
+		return false
+	}
+	for i := range this.Builds {
+		if !this.Builds[i].Equal(that1.Builds[i]) {
+			return false
+		}
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *AssetBuild) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AssetBuild)
+	if !ok {
+		that2, ok := that.(AssetBuild)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.URL != that1.URL {
+		return false
+	}
+	if this.Sha512 != that1.Sha512 {
+		return false
+	}
+	if len(this.Filters) != len(that1.Filters) {
+		return false
+	}
+	for i := range this.Filters {
+		if this.Filters[i] != that1.Filters[i] {
+			return false
+		}
+	}
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if this.Headers[i] != that1.Headers[i] {
+			return false
+		}
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -168,6 +313,7 @@ type AssetFace interface {
 	GetFilters() []string
 	GetObjectMeta() ObjectMeta
 	GetHeaders() map[string]string
+	GetBuilds() []*AssetBuild
 }
 
 func (this *Asset) Proto() github_com_golang_protobuf_proto.Message {
@@ -198,6 +344,10 @@ func (this *Asset) GetHeaders() map[string]string {
 	return this.Headers
 }
 
+func (this *Asset) GetBuilds() []*AssetBuild {
+	return this.Builds
+}
+
 func NewAssetFromFace(that AssetFace) *Asset {
 	this := &Asset{}
 	this.URL = that.GetURL()
@@ -205,6 +355,7 @@ func NewAssetFromFace(that AssetFace) *Asset {
 	this.Filters = that.GetFilters()
 	this.ObjectMeta = that.GetObjectMeta()
 	this.Headers = that.GetHeaders()
+	this.Builds = that.GetBuilds()
 	return this
 }
 
@@ -275,6 +426,83 @@ func (m *Asset) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], v)
 		}
 	}
+	if len(m.Builds) > 0 {
+		for _, msg := range m.Builds {
+			dAtA[i] = 0x52
+			i++
+			i = encodeVarintAsset(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *AssetBuild) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AssetBuild) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.URL) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintAsset(dAtA, i, uint64(len(m.URL)))
+		i += copy(dAtA[i:], m.URL)
+	}
+	if len(m.Sha512) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintAsset(dAtA, i, uint64(len(m.Sha512)))
+		i += copy(dAtA[i:], m.Sha512)
+	}
+	if len(m.Filters) > 0 {
+		for _, s := range m.Filters {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Headers) > 0 {
+		for k, _ := range m.Headers {
+			dAtA[i] = 0x22
+			i++
+			v := m.Headers[k]
+			mapSize := 1 + len(k) + sovAsset(uint64(len(k))) + 1 + len(v) + sovAsset(uint64(len(v)))
+			i = encodeVarintAsset(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintAsset(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintAsset(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -308,8 +536,37 @@ func NewPopulatedAsset(r randyAsset, easy bool) *Asset {
 			this.Headers[randStringAsset(r)] = randStringAsset(r)
 		}
 	}
+	if r.Intn(10) != 0 {
+		v4 := r.Intn(5)
+		this.Builds = make([]*AssetBuild, v4)
+		for i := 0; i < v4; i++ {
+			this.Builds[i] = NewPopulatedAssetBuild(r, easy)
+		}
+	}
+	if !easy && r.Intn(10) != 0 {
+		this.XXX_unrecognized = randUnrecognizedAsset(r, 11)
+	}
+	return this
+}
+
+func NewPopulatedAssetBuild(r randyAsset, easy bool) *AssetBuild {
+	this := &AssetBuild{}
+	this.URL = string(randStringAsset(r))
+	this.Sha512 = string(randStringAsset(r))
+	v5 := r.Intn(10)
+	this.Filters = make([]string, v5)
+	for i := 0; i < v5; i++ {
+		this.Filters[i] = string(randStringAsset(r))
+	}
+	if r.Intn(10) != 0 {
+		v6 := r.Intn(10)
+		this.Headers = make(map[string]string)
+		for i := 0; i < v6; i++ {
+			this.Headers[randStringAsset(r)] = randStringAsset(r)
+		}
+	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedAsset(r, 10)
+		this.XXX_unrecognized = randUnrecognizedAsset(r, 5)
 	}
 	return this
 }
@@ -333,9 +590,9 @@ func randUTF8RuneAsset(r randyAsset) rune {
 	return rune(ru + 61)
 }
 func randStringAsset(r randyAsset) string {
-	v4 := r.Intn(100)
-	tmps := make([]rune, v4)
-	for i := 0; i < v4; i++ {
+	v7 := r.Intn(100)
+	tmps := make([]rune, v7)
+	for i := 0; i < v7; i++ {
 		tmps[i] = randUTF8RuneAsset(r)
 	}
 	return string(tmps)
@@ -357,11 +614,11 @@ func randFieldAsset(dAtA []byte, r randyAsset, fieldNumber int, wire int) []byte
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateAsset(dAtA, uint64(key))
-		v5 := r.Int63()
+		v8 := r.Int63()
 		if r.Intn(2) == 0 {
-			v5 *= -1
+			v8 *= -1
 		}
-		dAtA = encodeVarintPopulateAsset(dAtA, uint64(v5))
+		dAtA = encodeVarintPopulateAsset(dAtA, uint64(v8))
 	case 1:
 		dAtA = encodeVarintPopulateAsset(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -416,6 +673,46 @@ func (m *Asset) Size() (n int) {
 			n += mapEntrySize + 1 + sovAsset(uint64(mapEntrySize))
 		}
 	}
+	if len(m.Builds) > 0 {
+		for _, e := range m.Builds {
+			l = e.Size()
+			n += 1 + l + sovAsset(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *AssetBuild) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.URL)
+	if l > 0 {
+		n += 1 + l + sovAsset(uint64(l))
+	}
+	l = len(m.Sha512)
+	if l > 0 {
+		n += 1 + l + sovAsset(uint64(l))
+	}
+	if len(m.Filters) > 0 {
+		for _, s := range m.Filters {
+			l = len(s)
+			n += 1 + l + sovAsset(uint64(l))
+		}
+	}
+	if len(m.Headers) > 0 {
+		for k, v := range m.Headers {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovAsset(uint64(len(k))) + 1 + len(v) + sovAsset(uint64(len(v)))
+			n += mapEntrySize + 1 + sovAsset(uint64(mapEntrySize))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -720,6 +1017,317 @@ func (m *Asset) Unmarshal(dAtA []byte) error {
 			}
 			m.Headers[mapkey] = mapvalue
 			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Builds", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAsset
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAsset
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAsset
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Builds = append(m.Builds, &AssetBuild{})
+			if err := m.Builds[len(m.Builds)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAsset(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAsset
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthAsset
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AssetBuild) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAsset
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AssetBuild: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AssetBuild: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field URL", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAsset
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAsset
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAsset
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.URL = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sha512", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAsset
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAsset
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAsset
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sha512 = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Filters", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAsset
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAsset
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAsset
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Filters = append(m.Filters, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAsset
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAsset
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAsset
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Headers == nil {
+				m.Headers = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAsset
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowAsset
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthAsset
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthAsset
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowAsset
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthAsset
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthAsset
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipAsset(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthAsset
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Headers[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAsset(dAtA[iNdEx:])
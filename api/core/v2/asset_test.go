@@ -58,4 +58,86 @@ func TestValidator(t *testing.T) {
 	asset = FixtureAsset("name")
 	asset.Sha512 = "nope"
 	assert.Error(asset.Validate())
+
+	// Given asset with valid builds it should pass
+	asset = FixtureAsset("name")
+	build := &AssetBuild{URL: asset.URL, Sha512: asset.Sha512, Filters: []string{"entity.system.fips_enabled"}}
+	asset.URL, asset.Sha512 = "", ""
+	asset.Builds = []*AssetBuild{build}
+	assert.NoError(asset.Validate())
+
+	// Given asset with builds and a URL it should not pass
+	asset.URL = build.URL
+	assert.Error(asset.Validate())
+
+	// Given asset with an invalid build it should not pass
+	asset.URL = ""
+	asset.Builds = []*AssetBuild{{URL: build.URL, Sha512: "nope"}}
+	assert.Error(asset.Validate())
+
+	// Given asset with invalid build filters it should not pass
+	asset.Builds = []*AssetBuild{{URL: build.URL, Sha512: build.Sha512, Filters: []string{"entity.system.arch ==="}}}
+	assert.Error(asset.Validate())
+}
+
+func TestAssetSelectBuild(t *testing.T) {
+	entity := FixtureEntity("entity")
+	entity.System.Arch = "arm64"
+	entity.System.LibCType = "musl"
+	entity.Labels = map[string]string{"region": "eu"}
+
+	asset := FixtureAsset("asset")
+	asset.Headers = map[string]string{"Authorization": "token", "X-Region": "us"}
+
+	// Given asset without builds it should be selected as is
+	selected, err := asset.SelectBuild(entity)
+	assert.NoError(t, err)
+	assert.Equal(t, asset, selected)
+
+	asset.URL, asset.Sha512 = "", ""
+	asset.Builds = []*AssetBuild{
+		{URL: "https://example.com/glibc.tar.gz", Sha512: "glibc", Filters: []string{"entity.system.libc_type == 'glibc'"}},
+		{
+			URL:     "https://example.com/musl-arm64.tar.gz",
+			Sha512:  "musl-arm64",
+			Filters: []string{"entity.system.libc_type == 'musl'", "entity.system.arch == 'arm64'", "entity.labels.region == 'eu'"},
+			Headers: map[string]string{"X-Region": "eu"},
+		},
+		{URL: "https://example.com/any.tar.gz", Sha512: "any"},
+	}
+
+	// Given asset with builds the first matching build should be selected
+	selected, err = asset.SelectBuild(entity)
+	assert.NoError(t, err)
+	if assert.NotNil(t, selected) {
+		assert.Equal(t, "https://example.com/musl-arm64.tar.gz", selected.URL)
+		assert.Equal(t, "musl-arm64", selected.Sha512)
+		assert.Empty(t, selected.Builds)
+		assert.Equal(t, map[string]string{"Authorization": "token", "X-Region": "eu"}, selected.Headers)
+	}
+	assert.Equal(t, map[string]string{"Authorization": "token", "X-Region": "us"}, asset.Headers)
+	assert.Len(t, asset.Builds, 3)
+
+	// Given asset without matching build it should not be selected
+	asset.Builds = asset.Builds[:1]
+	selected, err = asset.SelectBuild(entity)
+	assert.NoError(t, err)
+	assert.Nil(t, selected)
+}
+
+func TestMatchAssetFilters(t *testing.T) {
+	entity := FixtureEntity("entity")
+	entity.System.FIPSEnabled = true
+
+	match, err := MatchAssetFilters(nil, entity)
+	assert.NoError(t, err)
+	assert.True(t, match)
+
+	match, err = MatchAssetFilters([]string{"entity.system.fips_enabled", "entity.name == 'entity'"}, entity)
+	assert.NoError(t, err)
+	assert.True(t, match)
+
+	match, err = MatchAssetFilters([]string{"entity.system.fips_enabled", "entity.name == 'other'"}, entity)
+	assert.NoError(t, err)
+	assert.False(t, match)
 }
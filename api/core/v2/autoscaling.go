@@ -0,0 +1,20 @@
+package v2
+
+// AutoscalingSignals holds the load signals of a backend, used to autoscale
+// the backends, e.g. with the Kubernetes horizontal pod autoscaler or KEDA.
+type AutoscalingSignals struct {
+	// AgentSessions is the number of agent sessions on the backend.
+	AgentSessions int64 `json:"agent_sessions"`
+
+	// EventQueueDepth is the number of events waiting to be processed by the
+	// backend.
+	EventQueueDepth int64 `json:"event_queue_depth"`
+
+	// EventQueueUtilization is the ratio, between 0 and 1, of the event queue
+	// of the backend in use.
+	EventQueueUtilization float64 `json:"event_queue_utilization"`
+
+	// SchedulingLagSeconds is the last scheduling latency of check results,
+	// i.e. the time between the issue of check requests and their execution.
+	SchedulingLagSeconds float64 `json:"scheduling_lag_seconds"`
+}
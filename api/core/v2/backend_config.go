@@ -0,0 +1,34 @@
+package v2
+
+const (
+	// SettingSourceDefault is the source of the settings left to their
+	// default value
+	SettingSourceDefault = "default"
+	// SettingSourceFile is the source of the settings read from the
+	// configuration file
+	SettingSourceFile = "file"
+	// SettingSourceFlag is the source of the settings given on the command line
+	SettingSourceFlag = "flag"
+)
+
+// BackendConfig describes the effective configuration of a backend.
+type BackendConfig struct {
+	// ConfigFile is the path of the configuration file read by the backend, if
+	// any.
+	ConfigFile string `json:"config_file,omitempty"`
+	// Settings are the settings of the backend, sorted by name.
+	Settings []*BackendSetting `json:"settings"`
+}
+
+// BackendSetting is the effective value of a setting of the backend.
+type BackendSetting struct {
+	// Name is the name of the setting, as a flag or configuration file key.
+	Name string `json:"name"`
+	// Value is the value of the setting, or Redacted if sensitive.
+	Value interface{} `json:"value"`
+	// Source is the source of the value: SettingSourceDefault,
+	// SettingSourceFile or SettingSourceFlag.
+	Source string `json:"source"`
+	// Redacted is true if the value is sensitive and was redacted.
+	Redacted bool `json:"redacted,omitempty"`
+}
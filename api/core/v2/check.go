@@ -23,6 +23,10 @@ const (
 	// DefaultSplayCoverage is the default splay coverage for proxy check requests
 	DefaultSplayCoverage = 90.0
 
+	// DefaultSplayThreshold is the default number of matching entities from
+	// which the proxy check requests are splayed, even if splay is disabled
+	DefaultSplayThreshold = 100
+
 	// NagiosOutputMetricFormat is the accepted string to represent the output metric format of
 	// Nagios Perf Data
 	NagiosOutputMetricFormat = "nagios_perfdata"
@@ -38,10 +42,18 @@ const (
 	// InfluxDBOutputMetricFormat is the accepted string to represent the output metric format of
 	// InfluxDB Line
 	InfluxDBOutputMetricFormat = "influxdb_line"
+
+	// OpenTelemetryOutputMetricFormat is the accepted string to represent the output metric format of
+	// OTLP JSON
+	OpenTelemetryOutputMetricFormat = "otlp_json"
+
+	// StatsdOutputMetricFormat is the accepted string to represent the output metric format of
+	// StatsD Line, with the DogStatsD tags
+	StatsdOutputMetricFormat = "statsd_line"
 )
 
 // OutputMetricFormats represents all the accepted output_metric_format's a check can have
-var OutputMetricFormats = []string{NagiosOutputMetricFormat, GraphiteOutputMetricFormat, OpenTSDBOutputMetricFormat, InfluxDBOutputMetricFormat}
+var OutputMetricFormats = []string{NagiosOutputMetricFormat, GraphiteOutputMetricFormat, OpenTSDBOutputMetricFormat, InfluxDBOutputMetricFormat, OpenTelemetryOutputMetricFormat, StatsdOutputMetricFormat}
 
 // FixtureCheck returns a fixture for a Check object.
 func FixtureCheck(id string) *Check {
@@ -78,28 +90,35 @@ func NewCheck(c *CheckConfig) *Check {
 			Labels:      c.Labels,
 			Annotations: c.Annotations,
 		},
-		Command:              c.Command,
-		Handlers:             c.Handlers,
-		HighFlapThreshold:    c.HighFlapThreshold,
-		Interval:             c.Interval,
-		LowFlapThreshold:     c.LowFlapThreshold,
-		Publish:              c.Publish,
-		RuntimeAssets:        c.RuntimeAssets,
-		Subscriptions:        c.Subscriptions,
-		ProxyEntityName:      c.ProxyEntityName,
-		CheckHooks:           c.CheckHooks,
-		Stdin:                c.Stdin,
-		Subdue:               c.Subdue,
-		Cron:                 c.Cron,
-		Ttl:                  c.Ttl,
-		Timeout:              c.Timeout,
-		ProxyRequests:        c.ProxyRequests,
-		RoundRobin:           c.RoundRobin,
-		OutputMetricFormat:   c.OutputMetricFormat,
-		OutputMetricHandlers: c.OutputMetricHandlers,
-		EnvVars:              c.EnvVars,
-		DiscardOutput:        c.DiscardOutput,
-		MaxOutputSize:        c.MaxOutputSize,
+		Command:                 c.Command,
+		Handlers:                c.Handlers,
+		HighFlapThreshold:       c.HighFlapThreshold,
+		Interval:                c.Interval,
+		LowFlapThreshold:        c.LowFlapThreshold,
+		Publish:                 c.Publish,
+		RuntimeAssets:           c.RuntimeAssets,
+		Subscriptions:           c.Subscriptions,
+		ProxyEntityName:         c.ProxyEntityName,
+		CheckHooks:              c.CheckHooks,
+		Stdin:                   c.Stdin,
+		Subdue:                  c.Subdue,
+		Cron:                    c.Cron,
+		Ttl:                     c.Ttl,
+		Timeout:                 c.Timeout,
+		ProxyRequests:           c.ProxyRequests,
+		RoundRobin:              c.RoundRobin,
+		OutputMetricFormat:      c.OutputMetricFormat,
+		OutputMetricHandlers:    c.OutputMetricHandlers,
+		EnvVars:                 c.EnvVars,
+		DiscardOutput:           c.DiscardOutput,
+		MaxOutputSize:           c.MaxOutputSize,
+		OutputArtifacts:         c.OutputArtifacts,
+		MetricSampleRate:        c.MetricSampleRate,
+		RunAt:                   c.RunAt,
+		PrometheusScrape:        c.PrometheusScrape,
+		MaxConcurrentExecutions: c.MaxConcurrentExecutions,
+		Container:               c.Container,
+		EventLog:                c.EventLog,
 	}
 	if check.Labels == nil {
 		check.Labels = make(map[string]string)
@@ -132,6 +151,16 @@ func (c *Check) URIPath() string {
 
 // Validate returns an error if the check does not pass validation tests.
 func (c *Check) Validate() error {
+	if c.Cron == "" && len(c.RunAt) == 0 && c.Interval < 1 {
+		return errors.New("check interval must be greater than or equal to 1")
+	}
+	return c.ValidateUnscheduled()
+}
+
+// ValidateUnscheduled is like Validate, but accepts checks with neither an
+// interval nor a cron schedule, such as those of the events created through
+// the API, which are not executed by the agents.
+func (c *Check) ValidateUnscheduled() error {
 	if err := ValidateName(c.Name); err != nil {
 		return errors.New("check name " + err.Error())
 	}
@@ -143,9 +172,9 @@ func (c *Check) Validate() error {
 		if _, err := cron.ParseStandard(c.Cron); err != nil {
 			return errors.New("check cron string is invalid")
 		}
-	} else {
-		if c.Interval < 1 {
-			return errors.New("check interval must be greater than or equal to 1")
+	} else if len(c.RunAt) > 0 {
+		if err := ValidateRunAt(c.RunAt); err != nil {
+			return err
 		}
 	}
 
@@ -190,6 +219,30 @@ func (c *Check) Validate() error {
 		return err
 	}
 
+	if err := ValidateOutputArtifacts(c.OutputArtifacts); err != nil {
+		return err
+	}
+
+	if c.PrometheusScrape != nil {
+		if c.Command != "" {
+			return errors.New("must only specify either a command or a prometheus scrape")
+		}
+		if c.OutputMetricFormat != "" {
+			return errors.New("output metric format must not be set for a prometheus scrape")
+		}
+		if err := c.PrometheusScrape.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := validateCheckContainer(c.Container, c.Command, c.RuntimeAssets, c.PrometheusScrape); err != nil {
+		return err
+	}
+
+	if err := validateCheckEventLog(c.EventLog, c.Command, c.OutputMetricFormat, c.PrometheusScrape); err != nil {
+		return err
+	}
+
 	if c.MaxOutputSize < 0 {
 		return fmt.Errorf("MaxOutputSize must be >= 0")
 	}
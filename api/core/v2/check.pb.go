@@ -36,10 +36,17 @@ type CheckRequest struct {
 	// Issued describes the time in which the check request was issued
 	Issued int64 `protobuf:"varint,4,opt,name=Issued,proto3" json:"issued"`
 	// HookAssets is a map of assets required to execute hooks.
-	HookAssets           map[string]*AssetList `protobuf:"bytes,5,rep,name=hook_assets,json=hookAssets,proto3" json:"hook_assets" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	HookAssets map[string]*AssetList `protobuf:"bytes,5,rep,name=hook_assets,json=hookAssets,proto3" json:"hook_assets" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// ID uniquely identifies the check request, allowing agents to discard
+	// requests they already received.
+	ID string `protobuf:"bytes,6,opt,name=id,proto3" json:"id,omitempty"`
+	// Expires is the time after which the check request is stale and
+	// discarded by the agents, as a Unix timestamp. The check requests
+	// without expiry never expire.
+	Expires              int64    `protobuf:"varint,7,opt,name=expires,proto3" json:"expires,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *CheckRequest) Reset()         { *m = CheckRequest{} }
@@ -110,6 +117,20 @@ func (m *CheckRequest) GetHookAssets() map[string]*AssetList {
 	return nil
 }
 
+func (m *CheckRequest) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *CheckRequest) GetExpires() int64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
 // An AssetList represents a list of assets for a CheckRequest.
 type AssetList struct {
 	// Assets are a list of assets required to execute check or hook.
@@ -169,7 +190,11 @@ type ProxyRequests struct {
 	Splay bool `protobuf:"varint,2,opt,name=splay,proto3" json:"splay"`
 	// SplayCoverage is the percentage used for proxy check request splay
 	// calculation.
-	SplayCoverage        uint32   `protobuf:"varint,3,opt,name=splay_coverage,json=splayCoverage,proto3" json:"splay_coverage"`
+	SplayCoverage uint32 `protobuf:"varint,3,opt,name=splay_coverage,json=splayCoverage,proto3" json:"splay_coverage"`
+	// SplayThreshold is the number of matching entities from which the proxy
+	// check requests are splayed, even if splay is disabled. The default
+	// threshold is used if 0.
+	SplayThreshold       uint32   `protobuf:"varint,4,opt,name=splay_threshold,json=splayThreshold,proto3" json:"splay_threshold,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -229,6 +254,346 @@ func (m *ProxyRequests) GetSplayCoverage() uint32 {
 	return 0
 }
 
+func (m *ProxyRequests) GetSplayThreshold() uint32 {
+	if m != nil {
+		return m.SplayThreshold
+	}
+	return 0
+}
+
+// A PrometheusScrape is the specification of a check scraping the metrics of
+// a Prometheus endpoint, executed by the agent instead of a command.
+type PrometheusScrape struct {
+	// URL is the URL of the Prometheus metrics endpoint.
+	URL string `protobuf:"bytes,1,opt,name=url,proto3" json:"url"`
+	// Metrics are the names of the metrics to keep, or glob patterns matching
+	// them, before relabeling. All the metrics are kept if empty.
+	Metrics []string `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	// Relabel are the relabeling rules applied to the samples, in order.
+	Relabel              []PrometheusRelabel `protobuf:"bytes,3,rep,name=relabel,proto3" json:"relabel,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *PrometheusScrape) Reset()         { *m = PrometheusScrape{} }
+func (m *PrometheusScrape) String() string { return proto.CompactTextString(m) }
+func (*PrometheusScrape) ProtoMessage()    {}
+func (*PrometheusScrape) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d8d3c606fb107336, []int{3}
+}
+func (m *PrometheusScrape) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PrometheusScrape) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PrometheusScrape.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PrometheusScrape) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrometheusScrape.Merge(m, src)
+}
+func (m *PrometheusScrape) XXX_Size() int {
+	return m.Size()
+}
+func (m *PrometheusScrape) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrometheusScrape.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrometheusScrape proto.InternalMessageInfo
+
+func (m *PrometheusScrape) GetURL() string {
+	if m != nil {
+		return m.URL
+	}
+	return ""
+}
+
+func (m *PrometheusScrape) GetMetrics() []string {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+func (m *PrometheusScrape) GetRelabel() []PrometheusRelabel {
+	if m != nil {
+		return m.Relabel
+	}
+	return nil
+}
+
+// A PrometheusRelabel is a relabeling rule applied to the samples of a
+// Prometheus scrape, like the metric_relabel_configs of Prometheus.
+type PrometheusRelabel struct {
+	// SourceLabels are the labels whose values, joined with a semicolon, are
+	// matched against the regex. The name of the metric is the __name__
+	// label.
+	SourceLabels []string `protobuf:"bytes,1,rep,name=source_labels,json=sourceLabels,proto3" json:"source_labels,omitempty"`
+	// Regex is the regular expression matched against the source labels. It
+	// defaults to (.*).
+	Regex string `protobuf:"bytes,2,opt,name=regex,proto3" json:"regex,omitempty"`
+	// TargetLabel is the label set by the replace action.
+	TargetLabel string `protobuf:"bytes,3,opt,name=target_label,json=targetLabel,proto3" json:"target_label,omitempty"`
+	// Replacement is the value of the target label of the replace action, in
+	// which the regex capture groups are expanded. It defaults to $1.
+	Replacement string `protobuf:"bytes,4,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	// Action is one of replace, keep, drop, labeldrop and labelkeep. It
+	// defaults to replace.
+	Action               string   `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrometheusRelabel) Reset()         { *m = PrometheusRelabel{} }
+func (m *PrometheusRelabel) String() string { return proto.CompactTextString(m) }
+func (*PrometheusRelabel) ProtoMessage()    {}
+func (*PrometheusRelabel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d8d3c606fb107336, []int{4}
+}
+func (m *PrometheusRelabel) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PrometheusRelabel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PrometheusRelabel.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PrometheusRelabel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrometheusRelabel.Merge(m, src)
+}
+func (m *PrometheusRelabel) XXX_Size() int {
+	return m.Size()
+}
+func (m *PrometheusRelabel) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrometheusRelabel.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrometheusRelabel proto.InternalMessageInfo
+
+func (m *PrometheusRelabel) GetSourceLabels() []string {
+	if m != nil {
+		return m.SourceLabels
+	}
+	return nil
+}
+
+func (m *PrometheusRelabel) GetRegex() string {
+	if m != nil {
+		return m.Regex
+	}
+	return ""
+}
+
+func (m *PrometheusRelabel) GetTargetLabel() string {
+	if m != nil {
+		return m.TargetLabel
+	}
+	return ""
+}
+
+func (m *PrometheusRelabel) GetReplacement() string {
+	if m != nil {
+		return m.Replacement
+	}
+	return ""
+}
+
+func (m *PrometheusRelabel) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+// A CheckContainer is the container image a check command is executed in,
+// with the container engine of the agent, instead of on the host.
+type CheckContainer struct {
+	// Image is the reference of the container image.
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image"`
+	// Runtime is the container engine running the container: docker, podman
+	// or nerdctl, for containerd. The container runtime of the agent is used
+	// if empty.
+	Runtime string `protobuf:"bytes,2,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	// Volumes are the host paths mounted in the container, of the form
+	// host_path:container_path[:ro].
+	Volumes []string `protobuf:"bytes,3,rep,name=volumes,proto3" json:"volumes,omitempty"`
+	// Network is the network the container is attached to. The default
+	// network of the container engine is used if empty.
+	Network              string   `protobuf:"bytes,4,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckContainer) Reset()         { *m = CheckContainer{} }
+func (m *CheckContainer) String() string { return proto.CompactTextString(m) }
+func (*CheckContainer) ProtoMessage()    {}
+func (*CheckContainer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d8d3c606fb107336, []int{5}
+}
+func (m *CheckContainer) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CheckContainer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CheckContainer.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CheckContainer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckContainer.Merge(m, src)
+}
+func (m *CheckContainer) XXX_Size() int {
+	return m.Size()
+}
+func (m *CheckContainer) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckContainer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckContainer proto.InternalMessageInfo
+
+func (m *CheckContainer) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *CheckContainer) GetRuntime() string {
+	if m != nil {
+		return m.Runtime
+	}
+	return ""
+}
+
+func (m *CheckContainer) GetVolumes() []string {
+	if m != nil {
+		return m.Volumes
+	}
+	return nil
+}
+
+func (m *CheckContainer) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+// An EventLogQuery is the specification of a check querying the records of a
+// Windows event log channel, executed by the agent instead of a command.
+type EventLogQuery struct {
+	// Channel is the event log channel queried, e.g. System, Application or
+	// Microsoft-Windows-Sysmon/Operational.
+	Channel string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel"`
+	// Levels are the levels of the records matched: critical, error,
+	// warning, information or verbose. The records of all levels are matched
+	// if empty.
+	Levels []string `protobuf:"bytes,2,rep,name=levels,proto3" json:"levels,omitempty"`
+	// Providers are the names of the providers of the records matched. The
+	// records of all providers are matched if empty.
+	Providers []string `protobuf:"bytes,3,rep,name=providers,proto3" json:"providers,omitempty"`
+	// EventIDs are the event IDs of the records matched. The records of all
+	// event IDs are matched if empty.
+	EventIDs []uint32 `protobuf:"varint,4,rep,packed,name=event_ids,json=eventIds,proto3" json:"event_ids,omitempty"`
+	// MaxRecords is the maximum number of records read at every execution,
+	// the following ones are read by the next executions. It defaults to 100.
+	MaxRecords           uint32   `protobuf:"varint,5,opt,name=max_records,json=maxRecords,proto3" json:"max_records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventLogQuery) Reset()         { *m = EventLogQuery{} }
+func (m *EventLogQuery) String() string { return proto.CompactTextString(m) }
+func (*EventLogQuery) ProtoMessage()    {}
+func (*EventLogQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d8d3c606fb107336, []int{6}
+}
+func (m *EventLogQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *EventLogQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_EventLogQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *EventLogQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventLogQuery.Merge(m, src)
+}
+func (m *EventLogQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *EventLogQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventLogQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventLogQuery proto.InternalMessageInfo
+
+func (m *EventLogQuery) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *EventLogQuery) GetLevels() []string {
+	if m != nil {
+		return m.Levels
+	}
+	return nil
+}
+
+func (m *EventLogQuery) GetProviders() []string {
+	if m != nil {
+		return m.Providers
+	}
+	return nil
+}
+
+func (m *EventLogQuery) GetEventIDs() []uint32 {
+	if m != nil {
+		return m.EventIDs
+	}
+	return nil
+}
+
+func (m *EventLogQuery) GetMaxRecords() uint32 {
+	if m != nil {
+		return m.MaxRecords
+	}
+	return 0
+}
+
 // CheckConfig is the specification of a check.
 type CheckConfig struct {
 	// Command is the command to be executed.
@@ -292,17 +657,43 @@ type CheckConfig struct {
 	MaxOutputSize int64 `protobuf:"varint,27,opt,name=max_output_size,json=maxOutputSize,proto3" json:"max_output_size,omitempty"`
 	// DiscardOutput causes agents to discard check output. No check output is
 	// written to the backend, but metrics extraction is still performed.
-	DiscardOutput        bool     `protobuf:"varint,28,opt,name=discard_output,json=discardOutput,proto3" json:"discard_output,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	DiscardOutput bool `protobuf:"varint,28,opt,name=discard_output,json=discardOutput,proto3" json:"discard_output,omitempty"`
+	// OutputArtifacts are the paths, or glob patterns, of the files produced
+	// by the check which agents upload to their artifact store after each
+	// execution.
+	OutputArtifacts []string `protobuf:"bytes,29,rep,name=output_artifacts,json=outputArtifacts,proto3" json:"output_artifacts,omitempty"`
+	// MetricSampleRate keeps 1 in MetricSampleRate of the OK events of the
+	// check carrying metrics, sampled by the backend before storing the
+	// events. The events changing the status of the check are always kept.
+	// The events are all kept if 0 or 1.
+	MetricSampleRate uint32 `protobuf:"varint,30,opt,name=metric_sample_rate,json=metricSampleRate,proto3" json:"metric_sample_rate,omitempty"`
+	// RunAt are the times, in RFC 3339 format, at which the check is
+	// scheduled, instead of an interval or a cron schedule. The check is
+	// executed once at each of them.
+	RunAt []string `protobuf:"bytes,31,rep,name=run_at,json=runAt,proto3" json:"run_at,omitempty"`
+	// PrometheusScrape makes the check scrape the metrics of a Prometheus
+	// endpoint instead of executing a command.
+	PrometheusScrape *PrometheusScrape `protobuf:"bytes,32,opt,name=prometheus_scrape,json=prometheusScrape,proto3" json:"prometheus_scrape,omitempty"`
+	// MaxConcurrentExecutions is the maximum number of agents executing the
+	// check at the same time, across all its subscriptions. The check request
+	// is not sent to an agent while the limit is reached. Unlimited if 0.
+	MaxConcurrentExecutions uint32 `protobuf:"varint,33,opt,name=max_concurrent_executions,json=maxConcurrentExecutions,proto3" json:"max_concurrent_executions,omitempty"`
+	// Container makes the agent execute the command of the check in a
+	// container instead of on the host.
+	Container *CheckContainer `protobuf:"bytes,34,opt,name=container,proto3" json:"container,omitempty"`
+	// EventLog makes the check query the records of a Windows event log
+	// channel instead of executing a command.
+	EventLog             *EventLogQuery `protobuf:"bytes,35,opt,name=event_log,json=eventLog,proto3" json:"event_log,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *CheckConfig) Reset()         { *m = CheckConfig{} }
 func (m *CheckConfig) String() string { return proto.CompactTextString(m) }
 func (*CheckConfig) ProtoMessage()    {}
 func (*CheckConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d8d3c606fb107336, []int{3}
+	return fileDescriptor_d8d3c606fb107336, []int{7}
 }
 func (m *CheckConfig) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -425,6 +816,41 @@ type Check struct {
 	// DiscardOutput causes agents to discard check output. No check output is
 	// written to the backend, but metrics extraction is still performed.
 	DiscardOutput bool `protobuf:"varint,40,opt,name=discard_output,json=discardOutput,proto3" json:"discard_output,omitempty"`
+	// Received is the time the check result was received by the backend, in
+	// seconds since the Epoch.
+	Received int64 `protobuf:"varint,41,opt,name=received,proto3" json:"received,omitempty"`
+	// Processed is the time the event of the check was processed by the
+	// backend, in seconds since the Epoch.
+	Processed int64 `protobuf:"varint,42,opt,name=processed,proto3" json:"processed,omitempty"`
+	// OutputArtifacts are the paths, or glob patterns, of the files produced
+	// by the check which agents upload to their artifact store after each
+	// execution.
+	OutputArtifacts []string `protobuf:"bytes,43,rep,name=output_artifacts,json=outputArtifacts,proto3" json:"output_artifacts,omitempty"`
+	// ArtifactLinks are the URLs of the artifacts uploaded by the agent for
+	// this execution of the check.
+	ArtifactLinks []string `protobuf:"bytes,44,rep,name=artifact_links,json=artifactLinks,proto3" json:"artifact_links,omitempty"`
+	// MetricSampleRate keeps 1 in MetricSampleRate of the OK events of the
+	// check carrying metrics, sampled by the backend before storing the
+	// events. The events changing the status of the check are always kept.
+	// The events are all kept if 0 or 1.
+	MetricSampleRate uint32 `protobuf:"varint,45,opt,name=metric_sample_rate,json=metricSampleRate,proto3" json:"metric_sample_rate,omitempty"`
+	// RunAt are the times, in RFC 3339 format, at which the check is
+	// scheduled, instead of an interval or a cron schedule. The check is
+	// executed once at each of them.
+	RunAt []string `protobuf:"bytes,46,rep,name=run_at,json=runAt,proto3" json:"run_at,omitempty"`
+	// PrometheusScrape makes the check scrape the metrics of a Prometheus
+	// endpoint instead of executing a command.
+	PrometheusScrape *PrometheusScrape `protobuf:"bytes,47,opt,name=prometheus_scrape,json=prometheusScrape,proto3" json:"prometheus_scrape,omitempty"`
+	// MaxConcurrentExecutions is the maximum number of agents executing the
+	// check at the same time, across all its subscriptions. The check request
+	// is not sent to an agent while the limit is reached. Unlimited if 0.
+	MaxConcurrentExecutions uint32 `protobuf:"varint,48,opt,name=max_concurrent_executions,json=maxConcurrentExecutions,proto3" json:"max_concurrent_executions,omitempty"`
+	// Container makes the agent execute the command of the check in a
+	// container instead of on the host.
+	Container *CheckContainer `protobuf:"bytes,49,opt,name=container,proto3" json:"container,omitempty"`
+	// EventLog makes the check query the records of a Windows event log
+	// channel instead of executing a command.
+	EventLog *EventLogQuery `protobuf:"bytes,50,opt,name=event_log,json=eventLog,proto3" json:"event_log,omitempty"`
 	// ExtendedAttributes store serialized arbitrary JSON-encoded data
 	ExtendedAttributes   []byte   `protobuf:"bytes,99,opt,name=ExtendedAttributes,proto3" json:"-"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -436,7 +862,7 @@ func (m *Check) Reset()         { *m = Check{} }
 func (m *Check) String() string { return proto.CompactTextString(m) }
 func (*Check) ProtoMessage()    {}
 func (*Check) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d8d3c606fb107336, []int{4}
+	return fileDescriptor_d8d3c606fb107336, []int{8}
 }
 func (m *Check) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -480,7 +906,7 @@ func (m *CheckHistory) Reset()         { *m = CheckHistory{} }
 func (m *CheckHistory) String() string { return proto.CompactTextString(m) }
 func (*CheckHistory) ProtoMessage()    {}
 func (*CheckHistory) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d8d3c606fb107336, []int{5}
+	return fileDescriptor_d8d3c606fb107336, []int{9}
 }
 func (m *CheckHistory) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -528,6 +954,10 @@ func init() {
 	proto.RegisterMapType((map[string]*AssetList)(nil), "sensu.core.v2.CheckRequest.HookAssetsEntry")
 	proto.RegisterType((*AssetList)(nil), "sensu.core.v2.AssetList")
 	proto.RegisterType((*ProxyRequests)(nil), "sensu.core.v2.ProxyRequests")
+	proto.RegisterType((*PrometheusScrape)(nil), "sensu.core.v2.PrometheusScrape")
+	proto.RegisterType((*PrometheusRelabel)(nil), "sensu.core.v2.PrometheusRelabel")
+	proto.RegisterType((*CheckContainer)(nil), "sensu.core.v2.CheckContainer")
+	proto.RegisterType((*EventLogQuery)(nil), "sensu.core.v2.EventLogQuery")
 	proto.RegisterType((*CheckConfig)(nil), "sensu.core.v2.CheckConfig")
 	proto.RegisterType((*Check)(nil), "sensu.core.v2.Check")
 	proto.RegisterType((*CheckHistory)(nil), "sensu.core.v2.CheckHistory")
@@ -536,96 +966,139 @@ func init() {
 func init() { proto.RegisterFile("check.proto", fileDescriptor_d8d3c606fb107336) }
 
 var fileDescriptor_d8d3c606fb107336 = []byte{
-	// 1419 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x57, 0x4f, 0x6f, 0x1b, 0xb7,
-	0x12, 0xf7, 0xda, 0xb1, 0x6c, 0x53, 0x96, 0xff, 0xd0, 0x76, 0x4c, 0x2b, 0x89, 0x56, 0xcf, 0xef,
-	0x25, 0x11, 0xf0, 0xde, 0x53, 0x5e, 0xfc, 0x1a, 0x34, 0x0d, 0x7a, 0x68, 0xd6, 0x4d, 0xea, 0xb4,
-	0x49, 0x1c, 0x30, 0x69, 0x0d, 0x14, 0x2d, 0x16, 0xd4, 0x2e, 0x6d, 0x6d, 0xad, 0x5d, 0xaa, 0x4b,
-	0xae, 0x6c, 0xe7, 0x13, 0xf4, 0xd6, 0x6b, 0x8f, 0x39, 0xa6, 0xa7, 0x5e, 0xfb, 0x11, 0x72, 0xcc,
-	0x27, 0x58, 0xb4, 0xee, 0x6d, 0x3f, 0x41, 0x6f, 0x2d, 0x38, 0x4b, 0xc9, 0x92, 0x2c, 0xa7, 0x39,
-	0xa4, 0x40, 0x51, 0xe4, 0x22, 0xce, 0xfc, 0x66, 0x66, 0x49, 0x0e, 0x67, 0x7e, 0xa4, 0x50, 0xd1,
-	0x6b, 0x72, 0x6f, 0xbf, 0xde, 0x8e, 0x85, 0x12, 0xb8, 0x24, 0x79, 0x24, 0x93, 0xba, 0x27, 0x62,
-	0x5e, 0xef, 0x6c, 0x94, 0xdf, 0xd9, 0x0b, 0x54, 0x33, 0x69, 0xd4, 0x3d, 0x11, 0x5e, 0xdb, 0x13,
-	0x7b, 0xe2, 0x1a, 0x78, 0x35, 0x92, 0xdd, 0x0f, 0x3a, 0xd7, 0xeb, 0x1b, 0xf5, 0xeb, 0x00, 0x02,
-	0x06, 0x52, 0xfe, 0x91, 0x72, 0x91, 0x49, 0xc9, 0x95, 0x51, 0x50, 0x53, 0x88, 0xfd, 0xae, 0x1c,
-	0x72, 0xc5, 0x8c, 0xbc, 0xa8, 0x82, 0x90, 0xbb, 0x07, 0x41, 0xe4, 0x8b, 0x83, 0x1c, 0x5a, 0xff,
-	0x76, 0x02, 0xcd, 0x6e, 0xea, 0xc5, 0x50, 0xfe, 0x75, 0xc2, 0xa5, 0xc2, 0x37, 0x51, 0xc1, 0x13,
-	0xd1, 0x6e, 0xb0, 0x47, 0xac, 0xaa, 0x55, 0x2b, 0x6e, 0x94, 0xeb, 0x03, 0xcb, 0xab, 0x83, 0xf3,
-	0x26, 0x78, 0x38, 0xe7, 0x5e, 0xa4, 0xb6, 0x45, 0x8d, 0x3f, 0xde, 0x40, 0x05, 0x58, 0x84, 0x24,
-	0xe3, 0xd5, 0x89, 0x5a, 0x71, 0x63, 0x79, 0x28, 0xf2, 0xb6, 0x36, 0x42, 0xcc, 0x18, 0x35, 0x9e,
-	0xf8, 0x06, 0x9a, 0xd4, 0x6b, 0x95, 0x64, 0x02, 0x42, 0xd6, 0x86, 0x42, 0xb6, 0x84, 0xe8, 0x9f,
-	0x6b, 0x8c, 0xe6, 0xde, 0x78, 0x1d, 0x15, 0xee, 0x49, 0x99, 0x70, 0x9f, 0x9c, 0xab, 0x5a, 0xb5,
-	0x09, 0x07, 0x65, 0xa9, 0x5d, 0x08, 0x00, 0xa1, 0xc6, 0x82, 0xbf, 0x44, 0x45, 0xed, 0xec, 0x9a,
-	0x35, 0x4d, 0xc2, 0x04, 0xff, 0x1e, 0xb5, 0x1b, 0xb3, 0x75, 0x98, 0x0d, 0x16, 0x29, 0xef, 0x44,
-	0x2a, 0x3e, 0x72, 0xe6, 0xb3, 0xd4, 0xee, 0xff, 0x06, 0x85, 0xbc, 0xe6, 0x1e, 0xe5, 0x1d, 0x34,
-	0x3f, 0xe4, 0x8f, 0x17, 0xd0, 0xc4, 0x3e, 0x3f, 0x82, 0xbc, 0xcd, 0x50, 0x2d, 0xe2, 0x3a, 0x9a,
-	0xec, 0xb0, 0x56, 0xc2, 0xc9, 0x38, 0xe4, 0x92, 0x8c, 0xca, 0xc8, 0xfd, 0x40, 0x2a, 0x9a, 0xbb,
-	0xdd, 0x1a, 0xbf, 0x69, 0xad, 0xdf, 0x43, 0x33, 0x3d, 0x1c, 0xbf, 0xdf, 0xcb, 0xa9, 0xf5, 0x8a,
-	0x9c, 0xce, 0xe9, 0xdc, 0xe8, 0x14, 0x98, 0x75, 0x9a, 0x71, 0xfd, 0x07, 0x0b, 0x95, 0x1e, 0xc5,
-	0xe2, 0xf0, 0xc8, 0xec, 0x50, 0x62, 0x07, 0x2d, 0xf2, 0x48, 0x05, 0xea, 0xc8, 0x65, 0x4a, 0xc5,
-	0x41, 0x23, 0x51, 0x3c, 0xff, 0xf4, 0x8c, 0xb3, 0x92, 0xa5, 0xf6, 0x69, 0x23, 0x5d, 0xc8, 0xa1,
-	0xdb, 0x3d, 0x04, 0xdb, 0x68, 0x52, 0xb6, 0x5b, 0xec, 0x08, 0x36, 0x35, 0xed, 0xcc, 0x64, 0xa9,
-	0x9d, 0x03, 0x34, 0x1f, 0xf0, 0x7b, 0x68, 0x0e, 0x04, 0xd7, 0x13, 0x1d, 0x1e, 0xb3, 0x3d, 0x4e,
-	0x26, 0xaa, 0x56, 0xad, 0xe4, 0xe0, 0x2c, 0xb5, 0x87, 0x2c, 0xb4, 0x04, 0xfa, 0xa6, 0x51, 0xd7,
-	0xbf, 0x47, 0xa8, 0xd8, 0x57, 0x61, 0x98, 0xa0, 0x29, 0x4f, 0x84, 0x21, 0x8b, 0x7c, 0x93, 0xd6,
-	0xae, 0x8a, 0x6b, 0x68, 0xba, 0xc9, 0x22, 0xbf, 0xc5, 0xe3, 0xbc, 0x78, 0x66, 0x9c, 0xd9, 0x2c,
-	0xb5, 0x7b, 0x18, 0xed, 0x49, 0xf8, 0x23, 0xb4, 0xd4, 0x0c, 0xf6, 0x9a, 0xee, 0x6e, 0x8b, 0xb5,
-	0x5d, 0xd5, 0x8c, 0xb9, 0x6c, 0x8a, 0x56, 0x5e, 0x39, 0x25, 0x67, 0x35, 0x4b, 0xed, 0x51, 0x66,
-	0xba, 0xa8, 0xc1, 0xbb, 0x2d, 0xd6, 0x7e, 0xd2, 0x85, 0xf4, 0x94, 0x41, 0xa4, 0x78, 0xdc, 0x61,
-	0x2d, 0x32, 0x09, 0xd1, 0x30, 0x65, 0x17, 0xa3, 0x3d, 0x09, 0x7f, 0x88, 0x70, 0x4b, 0x1c, 0x0c,
-	0xcf, 0x58, 0x80, 0x98, 0xf3, 0x59, 0x6a, 0x8f, 0xb0, 0xd2, 0x85, 0x96, 0x38, 0x18, 0x9c, 0xef,
-	0x32, 0x9a, 0x6a, 0x27, 0x8d, 0x56, 0x20, 0x9b, 0x64, 0x06, 0x52, 0x5d, 0xcc, 0x52, 0xbb, 0x0b,
-	0xd1, 0xae, 0xa0, 0xd3, 0x1d, 0x27, 0x11, 0xb4, 0xb6, 0xa9, 0x15, 0x04, 0xf9, 0x80, 0x74, 0x0f,
-	0x5a, 0x68, 0xc9, 0xe8, 0x79, 0xd9, 0xe2, 0x77, 0x51, 0x49, 0x26, 0x0d, 0xe9, 0xc5, 0x41, 0x5b,
-	0x05, 0x22, 0x92, 0xa4, 0x08, 0x91, 0x8b, 0x59, 0x6a, 0x0f, 0x1a, 0xe8, 0xa0, 0x8a, 0x6f, 0x20,
-	0x7c, 0xe7, 0x50, 0xf1, 0xc8, 0xe7, 0xfe, 0x49, 0x65, 0x90, 0xd9, 0xaa, 0x55, 0x9b, 0x75, 0x26,
-	0xb3, 0xd4, 0xb6, 0xfe, 0x4b, 0x47, 0x38, 0xe0, 0x27, 0x68, 0xb1, 0xad, 0xeb, 0xd1, 0x35, 0x75,
-	0x16, 0xb1, 0x90, 0x93, 0x92, 0x3e, 0x58, 0xa7, 0x76, 0x9c, 0xda, 0xf3, 0x50, 0xac, 0x77, 0xc0,
-	0xf6, 0x90, 0x85, 0x5c, 0x57, 0xe4, 0x29, 0x7f, 0x3a, 0xdf, 0x1e, 0xf4, 0xc2, 0x0f, 0x0c, 0x9f,
-	0xba, 0x39, 0x95, 0xcc, 0x41, 0xa7, 0xac, 0x8e, 0xa0, 0x12, 0xdd, 0x52, 0xce, 0x92, 0x69, 0x96,
-	0xfe, 0x18, 0x8a, 0x40, 0xd9, 0x02, 0x72, 0xd1, 0xf5, 0xad, 0xfc, 0x20, 0x22, 0xf3, 0x7d, 0xf5,
-	0xad, 0x01, 0x9a, 0x0f, 0xf8, 0x36, 0x2a, 0xc8, 0xa4, 0xe1, 0x27, 0x9c, 0x2c, 0x40, 0x5b, 0x5f,
-	0x1a, 0x9a, 0xea, 0x49, 0x10, 0xf2, 0x1d, 0x20, 0xd9, 0x9d, 0x26, 0x8f, 0x72, 0x72, 0xca, 0x03,
-	0xa8, 0x19, 0x31, 0x46, 0xe7, 0xbc, 0x58, 0x44, 0x64, 0x11, 0x8a, 0x1a, 0x64, 0xbc, 0x86, 0x26,
-	0x94, 0x6a, 0x11, 0x0c, 0x8c, 0x36, 0x95, 0xa5, 0xb6, 0x56, 0xa9, 0xfe, 0xd1, 0x95, 0xa0, 0x4f,
-	0x4d, 0x24, 0x8a, 0x2c, 0x41, 0x11, 0x41, 0x25, 0x18, 0x88, 0x76, 0x05, 0xbc, 0x89, 0xe6, 0xf2,
-	0x74, 0xc5, 0xa6, 0xdf, 0xc9, 0x32, 0x2c, 0xf0, 0xe2, 0xd0, 0x02, 0x07, 0x38, 0x81, 0x96, 0xda,
-	0x03, 0x14, 0xf1, 0x3f, 0x54, 0x8c, 0x45, 0x12, 0xf9, 0x6e, 0x2c, 0x1a, 0x41, 0x44, 0x56, 0x20,
-	0x09, 0x40, 0x85, 0x7d, 0x30, 0x45, 0xa0, 0x50, 0x2d, 0xe3, 0x8f, 0xd1, 0xb2, 0x48, 0x54, 0x3b,
-	0x51, 0x6e, 0xc8, 0x55, 0x1c, 0x78, 0xee, 0xae, 0x88, 0x43, 0xa6, 0xc8, 0x79, 0x38, 0x58, 0x92,
-	0xa5, 0xf6, 0x48, 0x3b, 0xc5, 0x39, 0xfa, 0x00, 0xc0, 0xbb, 0x80, 0xe1, 0x47, 0xe8, 0xfc, 0xa0,
-	0x6f, 0xaf, 0xc9, 0x57, 0xa1, 0x34, 0xcb, 0x59, 0x6a, 0x9f, 0xe1, 0x41, 0x97, 0xfb, 0xbf, 0xb7,
-	0xd5, 0x6d, 0xff, 0xab, 0x68, 0x9a, 0x47, 0x1d, 0xb7, 0xc3, 0x62, 0x49, 0xc8, 0x09, 0x51, 0x74,
-	0x31, 0x3a, 0xc5, 0xa3, 0xce, 0x67, 0x2c, 0x96, 0xf8, 0x53, 0x34, 0xad, 0xef, 0x4a, 0x9f, 0x29,
-	0x46, 0xca, 0x90, 0xb7, 0xe1, 0xeb, 0x68, 0xbb, 0xf1, 0x15, 0xf7, 0xf4, 0xf7, 0x99, 0x53, 0xd1,
-	0x55, 0xf4, 0x32, 0xb5, 0x2d, 0xdd, 0xcd, 0xdd, 0xb0, 0xff, 0x88, 0x30, 0x50, 0x3c, 0x6c, 0xab,
-	0x23, 0xda, 0xfb, 0x14, 0xbe, 0x82, 0xe6, 0x43, 0x76, 0xe8, 0x9a, 0x35, 0xcb, 0xe0, 0x29, 0x27,
-	0x17, 0xf4, 0x11, 0xd3, 0x52, 0xc8, 0x0e, 0xb7, 0x01, 0x7d, 0x1c, 0x3c, 0xe5, 0xf8, 0x32, 0x9a,
-	0xf3, 0x03, 0xe9, 0xb1, 0xd8, 0x37, 0xbe, 0xe4, 0xa2, 0x4e, 0x3d, 0x2d, 0x19, 0x34, 0x77, 0xbd,
-	0x35, 0xfd, 0xcd, 0x33, 0x7b, 0xec, 0xf9, 0x33, 0xdb, 0x5a, 0xff, 0x6d, 0x0e, 0x4d, 0x02, 0x57,
-	0xbe, 0x65, 0xc9, 0xbf, 0x28, 0x4b, 0xbe, 0xa5, 0xbb, 0xbf, 0x23, 0xdd, 0x95, 0xd1, 0xb4, 0x9f,
-	0xc4, 0x4c, 0x1f, 0x31, 0x50, 0x9c, 0x45, 0x7b, 0xba, 0x2e, 0x7e, 0x7e, 0xc8, 0xbd, 0x44, 0x71,
-	0x9f, 0xac, 0xc2, 0xce, 0x72, 0xb2, 0x31, 0x18, 0xed, 0x49, 0xf8, 0x2e, 0x9a, 0x6a, 0x06, 0x52,
-	0x89, 0xf8, 0x08, 0x58, 0xa9, 0xb8, 0x71, 0x61, 0xd4, 0xd3, 0x74, 0x2b, 0x77, 0x71, 0xe6, 0xcd,
-	0x29, 0x76, 0x63, 0x68, 0x57, 0xd0, 0x4f, 0xe1, 0xfc, 0xe1, 0x4b, 0xd6, 0x4e, 0x3f, 0x85, 0xf3,
-	0x51, 0xfb, 0x18, 0x4a, 0x29, 0x43, 0xf1, 0x81, 0x4f, 0x8e, 0x50, 0x33, 0xe2, 0x65, 0x5d, 0x06,
-	0x4c, 0xe5, 0xe4, 0x34, 0x43, 0x73, 0x45, 0x47, 0x6a, 0x21, 0x91, 0x40, 0x46, 0x25, 0x73, 0xb8,
-	0x80, 0x50, 0x33, 0xea, 0x36, 0x56, 0x42, 0xb1, 0x96, 0x0b, 0x21, 0xae, 0xd7, 0x64, 0xd1, 0x1e,
-	0x27, 0x97, 0x4e, 0xda, 0xf8, 0xb4, 0x95, 0x2e, 0x00, 0xf6, 0x58, 0x43, 0x9b, 0x80, 0xe0, 0x3a,
-	0x9a, 0x6a, 0x31, 0xa9, 0x5c, 0xb1, 0x4f, 0x2a, 0xb0, 0x91, 0x95, 0xe3, 0xd4, 0x2e, 0xdc, 0x67,
-	0x52, 0x6d, 0x7f, 0xa2, 0x37, 0x6e, 0x8c, 0xb4, 0xa0, 0x85, 0xed, 0x7d, 0x7c, 0x1d, 0x15, 0x85,
-	0xe7, 0x25, 0x71, 0xcc, 0x23, 0x8f, 0x4b, 0x62, 0x43, 0x0c, 0x9c, 0x5b, 0x1f, 0x4c, 0xfb, 0x15,
-	0xfc, 0x10, 0xad, 0xf4, 0xa9, 0xee, 0x01, 0x53, 0x3c, 0x0e, 0x59, 0xbc, 0x4f, 0xaa, 0x10, 0xbc,
-	0x96, 0xa5, 0xf6, 0x68, 0x07, 0xba, 0xdc, 0x07, 0xef, 0x74, 0x51, 0x5c, 0x45, 0xd3, 0x32, 0x68,
-	0x69, 0xd0, 0x27, 0xff, 0x00, 0x4a, 0xc8, 0xff, 0x10, 0xf5, 0x50, 0x7c, 0xad, 0xfb, 0xf7, 0x66,
-	0x1d, 0x8e, 0x78, 0x69, 0x44, 0x93, 0x9a, 0x18, 0xf3, 0xc7, 0xe6, 0xac, 0xab, 0xf4, 0x9f, 0x6f,
-	0xf4, 0x2a, 0xfd, 0xd7, 0x1b, 0xb8, 0x4a, 0x2f, 0xbf, 0xee, 0x55, 0x7a, 0xe5, 0x4f, 0xbd, 0x4a,
-	0xaf, 0xbe, 0xde, 0x55, 0x5a, 0x1b, 0x71, 0x95, 0x9e, 0xf1, 0x88, 0xf5, 0xfe, 0xe0, 0x11, 0xdb,
-	0x77, 0x03, 0x7f, 0x61, 0xfe, 0x3b, 0x6f, 0x9d, 0xf4, 0xa2, 0xe9, 0x16, 0xeb, 0xcc, 0x6e, 0xe9,
-	0x67, 0x88, 0xf1, 0x57, 0x31, 0x84, 0x53, 0xfd, 0xf5, 0xe7, 0x8a, 0xf5, 0xfc, 0xb8, 0x62, 0xfd,
-	0x78, 0x5c, 0xb1, 0x5e, 0x1c, 0x57, 0xac, 0x97, 0xc7, 0x15, 0xeb, 0xa7, 0xe3, 0x8a, 0xf5, 0xdd,
-	0x2f, 0x95, 0xb1, 0xcf, 0xc7, 0x3b, 0x1b, 0x8d, 0x02, 0xfc, 0x87, 0xff, 0xff, 0xef, 0x01, 0x00,
-	0x00, 0xff, 0xff, 0xb0, 0xb8, 0xe2, 0xb4, 0x4f, 0x10, 0x00, 0x00,
+	// 2110 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x58, 0x5f, 0x6f, 0x1b, 0xc7,
+	0x11, 0xf7, 0x89, 0x16, 0x25, 0x2e, 0x45, 0xfd, 0x59, 0x49, 0xd6, 0x4a, 0xb6, 0x75, 0x0c, 0x5d,
+	0x3b, 0x72, 0xe3, 0xd0, 0xb1, 0x5a, 0xa3, 0x89, 0xd3, 0x02, 0x36, 0x65, 0xbb, 0x76, 0xab, 0xc4,
+	0xee, 0xda, 0xa9, 0x81, 0xa2, 0xc5, 0x61, 0x75, 0xb7, 0x26, 0xaf, 0xba, 0x3f, 0xec, 0xee, 0x1e,
+	0x25, 0xe5, 0x13, 0xf4, 0x23, 0xb4, 0xe8, 0x4b, 0x1e, 0x83, 0x7e, 0x82, 0x3e, 0xf4, 0xb1, 0x0f,
+	0x79, 0x0c, 0xfa, 0x01, 0x0e, 0xad, 0xfa, 0x52, 0xf0, 0x0b, 0xb4, 0x8f, 0xc5, 0xce, 0xee, 0x51,
+	0x47, 0x89, 0x8e, 0x13, 0x34, 0x01, 0x8a, 0x22, 0x2f, 0xdc, 0xd9, 0xdf, 0xcc, 0xec, 0x9f, 0x99,
+	0xd9, 0x99, 0xe1, 0xa1, 0xba, 0xdf, 0xe3, 0xfe, 0x7e, 0xbb, 0x2f, 0x52, 0x95, 0xe2, 0x86, 0xe4,
+	0x89, 0xcc, 0xda, 0x7e, 0x2a, 0x78, 0x7b, 0xb0, 0xbd, 0xf1, 0xfd, 0x6e, 0xa8, 0x7a, 0xd9, 0x5e,
+	0xdb, 0x4f, 0xe3, 0x9b, 0xdd, 0xb4, 0x9b, 0xde, 0x04, 0xa9, 0xbd, 0xec, 0xe5, 0xdd, 0xc1, 0xad,
+	0xf6, 0x76, 0xfb, 0x16, 0x80, 0x80, 0x01, 0x65, 0x16, 0xd9, 0xa8, 0x33, 0x29, 0xb9, 0xb2, 0x13,
+	0xd4, 0x4b, 0xd3, 0xfd, 0x82, 0x8e, 0xb9, 0x62, 0x96, 0x5e, 0x52, 0x61, 0xcc, 0xbd, 0x83, 0x30,
+	0x09, 0xd2, 0x03, 0x03, 0xb5, 0xfe, 0x59, 0x41, 0x73, 0x3b, 0xfa, 0x30, 0x94, 0xff, 0x26, 0xe3,
+	0x52, 0xe1, 0x77, 0x51, 0xd5, 0x4f, 0x93, 0x97, 0x61, 0x97, 0x38, 0x4d, 0x67, 0xab, 0xbe, 0xbd,
+	0xd1, 0x1e, 0x3b, 0x5e, 0x1b, 0x84, 0x77, 0x40, 0xa2, 0x73, 0xfe, 0xb3, 0xdc, 0x75, 0xa8, 0x95,
+	0xc7, 0xdb, 0xa8, 0x0a, 0x87, 0x90, 0x64, 0xaa, 0x59, 0xd9, 0xaa, 0x6f, 0xaf, 0x9c, 0xd2, 0xbc,
+	0xa7, 0x99, 0xa0, 0x73, 0x8e, 0x5a, 0x49, 0x7c, 0x1b, 0x4d, 0xeb, 0xb3, 0x4a, 0x52, 0x01, 0x95,
+	0xf5, 0x53, 0x2a, 0x8f, 0xd2, 0xb4, 0xbc, 0xd7, 0x39, 0x6a, 0xa4, 0x71, 0x0b, 0x55, 0x1f, 0x4b,
+	0x99, 0xf1, 0x80, 0x9c, 0x6f, 0x3a, 0x5b, 0x95, 0x0e, 0x1a, 0xe6, 0x6e, 0x35, 0x04, 0x84, 0x5a,
+	0x0e, 0xfe, 0x15, 0xaa, 0x6b, 0x61, 0xcf, 0x9e, 0x69, 0x1a, 0x36, 0x78, 0x6b, 0xd2, 0x6d, 0xec,
+	0xd5, 0x61, 0x37, 0x38, 0xa4, 0x7c, 0x90, 0x28, 0x71, 0xd4, 0x59, 0x18, 0xe6, 0x6e, 0x79, 0x0d,
+	0x8a, 0x7a, 0x23, 0x09, 0x7c, 0x0d, 0x4d, 0x85, 0x01, 0xa9, 0x36, 0x9d, 0xad, 0x5a, 0xe7, 0xc2,
+	0x71, 0xee, 0x4e, 0x3d, 0xbe, 0x3f, 0xcc, 0xdd, 0xb9, 0x30, 0xb8, 0x91, 0xc6, 0xa1, 0xe2, 0x71,
+	0x5f, 0x1d, 0xd1, 0xa9, 0x30, 0xc0, 0x37, 0xd1, 0x0c, 0x3f, 0xec, 0x87, 0x82, 0x4b, 0x32, 0x03,
+	0x67, 0x5d, 0x1d, 0xe6, 0xee, 0x92, 0x85, 0x4a, 0xb2, 0x85, 0xd4, 0xc6, 0x0b, 0xb4, 0x70, 0xea,
+	0x20, 0x78, 0x11, 0x55, 0xf6, 0xf9, 0x11, 0x38, 0xa4, 0x46, 0x35, 0x89, 0xdb, 0x68, 0x7a, 0xc0,
+	0xa2, 0x8c, 0x93, 0x29, 0x70, 0x12, 0x99, 0x64, 0xea, 0xdd, 0x50, 0x2a, 0x6a, 0xc4, 0xee, 0x4c,
+	0xbd, 0xeb, 0xb4, 0x1e, 0xa3, 0xda, 0x08, 0xc7, 0x3f, 0x1c, 0x39, 0xcb, 0xf9, 0x02, 0x67, 0xcd,
+	0x6b, 0xa3, 0x6b, 0xdb, 0x5a, 0x03, 0xd8, 0xb1, 0xf5, 0x2f, 0x07, 0x35, 0x9e, 0x8a, 0xf4, 0xf0,
+	0xc8, 0x9a, 0x4e, 0xe2, 0x0e, 0x5a, 0xe2, 0x89, 0x0a, 0xd5, 0x91, 0xc7, 0x94, 0x12, 0xe1, 0x5e,
+	0xa6, 0xb8, 0x59, 0xba, 0x66, 0x2f, 0x7c, 0x9a, 0x49, 0x17, 0x0d, 0x74, 0x6f, 0x84, 0x60, 0x17,
+	0x4d, 0xcb, 0x7e, 0xc4, 0x8e, 0xe0, 0x52, 0xb3, 0x9d, 0xda, 0x30, 0x77, 0x0d, 0x40, 0xcd, 0x80,
+	0xdf, 0x43, 0xf3, 0x40, 0x78, 0x7e, 0x3a, 0xe0, 0x82, 0x75, 0x39, 0xa9, 0x34, 0x9d, 0xad, 0x46,
+	0x07, 0x0f, 0x73, 0xf7, 0x14, 0x87, 0x36, 0x60, 0xbe, 0x63, 0xa7, 0xf8, 0x21, 0x5a, 0x30, 0x02,
+	0xaa, 0x27, 0xb8, 0xec, 0xa5, 0x91, 0x09, 0x9d, 0x46, 0xe7, 0xf2, 0x30, 0x77, 0xd7, 0x4f, 0xb1,
+	0x4a, 0x6e, 0x31, 0xcb, 0x3e, 0x2f, 0x38, 0xad, 0x3f, 0x3b, 0x68, 0xf1, 0xa9, 0x48, 0x63, 0xae,
+	0x7a, 0x3c, 0x93, 0xcf, 0x7c, 0xc1, 0xfa, 0x1c, 0x37, 0x51, 0x25, 0x13, 0x91, 0xf1, 0x4f, 0x67,
+	0xfe, 0x38, 0x77, 0x2b, 0x1f, 0xd1, 0xdd, 0x61, 0xee, 0x6a, 0x94, 0xea, 0x1f, 0x1d, 0x05, 0x31,
+	0x57, 0x22, 0xf4, 0xcd, 0xe3, 0xb0, 0x46, 0xb1, 0x50, 0x39, 0x0a, 0x2c, 0x84, 0x3f, 0x42, 0x33,
+	0x82, 0x47, 0x6c, 0x8f, 0x47, 0xf6, 0x69, 0x34, 0x4f, 0x39, 0xe8, 0xe4, 0x10, 0xd4, 0xc8, 0x75,
+	0xd6, 0xad, 0xb3, 0x96, 0xac, 0x62, 0x79, 0x59, 0x0b, 0xb5, 0xfe, 0x38, 0x85, 0x96, 0xce, 0x68,
+	0xe2, 0xbb, 0xa8, 0x21, 0xd3, 0x4c, 0xf8, 0xdc, 0x83, 0x79, 0xe1, 0xb8, 0x8b, 0xc3, 0xdc, 0x5d,
+	0x1b, 0x63, 0x94, 0x96, 0x9c, 0x33, 0x8c, 0x5d, 0xc0, 0xf1, 0x75, 0x34, 0x2d, 0x78, 0x97, 0x1f,
+	0x82, 0xeb, 0x6a, 0x9d, 0xe5, 0x61, 0xee, 0x2e, 0x00, 0x50, 0xd2, 0x30, 0x12, 0xf8, 0x47, 0x68,
+	0x4e, 0x31, 0xd1, 0xe5, 0xca, 0x2b, 0xae, 0xa7, 0x35, 0x36, 0x86, 0xb9, 0x7b, 0xa1, 0x8c, 0x97,
+	0x14, 0xeb, 0x06, 0x87, 0xad, 0xf0, 0xfb, 0xa8, 0x2e, 0x78, 0x3f, 0x62, 0x3e, 0x8f, 0x79, 0xa2,
+	0xc0, 0x89, 0xb5, 0xce, 0xfa, 0x30, 0x77, 0x57, 0x4b, 0x70, 0x59, 0xb9, 0x04, 0xe3, 0x1b, 0xa8,
+	0xca, 0x7c, 0x15, 0xa6, 0x09, 0x99, 0x06, 0xbd, 0x95, 0x61, 0xee, 0x2e, 0x1a, 0xa4, 0xa4, 0x62,
+	0x65, 0x5a, 0x7f, 0x71, 0xd0, 0x7c, 0x91, 0xee, 0x14, 0x0b, 0x13, 0x2e, 0x74, 0x88, 0x86, 0xb1,
+	0x0e, 0x3c, 0xe3, 0x6b, 0x08, 0x51, 0x00, 0xa8, 0x19, 0xb4, 0xa3, 0x45, 0x96, 0xe8, 0x3c, 0x6b,
+	0x4d, 0x01, 0x8e, 0xb6, 0xd0, 0x98, 0x47, 0x0c, 0xa4, 0x15, 0x06, 0x69, 0x94, 0xc5, 0xdc, 0xe4,
+	0x40, 0xab, 0x60, 0xa1, 0xb2, 0x82, 0x85, 0xb4, 0x42, 0xc2, 0xd5, 0x41, 0x2a, 0xf6, 0xed, 0xe5,
+	0x41, 0xc1, 0x42, 0x65, 0x05, 0x0b, 0xb5, 0x7e, 0x3f, 0x85, 0x1a, 0x0f, 0x06, 0x3c, 0x51, 0xbb,
+	0x69, 0xf7, 0x67, 0x19, 0x17, 0x47, 0xf8, 0x2a, 0x9a, 0xf1, 0x7b, 0x2c, 0x49, 0x78, 0x11, 0xb3,
+	0xf5, 0x61, 0xee, 0x16, 0x10, 0x2d, 0x08, 0x6d, 0xad, 0x88, 0x0f, 0x78, 0x54, 0xc4, 0x2c, 0x58,
+	0xcb, 0x20, 0x65, 0x6b, 0x19, 0x04, 0xdf, 0x46, 0xb5, 0xbe, 0x48, 0x07, 0x61, 0xc0, 0x45, 0x71,
+	0x95, 0xb5, 0x61, 0xee, 0x2e, 0x8f, 0xc0, 0x92, 0xce, 0x89, 0x24, 0xbe, 0x8b, 0x6a, 0x5c, 0x1f,
+	0xce, 0x0b, 0x03, 0x49, 0xce, 0x37, 0x2b, 0x5b, 0x8d, 0xce, 0x95, 0xe3, 0xdc, 0x9d, 0x85, 0x13,
+	0x3f, 0xbe, 0x2f, 0xf5, 0x12, 0x23, 0x81, 0xd2, 0x12, 0xb3, 0x00, 0x3e, 0x0e, 0x24, 0xbe, 0x83,
+	0xea, 0x31, 0x3b, 0xf4, 0x04, 0xf7, 0x53, 0x11, 0x48, 0xf0, 0x6c, 0xc3, 0x44, 0x44, 0x09, 0x2e,
+	0x69, 0xa2, 0x98, 0x1d, 0x52, 0x83, 0xb6, 0xfe, 0xd0, 0x40, 0xf5, 0x52, 0x45, 0xc3, 0x04, 0xcd,
+	0xf8, 0x69, 0x1c, 0xb3, 0x24, 0xb0, 0xd9, 0xb6, 0x98, 0xe2, 0x2d, 0x34, 0xdb, 0x63, 0x49, 0x10,
+	0x9d, 0xdc, 0x6e, 0x6e, 0x98, 0xbb, 0x23, 0x8c, 0x8e, 0x28, 0xfc, 0x63, 0xb4, 0xdc, 0x0b, 0xbb,
+	0x3d, 0xef, 0x65, 0xc4, 0xfa, 0x67, 0xd2, 0x0d, 0x98, 0x64, 0x02, 0x9b, 0x2e, 0x69, 0xf0, 0x61,
+	0xc4, 0xfa, 0xa3, 0x5c, 0xa3, 0xb7, 0x0c, 0x13, 0xc5, 0xc5, 0x80, 0x45, 0xf6, 0x56, 0xb0, 0x65,
+	0x81, 0xd1, 0x11, 0x85, 0xef, 0x23, 0x1c, 0xa5, 0x07, 0xa7, 0x77, 0xac, 0x82, 0xce, 0x85, 0x61,
+	0xee, 0x4e, 0xe0, 0xd2, 0xc5, 0x28, 0x3d, 0x18, 0xdf, 0xef, 0x2a, 0x9a, 0xe9, 0x67, 0x7b, 0x51,
+	0x28, 0x7b, 0xa4, 0x06, 0x19, 0x18, 0xc2, 0xc2, 0x42, 0xb4, 0x20, 0x74, 0x16, 0xb6, 0xc1, 0x5b,
+	0xd4, 0x56, 0x04, 0xf6, 0x80, 0x2c, 0x3c, 0xce, 0xa1, 0x0d, 0x3b, 0xb7, 0x45, 0xf3, 0x07, 0xa8,
+	0x21, 0xb3, 0x3d, 0xe9, 0x8b, 0xb0, 0xaf, 0x5f, 0x98, 0x24, 0x75, 0xd0, 0x5c, 0x1a, 0xe6, 0xee,
+	0x38, 0x83, 0x8e, 0x4f, 0xf1, 0x6d, 0x84, 0x1f, 0x1c, 0x2a, 0x9e, 0x04, 0x3c, 0x38, 0x29, 0x18,
+	0x64, 0xae, 0xe9, 0x6c, 0xcd, 0x75, 0xa6, 0x87, 0xb9, 0xeb, 0xbc, 0x4d, 0x27, 0x08, 0xe0, 0xe7,
+	0x68, 0xa9, 0xaf, 0xcb, 0x94, 0x67, 0xcb, 0x4f, 0xc2, 0x62, 0x4e, 0x1a, 0x10, 0xf2, 0x5b, 0xc7,
+	0xb9, 0xbb, 0x00, 0x35, 0xec, 0x01, 0xf0, 0x3e, 0x64, 0x31, 0xd7, 0x0f, 0xe9, 0x8c, 0x3c, 0x5d,
+	0xe8, 0x8f, 0x4b, 0xe1, 0x0f, 0x6c, 0xff, 0xe6, 0x99, 0xd6, 0x65, 0x1e, 0xf2, 0xf3, 0xda, 0x84,
+	0xd6, 0x45, 0x57, 0xda, 0xce, 0xb2, 0x4d, 0xcb, 0x65, 0x1d, 0x8a, 0x60, 0xa2, 0x65, 0x4c, 0xd9,
+	0x53, 0x41, 0x98, 0x90, 0x85, 0x52, 0xd9, 0xd3, 0x00, 0x35, 0x03, 0xbe, 0x87, 0xaa, 0x32, 0xdb,
+	0x0b, 0x32, 0x4e, 0x16, 0xa1, 0xda, 0x5f, 0x3e, 0xb5, 0xd5, 0xf3, 0x30, 0xe6, 0x2f, 0xa0, 0xa9,
+	0x7b, 0xd1, 0xe3, 0x89, 0x69, 0x86, 0x8c, 0x02, 0xb5, 0x23, 0xc6, 0xe8, 0xbc, 0x2f, 0xd2, 0x84,
+	0x2c, 0x41, 0x50, 0x03, 0x8d, 0xd7, 0x51, 0x45, 0xa9, 0x88, 0x60, 0xe8, 0x4a, 0x66, 0x74, 0xb9,
+	0x52, 0x2a, 0xa2, 0xfa, 0x47, 0x47, 0x82, 0xf6, 0x5a, 0x9a, 0x29, 0xb2, 0x0c, 0x41, 0x04, 0x91,
+	0x60, 0x21, 0x5a, 0x10, 0x78, 0x07, 0xcd, 0x1b, 0x73, 0x09, 0xdb, 0x06, 0x90, 0x15, 0x38, 0xe0,
+	0xa5, 0xb3, 0xb5, 0xea, 0xa4, 0x55, 0xa0, 0x8d, 0x7e, 0x79, 0x8a, 0xdf, 0x41, 0x75, 0x91, 0x66,
+	0x49, 0xe0, 0x89, 0x74, 0x2f, 0x4c, 0xc8, 0x2a, 0x18, 0x01, 0x5a, 0xaf, 0x12, 0x4c, 0x11, 0x4c,
+	0xa8, 0xa6, 0xf1, 0x4f, 0xd0, 0x4a, 0x9a, 0xa9, 0x7e, 0xa6, 0x3c, 0x53, 0x2d, 0xbd, 0x97, 0xa9,
+	0x88, 0x99, 0x22, 0x17, 0xc0, 0xb1, 0x64, 0x98, 0xbb, 0x13, 0xf9, 0x14, 0x1b, 0xf4, 0x03, 0x00,
+	0x1f, 0x02, 0x86, 0x9f, 0xa2, 0x0b, 0xe3, 0xb2, 0xa3, 0x47, 0xbe, 0xd6, 0xac, 0x14, 0x75, 0x69,
+	0xb2, 0x04, 0x5d, 0x29, 0xaf, 0xf7, 0xc8, 0xa2, 0xf8, 0x4d, 0x34, 0xcb, 0x93, 0x81, 0x37, 0x60,
+	0x42, 0x12, 0x72, 0x92, 0x28, 0x0a, 0x8c, 0xce, 0xf0, 0x64, 0xf0, 0x73, 0x26, 0x74, 0x89, 0x9f,
+	0xd5, 0xbd, 0x79, 0xc0, 0x14, 0x23, 0x1b, 0x4d, 0x67, 0x42, 0xfb, 0xfb, 0x64, 0xef, 0xd7, 0xdc,
+	0xd7, 0xeb, 0xb3, 0xce, 0xa6, 0x8e, 0xa2, 0xcf, 0x73, 0xd7, 0xd1, 0xaf, 0xb9, 0x50, 0x2b, 0xa7,
+	0xc3, 0x02, 0xc3, 0xd7, 0xd0, 0x82, 0xce, 0x7b, 0xf6, 0xcc, 0x32, 0xfc, 0x98, 0x93, 0x8b, 0xda,
+	0xc5, 0xb4, 0x11, 0xb3, 0xc3, 0x27, 0x80, 0x3e, 0x0b, 0x3f, 0xe6, 0xf8, 0x2a, 0x9a, 0x0f, 0x42,
+	0xe9, 0x33, 0x11, 0x58, 0x59, 0x72, 0x49, 0x9b, 0x9e, 0x36, 0x2c, 0x6a, 0x44, 0xf1, 0x75, 0xb4,
+	0x68, 0x97, 0x62, 0x42, 0x85, 0x2f, 0x99, 0xaf, 0x24, 0xb9, 0xac, 0xaf, 0x45, 0x17, 0x0c, 0x7e,
+	0xaf, 0x80, 0xf1, 0x0d, 0x84, 0xad, 0x89, 0x24, 0x8b, 0xfb, 0x11, 0xf7, 0x04, 0x53, 0x9c, 0x6c,
+	0xea, 0x00, 0xa2, 0x8b, 0x86, 0xf3, 0x0c, 0x18, 0x94, 0x29, 0x8e, 0x57, 0x51, 0x55, 0x64, 0x89,
+	0xc7, 0x14, 0x71, 0x61, 0xb9, 0x69, 0x91, 0x25, 0xf7, 0x14, 0xde, 0x85, 0x27, 0x6b, 0x1b, 0x14,
+	0x4f, 0x42, 0x83, 0x45, 0x9a, 0x60, 0x1e, 0xf7, 0x95, 0x2d, 0x90, 0xe9, 0xc3, 0xe8, 0x62, 0xff,
+	0x14, 0x82, 0xef, 0xa0, 0x75, 0x6d, 0x0c, 0x3f, 0x4d, 0xfc, 0x4c, 0x08, 0x5d, 0x45, 0xf8, 0x21,
+	0xf7, 0x33, 0x93, 0x7c, 0xde, 0x80, 0x93, 0xad, 0xc5, 0xec, 0x70, 0x67, 0xc4, 0x7f, 0x30, 0x62,
+	0xe3, 0xf7, 0x51, 0xcd, 0x2f, 0x0a, 0x3f, 0x69, 0x4d, 0x7c, 0x79, 0xe3, 0xdd, 0x01, 0x3d, 0x91,
+	0xc7, 0xef, 0x15, 0x65, 0x2d, 0x4a, 0xbb, 0xe4, 0xca, 0xc4, 0x57, 0x31, 0x56, 0x93, 0x6d, 0x3d,
+	0xdb, 0x4d, 0xbb, 0x77, 0x66, 0x7f, 0xfb, 0x89, 0x7b, 0xee, 0xd3, 0x4f, 0x5c, 0xa7, 0xf5, 0x57,
+	0x8c, 0xa6, 0x61, 0x8b, 0x6f, 0xeb, 0xd2, 0xff, 0x68, 0x5d, 0xfa, 0xb6, 0xc0, 0xfc, 0x3f, 0x16,
+	0x98, 0x0d, 0x34, 0x1b, 0x64, 0x82, 0xc1, 0x1f, 0x05, 0x5d, 0x54, 0x1c, 0x3a, 0x9a, 0xeb, 0xe0,
+	0x37, 0x29, 0x84, 0x07, 0x64, 0x0d, 0x6e, 0x66, 0xd2, 0xbb, 0xc5, 0xe8, 0x88, 0xc2, 0x0f, 0xd1,
+	0x4c, 0x2f, 0x94, 0x2a, 0x15, 0x47, 0x50, 0x07, 0xea, 0xdb, 0x17, 0x27, 0x65, 0x8f, 0x47, 0x46,
+	0xa4, 0xb3, 0x60, 0xbd, 0x58, 0xe8, 0xd0, 0x82, 0xd0, 0x1f, 0x3b, 0xcc, 0xa7, 0x0d, 0xb2, 0x7e,
+	0xf6, 0x63, 0x87, 0x19, 0xb5, 0x8c, 0x4d, 0xe2, 0x1b, 0x10, 0x7c, 0x20, 0x63, 0x10, 0x6a, 0x47,
+	0xbc, 0xa2, 0xc3, 0x80, 0x29, 0x53, 0x0e, 0x6a, 0xd4, 0x4c, 0xb4, 0xa6, 0x26, 0x32, 0x09, 0xe9,
+	0xbf, 0x61, 0x9d, 0x0b, 0x08, 0xb5, 0xa3, 0x7e, 0xc6, 0x2a, 0x55, 0x2c, 0xf2, 0x40, 0xc5, 0xd3,
+	0xff, 0x0f, 0xba, 0x9c, 0x5c, 0x3e, 0x79, 0xc6, 0x67, 0xb9, 0x74, 0x11, 0xb0, 0x67, 0x1a, 0xda,
+	0x01, 0x04, 0xb7, 0xd1, 0x4c, 0xc4, 0xa4, 0xf2, 0xd2, 0x7d, 0xa8, 0x09, 0x95, 0xce, 0xea, 0x71,
+	0xee, 0x56, 0x77, 0x99, 0x54, 0x4f, 0x7e, 0xaa, 0x2f, 0x6e, 0x99, 0xb4, 0xaa, 0x89, 0x27, 0xfb,
+	0xf8, 0x16, 0xaa, 0xa7, 0xbe, 0x49, 0xcb, 0x3e, 0x97, 0xc4, 0x05, 0x1d, 0xf0, 0x5b, 0x09, 0xa6,
+	0xe5, 0x09, 0xfe, 0x10, 0xad, 0x96, 0xa6, 0xde, 0x01, 0x53, 0x5c, 0xc4, 0x4c, 0xec, 0x43, 0x01,
+	0xa9, 0x98, 0x3f, 0x05, 0x13, 0x05, 0xe8, 0x4a, 0x09, 0x7e, 0x51, 0xa0, 0xb8, 0x89, 0x66, 0x65,
+	0x18, 0x69, 0x30, 0x20, 0x6f, 0x40, 0x4a, 0x30, 0x9f, 0xbc, 0x46, 0x28, 0xbe, 0x59, 0x7c, 0xc0,
+	0x6a, 0x81, 0x8b, 0x97, 0x27, 0x3c, 0x52, 0xab, 0x63, 0xe4, 0x5e, 0xd9, 0xbc, 0x5c, 0xf9, 0x5a,
+	0x9b, 0x97, 0xef, 0x7c, 0x0d, 0xcd, 0xcb, 0xd5, 0x2f, 0xdb, 0xbc, 0x5c, 0xfb, 0x46, 0x9b, 0x97,
+	0x37, 0xbf, 0x5c, 0xf3, 0xb2, 0x35, 0xa9, 0x79, 0xd9, 0x40, 0xb3, 0x82, 0xfb, 0x3c, 0x1c, 0xf0,
+	0x80, 0x5c, 0x87, 0x75, 0x46, 0x73, 0x7c, 0x09, 0xfe, 0xaf, 0xfa, 0x5c, 0x4a, 0x1e, 0x90, 0xef,
+	0x02, 0xf3, 0x04, 0x98, 0xd8, 0xf6, 0xbc, 0x35, 0xb9, 0xed, 0xb9, 0x8a, 0xe6, 0x0b, 0x19, 0x2f,
+	0x0a, 0x93, 0x7d, 0x49, 0x6e, 0x80, 0x60, 0xa3, 0x40, 0x77, 0x35, 0xf8, 0x8a, 0xee, 0xe8, 0xed,
+	0xd7, 0x76, 0x47, 0xed, 0xd7, 0x76, 0x47, 0x37, 0xbf, 0x91, 0xee, 0xe8, 0x9d, 0xaf, 0xd0, 0x1d,
+	0xdd, 0xfa, 0x6f, 0xba, 0xa3, 0xed, 0xaf, 0xd2, 0x1d, 0xbd, 0xe2, 0x9f, 0xa0, 0xff, 0x9a, 0x7f,
+	0x82, 0xa5, 0xa6, 0xea, 0x97, 0x68, 0xae, 0x9c, 0x78, 0x4b, 0x09, 0xd0, 0x79, 0x65, 0x02, 0x2c,
+	0x27, 0xfd, 0xa9, 0x2f, 0x4a, 0xfa, 0x9d, 0xe6, 0xbf, 0xff, 0xbe, 0xe9, 0x7c, 0x7a, 0xbc, 0xe9,
+	0xfc, 0xe9, 0x78, 0xd3, 0xf9, 0xec, 0x78, 0xd3, 0xf9, 0xfc, 0x78, 0xd3, 0xf9, 0xdb, 0xf1, 0xa6,
+	0xf3, 0xbb, 0x7f, 0x6c, 0x9e, 0xfb, 0xc5, 0xd4, 0x60, 0x7b, 0xaf, 0x0a, 0x1f, 0xde, 0xbf, 0xf7,
+	0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xe0, 0x62, 0xd5, 0x2a, 0x04, 0x18, 0x00, 0x00,
 }
 
 func (this *CheckRequest) Equal(that interface{}) bool {
@@ -677,6 +1150,12 @@ func (this *CheckRequest) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.ID != that1.ID {
+		return false
+	}
+	if this.Expires != that1.Expires {
+		return false
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -747,19 +1226,22 @@ func (this *ProxyRequests) Equal(that interface{}) bool {
 	if this.SplayCoverage != that1.SplayCoverage {
 		return false
 	}
+	if this.SplayThreshold != that1.SplayThreshold {
+		return false
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
 	return true
 }
-func (this *CheckConfig) Equal(that interface{}) bool {
+func (this *PrometheusScrape) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*CheckConfig)
+	that1, ok := that.(*PrometheusScrape)
 	if !ok {
-		that2, ok := that.(CheckConfig)
+		that2, ok := that.(PrometheusScrape)
 		if ok {
 			that1 = &that2
 		} else {
@@ -771,69 +1253,251 @@ func (this *CheckConfig) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Command != that1.Command {
+	if this.URL != that1.URL {
 		return false
 	}
-	if len(this.Handlers) != len(that1.Handlers) {
+	if len(this.Metrics) != len(that1.Metrics) {
 		return false
 	}
-	for i := range this.Handlers {
-		if this.Handlers[i] != that1.Handlers[i] {
+	for i := range this.Metrics {
+		if this.Metrics[i] != that1.Metrics[i] {
 			return false
 		}
 	}
-	if this.HighFlapThreshold != that1.HighFlapThreshold {
+	if len(this.Relabel) != len(that1.Relabel) {
 		return false
 	}
-	if this.Interval != that1.Interval {
-		return false
+	for i := range this.Relabel {
+		if !this.Relabel[i].Equal(&that1.Relabel[i]) {
+			return false
+		}
 	}
-	if this.LowFlapThreshold != that1.LowFlapThreshold {
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
-	if this.Publish != that1.Publish {
+	return true
+}
+func (this *PrometheusRelabel) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*PrometheusRelabel)
+	if !ok {
+		that2, ok := that.(PrometheusRelabel)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
 		return false
 	}
-	if len(this.RuntimeAssets) != len(that1.RuntimeAssets) {
+	if len(this.SourceLabels) != len(that1.SourceLabels) {
 		return false
 	}
-	for i := range this.RuntimeAssets {
-		if this.RuntimeAssets[i] != that1.RuntimeAssets[i] {
+	for i := range this.SourceLabels {
+		if this.SourceLabels[i] != that1.SourceLabels[i] {
 			return false
 		}
 	}
-	if len(this.Subscriptions) != len(that1.Subscriptions) {
+	if this.Regex != that1.Regex {
 		return false
 	}
-	for i := range this.Subscriptions {
-		if this.Subscriptions[i] != that1.Subscriptions[i] {
-			return false
-		}
+	if this.TargetLabel != that1.TargetLabel {
+		return false
 	}
-	if !bytes.Equal(this.ExtendedAttributes, that1.ExtendedAttributes) {
+	if this.Replacement != that1.Replacement {
 		return false
 	}
-	if this.ProxyEntityName != that1.ProxyEntityName {
+	if this.Action != that1.Action {
 		return false
 	}
-	if len(this.CheckHooks) != len(that1.CheckHooks) {
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
-	for i := range this.CheckHooks {
-		if !this.CheckHooks[i].Equal(&that1.CheckHooks[i]) {
+	return true
+}
+func (this *CheckContainer) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CheckContainer)
+	if !ok {
+		that2, ok := that.(CheckContainer)
+		if ok {
+			that1 = &that2
+		} else {
 			return false
 		}
 	}
-	if this.Stdin != that1.Stdin {
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
 		return false
 	}
-	if !this.Subdue.Equal(that1.Subdue) {
+	if this.Image != that1.Image {
 		return false
 	}
-	if this.Cron != that1.Cron {
+	if this.Runtime != that1.Runtime {
 		return false
 	}
-	if this.Ttl != that1.Ttl {
+	if len(this.Volumes) != len(that1.Volumes) {
+		return false
+	}
+	for i := range this.Volumes {
+		if this.Volumes[i] != that1.Volumes[i] {
+			return false
+		}
+	}
+	if this.Network != that1.Network {
+		return false
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *EventLogQuery) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*EventLogQuery)
+	if !ok {
+		that2, ok := that.(EventLogQuery)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Channel != that1.Channel {
+		return false
+	}
+	if len(this.Levels) != len(that1.Levels) {
+		return false
+	}
+	for i := range this.Levels {
+		if this.Levels[i] != that1.Levels[i] {
+			return false
+		}
+	}
+	if len(this.Providers) != len(that1.Providers) {
+		return false
+	}
+	for i := range this.Providers {
+		if this.Providers[i] != that1.Providers[i] {
+			return false
+		}
+	}
+	if len(this.EventIDs) != len(that1.EventIDs) {
+		return false
+	}
+	for i := range this.EventIDs {
+		if this.EventIDs[i] != that1.EventIDs[i] {
+			return false
+		}
+	}
+	if this.MaxRecords != that1.MaxRecords {
+		return false
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *CheckConfig) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CheckConfig)
+	if !ok {
+		that2, ok := that.(CheckConfig)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Command != that1.Command {
+		return false
+	}
+	if len(this.Handlers) != len(that1.Handlers) {
+		return false
+	}
+	for i := range this.Handlers {
+		if this.Handlers[i] != that1.Handlers[i] {
+			return false
+		}
+	}
+	if this.HighFlapThreshold != that1.HighFlapThreshold {
+		return false
+	}
+	if this.Interval != that1.Interval {
+		return false
+	}
+	if this.LowFlapThreshold != that1.LowFlapThreshold {
+		return false
+	}
+	if this.Publish != that1.Publish {
+		return false
+	}
+	if len(this.RuntimeAssets) != len(that1.RuntimeAssets) {
+		return false
+	}
+	for i := range this.RuntimeAssets {
+		if this.RuntimeAssets[i] != that1.RuntimeAssets[i] {
+			return false
+		}
+	}
+	if len(this.Subscriptions) != len(that1.Subscriptions) {
+		return false
+	}
+	for i := range this.Subscriptions {
+		if this.Subscriptions[i] != that1.Subscriptions[i] {
+			return false
+		}
+	}
+	if !bytes.Equal(this.ExtendedAttributes, that1.ExtendedAttributes) {
+		return false
+	}
+	if this.ProxyEntityName != that1.ProxyEntityName {
+		return false
+	}
+	if len(this.CheckHooks) != len(that1.CheckHooks) {
+		return false
+	}
+	for i := range this.CheckHooks {
+		if !this.CheckHooks[i].Equal(&that1.CheckHooks[i]) {
+			return false
+		}
+	}
+	if this.Stdin != that1.Stdin {
+		return false
+	}
+	if !this.Subdue.Equal(that1.Subdue) {
+		return false
+	}
+	if this.Cron != that1.Cron {
+		return false
+	}
+	if this.Ttl != that1.Ttl {
 		return false
 	}
 	if this.Timeout != that1.Timeout {
@@ -873,6 +1537,37 @@ func (this *CheckConfig) Equal(that interface{}) bool {
 	if this.DiscardOutput != that1.DiscardOutput {
 		return false
 	}
+	if len(this.OutputArtifacts) != len(that1.OutputArtifacts) {
+		return false
+	}
+	for i := range this.OutputArtifacts {
+		if this.OutputArtifacts[i] != that1.OutputArtifacts[i] {
+			return false
+		}
+	}
+	if this.MetricSampleRate != that1.MetricSampleRate {
+		return false
+	}
+	if len(this.RunAt) != len(that1.RunAt) {
+		return false
+	}
+	for i := range this.RunAt {
+		if this.RunAt[i] != that1.RunAt[i] {
+			return false
+		}
+	}
+	if !this.PrometheusScrape.Equal(that1.PrometheusScrape) {
+		return false
+	}
+	if this.MaxConcurrentExecutions != that1.MaxConcurrentExecutions {
+		return false
+	}
+	if !this.Container.Equal(that1.Container) {
+		return false
+	}
+	if !this.EventLog.Equal(that1.EventLog) {
+		return false
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -1050,6 +1745,51 @@ func (this *Check) Equal(that interface{}) bool {
 	if this.DiscardOutput != that1.DiscardOutput {
 		return false
 	}
+	if this.Received != that1.Received {
+		return false
+	}
+	if this.Processed != that1.Processed {
+		return false
+	}
+	if len(this.OutputArtifacts) != len(that1.OutputArtifacts) {
+		return false
+	}
+	for i := range this.OutputArtifacts {
+		if this.OutputArtifacts[i] != that1.OutputArtifacts[i] {
+			return false
+		}
+	}
+	if len(this.ArtifactLinks) != len(that1.ArtifactLinks) {
+		return false
+	}
+	for i := range this.ArtifactLinks {
+		if this.ArtifactLinks[i] != that1.ArtifactLinks[i] {
+			return false
+		}
+	}
+	if this.MetricSampleRate != that1.MetricSampleRate {
+		return false
+	}
+	if len(this.RunAt) != len(that1.RunAt) {
+		return false
+	}
+	for i := range this.RunAt {
+		if this.RunAt[i] != that1.RunAt[i] {
+			return false
+		}
+	}
+	if !this.PrometheusScrape.Equal(that1.PrometheusScrape) {
+		return false
+	}
+	if this.MaxConcurrentExecutions != that1.MaxConcurrentExecutions {
+		return false
+	}
+	if !this.Container.Equal(that1.Container) {
+		return false
+	}
+	if !this.EventLog.Equal(that1.EventLog) {
+		return false
+	}
 	if !bytes.Equal(this.ExtendedAttributes, that1.ExtendedAttributes) {
 		return false
 	}
@@ -1115,6 +1855,13 @@ type CheckConfigFace interface {
 	GetObjectMeta() ObjectMeta
 	GetMaxOutputSize() int64
 	GetDiscardOutput() bool
+	GetOutputArtifacts() []string
+	GetMetricSampleRate() uint32
+	GetRunAt() []string
+	GetPrometheusScrape() *PrometheusScrape
+	GetMaxConcurrentExecutions() uint32
+	GetContainer() *CheckContainer
+	GetEventLog() *EventLogQuery
 }
 
 func (this *CheckConfig) Proto() github_com_golang_protobuf_proto.Message {
@@ -1221,6 +1968,34 @@ func (this *CheckConfig) GetDiscardOutput() bool {
 	return this.DiscardOutput
 }
 
+func (this *CheckConfig) GetOutputArtifacts() []string {
+	return this.OutputArtifacts
+}
+
+func (this *CheckConfig) GetMetricSampleRate() uint32 {
+	return this.MetricSampleRate
+}
+
+func (this *CheckConfig) GetRunAt() []string {
+	return this.RunAt
+}
+
+func (this *CheckConfig) GetPrometheusScrape() *PrometheusScrape {
+	return this.PrometheusScrape
+}
+
+func (this *CheckConfig) GetMaxConcurrentExecutions() uint32 {
+	return this.MaxConcurrentExecutions
+}
+
+func (this *CheckConfig) GetContainer() *CheckContainer {
+	return this.Container
+}
+
+func (this *CheckConfig) GetEventLog() *EventLogQuery {
+	return this.EventLog
+}
+
 func NewCheckConfigFromFace(that CheckConfigFace) *CheckConfig {
 	this := &CheckConfig{}
 	this.Command = that.GetCommand()
@@ -1247,6 +2022,13 @@ func NewCheckConfigFromFace(that CheckConfigFace) *CheckConfig {
 	this.ObjectMeta = that.GetObjectMeta()
 	this.MaxOutputSize = that.GetMaxOutputSize()
 	this.DiscardOutput = that.GetDiscardOutput()
+	this.OutputArtifacts = that.GetOutputArtifacts()
+	this.MetricSampleRate = that.GetMetricSampleRate()
+	this.RunAt = that.GetRunAt()
+	this.PrometheusScrape = that.GetPrometheusScrape()
+	this.MaxConcurrentExecutions = that.GetMaxConcurrentExecutions()
+	this.Container = that.GetContainer()
+	this.EventLog = that.GetEventLog()
 	return this
 }
 
@@ -1288,6 +2070,16 @@ type CheckFace interface {
 	GetObjectMeta() ObjectMeta
 	GetMaxOutputSize() int64
 	GetDiscardOutput() bool
+	GetReceived() int64
+	GetProcessed() int64
+	GetOutputArtifacts() []string
+	GetArtifactLinks() []string
+	GetMetricSampleRate() uint32
+	GetRunAt() []string
+	GetPrometheusScrape() *PrometheusScrape
+	GetMaxConcurrentExecutions() uint32
+	GetContainer() *CheckContainer
+	GetEventLog() *EventLogQuery
 	GetExtendedAttributes() []byte
 }
 
@@ -1443,6 +2235,46 @@ func (this *Check) GetDiscardOutput() bool {
 	return this.DiscardOutput
 }
 
+func (this *Check) GetReceived() int64 {
+	return this.Received
+}
+
+func (this *Check) GetProcessed() int64 {
+	return this.Processed
+}
+
+func (this *Check) GetOutputArtifacts() []string {
+	return this.OutputArtifacts
+}
+
+func (this *Check) GetArtifactLinks() []string {
+	return this.ArtifactLinks
+}
+
+func (this *Check) GetMetricSampleRate() uint32 {
+	return this.MetricSampleRate
+}
+
+func (this *Check) GetRunAt() []string {
+	return this.RunAt
+}
+
+func (this *Check) GetPrometheusScrape() *PrometheusScrape {
+	return this.PrometheusScrape
+}
+
+func (this *Check) GetMaxConcurrentExecutions() uint32 {
+	return this.MaxConcurrentExecutions
+}
+
+func (this *Check) GetContainer() *CheckContainer {
+	return this.Container
+}
+
+func (this *Check) GetEventLog() *EventLogQuery {
+	return this.EventLog
+}
+
 func (this *Check) GetExtendedAttributes() []byte {
 	return this.ExtendedAttributes
 }
@@ -1485,6 +2317,16 @@ func NewCheckFromFace(that CheckFace) *Check {
 	this.ObjectMeta = that.GetObjectMeta()
 	this.MaxOutputSize = that.GetMaxOutputSize()
 	this.DiscardOutput = that.GetDiscardOutput()
+	this.Received = that.GetReceived()
+	this.Processed = that.GetProcessed()
+	this.OutputArtifacts = that.GetOutputArtifacts()
+	this.ArtifactLinks = that.GetArtifactLinks()
+	this.MetricSampleRate = that.GetMetricSampleRate()
+	this.RunAt = that.GetRunAt()
+	this.PrometheusScrape = that.GetPrometheusScrape()
+	this.MaxConcurrentExecutions = that.GetMaxConcurrentExecutions()
+	this.Container = that.GetContainer()
+	this.EventLog = that.GetEventLog()
 	this.ExtendedAttributes = that.GetExtendedAttributes()
 	return this
 }
@@ -1571,6 +2413,17 @@ func (m *CheckRequest) MarshalTo(dAtA []byte) (int, error) {
 			}
 		}
 	}
+	if len(m.ID) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if m.Expires != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.Expires))
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -1655,13 +2508,18 @@ func (m *ProxyRequests) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintCheck(dAtA, i, uint64(m.SplayCoverage))
 	}
+	if m.SplayThreshold != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.SplayThreshold))
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
 	return i, nil
 }
 
-func (m *CheckConfig) Marshal() (dAtA []byte, err error) {
+func (m *PrometheusScrape) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1671,20 +2529,20 @@ func (m *CheckConfig) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *CheckConfig) MarshalTo(dAtA []byte) (int, error) {
+func (m *PrometheusScrape) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.Command) > 0 {
+	if len(m.URL) > 0 {
 		dAtA[i] = 0xa
 		i++
-		i = encodeVarintCheck(dAtA, i, uint64(len(m.Command)))
-		i += copy(dAtA[i:], m.Command)
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.URL)))
+		i += copy(dAtA[i:], m.URL)
 	}
-	if len(m.Handlers) > 0 {
-		for _, s := range m.Handlers {
-			dAtA[i] = 0x1a
+	if len(m.Metrics) > 0 {
+		for _, s := range m.Metrics {
+			dAtA[i] = 0x12
 			i++
 			l = len(s)
 			for l >= 1<<7 {
@@ -1697,20 +2555,267 @@ func (m *CheckConfig) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], s)
 		}
 	}
-	if m.HighFlapThreshold != 0 {
-		dAtA[i] = 0x20
-		i++
-		i = encodeVarintCheck(dAtA, i, uint64(m.HighFlapThreshold))
+	if len(m.Relabel) > 0 {
+		for _, msg := range m.Relabel {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintCheck(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
 	}
-	if m.Interval != 0 {
-		dAtA[i] = 0x28
-		i++
-		i = encodeVarintCheck(dAtA, i, uint64(m.Interval))
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.LowFlapThreshold != 0 {
-		dAtA[i] = 0x30
-		i++
-		i = encodeVarintCheck(dAtA, i, uint64(m.LowFlapThreshold))
+	return i, nil
+}
+
+func (m *PrometheusRelabel) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PrometheusRelabel) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.SourceLabels) > 0 {
+		for _, s := range m.SourceLabels {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Regex) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.Regex)))
+		i += copy(dAtA[i:], m.Regex)
+	}
+	if len(m.TargetLabel) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.TargetLabel)))
+		i += copy(dAtA[i:], m.TargetLabel)
+	}
+	if len(m.Replacement) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.Replacement)))
+		i += copy(dAtA[i:], m.Replacement)
+	}
+	if len(m.Action) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.Action)))
+		i += copy(dAtA[i:], m.Action)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *CheckContainer) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CheckContainer) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Image) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.Image)))
+		i += copy(dAtA[i:], m.Image)
+	}
+	if len(m.Runtime) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.Runtime)))
+		i += copy(dAtA[i:], m.Runtime)
+	}
+	if len(m.Volumes) > 0 {
+		for _, s := range m.Volumes {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Network) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.Network)))
+		i += copy(dAtA[i:], m.Network)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *EventLogQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EventLogQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Channel) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.Channel)))
+		i += copy(dAtA[i:], m.Channel)
+	}
+	if len(m.Levels) > 0 {
+		for _, s := range m.Levels {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Providers) > 0 {
+		for _, s := range m.Providers {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.EventIDs) > 0 {
+		dAtA4 := make([]byte, len(m.EventIDs)*10)
+		var j3 int
+		for _, num := range m.EventIDs {
+			for num >= 1<<7 {
+				dAtA4[j3] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j3++
+			}
+			dAtA4[j3] = uint8(num)
+			j3++
+		}
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(j3))
+		i += copy(dAtA[i:], dAtA4[:j3])
+	}
+	if m.MaxRecords != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.MaxRecords))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *CheckConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CheckConfig) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Command) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.Command)))
+		i += copy(dAtA[i:], m.Command)
+	}
+	if len(m.Handlers) > 0 {
+		for _, s := range m.Handlers {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.HighFlapThreshold != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.HighFlapThreshold))
+	}
+	if m.Interval != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.Interval))
+	}
+	if m.LowFlapThreshold != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.LowFlapThreshold))
 	}
 	if m.Publish {
 		dAtA[i] = 0x48
@@ -1792,11 +2897,11 @@ func (m *CheckConfig) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintCheck(dAtA, i, uint64(m.Subdue.Size()))
-		n3, err := m.Subdue.MarshalTo(dAtA[i:])
+		n5, err := m.Subdue.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n3
+		i += n5
 	}
 	if len(m.Cron) > 0 {
 		dAtA[i] = 0x8a
@@ -1826,11 +2931,11 @@ func (m *CheckConfig) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintCheck(dAtA, i, uint64(m.ProxyRequests.Size()))
-		n4, err := m.ProxyRequests.MarshalTo(dAtA[i:])
+		n6, err := m.ProxyRequests.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n4
+		i += n6
 	}
 	if m.RoundRobin {
 		dAtA[i] = 0xa8
@@ -1891,11 +2996,11 @@ func (m *CheckConfig) MarshalTo(dAtA []byte) (int, error) {
 	dAtA[i] = 0x1
 	i++
 	i = encodeVarintCheck(dAtA, i, uint64(m.ObjectMeta.Size()))
-	n5, err := m.ObjectMeta.MarshalTo(dAtA[i:])
+	n7, err := m.ObjectMeta.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n5
+	i += n7
 	if m.MaxOutputSize != 0 {
 		dAtA[i] = 0xd8
 		i++
@@ -1915,6 +3020,90 @@ func (m *CheckConfig) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i++
 	}
+	if len(m.OutputArtifacts) > 0 {
+		for _, s := range m.OutputArtifacts {
+			dAtA[i] = 0xea
+			i++
+			dAtA[i] = 0x1
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.MetricSampleRate != 0 {
+		dAtA[i] = 0xf0
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.MetricSampleRate))
+	}
+	if len(m.RunAt) > 0 {
+		for _, s := range m.RunAt {
+			dAtA[i] = 0xfa
+			i++
+			dAtA[i] = 0x1
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.PrometheusScrape != nil {
+		dAtA[i] = 0x82
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.PrometheusScrape.Size()))
+		n8, err := m.PrometheusScrape.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	if m.MaxConcurrentExecutions != 0 {
+		dAtA[i] = 0x88
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.MaxConcurrentExecutions))
+	}
+	if m.Container != nil {
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.Container.Size()))
+		n9, err := m.Container.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	if m.EventLog != nil {
+		dAtA[i] = 0x9a
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.EventLog.Size()))
+		n10, err := m.EventLog.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -2046,11 +3235,11 @@ func (m *Check) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintCheck(dAtA, i, uint64(m.Subdue.Size()))
-		n6, err := m.Subdue.MarshalTo(dAtA[i:])
+		n11, err := m.Subdue.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n6
+		i += n11
 	}
 	if len(m.Cron) > 0 {
 		dAtA[i] = 0x8a
@@ -2080,11 +3269,11 @@ func (m *Check) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintCheck(dAtA, i, uint64(m.ProxyRequests.Size()))
-		n7, err := m.ProxyRequests.MarshalTo(dAtA[i:])
+		n12, err := m.ProxyRequests.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n7
+		i += n12
 	}
 	if m.RoundRobin {
 		dAtA[i] = 0xa8
@@ -2263,11 +3452,11 @@ func (m *Check) MarshalTo(dAtA []byte) (int, error) {
 	dAtA[i] = 0x2
 	i++
 	i = encodeVarintCheck(dAtA, i, uint64(m.ObjectMeta.Size()))
-	n8, err := m.ObjectMeta.MarshalTo(dAtA[i:])
+	n13, err := m.ObjectMeta.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n8
+	i += n13
 	if m.MaxOutputSize != 0 {
 		dAtA[i] = 0xb8
 		i++
@@ -2287,15 +3476,130 @@ func (m *Check) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i++
 	}
-	if len(m.ExtendedAttributes) > 0 {
-		dAtA[i] = 0x9a
+	if m.Received != 0 {
+		dAtA[i] = 0xc8
 		i++
-		dAtA[i] = 0x6
+		dAtA[i] = 0x2
 		i++
-		i = encodeVarintCheck(dAtA, i, uint64(len(m.ExtendedAttributes)))
-		i += copy(dAtA[i:], m.ExtendedAttributes)
+		i = encodeVarintCheck(dAtA, i, uint64(m.Received))
 	}
-	if m.XXX_unrecognized != nil {
+	if m.Processed != 0 {
+		dAtA[i] = 0xd0
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.Processed))
+	}
+	if len(m.OutputArtifacts) > 0 {
+		for _, s := range m.OutputArtifacts {
+			dAtA[i] = 0xda
+			i++
+			dAtA[i] = 0x2
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.ArtifactLinks) > 0 {
+		for _, s := range m.ArtifactLinks {
+			dAtA[i] = 0xe2
+			i++
+			dAtA[i] = 0x2
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.MetricSampleRate != 0 {
+		dAtA[i] = 0xe8
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.MetricSampleRate))
+	}
+	if len(m.RunAt) > 0 {
+		for _, s := range m.RunAt {
+			dAtA[i] = 0xf2
+			i++
+			dAtA[i] = 0x2
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.PrometheusScrape != nil {
+		dAtA[i] = 0xfa
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.PrometheusScrape.Size()))
+		n14, err := m.PrometheusScrape.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	if m.MaxConcurrentExecutions != 0 {
+		dAtA[i] = 0x80
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.MaxConcurrentExecutions))
+	}
+	if m.Container != nil {
+		dAtA[i] = 0x8a
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.Container.Size()))
+		n15, err := m.Container.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
+	}
+	if m.EventLog != nil {
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(m.EventLog.Size()))
+		n16, err := m.EventLog.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
+	if len(m.ExtendedAttributes) > 0 {
+		dAtA[i] = 0x9a
+		i++
+		dAtA[i] = 0x6
+		i++
+		i = encodeVarintCheck(dAtA, i, uint64(len(m.ExtendedAttributes)))
+		i += copy(dAtA[i:], m.ExtendedAttributes)
+	}
+	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
 	return i, nil
@@ -2373,8 +3677,13 @@ func NewPopulatedCheckRequest(r randyCheck, easy bool) *CheckRequest {
 			this.HookAssets[randStringCheck(r)] = NewPopulatedAssetList(r, easy)
 		}
 	}
+	this.ID = string(randStringCheck(r))
+	this.Expires = int64(r.Int63())
+	if r.Intn(2) == 0 {
+		this.Expires *= -1
+	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedCheck(r, 6)
+		this.XXX_unrecognized = randUnrecognizedCheck(r, 8)
 	}
 	return this
 }
@@ -2404,46 +3713,127 @@ func NewPopulatedProxyRequests(r randyCheck, easy bool) *ProxyRequests {
 	}
 	this.Splay = bool(bool(r.Intn(2) == 0))
 	this.SplayCoverage = uint32(r.Uint32())
+	this.SplayThreshold = uint32(r.Uint32())
+	if !easy && r.Intn(10) != 0 {
+		this.XXX_unrecognized = randUnrecognizedCheck(r, 5)
+	}
+	return this
+}
+
+func NewPopulatedPrometheusScrape(r randyCheck, easy bool) *PrometheusScrape {
+	this := &PrometheusScrape{}
+	this.URL = string(randStringCheck(r))
+	v9 := r.Intn(10)
+	this.Metrics = make([]string, v9)
+	for i := 0; i < v9; i++ {
+		this.Metrics[i] = string(randStringCheck(r))
+	}
+	if r.Intn(10) != 0 {
+		v10 := r.Intn(5)
+		this.Relabel = make([]PrometheusRelabel, v10)
+		for i := 0; i < v10; i++ {
+			v11 := NewPopulatedPrometheusRelabel(r, easy)
+			this.Relabel[i] = *v11
+		}
+	}
 	if !easy && r.Intn(10) != 0 {
 		this.XXX_unrecognized = randUnrecognizedCheck(r, 4)
 	}
 	return this
 }
 
+func NewPopulatedPrometheusRelabel(r randyCheck, easy bool) *PrometheusRelabel {
+	this := &PrometheusRelabel{}
+	v12 := r.Intn(10)
+	this.SourceLabels = make([]string, v12)
+	for i := 0; i < v12; i++ {
+		this.SourceLabels[i] = string(randStringCheck(r))
+	}
+	this.Regex = string(randStringCheck(r))
+	this.TargetLabel = string(randStringCheck(r))
+	this.Replacement = string(randStringCheck(r))
+	this.Action = string(randStringCheck(r))
+	if !easy && r.Intn(10) != 0 {
+		this.XXX_unrecognized = randUnrecognizedCheck(r, 6)
+	}
+	return this
+}
+
+func NewPopulatedCheckContainer(r randyCheck, easy bool) *CheckContainer {
+	this := &CheckContainer{}
+	this.Image = string(randStringCheck(r))
+	this.Runtime = string(randStringCheck(r))
+	v13 := r.Intn(10)
+	this.Volumes = make([]string, v13)
+	for i := 0; i < v13; i++ {
+		this.Volumes[i] = string(randStringCheck(r))
+	}
+	this.Network = string(randStringCheck(r))
+	if !easy && r.Intn(10) != 0 {
+		this.XXX_unrecognized = randUnrecognizedCheck(r, 5)
+	}
+	return this
+}
+
+func NewPopulatedEventLogQuery(r randyCheck, easy bool) *EventLogQuery {
+	this := &EventLogQuery{}
+	this.Channel = string(randStringCheck(r))
+	v14 := r.Intn(10)
+	this.Levels = make([]string, v14)
+	for i := 0; i < v14; i++ {
+		this.Levels[i] = string(randStringCheck(r))
+	}
+	v15 := r.Intn(10)
+	this.Providers = make([]string, v15)
+	for i := 0; i < v15; i++ {
+		this.Providers[i] = string(randStringCheck(r))
+	}
+	v16 := r.Intn(10)
+	this.EventIDs = make([]uint32, v16)
+	for i := 0; i < v16; i++ {
+		this.EventIDs[i] = uint32(r.Uint32())
+	}
+	this.MaxRecords = uint32(r.Uint32())
+	if !easy && r.Intn(10) != 0 {
+		this.XXX_unrecognized = randUnrecognizedCheck(r, 6)
+	}
+	return this
+}
+
 func NewPopulatedCheckConfig(r randyCheck, easy bool) *CheckConfig {
 	this := &CheckConfig{}
 	this.Command = string(randStringCheck(r))
-	v9 := r.Intn(10)
-	this.Handlers = make([]string, v9)
-	for i := 0; i < v9; i++ {
+	v17 := r.Intn(10)
+	this.Handlers = make([]string, v17)
+	for i := 0; i < v17; i++ {
 		this.Handlers[i] = string(randStringCheck(r))
 	}
 	this.HighFlapThreshold = uint32(r.Uint32())
 	this.Interval = uint32(r.Uint32())
 	this.LowFlapThreshold = uint32(r.Uint32())
 	this.Publish = bool(bool(r.Intn(2) == 0))
-	v10 := r.Intn(10)
-	this.RuntimeAssets = make([]string, v10)
-	for i := 0; i < v10; i++ {
+	v18 := r.Intn(10)
+	this.RuntimeAssets = make([]string, v18)
+	for i := 0; i < v18; i++ {
 		this.RuntimeAssets[i] = string(randStringCheck(r))
 	}
-	v11 := r.Intn(10)
-	this.Subscriptions = make([]string, v11)
-	for i := 0; i < v11; i++ {
+	v19 := r.Intn(10)
+	this.Subscriptions = make([]string, v19)
+	for i := 0; i < v19; i++ {
 		this.Subscriptions[i] = string(randStringCheck(r))
 	}
-	v12 := r.Intn(100)
-	this.ExtendedAttributes = make([]byte, v12)
-	for i := 0; i < v12; i++ {
+	v20 := r.Intn(100)
+	this.ExtendedAttributes = make([]byte, v20)
+	for i := 0; i < v20; i++ {
 		this.ExtendedAttributes[i] = byte(r.Intn(256))
 	}
 	this.ProxyEntityName = string(randStringCheck(r))
 	if r.Intn(10) != 0 {
-		v13 := r.Intn(5)
-		this.CheckHooks = make([]HookList, v13)
-		for i := 0; i < v13; i++ {
-			v14 := NewPopulatedHookList(r, easy)
-			this.CheckHooks[i] = *v14
+		v21 := r.Intn(5)
+		this.CheckHooks = make([]HookList, v21)
+		for i := 0; i < v21; i++ {
+			v22 := NewPopulatedHookList(r, easy)
+			this.CheckHooks[i] = *v22
 		}
 	}
 	this.Stdin = bool(bool(r.Intn(2) == 0))
@@ -2461,25 +3851,46 @@ func NewPopulatedCheckConfig(r randyCheck, easy bool) *CheckConfig {
 	}
 	this.RoundRobin = bool(bool(r.Intn(2) == 0))
 	this.OutputMetricFormat = string(randStringCheck(r))
-	v15 := r.Intn(10)
-	this.OutputMetricHandlers = make([]string, v15)
-	for i := 0; i < v15; i++ {
+	v23 := r.Intn(10)
+	this.OutputMetricHandlers = make([]string, v23)
+	for i := 0; i < v23; i++ {
 		this.OutputMetricHandlers[i] = string(randStringCheck(r))
 	}
-	v16 := r.Intn(10)
-	this.EnvVars = make([]string, v16)
-	for i := 0; i < v16; i++ {
+	v24 := r.Intn(10)
+	this.EnvVars = make([]string, v24)
+	for i := 0; i < v24; i++ {
 		this.EnvVars[i] = string(randStringCheck(r))
 	}
-	v17 := NewPopulatedObjectMeta(r, easy)
-	this.ObjectMeta = *v17
+	v25 := NewPopulatedObjectMeta(r, easy)
+	this.ObjectMeta = *v25
 	this.MaxOutputSize = int64(r.Int63())
 	if r.Intn(2) == 0 {
 		this.MaxOutputSize *= -1
 	}
 	this.DiscardOutput = bool(bool(r.Intn(2) == 0))
+	v26 := r.Intn(10)
+	this.OutputArtifacts = make([]string, v26)
+	for i := 0; i < v26; i++ {
+		this.OutputArtifacts[i] = string(randStringCheck(r))
+	}
+	this.MetricSampleRate = uint32(r.Uint32())
+	v27 := r.Intn(10)
+	this.RunAt = make([]string, v27)
+	for i := 0; i < v27; i++ {
+		this.RunAt[i] = string(randStringCheck(r))
+	}
+	if r.Intn(10) != 0 {
+		this.PrometheusScrape = NewPopulatedPrometheusScrape(r, easy)
+	}
+	this.MaxConcurrentExecutions = uint32(r.Uint32())
+	if r.Intn(10) != 0 {
+		this.Container = NewPopulatedCheckContainer(r, easy)
+	}
+	if r.Intn(10) != 0 {
+		this.EventLog = NewPopulatedEventLogQuery(r, easy)
+	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedCheck(r, 29)
+		this.XXX_unrecognized = randUnrecognizedCheck(r, 36)
 	}
 	return this
 }
@@ -2487,32 +3898,32 @@ func NewPopulatedCheckConfig(r randyCheck, easy bool) *CheckConfig {
 func NewPopulatedCheck(r randyCheck, easy bool) *Check {
 	this := &Check{}
 	this.Command = string(randStringCheck(r))
-	v18 := r.Intn(10)
-	this.Handlers = make([]string, v18)
-	for i := 0; i < v18; i++ {
+	v28 := r.Intn(10)
+	this.Handlers = make([]string, v28)
+	for i := 0; i < v28; i++ {
 		this.Handlers[i] = string(randStringCheck(r))
 	}
 	this.HighFlapThreshold = uint32(r.Uint32())
 	this.Interval = uint32(r.Uint32())
 	this.LowFlapThreshold = uint32(r.Uint32())
 	this.Publish = bool(bool(r.Intn(2) == 0))
-	v19 := r.Intn(10)
-	this.RuntimeAssets = make([]string, v19)
-	for i := 0; i < v19; i++ {
+	v29 := r.Intn(10)
+	this.RuntimeAssets = make([]string, v29)
+	for i := 0; i < v29; i++ {
 		this.RuntimeAssets[i] = string(randStringCheck(r))
 	}
-	v20 := r.Intn(10)
-	this.Subscriptions = make([]string, v20)
-	for i := 0; i < v20; i++ {
+	v30 := r.Intn(10)
+	this.Subscriptions = make([]string, v30)
+	for i := 0; i < v30; i++ {
 		this.Subscriptions[i] = string(randStringCheck(r))
 	}
 	this.ProxyEntityName = string(randStringCheck(r))
 	if r.Intn(10) != 0 {
-		v21 := r.Intn(5)
-		this.CheckHooks = make([]HookList, v21)
-		for i := 0; i < v21; i++ {
-			v22 := NewPopulatedHookList(r, easy)
-			this.CheckHooks[i] = *v22
+		v31 := r.Intn(5)
+		this.CheckHooks = make([]HookList, v31)
+		for i := 0; i < v31; i++ {
+			v32 := NewPopulatedHookList(r, easy)
+			this.CheckHooks[i] = *v32
 		}
 	}
 	this.Stdin = bool(bool(r.Intn(2) == 0))
@@ -2538,11 +3949,11 @@ func NewPopulatedCheck(r randyCheck, easy bool) *Check {
 		this.Executed *= -1
 	}
 	if r.Intn(10) != 0 {
-		v23 := r.Intn(5)
-		this.History = make([]CheckHistory, v23)
-		for i := 0; i < v23; i++ {
-			v24 := NewPopulatedCheckHistory(r, easy)
-			this.History[i] = *v24
+		v33 := r.Intn(5)
+		this.History = make([]CheckHistory, v33)
+		for i := 0; i < v33; i++ {
+			v34 := NewPopulatedCheckHistory(r, easy)
+			this.History[i] = *v34
 		}
 	}
 	this.Issued = int64(r.Int63())
@@ -2565,39 +3976,73 @@ func NewPopulatedCheck(r randyCheck, easy bool) *Check {
 	if r.Intn(2) == 0 {
 		this.OccurrencesWatermark *= -1
 	}
-	v25 := r.Intn(10)
-	this.Silenced = make([]string, v25)
-	for i := 0; i < v25; i++ {
+	v35 := r.Intn(10)
+	this.Silenced = make([]string, v35)
+	for i := 0; i < v35; i++ {
 		this.Silenced[i] = string(randStringCheck(r))
 	}
 	if r.Intn(10) != 0 {
-		v26 := r.Intn(5)
-		this.Hooks = make([]*Hook, v26)
-		for i := 0; i < v26; i++ {
+		v36 := r.Intn(5)
+		this.Hooks = make([]*Hook, v36)
+		for i := 0; i < v36; i++ {
 			this.Hooks[i] = NewPopulatedHook(r, easy)
 		}
 	}
 	this.OutputMetricFormat = string(randStringCheck(r))
-	v27 := r.Intn(10)
-	this.OutputMetricHandlers = make([]string, v27)
-	for i := 0; i < v27; i++ {
+	v37 := r.Intn(10)
+	this.OutputMetricHandlers = make([]string, v37)
+	for i := 0; i < v37; i++ {
 		this.OutputMetricHandlers[i] = string(randStringCheck(r))
 	}
-	v28 := r.Intn(10)
-	this.EnvVars = make([]string, v28)
-	for i := 0; i < v28; i++ {
+	v38 := r.Intn(10)
+	this.EnvVars = make([]string, v38)
+	for i := 0; i < v38; i++ {
 		this.EnvVars[i] = string(randStringCheck(r))
 	}
-	v29 := NewPopulatedObjectMeta(r, easy)
-	this.ObjectMeta = *v29
+	v39 := NewPopulatedObjectMeta(r, easy)
+	this.ObjectMeta = *v39
 	this.MaxOutputSize = int64(r.Int63())
 	if r.Intn(2) == 0 {
 		this.MaxOutputSize *= -1
 	}
 	this.DiscardOutput = bool(bool(r.Intn(2) == 0))
-	v30 := r.Intn(100)
-	this.ExtendedAttributes = make([]byte, v30)
-	for i := 0; i < v30; i++ {
+	this.Received = int64(r.Int63())
+	if r.Intn(2) == 0 {
+		this.Received *= -1
+	}
+	this.Processed = int64(r.Int63())
+	if r.Intn(2) == 0 {
+		this.Processed *= -1
+	}
+	v40 := r.Intn(10)
+	this.OutputArtifacts = make([]string, v40)
+	for i := 0; i < v40; i++ {
+		this.OutputArtifacts[i] = string(randStringCheck(r))
+	}
+	v41 := r.Intn(10)
+	this.ArtifactLinks = make([]string, v41)
+	for i := 0; i < v41; i++ {
+		this.ArtifactLinks[i] = string(randStringCheck(r))
+	}
+	this.MetricSampleRate = uint32(r.Uint32())
+	v42 := r.Intn(10)
+	this.RunAt = make([]string, v42)
+	for i := 0; i < v42; i++ {
+		this.RunAt[i] = string(randStringCheck(r))
+	}
+	if r.Intn(10) != 0 {
+		this.PrometheusScrape = NewPopulatedPrometheusScrape(r, easy)
+	}
+	this.MaxConcurrentExecutions = uint32(r.Uint32())
+	if r.Intn(10) != 0 {
+		this.Container = NewPopulatedCheckContainer(r, easy)
+	}
+	if r.Intn(10) != 0 {
+		this.EventLog = NewPopulatedEventLogQuery(r, easy)
+	}
+	v43 := r.Intn(100)
+	this.ExtendedAttributes = make([]byte, v43)
+	for i := 0; i < v43; i++ {
 		this.ExtendedAttributes[i] = byte(r.Intn(256))
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -2638,9 +4083,9 @@ func randUTF8RuneCheck(r randyCheck) rune {
 	return rune(ru + 61)
 }
 func randStringCheck(r randyCheck) string {
-	v31 := r.Intn(100)
-	tmps := make([]rune, v31)
-	for i := 0; i < v31; i++ {
+	v44 := r.Intn(100)
+	tmps := make([]rune, v44)
+	for i := 0; i < v44; i++ {
 		tmps[i] = randUTF8RuneCheck(r)
 	}
 	return string(tmps)
@@ -2662,11 +4107,11 @@ func randFieldCheck(dAtA []byte, r randyCheck, fieldNumber int, wire int) []byte
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateCheck(dAtA, uint64(key))
-		v32 := r.Int63()
+		v45 := r.Int63()
 		if r.Intn(2) == 0 {
-			v32 *= -1
+			v45 *= -1
 		}
-		dAtA = encodeVarintPopulateCheck(dAtA, uint64(v32))
+		dAtA = encodeVarintPopulateCheck(dAtA, uint64(v45))
 	case 1:
 		dAtA = encodeVarintPopulateCheck(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -2729,6 +4174,13 @@ func (m *CheckRequest) Size() (n int) {
 			n += mapEntrySize + 1 + sovCheck(uint64(mapEntrySize))
 		}
 	}
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovCheck(uint64(l))
+	}
+	if m.Expires != 0 {
+		n += 1 + sovCheck(uint64(m.Expires))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2771,84 +4223,217 @@ func (m *ProxyRequests) Size() (n int) {
 	if m.SplayCoverage != 0 {
 		n += 1 + sovCheck(uint64(m.SplayCoverage))
 	}
+	if m.SplayThreshold != 0 {
+		n += 1 + sovCheck(uint64(m.SplayThreshold))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
 	return n
 }
 
-func (m *CheckConfig) Size() (n int) {
+func (m *PrometheusScrape) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.Command)
+	l = len(m.URL)
 	if l > 0 {
 		n += 1 + l + sovCheck(uint64(l))
 	}
-	if len(m.Handlers) > 0 {
-		for _, s := range m.Handlers {
+	if len(m.Metrics) > 0 {
+		for _, s := range m.Metrics {
 			l = len(s)
 			n += 1 + l + sovCheck(uint64(l))
 		}
 	}
-	if m.HighFlapThreshold != 0 {
-		n += 1 + sovCheck(uint64(m.HighFlapThreshold))
-	}
-	if m.Interval != 0 {
-		n += 1 + sovCheck(uint64(m.Interval))
+	if len(m.Relabel) > 0 {
+		for _, e := range m.Relabel {
+			l = e.Size()
+			n += 1 + l + sovCheck(uint64(l))
+		}
 	}
-	if m.LowFlapThreshold != 0 {
-		n += 1 + sovCheck(uint64(m.LowFlapThreshold))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
 	}
-	if m.Publish {
-		n += 2
+	return n
+}
+
+func (m *PrometheusRelabel) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	if len(m.RuntimeAssets) > 0 {
-		for _, s := range m.RuntimeAssets {
+	var l int
+	_ = l
+	if len(m.SourceLabels) > 0 {
+		for _, s := range m.SourceLabels {
 			l = len(s)
 			n += 1 + l + sovCheck(uint64(l))
 		}
 	}
-	if len(m.Subscriptions) > 0 {
-		for _, s := range m.Subscriptions {
-			l = len(s)
-			n += 1 + l + sovCheck(uint64(l))
-		}
+	l = len(m.Regex)
+	if l > 0 {
+		n += 1 + l + sovCheck(uint64(l))
 	}
-	l = len(m.ExtendedAttributes)
+	l = len(m.TargetLabel)
 	if l > 0 {
 		n += 1 + l + sovCheck(uint64(l))
 	}
-	l = len(m.ProxyEntityName)
+	l = len(m.Replacement)
 	if l > 0 {
 		n += 1 + l + sovCheck(uint64(l))
 	}
-	if len(m.CheckHooks) > 0 {
-		for _, e := range m.CheckHooks {
-			l = e.Size()
-			n += 1 + l + sovCheck(uint64(l))
-		}
+	l = len(m.Action)
+	if l > 0 {
+		n += 1 + l + sovCheck(uint64(l))
 	}
-	if m.Stdin {
-		n += 2
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
 	}
-	if m.Subdue != nil {
-		l = m.Subdue.Size()
-		n += 2 + l + sovCheck(uint64(l))
+	return n
+}
+
+func (m *CheckContainer) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	l = len(m.Cron)
+	var l int
+	_ = l
+	l = len(m.Image)
 	if l > 0 {
-		n += 2 + l + sovCheck(uint64(l))
-	}
-	if m.Ttl != 0 {
-		n += 2 + sovCheck(uint64(m.Ttl))
+		n += 1 + l + sovCheck(uint64(l))
 	}
-	if m.Timeout != 0 {
-		n += 2 + sovCheck(uint64(m.Timeout))
+	l = len(m.Runtime)
+	if l > 0 {
+		n += 1 + l + sovCheck(uint64(l))
 	}
-	if m.ProxyRequests != nil {
+	if len(m.Volumes) > 0 {
+		for _, s := range m.Volumes {
+			l = len(s)
+			n += 1 + l + sovCheck(uint64(l))
+		}
+	}
+	l = len(m.Network)
+	if l > 0 {
+		n += 1 + l + sovCheck(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *EventLogQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Channel)
+	if l > 0 {
+		n += 1 + l + sovCheck(uint64(l))
+	}
+	if len(m.Levels) > 0 {
+		for _, s := range m.Levels {
+			l = len(s)
+			n += 1 + l + sovCheck(uint64(l))
+		}
+	}
+	if len(m.Providers) > 0 {
+		for _, s := range m.Providers {
+			l = len(s)
+			n += 1 + l + sovCheck(uint64(l))
+		}
+	}
+	if len(m.EventIDs) > 0 {
+		l = 0
+		for _, e := range m.EventIDs {
+			l += sovCheck(uint64(e))
+		}
+		n += 1 + sovCheck(uint64(l)) + l
+	}
+	if m.MaxRecords != 0 {
+		n += 1 + sovCheck(uint64(m.MaxRecords))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CheckConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Command)
+	if l > 0 {
+		n += 1 + l + sovCheck(uint64(l))
+	}
+	if len(m.Handlers) > 0 {
+		for _, s := range m.Handlers {
+			l = len(s)
+			n += 1 + l + sovCheck(uint64(l))
+		}
+	}
+	if m.HighFlapThreshold != 0 {
+		n += 1 + sovCheck(uint64(m.HighFlapThreshold))
+	}
+	if m.Interval != 0 {
+		n += 1 + sovCheck(uint64(m.Interval))
+	}
+	if m.LowFlapThreshold != 0 {
+		n += 1 + sovCheck(uint64(m.LowFlapThreshold))
+	}
+	if m.Publish {
+		n += 2
+	}
+	if len(m.RuntimeAssets) > 0 {
+		for _, s := range m.RuntimeAssets {
+			l = len(s)
+			n += 1 + l + sovCheck(uint64(l))
+		}
+	}
+	if len(m.Subscriptions) > 0 {
+		for _, s := range m.Subscriptions {
+			l = len(s)
+			n += 1 + l + sovCheck(uint64(l))
+		}
+	}
+	l = len(m.ExtendedAttributes)
+	if l > 0 {
+		n += 1 + l + sovCheck(uint64(l))
+	}
+	l = len(m.ProxyEntityName)
+	if l > 0 {
+		n += 1 + l + sovCheck(uint64(l))
+	}
+	if len(m.CheckHooks) > 0 {
+		for _, e := range m.CheckHooks {
+			l = e.Size()
+			n += 1 + l + sovCheck(uint64(l))
+		}
+	}
+	if m.Stdin {
+		n += 2
+	}
+	if m.Subdue != nil {
+		l = m.Subdue.Size()
+		n += 2 + l + sovCheck(uint64(l))
+	}
+	l = len(m.Cron)
+	if l > 0 {
+		n += 2 + l + sovCheck(uint64(l))
+	}
+	if m.Ttl != 0 {
+		n += 2 + sovCheck(uint64(m.Ttl))
+	}
+	if m.Timeout != 0 {
+		n += 2 + sovCheck(uint64(m.Timeout))
+	}
+	if m.ProxyRequests != nil {
 		l = m.ProxyRequests.Size()
 		n += 2 + l + sovCheck(uint64(l))
 	}
@@ -2879,6 +4464,36 @@ func (m *CheckConfig) Size() (n int) {
 	if m.DiscardOutput {
 		n += 3
 	}
+	if len(m.OutputArtifacts) > 0 {
+		for _, s := range m.OutputArtifacts {
+			l = len(s)
+			n += 2 + l + sovCheck(uint64(l))
+		}
+	}
+	if m.MetricSampleRate != 0 {
+		n += 2 + sovCheck(uint64(m.MetricSampleRate))
+	}
+	if len(m.RunAt) > 0 {
+		for _, s := range m.RunAt {
+			l = len(s)
+			n += 2 + l + sovCheck(uint64(l))
+		}
+	}
+	if m.PrometheusScrape != nil {
+		l = m.PrometheusScrape.Size()
+		n += 2 + l + sovCheck(uint64(l))
+	}
+	if m.MaxConcurrentExecutions != 0 {
+		n += 2 + sovCheck(uint64(m.MaxConcurrentExecutions))
+	}
+	if m.Container != nil {
+		l = m.Container.Size()
+		n += 2 + l + sovCheck(uint64(l))
+	}
+	if m.EventLog != nil {
+		l = m.EventLog.Size()
+		n += 2 + l + sovCheck(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -3033,6 +4648,48 @@ func (m *Check) Size() (n int) {
 	if m.DiscardOutput {
 		n += 3
 	}
+	if m.Received != 0 {
+		n += 2 + sovCheck(uint64(m.Received))
+	}
+	if m.Processed != 0 {
+		n += 2 + sovCheck(uint64(m.Processed))
+	}
+	if len(m.OutputArtifacts) > 0 {
+		for _, s := range m.OutputArtifacts {
+			l = len(s)
+			n += 2 + l + sovCheck(uint64(l))
+		}
+	}
+	if len(m.ArtifactLinks) > 0 {
+		for _, s := range m.ArtifactLinks {
+			l = len(s)
+			n += 2 + l + sovCheck(uint64(l))
+		}
+	}
+	if m.MetricSampleRate != 0 {
+		n += 2 + sovCheck(uint64(m.MetricSampleRate))
+	}
+	if len(m.RunAt) > 0 {
+		for _, s := range m.RunAt {
+			l = len(s)
+			n += 2 + l + sovCheck(uint64(l))
+		}
+	}
+	if m.PrometheusScrape != nil {
+		l = m.PrometheusScrape.Size()
+		n += 2 + l + sovCheck(uint64(l))
+	}
+	if m.MaxConcurrentExecutions != 0 {
+		n += 2 + sovCheck(uint64(m.MaxConcurrentExecutions))
+	}
+	if m.Container != nil {
+		l = m.Container.Size()
+		n += 2 + l + sovCheck(uint64(l))
+	}
+	if m.EventLog != nil {
+		l = m.EventLog.Size()
+		n += 2 + l + sovCheck(uint64(l))
+	}
 	l = len(m.ExtendedAttributes)
 	if l > 0 {
 		n += 2 + l + sovCheck(uint64(l))
@@ -3355,6 +5012,57 @@ func (m *CheckRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.HookAssets[mapkey] = mapvalue
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Expires", wireType)
+			}
+			m.Expires = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Expires |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCheck(dAtA[iNdEx:])
@@ -3499,7 +5207,731 @@ func (m *ProxyRequests) Unmarshal(dAtA []byte) error {
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EntityAttributes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EntityAttributes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EntityAttributes = append(m.EntityAttributes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Splay", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Splay = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplayCoverage", wireType)
+			}
+			m.SplayCoverage = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SplayCoverage |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplayThreshold", wireType)
+			}
+			m.SplayThreshold = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SplayThreshold |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheck(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrometheusScrape) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheck
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusScrape: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusScrape: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field URL", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.URL = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metrics = append(m.Metrics, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Relabel", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Relabel = append(m.Relabel, PrometheusRelabel{})
+			if err := m.Relabel[len(m.Relabel)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheck(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrometheusRelabel) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheck
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusRelabel: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusRelabel: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SourceLabels", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SourceLabels = append(m.SourceLabels, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Regex", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Regex = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TargetLabel", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TargetLabel = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Replacement", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Replacement = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Action", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Action = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheck(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CheckContainer) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheck
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CheckContainer: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CheckContainer: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Image", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Image = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Runtime", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Runtime = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Volumes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Volumes = append(m.Volumes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Network", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Network = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheck(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EventLogQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheck
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EventLogQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EventLogQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Channel", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Channel = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Levels", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3527,13 +5959,13 @@ func (m *ProxyRequests) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.EntityAttributes = append(m.EntityAttributes, string(dAtA[iNdEx:postIndex]))
+			m.Levels = append(m.Levels, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Splay", wireType)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Providers", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCheck
@@ -3543,17 +5975,105 @@ func (m *ProxyRequests) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Splay = bool(v != 0)
-		case 3:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Providers = append(m.Providers, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType == 0 {
+				var v uint32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowCheck
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.EventIDs = append(m.EventIDs, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowCheck
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthCheck
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthCheck
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.EventIDs) == 0 {
+					m.EventIDs = make([]uint32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowCheck
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.EventIDs = append(m.EventIDs, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventIDs", wireType)
+			}
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SplayCoverage", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRecords", wireType)
 			}
-			m.SplayCoverage = 0
+			m.MaxRecords = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCheck
@@ -3563,7 +6083,7 @@ func (m *ProxyRequests) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SplayCoverage |= uint32(b&0x7F) << shift
+				m.MaxRecords |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -4276,7 +6796,217 @@ func (m *CheckConfig) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			m.DiscardOutput = bool(v != 0)
+			m.DiscardOutput = bool(v != 0)
+		case 29:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OutputArtifacts", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OutputArtifacts = append(m.OutputArtifacts, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 30:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricSampleRate", wireType)
+			}
+			m.MetricSampleRate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MetricSampleRate |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 31:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RunAt", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RunAt = append(m.RunAt, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 32:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrometheusScrape", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PrometheusScrape == nil {
+				m.PrometheusScrape = &PrometheusScrape{}
+			}
+			if err := m.PrometheusScrape.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 33:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxConcurrentExecutions", wireType)
+			}
+			m.MaxConcurrentExecutions = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxConcurrentExecutions |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 34:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Container", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Container == nil {
+				m.Container = &CheckContainer{}
+			}
+			if err := m.Container.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 35:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventLog", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.EventLog == nil {
+				m.EventLog = &EventLogQuery{}
+			}
+			if err := m.EventLog.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCheck(dAtA[iNdEx:])
@@ -5260,6 +7990,286 @@ func (m *Check) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.DiscardOutput = bool(v != 0)
+		case 41:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Received", wireType)
+			}
+			m.Received = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Received |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 42:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Processed", wireType)
+			}
+			m.Processed = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Processed |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 43:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OutputArtifacts", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OutputArtifacts = append(m.OutputArtifacts, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 44:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArtifactLinks", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ArtifactLinks = append(m.ArtifactLinks, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 45:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricSampleRate", wireType)
+			}
+			m.MetricSampleRate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MetricSampleRate |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 46:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RunAt", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RunAt = append(m.RunAt, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 47:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrometheusScrape", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PrometheusScrape == nil {
+				m.PrometheusScrape = &PrometheusScrape{}
+			}
+			if err := m.PrometheusScrape.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 48:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxConcurrentExecutions", wireType)
+			}
+			m.MaxConcurrentExecutions = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxConcurrentExecutions |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 49:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Container", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Container == nil {
+				m.Container = &CheckContainer{}
+			}
+			if err := m.Container.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 50:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventLog", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheck
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheck
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCheck
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.EventLog == nil {
+				m.EventLog = &EventLogQuery{}
+			}
+			if err := m.EventLog.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 99:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field ExtendedAttributes", wireType)
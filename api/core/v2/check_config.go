@@ -90,7 +90,15 @@ func (c *CheckConfig) Validate() error {
 		}
 	}
 
-	if c.Interval == 0 && c.Cron == "" {
+	if len(c.RunAt) > 0 {
+		if c.Interval > 0 || c.Cron != "" {
+			return errors.New("must only specify either an interval, a cron schedule or run_at times")
+		}
+
+		if err := ValidateRunAt(c.RunAt); err != nil {
+			return err
+		}
+	} else if c.Interval == 0 && c.Cron == "" {
 		return errors.New("check interval must be greater than 0 or a valid cron schedule must be provided")
 	}
 
@@ -136,6 +144,30 @@ func (c *CheckConfig) Validate() error {
 		return err
 	}
 
+	if err := ValidateOutputArtifacts(c.OutputArtifacts); err != nil {
+		return err
+	}
+
+	if c.PrometheusScrape != nil {
+		if c.Command != "" {
+			return errors.New("must only specify either a command or a prometheus scrape")
+		}
+		if c.OutputMetricFormat != "" {
+			return errors.New("output metric format must not be set for a prometheus scrape")
+		}
+		if err := c.PrometheusScrape.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := validateCheckContainer(c.Container, c.Command, c.RuntimeAssets, c.PrometheusScrape); err != nil {
+		return err
+	}
+
+	if err := validateCheckEventLog(c.EventLog, c.Command, c.OutputMetricFormat, c.PrometheusScrape); err != nil {
+		return err
+	}
+
 	return c.Subdue.Validate()
 }
 
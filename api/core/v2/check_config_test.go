@@ -42,6 +42,24 @@ func TestCheckConfig(t *testing.T) {
 	assert.NoError(t, c.Validate())
 }
 
+func TestCheckConfigRunAtValidation(t *testing.T) {
+	c := FixtureCheckConfig("check")
+	c.RunAt = []string{"2019-07-01T02:00:00Z"}
+
+	// An interval and run_at times are exclusive
+	assert.Error(t, c.Validate())
+
+	c.Interval = 0
+	assert.NoError(t, c.Validate())
+
+	c.Cron = "* * * * *"
+	assert.Error(t, c.Validate())
+	c.Cron = ""
+
+	c.RunAt = []string{"tomorrow"}
+	assert.Error(t, c.Validate())
+}
+
 func TestCheckConfigHasNonNilSubscriptions(t *testing.T) {
 	var c CheckConfig
 	b, err := json.Marshal(&c)
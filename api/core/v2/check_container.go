@@ -0,0 +1,84 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// ContainerRuntimeDocker, ContainerRuntimePodman and
+	// ContainerRuntimeNerdctl are the container engines the agents can
+	// execute the check commands with.
+	ContainerRuntimeDocker  = "docker"
+	ContainerRuntimePodman  = "podman"
+	ContainerRuntimeNerdctl = "nerdctl"
+)
+
+// ContainerRuntimes are the container engines the agents can execute the
+// check commands with.
+var ContainerRuntimes = []string{ContainerRuntimeDocker, ContainerRuntimePodman, ContainerRuntimeNerdctl}
+
+// ValidateContainerRuntime returns an error if the container runtime is not
+// one of ContainerRuntimes. An empty runtime is valid.
+func ValidateContainerRuntime(runtime string) error {
+	if runtime == "" {
+		return nil
+	}
+	for _, r := range ContainerRuntimes {
+		if runtime == r {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid container runtime %q, must be one of: %s", runtime, strings.Join(ContainerRuntimes, ", "))
+}
+
+// FixtureCheckContainer returns a fixture for a CheckContainer object.
+func FixtureCheckContainer(image string) *CheckContainer {
+	return &CheckContainer{Image: image}
+}
+
+// Validate returns an error if the CheckContainer does not pass validation
+// tests.
+func (c *CheckContainer) Validate() error {
+	if c.Image == "" {
+		return errors.New("container image must not be empty")
+	}
+	if strings.HasPrefix(c.Image, "-") || strings.ContainsAny(c.Image, " \t\n") {
+		return fmt.Errorf("invalid container image %q", c.Image)
+	}
+	if err := ValidateContainerRuntime(c.Runtime); err != nil {
+		return err
+	}
+	for _, volume := range c.Volumes {
+		parts := strings.Split(volume, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid container volume %q, must be of the form host_path:container_path[:ro]", volume)
+		}
+		if len(parts) == 3 && parts[2] != "ro" && parts[2] != "rw" {
+			return fmt.Errorf("invalid container volume %q, its mode must be ro or rw", volume)
+		}
+	}
+	if strings.HasPrefix(c.Network, "-") {
+		return fmt.Errorf("invalid container network %q", c.Network)
+	}
+	return nil
+}
+
+// validateCheckContainer returns an error if the container of a check is
+// invalid, or if the check can not be executed in a container.
+func validateCheckContainer(container *CheckContainer, command string, runtimeAssets []string, scrape *PrometheusScrape) error {
+	if container == nil {
+		return nil
+	}
+	if scrape != nil {
+		return errors.New("must only specify either a container or a prometheus scrape")
+	}
+	if command == "" {
+		return errors.New("a command is required to execute the check in a container")
+	}
+	if len(runtimeAssets) > 0 {
+		return errors.New("runtime assets must not be set for a check executed in a container")
+	}
+	return container.Validate()
+}
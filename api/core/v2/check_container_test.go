@@ -0,0 +1,79 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckContainerValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		container *CheckContainer
+		wantErr   bool
+	}{
+		{
+			name:      "image only",
+			container: FixtureCheckContainer("alpine:3"),
+		},
+		{
+			name: "all fields",
+			container: &CheckContainer{
+				Image:   "registry.example.com/checks/disk:1.2",
+				Runtime: ContainerRuntimePodman,
+				Volumes: []string{"/var/log:/var/log:ro", "/tmp:/tmp"},
+				Network: "host",
+			},
+		},
+		{
+			name:      "missing image",
+			container: &CheckContainer{},
+			wantErr:   true,
+		},
+		{
+			name:      "image looking like a flag",
+			container: FixtureCheckContainer("--privileged"),
+			wantErr:   true,
+		},
+		{
+			name:      "unknown runtime",
+			container: &CheckContainer{Image: "alpine", Runtime: "lxc"},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid volume",
+			container: &CheckContainer{Image: "alpine", Volumes: []string{"/var/log"}},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid volume mode",
+			container: &CheckContainer{Image: "alpine", Volumes: []string{"/var/log:/var/log:z"}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.container.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckConfigValidateContainer(t *testing.T) {
+	check := FixtureCheckConfig("check")
+	check.RuntimeAssets = nil
+	check.Container = FixtureCheckContainer("alpine:3")
+	assert.NoError(t, check.Validate())
+
+	check.RuntimeAssets = []string{"ruby"}
+	assert.Error(t, check.Validate())
+
+	check.RuntimeAssets = nil
+	check.Command = ""
+	check.PrometheusScrape = FixturePrometheusScrape("http://localhost:9100/metrics")
+	assert.Error(t, check.Validate())
+}
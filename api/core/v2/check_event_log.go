@@ -0,0 +1,93 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// EventLogLevelCritical matches the critical records of the event log.
+	EventLogLevelCritical = "critical"
+
+	// EventLogLevelError matches the error records of the event log.
+	EventLogLevelError = "error"
+
+	// EventLogLevelWarning matches the warning records of the event log.
+	EventLogLevelWarning = "warning"
+
+	// EventLogLevelInformation matches the information records of the event
+	// log.
+	EventLogLevelInformation = "information"
+
+	// EventLogLevelVerbose matches the verbose records of the event log.
+	EventLogLevelVerbose = "verbose"
+
+	// DefaultEventLogMaxRecords is the maximum number of records read at every
+	// execution of the event log queries that do not specify one.
+	DefaultEventLogMaxRecords = 100
+)
+
+// EventLogLevels are the levels of the records of the event log, by
+// decreasing severity.
+var EventLogLevels = []string{
+	EventLogLevelCritical,
+	EventLogLevelError,
+	EventLogLevelWarning,
+	EventLogLevelInformation,
+	EventLogLevelVerbose,
+}
+
+// FixtureEventLogQuery returns a fixture for an EventLogQuery object.
+func FixtureEventLogQuery(channel string) *EventLogQuery {
+	return &EventLogQuery{Channel: channel}
+}
+
+// Validate returns an error if the EventLogQuery does not pass validation
+// tests.
+func (q *EventLogQuery) Validate() error {
+	if strings.TrimSpace(q.Channel) == "" {
+		return errors.New("event log channel must be set")
+	}
+	if strings.ContainsAny(q.Channel, "'\"<>*[]") {
+		return fmt.Errorf("invalid event log channel %q", q.Channel)
+	}
+	for _, level := range q.Levels {
+		if !stringsContain(EventLogLevels, level) {
+			return fmt.Errorf("invalid event log level %q, must be one of %v", level, EventLogLevels)
+		}
+	}
+	for _, provider := range q.Providers {
+		if provider == "" || strings.ContainsAny(provider, "'\"<>") {
+			return fmt.Errorf("invalid event log provider %q", provider)
+		}
+	}
+	return nil
+}
+
+// RecordsLimit returns the maximum number of records read at every execution
+// of the query.
+func (q *EventLogQuery) RecordsLimit() int {
+	if q.MaxRecords == 0 {
+		return DefaultEventLogMaxRecords
+	}
+	return int(q.MaxRecords)
+}
+
+// validateCheckEventLog returns an error if the event log query of a check is
+// invalid, or if the check specifies anything else to execute.
+func validateCheckEventLog(query *EventLogQuery, command, outputMetricFormat string, scrape *PrometheusScrape) error {
+	if query == nil {
+		return nil
+	}
+	if command != "" {
+		return errors.New("must only specify either a command or an event log query")
+	}
+	if scrape != nil {
+		return errors.New("must only specify either a prometheus scrape or an event log query")
+	}
+	if outputMetricFormat != "" {
+		return errors.New("output metric format must not be set for an event log query")
+	}
+	return query.Validate()
+}
@@ -0,0 +1,80 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventLogQueryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *EventLogQuery
+		wantErr bool
+	}{
+		{
+			name:  "channel only",
+			query: FixtureEventLogQuery("System"),
+		},
+		{
+			name: "all fields",
+			query: &EventLogQuery{
+				Channel:    "Microsoft-Windows-Sysmon/Operational",
+				Levels:     []string{EventLogLevelCritical, EventLogLevelError},
+				Providers:  []string{"Service Control Manager"},
+				EventIDs:   []uint32{7031, 7034},
+				MaxRecords: 10,
+			},
+		},
+		{
+			name:    "missing channel",
+			query:   &EventLogQuery{},
+			wantErr: true,
+		},
+		{
+			name:    "channel injecting xpath",
+			query:   FixtureEventLogQuery("System']"),
+			wantErr: true,
+		},
+		{
+			name:    "unknown level",
+			query:   &EventLogQuery{Channel: "System", Levels: []string{"fatal"}},
+			wantErr: true,
+		},
+		{
+			name:    "provider injecting xpath",
+			query:   &EventLogQuery{Channel: "System", Providers: []string{"a' or @Name='b"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.query.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("EventLogQuery.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventLogQueryRecordsLimit(t *testing.T) {
+	query := FixtureEventLogQuery("System")
+	assert.Equal(t, DefaultEventLogMaxRecords, query.RecordsLimit())
+	query.MaxRecords = 10
+	assert.Equal(t, 10, query.RecordsLimit())
+}
+
+func TestCheckConfigValidateEventLog(t *testing.T) {
+	check := FixtureCheckConfig("check")
+	check.EventLog = FixtureEventLogQuery("System")
+	assert.Error(t, check.Validate())
+
+	check.Command = ""
+	assert.NoError(t, check.Validate())
+
+	check.OutputMetricFormat = NagiosOutputMetricFormat
+	assert.Error(t, check.Validate())
+
+	check.OutputMetricFormat = ""
+	check.PrometheusScrape = FixturePrometheusScrape("http://localhost:9090/metrics")
+	assert.Error(t, check.Validate())
+}
@@ -0,0 +1,121 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+)
+
+const (
+	// PrometheusRelabelReplace sets the target label to the replacement when
+	// the regex matches the source labels.
+	PrometheusRelabelReplace = "replace"
+
+	// PrometheusRelabelKeep drops the samples whose source labels do not
+	// match the regex.
+	PrometheusRelabelKeep = "keep"
+
+	// PrometheusRelabelDrop drops the samples whose source labels match the
+	// regex.
+	PrometheusRelabelDrop = "drop"
+
+	// PrometheusRelabelLabelDrop removes the labels whose names match the
+	// regex.
+	PrometheusRelabelLabelDrop = "labeldrop"
+
+	// PrometheusRelabelLabelKeep removes the labels whose names do not match
+	// the regex.
+	PrometheusRelabelLabelKeep = "labelkeep"
+
+	// DefaultPrometheusRelabelRegex is the regex of the relabeling rules that
+	// do not specify one.
+	DefaultPrometheusRelabelRegex = "(.*)"
+
+	// DefaultPrometheusRelabelReplacement is the replacement of the
+	// relabeling rules that do not specify one.
+	DefaultPrometheusRelabelReplacement = "$1"
+)
+
+// FixturePrometheusScrape returns a fixture for a PrometheusScrape object.
+func FixturePrometheusScrape(url string) *PrometheusScrape {
+	return &PrometheusScrape{URL: url}
+}
+
+// Validate returns an error if the PrometheusScrape does not pass validation
+// tests.
+func (p *PrometheusScrape) Validate() error {
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return fmt.Errorf("invalid prometheus scrape url: %s", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("prometheus scrape url must be an http or https url")
+	}
+
+	for _, metric := range p.Metrics {
+		if _, err := path.Match(metric, ""); err != nil || metric == "" {
+			return fmt.Errorf("invalid prometheus scrape metric %q", metric)
+		}
+	}
+
+	for i := range p.Relabel {
+		if err := p.Relabel[i].Validate(); err != nil {
+			return fmt.Errorf("prometheus scrape relabel rule %d: %s", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if the PrometheusRelabel does not pass validation
+// tests.
+func (r *PrometheusRelabel) Validate() error {
+	if _, err := r.Compile(); err != nil {
+		return fmt.Errorf("invalid regex: %s", err)
+	}
+
+	switch r.ActionOrDefault() {
+	case PrometheusRelabelReplace:
+		if r.TargetLabel == "" {
+			return errors.New("the replace action requires a target label")
+		}
+		fallthrough
+	case PrometheusRelabelKeep, PrometheusRelabelDrop:
+		if len(r.SourceLabels) == 0 {
+			return fmt.Errorf("the %s action requires source labels", r.ActionOrDefault())
+		}
+	case PrometheusRelabelLabelDrop, PrometheusRelabelLabelKeep:
+	default:
+		return fmt.Errorf("invalid action %q", r.Action)
+	}
+	return nil
+}
+
+// ActionOrDefault returns the action of the rule, or the replace action if it
+// has none.
+func (r *PrometheusRelabel) ActionOrDefault() string {
+	if r.Action == "" {
+		return PrometheusRelabelReplace
+	}
+	return r.Action
+}
+
+// ReplacementOrDefault returns the replacement of the rule, or the default one
+// if it has none.
+func (r *PrometheusRelabel) ReplacementOrDefault() string {
+	if r.Replacement == "" {
+		return DefaultPrometheusRelabelReplacement
+	}
+	return r.Replacement
+}
+
+// Compile returns the regex of the rule, or the default one if it has none,
+// anchored at both ends like in Prometheus.
+func (r *PrometheusRelabel) Compile() (*regexp.Regexp, error) {
+	regex := r.Regex
+	if regex == "" {
+		regex = DefaultPrometheusRelabelRegex
+	}
+	return regexp.Compile("^(?:" + regex + ")$")
+}
@@ -0,0 +1,140 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusScrapeValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		scrape  *PrometheusScrape
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			scrape: FixturePrometheusScrape("http://localhost:9100/metrics"),
+		},
+		{
+			name:    "missing url",
+			scrape:  &PrometheusScrape{},
+			wantErr: true,
+		},
+		{
+			name:    "not http",
+			scrape:  FixturePrometheusScrape("file:///metrics"),
+			wantErr: true,
+		},
+		{
+			name: "metric patterns",
+			scrape: &PrometheusScrape{
+				URL:     "https://localhost:9100/metrics",
+				Metrics: []string{"node_cpu_seconds_total", "node_memory_*"},
+			},
+		},
+		{
+			name: "invalid metric pattern",
+			scrape: &PrometheusScrape{
+				URL:     "https://localhost:9100/metrics",
+				Metrics: []string{"node_["},
+			},
+			wantErr: true,
+		},
+		{
+			name: "relabel rules",
+			scrape: &PrometheusScrape{
+				URL: "http://localhost:9100/metrics",
+				Relabel: []PrometheusRelabel{
+					{SourceLabels: []string{"__name__"}, Regex: "go_.*", Action: PrometheusRelabelDrop},
+					{SourceLabels: []string{"instance"}, TargetLabel: "host"},
+					{Regex: "instance", Action: PrometheusRelabelLabelDrop},
+				},
+			},
+		},
+		{
+			name: "replace without target label",
+			scrape: &PrometheusScrape{
+				URL:     "http://localhost:9100/metrics",
+				Relabel: []PrometheusRelabel{{SourceLabels: []string{"instance"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "keep without source labels",
+			scrape: &PrometheusScrape{
+				URL:     "http://localhost:9100/metrics",
+				Relabel: []PrometheusRelabel{{Regex: "go_.*", Action: PrometheusRelabelKeep}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid regex",
+			scrape: &PrometheusScrape{
+				URL:     "http://localhost:9100/metrics",
+				Relabel: []PrometheusRelabel{{Regex: "(", Action: PrometheusRelabelLabelDrop}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid action",
+			scrape: &PrometheusScrape{
+				URL:     "http://localhost:9100/metrics",
+				Relabel: []PrometheusRelabel{{Action: "hashmod"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.scrape.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPrometheusRelabelDefaults(t *testing.T) {
+	rule := &PrometheusRelabel{}
+	assert.Equal(t, PrometheusRelabelReplace, rule.ActionOrDefault())
+	assert.Equal(t, DefaultPrometheusRelabelReplacement, rule.ReplacementOrDefault())
+
+	regex, err := rule.Compile()
+	require.NoError(t, err)
+	assert.True(t, regex.MatchString("anything"))
+
+	rule.Regex = "node_.*"
+	regex, err = rule.Compile()
+	require.NoError(t, err)
+	assert.True(t, regex.MatchString("node_load1"))
+	assert.False(t, regex.MatchString("go_node_load1"))
+}
+
+func TestCheckConfigPrometheusScrapeValidation(t *testing.T) {
+	c := FixtureCheckConfig("check")
+	c.Command = ""
+	c.PrometheusScrape = FixturePrometheusScrape("http://localhost:9100/metrics")
+	assert.NoError(t, c.Validate())
+
+	c.OutputMetricFormat = GraphiteOutputMetricFormat
+	assert.Error(t, c.Validate())
+
+	c.OutputMetricFormat = ""
+	c.Command = "true"
+	assert.Error(t, c.Validate())
+
+	c.Command = ""
+	c.PrometheusScrape.URL = ""
+	assert.Error(t, c.Validate())
+
+	check := NewCheck(FixtureCheckConfig("check"))
+	check.Command = ""
+	check.PrometheusScrape = FixturePrometheusScrape("http://localhost:9100/metrics")
+	assert.NoError(t, check.Validate())
+	check.Command = "true"
+	assert.Error(t, check.Validate())
+}
@@ -1,5 +1,7 @@
 package v2
 
+import "time"
+
 // FixtureCheckRequest returns a fixture for a CheckRequest object.
 func FixtureCheckRequest(id string) *CheckRequest {
 	config := FixtureCheckConfig(id)
@@ -14,3 +16,9 @@ func FixtureCheckRequest(id string) *CheckRequest {
 		},
 	}
 }
+
+// Expired returns true if the check request has an expiry, and it passed at
+// the given time.
+func (r *CheckRequest) Expired(now time.Time) bool {
+	return r.Expires != 0 && now.Unix() > r.Expires
+}
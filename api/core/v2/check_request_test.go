@@ -2,6 +2,7 @@ package v2
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -35,3 +36,15 @@ func TestFixtureProxyRequests(t *testing.T) {
 	p.SplayCoverage = 0
 	assert.Error(t, p.Validate())
 }
+
+func TestCheckRequestExpired(t *testing.T) {
+	now := time.Unix(1000, 0)
+	r := FixtureCheckRequest("check")
+	assert.False(t, r.Expired(now))
+
+	r.Expires = 1000
+	assert.False(t, r.Expired(now))
+
+	r.Expires = 999
+	assert.True(t, r.Expired(now))
+}
@@ -35,6 +35,13 @@ func TestScheduleValidation(t *testing.T) {
 
 	c.Cron = "this is an invalid cron"
 	assert.Error(t, c.Validate())
+
+	c.Cron = ""
+	c.RunAt = []string{"2019-07-01T02:00:00Z"}
+	assert.NoError(t, c.Validate())
+
+	c.RunAt = []string{"tomorrow"}
+	assert.Error(t, c.Validate())
 }
 
 func TestFixtureCheckIsValid(t *testing.T) {
@@ -0,0 +1,11 @@
+package v2
+
+// CheckConfigWarning describes a check configuration that is valid, but goes
+// against best practices and probably won't behave as intended.
+type CheckConfigWarning struct {
+	// Field is the name of the offending check field.
+	Field string `json:"field"`
+
+	// Message describes the issue.
+	Message string `json:"message"`
+}
@@ -0,0 +1,54 @@
+package v2
+
+// ClusterLeadership describes the leadership of the backend cluster.
+type ClusterLeadership struct {
+	// Leader is the etcd ID of the cluster member holding the leadership.
+	Leader uint64
+	// LeaderName is the name of the cluster member holding the leadership.
+	LeaderName string
+	// RaftTerm is the raft term of the current leadership.
+	RaftTerm uint64
+	// Members is the leadership as seen by every cluster member.
+	Members []*MemberLeadership
+	// Rings is the ownership of the triggers of the rings, which elect the
+	// backend running tessen and the agents running the round robin checks.
+	Rings []*RingOwnership
+}
+
+// MemberLeadership holds the leadership status of a cluster member.
+type MemberLeadership struct {
+	// MemberID is the etcd cluster member's ID.
+	MemberID uint64
+	// Name is the cluster member's name.
+	Name string
+	// Leader is the ID of the leader known by the cluster member.
+	Leader uint64
+	// IsLeader describes whether the cluster member holds the leadership.
+	IsLeader bool
+	// Err holds the string representation of any errors encountered while
+	// getting the member's status.
+	Err string
+}
+
+// RingOwnership describes the owner of the active trigger of a ring watcher.
+// The trigger is held by an etcd lease, and the owner is notified when it
+// expires.
+type RingOwnership struct {
+	// Namespace is the namespace of the ring, "global" for the rings of the
+	// backends.
+	Namespace string
+	// Ring is the name of the ring, the subscription of the round robin
+	// checks or "backends".
+	Ring string
+	// Watcher is the name of the ring watcher, the check or "tessen".
+	Watcher string
+	// Schedule is the interval, in seconds, or the cron schedule of the
+	// trigger.
+	Schedule string
+	// Owner is the ring item notified when the trigger expires.
+	Owner string
+	// LeaseID is the ID of the etcd lease holding the trigger.
+	LeaseID int64
+	// TTL is the remaining time to live of the lease, in seconds.
+	TTL int64
+}
@@ -0,0 +1,105 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+)
+
+const (
+	// EnrollmentTokensResource is the name of this resource type
+	EnrollmentTokensResource = "enrollment-tokens"
+
+	// MinEnrollmentTokenLength is the minimum length of the enrollment
+	// tokens, which are secrets.
+	MinEnrollmentTokenLength = 16
+)
+
+// EnrollmentToken is a one-time bootstrap token, with which an agent of its
+// namespace obtains a TLS client certificate signed by the backend. Its name
+// is the token itself, and it is deleted once used.
+type EnrollmentToken struct {
+	ObjectMeta `json:"metadata"`
+
+	// AgentName is the name of the only agent which can enroll with the
+	// token. Any agent can enroll with the token if empty.
+	AgentName string `json:"agent_name,omitempty"`
+
+	// Expires is the time, in seconds since the Unix epoch, after which the
+	// token can no longer be used. The token does not expire if 0.
+	Expires int64 `json:"expires,omitempty"`
+}
+
+// FixtureEnrollmentToken returns an enrollment token for testing.
+func FixtureEnrollmentToken(name string) *EnrollmentToken {
+	return &EnrollmentToken{
+		ObjectMeta: NewObjectMeta(name, "default"),
+	}
+}
+
+// GetObjectMeta returns the object metadata of the enrollment token.
+func (t *EnrollmentToken) GetObjectMeta() ObjectMeta {
+	return t.ObjectMeta
+}
+
+// SetNamespace sets the namespace of the resource.
+func (t *EnrollmentToken) SetNamespace(namespace string) {
+	t.Namespace = namespace
+}
+
+// StorePrefix returns the path prefix to this resource in the store
+func (t *EnrollmentToken) StorePrefix() string {
+	return EnrollmentTokensResource
+}
+
+// URIPath returns the path component of an enrollment token URI.
+func (t *EnrollmentToken) URIPath() string {
+	return path.Join(URLPrefix, "namespaces", url.PathEscape(t.Namespace), EnrollmentTokensResource, url.PathEscape(t.Name))
+}
+
+// Validate returns an error if the enrollment token does not pass validation
+// tests.
+func (t *EnrollmentToken) Validate() error {
+	if err := ValidateName(t.Name); err != nil {
+		return errors.New("enrollment token name " + err.Error())
+	}
+	if len(t.Name) < MinEnrollmentTokenLength {
+		return fmt.Errorf("enrollment token name must be at least %d characters long", MinEnrollmentTokenLength)
+	}
+	if t.Namespace == "" {
+		return errors.New("namespace must be set")
+	}
+	if t.AgentName != "" {
+		if err := ValidateName(t.AgentName); err != nil {
+			return errors.New("agent name " + err.Error())
+		}
+	}
+	if t.Expires < 0 {
+		return errors.New("expires must not be negative")
+	}
+	return nil
+}
+
+// Allows returns an error if the agent can not enroll with the token at the
+// given time.
+func (t *EnrollmentToken) Allows(agentName string, now time.Time) error {
+	if t.Expires > 0 && now.Unix() > t.Expires {
+		return errors.New("the enrollment token expired")
+	}
+	if t.AgentName != "" && t.AgentName != agentName {
+		return fmt.Errorf("the enrollment token is not valid for agent %q", agentName)
+	}
+	return nil
+}
+
+// EnrollmentTokenFields returns a set of fields that represent that resource
+func EnrollmentTokenFields(r Resource) map[string]string {
+	resource := r.(*EnrollmentToken)
+	return map[string]string{
+		"enrollment_token.name":       resource.ObjectMeta.Name,
+		"enrollment_token.namespace":  resource.ObjectMeta.Namespace,
+		"enrollment_token.agent_name": resource.AgentName,
+	}
+}
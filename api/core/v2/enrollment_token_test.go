@@ -0,0 +1,63 @@
+package v2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnrollmentTokenValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   func(*EnrollmentToken)
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			token: func(t *EnrollmentToken) {},
+		},
+		{
+			name:    "short token",
+			token:   func(t *EnrollmentToken) { t.Name = "abc" },
+			wantErr: true,
+		},
+		{
+			name:    "missing namespace",
+			token:   func(t *EnrollmentToken) { t.Namespace = "" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid agent name",
+			token:   func(t *EnrollmentToken) { t.AgentName = "web 1" },
+			wantErr: true,
+		},
+		{
+			name:    "negative expiry",
+			token:   func(t *EnrollmentToken) { t.Expires = -1 },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := FixtureEnrollmentToken("5f4dcc3b5aa765d61d8327deb882cf99")
+			tt.token(token)
+			if err := token.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("EnrollmentToken.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnrollmentTokenAllows(t *testing.T) {
+	now := time.Now()
+	token := FixtureEnrollmentToken("5f4dcc3b5aa765d61d8327deb882cf99")
+	assert.NoError(t, token.Allows("web-1", now))
+
+	token.AgentName = "web-1"
+	assert.NoError(t, token.Allows("web-1", now))
+	assert.Error(t, token.Allows("web-2", now))
+
+	token.Expires = now.Add(-time.Second).Unix()
+	assert.Error(t, token.Allows("web-1", now))
+}
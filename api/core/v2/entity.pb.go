@@ -81,14 +81,18 @@ var xxx_messageInfo_Entity proto.InternalMessageInfo
 // System contains information about the system that the Agent process
 // is running on, used for additional Entity context.
 type System struct {
-	Hostname             string   `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	OS                   string   `protobuf:"bytes,2,opt,name=os,proto3" json:"os,omitempty"`
-	Platform             string   `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
-	PlatformFamily       string   `protobuf:"bytes,4,opt,name=platform_family,json=platformFamily,proto3" json:"platform_family,omitempty"`
-	PlatformVersion      string   `protobuf:"bytes,5,opt,name=platform_version,json=platformVersion,proto3" json:"platform_version,omitempty"`
-	Network              Network  `protobuf:"bytes,6,opt,name=network,proto3" json:"network"`
-	Arch                 string   `protobuf:"bytes,7,opt,name=arch,proto3" json:"arch,omitempty"`
-	ARMVersion           int32    `protobuf:"varint,8,opt,name=arm_version,json=armVersion,proto3" json:"arm_version,omitempty"`
+	Hostname        string  `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	OS              string  `protobuf:"bytes,2,opt,name=os,proto3" json:"os,omitempty"`
+	Platform        string  `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
+	PlatformFamily  string  `protobuf:"bytes,4,opt,name=platform_family,json=platformFamily,proto3" json:"platform_family,omitempty"`
+	PlatformVersion string  `protobuf:"bytes,5,opt,name=platform_version,json=platformVersion,proto3" json:"platform_version,omitempty"`
+	Network         Network `protobuf:"bytes,6,opt,name=network,proto3" json:"network"`
+	Arch            string  `protobuf:"bytes,7,opt,name=arch,proto3" json:"arch,omitempty"`
+	ARMVersion      int32   `protobuf:"varint,8,opt,name=arm_version,json=armVersion,proto3" json:"arm_version,omitempty"`
+	// LibCType is the type of the C library of the system, glibc or musl
+	LibCType string `protobuf:"bytes,9,opt,name=libc_type,json=libcType,proto3" json:"libc_type,omitempty"`
+	// FIPSEnabled indicates if the kernel of the system runs in FIPS mode
+	FIPSEnabled          bool     `protobuf:"varint,10,opt,name=fips_enabled,json=fipsEnabled,proto3" json:"fips_enabled,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -183,6 +187,20 @@ func (m *System) GetARMVersion() int32 {
 	return 0
 }
 
+func (m *System) GetLibCType() string {
+	if m != nil {
+		return m.LibCType
+	}
+	return ""
+}
+
+func (m *System) GetFIPSEnabled() bool {
+	if m != nil {
+		return m.FIPSEnabled
+	}
+	return false
+}
+
 // Network contains information about the system network interfaces
 // that the Agent process is running on, used for additional Entity
 // context.
@@ -357,51 +375,55 @@ func init() {
 func init() { proto.RegisterFile("entity.proto", fileDescriptor_cf50d946d740d100) }
 
 var fileDescriptor_cf50d946d740d100 = []byte{
-	// 704 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x54, 0x4d, 0x6e, 0xdb, 0x46,
-	0x18, 0xf5, 0xe8, 0x5f, 0x23, 0x4b, 0x75, 0xa7, 0xa8, 0x41, 0x1b, 0x28, 0x49, 0x68, 0x53, 0xd6,
-	0x6d, 0x69, 0x58, 0x2e, 0x5c, 0xa0, 0xab, 0x9a, 0x4e, 0x02, 0x04, 0x81, 0x63, 0x60, 0x84, 0x64,
-	0x91, 0x45, 0x84, 0x11, 0x39, 0x92, 0x98, 0x88, 0xa4, 0x30, 0x33, 0x52, 0xa2, 0x1b, 0xe4, 0x08,
-	0xc9, 0xce, 0x4b, 0xe7, 0x06, 0x39, 0x82, 0x97, 0x3e, 0x01, 0x91, 0x30, 0x3b, 0x9d, 0x20, 0xcb,
-	0x60, 0x86, 0x3f, 0x96, 0x84, 0xec, 0xde, 0xf7, 0xf8, 0xbe, 0x9f, 0x99, 0xf7, 0x0d, 0xe1, 0x2e,
-	0x0d, 0x85, 0x2f, 0x96, 0xf6, 0x8c, 0x45, 0x22, 0x42, 0x6d, 0x4e, 0x43, 0x3e, 0xb7, 0xdd, 0x88,
-	0x51, 0x7b, 0xd1, 0x3b, 0xfc, 0x67, 0xec, 0x8b, 0xc9, 0x7c, 0x68, 0xbb, 0x51, 0x70, 0x3c, 0x8e,
-	0xc6, 0xd1, 0xb1, 0x52, 0x0d, 0xe7, 0xa3, 0xff, 0x17, 0x27, 0x76, 0xcf, 0x3e, 0x51, 0xa4, 0xe2,
-	0x14, 0x4a, 0x8b, 0x1c, 0xc2, 0x80, 0x0a, 0x92, 0xe2, 0xee, 0x87, 0x0a, 0xac, 0x3d, 0x54, 0x1d,
-	0xd0, 0x69, 0xde, 0x6b, 0xe0, 0x4e, 0x09, 0xe7, 0x1a, 0x30, 0x81, 0xd5, 0x74, 0xf6, 0x56, 0xb1,
-	0xb1, 0xc1, 0xe3, 0x56, 0x1a, 0x5d, 0xc8, 0x00, 0x9d, 0xc2, 0x1a, 0x5f, 0x72, 0x41, 0x03, 0xad,
-	0x6c, 0x02, 0xab, 0xd5, 0xfb, 0xd5, 0xde, 0x98, 0xd0, 0xee, 0xab, 0x8f, 0x4e, 0xe5, 0x36, 0x36,
-	0x76, 0x70, 0x26, 0x45, 0xff, 0xc2, 0x36, 0x9f, 0x0f, 0xb9, 0xcb, 0xfc, 0x99, 0xf0, 0xa3, 0x90,
-	0x6b, 0x15, 0xb3, 0x6c, 0x35, 0x9d, 0x9f, 0x57, 0xb1, 0xb1, 0xf9, 0x01, 0x6f, 0x86, 0xe8, 0x08,
-	0x36, 0xa7, 0x84, 0x8b, 0x01, 0xa7, 0x34, 0xd4, 0xaa, 0x26, 0xb0, 0xca, 0x4e, 0x7b, 0x15, 0x1b,
-	0xf7, 0x24, 0x6e, 0x48, 0xd8, 0xa7, 0x34, 0x44, 0x36, 0x84, 0x1e, 0x65, 0x74, 0xec, 0x73, 0x41,
-	0x99, 0x56, 0x33, 0x81, 0xd5, 0x70, 0x3a, 0xab, 0xd8, 0x58, 0x63, 0xf1, 0x1a, 0x46, 0x4f, 0x60,
-	0x27, 0x8f, 0x18, 0x91, 0xed, 0xb4, 0xba, 0x3a, 0xd1, 0x6f, 0x5b, 0x27, 0x7a, 0xb0, 0x21, 0xca,
-	0x4e, 0xb6, 0x95, 0x8a, 0x10, 0xac, 0xcc, 0x39, 0x65, 0x5a, 0x4b, 0xde, 0x21, 0x56, 0x18, 0x9d,
-	0xc1, 0x5f, 0xe8, 0x5b, 0x41, 0x43, 0x8f, 0x7a, 0x03, 0x22, 0x04, 0xf3, 0x87, 0x73, 0x41, 0xb9,
-	0xb6, 0x6b, 0x02, 0x6b, 0xd7, 0xa9, 0xae, 0x62, 0x03, 0xfc, 0x8d, 0x51, 0xae, 0x38, 0x2f, 0x04,
-	0x68, 0x1f, 0xd6, 0x18, 0xf5, 0x88, 0x2b, 0xb4, 0xb6, 0xbc, 0x26, 0x9c, 0x45, 0xe8, 0x19, 0x6c,
-	0x48, 0x23, 0x3d, 0x22, 0x88, 0xd6, 0x51, 0xa3, 0x1e, 0x6c, 0x8d, 0x7a, 0x35, 0x7c, 0x45, 0x5d,
-	0x71, 0x49, 0x05, 0x71, 0x74, 0x39, 0xe6, 0x5d, 0x6c, 0x80, 0x55, 0x6c, 0xa0, 0x3c, 0xed, 0xaf,
-	0x28, 0xf0, 0x05, 0x0d, 0x66, 0x62, 0x89, 0x8b, 0x52, 0xff, 0x35, 0xde, 0x5d, 0x1b, 0x3b, 0x37,
-	0xd7, 0x06, 0xe8, 0x7e, 0x2c, 0xc1, 0x5a, 0xea, 0x1f, 0x3a, 0x84, 0x8d, 0x49, 0xc4, 0x45, 0x48,
-	0x02, 0x9a, 0xee, 0x05, 0x2e, 0x62, 0xb4, 0x0f, 0x4b, 0x11, 0xd7, 0x4a, 0x6a, 0x5b, 0x6a, 0x49,
-	0x6c, 0x94, 0xae, 0xfa, 0xb8, 0x14, 0x71, 0x99, 0x33, 0x9b, 0x12, 0x31, 0x8a, 0x58, 0xba, 0x1c,
-	0x4d, 0x5c, 0xc4, 0xe8, 0x77, 0xf8, 0x53, 0x8e, 0x07, 0x23, 0x12, 0xf8, 0xd3, 0xa5, 0x56, 0x51,
-	0x92, 0x4e, 0x4e, 0x3f, 0x52, 0x2c, 0xfa, 0x03, 0xee, 0x15, 0xc2, 0x05, 0x65, 0x5c, 0xfa, 0x52,
-	0x55, 0xca, 0xa2, 0xc0, 0xf3, 0x94, 0x46, 0x67, 0xb0, 0x1e, 0x52, 0xf1, 0x26, 0x62, 0xaf, 0x95,
-	0xdb, 0xad, 0xde, 0xfe, 0xd6, 0x75, 0x3c, 0x4d, 0xbf, 0x66, 0x96, 0xe5, 0x62, 0xe9, 0x15, 0x61,
-	0xee, 0x44, 0xd9, 0xdd, 0xc4, 0x0a, 0xa3, 0x63, 0xd8, 0x22, 0x6b, 0x1d, 0x1b, 0x26, 0xb0, 0xaa,
-	0x4e, 0x27, 0x89, 0x0d, 0x78, 0x8e, 0x2f, 0xb3, 0x86, 0x18, 0x92, 0xa2, 0x79, 0xf7, 0x25, 0xac,
-	0x67, 0xe5, 0x51, 0x1f, 0x42, 0x3f, 0x14, 0x94, 0x8d, 0x88, 0x4b, 0xe5, 0x2b, 0x2a, 0x5b, 0xad,
-	0x9e, 0xf1, 0xe3, 0x51, 0x1e, 0xe7, 0x3a, 0x07, 0xc9, 0x99, 0xe4, 0x76, 0xde, 0xa7, 0xe2, 0x35,
-	0xdc, 0x0d, 0xe1, 0xde, 0x76, 0x8e, 0x1c, 0x7c, 0xcd, 0x10, 0x85, 0xd1, 0x01, 0x2c, 0x07, 0xc4,
-	0xcd, 0xdc, 0xa8, 0x27, 0xb1, 0x51, 0xbe, 0x3c, 0xbf, 0xc0, 0x92, 0x43, 0x7f, 0xc2, 0x26, 0xf1,
-	0x3c, 0x46, 0x39, 0xa7, 0x5c, 0x2b, 0xab, 0x17, 0xa7, 0x1e, 0x4f, 0x41, 0xe2, 0x7b, 0xd8, 0x3d,
-	0x82, 0x9d, 0xcd, 0x45, 0x47, 0x1a, 0xac, 0x4f, 0x48, 0xe8, 0x4d, 0x29, 0xcb, 0x1a, 0xe6, 0xa1,
-	0x63, 0x7e, 0xfb, 0xa2, 0x83, 0x9b, 0x44, 0x07, 0x9f, 0x12, 0x1d, 0xdc, 0x26, 0x3a, 0xb8, 0x4b,
-	0x74, 0xf0, 0x39, 0xd1, 0xc1, 0xfb, 0xaf, 0xfa, 0xce, 0x8b, 0xd2, 0xa2, 0x37, 0xac, 0xa9, 0x9f,
-	0xcd, 0xe9, 0xf7, 0x00, 0x00, 0x00, 0xff, 0xff, 0x5d, 0xa8, 0x0f, 0x89, 0xcd, 0x04, 0x00, 0x00,
+	// 766 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x54, 0xcf, 0x6e, 0xe3, 0x44,
+	0x18, 0xaf, 0x93, 0x34, 0x89, 0xbf, 0xa4, 0xd9, 0x32, 0x88, 0xca, 0x5b, 0x89, 0x4c, 0x94, 0x0b,
+	0xd9, 0x05, 0x5c, 0x6d, 0x8a, 0x16, 0x89, 0x13, 0x75, 0xd9, 0x95, 0x56, 0x50, 0x16, 0x4d, 0x80,
+	0x03, 0x07, 0xa2, 0xb1, 0xfd, 0xa5, 0x35, 0xc4, 0x76, 0x34, 0x33, 0x29, 0xe4, 0x0d, 0xf6, 0x11,
+	0xe0, 0xb6, 0xc7, 0x7d, 0x04, 0x1e, 0x61, 0x8f, 0x7d, 0x02, 0x0b, 0xcc, 0x2d, 0x4f, 0xc0, 0x11,
+	0xcd, 0xd8, 0x4e, 0x93, 0x68, 0x6f, 0xbf, 0xef, 0x37, 0xbf, 0xf1, 0xf7, 0xe7, 0xf7, 0x8d, 0xa1,
+	0x8b, 0x89, 0x8a, 0xd4, 0xca, 0x5d, 0x88, 0x54, 0xa5, 0xe4, 0x48, 0x62, 0x22, 0x97, 0x6e, 0x90,
+	0x0a, 0x74, 0x6f, 0xc7, 0xa7, 0x9f, 0x5d, 0x47, 0xea, 0x66, 0xe9, 0xbb, 0x41, 0x1a, 0x9f, 0x5d,
+	0xa7, 0xd7, 0xe9, 0x99, 0x51, 0xf9, 0xcb, 0xd9, 0x97, 0xb7, 0x4f, 0xdc, 0xb1, 0xfb, 0xc4, 0x90,
+	0x86, 0x33, 0xa8, 0xf8, 0xc8, 0x29, 0xc4, 0xa8, 0x78, 0x81, 0x87, 0x7f, 0x36, 0xa0, 0xf9, 0xcc,
+	0x64, 0x20, 0xe7, 0x55, 0xae, 0x69, 0x30, 0xe7, 0x52, 0x3a, 0xd6, 0xc0, 0x1a, 0xd9, 0xde, 0xf1,
+	0x3a, 0xa3, 0x3b, 0x3c, 0xeb, 0x14, 0xd1, 0xa5, 0x0e, 0xc8, 0x39, 0x34, 0xe5, 0x4a, 0x2a, 0x8c,
+	0x9d, 0xfa, 0xc0, 0x1a, 0x75, 0xc6, 0x1f, 0xb8, 0x3b, 0x15, 0xba, 0x13, 0x73, 0xe8, 0x35, 0xde,
+	0x66, 0xf4, 0x80, 0x95, 0x52, 0xf2, 0x39, 0x1c, 0xc9, 0xa5, 0x2f, 0x03, 0x11, 0x2d, 0x54, 0x94,
+	0x26, 0xd2, 0x69, 0x0c, 0xea, 0x23, 0xdb, 0x7b, 0x6f, 0x9d, 0xd1, 0xdd, 0x03, 0xb6, 0x1b, 0x92,
+	0xc7, 0x60, 0xcf, 0xb9, 0x54, 0x53, 0x89, 0x98, 0x38, 0x87, 0x03, 0x6b, 0x54, 0xf7, 0x8e, 0xd6,
+	0x19, 0xbd, 0x27, 0x59, 0x5b, 0xc3, 0x09, 0x62, 0x42, 0x5c, 0x80, 0x10, 0x05, 0x5e, 0x47, 0x52,
+	0xa1, 0x70, 0x9a, 0x03, 0x6b, 0xd4, 0xf6, 0x7a, 0xeb, 0x8c, 0x6e, 0xb1, 0x6c, 0x0b, 0x93, 0xaf,
+	0xa1, 0x57, 0x45, 0x82, 0xeb, 0x74, 0x4e, 0xcb, 0x74, 0xf4, 0xe1, 0x5e, 0x47, 0x5f, 0xed, 0x88,
+	0xca, 0xce, 0xf6, 0xae, 0x12, 0x02, 0x8d, 0xa5, 0x44, 0xe1, 0x74, 0xf4, 0x0c, 0x99, 0xc1, 0xe4,
+	0x29, 0xbc, 0x8f, 0xbf, 0x2b, 0x4c, 0x42, 0x0c, 0xa7, 0x5c, 0x29, 0x11, 0xf9, 0x4b, 0x85, 0xd2,
+	0xe9, 0x0e, 0xac, 0x51, 0xd7, 0x3b, 0x5c, 0x67, 0xd4, 0xfa, 0x94, 0x91, 0x4a, 0x71, 0xb1, 0x11,
+	0x90, 0x13, 0x68, 0x0a, 0x0c, 0x79, 0xa0, 0x9c, 0x23, 0x3d, 0x26, 0x56, 0x46, 0xe4, 0x07, 0x68,
+	0x6b, 0x23, 0x43, 0xae, 0xb8, 0xd3, 0x33, 0xa5, 0x3e, 0xdc, 0x2b, 0xf5, 0xa5, 0xff, 0x0b, 0x06,
+	0xea, 0x0a, 0x15, 0xf7, 0xfa, 0xba, 0xcc, 0xbb, 0x8c, 0x5a, 0xeb, 0x8c, 0x92, 0xea, 0xda, 0x27,
+	0x69, 0x1c, 0x29, 0x8c, 0x17, 0x6a, 0xc5, 0x36, 0x9f, 0xfa, 0xa2, 0xfd, 0xea, 0x35, 0x3d, 0x78,
+	0xf3, 0x9a, 0x5a, 0xc3, 0x57, 0x75, 0x68, 0x16, 0xfe, 0x91, 0x53, 0x68, 0xdf, 0xa4, 0x52, 0x25,
+	0x3c, 0xc6, 0x62, 0x2f, 0xd8, 0x26, 0x26, 0x27, 0x50, 0x4b, 0xa5, 0x53, 0x33, 0xdb, 0xd2, 0xcc,
+	0x33, 0x5a, 0x7b, 0x39, 0x61, 0xb5, 0x54, 0xea, 0x3b, 0x8b, 0x39, 0x57, 0xb3, 0x54, 0x14, 0xcb,
+	0x61, 0xb3, 0x4d, 0x4c, 0x3e, 0x82, 0x07, 0x15, 0x9e, 0xce, 0x78, 0x1c, 0xcd, 0x57, 0x4e, 0xc3,
+	0x48, 0x7a, 0x15, 0xfd, 0xdc, 0xb0, 0xe4, 0x11, 0x1c, 0x6f, 0x84, 0xb7, 0x28, 0x64, 0x94, 0x16,
+	0xc6, 0xdb, 0x6c, 0xf3, 0x81, 0x1f, 0x0b, 0x9a, 0x3c, 0x85, 0x56, 0x82, 0xea, 0xb7, 0x54, 0xfc,
+	0x6a, 0xdc, 0xee, 0x8c, 0x4f, 0xf6, 0xc6, 0xf1, 0x6d, 0x71, 0x5a, 0x5a, 0x56, 0x89, 0xb5, 0x57,
+	0x5c, 0x04, 0x37, 0xc6, 0x6e, 0x9b, 0x19, 0x4c, 0xce, 0xa0, 0xc3, 0xb7, 0x32, 0xb6, 0x07, 0xd6,
+	0xe8, 0xd0, 0xeb, 0xe5, 0x19, 0x85, 0x0b, 0x76, 0x55, 0x26, 0x64, 0xc0, 0xef, 0x93, 0x3f, 0x02,
+	0x7b, 0x1e, 0xf9, 0xc1, 0x54, 0xad, 0x16, 0xe8, 0xd8, 0x66, 0x16, 0xdd, 0x3c, 0xa3, 0xed, 0x6f,
+	0x22, 0xff, 0xf2, 0xfb, 0xd5, 0x02, 0x59, 0x5b, 0x1f, 0x6b, 0x44, 0xc6, 0xd0, 0x9d, 0x45, 0x0b,
+	0x39, 0xc5, 0x84, 0xfb, 0x73, 0x0c, 0x1d, 0x30, 0xab, 0xf9, 0x20, 0xcf, 0x68, 0xe7, 0xf9, 0x8b,
+	0xef, 0x26, 0xcf, 0x0a, 0x9a, 0x75, 0xb4, 0xa8, 0x0c, 0x86, 0x3f, 0x43, 0xab, 0xac, 0x9e, 0x4c,
+	0x00, 0xa2, 0x44, 0xa1, 0x98, 0xf1, 0x00, 0xf5, 0x23, 0xad, 0x8f, 0x3a, 0x63, 0xfa, 0xee, 0x4e,
+	0x5f, 0x54, 0x3a, 0x8f, 0xe8, 0x96, 0xf5, 0xf2, 0xdf, 0x5f, 0x65, 0x5b, 0x78, 0x98, 0xc0, 0xf1,
+	0xfe, 0x1d, 0x3d, 0x97, 0x2d, 0xbf, 0x0d, 0x26, 0x0f, 0xa1, 0x1e, 0xf3, 0xa0, 0x34, 0xbb, 0x95,
+	0x67, 0xb4, 0x7e, 0x75, 0x71, 0xc9, 0x34, 0x47, 0x3e, 0x06, 0x9b, 0x87, 0xa1, 0x40, 0x29, 0x51,
+	0x3a, 0x75, 0xf3, 0xa0, 0xcd, 0xdb, 0xdc, 0x90, 0xec, 0x1e, 0x0e, 0x1f, 0x43, 0x6f, 0xf7, 0x1d,
+	0x11, 0x07, 0x5a, 0x37, 0x3c, 0x09, 0xe7, 0x28, 0xca, 0x84, 0x55, 0xe8, 0x0d, 0xfe, 0xfb, 0xa7,
+	0x6f, 0xbd, 0xc9, 0xfb, 0xd6, 0x5f, 0x79, 0xdf, 0x7a, 0x9b, 0xf7, 0xad, 0xbb, 0xbc, 0x6f, 0xfd,
+	0x9d, 0xf7, 0xad, 0x3f, 0xfe, 0xed, 0x1f, 0xfc, 0x54, 0xbb, 0x1d, 0xfb, 0x4d, 0xf3, 0x2f, 0x3b,
+	0xff, 0x3f, 0x00, 0x00, 0xff, 0xff, 0x9f, 0x2b, 0xd7, 0xea, 0x2c, 0x05, 0x00, 0x00,
 }
 
 func (this *Entity) Equal(that interface{}) bool {
@@ -511,6 +533,12 @@ func (this *System) Equal(that interface{}) bool {
 	if this.ARMVersion != that1.ARMVersion {
 		return false
 	}
+	if this.LibCType != that1.LibCType {
+		return false
+	}
+	if this.FIPSEnabled != that1.FIPSEnabled {
+		return false
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -863,6 +891,22 @@ func (m *System) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintEntity(dAtA, i, uint64(m.ARMVersion))
 	}
+	if len(m.LibCType) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintEntity(dAtA, i, uint64(len(m.LibCType)))
+		i += copy(dAtA[i:], m.LibCType)
+	}
+	if m.FIPSEnabled {
+		dAtA[i] = 0x50
+		i++
+		if m.FIPSEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -1036,8 +1080,10 @@ func NewPopulatedSystem(r randyEntity, easy bool) *System {
 	if r.Intn(2) == 0 {
 		this.ARMVersion *= -1
 	}
+	this.LibCType = string(randStringEntity(r))
+	this.FIPSEnabled = bool(bool(r.Intn(2) == 0))
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedEntity(r, 9)
+		this.XXX_unrecognized = randUnrecognizedEntity(r, 11)
 	}
 	return this
 }
@@ -1237,6 +1283,13 @@ func (m *System) Size() (n int) {
 	if m.ARMVersion != 0 {
 		n += 1 + sovEntity(uint64(m.ARMVersion))
 	}
+	l = len(m.LibCType)
+	if l > 0 {
+		n += 1 + l + sovEntity(uint64(l))
+	}
+	if m.FIPSEnabled {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1943,6 +1996,58 @@ func (m *System) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LibCType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEntity
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEntity
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEntity
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LibCType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FIPSEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEntity
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FIPSEnabled = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEntity(dAtA[iNdEx:])
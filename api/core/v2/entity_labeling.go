@@ -0,0 +1,98 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+)
+
+// EntityLabeling sets and removes labels on all the entities of a namespace
+// matching its selector at once.
+type EntityLabeling struct {
+	// Selector selects the entities labeled.
+	Selector EntitySelector `json:"selector"`
+
+	// Set are the labels set on the entities, added or replaced.
+	Set map[string]string `json:"set,omitempty"`
+
+	// Remove are the keys of the labels removed from the entities.
+	Remove []string `json:"remove,omitempty"`
+}
+
+// EntitySelector selects the entities matching all its criteria.
+type EntitySelector struct {
+	// Labels are the labels the entities must have, with the same values.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Names are the names of the entities, one of which the entities must
+	// have.
+	Names []string `json:"names,omitempty"`
+}
+
+// EntityLabelingResult is the result of an EntityLabeling.
+type EntityLabelingResult struct {
+	// Entities are the names of the entities whose labels changed. The
+	// selected entities which already had the labels are not included.
+	Entities []string `json:"entities"`
+}
+
+// Validate returns an error if the labeling is invalid.
+func (l *EntityLabeling) Validate() error {
+	if len(l.Selector.Labels) == 0 && len(l.Selector.Names) == 0 {
+		return errors.New("the selector must select the entities by label or by name")
+	}
+	if len(l.Set) == 0 && len(l.Remove) == 0 {
+		return errors.New("labels must be set or removed")
+	}
+	for key := range l.Set {
+		if key == "" {
+			return errors.New("label keys must not be empty")
+		}
+		if utilstrings.InArray(key, l.Remove) {
+			return fmt.Errorf("label %q must not be both set and removed", key)
+		}
+	}
+	for _, key := range l.Remove {
+		if key == "" {
+			return errors.New("label keys must not be empty")
+		}
+	}
+	return nil
+}
+
+// Selects returns true if the entity matches the selector of the labeling.
+func (l *EntityLabeling) Selects(entity *Entity) bool {
+	if len(l.Selector.Names) > 0 && !utilstrings.InArray(entity.Name, l.Selector.Names) {
+		return false
+	}
+	for key, value := range l.Selector.Labels {
+		if label, ok := entity.Labels[key]; !ok || label != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply sets and removes the labels of the entity, and returns true if they
+// changed.
+func (l *EntityLabeling) Apply(entity *Entity) bool {
+	changed := false
+	for key, value := range l.Set {
+		if label, ok := entity.Labels[key]; ok && label == value {
+			continue
+		}
+		if entity.Labels == nil {
+			entity.Labels = make(map[string]string)
+		}
+		entity.Labels[key] = value
+		changed = true
+	}
+	for _, key := range l.Remove {
+		if _, ok := entity.Labels[key]; ok {
+			delete(entity.Labels, key)
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,93 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityLabelingValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		labeling *EntityLabeling
+		wantErr  bool
+	}{
+		{
+			name: "set by label",
+			labeling: &EntityLabeling{
+				Selector: EntitySelector{Labels: map[string]string{"team": "web"}},
+				Set:      map[string]string{"team": "frontend"},
+			},
+		},
+		{
+			name: "remove by name",
+			labeling: &EntityLabeling{
+				Selector: EntitySelector{Names: []string{"web-1"}},
+				Remove:   []string{"team"},
+			},
+		},
+		{
+			name: "missing selector",
+			labeling: &EntityLabeling{
+				Set: map[string]string{"team": "frontend"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing mutations",
+			labeling: &EntityLabeling{
+				Selector: EntitySelector{Names: []string{"web-1"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "label set and removed",
+			labeling: &EntityLabeling{
+				Selector: EntitySelector{Names: []string{"web-1"}},
+				Set:      map[string]string{"team": "frontend"},
+				Remove:   []string{"team"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty key",
+			labeling: &EntityLabeling{
+				Selector: EntitySelector{Names: []string{"web-1"}},
+				Remove:   []string{""},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.labeling.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("EntityLabeling.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEntityLabelingSelectsAndApply(t *testing.T) {
+	labeling := &EntityLabeling{
+		Selector: EntitySelector{
+			Labels: map[string]string{"team": "web"},
+			Names:  []string{"web-1", "web-2"},
+		},
+		Set:    map[string]string{"team": "frontend"},
+		Remove: []string{"legacy"},
+	}
+
+	entity := FixtureEntity("web-1")
+	entity.Labels = map[string]string{"team": "web", "legacy": "true"}
+	assert.True(t, labeling.Selects(entity))
+	assert.True(t, labeling.Apply(entity))
+	assert.Equal(t, map[string]string{"team": "frontend"}, entity.Labels)
+	assert.False(t, labeling.Apply(entity))
+
+	other := FixtureEntity("web-3")
+	other.Labels = map[string]string{"team": "web"}
+	assert.False(t, labeling.Selects(other))
+	other.Name = "web-2"
+	other.Labels["team"] = "db"
+	assert.False(t, labeling.Selects(other))
+}
@@ -0,0 +1,22 @@
+package v2
+
+import "errors"
+
+// EntityRename renames an entity, moving its events and the silenced entries
+// of its entity subscription to its new name.
+type EntityRename struct {
+	// Name is the new name of the entity.
+	Name string `json:"name"`
+
+	// Merge merges the entity into the entity of the new name if it exists,
+	// instead of failing.
+	Merge bool `json:"merge,omitempty"`
+}
+
+// Validate returns an error if the rename is invalid.
+func (r *EntityRename) Validate() error {
+	if r.Name == "" {
+		return errors.New("new name cannot be empty")
+	}
+	return ValidateName(r.Name)
+}
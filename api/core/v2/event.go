@@ -25,8 +25,34 @@ const (
 
 	// EventPassingState indicates successful check result status
 	EventPassingState = "passing"
+
+	// EventSourceAnnotation is the annotation of the events recording how
+	// they entered the system, of the form <kind>:<identity>. It is set by
+	// the backend, the value submitted with an event is discarded.
+	EventSourceAnnotation = "sensu.io/event-source"
+
+	// EventSourceAgent, EventSourceAPI and EventSourceBackend are the kinds
+	// of event sources: the session of an agent, a user of the API, and a
+	// component of the backend.
+	EventSourceAgent   = "agent"
+	EventSourceAPI     = "api"
+	EventSourceBackend = "backend"
 )
 
+// SetSource records the source of the event in its EventSourceAnnotation,
+// replacing any previous value.
+func (e *Event) SetSource(kind, identity string) {
+	if e.Annotations == nil {
+		e.Annotations = make(map[string]string)
+	}
+	e.Annotations[EventSourceAnnotation] = kind + ":" + identity
+}
+
+// Source returns the source of the event recorded by the backend, if any.
+func (e *Event) Source() string {
+	return e.Annotations[EventSourceAnnotation]
+}
+
 // StorePrefix returns the path prefix to this resource in the store
 func (e *Event) StorePrefix() string {
 	return EventsResource
@@ -55,7 +81,7 @@ func (e *Event) Validate() error {
 	}
 
 	if e.HasCheck() {
-		if err := e.Check.Validate(); err != nil {
+		if err := e.ValidateCheck(); err != nil {
 			return errors.New("check is invalid: " + err.Error())
 		}
 	}
@@ -73,6 +99,16 @@ func (e *Event) Validate() error {
 	return nil
 }
 
+// ValidateCheck returns an error if the check of the event does not pass
+// validation tests. The checks of the events created through the API need no
+// schedule.
+func (e *Event) ValidateCheck() error {
+	if strings.HasPrefix(e.Source(), EventSourceAPI+":") {
+		return e.Check.ValidateUnscheduled()
+	}
+	return e.Check.Validate()
+}
+
 // HasCheck determines if an event has check data.
 func (e *Event) HasCheck() bool {
 	return e.Check != nil
@@ -0,0 +1,54 @@
+package v2
+
+// EventPipeline records how the last event of an entity and check went
+// through the event pipeline, i.e. which handlers ran, when and with which
+// results.
+type EventPipeline struct {
+	// Namespace is the namespace of the event.
+	Namespace string `json:"namespace"`
+
+	// Entity is the name of the entity of the event.
+	Entity string `json:"entity"`
+
+	// Check is the name of the check of the event.
+	Check string `json:"check"`
+
+	// Timestamp is the timestamp of the event, in seconds since the Epoch.
+	Timestamp int64 `json:"timestamp"`
+
+	// Handlers are the executions of the handlers of the event.
+	Handlers []*HandlerExecution `json:"handlers"`
+}
+
+// HandlerExecution records the execution of a handler for an event.
+type HandlerExecution struct {
+	// Handler is the name of the handler.
+	Handler string `json:"handler"`
+
+	// Type is the type of the handler.
+	Type string `json:"type"`
+
+	// Executed is the time at which the handler was executed, in seconds
+	// since the Epoch.
+	Executed int64 `json:"executed"`
+
+	// Duration is the duration of the execution, in seconds.
+	Duration float64 `json:"duration"`
+
+	// Filtered describes whether the event was filtered out, in which case
+	// the handler was not executed.
+	Filtered bool `json:"filtered"`
+
+	// Status is the exit status of pipe handlers.
+	Status int `json:"status"`
+
+	// Output is the output of the handler, truncated when it is too large.
+	Output string `json:"output,omitempty"`
+
+	// OutputTruncated describes whether the output was truncated.
+	OutputTruncated bool `json:"output_truncated,omitempty"`
+
+	// Error holds the string representation of any errors encountered while
+	// handling the event.
+	Error string `json:"error,omitempty"`
+}
@@ -40,6 +40,19 @@ func TestEventValidateNoTimestamp(t *testing.T) {
 	}
 }
 
+func TestEventValidateNoSchedule(t *testing.T) {
+	// Only the checks of the events created through the API need no schedule
+	event := FixtureEvent("entity", "check")
+	event.Check.Interval = 0
+	assert.Error(t, event.Validate())
+
+	event.SetSource(EventSourceAgent, "entity")
+	assert.Error(t, event.Validate())
+
+	event.SetSource(EventSourceAPI, "admin")
+	assert.NoError(t, event.Validate())
+}
+
 func TestMarshalJSON(t *testing.T) {
 	event := FixtureEvent("entity", "check")
 	_, err := json.Marshal(event)
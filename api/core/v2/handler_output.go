@@ -0,0 +1,18 @@
+package v2
+
+// HandlerOutput records the output of an execution of a handler, kept for
+// debugging when the backend captures the outputs of the handlers.
+type HandlerOutput struct {
+	// Namespace is the namespace of the handled event.
+	Namespace string `json:"namespace"`
+
+	// Entity is the name of the entity of the handled event.
+	Entity string `json:"entity"`
+
+	// Check is the name of the check of the handled event, if it has one.
+	Check string `json:"check,omitempty"`
+
+	// HandlerExecution is the execution of the handler, with its output
+	// truncated to the maximum size of the captured outputs.
+	HandlerExecution
+}
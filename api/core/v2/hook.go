@@ -67,6 +67,10 @@ func (c *HookConfig) Validate() error {
 		return errors.New("hook timeout must be greater than 0")
 	}
 
+	if c.MaxOutputSize < 0 {
+		return errors.New("hook max output size must be >= 0")
+	}
+
 	if c.Namespace == "" {
 		return errors.New("namespace must be set")
 	}
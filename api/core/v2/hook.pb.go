@@ -36,7 +36,13 @@ type HookConfig struct {
 	// Stdin indicates if hook requests have stdin enabled
 	Stdin bool `protobuf:"varint,4,opt,name=stdin,proto3" json:"stdin"`
 	// RuntimeAssets are a list of assets required to execute hook.
-	RuntimeAssets        []string `protobuf:"bytes,5,rep,name=runtime_assets,json=runtimeAssets,proto3" json:"runtime_assets"`
+	RuntimeAssets []string `protobuf:"bytes,5,rep,name=runtime_assets,json=runtimeAssets,proto3" json:"runtime_assets"`
+	// MaxOutputSize is the maximum size in bytes of the hook output. If the
+	// output is larger than MaxOutputSize, it is truncated by the agent.
+	MaxOutputSize int64 `protobuf:"varint,6,opt,name=max_output_size,json=maxOutputSize,proto3" json:"max_output_size,omitempty"`
+	// Parallel indicates if the hook runs in parallel with the other hooks of
+	// the check, instead of after the previous ones.
+	Parallel             bool     `protobuf:"varint,7,opt,name=parallel,proto3" json:"parallel,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -89,7 +95,9 @@ type Hook struct {
 	// Output from the execution of Command
 	Output string `protobuf:"bytes,5,opt,name=output,proto3" json:"output,omitempty"`
 	// Status is the exit status code produced by the hook
-	Status               int32    `protobuf:"varint,6,opt,name=status,proto3" json:"status"`
+	Status int32 `protobuf:"varint,6,opt,name=status,proto3" json:"status"`
+	// Truncated indicates if the output was truncated to MaxOutputSize
+	Truncated            bool     `protobuf:"varint,7,opt,name=truncated,proto3" json:"truncated,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -163,6 +171,13 @@ func (m *Hook) GetStatus() int32 {
 	return 0
 }
 
+func (m *Hook) GetTruncated() bool {
+	if m != nil {
+		return m.Truncated
+	}
+	return false
+}
+
 type HookList struct {
 	// Hooks is the list of hooks for the check hook
 	Hooks []string `protobuf:"bytes,1,rep,name=hooks,proto3" json:"hooks"`
@@ -229,37 +244,42 @@ func init() {
 func init() { proto.RegisterFile("hook.proto", fileDescriptor_3eef30da1c11ee1b) }
 
 var fileDescriptor_3eef30da1c11ee1b = []byte{
-	// 471 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xbf, 0x6e, 0xd4, 0x40,
-	0x10, 0xc6, 0x6f, 0xef, 0x8f, 0xe3, 0x9b, 0xe4, 0x28, 0xb6, 0x40, 0xcb, 0x15, 0x5e, 0xeb, 0x24,
-	0x24, 0x17, 0xc8, 0x51, 0x0e, 0x1a, 0x68, 0x02, 0xa6, 0xa1, 0x00, 0x21, 0xad, 0x44, 0x43, 0x83,
-	0x7c, 0xf6, 0xe6, 0x62, 0x22, 0x7b, 0x4f, 0xb7, 0xb3, 0x27, 0xf2, 0x06, 0x88, 0x27, 0xa0, 0x4c,
-	0x99, 0x47, 0xe0, 0x11, 0x52, 0xe6, 0x09, 0x2c, 0x30, 0x9d, 0x25, 0x7a, 0x4a, 0xe4, 0xb5, 0xcf,
-	0xfc, 0x91, 0xa8, 0xe6, 0xfb, 0xbe, 0xdd, 0x59, 0x6b, 0x7e, 0x1e, 0x80, 0x73, 0xa5, 0x2e, 0xc2,
-	0xcd, 0x56, 0xa1, 0xa2, 0x33, 0x2d, 0x0b, 0x6d, 0xc2, 0x44, 0x6d, 0x65, 0xb8, 0x5b, 0xce, 0x1f,
-	0xad, 0x33, 0x3c, 0x37, 0xab, 0x30, 0x51, 0xf9, 0xf1, 0x5a, 0xad, 0xd5, 0xb1, 0xbd, 0xb5, 0x32,
-	0x67, 0x4f, 0x77, 0x27, 0xe1, 0x32, 0x3c, 0xb1, 0xa1, 0xcd, 0xac, 0x6a, 0x1f, 0x99, 0x43, 0x2e,
-	0x31, 0x6e, 0xf5, 0xe2, 0xd3, 0x10, 0xe0, 0x85, 0x52, 0x17, 0xcf, 0x55, 0x71, 0x96, 0xad, 0xe9,
-	0x1b, 0x70, 0x9b, 0xc3, 0x34, 0xc6, 0x98, 0x11, 0x9f, 0x04, 0x87, 0xcb, 0x7b, 0xe1, 0x5f, 0x9f,
-	0x0c, 0x5f, 0xaf, 0xde, 0xcb, 0x04, 0x5f, 0x49, 0x8c, 0x23, 0xef, 0xa6, 0xe4, 0x83, 0xdb, 0x92,
-	0x93, 0xba, 0xe4, 0x74, 0xdf, 0xf6, 0x40, 0xe5, 0x19, 0xca, 0x7c, 0x83, 0x97, 0xa2, 0x7f, 0x8a,
-	0x32, 0x38, 0x48, 0x54, 0x9e, 0xc7, 0x45, 0xca, 0x86, 0x3e, 0x09, 0xa6, 0x62, 0x6f, 0xe9, 0x7d,
-	0x38, 0xc0, 0x2c, 0x97, 0xca, 0x20, 0x1b, 0xf9, 0x24, 0x98, 0x45, 0x87, 0x75, 0xc9, 0xf7, 0x91,
-	0xd8, 0x0b, 0xca, 0x61, 0xa2, 0x31, 0xcd, 0x0a, 0x36, 0xf6, 0x49, 0xe0, 0x46, 0xd3, 0xba, 0xe4,
-	0x6d, 0x20, 0xda, 0x42, 0x1f, 0xc3, 0x9d, 0xad, 0x29, 0x9a, 0xeb, 0xef, 0x62, 0xad, 0x25, 0x6a,
-	0x36, 0xf1, 0x47, 0xc1, 0x34, 0xa2, 0x75, 0xc9, 0xff, 0x39, 0x11, 0xb3, 0xce, 0x3f, 0xb3, 0xf6,
-	0x89, 0xfb, 0xf1, 0x8a, 0x0f, 0xae, 0xaf, 0x38, 0x59, 0xfc, 0x20, 0x30, 0x6e, 0x60, 0xd0, 0x53,
-	0x70, 0x12, 0x0b, 0xe4, 0x3f, 0x10, 0x7e, 0x13, 0x8b, 0x8e, 0xfe, 0x80, 0x30, 0x10, 0x5d, 0x1b,
-	0x9d, 0x83, 0x9b, 0x9a, 0x6d, 0x8c, 0x99, 0x2a, 0xec, 0xc4, 0x44, 0xf4, 0x9e, 0x06, 0xe0, 0xca,
-	0x0f, 0x32, 0x31, 0x28, 0x53, 0x3b, 0xf3, 0x28, 0x3a, 0xaa, 0x4b, 0xde, 0x67, 0xa2, 0x57, 0x74,
-	0x01, 0x4e, 0xa6, 0xb5, 0x91, 0xa9, 0x1d, 0x7b, 0x14, 0x41, 0x5d, 0xf2, 0x2e, 0x11, 0x5d, 0xa5,
-	0x77, 0xc1, 0x51, 0x06, 0x37, 0x06, 0xd9, 0xc4, 0x92, 0xed, 0x5c, 0xd3, 0xab, 0x31, 0x46, 0xa3,
-	0x99, 0xe3, 0x93, 0x60, 0xd2, 0xf6, 0xb6, 0x89, 0xe8, 0xea, 0xe2, 0x14, 0xdc, 0x66, 0x92, 0x97,
-	0x99, 0xb6, 0x84, 0x9b, 0x3d, 0xd3, 0x8c, 0x58, 0x6e, 0x96, 0xb0, 0x0d, 0x44, 0x5b, 0x28, 0x85,
-	0x31, 0x5e, 0x6e, 0x64, 0xf7, 0x03, 0xad, 0x8e, 0xfc, 0x9f, 0xdf, 0x3c, 0x72, 0x5d, 0x79, 0xe4,
-	0x4b, 0xe5, 0x91, 0x9b, 0xca, 0x23, 0xb7, 0x95, 0x47, 0xbe, 0x56, 0x1e, 0xf9, 0xfc, 0xdd, 0x1b,
-	0xbc, 0x1d, 0xee, 0x96, 0x2b, 0xc7, 0xae, 0xd9, 0xc3, 0x5f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xe4,
-	0x17, 0xc3, 0xe9, 0xc5, 0x02, 0x00, 0x00,
+	// 556 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x93, 0xc1, 0x6e, 0xd3, 0x4c,
+	0x10, 0xc7, 0xb3, 0x75, 0x93, 0x38, 0xd3, 0xe6, 0xfb, 0xa4, 0x45, 0x2a, 0x6e, 0x24, 0xbc, 0x56,
+	0x24, 0x24, 0x1f, 0x90, 0xab, 0x06, 0x38, 0xc0, 0xa5, 0x60, 0x84, 0xc4, 0x01, 0x54, 0xc9, 0x88,
+	0x0b, 0x97, 0x68, 0x63, 0x6f, 0x53, 0xd3, 0xd8, 0x1b, 0x79, 0x77, 0xa3, 0xb4, 0x77, 0x24, 0x1e,
+	0x81, 0x63, 0x0f, 0x1c, 0xfa, 0x08, 0x3c, 0x42, 0x8f, 0x7d, 0x02, 0x0b, 0xc2, 0xcd, 0x4f, 0xc0,
+	0x11, 0x79, 0x6d, 0xa7, 0xa1, 0x12, 0xa7, 0x99, 0xf9, 0xcf, 0xcc, 0x7a, 0xfd, 0x9b, 0x59, 0x80,
+	0x53, 0xce, 0xcf, 0xbc, 0x79, 0xc6, 0x25, 0xc7, 0x7d, 0xc1, 0x52, 0xa1, 0xbc, 0x90, 0x67, 0xcc,
+	0x5b, 0x8c, 0x06, 0x4f, 0xa6, 0xb1, 0x3c, 0x55, 0x13, 0x2f, 0xe4, 0xc9, 0xc1, 0x94, 0x4f, 0xf9,
+	0x81, 0xae, 0x9a, 0xa8, 0x93, 0x17, 0x8b, 0x43, 0x6f, 0xe4, 0x1d, 0x6a, 0x51, 0x6b, 0xda, 0xab,
+	0x0e, 0x19, 0x40, 0xc2, 0x24, 0xad, 0xfc, 0xe1, 0x67, 0x03, 0xe0, 0x0d, 0xe7, 0x67, 0xaf, 0x78,
+	0x7a, 0x12, 0x4f, 0xf1, 0x07, 0x30, 0xcb, 0x64, 0x44, 0x25, 0xb5, 0x90, 0x83, 0xdc, 0x9d, 0xd1,
+	0xbe, 0xf7, 0xd7, 0x27, 0xbd, 0xe3, 0xc9, 0x27, 0x16, 0xca, 0x77, 0x4c, 0x52, 0xdf, 0xbe, 0xce,
+	0x49, 0xeb, 0x26, 0x27, 0xa8, 0xc8, 0x09, 0x6e, 0xda, 0x1e, 0xf1, 0x24, 0x96, 0x2c, 0x99, 0xcb,
+	0xf3, 0x60, 0x7d, 0x14, 0xb6, 0xa0, 0x1b, 0xf2, 0x24, 0xa1, 0x69, 0x64, 0x6d, 0x39, 0xc8, 0xed,
+	0x05, 0x4d, 0x88, 0x1f, 0x42, 0x57, 0xc6, 0x09, 0xe3, 0x4a, 0x5a, 0x86, 0x83, 0xdc, 0xbe, 0xbf,
+	0x53, 0xe4, 0xa4, 0x91, 0x82, 0xc6, 0xc1, 0x04, 0xda, 0x42, 0x46, 0x71, 0x6a, 0x6d, 0x3b, 0xc8,
+	0x35, 0xfd, 0x5e, 0x91, 0x93, 0x4a, 0x08, 0x2a, 0x83, 0x9f, 0xc1, 0x7f, 0x99, 0x4a, 0xcb, 0xf2,
+	0x31, 0x15, 0x82, 0x49, 0x61, 0xb5, 0x1d, 0xc3, 0xed, 0xf9, 0xb8, 0xc8, 0xc9, 0x9d, 0x4c, 0xd0,
+	0xaf, 0xe3, 0x97, 0x3a, 0xc4, 0xaf, 0xe1, 0xff, 0x84, 0x2e, 0xc7, 0x5c, 0xc9, 0xb9, 0x92, 0x63,
+	0x11, 0x5f, 0x30, 0xab, 0xe3, 0x20, 0xd7, 0xf0, 0x1f, 0x14, 0x39, 0xd9, 0xbf, 0x93, 0xda, 0xf8,
+	0xbd, 0x7e, 0x42, 0x97, 0xc7, 0x3a, 0xf3, 0x3e, 0xbe, 0x60, 0x78, 0x04, 0xe6, 0x9c, 0x66, 0x74,
+	0x36, 0x63, 0x33, 0xab, 0xab, 0x6f, 0xb9, 0x57, 0x72, 0x69, 0xb4, 0x4d, 0x2e, 0x8d, 0xf6, 0xdc,
+	0xfc, 0x72, 0x49, 0x5a, 0x57, 0x97, 0x04, 0x0d, 0xbf, 0x6d, 0xc1, 0x76, 0x39, 0x07, 0x7c, 0x04,
+	0x9d, 0x50, 0xcf, 0xe2, 0x1f, 0xfc, 0x6f, 0x87, 0xe5, 0xef, 0x6e, 0xf0, 0x6f, 0x05, 0x75, 0x1b,
+	0x1e, 0x80, 0x19, 0xa9, 0x8c, 0xca, 0x98, 0xa7, 0x1a, 0x36, 0x0a, 0xd6, 0x31, 0x76, 0xc1, 0x64,
+	0x4b, 0x16, 0x2a, 0xc9, 0x22, 0x8d, 0xdb, 0xf0, 0x77, 0x8b, 0x9c, 0xac, 0xb5, 0x60, 0xed, 0xe1,
+	0x21, 0x74, 0x62, 0x21, 0x14, 0x8b, 0x34, 0x71, 0xc3, 0x87, 0x22, 0x27, 0xb5, 0x12, 0xd4, 0x16,
+	0xef, 0x41, 0xa7, 0x22, 0x63, 0xb5, 0xf5, 0x50, 0xeb, 0xa8, 0xec, 0x15, 0x92, 0x4a, 0x25, 0x34,
+	0xc7, 0x76, 0xd5, 0x5b, 0x29, 0x41, 0x6d, 0xf1, 0x53, 0xe8, 0xc9, 0x4c, 0xa5, 0x21, 0x2d, 0xaf,
+	0x52, 0xe1, 0xba, 0x5f, 0xe4, 0xe4, 0xde, 0x5a, 0xdc, 0xe0, 0x75, 0x5b, 0x39, 0x3c, 0x02, 0xb3,
+	0x04, 0xf0, 0x36, 0x16, 0x7a, 0x27, 0xca, 0x97, 0x21, 0x2c, 0xa4, 0x27, 0xad, 0x77, 0x42, 0x0b,
+	0x41, 0x65, 0x30, 0x86, 0x6d, 0x79, 0x3e, 0x67, 0xf5, 0xca, 0x69, 0xdf, 0x77, 0x7e, 0xff, 0xb4,
+	0xd1, 0xd5, 0xca, 0x46, 0xdf, 0x57, 0x36, 0xba, 0x5e, 0xd9, 0xe8, 0x66, 0x65, 0xa3, 0x1f, 0x2b,
+	0x1b, 0x7d, 0xfd, 0x65, 0xb7, 0x3e, 0x6e, 0x2d, 0x46, 0x93, 0x8e, 0x7e, 0x18, 0x8f, 0xff, 0x04,
+	0x00, 0x00, 0xff, 0xff, 0x4c, 0xb1, 0xbb, 0xca, 0x77, 0x03, 0x00, 0x00,
 }
 
 func (this *HookConfig) Equal(that interface{}) bool {
@@ -301,6 +321,12 @@ func (this *HookConfig) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.MaxOutputSize != that1.MaxOutputSize {
+		return false
+	}
+	if this.Parallel != that1.Parallel {
+		return false
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -343,6 +369,9 @@ func (this *Hook) Equal(that interface{}) bool {
 	if this.Status != that1.Status {
 		return false
 	}
+	if this.Truncated != that1.Truncated {
+		return false
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -391,6 +420,8 @@ type HookConfigFace interface {
 	GetTimeout() uint32
 	GetStdin() bool
 	GetRuntimeAssets() []string
+	GetMaxOutputSize() int64
+	GetParallel() bool
 }
 
 func (this *HookConfig) Proto() github_com_golang_protobuf_proto.Message {
@@ -421,6 +452,14 @@ func (this *HookConfig) GetRuntimeAssets() []string {
 	return this.RuntimeAssets
 }
 
+func (this *HookConfig) GetMaxOutputSize() int64 {
+	return this.MaxOutputSize
+}
+
+func (this *HookConfig) GetParallel() bool {
+	return this.Parallel
+}
+
 func NewHookConfigFromFace(that HookConfigFace) *HookConfig {
 	this := &HookConfig{}
 	this.ObjectMeta = that.GetObjectMeta()
@@ -428,6 +467,8 @@ func NewHookConfigFromFace(that HookConfigFace) *HookConfig {
 	this.Timeout = that.GetTimeout()
 	this.Stdin = that.GetStdin()
 	this.RuntimeAssets = that.GetRuntimeAssets()
+	this.MaxOutputSize = that.GetMaxOutputSize()
+	this.Parallel = that.GetParallel()
 	return this
 }
 
@@ -490,6 +531,21 @@ func (m *HookConfig) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], s)
 		}
 	}
+	if m.MaxOutputSize != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintHook(dAtA, i, uint64(m.MaxOutputSize))
+	}
+	if m.Parallel {
+		dAtA[i] = 0x38
+		i++
+		if m.Parallel {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -546,6 +602,16 @@ func (m *Hook) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintHook(dAtA, i, uint64(m.Status))
 	}
+	if m.Truncated {
+		dAtA[i] = 0x38
+		i++
+		if m.Truncated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -615,8 +681,13 @@ func NewPopulatedHookConfig(r randyHook, easy bool) *HookConfig {
 	for i := 0; i < v2; i++ {
 		this.RuntimeAssets[i] = string(randStringHook(r))
 	}
+	this.MaxOutputSize = int64(r.Int63())
+	if r.Intn(2) == 0 {
+		this.MaxOutputSize *= -1
+	}
+	this.Parallel = bool(bool(r.Intn(2) == 0))
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedHook(r, 6)
+		this.XXX_unrecognized = randUnrecognizedHook(r, 8)
 	}
 	return this
 }
@@ -642,8 +713,9 @@ func NewPopulatedHook(r randyHook, easy bool) *Hook {
 	if r.Intn(2) == 0 {
 		this.Status *= -1
 	}
+	this.Truncated = bool(bool(r.Intn(2) == 0))
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedHook(r, 7)
+		this.XXX_unrecognized = randUnrecognizedHook(r, 8)
 	}
 	return this
 }
@@ -758,6 +830,12 @@ func (m *HookConfig) Size() (n int) {
 			n += 1 + l + sovHook(uint64(l))
 		}
 	}
+	if m.MaxOutputSize != 0 {
+		n += 1 + sovHook(uint64(m.MaxOutputSize))
+	}
+	if m.Parallel {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -788,6 +866,9 @@ func (m *Hook) Size() (n int) {
 	if m.Status != 0 {
 		n += 1 + sovHook(uint64(m.Status))
 	}
+	if m.Truncated {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -994,6 +1075,45 @@ func (m *HookConfig) Unmarshal(dAtA []byte) error {
 			}
 			m.RuntimeAssets = append(m.RuntimeAssets, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxOutputSize", wireType)
+			}
+			m.MaxOutputSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHook
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxOutputSize |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Parallel", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHook
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Parallel = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHook(dAtA[iNdEx:])
@@ -1181,6 +1301,26 @@ func (m *Hook) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Truncated", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHook
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Truncated = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHook(dAtA[iNdEx:])
@@ -28,6 +28,10 @@ func TestHookValidate(t *testing.T) {
 		Timeout: 10,
 	}
 	assert.NoError(t, h.Validate())
+
+	// Invalid max output size
+	h.MaxOutputSize = -1
+	assert.Error(t, h.Validate())
 }
 
 func TestHookListValidate(t *testing.T) {
@@ -11,3 +11,20 @@ func NewKeepaliveRecord(e *Entity, t int64) *KeepaliveRecord {
 		Time:       t,
 	}
 }
+
+const (
+	// KeepaliveInventoryAnnotation is the annotation of the keepalives whose
+	// entity was sent without its system inventory, to lighten them. The
+	// backend then keeps the system inventory it last received.
+	KeepaliveInventoryAnnotation = "sensu.io/keepalive-inventory"
+
+	// KeepaliveInventoryOmitted is the value of the inventory annotation of
+	// the keepalives sent without system inventory.
+	KeepaliveInventoryOmitted = "omitted"
+)
+
+// OmitsInventory returns true if the keepalive was sent without the system
+// inventory of its entity.
+func (e *Event) OmitsInventory() bool {
+	return e.Annotations[KeepaliveInventoryAnnotation] == KeepaliveInventoryOmitted
+}
@@ -0,0 +1,199 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+)
+
+const (
+	// LabelPolicyResource is the name of this resource type
+	LabelPolicyResource = "label-policy"
+)
+
+// LabelPolicyResources are the resource types on which the label policy is
+// enforced.
+var LabelPolicyResources = []string{ChecksResource, EntitiesResource}
+
+// LabelPolicy is the cluster-wide policy of the labels and annotations
+// required on resources when they are created, which lets routing rules rely
+// on their presence.
+type LabelPolicy struct {
+	// Rules are the requirements of the policy.
+	Rules []LabelPolicyRule `json:"rules"`
+
+	// patterns are the compiled patterns of the rules, by pattern. They are
+	// compiled when the policy is validated.
+	patterns map[string]*regexp.Regexp
+}
+
+// LabelPolicyRule requires labels and annotations on resources of the given
+// types. The labels and annotations map the required keys to regular
+// expressions their whole values must match, or to an empty string if any
+// value is accepted.
+type LabelPolicyRule struct {
+	// Resources are the resource types of the rule, e.g. "checks".
+	Resources []string `json:"resources"`
+
+	// Labels are the required labels.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are the required annotations.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// LabelPolicyViolations are the requirements of the label policy a resource
+// does not meet.
+type LabelPolicyViolations struct {
+	// Resource is the type of the resource.
+	Resource string
+
+	// Name is the name of the resource.
+	Name string
+
+	// Violations describes each unmet requirement.
+	Violations []string
+}
+
+// Error implements error.
+func (v *LabelPolicyViolations) Error() string {
+	return fmt.Sprintf(
+		"%s %q violates the label policy: %s",
+		v.Resource, v.Name, strings.Join(v.Violations, "; "),
+	)
+}
+
+// URIPath returns the path component of the label policy URI.
+func (p *LabelPolicy) URIPath() string {
+	return path.Join(URLPrefix, LabelPolicyResource)
+}
+
+// Validate returns an error if a rule of the label policy is invalid, and
+// compiles the patterns of its rules.
+func (p *LabelPolicy) Validate() error {
+	patterns := map[string]*regexp.Regexp{}
+	for i, rule := range p.Rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid rule %d: %s", i, err)
+		}
+		for _, requirements := range []map[string]string{rule.Labels, rule.Annotations} {
+			for _, pattern := range requirements {
+				if pattern != "" {
+					patterns[pattern] = regexp.MustCompile(anchorPattern(pattern))
+				}
+			}
+		}
+	}
+	p.patterns = patterns
+	return nil
+}
+
+// Validate returns an error if the rule has no resource, an unsupported
+// resource, an empty key or an invalid regular expression.
+func (r LabelPolicyRule) Validate() error {
+	if len(r.Resources) == 0 {
+		return errors.New("resources must not be empty")
+	}
+	for _, resource := range r.Resources {
+		if !utilstrings.InArray(resource, LabelPolicyResources) {
+			return fmt.Errorf(
+				"unsupported resource %q, must be one of: %s",
+				resource, strings.Join(LabelPolicyResources, ", "),
+			)
+		}
+	}
+	if len(r.Labels) == 0 && len(r.Annotations) == 0 {
+		return errors.New("labels and annotations must not both be empty")
+	}
+	if err := validateLabelRequirements("label", r.Labels); err != nil {
+		return err
+	}
+	return validateLabelRequirements("annotation", r.Annotations)
+}
+
+func validateLabelRequirements(kind string, requirements map[string]string) error {
+	for key, pattern := range requirements {
+		if key == "" {
+			return fmt.Errorf("%s keys must not be empty", kind)
+		}
+		if _, err := regexp.Compile(anchorPattern(pattern)); err != nil {
+			return fmt.Errorf("invalid pattern of %s %q: %s", kind, key, err)
+		}
+	}
+	return nil
+}
+
+// Enforce returns a *LabelPolicyViolations error if the metadata of a
+// resource of the given type does not meet the requirements of the policy.
+func (p *LabelPolicy) Enforce(resource string, meta ObjectMeta) error {
+	if p == nil {
+		return nil
+	}
+
+	var violations []string
+	for _, rule := range p.Rules {
+		if !utilstrings.InArray(resource, rule.Resources) {
+			continue
+		}
+		violations = append(violations, p.checkLabelRequirements("label", rule.Labels, meta.Labels)...)
+		violations = append(violations, p.checkLabelRequirements("annotation", rule.Annotations, meta.Annotations)...)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &LabelPolicyViolations{
+		Resource:   resource,
+		Name:       meta.Name,
+		Violations: violations,
+	}
+}
+
+func (p *LabelPolicy) checkLabelRequirements(kind string, requirements, values map[string]string) []string {
+	keys := make([]string, 0, len(requirements))
+	for key := range requirements {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var violations []string
+	for _, key := range keys {
+		pattern := requirements[key]
+		value, ok := values[key]
+		if !ok {
+			if pattern == "" {
+				violations = append(violations, fmt.Sprintf("missing required %s %q", kind, key))
+			} else {
+				violations = append(violations, fmt.Sprintf("missing required %s %q matching %q", kind, key, pattern))
+			}
+			continue
+		}
+		if pattern == "" {
+			continue
+		}
+		if re := p.pattern(pattern); re == nil || !re.MatchString(value) {
+			violations = append(violations, fmt.Sprintf("%s %q value %q does not match %q", kind, key, value, pattern))
+		}
+	}
+	return violations
+}
+
+// pattern returns the compiled pattern, compiling it if the policy was not
+// validated, or nil if it is invalid.
+func (p *LabelPolicy) pattern(pattern string) *regexp.Regexp {
+	if re, ok := p.patterns[pattern]; ok {
+		return re
+	}
+	re, _ := regexp.Compile(anchorPattern(pattern))
+	return re
+}
+
+// anchorPattern anchors a pattern so that it matches whole values.
+func anchorPattern(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
@@ -0,0 +1,156 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    LabelPolicyRule
+		wantErr bool
+	}{
+		{
+			name: "valid rule",
+			rule: LabelPolicyRule{
+				Resources:   []string{"checks", "entities"},
+				Labels:      map[string]string{"team": "^[a-z]+$", "service": ""},
+				Annotations: map[string]string{"runbook": "^https://"},
+			},
+		},
+		{
+			name:    "no resources",
+			rule:    LabelPolicyRule{Labels: map[string]string{"team": ""}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported resource",
+			rule:    LabelPolicyRule{Resources: []string{"handlers"}, Labels: map[string]string{"team": ""}},
+			wantErr: true,
+		},
+		{
+			name:    "no requirements",
+			rule:    LabelPolicyRule{Resources: []string{"checks"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty key",
+			rule:    LabelPolicyRule{Resources: []string{"checks"}, Labels: map[string]string{"": ""}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			rule:    LabelPolicyRule{Resources: []string{"checks"}, Annotations: map[string]string{"team": "["}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &LabelPolicy{Rules: []LabelPolicyRule{tt.rule}}
+			err := policy.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLabelPolicyEnforce(t *testing.T) {
+	policy := &LabelPolicy{
+		Rules: []LabelPolicyRule{
+			{
+				Resources: []string{"checks"},
+				Labels:    map[string]string{"team": "^[a-z]+$", "service": ""},
+			},
+			{
+				Resources:   []string{"checks", "entities"},
+				Annotations: map[string]string{"runbook": ""},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		resource   string
+		meta       ObjectMeta
+		violations []string
+	}{
+		{
+			name:     "compliant check",
+			resource: "checks",
+			meta: ObjectMeta{
+				Name:        "disk",
+				Labels:      map[string]string{"team": "ops", "service": "storage"},
+				Annotations: map[string]string{"runbook": "https://example.com"},
+			},
+		},
+		{
+			name:     "missing labels",
+			resource: "checks",
+			meta: ObjectMeta{
+				Name:        "disk",
+				Annotations: map[string]string{"runbook": "https://example.com"},
+			},
+			violations: []string{
+				`missing required label "service"`,
+				`missing required label "team" matching "^[a-z]+$"`,
+			},
+		},
+		{
+			name:     "mismatched label",
+			resource: "checks",
+			meta: ObjectMeta{
+				Name:        "disk",
+				Labels:      map[string]string{"team": "Ops", "service": "storage"},
+				Annotations: map[string]string{"runbook": "https://example.com"},
+			},
+			violations: []string{`label "team" value "Ops" does not match "^[a-z]+$"`},
+		},
+		{
+			name:       "entity rules only",
+			resource:   "entities",
+			meta:       ObjectMeta{Name: "server"},
+			violations: []string{`missing required annotation "runbook"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Enforce(tt.resource, tt.meta)
+			if len(tt.violations) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.IsType(t, &LabelPolicyViolations{}, err)
+			violations := err.(*LabelPolicyViolations)
+			assert.Equal(t, tt.resource, violations.Resource)
+			assert.Equal(t, tt.meta.Name, violations.Name)
+			assert.Equal(t, tt.violations, violations.Violations)
+		})
+	}
+}
+
+func TestNilLabelPolicyEnforce(t *testing.T) {
+	var policy *LabelPolicy
+	assert.NoError(t, policy.Enforce("checks", ObjectMeta{Name: "disk"}))
+}
+
+func TestLabelPolicyEnforceWholeValues(t *testing.T) {
+	policy := &LabelPolicy{
+		Rules: []LabelPolicyRule{
+			{
+				Resources: []string{"checks"},
+				Labels:    map[string]string{"team": "ops|dev"},
+			},
+		},
+	}
+	require.NoError(t, policy.Validate())
+
+	assert.NoError(t, policy.Enforce("checks", ObjectMeta{Name: "disk", Labels: map[string]string{"team": "dev"}}))
+	assert.Error(t, policy.Enforce("checks", ObjectMeta{Name: "disk", Labels: map[string]string{"team": "devops"}}))
+	assert.Error(t, policy.Enforce("checks", ObjectMeta{Name: "disk", Labels: map[string]string{"team": "operations"}}))
+}
@@ -0,0 +1,260 @@
+package v2
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+)
+
+const (
+	// FieldManagerQueryParam is the query parameter naming the field manager
+	// of the requests applying or updating resources.
+	FieldManagerQueryParam = "fieldManager"
+
+	// ForceConflictsQueryParam is the query parameter forcing the conflicts
+	// of the requests applying resources.
+	ForceConflictsQueryParam = "force"
+)
+
+// ManagedFields records the fields of a resource owned by each field manager,
+// i.e. each client configuring the resource with server-side apply, e.g.
+// sensuctl in a GitOps pipeline or the web UI. A manager applying a
+// configuration only changes the fields it sets, and cannot change the value
+// of the fields owned by other managers unless it forces the conflicts.
+type ManagedFields struct {
+	// Managers maps the field managers to the paths of the fields they own,
+	// as JSON pointers, e.g. /metadata/labels/team.
+	Managers map[string][]string `json:"managers"`
+}
+
+// FieldConflict is a field of a resource which a manager cannot change
+// because it is owned by another manager.
+type FieldConflict struct {
+	// Field is the path of the field, as a JSON pointer.
+	Field string `json:"field"`
+
+	// Manager is the manager owning the field.
+	Manager string `json:"manager"`
+}
+
+// Error implements error.
+func (c FieldConflict) Error() string {
+	return fmt.Sprintf("%s is owned by %s", c.Field, c.Manager)
+}
+
+// identityFields are the fields identifying a resource, which are not owned by
+// any manager.
+var identityFields = map[string]bool{
+	"/metadata/name":      true,
+	"/metadata/namespace": true,
+}
+
+// Apply merges the applied configuration of a manager into the current
+// configuration of a resource, both as decoded JSON objects, and records the
+// fields owned by the manager. The fields the manager previously applied but
+// no longer sets are removed, unless other managers own them. The merged
+// configuration is returned, or the conflicts if the manager changes fields
+// owned by other managers. Forcing the conflicts transfers the ownership of
+// the conflicting fields to the manager.
+func (m *ManagedFields) Apply(manager string, current, applied map[string]interface{}, force bool) (map[string]interface{}, []FieldConflict) {
+	if m.Managers == nil {
+		m.Managers = make(map[string][]string)
+	}
+	if current == nil {
+		current = make(map[string]interface{})
+	}
+
+	appliedFields := FieldPaths(applied)
+	var conflicts []FieldConflict
+	for _, field := range appliedFields {
+		value, _ := getField(applied, field)
+		currentValue, ok := getField(current, field)
+		if !ok || reflect.DeepEqual(value, currentValue) {
+			// The field is shared by the managers applying the same value
+			continue
+		}
+		for _, other := range m.sortedManagers() {
+			if other == manager || !utilstrings.InArray(field, m.Managers[other]) {
+				continue
+			}
+			if force {
+				m.Managers[other] = removeField(m.Managers[other], field)
+				continue
+			}
+			conflicts = append(conflicts, FieldConflict{Field: field, Manager: other})
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, conflicts
+	}
+
+	for _, field := range m.Managers[manager] {
+		if !utilstrings.InArray(field, appliedFields) && !m.ownedByOthers(manager, field) {
+			deleteField(current, field)
+		}
+	}
+	for _, field := range appliedFields {
+		value, _ := getField(applied, field)
+		setField(current, field, value)
+	}
+	for field := range identityFields {
+		if value, ok := getField(applied, field); ok {
+			setField(current, field, value)
+		}
+	}
+
+	m.Managers[manager] = appliedFields
+	m.prune()
+	return current, nil
+}
+
+// Update records the fields changed by a manager replacing the configuration
+// of a resource, both as decoded JSON objects. The manager takes the ownership
+// of the fields it adds or changes, and the fields it removes are no longer
+// owned by any manager. previous is nil if the resource did not exist.
+func (m *ManagedFields) Update(manager string, previous, updated map[string]interface{}) {
+	if m.Managers == nil {
+		m.Managers = make(map[string][]string)
+	}
+
+	updatedFields := FieldPaths(updated)
+	var changed []string
+	for _, field := range updatedFields {
+		value, _ := getField(updated, field)
+		previousValue, ok := getField(previous, field)
+		if !ok || !reflect.DeepEqual(value, previousValue) {
+			changed = append(changed, field)
+		}
+	}
+
+	for other, fields := range m.Managers {
+		var kept []string
+		for _, field := range fields {
+			if utilstrings.InArray(field, updatedFields) && (other == manager || !utilstrings.InArray(field, changed)) {
+				kept = append(kept, field)
+			}
+		}
+		m.Managers[other] = kept
+	}
+
+	for _, field := range changed {
+		if !utilstrings.InArray(field, m.Managers[manager]) {
+			m.Managers[manager] = append(m.Managers[manager], field)
+		}
+	}
+	sort.Strings(m.Managers[manager])
+	m.prune()
+}
+
+// FieldPaths returns the sorted paths, as JSON pointers, of the leaf fields of
+// a decoded JSON object. Arrays are leaf fields, and the fields identifying
+// the resource are omitted.
+func FieldPaths(obj map[string]interface{}) []string {
+	var paths []string
+	collectFieldPaths(obj, "", &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+func collectFieldPaths(obj map[string]interface{}, prefix string, paths *[]string) {
+	for key, value := range obj {
+		field := prefix + "/" + escapeFieldKey(key)
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			collectFieldPaths(child, field, paths)
+			continue
+		}
+		if !identityFields[field] {
+			*paths = append(*paths, field)
+		}
+	}
+}
+
+func escapeFieldKey(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
+
+func splitFieldPath(field string) []string {
+	keys := strings.Split(strings.TrimPrefix(field, "/"), "/")
+	for i, key := range keys {
+		keys[i] = strings.Replace(strings.Replace(key, "~1", "/", -1), "~0", "~", -1)
+	}
+	return keys
+}
+
+func getField(obj map[string]interface{}, field string) (interface{}, bool) {
+	keys := splitFieldPath(field)
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := obj[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		obj = child
+	}
+	value, ok := obj[keys[len(keys)-1]]
+	return value, ok
+}
+
+func setField(obj map[string]interface{}, field string, value interface{}) {
+	keys := splitFieldPath(field)
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := obj[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			obj[key] = child
+		}
+		obj = child
+	}
+	obj[keys[len(keys)-1]] = value
+}
+
+func deleteField(obj map[string]interface{}, field string) {
+	keys := splitFieldPath(field)
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := obj[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		obj = child
+	}
+	delete(obj, keys[len(keys)-1])
+}
+
+func removeField(fields []string, field string) []string {
+	var result []string
+	for _, f := range fields {
+		if f != field {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+func (m *ManagedFields) ownedByOthers(manager, field string) bool {
+	for other, fields := range m.Managers {
+		if other != manager && utilstrings.InArray(field, fields) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ManagedFields) sortedManagers() []string {
+	managers := make([]string, 0, len(m.Managers))
+	for manager := range m.Managers {
+		managers = append(managers, manager)
+	}
+	sort.Strings(managers)
+	return managers
+}
+
+// prune removes the managers owning no field.
+func (m *ManagedFields) prune() {
+	for manager, fields := range m.Managers {
+		if len(fields) == 0 {
+			delete(m.Managers, manager)
+		}
+	}
+}
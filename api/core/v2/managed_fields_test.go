@@ -0,0 +1,121 @@
+package v2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeFields(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	var obj map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &obj))
+	return obj
+}
+
+func TestFieldPaths(t *testing.T) {
+	obj := decodeFields(t, `{
+		"command": "check-disk",
+		"subscriptions": ["linux"],
+		"metadata": {
+			"name": "disk",
+			"namespace": "default",
+			"labels": {"team": "ops", "app.io/name": "disk", "a~b": "c"}
+		}
+	}`)
+	assert.Equal(t, []string{
+		"/command",
+		"/metadata/labels/app.io~1name",
+		"/metadata/labels/a~0b",
+		"/metadata/labels/team",
+		"/subscriptions",
+	}, FieldPaths(obj))
+}
+
+func TestManagedFieldsApply(t *testing.T) {
+	current := decodeFields(t, `{
+		"command": "check-disk",
+		"interval": 60,
+		"metadata": {"name": "disk", "labels": {"team": "ops"}}
+	}`)
+	managed := &ManagedFields{
+		Managers: map[string][]string{
+			"gitops": {"/command", "/interval", "/metadata/labels/owner"},
+			"web":    {"/metadata/labels/team"},
+		},
+	}
+
+	// gitops no longer sets /interval, and adds /timeout
+	applied := decodeFields(t, `{
+		"command": "check-disk",
+		"timeout": 10,
+		"metadata": {"name": "disk", "labels": {"team": "ops"}}
+	}`)
+	merged, conflicts := managed.Apply("gitops", current, applied, false)
+	require.Empty(t, conflicts)
+	assert.Equal(t, decodeFields(t, `{
+		"command": "check-disk",
+		"timeout": 10,
+		"metadata": {"name": "disk", "labels": {"team": "ops"}}
+	}`), merged)
+	assert.Equal(t, map[string][]string{
+		"gitops": {"/command", "/metadata/labels/team", "/timeout"},
+		"web":    {"/metadata/labels/team"},
+	}, managed.Managers)
+}
+
+func TestManagedFieldsApplyConflicts(t *testing.T) {
+	current := decodeFields(t, `{"command": "check-disk", "metadata": {"labels": {"team": "ops"}}}`)
+	managed := &ManagedFields{
+		Managers: map[string][]string{
+			"gitops": {"/command"},
+			"web":    {"/metadata/labels/team"},
+		},
+	}
+
+	applied := decodeFields(t, `{"command": "check-disk", "metadata": {"labels": {"team": "dev"}}}`)
+	merged, conflicts := managed.Apply("gitops", current, applied, false)
+	assert.Nil(t, merged)
+	assert.Equal(t, []FieldConflict{{Field: "/metadata/labels/team", Manager: "web"}}, conflicts)
+	assert.Equal(t, "/metadata/labels/team is owned by web", conflicts[0].Error())
+
+	// Forcing the conflicts transfers the ownership of the fields
+	merged, conflicts = managed.Apply("gitops", current, applied, true)
+	require.Empty(t, conflicts)
+	assert.Equal(t, "dev", merged["metadata"].(map[string]interface{})["labels"].(map[string]interface{})["team"])
+	assert.Equal(t, map[string][]string{
+		"gitops": {"/command", "/metadata/labels/team"},
+	}, managed.Managers)
+}
+
+func TestManagedFieldsApplyNewResource(t *testing.T) {
+	managed := &ManagedFields{}
+	applied := decodeFields(t, `{"command": "check-disk", "metadata": {"name": "disk"}}`)
+	merged, conflicts := managed.Apply("gitops", nil, applied, false)
+	require.Empty(t, conflicts)
+	assert.Equal(t, applied, merged)
+	assert.Equal(t, map[string][]string{"gitops": {"/command"}}, managed.Managers)
+}
+
+func TestManagedFieldsUpdate(t *testing.T) {
+	managed := &ManagedFields{
+		Managers: map[string][]string{
+			"gitops": {"/command", "/interval", "/timeout"},
+		},
+	}
+	previous := decodeFields(t, `{"command": "check-disk", "interval": 60, "timeout": 10}`)
+
+	// web changes the interval, removes the timeout and adds a label
+	updated := decodeFields(t, `{"command": "check-disk", "interval": 30, "metadata": {"labels": {"team": "ops"}}}`)
+	managed.Update("web", previous, updated)
+	assert.Equal(t, map[string][]string{
+		"gitops": {"/command"},
+		"web":    {"/interval", "/metadata/labels/team"},
+	}, managed.Managers)
+
+	// gitops cannot apply its interval without forcing the conflict
+	_, conflicts := managed.Apply("gitops", updated, previous, false)
+	assert.Equal(t, []FieldConflict{{Field: "/interval", Manager: "web"}}, conflicts)
+}
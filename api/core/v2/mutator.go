@@ -12,6 +12,14 @@ import (
 const (
 	// MutatorsResource is the name of this resource type
 	MutatorsResource = "mutators"
+
+	// MutatorPipeType represents mutators that pipe event data into arbitrary
+	// commands via STDIN
+	MutatorPipeType = "pipe"
+
+	// MutatorExtractMetricsType represents mutators that lift the numeric
+	// values of their allow list into the metric points of the event
+	MutatorExtractMetricsType = "extract_metrics"
 )
 
 // StorePrefix returns the path prefix to this resource in the store
@@ -29,8 +37,17 @@ func (m *Mutator) Validate() error {
 	if err := ValidateName(m.Name); err != nil {
 		return errors.New("mutator name " + err.Error())
 	}
-	if m.Command == "" {
-		return errors.New("mutator command must be set")
+	switch m.Type {
+	case "", MutatorPipeType:
+		if m.Command == "" {
+			return errors.New("mutator command must be set")
+		}
+	case MutatorExtractMetricsType:
+		if len(m.AllowList) == 0 {
+			return errors.New("mutator allow list must be set")
+		}
+	default:
+		return fmt.Errorf("unknown mutator type %q", m.Type)
 	}
 
 	if m.Namespace == "" {
@@ -53,6 +70,10 @@ func (m *Mutator) Update(from *Mutator, fields ...string) error {
 			m.EnvVars = append(m.EnvVars[0:0], from.EnvVars...)
 		case "RuntimeAssets":
 			m.RuntimeAssets = append(m.RuntimeAssets[0:0], from.RuntimeAssets...)
+		case "Type":
+			m.Type = from.Type
+		case "AllowList":
+			m.AllowList = append(m.AllowList[0:0], from.AllowList...)
 		default:
 			return fmt.Errorf("unsupported field: %q", f)
 		}
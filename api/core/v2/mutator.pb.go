@@ -35,7 +35,15 @@ type Mutator struct {
 	// Env is a list of environment variables to use with command execution
 	EnvVars []string `protobuf:"bytes,4,rep,name=env_vars,json=envVars,proto3" json:"env_vars"`
 	// RuntimeAssets are a list of assets required to execute a mutator.
-	RuntimeAssets        []string `protobuf:"bytes,8,rep,name=runtime_assets,json=runtimeAssets,proto3" json:"runtime_assets"`
+	RuntimeAssets []string `protobuf:"bytes,8,rep,name=runtime_assets,json=runtimeAssets,proto3" json:"runtime_assets"`
+	// Type is the type of the mutator, either "pipe" (the default), which
+	// executes the command, or "extract_metrics", which lifts the numeric
+	// values of the allow list into the metric points of the event.
+	Type string `protobuf:"bytes,9,opt,name=type,proto3" json:"type,omitempty"`
+	// AllowList are the check labels, annotations and JSON output paths, e.g.
+	// "disk.usage", whose numeric values are lifted by an extract_metrics
+	// mutator.
+	AllowList            []string `protobuf:"bytes,10,rep,name=allow_list,json=allowList,proto3" json:"allow_list,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -81,28 +89,31 @@ func init() {
 func init() { proto.RegisterFile("mutator.proto", fileDescriptor_a2bb83fa74d938fa) }
 
 var fileDescriptor_a2bb83fa74d938fa = []byte{
-	// 330 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x90, 0xb1, 0x4e, 0xf3, 0x30,
-	0x14, 0x85, 0xeb, 0xf6, 0xd7, 0x9f, 0xd4, 0x25, 0x0c, 0x9e, 0x42, 0x07, 0x3b, 0x42, 0x42, 0x64,
-	0x40, 0xae, 0x1a, 0x58, 0x60, 0x82, 0xec, 0x15, 0x52, 0x24, 0x18, 0x58, 0x2a, 0x27, 0x35, 0xa5,
-	0x48, 0x8e, 0xab, 0xd8, 0x89, 0xc4, 0x1b, 0xb0, 0xb1, 0x32, 0x76, 0xec, 0x23, 0xf0, 0x08, 0x1d,
-	0xfb, 0x04, 0x11, 0x84, 0x2d, 0x4f, 0xc0, 0x88, 0xea, 0x92, 0x4a, 0xb0, 0x9d, 0xf3, 0xdd, 0x7b,
-	0xcf, 0x91, 0x2e, 0x74, 0x44, 0xae, 0x99, 0x96, 0x19, 0x9d, 0x67, 0x52, 0x4b, 0xe4, 0x28, 0x9e,
-	0xaa, 0x9c, 0x26, 0x32, 0xe3, 0xb4, 0x08, 0xfa, 0x67, 0xd3, 0x99, 0x7e, 0xc8, 0x63, 0x9a, 0x48,
-	0x31, 0x98, 0xca, 0xa9, 0x1c, 0x98, 0xad, 0x38, 0xbf, 0xbf, 0x2c, 0x86, 0x34, 0xa0, 0x43, 0x03,
-	0x0d, 0x33, 0x6a, 0x1b, 0xd2, 0x87, 0x82, 0x6b, 0xb6, 0xd5, 0x87, 0x2f, 0x6d, 0x68, 0x8d, 0xb6,
-	0x15, 0xe8, 0x06, 0xda, 0x9b, 0xc9, 0x84, 0x69, 0xe6, 0x02, 0x0f, 0xf8, 0xbd, 0xe0, 0x80, 0xfe,
-	0xea, 0xa3, 0xd7, 0xf1, 0x23, 0x4f, 0xf4, 0x88, 0x6b, 0x16, 0xe2, 0x55, 0x49, 0x5a, 0xeb, 0x92,
-	0x80, 0xba, 0x24, 0xa8, 0x39, 0x3b, 0x91, 0x62, 0xa6, 0xb9, 0x98, 0xeb, 0xa7, 0x68, 0x17, 0x85,
-	0x5c, 0x68, 0x25, 0x52, 0x08, 0x96, 0x4e, 0xdc, 0xb6, 0x07, 0xfc, 0x6e, 0xd4, 0x58, 0x74, 0x04,
-	0x2d, 0x3d, 0x13, 0x5c, 0xe6, 0xda, 0xed, 0x78, 0xc0, 0x77, 0xc2, 0x5e, 0x5d, 0x92, 0x06, 0x45,
-	0x8d, 0x40, 0xc7, 0xd0, 0xe6, 0x69, 0x31, 0x2e, 0x58, 0xa6, 0xdc, 0x7f, 0x5e, 0xc7, 0xef, 0x86,
-	0x7b, 0x75, 0x49, 0x76, 0x2c, 0xb2, 0x78, 0x5a, 0xdc, 0xb2, 0x4c, 0xa1, 0x73, 0xb8, 0x9f, 0xe5,
-	0xe9, 0xe6, 0x6c, 0xcc, 0x94, 0xe2, 0x5a, 0xb9, 0xb6, 0x59, 0x47, 0x75, 0x49, 0xfe, 0x4c, 0x22,
-	0xe7, 0xc7, 0x5f, 0x19, 0x7b, 0x61, 0x3f, 0x2f, 0x48, 0x6b, 0xb9, 0x20, 0x20, 0xf4, 0xbe, 0x3e,
-	0x30, 0x58, 0x56, 0x18, 0xbc, 0x55, 0x18, 0xac, 0x2a, 0x0c, 0xd6, 0x15, 0x06, 0xef, 0x15, 0x06,
-	0xaf, 0x9f, 0xb8, 0x75, 0xd7, 0x2e, 0x82, 0xf8, 0xbf, 0x79, 0xdd, 0xe9, 0x77, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0xe9, 0xe3, 0xd6, 0xab, 0x9c, 0x01, 0x00, 0x00,
+	// 381 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x91, 0x31, 0x6f, 0xd4, 0x30,
+	0x18, 0x86, 0xcf, 0xd7, 0x8a, 0x24, 0x2e, 0xe9, 0x60, 0x31, 0x98, 0x0e, 0x76, 0x84, 0x04, 0x64,
+	0x40, 0xae, 0x1a, 0x90, 0x10, 0x4c, 0x90, 0x99, 0x0a, 0x29, 0x12, 0x0c, 0x2c, 0x27, 0x27, 0x35,
+	0x47, 0xd0, 0x39, 0x3e, 0xc5, 0x5f, 0x82, 0xba, 0x76, 0xe2, 0x27, 0x30, 0x76, 0xec, 0x4f, 0xe0,
+	0x27, 0x74, 0xec, 0x2f, 0x88, 0xb8, 0xb0, 0x65, 0x62, 0x64, 0x44, 0xf1, 0x91, 0x52, 0xba, 0x7d,
+	0xef, 0xf3, 0x7e, 0xef, 0xfb, 0x25, 0x32, 0x0e, 0x75, 0x03, 0x12, 0x4c, 0x2d, 0xd6, 0xb5, 0x01,
+	0x43, 0x42, 0xab, 0x2a, 0xdb, 0x88, 0xc2, 0xd4, 0x4a, 0xb4, 0xc9, 0xc1, 0xb3, 0x65, 0x09, 0x9f,
+	0x9a, 0x5c, 0x14, 0x46, 0x1f, 0x2e, 0xcd, 0xd2, 0x1c, 0xba, 0xad, 0xbc, 0xf9, 0xf8, 0xaa, 0x3d,
+	0x12, 0x89, 0x38, 0x72, 0xd0, 0x31, 0x37, 0x6d, 0x4b, 0x0e, 0xb0, 0x56, 0x20, 0xb7, 0xf3, 0x83,
+	0x5f, 0x73, 0xec, 0x1d, 0x6f, 0x4f, 0x90, 0x77, 0xd8, 0x1f, 0x9d, 0x13, 0x09, 0x92, 0xa2, 0x08,
+	0xc5, 0x7b, 0xc9, 0x7d, 0xf1, 0xdf, 0x3d, 0xf1, 0x36, 0xff, 0xac, 0x0a, 0x38, 0x56, 0x20, 0x53,
+	0x76, 0xd9, 0xf1, 0xd9, 0x55, 0xc7, 0xd1, 0xd0, 0x71, 0x32, 0xc5, 0x9e, 0x18, 0x5d, 0x82, 0xd2,
+	0x6b, 0x38, 0xcd, 0xae, 0xab, 0x08, 0xc5, 0x5e, 0x61, 0xb4, 0x96, 0xd5, 0x09, 0x9d, 0x47, 0x28,
+	0x0e, 0xb2, 0x49, 0x92, 0x87, 0xd8, 0x83, 0x52, 0x2b, 0xd3, 0x00, 0xdd, 0x89, 0x50, 0x1c, 0xa6,
+	0x7b, 0x43, 0xc7, 0x27, 0x94, 0x4d, 0x03, 0x79, 0x8c, 0x7d, 0x55, 0xb5, 0x8b, 0x56, 0xd6, 0x96,
+	0xee, 0x46, 0x3b, 0x71, 0x90, 0xde, 0x1d, 0x3a, 0x7e, 0xcd, 0x32, 0x4f, 0x55, 0xed, 0x7b, 0x59,
+	0x5b, 0xf2, 0x02, 0xef, 0xd7, 0x4d, 0x35, 0xc6, 0x16, 0xd2, 0x5a, 0x05, 0x96, 0xfa, 0x6e, 0x9d,
+	0x0c, 0x1d, 0xbf, 0xe5, 0x64, 0xe1, 0x5f, 0xfd, 0xda, 0x49, 0xf2, 0x08, 0xef, 0xc2, 0xe9, 0x5a,
+	0xd1, 0x60, 0xfc, 0xc2, 0x94, 0x9c, 0x6d, 0xf8, 0xfe, 0xa8, 0x6f, 0xfc, 0x90, 0xf3, 0xc9, 0x73,
+	0x8c, 0xe5, 0x6a, 0x65, 0xbe, 0x2c, 0x56, 0xa5, 0x05, 0x8a, 0x5d, 0x3d, 0x3d, 0xdb, 0xf0, 0x7b,
+	0xff, 0xe8, 0x8d, 0x4c, 0xe0, 0xe8, 0x9b, 0xd2, 0xc2, 0x4b, 0xff, 0xeb, 0x39, 0x9f, 0x5d, 0x9c,
+	0x73, 0x94, 0x46, 0xbf, 0x37, 0x0c, 0x5d, 0xf4, 0x0c, 0x7d, 0xef, 0x19, 0xba, 0xec, 0x19, 0xba,
+	0xea, 0x19, 0xfa, 0xd1, 0x33, 0xf4, 0xed, 0x27, 0x9b, 0x7d, 0x98, 0xb7, 0x49, 0x7e, 0xc7, 0xbd,
+	0xcd, 0xd3, 0x3f, 0x03, 0x00, 0x56, 0xb1, 0xa2, 0x1a, 0xfd, 0x01, 0x00, 0x00,
 }
 
 func (this *Mutator) Equal(that interface{}) bool {
@@ -149,6 +160,17 @@ func (this *Mutator) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.Type != that1.Type {
+		return false
+	}
+	if len(this.AllowList) != len(that1.AllowList) {
+		return false
+	}
+	for i := range this.AllowList {
+		if this.AllowList[i] != that1.AllowList[i] {
+			return false
+		}
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -162,6 +184,8 @@ type MutatorFace interface {
 	GetTimeout() uint32
 	GetEnvVars() []string
 	GetRuntimeAssets() []string
+	GetType() string
+	GetAllowList() []string
 }
 
 func (this *Mutator) Proto() github_com_golang_protobuf_proto.Message {
@@ -192,6 +216,14 @@ func (this *Mutator) GetRuntimeAssets() []string {
 	return this.RuntimeAssets
 }
 
+func (this *Mutator) GetType() string {
+	return this.Type
+}
+
+func (this *Mutator) GetAllowList() []string {
+	return this.AllowList
+}
+
 func NewMutatorFromFace(that MutatorFace) *Mutator {
 	this := &Mutator{}
 	this.ObjectMeta = that.GetObjectMeta()
@@ -199,6 +231,8 @@ func NewMutatorFromFace(that MutatorFace) *Mutator {
 	this.Timeout = that.GetTimeout()
 	this.EnvVars = that.GetEnvVars()
 	this.RuntimeAssets = that.GetRuntimeAssets()
+	this.Type = that.GetType()
+	this.AllowList = that.GetAllowList()
 	return this
 }
 
@@ -266,6 +300,27 @@ func (m *Mutator) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], s)
 		}
 	}
+	if len(m.Type) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintMutator(dAtA, i, uint64(len(m.Type)))
+		i += copy(dAtA[i:], m.Type)
+	}
+	if len(m.AllowList) > 0 {
+		for _, s := range m.AllowList {
+			dAtA[i] = 0x52
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -297,8 +352,14 @@ func NewPopulatedMutator(r randyMutator, easy bool) *Mutator {
 	for i := 0; i < v3; i++ {
 		this.RuntimeAssets[i] = string(randStringMutator(r))
 	}
+	this.Type = string(randStringMutator(r))
+	v4 := r.Intn(10)
+	this.AllowList = make([]string, v4)
+	for i := 0; i < v4; i++ {
+		this.AllowList[i] = string(randStringMutator(r))
+	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedMutator(r, 9)
+		this.XXX_unrecognized = randUnrecognizedMutator(r, 11)
 	}
 	return this
 }
@@ -322,9 +383,9 @@ func randUTF8RuneMutator(r randyMutator) rune {
 	return rune(ru + 61)
 }
 func randStringMutator(r randyMutator) string {
-	v4 := r.Intn(100)
-	tmps := make([]rune, v4)
-	for i := 0; i < v4; i++ {
+	v5 := r.Intn(100)
+	tmps := make([]rune, v5)
+	for i := 0; i < v5; i++ {
 		tmps[i] = randUTF8RuneMutator(r)
 	}
 	return string(tmps)
@@ -346,11 +407,11 @@ func randFieldMutator(dAtA []byte, r randyMutator, fieldNumber int, wire int) []
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateMutator(dAtA, uint64(key))
-		v5 := r.Int63()
+		v6 := r.Int63()
 		if r.Intn(2) == 0 {
-			v5 *= -1
+			v6 *= -1
 		}
-		dAtA = encodeVarintPopulateMutator(dAtA, uint64(v5))
+		dAtA = encodeVarintPopulateMutator(dAtA, uint64(v6))
 	case 1:
 		dAtA = encodeVarintPopulateMutator(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -402,6 +463,16 @@ func (m *Mutator) Size() (n int) {
 			n += 1 + l + sovMutator(uint64(l))
 		}
 	}
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovMutator(uint64(l))
+	}
+	if len(m.AllowList) > 0 {
+		for _, s := range m.AllowList {
+			l = len(s)
+			n += 1 + l + sovMutator(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -598,6 +669,70 @@ func (m *Mutator) Unmarshal(dAtA []byte) error {
 			}
 			m.RuntimeAssets = append(m.RuntimeAssets, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMutator
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMutator
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMutator
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowList", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMutator
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMutator
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMutator
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AllowList = append(m.AllowList, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMutator(dAtA[iNdEx:])
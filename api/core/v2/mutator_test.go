@@ -30,6 +30,19 @@ func TestMutatorValidate(t *testing.T) {
 
 	// Valid mutator
 	assert.NoError(t, m.Validate())
+
+	// Unknown type
+	m.Type = "foo"
+	assert.Error(t, m.Validate())
+
+	// Invalid allow list
+	m.Type = MutatorExtractMetricsType
+	m.Command = ""
+	assert.Error(t, m.Validate())
+	m.AllowList = []string{"disk.usage"}
+
+	// Valid extract_metrics mutator
+	assert.NoError(t, m.Validate())
 }
 
 func TestSortMutatorsByName(t *testing.T) {
@@ -26,7 +26,10 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 // Namespace represents a virtual cluster
 type Namespace struct {
 	// Name is the unique identifier for a namespace.
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// DefaultHandlers are the handlers of the events of the namespace whose
+	// check has no handlers.
+	DefaultHandlers      []string `protobuf:"bytes,2,rep,name=default_handlers,json=defaultHandlers,proto3" json:"default_handlers,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -72,6 +75,13 @@ func (m *Namespace) GetName() string {
 	return ""
 }
 
+func (m *Namespace) GetDefaultHandlers() []string {
+	if m != nil {
+		return m.DefaultHandlers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Namespace)(nil), "sensu.core.v2.Namespace")
 }
@@ -79,17 +89,20 @@ func init() {
 func init() { proto.RegisterFile("namespace.proto", fileDescriptor_ecb1e126f615f5dd) }
 
 var fileDescriptor_ecb1e126f615f5dd = []byte{
-	// 157 bytes of a gzipped FileDescriptorProto
+	// 208 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcf, 0x4b, 0xcc, 0x4d,
 	0x2d, 0x2e, 0x48, 0x4c, 0x4e, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x2d, 0x4e, 0xcd,
 	0x2b, 0x2e, 0xd5, 0x4b, 0xce, 0x2f, 0x4a, 0xd5, 0x2b, 0x33, 0x92, 0x32, 0x49, 0xcf, 0x2c, 0xc9,
 	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xcf, 0x4f, 0xcf, 0xd7, 0x07, 0xab, 0x4a, 0x2a,
 	0x4d, 0x73, 0x28, 0x33, 0xd4, 0x33, 0xd2, 0x33, 0x04, 0x0b, 0x82, 0xc5, 0xc0, 0x2c, 0x88, 0x21,
-	0x4a, 0xf2, 0x5c, 0x9c, 0x7e, 0x30, 0x73, 0x85, 0x84, 0xb8, 0x58, 0x40, 0x96, 0x48, 0x30, 0x2a,
-	0x30, 0x6a, 0x70, 0x06, 0x81, 0xd9, 0x4e, 0x0a, 0x3f, 0x1e, 0xca, 0x31, 0xae, 0x78, 0x24, 0xc7,
-	0xb8, 0xe3, 0x91, 0x1c, 0xe3, 0x89, 0x47, 0x72, 0x8c, 0x17, 0x1e, 0xc9, 0x31, 0x3e, 0x78, 0x24,
-	0xc7, 0x38, 0xe3, 0xb1, 0x1c, 0x43, 0x14, 0x53, 0x99, 0x51, 0x12, 0x1b, 0xd8, 0x24, 0x63, 0x40,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xa5, 0x9a, 0x72, 0x87, 0xa1, 0x00, 0x00, 0x00,
+	0x4a, 0x59, 0x5c, 0x9c, 0x7e, 0x30, 0x73, 0x85, 0x84, 0xb8, 0x58, 0x40, 0x96, 0x48, 0x30, 0x2a,
+	0x30, 0x6a, 0x70, 0x06, 0x81, 0xd9, 0x42, 0x9e, 0x5c, 0x02, 0x29, 0xa9, 0x69, 0x89, 0xa5, 0x39,
+	0x25, 0xf1, 0x19, 0x89, 0x79, 0x29, 0x39, 0xa9, 0x45, 0xc5, 0x12, 0x4c, 0x0a, 0xcc, 0x1a, 0x9c,
+	0x4e, 0x72, 0xaf, 0xee, 0xc9, 0x4b, 0xa1, 0xcb, 0xe9, 0xe4, 0xe7, 0x66, 0x96, 0xa4, 0xe6, 0x16,
+	0x94, 0x54, 0x06, 0xf1, 0x43, 0xe5, 0x3c, 0xa0, 0x52, 0x4e, 0x0a, 0x3f, 0x1e, 0xca, 0x31, 0xae,
+	0x78, 0x24, 0xc7, 0xb8, 0xe3, 0x91, 0x1c, 0xe3, 0x89, 0x47, 0x72, 0x8c, 0x17, 0x1e, 0xc9, 0x31,
+	0x3e, 0x78, 0x24, 0xc7, 0x38, 0xe3, 0xb1, 0x1c, 0x43, 0x14, 0x53, 0x99, 0x51, 0x12, 0x1b, 0xd8,
+	0x51, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x58, 0x1e, 0x97, 0xdd, 0xec, 0x00, 0x00, 0x00,
 }
 
 func (this *Namespace) Equal(that interface{}) bool {
@@ -114,6 +127,14 @@ func (this *Namespace) Equal(that interface{}) bool {
 	if this.Name != that1.Name {
 		return false
 	}
+	if len(this.DefaultHandlers) != len(that1.DefaultHandlers) {
+		return false
+	}
+	for i := range this.DefaultHandlers {
+		if this.DefaultHandlers[i] != that1.DefaultHandlers[i] {
+			return false
+		}
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -140,6 +161,21 @@ func (m *Namespace) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintNamespace(dAtA, i, uint64(len(m.Name)))
 		i += copy(dAtA[i:], m.Name)
 	}
+	if len(m.DefaultHandlers) > 0 {
+		for _, s := range m.DefaultHandlers {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -158,8 +194,13 @@ func encodeVarintNamespace(dAtA []byte, offset int, v uint64) int {
 func NewPopulatedNamespace(r randyNamespace, easy bool) *Namespace {
 	this := &Namespace{}
 	this.Name = string(randStringNamespace(r))
+	v1 := r.Intn(10)
+	this.DefaultHandlers = make([]string, v1)
+	for i := 0; i < v1; i++ {
+		this.DefaultHandlers[i] = string(randStringNamespace(r))
+	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedNamespace(r, 2)
+		this.XXX_unrecognized = randUnrecognizedNamespace(r, 3)
 	}
 	return this
 }
@@ -183,9 +224,9 @@ func randUTF8RuneNamespace(r randyNamespace) rune {
 	return rune(ru + 61)
 }
 func randStringNamespace(r randyNamespace) string {
-	v1 := r.Intn(100)
-	tmps := make([]rune, v1)
-	for i := 0; i < v1; i++ {
+	v2 := r.Intn(100)
+	tmps := make([]rune, v2)
+	for i := 0; i < v2; i++ {
 		tmps[i] = randUTF8RuneNamespace(r)
 	}
 	return string(tmps)
@@ -207,11 +248,11 @@ func randFieldNamespace(dAtA []byte, r randyNamespace, fieldNumber int, wire int
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateNamespace(dAtA, uint64(key))
-		v2 := r.Int63()
+		v3 := r.Int63()
 		if r.Intn(2) == 0 {
-			v2 *= -1
+			v3 *= -1
 		}
-		dAtA = encodeVarintPopulateNamespace(dAtA, uint64(v2))
+		dAtA = encodeVarintPopulateNamespace(dAtA, uint64(v3))
 	case 1:
 		dAtA = encodeVarintPopulateNamespace(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -246,6 +287,12 @@ func (m *Namespace) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovNamespace(uint64(l))
 	}
+	if len(m.DefaultHandlers) > 0 {
+		for _, s := range m.DefaultHandlers {
+			l = len(s)
+			n += 1 + l + sovNamespace(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -326,6 +373,38 @@ func (m *Namespace) Unmarshal(dAtA []byte) error {
 			}
 			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DefaultHandlers", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DefaultHandlers = append(m.DefaultHandlers, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
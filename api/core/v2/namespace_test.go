@@ -0,0 +1,18 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceValidate(t *testing.T) {
+	namespace := FixtureNamespace("default")
+	assert.NoError(t, namespace.Validate())
+
+	namespace.DefaultHandlers = []string{"slack", "pagerduty"}
+	assert.NoError(t, namespace.Validate())
+
+	namespace.DefaultHandlers = []string{"slack", ""}
+	assert.Error(t, namespace.Validate())
+}
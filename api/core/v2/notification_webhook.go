@@ -0,0 +1,166 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+)
+
+const (
+	// NotificationWebhooksResource is the name of this resource type
+	NotificationWebhooksResource = "notification-webhooks"
+
+	// NotificationWebhookActionCreated is the action of the resources created.
+	NotificationWebhookActionCreated = "created"
+
+	// NotificationWebhookActionUpdated is the action of the resources updated.
+	NotificationWebhookActionUpdated = "updated"
+
+	// NotificationWebhookActionDeleted is the action of the resources deleted.
+	NotificationWebhookActionDeleted = "deleted"
+)
+
+// NotificationWebhookResourceTypes are the resource types whose changes can
+// be notified by the notification webhooks.
+var NotificationWebhookResourceTypes = []string{
+	AssetsResource,
+	ChecksResource,
+	EventFiltersResource,
+	HandlersResource,
+	HooksResource,
+	MutatorsResource,
+	RemediationsResource,
+	RoleBindingsResource,
+	RolesResource,
+	SilencedResource,
+}
+
+// NotificationWebhookActions are the changes of the resources that can be
+// notified by the notification webhooks.
+var NotificationWebhookActions = []string{
+	NotificationWebhookActionCreated,
+	NotificationWebhookActionUpdated,
+	NotificationWebhookActionDeleted,
+}
+
+// NotificationWebhook posts a notification to an external URL whenever a
+// resource of the selected types changes in its namespace, e.g. to notify a
+// chat or a configuration drift detector when a check is updated.
+type NotificationWebhook struct {
+	ObjectMeta `json:"metadata"`
+
+	// URL is the URL the notifications are posted to.
+	URL string `json:"url"`
+
+	// ResourceTypes are the types of the resources whose changes are
+	// notified, e.g. checks or silenced.
+	ResourceTypes []string `json:"resource_types"`
+
+	// Actions are the changes notified: created, updated or deleted. All the
+	// changes are notified if empty.
+	Actions []string `json:"actions,omitempty"`
+
+	// Secret is the key of the HMAC-SHA256 signature of the notifications,
+	// sent in the X-Sensu-Signature header. The notifications are not signed
+	// if empty.
+	Secret string `json:"secret,omitempty"`
+
+	// Timeout is the time, in seconds, after which a notification fails.
+	// Defaults to 10 seconds.
+	Timeout uint32 `json:"timeout,omitempty"`
+
+	// Retries is the number of times a failed notification is posted again,
+	// with an exponential backoff.
+	Retries uint32 `json:"retries,omitempty"`
+}
+
+// FixtureNotificationWebhook returns a notification webhook for testing.
+func FixtureNotificationWebhook(name string) *NotificationWebhook {
+	return &NotificationWebhook{
+		ObjectMeta:    NewObjectMeta(name, "default"),
+		URL:           "https://example.com/hooks/sensu",
+		ResourceTypes: []string{ChecksResource},
+	}
+}
+
+// GetObjectMeta returns the object metadata of the notification webhook.
+func (w *NotificationWebhook) GetObjectMeta() ObjectMeta {
+	return w.ObjectMeta
+}
+
+// SetNamespace sets the namespace of the resource.
+func (w *NotificationWebhook) SetNamespace(namespace string) {
+	w.Namespace = namespace
+}
+
+// StorePrefix returns the path prefix to this resource in the store
+func (w *NotificationWebhook) StorePrefix() string {
+	return NotificationWebhooksResource
+}
+
+// URIPath returns the path component of a notification webhook URI.
+func (w *NotificationWebhook) URIPath() string {
+	return path.Join(URLPrefix, "namespaces", url.PathEscape(w.Namespace), NotificationWebhooksResource, url.PathEscape(w.Name))
+}
+
+// Validate returns an error if the notification webhook does not pass
+// validation tests.
+func (w *NotificationWebhook) Validate() error {
+	if err := ValidateName(w.Name); err != nil {
+		return errors.New("notification webhook name " + err.Error())
+	}
+	if w.Namespace == "" {
+		return errors.New("namespace must be set")
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %s", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q, must be an absolute http or https url", w.URL)
+	}
+	if len(w.ResourceTypes) == 0 {
+		return errors.New("at least one resource type must be set")
+	}
+	for _, resourceType := range w.ResourceTypes {
+		if !stringsContain(NotificationWebhookResourceTypes, resourceType) {
+			return fmt.Errorf("invalid resource type %q, must be one of %v", resourceType, NotificationWebhookResourceTypes)
+		}
+	}
+	for _, action := range w.Actions {
+		if !stringsContain(NotificationWebhookActions, action) {
+			return fmt.Errorf("invalid action %q, must be one of %v", action, NotificationWebhookActions)
+		}
+	}
+	return nil
+}
+
+// Selects returns true if the webhook notifies the given change of the
+// resources of the given type.
+func (w *NotificationWebhook) Selects(resourceType, action string) bool {
+	if !stringsContain(w.ResourceTypes, resourceType) {
+		return false
+	}
+	return len(w.Actions) == 0 || stringsContain(w.Actions, action)
+}
+
+// NotificationWebhookFields returns a set of fields that represent that
+// resource
+func NotificationWebhookFields(r Resource) map[string]string {
+	resource := r.(*NotificationWebhook)
+	return map[string]string{
+		"notification_webhook.name":      resource.ObjectMeta.Name,
+		"notification_webhook.namespace": resource.ObjectMeta.Namespace,
+		"notification_webhook.url":       resource.URL,
+	}
+}
+
+func stringsContain(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationWebhookValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*NotificationWebhook)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			mutate: func(w *NotificationWebhook) {},
+		},
+		{
+			name:    "missing namespace",
+			mutate:  func(w *NotificationWebhook) { w.Namespace = "" },
+			wantErr: true,
+		},
+		{
+			name:    "missing url",
+			mutate:  func(w *NotificationWebhook) { w.URL = "" },
+			wantErr: true,
+		},
+		{
+			name:    "relative url",
+			mutate:  func(w *NotificationWebhook) { w.URL = "/hooks/sensu" },
+			wantErr: true,
+		},
+		{
+			name:    "unsupported url scheme",
+			mutate:  func(w *NotificationWebhook) { w.URL = "ftp://example.com" },
+			wantErr: true,
+		},
+		{
+			name:    "missing resource types",
+			mutate:  func(w *NotificationWebhook) { w.ResourceTypes = nil },
+			wantErr: true,
+		},
+		{
+			name:    "unsupported resource type",
+			mutate:  func(w *NotificationWebhook) { w.ResourceTypes = []string{EventsResource} },
+			wantErr: true,
+		},
+		{
+			name:    "invalid action",
+			mutate:  func(w *NotificationWebhook) { w.Actions = []string{"renamed"} },
+			wantErr: true,
+		},
+		{
+			name: "all fields",
+			mutate: func(w *NotificationWebhook) {
+				w.ResourceTypes = []string{ChecksResource, SilencedResource}
+				w.Actions = []string{NotificationWebhookActionCreated}
+				w.Secret = "secret"
+				w.Timeout = 5
+				w.Retries = 3
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := FixtureNotificationWebhook("foo")
+			tt.mutate(w)
+			if err := w.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("NotificationWebhook.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNotificationWebhookSelects(t *testing.T) {
+	w := FixtureNotificationWebhook("foo")
+	assert.True(t, w.Selects(ChecksResource, NotificationWebhookActionCreated))
+	assert.True(t, w.Selects(ChecksResource, NotificationWebhookActionDeleted))
+	assert.False(t, w.Selects(SilencedResource, NotificationWebhookActionCreated))
+
+	w.Actions = []string{NotificationWebhookActionUpdated}
+	assert.True(t, w.Selects(ChecksResource, NotificationWebhookActionUpdated))
+	assert.False(t, w.Selects(ChecksResource, NotificationWebhookActionCreated))
+}
@@ -32,6 +32,24 @@ const (
 	// LocalSelfUserResource represents a local user trying to view itself
 	// or change its password
 	LocalSelfUserResource = "localselfuser"
+
+	// DebugResource represents the debug endpoints of the backend, which are
+	// only accessible with the VerbDebug verb
+	DebugResource = "debug"
+	// VerbDebug is the verb required to access the debug endpoints
+	VerbDebug = "debug"
+
+	// ClusterConfigResource represents the effective configuration of the
+	// backend, which is only granted by rules on all resources unless named
+	ClusterConfigResource = "cluster-config"
+
+	// AgentCommandsResource represents the ad-hoc commands run on connected
+	// agents, which are not granted by the rules on entities
+	AgentCommandsResource = "agent-commands"
+
+	// HandlerOutputsResource represents the captured outputs of the handler
+	// executions, which are not granted by the rules on handlers
+	HandlerOutputsResource = "handler-outputs"
 )
 
 // CommonCoreResources represents the common "core" resources found in a
@@ -46,6 +64,8 @@ var CommonCoreResources = []string{
 	"handlers",
 	"hooks",
 	"mutators",
+	"notification-webhooks",
+	"remediations",
 	"silenced",
 }
 
@@ -0,0 +1,140 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+)
+
+const (
+	// RemediationsResource is the name of this resource type
+	RemediationsResource = "remediations"
+)
+
+// Remediation requests the ad-hoc execution of a check when the results of
+// another check meet its trigger conditions, e.g. to restart a service when
+// the check monitoring it fails.
+type Remediation struct {
+	ObjectMeta `json:"metadata"`
+
+	// Check is the name of the check whose results trigger the remediation.
+	Check string `json:"check"`
+
+	// Statuses are the check statuses that trigger the remediation.
+	Statuses []uint32 `json:"statuses"`
+
+	// Occurrences are the numbers of consecutive occurrences of a status that
+	// trigger the remediation. The remediation is only triggered on the first
+	// occurrence if empty.
+	Occurrences []int64 `json:"occurrences,omitempty"`
+
+	// Request is the name of the check executed to remediate the problem.
+	Request string `json:"request"`
+
+	// Subscriptions are the subscriptions the remediation check is executed
+	// on. The check is executed on the entity of the triggering event if
+	// empty.
+	Subscriptions []string `json:"subscriptions,omitempty"`
+}
+
+// FixtureRemediation returns a remediation for testing.
+func FixtureRemediation(name string) *Remediation {
+	return &Remediation{
+		ObjectMeta: NewObjectMeta(name, "default"),
+		Check:      "check",
+		Statuses:   []uint32{2},
+		Request:    "remediate",
+	}
+}
+
+// GetObjectMeta returns the object metadata of the remediation.
+func (r *Remediation) GetObjectMeta() ObjectMeta {
+	return r.ObjectMeta
+}
+
+// SetNamespace sets the namespace of the resource.
+func (r *Remediation) SetNamespace(namespace string) {
+	r.Namespace = namespace
+}
+
+// StorePrefix returns the path prefix to this resource in the store
+func (r *Remediation) StorePrefix() string {
+	return RemediationsResource
+}
+
+// URIPath returns the path component of a remediation URI.
+func (r *Remediation) URIPath() string {
+	return path.Join(URLPrefix, "namespaces", url.PathEscape(r.Namespace), RemediationsResource, url.PathEscape(r.Name))
+}
+
+// Validate returns an error if the remediation does not pass validation
+// tests.
+func (r *Remediation) Validate() error {
+	if err := ValidateName(r.Name); err != nil {
+		return errors.New("remediation name " + err.Error())
+	}
+	if r.Namespace == "" {
+		return errors.New("namespace must be set")
+	}
+	if err := ValidateName(r.Check); err != nil {
+		return errors.New("check name " + err.Error())
+	}
+	if len(r.Statuses) == 0 {
+		return errors.New("at least one status must be set")
+	}
+	for _, occurrence := range r.Occurrences {
+		if occurrence < 1 {
+			return fmt.Errorf("invalid occurrence %d, must be greater than 0", occurrence)
+		}
+	}
+	if err := ValidateName(r.Request); err != nil {
+		return errors.New("request check name " + err.Error())
+	}
+	for _, subscription := range r.Subscriptions {
+		if err := ValidateSubscriptionName(subscription); err != nil {
+			return fmt.Errorf("subscription %s", err)
+		}
+	}
+	return nil
+}
+
+// Triggered returns true if the result of the check of the event meets the
+// trigger conditions of the remediation.
+func (r *Remediation) Triggered(event *Event) bool {
+	if !event.HasCheck() || event.Check.Name != r.Check || event.Check.Namespace != r.Namespace {
+		return false
+	}
+
+	var status bool
+	for _, s := range r.Statuses {
+		if s == event.Check.Status {
+			status = true
+			break
+		}
+	}
+	if !status {
+		return false
+	}
+
+	if len(r.Occurrences) == 0 {
+		return event.Check.Occurrences == 1
+	}
+	for _, occurrence := range r.Occurrences {
+		if occurrence == event.Check.Occurrences {
+			return true
+		}
+	}
+	return false
+}
+
+// RemediationFields returns a set of fields that represent that resource
+func RemediationFields(r Resource) map[string]string {
+	resource := r.(*Remediation)
+	return map[string]string{
+		"remediation.name":      resource.ObjectMeta.Name,
+		"remediation.namespace": resource.ObjectMeta.Namespace,
+		"remediation.check":     resource.Check,
+		"remediation.request":   resource.Request,
+	}
+}
@@ -0,0 +1,111 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemediationValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Remediation)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			mutate: func(r *Remediation) {},
+		},
+		{
+			name:    "missing check",
+			mutate:  func(r *Remediation) { r.Check = "" },
+			wantErr: true,
+		},
+		{
+			name:    "missing statuses",
+			mutate:  func(r *Remediation) { r.Statuses = nil },
+			wantErr: true,
+		},
+		{
+			name:    "invalid occurrence",
+			mutate:  func(r *Remediation) { r.Occurrences = []int64{0} },
+			wantErr: true,
+		},
+		{
+			name:    "missing request",
+			mutate:  func(r *Remediation) { r.Request = "" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid subscription",
+			mutate:  func(r *Remediation) { r.Subscriptions = []string{"a b"} },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := FixtureRemediation("foo")
+			tt.mutate(r)
+			if err := r.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Remediation.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemediationTriggered(t *testing.T) {
+	tests := []struct {
+		name        string
+		check       string
+		status      uint32
+		occurrences int64
+		remediation func(*Remediation)
+		want        bool
+	}{
+		{
+			name:        "first occurrence",
+			check:       "check",
+			status:      2,
+			occurrences: 1,
+			want:        true,
+		},
+		{
+			name:        "later occurrence",
+			check:       "check",
+			status:      2,
+			occurrences: 2,
+		},
+		{
+			name:        "other check",
+			check:       "other",
+			status:      2,
+			occurrences: 1,
+		},
+		{
+			name:        "other status",
+			check:       "check",
+			status:      1,
+			occurrences: 1,
+		},
+		{
+			name:        "listed occurrence",
+			check:       "check",
+			status:      2,
+			occurrences: 3,
+			remediation: func(r *Remediation) { r.Occurrences = []int64{1, 3} },
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := FixtureRemediation("foo")
+			if tt.remediation != nil {
+				tt.remediation(r)
+			}
+			event := FixtureEvent("entity", tt.check)
+			event.Check.Status = tt.status
+			event.Check.Occurrences = tt.occurrences
+			assert.Equal(t, tt.want, r.Triggered(event))
+		})
+	}
+}
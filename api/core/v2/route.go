@@ -0,0 +1,124 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+)
+
+const (
+	// RoutesResource is the name of the routes resource type
+	RoutesResource = "routes"
+)
+
+// Route is a cluster-wide notification routing rule, which sends the events it
+// selects to its handlers in addition to the handlers of their check. It lets
+// the notifications be routed without editing the checks.
+type Route struct {
+	// Name is the unique name of the route.
+	Name string `json:"name"`
+
+	// Namespaces restricts the route to the events of the given namespaces.
+	// The route applies to all the namespaces if empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Labels selects the events whose check or entity has all the given
+	// labels, with the given values. The labels of the check override the
+	// labels of the entity.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Severities selects the events whose check status has one of the given
+	// severities, among the severities of the check hooks. The route applies
+	// to all the severities if empty.
+	Severities []string `json:"severities,omitempty"`
+
+	// Handlers are the names of the handlers, or handler sets, the selected
+	// events are sent to. They are resolved in the namespace of each event.
+	Handlers []string `json:"handlers"`
+}
+
+// URIPath returns the path component of the route URI.
+func (r *Route) URIPath() string {
+	return path.Join(URLPrefix, RoutesResource, r.Name)
+}
+
+// Validate returns an error if the route is invalid.
+func (r *Route) Validate() error {
+	if err := ValidateName(r.Name); err != nil {
+		return errors.New("route name " + err.Error())
+	}
+	if len(r.Handlers) == 0 {
+		return errors.New("handlers must not be empty")
+	}
+	for _, severity := range r.Severities {
+		if !utilstrings.InArray(severity, Severities) {
+			return fmt.Errorf(
+				"invalid severity %q, must be one of: %s",
+				severity, strings.Join(Severities, ", "),
+			)
+		}
+	}
+	if _, ok := r.Labels[""]; ok {
+		return errors.New("label keys must not be empty")
+	}
+	return nil
+}
+
+// Matches returns true if the route selects the given event.
+func (r *Route) Matches(event *Event) bool {
+	if event.Entity == nil {
+		return false
+	}
+	if len(r.Namespaces) > 0 && !utilstrings.InArray(event.Entity.Namespace, r.Namespaces) {
+		return false
+	}
+	if len(r.Severities) > 0 {
+		if !event.HasCheck() {
+			return false
+		}
+		matched := false
+		for _, severity := range r.Severities {
+			matched = matched || matchesSeverity(event.Check.Status, severity)
+		}
+		if !matched {
+			return false
+		}
+	}
+	for key, value := range r.Labels {
+		if !eventHasLabel(event, key, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesSeverity returns true if the check status has the given severity,
+// like the check hooks.
+func matchesSeverity(status uint32, severity string) bool {
+	switch severity {
+	case "ok":
+		return status == 0
+	case "warning":
+		return status == 1
+	case "critical":
+		return status == 2
+	case "unknown":
+		return status > 2
+	case "non-zero":
+		return status != 0
+	}
+	return false
+}
+
+func eventHasLabel(event *Event, key, value string) bool {
+	if event.HasCheck() {
+		if v, ok := event.Check.Labels[key]; ok {
+			return v == value
+		}
+	}
+	v, ok := event.Entity.Labels[key]
+	return ok && v == value
+}
@@ -0,0 +1,119 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   Route
+		wantErr bool
+	}{
+		{
+			name:  "valid route",
+			route: Route{Name: "db", Labels: map[string]string{"team": "db"}, Severities: []string{"critical"}, Handlers: []string{"opsgenie"}},
+		},
+		{
+			name:    "invalid name",
+			route:   Route{Name: "d b", Handlers: []string{"opsgenie"}},
+			wantErr: true,
+		},
+		{
+			name:    "no handlers",
+			route:   Route{Name: "db"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid severity",
+			route:   Route{Name: "db", Severities: []string{"major"}, Handlers: []string{"opsgenie"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty label key",
+			route:   Route{Name: "db", Labels: map[string]string{"": "db"}, Handlers: []string{"opsgenie"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.route.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Route.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRouteMatches(t *testing.T) {
+	event := &Event{
+		Entity: &Entity{ObjectMeta: ObjectMeta{
+			Name:      "db01",
+			Namespace: "acme",
+			Labels:    map[string]string{"team": "db", "region": "us-west-2"},
+		}},
+		Check: &Check{
+			ObjectMeta: ObjectMeta{Name: "disk", Namespace: "acme", Labels: map[string]string{"team": "storage"}},
+			Status:     2,
+		},
+	}
+
+	tests := []struct {
+		name  string
+		route Route
+		want  bool
+	}{
+		{
+			name:  "no selector",
+			route: Route{},
+			want:  true,
+		},
+		{
+			name:  "matching namespace",
+			route: Route{Namespaces: []string{"default", "acme"}},
+			want:  true,
+		},
+		{
+			name:  "other namespace",
+			route: Route{Namespaces: []string{"default"}},
+		},
+		{
+			name:  "matching entity label",
+			route: Route{Labels: map[string]string{"region": "us-west-2"}},
+			want:  true,
+		},
+		{
+			name:  "check label overrides entity label",
+			route: Route{Labels: map[string]string{"team": "db"}},
+		},
+		{
+			name:  "matching check label",
+			route: Route{Labels: map[string]string{"team": "storage", "region": "us-west-2"}},
+			want:  true,
+		},
+		{
+			name:  "missing label",
+			route: Route{Labels: map[string]string{"env": "prod"}},
+		},
+		{
+			name:  "matching severity",
+			route: Route{Severities: []string{"warning", "critical"}},
+			want:  true,
+		},
+		{
+			name:  "non-zero severity",
+			route: Route{Severities: []string{"non-zero"}},
+			want:  true,
+		},
+		{
+			name:  "other severity",
+			route: Route{Severities: []string{"ok", "unknown"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.route.Matches(event))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ValidateRunAt ensures that the times at which a check is scheduled are in
+// RFC 3339 format.
+func ValidateRunAt(runAt []string) error {
+	for _, t := range runAt {
+		if t == "" {
+			return errors.New("run_at times must not be empty")
+		}
+		if _, err := time.Parse(time.RFC3339, t); err != nil {
+			return fmt.Errorf("invalid run_at time %q: must be in RFC 3339 format", t)
+		}
+	}
+	return nil
+}
+
+// NextRunAt returns the earliest of the times at which a check is scheduled
+// that is after now, and false if there is none left. Invalid times are
+// ignored.
+func NextRunAt(runAt []string, now time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, s := range runAt {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil || !t.After(now) {
+			continue
+		}
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	return next, !next.IsZero()
+}
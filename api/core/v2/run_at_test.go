@@ -0,0 +1,30 @@
+package v2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRunAt(t *testing.T) {
+	assert.NoError(t, ValidateRunAt(nil))
+	assert.NoError(t, ValidateRunAt([]string{"2019-07-01T02:00:00Z", "2019-07-01T04:00:00-04:00"}))
+	assert.Error(t, ValidateRunAt([]string{""}))
+	assert.Error(t, ValidateRunAt([]string{"2019-07-01 02:00"}))
+}
+
+func TestNextRunAt(t *testing.T) {
+	runAt := []string{"2019-07-03T00:00:00Z", "2019-07-01T00:00:00Z", "2019-07-02T00:00:00Z"}
+
+	next, ok := NextRunAt(runAt, time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC), next.UTC())
+
+	next, ok = NextRunAt(runAt, time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2019, 7, 2, 0, 0, 0, 0, time.UTC), next.UTC())
+
+	_, ok = NextRunAt(runAt, time.Date(2019, 7, 3, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
@@ -15,6 +15,14 @@ import (
 const (
 	// SilencedResource is the name of this resource type
 	SilencedResource = "silenced"
+
+	// SnoozedAtAnnotation is the annotation of the silenced entries created by
+	// snoozing an event, which holds the time the event was snoozed at.
+	SnoozedAtAnnotation = "sensu.io/snoozed-at"
+
+	// SnoozeDurationAnnotation is the annotation of the silenced entries
+	// created by snoozing an event, which holds the duration of the snooze.
+	SnoozeDurationAnnotation = "sensu.io/snooze-duration"
 )
 
 // StorePrefix returns the path prefix to this resource in the store
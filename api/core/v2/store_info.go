@@ -0,0 +1,33 @@
+package v2
+
+// StoreInfo describes the keys of the store, sampled periodically.
+type StoreInfo struct {
+	// Sampled is the time the store was sampled, as a Unix timestamp.
+	Sampled int64
+	// Revision is the revision of the store that was sampled.
+	Revision int64
+	// DBSize is the size of the database of the cluster member that served
+	// the sample, in bytes. It includes the history of the keys not
+	// compacted yet.
+	DBSize int64
+	// Keys is the total number of keys.
+	Keys int64
+	// Size is the total size of the keys and their values, in bytes.
+	Size int64
+	// Resources is the number of keys and their size for every resource type
+	// and namespace, the largest first.
+	Resources []*ResourceStoreInfo
+}
+
+// ResourceStoreInfo describes the keys of a resource type in a namespace.
+type ResourceStoreInfo struct {
+	// Resource is the resource type, as found in the keys of the store.
+	Resource string
+	// Namespace is the namespace of the resources, empty for the resources
+	// that are not namespaced.
+	Namespace string
+	// Keys is the number of keys.
+	Keys int64
+	// Size is the size of the keys and their values, in bytes.
+	Size int64
+}
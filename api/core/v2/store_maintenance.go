@@ -0,0 +1,60 @@
+package v2
+
+const (
+	// StoreMaintenanceTriggerSchedule is the trigger of the maintenances run
+	// on schedule.
+	StoreMaintenanceTriggerSchedule = "schedule"
+
+	// StoreMaintenanceTriggerAPI is the trigger of the maintenances requested
+	// through the API.
+	StoreMaintenanceTriggerAPI = "api"
+)
+
+// StoreMaintenance describes a compaction and defragmentation of the store.
+type StoreMaintenance struct {
+	// Trigger is what started the maintenance, either
+	// StoreMaintenanceTriggerSchedule or StoreMaintenanceTriggerAPI.
+	Trigger string
+	// Started is the time the maintenance started, as a Unix timestamp.
+	Started int64
+	// Finished is the time the maintenance finished, as a Unix timestamp. It
+	// is 0 while the maintenance is running.
+	Finished int64
+	// CompactRevision is the revision up to which the store was compacted. It
+	// is 0 if the store had fewer revisions than the retained ones.
+	CompactRevision int64
+	// CompactErr holds the string representation of any error encountered
+	// while compacting the store.
+	CompactErr string
+	// Members is the defragmentation of every cluster member.
+	Members []*MemberMaintenance
+	// AlarmErr holds the string representation of any error encountered
+	// while listing or disarming the NOSPACE alarms.
+	AlarmErr string
+}
+
+// MemberMaintenance describes the defragmentation of a cluster member.
+type MemberMaintenance struct {
+	// MemberID is the etcd cluster member's ID.
+	MemberID uint64
+	// Name is the cluster member's name.
+	Name string
+	// IsLeader describes whether the cluster member held the leadership. The
+	// leader is defragmented last.
+	IsLeader bool
+	// DBSizeBefore is the size of the member's database before its
+	// defragmentation, in bytes.
+	DBSizeBefore int64
+	// DBSizeAfter is the size of the member's database after its
+	// defragmentation, in bytes.
+	DBSizeAfter int64
+	// NoSpaceAlarm describes whether the cluster member had raised a NOSPACE
+	// alarm after its defragmentation.
+	NoSpaceAlarm bool
+	// NoSpaceAlarmDisarmed describes whether the NOSPACE alarm of the cluster
+	// member was disarmed, its database being below the quota.
+	NoSpaceAlarmDisarmed bool
+	// Err holds the string representation of any errors encountered while
+	// defragmenting the member, or the reason it was skipped.
+	Err string
+}
@@ -39,6 +39,8 @@ var typeMap = map[string]interface{}{
 	"deregistration":         &Deregistration{},
 	"Entity":                 &Entity{},
 	"entity":                 &Entity{},
+	"EnrollmentToken":        &EnrollmentToken{},
+	"enrollment_token":       &EnrollmentToken{},
 	"Event":                  &Event{},
 	"event":                  &Event{},
 	"EventFilter":            &EventFilter{},
@@ -69,6 +71,8 @@ var typeMap = map[string]interface{}{
 	"mutator":                &Mutator{},
 	"Namespace":              &Namespace{},
 	"namespace":              &Namespace{},
+	"NotificationWebhook":    &NotificationWebhook{},
+	"notification_webhook":   &NotificationWebhook{},
 	"Network":                &Network{},
 	"network":                &Network{},
 	"NetworkInterface":       &NetworkInterface{},
@@ -77,6 +81,8 @@ var typeMap = map[string]interface{}{
 	"object_meta":            &ObjectMeta{},
 	"ProxyRequests":          &ProxyRequests{},
 	"proxy_requests":         &ProxyRequests{},
+	"Remediation":            &Remediation{},
+	"remediation":            &Remediation{},
 	"Role":                   &Role{},
 	"role":                   &Role{},
 	"RoleBinding":            &RoleBinding{},
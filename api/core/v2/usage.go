@@ -0,0 +1,32 @@
+package v2
+
+const (
+	// UsageResource is the name of the usage resource type
+	UsageResource = "usage"
+)
+
+// UserUsage is the usage of the API and the event submissions of a user over
+// rolling windows, as tracked by a backend.
+type UserUsage struct {
+	// Username is the name of the user.
+	Username string `json:"username"`
+
+	// Requests counts the requests of the user to the API.
+	Requests UsageCounts `json:"requests"`
+
+	// Events counts the events submitted by the user, either to the API or
+	// by the agents authenticated as the user.
+	Events UsageCounts `json:"events"`
+}
+
+// UsageCounts are counts over rolling windows.
+type UsageCounts struct {
+	// LastMinute is the count over the last minute.
+	LastMinute int64 `json:"last_minute"`
+
+	// Last15Minutes is the count over the last 15 minutes.
+	Last15Minutes int64 `json:"last_15_minutes"`
+
+	// LastHour is the count over the last hour.
+	LastHour int64 `json:"last_hour"`
+}
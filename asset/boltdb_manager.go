@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
 
 	bolt "go.etcd.io/bbolt"
 
@@ -55,6 +56,7 @@ type boltDBAssetManager struct {
 	fetcher      Fetcher
 	expander     Expander
 	verifier     Verifier
+	lastUse      lastUseTracker
 }
 
 // Get opens a transaction to BoltDB, causing subsequent calls to
@@ -96,6 +98,7 @@ func (b *boltDBAssetManager) Get(ctx context.Context, asset *types.Asset) (*Runt
 	// Check to see if the view was successful.
 	if localAsset != nil {
 		localAsset.SHA512 = asset.Sha512
+		b.lastUse.touch(localAsset.Path, time.Now())
 		return localAsset, nil
 	}
 
@@ -3,9 +3,7 @@ package asset
 import (
 	"context"
 
-	"github.com/sensu/sensu-go/js"
 	"github.com/sensu/sensu-go/types"
-	"github.com/sensu/sensu-go/types/dynamic"
 	"github.com/sirupsen/logrus"
 )
 
@@ -27,6 +25,7 @@ type filteredManager struct {
 }
 
 // Get fetches, verifies, and expands an asset, but only if it is filtered.
+// The assets with builds are resolved to the build matching the entity.
 func (f *filteredManager) Get(ctx context.Context, asset *types.Asset) (*RuntimeAsset, error) {
 	fields := logrus.Fields{
 		"entity":  f.entity.Name,
@@ -44,26 +43,23 @@ func (f *filteredManager) Get(ctx context.Context, asset *types.Asset) (*Runtime
 		return nil, nil
 	}
 
+	build, err := asset.SelectBuild(f.entity)
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("error selecting the asset build")
+		return nil, err
+	}
+
+	if build == nil {
+		logger.WithFields(fields).Debug("no asset build matching the entity, not installing asset")
+		return nil, nil
+	}
+
 	logger.WithFields(fields).Debug("entity filtered, installing asset")
-	return f.getter.Get(ctx, asset)
+	return f.getter.Get(ctx, build)
 }
 
 // isFiltered evaluates the given asset's filters and returns true if all of
 // them match the current entity.
 func (f *filteredManager) isFiltered(asset *types.Asset) (bool, error) {
-	if len(asset.Filters) == 0 {
-		return true, nil
-	}
-
-	synth := dynamic.Synthesize(f.entity)
-	params := map[string]interface{}{"entity": synth}
-
-	for _, filter := range asset.Filters {
-		result, err := js.Evaluate(filter, params, nil)
-		if err != nil || !result {
-			return false, err
-		}
-	}
-
-	return true, nil
+	return types.MatchAssetFilters(asset.Filters, f.entity)
 }
@@ -12,13 +12,15 @@ import (
 
 type MockGetter struct {
 	getCalled bool
+	got       *types.Asset
 	asset     *RuntimeAsset
 	err       error
 }
 
 // Get satisfies the asset.Getter interface
-func (m *MockGetter) Get(context.Context, *types.Asset) (*RuntimeAsset, error) {
+func (m *MockGetter) Get(_ context.Context, asset *types.Asset) (*RuntimeAsset, error) {
 	m.getCalled = true
+	m.got = asset
 	return m.asset, m.err
 }
 
@@ -64,6 +66,33 @@ func TestFilteredManagerUnfilteredAsset(t *testing.T) {
 	assert.False(t, mockGetter.getCalled)
 }
 
+// FilteredManager should call underlying Getter with the matching build.
+func TestFilteredManagerAssetBuilds(t *testing.T) {
+	mockGetter, entity, filteredManager := NewTestFilteredManager()
+	entity.System.LibCType = "glibc"
+
+	fixtureAsset := types.FixtureAsset("test-asset")
+	fixtureAsset.Builds = []*types.AssetBuild{
+		{URL: "https://example.com/musl.tar.gz", Sha512: "musl", Filters: []string{"entity.system.libc_type == 'musl'"}},
+		{URL: "https://example.com/glibc.tar.gz", Sha512: "glibc", Filters: []string{"entity.system.libc_type == 'glibc'"}},
+	}
+	actualAsset, err := filteredManager.Get(context.TODO(), fixtureAsset)
+	assert.NoError(t, err)
+	assert.Equal(t, mockGetter.asset, actualAsset)
+	if assert.NotNil(t, mockGetter.got) {
+		assert.Equal(t, "https://example.com/glibc.tar.gz", mockGetter.got.URL)
+		assert.Equal(t, "glibc", mockGetter.got.Sha512)
+	}
+
+	// No build matches
+	mockGetter.getCalled = false
+	entity.System.LibCType = ""
+	actualAsset, err = filteredManager.Get(context.TODO(), fixtureAsset)
+	assert.NoError(t, err)
+	assert.Nil(t, actualAsset)
+	assert.False(t, mockGetter.getCalled)
+}
+
 // FilteredManager should return error passed by underlying Getter.
 func TestFilteredManagerError(t *testing.T) {
 	mockGetter, _, filteredManager := NewTestFilteredManager()
@@ -0,0 +1,242 @@
+package asset
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// DefaultCacheGCInterval is the default time between two garbage
+	// collections of the asset cache.
+	DefaultCacheGCInterval = 10 * time.Minute
+
+	// cacheGCGracePeriod is the time after its last use during which an asset
+	// is never evicted, so the checks and hooks using it can complete.
+	cacheGCGracePeriod = 10 * time.Minute
+
+	// touchInterval is the minimum time between two updates of the last use
+	// time of an asset.
+	touchInterval = time.Minute
+)
+
+var (
+	cacheSize = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "sensu_go_asset_cache_size_bytes",
+			Help: "Size of the installed assets in the asset cache",
+		},
+	)
+
+	cacheEvictions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_asset_cache_evictions_total",
+			Help: "Number of assets evicted from the asset cache, by reason (age or size)",
+		},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	_ = prometheus.Register(cacheSize)
+	_ = prometheus.Register(cacheEvictions)
+}
+
+// CacheGCConfig configures the garbage collection of the asset cache. The
+// assets not used for the retention are evicted, then the least recently used
+// ones until the cache fits its maximum size. The garbage collection is
+// disabled if both are 0.
+type CacheGCConfig struct {
+	// MaxSize is the maximum size of the installed assets, in bytes.
+	MaxSize int64
+
+	// Retention is the time after its last use after which an asset is
+	// evicted.
+	Retention time.Duration
+
+	// Interval is the time between two garbage collections. Defaults to
+	// DefaultCacheGCInterval.
+	Interval time.Duration
+}
+
+// Enabled returns true if the asset cache is garbage collected.
+func (c CacheGCConfig) Enabled() bool {
+	return c.MaxSize > 0 || c.Retention > 0
+}
+
+// cachedAsset is an installed asset, as seen by the garbage collector.
+type cachedAsset struct {
+	key      []byte
+	path     string
+	lastUsed time.Time
+	size     int64
+}
+
+// lastUseTracker records the last use of the installed assets in the
+// modification time of their directory, so it survives restarts.
+type lastUseTracker struct {
+	mu      sync.Mutex
+	touched map[string]time.Time
+}
+
+// touch records the use of the asset installed at path, at most once per
+// touchInterval.
+func (t *lastUseTracker) touch(path string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.touched == nil {
+		t.touched = map[string]time.Time{}
+	}
+	if now.Sub(t.touched[path]) < touchInterval {
+		return
+	}
+	t.touched[path] = now
+	if err := os.Chtimes(path, now, now); err != nil {
+		logger.WithError(err).WithField("path", path).Debug("could not record the use of the asset")
+	}
+}
+
+// forget drops the last use of an evicted asset.
+func (t *lastUseTracker) forget(path string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.touched, path)
+}
+
+// cachedAssets returns the installed assets, with their size and last use.
+func (b *boltDBAssetManager) cachedAssets() ([]cachedAsset, error) {
+	var assets []cachedAsset
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(assetBucketName)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(key, value []byte) error {
+			var localAsset RuntimeAsset
+			if err := json.Unmarshal(value, &localAsset); err != nil {
+				return nil
+			}
+			asset := cachedAsset{
+				key:  append([]byte{}, key...),
+				path: localAsset.Path,
+			}
+			if info, err := os.Stat(localAsset.Path); err == nil {
+				asset.lastUsed = info.ModTime()
+			}
+			asset.size = dirSize(localAsset.Path)
+			assets = append(assets, asset)
+			return nil
+		})
+	})
+	return assets, err
+}
+
+// dirSize returns the size of the files of a directory.
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// evict removes an installed asset from the cache. It is installed again the
+// next time it is requested.
+func (b *boltDBAssetManager) evict(asset cachedAsset) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(assetBucketName)
+		if bucket == nil {
+			return nil
+		}
+		if err := bucket.Delete(asset.key); err != nil {
+			return err
+		}
+		// The directory is removed in the transaction, so the asset is not
+		// requested while it is removed
+		return os.RemoveAll(asset.path)
+	})
+	b.lastUse.forget(asset.path)
+	return err
+}
+
+// collectGarbage evicts the assets not used for the retention, then the least
+// recently used ones until the cache fits its maximum size. The assets used
+// in the grace period are never evicted.
+func (b *boltDBAssetManager) collectGarbage(cfg CacheGCConfig, now time.Time) error {
+	assets, err := b.cachedAssets()
+	if err != nil {
+		return err
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].lastUsed.Before(assets[j].lastUsed)
+	})
+
+	var total int64
+	for _, asset := range assets {
+		total += asset.size
+	}
+
+	for _, asset := range assets {
+		if now.Sub(asset.lastUsed) < cacheGCGracePeriod {
+			break
+		}
+		reason := ""
+		if cfg.Retention > 0 && now.Sub(asset.lastUsed) > cfg.Retention {
+			reason = "age"
+		} else if cfg.MaxSize > 0 && total > cfg.MaxSize {
+			reason = "size"
+		} else {
+			continue
+		}
+		if err := b.evict(asset); err != nil {
+			logger.WithError(err).WithField("path", asset.path).Error("could not evict the asset from the cache")
+			continue
+		}
+		total -= asset.size
+		cacheEvictions.WithLabelValues(reason).Inc()
+		logger.WithFields(logrus.Fields{
+			"path":      asset.path,
+			"last_used": asset.lastUsed,
+			"reason":    reason,
+		}).Info("evicted the asset from the cache")
+	}
+
+	cacheSize.Set(float64(total))
+	if cfg.MaxSize > 0 && total > cfg.MaxSize {
+		logger.WithField("size", total).Warn("the asset cache exceeds its maximum size, its assets are in use")
+	}
+	return nil
+}
+
+// collectGarbagePeriodically collects the garbage of the asset cache at every
+// interval, until ctx is done.
+func (b *boltDBAssetManager) collectGarbagePeriodically(ctx context.Context, cfg CacheGCConfig) {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = DefaultCacheGCInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.collectGarbage(cfg, time.Now()); err != nil {
+			logger.WithError(err).Error("could not collect the garbage of the asset cache")
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package asset
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// installTestAsset installs an asset of the given size in the cache, last used
+// at the given time.
+func installTestAsset(t *testing.T, b *boltDBAssetManager, sha string, size int, lastUsed time.Time) string {
+	t.Helper()
+	path := filepath.Join(b.localStorage, sha)
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "bin"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "bin", "check"), make([]byte, size), 0755))
+	require.NoError(t, os.Chtimes(path, lastUsed, lastUsed))
+	value, err := json.Marshal(RuntimeAsset{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(assetBucketName)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(sha), value)
+	}))
+	return path
+}
+
+func TestCollectGarbage(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "asset_gc_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+	db, err := bolt.Open(filepath.Join(cacheDir, dbName), 0600, &bolt.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+	b := NewBoltDBGetter(db, cacheDir, nil, nil, nil).(*boltDBAssetManager)
+
+	now := time.Now()
+	old := installTestAsset(t, b, "old", 100, now.Add(-48*time.Hour))
+	lru := installTestAsset(t, b, "lru", 100, now.Add(-2*time.Hour))
+	recent := installTestAsset(t, b, "recent", 100, now.Add(-time.Hour))
+	inUse := installTestAsset(t, b, "in-use", 100, now)
+
+	// The old asset is evicted for its age, then the least recently used one
+	// for the size. The asset in use is kept even if the cache is too big.
+	require.NoError(t, b.collectGarbage(CacheGCConfig{MaxSize: 250, Retention: 24 * time.Hour}, now))
+	for path, kept := range map[string]bool{old: false, lru: false, recent: true, inUse: true} {
+		_, err := os.Stat(path)
+		assert.Equal(t, kept, err == nil, path)
+	}
+	assets, err := b.cachedAssets()
+	require.NoError(t, err)
+	assert.Len(t, assets, 2)
+
+	require.NoError(t, b.collectGarbage(CacheGCConfig{MaxSize: 50}, now))
+	assets, err = b.cachedAssets()
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Equal(t, inUse, assets[0].path)
+}
+
+func TestLastUseTracker(t *testing.T) {
+	path, err := ioutil.TempDir("", "asset_gc_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	var tracker lastUseTracker
+	now := time.Now().Add(time.Hour).Truncate(time.Second)
+	tracker.touch(path, now)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(now))
+
+	// The last use is only recorded once per interval
+	tracker.touch(path, now.Add(time.Second))
+	info, err = os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(now))
+}
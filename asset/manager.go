@@ -16,6 +16,10 @@ const (
 
 // Manager ...
 type Manager struct {
+	// GC configures the garbage collection of the asset cache. It is
+	// disabled by default.
+	GC CacheGCConfig
+
 	cacheDir string
 	entity   *types.Entity
 	stopping chan struct{}
@@ -56,5 +60,13 @@ func (m *Manager) StartAssetManager(ctx context.Context) (Getter, error) {
 	boltDBGetter := NewBoltDBGetter(
 		db, m.cacheDir, nil, nil, nil)
 
+	if m.GC.Enabled() {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			boltDBGetter.(*boltDBAssetManager).collectGarbagePeriodically(ctx, m.GC)
+		}()
+	}
+
 	return NewFilteredManager(boltDBGetter, m.entity), nil
 }
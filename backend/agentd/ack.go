@@ -0,0 +1,54 @@
+package agentd
+
+import (
+	"context"
+
+	"github.com/sensu/sensu-go/transport"
+)
+
+// rejectedEventError wraps the errors of the events which can never be
+// published, e.g. because they are invalid, whatever the number of attempts.
+type rejectedEventError struct {
+	err error
+}
+
+func (e rejectedEventError) Error() string {
+	return e.err.Error()
+}
+
+// handleAckedEvent is the acked event message handler. The event is handled
+// like the events of the event message handler, and acknowledged once it is
+// published to the message bus, or with the error that prevented it.
+func (s *Session) handleAckedEvent(ctx context.Context, payload []byte) error {
+	id, event, err := transport.SplitAckedEventPayload(payload)
+	if err != nil {
+		return err
+	}
+	if err := s.receiveEvent(ctx, event, id); err != nil {
+		s.sendEventAck(id, err)
+		return err
+	}
+	return nil
+}
+
+// sendEventAck acknowledges the event of the given ID, if not empty, with the
+// error that prevented its publication, if any. The events rejected with a
+// rejectedEventError are acknowledged as rejected, or without error if the
+// agent does not support it, so the agent does not send them again.
+func (s *Session) sendEventAck(id string, err error) {
+	if id == "" {
+		return
+	}
+	ack := &transport.EventAck{ID: id}
+	if _, ok := err.(rejectedEventError); ok && !s.supports(transport.FeatureEventRejection) {
+		err = nil
+	}
+	if err != nil {
+		ack.Error = err.Error()
+		_, ack.Rejected = err.(rejectedEventError)
+	}
+	select {
+	case s.checkChannel <- ack:
+	case <-s.stopping:
+	}
+}
@@ -0,0 +1,79 @@
+package agentd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// receiveEventAck returns the event ack sent by the session.
+func receiveEventAck(t *testing.T, s *Session) *transport.EventAck {
+	t.Helper()
+	select {
+	case msg := <-s.checkChannel:
+		ack, ok := msg.(*transport.EventAck)
+		require.True(t, ok)
+		return ack
+	case <-time.After(time.Second):
+		t.Fatal("the event was not acknowledged")
+	}
+	return nil
+}
+
+func TestPublishEventAck(t *testing.T) {
+	s, bus := newPublishSession(PublishBufferConfig{Size: 1, MaxBackoff: time.Millisecond})
+	s.checkChannel = make(chan interface{}, 1)
+	bus.On("Publish", messaging.TopicEventRaw, mock.Anything).Return(nil).Once()
+	bus.On("Publish", messaging.TopicEventRaw, mock.Anything).Return(errors.New("bus no longer running")).Once()
+	bus.On("Publish", messaging.TopicEventRaw, mock.Anything).Return(nil)
+
+	require.NoError(t, s.publish(transport.MessageTypeEvent, messaging.TopicEventRaw, corev2.FixtureEvent("agent1", "check"), "1"))
+	assert.Equal(t, &transport.EventAck{ID: "1"}, receiveEventAck(t, s))
+
+	// A buffered event is acknowledged once published
+	require.NoError(t, s.publish(transport.MessageTypeEvent, messaging.TopicEventRaw, corev2.FixtureEvent("agent1", "check"), "2"))
+	assert.Empty(t, s.checkChannel)
+	require.True(t, s.retryPublish(<-s.publishq))
+	assert.Equal(t, &transport.EventAck{ID: "2"}, receiveEventAck(t, s))
+
+	// The events are not acknowledged without ID
+	require.NoError(t, s.publish(transport.MessageTypeEvent, messaging.TopicEventRaw, corev2.FixtureEvent("agent1", "check"), ""))
+	assert.Empty(t, s.checkChannel)
+}
+
+func TestHandleAckedEventError(t *testing.T) {
+	s, _ := newPublishSession(PublishBufferConfig{})
+	s.checkChannel = make(chan interface{}, 1)
+	s.unmarshal = UnmarshalJSON
+
+	// The invalid events are rejected, so the agent does not send them again
+	s.cfg.ProtocolVersion = transport.ProtocolVersion
+	err := s.handleAckedEvent(context.Background(), transport.NewAckedEventPayload("1", []byte("{")))
+	assert.Error(t, err)
+	ack := receiveEventAck(t, s)
+	assert.Equal(t, "1", ack.ID)
+	assert.NotEmpty(t, ack.Error)
+	assert.True(t, ack.Rejected)
+
+	// The agents unaware of the rejections get an ack without error instead
+	s.cfg.ProtocolVersion = 4
+	assert.Error(t, s.handleAckedEvent(context.Background(), transport.NewAckedEventPayload("2", []byte("{}"))))
+	assert.Equal(t, &transport.EventAck{ID: "2"}, receiveEventAck(t, s))
+
+	// The events that could not be published are acknowledged with the
+	// error, to be sent again
+	s.cfg.ProtocolVersion = transport.ProtocolVersion
+	s.sendEventAck("3", errors.New("could not query the store for a proxy entity"))
+	assert.Equal(t, &transport.EventAck{ID: "3", Error: "could not query the store for a proxy entity"}, receiveEventAck(t, s))
+
+	assert.Error(t, s.handleAckedEvent(context.Background(), []byte("{}")))
+	assert.Empty(t, s.checkChannel)
+}
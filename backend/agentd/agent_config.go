@@ -0,0 +1,75 @@
+package agentd
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+)
+
+// watchAgentConfigs pushes the configurations of the agents to the sessions of
+// this backend whenever they change, until agentd stops. The agents connected
+// to the other backends of the cluster are reached by their own watchers.
+func (a *Agentd) watchAgentConfigs() {
+	defer a.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watchChan := a.store.GetAgentConfigWatcher(ctx)
+	for {
+		select {
+		case event, ok := <-watchChan:
+			if !ok {
+				// The watchChan has closed. Restart the watcher.
+				watchChan = a.store.GetAgentConfigWatcher(ctx)
+				continue
+			}
+			a.pushAgentConfig(event)
+		case <-a.stopping:
+			return
+		}
+	}
+}
+
+// pushAgentConfig publishes the configuration of an agent on its entity
+// subscription, which only its session subscribes to.
+func (a *Agentd) pushAgentConfig(event store.WatchEventAgentConfig) {
+	fields := logrus.Fields{
+		"namespace": event.Namespace,
+		"agent":     event.Entity,
+	}
+	topic := messaging.SubscriptionTopic(event.Namespace, corev2.GetEntitySubscription(event.Entity))
+	if err := a.bus.Publish(topic, event.AgentConfig); err != nil {
+		logger.WithFields(fields).WithError(err).Error("unable to push the agent config")
+		return
+	}
+	logger.WithFields(fields).Debug("agent config pushed")
+}
+
+// pushAgentConfig sends the configuration of the agent stored by the backend,
+// if any, so the agent applies it as soon as it connects.
+func (s *Session) pushAgentConfig() {
+	if !s.supports(transport.FeatureAgentConfig) {
+		return
+	}
+	ctx := context.WithValue(s.ctx, corev2.NamespaceKey, s.cfg.Namespace)
+	config, err := s.store.GetAgentConfig(ctx, s.cfg.AgentName)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"namespace": s.cfg.Namespace,
+			"agent":     s.cfg.AgentName,
+		}).WithError(err).Error("unable to get the agent config")
+		return
+	}
+	if config == nil {
+		return
+	}
+	select {
+	case s.checkChannel <- config:
+	case <-s.stopping:
+	}
+}
@@ -0,0 +1,113 @@
+package agentd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionPushAgentConfig(t *testing.T) {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+
+	config := &corev2.AgentConfig{LogLevel: "debug"}
+	st := &mockstore.MockStore{}
+	st.On("GetNamespace", mock.Anything, "acme").Return(&corev2.Namespace{}, nil)
+	st.On("GetAgentConfig", mock.Anything, "testing").Return(config, nil)
+
+	conn := &testTransport{sendCh: make(chan *transport.Message, 10)}
+	cfg := SessionConfig{
+		AgentName: "testing",
+		Namespace: "acme",
+	}
+	session, err := NewSession(cfg, conn, bus, st, UnmarshalJSON, MarshalJSON)
+	require.NoError(t, err)
+
+	session.wg.Add(1)
+	go session.subPump()
+	defer close(session.stopping)
+
+	session.pushAgentConfig()
+
+	select {
+	case msg := <-session.prioq:
+		assert.Equal(t, corev2.AgentConfigType, msg.Type)
+		var got corev2.AgentConfig
+		require.NoError(t, json.Unmarshal(msg.Payload, &got))
+		assert.Equal(t, config, &got)
+	case <-time.After(time.Second):
+		t.Fatal("the agent config was not queued")
+	}
+}
+
+func TestAgentdPushAgentConfig(t *testing.T) {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+
+	subscriber := messaging.ChannelSubscriber{Channel: make(chan interface{}, 1)}
+	topic := messaging.SubscriptionTopic("acme", corev2.GetEntitySubscription("testing"))
+	subscription, err := bus.Subscribe(topic, "testing", subscriber)
+	require.NoError(t, err)
+	defer func() { _ = subscription.Cancel() }()
+
+	a := &Agentd{bus: bus}
+	config := &corev2.AgentConfig{KeepaliveInterval: 10}
+	a.pushAgentConfig(store.WatchEventAgentConfig{
+		Namespace:   "acme",
+		Entity:      "testing",
+		AgentConfig: config,
+		Action:      store.WatchUpdate,
+	})
+
+	select {
+	case msg := <-subscriber.Channel:
+		assert.Equal(t, config, msg)
+	case <-time.After(time.Second):
+		t.Fatal("the agent config was not published")
+	}
+}
+
+func TestSessionPushAgentConfigUnsupported(t *testing.T) {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+
+	st := &mockstore.MockStore{}
+	st.On("GetNamespace", mock.Anything, "acme").Return(&corev2.Namespace{}, nil)
+
+	conn := &testTransport{sendCh: make(chan *transport.Message, 10)}
+	cfg := SessionConfig{
+		AgentName:       "testing",
+		Namespace:       "acme",
+		ProtocolVersion: 1,
+	}
+	session, err := NewSession(cfg, conn, bus, st, UnmarshalJSON, MarshalJSON)
+	require.NoError(t, err)
+
+	session.wg.Add(1)
+	go session.subPump()
+	defer close(session.stopping)
+
+	// The agent config is neither fetched nor sent to the agents that do not
+	// support it
+	session.pushAgentConfig()
+	session.checkChannel <- &corev2.AgentConfig{LogLevel: "debug"}
+
+	select {
+	case msg := <-session.prioq:
+		t.Fatalf("unexpected %s message", msg.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+	st.AssertNotCalled(t, "GetAgentConfig", mock.Anything, mock.Anything)
+}
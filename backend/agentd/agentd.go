@@ -2,8 +2,12 @@ package agentd
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -17,13 +21,24 @@ import (
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/usage"
 	"github.com/sensu/sensu-go/transport"
+	"google.golang.org/grpc"
 )
 
-var (
-	// upgrader is safe for concurrent use, and we don't need any particularly
-	// specialized configurations for different uses.
-	upgrader = &websocket.Upgrader{}
+const (
+	// DefaultPingInterval is the default time between the pings sent to the
+	// agents connected over WebSocket.
+	DefaultPingInterval = 30 * time.Second
+
+	// DefaultPongTimeout is the default time without pong after which the
+	// WebSocket connection of an agent is closed.
+	DefaultPongTimeout = 45 * time.Second
+
+	// DefaultMaxMissedPongs is the default number of consecutive pings
+	// without pong after which the WebSocket connection of an agent is
+	// closed.
+	DefaultMaxMissedPongs = 2
 )
 
 // Agentd is the backend HTTP API.
@@ -34,25 +49,121 @@ type Agentd struct {
 	// Port is the port Agentd is running on.
 	Port int
 
+	// GRPCPort is the port Agentd accepts gRPC agent sessions on, if not 0.
+	GRPCPort int
+
 	stopping   chan struct{}
 	running    *atomic.Value
 	wg         *sync.WaitGroup
 	errChan    chan error
 	httpServer *http.Server
+	grpcServer *grpc.Server
 	store      store.Store
 	bus        messaging.MessageBus
 	tls        *corev2.TLSOptions
 	ringPool   *ringv2.Pool
+
+	// upgrader is safe for concurrent use, it is shared by all the sessions.
+	upgrader    *websocket.Upgrader
+	compression transport.Compression
+	sendQueue   SendQueueConfig
+
+	sessions    *sessionSet
+	quota       *sessionQuota
+	drainWindow time.Duration
+	draining    int32
+
+	agentMetrics bool
+	clockSkew    ClockSkewConfig
+	messageSize  MessageSizeConfig
+	publishBuf   PublishBufferConfig
+	ping         transport.PingConfig
+	usage        *usage.Tracker
+
+	duplicatePolicy   string
+	duplicateWarnings *duplicateWarnings
+
+	resumeTokens *resumeTokens
+
+	rejectSpoofedEvents bool
+
+	// enrollment issues TLS client certificates to the agents, if enabled.
+	enrollment *enrollmentCA
 }
 
 // Config configures an Agentd.
 type Config struct {
 	Host     string
 	Port     int
+	GRPCPort int
 	Bus      messaging.MessageBus
 	Store    store.Store
 	TLS      *corev2.TLSOptions
 	RingPool *ringv2.Pool
+
+	// Compression configures the compression of the messages exchanged with
+	// the agents supporting it.
+	Compression transport.Compression
+
+	// SendQueue configures the queue of the messages sent to each agent.
+	SendQueue SendQueueConfig
+
+	// ClientCertAuth authenticates the agents with their TLS client
+	// certificate, verified with the trusted CA of TLS, instead of their
+	// username and password.
+	ClientCertAuth bool
+
+	// DrainWindow is the time over which the agents are asked to reconnect to
+	// another backend when Agentd stops. Sessions are not drained if 0.
+	DrainWindow time.Duration
+
+	// SessionLimits limits the number of concurrent agent sessions.
+	SessionLimits SessionLimits
+
+	// AgentMetrics labels the throughput metrics of the sessions with the
+	// names of the agents, in addition to their namespaces. It multiplies
+	// the number of time series by the number of agents.
+	AgentMetrics bool
+
+	// ClockSkew configures the detection of the agents whose clock is skewed.
+	ClockSkew ClockSkewConfig
+
+	// MessageSize configures the maximum size of the messages of the agents.
+	MessageSize MessageSizeConfig
+
+	// PublishBuffer configures the buffer of the messages of each agent that
+	// the message bus failed to publish.
+	PublishBuffer PublishBufferConfig
+
+	// Ping configures the pings sent to the agents connected over WebSocket,
+	// which keep the idle connections open through the intermediate proxies
+	// and detect the dead ones. Pings are disabled if its interval is 0.
+	Ping transport.PingConfig
+
+	// Usage records the events submitted by the agents, by user.
+	Usage *usage.Tracker
+
+	// DuplicateAgentPolicy is the policy applied to the sessions of the
+	// agents connecting with the name of an agent of the same namespace
+	// already connected to this backend: DuplicateAgentPolicyWarn,
+	// DuplicateAgentPolicyReject or DuplicateAgentPolicyEvict. Defaults to
+	// DefaultDuplicateAgentPolicy.
+	DuplicateAgentPolicy string
+
+	// ResumeTokenTTL is the time after their session stops during which the
+	// agents can resume it with their resume token, keeping their ring
+	// membership. They are still authenticated and authorized. Sessions can
+	// not be resumed if 0.
+	ResumeTokenTTL time.Duration
+
+	// RejectSpoofedEvents rejects the events submitted by the agents for
+	// entities other than themselves, unless their check declares the entity
+	// as a proxy entity and it is not another agent.
+	RejectSpoofedEvents bool
+
+	// Enrollment configures the issuance of TLS client certificates to the
+	// agents, on EnrollmentPath.
+	Enrollment EnrollmentConfig
 }
 
 // Option is a functional option.
@@ -63,6 +174,7 @@ func New(c Config, opts ...Option) (*Agentd, error) {
 	a := &Agentd{
 		Host:     c.Host,
 		Port:     c.Port,
+		GRPCPort: c.GRPCPort,
 		bus:      c.Bus,
 		store:    c.Store,
 		tls:      c.TLS,
@@ -71,6 +183,72 @@ func New(c Config, opts ...Option) (*Agentd, error) {
 		wg:       &sync.WaitGroup{},
 		errChan:  make(chan error, 1),
 		ringPool: c.RingPool,
+
+		upgrader:    c.Compression.Upgrader(),
+		compression: c.Compression,
+		sendQueue:   c.SendQueue.withDefaults(),
+		sessions:    newSessionSet(),
+		quota:       newSessionQuota(c.SessionLimits),
+		drainWindow: c.DrainWindow,
+
+		agentMetrics: c.AgentMetrics,
+		clockSkew:    c.ClockSkew,
+		messageSize:  c.MessageSize.withDefaults(),
+		publishBuf:   c.PublishBuffer,
+		ping:         c.Ping,
+		usage:        c.Usage,
+
+		duplicatePolicy:   c.DuplicateAgentPolicy,
+		duplicateWarnings: newDuplicateWarnings(),
+
+		rejectSpoofedEvents: c.RejectSpoofedEvents,
+	}
+	if a.duplicatePolicy == "" {
+		a.duplicatePolicy = DefaultDuplicateAgentPolicy
+	}
+
+	if err := c.Compression.Validate(); err != nil {
+		return nil, err
+	}
+	if err := a.sendQueue.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.SessionLimits.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.ClockSkew.Validate(); err != nil {
+		return nil, err
+	}
+	if err := a.messageSize.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.PublishBuffer.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.Ping.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ValidateDuplicateAgentPolicy(c.DuplicateAgentPolicy); err != nil {
+		return nil, err
+	}
+	if a.drainWindow < 0 {
+		return nil, fmt.Errorf("invalid drain window %s, must not be negative", a.drainWindow)
+	}
+	if c.ResumeTokenTTL < 0 {
+		return nil, fmt.Errorf("invalid resume token ttl %s, must not be negative", c.ResumeTokenTTL)
+	}
+	if c.ResumeTokenTTL > 0 {
+		a.resumeTokens = newResumeTokens(c.ResumeTokenTTL)
+	}
+	if c.Enrollment.Enabled() {
+		if c.TLS == nil {
+			return nil, errors.New("agent enrollment requires TLS")
+		}
+		ca, err := newEnrollmentCA(c.Enrollment)
+		if err != nil {
+			return nil, err
+		}
+		a.enrollment = ca
 	}
 
 	// prepare server TLS config
@@ -79,7 +257,27 @@ func New(c Config, opts ...Option) (*Agentd, error) {
 		return nil, err
 	}
 
-	handler := middlewares.BasicAuthentication(middlewares.BasicAuthorization(http.HandlerFunc(a.webSocketHandler), a.store), a.store)
+	// The agents resuming their session are authenticated and authorized
+	// again
+	handler := a.resumeHandler(http.HandlerFunc(a.webSocketHandler))
+	if c.ClientCertAuth {
+		if err := configureClientCertAuth(tlsServerConfig, c.TLS); err != nil {
+			return nil, err
+		}
+		handler = middlewares.CertificateAuthentication(middlewares.BasicAuthorization(handler, a.store))
+	} else {
+		handler = middlewares.BasicAuthentication(middlewares.BasicAuthorization(handler, a.store), a.store)
+	}
+	if a.enrollment != nil {
+		// The agents enroll without a certificate, and renew it with the
+		// one they present, verified by the enrollment CA
+		if tlsServerConfig.ClientAuth == tls.RequireAndVerifyClientCert {
+			tlsServerConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		} else {
+			tlsServerConfig.ClientAuth = tls.RequestClientCert
+		}
+		handler = a.enrollmentHandler(handler)
+	}
 	a.httpServer = &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", a.Host, a.Port),
 		Handler:      handler,
@@ -87,6 +285,9 @@ func New(c Config, opts ...Option) (*Agentd, error) {
 		ReadTimeout:  15 * time.Second,
 		TLSConfig:    tlsServerConfig,
 	}
+	if a.GRPCPort != 0 {
+		a.grpcServer = transport.NewGRPCServer(handler, tlsServerConfig)
+	}
 	for _, o := range opts {
 		if err := o(a); err != nil {
 			return nil, err
@@ -114,13 +315,55 @@ func (a *Agentd) Start() error {
 		}
 	}()
 
+	if a.grpcServer != nil {
+		addr := fmt.Sprintf("%s:%d", a.Host, a.GRPCPort)
+		logger.Info("starting agentd grpc server on address: ", addr)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			if err := a.grpcServer.Serve(listener); err != nil {
+				logger.WithError(err).Error("failed to start grpc server")
+			}
+		}()
+	}
+
+	a.wg.Add(1)
+	go a.watchAgentConfigs()
+
 	_ = prometheus.Register(sessionCounter)
+	_ = prometheus.Register(protocolVersionSessions)
+	_ = prometheus.Register(sessionRejections)
+	_ = prometheus.Register(checkExecutionsDelayed)
+	_ = prometheus.Register(checkExecutionsSkipped)
+	_ = prometheus.Register(sendQueueDepth)
+	_ = prometheus.Register(sendQueueDrops)
+	_ = prometheus.Register(sendQueueMessages)
+	_ = prometheus.Register(messagesReceived)
+	_ = prometheus.Register(messagesSent)
+	_ = prometheus.Register(bytesReceived)
+	_ = prometheus.Register(bytesSent)
+	_ = prometheus.Register(handlerErrors)
+	_ = prometheus.Register(oversizedMessages)
+	_ = prometheus.Register(publishLatency)
+	_ = prometheus.Register(publishRetries)
+	_ = prometheus.Register(publishDrops)
+	_ = prometheus.Register(missedPongs)
+	_ = prometheus.Register(duplicateAgents)
+	_ = prometheus.Register(sessionResumptions)
+	_ = prometheus.Register(spoofedEvents)
+	_ = prometheus.Register(enrollments)
 
 	return nil
 }
 
-// Stop Agentd.
+// Stop Agentd. The running sessions are drained first if a drain window is
+// configured.
 func (a *Agentd) Stop() error {
+	atomic.StoreInt32(&a.draining, 1)
 	if err := a.httpServer.Shutdown(context.TODO()); err != nil {
 		// failure/timeout shutting down the server gracefully
 		logger.Error("failed to shutdown http server gracefully - forcing shutdown")
@@ -128,6 +371,10 @@ func (a *Agentd) Stop() error {
 			logger.Error("failed to shutdown http server forcefully")
 		}
 	}
+	a.drain()
+	if a.grpcServer != nil {
+		a.grpcServer.Stop()
+	}
 	a.running.Store(false)
 	close(a.stopping)
 	a.wg.Wait()
@@ -147,6 +394,39 @@ func (a *Agentd) Name() string {
 }
 
 func (a *Agentd) webSocketHandler(w http.ResponseWriter, r *http.Request) {
+	// Agents connecting while the sessions are drained must go to another
+	// backend
+	if atomic.LoadInt32(&a.draining) == 1 {
+		http.Error(w, "backend is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	// The agents too old to be supported are rejected before the upgrade, so
+	// they get the reason of the rejection
+	protocolVersion, err := transport.ParseProtocolVersion(r.Header)
+	if err == nil {
+		protocolVersion, err = transport.NegotiateProtocolVersion(protocolVersion)
+	}
+	if err != nil {
+		logger.WithField("addr", r.RemoteAddr).WithError(err).Warn("rejecting agent session")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The session limits and the duplicate agent policy are enforced before
+	// the upgrade so the agent gets the reason of the rejection
+	namespace := r.Header.Get(transport.HeaderKeyNamespace)
+	agentName := r.Header.Get(transport.HeaderKeyAgentName)
+	if a.rejectDuplicateAgent(namespace, agentName, r.RemoteAddr) {
+		http.Error(w, fmt.Sprintf("agent %q is already connected", agentName), http.StatusConflict)
+		return
+	}
+	if err := a.quota.acquire(namespace); err != nil {
+		logger.WithField("addr", r.RemoteAddr).WithField("namespace", namespace).WithError(err).Warn("rejecting agent session")
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
 	var marshal MarshalFunc
 	var unmarshal UnmarshalFunc
 	var contentType string
@@ -155,12 +435,20 @@ func (a *Agentd) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 	logger.WithField("header", fmt.Sprintf("Accept: %s", ProtobufSerializationHeader)).Debug("setting header")
 	responseHeader.Add("Accept", JSONSerializationHeader)
 	logger.WithField("header", fmt.Sprintf("Accept: %s", JSONSerializationHeader)).Debug("setting header")
-	if r.Header.Get("Accept") == ProtobufSerializationHeader {
+	responseHeader.Add("Accept", MessagePackSerializationHeader)
+	logger.WithField("header", fmt.Sprintf("Accept: %s", MessagePackSerializationHeader)).Debug("setting header")
+	switch r.Header.Get("Accept") {
+	case ProtobufSerializationHeader:
 		marshal = proto.Marshal
 		unmarshal = proto.Unmarshal
 		contentType = ProtobufSerializationHeader
 		logger.WithField("format", "protobuf").Debug("setting serialization/deserialization")
-	} else {
+	case MessagePackSerializationHeader:
+		marshal = MarshalMessagePack
+		unmarshal = UnmarshalMessagePack
+		contentType = MessagePackSerializationHeader
+		logger.WithField("format", "MessagePack").Debug("setting serialization/deserialization")
+	default:
 		marshal = MarshalJSON
 		unmarshal = UnmarshalJSON
 		contentType = JSONSerializationHeader
@@ -168,30 +456,70 @@ func (a *Agentd) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	responseHeader.Set("Content-Type", contentType)
 	logger.WithField("header", fmt.Sprintf("Content-Type: %s", contentType)).Debug("setting header")
+	responseHeader.Set(transport.HeaderKeyProtocolVersion, strconv.Itoa(protocolVersion))
+
+	resumed, _ := r.Context().Value(resumedSessionKey{}).(*resumption)
+	// The agents in heartbeat-only mode only subscribe to their entity
+	// subscription, which the session never sends checks over
+	heartbeatOnly := r.Header.Get(transport.HeaderKeyHeartbeatOnly) == "true"
+	subscriptions := addEntitySubscription(agentName, strings.Split(r.Header.Get(transport.HeaderKeySubscriptions), ","))
+	if heartbeatOnly {
+		subscriptions = addEntitySubscription(agentName, nil)
+	}
+	var resumeToken string
+	if a.resumeTokens != nil {
+		resumeToken = a.resumeTokens.issue(SessionConfig{
+			Namespace:     namespace,
+			AgentName:     agentName,
+			User:          r.Header.Get(transport.HeaderKeyUser),
+			Subscriptions: subscriptions,
+		})
+		if resumeToken != "" {
+			responseHeader.Set(transport.HeaderKeyResumeToken, resumeToken)
+		}
+	}
+	abort := func() {
+		a.quota.release(namespace)
+		if resumeToken != "" {
+			a.resumeTokens.revoke(resumeToken)
+		}
+	}
 
-	conn, err := upgrader.Upgrade(w, r, responseHeader)
+	t, err := a.upgrade(w, r, responseHeader)
 	if err != nil {
-		logger.WithField("addr", r.RemoteAddr).WithError(err).Error("transport error on websocket upgrade")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		abort()
 		return
 	}
 
 	cfg := SessionConfig{
-		AgentAddr:     r.RemoteAddr,
-		AgentName:     r.Header.Get(transport.HeaderKeyAgentName),
-		Namespace:     r.Header.Get(transport.HeaderKeyNamespace),
-		User:          r.Header.Get(transport.HeaderKeyUser),
-		Subscriptions: strings.Split(r.Header.Get(transport.HeaderKeySubscriptions), ","),
-		RingPool:      a.ringPool,
-		ContentType:   contentType,
-	}
+		AgentAddr:       r.RemoteAddr,
+		AgentName:       agentName,
+		Namespace:       namespace,
+		User:            r.Header.Get(transport.HeaderKeyUser),
+		Subscriptions:   subscriptions,
+		RingPool:        a.ringPool,
+		ContentType:     contentType,
+		ProtocolVersion: protocolVersion,
+		SendQueue:       a.sendQueue,
+		AgentMetrics:    a.agentMetrics,
+		ClockSkew:       a.clockSkew,
+		MessageSize:     a.messageSize,
+		PublishBuffer:   a.publishBuf,
+		Ping:            a.ping,
+		Usage:           a.usage,
+		ResumeToken:     resumeToken,
+		Resumed:         resumed != nil,
+		HeartbeatOnly:   heartbeatOnly,
 
-	cfg.Subscriptions = addEntitySubscription(cfg.AgentName, cfg.Subscriptions)
+		RejectSpoofedEvents: a.rejectSpoofedEvents,
+	}
 
-	session, err := NewSession(cfg, transport.NewTransport(conn), a.bus, a.store, unmarshal, marshal)
+	session, err := NewSession(cfg, t, a.bus, a.store, unmarshal, marshal)
 	if err != nil {
 		logger.WithError(err).Error("failed to create session")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
+		_ = t.Close()
+		abort()
 		return
 	}
 
@@ -199,6 +527,68 @@ func (a *Agentd) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		logger.WithError(err).Error("failed to start session")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
+		_ = t.Close()
+		abort()
+		if resumeToken != "" {
+			// The stopped session left the agent in the rings for its token
+			removeFromRings(context.Background(), a.ringPool, namespace, agentName, subscriptions)
+		}
 		return
 	}
+	if resumed != nil {
+		logger.WithField("namespace", namespace).WithField("agent", agentName).Info("agent resumed session")
+		a.removeStaleRings(resumed, cfg.Subscriptions)
+	}
+	a.sessions.track(session)
+	a.handleDuplicateAgent(session)
+	go func() {
+		<-session.stopping
+		a.quota.release(namespace)
+		a.duplicateAgentStopped(session)
+		a.releaseResumeToken(session)
+	}()
+}
+
+// configureClientCertAuth configures the server TLS config to require and
+// verify the client certificates of the agents with the trusted CA of tlsOpts.
+func configureClientCertAuth(cfg *tls.Config, tlsOpts *corev2.TLSOptions) error {
+	if tlsOpts == nil || tlsOpts.TrustedCAFile == "" {
+		return errors.New("client certificate authentication requires TLS and a trusted CA file")
+	}
+	caCertPool, err := corev2.LoadCACerts(tlsOpts.TrustedCAFile)
+	if err != nil {
+		return err
+	}
+	cfg.ClientCAs = caCertPool
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	return nil
+}
+
+// upgrade returns the transport of the agent session, opened either with the
+// WebSocket or the gRPC transport. Errors are logged and written to w.
+func (a *Agentd) upgrade(w http.ResponseWriter, r *http.Request, responseHeader http.Header) (transport.Transport, error) {
+	if transport.IsGRPC(r) {
+		t, err := transport.UpgradeGRPC(w, r, responseHeader)
+		if err != nil {
+			logger.WithField("addr", r.RemoteAddr).WithError(err).Error("transport error on grpc upgrade")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return nil, err
+		}
+		return t, nil
+	}
+
+	conn, err := a.upgrader.Upgrade(w, r, responseHeader)
+	if err != nil {
+		logger.WithField("addr", r.RemoteAddr).WithError(err).Error("transport error on websocket upgrade")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, err
+	}
+
+	if err := a.compression.Configure(conn); err != nil {
+		logger.WithField("addr", r.RemoteAddr).WithError(err).Error("failed to configure websocket compression")
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return transport.NewTransport(conn), nil
 }
@@ -1,8 +1,12 @@
 package agentd
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,3 +18,22 @@ func TestAddEntitySubscription(t *testing.T) {
 	expectedSubscriptions := []string{"subscription", "entity:entity1"}
 	assert.Equal(t, expectedSubscriptions, subscriptions)
 }
+
+func TestNewClientCertAuthRequiresTrustedCA(t *testing.T) {
+	_, err := New(Config{ClientCertAuth: true})
+	assert.Error(t, err)
+
+	_, err = New(Config{ClientCertAuth: true, TLS: &corev2.TLSOptions{}})
+	assert.Error(t, err)
+}
+
+func TestWebSocketHandlerRejectsProtocolVersion(t *testing.T) {
+	a := &Agentd{}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(transport.HeaderKeyProtocolVersion, "zero")
+	w := httptest.NewRecorder()
+
+	a.webSocketHandler(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid protocol version")
+}
@@ -0,0 +1,289 @@
+package agentd
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCheckExecutionTTL is the time an agent holds an execution slot
+	// of a check without timeout nor interval, unless it reports its result
+	// earlier. It is also the time a check request without interval waits
+	// for an execution slot.
+	DefaultCheckExecutionTTL = time.Minute
+
+	// checkExecutionStoreTimeout is the time allowed to acquire or release an
+	// execution slot.
+	checkExecutionStoreTimeout = 5 * time.Second
+
+	// checkExecutionLeaseTTL is the TTL of the lease of the execution slots
+	// of a session, after which they are released if the backend stopped.
+	checkExecutionLeaseTTL = time.Minute
+
+	// checkExecutionQueueSize is the number of check requests queued for an
+	// execution slot, and of slots queued for their release.
+	checkExecutionQueueSize = 100
+)
+
+var (
+	// checkExecutionRetryInterval is the time between the attempts to acquire
+	// an execution slot for the queued check requests.
+	checkExecutionRetryInterval = 5 * time.Second
+
+	checkExecutionsDelayed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_check_executions_delayed_total",
+			Help: "Number of check requests queued because the maximum number of concurrent executions of the check was reached",
+		},
+		[]string{"namespace"},
+	)
+
+	checkExecutionsSkipped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_check_executions_skipped_total",
+			Help: "Number of check requests not sent to agents because no execution slot of the check could be acquired before the next request",
+		},
+		[]string{"namespace"},
+	)
+)
+
+// pendingExecution is a check request waiting for an execution slot.
+type pendingExecution struct {
+	request  *corev2.CheckRequest
+	deadline time.Time
+	delayed  bool
+}
+
+// checkExecutions are the execution slots held by the agent of a session,
+// and the check requests waiting for one. They are only accessed by the
+// execution pump.
+type checkExecutions struct {
+	// lease is the lease of the slots, granted with the first slot.
+	lease       int64
+	cancelLease context.CancelFunc
+
+	// held are the expiration times of the held slots, by check.
+	held map[string]time.Time
+
+	// pending are the check requests waiting for a slot, by check.
+	pending map[string]*pendingExecution
+}
+
+func newCheckExecutions() *checkExecutions {
+	return &checkExecutions{
+		held:    make(map[string]time.Time),
+		pending: make(map[string]*pendingExecution),
+	}
+}
+
+// checkExecutionTTL returns the time an agent holds an execution slot of the
+// check: until the check times out, or else until its next execution.
+func checkExecutionTTL(check *corev2.CheckConfig) time.Duration {
+	if check.Timeout > 0 {
+		return time.Duration(check.Timeout) * time.Second
+	}
+	if check.Interval > 0 {
+		return time.Duration(check.Interval) * time.Second
+	}
+	return DefaultCheckExecutionTTL
+}
+
+// checkRequestTTL returns the time a check request waits for an execution
+// slot: until the next request of the check.
+func checkRequestTTL(check *corev2.CheckConfig) time.Duration {
+	if check.Interval > 0 {
+		return time.Duration(check.Interval) * time.Second
+	}
+	return DefaultCheckExecutionTTL
+}
+
+// limited returns true if the number of concurrent executions of the check
+// of the request is limited.
+func limited(request *corev2.CheckRequest) bool {
+	return request.Config != nil && request.Config.MaxConcurrentExecutions > 0
+}
+
+// executionPump acquires the execution slots of the check requests queued by
+// subPump, off the send path, and hands the requests back to subPump once
+// acquired. The requests denied a slot are retried until the next request of
+// their check. The slots are released once the agent reports the result of
+// the check, or it times out, and all at once with their lease when the
+// session stops.
+func (s *Session) executionPump() {
+	defer func() {
+		if s.executions.cancelLease != nil {
+			s.executions.cancelLease()
+		}
+		s.wg.Done()
+	}()
+
+	ticker := time.NewTicker(checkExecutionRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case request := <-s.executionq:
+			s.queueCheckExecution(request, time.Now())
+			if !s.tryCheckExecution(request.Config.Name) {
+				return
+			}
+		case check := <-s.releaseq:
+			s.releaseCheckExecution(check)
+		case now := <-ticker.C:
+			s.expireCheckExecutions(now)
+			for check := range s.executions.pending {
+				if !s.tryCheckExecution(check) {
+					return
+				}
+			}
+		case <-s.stopping:
+			return
+		}
+	}
+}
+
+// queueCheckExecution queues the check request for an execution slot. It
+// replaces the previous request of the check still waiting for one.
+func (s *Session) queueCheckExecution(request *corev2.CheckRequest, now time.Time) {
+	check := request.Config.Name
+	if _, ok := s.executions.pending[check]; ok {
+		s.skipCheckExecution(check, "the next request of the check was received")
+	}
+	s.executions.pending[check] = &pendingExecution{
+		request:  request,
+		deadline: now.Add(checkRequestTTL(request.Config)),
+	}
+}
+
+// skipCheckExecution drops the queued request of the check.
+func (s *Session) skipCheckExecution(check, reason string) {
+	delete(s.executions.pending, check)
+	checkExecutionsSkipped.WithLabelValues(s.cfg.Namespace).Inc()
+	logger.WithFields(logrus.Fields{
+		"namespace": s.cfg.Namespace,
+		"agent":     s.cfg.AgentName,
+		"check":     check,
+	}).Warningf("could not acquire a check execution slot before %s, skipping check request", reason)
+}
+
+// expireCheckExecutions drops the queued requests that waited until the next
+// request of their check, and releases the slots held past their TTL.
+func (s *Session) expireCheckExecutions(now time.Time) {
+	for check, pending := range s.executions.pending {
+		if now.After(pending.deadline) {
+			s.skipCheckExecution(check, "the request expired")
+		}
+	}
+	for check, expiration := range s.executions.held {
+		if now.After(expiration) {
+			s.releaseCheckExecution(check)
+		}
+	}
+}
+
+// tryCheckExecution tries to acquire an execution slot for the queued request
+// of the check, and hands it back to subPump if acquired. It returns false if
+// the session is stopping.
+func (s *Session) tryCheckExecution(check string) bool {
+	pending, ok := s.executions.pending[check]
+	if !ok {
+		return true
+	}
+	fields := logrus.Fields{
+		"namespace": s.cfg.Namespace,
+		"agent":     s.cfg.AgentName,
+		"check":     check,
+	}
+
+	acquired, err := s.acquireCheckExecution(pending.request.Config)
+	if err != nil {
+		logger.WithError(err).WithFields(fields).Error("could not acquire a check execution slot, retrying")
+		return true
+	}
+	if !acquired {
+		if !pending.delayed {
+			pending.delayed = true
+			checkExecutionsDelayed.WithLabelValues(s.cfg.Namespace).Inc()
+			logger.WithFields(fields).Debug("maximum concurrent executions of check reached, queuing check request")
+		}
+		return true
+	}
+
+	delete(s.executions.pending, check)
+	s.executions.held[check] = time.Now().Add(checkExecutionTTL(pending.request.Config))
+	select {
+	case s.acquiredq <- pending.request:
+		return true
+	case <-s.stopping:
+		return false
+	}
+}
+
+// acquireCheckExecution acquires an execution slot of the check, attached to
+// the lease of the session. The lease is granted with the first slot, and
+// granted again if acquiring a slot fails, in case it expired.
+func (s *Session) acquireCheckExecution(check *corev2.CheckConfig) (bool, error) {
+	ctx := context.WithValue(s.ctx, corev2.NamespaceKey, s.cfg.Namespace)
+	if s.executions.lease == 0 {
+		leaseCtx, cancel := context.WithCancel(ctx)
+		lease, err := s.store.GrantCheckExecutionLease(leaseCtx, checkExecutionLeaseTTL)
+		if err != nil {
+			cancel()
+			return false, err
+		}
+		s.executions.lease = lease
+		s.executions.cancelLease = cancel
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkExecutionStoreTimeout)
+	defer cancel()
+	acquired, err := s.store.AcquireCheckExecution(
+		ctx, check.Name, s.cfg.AgentName, int(check.MaxConcurrentExecutions), s.executions.lease,
+	)
+	if err != nil {
+		s.executions.cancelLease()
+		s.executions.lease = 0
+		s.executions.cancelLease = nil
+		// The slots were attached to the lease
+		s.executions.held = make(map[string]time.Time)
+	}
+	return acquired, err
+}
+
+// releaseCheckExecution releases the execution slot of the check held by the
+// agent, once it reported the result of its execution or the slot expired.
+func (s *Session) releaseCheckExecution(check string) {
+	if _, ok := s.executions.held[check]; !ok {
+		return
+	}
+	delete(s.executions.held, check)
+	ctx, cancel := context.WithTimeout(
+		context.WithValue(s.ctx, corev2.NamespaceKey, s.cfg.Namespace),
+		checkExecutionStoreTimeout,
+	)
+	defer cancel()
+	if err := s.store.ReleaseCheckExecution(ctx, check, s.cfg.AgentName); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"namespace": s.cfg.Namespace,
+			"agent":     s.cfg.AgentName,
+			"check":     check,
+		}).Error("could not release the check execution slot")
+	}
+}
+
+// checkExecuted queues the release of the execution slot of the check, once
+// the agent reported its result. The slots of the agents that don't report
+// the limit of the check are released once they expire.
+func (s *Session) checkExecuted(check *corev2.Check) {
+	if check.MaxConcurrentExecutions == 0 {
+		return
+	}
+	select {
+	case s.releaseq <- check.Name:
+	case <-s.stopping:
+	}
+}
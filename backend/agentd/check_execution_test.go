@@ -0,0 +1,138 @@
+package agentd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newCheckExecutionSession(st *mockstore.MockStore) *Session {
+	return &Session{
+		cfg: SessionConfig{
+			AgentName: "db01",
+			Namespace: "executions",
+		},
+		store:      st,
+		ctx:        context.Background(),
+		stopping:   make(chan struct{}),
+		executions: newCheckExecutions(),
+		acquiredq:  make(chan *corev2.CheckRequest, checkExecutionQueueSize),
+	}
+}
+
+func TestCheckExecutionTTL(t *testing.T) {
+	check := corev2.FixtureCheckConfig("check")
+	check.Interval = 60
+	check.Timeout = 10
+	assert.Equal(t, 10*time.Second, checkExecutionTTL(check))
+	assert.Equal(t, time.Minute, checkRequestTTL(check))
+
+	check.Timeout = 0
+	assert.Equal(t, time.Minute, checkExecutionTTL(check))
+
+	check.Interval = 0
+	check.Cron = "@hourly"
+	assert.Equal(t, DefaultCheckExecutionTTL, checkExecutionTTL(check))
+	assert.Equal(t, DefaultCheckExecutionTTL, checkRequestTTL(check))
+}
+
+func TestAcquireCheckExecution(t *testing.T) {
+	st := &mockstore.MockStore{}
+	s := newCheckExecutionSession(st)
+
+	// The executions of the check are not limited
+	request := &corev2.CheckRequest{Config: corev2.FixtureCheckConfig("check")}
+	assert.False(t, limited(request))
+
+	request.Config.MaxConcurrentExecutions = 2
+	request.Config.Timeout = 30
+	assert.True(t, limited(request))
+
+	// The lease of the session is granted with its first slot
+	st.On("GrantCheckExecutionLease", mock.Anything, checkExecutionLeaseTTL).Return(int64(42), nil).Once()
+	st.On("AcquireCheckExecution", mock.Anything, "check", "db01", 2, int64(42)).Return(true, nil).Once()
+	s.queueCheckExecution(request, time.Now())
+	require.True(t, s.tryCheckExecution("check"))
+	assert.Equal(t, request, <-s.acquiredq)
+	assert.Empty(t, s.executions.pending)
+	assert.Contains(t, s.executions.held, "check")
+
+	// The slot is released once the agent reports the result
+	st.On("ReleaseCheckExecution", mock.Anything, "check", "db01").Return(nil).Once()
+	s.releaseCheckExecution("check")
+	s.releaseCheckExecution("check")
+	st.AssertNumberOfCalls(t, "ReleaseCheckExecution", 1)
+
+	// The request denied a slot is queued and retried, with the same lease
+	delayed := counterValue(t, checkExecutionsDelayed.WithLabelValues("executions"))
+	st.On("AcquireCheckExecution", mock.Anything, "check", "db01", 2, int64(42)).Return(false, nil).Twice()
+	s.queueCheckExecution(request, time.Now())
+	require.True(t, s.tryCheckExecution("check"))
+	require.True(t, s.tryCheckExecution("check"))
+	assert.Contains(t, s.executions.pending, "check")
+	assert.Empty(t, s.acquiredq)
+	assert.Equal(t, delayed+1, counterValue(t, checkExecutionsDelayed.WithLabelValues("executions")))
+
+	st.On("AcquireCheckExecution", mock.Anything, "check", "db01", 2, int64(42)).Return(true, nil).Once()
+	require.True(t, s.tryCheckExecution("check"))
+	assert.Equal(t, request, <-s.acquiredq)
+	st.AssertNumberOfCalls(t, "GrantCheckExecutionLease", 1)
+}
+
+func TestCheckExecutionLeaseRegranted(t *testing.T) {
+	st := &mockstore.MockStore{}
+	s := newCheckExecutionSession(st)
+	request := &corev2.CheckRequest{Config: corev2.FixtureCheckConfig("check")}
+	request.Config.MaxConcurrentExecutions = 1
+
+	st.On("GrantCheckExecutionLease", mock.Anything, checkExecutionLeaseTTL).Return(int64(42), nil).Once()
+	st.On("AcquireCheckExecution", mock.Anything, "check", "db01", 1, int64(42)).Return(false, errors.New("lease not found")).Once()
+	s.queueCheckExecution(request, time.Now())
+	require.True(t, s.tryCheckExecution("check"))
+	assert.Contains(t, s.executions.pending, "check")
+	assert.Zero(t, s.executions.lease)
+
+	// A new lease is granted for the retry
+	st.On("GrantCheckExecutionLease", mock.Anything, checkExecutionLeaseTTL).Return(int64(43), nil).Once()
+	st.On("AcquireCheckExecution", mock.Anything, "check", "db01", 1, int64(43)).Return(true, nil).Once()
+	require.True(t, s.tryCheckExecution("check"))
+	assert.Equal(t, request, <-s.acquiredq)
+}
+
+func TestExpireCheckExecutions(t *testing.T) {
+	st := &mockstore.MockStore{}
+	s := newCheckExecutionSession(st)
+	now := time.Now()
+
+	request := &corev2.CheckRequest{Config: corev2.FixtureCheckConfig("check")}
+	request.Config.MaxConcurrentExecutions = 1
+	request.Config.Interval = 60
+	s.queueCheckExecution(request, now)
+
+	// The queued request is replaced by the next request of the check
+	skipped := counterValue(t, checkExecutionsSkipped.WithLabelValues("executions"))
+	next := &corev2.CheckRequest{Config: request.Config, Issued: 1}
+	s.queueCheckExecution(next, now)
+	assert.Equal(t, next, s.executions.pending["check"].request)
+	assert.Equal(t, skipped+1, counterValue(t, checkExecutionsSkipped.WithLabelValues("executions")))
+
+	// The slots held past their TTL are released, and the requests are
+	// dropped once their check is due again
+	s.executions.held["other"] = now
+	st.On("ReleaseCheckExecution", mock.Anything, "other", "db01").Return(nil).Once()
+	s.expireCheckExecutions(now.Add(time.Second))
+	assert.Empty(t, s.executions.held)
+	assert.Contains(t, s.executions.pending, "check")
+
+	s.expireCheckExecutions(now.Add(61 * time.Second))
+	assert.Empty(t, s.executions.pending)
+	assert.Equal(t, skipped+2, counterValue(t, checkExecutionsSkipped.WithLabelValues("executions")))
+	st.AssertExpectations(t)
+}
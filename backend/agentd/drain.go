@@ -0,0 +1,158 @@
+package agentd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sensu/sensu-go/transport"
+)
+
+// sessionSet keeps track of the running sessions of an Agentd.
+type sessionSet struct {
+	mu       sync.Mutex
+	sessions map[*Session]struct{}
+
+	// names indexes the sessions by namespace and agent name.
+	names map[string]map[*Session]struct{}
+
+	// removed is closed, and replaced, every time a session is removed.
+	removed chan struct{}
+}
+
+func newSessionSet() *sessionSet {
+	return &sessionSet{
+		sessions: make(map[*Session]struct{}),
+		names:    make(map[string]map[*Session]struct{}),
+		removed:  make(chan struct{}),
+	}
+}
+
+// track adds the session to the set until it stops.
+func (s *sessionSet) track(session *Session) {
+	key := sessionKey(session.cfg.Namespace, session.cfg.AgentName)
+	s.mu.Lock()
+	s.sessions[session] = struct{}{}
+	if s.names[key] == nil {
+		s.names[key] = make(map[*Session]struct{})
+	}
+	s.names[key][session] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-session.stopping
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.sessions, session)
+		delete(s.names[key], session)
+		if len(s.names[key]) == 0 {
+			delete(s.names, key)
+		}
+		close(s.removed)
+		s.removed = make(chan struct{})
+	}()
+}
+
+// list returns the sessions of the set.
+func (s *sessionSet) list() []*Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessions := make([]*Session, 0, len(s.sessions))
+	for session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// named returns the running sessions of the agent of the given namespace and
+// name.
+func (s *sessionSet) named(namespace, agent string) []*Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sessions []*Session
+	for session := range s.names[sessionKey(namespace, agent)] {
+		select {
+		case <-session.stopping:
+		default:
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+// sessionKey returns the key of the sessions of an agent in a sessionSet.
+func sessionKey(namespace, agent string) string {
+	return namespace + ":" + agent
+}
+
+// wait blocks until the set is empty, and returns true, or until the timeout
+// channel receives, and returns false.
+func (s *sessionSet) wait(timeout <-chan time.Time) bool {
+	for {
+		s.mu.Lock()
+		n, removed := len(s.sessions), s.removed
+		s.mu.Unlock()
+		if n == 0 {
+			return true
+		}
+		select {
+		case <-removed:
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+// Drain asks the agent to reconnect to another backend. The session stops
+// once the agent has closed the connection.
+func (s *Session) Drain() {
+	select {
+	case s.drain <- struct{}{}:
+	default:
+		// A reconnect request is already pending
+	}
+}
+
+// sendReconnect sends the reconnect request to the agent. The agents that do
+// not support it are disconnected instead, and reconnect on their own.
+func (s *Session) sendReconnect() error {
+	if !s.supports(transport.FeatureReconnect) {
+		logger.WithField("agent", s.cfg.AgentName).Info("disconnecting agent so it reconnects to another backend")
+		return s.conn.Close()
+	}
+	logger.WithField("agent", s.cfg.AgentName).Info("asking agent to reconnect to another backend")
+	return s.conn.Send(transport.NewMessage(transport.MessageTypeReconnect, nil))
+}
+
+// drain asks the agents connected to this backend to reconnect to another
+// backend, staggering the requests over the drain window so the remaining
+// backends aren't hit by all the agents at once, and waits for the sessions
+// to end until the drain window expires.
+func (a *Agentd) drain() {
+	sessions := a.sessions.list()
+	if a.drainWindow <= 0 || len(sessions) == 0 {
+		return
+	}
+	logger.WithField("sessions", len(sessions)).Infof("draining agent sessions over %s", a.drainWindow)
+
+	deadline := time.NewTimer(a.drainWindow)
+	defer deadline.Stop()
+
+	interval := a.drainWindow / time.Duration(len(sessions))
+	for i, session := range sessions {
+		if i > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-timer.C:
+			case <-deadline.C:
+				timer.Stop()
+				logger.Warn("drain window expired before all the agents were asked to reconnect")
+				return
+			}
+		}
+		session.Drain()
+	}
+
+	if !a.sessions.wait(deadline.C) {
+		logger.WithField("sessions", len(a.sessions.list())).Warn("drain window expired with agent sessions still connected")
+	}
+}
@@ -0,0 +1,92 @@
+package agentd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+// newDrainSession returns a session which stops once it is drained, like the
+// session of an agent reconnecting to another backend, and records when it
+// was drained.
+func newDrainSession(drained chan<- time.Time) *Session {
+	s := &Session{
+		drain:    make(chan struct{}, 1),
+		stopping: make(chan struct{}),
+	}
+	go func() {
+		<-s.drain
+		drained <- time.Now()
+		close(s.stopping)
+	}()
+	return s
+}
+
+func TestAgentdDrain(t *testing.T) {
+	a := &Agentd{
+		sessions:    newSessionSet(),
+		drainWindow: 300 * time.Millisecond,
+	}
+	drained := make(chan time.Time, 3)
+	for i := 0; i < 3; i++ {
+		a.sessions.track(newDrainSession(drained))
+	}
+
+	start := time.Now()
+	a.drain()
+	assert.Empty(t, a.sessions.list())
+	close(drained)
+
+	// The reconnect requests are staggered over the drain window
+	var times []time.Time
+	for ts := range drained {
+		times = append(times, ts)
+	}
+	if assert.Len(t, times, 3) {
+		assert.True(t, times[2].Sub(start) >= 200*time.Millisecond)
+	}
+}
+
+func TestAgentdDrainWindowExpired(t *testing.T) {
+	a := &Agentd{
+		sessions:    newSessionSet(),
+		drainWindow: 50 * time.Millisecond,
+	}
+	// This session never stops
+	session := &Session{
+		drain:    make(chan struct{}, 1),
+		stopping: make(chan struct{}),
+	}
+	a.sessions.track(session)
+
+	a.drain()
+	assert.Len(t, a.sessions.list(), 1)
+	assert.Len(t, session.drain, 1)
+}
+
+func TestAgentdDrainDisabled(t *testing.T) {
+	a := &Agentd{sessions: newSessionSet()}
+	session := &Session{
+		drain:    make(chan struct{}, 1),
+		stopping: make(chan struct{}),
+	}
+	a.sessions.track(session)
+
+	a.drain()
+	assert.Len(t, session.drain, 0)
+}
+
+func TestSessionSendReconnectUnsupported(t *testing.T) {
+	conn := &testTransport{sendCh: make(chan *transport.Message, 1)}
+	s := &Session{
+		cfg:  SessionConfig{AgentName: "testing", ProtocolVersion: 1},
+		conn: conn,
+	}
+
+	// The agents that do not support the reconnect requests are disconnected
+	assert.NoError(t, s.sendReconnect())
+	assert.True(t, conn.Closed())
+	assert.Empty(t, conn.sendCh)
+}
@@ -0,0 +1,188 @@
+package agentd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DuplicateAgentPolicyWarn allows the session of a duplicate agent, and
+	// publishes a warning event of the agent-duplicate-name check until only
+	// one of the agents remains connected.
+	DuplicateAgentPolicyWarn = "warn"
+
+	// DuplicateAgentPolicyReject rejects the session of a duplicate agent.
+	DuplicateAgentPolicyReject = "reject"
+
+	// DuplicateAgentPolicyEvict closes the sessions of the agents already
+	// connected with the name of a duplicate agent.
+	DuplicateAgentPolicyEvict = "evict"
+
+	// DefaultDuplicateAgentPolicy is the default duplicate agent policy.
+	DefaultDuplicateAgentPolicy = DuplicateAgentPolicyWarn
+
+	// DuplicateAgentCheckName is the name of the check of the events
+	// reporting the agents connected more than once with the same name.
+	DuplicateAgentCheckName = "agent-duplicate-name"
+)
+
+var (
+	duplicateAgents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_duplicate_sessions_total",
+			Help: "Number of agent sessions opened with the name of an agent already connected to this backend",
+		},
+		[]string{"namespace", "policy"},
+	)
+)
+
+// ValidateDuplicateAgentPolicy returns an error if the duplicate agent policy
+// is unknown. An empty policy stands for the default policy.
+func ValidateDuplicateAgentPolicy(policy string) error {
+	switch policy {
+	case "", DuplicateAgentPolicyWarn, DuplicateAgentPolicyReject, DuplicateAgentPolicyEvict:
+		return nil
+	}
+	return fmt.Errorf(
+		"invalid duplicate agent policy %q, must be one of %q, %q or %q",
+		policy, DuplicateAgentPolicyWarn, DuplicateAgentPolicyReject, DuplicateAgentPolicyEvict,
+	)
+}
+
+// duplicateWarnings keeps track of the agents whose duplicate-name warning
+// event is not resolved yet.
+type duplicateWarnings struct {
+	mu     sync.Mutex
+	agents map[string]struct{}
+}
+
+func newDuplicateWarnings() *duplicateWarnings {
+	return &duplicateWarnings{agents: make(map[string]struct{})}
+}
+
+// add records the warning of the agent.
+func (w *duplicateWarnings) add(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.agents[key] = struct{}{}
+}
+
+// remove deletes the warning of the agent, and returns true if it existed.
+func (w *duplicateWarnings) remove(key string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.agents[key]
+	delete(w.agents, key)
+	return ok
+}
+
+// rejectDuplicateAgent returns true if the session of the agent must be
+// rejected, because an agent of the same name is already connected.
+func (a *Agentd) rejectDuplicateAgent(namespace, agent, addr string) bool {
+	if a.duplicatePolicy != DuplicateAgentPolicyReject || len(a.sessions.named(namespace, agent)) == 0 {
+		return false
+	}
+	duplicateAgents.WithLabelValues(namespace, a.duplicatePolicy).Inc()
+	logger.WithFields(logrus.Fields{
+		"addr":      addr,
+		"namespace": namespace,
+		"agent":     agent,
+	}).Warn("rejecting session of duplicate agent")
+	return true
+}
+
+// handleDuplicateAgent applies the duplicate agent policy to the new session,
+// if other sessions of the same agent are running.
+func (a *Agentd) handleDuplicateAgent(session *Session) {
+	var duplicates []*Session
+	for _, s := range a.sessions.named(session.cfg.Namespace, session.cfg.AgentName) {
+		if s != session {
+			duplicates = append(duplicates, s)
+		}
+	}
+	if len(duplicates) == 0 {
+		return
+	}
+	duplicateAgents.WithLabelValues(session.cfg.Namespace, a.duplicatePolicy).Inc()
+
+	addrs := make([]string, 0, len(duplicates))
+	for _, s := range duplicates {
+		addrs = append(addrs, s.cfg.AgentAddr)
+	}
+	fields := logrus.Fields{
+		"addr":       session.cfg.AgentAddr,
+		"namespace":  session.cfg.Namespace,
+		"agent":      session.cfg.AgentName,
+		"duplicates": addrs,
+	}
+
+	switch a.duplicatePolicy {
+	case DuplicateAgentPolicyEvict:
+		logger.WithFields(fields).Warn("evicting sessions of duplicate agent")
+		for _, s := range duplicates {
+			// The session stops once its connection is closed
+			_ = s.conn.Close()
+		}
+	default:
+		logger.WithFields(fields).Warn("duplicate agent connected")
+		a.duplicateWarnings.add(sessionKey(session.cfg.Namespace, session.cfg.AgentName))
+		a.publishDuplicateAgentEvent(session, append(addrs, session.cfg.AgentAddr))
+	}
+}
+
+// duplicateAgentStopped resolves the warning event of the agent of the stopped
+// session, if only one of its sessions remains.
+func (a *Agentd) duplicateAgentStopped(session *Session) {
+	namespace, agent := session.cfg.Namespace, session.cfg.AgentName
+	remaining := a.sessions.named(namespace, agent)
+	if len(remaining) > 1 || !a.duplicateWarnings.remove(sessionKey(namespace, agent)) {
+		return
+	}
+	var addrs []string
+	for _, s := range remaining {
+		addrs = append(addrs, s.cfg.AgentAddr)
+	}
+	a.publishDuplicateAgentEvent(session, addrs)
+}
+
+// publishDuplicateAgentEvent publishes the event of the agent-duplicate-name
+// check of the agent of the session, given the addresses of the connected
+// agents of that name. The check is in the warning state if more than one
+// agent is connected.
+func (a *Agentd) publishDuplicateAgentEvent(session *Session, addrs []string) {
+	namespace, agent := session.cfg.Namespace, session.cfg.AgentName
+	now := time.Now()
+	check := &corev2.Check{
+		ObjectMeta: corev2.NewObjectMeta(DuplicateAgentCheckName, namespace),
+		Interval:   1,
+		Executed:   now.Unix(),
+		Issued:     now.Unix(),
+		Output:     fmt.Sprintf("Agent %s is connected once", agent),
+	}
+	if len(addrs) > 1 {
+		check.Status = 1
+		check.Output = fmt.Sprintf(
+			"Agent %s is connected %d times to the backend, from %s", agent, len(addrs), strings.Join(addrs, ", "),
+		)
+	}
+	event := &corev2.Event{
+		ObjectMeta: corev2.NewObjectMeta("", namespace),
+		Timestamp:  now.Unix(),
+		Entity: &corev2.Entity{
+			ObjectMeta:  corev2.NewObjectMeta(agent, namespace),
+			EntityClass: corev2.EntityAgentClass,
+		},
+		Check: check,
+	}
+	event.SetSource(corev2.EventSourceBackend, "agentd")
+	if err := a.bus.Publish(messaging.TopicEventRaw, event); err != nil {
+		logger.WithError(err).Error("could not publish the duplicate agent event")
+	}
+}
@@ -0,0 +1,128 @@
+package agentd
+
+import (
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDuplicateSession returns a running session of the agent.
+func newDuplicateSession(addr string) *Session {
+	return &Session{
+		cfg: SessionConfig{
+			AgentAddr: addr,
+			AgentName: "testing",
+			Namespace: "acme",
+		},
+		conn:     &testTransport{},
+		stopping: make(chan struct{}),
+	}
+}
+
+func TestValidateDuplicateAgentPolicy(t *testing.T) {
+	assert.NoError(t, ValidateDuplicateAgentPolicy(""))
+	assert.NoError(t, ValidateDuplicateAgentPolicy(DuplicateAgentPolicyWarn))
+	assert.NoError(t, ValidateDuplicateAgentPolicy(DuplicateAgentPolicyReject))
+	assert.NoError(t, ValidateDuplicateAgentPolicy(DuplicateAgentPolicyEvict))
+	assert.Error(t, ValidateDuplicateAgentPolicy("ignore"))
+}
+
+func TestRejectDuplicateAgent(t *testing.T) {
+	a := &Agentd{
+		sessions:        newSessionSet(),
+		duplicatePolicy: DuplicateAgentPolicyReject,
+	}
+	assert.False(t, a.rejectDuplicateAgent("acme", "testing", "10.0.0.1"))
+
+	a.sessions.track(newDuplicateSession("10.0.0.1"))
+	assert.True(t, a.rejectDuplicateAgent("acme", "testing", "10.0.0.2"))
+	assert.False(t, a.rejectDuplicateAgent("default", "testing", "10.0.0.2"))
+	assert.False(t, a.rejectDuplicateAgent("acme", "other", "10.0.0.2"))
+
+	a.duplicatePolicy = DuplicateAgentPolicyWarn
+	assert.False(t, a.rejectDuplicateAgent("acme", "testing", "10.0.0.2"))
+}
+
+func TestHandleDuplicateAgentEvict(t *testing.T) {
+	a := &Agentd{
+		sessions:        newSessionSet(),
+		duplicatePolicy: DuplicateAgentPolicyEvict,
+	}
+	old := newDuplicateSession("10.0.0.1")
+	a.sessions.track(old)
+	session := newDuplicateSession("10.0.0.2")
+	a.sessions.track(session)
+
+	a.handleDuplicateAgent(session)
+	assert.True(t, old.conn.Closed())
+	assert.False(t, session.conn.Closed())
+}
+
+func TestHandleDuplicateAgentWarn(t *testing.T) {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	subscriber := messaging.ChannelSubscriber{Channel: make(chan interface{}, 2)}
+	subscription, err := bus.Subscribe(messaging.TopicEventRaw, "testing", subscriber)
+	require.NoError(t, err)
+	defer subscription.Cancel()
+
+	a := &Agentd{
+		bus:               bus,
+		sessions:          newSessionSet(),
+		duplicatePolicy:   DuplicateAgentPolicyWarn,
+		duplicateWarnings: newDuplicateWarnings(),
+	}
+	old := newDuplicateSession("10.0.0.1")
+	a.sessions.track(old)
+	session := newDuplicateSession("10.0.0.2")
+	a.sessions.track(session)
+
+	nextEvent := func() *corev2.Event {
+		select {
+		case msg := <-subscriber.Channel:
+			return msg.(*corev2.Event)
+		case <-time.After(time.Second):
+			t.Fatal("no event was published")
+		}
+		return nil
+	}
+
+	a.handleDuplicateAgent(session)
+	event := nextEvent()
+	assert.Equal(t, DuplicateAgentCheckName, event.Check.Name)
+	assert.Equal(t, "testing", event.Entity.Name)
+	assert.Equal(t, uint32(1), event.Check.Status)
+	assert.Contains(t, event.Check.Output, "10.0.0.1")
+	assert.Contains(t, event.Check.Output, "10.0.0.2")
+	assert.False(t, old.conn.Closed())
+
+	// The warning is resolved once only one of the agents remains
+	close(old.stopping)
+	a.duplicateAgentStopped(old)
+	event = nextEvent()
+	assert.Equal(t, uint32(0), event.Check.Status)
+
+	close(session.stopping)
+	a.duplicateAgentStopped(session)
+	select {
+	case <-subscriber.Channel:
+		t.Fatal("the warning was resolved twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSessionSetNamed(t *testing.T) {
+	set := newSessionSet()
+	session := newDuplicateSession("10.0.0.1")
+	set.track(session)
+	assert.Equal(t, []*Session{session}, set.named("acme", "testing"))
+	assert.Empty(t, set.named("acme", "other"))
+
+	close(session.stopping)
+	assert.Empty(t, set.named("acme", "testing"))
+}
@@ -0,0 +1,356 @@
+package agentd
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// EnrollmentPath is the path of the endpoint the agents obtain and renew
+	// their TLS client certificate from.
+	EnrollmentPath = "/enrollment"
+
+	// DefaultEnrollmentValidity is the default validity of the certificates
+	// issued to the agents.
+	DefaultEnrollmentValidity = 30 * 24 * time.Hour
+
+	// enrollmentClockSkew is how long before their issuance the certificates
+	// are valid, so the agents whose clock is late can use them at once.
+	enrollmentClockSkew = 5 * time.Minute
+
+	// maxEnrollmentRequestSize is the maximum size of the enrollment
+	// requests.
+	maxEnrollmentRequestSize = 64 * 1024
+
+	// enrollmentResultEnrolled, enrollmentResultRenewed and
+	// enrollmentResultRejected label the enrollments metric. The rejected
+	// enrollments aren't labelled by namespace, since the namespace of an
+	// unauthenticated request is chosen by the client.
+	enrollmentResultEnrolled = "enrolled"
+	enrollmentResultRenewed  = "renewed"
+	enrollmentResultRejected = "rejected"
+)
+
+var (
+	enrollments = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_enrollments_total",
+			Help: "Number of TLS client certificate requests of the agents, by result",
+		},
+		[]string{"namespace", "result"},
+	)
+)
+
+// EnrollmentConfig configures the issuance of TLS client certificates to the
+// agents presenting a one-time enrollment token, or a certificate previously
+// issued to renew. Enrollment is disabled if the CA files are not set.
+type EnrollmentConfig struct {
+	// CACertFile is the certificate of the CA signing the certificates of
+	// the agents. It must be trusted by the backends authenticating the
+	// agents with their certificate.
+	CACertFile string
+
+	// CAKeyFile is the private key of the CA.
+	CAKeyFile string
+
+	// Validity is the validity of the certificates issued. Defaults to
+	// DefaultEnrollmentValidity.
+	Validity time.Duration
+}
+
+// Enabled returns true if the agents can enroll.
+func (c EnrollmentConfig) Enabled() bool {
+	return c.CACertFile != "" || c.CAKeyFile != ""
+}
+
+// EnrollmentRequest is the request of an agent for a TLS client certificate.
+type EnrollmentRequest struct {
+	// Namespace is the namespace of the agent.
+	Namespace string `json:"namespace"`
+
+	// Token is the enrollment token of the agent. It is not needed to renew a
+	// certificate, the agent then authenticates with its current one.
+	Token string `json:"token,omitempty"`
+
+	// CSR is the PEM encoded certificate signing request of the agent, whose
+	// common name is the name of the agent.
+	CSR string `json:"csr"`
+}
+
+// EnrollmentResponse is the TLS client certificate issued to an agent.
+type EnrollmentResponse struct {
+	// Certificate is the PEM encoded certificate of the agent.
+	Certificate string `json:"certificate"`
+
+	// CA is the PEM encoded certificate of the CA which signed it.
+	CA string `json:"ca"`
+
+	// Expires is the time, in seconds since the Unix epoch, at which the
+	// certificate expires.
+	Expires int64 `json:"expires"`
+}
+
+// enrollmentCA signs the certificates of the agents.
+type enrollmentCA struct {
+	cert     *x509.Certificate
+	certPEM  []byte
+	key      crypto.Signer
+	pool     *x509.CertPool
+	validity time.Duration
+}
+
+// newEnrollmentCA loads the CA of the enrollment configuration.
+func newEnrollmentCA(c EnrollmentConfig) (*enrollmentCA, error) {
+	if c.CACertFile == "" || c.CAKeyFile == "" {
+		return nil, errors.New("agent enrollment requires both a CA certificate and a CA key file")
+	}
+	if c.Validity < 0 {
+		return nil, fmt.Errorf("invalid enrollment certificate validity %s, must not be negative", c.Validity)
+	}
+	certPEM, err := ioutil.ReadFile(c.CACertFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading enrollment CA certificate: %s", err)
+	}
+	keyPEM, err := ioutil.ReadFile(c.CAKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading enrollment CA key: %s", err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrollment CA: %s", err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrollment CA: %s", err)
+	}
+	if !cert.IsCA {
+		return nil, errors.New("invalid enrollment CA: the certificate is not a CA certificate")
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("invalid enrollment CA: unsupported private key")
+	}
+	ca := &enrollmentCA{
+		cert:     cert,
+		certPEM:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+		key:      key,
+		pool:     x509.NewCertPool(),
+		validity: c.Validity,
+	}
+	ca.pool.AddCert(cert)
+	if ca.validity == 0 {
+		ca.validity = DefaultEnrollmentValidity
+	}
+	return ca, nil
+}
+
+// sign issues a client certificate to the agent of the namespace for the
+// public key of the CSR. The certificate does not outlive the CA.
+func (ca *enrollmentCA) sign(csr *x509.CertificateRequest, agent, namespace string, now time.Time) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:         agent,
+			OrganizationalUnit: []string{namespace},
+		},
+		NotBefore:   now.Add(-enrollmentClockSkew),
+		NotAfter:    now.Add(ca.validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if template.NotAfter.After(ca.cert.NotAfter) {
+		template.NotAfter = ca.cert.NotAfter
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// issuedIdentity returns the agent name and namespace of a certificate issued
+// by the CA, or an error if it was not issued by the CA or expired.
+func (ca *enrollmentCA) issuedIdentity(cert *x509.Certificate, now time.Time) (agent, namespace string, err error) {
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:       ca.pool,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if len(cert.Subject.OrganizationalUnit) == 0 {
+		return "", "", errors.New("the certificate has no namespace")
+	}
+	return cert.Subject.CommonName, cert.Subject.OrganizationalUnit[0], nil
+}
+
+// enrollmentHandler routes the enrollment requests to the enrollment
+// endpoint, and the other requests to next.
+func (a *Agentd) enrollmentHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != EnrollmentPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		a.enroll(w, r)
+	})
+}
+
+// enroll issues a TLS client certificate to the agent presenting an
+// enrollment token, consumed at once, or a certificate previously issued, to
+// renew it as long as the entity of the agent exists.
+func (a *Agentd) enroll(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxEnrollmentRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req EnrollmentRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid enrollment request: %s", err), http.StatusBadRequest)
+		return
+	}
+	block, _ := pem.Decode([]byte(req.CSR))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		http.Error(w, "invalid enrollment request: the csr must be a PEM encoded certificate request", http.StatusBadRequest)
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err == nil {
+		err = csr.CheckSignature()
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid enrollment request: %s", err), http.StatusBadRequest)
+		return
+	}
+	agent := csr.Subject.CommonName
+	if err := corev2.ValidateName(agent); err != nil {
+		http.Error(w, "invalid enrollment request: agent name "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	fields := logrus.Fields{"agent": agent}
+	namespace, result := req.Namespace, enrollmentResultEnrolled
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		// The agent renews the certificate it presents
+		certAgent, certNamespace, err := a.enrollment.issuedIdentity(r.TLS.PeerCertificates[0], now)
+		if err == nil && certAgent != agent {
+			err = fmt.Errorf("the certificate of agent %q can't be renewed for agent %q", certAgent, agent)
+		}
+		if err == nil {
+			err = a.checkRenewal(r.Context(), agent, certNamespace)
+		}
+		if err != nil {
+			a.rejectEnrollment(w, fields, certNamespace, err)
+			return
+		}
+		namespace, result = certNamespace, enrollmentResultRenewed
+	} else if err := a.consumeEnrollmentToken(r.Context(), req.Token, namespace, agent, now); err != nil {
+		a.rejectEnrollment(w, fields, namespace, err)
+		return
+	}
+	fields["namespace"] = namespace
+
+	cert, err := a.enrollment.sign(csr, agent, namespace, now)
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("could not sign the agent certificate")
+		http.Error(w, "could not sign the agent certificate", http.StatusInternalServerError)
+		return
+	}
+	enrollments.WithLabelValues(namespace, result).Inc()
+	fields["expires"] = cert.NotAfter
+	logger.WithFields(fields).Infof("agent certificate %s", result)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(EnrollmentResponse{
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})),
+		CA:          string(a.enrollment.certPEM),
+		Expires:     cert.NotAfter.Unix(),
+	})
+}
+
+// consumeEnrollmentToken deletes the enrollment token of the namespace, once
+// it was verified to allow the agent to enroll. Only one agent can consume
+// the token, the deletion fails for the others. The store failures are
+// returned as a *store.ErrInternal.
+func (a *Agentd) consumeEnrollmentToken(ctx context.Context, token, namespace, agent string, now time.Time) error {
+	if token == "" {
+		return errors.New("an enrollment token or a client certificate is required")
+	}
+	if len(token) < corev2.MinEnrollmentTokenLength || corev2.ValidateName(token) != nil || corev2.ValidateName(namespace) != nil {
+		return errors.New("invalid enrollment token")
+	}
+	ctx = context.WithValue(ctx, corev2.NamespaceKey, namespace)
+	var enrollmentToken corev2.EnrollmentToken
+	if err := a.store.GetResource(ctx, token, &enrollmentToken); err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			return errors.New("invalid enrollment token")
+		}
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if err := enrollmentToken.Allows(agent, now); err != nil {
+		return err
+	}
+	if err := a.store.DeleteResource(ctx, corev2.EnrollmentTokensResource, token); err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			return errors.New("the enrollment token was already used")
+		}
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
+
+// checkRenewal returns an error if the certificate of the agent can't be
+// renewed because its agent entity no longer exists, deleting the entity
+// being how the certificate of an agent is revoked. The store failures are
+// returned as a *store.ErrInternal.
+func (a *Agentd) checkRenewal(ctx context.Context, agent, namespace string) error {
+	ctx = context.WithValue(ctx, corev2.NamespaceKey, namespace)
+	entity, err := a.store.GetEntityByName(ctx, agent)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if entity == nil || entity.EntityClass != corev2.EntityAgentClass {
+		return fmt.Errorf("the certificate of agent %q was revoked, its entity does not exist", agent)
+	}
+	return nil
+}
+
+// rejectEnrollment logs the rejected enrollment and writes its error.
+func (a *Agentd) rejectEnrollment(w http.ResponseWriter, fields logrus.Fields, namespace string, err error) {
+	if _, ok := err.(*store.ErrInternal); ok {
+		logger.WithFields(fields).WithField("namespace", namespace).WithError(err).Error("could not verify the enrollment request")
+		http.Error(w, "could not verify the enrollment request", http.StatusInternalServerError)
+		return
+	}
+	enrollments.WithLabelValues("", enrollmentResultRejected).Inc()
+	logger.WithFields(fields).WithField("namespace", namespace).WithError(err).Warn("agent enrollment rejected")
+	http.Error(w, err.Error(), http.StatusUnauthorized)
+}
@@ -0,0 +1,216 @@
+package agentd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testEnrollmentToken = "5f4dcc3b5aa765d61d8327deb882cf99"
+
+// newTestEnrollmentCA writes a self-signed CA certificate and its key in dir.
+func newTestEnrollmentCA(t *testing.T, dir string) EnrollmentConfig {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sensu-agents"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	config := EnrollmentConfig{
+		CACertFile: filepath.Join(dir, "ca.pem"),
+		CAKeyFile:  filepath.Join(dir, "ca-key.pem"),
+		Validity:   48 * time.Hour,
+	}
+	require.NoError(t, ioutil.WriteFile(config.CACertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(config.CAKeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return config
+}
+
+func newTestEnrollmentRequest(t *testing.T, agent, token string, peer *x509.Certificate) *http.Request {
+	return newTestNamespaceEnrollmentRequest(t, "default", agent, token, peer)
+}
+
+func newTestNamespaceEnrollmentRequest(t *testing.T, namespace, agent, token string, peer *x509.Certificate) *http.Request {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: agent}}, key)
+	require.NoError(t, err)
+	body, err := json.Marshal(EnrollmentRequest{
+		Namespace: namespace,
+		Token:     token,
+		CSR:       string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, EnrollmentPath, bytes.NewReader(body))
+	if peer != nil {
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{peer}}
+	}
+	return req
+}
+
+// rejectedEnrollments returns the number of rejected enrollments, by namespace
+// label.
+func rejectedEnrollments(t *testing.T) map[string]float64 {
+	t.Helper()
+	metrics := make(chan prometheus.Metric, 64)
+	enrollments.Collect(metrics)
+	close(metrics)
+	rejected := make(map[string]float64)
+	for m := range metrics {
+		var metric dto.Metric
+		require.NoError(t, m.Write(&metric))
+		labels := make(map[string]string)
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels["result"] == enrollmentResultRejected {
+			rejected[labels["namespace"]] = metric.GetCounter().GetValue()
+		}
+	}
+	return rejected
+}
+
+func TestEnrollment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sensu-enrollment")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ca, err := newEnrollmentCA(newTestEnrollmentCA(t, dir))
+	require.NoError(t, err)
+
+	st := &mockstore.MockStore{}
+	a := &Agentd{store: st, enrollment: ca}
+	handler := a.enrollmentHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// The other requests are left to the sessions
+	assert.Equal(t, http.StatusTeapot, serve(httptest.NewRequest(http.MethodGet, "/", nil)).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(httptest.NewRequest(http.MethodGet, EnrollmentPath, nil)).Code)
+
+	// The token is consumed by the first agent enrolling with it
+	st.On("GetResource", mock.Anything, testEnrollmentToken, mock.AnythingOfType("*v2.EnrollmentToken")).Run(func(args mock.Arguments) {
+		token := args.Get(2).(*corev2.EnrollmentToken)
+		*token = *corev2.FixtureEnrollmentToken(testEnrollmentToken)
+		token.AgentName = "web-1"
+	}).Return(nil)
+	st.On("DeleteResource", mock.Anything, corev2.EnrollmentTokensResource, testEnrollmentToken).Return(nil).Once()
+	w := serve(newTestEnrollmentRequest(t, "web-1", testEnrollmentToken, nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp EnrollmentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	block, _ := pem.Decode([]byte(resp.Certificate))
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, "web-1", cert.Subject.CommonName)
+	assert.Equal(t, []string{"default"}, cert.Subject.OrganizationalUnit)
+	assert.Equal(t, cert.NotAfter.Unix(), resp.Expires)
+	assert.Equal(t, string(ca.certPEM), resp.CA)
+
+	st.On("DeleteResource", mock.Anything, corev2.EnrollmentTokensResource, testEnrollmentToken).Return(&store.ErrNotFound{}).Once()
+	assert.Equal(t, http.StatusUnauthorized, serve(newTestEnrollmentRequest(t, "web-1", testEnrollmentToken, nil)).Code)
+
+	// The token restricts the agent enrolling
+	assert.Equal(t, http.StatusUnauthorized, serve(newTestEnrollmentRequest(t, "web-2", testEnrollmentToken, nil)).Code)
+
+	// Unknown tokens are rejected
+	st.On("GetResource", mock.Anything, "0123456789abcdef0123", mock.Anything).Return(&store.ErrNotFound{})
+	assert.Equal(t, http.StatusUnauthorized, serve(newTestEnrollmentRequest(t, "web-1", "0123456789abcdef0123", nil)).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(newTestEnrollmentRequest(t, "web-1", "", nil)).Code)
+
+	// The rejections aren't labelled by the namespace of the request
+	rejected := rejectedEnrollments(t)
+	assert.Equal(t, http.StatusUnauthorized, serve(newTestNamespaceEnrollmentRequest(t, "unknown", "web-1", "0123456789abcdef0123", nil)).Code)
+	assert.Equal(t, rejected[""]+1, rejectedEnrollments(t)[""])
+	assert.NotContains(t, rejectedEnrollments(t), "unknown")
+
+	// The agents renew their certificate without token, as long as their
+	// entity exists
+	entity := corev2.FixtureEntity("web-1")
+	entity.EntityClass = corev2.EntityAgentClass
+	st.On("GetEntityByName", mock.Anything, "web-1").Return(entity, nil).Once()
+	w = serve(newTestEnrollmentRequest(t, "web-1", "", cert))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusUnauthorized, serve(newTestEnrollmentRequest(t, "web-2", "", cert)).Code)
+
+	// Deleting the entity revokes the certificate
+	st.On("GetEntityByName", mock.Anything, "web-1").Return((*corev2.Entity)(nil), nil).Once()
+	w = serve(newTestEnrollmentRequest(t, "web-1", "", cert))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "was revoked")
+	proxy := corev2.FixtureEntity("web-1")
+	proxy.EntityClass = corev2.EntityProxyClass
+	st.On("GetEntityByName", mock.Anything, "web-1").Return(proxy, nil).Once()
+	assert.Equal(t, http.StatusUnauthorized, serve(newTestEnrollmentRequest(t, "web-1", "", cert)).Code)
+	st.On("GetEntityByName", mock.Anything, "web-1").Return((*corev2.Entity)(nil), errors.New("unavailable")).Once()
+	assert.Equal(t, http.StatusInternalServerError, serve(newTestEnrollmentRequest(t, "web-1", "", cert)).Code)
+
+	// Certificates from other CAs can't be renewed
+	other, err := newEnrollmentCA(newTestEnrollmentCA(t, dir))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, serve(newTestEnrollmentRequest(t, "web-1", "", other.cert)).Code)
+
+	// Invalid requests
+	req := httptest.NewRequest(http.MethodPost, EnrollmentPath, bytes.NewReader([]byte(`{"csr": "invalid"}`)))
+	assert.Equal(t, http.StatusBadRequest, serve(req).Code)
+}
+
+func TestNewEnrollmentCA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sensu-enrollment")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	config := newTestEnrollmentCA(t, dir)
+
+	ca, err := newEnrollmentCA(EnrollmentConfig{CACertFile: config.CACertFile, CAKeyFile: config.CAKeyFile})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultEnrollmentValidity, ca.validity)
+
+	_, err = newEnrollmentCA(EnrollmentConfig{CACertFile: config.CACertFile})
+	assert.Error(t, err)
+
+	config.Validity = -time.Hour
+	_, err = newEnrollmentCA(config)
+	assert.Error(t, err)
+}
@@ -0,0 +1,162 @@
+package agentd
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
+)
+
+var (
+	messagesReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_messages_received_total",
+			Help: "Number of messages received from the agents",
+		},
+		[]string{"namespace", "agent", "type"},
+	)
+
+	messagesSent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_messages_sent_total",
+			Help: "Number of messages sent to the agents",
+		},
+		[]string{"namespace", "agent", "type"},
+	)
+
+	bytesReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_received_bytes_total",
+			Help: "Number of bytes of the payloads of the messages received from the agents",
+		},
+		[]string{"namespace", "agent"},
+	)
+
+	bytesSent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_sent_bytes_total",
+			Help: "Number of bytes of the payloads of the messages sent to the agents",
+		},
+		[]string{"namespace", "agent"},
+	)
+
+	handlerErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_handler_errors_total",
+			Help: "Number of messages of the agents that could not be handled",
+		},
+		[]string{"namespace", "agent", "type"},
+	)
+
+	publishLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "sensu_go_agent_publish_latency_seconds",
+			Help:    "Time between the creation of the events and keepalives by the agents and their publication to the message bus",
+			Buckets: []float64{0.5, 1, 2, 5, 10, 30, 60, 300},
+		},
+		[]string{"namespace", "type"},
+	)
+
+	oversizedMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_oversized_messages_total",
+			Help: "Number of messages of the agents exceeding the maximum message size, by action taken",
+		},
+		[]string{"namespace", "agent", "type", "action"},
+	)
+
+	missedPongs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_missed_pongs_total",
+			Help: "Number of pings sent to the agents over WebSocket that were not answered before the next ping",
+		},
+		[]string{"namespace", "agent"},
+	)
+)
+
+// sessionMetrics records the throughput metrics of a session. The metrics are
+// labeled with the namespace of the agent, and its name if the per-agent
+// metrics are enabled. The types of the messages are tracked to delete the
+// per-agent metrics when the session stops: received by the receive pump, and
+// sent by the send pump.
+type sessionMetrics struct {
+	namespace     string
+	agent         string
+	receivedTypes map[string]struct{}
+	sentTypes     map[string]struct{}
+}
+
+func newSessionMetrics(cfg SessionConfig) *sessionMetrics {
+	m := &sessionMetrics{
+		namespace:     cfg.Namespace,
+		receivedTypes: make(map[string]struct{}),
+		sentTypes:     make(map[string]struct{}),
+	}
+	if cfg.AgentMetrics {
+		m.agent = cfg.AgentName
+	}
+	return m
+}
+
+// received records a message received from the agent.
+func (m *sessionMetrics) received(msg *transport.Message) {
+	m.receivedTypes[msg.Type] = struct{}{}
+	messagesReceived.WithLabelValues(m.namespace, m.agent, msg.Type).Inc()
+	bytesReceived.WithLabelValues(m.namespace, m.agent).Add(float64(len(msg.Payload)))
+}
+
+// sent records a message sent to the agent.
+func (m *sessionMetrics) sent(msg *transport.Message) {
+	m.sentTypes[msg.Type] = struct{}{}
+	messagesSent.WithLabelValues(m.namespace, m.agent, msg.Type).Inc()
+	bytesSent.WithLabelValues(m.namespace, m.agent).Add(float64(len(msg.Payload)))
+}
+
+// handlerError records a message of the agent that could not be handled.
+func (m *sessionMetrics) handlerError(msgType string) {
+	handlerErrors.WithLabelValues(m.namespace, m.agent, msgType).Inc()
+}
+
+// published records the publication of an event or keepalive, created by the
+// agent at the timestamp of the event, to the message bus. The negative
+// latencies, caused by clock skew, are recorded as 0.
+func (m *sessionMetrics) published(msgType string, event *corev2.Event, now time.Time) {
+	latency := now.Sub(time.Unix(event.Timestamp, 0)).Seconds()
+	if latency < 0 {
+		latency = 0
+	}
+	publishLatency.WithLabelValues(m.namespace, msgType).Observe(latency)
+}
+
+// oversized records a message of the agent exceeding the maximum message
+// size, rejected or truncated.
+func (m *sessionMetrics) oversized(msgType, action string) {
+	oversizedMessages.WithLabelValues(m.namespace, m.agent, msgType, action).Inc()
+}
+
+// missedPong records a ping of the agent that was not answered before the
+// next one. It is called by the ping goroutine of the transport.
+func (m *sessionMetrics) missedPong() {
+	missedPongs.WithLabelValues(m.namespace, m.agent).Inc()
+}
+
+// delete removes the per-agent metrics of a stopped session. The metrics
+// aggregated by namespace are shared by the sessions, and kept.
+func (m *sessionMetrics) delete() {
+	if m.agent == "" {
+		return
+	}
+	for msgType := range m.receivedTypes {
+		messagesReceived.DeleteLabelValues(m.namespace, m.agent, msgType)
+		handlerErrors.DeleteLabelValues(m.namespace, m.agent, msgType)
+		oversizedMessages.DeleteLabelValues(m.namespace, m.agent, msgType, actionRejected)
+		oversizedMessages.DeleteLabelValues(m.namespace, m.agent, msgType, actionTruncated)
+	}
+	for msgType := range m.sentTypes {
+		messagesSent.DeleteLabelValues(m.namespace, m.agent, msgType)
+	}
+	bytesReceived.DeleteLabelValues(m.namespace, m.agent)
+	bytesSent.DeleteLabelValues(m.namespace, m.agent)
+	missedPongs.DeleteLabelValues(m.namespace, m.agent)
+}
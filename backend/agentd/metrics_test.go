@@ -0,0 +1,67 @@
+package agentd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	t.Helper()
+	var metric dto.Metric
+	require.NoError(t, counter.Write(&metric))
+	return metric.GetCounter().GetValue()
+}
+
+func TestSessionMetrics(t *testing.T) {
+	m := newSessionMetrics(SessionConfig{Namespace: "metrics", AgentName: "agent1", AgentMetrics: true})
+
+	m.received(transport.NewMessage(transport.MessageTypeEvent, make([]byte, 10)))
+	m.received(transport.NewMessage(transport.MessageTypeEvent, make([]byte, 5)))
+	m.handlerError(transport.MessageTypeEvent)
+	m.sent(transport.NewMessage(corev2.CheckRequestType, make([]byte, 20)))
+
+	assert.Equal(t, 2.0, counterValue(t, messagesReceived.WithLabelValues("metrics", "agent1", transport.MessageTypeEvent)))
+	assert.Equal(t, 15.0, counterValue(t, bytesReceived.WithLabelValues("metrics", "agent1")))
+	assert.Equal(t, 1.0, counterValue(t, handlerErrors.WithLabelValues("metrics", "agent1", transport.MessageTypeEvent)))
+	assert.Equal(t, 1.0, counterValue(t, messagesSent.WithLabelValues("metrics", "agent1", corev2.CheckRequestType)))
+	assert.Equal(t, 20.0, counterValue(t, bytesSent.WithLabelValues("metrics", "agent1")))
+
+	// The per-agent metrics are deleted with the session
+	m.delete()
+	assert.Equal(t, 0.0, counterValue(t, messagesReceived.WithLabelValues("metrics", "agent1", transport.MessageTypeEvent)))
+	assert.Equal(t, 0.0, counterValue(t, bytesSent.WithLabelValues("metrics", "agent1")))
+}
+
+func TestSessionMetricsByNamespace(t *testing.T) {
+	m1 := newSessionMetrics(SessionConfig{Namespace: "metrics-ns", AgentName: "agent1"})
+	m2 := newSessionMetrics(SessionConfig{Namespace: "metrics-ns", AgentName: "agent2"})
+
+	m1.received(transport.NewMessage(transport.MessageTypeKeepalive, nil))
+	m2.received(transport.NewMessage(transport.MessageTypeKeepalive, nil))
+	assert.Equal(t, 2.0, counterValue(t, messagesReceived.WithLabelValues("metrics-ns", "", transport.MessageTypeKeepalive)))
+
+	// The metrics shared by the sessions of the namespace are kept
+	m1.delete()
+	assert.Equal(t, 2.0, counterValue(t, messagesReceived.WithLabelValues("metrics-ns", "", transport.MessageTypeKeepalive)))
+}
+
+func TestSessionMetricsPublished(t *testing.T) {
+	m := newSessionMetrics(SessionConfig{Namespace: "metrics-latency"})
+	now := time.Unix(1000, 0)
+
+	m.published(transport.MessageTypeEvent, &corev2.Event{Timestamp: 997}, now)
+	// Events from the future, because of clock skew, have no latency
+	m.published(transport.MessageTypeEvent, &corev2.Event{Timestamp: 1005}, now)
+
+	var metric dto.Metric
+	require.NoError(t, publishLatency.WithLabelValues("metrics-latency", transport.MessageTypeEvent).Write(&metric))
+	assert.Equal(t, uint64(2), metric.GetHistogram().GetSampleCount())
+	assert.Equal(t, 3.0, metric.GetHistogram().GetSampleSum())
+}
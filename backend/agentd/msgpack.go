@@ -0,0 +1,43 @@
+package agentd
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/sensu/sensu-go/util/msgpack"
+)
+
+// MessagePackSerializationHeader is the Content-Type header which indicates
+// MessagePack serialization.
+const MessagePackSerializationHeader = "application/x-msgpack"
+
+// MarshalMessagePack serializes proto messages with MessagePack. The messages
+// are encoded like their JSON representation, with the same field names.
+func MarshalMessagePack(msg proto.Message) ([]byte, error) {
+	b, err := MarshalJSON(msg)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return msgpack.Marshal(v)
+}
+
+// UnmarshalMessagePack deserializes proto messages serialized with
+// MessagePack.
+func UnmarshalMessagePack(b []byte, msg proto.Message) error {
+	v, err := msgpack.Unmarshal(b)
+	if err != nil {
+		return err
+	}
+	j, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return UnmarshalJSON(j, msg)
+}
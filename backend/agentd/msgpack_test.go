@@ -0,0 +1,31 @@
+package agentd
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessagePackRoundTrip(t *testing.T) {
+	entity := corev2.FixtureEntity("entity1")
+	entity.Subscriptions = []string{"linux", "entity:entity1"}
+	entity.LastSeen = 1500000000
+
+	b, err := MarshalMessagePack(entity)
+	require.NoError(t, err)
+	j, err := MarshalJSON(entity)
+	require.NoError(t, err)
+	assert.True(t, len(b) < len(j))
+
+	decoded := &corev2.Entity{}
+	require.NoError(t, UnmarshalMessagePack(b, decoded))
+	assert.Equal(t, entity.Name, decoded.Name)
+	assert.Equal(t, entity.Subscriptions, decoded.Subscriptions)
+	assert.Equal(t, entity.LastSeen, decoded.LastSeen)
+}
+
+func TestUnmarshalMessagePackInvalid(t *testing.T) {
+	assert.Error(t, UnmarshalMessagePack([]byte{0xa3, 'a'}, &corev2.Entity{}))
+}
@@ -0,0 +1,179 @@
+package agentd
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MessageSizePolicyReject rejects the messages exceeding the maximum
+	// message size.
+	MessageSizePolicyReject = "reject"
+
+	// MessageSizePolicyTruncate truncates the check output of the events
+	// exceeding the maximum message size, and rejects the other messages
+	// exceeding it.
+	MessageSizePolicyTruncate = "truncate"
+
+	// truncatedOutputSuffix ends the check output of the truncated events.
+	truncatedOutputSuffix = "\n[output truncated by sensu-backend]"
+
+	// actionRejected and actionTruncated label the oversized messages
+	// metric.
+	actionRejected  = "rejected"
+	actionTruncated = "truncated"
+)
+
+// MessageSizeConfig configures the maximum size of the payloads of the
+// messages received from an agent.
+type MessageSizeConfig struct {
+	// MaxSize is the maximum size, in bytes, of the payload of a message. The
+	// size is not limited if MaxSize is 0.
+	MaxSize int
+
+	// Policy is the policy applied to the messages exceeding the maximum
+	// size: MessageSizePolicyReject or MessageSizePolicyTruncate.
+	Policy string
+}
+
+// Validate returns an error if the message size configuration is invalid.
+func (c MessageSizeConfig) Validate() error {
+	if c.MaxSize < 0 {
+		return fmt.Errorf("invalid maximum message size %d, must not be negative", c.MaxSize)
+	}
+	switch c.Policy {
+	case MessageSizePolicyReject, MessageSizePolicyTruncate:
+	default:
+		return fmt.Errorf(
+			"invalid message size policy %q, must be %q or %q",
+			c.Policy, MessageSizePolicyReject, MessageSizePolicyTruncate,
+		)
+	}
+	return nil
+}
+
+// withDefaults returns the configuration, with the reject policy if unset.
+func (c MessageSizeConfig) withDefaults() MessageSizeConfig {
+	if c.Policy == "" {
+		c.Policy = MessageSizePolicyReject
+	}
+	return c
+}
+
+// checkMessageSize applies the message size policy to the message received
+// from the agent. It returns false if the message is rejected, and must not
+// be handled. The payload of the events truncated by the policy is replaced.
+func (s *Session) checkMessageSize(msg *transport.Message) bool {
+	maxSize := s.cfg.MessageSize.MaxSize
+	if maxSize == 0 || len(msg.Payload) <= maxSize {
+		return true
+	}
+	size := len(msg.Payload)
+
+	var err error
+	if s.cfg.MessageSize.Policy == MessageSizePolicyTruncate {
+		var payload []byte
+		if payload, err = s.truncateEvent(msg); err == nil {
+			msg.Payload = payload
+			s.metrics.oversized(msg.Type, actionTruncated)
+			logger.WithFields(logrus.Fields{
+				"agent":    s.cfg.AgentName,
+				"type":     msg.Type,
+				"size":     size,
+				"max_size": maxSize,
+			}).Warn("truncated the check output of an oversized event")
+			return true
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("message size %d exceeds the maximum message size %d", size, maxSize)
+	}
+
+	s.metrics.oversized(msg.Type, actionRejected)
+	logger.WithError(err).WithFields(logrus.Fields{
+		"agent": s.cfg.AgentName,
+		"type":  msg.Type,
+	}).Warn("rejected an oversized message")
+	s.rejectMessage(msg, size, err)
+	return false
+}
+
+// truncateEvent returns the payload of the event or acked_event message with
+// the check output of the event truncated to fit the maximum message size. It
+// returns an error if the message is not an event, or if the event is still
+// too large without its check output.
+func (s *Session) truncateEvent(msg *transport.Message) ([]byte, error) {
+	payload := msg.Payload
+	var id string
+	if msg.Type == transport.MessageTypeAckedEvent {
+		var err error
+		if id, payload, err = transport.SplitAckedEventPayload(msg.Payload); err != nil {
+			return nil, err
+		}
+	} else if msg.Type != transport.MessageTypeEvent {
+		return nil, fmt.Errorf("%s messages can not be truncated", msg.Type)
+	}
+
+	event := &corev2.Event{}
+	if err := s.unmarshal(payload, event); err != nil {
+		return nil, err
+	}
+	excess := len(msg.Payload) - s.cfg.MessageSize.MaxSize + len(truncatedOutputSuffix)
+	if event.Check == nil || len(event.Check.Output) < excess {
+		return nil, fmt.Errorf("event is larger than the maximum message size %d without its check output", s.cfg.MessageSize.MaxSize)
+	}
+	event.Check.Output = truncateString(event.Check.Output, len(event.Check.Output)-excess) + truncatedOutputSuffix
+
+	truncated, err := s.marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	if id != "" {
+		truncated = transport.NewAckedEventPayload(id, truncated)
+	}
+	if len(truncated) > s.cfg.MessageSize.MaxSize {
+		return nil, fmt.Errorf("truncated event still exceeds the maximum message size %d", s.cfg.MessageSize.MaxSize)
+	}
+	return truncated, nil
+}
+
+// truncateString returns the longest prefix of s no longer than n bytes that
+// does not split a UTF-8 sequence.
+func truncateString(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// rejectMessage tells the agent that its message was rejected, if the agent
+// supports it. The agents that do not are only told about their rejected
+// acked events, which are acknowledged so that they are not sent again.
+func (s *Session) rejectMessage(msg *transport.Message, size int, err error) {
+	var id string
+	if msg.Type == transport.MessageTypeAckedEvent {
+		id, _, _ = transport.SplitAckedEventPayload(msg.Payload)
+	}
+	if !s.supports(transport.FeatureMessageRejection) {
+		s.sendEventAck(id, nil)
+		return
+	}
+	rejection := &transport.MessageRejection{
+		Type:    msg.Type,
+		Size:    size,
+		MaxSize: s.cfg.MessageSize.MaxSize,
+		EventID: id,
+		Error:   err.Error(),
+	}
+	select {
+	case s.checkChannel <- rejection:
+	case <-s.stopping:
+	}
+}
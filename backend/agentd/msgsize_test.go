@@ -0,0 +1,120 @@
+package agentd
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMessageSizeSession(cfg MessageSizeConfig, version int) *Session {
+	sessionCfg := SessionConfig{
+		Namespace:       "default",
+		AgentName:       "agent1",
+		ProtocolVersion: version,
+		MessageSize:     cfg.withDefaults(),
+	}
+	return &Session{
+		cfg:          sessionCfg,
+		stopping:     make(chan struct{}),
+		wg:           &sync.WaitGroup{},
+		checkChannel: make(chan interface{}, 1),
+		marshal:      proto.Marshal,
+		unmarshal:    proto.Unmarshal,
+		metrics:      newSessionMetrics(sessionCfg),
+	}
+}
+
+func eventPayload(t *testing.T, output string) []byte {
+	t.Helper()
+	event := corev2.FixtureEvent("agent1", "check")
+	event.Check.Output = output
+	payload, err := proto.Marshal(event)
+	require.NoError(t, err)
+	return payload
+}
+
+func TestMessageSizeConfigValidate(t *testing.T) {
+	assert.NoError(t, MessageSizeConfig{}.withDefaults().Validate())
+	assert.NoError(t, MessageSizeConfig{MaxSize: 1024, Policy: MessageSizePolicyTruncate}.Validate())
+	assert.Error(t, MessageSizeConfig{MaxSize: -1, Policy: MessageSizePolicyReject}.Validate())
+	assert.Error(t, MessageSizeConfig{Policy: "drop"}.Validate())
+}
+
+func TestCheckMessageSizeUnlimited(t *testing.T) {
+	s := newMessageSizeSession(MessageSizeConfig{}, transport.ProtocolVersion)
+	msg := transport.NewMessage(transport.MessageTypeEvent, eventPayload(t, strings.Repeat("a", 1<<20)))
+	assert.True(t, s.checkMessageSize(msg))
+	assert.Empty(t, s.checkChannel)
+}
+
+func TestCheckMessageSizeReject(t *testing.T) {
+	s := newMessageSizeSession(MessageSizeConfig{MaxSize: 1024}, transport.ProtocolVersion)
+
+	msg := transport.NewMessage(transport.MessageTypeEvent, eventPayload(t, "ok"))
+	assert.True(t, s.checkMessageSize(msg))
+	assert.Empty(t, s.checkChannel)
+
+	payload := transport.NewAckedEventPayload("1", eventPayload(t, strings.Repeat("a", 2048)))
+	msg = transport.NewMessage(transport.MessageTypeAckedEvent, payload)
+	assert.False(t, s.checkMessageSize(msg))
+	require.Len(t, s.checkChannel, 1)
+	rejection, ok := (<-s.checkChannel).(*transport.MessageRejection)
+	require.True(t, ok)
+	assert.Equal(t, transport.MessageTypeAckedEvent, rejection.Type)
+	assert.Equal(t, len(payload), rejection.Size)
+	assert.Equal(t, 1024, rejection.MaxSize)
+	assert.Equal(t, "1", rejection.EventID)
+	assert.NotEmpty(t, rejection.Error)
+}
+
+func TestCheckMessageSizeRejectOldAgent(t *testing.T) {
+	s := newMessageSizeSession(MessageSizeConfig{MaxSize: 1024}, 3)
+
+	// The rejected acked events are acknowledged, so that they are not sent
+	// again by the agents that do not support message rejections
+	payload := transport.NewAckedEventPayload("1", eventPayload(t, strings.Repeat("a", 2048)))
+	assert.False(t, s.checkMessageSize(transport.NewMessage(transport.MessageTypeAckedEvent, payload)))
+	assert.Equal(t, &transport.EventAck{ID: "1"}, receiveEventAck(t, s))
+
+	assert.False(t, s.checkMessageSize(transport.NewMessage(transport.MessageTypeEvent, eventPayload(t, strings.Repeat("a", 2048)))))
+	assert.Empty(t, s.checkChannel)
+}
+
+func TestCheckMessageSizeTruncate(t *testing.T) {
+	s := newMessageSizeSession(MessageSizeConfig{MaxSize: 1024, Policy: MessageSizePolicyTruncate}, transport.ProtocolVersion)
+
+	output := strings.Repeat("é", 1024)
+	msg := transport.NewMessage(transport.MessageTypeAckedEvent, transport.NewAckedEventPayload("1", eventPayload(t, output)))
+	require.True(t, s.checkMessageSize(msg))
+	assert.True(t, len(msg.Payload) <= 1024)
+	assert.Empty(t, s.checkChannel)
+
+	id, payload, err := transport.SplitAckedEventPayload(msg.Payload)
+	require.NoError(t, err)
+	assert.Equal(t, "1", id)
+	event := &corev2.Event{}
+	require.NoError(t, proto.Unmarshal(payload, event))
+	assert.True(t, strings.HasSuffix(event.Check.Output, truncatedOutputSuffix))
+	truncated := strings.TrimSuffix(event.Check.Output, truncatedOutputSuffix)
+	assert.True(t, strings.HasPrefix(output, truncated))
+	assert.Equal(t, 0, len(truncated)%len("é"))
+
+	// The messages other than events are rejected
+	msg = transport.NewMessage(transport.MessageTypeKeepalive, eventPayload(t, output))
+	assert.False(t, s.checkMessageSize(msg))
+	rejection := (<-s.checkChannel).(*transport.MessageRejection)
+	assert.Equal(t, transport.MessageTypeKeepalive, rejection.Type)
+}
+
+func TestTruncateString(t *testing.T) {
+	assert.Equal(t, "abc", truncateString("abc", 5))
+	assert.Equal(t, "ab", truncateString("abc", 2))
+	assert.Equal(t, "a", truncateString("aé", 2))
+	assert.Equal(t, "", truncateString("é", 1))
+}
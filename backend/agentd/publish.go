@@ -0,0 +1,183 @@
+package agentd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPublishBufferSize is the default number of messages of each
+	// agent session buffered while the message bus rejects them.
+	DefaultPublishBufferSize = 100
+
+	// DefaultPublishMaxBackoff is the default maximum time between the
+	// attempts to publish a buffered message.
+	DefaultPublishMaxBackoff = 5 * time.Second
+
+	// publishInitialBackoff is the time before the first attempt to publish a
+	// buffered message, doubled after each failed attempt.
+	publishInitialBackoff = 100 * time.Millisecond
+)
+
+var (
+	publishRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_publish_retries_total",
+			Help: "Number of attempts to publish the buffered messages of an agent to the message bus",
+		},
+		[]string{"namespace"},
+	)
+
+	publishDrops = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_publish_drops_total",
+			Help: "Number of messages of an agent dropped because they could not be published to the message bus",
+		},
+		[]string{"namespace"},
+	)
+)
+
+// PublishBufferConfig configures the buffer of the messages received from an
+// agent that the message bus failed to publish. The buffered messages are
+// published again, in order, with an exponential backoff.
+type PublishBufferConfig struct {
+	// Size is the number of messages buffered by each session. Messages are
+	// dropped as soon as they fail to be published if Size is 0.
+	Size int
+
+	// MaxBackoff is the maximum time between the attempts to publish a
+	// buffered message.
+	MaxBackoff time.Duration
+}
+
+// Validate returns an error if the publish buffer configuration is invalid.
+func (c PublishBufferConfig) Validate() error {
+	if c.Size < 0 {
+		return fmt.Errorf("invalid publish buffer size %d, must not be negative", c.Size)
+	}
+	if c.MaxBackoff < 0 {
+		return fmt.Errorf("invalid publish max backoff %s, must not be negative", c.MaxBackoff)
+	}
+	return nil
+}
+
+// withDefaults returns the configuration, with the default max backoff if
+// unset.
+func (c PublishBufferConfig) withDefaults() PublishBufferConfig {
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = DefaultPublishMaxBackoff
+	}
+	return c
+}
+
+// pendingPublish is a message waiting in the publish buffer.
+type pendingPublish struct {
+	msgType string
+	topic   string
+	event   *corev2.Event
+	ack     string
+}
+
+// publish publishes the event received from the agent to the topic. The event
+// is buffered if the message bus rejects it, or if older events are already
+// buffered so that the events are published in order. An error is only
+// returned if the event is dropped. The event is acknowledged once published
+// if ack is its ID.
+func (s *Session) publish(msgType, topic string, event *corev2.Event, ack string) error {
+	if len(s.publishq) == 0 {
+		err := s.bus.Publish(topic, event)
+		if err == nil {
+			s.metrics.published(msgType, event, time.Now())
+			s.sendEventAck(ack, nil)
+			return nil
+		}
+		if cap(s.publishq) == 0 {
+			return err
+		}
+		logger.WithError(err).WithField("topic", topic).Warn("could not publish message, buffering it")
+	}
+
+	select {
+	case s.publishq <- &pendingPublish{msgType: msgType, topic: topic, event: event, ack: ack}:
+		return nil
+	default:
+		publishDrops.WithLabelValues(s.cfg.Namespace).Inc()
+		return fmt.Errorf("publish buffer full, dropping %s message", msgType)
+	}
+}
+
+// publishPump publishes the buffered messages until the session stops.
+func (s *Session) publishPump() {
+	defer func() {
+		s.wg.Done()
+		logger.Info("shutting down - stopping publishPump")
+	}()
+
+	for {
+		select {
+		case <-s.stopping:
+			s.dropPublishBuffer()
+			return
+		case pending := <-s.publishq:
+			if !s.retryPublish(pending) {
+				s.dropPublishBuffer()
+				return
+			}
+		}
+	}
+}
+
+// retryPublish publishes the buffered message, with an exponential backoff
+// between the attempts. It returns false if the session stopped before the
+// message could be published.
+func (s *Session) retryPublish(pending *pendingPublish) bool {
+	backoff := publishInitialBackoff
+	maxBackoff := s.cfg.PublishBuffer.withDefaults().MaxBackoff
+	for {
+		publishRetries.WithLabelValues(s.cfg.Namespace).Inc()
+		err := s.bus.Publish(pending.topic, pending.event)
+		if err == nil {
+			s.metrics.published(pending.msgType, pending.event, time.Now())
+			s.sendEventAck(pending.ack, nil)
+			return true
+		}
+		logger.WithError(err).WithFields(logrus.Fields{
+			"topic":   pending.topic,
+			"backoff": backoff,
+		}).Warn("could not publish buffered message")
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-s.stopping:
+			timer.Stop()
+			publishDrops.WithLabelValues(s.cfg.Namespace).Inc()
+			return false
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// dropPublishBuffer drops the messages left in the publish buffer when the
+// session stops.
+func (s *Session) dropPublishBuffer() {
+	dropped := len(s.publishq)
+	if dropped == 0 {
+		return
+	}
+	for i := 0; i < dropped; i++ {
+		<-s.publishq
+	}
+	publishDrops.WithLabelValues(s.cfg.Namespace).Add(float64(dropped))
+	logger.WithFields(logrus.Fields{
+		"namespace": s.cfg.Namespace,
+		"agent":     s.cfg.AgentName,
+		"messages":  dropped,
+	}).Warn("dropped the messages left in the publish buffer")
+}
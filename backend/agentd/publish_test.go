@@ -0,0 +1,87 @@
+package agentd
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/testing/mockbus"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPublishSession(cfg PublishBufferConfig) (*Session, *mockbus.MockBus) {
+	bus := &mockbus.MockBus{}
+	sessionCfg := SessionConfig{Namespace: "default", AgentName: "agent1", PublishBuffer: cfg}
+	return &Session{
+		cfg:      sessionCfg,
+		bus:      bus,
+		stopping: make(chan struct{}),
+		wg:       &sync.WaitGroup{},
+		publishq: make(chan *pendingPublish, cfg.Size),
+		metrics:  newSessionMetrics(sessionCfg),
+	}, bus
+}
+
+func TestPublishBufferConfigValidate(t *testing.T) {
+	assert.NoError(t, PublishBufferConfig{}.Validate())
+	assert.NoError(t, PublishBufferConfig{Size: 10, MaxBackoff: time.Second}.Validate())
+	assert.Error(t, PublishBufferConfig{Size: -1}.Validate())
+	assert.Error(t, PublishBufferConfig{MaxBackoff: -time.Second}.Validate())
+}
+
+func TestPublishUnbuffered(t *testing.T) {
+	s, bus := newPublishSession(PublishBufferConfig{})
+	bus.On("Publish", messaging.TopicEventRaw, mock.Anything).Return(errors.New("bus no longer running"))
+
+	err := s.publish(transport.MessageTypeEvent, messaging.TopicEventRaw, corev2.FixtureEvent("agent1", "check"), "")
+	assert.Error(t, err)
+}
+
+func TestPublishBuffered(t *testing.T) {
+	s, bus := newPublishSession(PublishBufferConfig{Size: 2, MaxBackoff: time.Millisecond})
+	bus.On("Publish", messaging.TopicEventRaw, mock.Anything).Return(errors.New("bus no longer running")).Twice()
+	bus.On("Publish", messaging.TopicEventRaw, mock.Anything).Return(nil)
+
+	first := corev2.FixtureEvent("agent1", "first")
+	second := corev2.FixtureEvent("agent1", "second")
+	third := corev2.FixtureEvent("agent1", "third")
+
+	// The first event is buffered when the bus fails
+	require.NoError(t, s.publish(transport.MessageTypeEvent, messaging.TopicEventRaw, first, ""))
+	// The second one is buffered behind it, without being published first
+	require.NoError(t, s.publish(transport.MessageTypeEvent, messaging.TopicEventRaw, second, ""))
+	// The buffer is full, the third one is dropped
+	assert.Error(t, s.publish(transport.MessageTypeEvent, messaging.TopicEventRaw, third, ""))
+	bus.AssertNumberOfCalls(t, "Publish", 1)
+
+	// The buffered events are retried and published in order
+	require.True(t, s.retryPublish(<-s.publishq))
+	require.True(t, s.retryPublish(<-s.publishq))
+
+	var published []string
+	for _, call := range bus.Calls {
+		published = append(published, call.Arguments.Get(1).(*corev2.Event).Check.Name)
+	}
+	assert.Equal(t, []string{"first", "first", "first", "second"}, published)
+}
+
+func TestPublishPumpStop(t *testing.T) {
+	s, bus := newPublishSession(PublishBufferConfig{Size: 2, MaxBackoff: time.Millisecond})
+	bus.On("Publish", messaging.TopicEventRaw, mock.Anything).Return(errors.New("bus no longer running"))
+
+	require.NoError(t, s.publish(transport.MessageTypeEvent, messaging.TopicEventRaw, corev2.FixtureEvent("agent1", "first"), ""))
+	require.NoError(t, s.publish(transport.MessageTypeEvent, messaging.TopicEventRaw, corev2.FixtureEvent("agent1", "second"), ""))
+
+	s.wg.Add(1)
+	go s.publishPump()
+	close(s.stopping)
+	s.wg.Wait()
+
+	assert.Empty(t, s.publishq)
+}
@@ -0,0 +1,120 @@
+package agentd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	sessionRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_session_rejections_total",
+			Help: "Number of agent sessions rejected by the session limits of this backend",
+		},
+		[]string{"namespace"},
+	)
+)
+
+// SessionLimits limits the number of concurrent agent sessions on a backend.
+// A limit of 0 means unlimited.
+type SessionLimits struct {
+	// Total is the maximum number of sessions, across all the namespaces.
+	Total int
+
+	// Namespace is the maximum number of sessions of each namespace without
+	// a limit in Namespaces.
+	Namespace int
+
+	// Namespaces are the maximum numbers of sessions of specific namespaces.
+	Namespaces map[string]int
+}
+
+// Validate returns an error if a session limit is negative.
+func (l SessionLimits) Validate() error {
+	if l.Total < 0 {
+		return fmt.Errorf("invalid session limit %d, must not be negative", l.Total)
+	}
+	if l.Namespace < 0 {
+		return fmt.Errorf("invalid namespace session limit %d, must not be negative", l.Namespace)
+	}
+	for namespace, limit := range l.Namespaces {
+		if limit < 0 {
+			return fmt.Errorf("invalid session limit %d for namespace %q, must not be negative", limit, namespace)
+		}
+	}
+	return nil
+}
+
+// namespaceLimit returns the session limit of the namespace.
+func (l SessionLimits) namespaceLimit(namespace string) int {
+	if limit, ok := l.Namespaces[namespace]; ok {
+		return limit
+	}
+	return l.Namespace
+}
+
+// ParseNamespaceSessionLimits parses session limits of the form
+// namespace=limit.
+func ParseNamespaceSessionLimits(limits []string) (map[string]int, error) {
+	namespaces := make(map[string]int, len(limits))
+	for _, l := range limits {
+		parts := strings.SplitN(l, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid namespace session limit %q, must be of the form namespace=limit", l)
+		}
+		limit, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace session limit %q: %s", l, err)
+		}
+		namespaces[parts[0]] = limit
+	}
+	return namespaces, nil
+}
+
+// sessionQuota enforces the session limits of an Agentd.
+type sessionQuota struct {
+	limits     SessionLimits
+	mu         sync.Mutex
+	total      int
+	namespaces map[string]int
+}
+
+func newSessionQuota(limits SessionLimits) *sessionQuota {
+	return &sessionQuota{
+		limits:     limits,
+		namespaces: make(map[string]int),
+	}
+}
+
+// acquire reserves a session of the namespace, or returns an error if the
+// session limits are reached. The session must be released once it stops.
+func (q *sessionQuota) acquire(namespace string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.limits.Total > 0 && q.total >= q.limits.Total {
+		sessionRejections.WithLabelValues(namespace).Inc()
+		return fmt.Errorf("agent session limit of %d reached on this backend", q.limits.Total)
+	}
+	if limit := q.limits.namespaceLimit(namespace); limit > 0 && q.namespaces[namespace] >= limit {
+		sessionRejections.WithLabelValues(namespace).Inc()
+		return fmt.Errorf("agent session limit of %d reached for namespace %s", limit, namespace)
+	}
+	q.total++
+	q.namespaces[namespace]++
+	return nil
+}
+
+// release releases a session of the namespace.
+func (q *sessionQuota) release(namespace string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.total--
+	q.namespaces[namespace]--
+	if q.namespaces[namespace] <= 0 {
+		delete(q.namespaces, namespace)
+	}
+}
@@ -0,0 +1,59 @@
+package agentd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionQuota(t *testing.T) {
+	quota := newSessionQuota(SessionLimits{
+		Total:      3,
+		Namespace:  1,
+		Namespaces: map[string]int{"acme": 2},
+	})
+
+	require.NoError(t, quota.acquire("default"))
+	assert.Error(t, quota.acquire("default"))
+
+	require.NoError(t, quota.acquire("acme"))
+	require.NoError(t, quota.acquire("acme"))
+	assert.Error(t, quota.acquire("acme"))
+
+	quota.release("default")
+	require.NoError(t, quota.acquire("default"))
+
+	// The total limit applies across all the namespaces
+	assert.Error(t, quota.acquire("dev"))
+	quota.release("acme")
+	require.NoError(t, quota.acquire("dev"))
+}
+
+func TestSessionQuotaUnlimited(t *testing.T) {
+	quota := newSessionQuota(SessionLimits{Namespaces: map[string]int{"acme": 1}})
+	for i := 0; i < 10; i++ {
+		require.NoError(t, quota.acquire("default"))
+	}
+	require.NoError(t, quota.acquire("acme"))
+	assert.Error(t, quota.acquire("acme"))
+}
+
+func TestParseNamespaceSessionLimits(t *testing.T) {
+	limits, err := ParseNamespaceSessionLimits([]string{"default=10", "acme=0"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"default": 10, "acme": 0}, limits)
+
+	for _, l := range []string{"default", "=10", "default=ten"} {
+		_, err := ParseNamespaceSessionLimits([]string{l})
+		assert.Error(t, err, l)
+	}
+}
+
+func TestNewInvalidSessionLimits(t *testing.T) {
+	_, err := New(Config{SessionLimits: SessionLimits{Total: -1}})
+	assert.Error(t, err)
+
+	_, err = New(Config{SessionLimits: SessionLimits{Namespaces: map[string]int{"default": -1}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,224 @@
+package agentd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sensu/sensu-go/backend/ringv2"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// resumeResultResumed and resumeResultInvalid label the session
+	// resumptions metric.
+	resumeResultResumed = "resumed"
+	resumeResultInvalid = "invalid"
+)
+
+var (
+	sessionResumptions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_session_resumptions_total",
+			Help: "Number of agent sessions opened with a resume token, by result",
+		},
+		[]string{"namespace", "result"},
+	)
+)
+
+// resumedSessionKey is the request context key of the resumption of the
+// session resumed by the agent.
+type resumedSessionKey struct{}
+
+// resumption is the state of a session kept for the agent to resume it.
+type resumption struct {
+	namespace     string
+	agent         string
+	user          string
+	subscriptions []string
+
+	// removal removes the agent from the rings of its subscriptions once the
+	// token expires. It is nil while the session runs.
+	removal *time.Timer
+}
+
+// resumeTokens keeps the resume tokens issued to the agents, single-use and
+// valid for the ttl after their session stops. It is safe for concurrent use.
+type resumeTokens struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	tokens map[string]*resumption
+}
+
+func newResumeTokens(ttl time.Duration) *resumeTokens {
+	return &resumeTokens{
+		ttl:    ttl,
+		tokens: make(map[string]*resumption),
+	}
+}
+
+// issue returns a new resume token for the session of the given
+// configuration, or an empty token if it could not be generated.
+func (t *resumeTokens) issue(cfg SessionConfig) string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		logger.WithError(err).Error("could not generate resume token")
+		return ""
+	}
+	token := hex.EncodeToString(b)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tokens[token] = &resumption{
+		namespace:     cfg.Namespace,
+		agent:         cfg.AgentName,
+		user:          cfg.User,
+		subscriptions: cfg.Subscriptions,
+	}
+	return token
+}
+
+// revoke invalidates the token.
+func (t *resumeTokens) revoke(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r, ok := t.tokens[token]; ok && r.removal != nil {
+		r.removal.Stop()
+	}
+	delete(t.tokens, token)
+}
+
+// release starts the ttl of the token of a stopped session. The remove
+// function is called once the token expires without being used.
+func (t *resumeTokens) release(token string, remove func()) {
+	t.mu.Lock()
+	r, ok := t.tokens[token]
+	if ok {
+		r.removal = time.AfterFunc(t.ttl, func() {
+			t.mu.Lock()
+			expired := t.tokens[token] == r
+			if expired {
+				delete(t.tokens, token)
+			}
+			t.mu.Unlock()
+			if expired {
+				remove()
+			}
+		})
+	}
+	t.mu.Unlock()
+
+	// The revoked tokens can not be used to resume the session
+	if !ok {
+		remove()
+	}
+}
+
+// resume consumes the token of a stopped session of the agent, and returns
+// the resumption of the session. It returns nil if the token is unknown,
+// expired, issued to another agent or user, or if its session is still
+// running.
+func (t *resumeTokens) resume(token, namespace, agent, user string) *resumption {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r, ok := t.tokens[token]
+	if !ok || r.namespace != namespace || r.agent != agent || r.user != user || r.removal == nil {
+		return nil
+	}
+	if !r.removal.Stop() {
+		// The token expired, and the agent is being removed from the rings
+		return nil
+	}
+	delete(t.tokens, token)
+	return r
+}
+
+// resumeHandler returns a handler resuming the sessions of the agents with a
+// valid resume token, keeping their membership of the rings of their
+// subscriptions. It must be wrapped by the authentication and authorization
+// of the agents, which are never skipped: the token is only valid for the
+// user the agent authenticated as. The agents without a valid token open a
+// new session with next.
+func (a *Agentd) resumeHandler(next http.Handler) http.Handler {
+	if a.resumeTokens == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(transport.HeaderKeyResumeToken)
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		namespace := r.Header.Get(transport.HeaderKeyNamespace)
+		agent := r.Header.Get(transport.HeaderKeyAgentName)
+		user := r.Header.Get(transport.HeaderKeyUser)
+		resumed := a.resumeTokens.resume(token, namespace, agent, user)
+		if resumed == nil {
+			sessionResumptions.WithLabelValues(namespace, resumeResultInvalid).Inc()
+			logger.WithFields(logrus.Fields{
+				"namespace": namespace,
+				"agent":     agent,
+			}).Debug("invalid resume token, opening a new session")
+			next.ServeHTTP(w, r)
+			return
+		}
+		sessionResumptions.WithLabelValues(namespace, resumeResultResumed).Inc()
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), resumedSessionKey{}, resumed)))
+	})
+}
+
+// releaseResumeToken starts the ttl of the resume token of the stopped
+// session, after which the agent is removed from the rings of its
+// subscriptions. The agent is removed at once if the sessions are drained,
+// since it must reconnect to another backend.
+func (a *Agentd) releaseResumeToken(s *Session) {
+	token := s.cfg.ResumeToken
+	if token == "" {
+		return
+	}
+	remove := func() {
+		removeFromRings(context.Background(), a.ringPool, s.cfg.Namespace, s.cfg.AgentName, s.cfg.Subscriptions)
+	}
+	if atomic.LoadInt32(&a.draining) == 1 {
+		a.resumeTokens.revoke(token)
+		remove()
+		return
+	}
+	a.resumeTokens.release(token, remove)
+}
+
+// removeStaleRings removes the agent of the resumed session from the rings of
+// the subscriptions it no longer has.
+func (a *Agentd) removeStaleRings(resumed *resumption, subscriptions []string) {
+	current := make(map[string]struct{}, len(subscriptions))
+	for _, sub := range subscriptions {
+		current[sub] = struct{}{}
+	}
+	var stale []string
+	for _, sub := range resumed.subscriptions {
+		if _, ok := current[sub]; !ok {
+			stale = append(stale, sub)
+		}
+	}
+	removeFromRings(context.Background(), a.ringPool, resumed.namespace, resumed.agent, stale)
+}
+
+// removeFromRings removes the agent from the rings of the subscriptions.
+func removeFromRings(ctx context.Context, pool *ringv2.Pool, namespace, agent string, subscriptions []string) {
+	for _, sub := range subscriptions {
+		ring := pool.Get(ringv2.Path(namespace, sub))
+		logger.WithFields(logrus.Fields{
+			"namespace": namespace,
+			"agent":     agent,
+		}).Info("removing agent from ring")
+		if err := ring.Remove(ctx, agent); err != nil {
+			logger.WithError(err).Error("unable to remove agent from ring")
+		}
+	}
+}
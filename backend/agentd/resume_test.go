@@ -0,0 +1,123 @@
+package agentd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeTokens(t *testing.T) {
+	tokens := newResumeTokens(time.Minute)
+	cfg := SessionConfig{Namespace: "default", AgentName: "agent1", User: "agent", Subscriptions: []string{"linux"}}
+	token := tokens.issue(cfg)
+	require.NotEmpty(t, token)
+	assert.NotEqual(t, token, tokens.issue(cfg))
+
+	// The session can not be resumed while it runs
+	assert.Nil(t, tokens.resume(token, "default", "agent1", "agent"))
+
+	var removed int32
+	tokens.release(token, func() { atomic.AddInt32(&removed, 1) })
+	assert.Nil(t, tokens.resume(token, "default", "agent2", "agent"))
+	assert.Nil(t, tokens.resume(token, "acme", "agent1", "agent"))
+	assert.Nil(t, tokens.resume(token, "default", "agent1", "someone"))
+	assert.Nil(t, tokens.resume("unknown", "default", "agent1", "agent"))
+
+	resumed := tokens.resume(token, "default", "agent1", "agent")
+	require.NotNil(t, resumed)
+	assert.Equal(t, "agent", resumed.user)
+	assert.Equal(t, []string{"linux"}, resumed.subscriptions)
+
+	// Tokens are single-use, and the agent stays in the rings once resumed
+	assert.Nil(t, tokens.resume(token, "default", "agent1", "agent"))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&removed))
+}
+
+func TestResumeTokensExpire(t *testing.T) {
+	tokens := newResumeTokens(time.Millisecond)
+	token := tokens.issue(SessionConfig{Namespace: "default", AgentName: "agent1"})
+
+	removed := make(chan struct{})
+	tokens.release(token, func() { close(removed) })
+	select {
+	case <-removed:
+	case <-time.After(time.Second):
+		t.Fatal("the agent was not removed from the rings")
+	}
+	assert.Nil(t, tokens.resume(token, "default", "agent1", "agent"))
+}
+
+func TestResumeTokensRevoke(t *testing.T) {
+	tokens := newResumeTokens(time.Minute)
+	token := tokens.issue(SessionConfig{Namespace: "default", AgentName: "agent1"})
+	tokens.revoke(token)
+
+	// The agent is removed from the rings at once
+	var removed bool
+	tokens.release(token, func() { removed = true })
+	assert.True(t, removed)
+	assert.Nil(t, tokens.resume(token, "default", "agent1", "agent"))
+}
+
+func TestResumeHandler(t *testing.T) {
+	a := &Agentd{resumeTokens: newResumeTokens(time.Minute)}
+	token := a.resumeTokens.issue(SessionConfig{Namespace: "default", AgentName: "agent1", User: "agent"})
+	a.resumeTokens.release(token, func() {})
+
+	var resumed *resumption
+	var served bool
+	handler := a.resumeHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+		resumed, _ = r.Context().Value(resumedSessionKey{}).(*resumption)
+	}))
+	newRequest := func(token, user string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(transport.HeaderKeyNamespace, "default")
+		req.Header.Set(transport.HeaderKeyAgentName, "agent1")
+		req.Header.Set(transport.HeaderKeyUser, user)
+		if token != "" {
+			req.Header.Set(transport.HeaderKeyResumeToken, token)
+		}
+		return req
+	}
+
+	// The agents without a valid token open a new session
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest("", "agent"))
+	assert.True(t, served)
+	assert.Nil(t, resumed)
+	served = false
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest("invalid", "agent"))
+	assert.True(t, served)
+	assert.Nil(t, resumed)
+
+	// The token is only valid for the user of the session
+	served = false
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest(token, "someone"))
+	assert.True(t, served)
+	assert.Nil(t, resumed)
+
+	// The agents with a valid token resume their session
+	served = false
+	handler.ServeHTTP(httptest.NewRecorder(), newRequest(token, "agent"))
+	assert.True(t, served)
+	require.NotNil(t, resumed)
+	assert.Equal(t, "agent", resumed.user)
+}
+
+func TestResumeHandlerDisabled(t *testing.T) {
+	a := &Agentd{}
+	var served bool
+	handler := a.resumeHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(transport.HeaderKeyResumeToken, "token")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, served)
+}
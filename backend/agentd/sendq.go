@@ -0,0 +1,244 @@
+package agentd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultSendQueueSize is the default number of messages buffered for
+	// each agent session.
+	DefaultSendQueueSize = 10
+
+	// OverflowPolicyBlock blocks the sender until the send queue has room for
+	// the message, or until the send queue timeout expires and the message is
+	// dropped.
+	OverflowPolicyBlock = "block"
+
+	// OverflowPolicyDropOldest drops the oldest message of a full send queue
+	// to make room for the new one.
+	OverflowPolicyDropOldest = "drop-oldest"
+
+	// OverflowPolicyDropNewest drops the messages sent to a full send queue.
+	OverflowPolicyDropNewest = "drop-newest"
+
+	// DefaultSendQueuePriorityWindow is the default time before their deadline
+	// from which the check requests are sent ahead of the other messages.
+	DefaultSendQueuePriorityWindow = 5 * time.Second
+
+	// PriorityHigh is the priority of the time-sensitive messages, sent ahead
+	// of the other messages.
+	PriorityHigh = "high"
+
+	// PriorityNormal is the priority of the other messages.
+	PriorityNormal = "normal"
+)
+
+var (
+	sendQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sensu_go_agent_send_queue_depth",
+			Help: "Number of messages waiting to be sent to an agent",
+		},
+		[]string{"namespace", "agent", "priority"},
+	)
+
+	sendQueueDrops = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_send_queue_drops_total",
+			Help: "Number of messages dropped by the send queue of an agent",
+		},
+		[]string{"namespace", "agent", "priority"},
+	)
+
+	sendQueueMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_send_queue_messages_total",
+			Help: "Number of messages sent to an agent by its send queue",
+		},
+		[]string{"namespace", "agent", "priority"},
+	)
+)
+
+// SendQueueConfig configures the queue of the messages sent to an agent by
+// its session. The messages of high priority are queued in a separate lane,
+// emptied before the lane of the other messages.
+type SendQueueConfig struct {
+	// Size is the number of messages buffered by each lane of the queue.
+	Size int
+
+	// OverflowPolicy is the policy applied to the messages sent to a full
+	// queue: OverflowPolicyBlock, OverflowPolicyDropOldest or
+	// OverflowPolicyDropNewest.
+	OverflowPolicy string
+
+	// Timeout is the time a message waits for room in a full queue, with the
+	// OverflowPolicyBlock policy, before being dropped. The message waits
+	// indefinitely if Timeout is 0.
+	Timeout time.Duration
+
+	// PriorityWindow is the time before their deadline, the time of the next
+	// execution of their check, from which the check requests are sent with
+	// high priority. Only the overdue check requests are if PriorityWindow is
+	// 0.
+	PriorityWindow time.Duration
+}
+
+// Validate returns an error if the send queue configuration is invalid.
+func (c SendQueueConfig) Validate() error {
+	if c.Size < 1 {
+		return fmt.Errorf("invalid send queue size %d, must be at least 1", c.Size)
+	}
+	switch c.OverflowPolicy {
+	case OverflowPolicyBlock, OverflowPolicyDropOldest, OverflowPolicyDropNewest:
+	default:
+		return fmt.Errorf(
+			"invalid send queue overflow policy %q, must be one of %q, %q or %q",
+			c.OverflowPolicy, OverflowPolicyBlock, OverflowPolicyDropOldest, OverflowPolicyDropNewest,
+		)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("invalid send queue timeout %s, must not be negative", c.Timeout)
+	}
+	if c.PriorityWindow < 0 {
+		return fmt.Errorf("invalid send queue priority window %s, must not be negative", c.PriorityWindow)
+	}
+	return nil
+}
+
+// withDefaults returns the configuration, with the default queue size and
+// overflow policy if unset.
+func (c SendQueueConfig) withDefaults() SendQueueConfig {
+	if c.Size == 0 {
+		c.Size = DefaultSendQueueSize
+	}
+	if c.OverflowPolicy == "" {
+		c.OverflowPolicy = OverflowPolicyBlock
+	}
+	return c
+}
+
+// checkRequestPriority returns the priority of the check request: high if its
+// deadline, the time of the next execution of its check, is within the
+// priority window.
+func (c SendQueueConfig) checkRequestPriority(request *corev2.CheckRequest, now time.Time) string {
+	if request.Config == nil {
+		return PriorityNormal
+	}
+	deadline := time.Unix(request.Issued, 0).Add(time.Duration(request.Config.Interval) * time.Second)
+	if deadline.Sub(now) <= c.PriorityWindow {
+		return PriorityHigh
+	}
+	return PriorityNormal
+}
+
+// lane returns the lane of the send queue of the messages of the priority.
+func (s *Session) lane(priority string) chan *transport.Message {
+	if priority == PriorityHigh {
+		return s.prioq
+	}
+	return s.sendq
+}
+
+// enqueue adds the message to the lane of its priority in the send queue,
+// applying the overflow policy if the lane is full. It returns false if the
+// session is stopping.
+func (s *Session) enqueue(msg *transport.Message, priority string) bool {
+	defer s.updateSendQueueDepth()
+	lane := s.lane(priority)
+
+	select {
+	case lane <- msg:
+		return true
+	default:
+	}
+
+	switch s.cfg.SendQueue.OverflowPolicy {
+	case OverflowPolicyDropNewest:
+		s.dropMessage(msg, priority)
+	case OverflowPolicyDropOldest:
+		// Only subPump sends to the queue, so the loop ends as soon as sendPump
+		// or this loop makes room for the message.
+		for {
+			select {
+			case lane <- msg:
+				return true
+			default:
+			}
+			select {
+			case oldest := <-lane:
+				s.dropMessage(oldest, priority)
+			default:
+			}
+		}
+	default:
+		var timeout <-chan time.Time
+		if s.cfg.SendQueue.Timeout > 0 {
+			timer := time.NewTimer(s.cfg.SendQueue.Timeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case lane <- msg:
+		case <-timeout:
+			s.dropMessage(msg, priority)
+		case <-s.stopping:
+			return false
+		}
+	}
+
+	return true
+}
+
+// dequeue returns the next message of the send queue, and its priority,
+// without waiting. The messages of high priority are returned first.
+func (s *Session) dequeue() (*transport.Message, string) {
+	select {
+	case msg := <-s.prioq:
+		return msg, PriorityHigh
+	default:
+	}
+	select {
+	case msg := <-s.sendq:
+		return msg, PriorityNormal
+	default:
+	}
+	return nil, ""
+}
+
+// dropMessage records a message dropped by the send queue.
+func (s *Session) dropMessage(msg *transport.Message, priority string) {
+	sendQueueDrops.WithLabelValues(s.cfg.Namespace, s.cfg.AgentName, priority).Inc()
+	logger.WithFields(logrus.Fields{
+		"namespace": s.cfg.Namespace,
+		"agent":     s.cfg.AgentName,
+		"type":      msg.Type,
+		"priority":  priority,
+		"policy":    s.cfg.SendQueue.OverflowPolicy,
+	}).Warn("send queue full, dropping message")
+}
+
+// messageSent records a message sent by the send queue.
+func (s *Session) messageSent(priority string) {
+	sendQueueMessages.WithLabelValues(s.cfg.Namespace, s.cfg.AgentName, priority).Inc()
+}
+
+// updateSendQueueDepth records the number of messages in the send queue.
+func (s *Session) updateSendQueueDepth() {
+	sendQueueDepth.WithLabelValues(s.cfg.Namespace, s.cfg.AgentName, PriorityHigh).Set(float64(len(s.prioq)))
+	sendQueueDepth.WithLabelValues(s.cfg.Namespace, s.cfg.AgentName, PriorityNormal).Set(float64(len(s.sendq)))
+}
+
+// deleteSendQueueMetrics removes the send queue metrics of a stopped session.
+func (s *Session) deleteSendQueueMetrics() {
+	for _, priority := range []string{PriorityHigh, PriorityNormal} {
+		sendQueueDepth.DeleteLabelValues(s.cfg.Namespace, s.cfg.AgentName, priority)
+		sendQueueDrops.DeleteLabelValues(s.cfg.Namespace, s.cfg.AgentName, priority)
+		sendQueueMessages.DeleteLabelValues(s.cfg.Namespace, s.cfg.AgentName, priority)
+	}
+}
@@ -0,0 +1,226 @@
+package agentd
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSendQueueSession(cfg SendQueueConfig) *Session {
+	sessionCfg := SessionConfig{
+		Namespace: "default",
+		AgentName: "sendq",
+		SendQueue: cfg,
+	}
+	return &Session{
+		cfg:      sessionCfg,
+		sendq:    make(chan *transport.Message, cfg.Size),
+		prioq:    make(chan *transport.Message, cfg.Size),
+		stopping: make(chan struct{}),
+		metrics:  newSessionMetrics(sessionCfg),
+	}
+}
+
+func sendQueueDropCount(t *testing.T) float64 {
+	var metric dto.Metric
+	require.NoError(t, sendQueueDrops.WithLabelValues("default", "sendq", PriorityNormal).Write(&metric))
+	return metric.GetCounter().GetValue()
+}
+
+func sendQueueDepthValue(t *testing.T) float64 {
+	var metric dto.Metric
+	require.NoError(t, sendQueueDepth.WithLabelValues("default", "sendq", PriorityNormal).Write(&metric))
+	return metric.GetGauge().GetValue()
+}
+
+func TestSendQueueConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SendQueueConfig
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			cfg:  SendQueueConfig{}.withDefaults(),
+		},
+		{
+			name: "drop oldest",
+			cfg:  SendQueueConfig{Size: 100, OverflowPolicy: OverflowPolicyDropOldest},
+		},
+		{
+			name: "block with timeout",
+			cfg:  SendQueueConfig{Size: 1, OverflowPolicy: OverflowPolicyBlock, Timeout: time.Second},
+		},
+		{
+			name:    "invalid size",
+			cfg:     SendQueueConfig{Size: 0, OverflowPolicy: OverflowPolicyBlock},
+			wantErr: true,
+		},
+		{
+			name:    "invalid policy",
+			cfg:     SendQueueConfig{Size: 10, OverflowPolicy: "drop-everything"},
+			wantErr: true,
+		},
+		{
+			name:    "negative timeout",
+			cfg:     SendQueueConfig{Size: 10, OverflowPolicy: OverflowPolicyBlock, Timeout: -time.Second},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSendQueueDropNewest(t *testing.T) {
+	s := newSendQueueSession(SendQueueConfig{Size: 1, OverflowPolicy: OverflowPolicyDropNewest})
+	defer s.deleteSendQueueMetrics()
+
+	assert.True(t, s.enqueue(transport.NewMessage("first", nil), PriorityNormal))
+	assert.True(t, s.enqueue(transport.NewMessage("second", nil), PriorityNormal))
+
+	assert.Equal(t, "first", (<-s.sendq).Type)
+	assert.Equal(t, float64(1), sendQueueDropCount(t))
+}
+
+func TestSendQueueDropOldest(t *testing.T) {
+	s := newSendQueueSession(SendQueueConfig{Size: 2, OverflowPolicy: OverflowPolicyDropOldest})
+	defer s.deleteSendQueueMetrics()
+
+	assert.True(t, s.enqueue(transport.NewMessage("first", nil), PriorityNormal))
+	assert.True(t, s.enqueue(transport.NewMessage("second", nil), PriorityNormal))
+	assert.True(t, s.enqueue(transport.NewMessage("third", nil), PriorityNormal))
+	assert.Equal(t, float64(2), sendQueueDepthValue(t))
+
+	assert.Equal(t, "second", (<-s.sendq).Type)
+	assert.Equal(t, "third", (<-s.sendq).Type)
+	assert.Equal(t, float64(1), sendQueueDropCount(t))
+}
+
+func TestSendQueueBlockTimeout(t *testing.T) {
+	s := newSendQueueSession(SendQueueConfig{Size: 1, OverflowPolicy: OverflowPolicyBlock, Timeout: 10 * time.Millisecond})
+	defer s.deleteSendQueueMetrics()
+
+	assert.True(t, s.enqueue(transport.NewMessage("first", nil), PriorityNormal))
+	assert.True(t, s.enqueue(transport.NewMessage("second", nil), PriorityNormal))
+
+	assert.Equal(t, "first", (<-s.sendq).Type)
+	assert.Equal(t, float64(1), sendQueueDropCount(t))
+}
+
+func TestSendQueueBlock(t *testing.T) {
+	s := newSendQueueSession(SendQueueConfig{Size: 1, OverflowPolicy: OverflowPolicyBlock})
+	defer s.deleteSendQueueMetrics()
+
+	require.True(t, s.enqueue(transport.NewMessage("first", nil), PriorityNormal))
+
+	done := make(chan bool)
+	go func() {
+		done <- s.enqueue(transport.NewMessage("second", nil), PriorityNormal)
+	}()
+
+	assert.Equal(t, "first", (<-s.sendq).Type)
+	assert.True(t, <-done)
+	assert.Equal(t, "second", (<-s.sendq).Type)
+	assert.Equal(t, float64(0), sendQueueDropCount(t))
+}
+
+func TestSendQueueBlockStopping(t *testing.T) {
+	s := newSendQueueSession(SendQueueConfig{Size: 1, OverflowPolicy: OverflowPolicyBlock})
+	defer s.deleteSendQueueMetrics()
+
+	require.True(t, s.enqueue(transport.NewMessage("first", nil), PriorityNormal))
+	close(s.stopping)
+	assert.False(t, s.enqueue(transport.NewMessage("second", nil), PriorityNormal))
+}
+
+func TestCheckRequestPriority(t *testing.T) {
+	cfg := SendQueueConfig{PriorityWindow: 5 * time.Second}
+	now := time.Unix(1000, 0)
+
+	request := &corev2.CheckRequest{Config: corev2.FixtureCheckConfig("check"), Issued: 1000}
+	request.Config.Interval = 60
+	assert.Equal(t, PriorityNormal, cfg.checkRequestPriority(request, now))
+
+	// Due within the priority window
+	request.Config.Interval = 5
+	assert.Equal(t, PriorityHigh, cfg.checkRequestPriority(request, now))
+
+	// Overdue
+	request.Config.Interval = 60
+	assert.Equal(t, PriorityHigh, SendQueueConfig{}.checkRequestPriority(request, now.Add(2*time.Minute)))
+
+	assert.Equal(t, PriorityNormal, cfg.checkRequestPriority(&corev2.CheckRequest{}, now))
+}
+
+func TestSendQueuePriorityLanes(t *testing.T) {
+	s := newSendQueueSession(SendQueueConfig{Size: 2, OverflowPolicy: OverflowPolicyDropNewest})
+	defer s.deleteSendQueueMetrics()
+
+	assert.True(t, s.enqueue(transport.NewMessage("bulk1", nil), PriorityNormal))
+	assert.True(t, s.enqueue(transport.NewMessage("bulk2", nil), PriorityNormal))
+	// The lanes overflow independently
+	assert.True(t, s.enqueue(transport.NewMessage("urgent", nil), PriorityHigh))
+
+	var depth dto.Metric
+	require.NoError(t, sendQueueDepth.WithLabelValues("default", "sendq", PriorityHigh).Write(&depth))
+	assert.Equal(t, float64(1), depth.GetGauge().GetValue())
+
+	var order []string
+	for {
+		msg, _ := s.dequeue()
+		if msg == nil {
+			break
+		}
+		order = append(order, msg.Type)
+	}
+	assert.Equal(t, []string{"urgent", "bulk1", "bulk2"}, order)
+	assert.Equal(t, float64(0), sendQueueDropCount(t))
+}
+
+func TestSendPumpPriority(t *testing.T) {
+	s := newSendQueueSession(SendQueueConfig{Size: 2, OverflowPolicy: OverflowPolicyBlock})
+	defer s.deleteSendQueueMetrics()
+	conn := &recordingTransport{sent: make(chan *transport.Message, 3)}
+	s.conn = conn
+	s.drain = make(chan struct{}, 1)
+	s.wg = &sync.WaitGroup{}
+
+	require.True(t, s.enqueue(transport.NewMessage("bulk", nil), PriorityNormal))
+	require.True(t, s.enqueue(transport.NewMessage("urgent", nil), PriorityHigh))
+
+	s.wg.Add(1)
+	go s.sendPump()
+	assert.Equal(t, "urgent", (<-conn.sent).Type)
+	assert.Equal(t, "bulk", (<-conn.sent).Type)
+	close(s.stopping)
+	s.wg.Wait()
+
+	var sent dto.Metric
+	require.NoError(t, sendQueueMessages.WithLabelValues("default", "sendq", PriorityHigh).Write(&sent))
+	assert.Equal(t, float64(1), sent.GetCounter().GetValue())
+}
+
+// recordingTransport records the messages sent through it.
+type recordingTransport struct {
+	transport.Transport
+	sent chan *transport.Message
+}
+
+func (r *recordingTransport) Send(msg *transport.Message) error {
+	r.sent <- msg
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,6 +15,7 @@ import (
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/usage"
 	"github.com/sensu/sensu-go/handler"
 	"github.com/sensu/sensu-go/transport"
 	"github.com/sirupsen/logrus"
@@ -26,6 +29,14 @@ var (
 		},
 		[]string{"namespace"},
 	)
+
+	protocolVersionSessions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sensu_go_agent_sessions_by_protocol_version",
+			Help: "Number of active agent sessions on this backend by protocol version",
+		},
+		[]string{"protocol_version"},
+	)
 )
 
 // ProtobufSerializationHeader is the Content-Type header which indicates protobuf serialization.
@@ -48,6 +59,8 @@ func MarshalJSON(msg proto.Message) ([]byte, error) { return json.Marshal(msg) }
 
 // SessionStore specifies the storage requirements of the Session.
 type SessionStore interface {
+	store.AgentConfigStore
+	store.CheckExecutionStore
 	store.EntityStore
 	store.NamespaceStore
 }
@@ -65,6 +78,9 @@ type Session struct {
 	stopping     chan struct{}
 	wg           *sync.WaitGroup
 	sendq        chan *transport.Message
+	prioq        chan *transport.Message
+	publishq     chan *pendingPublish
+	drain        chan struct{}
 	checkChannel chan interface{}
 	bus          messaging.MessageBus
 	ringPool     *ringv2.Pool
@@ -72,14 +88,34 @@ type Session struct {
 	cancel       context.CancelFunc
 	marshal      MarshalFunc
 	unmarshal    UnmarshalFunc
+	metrics      *sessionMetrics
+
+	// skewed is true if the clock of the agent was skewed at its last
+	// keepalive or event. It is only accessed by the receive pump.
+	skewed bool
+
+	// executions are the execution slots held by the agent, and the check
+	// requests waiting for one. They are only accessed by executionPump.
+	executions *checkExecutions
+	executionq chan *corev2.CheckRequest
+	acquiredq  chan *corev2.CheckRequest
+	releaseq   chan string
 
 	subscriptions chan messaging.Subscription
+
+	// entity is the entity of the last keepalive of the agent, the builds of
+	// the assets of its check requests are selected against it.
+	entity   *corev2.Entity
+	entityMu sync.Mutex
 }
 
 func newSessionHandler(s *Session) *handler.MessageHandler {
 	handler := handler.NewMessageHandler()
 	handler.AddHandler(transport.MessageTypeKeepalive, s.handleKeepalive)
 	handler.AddHandler(transport.MessageTypeEvent, s.handleEvent)
+	handler.AddHandler(transport.MessageTypeAckedEvent, s.handleAckedEvent)
+	handler.AddHandler(corev2.AgentSpoolResponseType, s.handleSpoolResponse)
+	handler.AddHandler(corev2.AgentCommandResponseType, s.handleCommandResponse)
 
 	return handler
 }
@@ -94,6 +130,52 @@ type SessionConfig struct {
 	User          string
 	Subscriptions []string
 	RingPool      *ringv2.Pool
+
+	// ProtocolVersion is the version of the agent protocol negotiated with the
+	// agent. The messages of the features it does not support are not sent to
+	// the agent. Zero means the latest version.
+	ProtocolVersion int
+
+	// SendQueue configures the queue of the messages sent to the agent.
+	SendQueue SendQueueConfig
+
+	// AgentMetrics labels the throughput metrics of the session with the
+	// name of the agent, in addition to its namespace.
+	AgentMetrics bool
+
+	// ClockSkew configures the detection of the clock skew of the agent.
+	ClockSkew ClockSkewConfig
+
+	// MessageSize configures the maximum size of the messages of the agent.
+	MessageSize MessageSizeConfig
+
+	// PublishBuffer configures the buffer of the messages of the agent that
+	// the message bus failed to publish.
+	PublishBuffer PublishBufferConfig
+
+	// Ping configures the pings sent to the agent over WebSocket. Its
+	// MissedPong callback is set by the session.
+	Ping transport.PingConfig
+
+	// Usage records the events submitted by the agent, if not nil.
+	Usage *usage.Tracker
+
+	// ResumeToken is the token the agent can resume the session with once it
+	// stops. The agent is not removed from the rings of its subscriptions
+	// when the session stops if set, but once the token expires.
+	ResumeToken string
+
+	// Resumed is true if the agent resumed a stopped session with its resume
+	// token. The namespace of the agent is not validated again.
+	Resumed bool
+
+	// HeartbeatOnly never sends checks or remote commands to the agent, and
+	// reduces the subscriptions of its entity to the entity subscription.
+	HeartbeatOnly bool
+
+	// RejectSpoofedEvents rejects the events of the agent for entities other
+	// than itself and the proxy entities declared by their checks.
+	RejectSpoofedEvents bool
 }
 
 // NewSession creates a new Session object given the triple of a transport
@@ -103,11 +185,39 @@ type SessionConfig struct {
 func NewSession(cfg SessionConfig, conn transport.Transport, bus messaging.MessageBus, store store.Store, unmarshal UnmarshalFunc, marshal MarshalFunc) (*Session, error) {
 	// Validate the agent namespace
 	ctx, cancel := context.WithCancel(context.Background())
-	if _, err := store.GetNamespace(ctx, cfg.Namespace); err != nil {
+	if !cfg.Resumed {
+		if _, err := store.GetNamespace(ctx, cfg.Namespace); err != nil {
+			defer cancel()
+			return nil, fmt.Errorf(
+				"could not retrieve the namespace '%s': %s", cfg.Namespace, err.Error(),
+			)
+		}
+	}
+
+	if cfg.ProtocolVersion == 0 {
+		cfg.ProtocolVersion = transport.ProtocolVersion
+	}
+	cfg.SendQueue = cfg.SendQueue.withDefaults()
+	if err := cfg.SendQueue.Validate(); err != nil {
+		defer cancel()
+		return nil, err
+	}
+	if err := cfg.ClockSkew.Validate(); err != nil {
+		defer cancel()
+		return nil, err
+	}
+	cfg.MessageSize = cfg.MessageSize.withDefaults()
+	if err := cfg.MessageSize.Validate(); err != nil {
 		defer cancel()
-		return nil, fmt.Errorf(
-			"could not retrieve the namespace '%s': %s", cfg.Namespace, err.Error(),
-		)
+		return nil, err
+	}
+	if err := cfg.PublishBuffer.Validate(); err != nil {
+		defer cancel()
+		return nil, err
+	}
+	if err := cfg.Ping.Validate(); err != nil {
+		defer cancel()
+		return nil, err
 	}
 
 	logger.WithFields(logrus.Fields{
@@ -122,7 +232,10 @@ func NewSession(cfg SessionConfig, conn transport.Transport, bus messaging.Messa
 		cfg:           cfg,
 		stopping:      make(chan struct{}, 1),
 		wg:            &sync.WaitGroup{},
-		sendq:         make(chan *transport.Message, 10),
+		sendq:         make(chan *transport.Message, cfg.SendQueue.Size),
+		prioq:         make(chan *transport.Message, cfg.SendQueue.Size),
+		publishq:      make(chan *pendingPublish, cfg.PublishBuffer.Size),
+		drain:         make(chan struct{}, 1),
 		checkChannel:  make(chan interface{}, 100),
 		store:         store,
 		bus:           bus,
@@ -132,6 +245,11 @@ func NewSession(cfg SessionConfig, conn transport.Transport, bus messaging.Messa
 		ringPool:      cfg.RingPool,
 		unmarshal:     unmarshal,
 		marshal:       marshal,
+		metrics:       newSessionMetrics(cfg),
+		executions:    newCheckExecutions(),
+		executionq:    make(chan *corev2.CheckRequest, checkExecutionQueueSize),
+		acquiredq:     make(chan *corev2.CheckRequest, checkExecutionQueueSize),
+		releaseq:      make(chan string, checkExecutionQueueSize),
 	}
 	s.handler = newSessionHandler(s)
 	return s, nil
@@ -178,7 +296,12 @@ func (s *Session) recvPump() {
 				continue
 			}
 		}
+		s.metrics.received(msg)
+		if !s.checkMessageSize(msg) {
+			continue
+		}
 		if err := s.handler.Handle(ctx, msg.Type, msg.Payload); err != nil {
+			s.metrics.handlerError(msg.Type)
 			logger.WithError(err).WithFields(logrus.Fields{
 				"type":    msg.Type,
 				"payload": string(msg.Payload)}).Error("error handling message")
@@ -195,26 +318,113 @@ func (s *Session) subPump() {
 	for {
 		select {
 		case c := <-s.checkChannel:
-			request, ok := c.(*corev2.CheckRequest)
-			if !ok {
+			var msg *transport.Message
+			priority := PriorityNormal
+			switch request := c.(type) {
+			case *corev2.CheckRequest:
+				if s.cfg.HeartbeatOnly {
+					logger.WithFields(logrus.Fields{
+						"agent": s.cfg.AgentName,
+						"check": request.Config.Name,
+					}).Debug("discarding check request, the agent runs in heartbeat-only mode")
+					continue
+				}
+				if limited(request) {
+					// The execution pump sends the request back once it
+					// acquired an execution slot
+					select {
+					case s.executionq <- request:
+					case <-s.stopping:
+						return
+					}
+					continue
+				}
+				if !s.sendCheckRequest(request) {
+					return
+				}
+				continue
+			case *corev2.AgentSpoolRequest:
+				// Spool requests are always serialized as JSON
+				requestBytes, err := json.Marshal(request)
+				if err != nil {
+					logger.WithError(err).Error("session failed to serialize spool request")
+					continue
+				}
+				msg = transport.NewMessage(corev2.AgentSpoolRequestType, requestBytes)
+			case *corev2.AgentCommandRequest:
+				if s.cfg.HeartbeatOnly {
+					s.rejectCommandRequest(request, "the agent runs in heartbeat-only mode")
+					continue
+				}
+				// Command requests are always serialized as JSON
+				requestBytes, err := json.Marshal(request)
+				if err != nil {
+					logger.WithError(err).Error("session failed to serialize command request")
+					continue
+				}
+				msg = transport.NewMessage(corev2.AgentCommandRequestType, requestBytes)
+			case *corev2.AgentConfig:
+				if !s.supports(transport.FeatureAgentConfig) {
+					continue
+				}
+				// Agent configs are always serialized as JSON
+				configBytes, err := json.Marshal(request)
+				if err != nil {
+					logger.WithError(err).Error("session failed to serialize agent config")
+					continue
+				}
+				msg = transport.NewMessage(corev2.AgentConfigType, configBytes)
+				priority = PriorityHigh
+			case *transport.EventAck:
+				// Event acks are always serialized as JSON
+				ackBytes, err := json.Marshal(request)
+				if err != nil {
+					logger.WithError(err).Error("session failed to serialize event ack")
+					continue
+				}
+				msg = transport.NewMessage(transport.MessageTypeEventAck, ackBytes)
+				priority = PriorityHigh
+			case *transport.MessageRejection:
+				// Message rejections are always serialized as JSON
+				rejectionBytes, err := json.Marshal(request)
+				if err != nil {
+					logger.WithError(err).Error("session failed to serialize message rejection")
+					continue
+				}
+				msg = transport.NewMessage(transport.MessageTypeMessageRejected, rejectionBytes)
+				priority = PriorityHigh
+			default:
 				logger.Error("session received non-config over check channel")
 				continue
 			}
 
-			configBytes, err := s.marshal(request)
-			if err != nil {
-				logger.WithError(err).Error("session failed to serialize check request")
-				continue
+			if !s.enqueue(msg, priority) {
+				return
+			}
+		case request := <-s.acquiredq:
+			// The request holds an execution slot of its check
+			if !s.sendCheckRequest(request) {
+				return
 			}
-
-			msg := transport.NewMessage(corev2.CheckRequestType, configBytes)
-			s.sendq <- msg
 		case <-s.stopping:
 			return
 		}
 	}
 }
 
+// sendCheckRequest queues the check request for the agent. It returns false if
+// the session is stopping.
+func (s *Session) sendCheckRequest(request *corev2.CheckRequest) bool {
+	request = s.selectAssetBuilds(request)
+	configBytes, err := s.marshal(request)
+	if err != nil {
+		logger.WithError(err).Error("session failed to serialize check request")
+		return true
+	}
+	msg := transport.NewMessage(corev2.CheckRequestType, configBytes)
+	return s.enqueue(msg, s.cfg.SendQueue.checkRequestPriority(request, time.Now()))
+}
+
 func (s *Session) sendPump() {
 	defer func() {
 		s.wg.Done()
@@ -223,20 +433,48 @@ func (s *Session) sendPump() {
 
 	for {
 		select {
-		case msg := <-s.sendq:
-			logger.WithField("payload_size", len(msg.Payload)).Debug("session - sending message")
-			err := s.conn.Send(msg)
-			if err != nil {
-				switch err := err.(type) {
-				case transport.ConnectionError, transport.ClosedError:
-					return
-				default:
-					logger.WithError(err).Error("send error")
-				}
+		case <-s.drain:
+			if err := s.sendReconnect(); err != nil {
+				logger.WithError(err).Error("send error")
 			}
+			continue
 		case <-s.stopping:
 			return
+		default:
+		}
+
+		// The messages of high priority are sent first, then whichever message
+		// comes first
+		msg, priority := s.dequeue()
+		if msg == nil {
+			select {
+			case msg = <-s.prioq:
+				priority = PriorityHigh
+			case msg = <-s.sendq:
+				priority = PriorityNormal
+			case <-s.drain:
+				if err := s.sendReconnect(); err != nil {
+					logger.WithError(err).Error("send error")
+				}
+				continue
+			case <-s.stopping:
+				return
+			}
+		}
+
+		s.updateSendQueueDepth()
+		logger.WithField("payload_size", len(msg.Payload)).Debug("session - sending message")
+		if err := s.conn.Send(msg); err != nil {
+			switch err := err.(type) {
+			case transport.ConnectionError, transport.ClosedError:
+				return
+			default:
+				logger.WithError(err).Error("send error")
+			}
+			continue
 		}
+		s.messageSent(priority)
+		s.metrics.sent(msg)
 	}
 }
 
@@ -244,14 +482,26 @@ func (s *Session) sendPump() {
 // 1. Start send pump
 // 2. Start receive pump
 // 3. Start subscription pump
+// 4. Start publish and execution pumps
 // 5. Ensure bus unsubscribe when the session shuts down.
 func (s *Session) Start() (err error) {
 	sessionCounter.WithLabelValues(s.cfg.Namespace).Inc()
+	protocolVersionSessions.WithLabelValues(strconv.Itoa(s.cfg.ProtocolVersion)).Inc()
 	s.wg = &sync.WaitGroup{}
-	s.wg.Add(3)
+	s.wg.Add(5)
 	go s.sendPump()
 	go s.recvPump()
 	go s.subPump()
+	go s.publishPump()
+	go s.executionPump()
+
+	// The agents connected over gRPC are kept alive by the HTTP/2 pings
+	if ws, ok := s.conn.(*transport.WebSocketTransport); ok && s.cfg.Ping.Interval > 0 {
+		ping := s.cfg.Ping
+		ping.MissedPong = s.missedPong
+		ping.PongsLapsed = s.pongsLapsed
+		ws.Ping(s.ctx, ping)
+	}
 
 	namespace := s.cfg.Namespace
 	agentName := fmt.Sprintf("%s:%s", namespace, s.cfg.AgentName)
@@ -279,6 +529,9 @@ func (s *Session) Start() (err error) {
 	}
 	close(s.subscriptions)
 
+	// The stored config is pushed once subscribed, so that no update is missed
+	s.pushAgentConfig()
+
 	return nil
 }
 
@@ -286,6 +539,7 @@ func (s *Session) Start() (err error) {
 // shutdown. Blocks until the session has shutdown.
 func (s *Session) Stop() {
 	sessionCounter.WithLabelValues(s.cfg.Namespace).Dec()
+	protocolVersionSessions.WithLabelValues(strconv.Itoa(s.cfg.ProtocolVersion)).Dec()
 	defer s.cancel()
 	close(s.stopping)
 	s.wg.Wait()
@@ -296,18 +550,38 @@ func (s *Session) Stop() {
 		}
 	}
 	close(s.checkChannel)
-	for _, sub := range s.cfg.Subscriptions {
-		ring := s.ringPool.Get(ringv2.Path(s.cfg.Namespace, sub))
-		logger.WithFields(logrus.Fields{
-			"namespace": s.cfg.Namespace,
-			"agent":     s.cfg.AgentName,
-		}).Info("removing agent from ring")
-		if err := ring.Remove(s.ctx, s.cfg.AgentName); err != nil {
-			logger.WithError(err).Error("unable to remove agent from ring")
-		}
+	s.deleteSendQueueMetrics()
+	s.metrics.delete()
+	if s.cfg.ResumeToken == "" {
+		removeFromRings(s.ctx, s.ringPool, s.cfg.Namespace, s.cfg.AgentName, s.cfg.Subscriptions)
 	}
 }
 
+// supports returns true if the agent supports the feature of the protocol.
+func (s *Session) supports(feature transport.Feature) bool {
+	return transport.Supports(s.cfg.ProtocolVersion, feature)
+}
+
+// missedPong records a ping of the agent that was not answered before the
+// next one.
+func (s *Session) missedPong() {
+	s.metrics.missedPong()
+	logger.WithFields(logrus.Fields{
+		"namespace": s.cfg.Namespace,
+		"agent":     s.cfg.AgentName,
+	}).Warn("the agent did not answer the last ping")
+}
+
+// pongsLapsed records that the connection of the agent is closed because its
+// pongs lapsed, e.g. after a NAT timeout, so its session stops and leaves the
+// rings at once.
+func (s *Session) pongsLapsed() {
+	logger.WithFields(logrus.Fields{
+		"namespace": s.cfg.Namespace,
+		"agent":     s.cfg.AgentName,
+	}).Warn("the agent stopped answering the pings, closing its connection")
+}
+
 // handleKeepalive is the keepalive message handler.
 func (s *Session) handleKeepalive(ctx context.Context, payload []byte) error {
 	keepalive := &corev2.Event{}
@@ -325,34 +599,175 @@ func (s *Session) handleKeepalive(ctx context.Context, payload []byte) error {
 		return errors.New("keepalive contains invalid timestamp")
 	}
 
-	keepalive.Entity.Subscriptions = addEntitySubscription(keepalive.Entity.Name, keepalive.Entity.Subscriptions)
+	if err := s.verifyEventNamespace(keepalive); err != nil {
+		return err
+	}
+	if err := s.verifyEventEntity(keepalive); err != nil {
+		return err
+	}
+	keepalive.SetSource(corev2.EventSourceAgent, s.agentSource())
+
+	keepalive.Entity.Subscriptions = s.entitySubscriptions(keepalive.Entity)
+	s.checkClockSkew(keepalive, time.Now())
 
-	return s.bus.Publish(messaging.TopicKeepalive, keepalive)
+	s.entityMu.Lock()
+	s.entity = keepalive.Entity
+	s.entityMu.Unlock()
+
+	return s.publish(transport.MessageTypeKeepalive, messaging.TopicKeepalive, keepalive, "")
 }
 
 // handleEvent is the event message handler.
 func (s *Session) handleEvent(ctx context.Context, payload []byte) error {
+	return s.receiveEvent(ctx, payload, "")
+}
+
+// receiveEvent validates the event received from the agent and publishes it,
+// acknowledging it once published if ack is the ID of the event.
+func (s *Session) receiveEvent(ctx context.Context, payload []byte, ack string) error {
 	// Decode the payload to an event
 	event := &corev2.Event{}
 	if err := s.unmarshal(payload, event); err != nil {
-		return err
+		return rejectedEventError{err: err}
 	}
 
 	// Validate the received event
 	if err := event.Validate(); err != nil {
-		return err
+		return rejectedEventError{err: err}
+	}
+
+	if s.cfg.Usage != nil {
+		s.cfg.Usage.RecordEvent(s.cfg.User)
+	}
+
+	// The agent is only authorized for the namespace of the session
+	if err := s.verifyEventNamespace(event); err != nil {
+		return rejectedEventError{err: err}
 	}
 
+	s.checkClockSkew(event, time.Now())
+
 	// Verify if we have a source in the event and if so, use it as the entity by
 	// creating or retrieving it from the store
 	if event.HasCheck() {
+		s.checkExecuted(event.Check)
+		event.Check.Received = time.Now().Unix()
 		if err := getProxyEntity(event, s.store); err != nil {
 			return err
 		}
 	}
+	if err := s.verifyEventEntity(event); err != nil {
+		return rejectedEventError{err: err}
+	}
+	event.SetSource(corev2.EventSourceAgent, s.agentSource())
 
 	// Add the entity subscription to the subscriptions of this entity
-	event.Entity.Subscriptions = addEntitySubscription(event.Entity.Name, event.Entity.Subscriptions)
+	event.Entity.Subscriptions = s.entitySubscriptions(event.Entity)
+
+	return s.publish(transport.MessageTypeEvent, messaging.TopicEventRaw, event, ack)
+}
+
+// entitySubscriptions returns the subscriptions of an entity, with its entity
+// subscription. The subscriptions of the agent entity are reduced to its
+// entity subscription in heartbeat-only mode, so it joins no round robin ring.
+func (s *Session) entitySubscriptions(entity *corev2.Entity) []string {
+	if s.cfg.HeartbeatOnly && entity.Name == s.cfg.AgentName {
+		return addEntitySubscription(entity.Name, nil)
+	}
+	return addEntitySubscription(entity.Name, entity.Subscriptions)
+}
+
+// selectAssetBuilds returns the check request with the assets of the check
+// and of its hooks resolved to their builds matching the entity of the agent,
+// so that agents unaware of the builds get the right one. The assets without
+// a matching build are dropped. The request is returned as is until the agent
+// sends its first keepalive.
+func (s *Session) selectAssetBuilds(request *corev2.CheckRequest) *corev2.CheckRequest {
+	s.entityMu.Lock()
+	entity := s.entity
+	s.entityMu.Unlock()
+	if entity == nil {
+		return request
+	}
+
+	// The request is shared with the other sessions by the message bus
+	resolved := *request
+	resolved.Assets = s.selectBuilds(request.Assets, entity)
+	if len(request.HookAssets) > 0 {
+		resolved.HookAssets = make(map[string]*corev2.AssetList, len(request.HookAssets))
+		for hook, list := range request.HookAssets {
+			if list == nil {
+				continue
+			}
+			resolved.HookAssets[hook] = &corev2.AssetList{Assets: s.selectBuilds(list.Assets, entity)}
+		}
+	}
+	return &resolved
+}
+
+// selectBuilds resolves the assets with builds to their build matching the
+// entity. An asset whose builds fail to be evaluated is left to the agent.
+func (s *Session) selectBuilds(assets []corev2.Asset, entity *corev2.Entity) []corev2.Asset {
+	if len(assets) == 0 {
+		return assets
+	}
+	selected := make([]corev2.Asset, 0, len(assets))
+	for i := range assets {
+		build, err := assets[i].SelectBuild(entity)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"agent": s.cfg.AgentName,
+				"asset": assets[i].Name,
+			}).WithError(err).Error("error selecting the asset build")
+			selected = append(selected, assets[i])
+			continue
+		}
+		if build == nil {
+			logger.WithFields(logrus.Fields{
+				"agent": s.cfg.AgentName,
+				"asset": assets[i].Name,
+			}).Debug("no asset build matching the entity of the agent")
+			continue
+		}
+		selected = append(selected, *build)
+	}
+	return selected
+}
+
+// rejectCommandRequest answers a command request on behalf of the agent,
+// without sending it to the agent.
+func (s *Session) rejectCommandRequest(request *corev2.AgentCommandRequest, reason string) {
+	logger.WithFields(logrus.Fields{
+		"agent": s.cfg.AgentName,
+		"id":    request.ID,
+	}).Warn("rejecting command request: ", reason)
+	response := &corev2.AgentCommandResponse{
+		ID:    request.ID,
+		Error: "command denied, " + reason,
+	}
+	if err := s.bus.Publish(messaging.AgentCommandTopic(request.ID), response); err != nil {
+		logger.WithError(err).Error("session failed to publish command response")
+	}
+}
+
+// handleSpoolResponse is the spool response message handler. It publishes the
+// response for the API request that's waiting for it.
+func (s *Session) handleSpoolResponse(ctx context.Context, payload []byte) error {
+	var response corev2.AgentSpoolResponse
+	if err := json.Unmarshal(payload, &response); err != nil {
+		return err
+	}
+
+	return s.bus.Publish(messaging.AgentSpoolTopic(response.ID), &response)
+}
+
+// handleCommandResponse is the command response message handler. It publishes
+// the response for the API request that's waiting for it.
+func (s *Session) handleCommandResponse(ctx context.Context, payload []byte) error {
+	var response corev2.AgentCommandResponse
+	if err := json.Unmarshal(payload, &response); err != nil {
+		return err
+	}
 
-	return s.bus.Publish(messaging.TopicEventRaw, event)
+	return s.bus.Publish(messaging.AgentCommandTopic(response.ID), &response)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
@@ -32,7 +33,7 @@ func (t *testTransport) Close() error {
 	return nil
 }
 
-func (t *testTransport) Heartbeat(ctx context.Context, interval, timeout int) {}
+func (t *testTransport) Heartbeat(ctx context.Context, interval, timeout, maxMissed int) {}
 
 func (t *testTransport) Reconnect(wsServerURL string, tlsOpts *corev2.TLSOptions, requestHeader http.Header) error {
 	return nil
@@ -133,3 +134,100 @@ func TestBadSessionConfig(t *testing.T) {
 	assert.Nil(t, session)
 	assert.Error(t, err)
 }
+
+func TestSessionHeartbeatOnly(t *testing.T) {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+
+	st := &mockstore.MockStore{}
+	st.On("GetNamespace", mock.Anything, "acme").Return(&corev2.Namespace{}, nil)
+
+	conn := &testTransport{sendCh: make(chan *transport.Message, 10)}
+	cfg := SessionConfig{
+		AgentName:     "testing",
+		Namespace:     "acme",
+		HeartbeatOnly: true,
+	}
+	session, err := NewSession(cfg, conn, bus, st, UnmarshalJSON, MarshalJSON)
+	require.NoError(t, err)
+
+	subscriber := messaging.ChannelSubscriber{Channel: make(chan interface{}, 1)}
+	subscription, err := bus.Subscribe(messaging.AgentCommandTopic("abc"), "testing", subscriber)
+	require.NoError(t, err)
+	defer func() { _ = subscription.Cancel() }()
+
+	session.wg.Add(1)
+	go session.subPump()
+	defer close(session.stopping)
+
+	// The command requests are answered on behalf of the agent
+	session.checkChannel <- &corev2.AgentCommandRequest{ID: "abc"}
+	select {
+	case msg := <-subscriber.Channel:
+		response, ok := msg.(*corev2.AgentCommandResponse)
+		require.True(t, ok)
+		assert.Equal(t, "abc", response.ID)
+		assert.Contains(t, response.Error, "heartbeat-only")
+	case <-time.After(time.Second):
+		t.Fatal("the command request was not rejected")
+	}
+
+	// Neither the check requests nor the command requests are sent
+	session.checkChannel <- corev2.FixtureCheckRequest("check")
+	select {
+	case msg := <-session.sendq:
+		t.Fatalf("unexpected %s message", msg.Type)
+	case msg := <-session.prioq:
+		t.Fatalf("unexpected %s message", msg.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The agent entity is reduced to its entity subscription, unlike the
+	// proxy entities
+	entity := corev2.FixtureEntity("testing")
+	entity.Subscriptions = []string{"linux"}
+	assert.Equal(t, []string{"entity:testing"}, session.entitySubscriptions(entity))
+	proxy := corev2.FixtureEntity("proxy")
+	proxy.Subscriptions = []string{"linux"}
+	assert.Equal(t, []string{"linux", "entity:proxy"}, session.entitySubscriptions(proxy))
+}
+
+func TestSessionSelectAssetBuilds(t *testing.T) {
+	session := &Session{cfg: SessionConfig{AgentName: "testing"}}
+
+	asset := corev2.FixtureAsset("tool")
+	asset.URL, asset.Sha512 = "", ""
+	asset.Builds = []*corev2.AssetBuild{
+		{URL: "https://example.com/tool-musl.tar.gz", Sha512: "musl", Filters: []string{"entity.system.libc_type == 'musl'"}},
+		{URL: "https://example.com/tool-glibc.tar.gz", Sha512: "glibc", Filters: []string{"entity.system.libc_type == 'glibc'"}},
+	}
+	plain := corev2.FixtureAsset("plain")
+	request := corev2.FixtureCheckRequest("check")
+	request.Assets = []corev2.Asset{*asset, *plain}
+	request.HookAssets = map[string]*corev2.AssetList{"hook": {Assets: []corev2.Asset{*asset}}}
+
+	// The request is sent as is until the first keepalive of the agent
+	assert.Equal(t, request, session.selectAssetBuilds(request))
+
+	session.entity = corev2.FixtureEntity("testing")
+	session.entity.System.LibCType = "musl"
+	resolved := session.selectAssetBuilds(request)
+	require.Len(t, resolved.Assets, 2)
+	assert.Equal(t, "https://example.com/tool-musl.tar.gz", resolved.Assets[0].URL)
+	assert.Empty(t, resolved.Assets[0].Builds)
+	assert.Equal(t, plain.URL, resolved.Assets[1].URL)
+	require.Len(t, resolved.HookAssets["hook"].Assets, 1)
+	assert.Equal(t, "https://example.com/tool-musl.tar.gz", resolved.HookAssets["hook"].Assets[0].URL)
+
+	// The shared request is left untouched
+	assert.Len(t, request.Assets[0].Builds, 2)
+	assert.Len(t, request.HookAssets["hook"].Assets[0].Builds, 2)
+
+	// The assets without a matching build are dropped
+	session.entity.System.LibCType = ""
+	resolved = session.selectAssetBuilds(request)
+	require.Len(t, resolved.Assets, 1)
+	assert.Equal(t, "plain", resolved.Assets[0].Name)
+	assert.Empty(t, resolved.HookAssets["hook"].Assets)
+}
@@ -0,0 +1,124 @@
+package agentd
+
+import (
+	"fmt"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultClockSkewThreshold is the default clock skew from which the
+	// clock of an agent is considered skewed.
+	DefaultClockSkewThreshold = 30 * time.Second
+
+	// ClockSkewCheckName is the name of the check of the events reporting
+	// the agents whose clock is skewed.
+	ClockSkewCheckName = "agent-clock-skew"
+
+	// ClockSkewAnnotation is the annotation of the events of the agents whose
+	// clock is skewed, giving the clock skew measured by the backend.
+	ClockSkewAnnotation = "sensu.io/clock-skew"
+)
+
+// ClockSkewConfig configures the detection of the agents whose clock is
+// skewed, measured from the timestamps of their keepalives and events.
+type ClockSkewConfig struct {
+	// Threshold is the clock skew from which the clock of an agent is
+	// considered skewed. The detection is disabled if 0.
+	Threshold time.Duration
+
+	// Correct shifts the timestamps of the events of the agents whose clock
+	// is skewed to the time of the backend.
+	Correct bool
+}
+
+// Validate returns an error if the clock skew configuration is invalid.
+func (c ClockSkewConfig) Validate() error {
+	if c.Threshold < 0 {
+		return fmt.Errorf("invalid clock skew threshold %s, must not be negative", c.Threshold)
+	}
+	return nil
+}
+
+// checkClockSkew measures the clock skew of the agent from the timestamp of
+// its keepalive or event. The events of an agent whose clock is skewed are
+// annotated with the skew, and their timestamps are corrected if configured.
+// An event of the agent-clock-skew check is published when the clock of the
+// agent becomes skewed, and when it recovers.
+func (s *Session) checkClockSkew(event *corev2.Event, now time.Time) {
+	threshold := s.cfg.ClockSkew.Threshold
+	if threshold == 0 || event.Timestamp == 0 || event.Entity == nil {
+		return
+	}
+
+	skew := time.Unix(event.Timestamp, 0).Sub(now)
+	skewed := skew >= threshold || skew <= -threshold
+	if skewed {
+		if event.Annotations == nil {
+			event.Annotations = make(map[string]string)
+		}
+		event.Annotations[ClockSkewAnnotation] = skew.String()
+		if s.cfg.ClockSkew.Correct {
+			correctTimestamps(event, skew)
+		}
+	}
+
+	if skewed == s.skewed {
+		return
+	}
+	s.skewed = skewed
+	if err := s.bus.Publish(messaging.TopicEventRaw, clockSkewEvent(event.Entity, skew, threshold, skewed, now)); err != nil {
+		logger.WithError(err).Error("could not publish the clock skew event")
+	}
+}
+
+// correctTimestamps shifts the timestamps set by the agent by the skew.
+func correctTimestamps(event *corev2.Event, skew time.Duration) {
+	seconds := int64(skew.Round(time.Second) / time.Second)
+	event.Timestamp -= seconds
+	if event.Check != nil && event.Check.Executed != 0 {
+		event.Check.Executed -= seconds
+	}
+	if event.Entity.LastSeen != 0 {
+		event.Entity.LastSeen -= seconds
+	}
+}
+
+// clockSkewEvent returns the event reporting whether the clock of the entity
+// is skewed.
+func clockSkewEvent(entity *corev2.Entity, skew, threshold time.Duration, skewed bool, now time.Time) *corev2.Event {
+	check := &corev2.Check{
+		ObjectMeta: corev2.NewObjectMeta(ClockSkewCheckName, entity.Namespace),
+		Interval:   1,
+		Executed:   now.Unix(),
+		Issued:     now.Unix(),
+		Output:     fmt.Sprintf("Clock of %s is within %s of the backend", entity.Name, threshold),
+	}
+	if skewed {
+		direction := "ahead of"
+		if skew < 0 {
+			direction, skew = "behind", -skew
+		}
+		check.Status = 1
+		check.Output = fmt.Sprintf("Clock of %s is %s %s the backend (>= %s)", entity.Name, skew, direction, threshold)
+		logger.WithFields(logrus.Fields{
+			"namespace": entity.Namespace,
+			"agent":     entity.Name,
+			"skew":      skew.String(),
+		}).Warn("agent clock is skewed")
+	}
+
+	// The entity is shared with the event the skew was measured from
+	clone := *entity
+	event := &corev2.Event{
+		ObjectMeta: corev2.NewObjectMeta("", entity.Namespace),
+		Timestamp:  now.Unix(),
+		Entity:     &clone,
+		Check:      check,
+	}
+	event.SetSource(corev2.EventSourceBackend, "agentd")
+	return event
+}
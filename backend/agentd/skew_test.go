@@ -0,0 +1,133 @@
+package agentd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/testing/mockbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newSkewSession(cfg ClockSkewConfig) (*Session, *mockbus.MockBus) {
+	bus := &mockbus.MockBus{}
+	bus.On("Publish", mock.Anything, mock.Anything).Return(nil)
+	sessionCfg := SessionConfig{Namespace: "default", AgentName: "skewed", ClockSkew: cfg}
+	return &Session{
+		cfg:       sessionCfg,
+		bus:       bus,
+		unmarshal: proto.Unmarshal,
+		metrics:   newSessionMetrics(sessionCfg),
+	}, bus
+}
+
+// skewEvents returns the clock skew events published to the bus.
+func skewEvents(bus *mockbus.MockBus) []*corev2.Event {
+	var events []*corev2.Event
+	for _, call := range bus.Calls {
+		if call.Method != "Publish" {
+			continue
+		}
+		event, ok := call.Arguments.Get(1).(*corev2.Event)
+		if ok && event.Check != nil && event.Check.Name == ClockSkewCheckName {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestClockSkewConfigValidate(t *testing.T) {
+	assert.NoError(t, ClockSkewConfig{}.Validate())
+	assert.NoError(t, ClockSkewConfig{Threshold: time.Second}.Validate())
+	assert.Error(t, ClockSkewConfig{Threshold: -time.Second}.Validate())
+}
+
+func TestCheckClockSkew(t *testing.T) {
+	s, bus := newSkewSession(ClockSkewConfig{Threshold: 30 * time.Second})
+	now := time.Unix(10000, 0)
+
+	// In sync, nothing is reported
+	event := corev2.FixtureEvent("skewed", "check")
+	event.Timestamp = now.Unix() - 2
+	s.checkClockSkew(event, now)
+	assert.Empty(t, event.Annotations[ClockSkewAnnotation])
+	assert.Empty(t, skewEvents(bus))
+
+	// Ahead of the backend, the event is annotated and the agent reported
+	event = corev2.FixtureEvent("skewed", "check")
+	event.Timestamp = now.Unix() + 60
+	s.checkClockSkew(event, now)
+	assert.Equal(t, "1m0s", event.Annotations[ClockSkewAnnotation])
+	assert.Equal(t, now.Unix()+60, event.Timestamp)
+	events := skewEvents(bus)
+	require.Len(t, events, 1)
+	assert.Equal(t, uint32(1), events[0].Check.Status)
+	assert.Contains(t, events[0].Check.Output, "1m0s ahead of")
+
+	// Still skewed, the agent is not reported again
+	event = corev2.FixtureEvent("skewed", "check")
+	event.Timestamp = now.Unix() - 45
+	s.checkClockSkew(event, now)
+	assert.Equal(t, "-45s", event.Annotations[ClockSkewAnnotation])
+	assert.Len(t, skewEvents(bus), 1)
+
+	// Back in sync, the recovery is reported
+	event = corev2.FixtureEvent("skewed", "check")
+	event.Timestamp = now.Unix()
+	s.checkClockSkew(event, now)
+	events = skewEvents(bus)
+	require.Len(t, events, 2)
+	assert.Equal(t, uint32(0), events[1].Check.Status)
+	for _, call := range bus.Calls {
+		assert.Equal(t, messaging.TopicEventRaw, call.Arguments.String(0))
+	}
+}
+
+func TestCheckClockSkewCorrect(t *testing.T) {
+	s, _ := newSkewSession(ClockSkewConfig{Threshold: 30 * time.Second, Correct: true})
+	now := time.Unix(10000, 0)
+
+	event := corev2.FixtureEvent("skewed", "check")
+	event.Timestamp = now.Unix() - 3600
+	event.Check.Executed = now.Unix() - 3605
+	event.Entity.LastSeen = now.Unix() - 3600
+	s.checkClockSkew(event, now)
+	assert.Equal(t, now.Unix(), event.Timestamp)
+	assert.Equal(t, now.Unix()-5, event.Check.Executed)
+	assert.Equal(t, now.Unix(), event.Entity.LastSeen)
+	assert.Equal(t, "-1h0m0s", event.Annotations[ClockSkewAnnotation])
+}
+
+func TestCheckClockSkewDisabled(t *testing.T) {
+	s, bus := newSkewSession(ClockSkewConfig{})
+	event := corev2.FixtureEvent("skewed", "check")
+	event.Timestamp = 1
+	s.checkClockSkew(event, time.Now())
+	assert.Empty(t, event.Annotations[ClockSkewAnnotation])
+	assert.Empty(t, bus.Calls)
+}
+
+func TestHandleKeepaliveClockSkew(t *testing.T) {
+	s, bus := newSkewSession(ClockSkewConfig{Threshold: 30 * time.Second, Correct: true})
+
+	keepalive := corev2.FixtureEvent("skewed", "keepalive")
+	keepalive.Check = nil
+	keepalive.Timestamp = time.Now().Add(time.Hour).Unix()
+	payload, err := proto.Marshal(keepalive)
+	require.NoError(t, err)
+	require.NoError(t, s.handleKeepalive(nil, payload))
+
+	require.Len(t, skewEvents(bus), 1)
+	var published *corev2.Event
+	for _, call := range bus.Calls {
+		if call.Arguments.String(0) == messaging.TopicKeepalive {
+			published = call.Arguments.Get(1).(*corev2.Event)
+		}
+	}
+	require.NotNil(t, published)
+	assert.InDelta(t, time.Now().Unix(), published.Timestamp, 2)
+}
@@ -0,0 +1,96 @@
+package agentd
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	spoofedEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sensu_go_agent_spoofed_events_total",
+			Help: "Number of events rejected because their entity was neither the agent that submitted them nor a proxy entity, or because they belonged to another namespace",
+		},
+		[]string{"namespace"},
+	)
+)
+
+// spoofedEventError is returned for the events of an agent whose entity is
+// neither the agent nor a proxy entity.
+type spoofedEventError struct {
+	agent  string
+	entity string
+}
+
+func (e spoofedEventError) Error() string {
+	return fmt.Sprintf("agent %q is not allowed to submit events for the entity %q", e.agent, e.entity)
+}
+
+// foreignNamespaceError is returned for the events of an agent whose entity or
+// check belongs to another namespace than the one of the agent session.
+type foreignNamespaceError struct {
+	agent     string
+	namespace string
+}
+
+func (e foreignNamespaceError) Error() string {
+	return fmt.Sprintf("agent %q is not allowed to submit events in the namespace %q", e.agent, e.namespace)
+}
+
+// agentSource returns the source of the events submitted by the agent of the
+// session: the name of the agent and the user it authenticated as.
+func (s *Session) agentSource() string {
+	return fmt.Sprintf("%s;user:%s", s.cfg.AgentName, s.cfg.User)
+}
+
+// verifyEventEntity returns an error if the entity of the event submitted by
+// the agent is neither the agent itself nor the proxy entity declared by its
+// check, when the spoofed events are rejected. It is called once the proxy
+// entity replaced the entity of the event, so the agents can not use a proxy
+// entity name to submit events for another agent.
+func (s *Session) verifyEventEntity(event *corev2.Event) error {
+	if !s.cfg.RejectSpoofedEvents || event.Entity == nil || event.Entity.Name == s.cfg.AgentName {
+		return nil
+	}
+	proxy := event.HasCheck() && event.Check.ProxyEntityName == event.Entity.Name &&
+		event.Entity.EntityClass != corev2.EntityAgentClass
+	if proxy {
+		return nil
+	}
+
+	spoofedEvents.WithLabelValues(s.cfg.Namespace).Inc()
+	logger.WithFields(logrus.Fields{
+		"namespace": s.cfg.Namespace,
+		"agent":     s.cfg.AgentName,
+		"user":      s.cfg.User,
+		"entity":    event.Entity.Name,
+	}).Warn("rejected an event submitted by an agent for another entity")
+	return spoofedEventError{agent: s.cfg.AgentName, entity: event.Entity.Name}
+}
+
+// verifyEventNamespace returns an error if the entity or the check of the
+// event submitted by the agent belongs to another namespace than the one of
+// the session, which the agent was authorized for. It is called before the
+// proxy entity of the event is looked up in the namespace of its entity.
+func (s *Session) verifyEventNamespace(event *corev2.Event) error {
+	namespace := s.cfg.Namespace
+	if event.Entity != nil && event.Entity.Namespace != s.cfg.Namespace {
+		namespace = event.Entity.Namespace
+	} else if event.HasCheck() && event.Check.Namespace != s.cfg.Namespace {
+		namespace = event.Check.Namespace
+	} else {
+		return nil
+	}
+
+	spoofedEvents.WithLabelValues(s.cfg.Namespace).Inc()
+	logger.WithFields(logrus.Fields{
+		"namespace":       s.cfg.Namespace,
+		"agent":           s.cfg.AgentName,
+		"user":            s.cfg.User,
+		"event_namespace": namespace,
+	}).Warn("rejected an event submitted by an agent in another namespace")
+	return foreignNamespaceError{agent: s.cfg.AgentName, namespace: namespace}
+}
@@ -0,0 +1,139 @@
+package agentd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEventEntity(t *testing.T) {
+	proxyEvent := func(entityClass string) *corev2.Event {
+		event := corev2.FixtureEvent("switch", "check")
+		event.Entity.EntityClass = entityClass
+		event.Check.ProxyEntityName = "switch"
+		return event
+	}
+
+	tests := []struct {
+		name    string
+		reject  bool
+		event   *corev2.Event
+		wantErr bool
+	}{
+		{
+			name:  "own entity",
+			event: corev2.FixtureEvent("agent1", "check"),
+		},
+		{
+			name:  "other entity allowed",
+			event: corev2.FixtureEvent("agent2", "check"),
+		},
+		{
+			name:   "own entity with rejection",
+			reject: true,
+			event:  corev2.FixtureEvent("agent1", "check"),
+		},
+		{
+			name:    "other entity",
+			reject:  true,
+			event:   corev2.FixtureEvent("agent2", "check"),
+			wantErr: true,
+		},
+		{
+			name:   "proxy entity",
+			reject: true,
+			event:  proxyEvent(corev2.EntityProxyClass),
+		},
+		{
+			name:    "proxy entity of another agent",
+			reject:  true,
+			event:   proxyEvent(corev2.EntityAgentClass),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newPublishSession(PublishBufferConfig{})
+			s.cfg.RejectSpoofedEvents = tt.reject
+			err := s.verifyEventEntity(tt.event)
+			if tt.wantErr {
+				assert.IsType(t, spoofedEventError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyEventNamespace(t *testing.T) {
+	inNamespace := func(entity, check string) *corev2.Event {
+		event := corev2.FixtureEvent("agent1", "check")
+		event.Entity.Namespace = entity
+		event.Check.Namespace = check
+		return event
+	}
+
+	tests := []struct {
+		name    string
+		event   *corev2.Event
+		wantErr bool
+	}{
+		{
+			name:  "session namespace",
+			event: inNamespace("default", "default"),
+		},
+		{
+			name:    "entity in another namespace",
+			event:   inNamespace("acme", "default"),
+			wantErr: true,
+		},
+		{
+			name:    "check in another namespace",
+			event:   inNamespace("default", "acme"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newPublishSession(PublishBufferConfig{})
+			err := s.verifyEventNamespace(tt.event)
+			if tt.wantErr {
+				assert.IsType(t, foreignNamespaceError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReceiveEventForeignNamespace(t *testing.T) {
+	s, bus := newPublishSession(PublishBufferConfig{})
+	s.unmarshal = UnmarshalJSON
+
+	// The proxy entity is never looked up nor created in the other namespace,
+	// the session has no store
+	event := corev2.FixtureEvent("agent1", "check")
+	event.Entity.Namespace = "acme"
+	event.Check.Namespace = "acme"
+	event.Check.ProxyEntityName = "switch"
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	err = s.receiveEvent(context.Background(), payload, "")
+	if assert.IsType(t, rejectedEventError{}, err) {
+		assert.IsType(t, foreignNamespaceError{}, err.(rejectedEventError).err)
+	}
+	bus.AssertNotCalled(t, "Publish")
+}
+
+func TestAgentSource(t *testing.T) {
+	s, _ := newPublishSession(PublishBufferConfig{})
+	s.cfg.User = "agent"
+	event := corev2.FixtureEvent("agent1", "check")
+	event.SetSource(corev2.EventSourceAgent, s.agentSource())
+	assert.Equal(t, "agent:agent1;user:agent", event.Source())
+}
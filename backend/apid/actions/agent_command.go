@@ -0,0 +1,91 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/types"
+)
+
+// DefaultAgentCommandTimeout is the maximum time to wait for an agent to
+// accept or deny a command.
+const DefaultAgentCommandTimeout = 10 * time.Second
+
+// AgentCommandController relays command requests to the agents connected to
+// this backend over the message bus.
+type AgentCommandController struct {
+	bus     messaging.MessageBus
+	timeout time.Duration
+}
+
+// NewAgentCommandController returns a new AgentCommandController
+func NewAgentCommandController(bus messaging.MessageBus) AgentCommandController {
+	return AgentCommandController{
+		bus:     bus,
+		timeout: DefaultAgentCommandTimeout,
+	}
+}
+
+// Run asks the agent of the given entity to execute the command. It returns
+// once the agent accepted the command, whose result is sent by the agent as
+// an event.
+func (c AgentCommandController) Run(ctx context.Context, entity string, command *corev2.AgentCommand) (*corev2.AgentCommandResult, error) {
+	if err := command.Validate(); err != nil {
+		return nil, NewError(InvalidArgument, err)
+	}
+
+	request := &corev2.AgentCommandRequest{
+		AgentCommand: *command,
+		ID:           uuid.New().String(),
+	}
+	if request.Timeout == 0 {
+		request.Timeout = corev2.DefaultAgentCommandTimeout
+	}
+
+	// Subscribe to the response before sending the request, so it can't be
+	// missed
+	responses := messaging.ChannelSubscriber{Channel: make(chan interface{}, 1)}
+	topic := messaging.AgentCommandTopic(request.ID)
+	subscription, err := c.bus.Subscribe(topic, request.ID, responses)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	defer func() {
+		if err := subscription.Cancel(); err != nil {
+			logger.WithError(err).Error("unable to unsubscribe from message bus")
+		}
+	}()
+
+	namespace := corev2.ContextNamespace(ctx)
+	agentTopic := messaging.SubscriptionTopic(namespace, types.GetEntitySubscription(entity))
+	if err := c.bus.Publish(agentTopic, request); err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case msg := <-responses.Channel:
+		response, ok := msg.(*corev2.AgentCommandResponse)
+		if !ok {
+			return nil, NewErrorf(InternalErr, "unexpected command response")
+		}
+		if response.Error != "" {
+			return nil, NewError(InvalidArgument, fmt.Errorf("the agent denied the command: %s", response.Error))
+		}
+		return &corev2.AgentCommandResult{
+			ID:     request.ID,
+			Entity: entity,
+			Check:  corev2.AgentCommandCheckName,
+		}, nil
+	case <-timer.C:
+		return nil, NewErrorf(NotFound, "the agent did not respond, it may not be connected to this backend")
+	case <-ctx.Done():
+		return nil, NewError(InternalErr, ctx.Err())
+	}
+}
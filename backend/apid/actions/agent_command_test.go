@@ -0,0 +1,62 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentCommandControllerRun(t *testing.T) {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	defer bus.Stop()
+
+	// Act as the session of the agent entity1, which only accepts uptime
+	agent := messaging.ChannelSubscriber{Channel: make(chan interface{}, 1)}
+	topic := messaging.SubscriptionTopic("default", corev2.GetEntitySubscription("entity1"))
+	subscription, err := bus.Subscribe(topic, "entity1", agent)
+	require.NoError(t, err)
+	defer subscription.Cancel()
+	timeouts := make(chan uint32, 10)
+	go func() {
+		for msg := range agent.Channel {
+			request := msg.(*corev2.AgentCommandRequest)
+			timeouts <- request.Timeout
+			response := &corev2.AgentCommandResponse{ID: request.ID}
+			if request.Command != "uptime" {
+				response.Error = "command denied by the agent allow list"
+			}
+			_ = bus.Publish(messaging.AgentCommandTopic(request.ID), response)
+		}
+	}()
+
+	controller := NewAgentCommandController(bus)
+	controller.timeout = 100 * time.Millisecond
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+
+	result, err := controller.Run(ctx, "entity1", &corev2.AgentCommand{Command: "uptime"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.ID)
+	assert.Equal(t, "entity1", result.Entity)
+	assert.Equal(t, corev2.AgentCommandCheckName, result.Check)
+	assert.Equal(t, uint32(corev2.DefaultAgentCommandTimeout), <-timeouts)
+
+	_, err = controller.Run(ctx, "entity1", &corev2.AgentCommand{Command: "rm -rf /", Timeout: 5})
+	code, _ := StatusFromError(err)
+	assert.Equal(t, InvalidArgument, code)
+	assert.Equal(t, uint32(5), <-timeouts)
+
+	_, err = controller.Run(ctx, "entity1", &corev2.AgentCommand{})
+	code, _ = StatusFromError(err)
+	assert.Equal(t, InvalidArgument, code)
+
+	_, err = controller.Run(ctx, "entity2", &corev2.AgentCommand{Command: "uptime"})
+	code, _ = StatusFromError(err)
+	assert.Equal(t, NotFound, code)
+}
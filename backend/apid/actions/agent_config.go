@@ -0,0 +1,69 @@
+package actions
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// AgentConfigController exposes actions which a viewer can perform on the
+// configuration managed by the backend for the agents. The agents are sent
+// their configuration by agentd, which watches the store.
+type AgentConfigController struct {
+	store store.AgentConfigStore
+}
+
+// NewAgentConfigController returns a new AgentConfigController
+func NewAgentConfigController(store store.AgentConfigStore) AgentConfigController {
+	return AgentConfigController{
+		store: store,
+	}
+}
+
+// Get gets the configuration of the agent of the given entity, which is empty
+// if none was configured.
+func (c AgentConfigController) Get(ctx context.Context, entity string) (*corev2.AgentConfig, error) {
+	config, err := c.store.GetAgentConfig(ctx, entity)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	if config == nil {
+		config = &corev2.AgentConfig{}
+	}
+	return config, nil
+}
+
+// Update creates or updates the configuration of the agent of the given
+// entity.
+func (c AgentConfigController) Update(ctx context.Context, entity string, config *corev2.AgentConfig) error {
+	if err := config.Validate(); err != nil {
+		return NewError(InvalidArgument, err)
+	}
+
+	if err := c.store.UpdateAgentConfig(ctx, entity, config); err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotValid:
+			return NewError(InvalidArgument, err)
+		default:
+			return NewError(InternalErr, err)
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes the configuration of the agent of the given entity, which
+// reverts the agent to its own configuration.
+func (c AgentConfigController) Delete(ctx context.Context, entity string) error {
+	if err := c.store.DeleteAgentConfig(ctx, entity); err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotFound:
+			return NewError(NotFound, err)
+		default:
+			return NewError(InternalErr, err)
+		}
+	}
+
+	return nil
+}
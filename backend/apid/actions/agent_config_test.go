@@ -0,0 +1,144 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAgentConfig(t *testing.T) {
+	config := &corev2.AgentConfig{LogLevel: "debug"}
+
+	testCases := []struct {
+		name            string
+		storeConfig     *corev2.AgentConfig
+		storeErr        error
+		expected        *corev2.AgentConfig
+		expectedErr     bool
+		expectedErrCode ErrCode
+	}{
+		{
+			name:        "Configured agent",
+			storeConfig: config,
+			expected:    config,
+		},
+		{
+			name:     "No config",
+			expected: &corev2.AgentConfig{},
+		},
+		{
+			name:            "Store error",
+			storeErr:        errors.New("some error"),
+			expectedErr:     true,
+			expectedErrCode: InternalErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			store.On("GetAgentConfig", mock.Anything, "web01").Return(tc.storeConfig, tc.storeErr)
+			actions := NewAgentConfigController(store)
+
+			result, err := actions.Get(context.Background(), "web01")
+			if tc.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedErrCode, err.(Error).Code)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestUpdateAgentConfig(t *testing.T) {
+	testCases := []struct {
+		name            string
+		argument        *corev2.AgentConfig
+		storeErr        error
+		expectedErr     bool
+		expectedErrCode ErrCode
+	}{
+		{
+			name:     "Update",
+			argument: &corev2.AgentConfig{KeepaliveInterval: 10},
+		},
+		{
+			name:            "Invalid config",
+			argument:        &corev2.AgentConfig{LogLevel: "verbose"},
+			expectedErr:     true,
+			expectedErrCode: InvalidArgument,
+		},
+		{
+			name:            "Store error",
+			argument:        &corev2.AgentConfig{},
+			storeErr:        errors.New("some error"),
+			expectedErr:     true,
+			expectedErrCode: InternalErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			store.On("UpdateAgentConfig", mock.Anything, "web01", tc.argument).Return(tc.storeErr)
+			actions := NewAgentConfigController(store)
+
+			err := actions.Update(context.Background(), "web01", tc.argument)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedErrCode, err.(Error).Code)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDeleteAgentConfig(t *testing.T) {
+	testCases := []struct {
+		name            string
+		storeErr        error
+		expectedErr     bool
+		expectedErrCode ErrCode
+	}{
+		{
+			name: "Delete",
+		},
+		{
+			name:            "No config",
+			storeErr:        &store.ErrNotFound{Key: "web01"},
+			expectedErr:     true,
+			expectedErrCode: NotFound,
+		},
+		{
+			name:            "Store error",
+			storeErr:        errors.New("some error"),
+			expectedErr:     true,
+			expectedErrCode: InternalErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			store.On("DeleteAgentConfig", mock.Anything, "web01").Return(tc.storeErr)
+			actions := NewAgentConfigController(store)
+
+			err := actions.Delete(context.Background(), "web01")
+			if tc.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedErrCode, err.(Error).Code)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/types"
+)
+
+// DefaultAgentSpoolTimeout is the maximum time to wait for an agent to reply
+// to a spool request.
+const DefaultAgentSpoolTimeout = 10 * time.Second
+
+// AgentSpoolController relays spool requests to the agents connected to this
+// backend over the message bus.
+type AgentSpoolController struct {
+	bus     messaging.MessageBus
+	timeout time.Duration
+}
+
+// NewAgentSpoolController returns a new AgentSpoolController
+func NewAgentSpoolController(bus messaging.MessageBus) AgentSpoolController {
+	return AgentSpoolController{
+		bus:     bus,
+		timeout: DefaultAgentSpoolTimeout,
+	}
+}
+
+// Do asks the agent of the given entity to perform the given spool action, and
+// returns the resulting spool statistics.
+func (c AgentSpoolController) Do(ctx context.Context, entity, action string) (*corev2.AgentSpoolStats, error) {
+	if err := corev2.ValidateAgentSpoolAction(action); err != nil {
+		return nil, NewError(InvalidArgument, err)
+	}
+
+	request := &corev2.AgentSpoolRequest{
+		ID:     uuid.New().String(),
+		Action: action,
+	}
+
+	// Subscribe to the response before sending the request, so it can't be
+	// missed
+	responses := messaging.ChannelSubscriber{Channel: make(chan interface{}, 1)}
+	topic := messaging.AgentSpoolTopic(request.ID)
+	subscription, err := c.bus.Subscribe(topic, request.ID, responses)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	defer func() {
+		if err := subscription.Cancel(); err != nil {
+			logger.WithError(err).Error("unable to unsubscribe from message bus")
+		}
+	}()
+
+	namespace := corev2.ContextNamespace(ctx)
+	agentTopic := messaging.SubscriptionTopic(namespace, types.GetEntitySubscription(entity))
+	if err := c.bus.Publish(agentTopic, request); err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case msg := <-responses.Channel:
+		response, ok := msg.(*corev2.AgentSpoolResponse)
+		if !ok {
+			return nil, NewErrorf(InternalErr, "unexpected spool response")
+		}
+		if response.Error != "" {
+			return nil, NewError(InternalErr, errors.New(response.Error))
+		}
+		return &response.Stats, nil
+	case <-timer.C:
+		return nil, NewErrorf(NotFound, "the agent did not respond, it may not be connected to this backend")
+	case <-ctx.Done():
+		return nil, NewError(InternalErr, ctx.Err())
+	}
+}
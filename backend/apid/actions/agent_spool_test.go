@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentSpoolControllerDo(t *testing.T) {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	defer bus.Stop()
+
+	// Act as the session of the agent entity1
+	agent := messaging.ChannelSubscriber{Channel: make(chan interface{}, 1)}
+	topic := messaging.SubscriptionTopic("default", corev2.GetEntitySubscription("entity1"))
+	subscription, err := bus.Subscribe(topic, "entity1", agent)
+	require.NoError(t, err)
+	defer subscription.Cancel()
+	go func() {
+		for msg := range agent.Channel {
+			request := msg.(*corev2.AgentSpoolRequest)
+			response := &corev2.AgentSpoolResponse{
+				ID:    request.ID,
+				Stats: corev2.AgentSpoolStats{Messages: 42},
+			}
+			if request.Action == corev2.AgentSpoolPurge {
+				response.Error = "purge failed"
+			}
+			_ = bus.Publish(messaging.AgentSpoolTopic(request.ID), response)
+		}
+	}()
+
+	controller := NewAgentSpoolController(bus)
+	controller.timeout = 100 * time.Millisecond
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+
+	stats, err := controller.Do(ctx, "entity1", corev2.AgentSpoolInfo)
+	require.NoError(t, err)
+	assert.Equal(t, 42, stats.Messages)
+
+	_, err = controller.Do(ctx, "entity1", corev2.AgentSpoolPurge)
+	assert.Error(t, err)
+
+	_, err = controller.Do(ctx, "entity1", "explode")
+	code, _ := StatusFromError(err)
+	assert.Equal(t, InvalidArgument, code)
+
+	_, err = controller.Do(ctx, "entity2", corev2.AgentSpoolInfo)
+	code, _ = StatusFromError(err)
+	assert.Equal(t, NotFound, code)
+}
@@ -0,0 +1,40 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/sensu/sensu-go/backend/authorization"
+)
+
+// authorize returns a PermissionDenied error unless the user of the request
+// stored in ctx is authorized to perform the verb on the resource of the given
+// name, within the namespace of the request. It authorizes the resources an
+// action writes besides the one of the request, which the authorization
+// middleware doesn't know about. An empty name requires the verb on the whole
+// collection.
+func authorize(ctx context.Context, authorizer authorization.Authorizer, verb, resource, name string) error {
+	attrs := authorization.GetAttributes(ctx)
+	if attrs == nil {
+		return NewErrorf(PermissionDenied, "could not retrieve the request info")
+	}
+
+	authorized, err := authorizer.Authorize(ctx, &authorization.Attributes{
+		APIGroup:     attrs.APIGroup,
+		APIVersion:   attrs.APIVersion,
+		Namespace:    attrs.Namespace,
+		Resource:     resource,
+		ResourceName: name,
+		User:         attrs.User,
+		Verb:         verb,
+	})
+	if err != nil {
+		return NewError(InternalErr, err)
+	}
+	if !authorized {
+		if name != "" {
+			return NewErrorf(PermissionDenied, "unauthorized to %s the %s %q", verb, resource, name)
+		}
+		return NewErrorf(PermissionDenied, "unauthorized to %s the %s", verb, resource)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package actions
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// The prometheus metrics the autoscaling signals are computed from.
+const (
+	agentSessionsMetric      = "sensu_go_agent_sessions"
+	eventQueueDepthMetric    = "sensu_go_event_queue_depth"
+	eventQueueCapacityMetric = "sensu_go_event_queue_capacity"
+	schedulingLagMetric      = "sensu_go_check_scheduling_lag_seconds"
+)
+
+// AutoscalingController exposes the load signals of the backend.
+type AutoscalingController struct {
+	gatherer prometheus.Gatherer
+}
+
+// NewAutoscalingController returns a new AutoscalingController, computing the
+// signals from the metrics of the given gatherer.
+func NewAutoscalingController(gatherer prometheus.Gatherer) AutoscalingController {
+	return AutoscalingController{gatherer: gatherer}
+}
+
+// Signals returns the load signals of the backend.
+func (a AutoscalingController) Signals() (*corev2.AutoscalingSignals, error) {
+	families, err := a.gatherer.Gather()
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+
+	values := make(map[string]float64, len(families))
+	for _, family := range families {
+		for _, metric := range family.Metric {
+			values[family.GetName()] += metricValue(metric)
+		}
+	}
+
+	signals := &corev2.AutoscalingSignals{
+		AgentSessions:        int64(values[agentSessionsMetric]),
+		EventQueueDepth:      int64(values[eventQueueDepthMetric]),
+		SchedulingLagSeconds: values[schedulingLagMetric],
+	}
+	if capacity := values[eventQueueCapacityMetric]; capacity > 0 {
+		signals.EventQueueUtilization = values[eventQueueDepthMetric] / capacity
+	}
+
+	return signals, nil
+}
+
+// metricValue returns the value of a gauge or counter metric.
+func metricValue(metric *dto.Metric) float64 {
+	switch {
+	case metric.Gauge != nil:
+		return metric.Gauge.GetValue()
+	case metric.Counter != nil:
+		return metric.Counter.GetValue()
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoscalingSignals(t *testing.T) {
+	sessions := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: agentSessionsMetric, Help: "help"}, []string{"namespace"})
+	depth := prometheus.NewGauge(prometheus.GaugeOpts{Name: eventQueueDepthMetric, Help: "help"})
+	capacity := prometheus.NewGauge(prometheus.GaugeOpts{Name: eventQueueCapacityMetric, Help: "help"})
+	lag := prometheus.NewGauge(prometheus.GaugeOpts{Name: schedulingLagMetric, Help: "help"})
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(sessions, depth, capacity, lag)
+
+	sessions.WithLabelValues("default").Set(3)
+	sessions.WithLabelValues("acme").Set(2)
+	depth.Set(25)
+	capacity.Set(100)
+	lag.Set(4)
+
+	signals, err := NewAutoscalingController(registry).Signals()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), signals.AgentSessions)
+	assert.Equal(t, int64(25), signals.EventQueueDepth)
+	assert.Equal(t, 0.25, signals.EventQueueUtilization)
+	assert.Equal(t, float64(4), signals.SchedulingLagSeconds)
+}
+
+func TestAutoscalingSignalsNoMetrics(t *testing.T) {
+	signals, err := NewAutoscalingController(prometheus.NewRegistry()).Signals()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), signals.AgentSessions)
+	assert.Equal(t, float64(0), signals.EventQueueUtilization)
+}
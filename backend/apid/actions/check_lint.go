@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// Lint validates the check configuration, and returns the warnings about the
+// parts of the configuration going against best practices. Warnings do not
+// prevent the check from being created.
+func (a CheckController) Lint(ctx context.Context, check *corev2.CheckConfig) ([]corev2.CheckConfigWarning, error) {
+	if err := check.Validate(); err != nil {
+		return nil, NewError(InvalidArgument, err)
+	}
+	return lintCheck(check), nil
+}
+
+// lintCheck returns the best-practice warnings about the check configuration.
+func lintCheck(check *corev2.CheckConfig) []corev2.CheckConfigWarning {
+	warnings := []corev2.CheckConfigWarning{}
+	warn := func(field, format string, args ...interface{}) {
+		warnings = append(warnings, corev2.CheckConfigWarning{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(check.Handlers) == 0 && len(check.OutputMetricHandlers) == 0 {
+		warn("handlers", "no handlers are set, the events of the check will not be handled")
+	}
+
+	if len(check.Subscriptions) == 0 {
+		warn("subscriptions", "no subscriptions are set, the check will not be executed by any agent")
+	}
+
+	if check.Cron == "" && len(check.RunAt) == 0 && check.Timeout > 0 && check.Interval < check.Timeout {
+		warn("interval", "the interval (%ds) is shorter than the timeout (%ds), executions of the check may overlap", check.Interval, check.Timeout)
+	}
+
+	// These fields are used by the backend to build the check requests, before
+	// the agents perform the token substitution
+	tokenFields := []struct {
+		name   string
+		values []string
+	}{
+		{"subscriptions", check.Subscriptions},
+		{"runtime_assets", check.RuntimeAssets},
+	}
+	for _, field := range tokenFields {
+		for _, value := range field.values {
+			if strings.Contains(value, "{{") {
+				warn(field.name, "tokens are not substituted in this field")
+				break
+			}
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,101 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLint(t *testing.T) {
+	testCases := []struct {
+		name     string
+		check    func(*corev2.CheckConfig)
+		expected []string
+	}{
+		{
+			name: "no warnings",
+			check: func(check *corev2.CheckConfig) {
+				check.Handlers = []string{"slack"}
+			},
+			expected: []string{},
+		},
+		{
+			name: "no handlers",
+			check: func(check *corev2.CheckConfig) {
+				check.Handlers = nil
+			},
+			expected: []string{"handlers"},
+		},
+		{
+			name: "output metric handlers only",
+			check: func(check *corev2.CheckConfig) {
+				check.Handlers = nil
+				check.OutputMetricHandlers = []string{"influxdb"}
+			},
+			expected: []string{},
+		},
+		{
+			name: "no subscriptions",
+			check: func(check *corev2.CheckConfig) {
+				check.Handlers = []string{"slack"}
+				check.Subscriptions = nil
+			},
+			expected: []string{"subscriptions"},
+		},
+		{
+			name: "interval shorter than timeout",
+			check: func(check *corev2.CheckConfig) {
+				check.Handlers = []string{"slack"}
+				check.Interval = 10
+				check.Timeout = 30
+			},
+			expected: []string{"interval"},
+		},
+		{
+			name: "cron with a long timeout",
+			check: func(check *corev2.CheckConfig) {
+				check.Handlers = []string{"slack"}
+				check.Cron = "* * * * *"
+				check.Interval = 0
+				check.Timeout = 120
+			},
+			expected: []string{},
+		},
+		{
+			name: "tokens in subscriptions and assets",
+			check: func(check *corev2.CheckConfig) {
+				check.Handlers = []string{"{{ .labels.handler }}"}
+				check.Subscriptions = []string{"linux", "{{ .labels.team }}"}
+				check.RuntimeAssets = []string{"{{ .labels.asset }}"}
+			},
+			expected: []string{"subscriptions", "runtime_assets"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			check := corev2.FixtureCheckConfig("check1")
+			tc.check(check)
+
+			warnings := lintCheck(check)
+			fields := []string{}
+			for _, warning := range warnings {
+				fields = append(fields, warning.Field)
+			}
+			assert.Equal(t, tc.expected, fields)
+		})
+	}
+}
+
+func TestCheckLintInvalidCheck(t *testing.T) {
+	controller := CheckController{}
+	check := corev2.FixtureCheckConfig("check1")
+	check.Interval = 0
+
+	_, err := controller.Lint(context.Background(), check)
+	require.Error(t, err)
+	assert.Equal(t, InvalidArgument, err.(Error).Code)
+}
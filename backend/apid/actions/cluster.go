@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"crypto/tls"
 
 	"github.com/coreos/etcd/clientv3"
 	"github.com/sensu/sensu-go/backend/store"
@@ -10,15 +11,20 @@ import (
 // ClusterController is a thin wrapper around clientv3.Cluster. It exists
 // only for the purposes of access control.
 type ClusterController struct {
-	cluster clientv3.Cluster
-	store   store.ClusterIDStore
+	cluster             clientv3.Cluster
+	client              *clientv3.Client
+	store               store.ClusterIDStore
+	etcdClientTLSConfig *tls.Config
 }
 
-// NewClusterController provides a new controller for the etcd cluster.
-func NewClusterController(cluster clientv3.Cluster, store store.ClusterIDStore) ClusterController {
+// NewClusterController provides a new controller for the etcd cluster. The
+// client is used to inspect the leases of the rings.
+func NewClusterController(cluster clientv3.Cluster, client *clientv3.Client, store store.ClusterIDStore, etcdClientTLSConfig *tls.Config) ClusterController {
 	return ClusterController{
-		cluster: cluster,
-		store:   store,
+		cluster:             cluster,
+		client:              client,
+		store:               store,
+		etcdClientTLSConfig: etcdClientTLSConfig,
 	}
 }
 
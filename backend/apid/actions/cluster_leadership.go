@@ -0,0 +1,130 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/ringv2"
+)
+
+// memberDialTimeout is the timeout used to connect to a single cluster member.
+const memberDialTimeout = 5 * time.Second
+
+// Leadership returns the leadership of the cluster, as seen by each of its
+// members, and the ownership of the ring triggers.
+func (c ClusterController) Leadership(ctx context.Context) (*corev2.ClusterLeadership, error) {
+	mList, err := c.cluster.MemberList(ctx)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+
+	leadership := &corev2.ClusterLeadership{}
+	for _, member := range mList.Members {
+		status := &corev2.MemberLeadership{
+			MemberID: member.ID,
+			Name:     member.Name,
+		}
+		leadership.Members = append(leadership.Members, status)
+
+		resp, err := c.memberStatus(ctx, member)
+		if err != nil {
+			logger.WithField("member", member.ID).WithError(err).Warning("could not get the cluster member status")
+			status.Err = err.Error()
+			continue
+		}
+		status.Leader = resp.Leader
+		status.IsLeader = resp.Leader == member.ID
+		if status.IsLeader {
+			leadership.Leader = member.ID
+			leadership.LeaderName = member.Name
+			leadership.RaftTerm = resp.RaftTerm
+		}
+	}
+
+	leadership.Rings, err = ringv2.Ownerships(ctx, c.client)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+
+	return leadership, nil
+}
+
+// TransferLeadership gracefully hands the leadership of the cluster off to the
+// member with the given ID, e.g. before the planned maintenance of the current
+// leader.
+func (c ClusterController) TransferLeadership(ctx context.Context, id uint64) error {
+	mList, err := c.cluster.MemberList(ctx)
+	if err != nil {
+		return NewError(InternalErr, err)
+	}
+
+	var transferee *etcdserverpb.Member
+	for _, member := range mList.Members {
+		if member.ID == id {
+			transferee = member
+		}
+	}
+	if transferee == nil {
+		return NewErrorf(NotFound, "no cluster member with ID %x", id)
+	}
+
+	// The leadership can only be moved by the current leader
+	resp, err := c.memberStatus(ctx, transferee)
+	if err != nil {
+		return NewError(InternalErr, err)
+	}
+	if resp.Leader == id {
+		return nil
+	}
+	var leader *etcdserverpb.Member
+	for _, member := range mList.Members {
+		if member.ID == resp.Leader {
+			leader = member
+		}
+	}
+	if leader == nil {
+		return NewErrorf(InternalErr, "the cluster has no leader")
+	}
+
+	client, err := c.memberClient(leader)
+	if err != nil {
+		return NewError(InternalErr, err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	if _, err := client.MoveLeader(ctx, id); err != nil {
+		return NewError(InternalErr, err)
+	}
+
+	logger.WithField("member", transferee.Name).Info("transferred the cluster leadership")
+	return nil
+}
+
+// memberClient returns a client connected to the given cluster member only.
+func (c ClusterController) memberClient(member *etcdserverpb.Member) (*clientv3.Client, error) {
+	if len(member.ClientURLs) == 0 {
+		return nil, fmt.Errorf("cluster member %q has no client URL", member.Name)
+	}
+	return clientv3.New(clientv3.Config{
+		Endpoints:   member.ClientURLs,
+		DialTimeout: memberDialTimeout,
+		TLS:         c.etcdClientTLSConfig,
+	})
+}
+
+// memberStatus returns the status of the given cluster member.
+func (c ClusterController) memberStatus(ctx context.Context, member *etcdserverpb.Member) (*clientv3.StatusResponse, error) {
+	client, err := c.memberClient(member)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	return client.Status(ctx, member.ClientURLs[0])
+}
@@ -0,0 +1,84 @@
+// +build integration
+
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/etcd"
+	"github.com/sensu/sensu-go/backend/ringv2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterLeadership(t *testing.T) {
+	e, cleanup := etcd.NewTestEtcd(t)
+	defer cleanup()
+
+	client, err := e.NewClient()
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctrl := NewClusterController(clientv3.NewCluster(client), client, nil, nil)
+
+	leadership, err := ctrl.Leadership(context.Background())
+	require.NoError(t, err)
+	require.Len(t, leadership.Members, 1)
+	member := leadership.Members[0]
+	assert.Empty(t, member.Err)
+	assert.True(t, member.IsLeader)
+	assert.Equal(t, member.MemberID, leadership.Leader)
+	assert.Equal(t, "default", leadership.LeaderName)
+
+	// Transferring the leadership to the current leader is a no-op
+	assert.NoError(t, ctrl.TransferLeadership(context.Background(), leadership.Leader))
+
+	err = ctrl.TransferLeadership(context.Background(), leadership.Leader+1)
+	require.Error(t, err)
+	assert.Equal(t, NotFound, err.(Error).Code)
+}
+
+func TestClusterLeadershipRings(t *testing.T) {
+	e, cleanup := etcd.NewTestEtcd(t)
+	defer cleanup()
+
+	client, err := e.NewClient()
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ring := ringv2.New(client, ringv2.Path("global", "backends"))
+	require.NoError(t, ring.Add(ctx, "backend1", 60))
+	events := ring.Watch(ctx, "tessen", 1, 60, "")
+
+	ctrl := NewClusterController(clientv3.NewCluster(client), client, nil, nil)
+
+	// The trigger is created asynchronously by the ring watcher
+	var leadership *corev2.ClusterLeadership
+	for i := 0; i < 50; i++ {
+		leadership, err = ctrl.Leadership(ctx)
+		require.NoError(t, err)
+		if len(leadership.Rings) > 0 {
+			break
+		}
+		select {
+		case <-events:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	require.Len(t, leadership.Rings, 1)
+	owner := leadership.Rings[0]
+	assert.Equal(t, "global", owner.Namespace)
+	assert.Equal(t, "backends", owner.Ring)
+	assert.Equal(t, "tessen", owner.Watcher)
+	assert.Equal(t, "60", owner.Schedule)
+	assert.Equal(t, "backend1", owner.Owner)
+	assert.NotZero(t, owner.LeaseID)
+	assert.NotZero(t, owner.TTL)
+}
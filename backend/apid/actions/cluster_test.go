@@ -35,7 +35,7 @@ func (mockCluster) MemberUpdate(context.Context, uint64, []string) (*clientv3.Me
 var _ clientv3.Cluster = mockCluster{}
 
 func TestMemberList(t *testing.T) {
-	ctrl := NewClusterController(mockCluster{}, &mockstore.MockStore{})
+	ctrl := NewClusterController(mockCluster{}, nil, &mockstore.MockStore{}, nil)
 
 	_, err := ctrl.MemberList(context.Background())
 	if err != nil {
@@ -44,7 +44,7 @@ func TestMemberList(t *testing.T) {
 }
 
 func TestMemberAdd(t *testing.T) {
-	ctrl := NewClusterController(mockCluster{}, &mockstore.MockStore{})
+	ctrl := NewClusterController(mockCluster{}, nil, &mockstore.MockStore{}, nil)
 
 	_, err := ctrl.MemberAdd(context.Background(), []string{"foo"})
 	if err != nil {
@@ -53,7 +53,7 @@ func TestMemberAdd(t *testing.T) {
 }
 
 func TestMemberUpdate(t *testing.T) {
-	ctrl := NewClusterController(mockCluster{}, &mockstore.MockStore{})
+	ctrl := NewClusterController(mockCluster{}, nil, &mockstore.MockStore{}, nil)
 
 	_, err := ctrl.MemberUpdate(context.Background(), 1234, []string{"foo"})
 	if err != nil {
@@ -62,7 +62,7 @@ func TestMemberUpdate(t *testing.T) {
 }
 
 func TestMemberRemove(t *testing.T) {
-	ctrl := NewClusterController(mockCluster{}, &mockstore.MockStore{})
+	ctrl := NewClusterController(mockCluster{}, nil, &mockstore.MockStore{}, nil)
 
 	_, err := ctrl.MemberRemove(context.Background(), 1234)
 	if err != nil {
@@ -74,7 +74,7 @@ func TestNewClusterIDController(t *testing.T) {
 	assert := assert.New(t)
 
 	store := &mockstore.MockStore{}
-	actions := NewClusterController(mockCluster{}, store)
+	actions := NewClusterController(mockCluster{}, nil, store, nil)
 
 	assert.NotNil(actions)
 	assert.Equal(store, actions.store)
@@ -112,7 +112,7 @@ func TestGetClusterID(t *testing.T) {
 
 	for _, tc := range testCases {
 		store := &mockstore.MockStore{}
-		actions := NewClusterController(mockCluster{}, store)
+		actions := NewClusterController(mockCluster{}, nil, store, nil)
 
 		t.Run(tc.name, func(t *testing.T) {
 			assert := assert.New(t)
@@ -0,0 +1,149 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sirupsen/logrus"
+)
+
+// resolveEventsParam is the query parameter requesting the resolution of the
+// events of a deleted check.
+const resolveEventsParam = "resolveEvents"
+
+// deletedCheckOutput is the output of the resolution events emitted for a
+// deleted check.
+const deletedCheckOutput = "Resolved: the check was deleted"
+
+// CheckDeleter deletes checks. When requested, it also resolves the non-OK
+// events of the deleted check and removes its silenced entries, so the
+// events don't stay in a problem state forever.
+type CheckDeleter struct {
+	Store      store.Store
+	EventStore store.EventStore
+	Bus        messaging.MessageBus
+
+	// Authorizer authorizes the user to update the events and to delete the
+	// silenced entries of the deleted check, when they are resolved, since
+	// the request is only authorized to delete the check.
+	Authorizer authorization.Authorizer
+}
+
+// Delete deletes the check identified in the request path.
+func (d CheckDeleter) Delete(req *http.Request) (interface{}, error) {
+	params := mux.Vars(req)
+	checkName, err := url.PathUnescape(params["id"])
+	if err != nil {
+		return nil, NewError(InvalidArgument, err)
+	}
+
+	// The events and silenced entries are authorized before the check is
+	// deleted, so the check is not deleted if they can't be resolved
+	resolve := req.URL.Query().Get(resolveEventsParam) == "true"
+	var events []*corev2.Event
+	var entries []*corev2.Silenced
+	if resolve {
+		if events, entries, err = d.resolvable(req.Context(), checkName); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := d.Store.DeleteResource(req.Context(), (&corev2.CheckConfig{}).StorePrefix(), checkName); err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotFound:
+			return nil, NewErrorf(NotFound)
+		default:
+			return nil, NewError(InternalErr, err)
+		}
+	}
+
+	if !resolve {
+		return nil, nil
+	}
+
+	now := time.Now().Unix()
+	for _, event := range events {
+		resolution := resolutionEvent(event, now)
+		resolution.SetSource(corev2.EventSourceAPI, contextUsername(req.Context()))
+		if err := d.Bus.Publish(messaging.TopicEventRaw, resolution); err != nil {
+			logger := logger.WithFields(logrus.Fields{
+				"entity":    event.Entity.Name,
+				"check":     checkName,
+				"namespace": event.Check.Namespace})
+			logger.WithError(err).Error("error resolving event of deleted check")
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	if err := d.Store.DeleteSilencedEntryByName(req.Context(), names...); err != nil {
+		return nil, NewError(InternalErr, fmt.Errorf("error deleting silenced entries for check: %s", err))
+	}
+
+	return nil, nil
+}
+
+// resolvable returns the non-OK events and the silenced entries of the check,
+// once the user is authorized to update the events and to delete the
+// silenced entries.
+func (d CheckDeleter) resolvable(ctx context.Context, checkName string) ([]*corev2.Event, []*corev2.Silenced, error) {
+	allEvents, err := d.EventStore.GetEvents(ctx, &store.SelectionPredicate{})
+	if err != nil {
+		return nil, nil, NewError(InternalErr, fmt.Errorf("error fetching events for check: %s", err))
+	}
+	var events []*corev2.Event
+	for _, event := range allEvents {
+		if !event.HasCheck() || event.Check.Name != checkName || event.Check.Status == 0 {
+			continue
+		}
+		if err := authorize(ctx, d.Authorizer, "update", "events", path.Join(event.Entity.Name, checkName)); err != nil {
+			return nil, nil, err
+		}
+		events = append(events, event)
+	}
+
+	entries, err := d.Store.GetSilencedEntriesByCheckName(ctx, checkName)
+	if err != nil {
+		return nil, nil, NewError(InternalErr, fmt.Errorf("error fetching silenced entries for check: %s", err))
+	}
+	for _, entry := range entries {
+		if err := authorize(ctx, d.Authorizer, "delete", "silenced", entry.Name); err != nil {
+			return nil, nil, err
+		}
+	}
+	return events, entries, nil
+}
+
+// resolutionEvent returns a copy of the event with an OK check status,
+// executed at the given time.
+func resolutionEvent(event *corev2.Event, now int64) *corev2.Event {
+	check := *event.Check
+	check.Status = 0
+	check.Output = deletedCheckOutput
+	check.Executed = now
+	check.Silenced = nil
+
+	resolution := *event
+	resolution.Check = &check
+	resolution.Annotations = make(map[string]string, len(event.Annotations))
+	for key, value := range event.Annotations {
+		resolution.Annotations[key] = value
+	}
+	resolution.Metrics = nil
+	resolution.Timestamp = now
+	return &resolution
+}
@@ -0,0 +1,126 @@
+package actions
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mockbus"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// deniedResourceAuthorizer authorizes every request but the ones on the
+// denied resource.
+type deniedResourceAuthorizer struct {
+	denied string
+}
+
+func (a deniedResourceAuthorizer) Authorize(ctx context.Context, attrs *authorization.Attributes) (bool, error) {
+	return attrs.Resource != a.denied, nil
+}
+
+func TestCheckDeleterDelete(t *testing.T) {
+	failing := corev2.FixtureEvent("entity1", "check1")
+	failing.Check.Status = 2
+	passing := corev2.FixtureEvent("entity2", "check1")
+	other := corev2.FixtureEvent("entity1", "check2")
+	other.Check.Status = 2
+	silenced := corev2.FixtureSilenced("*:check1")
+
+	testCases := []struct {
+		name          string
+		query         string
+		deleteErr     error
+		denied        string
+		expectedCode  ErrCode
+		expectResolve bool
+	}{
+		{
+			name: "delete only",
+		},
+		{
+			name:          "delete and resolve events",
+			query:         "?resolveEvents=true",
+			expectResolve: true,
+		},
+		{
+			name:         "check not found",
+			query:        "?resolveEvents=true",
+			deleteErr:    &store.ErrNotFound{Key: "check1"},
+			expectedCode: NotFound,
+		},
+		{
+			name:         "unauthorized to update the events",
+			query:        "?resolveEvents=true",
+			denied:       "events",
+			expectedCode: PermissionDenied,
+		},
+		{
+			name:         "unauthorized to delete the silenced entries",
+			query:        "?resolveEvents=true",
+			denied:       "silenced",
+			expectedCode: PermissionDenied,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			st := &mockstore.MockStore{}
+			bus := &mockbus.MockBus{}
+			st.On("DeleteResource", mock.Anything, "checks", "check1").Return(tc.deleteErr)
+			st.On("GetEvents", mock.Anything, mock.Anything).
+				Return([]*corev2.Event{failing, passing, other}, nil)
+			st.On("GetSilencedEntriesByCheckName", mock.Anything).
+				Return([]*corev2.Silenced{silenced}, nil)
+			st.On("DeleteSilencedEntryByName", mock.Anything, []string{silenced.Name}).Return(nil)
+			bus.On("Publish", messaging.TopicEventRaw, mock.Anything).Return(nil)
+
+			authorizer := deniedResourceAuthorizer{denied: tc.denied}
+			deleter := CheckDeleter{Store: st, EventStore: st, Bus: bus, Authorizer: authorizer}
+			req := httptest.NewRequest("DELETE", "/checks/check1"+tc.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "check1"})
+			attrs := &authorization.Attributes{Namespace: "default", User: corev2.User{Username: "admin"}}
+			req = req.WithContext(authorization.SetAttributes(req.Context(), attrs))
+
+			_, err := deleter.Delete(req)
+			if tc.expectedCode != 0 {
+				inferErr, ok := err.(Error)
+				if !ok {
+					t.Fatalf("expected actions error, got %v", err)
+				}
+				assert.Equal(t, tc.expectedCode, inferErr.Code)
+				if tc.denied != "" {
+					// Nothing is deleted nor resolved without authorization
+					st.AssertNotCalled(t, "DeleteResource", mock.Anything, mock.Anything, mock.Anything)
+					bus.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !tc.expectResolve {
+				bus.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+				st.AssertNotCalled(t, "DeleteSilencedEntryByName", mock.Anything, mock.Anything)
+				return
+			}
+
+			bus.AssertNumberOfCalls(t, "Publish", 1)
+			resolution := bus.Calls[0].Arguments.Get(1).(*corev2.Event)
+			assert.Equal(t, "entity1", resolution.Entity.Name)
+			assert.Equal(t, "check1", resolution.Check.Name)
+			assert.Equal(t, uint32(0), resolution.Check.Status)
+			assert.Equal(t, deletedCheckOutput, resolution.Check.Output)
+			assert.Equal(t, uint32(2), failing.Check.Status)
+			st.AssertCalled(t, "DeleteSilencedEntryByName", mock.Anything, []string{silenced.Name})
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package actions
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// EntityLabeler sets and removes the labels of many entities at once.
+type EntityLabeler struct {
+	Store       store.EntityStore
+	LabelPolicy store.LabelPolicyStore
+}
+
+// Label applies the labeling to the entities of the namespace stored in ctx
+// matching its selector, and returns the names of the entities whose labels
+// changed. The label policy is enforced on the labeled entities.
+func (l EntityLabeler) Label(ctx context.Context, labeling *corev2.EntityLabeling) (*corev2.EntityLabelingResult, error) {
+	if err := labeling.Validate(); err != nil {
+		return nil, NewError(InvalidArgument, err)
+	}
+
+	var policy *corev2.LabelPolicy
+	if l.LabelPolicy != nil {
+		var err error
+		if policy, err = l.LabelPolicy.GetLabelPolicy(ctx); err != nil {
+			return nil, NewError(InternalErr, err)
+		}
+	}
+
+	entities, err := l.Store.LabelEntities(ctx, labeling, policy)
+	if err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotValid:
+			return nil, NewError(InvalidArgument, err)
+		default:
+			return nil, NewError(InternalErr, err)
+		}
+	}
+
+	return &corev2.EntityLabelingResult{Entities: entities}, nil
+}
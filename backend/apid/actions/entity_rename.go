@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// EntityRenamer renames entities, or merges them into existing entities,
+// without losing their events and silenced entries.
+type EntityRenamer struct {
+	Store      store.EntityStore
+	Authorizer authorization.Authorizer
+}
+
+// Rename renames the entity of the given name, within the namespace stored in
+// ctx, and returns the renamed entity. The request is only authorized to
+// delete the entity, so the user is also authorized to write the entity of the
+// new name, the events and the silenced entries before renaming.
+func (r EntityRenamer) Rename(ctx context.Context, name string, rename *corev2.EntityRename) (*corev2.Entity, error) {
+	if err := rename.Validate(); err != nil {
+		return nil, NewError(InvalidArgument, err)
+	}
+	if err := r.authorize(ctx, rename); err != nil {
+		return nil, err
+	}
+
+	entity, err := r.Store.RenameEntity(ctx, name, rename.Name, rename.Merge)
+	if err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotFound:
+			return nil, NewErrorf(NotFound)
+		case *store.ErrAlreadyExists:
+			return nil, NewErrorf(AlreadyExistsErr, "entity %s already exists, merge it to keep both histories", rename.Name)
+		case *store.ErrNotValid:
+			return nil, NewError(InvalidArgument, err)
+		default:
+			return nil, NewError(InternalErr, err)
+		}
+	}
+
+	return entity, nil
+}
+
+// authorize authorizes the writes of the rename besides the deletion of the
+// renamed entity. The events and silenced entries moved by the rename are
+// only known to the store, so they require the verb on their whole
+// collection.
+func (r EntityRenamer) authorize(ctx context.Context, rename *corev2.EntityRename) error {
+	if err := authorize(ctx, r.Authorizer, "create", "entities", rename.Name); err != nil {
+		return err
+	}
+	if rename.Merge {
+		if err := authorize(ctx, r.Authorizer, "update", "entities", rename.Name); err != nil {
+			return err
+		}
+	}
+	if err := authorize(ctx, r.Authorizer, "update", "events", ""); err != nil {
+		return err
+	}
+	return authorize(ctx, r.Authorizer, "update", "silenced", "")
+}
@@ -1,6 +1,9 @@
 package actions
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 //
 // Following defines error type w/ error codes. Helpful for
@@ -42,6 +45,10 @@ const (
 	// PaymentRequired is used when the user tries to use a feature that's gated
 	// behind a license.
 	PaymentRequired
+
+	// Conflict means that the action conflicts with the current state of the
+	// resource. Eg. if it changes fields owned by another field manager.
+	Conflict
 )
 
 // Default error messages if not message is provided.
@@ -53,6 +60,7 @@ var standardErrorMessages = map[ErrCode]string{
 	PermissionDenied: "unauthorized to perform action",
 	Unauthenticated:  "unauthenticated",
 	PaymentRequired:  "license required",
+	Conflict:         "conflict with the current state of the resource",
 }
 
 // Error describes an issue that ocurred while performing the action.
@@ -63,6 +71,39 @@ type Error struct {
 	// Message is a developer / operator friendly message briefly describing what
 	// occurred.
 	Message string
+	// Fields optionally describes each invalid field of the resource the
+	// action was performed on.
+	Fields []FieldError
+	// Denial optionally describes the permission a PermissionDenied error
+	// lacks.
+	Denial *Denial `json:",omitempty"`
+}
+
+// FieldError describes why a field of a resource is invalid.
+type FieldError struct {
+	// Field is the path of the field, e.g. check.interval
+	Field string `json:"field"`
+	// Message describes why the field is invalid
+	Message string `json:"message"`
+}
+
+// Denial describes a request denied by the authorization, so users can tell
+// which permission they lack.
+type Denial struct {
+	// Username is the user the request was denied to.
+	Username string `json:"username"`
+	// Verb is the denied verb, e.g. delete
+	Verb string `json:"verb"`
+	// Resource is the denied resource type, e.g. checks
+	Resource string `json:"resource"`
+	// ResourceName is the name of the denied resource, if any.
+	ResourceName string `json:"resource_name,omitempty"`
+	// Namespace is the namespace of the denied resource, empty for cluster
+	// wide resources.
+	Namespace string `json:"namespace,omitempty"`
+	// EvaluatedBindings are the role bindings and cluster role bindings of
+	// the user evaluated for the request.
+	EvaluatedBindings []string `json:"evaluated_bindings,omitempty"`
 }
 
 // Error method implements error interface
@@ -86,6 +127,20 @@ func NewErrorf(code ErrCode, s ...interface{}) Error {
 	return Error{Code: code, Message: fmt.Sprintf(f, s...)}
 }
 
+// NewFieldsError returns a new InvalidArgument Error describing the given
+// invalid fields.
+func NewFieldsError(fields []FieldError) Error {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return Error{
+		Code:    InvalidArgument,
+		Message: "invalid fields: " + strings.Join(messages, ", "),
+		Fields:  fields,
+	}
+}
+
 // StatusFromError extracts code from the given error.
 func StatusFromError(err error) (ErrCode, bool) {
 	erro, ok := err.(Error)
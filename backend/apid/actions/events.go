@@ -2,6 +2,8 @@ package actions
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/store"
@@ -9,17 +11,23 @@ import (
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 )
 
+// maxEventClockSkew is how far in the future the timestamps of events created
+// through the API can be, to tolerate clock skew between clients and backends.
+const maxEventClockSkew = 5 * time.Minute
+
 // EventController expose actions in which a viewer can perform.
 type EventController struct {
-	store store.EventStore
-	bus   messaging.MessageBus
+	store    store.EventStore
+	silenced store.SilencedStore
+	bus      messaging.MessageBus
 }
 
 // NewEventController returns new EventController
-func NewEventController(store store.EventStore, bus messaging.MessageBus) EventController {
+func NewEventController(store store.EventStore, silenced store.SilencedStore, bus messaging.MessageBus) EventController {
 	return EventController{
-		store: store,
-		bus:   bus,
+		store:    store,
+		silenced: silenced,
+		bus:      bus,
 	}
 }
 
@@ -66,6 +74,24 @@ func (a EventController) Get(ctx context.Context, entity, check string) (*corev2
 	return result, nil
 }
 
+// GetPipeline returns the record of the handling of the event indicated by the
+// supplied entity and check by the event pipeline.
+func (a EventController) GetPipeline(ctx context.Context, entity, check string) (*corev2.EventPipeline, error) {
+	if entity == "" || check == "" {
+		return nil, NewErrorf(InvalidArgument, "GetPipeline() requires both an entity and a check")
+	}
+
+	result, err := a.store.GetEventPipeline(ctx, entity, check)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	if result == nil {
+		return nil, NewErrorf(NotFound)
+	}
+
+	return result, nil
+}
+
 // Delete destroys the event indicated by the supplied entity and check.
 func (a EventController) Delete(ctx context.Context, entity, check string) error {
 	// Destroy (for events) requires both an entity and check
@@ -89,9 +115,67 @@ func (a EventController) Delete(ctx context.Context, entity, check string) error
 	return nil
 }
 
+// Snooze silences the event indicated by the supplied entity and check for the
+// given duration, with a silenced entry targeting the entity and the check.
+// The silenced entry is deleted by the store once it expires. Snoozing fails
+// with AlreadyExistsErr if the event is already silenced by an entry of the
+// same name, manual or snoozed, which is left untouched.
+func (a EventController) Snooze(ctx context.Context, entity, check string, duration time.Duration) (*corev2.Silenced, error) {
+	if entity == "" || check == "" {
+		return nil, NewErrorf(InvalidArgument, "Snooze() requires both an entity and a check")
+	}
+	if duration < time.Second {
+		return nil, NewErrorf(InvalidArgument, "the snooze duration must be at least one second")
+	}
+
+	event, err := a.store.GetEventByEntityCheck(ctx, entity, check)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	if event == nil {
+		return nil, NewErrorf(NotFound)
+	}
+
+	now := time.Now()
+	silenced := &corev2.Silenced{
+		ObjectMeta: corev2.ObjectMeta{
+			Namespace: corev2.ContextNamespace(ctx),
+			Annotations: map[string]string{
+				corev2.SnoozedAtAnnotation:      now.UTC().Format(time.RFC3339),
+				corev2.SnoozeDurationAnnotation: duration.String(),
+			},
+		},
+		Subscription: corev2.GetEntitySubscription(entity),
+		Check:        check,
+		Begin:        now.Unix(),
+		Expire:       int64(duration / time.Second),
+		Reason:       fmt.Sprintf("snoozed for %s", duration),
+	}
+	silenced.Prepare(ctx)
+	if err := silenced.Validate(); err != nil {
+		return nil, NewError(InvalidArgument, err)
+	}
+
+	if existing, err := a.silenced.GetSilencedEntryByName(ctx, silenced.Name); err != nil {
+		return nil, NewError(InternalErr, err)
+	} else if existing != nil {
+		return nil, NewErrorf(AlreadyExistsErr, "the event is already silenced by %s", silenced.Name)
+	}
+
+	if err := a.silenced.UpdateSilencedEntry(ctx, silenced); err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+
+	return silenced, nil
+}
+
 // CreateOrReplace creates the event indicated by the supplied entity and check.
 // If an event already exists for the entity and check, it updates that event.
 func (a EventController) CreateOrReplace(ctx context.Context, event *corev2.Event) error {
+	if fields := prepareEvent(event, time.Now()); len(fields) > 0 {
+		return NewFieldsError(fields)
+	}
+	event.SetSource(corev2.EventSourceAPI, contextUsername(ctx))
 	if err := event.Validate(); err != nil {
 		return NewError(InvalidArgument, err)
 	}
@@ -103,3 +187,84 @@ func (a EventController) CreateOrReplace(ctx context.Context, event *corev2.Even
 
 	return nil
 }
+
+// prepareEvent adds the missing attributes of an event created through the
+// API, and returns the fields preventing it from being processed. Unlike the
+// events sent by agents, these events are crafted by hand and are checked more
+// thoroughly, since malformed events are not safe to process.
+func prepareEvent(event *corev2.Event, now time.Time) []FieldError {
+	var fields []FieldError
+	invalid := func(field, format string, args ...interface{}) {
+		fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	maxTimestamp := now.Add(maxEventClockSkew).Unix()
+
+	if event.Timestamp == 0 {
+		event.Timestamp = now.Unix()
+	} else if event.Timestamp < 0 || event.Timestamp > maxTimestamp {
+		invalid("timestamp", "must be a unix timestamp that is not in the future")
+	}
+
+	if event.Entity == nil {
+		invalid("entity", "is required")
+	} else {
+		if event.Entity.Namespace == "" {
+			event.Entity.Namespace = event.Namespace
+		}
+		if event.Entity.EntityClass == "" {
+			event.Entity.EntityClass = corev2.EntityProxyClass
+		}
+		if err := corev2.ValidateName(event.Entity.Name); err != nil {
+			invalid("entity.metadata.name", "%s", err)
+		}
+		if err := corev2.ValidateName(event.Entity.EntityClass); err != nil {
+			invalid("entity.entity_class", "%s", err)
+		}
+	}
+
+	if !event.HasCheck() && !event.HasMetrics() {
+		invalid("check", "is required for events without metrics")
+	}
+
+	if event.HasCheck() {
+		check := event.Check
+		if check.Namespace == "" {
+			check.Namespace = event.Namespace
+		}
+		if check.Executed == 0 {
+			check.Executed = event.Timestamp
+		} else if check.Executed < 0 || check.Executed > maxTimestamp {
+			invalid("check.executed", "must be a unix timestamp that is not in the future")
+		}
+		if check.Issued < 0 || check.Issued > maxTimestamp {
+			invalid("check.issued", "must be a unix timestamp that is not in the future")
+		}
+		if err := corev2.ValidateName(check.Name); err != nil {
+			invalid("check.metadata.name", "%s", err)
+		}
+		for i, history := range check.History {
+			if history.Executed < 0 || history.Executed > maxTimestamp {
+				invalid(fmt.Sprintf("check.history[%d].executed", i), "must be a unix timestamp that is not in the future")
+			}
+		}
+	}
+
+	if event.HasMetrics() {
+		for i, point := range event.Metrics.Points {
+			if point == nil {
+				invalid(fmt.Sprintf("metrics.points[%d]", i), "must not be null")
+			}
+		}
+	}
+
+	return fields
+}
+
+// contextUsername returns the name of the user of the API request, the
+// subject of its JWT.
+func contextUsername(ctx context.Context) string {
+	if claims, ok := ctx.Value(corev2.ClaimsKey).(*corev2.Claims); ok {
+		return claims.Subject
+	}
+	return ""
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/store"
@@ -11,6 +12,7 @@ import (
 	"github.com/sensu/sensu-go/testing/mockstore"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewEventController(t *testing.T) {
@@ -18,10 +20,11 @@ func TestNewEventController(t *testing.T) {
 
 	store := &mockstore.MockStore{}
 	bus := &mockbus.MockBus{}
-	eventController := NewEventController(store, bus)
+	eventController := NewEventController(store, store, bus)
 
 	assert.NotNil(eventController)
 	assert.Equal(store, eventController.store)
+	assert.Equal(store, eventController.silenced)
 	assert.Equal(bus, eventController.bus)
 }
 
@@ -81,7 +84,7 @@ func TestEventList(t *testing.T) {
 	for _, tc := range testCases {
 		s := &mockstore.MockStore{}
 		bus := &mockbus.MockBus{}
-		eventController := NewEventController(s, bus)
+		eventController := NewEventController(s, s, bus)
 
 		t.Run(tc.name, func(t *testing.T) {
 			assert := assert.New(t)
@@ -158,7 +161,7 @@ func TestEventFind(t *testing.T) {
 	for _, tc := range testCases {
 		store := &mockstore.MockStore{}
 		bus := &mockbus.MockBus{}
-		eventController := NewEventController(store, bus)
+		eventController := NewEventController(store, store, bus)
 
 		t.Run(tc.name, func(t *testing.T) {
 			assert := assert.New(t)
@@ -182,6 +185,68 @@ func TestEventFind(t *testing.T) {
 	}
 }
 
+func TestEventGetPipeline(t *testing.T) {
+	ctx := context.Background()
+	pipeline := &corev2.EventPipeline{Entity: "entity1", Check: "check1"}
+
+	testCases := []struct {
+		name            string
+		entity          string
+		check           string
+		pipeline        *corev2.EventPipeline
+		storeErr        error
+		wantErr         bool
+		expectedErrCode ErrCode
+	}{
+		{
+			name:            "No Params",
+			wantErr:         true,
+			expectedErrCode: InvalidArgument,
+		},
+		{
+			name:     "Found",
+			entity:   "entity1",
+			check:    "check1",
+			pipeline: pipeline,
+		},
+		{
+			name:            "Not Found",
+			entity:          "entity1",
+			check:           "check1",
+			wantErr:         true,
+			expectedErrCode: NotFound,
+		},
+		{
+			name:            "Store Error",
+			entity:          "entity1",
+			check:           "check1",
+			storeErr:        errors.New("error"),
+			wantErr:         true,
+			expectedErrCode: InternalErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			eventController := NewEventController(store, store, &mockbus.MockBus{})
+			store.On("GetEventPipeline", ctx, tc.entity, tc.check).Return(tc.pipeline, tc.storeErr)
+
+			result, err := eventController.GetPipeline(ctx, tc.entity, tc.check)
+			if tc.wantErr {
+				inferErr, ok := err.(Error)
+				if !ok {
+					t.Fatalf("expected actions error, got %v", err)
+				}
+				assert.Equal(t, tc.expectedErrCode, inferErr.Code)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.pipeline, result)
+		})
+	}
+}
+
 func TestEventDestroy(t *testing.T) {
 	defaultCtx := context.Background()
 
@@ -231,7 +296,7 @@ func TestEventDestroy(t *testing.T) {
 	for _, tc := range testCases {
 		store := &mockstore.MockStore{}
 		bus := &mockbus.MockBus{}
-		eventController := NewEventController(store, bus)
+		eventController := NewEventController(store, store, bus)
 
 		t.Run(tc.name, func(t *testing.T) {
 			assert := assert.New(t)
@@ -257,6 +322,99 @@ func TestEventDestroy(t *testing.T) {
 	}
 }
 
+func TestEventSnooze(t *testing.T) {
+	ctx := store.NamespaceContext(context.Background(), "default")
+	ctx = context.WithValue(ctx, corev2.ClaimsKey, corev2.FixtureClaims("alice", nil))
+
+	testCases := []struct {
+		name            string
+		event           *corev2.Event
+		entity          string
+		check           string
+		duration        time.Duration
+		storeErr        error
+		existing        *corev2.Silenced
+		wantErr         bool
+		expectedErrCode ErrCode
+	}{
+		{
+			name:            "no check",
+			entity:          "entity1",
+			duration:        time.Hour,
+			wantErr:         true,
+			expectedErrCode: InvalidArgument,
+		},
+		{
+			name:            "duration too short",
+			entity:          "entity1",
+			check:           "check1",
+			duration:        time.Millisecond,
+			wantErr:         true,
+			expectedErrCode: InvalidArgument,
+		},
+		{
+			name:            "not found",
+			entity:          "entity1",
+			check:           "check1",
+			duration:        time.Hour,
+			wantErr:         true,
+			expectedErrCode: NotFound,
+		},
+		{
+			name:            "store error",
+			event:           corev2.FixtureEvent("entity1", "check1"),
+			entity:          "entity1",
+			check:           "check1",
+			duration:        time.Hour,
+			storeErr:        errors.New("error"),
+			wantErr:         true,
+			expectedErrCode: InternalErr,
+		},
+		{
+			name:            "already silenced",
+			event:           corev2.FixtureEvent("entity1", "check1"),
+			entity:          "entity1",
+			check:           "check1",
+			duration:        time.Hour,
+			existing:        corev2.FixtureSilenced("entity:entity1:check1"),
+			wantErr:         true,
+			expectedErrCode: AlreadyExistsErr,
+		},
+		{
+			name:     "snoozed",
+			event:    corev2.FixtureEvent("entity1", "check1"),
+			entity:   "entity1",
+			check:    "check1",
+			duration: 2 * time.Hour,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			eventController := NewEventController(store, store, &mockbus.MockBus{})
+			store.On("GetEventByEntityCheck", ctx, tc.entity, tc.check).Return(tc.event, nil)
+			store.On("GetSilencedEntryByName", ctx, mock.Anything).Return(tc.existing, nil)
+			store.On("UpdateSilencedEntry", ctx, mock.Anything).Return(tc.storeErr)
+
+			silenced, err := eventController.Snooze(ctx, tc.entity, tc.check, tc.duration)
+			if tc.wantErr {
+				inferErr, ok := err.(Error)
+				require.True(t, ok)
+				assert.Equal(t, tc.expectedErrCode, inferErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "entity:entity1:check1", silenced.Name)
+			assert.Equal(t, "default", silenced.Namespace)
+			assert.Equal(t, int64(7200), silenced.Expire)
+			assert.Equal(t, "alice", silenced.Creator)
+			assert.Equal(t, "2h0m0s", silenced.Annotations[corev2.SnoozeDurationAnnotation])
+			assert.NotEmpty(t, silenced.Annotations[corev2.SnoozedAtAnnotation])
+		})
+	}
+}
+
 func TestEventCreateOrReplace(t *testing.T) {
 	defaultCtx := context.Background()
 
@@ -305,7 +463,7 @@ func TestEventCreateOrReplace(t *testing.T) {
 	for _, tc := range testCases {
 		store := &mockstore.MockStore{}
 		bus := &mockbus.MockBus{}
-		actions := NewEventController(store, bus)
+		actions := NewEventController(store, store, bus)
 
 		t.Run(tc.name, func(t *testing.T) {
 			assert := assert.New(t)
@@ -333,3 +491,94 @@ func TestEventCreateOrReplace(t *testing.T) {
 		})
 	}
 }
+
+func TestEventCreateOrReplaceSource(t *testing.T) {
+	ctx := context.WithValue(context.Background(), corev2.ClaimsKey, corev2.FixtureClaims("alice", nil))
+	bus := &mockbus.MockBus{}
+	bus.On("Publish", mock.Anything, mock.Anything).Return(nil)
+	actions := NewEventController(&mockstore.MockStore{}, &mockstore.MockStore{}, bus)
+
+	// The source submitted with the event is replaced
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Annotations = map[string]string{corev2.EventSourceAnnotation: "agent:entity1"}
+	require.NoError(t, actions.CreateOrReplace(ctx, event))
+	assert.Equal(t, "api:alice", event.Source())
+}
+
+func TestPrepareEvent(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name           string
+		event          func() *corev2.Event
+		expectedFields []string
+		assertion      func(*testing.T, *corev2.Event)
+	}{
+		{
+			name:  "valid event",
+			event: func() *corev2.Event { return corev2.FixtureEvent("entity1", "check1") },
+		},
+		{
+			name: "defaults",
+			event: func() *corev2.Event {
+				return &corev2.Event{
+					ObjectMeta: corev2.NewObjectMeta("", "default"),
+					Entity:     &corev2.Entity{ObjectMeta: corev2.ObjectMeta{Name: "entity1"}},
+					Check:      &corev2.Check{ObjectMeta: corev2.ObjectMeta{Name: "check1"}},
+				}
+			},
+			assertion: func(t *testing.T, event *corev2.Event) {
+				assert.Equal(t, now.Unix(), event.Timestamp)
+				assert.Equal(t, corev2.EntityProxyClass, event.Entity.EntityClass)
+				assert.Equal(t, "default", event.Entity.Namespace)
+				assert.Equal(t, "default", event.Check.Namespace)
+				assert.Equal(t, uint32(0), event.Check.Interval)
+				assert.Equal(t, now.Unix(), event.Check.Executed)
+				event.SetSource(corev2.EventSourceAPI, "admin")
+				assert.NoError(t, event.Validate())
+			},
+		},
+		{
+			name: "invalid fields",
+			event: func() *corev2.Event {
+				event := corev2.FixtureEvent("entity1", "check1")
+				event.Timestamp = now.Add(time.Hour).Unix()
+				event.Entity.Name = ""
+				event.Check.Executed = -1
+				event.Check.Name = "!@#"
+				return event
+			},
+			expectedFields: []string{"timestamp", "entity.metadata.name", "check.executed", "check.metadata.name"},
+		},
+		{
+			name: "missing entity and check",
+			event: func() *corev2.Event {
+				return &corev2.Event{ObjectMeta: corev2.NewObjectMeta("", "default")}
+			},
+			expectedFields: []string{"entity", "check"},
+		},
+		{
+			name: "null metric point",
+			event: func() *corev2.Event {
+				event := corev2.FixtureEvent("entity1", "check1")
+				event.Metrics = &corev2.Metrics{Points: []*corev2.MetricPoint{nil}}
+				return event
+			},
+			expectedFields: []string{"metrics.points[0]"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event := tc.event()
+			var fields []string
+			for _, field := range prepareEvent(event, now) {
+				fields = append(fields, field.Field)
+			}
+			assert.Equal(t, tc.expectedFields, fields)
+			if tc.assertion != nil {
+				tc.assertion(t, event)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package actions
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// LabelPolicyController exposes actions which a viewer can perform on the
+// label policy of the cluster.
+type LabelPolicyController struct {
+	store store.LabelPolicyStore
+}
+
+// NewLabelPolicyController returns a new LabelPolicyController
+func NewLabelPolicyController(store store.LabelPolicyStore) LabelPolicyController {
+	return LabelPolicyController{
+		store: store,
+	}
+}
+
+// Get gets the label policy, which has no rule if none was configured.
+func (c LabelPolicyController) Get(ctx context.Context) (*corev2.LabelPolicy, error) {
+	policy, err := c.store.GetLabelPolicy(ctx)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	if policy == nil {
+		policy = &corev2.LabelPolicy{Rules: []corev2.LabelPolicyRule{}}
+	}
+	return policy, nil
+}
+
+// Update creates or updates the label policy.
+func (c LabelPolicyController) Update(ctx context.Context, policy *corev2.LabelPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return NewError(InvalidArgument, err)
+	}
+
+	if err := c.store.UpdateLabelPolicy(ctx, policy); err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotValid:
+			return NewError(InvalidArgument, err)
+		default:
+			return NewError(InternalErr, err)
+		}
+	}
+
+	return nil
+}
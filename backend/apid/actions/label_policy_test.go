@@ -0,0 +1,114 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetLabelPolicy(t *testing.T) {
+	policy := &corev2.LabelPolicy{
+		Rules: []corev2.LabelPolicyRule{
+			{Resources: []string{"checks"}, Labels: map[string]string{"team": ""}},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		storePolicy     *corev2.LabelPolicy
+		storeErr        error
+		expected        *corev2.LabelPolicy
+		expectedErr     bool
+		expectedErrCode ErrCode
+	}{
+		{
+			name:        "Configured policy",
+			storePolicy: policy,
+			expected:    policy,
+		},
+		{
+			name:     "No policy",
+			expected: &corev2.LabelPolicy{Rules: []corev2.LabelPolicyRule{}},
+		},
+		{
+			name:            "Store error",
+			storeErr:        errors.New("some error"),
+			expectedErr:     true,
+			expectedErrCode: InternalErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			store.On("GetLabelPolicy", mock.Anything).Return(tc.storePolicy, tc.storeErr)
+			actions := NewLabelPolicyController(store)
+
+			result, err := actions.Get(context.Background())
+			if tc.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedErrCode, err.(Error).Code)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestUpdateLabelPolicy(t *testing.T) {
+	testCases := []struct {
+		name            string
+		argument        *corev2.LabelPolicy
+		storeErr        error
+		expectedErr     bool
+		expectedErrCode ErrCode
+	}{
+		{
+			name: "Update",
+			argument: &corev2.LabelPolicy{
+				Rules: []corev2.LabelPolicyRule{
+					{Resources: []string{"entities"}, Labels: map[string]string{"team": "^[a-z]+$"}},
+				},
+			},
+		},
+		{
+			name: "Invalid policy",
+			argument: &corev2.LabelPolicy{
+				Rules: []corev2.LabelPolicyRule{
+					{Resources: []string{"entities"}, Labels: map[string]string{"team": "["}},
+				},
+			},
+			expectedErr:     true,
+			expectedErrCode: InvalidArgument,
+		},
+		{
+			name:            "Store error",
+			argument:        &corev2.LabelPolicy{},
+			storeErr:        errors.New("some error"),
+			expectedErr:     true,
+			expectedErrCode: InternalErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			store.On("UpdateLabelPolicy", mock.Anything, tc.argument).Return(tc.storeErr)
+			actions := NewLabelPolicyController(store)
+
+			err := actions.Update(context.Background(), tc.argument)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedErrCode, err.(Error).Code)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
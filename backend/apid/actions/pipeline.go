@@ -0,0 +1,48 @@
+package actions
+
+import (
+	"context"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/pipelined"
+)
+
+// PipelineSimulator takes events through the event pipeline without
+// executing their handlers.
+type PipelineSimulator interface {
+	Simulate(ctx context.Context, event *corev2.Event, executeMutators bool) (*pipelined.Simulation, error)
+}
+
+// PipelineController exposes actions which a viewer can perform on the event
+// pipeline.
+type PipelineController struct {
+	simulator PipelineSimulator
+}
+
+// NewPipelineController returns a new PipelineController
+func NewPipelineController(simulator PipelineSimulator) PipelineController {
+	return PipelineController{
+		simulator: simulator,
+	}
+}
+
+// Simulate reports which filters would deny the event, what its mutators
+// would produce and which handlers would be executed, without publishing it.
+// The on-call, pipe and extension mutators are only executed if
+// executeMutators is true.
+func (c PipelineController) Simulate(ctx context.Context, event *corev2.Event, executeMutators bool) (*pipelined.Simulation, error) {
+	if fields := prepareEvent(event, time.Now()); len(fields) > 0 {
+		return nil, NewFieldsError(fields)
+	}
+	if err := event.Validate(); err != nil {
+		return nil, NewError(InvalidArgument, err)
+	}
+
+	simulation, err := c.simulator.Simulate(ctx, event, executeMutators)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+
+	return simulation, nil
+}
@@ -0,0 +1,43 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/pipelined"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPipelineSimulator struct {
+	mock.Mock
+}
+
+func (m *mockPipelineSimulator) Simulate(ctx context.Context, event *corev2.Event, executeMutators bool) (*pipelined.Simulation, error) {
+	args := m.Called(ctx, event, executeMutators)
+	return args.Get(0).(*pipelined.Simulation), args.Error(1)
+}
+
+func TestPipelineSimulate(t *testing.T) {
+	simulator := &mockPipelineSimulator{}
+	simulation := &pipelined.Simulation{}
+	simulator.On("Simulate", mock.Anything, mock.Anything, false).Return(simulation, nil)
+	controller := NewPipelineController(simulator)
+
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Timestamp = 0
+	result, err := controller.Simulate(context.Background(), event, false)
+	require.NoError(t, err)
+	assert.Equal(t, simulation, result)
+	assert.NotZero(t, event.Timestamp)
+
+	// Invalid events are not simulated
+	event = corev2.FixtureEvent("entity1", "check1")
+	event.Check.Name = ""
+	_, err = controller.Simulate(context.Background(), event, false)
+	require.Error(t, err)
+	assert.Equal(t, InvalidArgument, err.(Error).Code)
+	simulator.AssertNumberOfCalls(t, "Simulate", 1)
+}
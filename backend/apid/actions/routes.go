@@ -0,0 +1,81 @@
+package actions
+
+import (
+	"context"
+	"errors"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// RouteController exposes actions which a viewer can perform on the
+// cluster-wide notification routes.
+type RouteController struct {
+	store store.RouteStore
+}
+
+// NewRouteController returns a new RouteController
+func NewRouteController(store store.RouteStore) RouteController {
+	return RouteController{
+		store: store,
+	}
+}
+
+// List returns all the routes.
+func (c RouteController) List(ctx context.Context) ([]*corev2.Route, error) {
+	routes, err := c.store.GetRoutes(ctx)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	return routes, nil
+}
+
+// Find returns the route of the given name.
+func (c RouteController) Find(ctx context.Context, name string) (*corev2.Route, error) {
+	route, err := c.store.GetRouteByName(ctx, name)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	if route == nil {
+		return nil, NewErrorf(NotFound)
+	}
+	return route, nil
+}
+
+// CreateOrReplace creates or replaces the route of the given name.
+func (c RouteController) CreateOrReplace(ctx context.Context, name string, route *corev2.Route) error {
+	if route.Name == "" {
+		route.Name = name
+	}
+	if route.Name != name {
+		return NewError(InvalidArgument, errors.New("the name of the route does not match the URL"))
+	}
+	if err := route.Validate(); err != nil {
+		return NewError(InvalidArgument, err)
+	}
+
+	if err := c.store.UpdateRoute(ctx, route); err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotValid:
+			return NewError(InvalidArgument, err)
+		default:
+			return NewError(InternalErr, err)
+		}
+	}
+
+	return nil
+}
+
+// Destroy deletes the route of the given name.
+func (c RouteController) Destroy(ctx context.Context, name string) error {
+	if err := c.store.DeleteRouteByName(ctx, name); err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotFound:
+			return NewError(NotFound, err)
+		default:
+			return NewError(InternalErr, err)
+		}
+	}
+
+	return nil
+}
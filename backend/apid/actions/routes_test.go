@@ -0,0 +1,154 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFindRoute(t *testing.T) {
+	route := &corev2.Route{Name: "db", Handlers: []string{"opsgenie"}}
+
+	testCases := []struct {
+		name            string
+		storeRoute      *corev2.Route
+		storeErr        error
+		expectedErr     bool
+		expectedErrCode ErrCode
+	}{
+		{
+			name:       "Found",
+			storeRoute: route,
+		},
+		{
+			name:            "Not found",
+			expectedErr:     true,
+			expectedErrCode: NotFound,
+		},
+		{
+			name:            "Store error",
+			storeErr:        errors.New("some error"),
+			expectedErr:     true,
+			expectedErrCode: InternalErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			store.On("GetRouteByName", mock.Anything, "db").Return(tc.storeRoute, tc.storeErr)
+			actions := NewRouteController(store)
+
+			result, err := actions.Find(context.Background(), "db")
+			if tc.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedErrCode, err.(Error).Code)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.storeRoute, result)
+		})
+	}
+}
+
+func TestCreateOrReplaceRoute(t *testing.T) {
+	testCases := []struct {
+		name            string
+		argument        *corev2.Route
+		storeErr        error
+		expectedErr     bool
+		expectedErrCode ErrCode
+	}{
+		{
+			name:     "Create",
+			argument: &corev2.Route{Name: "db", Handlers: []string{"opsgenie"}},
+		},
+		{
+			name:     "Name from the URL",
+			argument: &corev2.Route{Handlers: []string{"opsgenie"}},
+		},
+		{
+			name:            "Mismatched name",
+			argument:        &corev2.Route{Name: "web", Handlers: []string{"opsgenie"}},
+			expectedErr:     true,
+			expectedErrCode: InvalidArgument,
+		},
+		{
+			name:            "Invalid route",
+			argument:        &corev2.Route{Name: "db"},
+			expectedErr:     true,
+			expectedErrCode: InvalidArgument,
+		},
+		{
+			name:            "Store error",
+			argument:        &corev2.Route{Name: "db", Handlers: []string{"opsgenie"}},
+			storeErr:        errors.New("some error"),
+			expectedErr:     true,
+			expectedErrCode: InternalErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			store.On("UpdateRoute", mock.Anything, tc.argument).Return(tc.storeErr)
+			actions := NewRouteController(store)
+
+			err := actions.CreateOrReplace(context.Background(), "db", tc.argument)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedErrCode, err.(Error).Code)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "db", tc.argument.Name)
+		})
+	}
+}
+
+func TestDestroyRoute(t *testing.T) {
+	testCases := []struct {
+		name            string
+		storeErr        error
+		expectedErr     bool
+		expectedErrCode ErrCode
+	}{
+		{
+			name: "Delete",
+		},
+		{
+			name:            "Not found",
+			storeErr:        &store.ErrNotFound{Key: "db"},
+			expectedErr:     true,
+			expectedErrCode: NotFound,
+		},
+		{
+			name:            "Store error",
+			storeErr:        errors.New("some error"),
+			expectedErr:     true,
+			expectedErrCode: InternalErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			store.On("DeleteRouteByName", mock.Anything, "db").Return(tc.storeErr)
+			actions := NewRouteController(store)
+
+			err := actions.Destroy(context.Background(), "db")
+			if tc.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedErrCode, err.(Error).Code)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -12,7 +12,9 @@ import (
 
 	"github.com/coreos/etcd/clientv3"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/middlewares"
 	"github.com/sensu/sensu-go/backend/apid/routers"
@@ -20,6 +22,7 @@ import (
 	"github.com/sensu/sensu-go/backend/authorization/rbac"
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/usage"
 	"github.com/sensu/sensu-go/types"
 )
 
@@ -38,10 +41,19 @@ type APId struct {
 	store               store.Store
 	eventStore          store.EventStore
 	queueGetter         types.QueueGetter
+	pipelineSimulator   actions.PipelineSimulator
 	tls                 *types.TLSOptions
 	cluster             clientv3.Cluster
+	client              *clientv3.Client
 	etcdClientTLSConfig *tls.Config
 	clusterVersion      string
+	backendConfig       *corev2.BackendConfig
+	storeMaintainer     routers.StoreMaintainer
+	storeInfoSampler    routers.StoreInfoSampler
+	usageTracker        *usage.Tracker
+	filterLists         bool
+	denialDetails       string
+	readiness           routers.Readiness
 }
 
 // Option is a functional option.
@@ -55,11 +67,31 @@ type Config struct {
 	Store               store.Store
 	EventStore          store.EventStore
 	QueueGetter         types.QueueGetter
+	PipelineSimulator   actions.PipelineSimulator
 	TLS                 *types.TLSOptions
 	Cluster             clientv3.Cluster
+	Client              *clientv3.Client
 	EtcdClientTLSConfig *tls.Config
 	Authenticator       *authentication.Authenticator
 	ClusterVersion      string
+	BackendConfig       *corev2.BackendConfig
+	StoreMaintainer     routers.StoreMaintainer
+	StoreInfoSampler    routers.StoreInfoSampler
+	UsageTracker        *usage.Tracker
+	DebugAPI            bool
+
+	// FilterLists restricts the lists requested by the users only authorized
+	// for some resources of a collection, by name, to these resources,
+	// instead of denying the requests.
+	FilterLists bool
+
+	// DenialDetails is the level of details of the unauthorized requests in
+	// the response body: none, request or trace.
+	DenialDetails string
+
+	// Readiness reports whether the backend is warm on /ready. The backend
+	// is always reported ready if nil.
+	Readiness routers.Readiness
 }
 
 // New creates a new APId.
@@ -68,6 +100,7 @@ func New(c Config, opts ...Option) (*APId, error) {
 		store:               c.Store,
 		eventStore:          c.EventStore,
 		queueGetter:         c.QueueGetter,
+		pipelineSimulator:   c.PipelineSimulator,
 		tls:                 c.TLS,
 		bus:                 c.Bus,
 		stopping:            make(chan struct{}, 1),
@@ -75,9 +108,21 @@ func New(c Config, opts ...Option) (*APId, error) {
 		wg:                  &sync.WaitGroup{},
 		errChan:             make(chan error, 1),
 		cluster:             c.Cluster,
+		client:              c.Client,
 		etcdClientTLSConfig: c.EtcdClientTLSConfig,
 		Authenticator:       c.Authenticator,
 		clusterVersion:      c.ClusterVersion,
+		backendConfig:       c.BackendConfig,
+		storeMaintainer:     c.StoreMaintainer,
+		storeInfoSampler:    c.StoreInfoSampler,
+		usageTracker:        c.UsageTracker,
+		filterLists:         c.FilterLists,
+		denialDetails:       c.DenialDetails,
+		readiness:           c.Readiness,
+	}
+
+	if err := middlewares.ValidateDenialDetails(c.DenialDetails); err != nil {
+		return nil, err
 	}
 
 	// prepare TLS configs (both server and client)
@@ -106,10 +151,15 @@ func New(c Config, opts ...Option) (*APId, error) {
 	router := mux.NewRouter().UseEncodedPath()
 	router.NotFoundHandler = middlewares.SimpleLogger{}.Then(http.HandlerFunc(notFoundHandler))
 	router.Handle("/metrics", promhttp.Handler())
-	registerUnauthenticatedResources(router, a.store, a.cluster, a.etcdClientTLSConfig, a.clusterVersion, a.bus)
+	registerUnauthenticatedResources(router, a.store, a.cluster, a.etcdClientTLSConfig, a.clusterVersion, a.bus, a.readiness)
 	a.registerGraphQLService(router, c.URL, tlsClientConfig)
 	registerAuthenticationResources(router, a.store, a.Authenticator)
 	a.registerRestrictedResources(router)
+	a.registerAlertmanagerResources(router)
+	a.registerGrafanaResources(router)
+	if c.DebugAPI {
+		a.registerDebugResources(router)
+	}
 
 	a.HTTPServer = &http.Server{
 		Addr:         c.ListenAddress,
@@ -193,6 +243,7 @@ func registerUnauthenticatedResources(
 	etcdClientTLSConfig *tls.Config,
 	clusterVersion string,
 	bus messaging.MessageBus,
+	readiness routers.Readiness,
 ) {
 	mountRouters(
 		NewSubrouter(
@@ -201,6 +252,8 @@ func registerUnauthenticatedResources(
 			middlewares.LimitRequest{},
 		),
 		routers.NewHealthRouter(actions.NewHealthController(store, cluster, etcdClientTLSConfig)),
+		routers.NewReadyRouter(readiness),
+		routers.NewAutoscalingRouter(actions.NewAutoscalingController(prometheus.DefaultGatherer)),
 		routers.NewVersionRouter(actions.NewVersionController(clusterVersion)),
 		routers.NewTessenMetricRouter(actions.NewTessenMetricController(bus)),
 	)
@@ -241,6 +294,12 @@ func registerAuthenticationResources(router *mux.Router, store store.Store, auth
 }
 
 func (a *APId) registerRestrictedResources(router *mux.Router) {
+	// A nil tracker must not be wrapped in a non-nil interface
+	var usageTracker routers.UsageTracker
+	if a.usageTracker != nil {
+		usageTracker = a.usageTracker
+	}
+
 	a.CoreSubrouter = NewSubrouter(
 		router.NewRoute().
 			PathPrefix("/api/{group:core}/{version:v2}/"),
@@ -249,33 +308,102 @@ func (a *APId) registerRestrictedResources(router *mux.Router) {
 		middlewares.Authentication{},
 		middlewares.AllowList{Store: a.store},
 		middlewares.AuthorizationAttributes{},
-		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: a.store}},
+		middlewares.Usage{Tracker: a.usageTracker},
+		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: a.store}, FilterLists: a.filterLists, DenialDetails: a.denialDetails},
 		middlewares.LimitRequest{},
 		middlewares.Pagination{},
 	)
 	mountRouters(
 		a.CoreSubrouter,
+		routers.NewAgentCommandRouter(actions.NewAgentCommandController(a.bus)),
+		routers.NewAgentConfigRouter(actions.NewAgentConfigController(a.store)),
+		routers.NewAgentSpoolRouter(actions.NewAgentSpoolController(a.bus)),
 		routers.NewAssetRouter(a.store),
-		routers.NewChecksRouter(a.store, a.queueGetter),
+		routers.NewChecksRouter(a.store, a.eventStore, a.queueGetter, a.bus),
 		routers.NewClusterRolesRouter(a.store),
 		routers.NewClusterRoleBindingsRouter(a.store),
-		routers.NewClusterRouter(actions.NewClusterController(a.cluster, a.store)),
+		routers.NewClusterRouter(actions.NewClusterController(a.cluster, a.client, a.store, a.etcdClientTLSConfig)),
+		routers.NewClusterConfigRouter(a.backendConfig),
+		routers.NewClusterMaintenanceRouter(a.storeMaintainer),
+		routers.NewClusterStoreInfoRouter(a.storeInfoSampler),
+		routers.NewConversionsRouter(),
 		routers.NewEntitiesRouter(a.store, a.eventStore),
+		routers.NewEnrollmentTokensRouter(a.store),
 		routers.NewEventFiltersRouter(a.store),
-		routers.NewEventsRouter(a.eventStore, a.bus),
+		routers.NewEventsRouter(a.eventStore, a.store, a.bus),
 		routers.NewExtensionsRouter(a.store),
 		routers.NewHandlersRouter(a.store),
 		routers.NewHooksRouter(a.store),
+		routers.NewLabelPolicyRouter(actions.NewLabelPolicyController(a.store)),
 		routers.NewMutatorsRouter(a.store),
 		routers.NewNamespacesRouter(a.store),
+		routers.NewNotificationWebhooksRouter(a.store),
+		routers.NewPipelineRouter(actions.NewPipelineController(a.pipelineSimulator)),
+		routers.NewRemediationsRouter(a.store),
 		routers.NewRolesRouter(a.store),
 		routers.NewRoleBindingsRouter(a.store),
+		routers.NewRoutesRouter(actions.NewRouteController(a.store)),
 		routers.NewSilencedRouter(a.store),
 		routers.NewTessenRouter(actions.NewTessenController(a.store, a.bus)),
+		routers.NewUsageRouter(usageTracker),
 		routers.NewUsersRouter(a.store),
 	)
 }
 
+// registerAlertmanagerResources mounts the silences of the Alertmanager API
+// of each namespace, authorized as the silenced entries.
+func (a *APId) registerAlertmanagerResources(router *mux.Router) {
+	mountRouters(
+		NewSubrouter(
+			router.NewRoute().PathPrefix("/alertmanager/{namespace}/api/v2/"),
+			middlewares.SimpleLogger{},
+			middlewares.Namespace{},
+			middlewares.Authentication{},
+			middlewares.AllowList{Store: a.store},
+			middlewares.AuthorizationAttributes{},
+			middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: a.store}, DenialDetails: a.denialDetails},
+			middlewares.LimitRequest{},
+		),
+		routers.NewAlertmanagerRouter(a.store),
+	)
+}
+
+// registerGrafanaResources mounts the Grafana datasource of each namespace,
+// authorized as listing its events. Grafana can not refresh access tokens, so
+// it may authenticate with basic credentials instead.
+func (a *APId) registerGrafanaResources(router *mux.Router) {
+	mountRouters(
+		NewSubrouter(
+			router.NewRoute().PathPrefix("/grafana/{namespace}"),
+			middlewares.SimpleLogger{},
+			middlewares.Namespace{},
+			middlewares.BasicOrTokenAuthentication{Store: a.store},
+			middlewares.AllowList{Store: a.store},
+			middlewares.AuthorizationAttributes{},
+			middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: a.store}, DenialDetails: a.denialDetails},
+			middlewares.LimitRequest{},
+		),
+		routers.NewGrafanaRouter(a.eventStore),
+	)
+}
+
+// registerDebugResources mounts the debug endpoints, which are only
+// accessible to users granted the debug verb on the debug resource.
+func (a *APId) registerDebugResources(router *mux.Router) {
+	mountRouters(
+		NewSubrouter(
+			router.NewRoute(),
+			middlewares.SimpleLogger{},
+			middlewares.Authentication{},
+			middlewares.AllowList{Store: a.store},
+			middlewares.AuthorizationAttributes{},
+			middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: a.store}, DenialDetails: a.denialDetails},
+			middlewares.LimitRequest{},
+		),
+		routers.NewDebugRouter(),
+	)
+}
+
 func mountRouters(parent *mux.Router, subRouters ...routers.Router) {
 	for _, subRouter := range subRouters {
 		subRouter.Mount(parent)
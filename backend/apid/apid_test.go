@@ -0,0 +1,73 @@
+package apid
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugResources(t *testing.T) {
+	stor := &mockstore.MockStore{}
+	stor.On("GetToken", mock.Anything, mock.Anything).Return(&corev2.Claims{}, nil)
+	stor.On("ListClusterRoleBindings", mock.Anything, mock.Anything).
+		Return([]*corev2.ClusterRoleBinding{{
+			RoleRef: corev2.RoleRef{Type: "ClusterRole", Name: "cluster-admin"},
+			Subjects: []corev2.Subject{
+				{Type: corev2.UserType, Name: "admin"},
+			},
+			ObjectMeta: corev2.ObjectMeta{Name: "cluster-admin"},
+		}}, nil)
+	stor.On("GetClusterRole", mock.Anything, "cluster-admin", mock.Anything).
+		Return(&corev2.ClusterRole{Rules: []corev2.Rule{{
+			Verbs:     []string{corev2.VerbAll},
+			Resources: []string{corev2.ResourceAll},
+		}}}, nil)
+
+	router := mux.NewRouter().UseEncodedPath()
+	a := &APId{store: stor}
+	a.registerDebugResources(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	_, admin, err := jwt.AccessToken(corev2.FixtureClaims("admin", nil))
+	require.NoError(t, err)
+	_, user, err := jwt.AccessToken(corev2.FixtureClaims("user", nil))
+	require.NoError(t, err)
+
+	tests := []struct {
+		path         string
+		token        string
+		expectedCode int
+	}{
+		{"/debug/memstats", admin, http.StatusOK},
+		{"/debug/pprof/", admin, http.StatusOK},
+		{"/debug/pprof/heap", admin, http.StatusOK},
+		{"/debug/pprof/goroutine?debug=2", admin, http.StatusOK},
+		{"/debug/pprof/cmdline", admin, http.StatusOK},
+		{"/debug/memstats", user, http.StatusForbidden},
+		{"/debug/memstats", "", http.StatusUnauthorized},
+		{"/debug/debug/memstats", admin, http.StatusNotFound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+tc.path, nil)
+			require.NoError(t, err)
+			if tc.token != "" {
+				req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tc.token))
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+		})
+	}
+}
@@ -325,6 +325,9 @@ func getLoader(ctx context.Context, loaderKey key) (*dataloader.Loader, error) {
 
 // When resolving a field, GraphQL does not consider the absence of a value an
 // error; as such we omit the error if the API client returns Permission denied.
+// The lists are fetched through the API with the access token of the viewer,
+// so they are restricted to the resources it is granted by name like the API
+// lists are, when the backend filters lists.
 func handleListErr(err error) error {
 	if apiErr, ok := err.(client.APIError); ok {
 		if apiErr.Code == uint32(actions.PermissionDenied) {
@@ -295,6 +295,28 @@ func (r *mutationsImpl) DeleteEvent(p schema.MutationDeleteEventFieldResolverPar
 	}, nil
 }
 
+// SnoozeEvent implements response to request for the 'snoozeEvent' field.
+func (r *mutationsImpl) SnoozeEvent(p schema.MutationSnoozeEventFieldResolverParams) (interface{}, error) {
+	components, err := decodeEventGID(p.Args.Input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := setContextFromComponents(p.Context, components)
+	client := r.factory.NewWithContext(ctx)
+
+	duration := time.Duration(p.Args.Input.Duration) * time.Second
+	silenced, err := client.SnoozeEvent(components.Namespace(), components.EntityName(), components.CheckName(), duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"clientMutationId": p.Args.Input.ClientMutationID,
+		"silence":          silenced,
+	}, nil
+}
+
 func decodeEventGID(gid string) (globalid.EventComponents, error) {
 	components := globalid.EventComponents{}
 	parsedComponents, err := globalid.Parse(gid)
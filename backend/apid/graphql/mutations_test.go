@@ -1,8 +1,10 @@
 package graphql
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/sensu/sensu-go/backend/apid/graphql/globalid"
 	client "github.com/sensu/sensu-go/backend/apid/graphql/mockclient"
@@ -203,6 +205,39 @@ func TestMutationTypeDeleteEventField(t *testing.T) {
 	assert.Nil(t, body)
 }
 
+func TestMutationTypeSnoozeEventField(t *testing.T) {
+	evt := types.FixtureEvent("a", "b")
+	gid := globalid.EventTranslator.EncodeToString(evt)
+
+	inputs := schema.SnoozeEventInput{ID: gid, Duration: 7200}
+	params := schema.MutationSnoozeEventFieldResolverParams{}
+	params.Context = context.Background()
+	params.Args.Input = &inputs
+
+	client, factory := client.NewClientFactory()
+	impl := mutationsImpl{factory: factory}
+
+	// Success
+	silenced := types.FixtureSilenced("entity:a:b")
+	client.On("SnoozeEvent", mock.Anything, "a", "b", 2*time.Hour).Return(silenced, nil).Once()
+	body, err := impl.SnoozeEvent(params)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, body)
+
+	// Bad gid
+	params.Args.Input = &schema.SnoozeEventInput{ID: "tests", Duration: 7200}
+	body, err = impl.SnoozeEvent(params)
+	assert.Error(t, err)
+	assert.Nil(t, body)
+
+	// Snooze failed
+	params.Args.Input = &inputs
+	client.On("SnoozeEvent", mock.Anything, "a", "b", 2*time.Hour).Return(silenced, errors.New("err")).Once()
+	body, err = impl.SnoozeEvent(params)
+	assert.Error(t, err)
+	assert.Nil(t, body)
+}
+
 func TestMutationTypeDeleteHandlerField(t *testing.T) {
 	hd := types.FixtureHandler("a")
 	gid := globalid.HandlerTranslator.EncodeToString(hd)
@@ -151,6 +151,23 @@ type MutationDeleteEventFieldResolver interface {
 	DeleteEvent(p MutationDeleteEventFieldResolverParams) (interface{}, error)
 }
 
+// MutationSnoozeEventFieldResolverArgs contains arguments provided to snoozeEvent when selected
+type MutationSnoozeEventFieldResolverArgs struct {
+	Input *SnoozeEventInput // Input - self descriptive
+}
+
+// MutationSnoozeEventFieldResolverParams contains contextual info to resolve snoozeEvent field
+type MutationSnoozeEventFieldResolverParams struct {
+	graphql.ResolveParams
+	Args MutationSnoozeEventFieldResolverArgs
+}
+
+// MutationSnoozeEventFieldResolver implement to resolve requests for the Mutation's snoozeEvent field.
+type MutationSnoozeEventFieldResolver interface {
+	// SnoozeEvent implements response to request for snoozeEvent field.
+	SnoozeEvent(p MutationSnoozeEventFieldResolverParams) (interface{}, error)
+}
+
 // MutationDeleteEventFilterFieldResolverArgs contains arguments provided to deleteEventFilter when selected
 type MutationDeleteEventFilterFieldResolverArgs struct {
 	Input *DeleteRecordInput // Input - self descriptive
@@ -306,6 +323,7 @@ type MutationFieldResolvers interface {
 	MutationDeleteEntityFieldResolver
 	MutationResolveEventFieldResolver
 	MutationDeleteEventFieldResolver
+	MutationSnoozeEventFieldResolver
 	MutationDeleteEventFilterFieldResolver
 	MutationDeleteHandlerFieldResolver
 	MutationDeleteMutatorFieldResolver
@@ -408,6 +426,12 @@ func (_ MutationAliases) DeleteEvent(p MutationDeleteEventFieldResolverParams) (
 	return val, err
 }
 
+// SnoozeEvent implements response to request for 'snoozeEvent' field.
+func (_ MutationAliases) SnoozeEvent(p MutationSnoozeEventFieldResolverParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
 // DeleteEventFilter implements response to request for 'deleteEventFilter' field.
 func (_ MutationAliases) DeleteEventFilter(p MutationDeleteEventFilterFieldResolverParams) (interface{}, error) {
 	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
@@ -549,6 +573,19 @@ func _ObjTypeMutationDeleteEventHandler(impl interface{}) graphql1.FieldResolveF
 	}
 }
 
+func _ObjTypeMutationSnoozeEventHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(MutationSnoozeEventFieldResolver)
+	return func(p graphql1.ResolveParams) (interface{}, error) {
+		frp := MutationSnoozeEventFieldResolverParams{ResolveParams: p}
+		err := mapstructure.Decode(p.Args, &frp.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolver.SnoozeEvent(frp)
+	}
+}
+
 func _ObjTypeMutationDeleteEventFilterHandler(impl interface{}) graphql1.FieldResolveFn {
 	resolver := impl.(MutationDeleteEventFilterFieldResolver)
 	return func(p graphql1.ResolveParams) (interface{}, error) {
@@ -745,6 +782,16 @@ func _ObjectTypeMutationConfigFn() graphql1.ObjectConfig {
 				Name:              "resolveEvent",
 				Type:              graphql.OutputType("ResolveEventPayload"),
 			},
+			"snoozeEvent": &graphql1.Field{
+				Args: graphql1.FieldConfigArgument{"input": &graphql1.ArgumentConfig{
+					Description: "self descriptive",
+					Type:        graphql1.NewNonNull(graphql.InputType("SnoozeEventInput")),
+				}},
+				DeprecationReason: "",
+				Description:       "Silences an event for a given duration.",
+				Name:              "snoozeEvent",
+				Type:              graphql.OutputType("SnoozeEventPayload"),
+			},
 			"updateCheck": &graphql1.Field{
 				Args: graphql1.FieldConfigArgument{"input": &graphql1.ArgumentConfig{
 					Description: "self descriptive",
@@ -785,6 +832,7 @@ var _ObjectTypeMutationDesc = graphql.ObjectDesc{
 		"executeCheck":      _ObjTypeMutationExecuteCheckHandler,
 		"putWrapped":        _ObjTypeMutationPutWrappedHandler,
 		"resolveEvent":      _ObjTypeMutationResolveEventHandler,
+		"snoozeEvent":       _ObjTypeMutationSnoozeEventHandler,
 		"updateCheck":       _ObjTypeMutationUpdateCheckHandler,
 	},
 }
@@ -2317,6 +2365,253 @@ var _ObjectTypeResolveEventPayloadDesc = graphql.ObjectDesc{
 	},
 }
 
+// SnoozeEventInput self descriptive
+type SnoozeEventInput struct {
+	// ClientMutationID - A unique identifier for the client performing the mutation.
+	ClientMutationID string
+	// ID - Global ID of the event to snooze.
+	ID string
+	// Duration - Duration is the number of seconds the event will be silenced for.
+	Duration int
+}
+
+// SnoozeEventInputType self descriptive
+var SnoozeEventInputType = graphql.NewType("SnoozeEventInput", graphql.InputKind)
+
+// RegisterSnoozeEventInput registers SnoozeEventInput object type with given service.
+func RegisterSnoozeEventInput(svc *graphql.Service) {
+	svc.RegisterInput(_InputTypeSnoozeEventInputDesc)
+}
+func _InputTypeSnoozeEventInputConfigFn() graphql1.InputObjectConfig {
+	return graphql1.InputObjectConfig{
+		Description: "self descriptive",
+		Fields: graphql1.InputObjectConfigFieldMap{
+			"clientMutationId": &graphql1.InputObjectFieldConfig{
+				Description: "A unique identifier for the client performing the mutation.",
+				Type:        graphql1.String,
+			},
+			"duration": &graphql1.InputObjectFieldConfig{
+				Description: "Duration is the number of seconds the event will be silenced for.",
+				Type:        graphql1.NewNonNull(graphql1.Int),
+			},
+			"id": &graphql1.InputObjectFieldConfig{
+				Description: "Global ID of the event to snooze.",
+				Type:        graphql1.NewNonNull(graphql1.ID),
+			},
+		},
+		Name: "SnoozeEventInput",
+	}
+}
+
+// describe SnoozeEventInput's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _InputTypeSnoozeEventInputDesc = graphql.InputDesc{Config: _InputTypeSnoozeEventInputConfigFn}
+
+// SnoozeEventPayloadClientMutationIDFieldResolver implement to resolve requests for the SnoozeEventPayload's clientMutationId field.
+type SnoozeEventPayloadClientMutationIDFieldResolver interface {
+	// ClientMutationID implements response to request for clientMutationId field.
+	ClientMutationID(p graphql.ResolveParams) (string, error)
+}
+
+// SnoozeEventPayloadSilenceFieldResolver implement to resolve requests for the SnoozeEventPayload's silence field.
+type SnoozeEventPayloadSilenceFieldResolver interface {
+	// Silence implements response to request for silence field.
+	Silence(p graphql.ResolveParams) (interface{}, error)
+}
+
+//
+// SnoozeEventPayloadFieldResolvers represents a collection of methods whose products represent the
+// response values of the 'SnoozeEventPayload' type.
+//
+// == Example SDL
+//
+//   """
+//   Dog's are not hooman.
+//   """
+//   type Dog implements Pet {
+//     "name of this fine beast."
+//     name:  String!
+//
+//     "breed of this silly animal; probably shibe."
+//     breed: [Breed]
+//   }
+//
+// == Example generated interface
+//
+//   // DogResolver ...
+//   type DogFieldResolvers interface {
+//     DogNameFieldResolver
+//     DogBreedFieldResolver
+//
+//     // IsTypeOf is used to determine if a given value is associated with the Dog type
+//     IsTypeOf(interface{}, graphql.IsTypeOfParams) bool
+//   }
+//
+// == Example implementation ...
+//
+//   // DogResolver implements DogFieldResolvers interface
+//   type DogResolver struct {
+//     logger logrus.LogEntry
+//     store interface{
+//       store.BreedStore
+//       store.DogStore
+//     }
+//   }
+//
+//   // Name implements response to request for name field.
+//   func (r *DogResolver) Name(p graphql.ResolveParams) (interface{}, error) {
+//     // ... implementation details ...
+//     dog := p.Source.(DogGetter)
+//     return dog.GetName()
+//   }
+//
+//   // Breed implements response to request for breed field.
+//   func (r *DogResolver) Breed(p graphql.ResolveParams) (interface{}, error) {
+//     // ... implementation details ...
+//     dog := p.Source.(DogGetter)
+//     breed := r.store.GetBreed(dog.GetBreedName())
+//     return breed
+//   }
+//
+//   // IsTypeOf is used to determine if a given value is associated with the Dog type
+//   func (r *DogResolver) IsTypeOf(p graphql.IsTypeOfParams) bool {
+//     // ... implementation details ...
+//     _, ok := p.Value.(DogGetter)
+//     return ok
+//   }
+//
+type SnoozeEventPayloadFieldResolvers interface {
+	SnoozeEventPayloadClientMutationIDFieldResolver
+	SnoozeEventPayloadSilenceFieldResolver
+}
+
+// SnoozeEventPayloadAliases implements all methods on SnoozeEventPayloadFieldResolvers interface by using reflection to
+// match name of field to a field on the given value. Intent is reduce friction
+// of writing new resolvers by removing all the instances where you would simply
+// have the resolvers method return a field.
+//
+// == Example SDL
+//
+//    type Dog {
+//      name:   String!
+//      weight: Float!
+//      dob:    DateTime
+//      breed:  [Breed]
+//    }
+//
+// == Example generated aliases
+//
+//   type DogAliases struct {}
+//   func (_ DogAliases) Name(p graphql.ResolveParams) (interface{}, error) {
+//     // reflect...
+//   }
+//   func (_ DogAliases) Weight(p graphql.ResolveParams) (interface{}, error) {
+//     // reflect...
+//   }
+//   func (_ DogAliases) Dob(p graphql.ResolveParams) (interface{}, error) {
+//     // reflect...
+//   }
+//   func (_ DogAliases) Breed(p graphql.ResolveParams) (interface{}, error) {
+//     // reflect...
+//   }
+//
+// == Example Implementation
+//
+//   type DogResolver struct { // Implements DogResolver
+//     DogAliases
+//     store store.BreedStore
+//   }
+//
+//   // NOTE:
+//   // All other fields are satisified by DogAliases but since this one
+//   // requires hitting the store we implement it in our resolver.
+//   func (r *DogResolver) Breed(p graphql.ResolveParams) interface{} {
+//     dog := v.(*Dog)
+//     return r.BreedsById(dog.BreedIDs)
+//   }
+//
+type SnoozeEventPayloadAliases struct{}
+
+// ClientMutationID implements response to request for 'clientMutationId' field.
+func (_ SnoozeEventPayloadAliases) ClientMutationID(p graphql.ResolveParams) (string, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := val.(string)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'clientMutationId'")
+	}
+	return ret, err
+}
+
+// Silence implements response to request for 'silence' field.
+func (_ SnoozeEventPayloadAliases) Silence(p graphql.ResolveParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// SnoozeEventPayloadType self descriptive
+var SnoozeEventPayloadType = graphql.NewType("SnoozeEventPayload", graphql.ObjectKind)
+
+// RegisterSnoozeEventPayload registers SnoozeEventPayload object type with given service.
+func RegisterSnoozeEventPayload(svc *graphql.Service, impl SnoozeEventPayloadFieldResolvers) {
+	svc.RegisterObject(_ObjectTypeSnoozeEventPayloadDesc, impl)
+}
+func _ObjTypeSnoozeEventPayloadClientMutationIDHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(SnoozeEventPayloadClientMutationIDFieldResolver)
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.ClientMutationID(frp)
+	}
+}
+
+func _ObjTypeSnoozeEventPayloadSilenceHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(SnoozeEventPayloadSilenceFieldResolver)
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Silence(frp)
+	}
+}
+
+func _ObjectTypeSnoozeEventPayloadConfigFn() graphql1.ObjectConfig {
+	return graphql1.ObjectConfig{
+		Description: "self descriptive",
+		Fields: graphql1.Fields{
+			"clientMutationId": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "A unique identifier for the client performing the mutation.",
+				Name:              "clientMutationId",
+				Type:              graphql1.String,
+			},
+			"silence": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "The silence created to snooze the event.",
+				Name:              "silence",
+				Type:              graphql1.NewNonNull(graphql.OutputType("Silenced")),
+			},
+		},
+		Interfaces: []*graphql1.Interface{},
+		IsTypeOf: func(_ graphql1.IsTypeOfParams) bool {
+			// NOTE:
+			// Panic by default. Intent is that when Service is invoked, values of
+			// these fields are updated with instantiated resolvers. If these
+			// defaults are called it is most certainly programmer err.
+			// If you're see this comment then: 'Whoops! Sorry, my bad.'
+			panic("Unimplemented; see SnoozeEventPayloadFieldResolvers.")
+		},
+		Name: "SnoozeEventPayload",
+	}
+}
+
+// describe SnoozeEventPayload's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _ObjectTypeSnoozeEventPayloadDesc = graphql.ObjectDesc{
+	Config: _ObjectTypeSnoozeEventPayloadConfigFn,
+	FieldHandlers: map[string]graphql.FieldHandler{
+		"clientMutationId": _ObjTypeSnoozeEventPayloadClientMutationIDHandler,
+		"silence":          _ObjTypeSnoozeEventPayloadSilenceHandler,
+	},
+}
+
 // CreateSilenceInput self descriptive
 type CreateSilenceInput struct {
 	// ClientMutationID - A unique identifier for the client performing the mutation.
@@ -61,6 +61,7 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 	schema.RegisterProxyRequests(svc, &schema.ProxyRequestsAliases{})
 	schema.RegisterResource(svc, nil)
 	schema.RegisterResolveEventPayload(svc, &schema.ResolveEventPayloadAliases{})
+	schema.RegisterSnoozeEventPayload(svc, &schema.SnoozeEventPayloadAliases{})
 	schema.RegisterSchema(svc)
 	schema.RegisterSilenceable(svc, nil)
 	schema.RegisterSilenced(svc, &silencedImpl{factory: clientFactory})
@@ -140,6 +141,7 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 	schema.RegisterExecuteCheckInput(svc)
 	schema.RegisterExecuteCheckPayload(svc, &schema.ExecuteCheckPayloadAliases{})
 	schema.RegisterResolveEventInput(svc)
+	schema.RegisterSnoozeEventInput(svc)
 	schema.RegisterSilenceInputs(svc)
 	schema.RegisterUpdateCheckInput(svc)
 	schema.RegisterUpdateCheckPayload(svc, &checkMutationPayload{})
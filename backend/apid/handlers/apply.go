@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// ApplyResource merges the configuration given in the request body into the
+// resource identified in the request path, creating it if it does not exist,
+// on behalf of the field manager named by the fieldManager query parameter.
+// Only the fields set in the configuration are changed, and changing the
+// value of the fields owned by other managers fails with a Conflict error
+// unless the force query parameter is true. Applying also fails with a
+// Conflict error if the resource was modified while it was being applied.
+func (h Handlers) ApplyResource(r *http.Request) (interface{}, error) {
+	if h.ManagedFields == nil {
+		return nil, actions.NewErrorf(actions.InternalErr)
+	}
+
+	query := r.URL.Query()
+	manager := query.Get(corev2.FieldManagerQueryParam)
+	if manager == "" {
+		return nil, actions.NewErrorf(actions.InvalidArgument, "the %s query parameter is required", corev2.FieldManagerQueryParam)
+	}
+	var force bool
+	if value := query.Get(corev2.ForceConflictsQueryParam); value != "" {
+		var err error
+		if force, err = strconv.ParseBool(value); err != nil {
+			return nil, actions.NewError(actions.InvalidArgument, err)
+		}
+	}
+
+	var applied map[string]interface{}
+	if err := DecodeBody(r, &applied); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	if err := setAppliedIdentity(applied, mux.Vars(r)); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	current, managed, revision, err := h.managedResource(r.Context(), mux.Vars(r))
+	if err != nil {
+		return nil, err
+	}
+	var currentFields map[string]interface{}
+	if current != nil {
+		if currentFields, err = decodeFields(current); err != nil {
+			return nil, actions.NewError(actions.InternalErr, err)
+		}
+	}
+
+	merged, conflicts := managed.Apply(manager, currentFields, applied, force)
+	if len(conflicts) > 0 {
+		return nil, conflictsError(conflicts)
+	}
+
+	resource, err := h.encodeResource(merged)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckMeta(resource, mux.Vars(r)); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	if err := h.enforceLabelPolicy(r.Context(), resource); err != nil {
+		return nil, err
+	}
+
+	// The resource and its field ownership are written at once, only if no
+	// other update happened since they were read, so that concurrent
+	// managers can't silently overwrite each other
+	if err := h.ManagedFields.ApplyManagedResource(r.Context(), resource, managed, revision); err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotValid:
+			return nil, actions.NewError(actions.InvalidArgument, err)
+		case *store.ErrModified:
+			return nil, actions.NewErrorf(actions.Conflict, "the resource was modified concurrently, apply the configuration again")
+		default:
+			return nil, actions.NewError(actions.InternalErr, err)
+		}
+	}
+
+	return resource, nil
+}
+
+// recordUpdate records the fields changed by the field manager named by the
+// fieldManager query parameter, if any, when the resource was created or
+// replaced. previous is the resource before the request, or nil.
+func (h Handlers) recordUpdate(r *http.Request, previous, resource corev2.Resource) error {
+	if h.ManagedFields == nil {
+		return nil
+	}
+	manager := r.URL.Query().Get(corev2.FieldManagerQueryParam)
+	if manager == "" {
+		return nil
+	}
+
+	managed := &corev2.ManagedFields{}
+	var previousFields map[string]interface{}
+	if previous != nil {
+		var err error
+		if managed, err = h.getManagedFields(r.Context(), previous); err != nil {
+			return err
+		}
+		if previousFields, err = decodeFields(previous); err != nil {
+			return actions.NewError(actions.InternalErr, err)
+		}
+	}
+	updatedFields, err := decodeFields(resource)
+	if err != nil {
+		return actions.NewError(actions.InternalErr, err)
+	}
+
+	managed.Update(manager, previousFields, updatedFields)
+	if err := h.ManagedFields.UpdateManagedFields(r.Context(), resource, managed); err != nil {
+		return actions.NewError(actions.InternalErr, err)
+	}
+	return nil
+}
+
+// previousResource returns the resource about to be created or replaced by a
+// request of a field manager, or nil if it does not exist or if the request
+// has no field manager.
+func (h Handlers) previousResource(r *http.Request, resource corev2.Resource) (corev2.Resource, error) {
+	if h.ManagedFields == nil || r.URL.Query().Get(corev2.FieldManagerQueryParam) == "" {
+		return nil, nil
+	}
+	return h.currentResource(r.Context(), map[string]string{"id": url.PathEscape(resource.GetObjectMeta().Name)})
+}
+
+// currentResource returns the resource identified by the path variables, or
+// nil if it does not exist.
+func (h Handlers) currentResource(ctx context.Context, vars map[string]string) (corev2.Resource, error) {
+	name, err := url.PathUnescape(vars["id"])
+	if err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	resource, ok := reflect.New(reflect.TypeOf(h.Resource).Elem()).Interface().(corev2.Resource)
+	if !ok {
+		return nil, actions.NewErrorf(actions.InternalErr)
+	}
+	if err := h.Store.GetResource(ctx, name, resource); err != nil {
+		switch err.(type) {
+		case *store.ErrNotFound:
+			return nil, nil
+		default:
+			return nil, actions.NewError(actions.InternalErr, err)
+		}
+	}
+	return resource, nil
+}
+
+// managedResource returns the resource identified by the path variables, or
+// nil if it does not exist, along with its field ownership and its revision.
+func (h Handlers) managedResource(ctx context.Context, vars map[string]string) (corev2.Resource, *corev2.ManagedFields, int64, error) {
+	name, err := url.PathUnescape(vars["id"])
+	if err != nil {
+		return nil, nil, 0, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	resource, ok := reflect.New(reflect.TypeOf(h.Resource).Elem()).Interface().(corev2.Resource)
+	if !ok {
+		return nil, nil, 0, actions.NewErrorf(actions.InternalErr)
+	}
+	managed, revision, err := h.ManagedFields.GetManagedResource(ctx, name, resource)
+	if err != nil {
+		switch err.(type) {
+		case *store.ErrNotFound:
+			return nil, &corev2.ManagedFields{}, 0, nil
+		default:
+			return nil, nil, 0, actions.NewError(actions.InternalErr, err)
+		}
+	}
+	if managed == nil {
+		managed = &corev2.ManagedFields{}
+	}
+	return resource, managed, revision, nil
+}
+
+func (h Handlers) getManagedFields(ctx context.Context, resource corev2.Resource) (*corev2.ManagedFields, error) {
+	managed, err := h.ManagedFields.GetManagedFields(ctx, resource)
+	if err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	if managed == nil {
+		managed = &corev2.ManagedFields{}
+	}
+	return managed, nil
+}
+
+// encodeResource returns the resource configured by the decoded JSON object.
+func (h Handlers) encodeResource(fields map[string]interface{}) (corev2.Resource, error) {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	payload := reflect.New(reflect.TypeOf(h.Resource).Elem())
+	if err := json.Unmarshal(b, payload.Interface()); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	resource, ok := payload.Interface().(corev2.Resource)
+	if !ok {
+		return nil, actions.NewErrorf(actions.InvalidArgument)
+	}
+	return resource, nil
+}
+
+// decodeFields returns the resource as a decoded JSON object.
+func decodeFields(resource corev2.Resource) (map[string]interface{}, error) {
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(b, &fields)
+	return fields, err
+}
+
+// setAppliedIdentity sets the name and namespace of the applied configuration
+// from the path variables, if missing.
+func setAppliedIdentity(applied map[string]interface{}, vars map[string]string) error {
+	if applied == nil {
+		return errors.New("the applied configuration must be an object")
+	}
+	meta, ok := applied["metadata"].(map[string]interface{})
+	if !ok {
+		meta = make(map[string]interface{})
+		applied["metadata"] = meta
+	}
+	for field, variable := range map[string]string{"name": "id", "namespace": "namespace"} {
+		if _, ok := meta[field]; ok {
+			continue
+		}
+		value, err := url.PathUnescape(vars[variable])
+		if err != nil {
+			return err
+		}
+		if value != "" {
+			meta[field] = value
+		}
+	}
+	return nil
+}
+
+// conflictsError returns the Conflict error describing the fields owned by
+// other field managers.
+func conflictsError(conflicts []corev2.FieldConflict) error {
+	fields := make([]actions.FieldError, len(conflicts))
+	message := "apply failed with conflicts, use force to take the ownership of the fields:"
+	for i, conflict := range conflicts {
+		fields[i] = actions.FieldError{
+			Field:   conflict.Field,
+			Message: "owned by " + conflict.Manager,
+		}
+		message += " " + conflict.Error()
+		if i < len(conflicts)-1 {
+			message += ","
+		}
+	}
+	return actions.Error{
+		Code:    actions.Conflict,
+		Message: message,
+		Fields:  fields,
+	}
+}
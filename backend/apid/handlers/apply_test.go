@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/fixture"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandlers_ApplyResource(t *testing.T) {
+	type storeFunc func(*mockstore.MockStore)
+	current := &fixture.Resource{
+		Foo: "bar",
+		ObjectMeta: corev2.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			Labels:    map[string]string{"team": "ops"},
+		},
+	}
+	ownedBy := func(managers map[string][]string) storeFunc {
+		return func(s *mockstore.MockStore) {
+			s.On("GetManagedResource", mock.Anything, "foo", mock.AnythingOfType("*fixture.Resource")).
+				Return(&corev2.ManagedFields{Managers: managers}, int64(42), nil).
+				Run(func(args mock.Arguments) {
+					resource := args[2].(*fixture.Resource)
+					*resource = *current
+				})
+		}
+	}
+
+	applied := func(managers map[string][]string) storeFunc {
+		return func(s *mockstore.MockStore) {
+			ownedBy(managers)(s)
+			s.On("ApplyManagedResource", mock.Anything, mock.AnythingOfType("*fixture.Resource"), mock.Anything, int64(42)).
+				Return(nil)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		body         []byte
+		storeFunc    storeFunc
+		want         *fixture.Resource
+		wantManagers map[string][]string
+		wantErr      bool
+		wantErrCode  actions.ErrCode
+	}{
+		{
+			name:        "missing field manager",
+			body:        []byte(`{"foo": "baz"}`),
+			wantErr:     true,
+			wantErrCode: actions.InvalidArgument,
+		},
+		{
+			name:        "invalid force",
+			query:       "?fieldManager=gitops&force=maybe",
+			body:        []byte(`{"foo": "baz"}`),
+			wantErr:     true,
+			wantErrCode: actions.InvalidArgument,
+		},
+		{
+			name:        "invalid request body",
+			query:       "?fieldManager=gitops",
+			body:        []byte("foobar"),
+			wantErr:     true,
+			wantErrCode: actions.InvalidArgument,
+		},
+		{
+			name:  "store err",
+			query: "?fieldManager=gitops",
+			body:  []byte(`{"foo": "baz"}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetManagedResource", mock.Anything, "foo", mock.AnythingOfType("*fixture.Resource")).
+					Return(nil, int64(0), &store.ErrInternal{})
+			},
+			wantErr:     true,
+			wantErrCode: actions.InternalErr,
+		},
+		{
+			name:  "new resource",
+			query: "?fieldManager=gitops",
+			body:  []byte(`{"foo": "baz"}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetManagedResource", mock.Anything, "foo", mock.AnythingOfType("*fixture.Resource")).
+					Return(nil, int64(0), &store.ErrNotFound{})
+				s.On("ApplyManagedResource", mock.Anything, mock.AnythingOfType("*fixture.Resource"), mock.Anything, int64(0)).
+					Return(nil)
+			},
+			want: &fixture.Resource{
+				Foo:        "baz",
+				ObjectMeta: corev2.ObjectMeta{Name: "foo", Namespace: "default"},
+			},
+			wantManagers: map[string][]string{"gitops": {"/foo"}},
+		},
+		{
+			name:      "only the applied fields are changed",
+			query:     "?fieldManager=gitops",
+			body:      []byte(`{"foo": "baz"}`),
+			storeFunc: applied(map[string][]string{"web": {"/metadata/labels/team"}}),
+			want: &fixture.Resource{
+				Foo:        "baz",
+				ObjectMeta: current.ObjectMeta,
+			},
+			wantManagers: map[string][]string{
+				"gitops": {"/foo"},
+				"web":    {"/metadata/labels/team"},
+			},
+		},
+		{
+			name:        "conflict",
+			query:       "?fieldManager=gitops",
+			body:        []byte(`{"foo": "baz"}`),
+			storeFunc:   ownedBy(map[string][]string{"web": {"/foo"}}),
+			wantErr:     true,
+			wantErrCode: actions.Conflict,
+		},
+		{
+			name:      "forced conflict",
+			query:     "?fieldManager=gitops&force=true",
+			body:      []byte(`{"foo": "baz"}`),
+			storeFunc: applied(map[string][]string{"web": {"/foo"}}),
+			want: &fixture.Resource{
+				Foo:        "baz",
+				ObjectMeta: current.ObjectMeta,
+			},
+			wantManagers: map[string][]string{"gitops": {"/foo"}},
+		},
+		{
+			name:  "concurrent modification",
+			query: "?fieldManager=gitops",
+			body:  []byte(`{"foo": "baz"}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				ownedBy(nil)(s)
+				s.On("ApplyManagedResource", mock.Anything, mock.AnythingOfType("*fixture.Resource"), mock.Anything, int64(42)).
+					Return(&store.ErrModified{})
+			},
+			wantErr:     true,
+			wantErrCode: actions.Conflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &mockstore.MockStore{}
+			if tt.storeFunc != nil {
+				tt.storeFunc(s)
+			}
+			h := Handlers{
+				Resource:      &fixture.Resource{},
+				Store:         s,
+				ManagedFields: s,
+			}
+
+			r, _ := http.NewRequest(http.MethodPatch, "/"+tt.query, bytes.NewReader(tt.body))
+			r = mux.SetURLVars(r, map[string]string{"id": "foo", "namespace": "default"})
+
+			got, err := h.ApplyResource(r)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if err, ok := err.(actions.Error); assert.True(t, ok) {
+					assert.Equal(t, tt.wantErrCode, err.Code)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			for _, call := range s.Calls {
+				if call.Method == "ApplyManagedResource" {
+					managed := call.Arguments[2].(*corev2.ManagedFields)
+					assert.Equal(t, tt.wantManagers, managed.Managers)
+				}
+			}
+			s.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandlers_RecordUpdate(t *testing.T) {
+	s := &mockstore.MockStore{}
+	s.On("GetResource", mock.Anything, "foo", mock.AnythingOfType("*fixture.Resource")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			resource := args[2].(*fixture.Resource)
+			*resource = fixture.Resource{Foo: "bar", ObjectMeta: corev2.ObjectMeta{Name: "foo"}}
+		})
+	s.On("GetManagedFields", mock.Anything, mock.AnythingOfType("*fixture.Resource")).
+		Return(&corev2.ManagedFields{Managers: map[string][]string{"gitops": {"/foo"}}}, nil)
+	s.On("CreateOrUpdateResource", mock.Anything, mock.AnythingOfType("*fixture.Resource")).Return(nil)
+	var managed *corev2.ManagedFields
+	s.On("UpdateManagedFields", mock.Anything, mock.AnythingOfType("*fixture.Resource"), mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			managed = args[2].(*corev2.ManagedFields)
+		})
+
+	h := Handlers{
+		Resource:      &fixture.Resource{},
+		Store:         s,
+		ManagedFields: s,
+	}
+
+	body := []byte(`{"foo": "baz", "metadata": {"name": "foo"}}`)
+	r, _ := http.NewRequest(http.MethodPut, "/?fieldManager=web", bytes.NewReader(body))
+	r = mux.SetURLVars(r, map[string]string{"id": "foo"})
+	_, err := h.CreateOrUpdateResource(r)
+	assert.NoError(t, err)
+
+	// The web manager took the ownership of the field it changed
+	if assert.NotNil(t, managed) {
+		assert.Equal(t, map[string][]string{"web": {"/foo"}}, managed.Managers)
+	}
+}
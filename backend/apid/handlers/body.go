@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/ghodss/yaml"
+	"github.com/sensu/sensu-go/types"
+)
+
+// yamlMediaTypes are the media types accepted for YAML.
+var yamlMediaTypes = map[string]bool{
+	"application/yaml":   true,
+	"application/x-yaml": true,
+	"text/yaml":          true,
+	"text/x-yaml":        true,
+}
+
+// IsYAMLMediaType returns true if the media type is one of the YAML ones.
+func IsYAMLMediaType(mediaType string) bool {
+	return yamlMediaTypes[mediaType]
+}
+
+// DecodeBody decodes the request body into v, from JSON or, if its content
+// type is YAML, from YAML. A YAML resource may be wrapped with its type, like
+// the manifests used by sensuctl create.
+func DecodeBody(r *http.Request, v interface{}) error {
+	if !isYAMLBody(r) {
+		return json.NewDecoder(r.Body).Decode(v)
+	}
+	b, err := yamlBodyToJSON(r)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// isYAMLBody returns true if the body of the request is YAML.
+func isYAMLBody(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && IsYAMLMediaType(mediaType)
+}
+
+// yamlBodyToJSON converts the YAML body of a request to JSON. A resource
+// wrapped with its type is unwrapped.
+func yamlBodyToJSON(r *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	b, err := yaml.YAMLToJSON(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML: %s", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return b, nil
+	}
+	if _, ok := fields["spec"]; !ok {
+		return b, nil
+	}
+	if _, ok := fields["type"]; !ok {
+		return b, nil
+	}
+	var wrapper types.Wrapper
+	if err := json.Unmarshal(b, &wrapper); err != nil {
+		return nil, err
+	}
+	return json.Marshal(wrapper.Value)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/testing/fixture"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        `{"metadata":{"name":"slack","namespace":"default"},"type":"pipe"}`,
+		},
+		{
+			name:        "unwrapped yaml",
+			contentType: "application/yaml",
+			body:        "metadata:\n  name: slack\n  namespace: default\ntype: pipe\n",
+		},
+		{
+			name:        "wrapped yaml",
+			contentType: "text/yaml; charset=utf-8",
+			body:        "type: Handler\napi_version: core/v2\nmetadata:\n  name: slack\n  namespace: default\nspec:\n  type: pipe\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+			var handler corev2.Handler
+			require.NoError(t, DecodeBody(r, &handler))
+			assert.Equal(t, "slack", handler.Name)
+			assert.Equal(t, "default", handler.Namespace)
+			assert.Equal(t, "pipe", handler.Type)
+		})
+	}
+}
+
+func TestHandlers_CreateResourceYAML(t *testing.T) {
+	store := &mockstore.MockStore{}
+	store.On("CreateResource", mock.Anything, mock.AnythingOfType("*fixture.Resource")).Return(nil)
+	h := Handlers{
+		Resource: &fixture.Resource{},
+		Store:    store,
+	}
+
+	r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader("metadata:\n  name: foo\n  namespace: default\n"))
+	r.Header.Set("Content-Type", "application/yaml")
+	r = mux.SetURLVars(r, map[string]string{"id": "foo", "namespace": "default"})
+	_, err := h.CreateResource(r)
+	require.NoError(t, err)
+
+	resource := store.Calls[0].Arguments.Get(1).(*fixture.Resource)
+	assert.Equal(t, "foo", resource.Name)
+}
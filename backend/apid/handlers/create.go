@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 	"reflect"
 
@@ -15,7 +14,7 @@ import (
 // does not already exist
 func (h Handlers) CreateResource(r *http.Request) (interface{}, error) {
 	payload := reflect.New(reflect.TypeOf(h.Resource).Elem())
-	if err := json.NewDecoder(r.Body).Decode(payload.Interface()); err != nil {
+	if err := DecodeBody(r, payload.Interface()); err != nil {
 		return nil, actions.NewError(actions.InvalidArgument, err)
 	}
 
@@ -28,6 +27,10 @@ func (h Handlers) CreateResource(r *http.Request) (interface{}, error) {
 		return nil, actions.NewErrorf(actions.InvalidArgument)
 	}
 
+	if err := h.enforceLabelPolicy(r.Context(), resource); err != nil {
+		return nil, err
+	}
+
 	if err := h.Store.CreateResource(r.Context(), resource); err != nil {
 		switch err := err.(type) {
 		case *store.ErrAlreadyExists:
@@ -39,5 +42,9 @@ func (h Handlers) CreateResource(r *http.Request) (interface{}, error) {
 		}
 	}
 
+	if err := h.recordUpdate(r, nil, resource); err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
@@ -12,6 +12,15 @@ import (
 type Handlers struct {
 	Resource corev2.Resource
 	Store    store.ResourceStore
+
+	// LabelPolicy is the store of the label policy enforced on the created
+	// resources, if not nil.
+	LabelPolicy store.LabelPolicyStore
+
+	// ManagedFields is the store of the field ownership of the resources,
+	// required to apply resources and to record the fields updated by field
+	// managers.
+	ManagedFields store.ManagedFieldsStore
 }
 
 // CheckMeta inspects the resource metadata and ensures it matches what was
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+)
+
+// enforceLabelPolicy returns an InvalidArgument error if the resource does not
+// meet the requirements of the label policy.
+func (h Handlers) enforceLabelPolicy(ctx context.Context, resource corev2.Resource) error {
+	if h.LabelPolicy == nil {
+		return nil
+	}
+
+	policy, err := h.LabelPolicy.GetLabelPolicy(ctx)
+	if err != nil {
+		return actions.NewError(actions.InternalErr, err)
+	}
+
+	if err := policy.Enforce(resource.StorePrefix(), resource.GetObjectMeta()); err != nil {
+		return actions.NewError(actions.InvalidArgument, err)
+	}
+	return nil
+}
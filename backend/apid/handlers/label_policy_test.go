@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandlers_LabelPolicy(t *testing.T) {
+	policy := &corev2.LabelPolicy{
+		Rules: []corev2.LabelPolicyRule{
+			{Resources: []string{"checks"}, Labels: map[string]string{"team": "^[a-z]+$"}},
+		},
+	}
+	compliant := []byte(`{"metadata": {"name": "disk", "labels": {"team": "ops"}}}`)
+	noncompliant := []byte(`{"metadata": {"name": "disk"}}`)
+
+	tests := []struct {
+		name        string
+		body        []byte
+		policy      *corev2.LabelPolicy
+		policyErr   error
+		wantErr     bool
+		wantErrCode actions.ErrCode
+	}{
+		{
+			name: "no policy",
+			body: noncompliant,
+		},
+		{
+			name:   "compliant resource",
+			body:   compliant,
+			policy: policy,
+		},
+		{
+			name:        "noncompliant resource",
+			body:        noncompliant,
+			policy:      policy,
+			wantErr:     true,
+			wantErrCode: actions.InvalidArgument,
+		},
+		{
+			name:        "store err",
+			body:        compliant,
+			policyErr:   errors.New("error"),
+			wantErr:     true,
+			wantErrCode: actions.InternalErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockstore.MockStore{}
+			store.On("GetLabelPolicy", mock.Anything).Return(tt.policy, tt.policyErr)
+			store.On("CreateResource", mock.Anything, mock.Anything).Return(nil)
+			store.On("CreateOrUpdateResource", mock.Anything, mock.Anything).Return(nil)
+
+			h := Handlers{
+				Resource:    &corev2.CheckConfig{},
+				Store:       store,
+				LabelPolicy: store,
+			}
+
+			for _, handler := range []func(*http.Request) (interface{}, error){h.CreateResource, h.CreateOrUpdateResource} {
+				r, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+				_, err := handler(r)
+				if !tt.wantErr {
+					assert.NoError(t, err)
+					continue
+				}
+				assert.Error(t, err)
+				if err, ok := err.(actions.Error); assert.True(t, ok) {
+					assert.Equal(t, tt.wantErrCode, err.Code)
+				}
+			}
+		})
+	}
+}
@@ -2,13 +2,26 @@ package middlewares
 
 import (
 	"context"
+	"crypto/x509"
 	"net/http"
 
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/transport"
 	"github.com/sensu/sensu-go/types"
 )
 
+const (
+	// AgentCertificateGroup is the group of the agents authenticated with
+	// their TLS client certificate.
+	AgentCertificateGroup = "system:agents"
+
+	// AgentCertificateUserPrefix prefixes the names of the users of the
+	// agents authenticated with their TLS client certificate, so an agent
+	// can't be authenticated as the user of the same name.
+	AgentCertificateUserPrefix = "agent:"
+)
+
 // AuthStore specifies the storage requirements for auth types.
 type AuthStore interface {
 	// AuthenticateUser attempts to authenticate a user with the given username
@@ -85,3 +98,83 @@ func BasicAuthentication(next http.Handler, store AuthStore) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// BasicOrTokenAuthentication is HTTP middleware authenticating the requests
+// with their basic credentials if they have some, or with their access token
+// otherwise, for the clients which can not refresh access tokens.
+type BasicOrTokenAuthentication struct {
+	Store AuthStore
+}
+
+// Then middleware
+func (a BasicOrTokenAuthentication) Then(next http.Handler) http.Handler {
+	basic := BasicAuthentication(next, a.Store)
+	token := Authentication{}.Then(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); ok {
+			basic.ServeHTTP(w, r)
+			return
+		}
+		token.ServeHTTP(w, r)
+	})
+}
+
+// CertificateAuthentication is HTTP middleware authenticating agents with
+// their verified TLS client certificate. The agent name is the common name of
+// the certificate, or its first DNS name, and the agent namespace is the first
+// organizational unit of the certificate, if any. The agent is authenticated as
+// the user named after the agent with the AgentCertificateUserPrefix prefix,
+// member of the AgentCertificateGroup group, so its session is still
+// authorized with RBAC.
+func CertificateAuthentication(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			writeErr(w, actions.NewErrorf(actions.Unauthenticated, "missing client certificate"))
+			return
+		}
+
+		name, namespace := certificateIdentity(r.TLS.VerifiedChains[0][0])
+		if name == "" {
+			writeErr(w, actions.NewErrorf(actions.Unauthenticated, "client certificate has no common name"))
+			return
+		}
+
+		// The identity claimed by the agent must match its certificate
+		if agent := r.Header.Get(transport.HeaderKeyAgentName); agent != "" && agent != name {
+			logger.WithField("agent", agent).WithField("certificate", name).Error("agent name does not match its certificate")
+			writeErr(w, actions.NewErrorf(actions.Unauthenticated, "bad credentials"))
+			return
+		}
+		if namespace == "" {
+			namespace = r.Header.Get(transport.HeaderKeyNamespace)
+		} else if ns := r.Header.Get(transport.HeaderKeyNamespace); ns != "" && ns != namespace {
+			logger.WithField("namespace", ns).WithField("certificate", namespace).Error("agent namespace does not match its certificate")
+			writeErr(w, actions.NewErrorf(actions.Unauthenticated, "bad credentials"))
+			return
+		}
+		r.Header.Set(transport.HeaderKeyAgentName, name)
+		r.Header.Set(transport.HeaderKeyNamespace, namespace)
+		r.Header.Set(transport.HeaderKeyUser, AgentCertificateUserPrefix+name)
+
+		user := &types.User{
+			Username: AgentCertificateUserPrefix + name,
+			Groups:   []string{AgentCertificateGroup},
+		}
+		claims, _ := jwt.NewClaims(user)
+		ctx := jwt.SetClaimsIntoContext(r, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// certificateIdentity returns the agent name and namespace of a client
+// certificate.
+func certificateIdentity(cert *x509.Certificate) (name, namespace string) {
+	name = cert.Subject.CommonName
+	if name == "" && len(cert.DNSNames) > 0 {
+		name = cert.DNSNames[0]
+	}
+	if len(cert.Subject.OrganizationalUnit) > 0 {
+		namespace = cert.Subject.OrganizationalUnit[0]
+	}
+	return name, namespace
+}
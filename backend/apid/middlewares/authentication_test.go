@@ -1,6 +1,10 @@
 package middlewares
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -8,7 +12,10 @@ import (
 
 	"github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/sensu/sensu-go/transport"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestMiddlewareNoCredentials(t *testing.T) {
@@ -71,3 +78,133 @@ func TestMiddlewareIgnoreUnauthorized(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, res.StatusCode)
 }
+
+func TestBasicOrTokenAuthentication(t *testing.T) {
+	store := &mockstore.MockStore{}
+	store.On("AuthenticateUser", mock.Anything, "foo", "P@ssw0rd!").
+		Return(v2.FixtureUser("foo"), nil)
+	store.On("AuthenticateUser", mock.Anything, "foo", "wrong").
+		Return((*v2.User)(nil), errors.New("bad credentials"))
+
+	mware := BasicOrTokenAuthentication{Store: store}
+	server := httptest.NewServer(mware.Then(testHandler()))
+	defer server.Close()
+
+	_, tokenString, _ := jwt.AccessToken(v2.FixtureClaims("foo", nil))
+
+	tests := []struct {
+		name       string
+		auth       func(*http.Request)
+		wantStatus int
+	}{
+		{
+			name:       "no credentials",
+			auth:       func(*http.Request) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "valid basic credentials",
+			auth:       func(req *http.Request) { req.SetBasicAuth("foo", "P@ssw0rd!") },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid basic credentials",
+			auth:       func(req *http.Request) { req.SetBasicAuth("foo", "wrong") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "valid access token",
+			auth:       func(req *http.Request) { req.Header.Add("Authorization", "Bearer "+tokenString) },
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			tt.auth(req)
+			res, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestCertificateAuthentication(t *testing.T) {
+	tests := []struct {
+		name          string
+		cert          *x509.Certificate
+		header        http.Header
+		wantStatus    int
+		wantAgent     string
+		wantNamespace string
+	}{
+		{
+			name:       "no client certificate",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no common name",
+			cert:       &x509.Certificate{},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "common name",
+			cert:          &x509.Certificate{Subject: pkix.Name{CommonName: "agent1"}},
+			header:        http.Header{transport.HeaderKeyNamespace: {"default"}},
+			wantStatus:    http.StatusOK,
+			wantAgent:     "agent1",
+			wantNamespace: "default",
+		},
+		{
+			name:          "dns name and organizational unit",
+			cert:          &x509.Certificate{DNSNames: []string{"agent1"}, Subject: pkix.Name{OrganizationalUnit: []string{"acme"}}},
+			wantStatus:    http.StatusOK,
+			wantAgent:     "agent1",
+			wantNamespace: "acme",
+		},
+		{
+			name:       "agent name mismatch",
+			cert:       &x509.Certificate{Subject: pkix.Name{CommonName: "agent1"}},
+			header:     http.Header{transport.HeaderKeyAgentName: {"agent2"}},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "namespace mismatch",
+			cert:       &x509.Certificate{Subject: pkix.Name{CommonName: "agent1", OrganizationalUnit: []string{"acme"}}},
+			header:     http.Header{transport.HeaderKeyNamespace: {"default"}},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claims *v2.Claims
+			var header http.Header
+			handler := CertificateAuthentication(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				claims = jwt.GetClaimsFromContext(r.Context())
+				header = r.Header
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key := range tt.header {
+				req.Header.Set(key, tt.header[key][0])
+			}
+			if tt.cert != nil {
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{tt.cert}}}
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.wantAgent, header.Get(transport.HeaderKeyAgentName))
+			assert.Equal(t, "agent:"+tt.wantAgent, header.Get(transport.HeaderKeyUser))
+			assert.Equal(t, tt.wantNamespace, header.Get(transport.HeaderKeyNamespace))
+			if assert.NotNil(t, claims) {
+				assert.Equal(t, "agent:"+tt.wantAgent, claims.Subject)
+				assert.Equal(t, []string{AgentCertificateGroup}, claims.Groups)
+			}
+		})
+	}
+}
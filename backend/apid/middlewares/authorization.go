@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
@@ -12,9 +13,47 @@ import (
 	"github.com/sensu/sensu-go/transport"
 )
 
+const (
+	// DenialDetailsNone denies the unauthorized requests without details.
+	DenialDetailsNone = "none"
+
+	// DenialDetailsRequest describes the verb, resource and namespace of the
+	// unauthorized requests in the response body.
+	DenialDetailsRequest = "request"
+
+	// DenialDetailsTrace also lists the bindings of the user evaluated for
+	// the unauthorized requests in the response body.
+	DenialDetailsTrace = "trace"
+)
+
+// ValidateDenialDetails returns an error if the level of details of the
+// unauthorized requests is unknown. An empty level is the same as
+// DenialDetailsNone.
+func ValidateDenialDetails(details string) error {
+	switch details {
+	case "", DenialDetailsNone, DenialDetailsRequest, DenialDetailsTrace:
+		return nil
+	}
+	return fmt.Errorf(
+		"invalid denial details %q, must be one of %q, %q or %q",
+		details, DenialDetailsNone, DenialDetailsRequest, DenialDetailsTrace,
+	)
+}
+
 // Authorization is an HTTP middleware that enforces authorization
 type Authorization struct {
 	Authorizer authorization.Authorizer
+
+	// FilterLists lets through the list requests of the users only
+	// authorized for some resources of the collection, by name, if the
+	// Authorizer is a ResourceNamesAuthorizer. The lists are restricted to
+	// these resources.
+	FilterLists bool
+
+	// DenialDetails is the level of details of the unauthorized requests
+	// in the response body: DenialDetailsNone, DenialDetailsRequest or
+	// DenialDetailsTrace.
+	DenialDetails string
 }
 
 // Then middleware
@@ -42,14 +81,80 @@ func (a Authorization) Then(next http.Handler) http.Handler {
 			return
 		}
 		if !authorized {
-			writeErr(w, actions.NewErrorf(actions.PermissionDenied))
-			return
+			names, err := a.authorizedResourceNames(ctx, attrs)
+			if err != nil {
+				logger.WithError(err).Warning("unexpected error occurred during authorization")
+				writeErr(w, actions.NewErrorf(
+					actions.InternalErr,
+					"unexpected error occurred during authorization",
+				))
+				return
+			}
+			if len(names) == 0 {
+				writeErr(w, a.denied(ctx, attrs))
+				return
+			}
+			ctx = authorization.SetResourceNames(ctx, names)
 		}
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// authorizedResourceNames returns the names of the resources an unauthorized
+// list request can be restricted to, if lists are filtered.
+func (a Authorization) authorizedResourceNames(ctx context.Context, attrs *authorization.Attributes) ([]string, error) {
+	if !a.FilterLists || attrs.Verb != "list" {
+		return nil, nil
+	}
+	authorizer, ok := a.Authorizer.(authorization.ResourceNamesAuthorizer)
+	if !ok {
+		return nil, nil
+	}
+	return authorizer.AuthorizedResourceNames(ctx, attrs)
+}
+
+// denied returns the error of an unauthorized request, describing the missing
+// permission depending on the level of details.
+func (a Authorization) denied(ctx context.Context, attrs *authorization.Attributes) error {
+	err := actions.NewErrorf(actions.PermissionDenied)
+	if a.DenialDetails != DenialDetailsRequest && a.DenialDetails != DenialDetailsTrace {
+		return err
+	}
+
+	denial := &actions.Denial{
+		Username:     attrs.User.Username,
+		Verb:         attrs.Verb,
+		Resource:     attrs.Resource,
+		ResourceName: attrs.ResourceName,
+		Namespace:    attrs.Namespace,
+	}
+	role := "cluster role"
+	if denial.Namespace != "" {
+		role = "role"
+	}
+	hint := fmt.Sprintf("a %s granting the %q verb on the %q resource", role, denial.Verb, denial.Resource)
+	if denial.ResourceName != "" {
+		hint += fmt.Sprintf(" named %q", denial.ResourceName)
+	}
+	if denial.Namespace != "" {
+		hint += fmt.Sprintf(" in the %q namespace", denial.Namespace)
+	}
+	err.Message = fmt.Sprintf("%s: user %q requires %s", err.Message, denial.Username, hint)
+
+	if a.DenialDetails == DenialDetailsTrace {
+		if authorizer, ok := a.Authorizer.(authorization.BindingsAuthorizer); ok {
+			bindings, bindingsErr := authorizer.EvaluatedBindings(ctx, attrs)
+			if bindingsErr != nil {
+				logger.WithError(bindingsErr).Warning("could not determine the bindings evaluated during authorization")
+			}
+			denial.EvaluatedBindings = bindings
+		}
+	}
+	err.Denial = denial
+	return err
+}
+
 // BasicAuthorization performs basic authorization for event/entity creation via the agent websocket.
 func BasicAuthorization(next http.Handler, store store.Store) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -42,7 +42,7 @@ func (a AuthorizationAttributes) Then(next http.Handler) http.Handler {
 			attrs.Verb = "create"
 		case "GET", "HEAD":
 			attrs.Verb = "get"
-		case "PUT":
+		case "PUT", "PATCH":
 			attrs.Verb = "update"
 		case "DELETE":
 			attrs.Verb = "delete"
@@ -63,6 +63,57 @@ func (a AuthorizationAttributes) Then(next http.Handler) http.Handler {
 			attrs.Resource = "cluster-members"
 		}
 
+		// The effective configuration of the backend is authorized on its own
+		// resource, which the namespaced roles don't grant.
+		if strings.HasSuffix(r.URL.Path, "/cluster/config") {
+			attrs.Resource = types.ClusterConfigResource
+		}
+
+		// Running commands on an agent is authorized on its own resource, so
+		// that reading or updating entities doesn't grant it.
+		if attrs.Resource == "entities" && vars["subresource"] == "commands" {
+			attrs.Resource = types.AgentCommandsResource
+		}
+
+		// The outputs of the handlers may hold sensitive data, and are
+		// authorized on their own resource, so that reading handlers doesn't
+		// grant them.
+		if attrs.Resource == "handlers" && vars["subresource"] == "outputs" {
+			attrs.Resource = types.HandlerOutputsResource
+		}
+
+		// The silences of the Alertmanager API are translated to silenced
+		// entries, and authorized as such.
+		if strings.HasPrefix(r.URL.Path, "/alertmanager/") && (attrs.Resource == "silences" || attrs.Resource == "silence") {
+			attrs.Resource = "silenced"
+		}
+
+		// The Grafana datasource queries the events, even though it POSTs
+		// its queries.
+		if strings.HasPrefix(r.URL.Path, "/grafana/") {
+			attrs.Resource = "events"
+			attrs.Verb = "list"
+		}
+
+		// Snoozing an event creates a silenced entry, and is authorized as
+		// such so that creating events doesn't grant it.
+		if attrs.Resource == "events" && vars["subresource"] == "snooze" {
+			attrs.Resource = "silenced"
+			attrs.Verb = "create"
+			attrs.ResourceName, _ = types.SilencedName(types.GetEntitySubscription(vars["entity"]), vars["check"])
+		}
+
+		// Labeling entities in bulk updates them, even though it is a POST.
+		if attrs.Resource == "entities" && vars["subresource"] == "label" {
+			attrs.Verb = "update"
+		}
+
+		// Renaming an entity deletes it, even though it is a POST. The rename
+		// action authorizes the other resources it writes.
+		if attrs.Resource == "entities" && vars["subresource"] == "rename" {
+			attrs.Verb = "delete"
+		}
+
 		// Most resource names are identified by a route variable named "id".
 		// Other resources have snowflake paths; see their corresponding router
 		// and the expected paths above.
@@ -81,6 +132,12 @@ func (a AuthorizationAttributes) Then(next http.Handler) http.Handler {
 			attrs.Verb = "list"
 		}
 
+		// The debug endpoints expose the internals of the backend, and must
+		// not be accessible to users who can merely read resources.
+		if attrs.Resource == types.DebugResource {
+			attrs.Verb = types.VerbDebug
+		}
+
 		// Add the user to the attributes
 		if err := getUser(ctx, attrs); err != nil {
 			http.Error(w, err.Error(), http.StatusUnauthorized)
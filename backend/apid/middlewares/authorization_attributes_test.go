@@ -99,6 +99,124 @@ func TestAuthorizationAttributes(t *testing.T) {
 				Verb: "update",
 			},
 		},
+		{
+			description: "GET /api/core/v2/debug",
+			method:      "GET",
+			path:        "/api/core/v2/debug",
+			expected: authorization.Attributes{
+				APIGroup:   "core",
+				APIVersion: "v2",
+				Resource:   "debug",
+				Verb:       "debug",
+			},
+		},
+		{
+			description: "GET /api/core/v2/cluster/config",
+			method:      "GET",
+			path:        "/api/core/v2/cluster/config",
+			expected: authorization.Attributes{
+				APIGroup:     "core",
+				APIVersion:   "v2",
+				Resource:     "cluster-config",
+				ResourceName: "config",
+				Verb:         "get",
+			},
+		},
+		{
+			description: "POST /api/core/v2/namespaces/default/entities/foo/commands",
+			method:      "POST",
+			path:        "/api/core/v2/namespaces/default/entities/foo/commands",
+			expected: authorization.Attributes{
+				APIGroup:     "core",
+				APIVersion:   "v2",
+				Namespace:    "default",
+				Resource:     "agent-commands",
+				ResourceName: "foo",
+				Verb:         "create",
+			},
+		},
+		{
+			description: "GET /api/core/v2/namespaces/default/handlers/slack/outputs",
+			method:      "GET",
+			path:        "/api/core/v2/namespaces/default/handlers/slack/outputs",
+			expected: authorization.Attributes{
+				APIGroup:     "core",
+				APIVersion:   "v2",
+				Namespace:    "default",
+				Resource:     "handler-outputs",
+				ResourceName: "slack",
+				Verb:         "get",
+			},
+		},
+		{
+			description: "POST /api/core/v2/namespaces/default/entities/label",
+			method:      "POST",
+			path:        "/api/core/v2/namespaces/default/entities/label",
+			expected: authorization.Attributes{
+				APIGroup:   "core",
+				APIVersion: "v2",
+				Namespace:  "default",
+				Resource:   "entities",
+				Verb:       "update",
+			},
+		},
+		{
+			description: "POST /api/core/v2/namespaces/default/entities/foo/rename",
+			method:      "POST",
+			path:        "/api/core/v2/namespaces/default/entities/foo/rename",
+			expected: authorization.Attributes{
+				APIGroup:     "core",
+				APIVersion:   "v2",
+				Namespace:    "default",
+				Resource:     "entities",
+				ResourceName: "foo",
+				Verb:         "delete",
+			},
+		},
+		{
+			description: "POST /api/core/v2/namespaces/default/events/foo/check-cpu/snooze",
+			method:      "POST",
+			path:        "/api/core/v2/namespaces/default/events/foo/check-cpu/snooze",
+			expected: authorization.Attributes{
+				APIGroup:     "core",
+				APIVersion:   "v2",
+				Namespace:    "default",
+				Resource:     "silenced",
+				ResourceName: "entity:foo:check-cpu",
+				Verb:         "create",
+			},
+		},
+		{
+			description: "GET /alertmanager/default/api/v2/silences",
+			method:      "GET",
+			path:        "/alertmanager/default/api/v2/silences",
+			expected: authorization.Attributes{
+				Namespace: "default",
+				Resource:  "silenced",
+				Verb:      "list",
+			},
+		},
+		{
+			description: "DELETE /alertmanager/default/api/v2/silence/linux:disk",
+			method:      "DELETE",
+			path:        "/alertmanager/default/api/v2/silence/linux:disk",
+			expected: authorization.Attributes{
+				Namespace:    "default",
+				Resource:     "silenced",
+				ResourceName: "linux:disk",
+				Verb:         "delete",
+			},
+		},
+		{
+			description: "POST /grafana/default/query",
+			method:      "POST",
+			path:        "/grafana/default/query",
+			expected: authorization.Attributes{
+				Namespace: "default",
+				Resource:  "events",
+				Verb:      "list",
+			},
+		},
 	}
 
 	for _, tt := range cases {
@@ -125,10 +243,16 @@ func TestAuthorizationAttributes(t *testing.T) {
 
 			// Prepare the router
 			router := mux.NewRouter()
+			router.Path("/api/{group}/{version}/namespaces/{namespace}/{resource:entities}/{subresource:label}").Handler(testHandler)
+			router.Path("/api/{group}/{version}/namespaces/{namespace}/{resource:events}/{entity}/{check}/{subresource:snooze}").Handler(testHandler)
+			router.Path("/api/{group}/{version}/namespaces/{namespace}/{resource}/{id}/{subresource}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource}/{id}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/{resource}/{id}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/{resource}").Handler(testHandler)
+			router.Path("/alertmanager/{namespace}/api/v2/{resource:silence}/{id}").Handler(testHandler)
+			router.Path("/alertmanager/{namespace}/api/v2/{resource:silences}").Handler(testHandler)
+			router.PathPrefix("/grafana/{namespace}").Handler(testHandler)
 			router.PathPrefix("/").Handler(testHandler) // catch all
 			middleware := AuthorizationAttributes{}
 			router.Use(middleware.Then)
@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindingsAuthorizer struct {
+	bindings []string
+}
+
+func (a bindingsAuthorizer) Authorize(ctx context.Context, attrs *authorization.Attributes) (bool, error) {
+	return false, nil
+}
+
+func (a bindingsAuthorizer) EvaluatedBindings(ctx context.Context, attrs *authorization.Attributes) ([]string, error) {
+	return a.bindings, nil
+}
+
+func TestAuthorizationDenialDetails(t *testing.T) {
+	authorizer := bindingsAuthorizer{bindings: []string{"RoleBinding default/view (ClusterRole view)"}}
+	tests := []struct {
+		name        string
+		details     string
+		namespace   string
+		wantMessage string
+		wantDenial  *actions.Denial
+	}{
+		{
+			name:        "no details",
+			details:     DenialDetailsNone,
+			namespace:   "default",
+			wantMessage: "unauthorized to perform action",
+		},
+		{
+			name:        "request details",
+			details:     DenialDetailsRequest,
+			namespace:   "default",
+			wantMessage: `unauthorized to perform action: user "foo" requires a role granting the "delete" verb on the "checks" resource named "check-cpu" in the "default" namespace`,
+			wantDenial: &actions.Denial{
+				Username:     "foo",
+				Verb:         "delete",
+				Resource:     "checks",
+				ResourceName: "check-cpu",
+				Namespace:    "default",
+			},
+		},
+		{
+			name:        "trace details",
+			details:     DenialDetailsTrace,
+			wantMessage: `unauthorized to perform action: user "foo" requires a cluster role granting the "delete" verb on the "checks" resource named "check-cpu"`,
+			wantDenial: &actions.Denial{
+				Username:          "foo",
+				Verb:              "delete",
+				Resource:          "checks",
+				ResourceName:      "check-cpu",
+				EvaluatedBindings: authorizer.bindings,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Authorization{Authorizer: authorizer, DenialDetails: tt.details}.Then(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			)
+
+			attrs := &authorization.Attributes{
+				Namespace:    tt.namespace,
+				Resource:     "checks",
+				ResourceName: "check-cpu",
+				Verb:         "delete",
+				User:         corev2.User{Username: "foo"},
+			}
+			req := httptest.NewRequest(http.MethodDelete, "/checks/check-cpu", nil)
+			req = req.WithContext(authorization.SetAttributes(req.Context(), attrs))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			var body actions.Error
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantMessage, body.Message)
+			assert.Equal(t, tt.wantDenial, body.Denial)
+		})
+	}
+}
+
+func TestValidateDenialDetails(t *testing.T) {
+	for _, details := range []string{"", DenialDetailsNone, DenialDetailsRequest, DenialDetailsTrace} {
+		assert.NoError(t, ValidateDenialDetails(details))
+	}
+	assert.Error(t, ValidateDenialDetails("all"))
+}
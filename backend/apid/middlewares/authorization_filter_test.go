@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/stretchr/testify/assert"
+)
+
+type resourceNamesAuthorizer struct {
+	names []string
+	err   error
+}
+
+func (a resourceNamesAuthorizer) Authorize(ctx context.Context, attrs *authorization.Attributes) (bool, error) {
+	return false, nil
+}
+
+func (a resourceNamesAuthorizer) AuthorizedResourceNames(ctx context.Context, attrs *authorization.Attributes) ([]string, error) {
+	return a.names, a.err
+}
+
+func TestAuthorizationFilterLists(t *testing.T) {
+	tests := []struct {
+		name        string
+		authorizer  resourceNamesAuthorizer
+		filterLists bool
+		verb        string
+		wantStatus  int
+		wantNames   []string
+	}{
+		{
+			name:        "restricted list",
+			authorizer:  resourceNamesAuthorizer{names: []string{"check-cpu"}},
+			filterLists: true,
+			verb:        "list",
+			wantStatus:  http.StatusOK,
+			wantNames:   []string{"check-cpu"},
+		},
+		{
+			name:        "lists not filtered",
+			authorizer:  resourceNamesAuthorizer{names: []string{"check-cpu"}},
+			filterLists: false,
+			verb:        "list",
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:        "not a list",
+			authorizer:  resourceNamesAuthorizer{names: []string{"check-cpu"}},
+			filterLists: true,
+			verb:        "delete",
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:        "no resource names",
+			authorizer:  resourceNamesAuthorizer{},
+			filterLists: true,
+			verb:        "list",
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:        "authorizer error",
+			authorizer:  resourceNamesAuthorizer{err: errors.New("error")},
+			filterLists: true,
+			verb:        "list",
+			wantStatus:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			var restricted bool
+			handler := Authorization{Authorizer: tt.authorizer, FilterLists: tt.filterLists}.Then(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					names, restricted = authorization.GetResourceNames(r.Context())
+				}),
+			)
+
+			attrs := &authorization.Attributes{Resource: "checks", Verb: tt.verb}
+			req := httptest.NewRequest(http.MethodGet, "/checks", nil)
+			req = req.WithContext(authorization.SetAttributes(req.Context(), attrs))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantNames != nil, restricted)
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
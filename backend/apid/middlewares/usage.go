@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/usage"
+)
+
+// Usage is an HTTP middleware that records the requests of each user, and the
+// events they submit, with the usage tracker. It must be executed after the
+// AuthorizationAttributes middleware, and before the Authorization middleware
+// so that the denied requests are recorded too.
+type Usage struct {
+	Tracker *usage.Tracker
+}
+
+// Then middleware
+func (u Usage) Then(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attrs := authorization.GetAttributes(r.Context())
+		if u.Tracker != nil && attrs != nil && attrs.User.Username != "" {
+			u.Tracker.RecordRequest(attrs.User.Username)
+			if attrs.Resource == "events" && (attrs.Verb == "create" || attrs.Verb == "update") {
+				u.Tracker.RecordEvent(attrs.User.Username)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
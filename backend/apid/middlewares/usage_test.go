@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/usage"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsage(t *testing.T) {
+	tracker := usage.NewTracker()
+	mware := Usage{Tracker: tracker}
+	handler := mware.Then(testHandler())
+
+	requests := []*authorization.Attributes{
+		{User: types.User{Username: "admin"}, Resource: "checks", Verb: "list"},
+		{User: types.User{Username: "bot"}, Resource: "events", Verb: "create"},
+		{User: types.User{Username: "bot"}, Resource: "events", Verb: "update"},
+		{User: types.User{Username: "bot"}, Resource: "events", Verb: "list"},
+		{Resource: "checks", Verb: "list"},
+	}
+	for _, attrs := range requests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(authorization.SetAttributes(req.Context(), attrs))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	got := tracker.Usage()
+	require.Len(t, got, 2)
+	assert.Equal(t, "bot", got[0].Username)
+	assert.Equal(t, int64(3), got[0].Requests.LastHour)
+	assert.Equal(t, int64(2), got[0].Events.LastHour)
+	assert.Equal(t, "admin", got[1].Username)
+	assert.Equal(t, int64(1), got[1].Requests.LastHour)
+	assert.Equal(t, int64(0), got[1].Events.LastHour)
+}
@@ -0,0 +1,52 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+)
+
+// AgentCommandController represents the controller needs of the
+// AgentCommandRouter.
+type AgentCommandController interface {
+	Run(ctx context.Context, entity string, command *corev2.AgentCommand) (*corev2.AgentCommandResult, error)
+}
+
+// AgentCommandRouter handles requests for /entities/:entity/commands
+type AgentCommandRouter struct {
+	controller AgentCommandController
+}
+
+// NewAgentCommandRouter instantiates a new router for agent commands.
+func NewAgentCommandRouter(ctrl AgentCommandController) *AgentCommandRouter {
+	return &AgentCommandRouter{
+		controller: ctrl,
+	}
+}
+
+// Mount the AgentCommandRouter to a parent Router
+func (r *AgentCommandRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:entities}",
+	}
+
+	// the subresource variable is used to authorize the requests
+	routes.Path("{id}/{subresource:commands}", r.run).Methods(http.MethodPost)
+}
+
+func (r *AgentCommandRouter) run(req *http.Request) (interface{}, error) {
+	var command corev2.AgentCommand
+	if err := UnmarshalBody(req, &command); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	entity, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, err
+	}
+	return r.controller.Run(req.Context(), entity, &command)
+}
@@ -0,0 +1,63 @@
+package routers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAgentCommandController struct {
+	mock.Mock
+}
+
+func (m *mockAgentCommandController) Run(ctx context.Context, entity string, command *corev2.AgentCommand) (*corev2.AgentCommandResult, error) {
+	args := m.Called(ctx, entity, command)
+	return args.Get(0).(*corev2.AgentCommandResult), args.Error(1)
+}
+
+func TestAgentCommandRouter(t *testing.T) {
+	controller := &mockAgentCommandController{}
+	router := mux.NewRouter()
+	NewAgentCommandRouter(controller).Mount(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	command := &corev2.AgentCommand{Command: "uptime", Timeout: 5}
+	result := &corev2.AgentCommandResult{ID: "42", Entity: "entity1", Check: corev2.AgentCommandCheckName}
+	controller.On("Run", mock.Anything, "entity1", command).Return(result, nil).Once()
+
+	body, err := json.Marshal(command)
+	require.NoError(t, err)
+	req := newRequest(t, http.MethodPost, server.URL+"/namespaces/default/entities/entity1/commands", bytes.NewReader(body))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got corev2.AgentCommandResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.Equal(t, result, &got)
+	controller.AssertExpectations(t)
+}
+
+func TestAgentCommandRouterBadBody(t *testing.T) {
+	router := mux.NewRouter()
+	NewAgentCommandRouter(&mockAgentCommandController{}).Mount(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req := newRequest(t, http.MethodPost, server.URL+"/namespaces/default/entities/entity1/commands", bytes.NewReader([]byte("{")))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
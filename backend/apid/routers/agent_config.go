@@ -0,0 +1,72 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+)
+
+// AgentConfigController represents the controller needs of the
+// AgentConfigRouter.
+type AgentConfigController interface {
+	Get(ctx context.Context, entity string) (*corev2.AgentConfig, error)
+	Update(ctx context.Context, entity string, config *corev2.AgentConfig) error
+	Delete(ctx context.Context, entity string) error
+}
+
+// AgentConfigRouter handles requests for /entities/:entity/config
+type AgentConfigRouter struct {
+	controller AgentConfigController
+}
+
+// NewAgentConfigRouter instantiates a new router for the agent configs.
+func NewAgentConfigRouter(ctrl AgentConfigController) *AgentConfigRouter {
+	return &AgentConfigRouter{
+		controller: ctrl,
+	}
+}
+
+// Mount the AgentConfigRouter to a parent Router
+func (r *AgentConfigRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:entities}",
+	}
+
+	routes.Path("{id}/config", r.get).Methods(http.MethodGet)
+	routes.Path("{id}/config", r.update).Methods(http.MethodPut)
+	routes.Path("{id}/config", r.delete).Methods(http.MethodDelete)
+}
+
+func (r *AgentConfigRouter) get(req *http.Request) (interface{}, error) {
+	entity, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, err
+	}
+	return r.controller.Get(req.Context(), entity)
+}
+
+func (r *AgentConfigRouter) update(req *http.Request) (interface{}, error) {
+	config := &corev2.AgentConfig{}
+	if err := UnmarshalBody(req, config); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	entity, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, err
+	}
+	err = r.controller.Update(req.Context(), entity, config)
+	return config, err
+}
+
+func (r *AgentConfigRouter) delete(req *http.Request) (interface{}, error) {
+	entity, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.controller.Delete(req.Context(), entity)
+}
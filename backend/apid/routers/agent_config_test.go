@@ -0,0 +1,85 @@
+package routers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAgentConfigController struct {
+	mock.Mock
+}
+
+func (m *mockAgentConfigController) Get(ctx context.Context, entity string) (*corev2.AgentConfig, error) {
+	args := m.Called(ctx, entity)
+	return args.Get(0).(*corev2.AgentConfig), args.Error(1)
+}
+
+func (m *mockAgentConfigController) Update(ctx context.Context, entity string, config *corev2.AgentConfig) error {
+	return m.Called(ctx, entity, config).Error(0)
+}
+
+func (m *mockAgentConfigController) Delete(ctx context.Context, entity string) error {
+	return m.Called(ctx, entity).Error(0)
+}
+
+func TestAgentConfigRouter(t *testing.T) {
+	controller := &mockAgentConfigController{}
+	router := mux.NewRouter()
+	NewAgentConfigRouter(controller).Mount(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	url := server.URL + "/namespaces/default/entities/entity1/config"
+	config := &corev2.AgentConfig{LogLevel: "debug", Labels: map[string]string{"region": "us-west-2"}}
+
+	controller.On("Update", mock.Anything, "entity1", config).Return(nil).Once()
+	body, err := json.Marshal(config)
+	require.NoError(t, err)
+	req := newRequest(t, http.MethodPut, url, bytes.NewReader(body))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	controller.On("Get", mock.Anything, "entity1").Return(config, nil).Once()
+	req = newRequest(t, http.MethodGet, url, nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var got corev2.AgentConfig
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, config, &got)
+
+	controller.On("Delete", mock.Anything, "entity1").Return(nil).Once()
+	req = newRequest(t, http.MethodDelete, url, nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	controller.AssertExpectations(t)
+}
+
+func TestAgentConfigRouterBadBody(t *testing.T) {
+	router := mux.NewRouter()
+	NewAgentConfigRouter(&mockAgentConfigController{}).Mount(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req := newRequest(t, http.MethodPut, server.URL+"/namespaces/default/entities/entity1/config", bytes.NewReader([]byte("{")))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,51 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// AgentSpoolController represents the controller needs of the
+// AgentSpoolRouter.
+type AgentSpoolController interface {
+	Do(ctx context.Context, entity, action string) (*corev2.AgentSpoolStats, error)
+}
+
+// AgentSpoolRouter handles requests for /entities/:entity/spool
+type AgentSpoolRouter struct {
+	controller AgentSpoolController
+}
+
+// NewAgentSpoolRouter instantiates a new router for agent spools.
+func NewAgentSpoolRouter(ctrl AgentSpoolController) *AgentSpoolRouter {
+	return &AgentSpoolRouter{
+		controller: ctrl,
+	}
+}
+
+// Mount the AgentSpoolRouter to a parent Router
+func (r *AgentSpoolRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:entities}",
+	}
+
+	routes.Path("{id}/spool", r.handle(corev2.AgentSpoolInfo)).Methods(http.MethodGet)
+	routes.Path("{id}/spool", r.handle(corev2.AgentSpoolPurge)).Methods(http.MethodDelete)
+	routes.Path("{id}/spool/flush", r.handle(corev2.AgentSpoolFlush)).Methods(http.MethodPost)
+}
+
+func (r *AgentSpoolRouter) handle(action string) actionHandlerFunc {
+	return func(req *http.Request) (interface{}, error) {
+		params := mux.Vars(req)
+		entity, err := url.PathUnescape(params["id"])
+		if err != nil {
+			return nil, err
+		}
+		return r.controller.Do(req.Context(), entity, action)
+	}
+}
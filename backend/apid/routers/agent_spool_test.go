@@ -0,0 +1,59 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAgentSpoolController struct {
+	mock.Mock
+}
+
+func (m *mockAgentSpoolController) Do(ctx context.Context, entity, action string) (*corev2.AgentSpoolStats, error) {
+	args := m.Called(ctx, entity, action)
+	return args.Get(0).(*corev2.AgentSpoolStats), args.Error(1)
+}
+
+func TestAgentSpoolRouter(t *testing.T) {
+	controller := &mockAgentSpoolController{}
+	router := mux.NewRouter()
+	NewAgentSpoolRouter(controller).Mount(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	testCases := []struct {
+		method string
+		path   string
+		action string
+	}{
+		{http.MethodGet, "/namespaces/default/entities/entity1/spool", corev2.AgentSpoolInfo},
+		{http.MethodDelete, "/namespaces/default/entities/entity1/spool", corev2.AgentSpoolPurge},
+		{http.MethodPost, "/namespaces/default/entities/entity1/spool/flush", corev2.AgentSpoolFlush},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			controller.On("Do", mock.Anything, "entity1", tc.action).
+				Return(&corev2.AgentSpoolStats{Messages: 3}, nil).Once()
+
+			req := newRequest(t, tc.method, server.URL+tc.path, nil)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var stats corev2.AgentSpoolStats
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+			assert.Equal(t, 3, stats.Messages)
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package routers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	// The matchers of the Alertmanager silences translated to the Sensu
+	// silenced entries. The alertname, which identifies the alerts of
+	// Prometheus, identifies the checks of Sensu.
+	alertmanagerMatcherAlertname    = "alertname"
+	alertmanagerMatcherCheck        = "check"
+	alertmanagerMatcherSubscription = "subscription"
+	alertmanagerMatcherEntity       = "entity"
+	alertmanagerMatcherInstance     = "instance"
+	alertmanagerMatcherNamespace    = "namespace"
+
+	// The states of the Alertmanager silences. The expired Sensu silenced
+	// entries are deleted, so they are never reported.
+	alertmanagerSilenceActive  = "active"
+	alertmanagerSilencePending = "pending"
+
+	// entitySubscriptionPrefix is the prefix of the subscriptions unique to
+	// each entity.
+	entitySubscriptionPrefix = "entity:"
+)
+
+// alertmanagerMatcher is a matcher of the Alertmanager API.
+type alertmanagerMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual *bool  `json:"isEqual,omitempty"`
+}
+
+// alertmanagerSilence is a silence of the Alertmanager API, as it is posted
+// and returned.
+type alertmanagerSilence struct {
+	ID        string                     `json:"id,omitempty"`
+	Matchers  []alertmanagerMatcher      `json:"matchers"`
+	StartsAt  time.Time                  `json:"startsAt"`
+	EndsAt    time.Time                  `json:"endsAt"`
+	CreatedBy string                     `json:"createdBy"`
+	Comment   string                     `json:"comment"`
+	Status    *alertmanagerSilenceStatus `json:"status,omitempty"`
+	UpdatedAt *time.Time                 `json:"updatedAt,omitempty"`
+}
+
+type alertmanagerSilenceStatus struct {
+	State string `json:"state"`
+}
+
+// alertmanagerSilenceID is the response to the creation of a silence.
+type alertmanagerSilenceID struct {
+	SilenceID string `json:"silenceID"`
+}
+
+// AlertmanagerRouter handles requests for the silences of the Alertmanager
+// API, so the tools managing the silences of Prometheus can manage the
+// silenced entries of a namespace. The silences are identified by the names
+// of the silenced entries they are translated to.
+type AlertmanagerRouter struct {
+	controller silencedController
+	store      store.SilencedStore
+}
+
+// NewAlertmanagerRouter instantiates a new router for the Alertmanager
+// silences.
+func NewAlertmanagerRouter(store store.SilencedStore) *AlertmanagerRouter {
+	return &AlertmanagerRouter{
+		controller: actions.NewSilencedController(store),
+		store:      store,
+	}
+}
+
+// Mount the AlertmanagerRouter to a parent Router
+func (r *AlertmanagerRouter) Mount(parent *mux.Router) {
+	// the resource variable is used to authorize the requests, on the
+	// silenced entries
+	parent.HandleFunc("/{resource:silences}", compatibleHandler(r.list)).Methods(http.MethodGet)
+	parent.HandleFunc("/{resource:silences}", compatibleHandler(r.create)).Methods(http.MethodPost)
+	parent.HandleFunc("/{resource:silence}/{id}", compatibleHandler(r.get)).Methods(http.MethodGet)
+	parent.HandleFunc("/{resource:silence}/{id}", compatibleHandler(r.delete)).Methods(http.MethodDelete)
+}
+
+func (r *AlertmanagerRouter) list(req *http.Request) (interface{}, error) {
+	var filters []alertmanagerMatcher
+	for _, filter := range req.URL.Query()["filter"] {
+		matcher, err := parseAlertmanagerFilter(filter)
+		if err != nil {
+			return nil, actions.NewError(actions.InvalidArgument, err)
+		}
+		filters = append(filters, matcher)
+	}
+
+	entries, err := r.controller.List(req.Context(), "", "")
+	if err != nil {
+		return nil, err
+	}
+	restricted, err := restrictToResourceNames(req, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	silences := []alertmanagerSilence{}
+	for _, entry := range restricted.([]*corev2.Silenced) {
+		silence := silenceFromSilenced(entry, now)
+		if matchesAlertmanagerFilters(silence, filters) {
+			silences = append(silences, silence)
+		}
+	}
+	return silences, nil
+}
+
+func (r *AlertmanagerRouter) get(req *http.Request) (interface{}, error) {
+	entry, err := r.store.GetSilencedEntryByName(req.Context(), mux.Vars(req)["id"])
+	if err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	if entry == nil {
+		return nil, actions.NewErrorf(actions.NotFound)
+	}
+	return silenceFromSilenced(entry, time.Now()), nil
+}
+
+func (r *AlertmanagerRouter) create(req *http.Request) (interface{}, error) {
+	var silence alertmanagerSilence
+	if err := UnmarshalBody(req, &silence); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	namespace := mux.Vars(req)["namespace"]
+	entry, err := silencedFromSilence(&silence, namespace, time.Now())
+	if err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	// The ID of a silenced entry is derived from its matchers, so updating a
+	// silence with other matchers would replace another entry, which the
+	// authorization to create silenced entries does not grant. It must be
+	// deleted and created again instead.
+	if silence.ID != "" && silence.ID != entry.Name {
+		return nil, actions.NewErrorf(actions.InvalidArgument, "the matchers of silence %q can not be updated, delete it and create a new silence instead", silence.ID)
+	}
+	if err := r.controller.CreateOrReplace(req.Context(), entry); err != nil {
+		return nil, err
+	}
+	return alertmanagerSilenceID{SilenceID: entry.Name}, nil
+}
+
+func (r *AlertmanagerRouter) delete(req *http.Request) (interface{}, error) {
+	id := mux.Vars(req)["id"]
+	entry, err := r.store.GetSilencedEntryByName(req.Context(), id)
+	if err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	if entry == nil {
+		return nil, actions.NewErrorf(actions.NotFound)
+	}
+	if err := r.store.DeleteSilencedEntryByName(req.Context(), id); err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	return nil, nil
+}
+
+// silencedFromSilence translates an Alertmanager silence to the silenced entry
+// of the namespace. The matchers must be equality matchers on the check, the
+// subscription or the entity, since the silenced entries can not express the
+// others.
+func silencedFromSilence(silence *alertmanagerSilence, namespace string, now time.Time) (*corev2.Silenced, error) {
+	entry := corev2.NewSilenced(corev2.NewObjectMeta("", namespace))
+	for _, matcher := range silence.Matchers {
+		if matcher.IsRegex {
+			return nil, fmt.Errorf("matcher %q: regular expressions are not supported", matcher.Name)
+		}
+		if matcher.IsEqual != nil && !*matcher.IsEqual {
+			return nil, fmt.Errorf("matcher %q: negative matchers are not supported", matcher.Name)
+		}
+
+		var field *string
+		value := matcher.Value
+		switch matcher.Name {
+		case alertmanagerMatcherAlertname, alertmanagerMatcherCheck:
+			field = &entry.Check
+		case alertmanagerMatcherSubscription:
+			field = &entry.Subscription
+		case alertmanagerMatcherEntity, alertmanagerMatcherInstance:
+			field = &entry.Subscription
+			value = entitySubscriptionPrefix + value
+		case alertmanagerMatcherNamespace:
+			if value != namespace {
+				return nil, fmt.Errorf("matcher %q: the silence must be in the namespace %q", matcher.Name, namespace)
+			}
+			continue
+		default:
+			return nil, fmt.Errorf("matcher %q: unsupported matcher, must be one of alertname, check, subscription, entity, instance or namespace", matcher.Name)
+		}
+		if *field != "" && *field != value {
+			return nil, fmt.Errorf("matcher %q: the silence already matches %q", matcher.Name, *field)
+		}
+		*field = value
+	}
+	if entry.Check == "" && entry.Subscription == "" {
+		return nil, errors.New("the silence must match a check, a subscription or an entity")
+	}
+
+	begin := now
+	if silence.StartsAt.After(now) {
+		begin = silence.StartsAt
+	}
+	if !silence.EndsAt.After(begin) {
+		return nil, errors.New("the silence must end in the future, after it starts")
+	}
+	if !silence.StartsAt.IsZero() {
+		entry.Begin = silence.StartsAt.Unix()
+	}
+	// the expiration is relative to the beginning of the silence, if it is
+	// in the future
+	entry.Expire = int64(silence.EndsAt.Sub(begin).Round(time.Second) / time.Second)
+	if entry.Expire < 1 {
+		entry.Expire = 1
+	}
+	entry.Creator = silence.CreatedBy
+	entry.Reason = silence.Comment
+	return entry, nil
+}
+
+// silenceFromSilenced translates a silenced entry to an Alertmanager silence.
+// The expiration of the stored silenced entries is the time left before they
+// expire, and the ones which never expire end in a hundred years.
+func silenceFromSilenced(entry *corev2.Silenced, now time.Time) alertmanagerSilence {
+	startsAt := time.Unix(entry.Begin, 0).UTC()
+	endsAt := now.AddDate(100, 0, 0).UTC()
+	if entry.Expire > 0 {
+		endsAt = now.Add(time.Duration(entry.Expire) * time.Second).UTC()
+	}
+	state := alertmanagerSilenceActive
+	if startsAt.After(now) {
+		state = alertmanagerSilencePending
+	}
+
+	matchers := []alertmanagerMatcher{}
+	if entry.Check != "" && entry.Check != "*" {
+		matchers = append(matchers, alertmanagerMatcher{Name: alertmanagerMatcherAlertname, Value: entry.Check})
+	}
+	if strings.HasPrefix(entry.Subscription, entitySubscriptionPrefix) {
+		entity := strings.TrimPrefix(entry.Subscription, entitySubscriptionPrefix)
+		matchers = append(matchers, alertmanagerMatcher{Name: alertmanagerMatcherEntity, Value: entity})
+	} else if entry.Subscription != "" && entry.Subscription != "*" {
+		matchers = append(matchers, alertmanagerMatcher{Name: alertmanagerMatcherSubscription, Value: entry.Subscription})
+	}
+	matchers = append(matchers, alertmanagerMatcher{Name: alertmanagerMatcherNamespace, Value: entry.Namespace})
+	isEqual := true
+	for i := range matchers {
+		matchers[i].IsEqual = &isEqual
+	}
+
+	return alertmanagerSilence{
+		ID:        entry.Name,
+		Matchers:  matchers,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		CreatedBy: entry.Creator,
+		Comment:   entry.Reason,
+		Status:    &alertmanagerSilenceStatus{State: state},
+		UpdatedAt: &startsAt,
+	}
+}
+
+// parseAlertmanagerFilter parses a filter of the silences, which must be an
+// equality matcher such as alertname="disk".
+func parseAlertmanagerFilter(filter string) (alertmanagerMatcher, error) {
+	filter = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(filter), "{"), "}")
+	parts := strings.SplitN(filter, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || strings.ContainsAny(parts[0], "!~") || strings.HasPrefix(parts[1], "~") {
+		return alertmanagerMatcher{}, fmt.Errorf("invalid filter %q, only equality matchers are supported", filter)
+	}
+	value := strings.TrimSpace(parts[1])
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+	return alertmanagerMatcher{Name: strings.TrimSpace(parts[0]), Value: value}, nil
+}
+
+// matchesAlertmanagerFilters returns true if the silence has a matcher equal
+// to each of the filters.
+func matchesAlertmanagerFilters(silence alertmanagerSilence, filters []alertmanagerMatcher) bool {
+	for _, filter := range filters {
+		found := false
+		for _, matcher := range silence.Matchers {
+			if matcher.Name == filter.Name && matcher.Value == filter.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
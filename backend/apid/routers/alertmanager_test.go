@@ -0,0 +1,309 @@
+package routers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertmanagerRouter(t *testing.T) {
+	s := &mockstore.MockStore{}
+	router := NewAlertmanagerRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/alertmanager/{namespace}/api/v2").Subrouter()
+	router.Mount(parentRouter)
+
+	fixture := corev2.FixtureSilenced("linux:disk")
+	silence := []byte(`{"matchers":[{"name":"alertname","value":"disk","isRegex":false},{"name":"subscription","value":"linux","isRegex":false}],"endsAt":"2099-01-01T00:00:00Z","createdBy":"admin","comment":"maintenance"}`)
+
+	tests := []routerTestCase{
+		{
+			name:   "it lists the silences",
+			method: http.MethodGet,
+			path:   "/alertmanager/default/api/v2/silences",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetSilencedEntries", mock.Anything).
+					Return([]*corev2.Silenced{fixture}, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "it rejects the regular expression filters",
+			method:         http.MethodGet,
+			path:           "/alertmanager/default/api/v2/silences?filter=alertname%3D~%22disk%22",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "it returns 404 if the silence does not exist",
+			method: http.MethodGet,
+			path:   "/alertmanager/default/api/v2/silence/linux:disk",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetSilencedEntryByName", mock.Anything, "linux:disk").
+					Return((*corev2.Silenced)(nil), nil).Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:   "it returns the silence",
+			method: http.MethodGet,
+			path:   "/alertmanager/default/api/v2/silence/linux:disk",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetSilencedEntryByName", mock.Anything, "linux:disk").
+					Return(fixture, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "it rejects the silences which can not be translated",
+			method:         http.MethodPost,
+			path:           "/alertmanager/default/api/v2/silences",
+			body:           []byte(`{"matchers":[{"name":"severity","value":"critical","isRegex":false}],"endsAt":"2099-01-01T00:00:00Z"}`),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "it returns 500 if the store fails to create the silence",
+			method: http.MethodPost,
+			path:   "/alertmanager/default/api/v2/silences",
+			body:   silence,
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("UpdateSilencedEntry", mock.Anything, mock.Anything).
+					Return(errors.New("error")).Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:   "it creates the silence",
+			method: http.MethodPost,
+			path:   "/alertmanager/default/api/v2/silences",
+			body:   silence,
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("UpdateSilencedEntry", mock.Anything, mock.MatchedBy(func(entry *corev2.Silenced) bool {
+					return entry.Name == "linux:disk" && entry.Namespace == "default" && entry.Reason == "maintenance"
+				})).Return(nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it returns 404 when deleting a missing silence",
+			method: http.MethodDelete,
+			path:   "/alertmanager/default/api/v2/silence/linux:disk",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetSilencedEntryByName", mock.Anything, "linux:disk").
+					Return((*corev2.Silenced)(nil), nil).Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:   "it deletes the silence",
+			method: http.MethodDelete,
+			path:   "/alertmanager/default/api/v2/silence/linux:disk",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetSilencedEntryByName", mock.Anything, "linux:disk").
+					Return(fixture, nil).Once()
+				s.On("DeleteSilencedEntryByName", mock.Anything, []string{"linux:disk"}).
+					Return(nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
+
+func TestSilencedFromSilence(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	isNotEqual := false
+
+	tests := []struct {
+		name    string
+		silence alertmanagerSilence
+		want    *corev2.Silenced
+		wantErr bool
+	}{
+		{
+			name: "check and subscription",
+			silence: alertmanagerSilence{
+				Matchers: []alertmanagerMatcher{
+					{Name: "alertname", Value: "disk"},
+					{Name: "subscription", Value: "linux"},
+					{Name: "namespace", Value: "default"},
+				},
+				EndsAt:    now.Add(time.Hour),
+				CreatedBy: "admin",
+				Comment:   "maintenance",
+			},
+			want: &corev2.Silenced{
+				ObjectMeta:   corev2.NewObjectMeta("", "default"),
+				Check:        "disk",
+				Subscription: "linux",
+				Expire:       3600,
+				Creator:      "admin",
+				Reason:       "maintenance",
+			},
+		},
+		{
+			name: "entity in the future",
+			silence: alertmanagerSilence{
+				Matchers: []alertmanagerMatcher{{Name: "instance", Value: "server1"}},
+				StartsAt: now.Add(time.Hour),
+				EndsAt:   now.Add(3 * time.Hour),
+			},
+			want: &corev2.Silenced{
+				ObjectMeta:   corev2.NewObjectMeta("", "default"),
+				Subscription: "entity:server1",
+				Begin:        now.Add(time.Hour).Unix(),
+				Expire:       7200,
+			},
+		},
+		{
+			name: "started in the past",
+			silence: alertmanagerSilence{
+				Matchers: []alertmanagerMatcher{{Name: "check", Value: "disk"}},
+				StartsAt: now.Add(-time.Hour),
+				EndsAt:   now.Add(time.Hour),
+			},
+			want: &corev2.Silenced{
+				ObjectMeta: corev2.NewObjectMeta("", "default"),
+				Check:      "disk",
+				Begin:      now.Add(-time.Hour).Unix(),
+				Expire:     3600,
+			},
+		},
+		{
+			name: "regular expression",
+			silence: alertmanagerSilence{
+				Matchers: []alertmanagerMatcher{{Name: "alertname", Value: "disk.*", IsRegex: true}},
+				EndsAt:   now.Add(time.Hour),
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative matcher",
+			silence: alertmanagerSilence{
+				Matchers: []alertmanagerMatcher{{Name: "alertname", Value: "disk", IsEqual: &isNotEqual}},
+				EndsAt:   now.Add(time.Hour),
+			},
+			wantErr: true,
+		},
+		{
+			name: "subscription and entity",
+			silence: alertmanagerSilence{
+				Matchers: []alertmanagerMatcher{
+					{Name: "subscription", Value: "linux"},
+					{Name: "entity", Value: "server1"},
+				},
+				EndsAt: now.Add(time.Hour),
+			},
+			wantErr: true,
+		},
+		{
+			name: "other namespace",
+			silence: alertmanagerSilence{
+				Matchers: []alertmanagerMatcher{
+					{Name: "alertname", Value: "disk"},
+					{Name: "namespace", Value: "production"},
+				},
+				EndsAt: now.Add(time.Hour),
+			},
+			wantErr: true,
+		},
+		{
+			name: "namespace only",
+			silence: alertmanagerSilence{
+				Matchers: []alertmanagerMatcher{{Name: "namespace", Value: "default"}},
+				EndsAt:   now.Add(time.Hour),
+			},
+			wantErr: true,
+		},
+		{
+			name: "ended",
+			silence: alertmanagerSilence{
+				Matchers: []alertmanagerMatcher{{Name: "alertname", Value: "disk"}},
+				EndsAt:   now.Add(-time.Minute),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := silencedFromSilence(&tt.silence, "default", now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSilenceFromSilenced(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	entry := corev2.FixtureSilenced("entity:server1:disk")
+	entry.Begin = now.Add(time.Hour).Unix()
+	entry.Expire = 7200
+	entry.Creator = "admin"
+	entry.Reason = "maintenance"
+
+	silence := silenceFromSilenced(entry, now)
+	assert.Equal(t, "entity:server1:disk", silence.ID)
+	assert.Equal(t, "pending", silence.Status.State)
+	assert.Equal(t, now.Add(time.Hour).UTC(), silence.StartsAt)
+	assert.Equal(t, now.Add(2*time.Hour).UTC(), silence.EndsAt)
+	assert.Equal(t, "admin", silence.CreatedBy)
+	assert.Equal(t, "maintenance", silence.Comment)
+
+	var names []string
+	for _, matcher := range silence.Matchers {
+		names = append(names, matcher.Name+"="+matcher.Value)
+	}
+	assert.Equal(t, []string{"alertname=disk", "entity=server1", "namespace=default"}, names)
+
+	// the silence round trips through the Alertmanager API
+	b, err := json.Marshal(silence)
+	require.NoError(t, err)
+	var decoded alertmanagerSilence
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	translated, err := silencedFromSilence(&decoded, "default", now)
+	require.NoError(t, err)
+	assert.Equal(t, entry.Check, translated.Check)
+	assert.Equal(t, entry.Subscription, translated.Subscription)
+	// the time left of the stored entry includes the hour before it begins
+	assert.Equal(t, int64(3600), translated.Expire)
+
+	// the silenced entries which never expire end in a hundred years
+	entry.Begin = now.Add(-time.Hour).Unix()
+	entry.Expire = -1
+	silence = silenceFromSilenced(entry, now)
+	assert.Equal(t, "active", silence.Status.State)
+	assert.Equal(t, now.AddDate(100, 0, 0).UTC(), silence.EndsAt)
+}
+
+func TestParseAlertmanagerFilter(t *testing.T) {
+	matcher, err := parseAlertmanagerFilter(`alertname="disk"`)
+	require.NoError(t, err)
+	assert.Equal(t, alertmanagerMatcher{Name: "alertname", Value: "disk"}, matcher)
+
+	matcher, err = parseAlertmanagerFilter(`{subscription=linux}`)
+	require.NoError(t, err)
+	assert.Equal(t, alertmanagerMatcher{Name: "subscription", Value: "linux"}, matcher)
+
+	for _, filter := range []string{`alertname=~"disk"`, `alertname!="disk"`, `alertname`, `="disk"`} {
+		_, err := parseAlertmanagerFilter(filter)
+		assert.Error(t, err, filter)
+	}
+
+	silence := alertmanagerSilence{Matchers: []alertmanagerMatcher{{Name: "alertname", Value: "disk"}}}
+	assert.True(t, matchesAlertmanagerFilters(silence, []alertmanagerMatcher{{Name: "alertname", Value: "disk"}}))
+	assert.False(t, matchesAlertmanagerFilters(silence, []alertmanagerMatcher{{Name: "alertname", Value: "cpu"}}))
+}
@@ -13,11 +13,12 @@ type AssetsRouter struct {
 }
 
 // NewAssetRouter instantiates new router for controlling asset resources
-func NewAssetRouter(store store.ResourceStore) *AssetsRouter {
+func NewAssetRouter(store store.Store) *AssetsRouter {
 	return &AssetsRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.Asset{},
-			Store:    store,
+			Resource:      &corev2.Asset{},
+			Store:         store,
+			ManagedFields: store,
 		},
 	}
 }
@@ -34,5 +35,6 @@ func (r *AssetsRouter) Mount(parent *mux.Router) {
 	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:assets}", corev2.AssetFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 	routes.Del(r.handlers.DeleteResource)
 }
@@ -0,0 +1,41 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// AutoscalingController represents the controller needs of the
+// AutoscalingRouter.
+type AutoscalingController interface {
+	Signals() (*corev2.AutoscalingSignals, error)
+}
+
+// AutoscalingRouter handles requests for /autoscaling
+type AutoscalingRouter struct {
+	controller AutoscalingController
+}
+
+// NewAutoscalingRouter instantiates a new router exposing the autoscaling
+// signals of the backend.
+func NewAutoscalingRouter(ctrl AutoscalingController) *AutoscalingRouter {
+	return &AutoscalingRouter{
+		controller: ctrl,
+	}
+}
+
+// Mount the AutoscalingRouter to a parent Router
+func (r *AutoscalingRouter) Mount(parent *mux.Router) {
+	parent.HandleFunc("/autoscaling", r.signals).Methods(http.MethodGet)
+}
+
+func (r *AutoscalingRouter) signals(w http.ResponseWriter, req *http.Request) {
+	signals, err := r.controller.Signals()
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	RespondWith(w, req, signals)
+}
@@ -0,0 +1,60 @@
+package routers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAutoscalingController struct {
+	mock.Mock
+}
+
+func (m *mockAutoscalingController) Signals() (*corev2.AutoscalingSignals, error) {
+	args := m.Called()
+	return args.Get(0).(*corev2.AutoscalingSignals), args.Error(1)
+}
+
+func newAutoscalingTest(t *testing.T) (*mockAutoscalingController, *httptest.Server) {
+	controller := &mockAutoscalingController{}
+	autoscalingRouter := NewAutoscalingRouter(controller)
+	router := mux.NewRouter()
+	autoscalingRouter.Mount(router)
+	return controller, httptest.NewServer(router)
+}
+
+func TestAutoscalingSignals(t *testing.T) {
+	controller, server := newAutoscalingTest(t)
+	defer server.Close()
+	controller.On("Signals").Return(&corev2.AutoscalingSignals{AgentSessions: 42}, nil)
+
+	req := newRequest(t, http.MethodGet, server.URL+"/autoscaling", nil)
+	resp, err := new(http.Client).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var signals corev2.AutoscalingSignals
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&signals))
+	assert.Equal(t, int64(42), signals.AgentSessions)
+}
+
+func TestAutoscalingSignalsError(t *testing.T) {
+	controller, server := newAutoscalingTest(t)
+	defer server.Close()
+	controller.On("Signals").Return((*corev2.AutoscalingSignals)(nil), errors.New("error"))
+
+	req := newRequest(t, http.MethodGet, server.URL+"/autoscaling", nil)
+	resp, err := new(http.Client).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
@@ -12,6 +12,8 @@ import (
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/authorization/rbac"
+	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/types"
 )
@@ -21,21 +23,31 @@ type checkController interface {
 	AddCheckHook(context.Context, string, corev2.HookList) error
 	RemoveCheckHook(context.Context, string, string, string) error
 	QueueAdhocRequest(context.Context, string, *corev2.AdhocRequest) error
+	Lint(context.Context, *corev2.CheckConfig) ([]corev2.CheckConfigWarning, error)
 }
 
 // ChecksRouter handles requests for /checks
 type ChecksRouter struct {
 	controller checkController
 	handlers   handlers.Handlers
+	deleter    actions.CheckDeleter
 }
 
 // NewChecksRouter instantiates new router for controlling check resources
-func NewChecksRouter(store store.Store, getter types.QueueGetter) *ChecksRouter {
+func NewChecksRouter(store store.Store, events store.EventStore, getter types.QueueGetter, bus messaging.MessageBus) *ChecksRouter {
 	return &ChecksRouter{
 		controller: actions.NewCheckController(store, getter),
 		handlers: handlers.Handlers{
-			Resource: &corev2.CheckConfig{},
-			Store:    store,
+			Resource:      &corev2.CheckConfig{},
+			Store:         store,
+			LabelPolicy:   store,
+			ManagedFields: store,
+		},
+		deleter: actions.CheckDeleter{
+			Store:      store,
+			EventStore: events,
+			Bus:        bus,
+			Authorizer: &rbac.Authorizer{Store: store},
 		},
 	}
 }
@@ -47,16 +59,18 @@ func (r *ChecksRouter) Mount(parent *mux.Router) {
 		PathPrefix: "/namespaces/{namespace}/{resource:checks}",
 	}
 
-	routes.Del(r.handlers.DeleteResource)
+	routes.Del(r.deleter.Delete)
 	routes.Get(r.handlers.GetResource)
 	routes.List(r.handlers.ListResources, corev2.CheckConfigFields)
 	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:checks}", corev2.CheckConfigFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 
 	// Custom
 	routes.Path("{id}/hooks/{type}", r.addCheckHook).Methods(http.MethodPut)
 	routes.Path("{id}/hooks/{type}/hook/{hook}", r.removeCheckHook).Methods(http.MethodDelete)
+	routes.Path("validate", r.lintCheck).Methods(http.MethodPost)
 
 	// handlefunc returns a custom status and response
 	parent.HandleFunc(path.Join(routes.PathPrefix, "{id}/execute"), r.adhocRequest).Methods(http.MethodPost)
@@ -96,6 +110,22 @@ func (r *ChecksRouter) removeCheckHook(req *http.Request) (interface{}, error) {
 	return nil, err
 }
 
+func (r *ChecksRouter) lintCheck(req *http.Request) (interface{}, error) {
+	check := &corev2.CheckConfig{}
+	if err := UnmarshalBody(req, check); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	if check.Namespace == "" {
+		check.Namespace = mux.Vars(req)["namespace"]
+	}
+	if err := handlers.CheckMeta(check, mux.Vars(req)); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	return r.controller.Lint(req.Context(), check)
+}
+
 func (r *ChecksRouter) adhocRequest(w http.ResponseWriter, req *http.Request) {
 	adhocReq := corev2.AdhocRequest{}
 	if err := UnmarshalBody(req, &adhocReq); err != nil {
@@ -35,6 +35,11 @@ func (m *mockCheckController) QueueAdhocRequest(ctx context.Context, check strin
 	return m.Called(ctx, check, req).Error(0)
 }
 
+func (m *mockCheckController) Lint(ctx context.Context, check *corev2.CheckConfig) ([]corev2.CheckConfigWarning, error) {
+	args := m.Called(ctx, check)
+	return args.Get(0).([]corev2.CheckConfigWarning), args.Error(1)
+}
+
 func TestHttpApiChecksAdhocRequest(t *testing.T) {
 	defaultCtx := testutil.NewContext(
 		testutil.ContextWithNamespace("default"),
@@ -71,10 +76,13 @@ func TestHttpApiChecksAdhocRequest(t *testing.T) {
 func TestChecksRouter(t *testing.T) {
 	// Setup the router
 	s := &mockstore.MockStore{}
-	router := ChecksRouter{handlers: handlers.Handlers{
-		Resource: &corev2.CheckConfig{},
-		Store:    s,
-	}}
+	router := ChecksRouter{
+		handlers: handlers.Handlers{
+			Resource: &corev2.CheckConfig{},
+			Store:    s,
+		},
+		deleter: actions.CheckDeleter{Store: s},
+	}
 	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
 	router.Mount(parentRouter)
 
@@ -128,6 +136,25 @@ func TestChecksRouterCustomRoutes(t *testing.T) {
 			},
 			wantStatusCode: http.StatusNoContent,
 		},
+		{
+			name:   "it lints a check",
+			method: http.MethodPost,
+			path:   "/namespaces/default/checks/validate",
+			body:   []byte(`{"metadata":{"name":"check1"},"command":"true","interval":10}`),
+			controllerFunc: func(c *mockCheckController) {
+				c.On("Lint", mock.Anything, mock.MatchedBy(func(check *corev2.CheckConfig) bool {
+					return check.Name == "check1" && check.Namespace == "default"
+				})).Return([]corev2.CheckConfigWarning{{Field: "handlers", Message: "no handlers"}}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "it refuses to lint a check of another namespace",
+			method:         http.MethodPost,
+			path:           "/namespaces/default/checks/validate",
+			body:           []byte(`{"metadata":{"name":"check1","namespace":"acme"}}`),
+			wantStatusCode: http.StatusBadRequest,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -2,7 +2,6 @@ package routers
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,6 +10,7 @@ import (
 
 	"github.com/coreos/etcd/clientv3"
 	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 )
 
 // ClusterController represents the controller needs of the ClusterRouter.
@@ -29,6 +29,12 @@ type ClusterController interface {
 
 	// ClusterID gets the sensu cluster id.
 	ClusterID(ctx context.Context) (string, error)
+
+	// Leadership gets the leadership of the cluster.
+	Leadership(ctx context.Context) (*corev2.ClusterLeadership, error)
+
+	// TransferLeadership hands the leadership of the cluster off to the member.
+	TransferLeadership(ctx context.Context, id uint64) error
 }
 
 // ClusterRouter handles requests for /cluster
@@ -50,6 +56,8 @@ func (r *ClusterRouter) Mount(parent *mux.Router) {
 	parent.HandleFunc("/cluster/members/{id}", r.memberRemove).Methods(http.MethodDelete)
 	parent.HandleFunc("/cluster/members/{id}", r.memberUpdate).Methods(http.MethodPut)
 	parent.HandleFunc("/cluster/id", r.clusterID).Methods(http.MethodGet)
+	parent.HandleFunc("/cluster/leader", r.leadership).Methods(http.MethodGet)
+	parent.HandleFunc("/cluster/leader/{id}", r.transferLeadership).Methods(http.MethodPut)
 }
 
 func parseID(req *http.Request) (uint64, error) {
@@ -75,7 +83,7 @@ func (r *ClusterRouter) list(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(resp)
+	RespondWith(w, req, resp)
 }
 
 func (r *ClusterRouter) memberAdd(w http.ResponseWriter, req *http.Request) {
@@ -89,7 +97,7 @@ func (r *ClusterRouter) memberAdd(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(resp)
+	RespondWith(w, req, resp)
 }
 
 func (r *ClusterRouter) memberRemove(w http.ResponseWriter, req *http.Request) {
@@ -103,7 +111,7 @@ func (r *ClusterRouter) memberRemove(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(resp)
+	RespondWith(w, req, resp)
 }
 
 func (r *ClusterRouter) memberUpdate(w http.ResponseWriter, req *http.Request) {
@@ -122,7 +130,7 @@ func (r *ClusterRouter) memberUpdate(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(resp)
+	RespondWith(w, req, resp)
 }
 
 func (r *ClusterRouter) clusterID(w http.ResponseWriter, req *http.Request) {
@@ -131,5 +139,27 @@ func (r *ClusterRouter) clusterID(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(resp)
+	RespondWith(w, req, resp)
+}
+
+func (r *ClusterRouter) leadership(w http.ResponseWriter, req *http.Request) {
+	resp, err := r.controller.Leadership(req.Context())
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	RespondWith(w, req, resp)
+}
+
+func (r *ClusterRouter) transferLeadership(w http.ResponseWriter, req *http.Request) {
+	id, err := parseID(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := r.controller.TransferLeadership(req.Context(), id); err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
 }
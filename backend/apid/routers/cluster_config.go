@@ -0,0 +1,35 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+)
+
+// ClusterConfigRouter handles requests for /cluster/config, which exposes the
+// effective configuration of the backend serving the request.
+type ClusterConfigRouter struct {
+	config *corev2.BackendConfig
+}
+
+// NewClusterConfigRouter instantiates a new router for the effective
+// configuration of the backend.
+func NewClusterConfigRouter(config *corev2.BackendConfig) *ClusterConfigRouter {
+	return &ClusterConfigRouter{config: config}
+}
+
+// Mount the ClusterConfigRouter to a parent Router
+func (r *ClusterConfigRouter) Mount(parent *mux.Router) {
+	parent.HandleFunc("/cluster/config", r.get).Methods(http.MethodGet)
+}
+
+func (r *ClusterConfigRouter) get(w http.ResponseWriter, req *http.Request) {
+	// The configuration is unknown to backends not started by sensu-backend
+	if r.config == nil {
+		WriteError(w, actions.NewErrorf(actions.NotFound))
+		return
+	}
+	RespondWith(w, req, r.config)
+}
@@ -0,0 +1,42 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterConfigRouter(t *testing.T) {
+	config := &corev2.BackendConfig{
+		ConfigFile: "/etc/sensu/backend.yml",
+		Settings: []*corev2.BackendSetting{
+			{Name: "agent-port", Value: float64(8081), Source: corev2.SettingSourceDefault},
+			{Name: "etcd-initial-cluster-token", Value: corev2.Redacted, Source: corev2.SettingSourceFile, Redacted: true},
+		},
+	}
+	router := mux.NewRouter()
+	NewClusterConfigRouter(config).Mount(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cluster/config", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var got corev2.BackendConfig
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, config, &got)
+}
+
+func TestClusterConfigRouterUnknown(t *testing.T) {
+	router := mux.NewRouter()
+	NewClusterConfigRouter(nil).Mount(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cluster/config", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,67 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+)
+
+// StoreMaintainer represents the store maintenance needs of the
+// ClusterMaintenanceRouter.
+type StoreMaintainer interface {
+	// Status returns the last maintenance, or nil if none was run.
+	Status(ctx context.Context) (*corev2.StoreMaintenance, error)
+	// Trigger runs a maintenance in the background. It returns an error if a
+	// maintenance is already running.
+	Trigger() error
+}
+
+// ClusterMaintenanceRouter handles requests for /cluster/maintenance, which
+// triggers and inspects the compaction and defragmentation of the store.
+type ClusterMaintenanceRouter struct {
+	maintainer StoreMaintainer
+}
+
+// NewClusterMaintenanceRouter instantiates a new router for the store
+// maintenance.
+func NewClusterMaintenanceRouter(maintainer StoreMaintainer) *ClusterMaintenanceRouter {
+	return &ClusterMaintenanceRouter{maintainer: maintainer}
+}
+
+// Mount the ClusterMaintenanceRouter to a parent Router
+func (r *ClusterMaintenanceRouter) Mount(parent *mux.Router) {
+	parent.HandleFunc("/cluster/maintenance", r.status).Methods(http.MethodGet)
+	parent.HandleFunc("/cluster/maintenance", r.trigger).Methods(http.MethodPost)
+}
+
+func (r *ClusterMaintenanceRouter) status(w http.ResponseWriter, req *http.Request) {
+	if r.maintainer == nil {
+		WriteError(w, actions.NewErrorf(actions.NotFound))
+		return
+	}
+	status, err := r.maintainer.Status(req.Context())
+	if err != nil {
+		WriteError(w, actions.NewError(actions.InternalErr, err))
+		return
+	}
+	if status == nil {
+		WriteError(w, actions.NewErrorf(actions.NotFound, "the store maintenance never ran"))
+		return
+	}
+	RespondWith(w, req, status)
+}
+
+func (r *ClusterMaintenanceRouter) trigger(w http.ResponseWriter, req *http.Request) {
+	if r.maintainer == nil {
+		WriteError(w, actions.NewErrorf(actions.NotFound))
+		return
+	}
+	if err := r.maintainer.Trigger(); err != nil {
+		WriteError(w, actions.NewError(actions.Conflict, err))
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,95 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStoreMaintainer struct {
+	mock.Mock
+}
+
+func (m *mockStoreMaintainer) Status(ctx context.Context) (*corev2.StoreMaintenance, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*corev2.StoreMaintenance), args.Error(1)
+}
+
+func (m *mockStoreMaintainer) Trigger() error {
+	return m.Called().Error(0)
+}
+
+func TestClusterMaintenanceRouterStatus(t *testing.T) {
+	status := &corev2.StoreMaintenance{
+		Trigger:         corev2.StoreMaintenanceTriggerSchedule,
+		Started:         1000,
+		Finished:        1010,
+		CompactRevision: 42,
+		Members: []*corev2.MemberMaintenance{
+			{MemberID: 1, Name: "backend1", IsLeader: true, DBSizeBefore: 2048, DBSizeAfter: 1024},
+		},
+	}
+	maintainer := &mockStoreMaintainer{}
+	maintainer.On("Status", mock.Anything).Return(status, nil)
+	router := mux.NewRouter()
+	NewClusterMaintenanceRouter(maintainer).Mount(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cluster/maintenance", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var got corev2.StoreMaintenance
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, status, &got)
+}
+
+func TestClusterMaintenanceRouterNeverRan(t *testing.T) {
+	maintainer := &mockStoreMaintainer{}
+	maintainer.On("Status", mock.Anything).Return((*corev2.StoreMaintenance)(nil), nil)
+	router := mux.NewRouter()
+	NewClusterMaintenanceRouter(maintainer).Mount(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cluster/maintenance", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestClusterMaintenanceRouterTrigger(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "triggered",
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:     "already running",
+			err:      errors.New("a store maintenance is already running"),
+			wantCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maintainer := &mockStoreMaintainer{}
+			maintainer.On("Trigger").Return(tt.err)
+			router := mux.NewRouter()
+			NewClusterMaintenanceRouter(maintainer).Mount(router)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/cluster/maintenance", nil))
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+)
+
+// StoreInfoSampler represents the store sampling needs of the
+// ClusterStoreInfoRouter.
+type StoreInfoSampler interface {
+	// StoreInfo returns the last sample of the store, or nil if the store
+	// was not sampled yet.
+	StoreInfo() *corev2.StoreInfo
+}
+
+// ClusterStoreInfoRouter handles requests for /cluster/storeinfo, which
+// reports the number of keys in the store and their size by resource type and
+// namespace.
+type ClusterStoreInfoRouter struct {
+	sampler StoreInfoSampler
+}
+
+// NewClusterStoreInfoRouter instantiates a new router for the store info.
+func NewClusterStoreInfoRouter(sampler StoreInfoSampler) *ClusterStoreInfoRouter {
+	return &ClusterStoreInfoRouter{sampler: sampler}
+}
+
+// Mount the ClusterStoreInfoRouter to a parent Router
+func (r *ClusterStoreInfoRouter) Mount(parent *mux.Router) {
+	parent.HandleFunc("/cluster/storeinfo", r.get).Methods(http.MethodGet)
+}
+
+func (r *ClusterStoreInfoRouter) get(w http.ResponseWriter, req *http.Request) {
+	if r.sampler == nil {
+		WriteError(w, actions.NewErrorf(actions.NotFound))
+		return
+	}
+	info := r.sampler.StoreInfo()
+	if info == nil {
+		WriteError(w, actions.NewErrorf(actions.NotFound, "the store was not sampled yet"))
+		return
+	}
+	RespondWith(w, req, info)
+}
@@ -0,0 +1,54 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStoreInfoSampler struct {
+	info *corev2.StoreInfo
+}
+
+func (m mockStoreInfoSampler) StoreInfo() *corev2.StoreInfo {
+	return m.info
+}
+
+func TestClusterStoreInfoRouter(t *testing.T) {
+	info := &corev2.StoreInfo{
+		Sampled:  1000,
+		Revision: 42,
+		DBSize:   4096,
+		Keys:     3,
+		Size:     120,
+		Resources: []*corev2.ResourceStoreInfo{
+			{Resource: "events", Namespace: "default", Keys: 2, Size: 100},
+			{Resource: "users", Keys: 1, Size: 20},
+		},
+	}
+	router := mux.NewRouter()
+	NewClusterStoreInfoRouter(mockStoreInfoSampler{info: info}).Mount(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cluster/storeinfo", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var got corev2.StoreInfo
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, info, &got)
+}
+
+func TestClusterStoreInfoRouterNotSampled(t *testing.T) {
+	router := mux.NewRouter()
+	NewClusterStoreInfoRouter(mockStoreInfoSampler{}).Mount(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cluster/storeinfo", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/coreos/etcd/clientv3"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -43,6 +45,16 @@ func (m *mockClusterController) ClusterID(ctx context.Context) (string, error) {
 	return args.Get(0).(string), args.Error(1)
 }
 
+func (m *mockClusterController) Leadership(ctx context.Context) (*corev2.ClusterLeadership, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*corev2.ClusterLeadership), args.Error(1)
+}
+
+func (m *mockClusterController) TransferLeadership(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func newClusterTest(t *testing.T) (*mockClusterController, *httptest.Server) {
 	controller := &mockClusterController{}
 	clusterRouter := NewClusterRouter(controller)
@@ -267,3 +279,69 @@ func TestGetClusterID(t *testing.T) {
 
 	controller.AssertCalled(t, "ClusterID", mock.Anything)
 }
+
+func TestClusterRouterLeadership(t *testing.T) {
+	ctrl, server := newClusterTest(t)
+	defer server.Close()
+
+	client := new(http.Client)
+	ctrl.On("Leadership", mock.Anything).Return(&corev2.ClusterLeadership{Leader: 1234}, nil)
+
+	req := newRequest(t, http.MethodGet, server.URL+"/cluster/leader", nil)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode >= 400 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("bad status: %d (%q)", resp.StatusCode, string(body))
+	}
+
+	ctrl.AssertCalled(t, "Leadership", mock.Anything)
+}
+
+func TestClusterRouterTransferLeadership(t *testing.T) {
+	ctrl, server := newClusterTest(t)
+	defer server.Close()
+
+	client := new(http.Client)
+	ctrl.On("TransferLeadership", mock.Anything, uint64(1234)).Return(nil)
+
+	endpoint := fmt.Sprintf("/cluster/leader/%x", 1234)
+	req := newRequest(t, http.MethodPut, server.URL+endpoint, nil)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("bad status (want 204): %d (%q)", resp.StatusCode, string(body))
+	}
+
+	ctrl.AssertCalled(t, "TransferLeadership", mock.Anything, uint64(1234))
+}
+
+func TestClusterRouterTransferLeadershipNotFound(t *testing.T) {
+	ctrl, server := newClusterTest(t)
+	defer server.Close()
+
+	client := new(http.Client)
+	ctrl.On("TransferLeadership", mock.Anything, uint64(1234)).Return(actions.NewErrorf(actions.NotFound))
+
+	endpoint := fmt.Sprintf("/cluster/leader/%x", 1234)
+	req := newRequest(t, http.MethodPut, server.URL+endpoint, nil)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusNotFound {
+		body, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("bad status (want 404): %d (%q)", resp.StatusCode, string(body))
+	}
+}
@@ -13,11 +13,12 @@ type ClusterRoleBindingsRouter struct {
 }
 
 // NewClusterRoleBindingsRouter instantiates a new router for ClusterRoleBindings.
-func NewClusterRoleBindingsRouter(store store.ResourceStore) *ClusterRoleBindingsRouter {
+func NewClusterRoleBindingsRouter(store store.Store) *ClusterRoleBindingsRouter {
 	return &ClusterRoleBindingsRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.ClusterRoleBinding{},
-			Store:    store,
+			Resource:      &corev2.ClusterRoleBinding{},
+			Store:         store,
+			ManagedFields: store,
 		},
 	}
 }
@@ -34,4 +35,5 @@ func (r *ClusterRoleBindingsRouter) Mount(parent *mux.Router) {
 	routes.List(r.handlers.ListResources, corev2.ClusterRoleBindingFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 }
@@ -13,11 +13,12 @@ type ClusterRolesRouter struct {
 }
 
 // NewClusterRolesRouter instantiates a new router for ClusterRoles.
-func NewClusterRolesRouter(store store.ResourceStore) *ClusterRolesRouter {
+func NewClusterRolesRouter(store store.Store) *ClusterRolesRouter {
 	return &ClusterRolesRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.ClusterRole{},
-			Store:    store,
+			Resource:      &corev2.ClusterRole{},
+			Store:         store,
+			ManagedFields: store,
 		},
 	}
 }
@@ -34,4 +35,5 @@ func (r *ClusterRolesRouter) Mount(parent *mux.Router) {
 	routes.List(r.handlers.ListResources, corev2.ClusterRoleFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 }
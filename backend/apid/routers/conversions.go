@@ -0,0 +1,86 @@
+package routers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/nagios"
+	"github.com/sensu/sensu-go/types"
+)
+
+// NagiosConversionRequest is the body of a conversion of Nagios object
+// definitions.
+type NagiosConversionRequest struct {
+	// Objects are the object definitions, e.g. the content of objects.cfg.
+	Objects string `json:"objects"`
+
+	// Resources are the resource macros, e.g. the content of resource.cfg.
+	Resources string `json:"resources,omitempty"`
+
+	// IntervalLength is the number of seconds of an interval unit.
+	IntervalLength int `json:"interval_length,omitempty"`
+}
+
+// ConversionResponse is the result of a conversion.
+type ConversionResponse struct {
+	// Resources are the wrapped Sensu resources.
+	Resources []types.Wrapper `json:"resources"`
+
+	// Report describes how each object was converted.
+	Report []nagios.ReportEntry `json:"report"`
+}
+
+// ConversionsRouter handles requests for /conversions
+type ConversionsRouter struct{}
+
+// NewConversionsRouter instantiates a new router for the conversions of
+// foreign configurations to Sensu resources.
+func NewConversionsRouter() *ConversionsRouter {
+	return &ConversionsRouter{}
+}
+
+// Mount the ConversionsRouter to a parent Router
+func (r *ConversionsRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:conversions}",
+	}
+
+	routes.Path("nagios", r.convertNagios).Methods(http.MethodPost)
+}
+
+func (r *ConversionsRouter) convertNagios(req *http.Request) (interface{}, error) {
+	body := NagiosConversionRequest{}
+	if err := UnmarshalBody(req, &body); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	objects, err := nagios.Parse(strings.NewReader(body.Objects), "objects")
+	if err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	macros, err := nagios.ParseResources(strings.NewReader(body.Resources), "resources")
+	if err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	result, err := nagios.Convert(objects, nagios.Options{
+		Namespace:      mux.Vars(req)["namespace"],
+		IntervalLength: body.IntervalLength,
+		Macros:         macros,
+	})
+	if err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	response := ConversionResponse{
+		Resources: make([]types.Wrapper, 0, len(result.Resources)),
+		Report:    result.Report,
+	}
+	for _, resource := range result.Resources {
+		response.Resources = append(response.Resources, types.WrapResource(resource))
+	}
+	return response, nil
+}
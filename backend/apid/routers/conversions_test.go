@@ -0,0 +1,74 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversionsRouterNagios(t *testing.T) {
+	router := mux.NewRouter()
+	NewConversionsRouter().Mount(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	body, err := json.Marshal(NagiosConversionRequest{
+		Objects: `
+define command {
+	command_name  check_ping
+	command_line  $USER1$/check_ping -H $HOSTADDRESS$
+}
+define host {
+	host_name  web1
+	address    10.0.0.1
+}
+define service {
+	host_name            web1
+	service_description  PING
+	check_command        check_ping
+	check_interval       2
+}
+define contact {
+	contact_name  nobody
+}
+`,
+		Resources:      "$USER1$=/usr/lib/nagios/plugins\n",
+		IntervalLength: 30,
+	})
+	require.NoError(t, err)
+
+	req := newRequest(t, http.MethodPost, server.URL+"/namespaces/acme/conversions/nagios", strings.NewReader(string(body)))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Resources []struct {
+			Type  string                 `json:"type"`
+			Value map[string]interface{} `json:"spec"`
+		} `json:"resources"`
+		Report []map[string]string `json:"report"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Resources, 2)
+	assert.Equal(t, "Entity", result.Resources[0].Type)
+	assert.Equal(t, "CheckConfig", result.Resources[1].Type)
+	assert.Equal(t, "/usr/lib/nagios/plugins/check_ping -H {{ .labels.address }}", result.Resources[1].Value["command"])
+	assert.Equal(t, float64(60), result.Resources[1].Value["interval"])
+	require.Len(t, result.Report, 1)
+	assert.Equal(t, "contact nobody", result.Report[0]["object"])
+
+	// The invalid object definitions are rejected
+	req = newRequest(t, http.MethodPost, server.URL+"/namespaces/acme/conversions/nagios", strings.NewReader(`{"objects":"define host {"}`))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
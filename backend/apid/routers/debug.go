@@ -0,0 +1,64 @@
+package routers
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// defaultProfileSeconds is the duration of CPU profiles and traces when it's
+// not specified by the client. It must remain below the write timeout of the
+// API server.
+const defaultProfileSeconds = "10"
+
+// DebugRouter handles requests for /debug, which expose the pprof profiles
+// and runtime statistics of the backend.
+type DebugRouter struct{}
+
+// NewDebugRouter instantiates a new router for the debug endpoints.
+func NewDebugRouter() *DebugRouter {
+	return &DebugRouter{}
+}
+
+// Mount the DebugRouter to a parent Router
+func (r *DebugRouter) Mount(parent *mux.Router) {
+	// the resource variable is used to authorize the requests
+	prefix := "/{resource:" + corev2.DebugResource + "}"
+
+	parent.HandleFunc(prefix+"/memstats", r.memStats).Methods(http.MethodGet)
+	parent.HandleFunc(prefix+"/pprof/cmdline", pprof.Cmdline).Methods(http.MethodGet)
+	parent.HandleFunc(prefix+"/pprof/profile", withDefaultSeconds(pprof.Profile)).Methods(http.MethodGet)
+	parent.HandleFunc(prefix+"/pprof/symbol", pprof.Symbol).Methods(http.MethodGet, http.MethodPost)
+	parent.HandleFunc(prefix+"/pprof/trace", withDefaultSeconds(pprof.Trace)).Methods(http.MethodGet)
+	// the index also serves the named profiles, such as goroutine and allocs
+	parent.PathPrefix(prefix + "/pprof/").HandlerFunc(pprof.Index).Methods(http.MethodGet)
+}
+
+// memStatsResponse is a snapshot of the memory allocator statistics
+type memStatsResponse struct {
+	Goroutines int              `json:"goroutines"`
+	MemStats   runtime.MemStats `json:"memstats"`
+}
+
+func (r *DebugRouter) memStats(w http.ResponseWriter, req *http.Request) {
+	response := memStatsResponse{Goroutines: runtime.NumGoroutine()}
+	runtime.ReadMemStats(&response.MemStats)
+	RespondWith(w, req, response)
+}
+
+// withDefaultSeconds sets the seconds parameter of profiling requests that
+// don't specify it, since the pprof default of 30 seconds exceeds the write
+// timeout of the API server.
+func withDefaultSeconds(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		if query.Get("seconds") == "" {
+			query.Set("seconds", defaultProfileSeconds)
+			req.URL.RawQuery = query.Encode()
+		}
+		handler(w, req)
+	}
+}
@@ -0,0 +1,58 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDebugTest() *httptest.Server {
+	router := mux.NewRouter()
+	NewDebugRouter().Mount(router)
+	return httptest.NewServer(router)
+}
+
+func TestDebugMemStats(t *testing.T) {
+	server := newDebugTest()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/debug/memstats")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var stats memStatsResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	assert.True(t, stats.Goroutines > 0)
+	assert.True(t, stats.MemStats.Alloc > 0)
+}
+
+func TestDebugPprof(t *testing.T) {
+	server := newDebugTest()
+	defer server.Close()
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/goroutine?debug=2", "/debug/pprof/allocs", "/debug/pprof/cmdline"} {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+}
+
+func TestWithDefaultSeconds(t *testing.T) {
+	var seconds string
+	handler := withDefaultSeconds(func(w http.ResponseWriter, req *http.Request) {
+		seconds = req.URL.Query().Get("seconds")
+	})
+
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/debug/pprof/profile", nil))
+	assert.Equal(t, defaultProfileSeconds, seconds)
+
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/debug/pprof/profile?seconds=2", nil))
+	assert.Equal(t, "2", seconds)
+}
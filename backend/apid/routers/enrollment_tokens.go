@@ -0,0 +1,40 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// EnrollmentTokensRouter handles requests for /enrollment-tokens
+type EnrollmentTokensRouter struct {
+	handlers handlers.Handlers
+}
+
+// NewEnrollmentTokensRouter instantiates new router for controlling enrollment token resources
+func NewEnrollmentTokensRouter(store store.Store) *EnrollmentTokensRouter {
+	return &EnrollmentTokensRouter{
+		handlers: handlers.Handlers{
+			Resource:      &corev2.EnrollmentToken{},
+			Store:         store,
+			ManagedFields: store,
+		},
+	}
+}
+
+// Mount the EnrollmentTokensRouter to a parent Router
+func (r *EnrollmentTokensRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:enrollment-tokens}",
+	}
+
+	routes.Del(r.handlers.DeleteResource)
+	routes.Get(r.handlers.GetResource)
+	routes.List(r.handlers.ListResources, corev2.EnrollmentTokenFields)
+	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:enrollment-tokens}", corev2.EnrollmentTokenFields)
+	routes.Post(r.handlers.CreateResource)
+	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
+}
@@ -1,10 +1,15 @@
 package routers
 
 import (
+	"net/http"
+	"net/url"
+
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/authorization/rbac"
 	"github.com/sensu/sensu-go/backend/store"
 )
 
@@ -13,17 +18,21 @@ type EntitiesRouter struct {
 	handlers   handlers.Handlers
 	store      store.Store
 	eventStore store.EventStore
+	authorizer authorization.Authorizer
 }
 
 // NewEntitiesRouter instantiates new router for controlling entities resources
 func NewEntitiesRouter(store store.Store, events store.EventStore) *EntitiesRouter {
 	return &EntitiesRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.Entity{},
-			Store:    store,
+			Resource:      &corev2.Entity{},
+			Store:         store,
+			LabelPolicy:   store,
+			ManagedFields: store,
 		},
 		store:      store,
 		eventStore: events,
+		authorizer: &rbac.Authorizer{Store: store},
 	}
 }
 
@@ -40,9 +49,53 @@ func (r *EntitiesRouter) Mount(parent *mux.Router) {
 	}
 
 	routes.Del(deleter.Delete)
+	// the subresource variable is used to authorize the requests
+	routes.Path("{id}/{subresource:rename}", r.rename).Methods(http.MethodPost)
+	routes.Path("{subresource:label}", r.label).Methods(http.MethodPost)
 	routes.Get(r.handlers.GetResource)
+	routes.Path("", r.listBySubscription).Methods(http.MethodGet).Queries(subscriptionParam, "{subscription}")
+	handleAction(parent, "/{resource:entities}", r.listBySubscription).Methods(http.MethodGet).Queries(subscriptionParam, "{subscription}")
 	routes.List(r.handlers.ListResources, corev2.EntityFields)
 	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:entities}", corev2.EntityFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
+}
+
+// subscriptionParam is the query parameter restricting entity lists to the
+// entities with the given subscription.
+const subscriptionParam = "subscription"
+
+// listBySubscription lists the entities with the subscription given in the
+// request query, using the entity subscription index of the store.
+func (r *EntitiesRouter) listBySubscription(req *http.Request) (interface{}, error) {
+	entities, err := r.store.GetEntitiesBySubscription(req.Context(), mux.Vars(req)[subscriptionParam])
+	if err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	return entities, nil
+}
+
+// rename renames the entity, or merges it into an existing entity, keeping its
+// events and silenced entries.
+func (r *EntitiesRouter) rename(req *http.Request) (interface{}, error) {
+	var rename corev2.EntityRename
+	if err := UnmarshalBody(req, &rename); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	name, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	return actions.EntityRenamer{Store: r.store, Authorizer: r.authorizer}.Rename(req.Context(), name, &rename)
+}
+
+// label sets and removes the labels of the entities matching the selector of
+// the labeling, at once.
+func (r *EntitiesRouter) label(req *http.Request) (interface{}, error) {
+	var labeling corev2.EntityLabeling
+	if err := UnmarshalBody(req, &labeling); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	return actions.EntityLabeler{Store: r.store, LabelPolicy: r.store}.Label(req.Context(), &labeling)
 }
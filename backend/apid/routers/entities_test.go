@@ -1,22 +1,54 @@
 package routers
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/testing/mockstore"
 	"github.com/stretchr/testify/mock"
 )
 
+// deniedResourceAuthorizer authorizes every request but the ones on the
+// denied resource.
+type deniedResourceAuthorizer struct {
+	denied string
+}
+
+func (a deniedResourceAuthorizer) Authorize(ctx context.Context, attrs *authorization.Attributes) (bool, error) {
+	return attrs.Resource != a.denied, nil
+}
+
+// withAuthorizationAttributes stores the authorization attributes of an admin
+// in the requests, as the authorization middlewares do.
+func withAuthorizationAttributes(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attrs := &authorization.Attributes{
+			APIGroup:   "core",
+			APIVersion: "v2",
+			Namespace:  "default",
+			User:       corev2.User{Username: "admin"},
+		}
+		next.ServeHTTP(w, r.WithContext(authorization.SetAttributes(r.Context(), attrs)))
+	})
+}
+
 func TestEntitiesRouter(t *testing.T) {
 	// Setup the router
 	s := &mockstore.MockStore{}
 	s.On("GetEventsByEntity", mock.Anything, "foo", mock.Anything).Return([]*corev2.Event{corev2.FixtureEvent("foo", "bar")}, nil)
 	s.On("DeleteEventByEntityCheck", mock.Anything, "foo", "bar").Return(nil)
 	s.On("DeleteEntityByName", mock.Anything, "foo").Return(nil)
+	s.On("GetLabelPolicy", mock.Anything).Return(nil, nil)
 	router := NewEntitiesRouter(s, s)
+	router.authorizer = deniedResourceAuthorizer{}
 	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	parentRouter.Use(withAuthorizationAttributes)
 	router.Mount(parentRouter)
 
 	empty := &corev2.Entity{}
@@ -32,7 +64,143 @@ func TestEntitiesRouter(t *testing.T) {
 	// controller.
 	tests = append(tests, deleteResourceInvalidPathTestCase(fixture))
 	tests = append(tests, deleteResourceSuccessTestCase(fixture))
+	tests = append(tests, []routerTestCase{
+		{
+			name:   "it lists the entities of a subscription",
+			method: http.MethodGet,
+			path:   "/api/core/v2/namespaces/default/entities?subscription=linux",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetEntitiesBySubscription", mock.Anything, "linux").
+					Return([]*corev2.Entity{fixture}, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it lists the entities of a subscription in all namespaces",
+			method: http.MethodGet,
+			path:   "/api/core/v2/entities?subscription=linux",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetEntitiesBySubscription", mock.Anything, "linux").
+					Return([]*corev2.Entity{fixture}, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it returns 500 if the store fails to list the entities of a subscription",
+			method: http.MethodGet,
+			path:   "/api/core/v2/namespaces/default/entities?subscription=linux",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetEntitiesBySubscription", mock.Anything, "linux").
+					Return([]*corev2.Entity{}, errors.New("error")).Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:   "it renames an entity",
+			method: http.MethodPost,
+			path:   "/api/core/v2/namespaces/default/entities/foo/rename",
+			body:   []byte(`{"name":"bar"}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("RenameEntity", mock.Anything, "foo", "bar", false).
+					Return(corev2.FixtureEntity("bar"), nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it merges an entity",
+			method: http.MethodPost,
+			path:   "/api/core/v2/namespaces/default/entities/foo/rename",
+			body:   []byte(`{"name":"bar","merge":true}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("RenameEntity", mock.Anything, "foo", "bar", true).
+					Return(corev2.FixtureEntity("bar"), nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "it returns 400 if the new name of the entity is missing",
+			method:         http.MethodPost,
+			path:           "/api/core/v2/namespaces/default/entities/foo/rename",
+			body:           []byte(`{}`),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "it returns 404 if the entity to rename does not exist",
+			method: http.MethodPost,
+			path:   "/api/core/v2/namespaces/default/entities/foo/rename",
+			body:   []byte(`{"name":"bar"}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("RenameEntity", mock.Anything, "foo", "bar", false).
+					Return((*corev2.Entity)(nil), &store.ErrNotFound{}).Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:   "it returns 409 if the entity of the new name exists without merge",
+			method: http.MethodPost,
+			path:   "/api/core/v2/namespaces/default/entities/foo/rename",
+			body:   []byte(`{"name":"bar"}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("RenameEntity", mock.Anything, "foo", "bar", false).
+					Return((*corev2.Entity)(nil), &store.ErrAlreadyExists{}).Once()
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:   "it labels the entities",
+			method: http.MethodPost,
+			path:   "/api/core/v2/namespaces/default/entities/label",
+			body:   []byte(`{"selector":{"labels":{"team":"web"}},"set":{"team":"frontend"}}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetLabelPolicy", mock.Anything).
+					Return((*corev2.LabelPolicy)(nil), nil).Once()
+				s.On("LabelEntities", mock.Anything, mock.Anything, (*corev2.LabelPolicy)(nil)).
+					Return([]string{"web-1", "web-2"}, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "it returns 400 if the labeling has no selector",
+			method:         http.MethodPost,
+			path:           "/api/core/v2/namespaces/default/entities/label",
+			body:           []byte(`{"set":{"team":"frontend"}}`),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "it returns 400 if the labeling violates the label policy",
+			method: http.MethodPost,
+			path:   "/api/core/v2/namespaces/default/entities/label",
+			body:   []byte(`{"selector":{"names":["web-1"]},"remove":["team"]}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetLabelPolicy", mock.Anything).
+					Return((*corev2.LabelPolicy)(nil), nil).Once()
+				s.On("LabelEntities", mock.Anything, mock.Anything, (*corev2.LabelPolicy)(nil)).
+					Return([]string(nil), &store.ErrNotValid{Err: errors.New("violation")}).Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}...)
 	for _, tt := range tests {
 		run(t, tt, parentRouter, s)
 	}
 }
+
+func TestEntitiesRouterRenameAuthorization(t *testing.T) {
+	for _, resource := range []string{"entities", "events", "silenced"} {
+		s := &mockstore.MockStore{}
+		router := NewEntitiesRouter(s, s)
+		router.authorizer = deniedResourceAuthorizer{denied: resource}
+		parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+		parentRouter.Use(withAuthorizationAttributes)
+		router.Mount(parentRouter)
+
+		// The entity is not renamed, RenameEntity is not mocked
+		run(t, routerTestCase{
+			name:           "it returns 403 if the user can not write the " + resource,
+			method:         http.MethodPost,
+			path:           "/api/core/v2/namespaces/default/entities/foo/rename",
+			body:           []byte(`{"name":"bar","merge":true}`),
+			wantStatusCode: http.StatusForbidden,
+		}, parentRouter, s)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"time"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
@@ -24,13 +25,15 @@ type eventController interface {
 	CreateOrReplace(ctx context.Context, check *corev2.Event) error
 	Delete(ctx context.Context, entity, check string) error
 	Get(ctx context.Context, entity, check string) (*corev2.Event, error)
+	GetPipeline(ctx context.Context, entity, check string) (*corev2.EventPipeline, error)
 	List(ctx context.Context, pred *store.SelectionPredicate) ([]corev2.Resource, error)
+	Snooze(ctx context.Context, entity, check string, duration time.Duration) (*corev2.Silenced, error)
 }
 
 // NewEventsRouter instantiates new events controller
-func NewEventsRouter(store store.EventStore, bus messaging.MessageBus) *EventsRouter {
+func NewEventsRouter(store store.EventStore, silenced store.SilencedStore, bus messaging.MessageBus) *EventsRouter {
 	return &EventsRouter{
-		controller: actions.NewEventController(store, bus),
+		controller: actions.NewEventController(store, silenced, bus),
 	}
 }
 
@@ -47,6 +50,8 @@ func (r *EventsRouter) Mount(parent *mux.Router) {
 	routes.Path("{entity}/{check}", r.get).Methods(http.MethodGet)
 	routes.Path("{entity}/{check}", r.delete).Methods(http.MethodDelete)
 	routes.Path("{entity}/{check}", r.createOrReplace).Methods(http.MethodPost, http.MethodPut)
+	routes.Path("{entity}/{check}/pipeline", r.getPipeline).Methods(http.MethodGet)
+	routes.Path("{entity}/{check}/{subresource:snooze}", r.snooze).Methods(http.MethodPost)
 
 	// Additionaly allow a subcollection to be specified when listing events,
 	// which correspond to the entity name here
@@ -62,6 +67,24 @@ func (r *EventsRouter) get(req *http.Request) (interface{}, error) {
 	return record, err
 }
 
+func (r *EventsRouter) getPipeline(req *http.Request) (interface{}, error) {
+	params := actions.QueryParams(mux.Vars(req))
+	entity := url.PathEscape(params["entity"])
+	check := url.PathEscape(params["check"])
+	return r.controller.GetPipeline(req.Context(), entity, check)
+}
+
+func (r *EventsRouter) snooze(req *http.Request) (interface{}, error) {
+	params := actions.QueryParams(mux.Vars(req))
+	entity := url.PathEscape(params["entity"])
+	check := url.PathEscape(params["check"])
+	duration, err := time.ParseDuration(req.URL.Query().Get("duration"))
+	if err != nil {
+		return nil, actions.NewErrorf(actions.InvalidArgument, "invalid snooze duration: %s", err)
+	}
+	return r.controller.Snooze(req.Context(), entity, check, duration)
+}
+
 func (r *EventsRouter) delete(req *http.Request) (interface{}, error) {
 	params := actions.QueryParams(mux.Vars(req))
 	entity := url.PathEscape(params["entity"])
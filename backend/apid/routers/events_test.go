@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
@@ -32,11 +33,21 @@ func (m *mockEventController) Get(ctx context.Context, entity, check string) (*c
 	return args.Get(0).(*corev2.Event), args.Error(1)
 }
 
+func (m *mockEventController) GetPipeline(ctx context.Context, entity, check string) (*corev2.EventPipeline, error) {
+	args := m.Called(ctx, entity, check)
+	return args.Get(0).(*corev2.EventPipeline), args.Error(1)
+}
+
 func (m *mockEventController) List(ctx context.Context, pred *store.SelectionPredicate) ([]corev2.Resource, error) {
 	args := m.Called(ctx, pred)
 	return args.Get(0).([]corev2.Resource), args.Error(1)
 }
 
+func (m *mockEventController) Snooze(ctx context.Context, entity, check string, duration time.Duration) (*corev2.Silenced, error) {
+	args := m.Called(ctx, entity, check, duration)
+	return args.Get(0).(*corev2.Silenced), args.Error(1)
+}
+
 func TestEventsRouter(t *testing.T) {
 	type controllerFunc func(*mockEventController)
 
@@ -85,6 +96,45 @@ func TestEventsRouter(t *testing.T) {
 			},
 			wantStatusCode: http.StatusOK,
 		},
+		{
+			name:   "it returns 404 if an event pipeline record is not found",
+			method: http.MethodGet,
+			path:   fixture.URIPath() + "/pipeline",
+			controllerFunc: func(c *mockEventController) {
+				c.On("GetPipeline", mock.Anything, "foo", "check-cpu").
+					Return((*corev2.EventPipeline)(nil), actions.NewErrorf(actions.NotFound)).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:   "it returns 200 if an event pipeline record is found",
+			method: http.MethodGet,
+			path:   fixture.URIPath() + "/pipeline",
+			controllerFunc: func(c *mockEventController) {
+				c.On("GetPipeline", mock.Anything, "foo", "check-cpu").
+					Return(&corev2.EventPipeline{Entity: "foo", Check: "check-cpu"}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "it returns 400 if the snooze duration is invalid",
+			method:         http.MethodPost,
+			path:           fixture.URIPath() + "/snooze?duration=forever",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "it returns 200 if the event is snoozed",
+			method: http.MethodPost,
+			path:   fixture.URIPath() + "/snooze?duration=2h",
+			controllerFunc: func(c *mockEventController) {
+				c.On("Snooze", mock.Anything, "foo", "check-cpu", 2*time.Hour).
+					Return(corev2.FixtureSilenced("entity:foo:check-cpu"), nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
 		{
 			name:   "it returns 500 if the store encounters an error while listing events",
 			method: http.MethodGet,
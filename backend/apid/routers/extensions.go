@@ -13,11 +13,12 @@ type ExtensionsRouter struct {
 }
 
 // NewExtensionsRouter creates a new router for controlling extension resources
-func NewExtensionsRouter(store store.ResourceStore) *ExtensionsRouter {
+func NewExtensionsRouter(store store.Store) *ExtensionsRouter {
 	return &ExtensionsRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.Extension{},
-			Store:    store,
+			Resource:      &corev2.Extension{},
+			Store:         store,
+			ManagedFields: store,
 		},
 	}
 }
@@ -35,4 +36,5 @@ func (r *ExtensionsRouter) Mount(parent *mux.Router) {
 	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:extensions}", corev2.ExtensionFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 }
@@ -13,11 +13,12 @@ type EventFiltersRouter struct {
 }
 
 // NewEventFiltersRouter creates a new EventFiltersRouter.
-func NewEventFiltersRouter(store store.ResourceStore) *EventFiltersRouter {
+func NewEventFiltersRouter(store store.Store) *EventFiltersRouter {
 	return &EventFiltersRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.EventFilter{},
-			Store:    store,
+			Resource:      &corev2.EventFilter{},
+			Store:         store,
+			ManagedFields: store,
 		},
 	}
 }
@@ -35,4 +36,5 @@ func (r *EventFiltersRouter) Mount(parent *mux.Router) {
 	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:filters}", corev2.EventFilterFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 }
@@ -0,0 +1,382 @@
+package routers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	// The prefixes of the targets of the Grafana datasource:
+	//   status:<entity>/<check> is the status history of a check
+	//   metric:<name> are the points of a metric, by entity, check and tags
+	//   count:status is the number of events by status
+	//   count:label:<label> is the number of events by entity label value
+	grafanaTargetStatus      = "status:"
+	grafanaTargetMetric      = "metric:"
+	grafanaTargetCountStatus = "count:status"
+	grafanaTargetCountLabel  = "count:label:"
+
+	// grafanaTypeTable is the type of the targets queried as tables, rather
+	// than time series.
+	grafanaTypeTable = "table"
+)
+
+// grafanaSearch is the request of the metrics of a datasource.
+type grafanaSearch struct {
+	Target string `json:"target"`
+}
+
+// grafanaQuery is the query of the targets of a panel.
+type grafanaQuery struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	MaxDataPoints int             `json:"maxDataPoints"`
+	Targets       []grafanaTarget `json:"targets"`
+}
+
+type grafanaTarget struct {
+	Target string `json:"target"`
+	RefID  string `json:"refId"`
+	Type   string `json:"type"`
+}
+
+// grafanaTimeserie is a time series, whose data points are the value and the
+// time in milliseconds since the Epoch.
+type grafanaTimeserie struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type grafanaTable struct {
+	Type    string          `json:"type"`
+	Columns []grafanaColumn `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+type grafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// GrafanaRouter handles requests for the Grafana datasource API, which
+// queries the check statuses and metrics of the events of a namespace, so
+// Grafana panels can show them without exporting them to a time series
+// database. Only the status history and the metrics of the last event of each
+// entity and check are stored, so the series do not go further back.
+type GrafanaRouter struct {
+	store store.EventStore
+}
+
+// NewGrafanaRouter instantiates a new router for the Grafana datasource.
+func NewGrafanaRouter(store store.EventStore) *GrafanaRouter {
+	return &GrafanaRouter{store: store}
+}
+
+// Mount the GrafanaRouter to a parent Router
+func (r *GrafanaRouter) Mount(parent *mux.Router) {
+	// the datasource is tested with the root path
+	parent.HandleFunc("/", compatibleHandler(r.test)).Methods(http.MethodGet)
+	parent.HandleFunc("/search", compatibleHandler(r.search)).Methods(http.MethodPost)
+	parent.HandleFunc("/query", compatibleHandler(r.query)).Methods(http.MethodPost)
+}
+
+func (r *GrafanaRouter) test(req *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+// events returns the events of the namespace the request is authorized for.
+func (r *GrafanaRouter) events(req *http.Request) ([]*corev2.Event, error) {
+	events, err := r.store.GetEvents(req.Context(), &store.SelectionPredicate{})
+	if err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	restricted, err := restrictToResourceNames(req, events)
+	if err != nil {
+		return nil, err
+	}
+	return restricted.([]*corev2.Event), nil
+}
+
+func (r *GrafanaRouter) search(req *http.Request) (interface{}, error) {
+	var search grafanaSearch
+	if err := UnmarshalBody(req, &search); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	events, err := r.events(req)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []string{}
+	for _, target := range grafanaTargets(events) {
+		if strings.Contains(target, search.Target) {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+func (r *GrafanaRouter) query(req *http.Request) (interface{}, error) {
+	var query grafanaQuery
+	if err := UnmarshalBody(req, &query); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	to := query.Range.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	events, err := r.events(req)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []interface{}{}
+	for _, target := range query.Targets {
+		series, err := grafanaSeries(events, target.Target, query.Range.From, to)
+		if err != nil {
+			return nil, actions.NewError(actions.InvalidArgument, err)
+		}
+		if target.Type == grafanaTypeTable {
+			results = append(results, grafanaTableOf(target.Target, series))
+			continue
+		}
+		for _, serie := range series {
+			if query.MaxDataPoints > 0 && len(serie.Datapoints) > query.MaxDataPoints {
+				serie.Datapoints = serie.Datapoints[len(serie.Datapoints)-query.MaxDataPoints:]
+			}
+			results = append(results, serie)
+		}
+	}
+	return results, nil
+}
+
+// grafanaTargets returns the targets of the events, sorted.
+func grafanaTargets(events []*corev2.Event) []string {
+	set := map[string]struct{}{grafanaTargetCountStatus: {}}
+	for _, event := range events {
+		if event.HasCheck() {
+			set[grafanaTargetStatus+eventSeriesName(event)] = struct{}{}
+		}
+		if event.HasMetrics() {
+			for _, point := range event.Metrics.Points {
+				set[grafanaTargetMetric+point.Name] = struct{}{}
+			}
+		}
+		if event.Entity != nil {
+			for label := range event.Entity.Labels {
+				set[grafanaTargetCountLabel+label] = struct{}{}
+			}
+		}
+	}
+
+	targets := make([]string, 0, len(set))
+	for target := range set {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// grafanaSeries returns the time series of the target between from and to.
+// The counts are the ones of the current events, at the end of the range.
+func grafanaSeries(events []*corev2.Event, target string, from, to time.Time) ([]grafanaTimeserie, error) {
+	inRange := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(to)
+	}
+
+	switch {
+	case strings.HasPrefix(target, grafanaTargetStatus):
+		name := strings.TrimPrefix(target, grafanaTargetStatus)
+		serie := grafanaTimeserie{Target: name, Datapoints: [][2]float64{}}
+		for _, event := range events {
+			if !event.HasCheck() || eventSeriesName(event) != name {
+				continue
+			}
+			for _, history := range event.Check.History {
+				executed := time.Unix(history.Executed, 0)
+				if inRange(executed) {
+					serie.Datapoints = append(serie.Datapoints, grafanaDatapoint(float64(history.Status), executed))
+				}
+			}
+		}
+		sortDatapoints(serie.Datapoints)
+		return []grafanaTimeserie{serie}, nil
+
+	case strings.HasPrefix(target, grafanaTargetMetric):
+		name := strings.TrimPrefix(target, grafanaTargetMetric)
+		series := map[string]*grafanaTimeserie{}
+		for _, event := range events {
+			if !event.HasMetrics() {
+				continue
+			}
+			for _, point := range event.Metrics.Points {
+				timestamp := metricPointTime(point.Timestamp)
+				if point.Name != name || !inRange(timestamp) {
+					continue
+				}
+				key := metricSeriesName(event, point)
+				if series[key] == nil {
+					series[key] = &grafanaTimeserie{Target: key}
+				}
+				series[key].Datapoints = append(series[key].Datapoints, grafanaDatapoint(point.Value, timestamp))
+			}
+		}
+		result := make([]grafanaTimeserie, 0, len(series))
+		for _, serie := range series {
+			sortDatapoints(serie.Datapoints)
+			result = append(result, *serie)
+		}
+		sort.Slice(result, func(i, j int) bool { return result[i].Target < result[j].Target })
+		return result, nil
+
+	case target == grafanaTargetCountStatus:
+		return grafanaCounts(events, to, func(event *corev2.Event) (string, bool) {
+			if !event.HasCheck() {
+				return "", false
+			}
+			return checkStatusName(event.Check.Status), true
+		}), nil
+
+	case strings.HasPrefix(target, grafanaTargetCountLabel):
+		label := strings.TrimPrefix(target, grafanaTargetCountLabel)
+		return grafanaCounts(events, to, func(event *corev2.Event) (string, bool) {
+			if event.Entity == nil {
+				return "", false
+			}
+			value, ok := event.Entity.Labels[label]
+			return value, ok
+		}), nil
+	}
+
+	return nil, fmt.Errorf("invalid target %q, must be one of count:status, count:label:<label>, status:<entity>/<check> or metric:<name>", target)
+}
+
+// grafanaCounts counts the events by the key of each, with a single data
+// point at the given time.
+func grafanaCounts(events []*corev2.Event, at time.Time, key func(*corev2.Event) (string, bool)) []grafanaTimeserie {
+	counts := map[string]int{}
+	for _, event := range events {
+		if k, ok := key(event); ok {
+			counts[k]++
+		}
+	}
+	series := make([]grafanaTimeserie, 0, len(counts))
+	for k, count := range counts {
+		series = append(series, grafanaTimeserie{
+			Target:     k,
+			Datapoints: [][2]float64{grafanaDatapoint(float64(count), at)},
+		})
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].Target < series[j].Target })
+	return series
+}
+
+// grafanaTableOf returns the series as a table. The counts are tables of the
+// last value of each series, and the other targets tables of their data
+// points.
+func grafanaTableOf(target string, series []grafanaTimeserie) grafanaTable {
+	if strings.HasPrefix(target, "count:") {
+		table := grafanaTable{
+			Type:    grafanaTypeTable,
+			Columns: []grafanaColumn{{Text: strings.TrimPrefix(target, grafanaTargetCountLabel), Type: "string"}, {Text: "Count", Type: "number"}},
+			Rows:    [][]interface{}{},
+		}
+		if target == grafanaTargetCountStatus {
+			table.Columns[0].Text = "Status"
+		}
+		for _, serie := range series {
+			table.Rows = append(table.Rows, []interface{}{serie.Target, serie.Datapoints[len(serie.Datapoints)-1][0]})
+		}
+		return table
+	}
+
+	table := grafanaTable{
+		Type:    grafanaTypeTable,
+		Columns: []grafanaColumn{{Text: "Time", Type: "time"}, {Text: "Series", Type: "string"}, {Text: "Value", Type: "number"}},
+		Rows:    [][]interface{}{},
+	}
+	for _, serie := range series {
+		for _, datapoint := range serie.Datapoints {
+			table.Rows = append(table.Rows, []interface{}{int64(datapoint[1]), serie.Target, datapoint[0]})
+		}
+	}
+	return table
+}
+
+func grafanaDatapoint(value float64, t time.Time) [2]float64 {
+	return [2]float64{value, float64(t.UnixNano() / int64(time.Millisecond))}
+}
+
+func sortDatapoints(datapoints [][2]float64) {
+	sort.Slice(datapoints, func(i, j int) bool { return datapoints[i][1] < datapoints[j][1] })
+}
+
+// eventSeriesName identifies the entity and check of an event.
+func eventSeriesName(event *corev2.Event) string {
+	var entity string
+	if event.Entity != nil {
+		entity = event.Entity.Name
+	}
+	return entity + "/" + event.Check.Name
+}
+
+// metricSeriesName identifies the series of a metric point, with its entity,
+// check and tags.
+func metricSeriesName(event *corev2.Event, point *corev2.MetricPoint) string {
+	var name string
+	if event.Entity != nil {
+		name = event.Entity.Name
+	}
+	if event.HasCheck() {
+		name += "/" + event.Check.Name
+	}
+	name += " " + point.Name
+
+	if len(point.Tags) > 0 {
+		tags := make([]string, 0, len(point.Tags))
+		for _, tag := range point.Tags {
+			tags = append(tags, tag.Name+"="+tag.Value)
+		}
+		sort.Strings(tags)
+		name += "{" + strings.Join(tags, ",") + "}"
+	}
+	return name
+}
+
+// metricPointTime returns the time of a metric point, whose timestamp is in
+// seconds, milliseconds, microseconds or nanoseconds since the Epoch,
+// depending on the extractor of the metrics.
+func metricPointTime(timestamp int64) time.Time {
+	switch {
+	case timestamp < 1e11:
+		return time.Unix(timestamp, 0)
+	case timestamp < 1e14:
+		return time.Unix(0, timestamp*int64(time.Millisecond))
+	case timestamp < 1e17:
+		return time.Unix(0, timestamp*int64(time.Microsecond))
+	}
+	return time.Unix(0, timestamp)
+}
+
+// checkStatusName returns the name of a check status.
+func checkStatusName(status uint32) string {
+	switch status {
+	case 0:
+		return "passing"
+	case 1:
+		return "warning"
+	case 2:
+		return "critical"
+	}
+	return "unknown"
+}
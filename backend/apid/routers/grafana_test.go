@@ -0,0 +1,164 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func fixtureGrafanaEvents(now time.Time) []*corev2.Event {
+	disk := corev2.FixtureEvent("server1", "disk")
+	disk.Entity.Labels = map[string]string{"region": "us-west"}
+	disk.Check.Status = 2
+	disk.Check.History = []corev2.CheckHistory{
+		{Status: 0, Executed: now.Add(-2 * time.Minute).Unix()},
+		{Status: 2, Executed: now.Add(-time.Minute).Unix()},
+	}
+
+	cpu := corev2.FixtureEvent("server2", "cpu")
+	cpu.Entity.Labels = map[string]string{"region": "us-east"}
+	cpu.Metrics = &corev2.Metrics{Points: []*corev2.MetricPoint{
+		{Name: "cpu.idle", Value: 90, Timestamp: now.Add(-time.Minute).Unix()},
+		{Name: "cpu.idle", Value: 80, Timestamp: now.Add(-2 * time.Minute).UnixNano(), Tags: []*corev2.MetricTag{{Name: "cpu", Value: "0"}}},
+	}}
+
+	return []*corev2.Event{disk, cpu}
+}
+
+func TestGrafanaRouter(t *testing.T) {
+	s := &mockstore.MockStore{}
+	router := NewGrafanaRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/grafana/{namespace}").Subrouter()
+	router.Mount(parentRouter)
+
+	events := fixtureGrafanaEvents(time.Now())
+
+	tests := []routerTestCase{
+		{
+			name:           "it tests the datasource",
+			method:         http.MethodGet,
+			path:           "/grafana/default/",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it searches the targets",
+			method: http.MethodPost,
+			path:   "/grafana/default/search",
+			body:   []byte(`{"target":"status"}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetEvents", mock.Anything, mock.Anything).
+					Return(events, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it returns 500 if the store fails",
+			method: http.MethodPost,
+			path:   "/grafana/default/search",
+			body:   []byte(`{"target":""}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetEvents", mock.Anything, mock.Anything).
+					Return([]*corev2.Event(nil), errors.New("error")).Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:   "it queries the targets",
+			method: http.MethodPost,
+			path:   "/grafana/default/query",
+			body:   []byte(`{"range":{"from":"2000-01-01T00:00:00Z","to":"2099-01-01T00:00:00Z"},"targets":[{"target":"status:server1/disk","type":"timeserie"},{"target":"count:label:region","type":"table"}]}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetEvents", mock.Anything, mock.Anything).
+					Return(events, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it rejects the invalid targets",
+			method: http.MethodPost,
+			path:   "/grafana/default/query",
+			body:   []byte(`{"targets":[{"target":"foo"}]}`),
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetEvents", mock.Anything, mock.Anything).
+					Return(events, nil).Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
+
+func TestGrafanaTargets(t *testing.T) {
+	events := fixtureGrafanaEvents(time.Now())
+	assert.Equal(t, []string{
+		"count:label:region",
+		"count:status",
+		"metric:cpu.idle",
+		"status:server1/disk",
+		"status:server2/cpu",
+	}, grafanaTargets(events))
+}
+
+func TestGrafanaSeries(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	events := fixtureGrafanaEvents(now)
+	ms := func(t time.Time) float64 {
+		return float64(t.UnixNano() / int64(time.Millisecond))
+	}
+
+	series, err := grafanaSeries(events, "status:server1/disk", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, []grafanaTimeserie{{
+		Target: "server1/disk",
+		Datapoints: [][2]float64{
+			{0, ms(now.Add(-2 * time.Minute))},
+			{2, ms(now.Add(-time.Minute))},
+		},
+	}}, series)
+
+	// the range excludes the older status
+	series, err = grafanaSeries(events, "status:server1/disk", now.Add(-90*time.Second), now)
+	require.NoError(t, err)
+	assert.Len(t, series[0].Datapoints, 1)
+
+	series, err = grafanaSeries(events, "metric:cpu.idle", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, []grafanaTimeserie{
+		{Target: "server2/cpu cpu.idle", Datapoints: [][2]float64{{90, ms(now.Add(-time.Minute))}}},
+		{Target: "server2/cpu cpu.idle{cpu=0}", Datapoints: [][2]float64{{80, ms(now.Add(-2 * time.Minute))}}},
+	}, series)
+
+	series, err = grafanaSeries(events, "count:status", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, []grafanaTimeserie{
+		{Target: "critical", Datapoints: [][2]float64{{1, ms(now)}}},
+		{Target: "passing", Datapoints: [][2]float64{{1, ms(now)}}},
+	}, series)
+
+	series, err = grafanaSeries(events, "count:label:region", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	table := grafanaTableOf("count:label:region", series)
+	assert.Equal(t, []grafanaColumn{{Text: "region", Type: "string"}, {Text: "Count", Type: "number"}}, table.Columns)
+	assert.Equal(t, [][]interface{}{{"us-east", float64(1)}, {"us-west", float64(1)}}, table.Rows)
+
+	_, err = grafanaSeries(events, "foo", now.Add(-time.Hour), now)
+	assert.Error(t, err)
+}
+
+func TestMetricPointTime(t *testing.T) {
+	now := time.Unix(1600000000, 123000000)
+	assert.Equal(t, time.Unix(now.Unix(), 0), metricPointTime(now.Unix()))
+	assert.Equal(t, now, metricPointTime(now.UnixNano()/int64(time.Millisecond)))
+	assert.Equal(t, now, metricPointTime(now.UnixNano()/int64(time.Microsecond)))
+	assert.Equal(t, now, metricPointTime(now.UnixNano()))
+}
@@ -1,8 +1,12 @@
 package routers
 
 import (
+	"net/http"
+	"net/url"
+
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
 	"github.com/sensu/sensu-go/backend/store"
 )
@@ -10,15 +14,18 @@ import (
 // HandlersRouter handles requests for /handlers
 type HandlersRouter struct {
 	handlers handlers.Handlers
+	store    store.HandlerStore
 }
 
 // NewHandlersRouter instantiates new router for controlling handler resources
-func NewHandlersRouter(store store.ResourceStore) *HandlersRouter {
+func NewHandlersRouter(store store.Store) *HandlersRouter {
 	return &HandlersRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.Handler{},
-			Store:    store,
+			Resource:      &corev2.Handler{},
+			Store:         store,
+			ManagedFields: store,
 		},
+		store: store,
 	}
 }
 
@@ -29,9 +36,36 @@ func (r *HandlersRouter) Mount(parent *mux.Router) {
 		PathPrefix: "/namespaces/{namespace}/{resource:handlers}",
 	}
 	routes.Del(r.handlers.DeleteResource)
+	// the subresource variable is used to authorize the requests
+	routes.Path("{id}/{subresource:outputs}", r.outputs).Methods(http.MethodGet)
 	routes.Get(r.handlers.GetResource)
 	routes.List(r.handlers.ListResources, corev2.HandlerFields)
 	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:handlers}", corev2.HandlerFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
+}
+
+// outputs returns the captured outputs of the last executions of the handler,
+// most recent first.
+func (r *HandlersRouter) outputs(req *http.Request) (interface{}, error) {
+	name, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	handler, err := r.store.GetHandlerByName(req.Context(), name)
+	if err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	if handler == nil {
+		return nil, actions.NewErrorf(actions.NotFound)
+	}
+	outputs, err := r.store.GetHandlerOutputs(req.Context(), name)
+	if err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	if outputs == nil {
+		outputs = []*corev2.HandlerOutput{}
+	}
+	return outputs, nil
 }
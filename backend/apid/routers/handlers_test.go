@@ -1,11 +1,14 @@
 package routers
 
 import (
+	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandlersRouter(t *testing.T) {
@@ -24,6 +27,42 @@ func TestHandlersRouter(t *testing.T) {
 	tests = append(tests, createTestCases(empty)...)
 	tests = append(tests, updateTestCases(fixture)...)
 	tests = append(tests, deleteTestCases(fixture)...)
+	tests = append(tests, []routerTestCase{
+		{
+			name:   "it returns the outputs of a handler",
+			method: http.MethodGet,
+			path:   "/api/core/v2/namespaces/default/handlers/foo/outputs",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetHandlerByName", mock.Anything, "foo").
+					Return(fixture, nil).Once()
+				s.On("GetHandlerOutputs", mock.Anything, "foo").
+					Return([]*corev2.HandlerOutput{{Namespace: "default", Entity: "entity"}}, nil).Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it returns 404 if the handler does not exist",
+			method: http.MethodGet,
+			path:   "/api/core/v2/namespaces/default/handlers/foo/outputs",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetHandlerByName", mock.Anything, "foo").
+					Return((*corev2.Handler)(nil), nil).Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:   "it returns 500 if the store fails to get the outputs",
+			method: http.MethodGet,
+			path:   "/api/core/v2/namespaces/default/handlers/foo/outputs",
+			storeFunc: func(s *mockstore.MockStore) {
+				s.On("GetHandlerByName", mock.Anything, "foo").
+					Return(fixture, nil).Once()
+				s.On("GetHandlerOutputs", mock.Anything, "foo").
+					Return([]*corev2.HandlerOutput(nil), errors.New("error")).Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}...)
 	for _, tt := range tests {
 		run(t, tt, parentRouter, s)
 	}
@@ -2,7 +2,6 @@ package routers
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -31,7 +30,7 @@ func (r *HealthRouter) Mount(parent *mux.Router) {
 	parent.HandleFunc("/health", r.health).Methods(http.MethodGet)
 }
 
-func (r *HealthRouter) health(w http.ResponseWriter, _ *http.Request) {
+func (r *HealthRouter) health(w http.ResponseWriter, req *http.Request) {
 	clusterHealth := r.controller.GetClusterHealth(context.Background())
-	_ = json.NewEncoder(w).Encode(clusterHealth)
+	RespondWith(w, req, clusterHealth)
 }
@@ -13,11 +13,12 @@ type HooksRouter struct {
 }
 
 // NewHooksRouter instantiates new router for controlling hook resources
-func NewHooksRouter(store store.ResourceStore) *HooksRouter {
+func NewHooksRouter(store store.Store) *HooksRouter {
 	return &HooksRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.HookConfig{},
-			Store:    store,
+			Resource:      &corev2.HookConfig{},
+			Store:         store,
+			ManagedFields: store,
 		},
 	}
 }
@@ -35,4 +36,5 @@ func (r *HooksRouter) Mount(parent *mux.Router) {
 	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:hooks}", corev2.HookConfigFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 }
@@ -0,0 +1,53 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// LabelPolicyController represents the controller needs of the
+// LabelPolicyRouter.
+type LabelPolicyController interface {
+	Get(context.Context) (*corev2.LabelPolicy, error)
+	Update(context.Context, *corev2.LabelPolicy) error
+}
+
+// LabelPolicyRouter handles requests for /label-policy.
+type LabelPolicyRouter struct {
+	controller LabelPolicyController
+}
+
+// NewLabelPolicyRouter instantiates a new router for the label policy.
+func NewLabelPolicyRouter(ctrl LabelPolicyController) *LabelPolicyRouter {
+	return &LabelPolicyRouter{
+		controller: ctrl,
+	}
+}
+
+// Mount the LabelPolicyRouter on the given parent Router
+func (r *LabelPolicyRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/{resource:" + corev2.LabelPolicyResource + "}",
+	}
+
+	routes.Path("", r.get).Methods(http.MethodGet)
+	routes.Path("", r.update).Methods(http.MethodPut)
+}
+
+func (r *LabelPolicyRouter) get(req *http.Request) (interface{}, error) {
+	return r.controller.Get(req.Context())
+}
+
+func (r *LabelPolicyRouter) update(req *http.Request) (interface{}, error) {
+	obj := &corev2.LabelPolicy{}
+	if err := UnmarshalBody(req, obj); err != nil {
+		return nil, err
+	}
+
+	err := r.controller.Update(req.Context(), obj)
+	return obj, err
+}
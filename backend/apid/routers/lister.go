@@ -9,6 +9,7 @@ import (
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/authorization"
 	"github.com/sensu/sensu-go/backend/store"
 )
 
@@ -42,7 +43,7 @@ func List(list ListControllerFunc, fields FieldsFunc) http.HandlerFunc {
 			pred.Subcollection = subcollection
 		}
 
-		results, err := list(r.Context(), pred)
+		results, err := listRestricted(r, list, pred)
 		if err != nil {
 			WriteError(w, err)
 			return
@@ -57,6 +58,33 @@ func List(list ListControllerFunc, fields FieldsFunc) http.HandlerFunc {
 	}
 }
 
+// listRestricted lists the resources of the page. The pages of the users only
+// granted some resources of the collection are restricted to these resources
+// before they are limited, so the following pages are fetched until the page
+// is full or the collection is exhausted.
+func listRestricted(r *http.Request, list ListControllerFunc, pred *store.SelectionPredicate) ([]corev2.Resource, error) {
+	if _, ok := authorization.GetResourceNames(r.Context()); !ok {
+		return list(r.Context(), pred)
+	}
+	limit := pred.Limit
+	results := []corev2.Resource{}
+	for {
+		page, err := list(r.Context(), pred)
+		if err != nil {
+			return nil, err
+		}
+		restricted, err := restrictToResourceNames(r, page)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, restricted.([]corev2.Resource)...)
+		if pred.Continue == "" || limit == 0 || int64(len(results)) >= limit {
+			return results, nil
+		}
+		pred.Limit = limit - int64(len(results))
+	}
+}
+
 // We can't directly use a Lister in the mux.Router because it cannot be
 // modified at runtime, which is required for sensu-enterprise-go, therefore we
 // need this little wrapper
@@ -11,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/apid/middlewares"
+	"github.com/sensu/sensu-go/backend/authorization"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -114,3 +115,43 @@ func TestList(t *testing.T) {
 		})
 	}
 }
+
+func TestListRestrictedFillsPage(t *testing.T) {
+	pages := map[string][]corev2.Resource{
+		"":   {corev2.FixtureCheck("a"), corev2.FixtureCheck("b")},
+		"p2": {corev2.FixtureCheck("c"), corev2.FixtureCheck("d")},
+		"p3": {corev2.FixtureCheck("e")},
+	}
+	next := map[string]string{"": "p2", "p2": "p3", "p3": "p4"}
+	var limits []int64
+	list := func(ctx context.Context, pred *store.SelectionPredicate) ([]corev2.Resource, error) {
+		limits = append(limits, pred.Limit)
+		page := pages[pred.Continue]
+		pred.Continue = next[pred.Continue]
+		return page, nil
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/foo?limit=2", nil)
+	r = r.WithContext(authorization.SetResourceNames(r.Context(), []string{"b", "e"}))
+	w := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.PathPrefix("/foo").HandlerFunc(List(list,
+		func(r corev2.Resource) map[string]string { return map[string]string{} },
+	))
+	middleware := middlewares.Pagination{}
+	router.Use(middleware.Then)
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	checks := []*corev2.CheckConfig{}
+	if err := json.Unmarshal(w.Body.Bytes(), &checks); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, "b", checks[0].Name)
+		assert.Equal(t, "e", checks[1].Name)
+	}
+	assert.Equal(t, []int64{2, 1, 1}, limits)
+	assert.Equal(t, "cDQ", w.Header().Get(corev2.PaginationContinueHeader))
+}
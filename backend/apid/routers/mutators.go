@@ -13,11 +13,12 @@ type MutatorsRouter struct {
 }
 
 // NewMutatorsRouter creates a new MutatorsRouter.
-func NewMutatorsRouter(store store.ResourceStore) *MutatorsRouter {
+func NewMutatorsRouter(store store.Store) *MutatorsRouter {
 	return &MutatorsRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.Mutator{},
-			Store:    store,
+			Resource:      &corev2.Mutator{},
+			Store:         store,
+			ManagedFields: store,
 		},
 	}
 }
@@ -35,4 +36,5 @@ func (r *MutatorsRouter) Mount(parent *mux.Router) {
 	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:mutators}", corev2.MutatorFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 }
@@ -13,11 +13,12 @@ type NamespacesRouter struct {
 }
 
 // NewNamespacesRouter instantiates new router for controlling check resources
-func NewNamespacesRouter(store store.ResourceStore) *NamespacesRouter {
+func NewNamespacesRouter(store store.Store) *NamespacesRouter {
 	return &NamespacesRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.Namespace{},
-			Store:    store,
+			Resource:      &corev2.Namespace{},
+			Store:         store,
+			ManagedFields: store,
 		},
 	}
 }
@@ -34,4 +35,5 @@ func (r *NamespacesRouter) Mount(parent *mux.Router) {
 	routes.List(r.handlers.ListResources, corev2.NamespaceFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 }
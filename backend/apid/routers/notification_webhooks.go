@@ -0,0 +1,67 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// NotificationWebhooksRouter handles requests for /notification-webhooks
+type NotificationWebhooksRouter struct {
+	handlers handlers.Handlers
+}
+
+// NewNotificationWebhooksRouter instantiates new router for controlling notification webhook resources
+func NewNotificationWebhooksRouter(store store.Store) *NotificationWebhooksRouter {
+	return &NotificationWebhooksRouter{
+		handlers: handlers.Handlers{
+			Resource:      &corev2.NotificationWebhook{},
+			Store:         store,
+			ManagedFields: store,
+		},
+	}
+}
+
+// Mount the NotificationWebhooksRouter to a parent Router
+func (r *NotificationWebhooksRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:notification-webhooks}",
+	}
+
+	routes.Del(r.handlers.DeleteResource)
+	routes.Get(redactSecret(r.handlers.GetResource))
+	routes.List(r.list, corev2.NotificationWebhookFields)
+	routes.ListAllNamespaces(r.list, "/{resource:notification-webhooks}", corev2.NotificationWebhookFields)
+	routes.Post(r.handlers.CreateResource)
+	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(redactSecret(r.handlers.ApplyResource))
+}
+
+func (r *NotificationWebhooksRouter) list(ctx context.Context, pred *store.SelectionPredicate) ([]corev2.Resource, error) {
+	resources, err := r.handlers.ListResources(ctx, pred)
+	for _, resource := range resources {
+		redactNotificationWebhook(resource)
+	}
+	return resources, err
+}
+
+// redactSecret removes the secret of the notification webhook returned by
+// the action, since it is write-only.
+func redactSecret(action actionHandlerFunc) actionHandlerFunc {
+	return func(req *http.Request) (interface{}, error) {
+		resource, err := action(req)
+		redactNotificationWebhook(resource)
+		return resource, err
+	}
+}
+
+func redactNotificationWebhook(resource interface{}) {
+	if webhook, ok := resource.(*corev2.NotificationWebhook); ok && webhook != nil {
+		webhook.Secret = ""
+	}
+}
@@ -0,0 +1,65 @@
+package routers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotificationWebhooksRouter(t *testing.T) {
+	// Setup the router
+	s := &mockstore.MockStore{}
+	router := NewNotificationWebhooksRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	router.Mount(parentRouter)
+
+	empty := &corev2.NotificationWebhook{}
+	fixture := corev2.FixtureNotificationWebhook("foo")
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases(fixture)...)
+	tests = append(tests, listTestCases(empty)...)
+	tests = append(tests, createTestCases(empty)...)
+	tests = append(tests, updateTestCases(fixture)...)
+	tests = append(tests, deleteTestCases(fixture)...)
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
+
+func TestNotificationWebhooksRouterRedactsSecret(t *testing.T) {
+	s := &mockstore.MockStore{}
+	router := NewNotificationWebhooksRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	router.Mount(parentRouter)
+
+	fixture := corev2.FixtureNotificationWebhook("foo")
+	fixture.Secret = "hunter2"
+	s.On("GetResource", mock.Anything, "foo", mock.AnythingOfType("*v2.NotificationWebhook")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*corev2.NotificationWebhook) = *fixture
+		})
+	s.On("ListResources", mock.Anything, corev2.NotificationWebhooksResource, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			list := args.Get(2).(*[]*corev2.NotificationWebhook)
+			webhook := *fixture
+			*list = []*corev2.NotificationWebhook{&webhook}
+		})
+
+	for _, path := range []string{fixture.URIPath(), "/api/core/v2/namespaces/default/notification-webhooks"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		parentRouter.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Contains(t, w.Body.String(), "example.com", path)
+		assert.NotContains(t, w.Body.String(), "hunter2", path)
+	}
+}
@@ -0,0 +1,67 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/pipelined"
+)
+
+// PipelineController represents the controller needs of the PipelineRouter.
+type PipelineController interface {
+	Simulate(ctx context.Context, event *corev2.Event, executeMutators bool) (*pipelined.Simulation, error)
+}
+
+// PipelineRouter handles requests for /pipeline
+type PipelineRouter struct {
+	controller PipelineController
+}
+
+// NewPipelineRouter instantiates a new router for the event pipeline.
+func NewPipelineRouter(ctrl PipelineController) *PipelineRouter {
+	return &PipelineRouter{
+		controller: ctrl,
+	}
+}
+
+// Mount the PipelineRouter to a parent Router
+func (r *PipelineRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:pipeline}",
+	}
+
+	routes.Path("simulate", r.simulate).Methods(http.MethodPost)
+}
+
+func (r *PipelineRouter) simulate(req *http.Request) (interface{}, error) {
+	event := &corev2.Event{}
+	if err := UnmarshalBody(req, event); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	vars := mux.Vars(req)
+	if event.Entity != nil {
+		if event.Entity.Namespace == "" {
+			event.Entity.Namespace = vars["namespace"]
+		}
+		if err := handlers.CheckMeta(event.Entity, vars); err != nil {
+			return nil, actions.NewError(actions.InvalidArgument, err)
+		}
+	}
+
+	var executeMutators bool
+	if value := req.URL.Query().Get("executeMutators"); value != "" {
+		var err error
+		if executeMutators, err = strconv.ParseBool(value); err != nil {
+			return nil, actions.NewError(actions.InvalidArgument, err)
+		}
+	}
+
+	return r.controller.Simulate(req.Context(), event, executeMutators)
+}
@@ -0,0 +1,75 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/pipelined"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPipelineController struct {
+	mock.Mock
+}
+
+func (m *mockPipelineController) Simulate(ctx context.Context, event *corev2.Event, executeMutators bool) (*pipelined.Simulation, error) {
+	args := m.Called(ctx, event, executeMutators)
+	return args.Get(0).(*pipelined.Simulation), args.Error(1)
+}
+
+func TestPipelineRouterSimulate(t *testing.T) {
+	controller := &mockPipelineController{}
+	router := mux.NewRouter()
+	NewPipelineRouter(controller).Mount(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	simulation := &pipelined.Simulation{
+		Handlers: []pipelined.HandlerSimulation{{Handler: "slack", Type: "pipe", Handled: true}},
+	}
+	controller.On("Simulate", mock.Anything, mock.MatchedBy(func(event *corev2.Event) bool {
+		return event.Entity.Namespace == "default"
+	}), false).Return(simulation, nil)
+
+	body := `{"entity":{"metadata":{"name":"entity1"}},"check":{"metadata":{"name":"check1"}}}`
+	req := newRequest(t, http.MethodPost, server.URL+"/namespaces/default/pipeline/simulate", strings.NewReader(body))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result pipelined.Simulation
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, simulation, &result)
+
+	// The pipe and extension mutators are only executed on request
+	controller.On("Simulate", mock.Anything, mock.Anything, true).Return(simulation, nil).Once()
+	req = newRequest(t, http.MethodPost, server.URL+"/namespaces/default/pipeline/simulate?executeMutators=true", strings.NewReader(body))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	controller.AssertCalled(t, "Simulate", mock.Anything, mock.Anything, true)
+
+	req = newRequest(t, http.MethodPost, server.URL+"/namespaces/default/pipeline/simulate?executeMutators=maybe", strings.NewReader(body))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// The namespace of the event must match the namespace of the URI
+	body = `{"entity":{"metadata":{"name":"entity1","namespace":"acme"}},"check":{"metadata":{"name":"check1"}}}`
+	req = newRequest(t, http.MethodPost, server.URL+"/namespaces/default/pipeline/simulate", strings.NewReader(body))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,48 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Readiness represents the readiness needs of the ReadyRouter
+type Readiness interface {
+	// Ready returns true if the backend is warm.
+	Ready() bool
+
+	// Pending returns the conditions the backend waits for to be ready.
+	Pending() []string
+}
+
+// ReadyRouter handles requests for /ready, which responds with 503 while the
+// backend is warming up, so load balancers and orchestrators can wait for it.
+type ReadyRouter struct {
+	readiness Readiness
+}
+
+// NewReadyRouter instantiates a new router for the readiness of the backend.
+func NewReadyRouter(readiness Readiness) *ReadyRouter {
+	return &ReadyRouter{readiness: readiness}
+}
+
+// Mount the ReadyRouter to a parent Router
+func (r *ReadyRouter) Mount(parent *mux.Router) {
+	parent.HandleFunc("/ready", r.ready).Methods(http.MethodGet)
+}
+
+func (r *ReadyRouter) ready(w http.ResponseWriter, req *http.Request) {
+	response := struct {
+		Ready   bool     `json:"ready"`
+		Pending []string `json:"pending"`
+	}{Ready: true, Pending: []string{}}
+	if r.readiness != nil {
+		response.Ready = r.readiness.Ready()
+		response.Pending = r.readiness.Pending()
+	}
+	status := http.StatusOK
+	if !response.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	respondWithStatus(w, req, status, response)
+}
@@ -0,0 +1,85 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReadiness struct {
+	pending []string
+}
+
+func (f fakeReadiness) Ready() bool {
+	return len(f.pending) == 0
+}
+
+func (f fakeReadiness) Pending() []string {
+	return f.pending
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name      string
+		readiness Readiness
+		status    int
+		ready     bool
+		pending   []string
+	}{
+		{
+			name:      "warming up",
+			readiness: fakeReadiness{pending: []string{"warmup"}},
+			status:    http.StatusServiceUnavailable,
+			pending:   []string{"warmup"},
+		},
+		{
+			name:      "ready",
+			readiness: fakeReadiness{pending: []string{}},
+			status:    http.StatusOK,
+			ready:     true,
+			pending:   []string{},
+		},
+		{
+			name:    "no readiness",
+			status:  http.StatusOK,
+			ready:   true,
+			pending: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			NewReadyRouter(tt.readiness).Mount(router)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			assert.Equal(t, tt.status, w.Code)
+
+			var response struct {
+				Ready   bool     `json:"ready"`
+				Pending []string `json:"pending"`
+			}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, tt.ready, response.Ready)
+			assert.Equal(t, tt.pending, response.Pending)
+		})
+	}
+}
+
+func TestReadyYAML(t *testing.T) {
+	router := mux.NewRouter()
+	NewReadyRouter(fakeReadiness{pending: []string{"warmup"}}).Mount(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	req.Header.Set("Accept", YAMLContentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, YAMLContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "pending:\n- warmup\nready: false\n", w.Body.String())
+}
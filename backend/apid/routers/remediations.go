@@ -0,0 +1,40 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// RemediationsRouter handles requests for /remediations
+type RemediationsRouter struct {
+	handlers handlers.Handlers
+}
+
+// NewRemediationsRouter instantiates new router for controlling remediation resources
+func NewRemediationsRouter(store store.Store) *RemediationsRouter {
+	return &RemediationsRouter{
+		handlers: handlers.Handlers{
+			Resource:      &corev2.Remediation{},
+			Store:         store,
+			ManagedFields: store,
+		},
+	}
+}
+
+// Mount the RemediationsRouter to a parent Router
+func (r *RemediationsRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:remediations}",
+	}
+
+	routes.Del(r.handlers.DeleteResource)
+	routes.Get(r.handlers.GetResource)
+	routes.List(r.handlers.ListResources, corev2.RemediationFields)
+	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:remediations}", corev2.RemediationFields)
+	routes.Post(r.handlers.CreateResource)
+	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
+}
@@ -0,0 +1,59 @@
+package routers
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/authorization"
+)
+
+// restrictToResourceNames returns the resources of the list request, restricted
+// to the resources of the names the request is authorized for, if the user is
+// not authorized for the whole collection. The request is denied if the list
+// is not a slice of resources, since it can not be restricted.
+func restrictToResourceNames(r *http.Request, resources interface{}) (interface{}, error) {
+	names, ok := authorization.GetResourceNames(r.Context())
+	if !ok {
+		return resources, nil
+	}
+	allowed := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		allowed[name] = struct{}{}
+	}
+
+	list := reflect.ValueOf(resources)
+	if list.Kind() != reflect.Slice {
+		return nil, actions.NewErrorf(actions.PermissionDenied)
+	}
+	restricted := reflect.MakeSlice(list.Type(), 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		resource, ok := list.Index(i).Interface().(corev2.Resource)
+		if !ok {
+			return nil, actions.NewErrorf(actions.PermissionDenied)
+		}
+		if _, ok := allowed[resourceName(resource)]; ok {
+			restricted = reflect.Append(restricted, list.Index(i))
+		}
+	}
+	return restricted.Interface(), nil
+}
+
+// resourceName returns the name a resource is granted by. Events have no name
+// of their own, and are granted by the names of their entity and check, as
+// entity/check.
+func resourceName(resource corev2.Resource) string {
+	if event, ok := resource.(*corev2.Event); ok {
+		var entity, check string
+		if event.Entity != nil {
+			entity = event.Entity.Name
+		}
+		if event.Check != nil {
+			check = event.Check.Name
+		}
+		return path.Join(entity, check)
+	}
+	return resource.GetObjectMeta().Name
+}
@@ -0,0 +1,61 @@
+package routers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestrictToResourceNames(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/entities", nil)
+	entities := []*corev2.Entity{corev2.FixtureEntity("foo"), corev2.FixtureEntity("bar")}
+
+	// The requests authorized for the whole collection are not restricted
+	got, err := restrictToResourceNames(req, entities)
+	require.NoError(t, err)
+	assert.Equal(t, entities, got)
+
+	req = req.WithContext(authorization.SetResourceNames(req.Context(), []string{"bar", "baz"}))
+	got, err = restrictToResourceNames(req, entities)
+	require.NoError(t, err)
+	assert.Equal(t, []*corev2.Entity{entities[1]}, got)
+
+	resources := []corev2.Resource{entities[0], entities[1]}
+	got, err = restrictToResourceNames(req, resources)
+	require.NoError(t, err)
+	assert.Equal(t, []corev2.Resource{entities[1]}, got)
+
+	// Events are granted by the names of their entity and check
+	events := []*corev2.Event{corev2.FixtureEvent("foo", "check-cpu"), corev2.FixtureEvent("bar", "check-cpu")}
+	req = req.WithContext(authorization.SetResourceNames(req.Context(), []string{"bar/check-cpu"}))
+	got, err = restrictToResourceNames(req, events)
+	require.NoError(t, err)
+	assert.Equal(t, []*corev2.Event{events[1]}, got)
+
+	// The lists that can not be restricted are denied
+	_, err = restrictToResourceNames(req, map[string]string{"foo": "bar"})
+	assert.Error(t, err)
+	_, err = restrictToResourceNames(req, []string{"bar"})
+	assert.Error(t, err)
+}
+
+func TestRespondWithRestricted(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/cluster/members", nil)
+	req = req.WithContext(authorization.SetResourceNames(req.Context(), []string{"foo"}))
+
+	w := httptest.NewRecorder()
+	RespondWith(w, req, []*corev2.Entity{corev2.FixtureEntity("foo"), corev2.FixtureEntity("bar")})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"foo"`)
+	assert.NotContains(t, w.Body.String(), `"bar"`)
+
+	// The responses that are not lists of resources are denied
+	w = httptest.NewRecorder()
+	RespondWith(w, req, map[string]string{"members": "foo"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -13,11 +13,12 @@ type RoleBindingsRouter struct {
 }
 
 // NewRoleBindingsRouter instantiates a new router for RoleBindings.
-func NewRoleBindingsRouter(store store.ResourceStore) *RoleBindingsRouter {
+func NewRoleBindingsRouter(store store.Store) *RoleBindingsRouter {
 	return &RoleBindingsRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.RoleBinding{},
-			Store:    store,
+			Resource:      &corev2.RoleBinding{},
+			Store:         store,
+			ManagedFields: store,
 		},
 	}
 }
@@ -35,4 +36,5 @@ func (r *RoleBindingsRouter) Mount(parent *mux.Router) {
 	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:rolebindings}", corev2.RoleBindingFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 }
@@ -13,11 +13,12 @@ type RolesRouter struct {
 }
 
 // NewRolesRouter instantiates a new router for Roles.
-func NewRolesRouter(store store.ResourceStore) *RolesRouter {
+func NewRolesRouter(store store.Store) *RolesRouter {
 	return &RolesRouter{
 		handlers: handlers.Handlers{
-			Resource: &corev2.Role{},
-			Store:    store,
+			Resource:      &corev2.Role{},
+			Store:         store,
+			ManagedFields: store,
 		},
 	}
 }
@@ -35,4 +36,5 @@ func (r *RolesRouter) Mount(parent *mux.Router) {
 	routes.ListAllNamespaces(r.handlers.ListResources, "/{resource:roles}", corev2.RoleFields)
 	routes.Post(r.handlers.CreateResource)
 	routes.Put(r.handlers.CreateOrUpdateResource)
+	routes.Patch(r.handlers.ApplyResource)
 }
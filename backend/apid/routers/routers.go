@@ -8,17 +8,33 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
 )
 
 type errorBody struct {
-	Message string `json:"message"`
-	Code    uint32 `json:"code"`
+	Message string               `json:"message"`
+	Code    uint32               `json:"code"`
+	Fields  []actions.FieldError `json:"fields,omitempty"`
+	Denial  *actions.Denial      `json:"denial,omitempty"`
 }
 
-// RespondWith given writer and resource, marshal to JSON and write response.
+// RespondWith given writer and resource, marshal to JSON, or to YAML if the
+// client accepts it, and write response.
 func RespondWith(w http.ResponseWriter, r *http.Request, resources interface{}) {
-	// Set content-type to JSON
-	w.Header().Set("Content-Type", "application/json")
+	respondWithStatus(w, r, http.StatusOK, resources)
+}
+
+// respondWithStatus is RespondWith, with the status code of the response if
+// resources are present.
+func respondWithStatus(w http.ResponseWriter, r *http.Request, status int, resources interface{}) {
+	marshal := json.Marshal
+	if AcceptsYAML(r) {
+		marshal = MarshalYAML
+		w.Header().Set("Content-Type", YAMLContentType)
+	} else {
+		// Set content-type to JSON
+		w.Header().Set("Content-Type", "application/json")
+	}
 
 	// If no resource(s) are present return a 204 response code
 	if resources == nil {
@@ -30,14 +46,25 @@ func RespondWith(w http.ResponseWriter, r *http.Request, resources interface{})
 		return
 	}
 
+	// The lists of the users only granted some of their resources are
+	// restricted to these resources, whichever router responds
+	resources, err := restrictToResourceNames(r, resources)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
 	// Marshal
-	bytes, err := json.Marshal(resources)
+	bytes, err := marshal(resources)
 	if err != nil {
 		WriteError(w, err)
 		return
 	}
 
 	// Write response
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
 	if _, err := w.Write(bytes); err != nil {
 		logger.WithError(err).Error("failed to write response")
 		WriteError(w, err)
@@ -56,6 +83,8 @@ func WriteError(w http.ResponseWriter, err error) {
 	if ok {
 		errBody.Message = actionErr.Message
 		errBody.Code = uint32(actionErr.Code)
+		errBody.Fields = actionErr.Fields
+		errBody.Denial = actionErr.Denial
 		st = HTTPStatusFromCode(actionErr.Code)
 	} else {
 		errBody.Message = err.Error()
@@ -93,8 +122,12 @@ func HTTPStatusFromCode(code actions.ErrCode) int {
 		return http.StatusNotFound
 	case actions.AlreadyExistsErr:
 		return http.StatusConflict
+	case actions.PermissionDenied:
+		return http.StatusForbidden
 	case actions.PaymentRequired:
 		return http.StatusPaymentRequired
+	case actions.Conflict:
+		return http.StatusConflict
 	}
 
 	logger.WithField("code", code).Error("unknown error code")
@@ -132,6 +165,25 @@ func actionHandler(action actionHandlerFunc) http.HandlerFunc {
 	}
 }
 
+// compatibleHandler writes the response of an action with the 200 status code
+// the clients of the third-party compatible APIs expect, even if it has no
+// body. The actions restrict their responses to the authorized resource names
+// themselves, since they are not resources.
+func compatibleHandler(action actionHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resources, err := action(r)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		if resources == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		RespondWith(w, r, resources)
+	}
+}
+
 // listHandler is still used by silenced entries.
 // TODO(palourde): Add pagination to silenced entries
 func listHandler(fn listHandlerFunc) http.HandlerFunc {
@@ -187,12 +239,10 @@ func (r *ResourceRoute) Post(fn actionHandlerFunc) *mux.Route {
 	return r.Path("", fn).Methods(http.MethodPost)
 }
 
-// TODO: uncomment this and use it once controller update fits
-// http patch semantics.
 // Patch updates/modifies
-//func (r *ResourceRoute) Patch(fn actionHandlerFunc) *mux.Route {
-//	return r.path("{id}", fn).Methods(http.MethodPatch)
-//}
+func (r *ResourceRoute) Patch(fn actionHandlerFunc) *mux.Route {
+	return r.Path("{id}", fn).Methods(http.MethodPatch)
+}
 
 // Put updates/replaces
 func (r *ResourceRoute) Put(fn actionHandlerFunc) *mux.Route {
@@ -214,14 +264,14 @@ func handleAction(router *mux.Router, path string, fn actionHandlerFunc) *mux.Ro
 	return router.HandleFunc(path, actionHandler(fn))
 }
 
-// UnmarshalBody decodes the request body
+// UnmarshalBody decodes the request body, from JSON or, if its content type
+// is YAML, from YAML. A YAML resource may be wrapped with its type, like the
+// manifests used by sensuctl create.
 func UnmarshalBody(req *http.Request, record interface{}) error {
-	err := json.NewDecoder(req.Body).Decode(&record)
-	if err != nil {
+	if err := handlers.DecodeBody(req, &record); err != nil {
 		logger.WithError(err).Error("unable to read request body")
 		return err
 	}
-	// TODO: Support other types of requests other than JSON?
 
 	return nil
 }
@@ -2,9 +2,13 @@ package routers
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/sensu/sensu-go/backend/apid/actions"
 )
 
 func newRequest(t *testing.T, method, endpoint string, body io.Reader) *http.Request {
@@ -16,3 +20,21 @@ func newRequest(t *testing.T, method, endpoint string, body io.Reader) *http.Req
 
 	return req.WithContext(context.Background())
 }
+
+func TestWriteErrorFields(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteError(w, actions.NewFieldsError([]actions.FieldError{
+		{Field: "check.interval", Message: "must be greater than 0"},
+	}))
+
+	if got, want := w.Code, http.StatusBadRequest; got != want {
+		t.Fatalf("bad status: got %d, want %d", got, want)
+	}
+	var body errorBody
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Fields) != 1 || body.Fields[0].Field != "check.interval" {
+		t.Fatalf("bad fields: %v", body.Fields)
+	}
+}
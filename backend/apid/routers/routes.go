@@ -0,0 +1,78 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+)
+
+// RouteController represents the controller needs of the RoutesRouter.
+type RouteController interface {
+	List(ctx context.Context) ([]*corev2.Route, error)
+	Find(ctx context.Context, name string) (*corev2.Route, error)
+	CreateOrReplace(ctx context.Context, name string, route *corev2.Route) error
+	Destroy(ctx context.Context, name string) error
+}
+
+// RoutesRouter handles requests for /routes, the cluster-wide notification
+// routes.
+type RoutesRouter struct {
+	controller RouteController
+}
+
+// NewRoutesRouter instantiates a new router for the routes.
+func NewRoutesRouter(ctrl RouteController) *RoutesRouter {
+	return &RoutesRouter{
+		controller: ctrl,
+	}
+}
+
+// Mount the RoutesRouter to a parent Router
+func (r *RoutesRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/{resource:" + corev2.RoutesResource + "}",
+	}
+
+	routes.Path("", r.list).Methods(http.MethodGet)
+	routes.Path("{id}", r.find).Methods(http.MethodGet)
+	routes.Path("{id}", r.createOrReplace).Methods(http.MethodPut)
+	routes.Path("{id}", r.destroy).Methods(http.MethodDelete)
+}
+
+func (r *RoutesRouter) list(req *http.Request) (interface{}, error) {
+	return r.controller.List(req.Context())
+}
+
+func (r *RoutesRouter) find(req *http.Request) (interface{}, error) {
+	name, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, err
+	}
+	return r.controller.Find(req.Context(), name)
+}
+
+func (r *RoutesRouter) createOrReplace(req *http.Request) (interface{}, error) {
+	route := &corev2.Route{}
+	if err := UnmarshalBody(req, route); err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	name, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, err
+	}
+	err = r.controller.CreateOrReplace(req.Context(), name, route)
+	return route, err
+}
+
+func (r *RoutesRouter) destroy(req *http.Request) (interface{}, error) {
+	name, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.controller.Destroy(req.Context(), name)
+}
@@ -0,0 +1,84 @@
+package routers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRouteController struct {
+	mock.Mock
+}
+
+func (m *mockRouteController) List(ctx context.Context) ([]*corev2.Route, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*corev2.Route), args.Error(1)
+}
+
+func (m *mockRouteController) Find(ctx context.Context, name string) (*corev2.Route, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(*corev2.Route), args.Error(1)
+}
+
+func (m *mockRouteController) CreateOrReplace(ctx context.Context, name string, route *corev2.Route) error {
+	return m.Called(ctx, name, route).Error(0)
+}
+
+func (m *mockRouteController) Destroy(ctx context.Context, name string) error {
+	return m.Called(ctx, name).Error(0)
+}
+
+func TestRoutesRouter(t *testing.T) {
+	controller := &mockRouteController{}
+	router := mux.NewRouter()
+	NewRoutesRouter(controller).Mount(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	route := &corev2.Route{Name: "db", Labels: map[string]string{"team": "db"}, Handlers: []string{"opsgenie"}}
+
+	controller.On("CreateOrReplace", mock.Anything, "db", route).Return(nil).Once()
+	body, err := json.Marshal(route)
+	require.NoError(t, err)
+	req := newRequest(t, http.MethodPut, server.URL+"/routes/db", bytes.NewReader(body))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	controller.On("List", mock.Anything).Return([]*corev2.Route{route}, nil).Once()
+	req = newRequest(t, http.MethodGet, server.URL+"/routes", nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var routes []*corev2.Route
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&routes))
+	resp.Body.Close()
+	assert.Equal(t, []*corev2.Route{route}, routes)
+
+	controller.On("Find", mock.Anything, "db").Return(route, nil).Once()
+	req = newRequest(t, http.MethodGet, server.URL+"/routes/db", nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var got corev2.Route
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	resp.Body.Close()
+	assert.Equal(t, route, &got)
+
+	controller.On("Destroy", mock.Anything, "db").Return(nil).Once()
+	req = newRequest(t, http.MethodDelete, server.URL+"/routes/db", nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	controller.AssertExpectations(t)
+}
@@ -0,0 +1,43 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+)
+
+// UsageTracker represents the usage tracking needs of the UsageRouter.
+type UsageTracker interface {
+	// Usage returns the usage of the users active over the last hour.
+	Usage() []corev2.UserUsage
+}
+
+// UsageRouter handles requests for /usage, which reports the requests and the
+// event submissions of each user to this backend.
+type UsageRouter struct {
+	tracker UsageTracker
+}
+
+// NewUsageRouter instantiates a new router for the usage.
+func NewUsageRouter(tracker UsageTracker) *UsageRouter {
+	return &UsageRouter{tracker: tracker}
+}
+
+// Mount the UsageRouter to a parent Router
+func (r *UsageRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/{resource:" + corev2.UsageResource + "}",
+	}
+
+	routes.Path("", r.usage).Methods(http.MethodGet)
+}
+
+func (r *UsageRouter) usage(req *http.Request) (interface{}, error) {
+	if r.tracker == nil {
+		return nil, actions.NewErrorf(actions.NotFound)
+	}
+	return r.tracker.Usage(), nil
+}
@@ -0,0 +1,52 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUsageTracker []corev2.UserUsage
+
+func (f fakeUsageTracker) Usage() []corev2.UserUsage {
+	return f
+}
+
+func TestUsageRouter(t *testing.T) {
+	usage := fakeUsageTracker{
+		{Username: "bot", Requests: corev2.UsageCounts{LastMinute: 1, Last15Minutes: 4, LastHour: 9}},
+	}
+	router := mux.NewRouter()
+	NewUsageRouter(usage).Mount(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req := newRequest(t, http.MethodGet, server.URL+"/usage", nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got []corev2.UserUsage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.Equal(t, []corev2.UserUsage(usage), got)
+}
+
+func TestUsageRouterNoTracker(t *testing.T) {
+	router := mux.NewRouter()
+	NewUsageRouter(nil).Mount(router)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req := newRequest(t, http.MethodGet, server.URL+"/usage", nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
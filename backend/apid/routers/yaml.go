@@ -0,0 +1,114 @@
+package routers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/types"
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// YAMLContentType is the media type of the YAML requests and responses.
+const YAMLContentType = "application/yaml"
+
+// wrappedYAML is a resource wrapped with its type, in the format of the
+// manifests used by sensuctl create.
+type wrappedYAML struct {
+	Type       string                 `yaml:"type"`
+	APIVersion string                 `yaml:"api_version"`
+	ObjectMeta map[string]interface{} `yaml:"metadata,omitempty"`
+	Spec       map[string]interface{} `yaml:"spec"`
+}
+
+// AcceptsYAML returns true if the client prefers a YAML response, i.e. if the
+// Accept header of the request lists a YAML media type before any JSON one.
+func AcceptsYAML(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		if handlers.IsYAMLMediaType(mediaType) {
+			return true
+		}
+		if mediaType == "application/json" {
+			return false
+		}
+	}
+	return false
+}
+
+// MarshalYAML serializes the resources to YAML. The resources are wrapped with
+// their type, and lists are serialized as one document per element.
+func MarshalYAML(resources interface{}) ([]byte, error) {
+	v := reflect.ValueOf(resources)
+	if v.Kind() != reflect.Slice {
+		return marshalYAMLDocument(resources)
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < v.Len(); i++ {
+		doc, err := marshalYAMLDocument(v.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(doc)
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalYAMLDocument serializes a single value to a YAML document, wrapping
+// it with its type if it is a resource.
+func marshalYAMLDocument(v interface{}) ([]byte, error) {
+	resource, ok := v.(corev2.Resource)
+	if !ok || reflect.ValueOf(resource).IsNil() {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.JSONToYAML(b)
+	}
+
+	wrapped := types.WrapResource(resource)
+	spec, err := toYAMLMap(wrapped.Value)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := toYAMLMap(wrapped.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+	delete(spec, "metadata")
+	return yamlv2.Marshal(wrappedYAML{
+		Type:       wrapped.Type,
+		APIVersion: wrapped.APIVersion,
+		ObjectMeta: meta,
+		Spec:       spec,
+	})
+}
+
+// toYAMLMap produces a map from a value by serializing it to JSON, in order to
+// preserve the custom JSON marshalers and the JSON struct tags.
+func toYAMLMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
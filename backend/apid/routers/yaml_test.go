@@ -0,0 +1,107 @@
+package routers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsYAML(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "application/json", want: false},
+		{accept: "application/yaml", want: true},
+		{accept: "text/yaml; charset=utf-8", want: true},
+		{accept: "application/json, application/yaml", want: false},
+		{accept: "text/html, application/x-yaml;q=0.9, */*", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", tt.accept)
+			assert.Equal(t, tt.want, AcceptsYAML(req))
+		})
+	}
+}
+
+func TestRespondWithYAML(t *testing.T) {
+	handlers := []corev2.Resource{
+		corev2.FixtureHandler("slack"),
+		corev2.FixtureHandler("email"),
+	}
+	req := httptest.NewRequest(http.MethodGet, "/handlers", nil)
+	req.Header.Set("Accept", YAMLContentType)
+	w := httptest.NewRecorder()
+	RespondWith(w, req, handlers)
+
+	assert.Equal(t, YAMLContentType, w.Header().Get("Content-Type"))
+	docs := strings.Split(w.Body.String(), "---\n")
+	require.Len(t, docs, 2)
+	assert.True(t, strings.HasPrefix(docs[0], "type: Handler\napi_version: core/v2\nmetadata:\n"))
+	assert.Contains(t, docs[0], "name: slack")
+	assert.Contains(t, docs[1], "name: email")
+}
+
+func TestRespondWithYAMLNonResource(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", YAMLContentType)
+	w := httptest.NewRecorder()
+	RespondWith(w, req, map[string]int{"count": 2})
+	assert.Equal(t, "count: 2\n", w.Body.String())
+}
+
+func TestUnmarshalBodyYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "wrapped",
+			body: `type: Handler
+api_version: core/v2
+metadata:
+  name: slack
+  namespace: default
+spec:
+  type: pipe
+  command: slack-handler
+`,
+		},
+		{
+			name: "unwrapped",
+			body: `metadata:
+  name: slack
+  namespace: default
+type: pipe
+command: slack-handler
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/handlers/slack", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", YAMLContentType)
+			var handler corev2.Handler
+			require.NoError(t, UnmarshalBody(req, &handler))
+			assert.Equal(t, "slack", handler.Name)
+			assert.Equal(t, "default", handler.Namespace)
+			assert.Equal(t, "pipe", handler.Type)
+			assert.Equal(t, "slack-handler", handler.Command)
+		})
+	}
+}
+
+func TestUnmarshalBodyInvalidYAML(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/handlers/slack", strings.NewReader("type: [pipe"))
+	req.Header.Set("Content-Type", YAMLContentType)
+	var handler corev2.Handler
+	assert.Error(t, UnmarshalBody(req, &handler))
+}
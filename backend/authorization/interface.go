@@ -13,6 +13,22 @@ type Authorizer interface {
 	Authorize(ctx context.Context, attrs *Attributes) (bool, error)
 }
 
+// ResourceNamesAuthorizer is an Authorizer which also determines the names of
+// the resources of a collection a request is authorized for, when it is not
+// authorized for the whole collection.
+type ResourceNamesAuthorizer interface {
+	Authorizer
+	AuthorizedResourceNames(ctx context.Context, attrs *Attributes) ([]string, error)
+}
+
+// BindingsAuthorizer is an Authorizer which also determines the role bindings
+// and cluster role bindings of the user evaluated for a request, to explain
+// the requests it denies.
+type BindingsAuthorizer interface {
+	Authorizer
+	EvaluatedBindings(ctx context.Context, attrs *Attributes) ([]string, error)
+}
+
 // Attributes represents all the information required by an authorizer to make
 // an authorization decision
 type Attributes struct {
@@ -38,3 +54,19 @@ func GetAttributes(ctx context.Context) *Attributes {
 func SetAttributes(ctx context.Context, attrs *Attributes) context.Context {
 	return context.WithValue(ctx, types.AuthorizationAttributesKey, attrs)
 }
+
+type resourceNamesKey struct{}
+
+// GetResourceNames returns the names of the resources a list request is
+// restricted to, stored in the given context, and whether the request is
+// restricted at all
+func GetResourceNames(ctx context.Context) ([]string, bool) {
+	names, ok := ctx.Value(resourceNamesKey{}).([]string)
+	return names, ok
+}
+
+// SetResourceNames restricts the list request of the provided context to the
+// resources of the given names
+func SetResourceNames(ctx context.Context, names []string) context.Context {
+	return context.WithValue(ctx, resourceNamesKey{}, names)
+}
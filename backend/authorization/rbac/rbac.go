@@ -162,6 +162,95 @@ func (a *Authorizer) Authorize(ctx context.Context, attrs *authorization.Attribu
 	return authorized, visitErr
 }
 
+// AuthorizedResourceNames returns the names of the resources of the requested
+// collection that the rules of the user allow to get or list, for the requests
+// not authorized for the whole collection
+func (a *Authorizer) AuthorizedResourceNames(ctx context.Context, attrs *authorization.Attributes) ([]string, error) {
+	var (
+		names    []string
+		seen     = map[string]bool{}
+		visitErr error
+	)
+
+	a.VisitRulesFor(ctx, attrs, func(binding RoleBinding, rule corev2.Rule, err error) bool {
+		if err != nil {
+			switch err := err.(type) {
+			case *store.ErrNotFound:
+				logger.WithError(err).Debug("no bindings found")
+			default:
+				logger.WithError(err).Warning("could not retrieve the ClusterRoleBindings or RoleBindings")
+				visitErr = err
+				return false
+			}
+		}
+
+		if !rule.ResourceMatches(attrs.Resource) {
+			return true
+		}
+		if !rule.VerbMatches("get") && !rule.VerbMatches("list") {
+			return true
+		}
+		for _, name := range rule.ResourceNames {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		return true
+	})
+
+	if visitErr != nil {
+		return nil, visitErr
+	}
+	return names, nil
+}
+
+// EvaluatedBindings returns the role bindings and cluster role bindings of the
+// user evaluated for a request, with the role they refer to
+func (a *Authorizer) EvaluatedBindings(ctx context.Context, attrs *authorization.Attributes) ([]string, error) {
+	var (
+		bindings []string
+		seen     = map[string]bool{}
+		visitErr error
+	)
+
+	a.VisitRulesFor(ctx, attrs, func(binding RoleBinding, rule corev2.Rule, err error) bool {
+		if err != nil {
+			switch err := err.(type) {
+			case *store.ErrNotFound:
+				logger.WithError(err).Debug("no bindings found")
+			default:
+				logger.WithError(err).Warning("could not retrieve the ClusterRoleBindings or RoleBindings")
+				visitErr = err
+				return false
+			}
+		}
+		if binding == nil {
+			return true
+		}
+
+		var name string
+		roleRef := binding.GetRoleRef()
+		meta := binding.GetObjectMeta()
+		switch binding.(type) {
+		case *corev2.ClusterRoleBinding:
+			name = fmt.Sprintf("ClusterRoleBinding %s (%s %s)", meta.Name, roleRef.Type, roleRef.Name)
+		default:
+			name = fmt.Sprintf("RoleBinding %s/%s (%s %s)", meta.Namespace, meta.Name, roleRef.Type, roleRef.Name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			bindings = append(bindings, name)
+		}
+		return true
+	})
+
+	if visitErr != nil {
+		return nil, visitErr
+	}
+	return bindings, nil
+}
+
 func (a *Authorizer) getRoleReferencerules(ctx context.Context, roleRef types.RoleRef) ([]types.Rule, error) {
 	switch roleRef.Type {
 	case "Role":
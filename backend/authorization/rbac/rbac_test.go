@@ -448,3 +448,95 @@ func TestVisitRulesFor(t *testing.T) {
 		t.Fatalf("wrong number of rules: got %d, want %d", got, want)
 	}
 }
+
+func TestAuthorizedResourceNames(t *testing.T) {
+	s := &mockstore.MockStore{}
+	s.On("ListClusterRoleBindings", mock.Anything, &store.SelectionPredicate{}).
+		Return([]*types.ClusterRoleBinding{}, nil)
+	s.On("ListRoleBindings", mock.Anything, &store.SelectionPredicate{}).
+		Return([]*types.RoleBinding{{
+			RoleRef:  types.RoleRef{Type: "Role", Name: "checks"},
+			Subjects: []types.Subject{{Type: types.UserType, Name: "foo"}},
+		}}, nil)
+	s.On("GetRole", mock.Anything, "checks").
+		Return(&types.Role{Rules: []types.Rule{
+			{Verbs: []string{"get"}, Resources: []string{"checks"}, ResourceNames: []string{"check-cpu", "check-mem"}},
+			{Verbs: []string{"list"}, Resources: []string{"checks"}, ResourceNames: []string{"check-cpu", "check-disk"}},
+			{Verbs: []string{"delete"}, Resources: []string{"checks"}, ResourceNames: []string{"check-http"}},
+			{Verbs: []string{"get"}, Resources: []string{"handlers"}, ResourceNames: []string{"slack"}},
+		}}, nil)
+
+	a := &Authorizer{Store: s}
+	attrs := &authorization.Attributes{
+		Namespace: "acme",
+		Resource:  "checks",
+		Verb:      "list",
+		User:      types.User{Username: "foo"},
+	}
+	names, err := a.AuthorizedResourceNames(context.Background(), attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"check-cpu", "check-mem", "check-disk"}
+	if len(names) != len(want) {
+		t.Fatalf("got names %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("got names %v, want %v", names, want)
+		}
+	}
+}
+
+func TestEvaluatedBindings(t *testing.T) {
+	s := &mockstore.MockStore{}
+	s.On("ListClusterRoleBindings", mock.Anything, &store.SelectionPredicate{}).
+		Return([]*types.ClusterRoleBinding{{
+			ObjectMeta: types.ObjectMeta{Name: "viewers"},
+			RoleRef:    types.RoleRef{Type: "ClusterRole", Name: "view"},
+			Subjects:   []types.Subject{{Type: types.GroupType, Name: "ops"}},
+		}, {
+			ObjectMeta: types.ObjectMeta{Name: "admins"},
+			RoleRef:    types.RoleRef{Type: "ClusterRole", Name: "admin"},
+			Subjects:   []types.Subject{{Type: types.GroupType, Name: "admins"}},
+		}}, nil)
+	s.On("ListRoleBindings", mock.Anything, &store.SelectionPredicate{}).
+		Return([]*types.RoleBinding{{
+			ObjectMeta: types.ObjectMeta{Name: "checks", Namespace: "acme"},
+			RoleRef:    types.RoleRef{Type: "Role", Name: "checks"},
+			Subjects:   []types.Subject{{Type: types.UserType, Name: "foo"}},
+		}}, nil)
+	s.On("GetClusterRole", mock.Anything, "view").
+		Return(&types.ClusterRole{Rules: []types.Rule{
+			{Verbs: []string{"get", "list"}, Resources: []string{"*"}},
+			{Verbs: []string{"get"}, Resources: []string{"namespaces"}},
+		}}, nil)
+	s.On("GetRole", mock.Anything, "checks").
+		Return(&types.Role{Rules: []types.Rule{
+			{Verbs: []string{"get"}, Resources: []string{"checks"}},
+		}}, nil)
+
+	a := &Authorizer{Store: s}
+	attrs := &authorization.Attributes{
+		Namespace: "acme",
+		Resource:  "checks",
+		Verb:      "delete",
+		User:      types.User{Username: "foo", Groups: []string{"ops"}},
+	}
+	bindings, err := a.EvaluatedBindings(context.Background(), attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"ClusterRoleBinding viewers (ClusterRole view)",
+		"RoleBinding acme/checks (Role checks)",
+	}
+	if len(bindings) != len(want) {
+		t.Fatalf("got bindings %v, want %v", bindings, want)
+	}
+	for i := range want {
+		if bindings[i] != want[i] {
+			t.Fatalf("got bindings %v, want %v", bindings, want)
+		}
+	}
+}
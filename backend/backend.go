@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"runtime/debug"
@@ -21,20 +22,28 @@ import (
 	"github.com/sensu/sensu-go/backend/dashboardd"
 	"github.com/sensu/sensu-go/backend/etcd"
 	"github.com/sensu/sensu-go/backend/eventd"
+	"github.com/sensu/sensu-go/backend/forwardd"
 	"github.com/sensu/sensu-go/backend/keepalived"
 	"github.com/sensu/sensu-go/backend/liveness"
 	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/oncall"
 	"github.com/sensu/sensu-go/backend/pipelined"
 	"github.com/sensu/sensu-go/backend/queue"
+	"github.com/sensu/sensu-go/backend/readiness"
+	"github.com/sensu/sensu-go/backend/remediationd"
 	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/schedulerd"
 	"github.com/sensu/sensu-go/backend/seeds"
 	"github.com/sensu/sensu-go/backend/store"
 	etcdstore "github.com/sensu/sensu-go/backend/store/etcd"
 	"github.com/sensu/sensu-go/backend/tessend"
+	"github.com/sensu/sensu-go/backend/usage"
+	"github.com/sensu/sensu-go/backend/webhookd"
 	"github.com/sensu/sensu-go/rpc"
 	"github.com/sensu/sensu-go/system"
+	sensutransport "github.com/sensu/sensu-go/transport"
 	"github.com/sensu/sensu-go/types"
+	"github.com/sensu/sensu-go/util/systemd"
 	"github.com/spf13/viper"
 )
 
@@ -47,6 +56,12 @@ type Backend struct {
 	Store      store.Store
 	EventStore EventStoreUpdater
 
+	// Readiness becomes ready once the daemons are started and the warmup
+	// window elapsed.
+	Readiness *readiness.Gate
+
+	warmupWindow time.Duration
+
 	done   chan struct{}
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -59,6 +74,9 @@ type EventStoreUpdater interface {
 }
 
 func newClient(config *Config, backend *Backend) (*clientv3.Client, error) {
+	if config.NoEmbedEtcd && config.EtcdLightweight {
+		return nil, errors.New("the etcd lightweight mode requires the embedded etcd")
+	}
 	if config.NoEmbedEtcd {
 		tlsInfo := (transport.TLSInfo)(config.EtcdClientTLSInfo)
 		tlsConfig, err := tlsInfo.ClientConfig()
@@ -98,6 +116,7 @@ func newClient(config *Config, backend *Backend) (*clientv3.Client, error) {
 	if config.EtcdMaxRequestBytes != 0 {
 		cfg.MaxRequestBytes = config.EtcdMaxRequestBytes
 	}
+	cfg.Lightweight = config.EtcdLightweight
 
 	// Start etcd
 	e, err := etcd.NewEtcd(cfg)
@@ -175,6 +194,15 @@ func Initialize(config *Config) (*Backend, error) {
 		return nil, fmt.Errorf("error initializing asset manager: %s", err)
 	}
 
+	onCall, err := oncall.NewResolver(oncall.Config{
+		CacheTTL:       viper.GetDuration(FlagPipelinedOnCallCacheTTL),
+		PagerDutyToken: viper.GetString(FlagPipelinedOnCallPagerDutyToken),
+		ICalHosts:      viper.GetStringSlice(FlagPipelinedOnCallICalHosts),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing on-call resolver: %s", err)
+	}
+
 	// Initialize pipelined
 	pipeline, err := pipelined.New(pipelined.Config{
 		Store:                   stor,
@@ -183,12 +211,68 @@ func Initialize(config *Config) (*Backend, error) {
 		AssetGetter:             assetGetter,
 		BufferSize:              viper.GetInt(FlagPipelinedBufferSize),
 		WorkerCount:             viper.GetInt(FlagPipelinedWorkers),
+		HandlerGracePeriod:      viper.GetDuration(FlagPipelinedHandlerGracePeriod),
+		DefaultHandlerTimeout:   viper.GetDuration(FlagPipelinedDefaultHandlerTimeout),
+		OnCall:                  onCall,
+		HandlerOutputHistory:    viper.GetInt(FlagPipelinedHandlerOutputHistory),
+		HandlerOutputMaxSize:    viper.GetInt(FlagPipelinedHandlerOutputMaxSize),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", pipeline.Name(), err)
 	}
 	b.Daemons = append(b.Daemons, pipeline)
 
+	// Initialize remediationd
+	remediation, err := remediationd.New(remediationd.Config{
+		Store:       stor,
+		Bus:         bus,
+		QueueGetter: queueGetter,
+		BufferSize:  viper.GetInt(FlagPipelinedBufferSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing %s: %s", remediation.Name(), err)
+	}
+	b.Daemons = append(b.Daemons, remediation)
+
+	// Initialize webhookd
+	webhook, err := webhookd.New(webhookd.Config{
+		Store:          stor,
+		Client:         b.Client,
+		WorkerCount:    viper.GetInt(FlagPipelinedWorkers),
+		BufferSize:     viper.GetInt(FlagPipelinedBufferSize),
+		AllowedOrigins: viper.GetStringSlice(FlagNotificationWebhooksAllowedOrigins),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing webhookd: %s", err)
+	}
+	b.Daemons = append(b.Daemons, webhook)
+
+	// Initialize forwardd, if the events are forwarded to an upstream backend
+	if forwardURL := viper.GetString(FlagForwardURL); forwardURL != "" {
+		forward, err := forwardd.New(forwardd.Config{
+			Bus:        bus,
+			URL:        forwardURL,
+			Username:   viper.GetString(FlagForwardUsername),
+			Password:   viper.GetString(FlagForwardPassword),
+			Namespaces: viper.GetStringSlice(FlagForwardNamespaces),
+			BufferSize: viper.GetInt(FlagForwardBufferSize),
+			MaxBackoff: viper.GetDuration(FlagForwardMaxBackoff),
+			TLS: &corev2.TLSOptions{
+				TrustedCAFile:      viper.GetString(FlagForwardTrustedCAFile),
+				InsecureSkipVerify: viper.GetBool(FlagForwardInsecureSkipTLSVerify),
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error initializing forwardd: %s", err)
+		}
+		b.Daemons = append(b.Daemons, forward)
+	}
+
+	// The check scheduling and the keepalive and check TTL failures are held
+	// back until the backend is warm
+	b.Readiness = readiness.NewGate("eventd", "schedulerd", "keepalived")
+	b.warmupWindow = viper.GetDuration(FlagWarmupWindow)
+
 	// Initialize eventd
 	event, err := eventd.New(
 		b.ctx,
@@ -200,6 +284,7 @@ func Initialize(config *Config) (*Backend, error) {
 			Client:          b.Client,
 			BufferSize:      viper.GetInt(FlagEventdBufferSize),
 			WorkerCount:     viper.GetInt(FlagEventdWorkers),
+			Readiness:       b.Readiness,
 		},
 	)
 	if err != nil {
@@ -218,20 +303,74 @@ func Initialize(config *Config) (*Backend, error) {
 			QueueGetter: queueGetter,
 			RingPool:    ringPool,
 			Client:      b.Client,
+			Clock:       config.SchedulerClock,
+			Readiness:   b.Readiness,
 		})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", scheduler.Name(), err)
 	}
 	b.Daemons = append(b.Daemons, scheduler)
 
+	// The usage of the backend is tracked by apid and agentd
+	usageTracker := usage.NewTracker()
+
 	// Initialize agentd
+	namespaceSessionLimits, err := agentd.ParseNamespaceSessionLimits(viper.GetStringSlice(FlagAgentdNamespaceSessionLimits))
+	if err != nil {
+		return nil, fmt.Errorf("error initializing agentd: %s", err)
+	}
 	agent, err := agentd.New(agentd.Config{
 		Host:     config.AgentHost,
 		Port:     config.AgentPort,
+		GRPCPort: config.AgentGRPCPort,
 		Bus:      bus,
 		Store:    stor,
 		TLS:      config.TLS,
 		RingPool: ringPool,
+		Compression: sensutransport.Compression{
+			Enabled: config.AgentCompression,
+			Level:   config.AgentCompressionLevel,
+		},
+		SendQueue: agentd.SendQueueConfig{
+			Size:           viper.GetInt(FlagAgentdSendQueueSize),
+			OverflowPolicy: viper.GetString(FlagAgentdSendQueueOverflowPolicy),
+			Timeout:        viper.GetDuration(FlagAgentdSendQueueTimeout),
+			PriorityWindow: viper.GetDuration(FlagAgentdSendQueuePriorityWindow),
+		},
+		ClientCertAuth: config.AgentClientCertAuth,
+		DrainWindow:    viper.GetDuration(FlagAgentdDrainWindow),
+		SessionLimits: agentd.SessionLimits{
+			Total:      viper.GetInt(FlagAgentdSessionLimit),
+			Namespace:  viper.GetInt(FlagAgentdNamespaceSessionLimit),
+			Namespaces: namespaceSessionLimits,
+		},
+		AgentMetrics: viper.GetBool(FlagAgentdAgentMetrics),
+		ClockSkew: agentd.ClockSkewConfig{
+			Threshold: viper.GetDuration(FlagAgentdClockSkewThreshold),
+			Correct:   viper.GetBool(FlagAgentdClockSkewCorrection),
+		},
+		MessageSize: agentd.MessageSizeConfig{
+			MaxSize: viper.GetInt(FlagAgentdMaxMessageSize),
+			Policy:  viper.GetString(FlagAgentdMessageSizePolicy),
+		},
+		PublishBuffer: agentd.PublishBufferConfig{
+			Size:       viper.GetInt(FlagAgentdPublishBufferSize),
+			MaxBackoff: viper.GetDuration(FlagAgentdPublishMaxBackoff),
+		},
+		Ping: sensutransport.PingConfig{
+			Interval:       viper.GetDuration(FlagAgentdPingInterval),
+			Timeout:        viper.GetDuration(FlagAgentdPongTimeout),
+			MaxMissedPongs: viper.GetInt(FlagAgentdMaxMissedPongs),
+		},
+		Usage:                usageTracker,
+		DuplicateAgentPolicy: viper.GetString(FlagAgentdDuplicateAgentPolicy),
+		ResumeTokenTTL:       viper.GetDuration(FlagAgentdResumeTokenTTL),
+		RejectSpoofedEvents:  viper.GetBool(FlagAgentdRejectSpoofedEvents),
+		Enrollment: agentd.EnrollmentConfig{
+			CACertFile: viper.GetString(FlagAgentdEnrollmentCACertFile),
+			CAKeyFile:  viper.GetString(FlagAgentdEnrollmentCAKeyFile),
+			Validity:   viper.GetDuration(FlagAgentdEnrollmentValidity),
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", agent.Name(), err)
@@ -248,6 +387,7 @@ func Initialize(config *Config) (*Backend, error) {
 		RingPool:              ringPool,
 		BufferSize:            viper.GetInt(FlagKeepalivedBufferSize),
 		WorkerCount:           viper.GetInt(FlagKeepalivedWorkers),
+		Readiness:             b.Readiness,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", keepalive.Name(), err)
@@ -261,6 +401,33 @@ func Initialize(config *Config) (*Backend, error) {
 		return nil, err
 	}
 
+	// Initialize the store maintenance
+	quota := config.EtcdQuotaBackendBytes
+	if quota == 0 {
+		quota = etcd.DefaultQuotaBackendBytes
+	}
+	maintainer, err := etcd.NewMaintainer(etcd.MaintenanceConfig{
+		Client:            b.Client,
+		TLS:               etcdClientTLSConfig,
+		Interval:          viper.GetDuration(FlagEtcdMaintenanceInterval),
+		RetainedRevisions: viper.GetInt64(FlagEtcdMaintenanceRetainedRevisions),
+		QuotaBackendBytes: quota,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing the store maintenance: %s", err)
+	}
+	b.Daemons = append(b.Daemons, maintainer)
+
+	// Initialize the store sampling
+	storeInfoSampler, err := etcd.NewStoreInfoSampler(etcd.StoreInfoConfig{
+		Client:   b.Client,
+		Interval: viper.GetDuration(FlagEtcdStoreInfoInterval),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing the store sampling: %s", err)
+	}
+	b.Daemons = append(b.Daemons, storeInfoSampler)
+
 	// Prepare the authentication providers
 	authenticator := &authentication.Authenticator{}
 	basic := &basic.Provider{
@@ -283,11 +450,21 @@ func Initialize(config *Config) (*Backend, error) {
 		Store:               stor,
 		EventStore:          eventStoreProxy,
 		QueueGetter:         queueGetter,
+		PipelineSimulator:   pipeline,
 		TLS:                 config.TLS,
 		Cluster:             clientv3.NewCluster(b.Client),
+		Client:              b.Client,
 		EtcdClientTLSConfig: etcdClientTLSConfig,
 		Authenticator:       authenticator,
 		ClusterVersion:      clusterVersion,
+		BackendConfig:       config.Effective,
+		StoreMaintainer:     maintainer,
+		StoreInfoSampler:    storeInfoSampler,
+		UsageTracker:        usageTracker,
+		DebugAPI:            config.DebugAPI,
+		FilterLists:         config.APIFilterLists,
+		DenialDetails:       config.APIDenialDetails,
+		Readiness:           b.Readiness,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", api.Name(), err)
@@ -295,13 +472,21 @@ func Initialize(config *Config) (*Backend, error) {
 	b.Daemons = append(b.Daemons, api)
 
 	// Initialize tessend
+	var tessenExporters []tessend.Exporter
+	if config.TessenExportFile != "" {
+		tessenExporters = append(tessenExporters, tessend.NewFileExporter(config.TessenExportFile))
+	}
+	if config.TessenExportPrometheus {
+		tessenExporters = append(tessenExporters, tessend.NewPrometheusExporter())
+	}
 	tessen, err := tessend.New(
 		b.ctx,
 		tessend.Config{
-			Store:    stor,
-			RingPool: ringPool,
-			Client:   b.Client,
-			Bus:      bus,
+			Store:     stor,
+			RingPool:  ringPool,
+			Client:    b.Client,
+			Bus:       bus,
+			Exporters: tessenExporters,
 		})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", tessen.Name(), err)
@@ -361,6 +546,9 @@ func (b *Backend) Run() error {
 		})
 	}
 
+	// The warmup window starts once the daemons are started
+	b.Readiness.Start(b.warmupWindow)
+
 	// Reverse the order of our stopGroup so daemons are stopped in the proper
 	// order (last one started is first one stopped)
 	for i := len(sg)/2 - 1; i >= 0; i-- {
@@ -374,12 +562,28 @@ func (b *Backend) Run() error {
 	}
 	eg.Go()
 
+	// Notify systemd that the backend is started, and ping its watchdog while
+	// the daemons are running and etcd answers
+	running, stopRunning := context.WithCancel(b.ctx)
+	systemd.Ready()
+	systemd.Status("warming up")
+	go systemd.Watchdog(running, b.alive)
+	go func() {
+		select {
+		case <-b.Readiness.C():
+			systemd.Status("ready")
+		case <-running.Done():
+		}
+	}()
+
 	select {
 	case err := <-eg.Err():
 		logger.WithError(err).Error("error in error group")
 	case <-b.ctx.Done():
 		logger.Info("backend shutting down")
 	}
+	stopRunning()
+	systemd.Stopping()
 
 	var derr error
 
@@ -455,6 +659,19 @@ func (e errGroup) Err() <-chan error {
 	return e.out
 }
 
+// alive returns an error if the etcd client of the backend does not answer, so
+// that systemd restarts the backend if it does not recover within the watchdog
+// interval. The read is serializable, it does not depend on the etcd quorum.
+func (b *Backend) alive() error {
+	if b.Client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(b.ctx, 5*time.Second)
+	defer cancel()
+	_, err := b.Client.Get(ctx, store.Root, clientv3.WithSerializable(), clientv3.WithCountOnly())
+	return err
+}
+
 // Stop the Backend cleanly.
 func (b *Backend) Stop() {
 	b.cancel()
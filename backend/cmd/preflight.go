@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/sensu/sensu-go/backend/preflight"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// Preflight flag constants
+	flagPreflightFormat       = "format"
+	flagPreflightTimeout      = "timeout"
+	flagPreflightMaxClockSkew = "max-clock-skew"
+	flagPreflightMinOpenFiles = "min-open-files"
+
+	// Preflight output formats
+	preflightFormatTabular = "tabular"
+	preflightFormatJSON    = "json"
+)
+
+// PreflightCommand checks the configuration and the environment of the
+// backend before it starts. It accepts the flags and the configuration file
+// of the start command.
+func PreflightCommand(start *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "preflight",
+		Short:         "check the configuration and the environment of the sensu backend before starting it",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_ = viper.BindPFlags(start.Flags())
+
+			format, _ := cmd.Flags().GetString(flagPreflightFormat)
+			if format != preflightFormatTabular && format != preflightFormatJSON {
+				return fmt.Errorf("invalid format %q, expected %s or %s", format, preflightFormatTabular, preflightFormatJSON)
+			}
+			timeout, _ := cmd.Flags().GetDuration(flagPreflightTimeout)
+			maxClockSkew, _ := cmd.Flags().GetDuration(flagPreflightMaxClockSkew)
+			minOpenFiles, _ := cmd.Flags().GetUint64(flagPreflightMinOpenFiles)
+
+			report := runPreflight(preflight.Config{
+				Timeout:      timeout,
+				MaxClockSkew: maxClockSkew,
+				MinOpenFiles: minOpenFiles,
+			})
+			if err := writePreflightReport(cmd.OutOrStdout(), format, report); err != nil {
+				return err
+			}
+			if !report.Passed {
+				return errors.New("preflight checks failed")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().AddFlagSet(start.Flags())
+	cmd.Flags().String(flagPreflightFormat, preflightFormatTabular, fmt.Sprintf("output format (%s or %s)", preflightFormatTabular, preflightFormatJSON))
+	cmd.Flags().Duration(flagPreflightTimeout, preflight.DefaultTimeout, "time given to each network check")
+	cmd.Flags().Duration(flagPreflightMaxClockSkew, preflight.DefaultMaxClockSkew, "maximum clock difference tolerated between the backend and the etcd members")
+	cmd.Flags().Uint64(flagPreflightMinOpenFiles, preflight.DefaultMinOpenFiles, "minimum recommended limit of open files of the backend process")
+
+	cmd.Flags().SetNormalizeFunc(aliasNormalizeFunc)
+	cmd.SetUsageTemplate(startUsageTemplate)
+
+	return cmd
+}
+
+// runPreflight runs the preflight checks of the backend configured by viper.
+func runPreflight(config preflight.Config) preflight.Report {
+	if viper.GetString(flagConfigFile) != "" {
+		if err := viper.ReadInConfig(); err != nil {
+			return preflight.NewReport([]preflight.Result{
+				{Check: "config", Status: preflight.StatusFailure, Message: err.Error()},
+			})
+		}
+	}
+	cfg, err := newBackendConfig()
+	if err != nil {
+		return preflight.NewReport([]preflight.Result{
+			{Check: "config", Status: preflight.StatusFailure, Message: err.Error()},
+		})
+	}
+	config.Backend = cfg
+
+	results := []preflight.Result{
+		{Check: "config", Status: preflight.StatusOK, Message: "configuration is valid"},
+	}
+	report := preflight.Run(context.Background(), config)
+	return preflight.NewReport(append(results, report.Results...))
+}
+
+func writePreflightReport(w io.Writer, format string, report preflight.Report) error {
+	if format == preflightFormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tCHECK\tMESSAGE")
+	for _, result := range report.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Status, result.Check, result.Message)
+	}
+	return tw.Flush()
+}
@@ -9,9 +9,16 @@ import (
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/sensu/sensu-go/backend"
+	"github.com/sensu/sensu-go/backend/agentd"
+	"github.com/sensu/sensu-go/backend/apid/middlewares"
 	"github.com/sensu/sensu-go/backend/etcd"
+	"github.com/sensu/sensu-go/backend/forwardd"
+	"github.com/sensu/sensu-go/backend/oncall"
+	"github.com/sensu/sensu-go/backend/pipelined"
+	"github.com/sensu/sensu-go/transport"
 	"github.com/sensu/sensu-go/types"
 	"github.com/sensu/sensu-go/util/path"
 	stringsutil "github.com/sensu/sensu-go/util/strings"
@@ -26,6 +33,10 @@ const (
 	flagConfigFile            = "config-file"
 	flagAgentHost             = "agent-host"
 	flagAgentPort             = "agent-port"
+	flagAgentCompression      = "agent-websocket-compression"
+	flagAgentCompressionLevel = "agent-websocket-compression-level"
+	flagAgentClientCertAuth   = "agent-client-cert-auth"
+	flagAgentGRPCPort         = "agent-grpc-port"
 	deprecatedFlagAPIHost     = "api-host"
 	deprecatedFlagAPIPort     = "api-port"
 	flagAPIListenAddress      = "api-listen-address"
@@ -42,7 +53,12 @@ const (
 	flagTrustedCAFile         = "trusted-ca-file"
 	flagInsecureSkipTLSVerify = "insecure-skip-tls-verify"
 	flagDebug                 = "debug"
+	flagDebugAPI              = "debug-api"
+	flagAPIFilterLists        = "api-filter-lists"
+	flagAPIDenialDetails      = "api-denial-details"
 	flagLogLevel              = "log-level"
+	flagTessenExportFile      = "tessen-export-file"
+	flagTessenExportProm      = "tessen-export-prometheus"
 
 	// Etcd flag constants
 	deprecatedFlagEtcdClientURLs               = "listen-client-urls"
@@ -74,6 +90,7 @@ const (
 	flagEtcdCipherSuites       = "etcd-cipher-suites"
 	flagEtcdMaxRequestBytes    = "etcd-max-request-bytes"
 	flagEtcdQuotaBackendBytes  = "etcd-quota-backend-bytes"
+	flagEtcdLightweight        = "etcd-lightweight"
 
 	// Default values
 
@@ -127,6 +144,8 @@ type initializeFunc func(*backend.Config) (*backend.Backend, error)
 // StartCommand ...
 func StartCommand(initialize initializeFunc) *cobra.Command {
 	var setupErr error
+	// readConfigFile is the path of the configuration file read, if any
+	var readConfigFile string
 
 	cmd := &cobra.Command{
 		Use:           "start",
@@ -153,67 +172,11 @@ func StartCommand(initialize initializeFunc) *cobra.Command {
 			}
 			logrus.SetLevel(level)
 
-			cfg := &backend.Config{
-				AgentHost:             viper.GetString(flagAgentHost),
-				AgentPort:             viper.GetInt(flagAgentPort),
-				APIListenAddress:      viper.GetString(flagAPIListenAddress),
-				APIURL:                viper.GetString(flagAPIURL),
-				DashboardHost:         viper.GetString(flagDashboardHost),
-				DashboardPort:         viper.GetInt(flagDashboardPort),
-				DashboardTLSCertFile:  viper.GetString(flagDashboardCertFile),
-				DashboardTLSKeyFile:   viper.GetString(flagDashboardKeyFile),
-				DeregistrationHandler: viper.GetString(flagDeregistrationHandler),
-				CacheDir:              viper.GetString(flagCacheDir),
-				StateDir:              viper.GetString(flagStateDir),
-
-				EtcdAdvertiseClientURLs:      viper.GetStringSlice(flagEtcdAdvertiseClientURLs),
-				EtcdListenClientURLs:         viper.GetStringSlice(flagEtcdClientURLs),
-				EtcdListenPeerURLs:           viper.GetStringSlice(flagEtcdPeerURLs),
-				EtcdInitialCluster:           viper.GetString(flagEtcdInitialCluster),
-				EtcdInitialClusterState:      viper.GetString(flagEtcdInitialClusterState),
-				EtcdInitialAdvertisePeerURLs: viper.GetStringSlice(flagEtcdInitialAdvertisePeerURLs),
-				EtcdInitialClusterToken:      viper.GetString(flagEtcdInitialClusterToken),
-				EtcdName:                     viper.GetString(flagEtcdNodeName),
-				EtcdCipherSuites:             viper.GetStringSlice(flagEtcdCipherSuites),
-				EtcdQuotaBackendBytes:        viper.GetInt64(flagEtcdQuotaBackendBytes),
-				EtcdMaxRequestBytes:          viper.GetUint(flagEtcdMaxRequestBytes),
-				NoEmbedEtcd:                  viper.GetBool(flagNoEmbedEtcd),
-			}
-
-			// Sensu APIs TLS config
-			certFile := viper.GetString(flagCertFile)
-			keyFile := viper.GetString(flagKeyFile)
-			insecureSkipTLSVerify := viper.GetBool(flagInsecureSkipTLSVerify)
-			// TODO(ccressent gbolo): issue #2548
-			// Eventually this should be changed: --insecure-skip-tls-verify --etcd-insecure-skip-tls-verify
-			trustedCAFile := viper.GetString(flagTrustedCAFile)
-
-			if certFile != "" && keyFile != "" {
-				cfg.TLS = &types.TLSOptions{
-					CertFile:           certFile,
-					KeyFile:            keyFile,
-					TrustedCAFile:      trustedCAFile,
-					InsecureSkipVerify: insecureSkipTLSVerify,
-				}
-			} else if certFile == "" && keyFile != "" {
-				return fmt.Errorf("tls configuration error, missing flag: --%s", flagCertFile)
-			} else if certFile != "" && keyFile == "" {
-				return fmt.Errorf("tls configuration error, missing flag: --%s", flagKeyFile)
-			}
-
-			// Etcd TLS config
-			cfg.EtcdClientTLSInfo = etcd.TLSInfo{
-				CertFile:       viper.GetString(flagEtcdCertFile),
-				KeyFile:        viper.GetString(flagEtcdKeyFile),
-				TrustedCAFile:  viper.GetString(flagEtcdTrustedCAFile),
-				ClientCertAuth: viper.GetBool(flagEtcdClientCertAuth),
-			}
-			cfg.EtcdPeerTLSInfo = etcd.TLSInfo{
-				CertFile:       viper.GetString(flagEtcdPeerCertFile),
-				KeyFile:        viper.GetString(flagEtcdPeerKeyFile),
-				TrustedCAFile:  viper.GetString(flagEtcdPeerTrustedCAFile),
-				ClientCertAuth: viper.GetBool(flagEtcdPeerClientCertAuth),
+			cfg, err := newBackendConfig()
+			if err != nil {
+				return err
 			}
+			cfg.Effective = backend.NewEffectiveConfig(viper.GetViper(), cmd.Flags(), readConfigFile)
 
 			sensuBackend, err := initialize(cfg)
 			if err != nil {
@@ -261,6 +224,10 @@ func StartCommand(initialize initializeFunc) *cobra.Command {
 	// Flag defaults
 	viper.SetDefault(flagAgentHost, "[::]")
 	viper.SetDefault(flagAgentPort, 8081)
+	viper.SetDefault(flagAgentCompression, false)
+	viper.SetDefault(flagAgentCompressionLevel, transport.DefaultCompressionLevel)
+	viper.SetDefault(flagAgentClientCertAuth, false)
+	viper.SetDefault(flagAgentGRPCPort, 0)
 	viper.SetDefault(deprecatedFlagAPIHost, "[::]")
 	viper.SetDefault(deprecatedFlagAPIPort, 8080)
 	viper.SetDefault(flagAPIListenAddress, "[::]:8080")
@@ -276,13 +243,59 @@ func StartCommand(initialize initializeFunc) *cobra.Command {
 	viper.SetDefault(flagKeyFile, "")
 	viper.SetDefault(flagTrustedCAFile, "")
 	viper.SetDefault(flagInsecureSkipTLSVerify, false)
+	viper.SetDefault(flagDebugAPI, false)
+	viper.SetDefault(flagAPIFilterLists, false)
+	viper.SetDefault(flagAPIDenialDetails, middlewares.DenialDetailsNone)
 	viper.SetDefault(flagLogLevel, "warn")
+	viper.SetDefault(flagTessenExportFile, "")
+	viper.SetDefault(flagTessenExportProm, false)
 	viper.SetDefault(backend.FlagEventdWorkers, 100)
 	viper.SetDefault(backend.FlagEventdBufferSize, 100)
 	viper.SetDefault(backend.FlagKeepalivedWorkers, 100)
 	viper.SetDefault(backend.FlagKeepalivedBufferSize, 100)
 	viper.SetDefault(backend.FlagPipelinedWorkers, 100)
 	viper.SetDefault(backend.FlagPipelinedBufferSize, 100)
+	viper.SetDefault(backend.FlagPipelinedHandlerGracePeriod, 5*time.Second)
+	viper.SetDefault(backend.FlagPipelinedDefaultHandlerTimeout, time.Duration(0))
+	viper.SetDefault(backend.FlagPipelinedOnCallCacheTTL, oncall.DefaultCacheTTL)
+	viper.SetDefault(backend.FlagPipelinedOnCallPagerDutyToken, "")
+	viper.SetDefault(backend.FlagPipelinedOnCallICalHosts, []string{})
+	viper.SetDefault(backend.FlagPipelinedHandlerOutputHistory, 0)
+	viper.SetDefault(backend.FlagPipelinedHandlerOutputMaxSize, pipelined.DefaultHandlerOutputMaxSize)
+	viper.SetDefault(backend.FlagAgentdSendQueueSize, agentd.DefaultSendQueueSize)
+	viper.SetDefault(backend.FlagAgentdSendQueueOverflowPolicy, agentd.OverflowPolicyBlock)
+	viper.SetDefault(backend.FlagAgentdSendQueueTimeout, time.Duration(0))
+	viper.SetDefault(backend.FlagAgentdSendQueuePriorityWindow, agentd.DefaultSendQueuePriorityWindow)
+	viper.SetDefault(backend.FlagAgentdDrainWindow, time.Duration(0))
+	viper.SetDefault(backend.FlagAgentdSessionLimit, 0)
+	viper.SetDefault(backend.FlagAgentdNamespaceSessionLimit, 0)
+	viper.SetDefault(backend.FlagAgentdNamespaceSessionLimits, []string{})
+	viper.SetDefault(backend.FlagAgentdAgentMetrics, false)
+	viper.SetDefault(backend.FlagAgentdClockSkewThreshold, agentd.DefaultClockSkewThreshold)
+	viper.SetDefault(backend.FlagAgentdClockSkewCorrection, false)
+	viper.SetDefault(backend.FlagAgentdMaxMessageSize, 0)
+	viper.SetDefault(backend.FlagAgentdMessageSizePolicy, agentd.MessageSizePolicyReject)
+	viper.SetDefault(backend.FlagAgentdPublishBufferSize, agentd.DefaultPublishBufferSize)
+	viper.SetDefault(backend.FlagAgentdPublishMaxBackoff, agentd.DefaultPublishMaxBackoff)
+	viper.SetDefault(backend.FlagAgentdPingInterval, agentd.DefaultPingInterval)
+	viper.SetDefault(backend.FlagAgentdPongTimeout, agentd.DefaultPongTimeout)
+	viper.SetDefault(backend.FlagAgentdMaxMissedPongs, agentd.DefaultMaxMissedPongs)
+	viper.SetDefault(backend.FlagAgentdDuplicateAgentPolicy, agentd.DefaultDuplicateAgentPolicy)
+	viper.SetDefault(backend.FlagAgentdResumeTokenTTL, time.Duration(0))
+	viper.SetDefault(backend.FlagAgentdRejectSpoofedEvents, false)
+	viper.SetDefault(backend.FlagAgentdEnrollmentCACertFile, "")
+	viper.SetDefault(backend.FlagAgentdEnrollmentCAKeyFile, "")
+	viper.SetDefault(backend.FlagAgentdEnrollmentValidity, agentd.DefaultEnrollmentValidity)
+	viper.SetDefault(backend.FlagNotificationWebhooksAllowedOrigins, []string{})
+	viper.SetDefault(backend.FlagForwardURL, "")
+	viper.SetDefault(backend.FlagForwardUsername, "")
+	viper.SetDefault(backend.FlagForwardPassword, "")
+	viper.SetDefault(backend.FlagForwardNamespaces, []string{})
+	viper.SetDefault(backend.FlagForwardBufferSize, forwardd.DefaultBufferSize)
+	viper.SetDefault(backend.FlagForwardMaxBackoff, forwardd.DefaultMaxBackoff)
+	viper.SetDefault(backend.FlagForwardTrustedCAFile, "")
+	viper.SetDefault(backend.FlagForwardInsecureSkipTLSVerify, false)
+	viper.SetDefault(backend.FlagWarmupWindow, time.Duration(0))
 
 	// Etcd defaults
 	viper.SetDefault(flagEtcdAdvertiseClientURLs, defaultEtcdAdvertiseClientURL)
@@ -297,6 +310,10 @@ func StartCommand(initialize initializeFunc) *cobra.Command {
 	viper.SetDefault(flagEtcdQuotaBackendBytes, etcd.DefaultQuotaBackendBytes)
 	viper.SetDefault(flagEtcdMaxRequestBytes, etcd.DefaultMaxRequestBytes)
 	viper.SetDefault(flagNoEmbedEtcd, false)
+	viper.SetDefault(flagEtcdLightweight, false)
+	viper.SetDefault(backend.FlagEtcdMaintenanceInterval, time.Duration(0))
+	viper.SetDefault(backend.FlagEtcdMaintenanceRetainedRevisions, etcd.DefaultMaintenanceRetainedRevisions)
+	viper.SetDefault(backend.FlagEtcdStoreInfoInterval, etcd.DefaultStoreInfoInterval)
 
 	// Merge in config flag set so that it appears in command usage
 	cmd.Flags().AddFlagSet(configFlagSet)
@@ -304,6 +321,10 @@ func StartCommand(initialize initializeFunc) *cobra.Command {
 	// Main Flags
 	cmd.Flags().String(flagAgentHost, viper.GetString(flagAgentHost), "agent listener host")
 	cmd.Flags().Int(flagAgentPort, viper.GetInt(flagAgentPort), "agent listener port")
+	cmd.Flags().Bool(flagAgentCompression, viper.GetBool(flagAgentCompression), "compress the messages exchanged with the agents supporting the WebSocket permessage-deflate extension")
+	cmd.Flags().Int(flagAgentCompressionLevel, viper.GetInt(flagAgentCompressionLevel), "compression level of the messages sent to the agents, from -2 (Huffman coding only) to 9 (best compression)")
+	cmd.Flags().Bool(flagAgentClientCertAuth, viper.GetBool(flagAgentClientCertAuth), fmt.Sprintf("authenticate agents with TLS client certificates signed by --%s instead of usernames and passwords", flagTrustedCAFile))
+	cmd.Flags().Int(flagAgentGRPCPort, viper.GetInt(flagAgentGRPCPort), "agent gRPC listener port, the gRPC transport is disabled if 0")
 	cmd.Flags().String(flagAPIListenAddress, viper.GetString(flagAPIListenAddress), "address to listen on for api traffic")
 	cmd.Flags().String(flagAPIURL, viper.GetString(flagAPIURL), "url of the api to connect to")
 	cmd.Flags().String(flagDashboardHost, viper.GetString(flagDashboardHost), "dashboard listener host")
@@ -318,13 +339,61 @@ func StartCommand(initialize initializeFunc) *cobra.Command {
 	cmd.Flags().String(flagTrustedCAFile, viper.GetString(flagTrustedCAFile), "TLS CA certificate bundle in PEM format used for etcd client (mutual TLS)")
 	cmd.Flags().Bool(flagInsecureSkipTLSVerify, viper.GetBool(flagInsecureSkipTLSVerify), "skip TLS verification (not recommended!)")
 	cmd.Flags().Bool(flagDebug, false, "enable debugging and profiling features")
+	cmd.Flags().Bool(flagDebugAPI, viper.GetBool(flagDebugAPI), "expose profiling and runtime debug endpoints under /debug of the API, restricted to users granted the debug verb")
+	cmd.Flags().Bool(flagAPIFilterLists, viper.GetBool(flagAPIFilterLists), "restrict the lists requested by the users only granted access to some resources of a collection, by name, to these resources instead of denying the requests")
+	cmd.Flags().String(flagAPIDenialDetails, viper.GetString(flagAPIDenialDetails), "details of the denied requests in the API responses [none, request, trace], trace also listing the bindings of the user evaluated")
 	cmd.Flags().String(flagLogLevel, viper.GetString(flagLogLevel), "logging level [panic, fatal, error, warn, info, debug]")
+	cmd.Flags().String(flagTessenExportFile, viper.GetString(flagTessenExportFile), "path of a file to which the tessen metrics are appended, even if tessen is opted out")
+	cmd.Flags().Bool(flagTessenExportProm, viper.GetBool(flagTessenExportProm), "expose the tessen metrics on the /metrics endpoint, even if tessen is opted out")
 	cmd.Flags().Int(backend.FlagEventdWorkers, viper.GetInt(backend.FlagEventdWorkers), "number of workers spawned for processing incoming events")
 	cmd.Flags().Int(backend.FlagEventdBufferSize, viper.GetInt(backend.FlagEventdBufferSize), "number of incoming events that can be buffered")
 	cmd.Flags().Int(backend.FlagKeepalivedWorkers, viper.GetInt(backend.FlagKeepalivedWorkers), "number of workers spawned for processing incoming keepalives")
 	cmd.Flags().Int(backend.FlagKeepalivedBufferSize, viper.GetInt(backend.FlagKeepalivedBufferSize), "number of incoming keepalives that can be buffered")
 	cmd.Flags().Int(backend.FlagPipelinedWorkers, viper.GetInt(backend.FlagPipelinedWorkers), "number of workers spawned for handling events through the event pipeline")
 	cmd.Flags().Int(backend.FlagPipelinedBufferSize, viper.GetInt(backend.FlagPipelinedBufferSize), "number of events to handle that can be buffered")
+	cmd.Flags().Duration(backend.FlagPipelinedHandlerGracePeriod, viper.GetDuration(backend.FlagPipelinedHandlerGracePeriod), "time given to pipe handlers to exit after being terminated on timeout, before they are killed")
+	cmd.Flags().Duration(backend.FlagPipelinedDefaultHandlerTimeout, viper.GetDuration(backend.FlagPipelinedDefaultHandlerTimeout), "execution timeout of the pipe handlers without one (0 for none)")
+	cmd.Flags().Duration(backend.FlagPipelinedOnCallCacheTTL, viper.GetDuration(backend.FlagPipelinedOnCallCacheTTL), "time who is on call in a schedule is cached by the oncall mutator")
+	cmd.Flags().String(backend.FlagPipelinedOnCallPagerDutyToken, viper.GetString(backend.FlagPipelinedOnCallPagerDutyToken), "API token used by the oncall mutator to read the PagerDuty schedules")
+	cmd.Flags().StringSlice(backend.FlagPipelinedOnCallICalHosts, viper.GetStringSlice(backend.FlagPipelinedOnCallICalHosts), "comma-delimited list of hosts the oncall mutator can read the iCal calendars from (iCal calendars are disabled if empty)")
+	cmd.Flags().Int(backend.FlagPipelinedHandlerOutputHistory, viper.GetInt(backend.FlagPipelinedHandlerOutputHistory), "number of outputs of the last executions captured for each handler, for debugging (0 disables the capture)")
+	cmd.Flags().Int(backend.FlagPipelinedHandlerOutputMaxSize, viper.GetInt(backend.FlagPipelinedHandlerOutputMaxSize), "size, in bytes, at which the captured handler outputs are truncated")
+	cmd.Flags().Int(backend.FlagAgentdSendQueueSize, viper.GetInt(backend.FlagAgentdSendQueueSize), "number of messages that can be buffered for each agent")
+	cmd.Flags().String(backend.FlagAgentdSendQueueOverflowPolicy, viper.GetString(backend.FlagAgentdSendQueueOverflowPolicy), fmt.Sprintf("policy applied to the messages sent to the full queue of an agent (%s, %s or %s)", agentd.OverflowPolicyBlock, agentd.OverflowPolicyDropOldest, agentd.OverflowPolicyDropNewest))
+	cmd.Flags().Duration(backend.FlagAgentdSendQueueTimeout, viper.GetDuration(backend.FlagAgentdSendQueueTimeout), "time a message waits for room in the full queue of an agent before being dropped, with the block overflow policy (0 waits indefinitely)")
+	cmd.Flags().Duration(backend.FlagAgentdSendQueuePriorityWindow, viper.GetDuration(backend.FlagAgentdSendQueuePriorityWindow), "time before the next execution of their check from which the check requests are sent to an agent ahead of its other messages")
+	cmd.Flags().Duration(backend.FlagAgentdDrainWindow, viper.GetDuration(backend.FlagAgentdDrainWindow), "time over which the connected agents are asked to reconnect to another backend when the backend stops (0 to disable)")
+	cmd.Flags().Int(backend.FlagAgentdSessionLimit, viper.GetInt(backend.FlagAgentdSessionLimit), "maximum number of concurrent agent sessions on the backend (0 for unlimited)")
+	cmd.Flags().Int(backend.FlagAgentdNamespaceSessionLimit, viper.GetInt(backend.FlagAgentdNamespaceSessionLimit), "maximum number of concurrent agent sessions of each namespace on the backend (0 for unlimited)")
+	cmd.Flags().StringSlice(backend.FlagAgentdNamespaceSessionLimits, viper.GetStringSlice(backend.FlagAgentdNamespaceSessionLimits), fmt.Sprintf("maximum numbers of concurrent agent sessions of specific namespaces on the backend, overriding --%s, as a list of namespace=limit", backend.FlagAgentdNamespaceSessionLimit))
+	cmd.Flags().Bool(backend.FlagAgentdAgentMetrics, viper.GetBool(backend.FlagAgentdAgentMetrics), "label the agent session metrics with the names of the agents, in addition to their namespaces")
+	cmd.Flags().Duration(backend.FlagAgentdClockSkewThreshold, viper.GetDuration(backend.FlagAgentdClockSkewThreshold), "clock skew from which the events of an agent are annotated and an agent-clock-skew event is published (0 to disable)")
+	cmd.Flags().Bool(backend.FlagAgentdClockSkewCorrection, viper.GetBool(backend.FlagAgentdClockSkewCorrection), "shift the timestamps of the events of the agents whose clock is skewed to the time of the backend")
+	cmd.Flags().Int(backend.FlagAgentdMaxMessageSize, viper.GetInt(backend.FlagAgentdMaxMessageSize), "maximum size, in bytes, of the payloads of the messages received from the agents (0 for unlimited)")
+	cmd.Flags().String(backend.FlagAgentdMessageSizePolicy, viper.GetString(backend.FlagAgentdMessageSizePolicy), fmt.Sprintf("policy applied to the messages of the agents exceeding the maximum message size (%s, or %s to truncate the check output of the events)", agentd.MessageSizePolicyReject, agentd.MessageSizePolicyTruncate))
+	cmd.Flags().Int(backend.FlagAgentdPublishBufferSize, viper.GetInt(backend.FlagAgentdPublishBufferSize), "number of messages of each agent buffered and retried while the message bus fails to publish them (0 to disable)")
+	cmd.Flags().Duration(backend.FlagAgentdPublishMaxBackoff, viper.GetDuration(backend.FlagAgentdPublishMaxBackoff), "maximum time between the attempts to publish a buffered message of an agent")
+	cmd.Flags().Duration(backend.FlagAgentdPingInterval, viper.GetDuration(backend.FlagAgentdPingInterval), "time between the pings sent to the agents connected over WebSocket (0 to disable)")
+	cmd.Flags().Duration(backend.FlagAgentdPongTimeout, viper.GetDuration(backend.FlagAgentdPongTimeout), "time without pong after which the WebSocket connection of an agent is closed, must be greater than the ping interval")
+	cmd.Flags().Int(backend.FlagAgentdMaxMissedPongs, viper.GetInt(backend.FlagAgentdMaxMissedPongs), "number of consecutive pings without pong after which the WebSocket connection of an agent is closed, without waiting for the pong timeout (0 to only rely on the pong timeout)")
+	cmd.Flags().String(backend.FlagAgentdDuplicateAgentPolicy, viper.GetString(backend.FlagAgentdDuplicateAgentPolicy), "policy applied to the sessions of the agents connecting with the name of an agent already connected to the backend: warn, reject or evict")
+	cmd.Flags().Duration(backend.FlagWarmupWindow, viper.GetDuration(backend.FlagWarmupWindow), "time after the backend starts during which the checks are not scheduled and the keepalive and check TTL failures are postponed, so the agents can reconnect (0 to only wait for the caches and rings to be loaded)")
+	cmd.Flags().Duration(backend.FlagAgentdResumeTokenTTL, viper.GetDuration(backend.FlagAgentdResumeTokenTTL), "time after their session stops during which the agents reconnecting to the backend can resume it, keeping their ring membership (0 to disable)")
+	cmd.Flags().Bool(backend.FlagAgentdRejectSpoofedEvents, viper.GetBool(backend.FlagAgentdRejectSpoofedEvents), "reject the events submitted by the agents for entities other than themselves, unless their check declares a proxy entity that is not another agent")
+	cmd.Flags().String(backend.FlagAgentdEnrollmentCACertFile, viper.GetString(backend.FlagAgentdEnrollmentCACertFile), fmt.Sprintf("CA certificate signing the TLS client certificates of the agents enrolling with an enrollment token, it must be trusted by --%s to authenticate them", flagTrustedCAFile))
+	cmd.Flags().String(backend.FlagAgentdEnrollmentCAKeyFile, viper.GetString(backend.FlagAgentdEnrollmentCAKeyFile), "private key of the CA signing the TLS client certificates of the enrolling agents")
+	cmd.Flags().Duration(backend.FlagAgentdEnrollmentValidity, viper.GetDuration(backend.FlagAgentdEnrollmentValidity), "validity of the TLS client certificates issued to the enrolling agents, which renew them once two thirds of it elapsed")
+
+	// Forwarding flags
+	cmd.Flags().StringSlice(backend.FlagNotificationWebhooksAllowedOrigins, viper.GetStringSlice(backend.FlagNotificationWebhooksAllowedOrigins), "comma-delimited list of scheme://host[:port] origins the notifications of the notification webhooks can be posted to (no notification is posted if empty)")
+	cmd.Flags().String(backend.FlagForwardURL, viper.GetString(backend.FlagForwardURL), "URL of the API of the upstream backend the processed events are forwarded to (empty to disable forwarding)")
+	cmd.Flags().String(backend.FlagForwardUsername, viper.GetString(backend.FlagForwardUsername), "user of the upstream backend the events are forwarded as")
+	cmd.Flags().String(backend.FlagForwardPassword, viper.GetString(backend.FlagForwardPassword), "password of the user of the upstream backend the events are forwarded as")
+	cmd.Flags().StringSlice(backend.FlagForwardNamespaces, viper.GetStringSlice(backend.FlagForwardNamespaces), "namespaces of the forwarded events (all namespaces if empty)")
+	cmd.Flags().Int(backend.FlagForwardBufferSize, viper.GetInt(backend.FlagForwardBufferSize), "number of events buffered while the upstream backend is unreachable")
+	cmd.Flags().Duration(backend.FlagForwardMaxBackoff, viper.GetDuration(backend.FlagForwardMaxBackoff), "maximum time between the attempts to forward an event to the upstream backend")
+	cmd.Flags().String(backend.FlagForwardTrustedCAFile, viper.GetString(backend.FlagForwardTrustedCAFile), "path to the CA certificates trusted to verify the upstream backend")
+	cmd.Flags().Bool(backend.FlagForwardInsecureSkipTLSVerify, viper.GetBool(backend.FlagForwardInsecureSkipTLSVerify), "skip the verification of the TLS certificate of the upstream backend (not recommended!)")
 
 	// Etcd flags
 	cmd.Flags().StringSlice(flagEtcdAdvertiseClientURLs, viper.GetStringSlice(flagEtcdAdvertiseClientURLs), "list of this member's client URLs to advertise to the rest of the cluster.")
@@ -351,6 +420,14 @@ func StartCommand(initialize initializeFunc) *cobra.Command {
 	_ = cmd.Flags().SetAnnotation(flagEtcdQuotaBackendBytes, "categories", []string{"store"})
 	cmd.Flags().Uint(flagEtcdMaxRequestBytes, viper.GetUint(flagEtcdMaxRequestBytes), "maximum etcd request size in bytes (use with caution)")
 	_ = cmd.Flags().SetAnnotation(flagEtcdMaxRequestBytes, "categories", []string{"store"})
+	cmd.Flags().Bool(flagEtcdLightweight, viper.GetBool(flagEtcdLightweight), "trim the embedded etcd for small, single-node installs (the database size limit is unchanged, see --etcd-quota-backend-bytes)")
+	_ = cmd.Flags().SetAnnotation(flagEtcdLightweight, "categories", []string{"store"})
+	cmd.Flags().Duration(backend.FlagEtcdMaintenanceInterval, viper.GetDuration(backend.FlagEtcdMaintenanceInterval), "time between the scheduled compactions and rolling defragmentations of the store (0 to disable)")
+	_ = cmd.Flags().SetAnnotation(backend.FlagEtcdMaintenanceInterval, "categories", []string{"store"})
+	cmd.Flags().Int64(backend.FlagEtcdMaintenanceRetainedRevisions, viper.GetInt64(backend.FlagEtcdMaintenanceRetainedRevisions), "number of the most recent revisions kept by the compactions of the store")
+	_ = cmd.Flags().SetAnnotation(backend.FlagEtcdMaintenanceRetainedRevisions, "categories", []string{"store"})
+	cmd.Flags().Duration(backend.FlagEtcdStoreInfoInterval, viper.GetDuration(backend.FlagEtcdStoreInfoInterval), "time between the samples of the number and size of the keys of the store by resource type and namespace (0 to disable)")
+	_ = cmd.Flags().SetAnnotation(backend.FlagEtcdStoreInfoInterval, "categories", []string{"store"})
 
 	// Etcd TLS flags
 	cmd.Flags().String(flagEtcdCertFile, viper.GetString(flagEtcdCertFile), "path to the client server TLS cert file")
@@ -385,6 +462,8 @@ func StartCommand(initialize initializeFunc) *cobra.Command {
 	// Load the configuration file but only error out if flagConfigFile is used
 	if err := viper.ReadInConfig(); err != nil && configFile != "" {
 		setupErr = err
+	} else if err == nil {
+		readConfigFile = configFilePath
 	}
 
 	// Mark the old etcd keys as deprecated in the config file and then register
@@ -406,6 +485,84 @@ func StartCommand(initialize initializeFunc) *cobra.Command {
 	return cmd
 }
 
+// newBackendConfig returns the configuration of the backend, read from viper.
+func newBackendConfig() (*backend.Config, error) {
+	cfg := &backend.Config{
+		AgentHost:             viper.GetString(flagAgentHost),
+		AgentPort:             viper.GetInt(flagAgentPort),
+		AgentCompression:      viper.GetBool(flagAgentCompression),
+		AgentCompressionLevel: viper.GetInt(flagAgentCompressionLevel),
+		AgentGRPCPort:         viper.GetInt(flagAgentGRPCPort),
+		AgentClientCertAuth:   viper.GetBool(flagAgentClientCertAuth),
+		APIListenAddress:      viper.GetString(flagAPIListenAddress),
+		APIURL:                viper.GetString(flagAPIURL),
+		DebugAPI:              viper.GetBool(flagDebugAPI),
+		APIFilterLists:        viper.GetBool(flagAPIFilterLists),
+		APIDenialDetails:      viper.GetString(flagAPIDenialDetails),
+		DashboardHost:         viper.GetString(flagDashboardHost),
+		DashboardPort:         viper.GetInt(flagDashboardPort),
+		DashboardTLSCertFile:  viper.GetString(flagDashboardCertFile),
+		DashboardTLSKeyFile:   viper.GetString(flagDashboardKeyFile),
+		DeregistrationHandler: viper.GetString(flagDeregistrationHandler),
+		CacheDir:              viper.GetString(flagCacheDir),
+		StateDir:              viper.GetString(flagStateDir),
+
+		TessenExportFile:       viper.GetString(flagTessenExportFile),
+		TessenExportPrometheus: viper.GetBool(flagTessenExportProm),
+
+		EtcdAdvertiseClientURLs:      viper.GetStringSlice(flagEtcdAdvertiseClientURLs),
+		EtcdListenClientURLs:         viper.GetStringSlice(flagEtcdClientURLs),
+		EtcdListenPeerURLs:           viper.GetStringSlice(flagEtcdPeerURLs),
+		EtcdInitialCluster:           viper.GetString(flagEtcdInitialCluster),
+		EtcdInitialClusterState:      viper.GetString(flagEtcdInitialClusterState),
+		EtcdInitialAdvertisePeerURLs: viper.GetStringSlice(flagEtcdInitialAdvertisePeerURLs),
+		EtcdInitialClusterToken:      viper.GetString(flagEtcdInitialClusterToken),
+		EtcdName:                     viper.GetString(flagEtcdNodeName),
+		EtcdCipherSuites:             viper.GetStringSlice(flagEtcdCipherSuites),
+		EtcdQuotaBackendBytes:        viper.GetInt64(flagEtcdQuotaBackendBytes),
+		EtcdMaxRequestBytes:          viper.GetUint(flagEtcdMaxRequestBytes),
+		NoEmbedEtcd:                  viper.GetBool(flagNoEmbedEtcd),
+		EtcdLightweight:              viper.GetBool(flagEtcdLightweight),
+	}
+
+	// Sensu APIs TLS config
+	certFile := viper.GetString(flagCertFile)
+	keyFile := viper.GetString(flagKeyFile)
+	insecureSkipTLSVerify := viper.GetBool(flagInsecureSkipTLSVerify)
+	// TODO(ccressent gbolo): issue #2548
+	// Eventually this should be changed: --insecure-skip-tls-verify --etcd-insecure-skip-tls-verify
+	trustedCAFile := viper.GetString(flagTrustedCAFile)
+
+	if certFile != "" && keyFile != "" {
+		cfg.TLS = &types.TLSOptions{
+			CertFile:           certFile,
+			KeyFile:            keyFile,
+			TrustedCAFile:      trustedCAFile,
+			InsecureSkipVerify: insecureSkipTLSVerify,
+		}
+	} else if certFile == "" && keyFile != "" {
+		return nil, fmt.Errorf("tls configuration error, missing flag: --%s", flagCertFile)
+	} else if certFile != "" && keyFile == "" {
+		return nil, fmt.Errorf("tls configuration error, missing flag: --%s", flagKeyFile)
+	}
+
+	// Etcd TLS config
+	cfg.EtcdClientTLSInfo = etcd.TLSInfo{
+		CertFile:       viper.GetString(flagEtcdCertFile),
+		KeyFile:        viper.GetString(flagEtcdKeyFile),
+		TrustedCAFile:  viper.GetString(flagEtcdTrustedCAFile),
+		ClientCertAuth: viper.GetBool(flagEtcdClientCertAuth),
+	}
+	cfg.EtcdPeerTLSInfo = etcd.TLSInfo{
+		CertFile:       viper.GetString(flagEtcdPeerCertFile),
+		KeyFile:        viper.GetString(flagEtcdPeerKeyFile),
+		TrustedCAFile:  viper.GetString(flagEtcdPeerTrustedCAFile),
+		ClientCertAuth: viper.GetBool(flagEtcdPeerClientCertAuth),
+	}
+
+	return cfg, nil
+}
+
 func categoryFlags(category string, flags *pflag.FlagSet) *pflag.FlagSet {
 	flagSet := pflag.NewFlagSet(category, pflag.ContinueOnError)
 
@@ -1,7 +1,9 @@
 package backend
 
 import (
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/etcd"
+	"github.com/sensu/sensu-go/backend/schedulerd"
 	"github.com/sensu/sensu-go/types"
 )
 
@@ -27,6 +29,139 @@ const (
 	FlagPipelinedWorkers = "pipelined-workers"
 	// FlagPipelinedBufferSize defines the buffer size for pipelined
 	FlagPipelinedBufferSize = "pipelined-buffer-size"
+	// FlagPipelinedHandlerGracePeriod defines the time given to pipe handlers
+	// to exit after being terminated on timeout, before they are killed
+	FlagPipelinedHandlerGracePeriod = "pipelined-handler-grace-period"
+	// FlagPipelinedDefaultHandlerTimeout defines the execution timeout of
+	// the pipe handlers without one
+	FlagPipelinedDefaultHandlerTimeout = "pipelined-default-handler-timeout"
+	// FlagPipelinedOnCallCacheTTL defines the time the targets looked up by
+	// the oncall mutator are cached
+	FlagPipelinedOnCallCacheTTL = "pipelined-oncall-cache-ttl"
+	// FlagPipelinedOnCallPagerDutyToken defines the API token used by the
+	// oncall mutator to read the PagerDuty schedules
+	FlagPipelinedOnCallPagerDutyToken = "pipelined-oncall-pagerduty-token"
+	// FlagPipelinedOnCallICalHosts defines the hosts the oncall mutator can
+	// read the iCal calendars from
+	FlagPipelinedOnCallICalHosts = "pipelined-oncall-ical-hosts"
+	// FlagPipelinedHandlerOutputHistory defines the number of outputs of the
+	// last executions captured for each handler
+	FlagPipelinedHandlerOutputHistory = "pipelined-handler-output-history"
+	// FlagPipelinedHandlerOutputMaxSize defines the size at which the
+	// captured handler outputs are truncated
+	FlagPipelinedHandlerOutputMaxSize = "pipelined-handler-output-max-size"
+	// FlagAgentdSendQueueSize defines the number of messages buffered for
+	// each agent session
+	FlagAgentdSendQueueSize = "agentd-send-queue-size"
+	// FlagAgentdSendQueueOverflowPolicy defines the policy applied to the
+	// messages sent to the full queue of an agent session
+	FlagAgentdSendQueueOverflowPolicy = "agentd-send-queue-overflow-policy"
+	// FlagAgentdSendQueueTimeout defines the time a message waits for room in
+	// the full queue of an agent session with the block policy
+	FlagAgentdSendQueueTimeout = "agentd-send-queue-timeout"
+	// FlagAgentdSendQueuePriorityWindow defines the time before their deadline
+	// from which the check requests are sent ahead of the other messages
+	FlagAgentdSendQueuePriorityWindow = "agentd-send-queue-priority-window"
+	// FlagAgentdDrainWindow defines the time over which the agents are asked
+	// to reconnect to another backend when the backend stops
+	FlagAgentdDrainWindow = "agentd-drain-window"
+	// FlagAgentdSessionLimit defines the maximum number of concurrent agent
+	// sessions on the backend
+	FlagAgentdSessionLimit = "agentd-session-limit"
+	// FlagAgentdNamespaceSessionLimit defines the maximum number of concurrent
+	// agent sessions of each namespace on the backend
+	FlagAgentdNamespaceSessionLimit = "agentd-namespace-session-limit"
+	// FlagAgentdNamespaceSessionLimits defines the maximum numbers of
+	// concurrent agent sessions of specific namespaces on the backend
+	FlagAgentdNamespaceSessionLimits = "agentd-namespace-session-limits"
+	// FlagAgentdAgentMetrics defines whether the agent session metrics are
+	// labeled with the names of the agents
+	FlagAgentdAgentMetrics = "agentd-agent-metrics"
+	// FlagAgentdClockSkewThreshold defines the clock skew from which the
+	// clock of an agent is considered skewed
+	FlagAgentdClockSkewThreshold = "agentd-clock-skew-threshold"
+	// FlagAgentdClockSkewCorrection defines whether the timestamps of the
+	// events of the agents whose clock is skewed are corrected
+	FlagAgentdClockSkewCorrection = "agentd-clock-skew-correction"
+	// FlagAgentdMaxMessageSize defines the maximum size of the payloads of
+	// the messages received from the agents
+	FlagAgentdMaxMessageSize = "agentd-max-message-size"
+	// FlagAgentdMessageSizePolicy defines the policy applied to the messages
+	// exceeding the maximum message size
+	FlagAgentdMessageSizePolicy = "agentd-message-size-policy"
+	// FlagAgentdPublishBufferSize defines the number of messages of each
+	// agent buffered while the message bus fails to publish them
+	FlagAgentdPublishBufferSize = "agentd-publish-buffer-size"
+	// FlagAgentdPublishMaxBackoff defines the maximum time between the
+	// attempts to publish a buffered message
+	FlagAgentdPublishMaxBackoff = "agentd-publish-max-backoff"
+	// FlagEtcdMaintenanceInterval defines the time between the scheduled
+	// compactions and defragmentations of the store
+	FlagEtcdMaintenanceInterval = "etcd-maintenance-interval"
+	// FlagEtcdMaintenanceRetainedRevisions defines the number of the most
+	// recent revisions kept by the compactions of the store
+	FlagEtcdMaintenanceRetainedRevisions = "etcd-maintenance-retained-revisions"
+	// FlagEtcdStoreInfoInterval defines the time between the samples of the
+	// number and size of the keys of the store
+	FlagEtcdStoreInfoInterval = "etcd-store-info-interval"
+	// FlagAgentdPingInterval defines the time between the pings sent to the
+	// agents connected over WebSocket
+	FlagAgentdPingInterval = "agentd-ping-interval"
+	// FlagAgentdPongTimeout defines the time without pong after which the
+	// connection of an agent is closed
+	FlagAgentdPongTimeout = "agentd-pong-timeout"
+	// FlagAgentdMaxMissedPongs defines the number of consecutive pings
+	// without pong after which the connection of an agent is closed
+	FlagAgentdMaxMissedPongs = "agentd-max-missed-pongs"
+	// FlagAgentdDuplicateAgentPolicy defines the policy applied to the
+	// sessions of the agents connecting with the name of a connected agent
+	FlagAgentdDuplicateAgentPolicy = "agentd-duplicate-agent-policy"
+	// FlagAgentdResumeTokenTTL defines the time after their session stops
+	// during which the agents can resume it with their resume token
+	FlagAgentdResumeTokenTTL = "agentd-resume-token-ttl"
+	// FlagAgentdRejectSpoofedEvents defines whether the events submitted by
+	// the agents for entities other than themselves and their proxy entities
+	// are rejected
+	FlagAgentdRejectSpoofedEvents = "agentd-reject-spoofed-events"
+	// FlagAgentdEnrollmentCACertFile defines the certificate of the CA
+	// signing the TLS client certificates issued to the enrolling agents
+	FlagAgentdEnrollmentCACertFile = "agentd-enrollment-ca-cert-file"
+	// FlagAgentdEnrollmentCAKeyFile defines the private key of the CA
+	// signing the TLS client certificates issued to the enrolling agents
+	FlagAgentdEnrollmentCAKeyFile = "agentd-enrollment-ca-key-file"
+	// FlagAgentdEnrollmentValidity defines the validity of the TLS client
+	// certificates issued to the enrolling agents
+	FlagAgentdEnrollmentValidity = "agentd-enrollment-validity"
+	// FlagNotificationWebhooksAllowedOrigins defines the origins the
+	// notifications of the notification webhooks can be posted to
+	FlagNotificationWebhooksAllowedOrigins = "notification-webhooks-allowed-origins"
+	// FlagForwardURL defines the URL of the API of the upstream backend the
+	// events are forwarded to
+	FlagForwardURL = "forward-url"
+	// FlagForwardUsername defines the user of the upstream backend the
+	// events are forwarded as
+	FlagForwardUsername = "forward-username"
+	// FlagForwardPassword defines the password of the user of the upstream
+	// backend the events are forwarded as
+	FlagForwardPassword = "forward-password"
+	// FlagForwardNamespaces defines the namespaces of the forwarded events
+	FlagForwardNamespaces = "forward-namespaces"
+	// FlagForwardBufferSize defines the number of events buffered while the
+	// upstream backend is unreachable
+	FlagForwardBufferSize = "forward-buffer-size"
+	// FlagForwardMaxBackoff defines the maximum time between the attempts to
+	// forward an event
+	FlagForwardMaxBackoff = "forward-max-backoff"
+	// FlagForwardTrustedCAFile defines the CA certificates trusted to verify
+	// the upstream backend
+	FlagForwardTrustedCAFile = "forward-trusted-ca-file"
+	// FlagForwardInsecureSkipTLSVerify defines whether the certificate of the
+	// upstream backend is verified
+	FlagForwardInsecureSkipTLSVerify = "forward-insecure-skip-tls-verify"
+	// FlagWarmupWindow defines the time after the backend starts during which
+	// the checks are not scheduled and the keepalive and check TTL failures
+	// are not processed
+	FlagWarmupWindow = "warmup-window"
 )
 
 // Config specifies a Backend configuration.
@@ -36,12 +171,19 @@ type Config struct {
 	CacheDir string
 
 	// Agentd Configuration
-	AgentHost string
-	AgentPort int
+	AgentHost             string
+	AgentPort             int
+	AgentCompression      bool
+	AgentCompressionLevel int
+	AgentGRPCPort         int
+	AgentClientCertAuth   bool
 
 	// Apid Configuration
 	APIListenAddress string
 	APIURL           string
+	DebugAPI         bool
+	APIFilterLists   bool
+	APIDenialDetails string
 
 	// Dashboardd Configuration
 	DashboardHost        string
@@ -52,6 +194,14 @@ type Config struct {
 	// Pipelined Configuration
 	DeregistrationHandler string
 
+	// Schedulerd Configuration, the clock of the check schedulers is the
+	// timeproxy clock if nil
+	SchedulerClock schedulerd.Clock
+
+	// Tessend Configuration
+	TessenExportFile       string
+	TessenExportPrometheus bool
+
 	// Etcd configuration
 	EtcdAdvertiseClientURLs      []string
 	EtcdInitialAdvertisePeerURLs []string
@@ -69,6 +219,11 @@ type Config struct {
 	EtcdCipherSuites      []string
 	EtcdMaxRequestBytes   uint
 	EtcdQuotaBackendBytes int64
+	EtcdLightweight       bool
 
 	TLS *types.TLSOptions
+
+	// Effective is the effective configuration of the backend, with the source
+	// of each setting, exposed by the API.
+	Effective *corev2.BackendConfig
 }
@@ -0,0 +1,88 @@
+package backend
+
+import (
+	"sort"
+	"strings"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// sensitiveSettingWords are the words of the names of the settings whose
+// values are redacted from the effective configuration.
+var sensitiveSettingWords = []string{"password", "secret", "token"}
+
+// NewEffectiveConfig returns the effective configuration of the backend, made
+// of the value and source of each of the flags, read from v. The configFile
+// is the path of the configuration file read into v, if any. The deprecated
+// flags are left out, and the sensitive values are redacted.
+func NewEffectiveConfig(v *viper.Viper, flags *pflag.FlagSet, configFile string) *corev2.BackendConfig {
+	config := &corev2.BackendConfig{ConfigFile: configFile}
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Deprecated != "" {
+			return
+		}
+		setting := &corev2.BackendSetting{
+			Name:   flag.Name,
+			Value:  settingValue(v, flag),
+			Source: corev2.SettingSourceDefault,
+		}
+		if flag.Changed {
+			setting.Source = corev2.SettingSourceFlag
+		} else if v.InConfig(flag.Name) {
+			setting.Source = corev2.SettingSourceFile
+		}
+		if isSensitiveSetting(flag.Name) && !isZeroSetting(setting.Value) {
+			setting.Value = corev2.Redacted
+			setting.Redacted = true
+		}
+		config.Settings = append(config.Settings, setting)
+	})
+	sort.Slice(config.Settings, func(i, j int) bool {
+		return config.Settings[i].Name < config.Settings[j].Name
+	})
+	return config
+}
+
+// settingValue returns the value of the flag read from v, converted to the
+// type of the flag since the values of the flags, defaults and configuration
+// file can be of different types.
+func settingValue(v *viper.Viper, flag *pflag.Flag) interface{} {
+	switch flag.Value.Type() {
+	case "bool":
+		return v.GetBool(flag.Name)
+	case "int", "int32", "int64":
+		return v.GetInt64(flag.Name)
+	case "uint", "uint32", "uint64":
+		return v.GetUint64(flag.Name)
+	case "duration":
+		return v.GetDuration(flag.Name).String()
+	case "stringSlice":
+		return v.GetStringSlice(flag.Name)
+	default:
+		return v.GetString(flag.Name)
+	}
+}
+
+func isSensitiveSetting(name string) bool {
+	for _, word := range strings.Split(name, "-") {
+		for _, sensitive := range sensitiveSettingWords {
+			if word == sensitive {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isZeroSetting(value interface{}) bool {
+	switch value := value.(type) {
+	case string:
+		return value == ""
+	case []string:
+		return len(value) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,60 @@
+package backend
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEffectiveConfig(t *testing.T) {
+	v := viper.New()
+	v.SetDefault("agent-port", 8081)
+	v.SetDefault("agentd-drain-window", time.Duration(0))
+	v.SetDefault("etcd-initial-cluster-token", "")
+	v.SetDefault("eventd-workers", 100)
+	v.SetDefault("etcd-listen-client-urls", []string{"http://127.0.0.1:2379"})
+
+	flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+	flags.Int("agent-port", v.GetInt("agent-port"), "")
+	flags.Duration("agentd-drain-window", v.GetDuration("agentd-drain-window"), "")
+	flags.String("etcd-initial-cluster-token", v.GetString("etcd-initial-cluster-token"), "")
+	flags.Int("eventd-workers", v.GetInt("eventd-workers"), "")
+	flags.StringSlice("etcd-listen-client-urls", v.GetStringSlice("etcd-listen-client-urls"), "")
+	flags.Int("api-port", 8080, "")
+	require.NoError(t, flags.MarkDeprecated("api-port", "deprecated"))
+	require.NoError(t, flags.Parse([]string{"--agent-port", "9081", "--agentd-drain-window", "1m"}))
+	require.NoError(t, v.BindPFlags(flags))
+
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(bytes.NewBufferString("etcd-initial-cluster-token: s3cr3t\neventd-workers: 10\n")))
+
+	config := NewEffectiveConfig(v, flags, "/etc/sensu/backend.yml")
+	assert.Equal(t, &corev2.BackendConfig{
+		ConfigFile: "/etc/sensu/backend.yml",
+		Settings: []*corev2.BackendSetting{
+			{Name: "agent-port", Value: int64(9081), Source: corev2.SettingSourceFlag},
+			{Name: "agentd-drain-window", Value: "1m0s", Source: corev2.SettingSourceFlag},
+			{Name: "etcd-initial-cluster-token", Value: corev2.Redacted, Source: corev2.SettingSourceFile, Redacted: true},
+			{Name: "etcd-listen-client-urls", Value: []string{"http://127.0.0.1:2379"}, Source: corev2.SettingSourceDefault},
+			{Name: "eventd-workers", Value: int64(10), Source: corev2.SettingSourceFile},
+		},
+	}, config)
+}
+
+func TestNewEffectiveConfigUnsetSecret(t *testing.T) {
+	v := viper.New()
+	flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+	flags.String("etcd-initial-cluster-token", "", "")
+	require.NoError(t, v.BindPFlags(flags))
+
+	config := NewEffectiveConfig(v, flags, "")
+	require.Len(t, config.Settings, 1)
+	assert.Equal(t, "", config.Settings[0].Value)
+	assert.False(t, config.Settings[0].Redacted)
+}
@@ -39,6 +39,11 @@ const (
 	// DefaultQuotaBackendBytes is the default database size limit for etcd
 	// databases (4 GB)
 	DefaultQuotaBackendBytes int64 = (1 << 32)
+
+	// LightweightSnapshotCount is the number of committed transactions that
+	// trigger a snapshot in lightweight mode. Raft entries are kept in memory
+	// until the next snapshot, so a lower count reduces the memory overhead.
+	LightweightSnapshotCount = 5000
 )
 
 func init() {
@@ -64,6 +69,10 @@ type Config struct {
 
 	MaxRequestBytes   uint
 	QuotaBackendBytes int64
+
+	// Lightweight trims the embedded etcd for small, single-node installs,
+	// reducing its memory and disk overhead at the expense of clustering.
+	Lightweight bool
 }
 
 // TLSInfo wraps etcd transport TLSInfo
@@ -116,6 +125,29 @@ func (e *Etcd) GetClusterVersion() string {
 	return e.etcd.Server.ClusterVersion().String()
 }
 
+// lightweightConfig configures the embedded etcd for the lightweight mode,
+// which is only supported by single-node clusters. The store is still backed
+// by etcd: the rings, queues, liveness switches and resource caches use the
+// etcd client directly, so an etcd-less store is not supported. The database
+// size limit is left as configured, since lowering it would raise NOSPACE
+// alarms on installs outgrowing it.
+func lightweightConfig(cfg *embed.Config, config *Config) error {
+	members, err := etcdTypes.NewURLsMap(config.InitialCluster)
+	if err != nil {
+		return fmt.Errorf("invalid initial cluster: %s", err)
+	}
+	if len(members) > 1 {
+		return errors.New("the lightweight mode only supports single-node clusters")
+	}
+
+	cfg.SnapCount = LightweightSnapshotCount
+	cfg.MaxSnapFiles = 1
+	cfg.MaxWalFiles = 1
+	cfg.EnableV2 = false
+
+	return nil
+}
+
 // NewEtcd returns a new, configured, and running Etcd. The running Etcd will
 // panic on error. The calling goroutine should recover() from the panic and
 // shutdown accordingly. Callers must also ensure that the running Etcd is
@@ -183,6 +215,12 @@ func NewEtcd(config *Config) (*Etcd, error) {
 	cfg.QuotaBackendBytes = config.QuotaBackendBytes
 	cfg.MaxRequestBytes = config.MaxRequestBytes
 
+	if config.Lightweight {
+		if err := lightweightConfig(cfg, config); err != nil {
+			return nil, err
+		}
+	}
+
 	capnslog.SetFormatter(NewLogrusFormatter())
 
 	e, err := embed.StartEtcd(cfg)
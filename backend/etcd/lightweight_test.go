@@ -0,0 +1,39 @@
+package etcd
+
+import (
+	"testing"
+
+	"github.com/coreos/etcd/embed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLightweightConfig(t *testing.T) {
+	config := NewConfig()
+	config.InitialCluster = "default=http://127.0.0.1:2380"
+	cfg := embed.NewConfig()
+	cfg.QuotaBackendBytes = config.QuotaBackendBytes
+
+	require.NoError(t, lightweightConfig(cfg, config))
+	assert.Equal(t, uint64(LightweightSnapshotCount), cfg.SnapCount)
+	assert.Equal(t, DefaultQuotaBackendBytes, cfg.QuotaBackendBytes)
+	assert.False(t, cfg.EnableV2)
+}
+
+func TestLightweightConfigCustomQuota(t *testing.T) {
+	config := NewConfig()
+	config.InitialCluster = "default=http://127.0.0.1:2380"
+	config.QuotaBackendBytes = 1 << 30
+	cfg := embed.NewConfig()
+	cfg.QuotaBackendBytes = config.QuotaBackendBytes
+
+	require.NoError(t, lightweightConfig(cfg, config))
+	assert.Equal(t, int64(1<<30), cfg.QuotaBackendBytes)
+}
+
+func TestLightweightConfigCluster(t *testing.T) {
+	config := NewConfig()
+	config.InitialCluster = "one=http://127.0.0.1:2380,two=http://127.0.0.1:2381"
+
+	assert.Error(t, lightweightConfig(embed.NewConfig(), config))
+}
@@ -0,0 +1,469 @@
+package etcd
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/clientv3/concurrency"
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultMaintenanceRetainedRevisions is the default number of the most
+	// recent revisions kept by the compactions.
+	DefaultMaintenanceRetainedRevisions int64 = 1000
+)
+
+var (
+	maintenanceKeyPrefix = store.NewKeyBuilder("maintenance").Build()
+	maintenanceLockKey   = path.Join(maintenanceKeyPrefix, "lock")
+	maintenanceStatusKey = path.Join(maintenanceKeyPrefix, "status")
+
+	// maintenanceCheckPeriod is the maximum time between the checks of
+	// whether a scheduled maintenance is due.
+	maintenanceCheckPeriod = time.Minute
+
+	// maintenanceMemberDialTimeout is the timeout used to connect to a single
+	// cluster member.
+	maintenanceMemberDialTimeout = 5 * time.Second
+
+	// ErrMaintenanceRunning is returned when a maintenance is requested while
+	// another one is running on this backend.
+	ErrMaintenanceRunning = errors.New("a store maintenance is already running")
+)
+
+// MaintenanceConfig configures a Maintainer.
+type MaintenanceConfig struct {
+	// Client is the client of the etcd cluster.
+	Client *clientv3.Client
+
+	// TLS is the TLS configuration used to connect to every cluster member.
+	TLS *tls.Config
+
+	// Interval is the time between the scheduled maintenances. Maintenances
+	// only run when requested if Interval is 0.
+	Interval time.Duration
+
+	// RetainedRevisions is the number of the most recent revisions kept by
+	// the compactions, so the watchers lagging behind the current revision
+	// can still catch up. It defaults to DefaultMaintenanceRetainedRevisions.
+	RetainedRevisions int64
+
+	// QuotaBackendBytes is the database size limit of the cluster members.
+	// The NOSPACE alarms of the members whose database was defragmented
+	// below it are disarmed. It defaults to DefaultQuotaBackendBytes.
+	QuotaBackendBytes int64
+}
+
+// Maintainer compacts the store and defragments the cluster members, on
+// schedule or when requested. The members are defragmented one at a time,
+// the leader last, and only when all of them are healthy so the cluster keeps
+// its quorum. The NOSPACE alarms are then disarmed once the members are below
+// their quota. A lock in the store prevents the backends from running
+// maintenances concurrently.
+type Maintainer struct {
+	client   *clientv3.Client
+	tls      *tls.Config
+	interval time.Duration
+	retained int64
+	quota    int64
+	running  int32
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	errChan  chan error
+}
+
+// NewMaintainer creates a new Maintainer.
+func NewMaintainer(cfg MaintenanceConfig) (*Maintainer, error) {
+	if cfg.Interval < 0 {
+		return nil, fmt.Errorf("invalid maintenance interval %s, must not be negative", cfg.Interval)
+	}
+	if cfg.RetainedRevisions < 0 {
+		return nil, fmt.Errorf("invalid number of retained revisions %d, must not be negative", cfg.RetainedRevisions)
+	}
+	if cfg.RetainedRevisions == 0 {
+		cfg.RetainedRevisions = DefaultMaintenanceRetainedRevisions
+	}
+	if cfg.QuotaBackendBytes == 0 {
+		cfg.QuotaBackendBytes = DefaultQuotaBackendBytes
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Maintainer{
+		client:   cfg.Client,
+		tls:      cfg.TLS,
+		interval: cfg.Interval,
+		retained: cfg.RetainedRevisions,
+		quota:    cfg.QuotaBackendBytes,
+		ctx:      ctx,
+		cancel:   cancel,
+		errChan:  make(chan error, 1),
+	}, nil
+}
+
+// Start starts the scheduled maintenances, if enabled.
+func (m *Maintainer) Start() error {
+	if m.interval == 0 {
+		return nil
+	}
+	m.wg.Add(1)
+	go m.schedule()
+	return nil
+}
+
+// Stop stops the scheduled maintenances, and cancels the running one.
+func (m *Maintainer) Stop() error {
+	m.cancel()
+	m.wg.Wait()
+	return nil
+}
+
+// Err returns a channel on which terminal errors are reported.
+func (m *Maintainer) Err() <-chan error {
+	return m.errChan
+}
+
+// Name returns the daemon name.
+func (m *Maintainer) Name() string {
+	return "etcd-maintenance"
+}
+
+// Trigger runs a maintenance in the background. It returns
+// ErrMaintenanceRunning if a maintenance is already running on this backend.
+func (m *Maintainer) Trigger() error {
+	if !atomic.CompareAndSwapInt32(&m.running, 0, 1) {
+		return ErrMaintenanceRunning
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer atomic.StoreInt32(&m.running, 0)
+		if _, err := m.Run(m.ctx, corev2.StoreMaintenanceTriggerAPI, 0); err != nil {
+			logger.WithError(err).Error("store maintenance failed")
+		}
+	}()
+	return nil
+}
+
+// Status returns the last maintenance run by any backend, or nil if none was
+// run.
+func (m *Maintainer) Status(ctx context.Context) (*corev2.StoreMaintenance, error) {
+	resp, err := m.client.Get(ctx, maintenanceStatusKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	var status corev2.StoreMaintenance
+	if err := json.Unmarshal(resp.Kvs[0].Value, &status); err != nil {
+		return nil, fmt.Errorf("could not decode the store maintenance status: %s", err)
+	}
+	return &status, nil
+}
+
+// schedule runs the maintenances once their interval has elapsed since the
+// last one, whichever backend ran it.
+func (m *Maintainer) schedule() {
+	defer m.wg.Done()
+
+	period := m.interval
+	if period > maintenanceCheckPeriod {
+		period = maintenanceCheckPeriod
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			if !atomic.CompareAndSwapInt32(&m.running, 0, 1) {
+				continue
+			}
+			if _, err := m.Run(m.ctx, corev2.StoreMaintenanceTriggerSchedule, m.interval); err != nil {
+				logger.WithError(err).Error("scheduled store maintenance failed")
+			}
+			atomic.StoreInt32(&m.running, 0)
+		}
+	}
+}
+
+// Run compacts the store, defragments the cluster members and disarms their
+// NOSPACE alarms, while holding the maintenance lock. If interval is not 0,
+// the maintenance is skipped, and nil is returned, unless the last one started
+// at least interval ago.
+//
+// While a NOSPACE alarm is raised, the store rejects the writes needed by the
+// lock and the status, so the maintenance runs right away without them, until
+// the alarms are disarmed.
+func (m *Maintainer) Run(ctx context.Context, trigger string, interval time.Duration) (*corev2.StoreMaintenance, error) {
+	alarms, err := m.noSpaceAlarms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	noSpace := len(alarms) > 0
+
+	if noSpace {
+		logger.WithField("trigger", trigger).Warning("the store is out of space, maintaining it without the maintenance lock")
+	} else {
+		session, err := concurrency.NewSession(m.client, concurrency.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = session.Close()
+		}()
+		mutex := concurrency.NewMutex(session, maintenanceLockKey)
+		if err := mutex.Lock(ctx); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := mutex.Unlock(context.Background()); err != nil {
+				logger.WithError(err).Error("could not release the store maintenance lock")
+			}
+		}()
+	}
+
+	if interval > 0 && !noSpace {
+		last, err := m.Status(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if last != nil && time.Since(time.Unix(last.Started, 0)) < interval {
+			return nil, nil
+		}
+	}
+
+	status := &corev2.StoreMaintenance{
+		Trigger: trigger,
+		Started: time.Now().Unix(),
+	}
+	if !noSpace {
+		if err := m.saveStatus(ctx, status); err != nil {
+			return nil, err
+		}
+	}
+	logger.WithField("trigger", trigger).Info("starting store maintenance")
+
+	m.compact(ctx, status)
+	if err := m.defragment(ctx, status); err != nil {
+		return nil, err
+	}
+	m.disarm(ctx, status)
+
+	status.Finished = time.Now().Unix()
+	if err := m.saveStatus(ctx, status); err != nil {
+		return nil, err
+	}
+	logger.WithField("trigger", trigger).Info("finished store maintenance")
+	return status, nil
+}
+
+// compact compacts the store up to its current revision, minus the retained
+// revisions. Nothing is compacted while the store has fewer revisions.
+func (m *Maintainer) compact(ctx context.Context, status *corev2.StoreMaintenance) {
+	resp, err := m.client.Get(ctx, maintenanceStatusKey)
+	if err != nil {
+		status.CompactErr = err.Error()
+		return
+	}
+	revision := resp.Header.Revision - m.retained
+	if revision <= 0 {
+		return
+	}
+	status.CompactRevision = revision
+	_, err = m.client.Compact(ctx, revision, clientv3.WithCompactPhysical())
+	if err != nil && err != rpctypes.ErrCompacted {
+		logger.WithError(err).Error("could not compact the store")
+		status.CompactErr = err.Error()
+	}
+}
+
+// defragment defragments the cluster members one at a time, the leader last.
+// A member is skipped unless all of them are healthy.
+func (m *Maintainer) defragment(ctx context.Context, status *corev2.StoreMaintenance) error {
+	mList, err := m.client.MemberList(ctx)
+	if err != nil {
+		return err
+	}
+
+	healthy := true
+	members := make(map[uint64]*etcdserverpb.Member, len(mList.Members))
+	for _, member := range mList.Members {
+		members[member.ID] = member
+		maintenance := &corev2.MemberMaintenance{
+			MemberID: member.ID,
+			Name:     member.Name,
+		}
+		status.Members = append(status.Members, maintenance)
+
+		resp, err := m.memberStatus(ctx, member)
+		if err != nil {
+			logger.WithField("member", member.Name).WithError(err).Warning("could not get the cluster member status")
+			maintenance.Err = err.Error()
+			healthy = false
+			continue
+		}
+		maintenance.IsLeader = resp.Leader == member.ID
+		maintenance.DBSizeBefore = resp.DbSize
+	}
+
+	// Defragment the leader last, so the leadership doesn't move more than
+	// once
+	sort.SliceStable(status.Members, func(i, j int) bool {
+		return !status.Members[i].IsLeader && status.Members[j].IsLeader
+	})
+
+	for _, maintenance := range status.Members {
+		if !healthy {
+			if maintenance.Err == "" {
+				maintenance.Err = "skipped, not all the cluster members are healthy"
+			}
+			continue
+		}
+		member := members[maintenance.MemberID]
+		fields := logrus.Fields{"member": member.Name}
+		if err := m.defragmentMember(ctx, member); err != nil {
+			logger.WithFields(fields).WithError(err).Error("could not defragment the cluster member")
+			maintenance.Err = err.Error()
+		}
+
+		// Don't move on to the next member until this one serves requests
+		// again
+		resp, err := m.memberStatus(ctx, member)
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Error("cluster member unhealthy after its defragmentation")
+			if maintenance.Err == "" {
+				maintenance.Err = err.Error()
+			}
+			healthy = false
+			continue
+		}
+		maintenance.DBSizeAfter = resp.DbSize
+		logger.WithFields(fields).WithFields(logrus.Fields{
+			"db_size_before": maintenance.DBSizeBefore,
+			"db_size_after":  maintenance.DBSizeAfter,
+		}).Info("defragmented the cluster member")
+	}
+
+	return nil
+}
+
+// noSpaceAlarms returns the NOSPACE alarms raised by the cluster members.
+func (m *Maintainer) noSpaceAlarms(ctx context.Context) ([]*etcdserverpb.AlarmMember, error) {
+	resp, err := m.client.AlarmList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var alarms []*etcdserverpb.AlarmMember
+	for _, alarm := range resp.Alarms {
+		if alarm.Alarm == etcdserverpb.AlarmType_NOSPACE {
+			alarms = append(alarms, alarm)
+		}
+	}
+	return alarms, nil
+}
+
+// disarm disarms the NOSPACE alarms of the cluster members whose database was
+// defragmented below the quota. The alarms of the other members are kept, and
+// recorded in the status.
+func (m *Maintainer) disarm(ctx context.Context, status *corev2.StoreMaintenance) {
+	alarms, err := m.noSpaceAlarms(ctx)
+	if err != nil {
+		logger.WithError(err).Error("could not list the store alarms")
+		status.AlarmErr = err.Error()
+		return
+	}
+
+	for _, alarm := range alarms {
+		var maintenance *corev2.MemberMaintenance
+		for _, member := range status.Members {
+			if member.MemberID == alarm.MemberID {
+				maintenance = member
+				break
+			}
+		}
+		if maintenance == nil {
+			continue
+		}
+		maintenance.NoSpaceAlarm = true
+		if maintenance.DBSizeAfter == 0 || maintenance.DBSizeAfter >= m.quota {
+			continue
+		}
+
+		fields := logrus.Fields{"member": maintenance.Name}
+		_, err := m.client.AlarmDisarm(ctx, &clientv3.AlarmMember{
+			MemberID: alarm.MemberID,
+			Alarm:    alarm.Alarm,
+		})
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Error("could not disarm the NOSPACE alarm of the cluster member")
+			status.AlarmErr = err.Error()
+			continue
+		}
+		maintenance.NoSpaceAlarmDisarmed = true
+		logger.WithFields(fields).Info("disarmed the NOSPACE alarm of the cluster member")
+	}
+}
+
+// saveStatus stores the status of the maintenance.
+func (m *Maintainer) saveStatus(ctx context.Context, status *corev2.StoreMaintenance) error {
+	b, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	_, err = m.client.Put(ctx, maintenanceStatusKey, string(b))
+	return err
+}
+
+// memberClient returns a client connected to the given cluster member only.
+func (m *Maintainer) memberClient(member *etcdserverpb.Member) (*clientv3.Client, error) {
+	if len(member.ClientURLs) == 0 {
+		return nil, fmt.Errorf("cluster member %q has no client URL", member.Name)
+	}
+	return clientv3.New(clientv3.Config{
+		Endpoints:   member.ClientURLs,
+		DialTimeout: maintenanceMemberDialTimeout,
+		TLS:         m.tls,
+	})
+}
+
+// memberStatus returns the status of the given cluster member.
+func (m *Maintainer) memberStatus(ctx context.Context, member *etcdserverpb.Member) (*clientv3.StatusResponse, error) {
+	client, err := m.memberClient(member)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	return client.Status(ctx, member.ClientURLs[0])
+}
+
+// defragmentMember defragments the given cluster member.
+func (m *Maintainer) defragmentMember(ctx context.Context, member *etcdserverpb.Member) error {
+	client, err := m.memberClient(member)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	_, err = client.Defragment(ctx, member.ClientURLs[0])
+	return err
+}
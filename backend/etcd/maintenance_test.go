@@ -0,0 +1,110 @@
+// +build integration,!race
+
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintainerRun(t *testing.T) {
+	e, cleanup := NewTestEtcd(t)
+	defer cleanup()
+
+	client, err := e.NewClient()
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Create some history to compact
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		_, err := client.Put(ctx, "key", fmt.Sprintf("value%d", i))
+		require.NoError(t, err)
+	}
+
+	maintainer, err := NewMaintainer(MaintenanceConfig{Client: client, RetainedRevisions: 10})
+	require.NoError(t, err)
+
+	status, err := maintainer.Status(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, status)
+
+	status, err = maintainer.Run(ctx, corev2.StoreMaintenanceTriggerAPI, 0)
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	assert.Empty(t, status.CompactErr)
+	assert.NotZero(t, status.CompactRevision)
+
+	// The retained revisions are still readable
+	_, err = client.Get(ctx, "key", clientv3.WithRev(status.CompactRevision))
+	assert.NoError(t, err)
+	_, err = client.Get(ctx, "key", clientv3.WithRev(status.CompactRevision-1))
+	assert.Equal(t, rpctypes.ErrCompacted, err)
+	assert.NotZero(t, status.Finished)
+	require.Len(t, status.Members, 1)
+	assert.True(t, status.Members[0].IsLeader)
+	assert.Empty(t, status.Members[0].Err)
+	assert.NotZero(t, status.Members[0].DBSizeAfter)
+
+	saved, err := maintainer.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, status, saved)
+
+	// A scheduled maintenance is skipped until the interval elapsed
+	skipped, err := maintainer.Run(ctx, corev2.StoreMaintenanceTriggerSchedule, time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, skipped)
+}
+
+func TestMaintainerRunDisarmsNoSpaceAlarm(t *testing.T) {
+	e, cleanup := NewTestEtcd(t)
+	defer cleanup()
+
+	client, err := e.NewClient()
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	members, err := client.MemberList(ctx)
+	require.NoError(t, err)
+	require.Len(t, members.Members, 1)
+
+	_, err = etcdserverpb.NewMaintenanceClient(client.ActiveConnection()).Alarm(ctx, &etcdserverpb.AlarmRequest{
+		Action:   etcdserverpb.AlarmRequest_ACTIVATE,
+		MemberID: members.Members[0].ID,
+		Alarm:    etcdserverpb.AlarmType_NOSPACE,
+	})
+	require.NoError(t, err)
+
+	// The store rejects the writes while the alarm is raised
+	_, err = client.Put(ctx, "key", "value")
+	require.Equal(t, rpctypes.ErrNoSpace, err)
+
+	maintainer, err := NewMaintainer(MaintenanceConfig{Client: client})
+	require.NoError(t, err)
+
+	status, err := maintainer.Run(ctx, corev2.StoreMaintenanceTriggerSchedule, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	assert.Empty(t, status.AlarmErr)
+	require.Len(t, status.Members, 1)
+	assert.True(t, status.Members[0].NoSpaceAlarm)
+	assert.True(t, status.Members[0].NoSpaceAlarmDisarmed)
+
+	alarms, err := client.AlarmList(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, alarms.Alarms)
+
+	saved, err := maintainer.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, status, saved)
+}
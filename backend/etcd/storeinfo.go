@@ -0,0 +1,265 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	// DefaultStoreInfoInterval is the default time between the samples of the
+	// keys of the store.
+	DefaultStoreInfoInterval = 5 * time.Minute
+
+	// namespacesResource is the resource type of the namespaces in the keys
+	// of the store.
+	namespacesResource = "namespaces"
+)
+
+var (
+	// storeInfoPageSize is the number of keys read at once while sampling the
+	// store.
+	storeInfoPageSize int64 = 1000
+
+	storeKeys = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sensu_go_store_keys",
+			Help: "Number of keys in the store, by resource type and namespace",
+		},
+		[]string{"resource", "namespace"},
+	)
+
+	storeBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sensu_go_store_bytes",
+			Help: "Size of the keys and values in the store, by resource type and namespace",
+		},
+		[]string{"resource", "namespace"},
+	)
+)
+
+// StoreInfoConfig configures a StoreInfoSampler.
+type StoreInfoConfig struct {
+	// Client is the client of the etcd cluster.
+	Client *clientv3.Client
+
+	// Interval is the time between the samples. The store is not sampled if
+	// Interval is 0.
+	Interval time.Duration
+}
+
+// StoreInfoSampler periodically counts the keys of the store and sums their
+// size by resource type and namespace, and exports them as metrics, so the
+// resources filling the store quota can be found before it is exceeded.
+type StoreInfoSampler struct {
+	client   *clientv3.Client
+	interval time.Duration
+	mu       sync.Mutex
+	info     *corev2.StoreInfo
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	errChan  chan error
+}
+
+// NewStoreInfoSampler creates a new StoreInfoSampler.
+func NewStoreInfoSampler(cfg StoreInfoConfig) (*StoreInfoSampler, error) {
+	if cfg.Interval < 0 {
+		return nil, fmt.Errorf("invalid store info interval %s, must not be negative", cfg.Interval)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StoreInfoSampler{
+		client:   cfg.Client,
+		interval: cfg.Interval,
+		ctx:      ctx,
+		cancel:   cancel,
+		errChan:  make(chan error, 1),
+	}, nil
+}
+
+// Start starts sampling the store, if enabled.
+func (s *StoreInfoSampler) Start() error {
+	if s.interval == 0 {
+		return nil
+	}
+	_ = prometheus.Register(storeKeys)
+	_ = prometheus.Register(storeBytes)
+	s.wg.Add(1)
+	go s.sample()
+	return nil
+}
+
+// Stop stops sampling the store.
+func (s *StoreInfoSampler) Stop() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// Err returns a channel on which terminal errors are reported.
+func (s *StoreInfoSampler) Err() <-chan error {
+	return s.errChan
+}
+
+// Name returns the daemon name.
+func (s *StoreInfoSampler) Name() string {
+	return "etcd-store-info"
+}
+
+// StoreInfo returns the last sample of the store, or nil if the store was not
+// sampled yet.
+func (s *StoreInfoSampler) StoreInfo() *corev2.StoreInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.info
+}
+
+// sample samples the store at every interval, the first time right away.
+func (s *StoreInfoSampler) sample() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		info, err := s.Sample(s.ctx)
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return
+			}
+			logger.WithError(err).Error("could not sample the store")
+		} else {
+			s.mu.Lock()
+			s.info = info
+			s.mu.Unlock()
+			updateStoreMetrics(info)
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sample counts the keys of the store and sums their size by resource type
+// and namespace. The keys are read by pages, all at the same revision.
+func (s *StoreInfoSampler) Sample(ctx context.Context) (*corev2.StoreInfo, error) {
+	info := &corev2.StoreInfo{Sampled: time.Now().Unix()}
+
+	endpoints := s.client.Endpoints()
+	if len(endpoints) > 0 {
+		status, err := s.client.Status(ctx, endpoints[0])
+		if err != nil {
+			logger.WithError(err).Warning("could not get the size of the store database")
+		} else {
+			info.DBSize = status.DbSize
+		}
+	}
+
+	prefix := store.Root + "/"
+	end := clientv3.GetPrefixRangeEnd(prefix)
+	key := prefix
+	resources := map[[2]string]*corev2.ResourceStoreInfo{}
+	namespaces := map[string]bool{}
+
+	for {
+		opts := []clientv3.OpOption{
+			clientv3.WithRange(end),
+			clientv3.WithLimit(storeInfoPageSize),
+		}
+		if info.Revision != 0 {
+			opts = append(opts, clientv3.WithRev(info.Revision))
+		}
+		resp, err := s.client.Get(ctx, key, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if info.Revision == 0 {
+			info.Revision = resp.Header.Revision
+		}
+
+		for _, kv := range resp.Kvs {
+			parts := strings.Split(strings.TrimPrefix(string(kv.Key), prefix), "/")
+			id := [2]string{parts[0]}
+			if len(parts) > 2 {
+				id[1] = parts[1]
+			} else if parts[0] == namespacesResource && len(parts) == 2 {
+				namespaces[parts[1]] = true
+			}
+			resource, ok := resources[id]
+			if !ok {
+				resource = &corev2.ResourceStoreInfo{Resource: id[0], Namespace: id[1]}
+				resources[id] = resource
+			}
+			size := int64(len(kv.Key) + len(kv.Value))
+			resource.Keys++
+			resource.Size += size
+			info.Keys++
+			info.Size += size
+		}
+
+		if !resp.More || len(resp.Kvs) == 0 {
+			break
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+
+	info.Resources = mergeUnknownNamespaces(resources, namespaces)
+	return info, nil
+}
+
+// mergeUnknownNamespaces merges the keys whose second component is not a
+// namespace, like the ones of the resources that are not namespaced, into
+// their resource type, and returns the resources sorted by size, the largest
+// first.
+func mergeUnknownNamespaces(resources map[[2]string]*corev2.ResourceStoreInfo, namespaces map[string]bool) []*corev2.ResourceStoreInfo {
+	merged := make(map[[2]string]*corev2.ResourceStoreInfo, len(resources))
+	for id, resource := range resources {
+		if !namespaces[id[1]] {
+			id[1] = ""
+			resource.Namespace = ""
+		}
+		if existing, ok := merged[id]; ok {
+			existing.Keys += resource.Keys
+			existing.Size += resource.Size
+			continue
+		}
+		merged[id] = resource
+	}
+
+	result := make([]*corev2.ResourceStoreInfo, 0, len(merged))
+	for _, resource := range merged {
+		result = append(result, resource)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Size != result[j].Size {
+			return result[i].Size > result[j].Size
+		}
+		if result[i].Resource != result[j].Resource {
+			return result[i].Resource < result[j].Resource
+		}
+		return result[i].Namespace < result[j].Namespace
+	})
+	return result
+}
+
+// updateStoreMetrics exports the sample of the store as metrics. The previous
+// sample is discarded, so the deleted namespaces are not reported anymore.
+func updateStoreMetrics(info *corev2.StoreInfo) {
+	storeKeys.Reset()
+	storeBytes.Reset()
+	for _, resource := range info.Resources {
+		storeKeys.WithLabelValues(resource.Resource, resource.Namespace).Set(float64(resource.Keys))
+		storeBytes.WithLabelValues(resource.Resource, resource.Namespace).Set(float64(resource.Size))
+	}
+}
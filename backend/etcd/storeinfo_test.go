@@ -0,0 +1,65 @@
+//go:build integration && !race
+// +build integration,!race
+
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreInfoSamplerSample(t *testing.T) {
+	e, cleanup := NewTestEtcd(t)
+	defer cleanup()
+
+	client, err := e.NewClient()
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	keys := map[string]string{
+		"/sensu.io/namespaces/default":           "{}",
+		"/sensu.io/checks/default/check1":        "check1",
+		"/sensu.io/checks/default/check2":        "check2",
+		"/sensu.io/events/default/entity/check1": "event",
+		"/sensu.io/users/admin":                  "admin",
+		"/sensu.io/rings/subscription/items/a":   "a",
+		"/other/key":                             "ignored",
+	}
+	for key, value := range keys {
+		_, err := client.Put(ctx, key, value)
+		require.NoError(t, err)
+	}
+
+	// Read the keys over several pages
+	defer func(size int64) { storeInfoPageSize = size }(storeInfoPageSize)
+	storeInfoPageSize = 2
+
+	sampler, err := NewStoreInfoSampler(StoreInfoConfig{Client: client})
+	require.NoError(t, err)
+	assert.Nil(t, sampler.StoreInfo())
+
+	info, err := sampler.Sample(ctx)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Revision)
+	assert.NotZero(t, info.DBSize)
+	assert.Equal(t, int64(6), info.Keys)
+
+	got := map[[2]string]int64{}
+	for _, resource := range info.Resources {
+		got[[2]string{resource.Resource, resource.Namespace}] = resource.Keys
+	}
+	assert.Equal(t, map[[2]string]int64{
+		{"namespaces", ""}:    1,
+		{"checks", "default"}: 2,
+		{"events", "default"}: 1,
+		{"users", ""}:         1,
+		{"rings", ""}:         1,
+	}, got)
+	require.NotEmpty(t, info.Resources)
+	assert.Equal(t, "checks", info.Resources[0].Resource)
+	assert.Equal(t, int64(len("/sensu.io/checks/default/check1")+len("check1"))*2, info.Resources[0].Size)
+}
@@ -14,6 +14,7 @@ import (
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/liveness"
 	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/readiness"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/backend/store/cache"
 	"github.com/sirupsen/logrus"
@@ -32,6 +33,14 @@ const (
 
 	// EventsProcessedLabelSuccess is the name of the label used to count events processed successfully.
 	EventsProcessedLabelSuccess = "success"
+
+	// EventQueueDepthGauge is the name of the prometheus gauge used to report
+	// the number of events waiting to be processed.
+	EventQueueDepthGauge = "sensu_go_event_queue_depth"
+
+	// EventQueueCapacityGauge is the name of the prometheus gauge used to
+	// report the number of events that can wait to be processed.
+	EventQueueCapacityGauge = "sensu_go_event_queue_capacity"
 )
 
 var (
@@ -47,6 +56,23 @@ var (
 		},
 		[]string{EventsProcessedLabelName},
 	)
+
+	// EventQueueDepth reports the number of events waiting to be processed.
+	EventQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: EventQueueDepthGauge,
+			Help: "The number of events waiting to be processed",
+		},
+	)
+
+	// EventQueueCapacity reports the number of events that can wait to be
+	// processed.
+	EventQueueCapacity = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: EventQueueCapacityGauge,
+			Help: "The number of events that can wait to be processed",
+		},
+	)
 )
 
 // Eventd handles incoming sensu events and stores them in etcd.
@@ -66,6 +92,8 @@ type Eventd struct {
 	wg              *sync.WaitGroup
 	Logger          Logger
 	silencedCache   *cache.Resource
+	sampler         *sampler
+	readiness       *readiness.Gate
 }
 
 // Option is a functional option.
@@ -80,6 +108,10 @@ type Config struct {
 	Client          *clientv3.Client
 	BufferSize      int
 	WorkerCount     int
+
+	// Readiness postpones the check TTL failures until the backend is warm.
+	// The failures are processed at once if nil.
+	Readiness *readiness.Gate
 }
 
 // New creates a new Eventd.
@@ -103,6 +135,8 @@ func New(ctx context.Context, c Config, opts ...Option) (*Eventd, error) {
 		wg:              &sync.WaitGroup{},
 		mu:              &sync.Mutex{},
 		Logger:          &RawLogger{},
+		sampler:         newSampler(),
+		readiness:       c.Readiness,
 	}
 
 	e.ctx, e.cancel = context.WithCancel(ctx)
@@ -119,6 +153,12 @@ func New(ctx context.Context, c Config, opts ...Option) (*Eventd, error) {
 	}
 
 	_ = prometheus.Register(EventsProcessed)
+	_ = prometheus.Register(CheckLatency)
+	_ = prometheus.Register(CheckSchedulingLag)
+	_ = prometheus.Register(EventQueueDepth)
+	_ = prometheus.Register(EventQueueCapacity)
+	_ = prometheus.Register(EventsSampled)
+	EventQueueCapacity.Set(float64(c.BufferSize))
 
 	return e, nil
 }
@@ -137,6 +177,7 @@ func (e *Eventd) Start() error {
 		return err
 	}
 	e.startHandlers()
+	e.readiness.Done(e.Name())
 
 	return nil
 }
@@ -176,6 +217,7 @@ func (e *Eventd) startHandlers() {
 						return
 					}
 
+					EventQueueDepth.Set(float64(len(e.eventChan)))
 					if err := e.handleMessage(msg); err != nil {
 						logger.WithError(err).Error("eventd - error handling event")
 					}
@@ -214,8 +256,27 @@ func (e *Eventd) handleMessage(msg interface{}) error {
 		return e.bus.Publish(messaging.TopicEvent, event)
 	}
 
+	// The OK metric events sampled away are neither stored nor published,
+	// but they still prove that the check is executed.
+	if !e.sampler.keep(event) {
+		EventsSampled.WithLabelValues(event.Entity.Namespace, event.Check.Name).Inc()
+		if event.Check.Ttl > 0 {
+			switches := e.livenessFactory("eventd", e.dead, e.alive, logger)
+			return switches.Alive(context.TODO(), eventKey(event), int64(event.Check.Ttl))
+		}
+		return nil
+	}
+
 	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, event.Entity.Namespace)
 
+	// Record the time the event was processed. Events that didn't go through
+	// an agent session, like the ones created with the API, are received now.
+	event.Check.Processed = time.Now().Unix()
+	if event.Check.Received == 0 {
+		event.Check.Received = event.Check.Processed
+	}
+	observeCheckLatency(event.Check)
+
 	// Add any silenced subscriptions to the event
 	getSilenced(ctx, event, e.silencedCache)
 
@@ -291,6 +352,12 @@ func (e *Eventd) dead(key string, prev liveness.State, leader bool) (bury bool)
 		"entity":    entity,
 		"namespace": namespace})
 
+	if !e.readiness.Ready() {
+		// The dead callback is called again at every TTL interval
+		lager.Info("check TTL expired while the backend is warming up, postponing the failure")
+		return false
+	}
+
 	lager.Warn("check TTL expired")
 
 	// NOTE: To support check TTL for round robin scheduling, load all events
@@ -56,6 +56,7 @@ func newEventd(store store.Store, bus messaging.MessageBus, livenessFactory live
 		Logger:          &RawLogger{},
 		workerCount:     5,
 		silencedCache:   &cache.Resource{},
+		sampler:         newSampler(),
 	}
 }
 
@@ -256,6 +257,7 @@ func TestCheckTTL(t *testing.T) {
 				wg:              &sync.WaitGroup{},
 				Logger:          &RawLogger{},
 				silencedCache:   &cache.Resource{},
+				sampler:         newSampler(),
 			}
 			var err error
 			e.bus, err = messaging.NewWizardBus(messaging.WizardBusConfig{})
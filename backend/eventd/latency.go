@@ -0,0 +1,77 @@
+package eventd
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+const (
+	// CheckLatencyHistogramVec is the name of the prometheus histogram vec
+	// used to measure the latency of check results.
+	CheckLatencyHistogramVec = "sensu_go_check_latency_seconds"
+
+	// CheckSchedulingLagGauge is the name of the prometheus gauge used to
+	// report the last scheduling latency of check results.
+	CheckSchedulingLagGauge = "sensu_go_check_scheduling_lag_seconds"
+
+	// CheckLatencyLabelName is the name of the label which stores the stage
+	// of the latency measured.
+	CheckLatencyLabelName = "stage"
+
+	// CheckLatencyStageScheduling is the stage between the issue of a check
+	// request by the scheduler and the start of the check execution by the
+	// agent.
+	CheckLatencyStageScheduling = "scheduling"
+
+	// CheckLatencyStageAgent is the stage between the start of the check
+	// execution and the reception of its result by the backend. It includes
+	// the duration of the check execution.
+	CheckLatencyStageAgent = "agent"
+
+	// CheckLatencyStagePipeline is the stage between the reception of a
+	// check result by the backend and the processing of its event.
+	CheckLatencyStagePipeline = "pipeline"
+)
+
+// CheckLatency measures the latency of check results, per stage.
+var CheckLatency = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    CheckLatencyHistogramVec,
+		Help:    "The latency of check results, per stage",
+		Buckets: []float64{0, 1, 2, 5, 10, 30, 60, 120, 300, 600},
+	},
+	[]string{CheckLatencyLabelName},
+)
+
+// CheckSchedulingLag reports the last scheduling latency of check results,
+// giving the current lag of the scheduling.
+var CheckSchedulingLag = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: CheckSchedulingLagGauge,
+		Help: "The last scheduling latency of check results",
+	},
+)
+
+// observeCheckLatency records the latency of every stage of the check that
+// has both of its timestamps set. Negative latencies, caused by clock skew
+// between the backends and the agents, are ignored.
+func observeCheckLatency(check *corev2.Check) {
+	stages := []struct {
+		name       string
+		start, end int64
+	}{
+		{CheckLatencyStageScheduling, check.Issued, check.Executed},
+		{CheckLatencyStageAgent, check.Executed, check.Received},
+		{CheckLatencyStagePipeline, check.Received, check.Processed},
+	}
+	for _, stage := range stages {
+		if stage.start == 0 || stage.end == 0 || stage.end < stage.start {
+			continue
+		}
+		latency := float64(stage.end - stage.start)
+		CheckLatency.WithLabelValues(stage.name).Observe(latency)
+		if stage.name == CheckLatencyStageScheduling {
+			CheckSchedulingLag.Set(latency)
+		}
+	}
+}
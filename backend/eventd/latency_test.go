@@ -0,0 +1,62 @@
+package eventd
+
+import (
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+func sampleCount(t *testing.T, stage string) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	observer, err := CheckLatency.GetMetricWithLabelValues(stage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := observer.(interface{ Write(*dto.Metric) error }).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestObserveCheckLatency(t *testing.T) {
+	stages := []string{CheckLatencyStageScheduling, CheckLatencyStageAgent, CheckLatencyStagePipeline}
+
+	tests := []struct {
+		name     string
+		check    corev2.Check
+		expected []uint64
+	}{
+		{
+			name:     "all stages",
+			check:    corev2.Check{Issued: 10, Executed: 12, Received: 15, Processed: 16},
+			expected: []uint64{1, 1, 1},
+		},
+		{
+			name:     "unscheduled check",
+			check:    corev2.Check{Executed: 12, Received: 15, Processed: 16},
+			expected: []uint64{0, 1, 1},
+		},
+		{
+			name:     "clock skew",
+			check:    corev2.Check{Issued: 10, Executed: 8, Received: 9, Processed: 9},
+			expected: []uint64{0, 1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := make([]uint64, len(stages))
+			for i, stage := range stages {
+				before[i] = sampleCount(t, stage)
+			}
+			observeCheckLatency(&tt.check)
+			for i, stage := range stages {
+				if got := sampleCount(t, stage) - before[i]; got != tt.expected[i] {
+					t.Errorf("stage %s: got %d observations, want %d", stage, got, tt.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package eventd
+
+import (
+	"path"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/util/lru"
+)
+
+const (
+	// EventsSampledCounterVec is the name of the prometheus counter vec used
+	// to count the events sampled away.
+	EventsSampledCounterVec = "sensu_go_events_sampled"
+)
+
+// EventsSampled counts the OK metric events sampled away, per namespace and
+// check.
+var EventsSampled = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: EventsSampledCounterVec,
+		Help: "The total number of OK metric events sampled away before storage",
+	},
+	[]string{"namespace", "check"},
+)
+
+// samplerMaxStates is the number of entity checks tracked by the sampler. The
+// least recently seen ones are forgotten, and their next event is kept.
+const samplerMaxStates = 100000
+
+// sampler samples the OK metric events of the checks with a metric sample
+// rate. It tracks the last status and the number of events sampled away since
+// the last event kept of each entity and check.
+type sampler struct {
+	mu     sync.Mutex
+	states *lru.Cache
+}
+
+type sampleState struct {
+	status  uint32
+	skipped uint32
+}
+
+func newSampler() *sampler {
+	return &sampler{states: lru.New(samplerMaxStates)}
+}
+
+// keep returns whether the event must be stored and published. Every event is
+// kept, except the OK events carrying metrics of the checks with a metric
+// sample rate greater than 1, of which 1 in the sample rate is kept. The
+// events changing the status of the check are always kept, as well as the
+// first event of the check seen by the backend. The events sampled away still
+// refresh the TTL of their check, since they prove that it is executed.
+func (s *sampler) keep(event *corev2.Event) bool {
+	key := path.Join(event.Entity.Namespace, event.Check.Name, event.Entity.Name)
+	rate := event.Check.MetricSampleRate
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rate <= 1 || !event.HasMetrics() {
+		s.states.Remove(key)
+		return true
+	}
+
+	value, ok := s.states.Get(key)
+	if !ok || value.(*sampleState).status != event.Check.Status || event.Check.Status != 0 {
+		s.states.Add(key, &sampleState{status: event.Check.Status})
+		return true
+	}
+	state := value.(*sampleState)
+	state.skipped++
+	if state.skipped >= rate {
+		state.skipped = 0
+		return true
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package eventd
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store/cache"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func sampledEvent(status, rate uint32) *corev2.Event {
+	event := corev2.FixtureEvent("entity", "check")
+	event.Check.Status = status
+	event.Check.MetricSampleRate = rate
+	event.Metrics = corev2.FixtureMetrics()
+	return event
+}
+
+func TestSamplerKeep(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []*corev2.Event
+		expected []bool
+	}{
+		{
+			name:     "no sample rate",
+			events:   []*corev2.Event{sampledEvent(0, 0), sampledEvent(0, 0), sampledEvent(0, 1)},
+			expected: []bool{true, true, true},
+		},
+		{
+			name:     "1 in 3 OK events",
+			events:   []*corev2.Event{sampledEvent(0, 3), sampledEvent(0, 3), sampledEvent(0, 3), sampledEvent(0, 3), sampledEvent(0, 3)},
+			expected: []bool{true, false, false, true, false},
+		},
+		{
+			name:     "status changes",
+			events:   []*corev2.Event{sampledEvent(0, 3), sampledEvent(0, 3), sampledEvent(2, 3), sampledEvent(2, 3), sampledEvent(0, 3), sampledEvent(0, 3)},
+			expected: []bool{true, false, true, true, true, false},
+		},
+		{
+			name:     "events without metrics",
+			events:   []*corev2.Event{sampledEvent(0, 3), corev2.FixtureEvent("entity", "check"), sampledEvent(0, 3)},
+			expected: []bool{true, true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSampler()
+			for i, event := range tt.events {
+				assert.Equal(t, tt.expected[i], s.keep(event), "event %d", i)
+			}
+		})
+	}
+}
+
+func TestSamplerKeepPerEntity(t *testing.T) {
+	s := newSampler()
+	other := sampledEvent(0, 2)
+	other.Entity.Name = "other"
+
+	assert.True(t, s.keep(sampledEvent(0, 2)))
+	assert.True(t, s.keep(other))
+	assert.False(t, s.keep(sampledEvent(0, 2)))
+	assert.False(t, s.keep(other))
+}
+
+func TestSamplerMaxStates(t *testing.T) {
+	s := newSampler()
+	for i := 0; i < samplerMaxStates+10; i++ {
+		event := sampledEvent(0, 2)
+		event.Entity.Name = fmt.Sprintf("entity%d", i)
+		assert.True(t, s.keep(event))
+	}
+	assert.Equal(t, samplerMaxStates, s.states.Len())
+}
+
+func eventsSampled(t *testing.T, namespace, check string) float64 {
+	t.Helper()
+	var metric dto.Metric
+	counter, err := EventsSampled.GetMetricWithLabelValues(namespace, check)
+	require.NoError(t, err)
+	require.NoError(t, counter.Write(&metric))
+	return metric.GetCounter().GetValue()
+}
+
+func TestHandleMessageSampling(t *testing.T) {
+	store := &mockstore.MockStore{}
+	switches := &mockSwitchSet{}
+	e := &Eventd{
+		store:           store,
+		eventStore:      store,
+		livenessFactory: newFakeFactory(switches),
+		workerCount:     1,
+		wg:              &sync.WaitGroup{},
+		Logger:          &RawLogger{},
+		silencedCache:   &cache.Resource{},
+		sampler:         newSampler(),
+	}
+	var err error
+	e.bus, err = messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, e.bus.Start())
+
+	event := sampledEvent(0, 10)
+	event.Check.Ttl = 120
+	store.On("UpdateEvent", mock.Anything, mock.Anything).Return(event, (*corev2.Event)(nil), nil)
+	switches.On("Alive", mock.Anything, "default/check/entity", int64(120)).Return(nil)
+
+	before := eventsSampled(t, "default", "check")
+	require.NoError(t, e.handleMessage(event))
+	require.NoError(t, e.handleMessage(sampledEvent(0, 10)))
+	sampled := sampledEvent(0, 10)
+	sampled.Check.Ttl = 120
+	require.NoError(t, e.handleMessage(sampled))
+
+	// Only the first event is stored, but the TTL of the check is refreshed
+	// by every event
+	store.AssertNumberOfCalls(t, "UpdateEvent", 1)
+	switches.AssertNumberOfCalls(t, "Alive", 2)
+	assert.Equal(t, before+2, eventsSampled(t, "default", "check"))
+}
@@ -0,0 +1,437 @@
+// Package forwardd forwards the processed events of an edge backend to an
+// upstream backend, for hierarchical deployments.
+package forwardd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultBufferSize is the default number of events buffered while the
+	// upstream backend is unreachable.
+	DefaultBufferSize = 1000
+
+	// DefaultMaxBackoff is the default maximum time between the attempts to
+	// forward an event.
+	DefaultMaxBackoff = 30 * time.Second
+
+	// initialBackoff is the time before the second attempt to forward an
+	// event, doubled after each failed attempt.
+	initialBackoff = 100 * time.Millisecond
+
+	// requestTimeout is the timeout of the requests to the upstream backend.
+	requestTimeout = 10 * time.Second
+
+	// tokenExpiryMargin is the time before its expiration from which the
+	// access token is renewed.
+	tokenExpiryMargin = 10 * time.Second
+)
+
+var (
+	forwardedEvents = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "sensu_go_forwarded_events_total",
+			Help: "Number of events forwarded to the upstream backend",
+		},
+	)
+
+	forwardRetries = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "sensu_go_forward_retries_total",
+			Help: "Number of failed attempts to forward an event to the upstream backend",
+		},
+	)
+
+	forwardDrops = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "sensu_go_forward_drops_total",
+			Help: "Number of events dropped instead of being forwarded to the upstream backend",
+		},
+	)
+
+	forwardQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "sensu_go_forward_queue_depth",
+			Help: "Number of events waiting to be forwarded to the upstream backend",
+		},
+	)
+)
+
+// Forwardd forwards the events processed by the backend to the events API of
+// an upstream backend. The events are buffered, in order, while the upstream
+// backend is unreachable, and dropped once the buffer is full.
+type Forwardd struct {
+	bus          messaging.MessageBus
+	upstream     *upstream
+	namespaces   map[string]struct{}
+	maxBackoff   time.Duration
+	eventChan    chan interface{}
+	forwardq     chan *corev2.Event
+	subscription messaging.Subscription
+	stopping     chan struct{}
+	wg           *sync.WaitGroup
+	errChan      chan error
+}
+
+// Config configures a Forwardd.
+type Config struct {
+	Bus messaging.MessageBus
+
+	// URL is the URL of the API of the upstream backend.
+	URL string
+
+	// Username and Password are the credentials of the user of the upstream
+	// backend the events are created as.
+	Username string
+	Password string
+
+	// Namespaces are the namespaces of the forwarded events. The events of
+	// all the namespaces are forwarded if empty.
+	Namespaces []string
+
+	// BufferSize is the number of events buffered while the upstream backend
+	// is unreachable.
+	BufferSize int
+
+	// MaxBackoff is the maximum time between the attempts to forward an event.
+	MaxBackoff time.Duration
+
+	// TLS configures the connections to the upstream backend.
+	TLS *corev2.TLSOptions
+}
+
+// Option is a functional option used to configure Forwardd.
+type Option func(*Forwardd) error
+
+// New creates a new Forwardd with supplied Options applied.
+func New(c Config, options ...Option) (*Forwardd, error) {
+	baseURL, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forward url: %s", err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid forward url: unsupported scheme %q", baseURL.Scheme)
+	}
+	if c.Username == "" {
+		return nil, fmt.Errorf("a forward username is required")
+	}
+	if c.BufferSize < 0 {
+		return nil, fmt.Errorf("invalid forward buffer size %d, must not be negative", c.BufferSize)
+	}
+	if c.MaxBackoff < 0 {
+		return nil, fmt.Errorf("invalid forward max backoff %s, must not be negative", c.MaxBackoff)
+	}
+	if c.BufferSize == 0 {
+		c.BufferSize = DefaultBufferSize
+	}
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = DefaultMaxBackoff
+	}
+
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if c.TLS != nil {
+		tlsConfig, err := c.TLS.ToClientTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	f := &Forwardd{
+		bus: c.Bus,
+		upstream: &upstream{
+			baseURL:  baseURL,
+			username: c.Username,
+			password: c.Password,
+			client:   &http.Client{Transport: transport, Timeout: requestTimeout},
+		},
+		maxBackoff: c.MaxBackoff,
+		eventChan:  make(chan interface{}, 1),
+		forwardq:   make(chan *corev2.Event, c.BufferSize),
+		stopping:   make(chan struct{}),
+		wg:         &sync.WaitGroup{},
+		errChan:    make(chan error, 1),
+	}
+	if len(c.Namespaces) > 0 {
+		f.namespaces = make(map[string]struct{}, len(c.Namespaces))
+		for _, namespace := range c.Namespaces {
+			f.namespaces[namespace] = struct{}{}
+		}
+	}
+	for _, o := range options {
+		if err := o(f); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Receiver returns the event channel for forwardd.
+func (f *Forwardd) Receiver() chan<- interface{} {
+	return f.eventChan
+}
+
+// Start forwardd, subscribing to the "event" message bus topic.
+func (f *Forwardd) Start() error {
+	_ = prometheus.Register(forwardedEvents)
+	_ = prometheus.Register(forwardRetries)
+	_ = prometheus.Register(forwardDrops)
+	_ = prometheus.Register(forwardQueueDepth)
+
+	sub, err := f.bus.Subscribe(messaging.TopicEvent, "forwardd", f)
+	if err != nil {
+		return err
+	}
+	f.subscription = sub
+
+	f.wg.Add(2)
+	go f.receivePump()
+	go f.forwardPump()
+
+	return nil
+}
+
+// Stop forwardd.
+func (f *Forwardd) Stop() error {
+	close(f.stopping)
+	f.wg.Wait()
+	close(f.errChan)
+	return f.subscription.Cancel()
+}
+
+// Err returns a channel to listen for terminal errors on.
+func (f *Forwardd) Err() <-chan error {
+	return f.errChan
+}
+
+// Name returns the daemon name
+func (f *Forwardd) Name() string {
+	return "forwardd"
+}
+
+// receivePump buffers the events received from the message bus, without ever
+// blocking it.
+func (f *Forwardd) receivePump() {
+	defer f.wg.Done()
+	for {
+		select {
+		case <-f.stopping:
+			return
+		case msg := <-f.eventChan:
+			event, ok := msg.(*corev2.Event)
+			if !ok || !f.forwarded(event) {
+				continue
+			}
+			select {
+			case f.forwardq <- event:
+				forwardQueueDepth.Set(float64(len(f.forwardq)))
+			default:
+				forwardDrops.Inc()
+				logger.WithFields(eventFields(event)).Warn("forward buffer full, dropping event")
+			}
+		}
+	}
+}
+
+// forwardPump forwards the buffered events until forwardd stops.
+func (f *Forwardd) forwardPump() {
+	defer f.wg.Done()
+	for {
+		select {
+		case <-f.stopping:
+			f.dropForwardBuffer()
+			return
+		case event := <-f.forwardq:
+			forwardQueueDepth.Set(float64(len(f.forwardq)))
+			if !f.retryForward(event) {
+				f.dropForwardBuffer()
+				return
+			}
+		}
+	}
+}
+
+// forwarded returns true if the event belongs to a forwarded namespace.
+func (f *Forwardd) forwarded(event *corev2.Event) bool {
+	if event.Entity == nil {
+		return false
+	}
+	if f.namespaces == nil {
+		return true
+	}
+	_, ok := f.namespaces[event.Entity.Namespace]
+	return ok
+}
+
+// retryForward forwards the event, with an exponential backoff between the
+// attempts. The events rejected by the upstream backend are dropped, since
+// they would be rejected again. It returns false if forwardd stopped before
+// the event could be forwarded.
+func (f *Forwardd) retryForward(event *corev2.Event) bool {
+	backoff := initialBackoff
+	for {
+		err := f.upstream.forward(event)
+		if err == nil {
+			forwardedEvents.Inc()
+			return true
+		}
+		if rejected, ok := err.(*rejectedError); ok {
+			forwardDrops.Inc()
+			logger.WithFields(eventFields(event)).WithError(rejected).Error("upstream backend rejected the event, dropping it")
+			return true
+		}
+		forwardRetries.Inc()
+		logger.WithFields(eventFields(event)).WithError(err).WithField("backoff", backoff).Warn("could not forward event")
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-f.stopping:
+			timer.Stop()
+			forwardDrops.Inc()
+			return false
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > f.maxBackoff {
+			backoff = f.maxBackoff
+		}
+	}
+}
+
+// dropForwardBuffer drops the events left in the forward buffer when forwardd
+// stops.
+func (f *Forwardd) dropForwardBuffer() {
+	dropped := len(f.forwardq)
+	if dropped == 0 {
+		return
+	}
+	for i := 0; i < dropped; i++ {
+		<-f.forwardq
+	}
+	forwardDrops.Add(float64(dropped))
+	forwardQueueDepth.Set(0)
+	logger.WithField("events", dropped).Warn("dropped the events left in the forward buffer")
+}
+
+func eventFields(event *corev2.Event) logrus.Fields {
+	fields := logrus.Fields{
+		"namespace": event.Entity.Namespace,
+		"entity":    event.Entity.Name,
+	}
+	if event.HasCheck() {
+		fields["check"] = event.Check.Name
+	}
+	return fields
+}
+
+// rejectedError is returned when the upstream backend rejects an event.
+type rejectedError struct {
+	status string
+	body   string
+}
+
+func (e *rejectedError) Error() string {
+	return fmt.Sprintf("%s: %s", e.status, e.body)
+}
+
+// upstream is a client of the API of the upstream backend.
+type upstream struct {
+	baseURL  *url.URL
+	username string
+	password string
+	client   *http.Client
+
+	// token is the access token, renewed before its expiration
+	token *corev2.Tokens
+}
+
+// forward creates the event through the events API of the upstream backend,
+// in the namespace of its entity.
+func (u *upstream) forward(event *corev2.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return &rejectedError{status: "invalid event", body: err.Error()}
+	}
+	token, err := u.accessToken()
+	if err != nil {
+		return err
+	}
+
+	eventsPath := path.Join("/api/core/v2/namespaces", event.Entity.Namespace, "events")
+	req, err := http.NewRequest(http.MethodPost, u.url(eventsPath), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := ioutil.ReadAll(resp.Body)
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		// The access token was revoked, or the upstream backend restarted
+		u.token = nil
+		return fmt.Errorf("upstream backend rejected the access token: %s", resp.Status)
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return fmt.Errorf("upstream backend error: %s: %s", resp.Status, respBody)
+	default:
+		return &rejectedError{status: resp.Status, body: string(respBody)}
+	}
+}
+
+// accessToken returns an access token of the upstream backend, authenticating
+// again if the current one is about to expire.
+func (u *upstream) accessToken() (string, error) {
+	if u.token != nil && time.Until(time.Unix(u.token.ExpiresAt, 0)) > tokenExpiryMargin {
+		return u.token.Access, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.url("/auth"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(u.username, u.password)
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not authenticate to the upstream backend: %s", resp.Status)
+	}
+
+	token := &corev2.Tokens{}
+	if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+		return "", fmt.Errorf("invalid upstream backend access token: %s", err)
+	}
+	u.token = token
+	return token.Access, nil
+}
+
+// url returns the URL of the path of the upstream backend, below the path of
+// its base URL.
+func (u *upstream) url(p string) string {
+	ref := *u.baseURL
+	ref.Path = path.Join(ref.Path, p)
+	return ref.String()
+}
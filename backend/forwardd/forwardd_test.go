@@ -0,0 +1,186 @@
+package forwardd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUpstream is an upstream backend recording the events it receives.
+type testUpstream struct {
+	mu       sync.Mutex
+	logins   int
+	events   []*corev2.Event
+	statuses []int
+	received chan struct{}
+}
+
+func newTestUpstream(t *testing.T) (*testUpstream, *httptest.Server) {
+	t.Helper()
+	u := &testUpstream{received: make(chan struct{}, 10)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth", func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "edge" || password != "P@ssw0rd!" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		u.mu.Lock()
+		u.logins++
+		u.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(&corev2.Tokens{
+			Access:    "token",
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		})
+	})
+	mux.HandleFunc("/api/core/v2/namespaces/default/events", func(w http.ResponseWriter, r *http.Request) {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if len(u.statuses) > 0 {
+			status := u.statuses[0]
+			u.statuses = u.statuses[1:]
+			w.WriteHeader(status)
+			return
+		}
+		event := &corev2.Event{}
+		if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		u.events = append(u.events, event)
+		w.WriteHeader(http.StatusCreated)
+		u.received <- struct{}{}
+	})
+	return u, httptest.NewServer(mux)
+}
+
+func fixtureMetricsEvent(entity, namespace string) *corev2.Event {
+	event := &corev2.Event{
+		Entity: corev2.FixtureEntity(entity),
+		Metrics: &corev2.Metrics{
+			Points: []*corev2.MetricPoint{{Name: "cpu", Value: 1, Tags: []*corev2.MetricTag{}}},
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	event.Entity.Namespace = namespace
+	return event
+}
+
+func newTestForwardd(t *testing.T, url string, namespaces ...string) (*Forwardd, messaging.MessageBus) {
+	t.Helper()
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+
+	f, err := New(Config{
+		Bus:        bus,
+		URL:        url,
+		Username:   "edge",
+		Password:   "P@ssw0rd!",
+		Namespaces: namespaces,
+		MaxBackoff: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, f.Start())
+	return f, bus
+}
+
+func TestForwardd(t *testing.T) {
+	upstream, server := newTestUpstream(t)
+	defer server.Close()
+	upstream.statuses = []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+
+	f, bus := newTestForwardd(t, server.URL, "default")
+	defer bus.Stop()
+	defer f.Stop()
+
+	require.NoError(t, bus.Publish(messaging.TopicEvent, fixtureMetricsEvent("other", "acme")))
+	require.NoError(t, bus.Publish(messaging.TopicEvent, fixtureMetricsEvent("first", "default")))
+	require.NoError(t, bus.Publish(messaging.TopicEvent, fixtureMetricsEvent("second", "default")))
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-upstream.received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("events not forwarded")
+		}
+	}
+
+	upstream.mu.Lock()
+	defer upstream.mu.Unlock()
+	require.Len(t, upstream.events, 2)
+	assert.Equal(t, "first", upstream.events[0].Entity.Name)
+	assert.Equal(t, "second", upstream.events[1].Entity.Name)
+	assert.Equal(t, "cpu", upstream.events[0].Metrics.Points[0].Name)
+	assert.Equal(t, 1, upstream.logins)
+}
+
+func TestUpstreamForward(t *testing.T) {
+	upstream, server := newTestUpstream(t)
+	defer server.Close()
+
+	f, err := New(Config{URL: server.URL + "/", Username: "edge", Password: "P@ssw0rd!"})
+	require.NoError(t, err)
+	event := fixtureMetricsEvent("entity", "default")
+
+	// The upstream backend rejects the event
+	upstream.statuses = []int{http.StatusBadRequest}
+	err = f.upstream.forward(event)
+	_, ok := err.(*rejectedError)
+	assert.True(t, ok, "expected a rejected error, got %v", err)
+
+	// The access token is renewed once rejected
+	upstream.statuses = []int{http.StatusUnauthorized}
+	err = f.upstream.forward(event)
+	require.Error(t, err)
+	_, ok = err.(*rejectedError)
+	assert.False(t, ok)
+	assert.Nil(t, f.upstream.token)
+	require.NoError(t, f.upstream.forward(event))
+	assert.Equal(t, 2, upstream.logins)
+
+	// Bad credentials are retried
+	f.upstream.token = nil
+	f.upstream.password = "wrong"
+	err = f.upstream.forward(event)
+	require.Error(t, err)
+	_, ok = err.(*rejectedError)
+	assert.False(t, ok)
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{
+			name:   "unsupported scheme",
+			config: Config{URL: "ftp://upstream", Username: "edge"},
+		},
+		{
+			name:   "missing username",
+			config: Config{URL: "https://upstream:8080"},
+		},
+		{
+			name:   "negative buffer size",
+			config: Config{URL: "https://upstream:8080", Username: "edge", BufferSize: -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.config)
+			assert.Error(t, err)
+		})
+	}
+}
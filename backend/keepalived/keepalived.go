@@ -12,6 +12,7 @@ import (
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/liveness"
 	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/readiness"
 	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/types"
@@ -51,6 +52,7 @@ type Keepalived struct {
 	errChan               chan error
 	livenessFactory       liveness.Factory
 	ringPool              *ringv2.Pool
+	readiness             *readiness.Gate
 }
 
 // Option is a functional option.
@@ -66,6 +68,10 @@ type Config struct {
 	RingPool              *ringv2.Pool
 	BufferSize            int
 	WorkerCount           int
+
+	// Readiness postpones the keepalive failures until the backend is warm.
+	// The failures are processed at once if nil.
+	Readiness *readiness.Gate
 }
 
 // New creates a new Keepalived.
@@ -88,6 +94,7 @@ func New(c Config, opts ...Option) (*Keepalived, error) {
 		mu:                    &sync.Mutex{},
 		errChan:               make(chan error, 1),
 		ringPool:              c.RingPool,
+		readiness:             c.Readiness,
 	}
 	for _, o := range opts {
 		if err := o(k); err != nil {
@@ -117,6 +124,7 @@ func (k *Keepalived) Start() error {
 	}
 
 	k.startWorkers()
+	k.readiness.Done(k.Name())
 
 	return nil
 }
@@ -354,6 +362,13 @@ func (k *Keepalived) dead(key string, prev liveness.State, leader bool) bool {
 		return false
 	}
 
+	if !k.readiness.Ready() {
+		// The dead callback is called again at every TTL interval, the
+		// agent may reconnect in the meantime
+		lager.Info("keepalive timed out while the backend is warming up, postponing the failure")
+		return false
+	}
+
 	lager.Warn("keepalive timed out")
 
 	// Now verify if we encountered an error while parsing the key
@@ -406,6 +421,7 @@ func (k *Keepalived) dead(key string, prev liveness.State, leader bool) bool {
 	event := createKeepaliveEvent(currentEvent)
 	event.Check.Status = 1
 	event.Check.Output = fmt.Sprintf("No keepalive sent from %s for %v seconds (>= %v)", entity.Name, time.Now().Unix()-entity.LastSeen, event.Check.Timeout)
+	event.SetSource(corev2.EventSourceBackend, "keepalived")
 
 	if err := k.bus.Publish(messaging.TopicEventRaw, event); err != nil {
 		lager.WithError(err).Error("error publishing event")
@@ -454,6 +470,18 @@ func (k *Keepalived) handleUpdate(e *types.Event) error {
 
 	entity.LastSeen = e.Timestamp
 
+	// The keepalives can be sent without the system inventory of the entity,
+	// the last one received is kept
+	if e.OmitsInventory() {
+		stored, err := k.store.GetEntityByName(ctx, entity.Name)
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			entity.System = stored.System
+		}
+	}
+
 	if err := k.store.UpdateEntity(ctx, entity); err != nil {
 		logger.WithError(err).Error("error updating entity in store")
 		return err
@@ -461,6 +489,12 @@ func (k *Keepalived) handleUpdate(e *types.Event) error {
 	event := createKeepaliveEvent(e)
 	event.Check.Status = 0
 	event.Check.Output = fmt.Sprintf("Keepalive last sent from %s at %s", entity.Name, time.Unix(entity.LastSeen, 0).String())
+	if source := e.Source(); source != "" {
+		// The event is the outcome of the keepalive of the agent
+		event.Annotations = map[string]string{corev2.EventSourceAnnotation: source}
+	} else {
+		event.SetSource(corev2.EventSourceBackend, "keepalived")
+	}
 
 	if entity.EntityClass == corev2.EntityAgentClass {
 		// Refresh the rings that the entity is involved in
@@ -6,6 +6,7 @@ import (
 
 	"github.com/sensu/sensu-go/backend/liveness"
 	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/readiness"
 	"github.com/sensu/sensu-go/testing/mockstore"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -325,3 +326,40 @@ func TestDeadCallbackNoEvent(t *testing.T) {
 	// Smoke test - just want to make sure there is no panic
 	keepalived.dead("default/testSubscriber", liveness.Alive, true)
 }
+
+func TestDeadCallbackWarmingUp(t *testing.T) {
+	store := &mockstore.MockStore{}
+	gate := readiness.NewGate("keepalived")
+	keepalived, err := New(Config{Store: store, LivenessFactory: fakeFactory, Readiness: gate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The failure is postponed without reading the entity while the backend
+	// is warming up
+	assert.False(t, keepalived.dead("default/testSubscriber", liveness.Alive, true))
+	store.AssertNotCalled(t, "GetEntityByName", mock.Anything, mock.Anything)
+}
+
+func TestHandleUpdateOmittedInventory(t *testing.T) {
+	test := newKeepalivedTest(t)
+	defer test.Dispose(t)
+
+	stored := corev2.FixtureEntity("entity")
+	stored.System.Hostname = "web-1"
+	stored.System.Network.Interfaces = []corev2.NetworkInterface{{Name: "eth0"}}
+
+	event := corev2.FixtureEvent("entity", "keepalive")
+	event.Entity.System = corev2.System{}
+	event.Annotations = map[string]string{
+		corev2.KeepaliveInventoryAnnotation: corev2.KeepaliveInventoryOmitted,
+	}
+	test.Store.On("DeleteFailingKeepalive", mock.Anything, event.Entity).Return(nil)
+	test.Store.On("GetEntityByName", mock.Anything, "entity").Return(stored, nil)
+	test.Store.On("UpdateEntity", mock.Anything, mock.MatchedBy(func(entity *corev2.Entity) bool {
+		return entity.System.Hostname == "web-1" && len(entity.System.Network.Interfaces) == 1
+	})).Return(nil)
+
+	require.NoError(t, test.Keepalived.handleUpdate(event))
+	test.Store.AssertExpectations(t)
+}
@@ -29,6 +29,14 @@ const (
 
 	// TopicTessenMetric is the topic prefix for tessen api metrics to Tessend.
 	TopicTessenMetric = "sensu:tessen-metric"
+
+	// TopicAgentSpool is the topic prefix for the responses of agents to
+	// spool requests.
+	TopicAgentSpool = "sensu:agent-spool"
+
+	// TopicAgentCommand is the topic prefix for the responses of agents to
+	// command requests.
+	TopicAgentCommand = "sensu:agent-command"
 )
 
 var (
@@ -48,6 +56,16 @@ type Subscriber interface {
 	Receiver() chan<- interface{}
 }
 
+// ChannelSubscriber is a Subscriber that receives messages on its Channel.
+type ChannelSubscriber struct {
+	Channel chan interface{}
+}
+
+// Receiver returns the channel of the subscriber.
+func (c ChannelSubscriber) Receiver() chan<- interface{} {
+	return c.Channel
+}
+
 // A Subscription is a cancellable subscription to a WizardTopic.
 type Subscription struct {
 	id     string
@@ -83,6 +101,18 @@ type MessageBus interface {
 	Publish(topic string, message interface{}) error
 }
 
+// AgentSpoolTopic is a helper to determine the topic on which the response to
+// the spool request with the given ID is published.
+func AgentSpoolTopic(requestID string) string {
+	return fmt.Sprintf("%s:%s", TopicAgentSpool, requestID)
+}
+
+// AgentCommandTopic is a helper to determine the topic on which the response
+// to the command request with the given ID is published.
+func AgentCommandTopic(requestID string) string {
+	return fmt.Sprintf("%s:%s", TopicAgentCommand, requestID)
+}
+
 // SubscriptionTopic is a helper to determine the proper topic name for a
 // subscription based on the namespace
 func SubscriptionTopic(namespace, sub string) string {
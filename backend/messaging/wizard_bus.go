@@ -117,7 +117,24 @@ func (b *WizardBus) Subscribe(topic string, consumer string, sub Subscriber) (Su
 	}
 
 	subscription, err := t.Subscribe(consumer, sub)
-	return subscription, err
+	if err != nil {
+		return subscription, err
+	}
+
+	// Forget about the topic once its last subscriber is gone, so that
+	// short-lived topics don't accumulate.
+	cancel := subscription.cancel
+	subscription.cancel = func(id string) error {
+		err := cancel(id)
+		b.topicsMu.Lock()
+		if b.topics[topic] == t && t.IsClosed() {
+			delete(b.topics, topic)
+		}
+		b.topicsMu.Unlock()
+		return err
+	}
+
+	return subscription, nil
 }
 
 // Publish publishes a message to a topic. If the topic does not
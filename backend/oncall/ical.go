@@ -0,0 +1,178 @@
+package oncall
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// icalEvent is an event of an iCal calendar.
+type icalEvent struct {
+	start    time.Time
+	end      time.Time
+	summary  string
+	attendee string
+}
+
+// lookupICal returns who is on call at the given time in the iCal calendar:
+// the email address of the attendee of the event in progress, or its summary
+// if it has no attendee. The calendar, and the redirections it is read
+// through, must be on one of the allowed hosts. Recurring events are not
+// expanded, and rejected, since the calendars of the on-call schedules are
+// expected to list every shift.
+func (r *Resolver) lookupICal(ctx context.Context, calendarURL string, now time.Time) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, calendarURL, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := r.allowICalHost(req.URL); err != nil {
+		return "", err
+	}
+	client := *r.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return r.allowICalHost(req.URL)
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxScheduleSize))
+		return "", fmt.Errorf("iCal calendar %q: %s", calendarURL, resp.Status)
+	}
+
+	body := &io.LimitedReader{R: resp.Body, N: maxScheduleSize + 1}
+	events, err := parseICal(body)
+	if body.N <= 0 {
+		return "", fmt.Errorf("iCal calendar %q: larger than %d bytes", calendarURL, maxScheduleSize)
+	}
+	if err != nil {
+		return "", fmt.Errorf("iCal calendar %q: %s", calendarURL, err)
+	}
+	for _, event := range events {
+		if now.Before(event.start) || !now.Before(event.end) {
+			continue
+		}
+		if event.attendee != "" {
+			return event.attendee, nil
+		}
+		if event.summary != "" {
+			return event.summary, nil
+		}
+	}
+	return "", ErrNoTarget
+}
+
+// allowICalHost returns an error if the iCal calendars can't be read from the
+// host of the URL.
+func (r *Resolver) allowICalHost(u *url.URL) error {
+	if _, ok := r.icalHosts[strings.ToLower(u.Host)]; ok {
+		return nil
+	}
+	if _, ok := r.icalHosts[strings.ToLower(u.Hostname())]; ok {
+		return nil
+	}
+	return fmt.Errorf("iCal calendar host %q is not allowed", u.Host)
+}
+
+// parseICal returns the events of the iCal calendar. The events without start
+// or end are ignored, and the recurring events are rejected.
+func parseICal(r io.Reader) ([]icalEvent, error) {
+	lines, err := unfoldICal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []icalEvent
+	var event *icalEvent
+	for _, line := range lines {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		params := strings.Split(parts[0], ";")
+		name, value := strings.ToUpper(params[0]), parts[1]
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &icalEvent{}
+		case name == "END" && value == "VEVENT":
+			if event != nil && !event.start.IsZero() && !event.end.IsZero() {
+				events = append(events, *event)
+			}
+			event = nil
+		case event == nil:
+		case name == "DTSTART":
+			if event.start, err = parseICalTime(value, params[1:]); err != nil {
+				return nil, err
+			}
+		case name == "DTEND":
+			if event.end, err = parseICalTime(value, params[1:]); err != nil {
+				return nil, err
+			}
+		case name == "RRULE" || name == "RDATE":
+			return nil, errors.New("recurring events are not supported")
+		case name == "SUMMARY":
+			event.summary = unescapeICal(value)
+		case name == "ATTENDEE":
+			if strings.HasPrefix(strings.ToLower(value), "mailto:") {
+				event.attendee = value[len("mailto:"):]
+			}
+		}
+	}
+	return events, nil
+}
+
+// unfoldICal returns the content lines of the iCal calendar, unfolded.
+func unfoldICal(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseICalTime parses an iCal date or date-time value, in UTC, in the time
+// zone of its TZID parameter, or else in local time.
+func parseICalTime(value string, params []string) (time.Time, error) {
+	location := time.Local
+	for _, param := range params {
+		if strings.HasPrefix(strings.ToUpper(param), "TZID=") {
+			loc, err := time.LoadLocation(strings.Trim(param[len("TZID="):], `"`))
+			if err != nil {
+				return time.Time{}, err
+			}
+			location = loc
+		}
+	}
+	layout := "20060102T150405"
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse("20060102T150405Z", value)
+	case len(value) == len("20060102"):
+		layout = "20060102"
+	}
+	return time.ParseInLocation(layout, value, location)
+}
+
+// unescapeICal unescapes an iCal text value.
+func unescapeICal(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
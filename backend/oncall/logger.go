@@ -0,0 +1,7 @@
+package oncall
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "oncall",
+})
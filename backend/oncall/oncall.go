@@ -0,0 +1,179 @@
+// Package oncall looks up who is on call, in the external on-call schedules
+// of the events, so the event handlers can notify them.
+package oncall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SourceAnnotation is the annotation of the checks and entities giving
+	// the on-call schedule of their events: pagerduty:<schedule ID> for a
+	// PagerDuty schedule, or the http(s) URL of an iCal calendar.
+	SourceAnnotation = "sensu.io/oncall-source"
+
+	// TargetAnnotation is the annotation of the events holding who is on
+	// call in their on-call schedule.
+	TargetAnnotation = "sensu.io/oncall"
+
+	// DefaultCacheTTL is the default time the on-call targets are cached.
+	DefaultCacheTTL = 5 * time.Minute
+
+	// pagerDutyPrefix is the prefix of the PagerDuty schedule sources.
+	pagerDutyPrefix = "pagerduty:"
+
+	// maxScheduleSize is the maximum size, in bytes, of an on-call schedule.
+	maxScheduleSize = 5 * 1024 * 1024
+)
+
+// ErrNoTarget is returned when nobody is on call in a schedule.
+var ErrNoTarget = errors.New("nobody is on call")
+
+// Config configures a Resolver.
+type Config struct {
+	// CacheTTL is the time the on-call targets are cached. Defaults to
+	// DefaultCacheTTL.
+	CacheTTL time.Duration
+
+	// PagerDutyToken is the API token used to read the PagerDuty schedules.
+	PagerDutyToken string
+
+	// ICalHosts are the hosts the iCal calendars can be read from, with or
+	// without a port. The iCal sources are rejected if empty.
+	ICalHosts []string
+
+	// Client is the HTTP client of the lookups. Defaults to a client with a
+	// timeout of 10 seconds.
+	Client *http.Client
+}
+
+// Resolver looks up who is on call in the on-call schedules, and caches the
+// results. It is safe for concurrent use.
+type Resolver struct {
+	cacheTTL       time.Duration
+	pagerDutyToken string
+	pagerDutyURL   string
+	client         *http.Client
+	icalHosts      map[string]struct{}
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+
+	// calls are the lookups in flight, by source, shared by the concurrent
+	// lookups of the same source.
+	calls map[string]*lookupCall
+
+	// now is replaced by the tests.
+	now func() time.Time
+}
+
+// cacheEntry is a cached target, or the absence of target if noTarget is
+// true.
+type cacheEntry struct {
+	target   string
+	noTarget bool
+	expires  time.Time
+}
+
+func (e cacheEntry) result() (string, error) {
+	if e.noTarget {
+		return "", ErrNoTarget
+	}
+	return e.target, nil
+}
+
+// lookupCall is a lookup in flight. Its result is set before done is closed.
+type lookupCall struct {
+	done   chan struct{}
+	target string
+	err    error
+}
+
+// NewResolver returns a new Resolver.
+func NewResolver(c Config) (*Resolver, error) {
+	if c.CacheTTL < 0 {
+		return nil, fmt.Errorf("invalid on-call cache TTL %s, must not be negative", c.CacheTTL)
+	}
+	if c.CacheTTL == 0 {
+		c.CacheTTL = DefaultCacheTTL
+	}
+	if c.Client == nil {
+		c.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	icalHosts := make(map[string]struct{}, len(c.ICalHosts))
+	for _, host := range c.ICalHosts {
+		icalHosts[strings.ToLower(host)] = struct{}{}
+	}
+	return &Resolver{
+		cacheTTL:       c.CacheTTL,
+		pagerDutyToken: c.PagerDutyToken,
+		pagerDutyURL:   defaultPagerDutyURL,
+		client:         c.Client,
+		icalHosts:      icalHosts,
+		cache:          make(map[string]cacheEntry),
+		calls:          make(map[string]*lookupCall),
+		now:            time.Now,
+	}, nil
+}
+
+// Lookup returns who is on call in the schedule of the given source. The
+// targets, and the absence of target, are cached, and the expired result of a
+// source is still returned if the schedule can't be read. The concurrent
+// lookups of a source share a single read of its schedule.
+func (r *Resolver) Lookup(ctx context.Context, source string) (string, error) {
+	now := r.now()
+	r.mu.Lock()
+	entry, cached := r.cache[source]
+	if cached && now.Before(entry.expires) {
+		r.mu.Unlock()
+		return entry.result()
+	}
+	call, inFlight := r.calls[source]
+	if !inFlight {
+		call = &lookupCall{done: make(chan struct{})}
+		r.calls[source] = call
+	}
+	r.mu.Unlock()
+
+	if inFlight {
+		select {
+		case <-call.done:
+			return call.target, call.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	target, err := r.lookup(ctx, source, now)
+	r.mu.Lock()
+	switch {
+	case err == nil || err == ErrNoTarget:
+		r.cache[source] = cacheEntry{target: target, noTarget: err == ErrNoTarget, expires: now.Add(r.cacheTTL)}
+	case cached:
+		logger.WithField("source", source).WithError(err).Warn("could not read on-call schedule, using the cached target")
+		target, err = entry.result()
+	}
+	delete(r.calls, source)
+	r.mu.Unlock()
+
+	call.target, call.err = target, err
+	close(call.done)
+	return target, err
+}
+
+// lookup reads who is on call at the given time in the schedule of the source.
+func (r *Resolver) lookup(ctx context.Context, source string, now time.Time) (string, error) {
+	switch {
+	case strings.HasPrefix(source, pagerDutyPrefix):
+		return r.lookupPagerDuty(ctx, strings.TrimPrefix(source, pagerDutyPrefix))
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		return r.lookupICal(ctx, source, now)
+	}
+	return "", fmt.Errorf("invalid on-call source %q, must be pagerduty:<schedule ID> or an iCal URL", source)
+}
@@ -0,0 +1,256 @@
+package oncall
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const calendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20190601T000000Z\r\n" +
+	"DTEND:20190608T000000Z\r\n" +
+	"SUMMARY:On call: Alice\r\n" +
+	"ATTENDEE;CN=Alice:mailto:alice@example.com\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=America/Montreal:20190608T000000\r\n" +
+	"DTEND;TZID=America/Montreal:20190615T0000\r\n" +
+	" 00\r\n" +
+	"SUMMARY:Bob\\, the second\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func newTestResolver(t *testing.T, c Config, now time.Time) *Resolver {
+	t.Helper()
+	r, err := NewResolver(c)
+	require.NoError(t, err)
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func TestNewResolverInvalidCacheTTL(t *testing.T) {
+	_, err := NewResolver(Config{CacheTTL: -time.Second})
+	assert.Error(t, err)
+}
+
+func TestLookupICal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, calendar)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		want    string
+		wantErr error
+	}{
+		{
+			name: "attendee",
+			now:  time.Date(2019, 6, 3, 12, 0, 0, 0, time.UTC),
+			want: "alice@example.com",
+		},
+		{
+			// Bob's shift starts at midnight in Montreal, 4 AM UTC
+			name:    "before shift in time zone",
+			now:     time.Date(2019, 6, 8, 3, 0, 0, 0, time.UTC),
+			wantErr: ErrNoTarget,
+		},
+		{
+			name: "summary",
+			now:  time.Date(2019, 6, 8, 5, 0, 0, 0, time.UTC),
+			want: "Bob, the second",
+		},
+		{
+			name:    "nobody",
+			now:     time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+			wantErr: ErrNoTarget,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestResolver(t, Config{ICalHosts: []string{"127.0.0.1"}}, tt.now)
+			got, err := r.Lookup(context.Background(), server.URL)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLookupPagerDuty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/oncalls", r.URL.Path)
+		assert.Equal(t, "PSCHED1", r.URL.Query().Get("schedule_ids[]"))
+		assert.Equal(t, "Token token=secret", r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"oncalls": [
+			{"escalation_level": 2, "user": {"summary": "Bob", "email": "bob@example.com"}},
+			{"escalation_level": 1, "user": {"summary": "Alice"}}
+		]}`)
+	}))
+	defer server.Close()
+
+	r := newTestResolver(t, Config{PagerDutyToken: "secret"}, time.Now())
+	r.pagerDutyURL = server.URL
+	target, err := r.Lookup(context.Background(), "pagerduty:PSCHED1")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", target)
+
+	r = newTestResolver(t, Config{}, time.Now())
+	r.pagerDutyURL = server.URL
+	_, err = r.Lookup(context.Background(), "pagerduty:PSCHED1")
+	assert.Error(t, err)
+}
+
+func TestLookupCache(t *testing.T) {
+	var requests int32
+	var failing int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, calendar)
+	}))
+	defer server.Close()
+
+	now := time.Date(2019, 6, 3, 12, 0, 0, 0, time.UTC)
+	r := newTestResolver(t, Config{CacheTTL: time.Minute, ICalHosts: []string{"127.0.0.1"}}, now)
+	for i := 0; i < 3; i++ {
+		target, err := r.Lookup(context.Background(), server.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "alice@example.com", target)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// The expired target is still used while the calendar can't be read
+	atomic.StoreInt32(&failing, 1)
+	r.now = func() time.Time { return now.Add(2 * time.Minute) }
+	target, err := r.Lookup(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", target)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// The absence of target is cached as well
+	atomic.StoreInt32(&failing, 0)
+	r.now = func() time.Time { return now.Add(365 * 24 * time.Hour) }
+	for i := 0; i < 3; i++ {
+		_, err := r.Lookup(context.Background(), server.URL)
+		assert.Equal(t, ErrNoTarget, err)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestLookupConcurrent(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		fmt.Fprint(w, calendar)
+	}))
+	defer server.Close()
+
+	r := newTestResolver(t, Config{ICalHosts: []string{"127.0.0.1"}}, time.Date(2019, 6, 3, 12, 0, 0, 0, time.UTC))
+	var wg sync.WaitGroup
+	results := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			target, err := r.Lookup(context.Background(), server.URL)
+			assert.NoError(t, err)
+			results <- target
+		}()
+	}
+	// Wait for the first lookup to reach the server before releasing it
+	for atomic.LoadInt32(&requests) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+	for target := range results {
+		assert.Equal(t, "alice@example.com", target)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestLookupICalHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, calendar)
+	}))
+	defer server.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusFound))
+	defer redirect.Close()
+	now := time.Date(2019, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	// The iCal calendars are disabled without allowed hosts
+	r := newTestResolver(t, Config{}, now)
+	_, err := r.Lookup(context.Background(), server.URL)
+	assert.Error(t, err)
+
+	r = newTestResolver(t, Config{ICalHosts: []string{"example.com"}}, now)
+	_, err = r.Lookup(context.Background(), server.URL)
+	assert.Error(t, err)
+
+	// The host can be allowed with its port only
+	host := strings.TrimPrefix(server.URL, "http://")
+	r = newTestResolver(t, Config{ICalHosts: []string{host}}, now)
+	target, err := r.Lookup(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", target)
+
+	// The redirections to the other hosts are not followed
+	_, err = r.Lookup(context.Background(), redirect.URL)
+	assert.Error(t, err)
+}
+
+func TestLookupICalTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "BEGIN:VCALENDAR\r\n")
+		line := "X-COMMENT:" + strings.Repeat("x", 1000) + "\r\n"
+		for written := 0; written <= maxScheduleSize; written += len(line) {
+			fmt.Fprint(w, line)
+		}
+	}))
+	defer server.Close()
+
+	r := newTestResolver(t, Config{ICalHosts: []string{"127.0.0.1"}}, time.Now())
+	_, err := r.Lookup(context.Background(), server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "larger than")
+}
+
+func TestParseICalRejectsRecurringEvents(t *testing.T) {
+	_, err := parseICal(strings.NewReader("BEGIN:VEVENT\nDTSTART:20190601T000000Z\nDTEND:20190608T000000Z\nRRULE:FREQ=WEEKLY\nSUMMARY:Alice\nEND:VEVENT\n"))
+	assert.Error(t, err)
+}
+
+func TestLookupInvalidSource(t *testing.T) {
+	r := newTestResolver(t, Config{}, time.Now())
+	_, err := r.Lookup(context.Background(), "ftp://example.com/oncall.ics")
+	assert.Error(t, err)
+}
+
+func TestParseICalIgnoresIncompleteEvents(t *testing.T) {
+	events, err := parseICal(strings.NewReader("BEGIN:VEVENT\nSUMMARY:Alice\nEND:VEVENT\n"))
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
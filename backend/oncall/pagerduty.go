@@ -0,0 +1,74 @@
+package oncall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const defaultPagerDutyURL = "https://api.pagerduty.com"
+
+// pagerDutyOnCalls is the response of the PagerDuty on-calls API.
+type pagerDutyOnCalls struct {
+	OnCalls []struct {
+		EscalationLevel int `json:"escalation_level"`
+		User            struct {
+			Summary string `json:"summary"`
+			Email   string `json:"email"`
+		} `json:"user"`
+	} `json:"oncalls"`
+}
+
+// lookupPagerDuty returns the user on call at the first escalation level of
+// the PagerDuty schedule: their email address, or their name if it is unknown.
+func (r *Resolver) lookupPagerDuty(ctx context.Context, schedule string) (string, error) {
+	if r.pagerDutyToken == "" {
+		return "", errors.New("no PagerDuty API token is configured")
+	}
+	query := url.Values{}
+	query.Set("schedule_ids[]", schedule)
+	query.Set("include[]", "users")
+	query.Set("earliest", "true")
+	req, err := http.NewRequest(http.MethodGet, r.pagerDutyURL+"/oncalls?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Authorization", "Token token="+r.pagerDutyToken)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxScheduleSize))
+		return "", fmt.Errorf("PagerDuty schedule %q: %s", schedule, resp.Status)
+	}
+
+	var onCalls pagerDutyOnCalls
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxScheduleSize)).Decode(&onCalls); err != nil {
+		return "", fmt.Errorf("PagerDuty schedule %q: %s", schedule, err)
+	}
+	target, level := "", 0
+	for _, onCall := range onCalls.OnCalls {
+		user := onCall.User.Email
+		if user == "" {
+			user = onCall.User.Summary
+		}
+		if user == "" || (target != "" && onCall.EscalationLevel >= level) {
+			continue
+		}
+		target, level = user, onCall.EscalationLevel
+	}
+	if target == "" {
+		return "", ErrNoTarget
+	}
+	return target, nil
+}
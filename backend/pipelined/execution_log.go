@@ -0,0 +1,116 @@
+package pipelined
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxExecutionOutput is the size, in bytes, at which the handler outputs
+	// recorded in the event pipeline records are truncated.
+	maxExecutionOutput = 1024
+
+	// DefaultHandlerOutputMaxSize is the default size, in bytes, at which the
+	// captured handler outputs are truncated.
+	DefaultHandlerOutputMaxSize = 16 * 1024
+
+	// MaxHandlerOutputHistorySize is the maximum total size, in bytes, of the
+	// captured outputs of a handler, so they fit in a single etcd value.
+	MaxHandlerOutputHistorySize = 1024 * 1024
+)
+
+// newEventPipeline returns an empty event pipeline record for the event.
+func newEventPipeline(event *types.Event) *corev2.EventPipeline {
+	pipeline := &corev2.EventPipeline{
+		Namespace: event.Entity.Namespace,
+		Entity:    event.Entity.Name,
+		Timestamp: event.Timestamp,
+	}
+	if event.HasCheck() {
+		pipeline.Check = event.Check.Name
+	}
+	return pipeline
+}
+
+// storeEventPipeline stores the event pipeline record, so users can find out
+// whether the handlers of an event ran. Events without checks are not
+// recorded since records are identified by entity and check.
+func (p *Pipelined) storeEventPipeline(ctx context.Context, pipeline *corev2.EventPipeline) {
+	if pipeline.Check == "" || len(pipeline.Handlers) == 0 {
+		return
+	}
+
+	sort.Slice(pipeline.Handlers, func(i, j int) bool {
+		return pipeline.Handlers[i].Handler < pipeline.Handlers[j].Handler
+	})
+
+	if err := p.store.UpdateEventPipeline(ctx, pipeline); err != nil {
+		fields := logrus.Fields{
+			"namespace": pipeline.Namespace,
+			"entity":    pipeline.Entity,
+			"check":     pipeline.Check,
+		}
+		logger.WithFields(fields).WithError(err).Error("failed to store the event pipeline record")
+	}
+}
+
+// setExecutionOutput sets the output of the handler execution, truncated to
+// maxExecutionOutput bytes.
+func setExecutionOutput(execution *corev2.HandlerExecution, output string) {
+	if len(output) > maxExecutionOutput {
+		output = output[:maxExecutionOutput]
+		execution.OutputTruncated = true
+	}
+	execution.Output = output
+}
+
+// validateHandlerOutputCapture returns an error if the number of handler
+// outputs captured or their maximum size is invalid.
+func validateHandlerOutputCapture(history, maxSize int) error {
+	if history < 0 {
+		return fmt.Errorf("invalid handler output history %d, must not be negative", history)
+	}
+	if maxSize <= 0 {
+		return fmt.Errorf("invalid handler output maximum size %d, must be positive", maxSize)
+	}
+	if history*maxSize > MaxHandlerOutputHistorySize {
+		return fmt.Errorf("the %d handler outputs of %d bytes captured exceed %d bytes per handler", history, maxSize, MaxHandlerOutputHistorySize)
+	}
+	return nil
+}
+
+// captureHandlerOutput stores the output of the handler execution, truncated
+// to the maximum size of the captured outputs, if the handler outputs are
+// captured, so users can find out why a handler had no effect.
+func (p *Pipelined) captureHandlerOutput(ctx context.Context, event *types.Event, execution *corev2.HandlerExecution, output string) {
+	if p.outputHistory == 0 {
+		return
+	}
+
+	captured := &corev2.HandlerOutput{
+		Namespace:        event.Entity.Namespace,
+		Entity:           event.Entity.Name,
+		HandlerExecution: *execution,
+	}
+	if event.HasCheck() {
+		captured.Check = event.Check.Name
+	}
+	captured.Output, captured.OutputTruncated = output, false
+	if len(output) > p.outputMaxSize {
+		captured.Output = output[:p.outputMaxSize]
+		captured.OutputTruncated = true
+	}
+
+	if err := p.store.AddHandlerOutput(ctx, execution.Handler, captured, p.outputHistory); err != nil {
+		fields := logrus.Fields{
+			"namespace": captured.Namespace,
+			"handler":   execution.Handler,
+		}
+		logger.WithFields(fields).WithError(err).Error("failed to capture the handler output")
+	}
+}
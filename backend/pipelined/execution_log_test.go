@@ -0,0 +1,107 @@
+package pipelined
+
+import (
+	"strings"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleEventRecordsPipeline(t *testing.T) {
+	store := &mockstore.MockStore{}
+	p := &Pipelined{store: store, executor: command.NewExecutor()}
+
+	pipe := corev2.FixtureHandler("pipe")
+	pipe.Type = "pipe"
+	pipe.Command = "cat"
+	pipe.Mutator = "only_check_output"
+	filtered := corev2.FixtureHandler("filtered")
+	filtered.Type = "pipe"
+	filtered.Filters = []string{"has_metrics"}
+
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.Output = "ok"
+	event.Check.Handlers = []string{"pipe", "filtered"}
+
+	store.On("GetHandlerByName", mock.Anything, "pipe").Return(pipe, nil)
+	store.On("GetHandlerByName", mock.Anything, "filtered").Return(filtered, nil)
+	store.On("UpdateEventPipeline", mock.Anything, mock.Anything).Return(nil)
+	store.On("GetRoutes", mock.Anything).Return([]*corev2.Route(nil), nil)
+
+	require.NoError(t, p.handleEvent(event))
+
+	store.AssertNumberOfCalls(t, "UpdateEventPipeline", 1)
+	pipeline := store.Calls[len(store.Calls)-1].Arguments.Get(1).(*corev2.EventPipeline)
+	assert.Equal(t, "default", pipeline.Namespace)
+	assert.Equal(t, "entity1", pipeline.Entity)
+	assert.Equal(t, "check1", pipeline.Check)
+	require.Len(t, pipeline.Handlers, 2)
+
+	assert.Equal(t, "filtered", pipeline.Handlers[0].Handler)
+	assert.True(t, pipeline.Handlers[0].Filtered)
+
+	assert.Equal(t, "pipe", pipeline.Handlers[1].Handler)
+	assert.False(t, pipeline.Handlers[1].Filtered)
+	assert.Equal(t, 0, pipeline.Handlers[1].Status)
+	assert.Equal(t, "ok", pipeline.Handlers[1].Output)
+	assert.NotZero(t, pipeline.Handlers[1].Executed)
+}
+
+func TestSetExecutionOutput(t *testing.T) {
+	execution := &corev2.HandlerExecution{}
+	setExecutionOutput(execution, "ok")
+	assert.Equal(t, "ok", execution.Output)
+	assert.False(t, execution.OutputTruncated)
+
+	setExecutionOutput(execution, strings.Repeat("a", maxExecutionOutput+1))
+	assert.Len(t, execution.Output, maxExecutionOutput)
+	assert.True(t, execution.OutputTruncated)
+}
+
+func TestHandleEventCapturesOutput(t *testing.T) {
+	store := &mockstore.MockStore{}
+	p := &Pipelined{store: store, executor: command.NewExecutor(), outputHistory: 10, outputMaxSize: 3}
+
+	pipe := corev2.FixtureHandler("pipe")
+	pipe.Type = "pipe"
+	pipe.Command = "cat"
+	pipe.Mutator = "only_check_output"
+
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.Output = "okay"
+	event.Check.Handlers = []string{"pipe"}
+
+	store.On("GetHandlerByName", mock.Anything, "pipe").Return(pipe, nil)
+	store.On("UpdateEventPipeline", mock.Anything, mock.Anything).Return(nil)
+	store.On("GetRoutes", mock.Anything).Return([]*corev2.Route(nil), nil)
+	store.On("AddHandlerOutput", mock.Anything, "pipe", mock.Anything, 10).Return(nil)
+
+	require.NoError(t, p.handleEvent(event))
+
+	store.AssertNumberOfCalls(t, "AddHandlerOutput", 1)
+	var captured *corev2.HandlerOutput
+	for _, call := range store.Calls {
+		if call.Method == "AddHandlerOutput" {
+			captured = call.Arguments.Get(2).(*corev2.HandlerOutput)
+		}
+	}
+	assert.Equal(t, "default", captured.Namespace)
+	assert.Equal(t, "entity1", captured.Entity)
+	assert.Equal(t, "check1", captured.Check)
+	assert.Equal(t, "pipe", captured.Handler)
+	assert.Equal(t, "oka", captured.Output)
+	assert.True(t, captured.OutputTruncated)
+}
+
+func TestValidateHandlerOutputCapture(t *testing.T) {
+	assert.NoError(t, validateHandlerOutputCapture(0, DefaultHandlerOutputMaxSize))
+	assert.NoError(t, validateHandlerOutputCapture(64, DefaultHandlerOutputMaxSize))
+	assert.Error(t, validateHandlerOutputCapture(-1, DefaultHandlerOutputMaxSize))
+	assert.Error(t, validateHandlerOutputCapture(10, 0))
+	assert.Error(t, validateHandlerOutputCapture(65, DefaultHandlerOutputMaxSize))
+}
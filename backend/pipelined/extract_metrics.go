@@ -0,0 +1,110 @@
+package pipelined
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/sensu/sensu-go/types"
+)
+
+// extractMetricsMutator produces the JSON encoding of the Sensu event, with
+// the numeric values of the allow list of the extract_metrics mutator added to
+// its metric points. The allow list names check labels, annotations and paths
+// in the JSON output, e.g. "disk.usage" or "cpus.0.load", and the values are
+// named after them. It is used with checks that don't produce metrics in one
+// of the supported output metric formats.
+func (p *Pipelined) extractMetricsMutator(mutator *types.Mutator, event *types.Event) ([]byte, error) {
+	if !event.HasCheck() {
+		return p.jsonMutator(event)
+	}
+
+	// The event is shared with the other handlers, only modify a copy
+	mutated := *event
+	metrics := &types.Metrics{}
+	if event.HasMetrics() {
+		*metrics = *event.Metrics
+	}
+	points := extractMetrics(event.Check, mutator.AllowList)
+	metrics.Points = append(append([]*types.MetricPoint{}, metrics.Points...), points...)
+	mutated.Metrics = metrics
+
+	return p.jsonMutator(&mutated)
+}
+
+// extractMetrics returns the metric points lifted from the check labels,
+// annotations and JSON output in the allow list.
+func extractMetrics(check *types.Check, allowList []string) []*types.MetricPoint {
+	allowed := make(map[string]bool, len(allowList))
+	for _, name := range allowList {
+		allowed[name] = true
+	}
+
+	points := []*types.MetricPoint{}
+	addPoint := func(name string, value float64) {
+		if !allowed[name] {
+			return
+		}
+		points = append(points, &types.MetricPoint{
+			Name:      name,
+			Value:     value,
+			Timestamp: check.Executed,
+			Tags:      []*types.MetricTag{},
+		})
+	}
+
+	for _, values := range []map[string]string{check.Labels, check.Annotations} {
+		for _, key := range sortedKeys(values) {
+			if value, err := strconv.ParseFloat(values[key], 64); err == nil {
+				addPoint(key, value)
+			}
+		}
+	}
+
+	var output interface{}
+	if err := json.Unmarshal([]byte(check.Output), &output); err == nil {
+		walkNumbers("", output, addPoint)
+	}
+
+	return points
+}
+
+// walkNumbers calls fn with the path and value of every number found in the
+// decoded JSON value.
+func walkNumbers(path string, value interface{}, fn func(string, float64)) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+
+	switch value := value.(type) {
+	case float64:
+		if path != "" {
+			fn(path, value)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkNumbers(join(key), value[key], fn)
+		}
+	case []interface{}:
+		for i, elem := range value {
+			walkNumbers(join(strconv.Itoa(i)), elem, fn)
+		}
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,105 @@
+package pipelined
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractMetrics(t *testing.T) {
+	testCases := []struct {
+		name        string
+		allowList   []string
+		labels      map[string]string
+		annotations map[string]string
+		output      string
+		expected    map[string]float64
+	}{
+		{
+			name:      "no numeric values",
+			allowList: []string{"region", "status"},
+			labels:    map[string]string{"region": "us-west-1"},
+			output:    "CheckHTTP OK: 200",
+			expected:  map[string]float64{},
+		},
+		{
+			name:        "labels and annotations",
+			allowList:   []string{"cores", "load"},
+			labels:      map[string]string{"region": "us-west-1", "cores": "4", "port": "8080"},
+			annotations: map[string]string{"load": "0.75", "retries": "3"},
+			expected:    map[string]float64{"cores": 4, "load": 0.75},
+		},
+		{
+			name:      "json output",
+			allowList: []string{"disk.usage", "cpus.0.load", "cpus.1.load"},
+			output:    `{"disk": {"usage": 42.5, "free": 57.5, "mount": "/"}, "cpus": [{"load": 1}, {"load": 2}], "ok": true}`,
+			expected: map[string]float64{
+				"disk.usage":  42.5,
+				"cpus.0.load": 1,
+				"cpus.1.load": 2,
+			},
+		},
+		{
+			name:     "empty allow list",
+			labels:   map[string]string{"cores": "4"},
+			output:   `{"disk": {"usage": 42.5}}`,
+			expected: map[string]float64{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			check := types.FixtureCheck("check1")
+			check.Labels = tc.labels
+			check.Annotations = tc.annotations
+			check.Output = tc.output
+			check.Executed = 1560000000
+
+			points := extractMetrics(check, tc.allowList)
+			values := map[string]float64{}
+			for _, point := range points {
+				assert.Equal(t, check.Executed, point.Timestamp)
+				values[point.Name] = point.Value
+			}
+			assert.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestExtractMetricsMutator(t *testing.T) {
+	store := &mockstore.MockStore{}
+	p, err := New(Config{Store: store})
+	require.NoError(t, err)
+
+	mutator := types.FixtureMutator("metrics")
+	mutator.Type = types.MutatorExtractMetricsType
+	mutator.AllowList = []string{"requests"}
+	store.On("GetMutatorByName", mock.Anything, "metrics").Return(mutator, nil)
+
+	event := &types.Event{
+		Entity: types.FixtureEntity("entity1"),
+		Check:  &types.Check{Output: `{"requests": 12, "errors": 1}`},
+		Metrics: &types.Metrics{
+			Handlers: []string{"influxdb"},
+			Points:   []*types.MetricPoint{{Name: "latency", Value: 0.2, Tags: []*types.MetricTag{}}},
+		},
+	}
+	handler := &types.Handler{Mutator: "metrics"}
+	eventData, err := p.mutateEvent(handler, event)
+	require.NoError(t, err)
+
+	var mutated types.Event
+	require.NoError(t, json.Unmarshal(eventData, &mutated))
+	require.Len(t, mutated.Metrics.Points, 2)
+	assert.Equal(t, "latency", mutated.Metrics.Points[0].Name)
+	assert.Equal(t, "requests", mutated.Metrics.Points[1].Name)
+	assert.Equal(t, []string{"influxdb"}, mutated.Metrics.Handlers)
+
+	// The original event is left untouched
+	assert.Len(t, event.Metrics.Points, 1)
+}
@@ -89,6 +89,12 @@ func evaluateEventFilter(event *types.Event, filter *types.EventFilter, assets a
 // filterEvent filters a Sensu event, determining if it will continue
 // through the Sensu pipeline. Returns true if the event should be filtered/denied.
 func (p *Pipelined) filterEvent(handler *types.Handler, event *types.Event) bool {
+	return p.deniedBy(handler, event) != ""
+}
+
+// deniedBy returns the name of the first filter of the handler denying the
+// event, or an empty string if the event is allowed.
+func (p *Pipelined) deniedBy(handler *types.Handler, event *types.Event) string {
 	// Prepare the logging
 	fields := utillogging.EventFields(event, false)
 	fields["handler"] = handler.Name
@@ -103,19 +109,19 @@ func (p *Pipelined) filterEvent(handler *types.Handler, event *types.Event) bool
 			// Deny an event if it is neither an incident nor resolution.
 			if !event.IsIncident() && !event.IsResolution() {
 				logger.WithFields(fields).Debug("denying event that is not an incident/resolution")
-				return true
+				return filterName
 			}
 		case "has_metrics":
 			// Deny an event if it does not have metrics
 			if !event.HasMetrics() {
 				logger.WithFields(fields).Debug("denying event without metrics")
-				return true
+				return filterName
 			}
 		case "not_silenced":
 			// Deny event that is silenced.
 			if event.IsSilenced() {
 				logger.WithFields(fields).Debug("denying event that is silenced")
-				return true
+				return filterName
 			}
 		default:
 			// Retrieve the filter from the store with its name
@@ -124,7 +130,7 @@ func (p *Pipelined) filterEvent(handler *types.Handler, event *types.Event) bool
 			if err != nil {
 				logger.WithFields(fields).WithError(err).
 					Warning("could not retrieve filter")
-				return false
+				return ""
 			}
 
 			if filter != nil {
@@ -140,7 +146,7 @@ func (p *Pipelined) filterEvent(handler *types.Handler, event *types.Event) bool
 				filtered := evaluateEventFilter(event, filter, assets)
 				if filtered {
 					logger.WithFields(fields).Debug("denying event with custom filter")
-					return true
+					return filterName
 				}
 				continue
 			}
@@ -172,11 +178,11 @@ func (p *Pipelined) filterEvent(handler *types.Handler, event *types.Event) bool
 			}
 			if filtered {
 				logger.WithFields(fields).Debug("denying event with custom filter extension")
-				return true
+				return filterName
 			}
 		}
 	}
 
 	logger.WithFields(fields).Debug("allowing event")
-	return false
+	return ""
 }
@@ -9,6 +9,7 @@ import (
 	"os"
 	"time"
 
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/asset"
 	"github.com/sensu/sensu-go/command"
 	"github.com/sensu/sensu-go/rpc"
@@ -43,17 +44,7 @@ func (p *Pipelined) handleEvent(event *types.Event) error {
 	// Prepare log entry
 	fields := utillogging.EventFields(event, false)
 
-	var handlerList []string
-
-	if event.HasCheck() {
-		handlerList = append(handlerList, event.Check.Handlers...)
-	}
-
-	if event.HasMetrics() {
-		handlerList = append(handlerList, event.Metrics.Handlers...)
-	}
-
-	handlers, err := p.expandHandlers(ctx, handlerList, 1)
+	handlers, err := p.expandHandlers(ctx, p.eventHandlers(ctx, event), 1)
 	if err != nil {
 		return err
 	}
@@ -63,43 +54,127 @@ func (p *Pipelined) handleEvent(event *types.Event) error {
 		return nil
 	}
 
+	pipeline := newEventPipeline(event)
+	defer p.storeEventPipeline(ctx, pipeline)
+
 	for _, u := range handlers {
 		handler := u.Handler
 		fields["handler"] = handler.Name
 
+		execution := &corev2.HandlerExecution{
+			Handler:  handler.Name,
+			Type:     handler.Type,
+			Executed: time.Now().Unix(),
+		}
+		pipeline.Handlers = append(pipeline.Handlers, execution)
+
 		if filtered := p.filterEvent(handler, event); filtered {
 			logger.WithFields(fields).Info("event filtered")
+			execution.Filtered = true
 			continue
 		}
 
 		eventData, err := p.mutateEvent(handler, event)
 		if err != nil {
+			execution.Error = err.Error()
+			p.captureHandlerOutput(ctx, event, execution, "")
 			continue
 		}
 
 		logger.WithFields(fields).Info("sending event to handler")
 
+		var output string
+		start := time.Now()
 		switch handler.Type {
 		case "pipe":
-			if _, err := p.pipeHandler(handler, eventData); err != nil {
+			result, err := p.pipeHandler(handler, eventData)
+			if err != nil {
 				logger.WithFields(fields).Error(err)
+				execution.Error = err.Error()
+			} else {
+				execution.Status = result.Status
+				output = result.Output
+				setExecutionOutput(execution, output)
 			}
 		case "tcp", "udp":
 			if _, err := p.socketHandler(handler, eventData); err != nil {
 				logger.WithFields(fields).Error(err)
+				execution.Error = err.Error()
 			}
 		case "grpc":
-			if _, err := p.grpcHandler(u.Extension, event, eventData); err != nil {
+			result, err := p.grpcHandler(u.Extension, event, eventData)
+			if err != nil {
 				logger.WithFields(fields).Error(err)
+				execution.Error = err.Error()
+			} else {
+				output = result.Output
+				setExecutionOutput(execution, output)
 			}
 		default:
 			return errors.New("unknown handler type")
 		}
+		execution.Duration = time.Since(start).Seconds()
+		p.captureHandlerOutput(ctx, event, execution, output)
 	}
 
 	return nil
 }
 
+// eventHandlers returns the names of the handlers of the event check, or the
+// default handlers of its namespace if the check has none, of its metrics, and
+// of the routes selecting the event.
+func (p *Pipelined) eventHandlers(ctx context.Context, event *types.Event) []string {
+	var handlerList []string
+
+	if event.HasCheck() {
+		if len(event.Check.Handlers) > 0 {
+			handlerList = append(handlerList, event.Check.Handlers...)
+		} else {
+			handlerList = append(handlerList, p.defaultHandlers(ctx, event)...)
+		}
+	}
+
+	if event.HasMetrics() {
+		handlerList = append(handlerList, event.Metrics.Handlers...)
+	}
+
+	return append(handlerList, p.routedHandlers(ctx, event)...)
+}
+
+// defaultHandlers returns the names of the default handlers of the namespace
+// of the event. The event is not sent to them if the namespace can't be
+// retrieved.
+func (p *Pipelined) defaultHandlers(ctx context.Context, event *types.Event) []string {
+	namespace, err := p.store.GetNamespace(ctx, event.Entity.Namespace)
+	if err != nil {
+		logger.WithFields(utillogging.EventFields(event, false)).WithError(err).Error("unable to retrieve the default handlers of the namespace")
+		return nil
+	}
+	if namespace == nil {
+		return nil
+	}
+	return namespace.DefaultHandlers
+}
+
+// routedHandlers returns the names of the handlers of the routes selecting the
+// event. The event is still sent to the handlers of its check and metrics if
+// the routes can't be retrieved.
+func (p *Pipelined) routedHandlers(ctx context.Context, event *types.Event) []string {
+	routes, err := p.store.GetRoutes(ctx)
+	if err != nil {
+		logger.WithFields(utillogging.EventFields(event, false)).WithError(err).Error("unable to retrieve the routes")
+		return nil
+	}
+
+	var handlerList []string
+	for _, route := range routes {
+		if route.Matches(event) {
+			handlerList = append(handlerList, route.Handlers...)
+		}
+	}
+	return handlerList
+}
+
 // expandHandlers turns a list of Sensu handler names into a list of
 // handlers, while expanding handler sets with support for some
 // nesting. Handlers are fetched from etcd.
@@ -187,9 +262,18 @@ func (p *Pipelined) pipeHandler(handler *types.Handler, eventData []byte) (*comm
 	// Prepare environment variables
 	env := environment.MergeEnvironments(os.Environ(), handler.EnvVars)
 
+	// If Timeout is not specified, use the default, if any. A timeout of 0
+	// doesn't limit the execution.
+	timeout := int(handler.Timeout)
+	if timeout == 0 {
+		timeout = p.defaultTimeout
+	}
+
 	handlerExec := command.ExecutionRequest{}
 	handlerExec.Command = handler.Command
-	handlerExec.Timeout = int(handler.Timeout)
+	handlerExec.Timeout = timeout
+	handlerExec.GracePeriod = p.gracePeriod
+	handlerExec.KillProcessGroup = true
 	handlerExec.Env = env
 	handlerExec.Input = string(eventData[:])
 
@@ -220,6 +304,10 @@ func (p *Pipelined) pipeHandler(handler *types.Handler, eventData []byte) (*comm
 	if err != nil {
 		logger.WithFields(fields).WithError(err).Error("failed to execute event pipe handler")
 	} else {
+		recordHandlerExecution(handler, result)
+		if result.Leaked {
+			logger.WithFields(fields).Warn("killed the processes left behind by the event pipe handler")
+		}
 		fields["status"] = result.Status
 		fields["output"] = result.Output
 		logger.WithFields(fields).Info("event pipe handler executed")
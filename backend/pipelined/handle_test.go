@@ -67,6 +67,7 @@ func TestPipelinedHandleEvent(t *testing.T) {
 	p := &Pipelined{}
 
 	store := &mockstore.MockStore{}
+	store.On("GetRoutes", mock.Anything).Return([]*corev2.Route(nil), nil)
 	p.store = store
 
 	entity := types.FixtureEntity("entity1")
@@ -343,3 +344,62 @@ func TestPipelinedGRPCHandler(t *testing.T) {
 	assert.Equal(t, "ok", result.Output)
 	assert.Equal(t, "", result.Error)
 }
+
+func TestPipelinedEventHandlersRoutes(t *testing.T) {
+	store := &mockstore.MockStore{}
+	p := &Pipelined{store: store}
+
+	store.On("GetRoutes", mock.Anything).Return([]*corev2.Route{
+		{Name: "db", Labels: map[string]string{"team": "db"}, Handlers: []string{"opsgenie"}},
+		{Name: "critical", Severities: []string{"critical"}, Handlers: []string{"pagerduty"}},
+	}, nil)
+
+	event := &corev2.Event{
+		Entity: corev2.FixtureEntity("entity1"),
+		Check:  corev2.FixtureCheck("check1"),
+	}
+	event.Entity.Labels = map[string]string{"team": "db"}
+	event.Check.Handlers = []string{"slack"}
+	event.Check.Status = 1
+
+	assert.Equal(t, []string{"slack", "opsgenie"}, p.eventHandlers(context.Background(), event))
+
+	event.Check.Status = 2
+	assert.Equal(t, []string{"slack", "opsgenie", "pagerduty"}, p.eventHandlers(context.Background(), event))
+}
+
+func TestPipelinedEventHandlersRoutesError(t *testing.T) {
+	store := &mockstore.MockStore{}
+	p := &Pipelined{store: store}
+
+	store.On("GetRoutes", mock.Anything).Return([]*corev2.Route(nil), errors.New("error"))
+
+	event := &corev2.Event{
+		Entity: corev2.FixtureEntity("entity1"),
+		Check:  corev2.FixtureCheck("check1"),
+	}
+	event.Check.Handlers = []string{"slack"}
+
+	assert.Equal(t, []string{"slack"}, p.eventHandlers(context.Background(), event))
+}
+
+func TestPipelinedEventHandlersDefaultHandlers(t *testing.T) {
+	store := &mockstore.MockStore{}
+	p := &Pipelined{store: store}
+
+	namespace := corev2.FixtureNamespace("default")
+	namespace.DefaultHandlers = []string{"pagerduty"}
+	store.On("GetNamespace", mock.Anything, "default").Return(namespace, nil)
+	store.On("GetRoutes", mock.Anything).Return([]*corev2.Route(nil), nil)
+
+	event := &corev2.Event{
+		Entity: corev2.FixtureEntity("entity1"),
+		Check:  corev2.FixtureCheck("check1"),
+	}
+	assert.Equal(t, []string{"pagerduty"}, p.eventHandlers(context.Background(), event))
+
+	// The handlers of the check replace the default handlers
+	event.Check.Handlers = []string{"slack"}
+	assert.Equal(t, []string{"slack"}, p.eventHandlers(context.Background(), event))
+	store.AssertNumberOfCalls(t, "GetNamespace", 1)
+}
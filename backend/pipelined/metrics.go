@@ -0,0 +1,76 @@
+package pipelined
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/types"
+)
+
+const (
+	// HandlerTimeoutsCounterVec is the name of the prometheus counter vec
+	// used to count the pipe handler executions that timed out.
+	HandlerTimeoutsCounterVec = "sensu_go_handler_timeouts"
+
+	// HandlerLeakedProcessesCounterVec is the name of the prometheus counter
+	// vec used to count the pipe handler executions that left processes
+	// behind.
+	HandlerLeakedProcessesCounterVec = "sensu_go_handler_leaked_processes"
+
+	// HandlerZombiesCounterVec is the name of the prometheus counter vec used
+	// to count the pipe handler executions whose processes could not be
+	// killed.
+	HandlerZombiesCounterVec = "sensu_go_handler_zombies"
+)
+
+var (
+	handlerLabels = []string{"namespace", "handler"}
+
+	// HandlerTimeouts counts the pipe handler executions that timed out.
+	HandlerTimeouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: HandlerTimeoutsCounterVec,
+			Help: "The total number of pipe handler executions that timed out",
+		},
+		handlerLabels,
+	)
+
+	// HandlerLeakedProcesses counts the pipe handler executions that left
+	// processes behind, which were killed.
+	HandlerLeakedProcesses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: HandlerLeakedProcessesCounterVec,
+			Help: "The total number of pipe handler executions that left processes behind",
+		},
+		handlerLabels,
+	)
+
+	// HandlerZombies counts the pipe handler executions whose processes could
+	// not be killed after timing out.
+	HandlerZombies = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: HandlerZombiesCounterVec,
+			Help: "The total number of pipe handler executions whose processes could not be killed",
+		},
+		handlerLabels,
+	)
+)
+
+func registerMetrics() {
+	_ = prometheus.Register(HandlerTimeouts)
+	_ = prometheus.Register(HandlerLeakedProcesses)
+	_ = prometheus.Register(HandlerZombies)
+}
+
+// recordHandlerExecution updates the handler metrics with the outcome of a
+// pipe handler execution.
+func recordHandlerExecution(handler *types.Handler, result *command.ExecutionResponse) {
+	if result.TimedOut {
+		HandlerTimeouts.WithLabelValues(handler.Namespace, handler.Name).Inc()
+	}
+	if result.Leaked {
+		HandlerLeakedProcesses.WithLabelValues(handler.Namespace, handler.Name).Inc()
+	}
+	if result.Zombie {
+		HandlerZombies.WithLabelValues(handler.Namespace, handler.Name).Inc()
+	}
+}
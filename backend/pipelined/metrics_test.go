@@ -0,0 +1,46 @@
+// +build !windows
+
+package pipelined
+
+import (
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelinedPipeHandlerLeakedProcesses(t *testing.T) {
+	p := &Pipelined{}
+	p.executor = &command.ExecutionRequest{}
+
+	handler := types.FixtureHandler("leaky")
+	handler.Command = "sleep 10 >/dev/null 2>&1 & echo done"
+
+	result, err := p.pipeHandler(handler, []byte("{}"))
+	require.NoError(t, err)
+	assert.True(t, result.Leaked)
+
+	var metric dto.Metric
+	require.NoError(t, HandlerLeakedProcesses.WithLabelValues(handler.Namespace, handler.Name).Write(&metric))
+	assert.Equal(t, float64(1), metric.GetCounter().GetValue())
+}
+
+func TestPipelinedPipeHandlerTimeout(t *testing.T) {
+	p := &Pipelined{}
+	p.executor = &command.ExecutionRequest{}
+
+	handler := types.FixtureHandler("slow")
+	handler.Command = "sleep 10"
+	handler.Timeout = 1
+
+	result, err := p.pipeHandler(handler, []byte("{}"))
+	require.NoError(t, err)
+	assert.True(t, result.TimedOut)
+
+	var metric dto.Metric
+	require.NoError(t, HandlerTimeouts.WithLabelValues(handler.Namespace, handler.Name).Write(&metric))
+	assert.Equal(t, float64(1), metric.GetCounter().GetValue())
+}
@@ -41,6 +41,15 @@ func (p *Pipelined) mutateEvent(handler *types.Handler, event *types.Event) ([]b
 		}
 	}
 
+	if handler.Mutator == OnCallMutator {
+		eventData, err := p.onCallMutator(event)
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Error("failed to mutate event")
+			return nil, err
+		}
+		return eventData, nil
+	}
+
 	ctx := context.WithValue(context.Background(), types.NamespaceKey, event.Entity.Namespace)
 	fields["mutator"] = handler.Mutator
 
@@ -75,6 +84,15 @@ func (p *Pipelined) mutateEvent(handler *types.Handler, event *types.Event) ([]b
 		return eventData, nil
 	}
 
+	if mutator.Type == types.MutatorExtractMetricsType {
+		eventData, err := p.extractMetricsMutator(mutator, event)
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Error("failed to mutate the event")
+			return nil, err
+		}
+		return eventData, nil
+	}
+
 	eventData, err := p.pipeMutator(mutator, event)
 
 	if err != nil {
@@ -0,0 +1,61 @@
+package pipelined
+
+import (
+	"context"
+
+	"github.com/sensu/sensu-go/backend/oncall"
+	"github.com/sensu/sensu-go/types"
+	utillogging "github.com/sensu/sensu-go/util/logging"
+)
+
+// OnCallMutator is the name of the built-in mutator that adds who is on call,
+// in the on-call schedule of the event, to its annotations.
+const OnCallMutator = "oncall"
+
+// onCallMutator produces the JSON encoding of the Sensu event, with who is on
+// call in its on-call schedule in its sensu.io/oncall annotation.
+func (p *Pipelined) onCallMutator(event *types.Event) ([]byte, error) {
+	return p.jsonMutator(p.onCallEvent(event))
+}
+
+// onCallEvent returns the event, with who is on call in the schedule given by
+// the sensu.io/oncall-source annotation of its check, or else of its entity,
+// in its sensu.io/oncall annotation. The event is returned as is if the
+// schedule can't be read.
+func (p *Pipelined) onCallEvent(event *types.Event) *types.Event {
+	source := onCallSource(event)
+	if source == "" || p.onCall == nil {
+		return event
+	}
+
+	target, err := p.onCall.Lookup(context.Background(), source)
+	if err != nil {
+		fields := utillogging.EventFields(event, false)
+		fields["source"] = source
+		logger.WithFields(fields).WithError(err).Warn("could not look up who is on call")
+		return event
+	}
+
+	// The event is shared with the other handlers, only modify a copy
+	mutated := *event
+	mutated.Annotations = make(map[string]string, len(event.Annotations)+1)
+	for key, value := range event.Annotations {
+		mutated.Annotations[key] = value
+	}
+	mutated.Annotations[oncall.TargetAnnotation] = target
+	return &mutated
+}
+
+// onCallSource returns the on-call schedule of the event, given by its check,
+// or else by its entity.
+func onCallSource(event *types.Event) string {
+	if event.HasCheck() {
+		if source := event.Check.Annotations[oncall.SourceAnnotation]; source != "" {
+			return source
+		}
+	}
+	if event.Entity != nil {
+		return event.Entity.Annotations[oncall.SourceAnnotation]
+	}
+	return ""
+}
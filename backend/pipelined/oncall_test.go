@@ -0,0 +1,81 @@
+package pipelined
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sensu/sensu-go/backend/oncall"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnCallEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "BEGIN:VCALENDAR\r\n"+
+			"BEGIN:VEVENT\r\n"+
+			"DTSTART:20000101T000000Z\r\n"+
+			"DTEND:21000101T000000Z\r\n"+
+			"SUMMARY:alice\r\n"+
+			"END:VEVENT\r\n"+
+			"END:VCALENDAR\r\n")
+	}))
+	defer server.Close()
+
+	resolver, err := oncall.NewResolver(oncall.Config{ICalHosts: []string{"127.0.0.1"}})
+	require.NoError(t, err)
+	p := &Pipelined{onCall: resolver}
+
+	testCases := []struct {
+		name             string
+		checkSource      string
+		entitySource     string
+		expectedOnCall   string
+		expectedAnnotate bool
+	}{
+		{
+			name: "no schedule",
+		},
+		{
+			name:             "entity schedule",
+			entitySource:     server.URL,
+			expectedOnCall:   "alice",
+			expectedAnnotate: true,
+		},
+		{
+			name:             "check schedule",
+			checkSource:      server.URL,
+			entitySource:     "pagerduty:PSCHED1",
+			expectedOnCall:   "alice",
+			expectedAnnotate: true,
+		},
+		{
+			name:         "unreadable schedule",
+			entitySource: "ftp://example.com/oncall.ics",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event := types.FixtureEvent("entity1", "check1")
+			event.Check.Annotations = map[string]string{}
+			if tc.checkSource != "" {
+				event.Check.Annotations[oncall.SourceAnnotation] = tc.checkSource
+			}
+			event.Entity.Annotations = map[string]string{}
+			if tc.entitySource != "" {
+				event.Entity.Annotations[oncall.SourceAnnotation] = tc.entitySource
+			}
+
+			mutated := p.onCallEvent(event)
+			target, ok := mutated.Annotations[oncall.TargetAnnotation]
+			assert.Equal(t, tc.expectedAnnotate, ok)
+			assert.Equal(t, tc.expectedOnCall, target)
+
+			// The event shared with the other handlers is left untouched
+			assert.NotContains(t, event.Annotations, oncall.TargetAnnotation)
+		})
+	}
+}
@@ -4,9 +4,11 @@ package pipelined
 import (
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/sensu/sensu-go/asset"
 	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/oncall"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/command"
 	"github.com/sensu/sensu-go/rpc"
@@ -34,6 +36,11 @@ type Pipelined struct {
 	extensionExecutor ExtensionExecutorGetterFunc
 	executor          command.Executor
 	workerCount       int
+	gracePeriod       time.Duration
+	defaultTimeout    int
+	onCall            *oncall.Resolver
+	outputHistory     int
+	outputMaxSize     int
 }
 
 // Config configures a Pipelined.
@@ -44,6 +51,35 @@ type Config struct {
 	AssetGetter             asset.Getter
 	BufferSize              int
 	WorkerCount             int
+	HandlerGracePeriod      time.Duration
+
+	// DefaultHandlerTimeout is the execution timeout of the pipe handlers
+	// without one. They are not limited if it is 0.
+	DefaultHandlerTimeout time.Duration
+
+	// OnCall looks up who is on call for the oncall mutator.
+	OnCall *oncall.Resolver
+
+	// HandlerOutputHistory is the number of outputs of the last executions
+	// captured for each handler, none if 0.
+	HandlerOutputHistory int
+
+	// HandlerOutputMaxSize is the size, in bytes, at which the captured
+	// handler outputs are truncated.
+	HandlerOutputMaxSize int
+}
+
+// handlerTimeout returns the timeout, in seconds, of the pipe handler
+// executions, rounded up to one second.
+func handlerTimeout(timeout time.Duration) int {
+	if timeout <= 0 {
+		return 0
+	}
+	seconds := int(timeout / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
 }
 
 // Option is a functional option used to configure Pipelined.
@@ -57,6 +93,12 @@ func New(c Config, options ...Option) (*Pipelined, error) {
 	if c.WorkerCount == 0 {
 		c.WorkerCount = 1
 	}
+	if c.HandlerOutputMaxSize == 0 {
+		c.HandlerOutputMaxSize = DefaultHandlerOutputMaxSize
+	}
+	if err := validateHandlerOutputCapture(c.HandlerOutputHistory, c.HandlerOutputMaxSize); err != nil {
+		return nil, err
+	}
 
 	p := &Pipelined{
 		store:             c.Store,
@@ -70,12 +112,18 @@ func New(c Config, options ...Option) (*Pipelined, error) {
 		workerCount:       c.WorkerCount,
 		executor:          command.NewExecutor(),
 		assetGetter:       c.AssetGetter,
+		gracePeriod:       c.HandlerGracePeriod,
+		defaultTimeout:    handlerTimeout(c.DefaultHandlerTimeout),
+		onCall:            c.OnCall,
+		outputHistory:     c.HandlerOutputHistory,
+		outputMaxSize:     c.HandlerOutputMaxSize,
 	}
 	for _, o := range options {
 		if err := o(p); err != nil {
 			return nil, err
 		}
 	}
+	registerMetrics()
 	return p, nil
 }
 
@@ -0,0 +1,123 @@
+package pipelined
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sensu/sensu-go/types"
+)
+
+// Simulation describes what the pipeline would do with an event.
+type Simulation struct {
+	// Handlers lists the outcome of the event for each of its handlers,
+	// ordered by handler name.
+	Handlers []HandlerSimulation `json:"handlers"`
+}
+
+// HandlerSimulation describes what a handler would do with an event.
+type HandlerSimulation struct {
+	// Handler is the name of the handler.
+	Handler string `json:"handler"`
+
+	// Type is the type of the handler.
+	Type string `json:"type"`
+
+	// FilteredBy is the name of the filter that would deny the event, if any.
+	FilteredBy string `json:"filtered_by,omitempty"`
+
+	// Mutator is the name of the mutator of the handler, if any.
+	Mutator string `json:"mutator,omitempty"`
+
+	// MutatorSkipped is true if the mutator of the handler was not executed,
+	// being an on-call, pipe or extension mutator, so Output is empty.
+	MutatorSkipped bool `json:"mutator_skipped,omitempty"`
+
+	// Output is the event data that the handler would receive, as produced
+	// by the mutator.
+	Output string `json:"output,omitempty"`
+
+	// Error is the error that would prevent the handler from receiving the
+	// event, if any.
+	Error string `json:"error,omitempty"`
+
+	// Handled is true if the handler would be executed for the event.
+	Handled bool `json:"handled"`
+}
+
+// Simulate takes an event through the filters and mutators of its handlers,
+// including the handlers of the routes selecting it, and reports what would
+// happen to it. Handlers are never executed. Only the JSON, only_check_output
+// and extract_metrics mutators, which have no side effects, are executed,
+// unless executeMutators is true: the on-call, pipe and extension mutators then
+// run as they would for a real event.
+func (p *Pipelined) Simulate(ctx context.Context, event *types.Event, executeMutators bool) (*Simulation, error) {
+	handlers, err := p.expandHandlers(ctx, p.eventHandlers(ctx, event), 1)
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &Simulation{Handlers: []HandlerSimulation{}}
+	for _, u := range handlers {
+		handler := u.Handler
+		result := HandlerSimulation{
+			Handler: handler.Name,
+			Type:    handler.Type,
+			Mutator: handler.Mutator,
+		}
+
+		if result.FilteredBy = p.deniedBy(handler, event); result.FilteredBy == "" {
+			var eventData []byte
+			var err error
+			if executeMutators || p.sideEffectFreeMutator(ctx, handler.Mutator) {
+				eventData, err = p.mutateEvent(handler, event)
+			} else {
+				result.MutatorSkipped = true
+			}
+			switch {
+			case err != nil:
+				result.Error = err.Error()
+			case !knownHandlerType(handler.Type):
+				result.Error = "unknown handler type"
+			default:
+				result.Output = string(eventData)
+				result.Handled = true
+			}
+		}
+
+		simulation.Handlers = append(simulation.Handlers, result)
+	}
+
+	sort.Slice(simulation.Handlers, func(i, j int) bool {
+		return simulation.Handlers[i].Handler < simulation.Handlers[j].Handler
+	})
+
+	return simulation, nil
+}
+
+// sideEffectFreeMutator returns true if the mutator of the given name is a
+// built-in mutator, the default JSON mutator if name is empty, or an
+// extract_metrics mutator. The on-call mutator is not, since it queries the
+// on-call schedules of external services.
+func (p *Pipelined) sideEffectFreeMutator(ctx context.Context, name string) bool {
+	switch name {
+	case "", "only_check_output":
+		return true
+	case OnCallMutator:
+		return false
+	}
+	mutator, err := p.store.GetMutatorByName(ctx, name)
+	if err != nil || mutator == nil {
+		return false
+	}
+	return mutator.Type == types.MutatorExtractMetricsType
+}
+
+// knownHandlerType returns true if the pipeline can execute handlers of the
+// given type.
+func knownHandlerType(typ string) bool {
+	switch typ {
+	case "pipe", "tcp", "udp", "grpc":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package pipelined
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelinedSimulate(t *testing.T) {
+	store := &mockstore.MockStore{}
+	p, err := New(Config{Store: store})
+	require.NoError(t, err)
+
+	incidents := types.FakeHandlerCommand("cat")
+	incidents.Name = "incidents"
+	incidents.Type = "pipe"
+	incidents.Filters = []string{"is_incident"}
+
+	metrics := types.FakeHandlerCommand("cat")
+	metrics.Name = "metrics"
+	metrics.Type = "tcp"
+	metrics.Mutator = "only_check_output"
+
+	set := &types.Handler{
+		ObjectMeta: types.ObjectMeta{Name: "set"},
+		Type:       "set",
+		Handlers:   []string{"incidents", "metrics"},
+	}
+
+	store.On("GetHandlerByName", mock.Anything, "set").Return(set, nil)
+	store.On("GetHandlerByName", mock.Anything, "incidents").Return(incidents, nil)
+	store.On("GetHandlerByName", mock.Anything, "metrics").Return(metrics, nil)
+	store.On("GetRoutes", mock.Anything).Return([]*corev2.Route(nil), nil)
+
+	event := types.FixtureEvent("entity1", "check1")
+	event.Check.Handlers = []string{"set"}
+	event.Check.Output = "foo"
+
+	simulation, err := p.Simulate(context.Background(), event, false)
+	require.NoError(t, err)
+
+	require.Len(t, simulation.Handlers, 2)
+	assert.Equal(t, HandlerSimulation{
+		Handler:    "incidents",
+		Type:       "pipe",
+		FilteredBy: "is_incident",
+	}, simulation.Handlers[0])
+	assert.Equal(t, HandlerSimulation{
+		Handler: "metrics",
+		Type:    "tcp",
+		Mutator: "only_check_output",
+		Output:  "foo",
+		Handled: true,
+	}, simulation.Handlers[1])
+}
+
+func TestPipelinedSimulateSkipsMutators(t *testing.T) {
+	store := &mockstore.MockStore{}
+	p, err := New(Config{Store: store})
+	require.NoError(t, err)
+
+	handler := types.FakeHandlerCommand("cat")
+	handler.Name = "slack"
+	handler.Type = "pipe"
+	handler.Mutator = "enrich"
+
+	mutator := &corev2.Mutator{ObjectMeta: corev2.ObjectMeta{Name: "enrich"}, Command: "echo enriched"}
+
+	store.On("GetHandlerByName", mock.Anything, "slack").Return(handler, nil)
+	store.On("GetMutatorByName", mock.Anything, "enrich").Return(mutator, nil)
+	store.On("GetRoutes", mock.Anything).Return([]*corev2.Route(nil), nil)
+
+	event := types.FixtureEvent("entity1", "check1")
+	event.Check.Handlers = []string{"slack"}
+
+	// The pipe mutator is not executed
+	simulation, err := p.Simulate(context.Background(), event, false)
+	require.NoError(t, err)
+	require.Len(t, simulation.Handlers, 1)
+	assert.Equal(t, HandlerSimulation{
+		Handler:        "slack",
+		Type:           "pipe",
+		Mutator:        "enrich",
+		MutatorSkipped: true,
+		Handled:        true,
+	}, simulation.Handlers[0])
+
+	// It is executed on request
+	simulation, err = p.Simulate(context.Background(), event, true)
+	require.NoError(t, err)
+	require.Len(t, simulation.Handlers, 1)
+	assert.False(t, simulation.Handlers[0].MutatorSkipped)
+	assert.Equal(t, "enriched\n", simulation.Handlers[0].Output)
+
+	// The extract_metrics mutators have no side effect and are executed
+	mutator.Type = corev2.MutatorExtractMetricsType
+	mutator.AllowList = []string{"cores"}
+	event.Check.Labels = map[string]string{"cores": "4"}
+	simulation, err = p.Simulate(context.Background(), event, false)
+	require.NoError(t, err)
+	require.Len(t, simulation.Handlers, 1)
+	assert.False(t, simulation.Handlers[0].MutatorSkipped)
+	assert.Contains(t, simulation.Handlers[0].Output, `"name":"cores"`)
+}
+
+func TestPipelinedSimulateSkipsOnCallMutator(t *testing.T) {
+	store := &mockstore.MockStore{}
+	p, err := New(Config{Store: store})
+	require.NoError(t, err)
+
+	handler := types.FakeHandlerCommand("cat")
+	handler.Name = "pagerduty"
+	handler.Type = "pipe"
+	handler.Mutator = OnCallMutator
+
+	store.On("GetHandlerByName", mock.Anything, "pagerduty").Return(handler, nil)
+	store.On("GetRoutes", mock.Anything).Return([]*corev2.Route(nil), nil)
+
+	event := types.FixtureEvent("entity1", "check1")
+	event.Check.Handlers = []string{"pagerduty"}
+
+	// The on-call schedules are not queried
+	simulation, err := p.Simulate(context.Background(), event, false)
+	require.NoError(t, err)
+	require.Len(t, simulation.Handlers, 1)
+	assert.Equal(t, HandlerSimulation{
+		Handler:        "pagerduty",
+		Type:           "pipe",
+		Mutator:        OnCallMutator,
+		MutatorSkipped: true,
+		Handled:        true,
+	}, simulation.Handlers[0])
+}
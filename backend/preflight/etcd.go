@@ -0,0 +1,201 @@
+package preflight
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/pkg/transport"
+	"github.com/sensu/sensu-go/backend/etcd"
+)
+
+// checkEtcd checks the embedded etcd, or the external etcd cluster.
+func checkEtcd(ctx context.Context, config Config) []Result {
+	quota := config.Backend.EtcdQuotaBackendBytes
+	if quota == 0 {
+		quota = etcd.DefaultQuotaBackendBytes
+	}
+	if config.Backend.NoEmbedEtcd {
+		return checkExternalEtcd(ctx, config, quota)
+	}
+	return checkEmbeddedEtcd(ctx, config, quota)
+}
+
+// checkExternalEtcd checks that the endpoints of the external etcd cluster are
+// reachable, that their databases are below the quota and that they raised no
+// alarm, and measures the clock skew of the members of the cluster.
+func checkExternalEtcd(ctx context.Context, config Config, quota int64) []Result {
+	tlsInfo := (transport.TLSInfo)(config.Backend.EtcdClientTLSInfo)
+	tlsConfig, err := tlsInfo.ClientConfig()
+	if err != nil {
+		return []Result{failure("etcd/connectivity", fmt.Sprintf("invalid etcd client TLS configuration: %s", err))}
+	}
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.Backend.EtcdAdvertiseClientURLs,
+		DialTimeout: config.Timeout,
+		TLS:         tlsConfig,
+	})
+	if err != nil {
+		return []Result{failure("etcd/connectivity", fmt.Sprintf("could not connect to etcd: %s", err))}
+	}
+	defer client.Close()
+
+	var results []Result
+	for _, endpoint := range config.Backend.EtcdAdvertiseClientURLs {
+		sctx, cancel := context.WithTimeout(ctx, config.Timeout)
+		status, err := client.Status(sctx, endpoint)
+		cancel()
+		if err != nil {
+			results = append(results, failure("etcd/connectivity", fmt.Sprintf("could not reach %s: %s", endpoint, err)))
+			continue
+		}
+		results = append(results,
+			ok("etcd/connectivity", fmt.Sprintf("%s is reachable, etcd version %s", endpoint, status.Version)),
+			checkQuota("etcd/quota", fmt.Sprintf("database of %s", endpoint), status.DbSize, quota),
+		)
+	}
+
+	actx, cancel := context.WithTimeout(ctx, config.Timeout)
+	alarms, err := client.AlarmList(actx)
+	cancel()
+	switch {
+	case err != nil:
+		results = append(results, failure("etcd/alarms", fmt.Sprintf("could not list the alarms: %s", err)))
+	case len(alarms.Alarms) == 0:
+		results = append(results, ok("etcd/alarms", "no alarm raised"))
+	default:
+		for _, alarm := range alarms.Alarms {
+			results = append(results, failure("etcd/alarms", fmt.Sprintf("member %x raised the %s alarm", alarm.MemberID, alarm.Alarm)))
+		}
+	}
+
+	mctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	members, err := client.MemberList(mctx)
+	cancel()
+	if err != nil {
+		return append(results, failure("etcd/members", fmt.Sprintf("could not list the members: %s", err)))
+	}
+	httpClient := newHTTPClient(tlsConfig, config.Timeout)
+	for _, member := range members.Members {
+		if len(member.ClientURLs) == 0 {
+			continue
+		}
+		check := "etcd/clock-skew/" + member.Name
+		skew, err := clockSkew(ctx, httpClient, member.ClientURLs[0])
+		if err != nil {
+			results = append(results, failure(check, fmt.Sprintf("could not reach %s: %s", member.ClientURLs[0], err)))
+			continue
+		}
+		results = append(results, checkClockSkew(check, skew, config.MaxClockSkew))
+	}
+	return results
+}
+
+// checkEmbeddedEtcd checks that the database of the embedded etcd is below the
+// quota, and measures the clock skew of the other members of the initial
+// cluster, through their peer URLs.
+func checkEmbeddedEtcd(ctx context.Context, config Config, quota int64) []Result {
+	var results []Result
+
+	db := filepath.Join(config.Backend.StateDir, "etcd", "data", "member", "snap", "db")
+	if info, err := os.Stat(db); err == nil {
+		results = append(results, checkQuota("etcd/quota", "database", info.Size(), quota))
+	} else if os.IsNotExist(err) {
+		results = append(results, ok("etcd/quota", "no database yet"))
+	} else {
+		results = append(results, failure("etcd/quota", fmt.Sprintf("could not read the database: %s", err)))
+	}
+
+	tlsInfo := (transport.TLSInfo)(config.Backend.EtcdPeerTLSInfo)
+	tlsConfig, err := tlsInfo.ClientConfig()
+	if err != nil {
+		return append(results, failure("etcd/peers", fmt.Sprintf("invalid etcd peer TLS configuration: %s", err)))
+	}
+	httpClient := newHTTPClient(tlsConfig, config.Timeout)
+	for _, member := range strings.Split(config.Backend.EtcdInitialCluster, ",") {
+		parts := strings.SplitN(member, "=", 2)
+		if len(parts) != 2 {
+			results = append(results, failure("etcd/peers", fmt.Sprintf("invalid initial cluster member %q", member)))
+			continue
+		}
+		name, peerURL := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if name == config.Backend.EtcdName {
+			continue
+		}
+		// The other members may not be started yet
+		check := "etcd/clock-skew/" + name
+		skew, err := clockSkew(ctx, httpClient, peerURL)
+		if err != nil {
+			results = append(results, warning(check, fmt.Sprintf("could not reach %s: %s", peerURL, err)))
+			continue
+		}
+		results = append(results, checkClockSkew(check, skew, config.MaxClockSkew))
+	}
+	return results
+}
+
+func checkQuota(check, what string, size, quota int64) Result {
+	switch {
+	case size >= quota:
+		return failure(check, fmt.Sprintf("%s size of %d bytes reached the quota of %d bytes", what, size, quota))
+	case float64(size) >= quotaWarningRatio*float64(quota):
+		return warning(check, fmt.Sprintf("%s size of %d bytes is close to the quota of %d bytes", what, size, quota))
+	}
+	return ok(check, fmt.Sprintf("%s size of %d bytes is below the quota of %d bytes", what, size, quota))
+}
+
+func newHTTPClient(tlsConfig *tls.Config, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+}
+
+// skewMeasurement is a clock skew measured from the Date header of a response.
+type skewMeasurement struct {
+	// skew is the difference between the Date header and the local time in
+	// the middle of the request.
+	skew time.Duration
+
+	// rtt is the duration of the request.
+	rtt time.Duration
+}
+
+// clockSkew measures the clock skew of the etcd server at the URL.
+func clockSkew(ctx context.Context, client *http.Client, u string) (skewMeasurement, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(u, "/")+"/version", nil)
+	if err != nil {
+		return skewMeasurement{}, err
+	}
+	start := time.Now()
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return skewMeasurement{}, err
+	}
+	rtt := time.Since(start)
+	_ = resp.Body.Close()
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return skewMeasurement{}, fmt.Errorf("invalid Date header: %s", err)
+	}
+	return skewMeasurement{skew: date.Sub(start.Add(rtt / 2)), rtt: rtt}, nil
+}
+
+// checkClockSkew reports the skews that certainly exceed max. The Date header
+// is truncated to the second, and the server generated it at some point of the
+// request, so the actual skew is between skew - rtt/2 and skew + 1s + rtt/2.
+func checkClockSkew(check string, m skewMeasurement, max time.Duration) Result {
+	low := m.skew - m.rtt/2
+	high := m.skew + time.Second + m.rtt/2
+	if low > max || high < -max {
+		return warning(check, fmt.Sprintf("clock skew of about %s exceeds %s", (m.skew+time.Second/2).Round(time.Second), max))
+	}
+	return ok(check, fmt.Sprintf("clock skew within %s", max))
+}
@@ -0,0 +1,127 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/backend"
+	"github.com/sensu/sensu-go/backend/etcd"
+	"github.com/sensu/sensu-go/testing/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckQuota(t *testing.T) {
+	assert.Equal(t, StatusOK, checkQuota("etcd/quota", "database", 10, 100).Status)
+	assert.Equal(t, StatusWarning, checkQuota("etcd/quota", "database", 80, 100).Status)
+	assert.Equal(t, StatusFailure, checkQuota("etcd/quota", "database", 100, 100).Status)
+}
+
+func TestCheckClockSkew(t *testing.T) {
+	tests := []struct {
+		name string
+		skew time.Duration
+		rtt  time.Duration
+		want Status
+	}{
+		{name: "in sync", skew: -400 * time.Millisecond, want: StatusOK},
+		{name: "truncated date", skew: -1500 * time.Millisecond, want: StatusOK},
+		{name: "ahead", skew: 1100 * time.Millisecond, want: StatusWarning},
+		{name: "behind", skew: -2100 * time.Millisecond, want: StatusWarning},
+		{name: "slow request", skew: 1100 * time.Millisecond, rtt: time.Second, want: StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checkClockSkew("etcd/clock-skew/default", skewMeasurement{skew: tt.skew, rtt: tt.rtt}, time.Second)
+			assert.Equal(t, tt.want, result.Status, result.Message)
+		})
+	}
+}
+
+// newSkewedServer returns a server responding with a Date header skewed from
+// the local time.
+func newSkewedServer(skew time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(skew).UTC().Format(http.TimeFormat))
+		fmt.Fprint(w, `{"etcdserver":"3.3.13"}`)
+	}))
+}
+
+func TestClockSkew(t *testing.T) {
+	server := newSkewedServer(time.Hour)
+	defer server.Close()
+
+	m, err := clockSkew(context.Background(), server.Client(), server.URL)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(time.Hour), float64(m.skew), float64(2*time.Second))
+	assert.Equal(t, StatusWarning, checkClockSkew("etcd/clock-skew/default", m, time.Second).Status)
+}
+
+func TestCheckEmbeddedEtcd(t *testing.T) {
+	stateDir, remove := testutil.TempDir(t)
+	defer remove()
+
+	db := filepath.Join(stateDir, "etcd", "data", "member", "snap", "db")
+	require.NoError(t, os.MkdirAll(filepath.Dir(db), 0700))
+	require.NoError(t, ioutil.WriteFile(db, make([]byte, 900), 0600))
+
+	skewed := newSkewedServer(-time.Hour)
+	defer skewed.Close()
+	inSync := newSkewedServer(0)
+	defer inSync.Close()
+
+	config := Config{
+		Backend: &backend.Config{
+			StateDir:              stateDir,
+			EtcdName:              "backend-1",
+			EtcdQuotaBackendBytes: 1000,
+			EtcdInitialCluster: fmt.Sprintf("backend-1=http://127.0.0.1:2380,backend-2=%s,backend-3=%s,backend-4=http://127.0.0.1:1",
+				skewed.URL, inSync.URL),
+		},
+		Timeout:      time.Second,
+		MaxClockSkew: time.Second,
+	}
+	results := checkEtcd(context.Background(), config)
+	require.Len(t, results, 4)
+	assert.Equal(t, warning("etcd/quota", results[0].Message), results[0])
+	assert.Equal(t, warning("etcd/clock-skew/backend-2", results[1].Message), results[1])
+	assert.Equal(t, ok("etcd/clock-skew/backend-3", results[2].Message), results[2])
+	assert.Equal(t, warning("etcd/clock-skew/backend-4", results[3].Message), results[3])
+}
+
+func TestCheckExternalEtcd(t *testing.T) {
+	e, cleanup := etcd.NewTestEtcd(t)
+	defer cleanup()
+
+	config := Config{
+		Backend: &backend.Config{
+			NoEmbedEtcd:             true,
+			EtcdAdvertiseClientURLs: e.ClientURLs(),
+		},
+		Timeout:      5 * time.Second,
+		MaxClockSkew: time.Second,
+	}
+	results := checkEtcd(context.Background(), config)
+	require.Len(t, results, 4)
+	for _, result := range results {
+		assert.Equal(t, StatusOK, result.Status, result.Message)
+	}
+	assert.Equal(t, "etcd/connectivity", results[0].Check)
+	assert.Equal(t, "etcd/quota", results[1].Check)
+	assert.Equal(t, "etcd/alarms", results[2].Check)
+	assert.Equal(t, "etcd/clock-skew/default", results[3].Check)
+
+	// An unreachable etcd fails the check
+	config.Backend.EtcdAdvertiseClientURLs = []string{"http://127.0.0.1:1"}
+	config.Timeout = time.Second
+	results = checkEtcd(context.Background(), config)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusFailure, results[0].Status)
+}
@@ -0,0 +1,70 @@
+package preflight
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/sensu/sensu-go/backend"
+)
+
+// listenAddress is an address the backend listens on.
+type listenAddress struct {
+	name    string
+	address string
+}
+
+// listenAddresses returns the addresses the backend listens on, formatted like
+// the daemons format them.
+func listenAddresses(config *backend.Config) ([]listenAddress, []Result) {
+	addresses := []listenAddress{
+		{name: "agent", address: fmt.Sprintf("%s:%d", config.AgentHost, config.AgentPort)},
+		{name: "api", address: config.APIListenAddress},
+		{name: "dashboard", address: fmt.Sprintf("%s:%d", config.DashboardHost, config.DashboardPort)},
+	}
+	if config.AgentGRPCPort != 0 {
+		addresses = append(addresses, listenAddress{
+			name:    "agent-grpc",
+			address: fmt.Sprintf("%s:%d", config.AgentHost, config.AgentGRPCPort),
+		})
+	}
+	if config.NoEmbedEtcd {
+		return addresses, nil
+	}
+
+	var results []Result
+	etcdURLs := []struct {
+		name string
+		urls []string
+	}{
+		{name: "etcd-client", urls: config.EtcdListenClientURLs},
+		{name: "etcd-peer", urls: config.EtcdListenPeerURLs},
+	}
+	for _, e := range etcdURLs {
+		for _, u := range e.urls {
+			parsed, err := url.Parse(u)
+			if err != nil || parsed.Host == "" {
+				results = append(results, failure("port/"+e.name, fmt.Sprintf("invalid URL %q", u)))
+				continue
+			}
+			addresses = append(addresses, listenAddress{name: e.name, address: parsed.Host})
+		}
+	}
+	return addresses, results
+}
+
+// checkPorts checks that the addresses the backend listens on are available.
+func checkPorts(config *backend.Config) []Result {
+	addresses, results := listenAddresses(config)
+	for _, a := range addresses {
+		check := "port/" + a.name
+		listener, err := net.Listen("tcp", a.address)
+		if err != nil {
+			results = append(results, failure(check, fmt.Sprintf("cannot listen on %s: %s", a.address, err)))
+			continue
+		}
+		_ = listener.Close()
+		results = append(results, ok(check, fmt.Sprintf("%s is available", a.address)))
+	}
+	return results
+}
@@ -0,0 +1,40 @@
+package preflight
+
+import (
+	"net"
+	"testing"
+
+	"github.com/sensu/sensu-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPorts(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	config := &backend.Config{
+		AgentHost:            "127.0.0.1",
+		AgentPort:            port,
+		APIListenAddress:     "127.0.0.1:0",
+		DashboardHost:        "127.0.0.1",
+		DashboardPort:        0,
+		EtcdListenClientURLs: []string{"http://127.0.0.1:0"},
+		EtcdListenPeerURLs:   []string{"%"},
+	}
+	results := checkPorts(config)
+	require.Len(t, results, 5)
+	assert.Equal(t, "port/etcd-peer", results[0].Check)
+	assert.Equal(t, StatusFailure, results[0].Status)
+	assert.Equal(t, "port/agent", results[1].Check)
+	assert.Equal(t, StatusFailure, results[1].Status)
+	for _, result := range results[2:] {
+		assert.Equal(t, StatusOK, result.Status, result.Check)
+	}
+
+	// The listen URLs of etcd are ignored with an external etcd
+	config.NoEmbedEtcd = true
+	assert.Len(t, checkPorts(config), 3)
+}
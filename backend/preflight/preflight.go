@@ -0,0 +1,130 @@
+// Package preflight checks the configuration and the environment of a backend
+// before it starts, so that misconfigurations surface before its daemons are
+// half started.
+package preflight
+
+import (
+	"context"
+	"time"
+
+	"github.com/sensu/sensu-go/backend"
+)
+
+const (
+	// DefaultTimeout is the default time given to each network check.
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultMaxClockSkew is the default maximum clock difference tolerated
+	// between the backend and the etcd members.
+	DefaultMaxClockSkew = time.Second
+
+	// DefaultMinOpenFiles is the default minimum limit of open files of the
+	// backend process.
+	DefaultMinOpenFiles = 65535
+
+	// quotaWarningRatio is the ratio of the etcd quota from which the size of
+	// the database is reported.
+	quotaWarningRatio = 0.8
+
+	// certificateExpiryWarning is the time before the expiry of a certificate
+	// from which it is reported.
+	certificateExpiryWarning = 30 * 24 * time.Hour
+)
+
+// Status is the status of a check.
+type Status string
+
+const (
+	// StatusOK is the status of the passed checks.
+	StatusOK Status = "ok"
+
+	// StatusWarning is the status of the checks that passed, but found an
+	// issue that may affect the backend later on.
+	StatusWarning Status = "warning"
+
+	// StatusFailure is the status of the checks that found an issue that
+	// prevents the backend from running.
+	StatusFailure Status = "failure"
+)
+
+// Result is the result of a check.
+type Result struct {
+	// Check is the name of the check, prefixed by its category.
+	Check string `json:"check"`
+
+	// Status is the status of the check.
+	Status Status `json:"status"`
+
+	// Message describes the result of the check.
+	Message string `json:"message"`
+}
+
+// Report is the report of the preflight checks.
+type Report struct {
+	// Results are the results of the checks, in the order they ran.
+	Results []Result `json:"results"`
+
+	// Passed is true if none of the checks failed.
+	Passed bool `json:"passed"`
+}
+
+// Config configures the preflight checks.
+type Config struct {
+	// Backend is the configuration of the checked backend.
+	Backend *backend.Config
+
+	// Timeout is the time given to each network check. It defaults to
+	// DefaultTimeout.
+	Timeout time.Duration
+
+	// MaxClockSkew is the maximum clock difference tolerated between the
+	// backend and the etcd members. It defaults to DefaultMaxClockSkew.
+	MaxClockSkew time.Duration
+
+	// MinOpenFiles is the minimum limit of open files of the backend process.
+	// It defaults to DefaultMinOpenFiles.
+	MinOpenFiles uint64
+}
+
+// Run runs the preflight checks of the backend.
+func Run(ctx context.Context, config Config) Report {
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.MaxClockSkew == 0 {
+		config.MaxClockSkew = DefaultMaxClockSkew
+	}
+	if config.MinOpenFiles == 0 {
+		config.MinOpenFiles = DefaultMinOpenFiles
+	}
+
+	var results []Result
+	results = append(results, checkTLS(config.Backend, time.Now())...)
+	results = append(results, checkPorts(config.Backend)...)
+	results = append(results, checkOpenFiles(config.MinOpenFiles)...)
+	results = append(results, checkEtcd(ctx, config)...)
+	return NewReport(results)
+}
+
+// NewReport returns the report of the results.
+func NewReport(results []Result) Report {
+	report := Report{Results: results, Passed: true}
+	for _, result := range results {
+		if result.Status == StatusFailure {
+			report.Passed = false
+		}
+	}
+	return report
+}
+
+func ok(check, message string) Result {
+	return Result{Check: check, Status: StatusOK, Message: message}
+}
+
+func warning(check, message string) Result {
+	return Result{Check: check, Status: StatusWarning, Message: message}
+}
+
+func failure(check, message string) Result {
+	return Result{Check: check, Status: StatusFailure, Message: message}
+}
@@ -0,0 +1,16 @@
+package preflight
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReport(t *testing.T) {
+	report := NewReport([]Result{ok("a", ""), warning("b", "")})
+	assert.True(t, report.Passed)
+
+	report = NewReport([]Result{ok("a", ""), failure("b", ""), warning("c", "")})
+	assert.False(t, report.Passed)
+	assert.Len(t, report.Results, 3)
+}
@@ -0,0 +1,102 @@
+package preflight
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/sensu/sensu-go/backend"
+)
+
+// tlsMaterial is a certificate, its key and a CA bundle of the backend.
+type tlsMaterial struct {
+	name          string
+	certFile      string
+	keyFile       string
+	trustedCAFile string
+}
+
+// tlsMaterials returns the TLS material configured for the backend.
+func tlsMaterials(config *backend.Config) []tlsMaterial {
+	var materials []tlsMaterial
+	if config.TLS != nil {
+		materials = append(materials, tlsMaterial{
+			name:          "api",
+			certFile:      config.TLS.CertFile,
+			keyFile:       config.TLS.KeyFile,
+			trustedCAFile: config.TLS.TrustedCAFile,
+		})
+	}
+	materials = append(materials,
+		tlsMaterial{
+			name:     "dashboard",
+			certFile: config.DashboardTLSCertFile,
+			keyFile:  config.DashboardTLSKeyFile,
+		},
+		tlsMaterial{
+			name:          "etcd-client",
+			certFile:      config.EtcdClientTLSInfo.CertFile,
+			keyFile:       config.EtcdClientTLSInfo.KeyFile,
+			trustedCAFile: config.EtcdClientTLSInfo.TrustedCAFile,
+		},
+		tlsMaterial{
+			name:          "etcd-peer",
+			certFile:      config.EtcdPeerTLSInfo.CertFile,
+			keyFile:       config.EtcdPeerTLSInfo.KeyFile,
+			trustedCAFile: config.EtcdPeerTLSInfo.TrustedCAFile,
+		},
+	)
+	return materials
+}
+
+// checkTLS checks that the configured certificates match their keys and are
+// valid at the given time, and that the CA bundles contain certificates.
+func checkTLS(config *backend.Config, now time.Time) []Result {
+	var results []Result
+	for _, m := range tlsMaterials(config) {
+		if m.certFile != "" || m.keyFile != "" {
+			results = append(results, checkCertificate("tls/"+m.name, m.certFile, m.keyFile, now))
+		}
+		if m.trustedCAFile != "" {
+			results = append(results, checkTrustedCA("tls/"+m.name+"-ca", m.trustedCAFile))
+		}
+	}
+	return results
+}
+
+func checkCertificate(check, certFile, keyFile string, now time.Time) Result {
+	if certFile == "" || keyFile == "" {
+		return failure(check, "both a certificate and a key are required")
+	}
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return failure(check, fmt.Sprintf("invalid certificate %s or key %s: %s", certFile, keyFile, err))
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return failure(check, fmt.Sprintf("invalid certificate %s: %s", certFile, err))
+	}
+
+	switch {
+	case now.Before(cert.NotBefore):
+		return failure(check, fmt.Sprintf("certificate %s is not valid before %s", certFile, cert.NotBefore.Format(time.RFC3339)))
+	case now.After(cert.NotAfter):
+		return failure(check, fmt.Sprintf("certificate %s expired on %s", certFile, cert.NotAfter.Format(time.RFC3339)))
+	case cert.NotAfter.Sub(now) < certificateExpiryWarning:
+		return warning(check, fmt.Sprintf("certificate %s expires on %s", certFile, cert.NotAfter.Format(time.RFC3339)))
+	}
+	return ok(check, fmt.Sprintf("certificate %s is valid until %s", certFile, cert.NotAfter.Format(time.RFC3339)))
+}
+
+func checkTrustedCA(check, caFile string) Result {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return failure(check, fmt.Sprintf("could not read CA bundle: %s", err))
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(pem) {
+		return failure(check, fmt.Sprintf("CA bundle %s contains no certificates", caFile))
+	}
+	return ok(check, fmt.Sprintf("CA bundle %s is valid", caFile))
+}
@@ -0,0 +1,101 @@
+package preflight
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/backend"
+	"github.com/sensu/sensu-go/backend/etcd"
+	"github.com/sensu/sensu-go/testing/testutil"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate writes a self-signed certificate valid between notBefore
+// and notAfter, and its key, to the directory.
+func writeCertificate(t *testing.T, dir, name string, notBefore, notAfter time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".pem")
+	keyFile := filepath.Join(dir, name+"-key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func TestCheckCertificate(t *testing.T) {
+	dir, remove := testutil.TempDir(t)
+	defer remove()
+
+	now := time.Now()
+	validCert, validKey := writeCertificate(t, dir, "valid", now.Add(-time.Hour), now.Add(365*24*time.Hour))
+	expiringCert, expiringKey := writeCertificate(t, dir, "expiring", now.Add(-time.Hour), now.Add(24*time.Hour))
+	expiredCert, expiredKey := writeCertificate(t, dir, "expired", now.Add(-2*time.Hour), now.Add(-time.Hour))
+	futureCert, futureKey := writeCertificate(t, dir, "future", now.Add(time.Hour), now.Add(2*time.Hour))
+
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+		want     Status
+	}{
+		{name: "valid", certFile: validCert, keyFile: validKey, want: StatusOK},
+		{name: "expiring", certFile: expiringCert, keyFile: expiringKey, want: StatusWarning},
+		{name: "expired", certFile: expiredCert, keyFile: expiredKey, want: StatusFailure},
+		{name: "not yet valid", certFile: futureCert, keyFile: futureKey, want: StatusFailure},
+		{name: "mismatched key", certFile: validCert, keyFile: expiringKey, want: StatusFailure},
+		{name: "missing key", certFile: validCert, want: StatusFailure},
+		{name: "missing file", certFile: filepath.Join(dir, "missing.pem"), keyFile: validKey, want: StatusFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checkCertificate("tls/api", tt.certFile, tt.keyFile, now)
+			assert.Equal(t, tt.want, result.Status, result.Message)
+		})
+	}
+}
+
+func TestCheckTLS(t *testing.T) {
+	dir, remove := testutil.TempDir(t)
+	defer remove()
+
+	now := time.Now()
+	certFile, keyFile := writeCertificate(t, dir, "backend", now.Add(-time.Hour), now.Add(365*24*time.Hour))
+	emptyCA := filepath.Join(dir, "empty.pem")
+	require.NoError(t, ioutil.WriteFile(emptyCA, []byte("not a certificate"), 0600))
+
+	config := &backend.Config{
+		TLS: &types.TLSOptions{CertFile: certFile, KeyFile: keyFile, TrustedCAFile: certFile},
+		EtcdClientTLSInfo: etcd.TLSInfo{
+			TrustedCAFile: emptyCA,
+		},
+	}
+	results := checkTLS(config, now)
+	require.Len(t, results, 3)
+	assert.Equal(t, ok("tls/api", results[0].Message), results[0])
+	assert.Equal(t, ok("tls/api-ca", results[1].Message), results[1])
+	assert.Equal(t, failure("tls/etcd-client-ca", results[2].Message), results[2])
+}
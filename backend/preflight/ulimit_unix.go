@@ -0,0 +1,22 @@
+// +build !windows
+
+package preflight
+
+import (
+	"fmt"
+	"syscall"
+)
+
+const checkOpenFilesName = "ulimit/nofile"
+
+// checkOpenFiles checks that the backend process can open at least min files.
+func checkOpenFiles(min uint64) []Result {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return []Result{warning(checkOpenFilesName, fmt.Sprintf("could not get the limit of open files: %s", err))}
+	}
+	if uint64(limit.Cur) < min {
+		return []Result{warning(checkOpenFilesName, fmt.Sprintf("the limit of open files is %d, at least %d is recommended", limit.Cur, min))}
+	}
+	return []Result{ok(checkOpenFilesName, fmt.Sprintf("the limit of open files is %d", limit.Cur))}
+}
@@ -0,0 +1,9 @@
+// +build windows
+
+package preflight
+
+// checkOpenFiles does nothing, Windows doesn't limit the number of open files
+// of the processes.
+func checkOpenFiles(min uint64) []Result {
+	return nil
+}
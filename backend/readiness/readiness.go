@@ -0,0 +1,123 @@
+// Package readiness tracks whether the backend is warm after it starts, so the
+// daemons can hold back the check scheduling and the keepalive and check TTL
+// failures until the caches, the rings and the agent connections settled.
+package readiness
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// Warmup is the pending condition of the warmup window.
+const Warmup = "warmup"
+
+var (
+	readyGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "sensu_go_backend_ready",
+			Help: "Whether the backend is warm and schedules checks and processes keepalive failures (1) or not (0)",
+		},
+	)
+)
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "readiness",
+})
+
+// Gate becomes ready once all of its conditions are met and its warmup window
+// elapsed. A nil Gate is always ready.
+type Gate struct {
+	mu      sync.Mutex
+	pending map[string]struct{}
+	ready   chan struct{}
+}
+
+// NewGate creates a Gate waiting for the given conditions, and for its warmup
+// window once started.
+func NewGate(conditions ...string) *Gate {
+	g := &Gate{
+		pending: map[string]struct{}{Warmup: {}},
+		ready:   make(chan struct{}),
+	}
+	for _, condition := range conditions {
+		g.pending[condition] = struct{}{}
+	}
+	_ = prometheus.Register(readyGauge)
+	readyGauge.Set(0)
+	return g
+}
+
+// Start starts the warmup window. The gate is ready when the window elapsed
+// and all its conditions are met.
+func (g *Gate) Start(window time.Duration) {
+	if g == nil {
+		return
+	}
+	logger.WithField("window", window.String()).Info("backend warming up")
+	if window <= 0 {
+		g.Done(Warmup)
+		return
+	}
+	time.AfterFunc(window, func() { g.Done(Warmup) })
+}
+
+// Done marks the condition as met.
+func (g *Gate) Done(condition string) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.pending[condition]; !ok {
+		return
+	}
+	delete(g.pending, condition)
+	logger.WithField("condition", condition).Debug("readiness condition met")
+	if len(g.pending) == 0 {
+		close(g.ready)
+		readyGauge.Set(1)
+		logger.Info("backend is ready")
+	}
+}
+
+// Ready returns true if the gate is ready.
+func (g *Gate) Ready() bool {
+	if g == nil {
+		return true
+	}
+	select {
+	case <-g.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// C returns a channel closed once the gate is ready.
+func (g *Gate) C() <-chan struct{} {
+	if g == nil {
+		ready := make(chan struct{})
+		close(ready)
+		return ready
+	}
+	return g.ready
+}
+
+// Pending returns the conditions not met yet, sorted by name.
+func (g *Gate) Pending() []string {
+	pending := []string{}
+	if g == nil {
+		return pending
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for condition := range g.pending {
+		pending = append(pending, condition)
+	}
+	sort.Strings(pending)
+	return pending
+}
@@ -0,0 +1,50 @@
+package readiness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGate(t *testing.T) {
+	gate := NewGate("schedulerd", "keepalived")
+	assert.False(t, gate.Ready())
+	assert.Equal(t, []string{"keepalived", "schedulerd", Warmup}, gate.Pending())
+
+	gate.Done("schedulerd")
+	gate.Done("schedulerd")
+	gate.Start(0)
+	assert.False(t, gate.Ready())
+	assert.Equal(t, []string{"keepalived"}, gate.Pending())
+
+	gate.Done("keepalived")
+	assert.True(t, gate.Ready())
+	assert.Empty(t, gate.Pending())
+	select {
+	case <-gate.C():
+	default:
+		t.Fatal("gate channel not closed")
+	}
+}
+
+func TestGateWarmupWindow(t *testing.T) {
+	gate := NewGate()
+	gate.Start(50 * time.Millisecond)
+	assert.False(t, gate.Ready())
+	select {
+	case <-gate.C():
+	case <-time.After(5 * time.Second):
+		t.Fatal("gate not ready after its warmup window")
+	}
+	assert.True(t, gate.Ready())
+}
+
+func TestNilGate(t *testing.T) {
+	var gate *Gate
+	gate.Start(time.Hour)
+	gate.Done("schedulerd")
+	assert.True(t, gate.Ready())
+	assert.Empty(t, gate.Pending())
+	<-gate.C()
+}
@@ -0,0 +1,7 @@
+package remediationd
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "remediationd",
+})
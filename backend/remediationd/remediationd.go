@@ -0,0 +1,161 @@
+// Package remediationd executes the remediation checks triggered by events.
+package remediationd
+
+import (
+	"context"
+	"sync"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+// AdhocQueuer queues ad-hoc check execution requests.
+type AdhocQueuer interface {
+	QueueAdhocRequest(ctx context.Context, name string, adhocRequest *corev2.AdhocRequest) error
+}
+
+// Remediationd matches the events against the remediations of their
+// namespace, and requests the ad-hoc execution of the remediation checks they
+// trigger.
+type Remediationd struct {
+	store        store.ResourceStore
+	bus          messaging.MessageBus
+	queuer       AdhocQueuer
+	eventChan    chan interface{}
+	subscription messaging.Subscription
+	stopping     chan struct{}
+	wg           *sync.WaitGroup
+	errChan      chan error
+}
+
+// Config configures a Remediationd.
+type Config struct {
+	Store       store.Store
+	Bus         messaging.MessageBus
+	QueueGetter types.QueueGetter
+	BufferSize  int
+}
+
+// Option is a functional option used to configure Remediationd.
+type Option func(*Remediationd) error
+
+// New creates a new Remediationd with supplied Options applied.
+func New(c Config, options ...Option) (*Remediationd, error) {
+	if c.BufferSize == 0 {
+		c.BufferSize = 1
+	}
+
+	r := &Remediationd{
+		store:     c.Store,
+		bus:       c.Bus,
+		queuer:    actions.NewCheckController(c.Store, c.QueueGetter),
+		eventChan: make(chan interface{}, c.BufferSize),
+		stopping:  make(chan struct{}),
+		wg:        &sync.WaitGroup{},
+		errChan:   make(chan error, 1),
+	}
+	for _, o := range options {
+		if err := o(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Receiver returns the event channel for remediationd.
+func (r *Remediationd) Receiver() chan<- interface{} {
+	return r.eventChan
+}
+
+// Start remediationd, subscribing to the "event" message bus topic.
+func (r *Remediationd) Start() error {
+	sub, err := r.bus.Subscribe(messaging.TopicEvent, "remediationd", r)
+	if err != nil {
+		return err
+	}
+	r.subscription = sub
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		for {
+			select {
+			case <-r.stopping:
+				return
+			case msg := <-r.eventChan:
+				event, ok := msg.(*corev2.Event)
+				if !ok {
+					continue
+				}
+				r.handleEvent(context.Background(), event)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop remediationd.
+func (r *Remediationd) Stop() error {
+	close(r.stopping)
+	r.wg.Wait()
+	close(r.errChan)
+	return r.subscription.Cancel()
+}
+
+// Err returns a channel to listen for terminal errors on.
+func (r *Remediationd) Err() <-chan error {
+	return r.errChan
+}
+
+// Name returns the daemon name
+func (r *Remediationd) Name() string {
+	return "remediationd"
+}
+
+// handleEvent requests the execution of the remediation checks triggered by
+// the event.
+func (r *Remediationd) handleEvent(ctx context.Context, event *corev2.Event) {
+	if !event.HasCheck() {
+		return
+	}
+	ctx = context.WithValue(ctx, corev2.NamespaceKey, event.Check.Namespace)
+
+	var remediations []*corev2.Remediation
+	if err := r.store.ListResources(ctx, corev2.RemediationsResource, &remediations, &store.SelectionPredicate{}); err != nil {
+		logger.WithError(err).Error("could not list the remediations")
+		return
+	}
+
+	for _, remediation := range remediations {
+		if !remediation.Triggered(event) {
+			continue
+		}
+
+		subscriptions := remediation.Subscriptions
+		if len(subscriptions) == 0 && event.Entity != nil {
+			subscriptions = []string{corev2.GetEntitySubscription(event.Entity.Name)}
+		}
+		request := &corev2.AdhocRequest{
+			ObjectMeta:    corev2.NewObjectMeta(remediation.Request, remediation.Namespace),
+			Subscriptions: subscriptions,
+			Creator:       "remediationd",
+			Reason:        "remediation " + remediation.Name,
+		}
+
+		fields := logrus.Fields{
+			"namespace":   remediation.Namespace,
+			"remediation": remediation.Name,
+			"check":       remediation.Request,
+		}
+		if err := r.queuer.QueueAdhocRequest(ctx, remediation.Request, request); err != nil {
+			logger.WithFields(fields).WithError(err).Error("could not request the remediation check")
+			continue
+		}
+		logger.WithFields(fields).Info("requested the remediation check")
+	}
+}
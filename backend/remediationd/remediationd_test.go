@@ -0,0 +1,78 @@
+package remediationd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testQueuer struct {
+	requests []*corev2.AdhocRequest
+	err      error
+}
+
+func (q *testQueuer) QueueAdhocRequest(ctx context.Context, name string, request *corev2.AdhocRequest) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.requests = append(q.requests, request)
+	return nil
+}
+
+func TestHandleEvent(t *testing.T) {
+	onEntity := corev2.FixtureRemediation("on-entity")
+	onSubscription := corev2.FixtureRemediation("on-subscription")
+	onSubscription.Subscriptions = []string{"web"}
+	notTriggered := corev2.FixtureRemediation("not-triggered")
+	notTriggered.Statuses = []uint32{1}
+
+	tests := []struct {
+		name     string
+		listErr  error
+		queueErr error
+		wantSubs [][]string
+	}{
+		{
+			name:     "triggered remediations are requested",
+			wantSubs: [][]string{{"entity:entity1"}, {"web"}},
+		},
+		{
+			name:    "store error",
+			listErr: errors.New("error"),
+		},
+		{
+			name:     "queue error",
+			queueErr: errors.New("error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &mockstore.MockStore{}
+			s.On("ListResources", mock.Anything, corev2.RemediationsResource, mock.Anything, mock.Anything).
+				Return(tt.listErr).
+				Run(func(args mock.Arguments) {
+					list := args[2].(*[]*corev2.Remediation)
+					*list = []*corev2.Remediation{onEntity, onSubscription, notTriggered}
+				})
+			queuer := &testQueuer{err: tt.queueErr}
+			r := &Remediationd{store: s, queuer: queuer}
+
+			event := corev2.FixtureEvent("entity1", "check")
+			event.Check.Status = 2
+			event.Check.Occurrences = 1
+			r.handleEvent(context.Background(), event)
+
+			var subs [][]string
+			for _, request := range queuer.requests {
+				assert.Equal(t, "remediate", request.Name)
+				subs = append(subs, request.Subscriptions)
+			}
+			assert.Equal(t, tt.wantSubs, subs)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package ringv2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coreos/etcd/clientv3"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// Ownerships returns the owner of the active trigger of every ring watcher,
+// e.g. the backend running tessen or the agent running the next round robin
+// check, along with the etcd lease holding it. The ownerships are sorted by
+// namespace, ring and watcher.
+func Ownerships(ctx context.Context, client *clientv3.Client) ([]*corev2.RingOwnership, error) {
+	prefix := store.NewKeyBuilder("rings").Build() + "/"
+	resp, err := client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	ownerships := []*corev2.RingOwnership{}
+	for _, kv := range resp.Kvs {
+		// The trigger keys are <namespace>/<ring>/triggers/<watcher>/<values>/<interval>
+		key := strings.TrimPrefix(string(kv.Key), prefix)
+		idx := strings.Index(key, "/triggers/")
+		if idx < 0 {
+			continue
+		}
+		ring := strings.SplitN(key[:idx], "/", 2)
+		trigger := strings.SplitN(key[idx+len("/triggers/"):], "/", 3)
+		if len(ring) != 2 || len(trigger) != 3 {
+			continue
+		}
+		ownership := &corev2.RingOwnership{
+			Namespace: ring[0],
+			Ring:      ring[1],
+			Watcher:   trigger[0],
+			Schedule:  trigger[2],
+			Owner:     string(kv.Value),
+			LeaseID:   kv.Lease,
+		}
+		if kv.Lease != 0 {
+			ttl, err := client.TimeToLive(ctx, clientv3.LeaseID(kv.Lease))
+			if err != nil {
+				return nil, fmt.Errorf("could not get the lease of the ring trigger %q: %s", kv.Key, err)
+			}
+			ownership.TTL = ttl.TTL
+		}
+		ownerships = append(ownerships, ownership)
+	}
+
+	sort.Slice(ownerships, func(i, j int) bool {
+		a, b := ownerships[i], ownerships[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Ring != b.Ring {
+			return a.Ring < b.Ring
+		}
+		return a.Watcher < b.Watcher
+	})
+
+	return ownerships, nil
+}
@@ -27,16 +27,18 @@ type IntervalTimer struct {
 	interval time.Duration
 	splay    uint64
 	timer    *time.Timer
+	clock    Clock
 }
 
-// NewIntervalTimer establishes new check timer given a name & an initial interval
-func NewIntervalTimer(name string, interval uint) *IntervalTimer {
+// NewIntervalTimer establishes new check timer given a name & an initial
+// interval, on the clock (the timeproxy clock if nil)
+func NewIntervalTimer(name string, interval uint, clock Clock) *IntervalTimer {
 	// Calculate a check execution splay to ensure
 	// execution is consistent between process restarts.
 	sum := md5.Sum([]byte(name))
 	splay := binary.LittleEndian.Uint64(sum[:])
 
-	timer := &IntervalTimer{splay: splay}
+	timer := &IntervalTimer{splay: splay, clock: clockOrDefault(clock)}
 	timer.SetDuration("", interval)
 	return timer
 }
@@ -54,7 +56,7 @@ func (timerPtr *IntervalTimer) SetDuration(cron string, interval uint) {
 // Start sets up a new timer
 func (timerPtr *IntervalTimer) Start() {
 	initOffset := timerPtr.calcInitialOffset()
-	timerPtr.timer = time.NewTimer(initOffset)
+	timerPtr.timer = timerPtr.clock.NewTimer(initOffset)
 }
 
 // Next reset's timer using interval
@@ -79,7 +81,7 @@ func (timerPtr *IntervalTimer) Stop() {
 
 // Calculate the first execution time using splay & interval
 func (timerPtr *IntervalTimer) calcInitialOffset() time.Duration {
-	now := uint64(time.Now().UnixNano())
+	now := uint64(timerPtr.clock.Now().UnixNano())
 	offset := (timerPtr.splay - now) % uint64(timerPtr.interval)
 	logger.WithField("offset", time.Duration(offset)/time.Second).Debug("initial offset for interval timer (in seconds)")
 	return time.Duration(offset) / time.Nanosecond
@@ -89,18 +91,21 @@ func (timerPtr *IntervalTimer) calcInitialOffset() time.Duration {
 type CronTimer struct {
 	next  time.Duration
 	timer *time.Timer
+	clock Clock
 }
 
-// NewCronTimer establishes new check timer given a name & an initial interval
-func NewCronTimer(name string, cronStr string) *CronTimer {
-	diff, err := NextCronTime(time.Now(), cronStr)
+// NewCronTimer establishes new check timer given a name & a cron string, on
+// the clock (the timeproxy clock if nil)
+func NewCronTimer(name string, cronStr string, clock Clock) *CronTimer {
+	clock = clockOrDefault(clock)
+	diff, err := NextCronTime(clock.Now(), cronStr)
 	// we shouldn't hit this error because we've already validated the cron string
 	// but log and exit cleanly to revert to the interval timer
 	if err != nil {
 		logger.WithError(err).Error("invalid cron, reverting to interval")
 		return nil
 	}
-	timer := &CronTimer{next: diff}
+	timer := &CronTimer{next: diff, clock: clock}
 	return timer
 }
 
@@ -111,7 +116,7 @@ func (timerPtr *CronTimer) C() <-chan time.Time {
 
 // SetDuration updates the interval in which timers are set
 func (timerPtr *CronTimer) SetDuration(cronStr string, interval uint) {
-	diff, err := NextCronTime(time.Now(), cronStr)
+	diff, err := NextCronTime(timerPtr.clock.Now(), cronStr)
 	// we shouldn't hit this error because we've already validated the cron string
 	// but log and exit cleanly to revert to the interval timer
 	if err != nil {
@@ -123,7 +128,7 @@ func (timerPtr *CronTimer) SetDuration(cronStr string, interval uint) {
 
 // Start sets up a new timer
 func (timerPtr *CronTimer) Start() {
-	timerPtr.timer = time.NewTimer(timerPtr.next)
+	timerPtr.timer = timerPtr.clock.NewTimer(timerPtr.next)
 }
 
 // Next reset's timer using interval
@@ -29,11 +29,11 @@ func TestNextCronTime(t *testing.T) {
 }
 
 func TestSplay(t *testing.T) {
-	timer := NewIntervalTimer("check1", 10)
+	timer := NewIntervalTimer("check1", 10, mockTime)
 
 	assert.Condition(t, func() bool { return timer.splay > 0 })
 
-	timer2 := NewIntervalTimer("check1", 10)
+	timer2 := NewIntervalTimer("check1", 10, mockTime)
 	assert.Equal(t, timer.splay, timer2.splay)
 }
 
@@ -41,7 +41,7 @@ func TestInitialOffset(t *testing.T) {
 	inputs := []uint{1, 10, 60}
 	for _, intervalSeconds := range inputs {
 		now := mockTime.Now()
-		timer := NewIntervalTimer("check1", intervalSeconds)
+		timer := NewIntervalTimer("check1", intervalSeconds, mockTime)
 		nextExecution := timer.calcInitialOffset()
 		executionTime := now.Add(nextExecution)
 
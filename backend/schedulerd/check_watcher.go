@@ -21,10 +21,12 @@ type CheckWatcher struct {
 	ctx         context.Context
 	ringPool    *ringv2.Pool
 	entityCache *cache.Resource
+	clock       Clock
 }
 
-// NewCheckWatcher creates a new ScheduleManager.
-func NewCheckWatcher(ctx context.Context, msgBus messaging.MessageBus, store store.Store, pool *ringv2.Pool, cache *cache.Resource) *CheckWatcher {
+// NewCheckWatcher creates a new ScheduleManager, whose schedulers run on the
+// clock (the timeproxy clock if nil).
+func NewCheckWatcher(ctx context.Context, msgBus messaging.MessageBus, store store.Store, pool *ringv2.Pool, cache *cache.Resource, clock Clock) *CheckWatcher {
 	watcher := &CheckWatcher{
 		store:       store,
 		items:       make(map[string]Scheduler),
@@ -32,6 +34,7 @@ func NewCheckWatcher(ctx context.Context, msgBus messaging.MessageBus, store sto
 		ctx:         ctx,
 		ringPool:    pool,
 		entityCache: cache,
+		clock:       clock,
 	}
 
 	return watcher
@@ -61,16 +64,18 @@ func (c *CheckWatcher) startScheduler(check *types.CheckConfig) error {
 
 	switch GetSchedulerType(check) {
 	case IntervalType:
-		scheduler = NewIntervalScheduler(c.ctx, c.store, c.bus, check, c.entityCache)
+		scheduler = NewIntervalScheduler(c.ctx, c.store, c.bus, check, c.entityCache, c.clock)
 	case CronType:
-		scheduler = NewCronScheduler(c.ctx, c.store, c.bus, check, c.entityCache)
+		scheduler = NewCronScheduler(c.ctx, c.store, c.bus, check, c.entityCache, c.clock)
 	case RoundRobinIntervalType:
-		scheduler = NewRoundRobinIntervalScheduler(c.ctx, c.store, c.bus, c.ringPool, check, c.entityCache)
+		scheduler = NewRoundRobinIntervalScheduler(c.ctx, c.store, c.bus, c.ringPool, check, c.entityCache, c.clock)
 	case RoundRobinCronType:
-		scheduler = NewRoundRobinCronScheduler(c.ctx, c.store, c.bus, c.ringPool, check, c.entityCache)
+		scheduler = NewRoundRobinCronScheduler(c.ctx, c.store, c.bus, c.ringPool, check, c.entityCache, c.clock)
+	case RunAtType:
+		scheduler = NewRunAtScheduler(c.ctx, c.store, c.bus, check, c.entityCache, c.clock)
 	default:
 		logger.Error("bad scheduler type, falling back to interval scheduler")
-		scheduler = NewIntervalScheduler(c.ctx, c.store, c.bus, check, c.entityCache)
+		scheduler = NewIntervalScheduler(c.ctx, c.store, c.bus, check, c.entityCache, c.clock)
 	}
 
 	// Start scheduling check
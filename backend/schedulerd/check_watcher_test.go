@@ -37,7 +37,7 @@ func TestCheckWatcherSmoke(t *testing.T) {
 	watcherChan := make(chan store.WatchEventCheckConfig)
 	st.On("GetCheckConfigWatcher", mock.Anything).Return((<-chan store.WatchEventCheckConfig)(watcherChan), nil)
 
-	watcher := NewCheckWatcher(ctx, bus, st, nil, &cache.Resource{})
+	watcher := NewCheckWatcher(ctx, bus, st, nil, &cache.Resource{}, mockTime)
 	require.NoError(t, watcher.Start())
 
 	checkAA := types.FixtureCheckConfig("a")
@@ -0,0 +1,28 @@
+package schedulerd
+
+import (
+	time "github.com/echlebek/timeproxy"
+)
+
+// Clock is the source of time of the schedulers: the timers of the checks, the
+// splay of the proxy check requests and the time the requests are issued.
+// It is satisfied by timeproxy.RealTime, and by crock.Time, whose Set method
+// advances the time, and fires the timers, deterministically in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a timer firing after the duration.
+	NewTimer(time.Duration) *time.Timer
+
+	// Sleep pauses the current goroutine for the duration.
+	Sleep(time.Duration)
+}
+
+// clockOrDefault returns the clock, or the timeproxy clock if nil.
+func clockOrDefault(clock Clock) Clock {
+	if clock == nil {
+		return time.TimeProxy
+	}
+	return clock
+}
@@ -0,0 +1,217 @@
+package schedulerd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/echlebek/crock"
+	"github.com/echlebek/timeproxy"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/cache"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testClock is a halted mock clock, advanced by the tests with Set. It
+// signals on armed every time a timer is created or reset, so that the tests
+// advance the time once the schedulers wait for it.
+type testClock struct {
+	*crock.Time
+	armed chan struct{}
+}
+
+func newTestClock(now time.Time) *testClock {
+	return &testClock{Time: crock.NewTime(now), armed: make(chan struct{}, 10)}
+}
+
+func (c *testClock) NewTimer(d time.Duration) *timeproxy.Timer {
+	timer := c.Time.NewTimer(d)
+	reset := timer.ResetFunc
+	timer.ResetFunc = func(d time.Duration) bool {
+		active := reset(d)
+		c.armed <- struct{}{}
+		return active
+	}
+	c.armed <- struct{}{}
+	return timer
+}
+
+// advance sets the clock to the given offset from its initial time.
+func (c *testClock) advance(t0 time.Time, d time.Duration) {
+	c.Set(t0.Add(d))
+}
+
+func (c *testClock) waitArmed(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.armed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a timer")
+	}
+}
+
+func assertFired(t *testing.T, c <-chan time.Time, fired bool) {
+	t.Helper()
+	select {
+	case <-c:
+		if !fired {
+			t.Fatal("timer fired early")
+		}
+	default:
+		if fired {
+			t.Fatal("timer did not fire")
+		}
+	}
+}
+
+func TestIntervalTimerClock(t *testing.T) {
+	t0 := time.Unix(1500000000, 0)
+	clock := newTestClock(t0)
+
+	timer := NewIntervalTimer("check1", 10, clock)
+	offset := timer.calcInitialOffset()
+	timer.Start()
+	defer timer.Stop()
+
+	clock.advance(t0, offset-time.Millisecond)
+	assertFired(t, timer.C(), offset == 0)
+	clock.advance(t0, offset)
+	assertFired(t, timer.C(), offset != 0)
+
+	timer.Next()
+	clock.advance(t0, offset+10*time.Second-time.Millisecond)
+	assertFired(t, timer.C(), false)
+	clock.advance(t0, offset+10*time.Second)
+	assertFired(t, timer.C(), true)
+}
+
+func TestCronTimerClock(t *testing.T) {
+	t0 := time.Date(2019, 1, 1, 0, 0, 30, 0, time.UTC)
+	clock := newTestClock(t0)
+
+	timer := NewCronTimer("check1", "* * * * *", clock)
+	timer.Start()
+	defer timer.Stop()
+
+	clock.advance(t0, 30*time.Second-time.Millisecond)
+	assertFired(t, timer.C(), false)
+	clock.advance(t0, 30*time.Second)
+	assertFired(t, timer.C(), true)
+
+	timer.SetDuration("* * * * *", 0)
+	timer.Next()
+	clock.advance(t0, 90*time.Second-time.Millisecond)
+	assertFired(t, timer.C(), false)
+	clock.advance(t0, 90*time.Second)
+	assertFired(t, timer.C(), true)
+}
+
+func TestIntervalSchedulerClock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t0 := time.Unix(1500000000, 0)
+	clock := newTestClock(t0)
+
+	request := types.FixtureCheckRequest("check1")
+	check := request.Config
+	check.Interval = 10
+	check.Subscriptions = []string{"subscription1"}
+	s := &mockstore.MockStore{}
+	s.On("GetAssets", mock.Anything, &store.SelectionPredicate{}).Return([]*types.Asset{}, nil)
+	s.On("GetHookConfigs", mock.Anything, &store.SelectionPredicate{}).Return([]*types.HookConfig{}, nil)
+
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	defer bus.Stop()
+
+	receiver := &TestIntervalScheduler{channel: make(chan interface{}, 10)}
+	sub, err := bus.Subscribe(messaging.SubscriptionTopic(check.Namespace, "subscription1"), "scheduler", receiver)
+	require.NoError(t, err)
+	defer sub.Cancel()
+
+	scheduler := NewIntervalScheduler(ctx, s, bus, check, &cache.Resource{}, clock)
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	// The first request is due within an interval, at the splay of the check
+	clock.waitArmed(t)
+	clock.advance(t0, 10*time.Second)
+	msg := <-receiver.channel
+	assert.Equal(t, t0.Add(10*time.Second).Unix(), msg.(*types.CheckRequest).Issued)
+
+	// The next requests are issued at every interval of the clock
+	for i := 2; i <= 4; i++ {
+		clock.waitArmed(t)
+		next := time.Duration(i) * 10 * time.Second
+		clock.advance(t0, next-time.Millisecond)
+		select {
+		case <-receiver.channel:
+			t.Fatal("request issued early")
+		case <-time.After(10 * time.Millisecond):
+		}
+		clock.advance(t0, next)
+		msg := <-receiver.channel
+		assert.Equal(t, t0.Add(next).Unix(), msg.(*types.CheckRequest).Issued)
+	}
+}
+
+func TestRunAtSchedulerClock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t0 := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	clock := newTestClock(t0)
+
+	request := types.FixtureCheckRequest("check1")
+	check := request.Config
+	check.Interval = 0
+	check.RunAt = []string{"2019-07-01T02:00:00Z", "2019-06-30T00:00:00Z", "2019-07-01T01:00:00Z"}
+	check.Subscriptions = []string{"subscription1"}
+	s := &mockstore.MockStore{}
+	s.On("GetAssets", mock.Anything, &store.SelectionPredicate{}).Return([]*types.Asset{}, nil)
+	s.On("GetHookConfigs", mock.Anything, &store.SelectionPredicate{}).Return([]*types.HookConfig{}, nil)
+
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	defer bus.Stop()
+
+	receiver := &TestIntervalScheduler{channel: make(chan interface{}, 10)}
+	sub, err := bus.Subscribe(messaging.SubscriptionTopic(check.Namespace, "subscription1"), "scheduler", receiver)
+	require.NoError(t, err)
+	defer sub.Cancel()
+
+	scheduler := NewRunAtScheduler(ctx, s, bus, check, &cache.Resource{}, clock)
+	assert.Equal(t, RunAtType, GetSchedulerType(check))
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	// The time in the past is skipped, the others are run once, in order
+	for _, next := range []time.Duration{time.Hour, 2 * time.Hour} {
+		clock.waitArmed(t)
+		clock.advance(t0, next-time.Millisecond)
+		select {
+		case <-receiver.channel:
+			t.Fatal("request issued early")
+		case <-time.After(10 * time.Millisecond):
+		}
+		clock.advance(t0, next)
+		msg := <-receiver.channel
+		assert.Equal(t, t0.Add(next).Unix(), msg.(*types.CheckRequest).Issued)
+	}
+
+	// No time is left
+	clock.advance(t0, 24*time.Hour)
+	select {
+	case <-receiver.channel:
+		t.Fatal("request issued after the last run_at time")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
@@ -22,10 +22,11 @@ type CronScheduler struct {
 	cancel        context.CancelFunc
 	interrupt     chan *corev2.CheckConfig
 	entityCache   *cache.Resource
+	clock         Clock
 }
 
 // NewCronScheduler initializes a CronScheduler
-func NewCronScheduler(ctx context.Context, store store.Store, bus messaging.MessageBus, check *corev2.CheckConfig, cache *cache.Resource) *CronScheduler {
+func NewCronScheduler(ctx context.Context, store store.Store, bus messaging.MessageBus, check *corev2.CheckConfig, cache *cache.Resource, clock Clock) *CronScheduler {
 	sched := &CronScheduler{
 		store:         store,
 		bus:           bus,
@@ -38,6 +39,7 @@ func NewCronScheduler(ctx context.Context, store store.Store, bus messaging.Mess
 			"scheduler_type": CronType.String(),
 		}),
 		entityCache: cache,
+		clock:       clock,
 	}
 	sched.ctx, sched.cancel = context.WithCancel(ctx)
 	sched.ctx = corev2.SetContextFromResource(sched.ctx, check)
@@ -66,8 +68,8 @@ func (s *CronScheduler) Start() {
 
 func (s *CronScheduler) start() {
 	s.logger.Info("starting new cron scheduler")
-	timer := NewCronTimer(s.check.Name, s.check.Cron)
-	executor := NewCheckExecutor(s.bus, s.check.Namespace, s.store, s.entityCache)
+	timer := NewCronTimer(s.check.Name, s.check.Cron, s.clock)
+	executor := NewCheckExecutor(s.bus, s.check.Namespace, s.store, s.entityCache, s.clock)
 	timer.Start()
 
 	for {
@@ -2,11 +2,18 @@ package schedulerd
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
 
 	time "github.com/echlebek/timeproxy"
+	"github.com/google/uuid"
+	"github.com/robfig/cron"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/store"
@@ -17,6 +24,11 @@ import (
 
 var (
 	adhocQueueName = "adhocRequest"
+
+	// minCheckRequestTTL is the minimum time during which a scheduled check
+	// request can be executed by the agents, so they don't discard the
+	// requests of the checks with short intervals because of clock skew.
+	minCheckRequestTTL = time.Minute
 )
 
 // Executor executes scheduled or adhoc checks
@@ -34,11 +46,13 @@ type CheckExecutor struct {
 	store       store.Store
 	namespace   string
 	entityCache *cache.Resource
+	clock       Clock
 }
 
-// NewCheckExecutor creates a new check executor
-func NewCheckExecutor(bus messaging.MessageBus, namespace string, store store.Store, cache *cache.Resource) *CheckExecutor {
-	return &CheckExecutor{bus: bus, namespace: namespace, store: store, entityCache: cache}
+// NewCheckExecutor creates a new check executor, issuing the requests at the
+// time of the clock (the timeproxy clock if nil)
+func NewCheckExecutor(bus messaging.MessageBus, namespace string, store store.Store, cache *cache.Resource, clock Clock) *CheckExecutor {
+	return &CheckExecutor{bus: bus, namespace: namespace, store: store, entityCache: cache, clock: clockOrDefault(clock)}
 }
 
 // ProcessCheck processes a check by publishing its proxy requests (if any)
@@ -52,7 +66,7 @@ func (c *CheckExecutor) getEntities(ctx context.Context) ([]cache.Value, error)
 }
 
 func (c *CheckExecutor) publishProxyCheckRequests(entities []*types.Entity, check *types.CheckConfig) error {
-	return publishProxyCheckRequests(c, entities, check)
+	return publishProxyCheckRequests(c, c.clock, entities, check)
 }
 
 func (c *CheckExecutor) execute(check *types.CheckConfig) error {
@@ -105,7 +119,53 @@ func (c *CheckExecutor) executeOnEntity(check *corev2.CheckConfig, entity string
 }
 
 func (c *CheckExecutor) buildRequest(check *types.CheckConfig) (*types.CheckRequest, error) {
-	return buildRequest(check, c.store)
+	request, err := buildRequest(check, c.store, c.clock)
+	if err != nil {
+		return nil, err
+	}
+	request.ID = scheduledRequestID(check, time.Unix(request.Issued, 0))
+	request.Expires = scheduledRequestExpiry(check, time.Unix(request.Issued, 0))
+	return request, nil
+}
+
+// scheduledRequestID returns the identifier of the scheduled execution of a
+// check at the given time. Interval checks fire at the same offset of every
+// interval on every backend, so the identifier is derived from the interval
+// the execution belongs to, and backends that schedule the same execution,
+// e.g. after a ring or leadership change, produce the same identifier. This
+// allows agents to discard the duplicate check requests they receive.
+func scheduledRequestID(check *types.CheckConfig, issued time.Time) string {
+	slot := issued.Unix()
+	if check.Cron == "" && check.Interval > 0 {
+		sum := md5.Sum([]byte(check.Name))
+		splay := binary.LittleEndian.Uint64(sum[:])
+		interval := uint64(check.Interval) * uint64(time.Second)
+		// round to the nearest execution to absorb timer jitter
+		elapsed := uint64(issued.UnixNano()) - splay%interval + interval/2
+		slot = int64(elapsed / interval)
+	}
+	return path.Join(check.Namespace, check.Name, check.ProxyEntityName, strconv.FormatInt(slot, 10))
+}
+
+// scheduledRequestExpiry returns the time after which the scheduled execution
+// of a check issued at the given time is stale, as a Unix timestamp: once the
+// next execution was scheduled, or after minCheckRequestTTL if later. This
+// allows agents reconnecting after an outage to discard the requests queued
+// in the meantime instead of executing all of them.
+func scheduledRequestExpiry(check *types.CheckConfig, issued time.Time) int64 {
+	ttl := time.Duration(check.Interval) * time.Second
+	if check.Cron != "" {
+		schedule, err := cron.ParseStandard(check.Cron)
+		if err != nil {
+			return 0
+		}
+		next := schedule.Next(issued)
+		ttl = schedule.Next(next).Sub(next)
+	}
+	if ttl < minCheckRequestTTL {
+		ttl = minCheckRequestTTL
+	}
+	return issued.Add(ttl).Unix()
 }
 
 func assetIsRelevant(asset *types.Asset, assets []string) bool {
@@ -140,10 +200,12 @@ type AdhocRequestExecutor struct {
 	cancel         context.CancelFunc
 	listenQueueErr chan error
 	entityCache    *cache.Resource
+	clock          Clock
 }
 
-// NewAdhocRequestExecutor returns a new AdhocRequestExecutor.
-func NewAdhocRequestExecutor(ctx context.Context, store store.Store, queue types.Queue, bus messaging.MessageBus, cache *cache.Resource) *AdhocRequestExecutor {
+// NewAdhocRequestExecutor returns a new AdhocRequestExecutor, issuing the
+// requests at the time of the clock (the timeproxy clock if nil).
+func NewAdhocRequestExecutor(ctx context.Context, store store.Store, queue types.Queue, bus messaging.MessageBus, cache *cache.Resource, clock Clock) *AdhocRequestExecutor {
 	ctx, cancel := context.WithCancel(ctx)
 	executor := &AdhocRequestExecutor{
 		adhocQueue:  queue,
@@ -152,6 +214,7 @@ func NewAdhocRequestExecutor(ctx context.Context, store store.Store, queue types
 		ctx:         ctx,
 		cancel:      cancel,
 		entityCache: cache,
+		clock:       clockOrDefault(clock),
 	}
 	go executor.listenQueue(ctx)
 	return executor
@@ -207,7 +270,7 @@ func (a *AdhocRequestExecutor) getEntities(ctx context.Context) ([]cache.Value,
 }
 
 func (a *AdhocRequestExecutor) publishProxyCheckRequests(entities []*types.Entity, check *types.CheckConfig) error {
-	return publishProxyCheckRequests(a, entities, check)
+	return publishProxyCheckRequests(a, a.clock, entities, check)
 }
 
 func (a *AdhocRequestExecutor) execute(check *types.CheckConfig) error {
@@ -233,20 +296,25 @@ func (a *AdhocRequestExecutor) execute(check *types.CheckConfig) error {
 }
 
 func (a *AdhocRequestExecutor) buildRequest(check *types.CheckConfig) (*types.CheckRequest, error) {
-	return buildRequest(check, a.store)
+	request, err := buildRequest(check, a.store, a.clock)
+	if err != nil {
+		return nil, err
+	}
+	// adhoc requests are never duplicates of each other
+	request.ID = uuid.New().String()
+	return request, nil
 }
 
-func publishProxyCheckRequests(e Executor, entities []*types.Entity, check *types.CheckConfig) error {
-	var splay time.Duration
-	if check.ProxyRequests.Splay {
-		var err error
-		if splay, err = calculateSplayInterval(check, len(entities)); err != nil {
-			return err
-		}
+func publishProxyCheckRequests(e Executor, clock Clock, entities []*types.Entity, check *types.CheckConfig) error {
+	size, splay, err := calculateProxyRequestBatches(check, len(entities), clock.Now())
+	if err != nil {
+		return err
 	}
 
-	for _, entity := range entities {
-		time.Sleep(splay)
+	for i, entity := range entities {
+		if i%size == 0 {
+			clock.Sleep(splay)
+		}
 		substitutedCheck, err := substituteProxyEntityTokens(entity, check)
 		if err != nil {
 			return err
@@ -296,31 +364,41 @@ func processRoundRobinCheck(ctx context.Context, executor *CheckExecutor, check
 }
 
 func publishRoundRobinProxyCheckRequests(executor *CheckExecutor, check *corev2.CheckConfig, proxyEntities []*corev2.Entity, agentEntities []string) error {
-	var splay time.Duration
-	if check.ProxyRequests.Splay {
-		var err error
-		if splay, err = calculateSplayInterval(check, len(proxyEntities)); err != nil {
-			return err
-		}
+	size, splay, err := calculateProxyRequestBatches(check, len(proxyEntities), executor.clock.Now())
+	if err != nil {
+		return err
 	}
 
-	for i, proxyEntity := range proxyEntities {
-		now := time.Now()
-		agentEntity := agentEntities[i]
-		substitutedCheck, err := substituteProxyEntityTokens(proxyEntity, check)
-		if err != nil {
-			return err
+	for start := 0; start < len(proxyEntities); start += size {
+		now := executor.clock.Now()
+		end := start + size
+		if end > len(proxyEntities) {
+			end = len(proxyEntities)
 		}
-		if err := executor.executeOnEntity(substitutedCheck, agentEntity); err != nil {
-			return err
+		// The requests of a batch are sent to each agent one after the other
+		batch := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			batch = append(batch, i)
+		}
+		sort.SliceStable(batch, func(i, j int) bool {
+			return agentEntities[batch[i]] < agentEntities[batch[j]]
+		})
+		for _, i := range batch {
+			substitutedCheck, err := substituteProxyEntityTokens(proxyEntities[i], check)
+			if err != nil {
+				return err
+			}
+			if err := executor.executeOnEntity(substitutedCheck, agentEntities[i]); err != nil {
+				return err
+			}
 		}
-		dreamtime := splay - time.Now().Sub(now)
-		time.Sleep(dreamtime)
+		dreamtime := splay - executor.clock.Now().Sub(now)
+		executor.clock.Sleep(dreamtime)
 	}
 	return nil
 }
 
-func buildRequest(check *types.CheckConfig, s store.Store) (*types.CheckRequest, error) {
+func buildRequest(check *types.CheckConfig, s store.Store, clock Clock) (*types.CheckRequest, error) {
 	request := &types.CheckRequest{}
 	request.Config = check
 	request.HookAssets = make(map[string]*corev2.AssetList)
@@ -368,7 +446,7 @@ func buildRequest(check *types.CheckConfig, s store.Store) (*types.CheckRequest,
 		}
 	}
 
-	request.Issued = time.Now().Unix()
+	request.Issued = clock.Now().Unix()
 
 	return request, nil
 }
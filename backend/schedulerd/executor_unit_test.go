@@ -0,0 +1,54 @@
+package schedulerd
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"testing"
+
+	time "github.com/echlebek/timeproxy"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledRequestID(t *testing.T) {
+	check := types.FixtureCheckConfig("check")
+	check.Interval = 60
+
+	// The interval timer fires at the same offset of every interval
+	sum := md5.Sum([]byte(check.Name))
+	offset := binary.LittleEndian.Uint64(sum[:]) % uint64(time.Minute)
+	fired := time.Unix(0, int64(1000*uint64(time.Minute)+offset))
+
+	id := scheduledRequestID(check, fired)
+	assert.Equal(t, id, scheduledRequestID(check, fired.Add(2*time.Second)))
+	assert.Equal(t, id, scheduledRequestID(check, fired.Add(-2*time.Second)))
+	assert.NotEqual(t, id, scheduledRequestID(check, fired.Add(time.Minute)))
+
+	proxyCheck := types.FixtureCheckConfig("check")
+	proxyCheck.Interval = 60
+	proxyCheck.ProxyEntityName = "proxy"
+	assert.NotEqual(t, id, scheduledRequestID(proxyCheck, fired))
+
+	check.Cron = "* * * * *"
+	assert.NotEqual(t,
+		scheduledRequestID(check, fired),
+		scheduledRequestID(check, fired.Add(time.Second)))
+}
+
+func TestScheduledRequestExpiry(t *testing.T) {
+	issued := time.Unix(1000*60, 0)
+	check := types.FixtureCheckConfig("check")
+
+	check.Interval = 300
+	assert.Equal(t, issued.Add(5*time.Minute).Unix(), scheduledRequestExpiry(check, issued))
+
+	// Short intervals expire after the minimum TTL
+	check.Interval = 10
+	assert.Equal(t, issued.Add(minCheckRequestTTL).Unix(), scheduledRequestExpiry(check, issued))
+
+	check.Cron = "0 * * * *"
+	assert.Equal(t, issued.Add(time.Hour).Unix(), scheduledRequestExpiry(check, issued))
+
+	check.Cron = "invalid"
+	assert.Zero(t, scheduledRequestExpiry(check, issued))
+}
@@ -33,6 +33,8 @@ const (
 	RoundRobinIntervalType
 	// RoundRobinCronType ...
 	RoundRobinCronType
+	// RunAtType ...
+	RunAtType
 )
 
 func (s SchedulerType) String() string {
@@ -45,13 +47,19 @@ func (s SchedulerType) String() string {
 		return "round-robin interval"
 	case RoundRobinCronType:
 		return "round-robin cron"
+	case RunAtType:
+		return "run_at"
 	default:
 		return "invalid"
 	}
 }
 
-// GetSchedulerType gets the SchedulerType for a given check config.
+// GetSchedulerType gets the SchedulerType for a given check config. The checks
+// scheduled at run_at times are never scheduled round-robin.
 func GetSchedulerType(check *corev2.CheckConfig) SchedulerType {
+	if len(check.RunAt) > 0 {
+		return RunAtType
+	}
 	if check.Cron != "" {
 		if check.RoundRobin {
 			return RoundRobinCronType
@@ -22,10 +22,11 @@ type IntervalScheduler struct {
 	cancel            context.CancelFunc
 	interrupt         chan *corev2.CheckConfig
 	entityCache       *cache.Resource
+	clock             Clock
 }
 
 // NewIntervalScheduler initializes an IntervalScheduler
-func NewIntervalScheduler(ctx context.Context, store store.Store, bus messaging.MessageBus, check *types.CheckConfig, cache *cache.Resource) *IntervalScheduler {
+func NewIntervalScheduler(ctx context.Context, store store.Store, bus messaging.MessageBus, check *types.CheckConfig, cache *cache.Resource, clock Clock) *IntervalScheduler {
 	sched := &IntervalScheduler{
 		store:             store,
 		bus:               bus,
@@ -38,6 +39,7 @@ func NewIntervalScheduler(ctx context.Context, store store.Store, bus messaging.
 			"scheduler_type": IntervalType.String(),
 		}),
 		entityCache: cache,
+		clock:       clock,
 	}
 	sched.ctx, sched.cancel = context.WithCancel(ctx)
 	sched.ctx = types.SetContextFromResource(sched.ctx, check)
@@ -66,8 +68,8 @@ func (s *IntervalScheduler) Start() {
 
 func (s *IntervalScheduler) start() {
 	s.logger.Info("starting new interval scheduler")
-	timer := NewIntervalTimer(s.check.Name, uint(s.check.Interval))
-	executor := NewCheckExecutor(s.bus, s.check.Namespace, s.store, s.entityCache)
+	timer := NewIntervalTimer(s.check.Name, uint(s.check.Interval), s.clock)
+	executor := NewCheckExecutor(s.bus, s.check.Namespace, s.store, s.entityCache, s.clock)
 
 	timer.Start()
 
@@ -13,6 +13,11 @@ import (
 	"github.com/sensu/sensu-go/types/dynamic"
 )
 
+// MinProxyRequestsSplay is the minimum duration between two batches of splayed
+// proxy check requests. The requests of the entities that would be published
+// closer together are batched instead.
+const MinProxyRequestsSplay = 100 * time.Millisecond
+
 // matchEntities matches the provided list of entities to the entity attributes
 // configured in the proxy request
 func matchEntities(entities []cache.Value, proxyRequest *corev2.ProxyRequests) []*corev2.Entity {
@@ -70,19 +75,19 @@ func substituteProxyEntityTokens(entity *corev2.Entity, check *corev2.CheckConfi
 }
 
 // calculateSplayInterval calculates the duration between publishing proxy
-// requests to each individual entity (based on a configurable splay %)
-func calculateSplayInterval(check *corev2.CheckConfig, numEntities int) (time.Duration, error) {
+// requests to each individual entity (based on a configurable splay %), from
+// the given time
+func calculateSplayInterval(check *corev2.CheckConfig, numEntities int, now time.Time) (time.Duration, error) {
 	next := time.Second * time.Duration(check.Interval)
 	if check.Cron != "" {
 		schedule, err := cron.ParseStandard(check.Cron)
 		if err != nil {
 			return 0, err
 		}
-		now := time.Now()
 		then := schedule.Next(now)
 		next = then.Sub(now)
 		if next < 5*time.Second {
-			now = time.Now().Add(next + time.Second)
+			now = now.Add(next + time.Second)
 			then = schedule.Next(now)
 			next = then.Sub(now)
 		}
@@ -95,3 +100,39 @@ func calculateSplayInterval(check *corev2.CheckConfig, numEntities int) (time.Du
 	splay := time.Duration(float64(next) * timeSlice)
 	return splay, nil
 }
+
+// splayProxyRequests returns true if the proxy requests of the check are
+// splayed: if splay is enabled, or if the number of matching entities reaches
+// the splay threshold, so that large fan-outs don't burst every interval.
+func splayProxyRequests(check *corev2.CheckConfig, numEntities int) bool {
+	if check.ProxyRequests.Splay {
+		return true
+	}
+	threshold := check.ProxyRequests.SplayThreshold
+	if threshold == 0 {
+		threshold = corev2.DefaultSplayThreshold
+	}
+	return numEntities >= int(threshold)
+}
+
+// calculateProxyRequestBatches returns the number of proxy requests published
+// at once, and the duration between two batches of requests. The requests are
+// batched when they would be published less than MinProxyRequestsSplay apart,
+// and are all published at once if they are not splayed.
+func calculateProxyRequestBatches(check *corev2.CheckConfig, numEntities int, now time.Time) (int, time.Duration, error) {
+	if numEntities == 0 || !splayProxyRequests(check, numEntities) {
+		return numEntities, 0, nil
+	}
+	splay, err := calculateSplayInterval(check, numEntities, now)
+	if err != nil {
+		return 0, 0, err
+	}
+	if splay >= MinProxyRequestsSplay {
+		return 1, splay, nil
+	}
+	if splay <= 0 {
+		return numEntities, 0, nil
+	}
+	size := int((MinProxyRequestsSplay + splay - 1) / splay)
+	return size, splay * time.Duration(size), nil
+}
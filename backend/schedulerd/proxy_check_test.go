@@ -114,20 +114,20 @@ func TestSplayCalculation(t *testing.T) {
 
 	// 10s * 90% / 3 = 3
 	check.Interval = 10
-	splay, err := calculateSplayInterval(check, 3)
+	splay, err := calculateSplayInterval(check, 3, mockTime.Now())
 	assert.Equal(3*time.Second, splay)
 	assert.Nil(err)
 
 	// 20s * 50% / 5 = 2
 	check.Interval = 20
 	check.ProxyRequests.SplayCoverage = 50
-	splay, err = calculateSplayInterval(check, 5)
+	splay, err = calculateSplayInterval(check, 5, mockTime.Now())
 	assert.Equal(2*time.Second, splay)
 	assert.Nil(err)
 
 	// invalid cron string
 	check.Cron = "invalid"
-	splay, err = calculateSplayInterval(check, 5)
+	splay, err = calculateSplayInterval(check, 5, mockTime.Now())
 	assert.Equal(time.Duration(0), splay)
 	assert.NotNil(err)
 
@@ -135,11 +135,95 @@ func TestSplayCalculation(t *testing.T) {
 	// this test will depend on when it is run, but the
 	// largest splay calculation will be 15
 	check.Cron = "* * * * *"
-	splay, err = calculateSplayInterval(check, 2)
+	splay, err = calculateSplayInterval(check, 2, mockTime.Now())
 	assert.True(splay >= 0 && splay <= 15*time.Second)
 	assert.Nil(err)
 }
 
+func TestSplayCalculationCron(t *testing.T) {
+	check := corev2.FixtureCheckConfig("check1")
+	check.ProxyRequests = corev2.FixtureProxyRequests(true)
+	check.ProxyRequests.SplayCoverage = 50
+	check.Cron = "* * * * *"
+
+	// 30s until the next minute * 50% / 2 = 7.5s
+	now := time.Date(2019, 1, 1, 0, 0, 30, 0, time.UTC)
+	splay, err := calculateSplayInterval(check, 2, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 7500*time.Millisecond, splay)
+
+	// less than 5s until the next minute, the one after is used:
+	// 59s * 50% / 2 = 14.75s
+	now = time.Date(2019, 1, 1, 0, 0, 57, 0, time.UTC)
+	splay, err = calculateSplayInterval(check, 2, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 14750*time.Millisecond, splay)
+}
+
+func TestCalculateProxyRequestBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		splay     bool
+		threshold uint32
+		entities  int
+		size      int
+		interval  time.Duration
+	}{
+		{
+			name:     "not splayed under the default threshold",
+			entities: 10,
+			size:     10,
+		},
+		{
+			name:     "splayed",
+			splay:    true,
+			entities: 3,
+			size:     1,
+			interval: 30 * time.Second,
+		},
+		{
+			// 100s * 90% / 100 = 900ms
+			name:     "splayed from the default threshold",
+			entities: 100,
+			size:     1,
+			interval: 900 * time.Millisecond,
+		},
+		{
+			// 100s * 90% / 5000 = 18ms, batches of 6 every 108ms
+			name:     "batched",
+			entities: 5000,
+			size:     6,
+			interval: 108 * time.Millisecond,
+		},
+		{
+			name:      "splayed from the threshold of the check",
+			threshold: 10,
+			entities:  10,
+			size:      1,
+			interval:  9 * time.Second,
+		},
+		{
+			name:      "not splayed under the threshold of the check",
+			threshold: 1000,
+			entities:  500,
+			size:      500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := corev2.FixtureCheckConfig("check1")
+			check.Interval = 100
+			check.ProxyRequests = corev2.FixtureProxyRequests(tt.splay)
+			check.ProxyRequests.SplayThreshold = tt.threshold
+
+			size, interval, err := calculateProxyRequestBatches(check, tt.entities, mockTime.Now())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.size, size)
+			assert.Equal(t, tt.interval, interval)
+		})
+	}
+}
+
 func TestSubstituteProxyEntityTokens(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,121 @@
+package schedulerd
+
+import (
+	"context"
+
+	time "github.com/echlebek/timeproxy"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/cache"
+	"github.com/sirupsen/logrus"
+)
+
+// RunAtScheduler schedules checks to be executed once at each of the times of
+// their run_at attribute. The times that passed while the scheduler was not
+// running, e.g. while the backend was down, are skipped.
+type RunAtScheduler struct {
+	check       *corev2.CheckConfig
+	store       store.Store
+	bus         messaging.MessageBus
+	logger      *logrus.Entry
+	ctx         context.Context
+	cancel      context.CancelFunc
+	interrupt   chan *corev2.CheckConfig
+	entityCache *cache.Resource
+	clock       Clock
+}
+
+// NewRunAtScheduler initializes a RunAtScheduler
+func NewRunAtScheduler(ctx context.Context, store store.Store, bus messaging.MessageBus, check *corev2.CheckConfig, cache *cache.Resource, clock Clock) *RunAtScheduler {
+	sched := &RunAtScheduler{
+		store:     store,
+		bus:       bus,
+		check:     check,
+		interrupt: make(chan *corev2.CheckConfig),
+		logger: logger.WithFields(logrus.Fields{
+			"name":           check.Name,
+			"namespace":      check.Namespace,
+			"scheduler_type": RunAtType.String(),
+		}),
+		entityCache: cache,
+		clock:       clockOrDefault(clock),
+	}
+	sched.ctx, sched.cancel = context.WithCancel(ctx)
+	sched.ctx = corev2.SetContextFromResource(sched.ctx, check)
+	return sched
+}
+
+func (s *RunAtScheduler) schedule(executor *CheckExecutor) {
+	if s.check.IsSubdued() {
+		s.logger.Debug("check is subdued")
+		return
+	}
+
+	s.logger.Debug("check is not subdued")
+
+	if err := executor.processCheck(s.ctx, s.check); err != nil {
+		logger.WithError(err).Error("error executing check")
+	}
+}
+
+// Start starts the run_at scheduler.
+func (s *RunAtScheduler) Start() {
+	go s.start()
+}
+
+func (s *RunAtScheduler) start() {
+	s.logger.Info("starting new run_at scheduler")
+	executor := NewCheckExecutor(s.bus, s.check.Namespace, s.store, s.entityCache, s.clock)
+
+	for {
+		// The next time is looked up after every execution and every change
+		// of the check, so the timer always matches the current run_at times
+		var timer *time.Timer
+		var fired <-chan time.Time
+		now := s.clock.Now()
+		if next, ok := corev2.NextRunAt(s.check.RunAt, now); ok {
+			timer = s.clock.NewTimer(next.Sub(now))
+			fired = timer.C
+		} else {
+			s.logger.Debug("no run_at time left")
+		}
+
+		select {
+		case <-s.ctx.Done():
+			stopTimer(timer)
+			return
+		case check := <-s.interrupt:
+			stopTimer(timer)
+			s.check = check
+			continue
+		case <-fired:
+		}
+		s.schedule(executor)
+	}
+}
+
+// stopTimer stops the timer, if any.
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
+
+// Interrupt refreshes the scheduler with a revised check config.
+func (s *RunAtScheduler) Interrupt(check *corev2.CheckConfig) {
+	s.interrupt <- check
+}
+
+// Stop stops the run_at scheduler.
+func (s *RunAtScheduler) Stop() error {
+	logger.Info("stopping run_at scheduler")
+	s.cancel()
+
+	return nil
+}
+
+// Type returns the type of the run_at scheduler.
+func (s *RunAtScheduler) Type() SchedulerType {
+	return RunAtType
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/coreos/etcd/clientv3"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/readiness"
 	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/backend/store/cache"
@@ -25,6 +26,7 @@ type Schedulerd struct {
 	errChan              chan error
 	ringPool             *ringv2.Pool
 	entityCache          *cache.Resource
+	readiness            *readiness.Gate
 }
 
 // Option is a functional option.
@@ -37,6 +39,15 @@ type Config struct {
 	RingPool    *ringv2.Pool
 	Bus         messaging.MessageBus
 	Client      *clientv3.Client
+
+	// Clock is the clock of the schedulers. It defaults to the timeproxy
+	// clock, and can be replaced by a mock clock to advance the time of the
+	// schedulers deterministically in tests.
+	Clock Clock
+
+	// Readiness holds back the scheduled check requests until the backend
+	// is warm. The requests are published at once if nil.
+	Readiness *readiness.Gate
 }
 
 // New creates a new Schedulerd.
@@ -47,6 +58,7 @@ func New(ctx context.Context, c Config, opts ...Option) (*Schedulerd, error) {
 		bus:         c.Bus,
 		errChan:     make(chan error, 1),
 		ringPool:    c.RingPool,
+		readiness:   c.Readiness,
 	}
 	s.ctx, s.cancel = context.WithCancel(ctx)
 	cache, err := cache.New(s.ctx, c.Client, &corev2.Entity{}, true)
@@ -54,8 +66,13 @@ func New(ctx context.Context, c Config, opts ...Option) (*Schedulerd, error) {
 		return nil, err
 	}
 	s.entityCache = cache
-	s.checkWatcher = NewCheckWatcher(s.ctx, c.Bus, c.Store, c.RingPool, cache)
-	s.adhocRequestExecutor = NewAdhocRequestExecutor(s.ctx, s.store, s.queueGetter.GetQueue(adhocQueueName), s.bus, s.entityCache)
+	// The ad hoc requests are not held back, only the scheduled ones
+	var schedulerBus messaging.MessageBus = c.Bus
+	if c.Readiness != nil {
+		schedulerBus = &warmupBus{MessageBus: c.Bus, readiness: c.Readiness}
+	}
+	s.checkWatcher = NewCheckWatcher(s.ctx, schedulerBus, c.Store, c.RingPool, cache, c.Clock)
+	s.adhocRequestExecutor = NewAdhocRequestExecutor(s.ctx, s.store, s.queueGetter.GetQueue(adhocQueueName), s.bus, s.entityCache, c.Clock)
 
 	for _, o := range opts {
 		if err := o(s); err != nil {
@@ -67,7 +84,11 @@ func New(ctx context.Context, c Config, opts ...Option) (*Schedulerd, error) {
 
 // Start the Scheduler daemon.
 func (s *Schedulerd) Start() error {
-	return s.checkWatcher.Start()
+	if err := s.checkWatcher.Start(); err != nil {
+		return err
+	}
+	s.readiness.Done(s.Name())
+	return nil
 }
 
 // Stop the scheduler daemon.
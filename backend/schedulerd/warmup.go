@@ -0,0 +1,27 @@
+package schedulerd
+
+import (
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/readiness"
+	"github.com/sirupsen/logrus"
+)
+
+// warmupBus is the message bus of the schedulers, which drops the check
+// requests they publish until the backend is warm. The checks are scheduled
+// again at their next execution.
+type warmupBus struct {
+	messaging.MessageBus
+	readiness *readiness.Gate
+}
+
+// Publish publishes the message, unless the backend is warming up.
+func (b *warmupBus) Publish(topic string, message interface{}) error {
+	if !b.readiness.Ready() {
+		logger.WithFields(logrus.Fields{
+			"topic":   topic,
+			"pending": b.readiness.Pending(),
+		}).Debug("backend warming up, not sending check request")
+		return nil
+	}
+	return b.MessageBus.Publish(topic, message)
+}
@@ -0,0 +1,34 @@
+package schedulerd
+
+import (
+	"testing"
+
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/readiness"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingBus struct {
+	messaging.MessageBus
+	topics []string
+}
+
+func (b *recordingBus) Publish(topic string, message interface{}) error {
+	b.topics = append(b.topics, topic)
+	return nil
+}
+
+func TestWarmupBus(t *testing.T) {
+	gate := readiness.NewGate("schedulerd")
+	recorder := &recordingBus{}
+	bus := &warmupBus{MessageBus: recorder, readiness: gate}
+
+	require.NoError(t, bus.Publish("sensu:check:default:linux", nil))
+	assert.Empty(t, recorder.topics)
+
+	gate.Start(0)
+	gate.Done("schedulerd")
+	require.NoError(t, bus.Publish("sensu:check:default:linux", nil))
+	assert.Equal(t, []string{"sensu:check:default:linux"}, recorder.topics)
+}
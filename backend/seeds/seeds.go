@@ -168,6 +168,7 @@ func setupClusterRoles(store store.Store) error {
 				Resources: append(types.CommonCoreResources, []string{
 					"roles",
 					"rolebindings",
+					corev2.EnrollmentTokensResource,
 				}...),
 			},
 			types.Rule{
@@ -0,0 +1,122 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	agentConfigPathPrefix = "agent-configs"
+)
+
+var (
+	agentConfigKeyBuilder = store.NewKeyBuilder(agentConfigPathPrefix)
+)
+
+func getAgentConfigPath(ctx context.Context, entity string) string {
+	return agentConfigKeyBuilder.WithContext(ctx).Build(entity)
+}
+
+// DeleteAgentConfig deletes the configuration of the agent of the given
+// entity.
+func (s *Store) DeleteAgentConfig(ctx context.Context, entity string) error {
+	if entity == "" {
+		return errors.New("must specify entity")
+	}
+
+	key := getAgentConfigPath(ctx, entity)
+	resp, err := s.client.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return &store.ErrNotFound{Key: key}
+	}
+	return nil
+}
+
+// GetAgentConfig gets the configuration of the agent of the given entity, or
+// nil if there is none.
+func (s *Store) GetAgentConfig(ctx context.Context, entity string) (*corev2.AgentConfig, error) {
+	if entity == "" {
+		return nil, errors.New("must specify entity")
+	}
+
+	config := &corev2.AgentConfig{}
+	err := Get(ctx, s.client, getAgentConfigPath(ctx, entity), config)
+	if _, ok := err.(*store.ErrNotFound); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// UpdateAgentConfig creates or updates the configuration of the agent of the
+// given entity.
+func (s *Store) UpdateAgentConfig(ctx context.Context, entity string, config *corev2.AgentConfig) error {
+	if entity == "" {
+		return errors.New("must specify entity")
+	}
+	if err := config.Validate(); err != nil {
+		return &store.ErrNotValid{Err: err}
+	}
+
+	key := getAgentConfigPath(ctx, entity)
+	b, err := json.Marshal(config)
+	if err != nil {
+		return &store.ErrEncode{Key: key, Err: err}
+	}
+	_, err = s.client.Put(ctx, key, string(b))
+	return err
+}
+
+// GetAgentConfigWatcher returns a channel that emits WatchEventAgentConfig
+// structs notifying the caller that the configuration of an agent was updated,
+// in any namespace. If the watcher runs into a terminal error or the context
+// passed is cancelled, then the channel will be closed.
+func (s *Store) GetAgentConfigWatcher(ctx context.Context) <-chan store.WatchEventAgentConfig {
+	ch := make(chan store.WatchEventAgentConfig, 1)
+	prefix := agentConfigKeyBuilder.Build() + "/"
+	w := Watch(ctx, s.client, prefix, true)
+
+	go func() {
+		defer close(ch)
+		for response := range w.Result() {
+			// The configurations are pushed again when the agents reconnect
+			if response.Type == store.WatchError {
+				continue
+			}
+
+			// The keys are made of the namespace and the name of the entity
+			parts := strings.SplitN(strings.TrimPrefix(response.Key, prefix), "/", 2)
+			if len(parts) != 2 {
+				logger.WithField("key", response.Key).Error("unexpected agent config key")
+				continue
+			}
+
+			var config corev2.AgentConfig
+			if response.Type != store.WatchDelete {
+				if err := json.Unmarshal(response.Object, &config); err != nil {
+					logger.WithField("key", response.Key).WithError(err).Error("unable to unmarshal agent config from key")
+					continue
+				}
+			}
+
+			ch <- store.WatchEventAgentConfig{
+				Namespace:   parts[0],
+				Entity:      parts[1],
+				AgentConfig: &config,
+				Action:      response.Type,
+			}
+		}
+	}()
+
+	return ch
+}
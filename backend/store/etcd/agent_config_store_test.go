@@ -0,0 +1,66 @@
+// +build integration,!race
+
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentConfigStorage(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+
+		// We should receive no config if none was configured
+		config, err := s.GetAgentConfig(ctx, "web01")
+		require.NoError(t, err)
+		assert.Nil(t, config)
+
+		// Invalid configs are rejected
+		err = s.UpdateAgentConfig(ctx, "web01", &corev2.AgentConfig{LogLevel: "verbose"})
+		assert.IsType(t, &store.ErrNotValid{}, err)
+
+		watchCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		watcher := s.GetAgentConfigWatcher(watchCtx)
+
+		expected := &corev2.AgentConfig{
+			LogLevel: "debug",
+			Labels:   map[string]string{"region": "us-west-2"},
+		}
+		require.NoError(t, s.UpdateAgentConfig(ctx, "web01", expected))
+
+		event := <-watcher
+		assert.Equal(t, store.WatchCreate, event.Action)
+		assert.Equal(t, "default", event.Namespace)
+		assert.Equal(t, "web01", event.Entity)
+		assert.Equal(t, expected, event.AgentConfig)
+
+		config, err = s.GetAgentConfig(ctx, "web01")
+		require.NoError(t, err)
+		assert.Equal(t, expected, config)
+
+		// The configs are namespaced
+		acme := context.WithValue(context.Background(), corev2.NamespaceKey, "acme")
+		config, err = s.GetAgentConfig(acme, "web01")
+		require.NoError(t, err)
+		assert.Nil(t, config)
+
+		require.NoError(t, s.DeleteAgentConfig(ctx, "web01"))
+		event = <-watcher
+		assert.Equal(t, store.WatchDelete, event.Action)
+		assert.Equal(t, &corev2.AgentConfig{}, event.AgentConfig)
+
+		config, err = s.GetAgentConfig(ctx, "web01")
+		require.NoError(t, err)
+		assert.Nil(t, config)
+
+		err = s.DeleteAgentConfig(ctx, "web01")
+		assert.IsType(t, &store.ErrNotFound{}, err)
+	})
+}
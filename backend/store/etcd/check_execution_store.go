@@ -0,0 +1,129 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	checkExecutionsPathPrefix = "check-executions"
+
+	// checkExecutionAttempts is the number of attempts to acquire an
+	// execution slot while other agents acquire or release slots of the same
+	// check.
+	checkExecutionAttempts = 5
+
+	// checkExecutionGrantTimeout is the time allowed to grant the lease of the
+	// execution slots of a session.
+	checkExecutionGrantTimeout = 5 * time.Second
+
+	// checkExecutionRevokeTimeout is the time allowed to revoke the lease of
+	// the execution slots of a session.
+	checkExecutionRevokeTimeout = 5 * time.Second
+)
+
+var (
+	checkExecutionKeyBuilder = store.NewKeyBuilder(checkExecutionsPathPrefix)
+)
+
+func getCheckExecutionPath(ctx context.Context, check, agent string) string {
+	return checkExecutionKeyBuilder.WithContext(ctx).Build(check, agent)
+}
+
+func getCheckExecutionsPath(ctx context.Context, check string) string {
+	return checkExecutionKeyBuilder.WithContext(ctx).WithExactMatch().Build(check)
+}
+
+// GrantCheckExecutionLease grants the lease of the execution slots of an
+// agent session, so that they are released if the backend of the session
+// stops, and keeps it alive until ctx is done.
+func (s *Store) GrantCheckExecutionLease(ctx context.Context, ttl time.Duration) (int64, error) {
+	seconds := int64(ttl / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	// The timeout only applies to the grant, the lease is kept alive with ctx
+	grantCtx, cancel := context.WithTimeout(ctx, checkExecutionGrantTimeout)
+	lease, err := s.client.Grant(grantCtx, seconds)
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+	keepalives, err := s.client.KeepAlive(ctx, lease.ID)
+	if err != nil {
+		_, _ = s.client.Revoke(context.Background(), lease.ID)
+		return 0, err
+	}
+
+	go func() {
+		for range keepalives {
+		}
+		// The lease is kept alive until ctx is done, or it expired
+		revokeCtx, cancel := context.WithTimeout(context.Background(), checkExecutionRevokeTimeout)
+		defer cancel()
+		_, _ = s.client.Revoke(revokeCtx, lease.ID)
+	}()
+
+	return int64(lease.ID), nil
+}
+
+// AcquireCheckExecution acquires one of the execution slots of the check for
+// the agent. Each slot is a key of the agent, attached to the lease of its
+// session.
+func (s *Store) AcquireCheckExecution(ctx context.Context, check, agent string, max int, lease int64) (bool, error) {
+	if check == "" || agent == "" {
+		return false, errors.New("must specify check and agent")
+	}
+	if max <= 0 {
+		return false, errors.New("the maximum number of executions must be positive")
+	}
+
+	prefix := getCheckExecutionsPath(ctx, check)
+	key := getCheckExecutionPath(ctx, check, agent)
+	for attempt := 0; attempt < checkExecutionAttempts; attempt++ {
+		resp, err := s.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+		if err != nil {
+			return false, err
+		}
+		held := false
+		for _, kv := range resp.Kvs {
+			if string(kv.Key) == key {
+				held = true
+				break
+			}
+		}
+		if !held && len(resp.Kvs) >= max {
+			break
+		}
+
+		// The slot is only acquired if no other slot was acquired since they
+		// were counted
+		cmp := clientv3.Compare(clientv3.ModRevision(prefix), "<", resp.Header.Revision+1).WithPrefix()
+		put := clientv3.OpPut(key, agent, clientv3.WithLease(clientv3.LeaseID(lease)))
+		txn, err := s.client.Txn(ctx).If(cmp).Then(put).Commit()
+		if err != nil {
+			return false, err
+		}
+		if txn.Succeeded {
+			return true, nil
+		}
+	}
+
+	// The slots are all held, or kept being acquired by other agents
+	return false, nil
+}
+
+// ReleaseCheckExecution releases the execution slot of the check held by the
+// agent.
+func (s *Store) ReleaseCheckExecution(ctx context.Context, check, agent string) error {
+	if check == "" || agent == "" {
+		return errors.New("must specify check and agent")
+	}
+
+	_, err := s.client.Delete(ctx, getCheckExecutionPath(ctx, check, agent))
+	return err
+}
@@ -0,0 +1,95 @@
+// +build integration,!race
+
+package etcd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckExecutionStorage(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+
+		leaseCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		lease, err := s.GrantCheckExecutionLease(leaseCtx, time.Minute)
+		require.NoError(t, err)
+
+		for _, agent := range []string{"db01", "db02"} {
+			acquired, err := s.AcquireCheckExecution(ctx, "check-db", agent, 2, lease)
+			require.NoError(t, err)
+			assert.True(t, acquired, agent)
+		}
+
+		// The slots are all held
+		acquired, err := s.AcquireCheckExecution(ctx, "check-db", "db03", 2, lease)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		// An agent holding a slot keeps it
+		acquired, err = s.AcquireCheckExecution(ctx, "check-db", "db01", 2, lease)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		// The slots of a check don't limit the other checks, nor the same
+		// check of other namespaces
+		acquired, err = s.AcquireCheckExecution(ctx, "check-db-backup", "db03", 2, lease)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		acme := context.WithValue(context.Background(), corev2.NamespaceKey, "acme")
+		acquired, err = s.AcquireCheckExecution(acme, "check-db", "db03", 2, lease)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		require.NoError(t, s.ReleaseCheckExecution(ctx, "check-db", "db02"))
+		acquired, err = s.AcquireCheckExecution(ctx, "check-db", "db03", 2, lease)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		// Releasing a slot that isn't held is not an error
+		assert.NoError(t, s.ReleaseCheckExecution(ctx, "check-db", "db04"))
+
+		_, err = s.AcquireCheckExecution(ctx, "check-db", "db04", 0, lease)
+		assert.Error(t, err)
+
+		// The slots are released with the lease of the session
+		cancel()
+		for i := 0; i < 50; i++ {
+			acquired, err = s.AcquireCheckExecution(ctx, "check-db", "db04", 1, 0)
+			require.NoError(t, err)
+			if acquired {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		assert.True(t, acquired)
+
+		// Acquiring a slot with an expired lease fails
+		_, err = s.AcquireCheckExecution(ctx, "check-db-backup", "db04", 2, lease)
+		assert.Error(t, err)
+	})
+}
+
+func TestCheckExecutionLeaseOutlivesGrant(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+
+		leaseCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		lease, err := s.GrantCheckExecutionLease(leaseCtx, time.Minute)
+		require.NoError(t, err)
+
+		// The lease is kept alive once the timeout of its grant is cancelled
+		time.Sleep(time.Second)
+		acquired, err := s.AcquireCheckExecution(ctx, "check-db", "db01", 1, lease)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+}
@@ -0,0 +1,86 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/gogo/protobuf/proto"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// labelEntitiesAttempts is the number of times the entities modified during
+// their labeling, e.g. by a keepalive, are labeled again.
+const labelEntitiesAttempts = 5
+
+// LabelEntities sets and removes the labels of the labeling on the entities
+// matching its selector, within the namespace stored in ctx, and returns the
+// names of the entities whose labels changed. The entities are labeled
+// atomically, in a single transaction, which fails if any of them is modified
+// in the meantime, in which case they are labeled again. The labeling is
+// rejected if it changes more than maxTxnOps entities. If the policy is not
+// nil, it is enforced on the labeled entities.
+func (s *Store) LabelEntities(ctx context.Context, labeling *corev2.EntityLabeling, policy *corev2.LabelPolicy) ([]string, error) {
+	if store.NewNamespaceFromContext(ctx) == "" {
+		return nil, &store.ErrNotValid{Err: errors.New("the namespace of the entities must be set")}
+	}
+	if err := labeling.Validate(); err != nil {
+		return nil, &store.ErrNotValid{Err: err}
+	}
+
+	for attempt := 0; attempt < labelEntitiesAttempts; attempt++ {
+		resp, err := s.client.Get(ctx, GetEntitiesPath(ctx, ""), clientv3.WithPrefix())
+		if err != nil {
+			return nil, err
+		}
+
+		var names []string
+		var cmps []clientv3.Cmp
+		var ops []clientv3.Op
+		for _, kv := range resp.Kvs {
+			entity := &corev2.Entity{}
+			if err := unmarshal(kv.Value, entity); err != nil {
+				return nil, &store.ErrDecode{Key: string(kv.Key), Err: err}
+			}
+			if !labeling.Selects(entity) || !labeling.Apply(entity) {
+				continue
+			}
+			if err := policy.Enforce(corev2.EntitiesResource, entity.ObjectMeta); err != nil {
+				return nil, &store.ErrNotValid{Err: err}
+			}
+			if err := entity.Validate(); err != nil {
+				return nil, &store.ErrNotValid{Err: err}
+			}
+			entityBytes, err := proto.Marshal(entity)
+			if err != nil {
+				return nil, &store.ErrEncode{Key: string(kv.Key), Err: err}
+			}
+			cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(string(kv.Key)), "=", kv.ModRevision))
+			ops = append(ops, clientv3.OpPut(string(kv.Key), string(entityBytes)))
+			names = append(names, entity.Name)
+		}
+		if len(ops) == 0 {
+			return []string{}, nil
+		}
+		if len(ops) > maxTxnOps {
+			return nil, &store.ErrNotValid{Err: fmt.Errorf(
+				"the labeling changes %d entities, more than the %d entities labeled atomically, narrow its selector",
+				len(ops), maxTxnOps,
+			)}
+		}
+
+		res, err := s.client.Txn(ctx).If(cmps...).Then(ops...).Commit()
+		if err != nil {
+			return nil, err
+		}
+		if res.Succeeded {
+			sort.Strings(names)
+			return names, nil
+		}
+	}
+
+	return nil, &store.ErrInternal{Message: "the entities were modified during their labeling, try again"}
+}
@@ -0,0 +1,79 @@
+// +build integration,!race
+
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelEntities(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+		for i := 0; i < maxTxnOps; i++ {
+			entity := corev2.FixtureEntity(fmt.Sprintf("web-%d", i))
+			entity.Labels = map[string]string{"team": "web"}
+			require.NoError(t, s.UpdateEntity(ctx, entity))
+		}
+		db := corev2.FixtureEntity("db")
+		db.Labels = map[string]string{"team": "db"}
+		require.NoError(t, s.UpdateEntity(ctx, db))
+
+		labeling := &corev2.EntityLabeling{
+			Selector: corev2.EntitySelector{Labels: map[string]string{"team": "web"}},
+			Set:      map[string]string{"team": "frontend", "region": "us-west-1"},
+		}
+		labeled, err := s.LabelEntities(ctx, labeling, nil)
+		require.NoError(t, err)
+		assert.Len(t, labeled, maxTxnOps)
+
+		entity, err := s.GetEntityByName(ctx, "web-0")
+		require.NoError(t, err)
+		assert.Equal(t, "frontend", entity.Labels["team"])
+		assert.Equal(t, "us-west-1", entity.Labels["region"])
+		entity, err = s.GetEntityByName(ctx, "db")
+		require.NoError(t, err)
+		assert.Equal(t, "db", entity.Labels["team"])
+
+		// The entities already labeled are not labeled again
+		labeling = &corev2.EntityLabeling{
+			Selector: corev2.EntitySelector{Names: []string{"web-0", "db"}},
+			Set:      map[string]string{"region": "us-west-1"},
+		}
+		labeled, err = s.LabelEntities(ctx, labeling, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"db"}, labeled)
+
+		// The label policy is enforced
+		policy := &corev2.LabelPolicy{Rules: []corev2.LabelPolicyRule{{
+			Resources: []string{corev2.EntitiesResource},
+			Labels:    map[string]string{"region": ""},
+		}}}
+		labeling = &corev2.EntityLabeling{
+			Selector: corev2.EntitySelector{Names: []string{"db"}},
+			Remove:   []string{"region"},
+		}
+		_, err = s.LabelEntities(ctx, labeling, policy)
+		assert.IsType(t, &store.ErrNotValid{}, err)
+		entity, err = s.GetEntityByName(ctx, "db")
+		require.NoError(t, err)
+		assert.Equal(t, "us-west-1", entity.Labels["region"])
+
+		// More entities than fit in a transaction are not labeled at all
+		labeling = &corev2.EntityLabeling{
+			Selector: corev2.EntitySelector{Labels: map[string]string{"region": "us-west-1"}},
+			Set:      map[string]string{"env": "prod"},
+		}
+		_, err = s.LabelEntities(ctx, labeling, nil)
+		assert.IsType(t, &store.ErrNotValid{}, err)
+		entity, err = s.GetEntityByName(ctx, "web-0")
+		require.NoError(t, err)
+		assert.Empty(t, entity.Labels["env"])
+	})
+}
@@ -0,0 +1,261 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/gogo/protobuf/proto"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// maxCheckHistory is the number of entries kept in the history of a check.
+const maxCheckHistory = 21
+
+// RenameEntity renames the entity of the given name, within the namespace
+// stored in ctx, and moves its events and the silenced entries of its entity
+// subscription to the new name, in a single transaction. If an entity of the
+// new name exists, it returns an ErrAlreadyExists error unless merge is true,
+// in which case the entity is merged into it: the existing entity is kept,
+// completed with the subscriptions, labels and annotations of the renamed
+// one, and the histories of the events of the same checks are merged.
+func (s *Store) RenameEntity(ctx context.Context, name, newName string, merge bool) (*corev2.Entity, error) {
+	if name == "" || newName == "" {
+		return nil, errors.New("must specify name and new name")
+	}
+	if name == newName {
+		return nil, &store.ErrNotValid{Err: errors.New("the new name of the entity must be different")}
+	}
+
+	key := GetEntitiesPath(ctx, name)
+	newKey := GetEntitiesPath(ctx, newName)
+	resp, err := s.client.Txn(ctx).Then(clientv3.OpGet(key), clientv3.OpGet(newKey)).Commit()
+	if err != nil {
+		return nil, err
+	}
+	kvs := resp.Responses[0].GetResponseRange().Kvs
+	if len(kvs) == 0 {
+		return nil, &store.ErrNotFound{Key: key}
+	}
+	entity := &corev2.Entity{}
+	if err := unmarshal(kvs[0].Value, entity); err != nil {
+		return nil, &store.ErrDecode{Key: key, Err: err}
+	}
+	// The transaction fails if either entity changes in the meantime
+	cmps := []clientv3.Cmp{
+		clientv3.Compare(clientv3.ModRevision(key), "=", kvs[0].ModRevision),
+		clientv3.Compare(clientv3.ModRevision(newKey), "=", 0),
+	}
+
+	var renamed *corev2.Entity
+	if newKvs := resp.Responses[1].GetResponseRange().Kvs; len(newKvs) > 0 {
+		if !merge {
+			return nil, &store.ErrAlreadyExists{Key: newKey}
+		}
+		renamed = &corev2.Entity{}
+		if err := unmarshal(newKvs[0].Value, renamed); err != nil {
+			return nil, &store.ErrDecode{Key: newKey, Err: err}
+		}
+		cmps[1] = clientv3.Compare(clientv3.ModRevision(newKey), "=", newKvs[0].ModRevision)
+		mergeEntity(renamed, entity)
+	} else {
+		renamed = renameEntity(entity, newName)
+	}
+	if err := renamed.Validate(); err != nil {
+		return nil, &store.ErrNotValid{Err: err}
+	}
+
+	ops, err := s.renameEntityOps(ctx, entity, renamed)
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) > maxTxnOps {
+		return nil, &store.ErrNotValid{Err: fmt.Errorf(
+			"the entity %s has too many events and silenced entries to be renamed atomically", name,
+		)}
+	}
+
+	res, err := s.client.Txn(ctx).If(cmps...).Then(ops...).Commit()
+	if err != nil {
+		return nil, err
+	}
+	if !res.Succeeded {
+		return nil, &store.ErrInternal{
+			Message: fmt.Sprintf("the entity %s or %s was modified during the rename, try again", name, newName),
+		}
+	}
+	return renamed, nil
+}
+
+// renameEntityOps returns the operations moving the entity, its events and the
+// silenced entries of its entity subscription to the renamed entity.
+func (s *Store) renameEntityOps(ctx context.Context, entity, renamed *corev2.Entity) ([]clientv3.Op, error) {
+	entityBytes, err := proto.Marshal(renamed)
+	if err != nil {
+		return nil, &store.ErrEncode{Key: getEntityPath(renamed), Err: err}
+	}
+	ops := []clientv3.Op{
+		clientv3.OpDelete(getEntityPath(entity)),
+		clientv3.OpDelete(managedFieldsKey(entityPathPrefix, entity.Namespace, entity.Name)),
+		clientv3.OpPut(getEntityPath(renamed), string(entityBytes)),
+		clientv3.OpDelete(getKeepalivePath(s.keepalivesPath, entity)),
+	}
+	ops = append(ops, entitySubscriptionOps(renamed)...)
+
+	eventOps, err := s.renameEventOps(ctx, entity, renamed)
+	if err != nil {
+		return nil, err
+	}
+	ops = append(ops, eventOps...)
+
+	silencedOps, err := s.renameSilencedOps(ctx, entity, renamed)
+	if err != nil {
+		return nil, err
+	}
+	return append(ops, silencedOps...), nil
+}
+
+// renameEventOps returns the operations moving the events of the entity to the
+// renamed entity, merged with the events of the same checks it already has.
+func (s *Store) renameEventOps(ctx context.Context, entity, renamed *corev2.Entity) ([]clientv3.Op, error) {
+	events, err := s.GetEventsByEntity(ctx, entity.Name, &store.SelectionPredicate{})
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	ops := []clientv3.Op{
+		clientv3.OpDelete(GetEventsPath(ctx, entity.Name), clientv3.WithPrefix()),
+		clientv3.OpDelete(getEventPipelinePath(entity.Namespace, entity.Name, "")+"/", clientv3.WithPrefix()),
+	}
+	for _, event := range events {
+		if !event.HasCheck() {
+			continue
+		}
+		existing, err := s.GetEventByEntityCheck(ctx, renamed.Name, event.Check.Name)
+		if err != nil {
+			return nil, err
+		}
+		event = mergeEvent(event, existing)
+		event.Entity = renamed
+		eventBytes, err := proto.Marshal(event)
+		if err != nil {
+			return nil, &store.ErrEncode{Key: getEventPath(event), Err: err}
+		}
+		ops = append(ops, clientv3.OpPut(getEventPath(event), string(eventBytes)))
+	}
+	return ops, nil
+}
+
+// renameSilencedOps returns the operations moving the silenced entries of the
+// entity subscription of the entity to the one of the renamed entity. The
+// entries keep their lease, so they expire as they would have.
+func (s *Store) renameSilencedOps(ctx context.Context, entity, renamed *corev2.Entity) ([]clientv3.Op, error) {
+	subscription := corev2.GetEntitySubscription(entity.Name)
+	entries, err := s.GetSilencedEntriesBySubscription(ctx, subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := []clientv3.Op{}
+	for _, entry := range entries {
+		key := GetSilencedPath(ctx, entry.Name)
+		resp, err := s.client.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Kvs) == 0 {
+			// The entry expired in the meantime
+			continue
+		}
+
+		entry.Subscription = corev2.GetEntitySubscription(renamed.Name)
+		entry.Name, _ = corev2.SilencedName(entry.Subscription, entry.Check)
+		entryBytes, err := proto.Marshal(entry)
+		if err != nil {
+			return nil, &store.ErrEncode{Key: key, Err: err}
+		}
+		var opts []clientv3.OpOption
+		if lease := clientv3.LeaseID(resp.Kvs[0].Lease); lease != clientv3.NoLease {
+			opts = append(opts, clientv3.WithLease(lease))
+		}
+		ops = append(ops,
+			clientv3.OpDelete(key),
+			clientv3.OpPut(GetSilencedPath(ctx, entry.Name), string(entryBytes), opts...),
+		)
+	}
+	return ops, nil
+}
+
+// renameEntity returns a copy of the entity with the new name, subscribed to
+// the entity subscription of the new name instead of the old one.
+func renameEntity(entity *corev2.Entity, newName string) *corev2.Entity {
+	renamed := *entity
+	renamed.ObjectMeta.Name = newName
+	renamed.Subscriptions = make([]string, 0, len(entity.Subscriptions))
+	old := corev2.GetEntitySubscription(entity.Name)
+	for _, subscription := range entity.Subscriptions {
+		if subscription == old {
+			subscription = corev2.GetEntitySubscription(newName)
+		}
+		renamed.Subscriptions = append(renamed.Subscriptions, subscription)
+	}
+	return &renamed
+}
+
+// mergeEntity completes the entity with the subscriptions, labels and
+// annotations of the merged entity it does not have, except its entity
+// subscription.
+func mergeEntity(entity, merged *corev2.Entity) {
+	old := corev2.GetEntitySubscription(merged.Name)
+	for _, subscription := range merged.Subscriptions {
+		if subscription != old && !subscribed(entity, subscription) {
+			entity.Subscriptions = append(entity.Subscriptions, subscription)
+		}
+	}
+	if entity.Labels == nil {
+		entity.Labels = make(map[string]string)
+	}
+	for k, v := range merged.Labels {
+		if _, ok := entity.Labels[k]; !ok {
+			entity.Labels[k] = v
+		}
+	}
+	if entity.Annotations == nil {
+		entity.Annotations = make(map[string]string)
+	}
+	for k, v := range merged.Annotations {
+		if _, ok := entity.Annotations[k]; !ok {
+			entity.Annotations[k] = v
+		}
+	}
+}
+
+// mergeEvent returns the most recent of the two events of the same check, with
+// the check history of both. The existing event may be nil.
+func mergeEvent(event, existing *corev2.Event) *corev2.Event {
+	if existing == nil || !existing.HasCheck() {
+		return event
+	}
+	latest, other := existing, event
+	if event.Timestamp > existing.Timestamp {
+		latest, other = event, existing
+	}
+
+	history := append([]corev2.CheckHistory{}, latest.Check.History...)
+	history = append(history, other.Check.History...)
+	sort.Sort(corev2.ByExecuted(history))
+	if len(history) > maxCheckHistory {
+		history = history[len(history)-maxCheckHistory:]
+	}
+	latest.Check.History = history
+	if other.Check.LastOK > latest.Check.LastOK {
+		latest.Check.LastOK = other.Check.LastOK
+	}
+	return latest
+}
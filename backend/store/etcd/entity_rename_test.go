@@ -0,0 +1,115 @@
+// +build integration,!race
+
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameEntity(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		entity := corev2.FixtureEntity("old")
+		entity.Subscriptions = []string{"linux", corev2.GetEntitySubscription("old")}
+		ctx := context.WithValue(context.Background(), corev2.NamespaceKey, entity.Namespace)
+		require.NoError(t, s.UpdateEntity(ctx, entity))
+
+		event := corev2.FixtureEvent("old", "disk")
+		event.Entity = entity
+		_, _, err := s.UpdateEvent(ctx, event)
+		require.NoError(t, err)
+
+		silenced := corev2.FixtureSilenced(corev2.GetEntitySubscription("old") + ":disk")
+		silenced.Namespace = entity.Namespace
+		silenced.Expire = 3600
+		require.NoError(t, s.UpdateSilencedEntry(ctx, silenced))
+
+		renamed, err := s.RenameEntity(ctx, "old", "new", false)
+		require.NoError(t, err)
+		assert.Equal(t, "new", renamed.Name)
+		assert.Equal(t, []string{"linux", corev2.GetEntitySubscription("new")}, renamed.Subscriptions)
+
+		got, err := s.GetEntityByName(ctx, "old")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		got, err = s.GetEntityByName(ctx, "new")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		events, err := s.GetEventsByEntity(ctx, "old", &store.SelectionPredicate{})
+		require.NoError(t, err)
+		assert.Empty(t, events)
+		newEvent, err := s.GetEventByEntityCheck(ctx, "new", "disk")
+		require.NoError(t, err)
+		require.NotNil(t, newEvent)
+		assert.Equal(t, "new", newEvent.Entity.Name)
+
+		entries, err := s.GetSilencedEntriesBySubscription(ctx, corev2.GetEntitySubscription("old"))
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+		entries, err = s.GetSilencedEntriesBySubscription(ctx, corev2.GetEntitySubscription("new"))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, corev2.GetEntitySubscription("new")+":disk", entries[0].Name)
+		assert.True(t, entries[0].Expire > 0)
+
+		entities, err := s.GetEntitiesBySubscription(ctx, corev2.GetEntitySubscription("new"))
+		require.NoError(t, err)
+		assert.Len(t, entities, 1)
+
+		_, err = s.RenameEntity(ctx, "old", "other", false)
+		assert.IsType(t, &store.ErrNotFound{}, err)
+	})
+}
+
+func TestMergeEntity(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		source := corev2.FixtureEntity("source")
+		source.Subscriptions = []string{"linux", corev2.GetEntitySubscription("source")}
+		source.Labels = map[string]string{"region": "us-west-1", "team": "ops"}
+		target := corev2.FixtureEntity("target")
+		target.Subscriptions = []string{"web", corev2.GetEntitySubscription("target")}
+		target.Labels = map[string]string{"region": "us-east-1"}
+		ctx := context.WithValue(context.Background(), corev2.NamespaceKey, source.Namespace)
+		require.NoError(t, s.UpdateEntity(ctx, source))
+		require.NoError(t, s.UpdateEntity(ctx, target))
+
+		old := corev2.FixtureEvent("source", "disk")
+		old.Entity = source
+		old.Timestamp -= 60
+		old.Check.Executed = old.Timestamp
+		old.Check.History = []corev2.CheckHistory{{Executed: old.Timestamp}}
+		_, _, err := s.UpdateEvent(ctx, old)
+		require.NoError(t, err)
+		latest := corev2.FixtureEvent("target", "disk")
+		latest.Entity = target
+		latest.Check.History = []corev2.CheckHistory{{Executed: latest.Timestamp}}
+		_, _, err = s.UpdateEvent(ctx, latest)
+		require.NoError(t, err)
+
+		_, err = s.RenameEntity(ctx, "source", "target", false)
+		assert.IsType(t, &store.ErrAlreadyExists{}, err)
+
+		merged, err := s.RenameEntity(ctx, "source", "target", true)
+		require.NoError(t, err)
+		assert.Equal(t, "target", merged.Name)
+		assert.ElementsMatch(t, []string{"web", corev2.GetEntitySubscription("target"), "linux"}, merged.Subscriptions)
+		assert.Equal(t, map[string]string{"region": "us-east-1", "team": "ops"}, merged.Labels)
+
+		got, err := s.GetEntityByName(ctx, "source")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		event, err := s.GetEventByEntityCheck(ctx, "target", "disk")
+		require.NoError(t, err)
+		require.NotNil(t, event)
+		assert.Equal(t, latest.Timestamp, event.Timestamp)
+		require.Len(t, event.Check.History, 2)
+		assert.Equal(t, old.Check.Executed, event.Check.History[0].Executed)
+	})
+}
@@ -28,31 +28,39 @@ func GetEntitiesPath(ctx context.Context, name string) string {
 	return entityKeyBuilder.WithContext(ctx).Build(name)
 }
 
-// DeleteEntity deletes an Entity.
+// DeleteEntity deletes an Entity, along with its field ownership.
 func (s *Store) DeleteEntity(ctx context.Context, e *corev2.Entity) error {
 	if err := e.Validate(); err != nil {
 		return err
 	}
-	_, err := s.client.Delete(ctx, getEntityPath(e))
+	_, err := s.client.Txn(ctx).Then(
+		clientv3.OpDelete(getEntityPath(e)),
+		clientv3.OpDelete(managedFieldsKey(entityPathPrefix, e.Namespace, e.Name)),
+	).Commit()
 	return err
 }
 
-// DeleteEntityByName deletes an Entity by its name.
+// DeleteEntityByName deletes an Entity by its name, along with its field
+// ownership.
 func (s *Store) DeleteEntityByName(ctx context.Context, name string) error {
 	if name == "" {
 		return errors.New("must specify name")
 	}
 
 	key := GetEntitiesPath(ctx, name)
-	resp, err := s.client.Delete(ctx, key)
+	resp, err := s.client.Txn(ctx).Then(
+		clientv3.OpDelete(key),
+		deleteManagedFields(ctx, entityPathPrefix, name),
+	).Commit()
 	if err != nil {
 		return err
 	}
-	if resp.Deleted == 0 {
+	deleted := resp.Responses[0].GetResponseDeleteRange().Deleted
+	if deleted == 0 {
 		return &store.ErrNotFound{Key: key}
-	} else if resp.Deleted > 1 {
+	} else if deleted > 1 {
 		return &store.ErrInternal{
-			Message: fmt.Sprintf("expected to delete exactly 1 key, deleted %d", resp.Deleted),
+			Message: fmt.Sprintf("expected to delete exactly 1 key, deleted %d", deleted),
 		}
 	}
 
@@ -104,9 +112,17 @@ func (s *Store) UpdateEntity(ctx context.Context, e *corev2.Entity) error {
 		return err
 	}
 
+	// Index the entity under its subscriptions in the same transaction, unless
+	// it has too many of them
+	ops := []clientv3.Op{clientv3.OpPut(getEntityPath(e), string(eStr))}
+	indexOps := entitySubscriptionOps(e)
+	if len(ops)+len(indexOps) <= maxTxnOps {
+		ops = append(ops, indexOps...)
+		indexOps = nil
+	}
+
 	cmp := clientv3.Compare(clientv3.Version(getNamespacePath(e.Namespace)), ">", 0)
-	req := clientv3.OpPut(getEntityPath(e), string(eStr))
-	res, err := s.client.Txn(ctx).If(cmp).Then(req).Commit()
+	res, err := s.client.Txn(ctx).If(cmp).Then(ops...).Commit()
 	if err != nil {
 		return err
 	}
@@ -118,5 +134,6 @@ func (s *Store) UpdateEntity(ctx context.Context, e *corev2.Entity) error {
 		)
 	}
 
-	return nil
+	_, err = s.commitOps(ctx, indexOps)
+	return err
 }
@@ -55,3 +55,46 @@ func TestEntityStorage(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestGetEntitiesBySubscription(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		linux := types.FixtureEntity("linux")
+		linux.Subscriptions = []string{"linux", "web/frontend"}
+		windows := types.FixtureEntity("windows")
+		windows.Subscriptions = []string{"windows"}
+		ctx := context.WithValue(context.Background(), types.NamespaceKey, linux.Namespace)
+
+		// Entities created before the index are indexed on the first query
+		require.NoError(t, s.(*Store).CreateOrUpdateResource(ctx, windows))
+		_, err := s.(*Store).client.Delete(ctx, getEntitySubscriptionPath(windows.Namespace, "windows", windows.Name))
+		require.NoError(t, err)
+		require.NoError(t, s.UpdateEntity(ctx, linux))
+
+		entities, err := s.GetEntitiesBySubscription(ctx, "windows")
+		require.NoError(t, err)
+		require.Len(t, entities, 1)
+		assert.Equal(t, "windows", entities[0].Name)
+
+		entities, err = s.GetEntitiesBySubscription(ctx, "web/frontend")
+		require.NoError(t, err)
+		require.Len(t, entities, 1)
+		assert.Equal(t, "linux", entities[0].Name)
+
+		// Removed subscriptions and deleted entities are not returned
+		linux.Subscriptions = []string{"web/frontend"}
+		require.NoError(t, s.UpdateEntity(ctx, linux))
+		entities, err = s.GetEntitiesBySubscription(ctx, "linux")
+		require.NoError(t, err)
+		assert.Empty(t, entities)
+
+		require.NoError(t, s.DeleteEntity(ctx, linux))
+		entities, err = s.GetEntitiesBySubscription(ctx, "web/frontend")
+		require.NoError(t, err)
+		assert.Empty(t, entities)
+
+		// All namespaces
+		entities, err = s.GetEntitiesBySubscription(context.Background(), "windows")
+		require.NoError(t, err)
+		assert.Len(t, entities, 1)
+	})
+}
@@ -0,0 +1,182 @@
+package etcd
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/coreos/etcd/clientv3"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// The entity subscription index maps the subscriptions of a namespace to the
+// entities subscribed to them, with keys like
+// /sensu.io/entity-subscriptions/{namespace}/{subscription}/{entity}. It is
+// updated with the entities, but never cleaned on their deletion or on the
+// removal of subscriptions: the stale keys are removed when they are read.
+const (
+	entitySubscriptionsPathPrefix        = "entity-subscriptions"
+	entitySubscriptionsIndexedPathPrefix = "entity-subscriptions-indexed"
+
+	// maxTxnOps is the maximum number of operations of an etcd transaction.
+	maxTxnOps = 128
+)
+
+var (
+	entitySubscriptionKeyBuilder         = store.NewKeyBuilder(entitySubscriptionsPathPrefix)
+	entitySubscriptionsIndexedKeyBuilder = store.NewKeyBuilder(entitySubscriptionsIndexedPathPrefix)
+)
+
+// getEntitySubscriptionPath returns the key indexing the entity under the
+// subscription. An empty entity name gives the prefix of all the entities
+// subscribed to the subscription.
+func getEntitySubscriptionPath(namespace, subscription, entity string) string {
+	return entitySubscriptionKeyBuilder.WithNamespace(namespace).Build(url.PathEscape(subscription), entity)
+}
+
+// entitySubscriptionOps returns the operations indexing the entity under its
+// subscriptions.
+func entitySubscriptionOps(entity *corev2.Entity) []clientv3.Op {
+	seen := make(map[string]bool, len(entity.Subscriptions))
+	ops := make([]clientv3.Op, 0, len(entity.Subscriptions))
+	for _, subscription := range entity.Subscriptions {
+		if seen[subscription] {
+			continue
+		}
+		seen[subscription] = true
+		ops = append(ops, clientv3.OpPut(getEntitySubscriptionPath(entity.Namespace, subscription, entity.Name), ""))
+	}
+	return ops
+}
+
+// commitOps commits the operations, in as many transactions as needed.
+func (s *Store) commitOps(ctx context.Context, ops []clientv3.Op) ([]*clientv3.TxnResponse, error) {
+	var responses []*clientv3.TxnResponse
+	for len(ops) > 0 {
+		n := len(ops)
+		if n > maxTxnOps {
+			n = maxTxnOps
+		}
+		resp, err := s.client.Txn(ctx).Then(ops[:n]...).Commit()
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, resp)
+		ops = ops[n:]
+	}
+	return responses, nil
+}
+
+// indexEntitySubscriptions indexes the entity under its subscriptions.
+func (s *Store) indexEntitySubscriptions(ctx context.Context, entity *corev2.Entity) error {
+	_, err := s.commitOps(ctx, entitySubscriptionOps(entity))
+	return err
+}
+
+// ensureEntitySubscriptionIndex builds the entity subscription index of the
+// namespace, if it wasn't built yet, e.g. for the entities created before the
+// index was introduced.
+func (s *Store) ensureEntitySubscriptionIndex(ctx context.Context, namespace string) error {
+	marker := entitySubscriptionsIndexedKeyBuilder.WithNamespace(namespace).Build("")
+	resp, err := s.client.Get(ctx, marker, clientv3.WithKeysOnly())
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) > 0 {
+		return nil
+	}
+
+	entities, err := s.GetEntities(ctx, &store.SelectionPredicate{})
+	if err != nil {
+		return err
+	}
+	ops := []clientv3.Op{}
+	for _, entity := range entities {
+		ops = append(ops, entitySubscriptionOps(entity)...)
+	}
+	ops = append(ops, clientv3.OpPut(marker, ""))
+	_, err = s.commitOps(ctx, ops)
+	return err
+}
+
+// GetEntitiesBySubscription returns the entities of the namespace stored in ctx
+// that are subscribed to the given subscription, using the entity subscription
+// index.
+func (s *Store) GetEntitiesBySubscription(ctx context.Context, subscription string) ([]*corev2.Entity, error) {
+	entities := []*corev2.Entity{}
+	namespace := store.NewNamespaceFromContext(ctx)
+	if namespace == "" || namespace == store.WildcardValue {
+		// The index is per namespace, look for the entities of every namespace
+		all, err := s.GetEntities(ctx, &store.SelectionPredicate{})
+		if err != nil {
+			return nil, err
+		}
+		for _, entity := range all {
+			if subscribed(entity, subscription) {
+				entities = append(entities, entity)
+			}
+		}
+		return entities, nil
+	}
+
+	if err := s.ensureEntitySubscriptionIndex(ctx, namespace); err != nil {
+		return nil, err
+	}
+
+	prefix := getEntitySubscriptionPath(namespace, subscription, "")
+	resp, err := s.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+	indexKeys := make([]string, 0, len(resp.Kvs))
+	gets := make([]clientv3.Op, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		name := string(kv.Key[len(prefix):])
+		indexKeys = append(indexKeys, string(kv.Key))
+		gets = append(gets, clientv3.OpGet(entityKeyBuilder.WithNamespace(namespace).Build(name)))
+	}
+
+	responses, err := s.commitOps(ctx, gets)
+	if err != nil {
+		return nil, err
+	}
+
+	stale := []clientv3.Op{}
+	i := 0
+	for _, txnResp := range responses {
+		for _, op := range txnResp.Responses {
+			indexKey := indexKeys[i]
+			i++
+			kvs := op.GetResponseRange().Kvs
+			if len(kvs) == 0 {
+				stale = append(stale, clientv3.OpDelete(indexKey))
+				continue
+			}
+			entity := &corev2.Entity{}
+			if err := unmarshal(kvs[0].Value, entity); err != nil {
+				return nil, err
+			}
+			if !subscribed(entity, subscription) {
+				stale = append(stale, clientv3.OpDelete(indexKey))
+				continue
+			}
+			entities = append(entities, entity)
+		}
+	}
+
+	if _, err := s.commitOps(ctx, stale); err != nil {
+		logger.WithError(err).Warning("could not remove stale entity subscription index keys")
+	}
+
+	return entities, nil
+}
+
+// subscribed returns whether the entity is subscribed to the subscription.
+func subscribed(entity *corev2.Entity, subscription string) bool {
+	for _, sub := range entity.Subscriptions {
+		if sub == subscription {
+			return true
+		}
+	}
+	return false
+}
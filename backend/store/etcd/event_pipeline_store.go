@@ -0,0 +1,57 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	eventPipelinesPathPrefix = "event-pipelines"
+)
+
+func getEventPipelinePath(namespace, entity, check string) string {
+	return path.Join(EtcdRoot, eventPipelinesPathPrefix, namespace, entity, check)
+}
+
+// GetEventPipeline gets the event pipeline record of an event by entity name
+// and check name.
+func (s *Store) GetEventPipeline(ctx context.Context, entityName, checkName string) (*corev2.EventPipeline, error) {
+	if entityName == "" || checkName == "" {
+		return nil, errors.New("must specify entity and check name")
+	}
+	namespace := corev2.ContextNamespace(ctx)
+	if namespace == "" {
+		return nil, errors.New("namespace missing from context")
+	}
+
+	pipeline := &corev2.EventPipeline{}
+	err := Get(ctx, s.client, getEventPipelinePath(namespace, entityName, checkName), pipeline)
+	if _, ok := err.(*store.ErrNotFound); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pipeline, nil
+}
+
+// UpdateEventPipeline creates or updates the event pipeline record of an
+// event.
+func (s *Store) UpdateEventPipeline(ctx context.Context, pipeline *corev2.EventPipeline) error {
+	if pipeline.Namespace == "" || pipeline.Entity == "" || pipeline.Check == "" {
+		return errors.New("must specify namespace, entity and check name")
+	}
+
+	key := getEventPipelinePath(pipeline.Namespace, pipeline.Entity, pipeline.Check)
+	b, err := json.Marshal(pipeline)
+	if err != nil {
+		return &store.ErrEncode{Key: key, Err: err}
+	}
+	_, err = s.client.Put(ctx, key, string(b))
+	return err
+}
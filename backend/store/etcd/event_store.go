@@ -51,7 +51,8 @@ func GetEventsPath(ctx context.Context, entity string) string {
 	return b.Build(entity)
 }
 
-// DeleteEventByEntityCheck deletes an event by entity name and check name.
+// DeleteEventByEntityCheck deletes an event, and its event pipeline record, by
+// entity name and check name.
 func (s *Store) DeleteEventByEntityCheck(ctx context.Context, entityName, checkName string) error {
 	if entityName == "" || checkName == "" {
 		return errors.New("must specify entity and check name")
@@ -62,7 +63,12 @@ func (s *Store) DeleteEventByEntityCheck(ctx context.Context, entityName, checkN
 		return err
 	}
 
-	_, err = s.client.Delete(ctx, path)
+	// The event pipeline record is deleted along with the event
+	pipelinePath := getEventPipelinePath(corev2.ContextNamespace(ctx), entityName, checkName)
+	_, err = s.client.Txn(ctx).Then(
+		clientv3.OpDelete(path),
+		clientv3.OpDelete(pipelinePath),
+	).Commit()
 	return err
 }
 
@@ -212,7 +218,7 @@ func (s *Store) UpdateEvent(ctx context.Context, event *corev2.Event) (*corev2.E
 		return nil, nil, errors.New("event has no check")
 	}
 
-	if err := event.Check.Validate(); err != nil {
+	if err := event.ValidateCheck(); err != nil {
 		return nil, nil, err
 	}
 
@@ -627,3 +627,37 @@ func testGetEventsByEntityPagination(t *testing.T, ctx context.Context, etcd sto
 		}
 	}
 }
+
+func TestEventPipelineStorage(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		event := corev2.FixtureEvent("entity1", "check1")
+		ctx := context.WithValue(context.Background(), corev2.NamespaceKey, event.Entity.Namespace)
+
+		pipeline, err := s.GetEventPipeline(ctx, "entity1", "check1")
+		require.NoError(t, err)
+		assert.Nil(t, pipeline)
+
+		want := &corev2.EventPipeline{
+			Namespace: event.Entity.Namespace,
+			Entity:    "entity1",
+			Check:     "check1",
+			Timestamp: event.Timestamp,
+			Handlers: []*corev2.HandlerExecution{
+				{Handler: "slack", Type: "pipe", Status: 2, Output: "error"},
+			},
+		}
+		require.NoError(t, s.UpdateEventPipeline(ctx, want))
+
+		pipeline, err = s.GetEventPipeline(ctx, "entity1", "check1")
+		require.NoError(t, err)
+		assert.Equal(t, want, pipeline)
+
+		// The record is deleted along with the event
+		_, _, err = s.UpdateEvent(ctx, event)
+		require.NoError(t, err)
+		require.NoError(t, s.DeleteEventByEntityCheck(ctx, "entity1", "check1"))
+		pipeline, err = s.GetEventPipeline(ctx, "entity1", "check1")
+		require.NoError(t, err)
+		assert.Nil(t, pipeline)
+	})
+}
@@ -0,0 +1,96 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/coreos/etcd/clientv3"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	handlerOutputsPathPrefix = "handler-outputs"
+
+	// addHandlerOutputAttempts is the number of times a handler output is
+	// added again when the outputs of the handler were modified meanwhile,
+	// e.g. by another pipelined worker.
+	addHandlerOutputAttempts = 5
+)
+
+func getHandlerOutputsPath(namespace, handler string) string {
+	return path.Join(EtcdRoot, handlerOutputsPathPrefix, namespace, handler)
+}
+
+// GetHandlerOutputs gets the captured outputs of the last executions of a
+// handler by name, most recent first.
+func (s *Store) GetHandlerOutputs(ctx context.Context, name string) ([]*corev2.HandlerOutput, error) {
+	if name == "" {
+		return nil, errors.New("must specify name of handler")
+	}
+	namespace := corev2.ContextNamespace(ctx)
+	if namespace == "" {
+		return nil, errors.New("namespace missing from context")
+	}
+
+	outputs, _, err := s.getHandlerOutputs(ctx, getHandlerOutputsPath(namespace, name))
+	return outputs, err
+}
+
+// AddHandlerOutput adds the output of an execution of a handler by name to
+// its captured outputs, and only keeps the history most recent ones.
+func (s *Store) AddHandlerOutput(ctx context.Context, name string, output *corev2.HandlerOutput, history int) error {
+	if name == "" || output.Namespace == "" {
+		return errors.New("must specify namespace and name of handler")
+	}
+	if history <= 0 {
+		return errors.New("the number of handler outputs kept must be positive")
+	}
+
+	key := getHandlerOutputsPath(output.Namespace, name)
+	for attempt := 0; attempt < addHandlerOutputAttempts; attempt++ {
+		outputs, revision, err := s.getHandlerOutputs(ctx, key)
+		if err != nil {
+			return err
+		}
+		outputs = append([]*corev2.HandlerOutput{output}, outputs...)
+		if len(outputs) > history {
+			outputs = outputs[:history]
+		}
+		b, err := json.Marshal(outputs)
+		if err != nil {
+			return &store.ErrEncode{Key: key, Err: err}
+		}
+
+		cmp := clientv3.Compare(clientv3.ModRevision(key), "=", revision)
+		res, err := s.client.Txn(ctx).If(cmp).Then(clientv3.OpPut(key, string(b))).Commit()
+		if err != nil {
+			return err
+		}
+		if res.Succeeded {
+			return nil
+		}
+	}
+
+	return &store.ErrInternal{Message: "the handler outputs were modified concurrently, the output was dropped"}
+}
+
+// getHandlerOutputs returns the handler outputs stored at the key, and the
+// revision at which they were last modified, 0 if none were found.
+func (s *Store) getHandlerOutputs(ctx context.Context, key string) ([]*corev2.HandlerOutput, int64, error) {
+	resp, err := s.client.Get(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, 0, nil
+	}
+
+	var outputs []*corev2.HandlerOutput
+	if err := json.Unmarshal(resp.Kvs[0].Value, &outputs); err != nil {
+		return nil, 0, &store.ErrDecode{Key: key, Err: err}
+	}
+	return outputs, resp.Kvs[0].ModRevision, nil
+}
@@ -0,0 +1,47 @@
+// +build integration,!race
+
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerOutputStorage(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		handler := corev2.FixtureHandler("handler1")
+		ctx := context.WithValue(context.Background(), corev2.NamespaceKey, handler.Namespace)
+		require.NoError(t, s.UpdateHandler(ctx, handler))
+
+		outputs, err := s.GetHandlerOutputs(ctx, "handler1")
+		require.NoError(t, err)
+		assert.Empty(t, outputs)
+
+		// Only the most recent outputs are kept
+		for _, output := range []string{"first", "second", "third"} {
+			captured := &corev2.HandlerOutput{
+				Namespace:        handler.Namespace,
+				Entity:           "entity1",
+				HandlerExecution: corev2.HandlerExecution{Handler: "handler1", Output: output},
+			}
+			require.NoError(t, s.AddHandlerOutput(ctx, "handler1", captured, 2))
+		}
+		outputs, err = s.GetHandlerOutputs(ctx, "handler1")
+		require.NoError(t, err)
+		require.Len(t, outputs, 2)
+		assert.Equal(t, "third", outputs[0].Output)
+		assert.Equal(t, "second", outputs[1].Output)
+		assert.Equal(t, "entity1", outputs[0].Entity)
+
+		// The outputs are deleted with the handler
+		require.NoError(t, s.DeleteHandlerByName(ctx, "handler1"))
+		outputs, err = s.GetHandlerOutputs(ctx, "handler1")
+		require.NoError(t, err)
+		assert.Empty(t, outputs)
+	})
+}
@@ -25,13 +25,16 @@ func GetHandlersPath(ctx context.Context, name string) string {
 	return handlerKeyBuilder.WithContext(ctx).Build(name)
 }
 
-// DeleteHandlerByName deletes a Handler by name.
+// DeleteHandlerByName deletes a Handler by name, and its captured outputs.
 func (s *Store) DeleteHandlerByName(ctx context.Context, name string) error {
 	if name == "" {
 		return errors.New("must specify name of handler")
 	}
 
-	_, err := s.client.Delete(ctx, GetHandlersPath(ctx, name))
+	_, err := s.client.Txn(ctx).Then(
+		clientv3.OpDelete(GetHandlersPath(ctx, name)),
+		clientv3.OpDelete(getHandlerOutputsPath(store.NewNamespaceFromContext(ctx), name)),
+	).Commit()
 	return err
 }
 
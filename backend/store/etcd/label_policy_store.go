@@ -0,0 +1,51 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	labelPolicyPathPrefix = "label-policy"
+)
+
+func getLabelPolicyPath() string {
+	return path.Join(EtcdRoot, labelPolicyPathPrefix)
+}
+
+// GetLabelPolicy gets the label policy of the cluster, or nil if there is
+// none.
+func (s *Store) GetLabelPolicy(ctx context.Context) (*corev2.LabelPolicy, error) {
+	policy := &corev2.LabelPolicy{}
+	err := Get(ctx, s.client, getLabelPolicyPath(), policy)
+	if _, ok := err.(*store.ErrNotFound); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Compile the patterns of the policy
+	if err := policy.Validate(); err != nil {
+		return nil, &store.ErrNotValid{Err: err}
+	}
+	return policy, nil
+}
+
+// UpdateLabelPolicy creates or updates the label policy of the cluster.
+func (s *Store) UpdateLabelPolicy(ctx context.Context, policy *corev2.LabelPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return &store.ErrNotValid{Err: err}
+	}
+
+	key := getLabelPolicyPath()
+	b, err := json.Marshal(policy)
+	if err != nil {
+		return &store.ErrEncode{Key: key, Err: err}
+	}
+	_, err = s.client.Put(ctx, key, string(b))
+	return err
+}
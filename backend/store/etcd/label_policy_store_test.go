@@ -0,0 +1,42 @@
+// +build integration,!race
+
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelPolicyStorage(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		ctx := context.Background()
+
+		// We should receive no policy if none was configured
+		policy, err := s.GetLabelPolicy(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, policy)
+
+		// Invalid policies are rejected
+		invalid := &corev2.LabelPolicy{
+			Rules: []corev2.LabelPolicyRule{{Resources: []string{"checks"}}},
+		}
+		err = s.UpdateLabelPolicy(ctx, invalid)
+		assert.IsType(t, &store.ErrNotValid{}, err)
+
+		expected := &corev2.LabelPolicy{
+			Rules: []corev2.LabelPolicyRule{
+				{Resources: []string{"checks"}, Labels: map[string]string{"team": "^[a-z]+$"}},
+			},
+		}
+		require.NoError(t, s.UpdateLabelPolicy(ctx, expected))
+
+		policy, err = s.GetLabelPolicy(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expected, policy)
+	})
+}
@@ -0,0 +1,148 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/coreos/etcd/clientv3"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	managedFieldsPathPrefix = "managed-fields"
+)
+
+func getManagedFieldsPath(resource corev2.Resource) string {
+	meta := resource.GetObjectMeta()
+	return managedFieldsKey(resource.StorePrefix(), meta.Namespace, meta.Name)
+}
+
+func managedFieldsKey(resourcePrefix, namespace, name string) string {
+	return path.Join(EtcdRoot, managedFieldsPathPrefix, resourcePrefix, namespace, name)
+}
+
+// deleteManagedFields returns the operation deleting the field ownership of
+// the resource of the given name, within the namespace of the context, so that
+// a resource created again with the same name doesn't inherit it.
+func deleteManagedFields(ctx context.Context, resourcePrefix, name string) clientv3.Op {
+	return clientv3.OpDelete(managedFieldsKey(resourcePrefix, store.NewNamespaceFromContext(ctx), name))
+}
+
+// GetManagedFields gets the fields of a resource owned by each field manager,
+// or nil if no field is owned.
+func (s *Store) GetManagedFields(ctx context.Context, resource corev2.Resource) (*corev2.ManagedFields, error) {
+	if resource.GetObjectMeta().Name == "" {
+		return nil, errors.New("must specify resource name")
+	}
+
+	fields := &corev2.ManagedFields{}
+	err := Get(ctx, s.client, getManagedFieldsPath(resource), fields)
+	if _, ok := err.(*store.ErrNotFound); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// UpdateManagedFields creates or updates the fields of a resource owned by
+// each field manager.
+func (s *Store) UpdateManagedFields(ctx context.Context, resource corev2.Resource, fields *corev2.ManagedFields) error {
+	if resource.GetObjectMeta().Name == "" {
+		return errors.New("must specify resource name")
+	}
+
+	key := getManagedFieldsPath(resource)
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return &store.ErrEncode{Key: key, Err: err}
+	}
+	_, err = s.client.Put(ctx, key, string(b))
+	return err
+}
+
+// GetManagedResource gets the resource of the given name, within the namespace
+// of the context, and its field ownership, or nil if no field is owned, at
+// once. It also returns the revision of the resource, to apply changes to it
+// with ApplyManagedResource.
+func (s *Store) GetManagedResource(ctx context.Context, name string, resource corev2.Resource) (*corev2.ManagedFields, int64, error) {
+	if name == "" {
+		return nil, 0, errors.New("must specify resource name")
+	}
+
+	key := store.KeyFromArgs(ctx, resource.StorePrefix(), name)
+	fieldsKey := managedFieldsKey(resource.StorePrefix(), store.NewNamespaceFromContext(ctx), name)
+	resp, err := s.client.Txn(ctx).Then(clientv3.OpGet(key), clientv3.OpGet(fieldsKey)).Commit()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	kvs := resp.Responses[0].GetResponseRange().Kvs
+	if len(kvs) == 0 {
+		return nil, 0, &store.ErrNotFound{Key: key}
+	}
+	if err := unmarshal(kvs[0].Value, resource); err != nil {
+		return nil, 0, &store.ErrDecode{Key: key, Err: err}
+	}
+	revision := kvs[0].ModRevision
+
+	var fields *corev2.ManagedFields
+	if kvs := resp.Responses[1].GetResponseRange().Kvs; len(kvs) > 0 {
+		fields = &corev2.ManagedFields{}
+		if err := unmarshal(kvs[0].Value, fields); err != nil {
+			return nil, 0, &store.ErrDecode{Key: fieldsKey, Err: err}
+		}
+	}
+	return fields, revision, nil
+}
+
+// ApplyManagedResource creates or updates a resource and its field ownership
+// in a single transaction, only if the resource is still at the given
+// revision, or 0 if it did not exist. Otherwise, another update happened
+// since the resource was read, and ErrModified is returned.
+func (s *Store) ApplyManagedResource(ctx context.Context, resource corev2.Resource, fields *corev2.ManagedFields, revision int64) error {
+	if err := resource.Validate(); err != nil {
+		return &store.ErrNotValid{Err: err}
+	}
+
+	key := store.KeyFromResource(resource)
+	namespace := resource.GetObjectMeta().Namespace
+	bytes, err := encode(key, resource)
+	if err != nil {
+		return err
+	}
+	fieldsKey := getManagedFieldsPath(resource)
+	fieldsBytes, err := json.Marshal(fields)
+	if err != nil {
+		return &store.ErrEncode{Key: fieldsKey, Err: err}
+	}
+
+	comparisons := []clientv3.Cmp{clientv3.Compare(clientv3.ModRevision(key), "=", revision)}
+	// If we had a namespace provided, make sure it exists
+	if namespace != "" {
+		comparisons = append(comparisons, namespaceFound(namespace))
+	}
+	resp, err := s.client.Txn(ctx).If(comparisons...).Then(
+		clientv3.OpPut(key, string(bytes)),
+		clientv3.OpPut(fieldsKey, string(fieldsBytes)),
+	).Else(getNamespace(namespace)).Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		// Check if the namespace was missing
+		if namespace != "" && len(resp.Responses[0].GetResponseRange().Kvs) == 0 {
+			return &store.ErrNamespaceMissing{Namespace: namespace}
+		}
+		return &store.ErrModified{Key: key}
+	}
+
+	if entity, ok := resource.(*corev2.Entity); ok {
+		return s.indexEntitySubscriptions(ctx, entity)
+	}
+	return nil
+}
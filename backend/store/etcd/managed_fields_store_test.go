@@ -0,0 +1,73 @@
+// +build integration,!race
+
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagedFieldsStorage(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		ctx := context.Background()
+		check := corev2.FixtureCheckConfig("disk")
+
+		// We should receive no managed fields if none were recorded
+		fields, err := s.GetManagedFields(ctx, check)
+		require.NoError(t, err)
+		assert.Nil(t, fields)
+
+		expected := &corev2.ManagedFields{
+			Managers: map[string][]string{"sensuctl": {"/command", "/interval"}},
+		}
+		require.NoError(t, s.UpdateManagedFields(ctx, check, expected))
+
+		fields, err = s.GetManagedFields(ctx, check)
+		require.NoError(t, err)
+		assert.Equal(t, expected, fields)
+
+		// The managed fields of other resources are distinct
+		fields, err = s.GetManagedFields(ctx, corev2.FixtureCheckConfig("cpu"))
+		require.NoError(t, err)
+		assert.Nil(t, fields)
+	})
+}
+
+func TestApplyManagedResource(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		ctx := store.NamespaceContext(context.Background(), "default")
+		check := corev2.FixtureCheckConfig("disk")
+		fields := &corev2.ManagedFields{
+			Managers: map[string][]string{"gitops": {"/command"}},
+		}
+
+		// The resource is created if it did not exist
+		require.NoError(t, s.ApplyManagedResource(ctx, check, fields, 0))
+		got, revision, err := s.GetManagedResource(ctx, "disk", &corev2.CheckConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, fields, got)
+		assert.NotZero(t, revision)
+
+		// The resource is updated at the revision it was read
+		require.NoError(t, s.ApplyManagedResource(ctx, check, fields, revision))
+
+		// The resource was modified since it was read at the first revision
+		err = s.ApplyManagedResource(ctx, check, fields, revision)
+		assert.IsType(t, &store.ErrModified{}, err)
+		err = s.ApplyManagedResource(ctx, check, fields, 0)
+		assert.IsType(t, &store.ErrModified{}, err)
+
+		// The field ownership is deleted along with the resource
+		require.NoError(t, s.DeleteResource(ctx, check.StorePrefix(), "disk"))
+		got, err = s.GetManagedFields(ctx, check)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		_, _, err = s.GetManagedResource(ctx, "disk", &corev2.CheckConfig{})
+		assert.IsType(t, &store.ErrNotFound{}, err)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/coreos/etcd/clientv3"
 	"github.com/gogo/protobuf/proto"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/store"
@@ -23,7 +24,14 @@ func (s *Store) CreateResource(ctx context.Context, resource corev2.Resource) er
 		return &store.ErrEncode{Key: key, Err: fmt.Errorf("%T is not proto.Message", resource)}
 	}
 
-	return Create(ctx, s.client, key, namespace, msg)
+	if err := Create(ctx, s.client, key, namespace, msg); err != nil {
+		return err
+	}
+
+	if entity, ok := resource.(*corev2.Entity); ok {
+		return s.indexEntitySubscriptions(ctx, entity)
+	}
+	return nil
 }
 
 // CreateOrUpdateResource creates or updates the given resource regardless of
@@ -35,13 +43,36 @@ func (s *Store) CreateOrUpdateResource(ctx context.Context, resource corev2.Reso
 
 	key := store.KeyFromResource(resource)
 	namespace := resource.GetObjectMeta().Namespace
-	return CreateOrUpdate(ctx, s.client, key, namespace, resource)
+	if err := CreateOrUpdate(ctx, s.client, key, namespace, resource); err != nil {
+		return err
+	}
+
+	if entity, ok := resource.(*corev2.Entity); ok {
+		return s.indexEntitySubscriptions(ctx, entity)
+	}
+	return nil
 }
 
-// DeleteResource deletes the resource using the given resource prefix and name
+// DeleteResource deletes the resource using the given resource prefix and
+// name, along with its field ownership
 func (s *Store) DeleteResource(ctx context.Context, resourcePrefix, name string) error {
 	key := store.KeyFromArgs(ctx, resourcePrefix, name)
-	return Delete(ctx, s.client, key)
+	resp, err := s.client.Txn(ctx).Then(
+		clientv3.OpDelete(key),
+		deleteManagedFields(ctx, resourcePrefix, name),
+	).Commit()
+	if err != nil {
+		return err
+	}
+	deleted := resp.Responses[0].GetResponseDeleteRange().Deleted
+	if deleted == 0 {
+		return &store.ErrNotFound{Key: key}
+	} else if deleted > 1 {
+		return &store.ErrInternal{
+			Message: fmt.Sprintf("expected to delete exactly 1 key, deleted %d", deleted),
+		}
+	}
+	return nil
 }
 
 // GetResource retrieves a resource with the given name and stores it into the
@@ -0,0 +1,68 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	routesPathPrefix = "routes"
+)
+
+// getRoutesPath gets the path of the route store. The routes are not
+// namespaced.
+func getRoutesPath(ctx context.Context, name string) string {
+	return path.Join(store.Root, routesPathPrefix, name)
+}
+
+// DeleteRouteByName deletes a route by its name.
+func (s *Store) DeleteRouteByName(ctx context.Context, name string) error {
+	if name == "" {
+		return errors.New("must specify name")
+	}
+	return Delete(ctx, s.client, getRoutesPath(ctx, name))
+}
+
+// GetRouteByName gets a route by its name, or nil if there is none.
+func (s *Store) GetRouteByName(ctx context.Context, name string) (*corev2.Route, error) {
+	if name == "" {
+		return nil, errors.New("must specify name")
+	}
+
+	route := &corev2.Route{}
+	err := Get(ctx, s.client, getRoutesPath(ctx, name), route)
+	if _, ok := err.(*store.ErrNotFound); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+// GetRoutes gets all the routes.
+func (s *Store) GetRoutes(ctx context.Context) ([]*corev2.Route, error) {
+	routes := []*corev2.Route{}
+	err := List(ctx, s.client, getRoutesPath, &routes, &store.SelectionPredicate{})
+	return routes, err
+}
+
+// UpdateRoute creates or updates a route.
+func (s *Store) UpdateRoute(ctx context.Context, route *corev2.Route) error {
+	if err := route.Validate(); err != nil {
+		return &store.ErrNotValid{Err: err}
+	}
+
+	key := getRoutesPath(ctx, route.Name)
+	b, err := json.Marshal(route)
+	if err != nil {
+		return &store.ErrEncode{Key: key, Err: err}
+	}
+	_, err = s.client.Put(ctx, key, string(b))
+	return err
+}
@@ -0,0 +1,59 @@
+// +build integration,!race
+
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteStorage(t *testing.T) {
+	testWithEtcd(t, func(s store.Store) {
+		ctx := context.Background()
+
+		// We should receive an empty slice if no results were found
+		routes, err := s.GetRoutes(ctx)
+		require.NoError(t, err)
+		assert.NotNil(t, routes)
+		assert.Empty(t, routes)
+
+		route, err := s.GetRouteByName(ctx, "db")
+		require.NoError(t, err)
+		assert.Nil(t, route)
+
+		// Invalid routes are rejected
+		err = s.UpdateRoute(ctx, &corev2.Route{Name: "db"})
+		assert.IsType(t, &store.ErrNotValid{}, err)
+
+		expected := &corev2.Route{
+			Name:       "db",
+			Labels:     map[string]string{"team": "db"},
+			Severities: []string{"critical"},
+			Handlers:   []string{"opsgenie"},
+		}
+		require.NoError(t, s.UpdateRoute(ctx, expected))
+
+		route, err = s.GetRouteByName(ctx, "db")
+		require.NoError(t, err)
+		assert.Equal(t, expected, route)
+
+		// The routes are not namespaced
+		acme := context.WithValue(ctx, corev2.NamespaceKey, "acme")
+		routes, err = s.GetRoutes(acme)
+		require.NoError(t, err)
+		assert.Equal(t, []*corev2.Route{expected}, routes)
+
+		require.NoError(t, s.DeleteRouteByName(ctx, "db"))
+		route, err = s.GetRouteByName(ctx, "db")
+		require.NoError(t, err)
+		assert.Nil(t, route)
+
+		err = s.DeleteRouteByName(ctx, "db")
+		assert.IsType(t, &store.ErrNotFound{}, err)
+	})
+}
@@ -82,28 +82,9 @@ func Create(ctx context.Context, client *clientv3.Client, key, namespace string,
 // CreateOrUpdate writes the given key with the serialized object, regarless of
 // its current existence
 func CreateOrUpdate(ctx context.Context, client *clientv3.Client, key, namespace string, object interface{}) error {
-	var bytes []byte
-	var err error
-
-	switch object.(type) {
-	case types.Wrapper:
-		// Supporting protobuf serialization for wrapped resources is not
-		// straightforward since the types.Wrapper struct holds an interface. We
-		// will just use JSON encoding for now since the all store functions support
-		// both for decoding.
-		bytes, err = json.Marshal(object)
-		if err != nil {
-			return &store.ErrEncode{Key: key, Err: err}
-		}
-	default:
-		msg, ok := object.(proto.Message)
-		if !ok {
-			return &store.ErrEncode{Key: key, Err: fmt.Errorf("%T is not proto.Message", object)}
-		}
-		bytes, err = proto.Marshal(msg)
-		if err != nil {
-			return &store.ErrEncode{Key: key, Err: err}
-		}
+	bytes, err := encode(key, object)
+	if err != nil {
+		return err
 	}
 
 	comparisons := []clientv3.Cmp{}
@@ -132,6 +113,32 @@ func CreateOrUpdate(ctx context.Context, client *clientv3.Client, key, namespace
 	return nil
 }
 
+// encode serializes the object stored under the given key.
+func encode(key string, object interface{}) ([]byte, error) {
+	switch object.(type) {
+	case types.Wrapper:
+		// Supporting protobuf serialization for wrapped resources is not
+		// straightforward since the types.Wrapper struct holds an interface. We
+		// will just use JSON encoding for now since the all store functions support
+		// both for decoding.
+		bytes, err := json.Marshal(object)
+		if err != nil {
+			return nil, &store.ErrEncode{Key: key, Err: err}
+		}
+		return bytes, nil
+	default:
+		msg, ok := object.(proto.Message)
+		if !ok {
+			return nil, &store.ErrEncode{Key: key, Err: fmt.Errorf("%T is not proto.Message", object)}
+		}
+		bytes, err := proto.Marshal(msg)
+		if err != nil {
+			return nil, &store.ErrEncode{Key: key, Err: err}
+		}
+		return bytes, nil
+	}
+}
+
 // Delete the given key
 func Delete(ctx context.Context, client *clientv3.Client, key string) error {
 	resp, err := client.Delete(ctx, key)
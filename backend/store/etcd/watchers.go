@@ -109,6 +109,7 @@ func GetResourceWatcher(ctx context.Context, client *clientv3.Client, key string
 			ch <- store.WatchEventResource{
 				Action:   response.Type,
 				Resource: resource,
+				Revision: response.Revision,
 			}
 		}
 	}()
@@ -52,6 +52,14 @@ func (e *EventStoreProxy) UpdateEvent(ctx context.Context, event *types.Event) (
 	return e.do().UpdateEvent(ctx, event)
 }
 
+func (e *EventStoreProxy) GetEventPipeline(ctx context.Context, entity, check string) (*corev2.EventPipeline, error) {
+	return e.do().GetEventPipeline(ctx, entity, check)
+}
+
+func (e *EventStoreProxy) UpdateEventPipeline(ctx context.Context, pipeline *corev2.EventPipeline) error {
+	return e.do().UpdateEventPipeline(ctx, pipeline)
+}
+
 type closer interface {
 	Close() error
 }
@@ -33,6 +33,14 @@ func (mockEventStore) UpdateEvent(ctx context.Context, event *types.Event) (old,
 	return nil, nil, nil
 }
 
+func (mockEventStore) GetEventPipeline(ctx context.Context, entity, check string) (*corev2.EventPipeline, error) {
+	return nil, nil
+}
+
+func (mockEventStore) UpdateEventPipeline(ctx context.Context, pipeline *corev2.EventPipeline) error {
+	return nil
+}
+
 func TestEventStoreProxy(t *testing.T) {
 	storeA := mockEventStore{"a"}
 	storeB := mockEventStore{"b"}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/coreos/etcd/clientv3"
 	jwt "github.com/dgrijalva/jwt-go"
@@ -69,6 +70,16 @@ func (e *ErrNotValid) Error() string {
 	return fmt.Sprintf("resource is invalid: %s", e.Err.Error())
 }
 
+// ErrModified is returned when an object was modified since it was read, and
+// the update based on the object read was not applied
+type ErrModified struct {
+	Key string
+}
+
+func (e *ErrModified) Error() string {
+	return fmt.Sprintf("the key %s was modified since it was read", e.Key)
+}
+
 // ErrInternal is returned when something generally bad happened while
 // interacting with the store. Other, more specific errors should preferably be
 // returned when appropriate.
@@ -112,16 +123,31 @@ type WatchEventTessenConfig struct {
 	Action       WatchActionType
 }
 
+// WatchEventAgentConfig is a notification that the configuration of the agent
+// of an entity has been updated. The configuration is empty if it was
+// deleted.
+type WatchEventAgentConfig struct {
+	Namespace   string
+	Entity      string
+	AgentConfig *corev2.AgentConfig
+	Action      WatchActionType
+}
+
 // WatchEventResource is a store event about a specific resource
 type WatchEventResource struct {
 	Resource corev2.Resource
 	Action   WatchActionType
+	Revision int64
 }
 
 // Store is used to abstract the durable storage used by the Sensu backend
 // processses. Each Sensu resources is represented by its own interface. A
 // MockStore is available in order to mock a store implementation
 type Store interface {
+	// AgentConfigStore provides an interface for managing the configuration
+	// of the agents
+	AgentConfigStore
+
 	// AssetStore provides an interface for managing checks assets
 	AssetStore
 
@@ -131,6 +157,10 @@ type Store interface {
 	// CheckConfigStore provides an interface for managing checks configuration
 	CheckConfigStore
 
+	// CheckExecutionStore provides an interface for limiting the number of
+	// agents executing a check at the same time
+	CheckExecutionStore
+
 	// ClusterIDStore provides an interface for managing the sensu cluster id
 	ClusterIDStore
 
@@ -155,6 +185,13 @@ type Store interface {
 	// KeepaliveStore provides an interface for managing entities keepalives
 	KeepaliveStore
 
+	// LabelPolicyStore provides an interface for managing the label policy
+	LabelPolicyStore
+
+	// ManagedFieldsStore provides an interface for managing the field
+	// ownership of resources
+	ManagedFieldsStore
+
 	// MutatorStore provides an interface for managing events mutators
 	MutatorStore
 
@@ -173,6 +210,9 @@ type Store interface {
 	// RoleBindingStore provides an interface for managing role bindings
 	RoleBindingStore
 
+	// RouteStore provides an interface for managing the routes
+	RouteStore
+
 	// SilencedStore provides an interface for managing silenced entries,
 	// consisting of entities, subscriptions and/or checks
 	SilencedStore
@@ -197,6 +237,26 @@ type Store interface {
 	NewInitializer() (Initializer, error)
 }
 
+// AgentConfigStore provides methods for managing the configuration managed by
+// the backend for the agents
+type AgentConfigStore interface {
+	// DeleteAgentConfig deletes the configuration of the agent of the given
+	// entity, using the namespace stored in ctx.
+	DeleteAgentConfig(ctx context.Context, entity string) error
+
+	// GetAgentConfig gets the configuration of the agent of the given entity,
+	// using the namespace stored in ctx, or nil if there is none.
+	GetAgentConfig(ctx context.Context, entity string) (*corev2.AgentConfig, error)
+
+	// GetAgentConfigWatcher returns a watcher of the configurations of the
+	// agents of all namespaces
+	GetAgentConfigWatcher(ctx context.Context) <-chan WatchEventAgentConfig
+
+	// UpdateAgentConfig creates or updates the configuration of the agent of
+	// the given entity, using the namespace stored in ctx.
+	UpdateAgentConfig(ctx context.Context, entity string, config *corev2.AgentConfig) error
+}
+
 // AssetStore provides methods for managing checks assets
 type AssetStore interface {
 	// DeleteAssetByName deletes an asset using the given name and the
@@ -254,6 +314,27 @@ type CheckConfigStore interface {
 	GetCheckConfigWatcher(ctx context.Context) <-chan WatchEventCheckConfig
 }
 
+// CheckExecutionStore provides methods for limiting the number of agents
+// executing a check at the same time, with a semaphore shared by all the
+// backends
+type CheckExecutionStore interface {
+	// GrantCheckExecutionLease grants a lease of the given ttl, to which the
+	// execution slots of an agent session are attached, and keeps it alive
+	// until ctx is done. The lease is then revoked, releasing its slots, so
+	// ctx must live as long as the session.
+	GrantCheckExecutionLease(ctx context.Context, ttl time.Duration) (int64, error)
+
+	// AcquireCheckExecution acquires one of the max execution slots of the
+	// given check for the given agent, attached to the given lease, using the
+	// namespace stored in ctx. It returns false if the slots are all held by
+	// other agents. An agent already holding a slot keeps it.
+	AcquireCheckExecution(ctx context.Context, check, agent string, max int, lease int64) (bool, error)
+
+	// ReleaseCheckExecution releases the execution slot of the given check
+	// held by the given agent, if any, using the namespace stored in ctx.
+	ReleaseCheckExecution(ctx context.Context, check, agent string) error
+}
+
 // ClusterIDStore provides methods for managing the sensu cluster id
 type ClusterIDStore interface {
 	// CreateClusterID creates a sensu cluster id
@@ -342,10 +423,28 @@ type EntityStore interface {
 	// with no error is returned if none were found.
 	GetEntities(ctx context.Context, pred *SelectionPredicate) ([]*types.Entity, error)
 
+	// GetEntitiesBySubscription returns the entities in the given ctx's
+	// namespace that are subscribed to the given subscription.
+	GetEntitiesBySubscription(ctx context.Context, subscription string) ([]*types.Entity, error)
+
 	// GetEntityByName returns an entity using the given name and the namespace stored
 	// in ctx. The resulting entity is nil if none was found.
 	GetEntityByName(ctx context.Context, name string) (*types.Entity, error)
 
+	// LabelEntities sets and removes the labels of the labeling on the
+	// entities matching its selector, within the namespace stored in ctx, and
+	// returns the names of the entities whose labels changed. The entities
+	// are labeled atomically, and ErrNotValid is returned if there are too
+	// many of them. If the policy is not nil, it is enforced on the labeled
+	// entities.
+	LabelEntities(ctx context.Context, labeling *corev2.EntityLabeling, policy *corev2.LabelPolicy) ([]string, error)
+
+	// RenameEntity renames an entity, and moves its events and the silenced
+	// entries of its entity subscription, using the given names and the
+	// namespace stored in ctx. If merge is true, the entity is merged into the
+	// entity of the new name when it exists.
+	RenameEntity(ctx context.Context, name, newName string, merge bool) (*types.Entity, error)
+
 	// UpdateEntity creates or updates a given entity.
 	UpdateEntity(ctx context.Context, entity *types.Entity) error
 }
@@ -373,6 +472,15 @@ type EventStore interface {
 	// event, which may be the same as the event that was passed in, and the
 	// previous event, if one existed, as well as any error that occurred.
 	UpdateEvent(ctx context.Context, event *types.Event) (old, new *types.Event, err error)
+
+	// GetEventPipeline returns the record of the handling of the last event of
+	// the given entity and check by the event pipeline, within the namespace
+	// stored in ctx. The resulting record is nil if none was found.
+	GetEventPipeline(ctx context.Context, entity, check string) (*corev2.EventPipeline, error)
+
+	// UpdateEventPipeline creates or updates the event pipeline record of an
+	// event.
+	UpdateEventPipeline(ctx context.Context, pipeline *corev2.EventPipeline) error
 }
 
 // EventFilterStore provides methods for managing events filters
@@ -409,6 +517,16 @@ type HandlerStore interface {
 
 	// UpdateHandler creates or updates a given handler.
 	UpdateHandler(ctx context.Context, handler *types.Handler) error
+
+	// GetHandlerOutputs returns the captured outputs of the last executions
+	// of a handler using the given name and the namespace stored in ctx, most
+	// recent first. A nil slice with no error is returned if none were found.
+	GetHandlerOutputs(ctx context.Context, name string) ([]*corev2.HandlerOutput, error)
+
+	// AddHandlerOutput adds the output of an execution of the handler with the
+	// given name to its captured outputs, and only keeps the history most
+	// recent ones.
+	AddHandlerOutput(ctx context.Context, name string, output *corev2.HandlerOutput, history int) error
 }
 
 // HealthStore provides methods for cluster health
@@ -526,6 +644,23 @@ type RoleStore interface {
 	UpdateRole(ctx context.Context, role *types.Role) error
 }
 
+// RouteStore provides methods for managing the cluster-wide notification
+// routes
+type RouteStore interface {
+	// DeleteRouteByName deletes a route using the given name.
+	DeleteRouteByName(ctx context.Context, name string) error
+
+	// GetRouteByName returns a route using the given name, or nil if there is
+	// none.
+	GetRouteByName(ctx context.Context, name string) (*corev2.Route, error)
+
+	// GetRoutes returns all the routes.
+	GetRoutes(ctx context.Context) ([]*corev2.Route, error)
+
+	// UpdateRoute creates or updates a given route.
+	UpdateRoute(ctx context.Context, route *corev2.Route) error
+}
+
 // SilencedStore provides methods for managing silenced entries,
 // consisting of entities, subscriptions and/or checks
 type SilencedStore interface {
@@ -558,6 +693,38 @@ type SilencedStore interface {
 	GetSilencedEntriesByName(ctx context.Context, id ...string) ([]*types.Silenced, error)
 }
 
+// LabelPolicyStore provides methods for managing the label policy of the
+// cluster
+type LabelPolicyStore interface {
+	// GetLabelPolicy gets the label policy, or nil if there is none
+	GetLabelPolicy(context.Context) (*corev2.LabelPolicy, error)
+
+	// UpdateLabelPolicy creates or updates the label policy
+	UpdateLabelPolicy(context.Context, *corev2.LabelPolicy) error
+}
+
+// ManagedFieldsStore provides methods for managing the fields of resources
+// owned by each field manager
+type ManagedFieldsStore interface {
+	// GetManagedFields gets the field ownership of a resource, or nil if no
+	// field is owned
+	GetManagedFields(context.Context, corev2.Resource) (*corev2.ManagedFields, error)
+
+	// UpdateManagedFields creates or updates the field ownership of a resource
+	UpdateManagedFields(context.Context, corev2.Resource, *corev2.ManagedFields) error
+
+	// GetManagedResource gets the resource of the given name and its field
+	// ownership, or nil if no field is owned, at once. It also returns the
+	// revision of the resource, to apply changes to it with
+	// ApplyManagedResource.
+	GetManagedResource(ctx context.Context, name string, resource corev2.Resource) (*corev2.ManagedFields, int64, error)
+
+	// ApplyManagedResource creates or updates a resource and its field
+	// ownership at once, only if the resource is still at the given revision,
+	// or 0 if it did not exist. It returns ErrModified otherwise.
+	ApplyManagedResource(ctx context.Context, resource corev2.Resource, fields *corev2.ManagedFields, revision int64) error
+}
+
 // TessenConfigStore provides methods for managing the Tessen configuration
 type TessenConfigStore interface {
 	// CreateOrUpdateTessenConfig creates or updates the tessen configuration
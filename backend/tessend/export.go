@@ -0,0 +1,113 @@
+package tessend
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+const (
+	// exportedMetricName is the name of the prometheus gauge containing the
+	// tessen metrics.
+	exportedMetricName = "sensu_tessen_metric"
+)
+
+// Exporter exports the tessen data payloads locally, allowing users to make
+// use of the collected metrics without sending them to the tessen service.
+type Exporter interface {
+	Export(data *Data) error
+}
+
+// FileExporter appends the data payloads to a file, one JSON document per
+// line.
+type FileExporter struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileExporter returns a FileExporter writing to the file at path.
+func NewFileExporter(path string) *FileExporter {
+	return &FileExporter{Path: path}
+}
+
+// Export appends the data payload to the file.
+func (e *FileExporter) Export(data *Data) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	f, err := os.OpenFile(e.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// PrometheusExporter is a prometheus collector exposing the last value of
+// every tessen metric, as the sensu_tessen_metric gauge. Metric points are
+// identified by their name and hostname tag, other tags are not exposed.
+type PrometheusExporter struct {
+	desc   *prometheus.Desc
+	points map[[2]string]float64
+	mu     sync.Mutex
+}
+
+// NewPrometheusExporter returns a new PrometheusExporter. It must be
+// registered with a prometheus registry to expose the metrics.
+func NewPrometheusExporter() *PrometheusExporter {
+	return &PrometheusExporter{
+		desc: prometheus.NewDesc(
+			exportedMetricName,
+			"Last value of the metrics collected by tessen",
+			[]string{"name", "hostname"},
+			nil,
+		),
+		points: make(map[[2]string]float64),
+	}
+}
+
+// Export records the metric points of the data payload.
+func (e *PrometheusExporter) Export(data *Data) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, point := range data.Metrics.Points {
+		e.points[[2]string{point.Name, hostnameTag(point)}] = point.Value
+	}
+	return nil
+}
+
+// Describe implements prometheus.Collector.
+func (e *PrometheusExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.desc
+}
+
+// Collect implements prometheus.Collector.
+func (e *PrometheusExporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for key, value := range e.points {
+		ch <- prometheus.MustNewConstMetric(e.desc, prometheus.GaugeValue, value, key[0], key[1])
+	}
+}
+
+// hostnameTag returns the value of the hostname tag of the metric point, or
+// an empty string for cluster-wide metrics.
+func hostnameTag(point *corev2.MetricPoint) string {
+	for _, tag := range point.Tags {
+		if tag.Name == "hostname" {
+			return tag.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package tessend
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testData(value float64) *Data {
+	return &Data{
+		Cluster: Cluster{ID: "foo"},
+		Metrics: corev2.Metrics{
+			Points: []*corev2.MetricPoint{
+				{
+					Name:  "entity_count",
+					Value: value,
+				},
+				{
+					Name:  "sensu_events_processed",
+					Value: 2 * value,
+					Tags:  []*corev2.MetricTag{{Name: "hostname", Value: "backend1"}},
+				},
+			},
+		},
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tessen")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	exporter := NewFileExporter(filepath.Join(dir, "tessen.json"))
+	require.NoError(t, exporter.Export(testData(1)))
+	require.NoError(t, exporter.Export(testData(2)))
+
+	f, err := os.Open(exporter.Path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var payloads []Data
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var data Data
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &data))
+		payloads = append(payloads, data)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, payloads, 2)
+	assert.Equal(t, "foo", payloads[1].Cluster.ID)
+	assert.Equal(t, float64(2), payloads[1].Metrics.Points[0].Value)
+}
+
+func TestPrometheusExporter(t *testing.T) {
+	exporter := NewPrometheusExporter()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(exporter))
+
+	require.NoError(t, exporter.Export(testData(1)))
+	require.NoError(t, exporter.Export(testData(3)))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, exportedMetricName, families[0].GetName())
+
+	values := map[string]float64{}
+	for _, metric := range families[0].Metric {
+		values[labelValue(metric, "name")+"/"+labelValue(metric, "hostname")] = metric.GetGauge().GetValue()
+	}
+	assert.Equal(t, map[string]float64{
+		"entity_count/":                   3,
+		"sensu_events_processed/backend1": 6,
+	}, values)
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.Label {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/coreos/etcd/clientv3"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/eventd"
@@ -90,6 +91,7 @@ type Tessend struct {
 	duration     time.Duration
 	AllowOptOut  bool
 	config       *corev2.TessenConfig
+	exporters    []Exporter
 }
 
 // Option is a functional option.
@@ -101,6 +103,10 @@ type Config struct {
 	RingPool *ringv2.Pool
 	Client   *clientv3.Client
 	Bus      messaging.MessageBus
+
+	// Exporters export the collected metrics locally. Metrics are collected
+	// and exported even if tessen is opted out.
+	Exporters []Exporter
 }
 
 // New creates a new TessenD.
@@ -116,6 +122,12 @@ func New(ctx context.Context, c Config, opts ...Option) (*Tessend, error) {
 		messageChan: make(chan interface{}, 1),
 		duration:    perResourceDuration,
 		AllowOptOut: true,
+		exporters:   c.Exporters,
+	}
+	for _, exporter := range t.exporters {
+		if collector, ok := exporter.(prometheus.Collector); ok {
+			_ = prometheus.Register(collector)
+		}
 	}
 	t.ctx, t.cancel = context.WithCancel(ctx)
 	t.interrupt = make(chan *corev2.TessenConfig, 1)
@@ -153,8 +165,8 @@ func (t *Tessend) Start() error {
 	go t.startPromMetricsUpdates()
 	go t.start()
 	// Attempt to send data immediately if tessen is enabled
-	if t.enabled() {
-		go t.collectAndSend()
+	if enabled := t.enabled(); enabled || t.exporting() {
+		go t.collectAndSend(enabled)
 	}
 
 	return nil
@@ -231,7 +243,7 @@ func (t *Tessend) startMessageHandler() {
 
 		metrics, ok := msg.([]corev2.MetricPoint)
 		if ok {
-			if t.enabled() {
+			if enabled := t.enabled(); enabled || t.exporting() {
 				data := t.getDataPayload()
 				now := time.Now().Unix()
 				for _, metric := range metrics {
@@ -249,7 +261,7 @@ func (t *Tessend) startMessageHandler() {
 					"id":            data.Cluster.ID,
 					"metric_points": len(data.Metrics.Points),
 				}).Info("sending web ui metrics to tessen")
-				_ = t.send(data)
+				_ = t.report(data, enabled)
 			}
 			continue
 		}
@@ -341,8 +353,8 @@ func (t *Tessend) handleEvents(tessen *corev2.TessenConfig, ch <-chan ringv2.Eve
 			logger.WithField("values", event.Values).Debug("tessen ring trigger")
 			// only trigger tessen if the next backend in the ring is this backend
 			if event.Values[0] == t.backendID {
-				if t.enabled() {
-					go t.collectAndSend()
+				if enabled := t.enabled(); enabled || t.exporting() {
+					go t.collectAndSend(enabled)
 				}
 			}
 		case ringv2.EventClosing:
@@ -361,15 +373,16 @@ func (t *Tessend) startPromMetricsUpdates() {
 		case <-t.ctx.Done():
 			return
 		case <-ticker.C:
-			if t.enabled() {
-				t.sendPromMetrics()
+			if enabled := t.enabled(); enabled || t.exporting() {
+				t.sendPromMetrics(enabled)
 			}
 		}
 	}
 }
 
-// sendPromMetrics collects and sends prometheus metrics for event processing to tessen.
-func (t *Tessend) sendPromMetrics() {
+// sendPromMetrics collects prometheus metrics for event processing, exports
+// them and sends them to tessen if send is true.
+func (t *Tessend) sendPromMetrics(send bool) {
 	var hostname string
 
 	// collect data
@@ -411,7 +424,7 @@ func (t *Tessend) sendPromMetrics() {
 	}).Info("sending event processing metrics to tessen")
 
 	// send data
-	_ = t.send(data)
+	_ = t.report(data, send)
 }
 
 // start starts the tessen service.
@@ -452,9 +465,10 @@ func (t *Tessend) enabled() bool {
 	return true
 }
 
-// collectAndSend is a durable function to collect and send data to tessen.
-// Errors are logged and tessen continues to the best of its ability.
-func (t *Tessend) collectAndSend() {
+// collectAndSend is a durable function to collect data, export it and send it
+// to tessen if send is true. Errors are logged and tessen continues to the
+// best of its ability.
+func (t *Tessend) collectAndSend(send bool) {
 	// collect data
 	data := t.getDataPayload()
 	t.getPerResourceMetrics(time.Now().Unix(), data)
@@ -466,7 +480,7 @@ func (t *Tessend) collectAndSend() {
 	}).Info("sending resource counts to tessen")
 
 	// send data
-	respHeader := t.send(data)
+	respHeader := t.report(data, send)
 	if respHeader == "" {
 		logger.Debug("no tessen response header")
 		return
@@ -584,6 +598,25 @@ func (t *Tessend) getTessenConfigMetrics(now int64, tessen *corev2.TessenConfig,
 	data.Metrics.Points = append(data.Metrics.Points, mp)
 }
 
+// exporting returns true if tessen exports the metrics it collects locally.
+func (t *Tessend) exporting() bool {
+	return len(t.exporters) > 0
+}
+
+// report exports the data payload with every exporter, then sends it to the
+// tessen url if send is true. It returns the interval response header.
+func (t *Tessend) report(data *Data, send bool) string {
+	for _, exporter := range t.exporters {
+		if err := exporter.Export(data); err != nil {
+			logger.WithError(err).Error("unable to export tessen metrics")
+		}
+	}
+	if !send {
+		return ""
+	}
+	return t.send(data)
+}
+
 // send sends the data payload to the tessen url and retrieves the interval response header.
 func (t *Tessend) send(data *Data) string {
 	b, _ := json.Marshal(data)
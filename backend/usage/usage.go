@@ -0,0 +1,147 @@
+// Package usage tracks the usage of the backend by each user over rolling
+// windows, so the administrators can identify the abusive automation.
+package usage
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// buckets is the number of one-minute buckets of each counter, which covers
+// the longest rolling window.
+const buckets = 60
+
+// counter counts over rolling windows with one-minute buckets.
+type counter struct {
+	buckets [buckets]int64
+
+	// last is the minute of the last bucket, since the epoch
+	last int64
+}
+
+// advance clears the buckets of the minutes elapsed since the last bucket.
+func (c *counter) advance(minute int64) {
+	if minute <= c.last {
+		return
+	}
+	if minute-c.last >= buckets {
+		c.buckets = [buckets]int64{}
+	} else {
+		for m := c.last + 1; m <= minute; m++ {
+			c.buckets[m%buckets] = 0
+		}
+	}
+	c.last = minute
+}
+
+func (c *counter) add(minute int64) {
+	c.advance(minute)
+	c.buckets[c.last%buckets]++
+}
+
+// sum returns the count over the given number of minutes, up to the given
+// minute included.
+func (c *counter) sum(minute, minutes int64) int64 {
+	c.advance(minute)
+	var sum int64
+	for m := c.last - minutes + 1; m <= c.last; m++ {
+		if m >= 0 {
+			sum += c.buckets[m%buckets]
+		}
+	}
+	return sum
+}
+
+func (c *counter) counts(minute int64) corev2.UsageCounts {
+	return corev2.UsageCounts{
+		LastMinute:    c.sum(minute, 1),
+		Last15Minutes: c.sum(minute, 15),
+		LastHour:      c.sum(minute, buckets),
+	}
+}
+
+type user struct {
+	requests counter
+	events   counter
+}
+
+// Tracker tracks the requests to the API and the event submissions of each
+// user. It is safe for concurrent use.
+type Tracker struct {
+	mu    sync.Mutex
+	users map[string]*user
+	now   func() time.Time
+}
+
+// NewTracker returns a new Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		users: make(map[string]*user),
+		now:   time.Now,
+	}
+}
+
+func (t *Tracker) minute() int64 {
+	return t.now().Unix() / 60
+}
+
+func (t *Tracker) user(username string) *user {
+	u, ok := t.users[username]
+	if !ok {
+		u = &user{}
+		t.users[username] = u
+	}
+	return u
+}
+
+// RecordRequest records a request of the given user to the API.
+func (t *Tracker) RecordRequest(username string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.user(username).requests.add(t.minute())
+}
+
+// RecordEvent records an event submitted by the given user.
+func (t *Tracker) RecordEvent(username string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.user(username).events.add(t.minute())
+}
+
+// Usage returns the usage of the users active over the last hour, by
+// decreasing number of requests then events over the last hour. The users
+// inactive over the last hour are forgotten.
+func (t *Tracker) Usage() []corev2.UserUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	minute := t.minute()
+	usage := make([]corev2.UserUsage, 0, len(t.users))
+	for username, u := range t.users {
+		requests := u.requests.counts(minute)
+		events := u.events.counts(minute)
+		if requests.LastHour == 0 && events.LastHour == 0 {
+			delete(t.users, username)
+			continue
+		}
+		usage = append(usage, corev2.UserUsage{
+			Username: username,
+			Requests: requests,
+			Events:   events,
+		})
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Requests.LastHour != usage[j].Requests.LastHour {
+			return usage[i].Requests.LastHour > usage[j].Requests.LastHour
+		}
+		if usage[i].Events.LastHour != usage[j].Events.LastHour {
+			return usage[i].Events.LastHour > usage[j].Events.LastHour
+		}
+		return usage[i].Username < usage[j].Username
+	})
+	return usage
+}
@@ -0,0 +1,51 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+
+	tracker.RecordRequest("admin")
+	tracker.RecordEvent("agent")
+	tracker.RecordEvent("agent")
+
+	now = now.Add(10 * time.Minute)
+	tracker.RecordRequest("admin")
+	tracker.RecordRequest("admin")
+	tracker.RecordEvent("agent")
+
+	usage := tracker.Usage()
+	require.Len(t, usage, 2)
+	assert.Equal(t, corev2.UserUsage{
+		Username: "admin",
+		Requests: corev2.UsageCounts{LastMinute: 2, Last15Minutes: 3, LastHour: 3},
+	}, usage[0])
+	assert.Equal(t, corev2.UserUsage{
+		Username: "agent",
+		Events:   corev2.UsageCounts{LastMinute: 1, Last15Minutes: 3, LastHour: 3},
+	}, usage[1])
+
+	// The first records leave the 15 minutes window
+	now = now.Add(10 * time.Minute)
+	usage = tracker.Usage()
+	require.Len(t, usage, 2)
+	assert.Equal(t, corev2.UsageCounts{Last15Minutes: 2, LastHour: 3}, usage[0].Requests)
+	assert.Equal(t, corev2.UsageCounts{Last15Minutes: 1, LastHour: 3}, usage[1].Events)
+
+	// The users inactive over the last hour are forgotten
+	now = now.Add(time.Hour)
+	tracker.RecordEvent("agent")
+	usage = tracker.Usage()
+	require.Len(t, usage, 1)
+	assert.Equal(t, "agent", usage[0].Username)
+	assert.Equal(t, int64(1), usage[0].Events.LastHour)
+}
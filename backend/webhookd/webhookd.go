@@ -0,0 +1,449 @@
+// Package webhookd posts the notifications of the notification webhooks when
+// the resources they select change.
+package webhookd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/etcd"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SignatureHeader is the header of the HMAC-SHA256 signature of the
+	// notifications, with the secret of the webhook as key.
+	SignatureHeader = "X-Sensu-Signature"
+
+	// defaultTimeout is the timeout of the notifications of the webhooks
+	// without one.
+	defaultTimeout = 10 * time.Second
+
+	// claimTTL is the time, in seconds, during which a change claimed by a
+	// backend is not notified by the others.
+	claimTTL = 300
+
+	// claimsPath is the path of the claims in the store.
+	claimsPath = "notification-webhook-claims"
+)
+
+var (
+	// retryBackoff is the delay before the first retry of a failed
+	// notification, doubled at every retry.
+	retryBackoff = time.Second
+
+	// maxRetryBackoff is the maximum delay between two retries.
+	maxRetryBackoff = time.Minute
+)
+
+// watchedResources are the resources watched for every resource type
+// supported by the notification webhooks.
+var watchedResources = map[string]corev2.Resource{
+	corev2.AssetsResource:       &corev2.Asset{},
+	corev2.ChecksResource:       &corev2.CheckConfig{},
+	corev2.EventFiltersResource: &corev2.EventFilter{},
+	corev2.HandlersResource:     &corev2.Handler{},
+	corev2.HooksResource:        &corev2.HookConfig{},
+	corev2.MutatorsResource:     &corev2.Mutator{},
+	corev2.RemediationsResource: &corev2.Remediation{},
+	corev2.RoleBindingsResource: &corev2.RoleBinding{},
+	corev2.RolesResource:        &corev2.Role{},
+	corev2.SilencedResource:     &corev2.Silenced{},
+}
+
+// watchActions are the notification webhook actions of the store watch
+// events.
+var watchActions = map[store.WatchActionType]string{
+	store.WatchCreate: corev2.NotificationWebhookActionCreated,
+	store.WatchUpdate: corev2.NotificationWebhookActionUpdated,
+	store.WatchDelete: corev2.NotificationWebhookActionDeleted,
+}
+
+// Notification is the body of the requests posted by the notification
+// webhooks. It does not include the changed resource, which may hold
+// secrets, only its type and name.
+type Notification struct {
+	// Webhook is the name of the notification webhook.
+	Webhook string `json:"webhook"`
+
+	// Namespace is the namespace of the changed resource.
+	Namespace string `json:"namespace"`
+
+	// ResourceType is the type of the changed resource, e.g. checks.
+	ResourceType string `json:"resource_type"`
+
+	// Action is the change: created, updated or deleted.
+	Action string `json:"action"`
+
+	// Name is the name of the changed resource.
+	Name string `json:"name"`
+
+	// Revision is the revision of the store at which the resource changed.
+	Revision int64 `json:"revision"`
+
+	// Timestamp is the time the notification was posted, as a Unix
+	// timestamp.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// Claimer claims the changes to notify, so every change is only notified by
+// one backend of the cluster.
+type Claimer interface {
+	// Claim returns true if the change identified by the key was not claimed
+	// yet.
+	Claim(ctx context.Context, key string) (bool, error)
+}
+
+// pendingNotification is a notification queued for the workers.
+type pendingNotification struct {
+	webhook      *corev2.NotificationWebhook
+	notification *Notification
+	fields       logrus.Fields
+}
+
+// Webhookd watches the resources selected by the notification webhooks, and
+// posts their notifications when they change.
+type Webhookd struct {
+	store         store.ResourceStore
+	client        *clientv3.Client
+	claimer       Claimer
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+	workers       sync.WaitGroup
+	workerCount   int
+	notifications chan *pendingNotification
+	errChan       chan error
+	origins       []*url.URL
+}
+
+// Config configures a Webhookd.
+type Config struct {
+	Store  store.Store
+	Client *clientv3.Client
+
+	// WorkerCount is the number of notifications posted at the same time.
+	WorkerCount int
+
+	// BufferSize is the number of notifications queued for the workers,
+	// after which the changes are not handled until a worker is available.
+	BufferSize int
+
+	// AllowedOrigins are the scheme://host[:port] origins the notifications
+	// can be posted to. Any port of the host is allowed if the origin has
+	// none. No notification is posted if empty.
+	AllowedOrigins []string
+}
+
+// Option is a functional option used to configure Webhookd.
+type Option func(*Webhookd) error
+
+// WithClaimer sets the claimer of the changes to notify.
+func WithClaimer(claimer Claimer) Option {
+	return func(w *Webhookd) error {
+		w.claimer = claimer
+		return nil
+	}
+}
+
+// New creates a new Webhookd with supplied Options applied.
+func New(c Config, options ...Option) (*Webhookd, error) {
+	origins := make([]*url.URL, 0, len(c.AllowedOrigins))
+	for _, origin := range c.AllowedOrigins {
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+			return nil, fmt.Errorf("invalid notification webhook origin %q, must be of the form scheme://host[:port]", origin)
+		}
+		origins = append(origins, u)
+	}
+
+	if c.BufferSize == 0 {
+		c.BufferSize = 1
+	}
+	if c.WorkerCount == 0 {
+		c.WorkerCount = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Webhookd{
+		store:         c.Store,
+		client:        c.Client,
+		claimer:       &etcdClaimer{client: c.Client},
+		ctx:           ctx,
+		cancel:        cancel,
+		workerCount:   c.WorkerCount,
+		notifications: make(chan *pendingNotification, c.BufferSize),
+		errChan:       make(chan error, 1),
+		origins:       origins,
+	}
+	for _, o := range options {
+		if err := o(w); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Start webhookd, watching every resource type supported by the notification
+// webhooks.
+func (w *Webhookd) Start() error {
+	w.startWorkers()
+	for resourceType, resource := range watchedResources {
+		key := path.Join(store.Root, resource.StorePrefix()) + "/"
+		watcher := etcd.GetResourceWatcher(w.ctx, w.client, key, reflect.TypeOf(resource))
+		w.wg.Add(1)
+		go w.watch(resourceType, watcher)
+	}
+	return nil
+}
+
+// Stop webhookd, aborting the retries of the failed notifications.
+func (w *Webhookd) Stop() error {
+	w.cancel()
+	w.wg.Wait()
+	w.stopWorkers()
+	close(w.errChan)
+	return nil
+}
+
+// startWorkers starts the workers posting the queued notifications.
+func (w *Webhookd) startWorkers() {
+	w.workers.Add(w.workerCount)
+	for i := 0; i < w.workerCount; i++ {
+		go w.work()
+	}
+}
+
+// stopWorkers waits for the workers to post the queued notifications. The
+// changes must not be handled anymore.
+func (w *Webhookd) stopWorkers() {
+	close(w.notifications)
+	w.workers.Wait()
+}
+
+func (w *Webhookd) work() {
+	defer w.workers.Done()
+	for pending := range w.notifications {
+		if err := w.notify(pending.webhook, pending.notification); err != nil {
+			logger.WithFields(pending.fields).WithError(err).Error("could not post the notification")
+			continue
+		}
+		logger.WithFields(pending.fields).Debug("posted the notification")
+	}
+}
+
+// Err returns a channel to listen for terminal errors on.
+func (w *Webhookd) Err() <-chan error {
+	return w.errChan
+}
+
+// Name returns the daemon name
+func (w *Webhookd) Name() string {
+	return "webhookd"
+}
+
+func (w *Webhookd) watch(resourceType string, watcher <-chan store.WatchEventResource) {
+	defer w.wg.Done()
+	for event := range watcher {
+		w.handleChange(w.ctx, resourceType, event)
+	}
+}
+
+// handleChange queues the notifications of the webhooks selecting the change,
+// if no other backend claimed them yet. The changes of the cluster-wide
+// resources, without namespace, are only notified by the webhooks without
+// namespace, and the changes of the namespaced resources by the webhooks of
+// their namespace.
+func (w *Webhookd) handleChange(ctx context.Context, resourceType string, event store.WatchEventResource) {
+	action, ok := watchActions[event.Action]
+	if !ok || event.Resource == nil {
+		return
+	}
+	meta := event.Resource.GetObjectMeta()
+	ctx = context.WithValue(ctx, corev2.NamespaceKey, meta.Namespace)
+
+	var webhooks []*corev2.NotificationWebhook
+	if err := w.store.ListResources(ctx, corev2.NotificationWebhooksResource, &webhooks, &store.SelectionPredicate{}); err != nil {
+		logger.WithError(err).Error("could not list the notification webhooks")
+		return
+	}
+
+	for _, webhook := range webhooks {
+		// Listing the webhooks without namespace lists those of every
+		// namespace
+		if webhook.Namespace != meta.Namespace || !webhook.Selects(resourceType, action) {
+			continue
+		}
+
+		notification := &Notification{
+			Webhook:      webhook.Name,
+			Namespace:    meta.Namespace,
+			ResourceType: resourceType,
+			Action:       action,
+			Name:         meta.Name,
+			Revision:     event.Revision,
+		}
+		fields := logrus.Fields{
+			"namespace":            webhook.Namespace,
+			"notification_webhook": webhook.Name,
+			"resource_type":        resourceType,
+			"resource":             meta.Name,
+			"action":               action,
+		}
+
+		key := path.Join(webhook.Namespace, webhook.Name, fmt.Sprint(event.Revision), resourceType, meta.Name)
+		claimed, err := w.claimer.Claim(ctx, key)
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Warning("could not claim the notification, posting it anyway")
+		} else if !claimed {
+			continue
+		}
+
+		select {
+		case w.notifications <- &pendingNotification{webhook: webhook, notification: notification, fields: fields}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// notify posts the notification to the webhook, and retries with an
+// exponential backoff until it succeeds or the retries of the webhook are
+// exhausted.
+func (w *Webhookd) notify(webhook *corev2.NotificationWebhook, notification *Notification) error {
+	timeout := defaultTimeout
+	if webhook.Timeout > 0 {
+		timeout = time.Duration(webhook.Timeout) * time.Second
+	}
+	if err := w.allowURL(webhook.URL); err != nil {
+		return err
+	}
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return w.allowURL(req.URL.String())
+		},
+	}
+	backoff := retryBackoff
+
+	var err error
+	for attempt := uint32(0); ; attempt++ {
+		notification.Timestamp = time.Now().Unix()
+		if err = post(w.ctx, client, webhook, notification); err == nil || attempt >= webhook.Retries {
+			return err
+		}
+		logger.WithError(err).WithField("notification_webhook", webhook.Name).Warning("notification failed, retrying")
+
+		select {
+		case <-w.ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// allowURL returns an error if the notifications can't be posted to the URL,
+// because its scheme and host match none of the allowed origins.
+func (w *Webhookd) allowURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	for _, origin := range w.origins {
+		if !strings.EqualFold(origin.Scheme, u.Scheme) {
+			continue
+		}
+		if origin.Port() == "" && strings.EqualFold(origin.Hostname(), u.Hostname()) {
+			return nil
+		}
+		if strings.EqualFold(origin.Host, u.Host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("notification webhook url %q does not match the allowed origins", rawURL)
+}
+
+// post posts the notification to the webhook, signed with its secret, if
+// any. Any status other than 2xx is an error.
+func post(ctx context.Context, client *http.Client, webhook *corev2.NotificationWebhook, notification *Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign([]byte(webhook.Secret), body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the value of the signature header of the notification body,
+// i.e. the hex encoded HMAC-SHA256 of the body with the given secret,
+// prefixed with "sha256=".
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// etcdClaimer claims the changes with keys created with a lease, so they
+// expire once every backend of the cluster saw the change.
+type etcdClaimer struct {
+	client *clientv3.Client
+}
+
+// Claim creates the claim of the change, unless it already exists.
+func (c *etcdClaimer) Claim(ctx context.Context, key string) (bool, error) {
+	lease, err := c.client.Grant(ctx, claimTTL)
+	if err != nil {
+		return false, err
+	}
+	key = path.Join(store.Root, claimsPath, key)
+	resp, err := c.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, "", clientv3.WithLease(lease.ID))).
+		Commit()
+	if err != nil || !resp.Succeeded {
+		_, _ = c.client.Revoke(ctx, lease.ID)
+	}
+	if err != nil {
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
@@ -0,0 +1,283 @@
+package webhookd
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type testClaimer struct {
+	mu     sync.Mutex
+	claims map[string]bool
+}
+
+func (c *testClaimer) Claim(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.claims[key] {
+		return false, nil
+	}
+	c.claims[key] = true
+	return true, nil
+}
+
+type testServer struct {
+	*httptest.Server
+	mu            sync.Mutex
+	failures      int
+	notifications []Notification
+	signatures    []string
+}
+
+func newTestServer(failures int) *testServer {
+	s := &testServer{failures: failures}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.failures > 0 {
+			s.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		var notification Notification
+		_ = json.Unmarshal(body, &notification)
+		s.notifications = append(s.notifications, notification)
+		s.signatures = append(s.signatures, r.Header.Get(SignatureHeader))
+	}))
+	return s
+}
+
+func newTestWebhookd(t *testing.T, webhooks ...*corev2.NotificationWebhook) *Webhookd {
+	t.Helper()
+	s := &mockstore.MockStore{}
+	s.On("ListResources", mock.Anything, corev2.NotificationWebhooksResource, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			list := args[2].(*[]*corev2.NotificationWebhook)
+			*list = webhooks
+		})
+	w, err := New(Config{Store: s, AllowedOrigins: []string{"http://127.0.0.1"}}, WithClaimer(&testClaimer{claims: map[string]bool{}}))
+	require.NoError(t, err)
+	return w
+}
+
+func TestHandleChange(t *testing.T) {
+	server := newTestServer(0)
+	defer server.Close()
+
+	checks := corev2.FixtureNotificationWebhook("checks")
+	checks.URL = server.URL
+	checks.Secret = "secret"
+	deleted := corev2.FixtureNotificationWebhook("deleted")
+	deleted.URL = server.URL
+	deleted.Actions = []string{corev2.NotificationWebhookActionDeleted}
+	silenced := corev2.FixtureNotificationWebhook("silenced")
+	silenced.URL = server.URL
+	silenced.ResourceTypes = []string{corev2.SilencedResource}
+
+	w := newTestWebhookd(t, checks, deleted, silenced)
+	w.startWorkers()
+	event := store.WatchEventResource{
+		Action:   store.WatchUpdate,
+		Resource: corev2.FixtureCheckConfig("check-cpu"),
+		Revision: 42,
+	}
+	w.handleChange(context.Background(), corev2.ChecksResource, event)
+	// Another backend handling the same change does not notify it again
+	w.handleChange(context.Background(), corev2.ChecksResource, event)
+	w.stopWorkers()
+
+	require.Len(t, server.notifications, 1)
+	notification := server.notifications[0]
+	assert.Equal(t, "checks", notification.Webhook)
+	assert.Equal(t, "default", notification.Namespace)
+	assert.Equal(t, corev2.ChecksResource, notification.ResourceType)
+	assert.Equal(t, corev2.NotificationWebhookActionUpdated, notification.Action)
+	assert.Equal(t, "check-cpu", notification.Name)
+	assert.Equal(t, int64(42), notification.Revision)
+
+	body, err := json.Marshal(notification)
+	require.NoError(t, err)
+	assert.Equal(t, Sign([]byte("secret"), body), server.signatures[0])
+}
+
+func TestHandleChangeIgnoresErrors(t *testing.T) {
+	server := newTestServer(0)
+	defer server.Close()
+
+	webhook := corev2.FixtureNotificationWebhook("checks")
+	webhook.URL = server.URL
+	w := newTestWebhookd(t, webhook)
+	w.startWorkers()
+	w.handleChange(context.Background(), corev2.ChecksResource, store.WatchEventResource{Action: store.WatchError})
+	w.stopWorkers()
+
+	assert.Empty(t, server.notifications)
+}
+
+func TestHandleChangeNamespaces(t *testing.T) {
+	server := newTestServer(0)
+	defer server.Close()
+
+	webhook := corev2.FixtureNotificationWebhook("checks")
+	webhook.URL = server.URL
+	other := corev2.FixtureNotificationWebhook("checks")
+	other.Namespace = "other"
+	other.URL = server.URL
+
+	w := newTestWebhookd(t, webhook, other)
+	w.startWorkers()
+
+	// The change of a cluster-wide resource is not notified to the
+	// namespaced webhooks
+	check := corev2.FixtureCheckConfig("check-cpu")
+	check.Namespace = ""
+	w.handleChange(context.Background(), corev2.ChecksResource, store.WatchEventResource{
+		Action:   store.WatchCreate,
+		Resource: check,
+		Revision: 1,
+	})
+	w.handleChange(context.Background(), corev2.ChecksResource, store.WatchEventResource{
+		Action:   store.WatchCreate,
+		Resource: corev2.FixtureCheckConfig("check-cpu"),
+		Revision: 2,
+	})
+	w.stopWorkers()
+
+	require.Len(t, server.notifications, 1)
+	assert.Equal(t, "default", server.notifications[0].Namespace)
+	assert.Equal(t, int64(2), server.notifications[0].Revision)
+}
+
+func TestHandleChangeWorkers(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var inflight, maxInflight int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inflight++
+		if inflight > maxInflight {
+			maxInflight = inflight
+		}
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		inflight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	webhooks := []*corev2.NotificationWebhook{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		webhook := corev2.FixtureNotificationWebhook(name)
+		webhook.URL = server.URL
+		webhooks = append(webhooks, webhook)
+	}
+	w := newTestWebhookd(t, webhooks...)
+	w.workerCount = 2
+	w.notifications = make(chan *pendingNotification, len(webhooks))
+	w.startWorkers()
+
+	w.handleChange(context.Background(), corev2.ChecksResource, store.WatchEventResource{
+		Action:   store.WatchCreate,
+		Resource: corev2.FixtureCheckConfig("check-cpu"),
+		Revision: 1,
+	})
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	w.stopWorkers()
+
+	// The notifications are posted by the workers only
+	assert.Equal(t, 2, maxInflight)
+}
+
+func TestNotifyRetries(t *testing.T) {
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	tests := []struct {
+		name     string
+		failures int
+		retries  uint32
+		wantErr  bool
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:     "success after retries",
+			failures: 2,
+			retries:  2,
+		},
+		{
+			name:     "retries exhausted",
+			failures: 3,
+			retries:  2,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(tt.failures)
+			defer server.Close()
+
+			webhook := corev2.FixtureNotificationWebhook("checks")
+			webhook.URL = server.URL
+			webhook.Retries = tt.retries
+			w := newTestWebhookd(t, webhook)
+			defer w.Stop()
+
+			err := w.notify(webhook, &Notification{Webhook: webhook.Name})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				assert.Len(t, server.notifications, 1)
+			}
+		})
+	}
+}
+
+func TestNotifyAllowedOrigins(t *testing.T) {
+	server := newTestServer(0)
+	defer server.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusTemporaryRedirect))
+	defer redirect.Close()
+
+	webhook := corev2.FixtureNotificationWebhook("checks")
+	webhook.URL = server.URL
+	w, err := New(Config{AllowedOrigins: []string{"https://127.0.0.1"}})
+	require.NoError(t, err)
+	assert.Error(t, w.notify(webhook, &Notification{Webhook: webhook.Name}))
+
+	// The redirections are followed to the allowed origins only
+	w, err = New(Config{AllowedOrigins: []string{strings.TrimSuffix(redirect.URL, "/")}})
+	require.NoError(t, err)
+	webhook.URL = redirect.URL
+	assert.Error(t, w.notify(webhook, &Notification{Webhook: webhook.Name}))
+	assert.Empty(t, server.notifications)
+
+	w, err = New(Config{AllowedOrigins: []string{"http://127.0.0.1"}})
+	require.NoError(t, err)
+	assert.NoError(t, w.notify(webhook, &Notification{Webhook: webhook.Name}))
+	assert.Len(t, server.notifications, 1)
+
+	for _, origin := range []string{"127.0.0.1", "ftp://127.0.0.1", "https://example.com/hooks"} {
+		_, err := New(Config{AllowedOrigins: []string{origin}})
+		assert.Error(t, err, origin)
+	}
+}
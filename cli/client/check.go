@@ -50,11 +50,39 @@ func (client *RestClient) UpdateCheck(check *types.CheckConfig) (err error) {
 	return nil
 }
 
+// ValidateCheck validates the given check on configured Sensu instance, and
+// returns the best-practice warnings about its configuration
+func (client *RestClient) ValidateCheck(check *types.CheckConfig) ([]corev2.CheckConfigWarning, error) {
+	bytes, err := json.Marshal(check)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []corev2.CheckConfigWarning
+	path := checksPath(check.Namespace, "validate")
+	res, err := client.R().SetBody(bytes).SetResult(&warnings).Post(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+
+	return warnings, nil
+}
+
 // DeleteCheck deletes check from configured Sensu instance
 func (client *RestClient) DeleteCheck(namespace, name string) error {
 	return client.Delete(checksPath(namespace, name))
 }
 
+// DeleteCheckAndResolveEvents deletes check from configured Sensu instance,
+// and resolves its events that are not in an OK state
+func (client *RestClient) DeleteCheckAndResolveEvents(namespace, name string) error {
+	return client.Delete(checksPath(namespace, name) + "?resolveEvents=true")
+}
+
 // ExecuteCheck sends an execution request with the provided adhoc request
 func (client *RestClient) ExecuteCheck(req *types.AdhocRequest) error {
 	bytes, err := json.Marshal(req)
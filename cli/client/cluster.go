@@ -7,10 +7,12 @@ import (
 	"strings"
 
 	"github.com/coreos/etcd/clientv3"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 )
 
 var clusterMembersPath = CreateBasePath(coreAPIGroup, coreAPIVersion, "cluster", "members")
 var clusterIDPath = CreateBasePath(coreAPIGroup, coreAPIVersion, "cluster", "id")
+var clusterLeaderPath = CreateBasePath(coreAPIGroup, coreAPIVersion, "cluster", "leader")
 
 // MemberList lists all members in the cluster.
 func (c *RestClient) MemberList() (*clientv3.MemberListResponse, error) {
@@ -83,3 +85,30 @@ func (c *RestClient) FetchClusterID() (string, error) {
 
 	return string(res.Body()), err
 }
+
+// FetchClusterLeadership fetches the leadership of the cluster.
+func (c *RestClient) FetchClusterLeadership() (*corev2.ClusterLeadership, error) {
+	path := clusterLeaderPath()
+	res, err := c.R().Get(path)
+	if err != nil {
+		return nil, fmt.Errorf("GET %q: %s", path, err)
+	}
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+	var result corev2.ClusterLeadership
+	return &result, json.Unmarshal(res.Body(), &result)
+}
+
+// TransferLeadership hands the leadership of the cluster off to a member.
+func (c *RestClient) TransferLeadership(id uint64) error {
+	endpoint := fmt.Sprintf("%s/%x", clusterLeaderPath(), id)
+	res, err := c.R().Put(endpoint)
+	if err != nil {
+		return fmt.Errorf("PUT %q: %s", endpoint, err)
+	}
+	if res.StatusCode() >= 400 {
+		return UnmarshalError(res)
+	}
+	return nil
+}
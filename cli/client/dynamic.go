@@ -0,0 +1,169 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/sensu/sensu-go/types"
+)
+
+// dynamicResourceClient implements ResourceClient for any type known to the
+// type registry, using the resources' URIPath to build request paths.
+type dynamicResourceClient struct {
+	client    *RestClient
+	typeMeta  types.TypeMeta
+	namespace string
+}
+
+// Resource returns a ResourceClient for the resource type described by tm.
+// The client operates in the configured namespace until Namespace is called.
+func (client *RestClient) Resource(tm types.TypeMeta) ResourceClient {
+	if tm.APIVersion == "" {
+		tm.APIVersion = "core/v2"
+	}
+	return &dynamicResourceClient{
+		client:    client,
+		typeMeta:  tm,
+		namespace: client.config.Namespace(),
+	}
+}
+
+// Namespace returns a copy of the client operating in the given namespace.
+func (d *dynamicResourceClient) Namespace(namespace string) ResourceClient {
+	c := *d
+	c.namespace = namespace
+	return &c
+}
+
+// Get fetches the resource with the given name.
+func (d *dynamicResourceClient) Get(name string) (types.Wrapper, error) {
+	var w types.Wrapper
+	path, err := d.path(name)
+	if err != nil {
+		return w, err
+	}
+
+	res, err := d.client.R().Get(path)
+	if err != nil {
+		return w, err
+	}
+	if res.StatusCode() >= 400 {
+		return w, UnmarshalError(res)
+	}
+
+	return d.decode(res.Body())
+}
+
+// List fetches all the resources of the client's type, following continue
+// tokens.
+func (d *dynamicResourceClient) List(options *ListOptions) ([]types.Wrapper, error) {
+	path, err := d.path("")
+	if err != nil {
+		return nil, err
+	}
+
+	var wrappers []types.Wrapper
+	err = d.client.ListEach(path, &[]json.RawMessage{}, options, func(obj interface{}) error {
+		w, err := d.decode(*obj.(*json.RawMessage))
+		if err != nil {
+			return err
+		}
+		wrappers = append(wrappers, w)
+		return nil
+	})
+
+	return wrappers, err
+}
+
+// Put creates or updates the given resource.
+func (d *dynamicResourceClient) Put(w types.Wrapper) error {
+	if w.Value == nil {
+		return errors.New("no spec provided")
+	}
+	if w.Type == "" {
+		w.TypeMeta = d.typeMeta
+	}
+	// Like the type of the client, the API version defaults to core/v2
+	if w.APIVersion == "" {
+		w.APIVersion = "core/v2"
+	}
+	if w.Type != d.typeMeta.Type {
+		return fmt.Errorf("resource is a %s, not a %s", w.Type, d.typeMeta.Type)
+	}
+	if w.APIVersion != d.typeMeta.APIVersion {
+		return fmt.Errorf("resource has API version %s, not %s", w.APIVersion, d.typeMeta.APIVersion)
+	}
+	if w.Value.GetObjectMeta().Namespace == "" {
+		w.Value.SetNamespace(d.namespace)
+	}
+	return d.client.PutResource(w)
+}
+
+// Delete deletes the resource with the given name.
+func (d *dynamicResourceClient) Delete(name string) error {
+	path, err := d.path(name)
+	if err != nil {
+		return err
+	}
+	return d.client.Delete(path)
+}
+
+// path returns the URI path of the resource with the given name, or of the
+// collection if name is empty.
+func (d *dynamicResourceClient) path(name string) (string, error) {
+	r, err := types.ResolveType(d.typeMeta.APIVersion, d.typeMeta.Type)
+	if err != nil {
+		return "", err
+	}
+	r.SetNamespace(d.namespace)
+	if err := setName(r, name); err != nil {
+		return "", err
+	}
+	path := r.URIPath()
+	if path == "" {
+		return "", fmt.Errorf("%s resources can not be addressed by name", d.typeMeta.Type)
+	}
+	return path, nil
+}
+
+// decode unmarshals a resource returned by the API into a wrapper. The core
+// API returns bare resources, while other APIs return wrapped resources.
+func (d *dynamicResourceClient) decode(b []byte) (types.Wrapper, error) {
+	var w types.Wrapper
+	if d.typeMeta.APIVersion != "core/v2" {
+		err := json.Unmarshal(b, &w)
+		return w, err
+	}
+
+	r, err := types.ResolveType(d.typeMeta.APIVersion, d.typeMeta.Type)
+	if err != nil {
+		return w, err
+	}
+	if err := json.Unmarshal(b, r); err != nil {
+		return w, err
+	}
+
+	return types.Wrapper{
+		TypeMeta:   d.typeMeta,
+		ObjectMeta: r.GetObjectMeta(),
+		Value:      r,
+	}, nil
+}
+
+// setName sets the name of a zero-valued resource, either through its
+// ObjectMeta or, for types without metadata such as namespaces, through its
+// Name field.
+func setName(r types.Resource, name string) error {
+	val := reflect.Indirect(reflect.ValueOf(r))
+	if meta := val.FieldByName("ObjectMeta"); meta.IsValid() {
+		meta.FieldByName("Name").SetString(name)
+		return nil
+	}
+	if field := val.FieldByName("Name"); field.IsValid() && field.Kind() == reflect.String {
+		field.SetString(name)
+		return nil
+	}
+	return fmt.Errorf("can't set the name of %s resources", val.Type().Name())
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/cli/client/config"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicResourcePath(t *testing.T) {
+	mockConfig := &config.MockConfig{}
+	mockConfig.On("Namespace").Return("default")
+	client := &RestClient{resty: resty.New(), config: mockConfig}
+
+	testCases := []struct {
+		typeMeta  types.TypeMeta
+		namespace string
+		name      string
+		want      string
+	}{
+		{
+			typeMeta:  types.TypeMeta{Type: "CheckConfig"},
+			namespace: "dev",
+			name:      "check-cpu",
+			want:      "/api/core/v2/namespaces/dev/checks/check-cpu",
+		},
+		{
+			typeMeta:  types.TypeMeta{Type: "CheckConfig", APIVersion: "core/v2"},
+			namespace: "dev",
+			want:      "/api/core/v2/namespaces/dev/checks",
+		},
+		{
+			typeMeta: types.TypeMeta{Type: "Namespace"},
+			name:     "dev",
+			want:     "/api/core/v2/namespaces/dev",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			rc := client.Resource(tc.typeMeta).Namespace(tc.namespace).(*dynamicResourceClient)
+			path, err := rc.path(tc.name)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, path)
+		})
+	}
+
+	_, err := client.Resource(types.TypeMeta{Type: "NotAType"}).(*dynamicResourceClient).path("foo")
+	assert.Error(t, err)
+}
+
+func TestDynamicResourceGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/core/v2/namespaces/default/checks/check-cpu", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"metadata":{"name":"check-cpu","namespace":"default"},"command":"true"}`))
+	}))
+	defer server.Close()
+
+	mockConfig := &config.MockConfig{}
+	mockConfig.On("APIUrl").Return(server.URL)
+	mockConfig.On("Tokens").Return(&types.Tokens{})
+	mockConfig.On("Namespace").Return("default")
+	client := &RestClient{resty: resty.New(), config: mockConfig}
+
+	w, err := client.Resource(types.TypeMeta{Type: "CheckConfig"}).Get("check-cpu")
+	require.NoError(t, err)
+	assert.Equal(t, "CheckConfig", w.Type)
+	assert.Equal(t, "check-cpu", w.ObjectMeta.Name)
+	assert.Equal(t, "true", w.Value.(*corev2.CheckConfig).Command)
+}
+
+func TestDynamicResourcePut(t *testing.T) {
+	var puts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/core/v2/namespaces/default/checks/check-cpu", r.URL.Path)
+		puts++
+	}))
+	defer server.Close()
+
+	mockConfig := &config.MockConfig{}
+	mockConfig.On("APIUrl").Return(server.URL)
+	mockConfig.On("Tokens").Return(&types.Tokens{})
+	mockConfig.On("Namespace").Return("default")
+	client := &RestClient{resty: resty.New(), config: mockConfig}
+	rc := client.Resource(types.TypeMeta{Type: "CheckConfig"})
+
+	// The API version of the resource defaults to core/v2
+	check := corev2.FixtureCheckConfig("check-cpu")
+	require.NoError(t, rc.Put(types.Wrapper{TypeMeta: types.TypeMeta{Type: "CheckConfig"}, Value: check}))
+	assert.Equal(t, 1, puts)
+
+	err := rc.Put(types.Wrapper{TypeMeta: types.TypeMeta{Type: "Handler"}, Value: check})
+	require.Error(t, err)
+	assert.Equal(t, "resource is a Handler, not a CheckConfig", err.Error())
+
+	err = rc.Put(types.Wrapper{TypeMeta: types.TypeMeta{Type: "CheckConfig", APIVersion: "core/v3"}, Value: check})
+	require.Error(t, err)
+	assert.Equal(t, "resource has API version core/v3, not core/v2", err.Error())
+	assert.Equal(t, 1, puts)
+}
@@ -3,6 +3,8 @@ package client
 import (
 	"encoding/json"
 
+	"github.com/go-resty/resty"
+
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/types"
 )
@@ -82,3 +84,132 @@ func (client *RestClient) CreateEntity(entity *types.Entity) (err error) {
 
 	return nil
 }
+
+// FetchEntitySpool fetches the spool statistics of the agent of the given
+// entity
+func (client *RestClient) FetchEntitySpool(namespace, name string) (*corev2.AgentSpoolStats, error) {
+	return client.entitySpool(client.R().Get, entitiesPath(namespace, name, "spool"))
+}
+
+// FlushEntitySpool asks the agent of the given entity to transmit its spooled
+// events without rate limiting
+func (client *RestClient) FlushEntitySpool(namespace, name string) (*corev2.AgentSpoolStats, error) {
+	return client.entitySpool(client.R().Post, entitiesPath(namespace, name, "spool", "flush"))
+}
+
+// PurgeEntitySpool asks the agent of the given entity to discard its spooled
+// events
+func (client *RestClient) PurgeEntitySpool(namespace, name string) (*corev2.AgentSpoolStats, error) {
+	return client.entitySpool(client.R().Delete, entitiesPath(namespace, name, "spool"))
+}
+
+func (client *RestClient) entitySpool(do func(string) (*resty.Response, error), path string) (*corev2.AgentSpoolStats, error) {
+	res, err := do(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+
+	var stats corev2.AgentSpoolStats
+	err = json.Unmarshal(res.Body(), &stats)
+	return &stats, err
+}
+
+// ExecuteEntityCommand asks the agent of the given entity to execute a
+// command, whose result is sent by the agent as an event
+func (client *RestClient) ExecuteEntityCommand(namespace, name string, command *corev2.AgentCommand) (*corev2.AgentCommandResult, error) {
+	bytes, err := json.Marshal(command)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.R().SetBody(bytes).Post(entitiesPath(namespace, name, "commands"))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+
+	var result corev2.AgentCommandResult
+	err = json.Unmarshal(res.Body(), &result)
+	return &result, err
+}
+
+// RenameEntity renames the given entity, or merges it into the entity of the
+// new name, keeping its events and silenced entries
+func (client *RestClient) RenameEntity(namespace, name string, rename *corev2.EntityRename) (*corev2.Entity, error) {
+	bytes, err := json.Marshal(rename)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.R().SetBody(bytes).Post(entitiesPath(namespace, name, "rename"))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+
+	var entity corev2.Entity
+	err = json.Unmarshal(res.Body(), &entity)
+	return &entity, err
+}
+
+// FetchAgentConfig fetches the configuration managed by the backend for the
+// agent of the given entity
+func (client *RestClient) FetchAgentConfig(namespace, name string) (*corev2.AgentConfig, error) {
+	res, err := client.R().Get(entitiesPath(namespace, name, "config"))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+
+	var config corev2.AgentConfig
+	err = json.Unmarshal(res.Body(), &config)
+	return &config, err
+}
+
+// UpdateAgentConfig replaces the configuration managed by the backend for the
+// agent of the given entity
+func (client *RestClient) UpdateAgentConfig(namespace, name string, config *corev2.AgentConfig) error {
+	bytes, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.R().SetBody(bytes).Put(entitiesPath(namespace, name, "config"))
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode() >= 400 {
+		return UnmarshalError(res)
+	}
+
+	return nil
+}
+
+// DeleteAgentConfig deletes the configuration managed by the backend for the
+// agent of the given entity
+func (client *RestClient) DeleteAgentConfig(namespace, name string) error {
+	res, err := client.R().Delete(entitiesPath(namespace, name, "config"))
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode() >= 400 {
+		return UnmarshalError(res)
+	}
+
+	return nil
+}
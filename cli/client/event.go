@@ -71,3 +71,22 @@ func (client *RestClient) ResolveEvent(event *types.Event) error {
 	event.Timestamp = int64(time.Now().Unix())
 	return client.UpdateEvent(event)
 }
+
+// SnoozeEvent silences an event for the given duration, and returns the
+// silenced entry created.
+func (client *RestClient) SnoozeEvent(namespace, entity, check string, duration time.Duration) (*types.Silenced, error) {
+	var silenced *types.Silenced
+
+	path := eventsPath(namespace, entity, check, "snooze")
+	res, err := client.R().SetQueryParam("duration", duration.String()).Post(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+
+	err = json.Unmarshal(res.Body(), &silenced)
+	return silenced, err
+}
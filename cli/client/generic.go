@@ -1,9 +1,11 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 
 	v2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/types"
@@ -41,17 +43,101 @@ func (client *RestClient) Get(path string, obj interface{}) error {
 // The options parameter allows for enhancing the request with field/label
 // selectors (filtering), pagination, ...
 func (client *RestClient) List(path string, objs interface{}, options *ListOptions) error {
+	o := reflect.ValueOf(objs)
+	if o.Kind() != reflect.Ptr || o.Elem().Kind() != reflect.Slice {
+		panic("unexpected type for objs")
+	}
+
+	return client.listPages(context.Background(), path, o.Elem().Type(), options, func(page reflect.Value) error {
+		o.Elem().Set(reflect.AppendSlice(o.Elem(), page))
+		return nil
+	})
+}
+
+// ListEach sends GET requests for the objects at the given path, one chunk at
+// a time, and calls fn with a pointer to each object as it is decoded. Unlike
+// List, at most one chunk of objects is held in memory, which makes it
+// suitable for iterating over very large collections. objs must be a pointer
+// to a slice and is only used to determine the type of the objects; it is not
+// modified. options may be nil, and if options.ChunkSize is not positive,
+// DefaultChunkSize is used. Iteration stops at the first error returned by
+// fn, and that error is returned.
+func (client *RestClient) ListEach(path string, objs interface{}, options *ListOptions, fn func(obj interface{}) error) error {
+	return client.listEach(context.Background(), path, objs, options, fn)
+}
+
+// ListChan is like ListEach, but yields a pointer to each object on the
+// returned channel. The object channel is closed once every chunk has been
+// consumed, an error occurred or ctx is done, which also aborts the request
+// in flight. The error channel receives at most one error and is closed
+// afterwards. The caller must either drain the object channel or cancel ctx,
+// otherwise the listing goroutine is blocked forever.
+func (client *RestClient) ListChan(ctx context.Context, path string, objs interface{}, options *ListOptions) (<-chan interface{}, <-chan error) {
+	objc := make(chan interface{})
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(objc)
+
+		err := client.listEach(ctx, path, objs, options, func(obj interface{}) error {
+			select {
+			case objc <- obj:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+
+	return objc, errc
+}
+
+// listEach implements ListEach, sending the requests with ctx.
+func (client *RestClient) listEach(ctx context.Context, path string, objs interface{}, options *ListOptions, fn func(obj interface{}) error) error {
 	objsType := reflect.TypeOf(objs)
 	if objsType.Kind() != reflect.Ptr || objsType.Elem().Kind() != reflect.Slice {
 		panic("unexpected type for objs")
 	}
 
+	if options == nil {
+		options = &ListOptions{}
+	}
+	opts := *options
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	defer func() {
+		options.ContinueToken = opts.ContinueToken
+	}()
+
+	return client.listPages(ctx, path, objsType.Elem(), &opts, func(page reflect.Value) error {
+		for i := 0; i < page.Len(); i++ {
+			if err := fn(page.Index(i).Addr().Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// listPages sends GET requests for the objects at the given path, following
+// the continue tokens returned by the API, and calls fn with each decoded
+// page. sliceType is the type of the slice each page is decoded into. The
+// requests are aborted once ctx is done.
+func (client *RestClient) listPages(ctx context.Context, path string, sliceType reflect.Type, options *ListOptions, fn func(page reflect.Value) error) error {
 	for {
-		request := client.R()
+		request := client.R().SetContext(ctx)
 		ApplyListOptions(request, options)
 
 		resp, err := request.Get(path)
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			return err
 		}
 
@@ -59,13 +145,14 @@ func (client *RestClient) List(path string, objs interface{}, options *ListOptio
 			return UnmarshalError(resp)
 		}
 
-		newObjs := reflect.New(objsType.Elem())
-		if err := json.Unmarshal(resp.Body(), newObjs.Interface()); err != nil {
+		page := reflect.New(sliceType)
+		if err := json.Unmarshal(resp.Body(), page.Interface()); err != nil {
 			return err
 		}
 
-		o := reflect.ValueOf(objs).Elem()
-		o.Set(reflect.AppendSlice(o, newObjs.Elem()))
+		if err := fn(page.Elem()); err != nil {
+			return err
+		}
 
 		options.ContinueToken = resp.Header().Get(v2.PaginationContinueHeader)
 		if options.ContinueToken == "" {
@@ -130,3 +217,20 @@ func (client *RestClient) PutResource(r types.Wrapper) error {
 	}
 	return nil
 }
+
+// ApplyResource sends a PATCH request with the fields of the resource to
+// apply as the payload to the given path.
+func (client *RestClient) ApplyResource(path string, fields interface{}, manager string, force bool) error {
+	res, err := client.R().
+		SetQueryParam(v2.FieldManagerQueryParam, manager).
+		SetQueryParam(v2.ForceConflictsQueryParam, strconv.FormatBool(force)).
+		SetBody(fields).
+		Patch(path)
+	if err != nil {
+		return fmt.Errorf("PATCH %q: %s", path, err)
+	}
+	if res.StatusCode() >= 400 {
+		return UnmarshalError(res)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/cli/client/config"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPaginatedServer returns a server that serves total checks in chunks of
+// the requested limit, using the chunk offset as continue token.
+func newPaginatedServer(t *testing.T, total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		require.NoError(t, err)
+		offset := 0
+		if c := r.URL.Query().Get("continue"); c != "" {
+			offset, err = strconv.Atoi(c)
+			require.NoError(t, err)
+		}
+
+		body := "["
+		for i := offset; i < total && i < offset+limit; i++ {
+			if i > offset {
+				body += ","
+			}
+			body += fmt.Sprintf(`{"metadata":{"name":"check%d"}}`, i)
+		}
+		body += "]"
+		if offset+limit < total {
+			w.Header().Set(corev2.PaginationContinueHeader, strconv.Itoa(offset+limit))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func newTestClient(url string) *RestClient {
+	mockConfig := &config.MockConfig{}
+	mockConfig.On("APIUrl").Return(url)
+	mockConfig.On("Tokens").Return(&types.Tokens{})
+	return &RestClient{resty: resty.New(), config: mockConfig}
+}
+
+func TestListEach(t *testing.T) {
+	server := newPaginatedServer(t, 250)
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	var names []string
+	err := client.ListEach("/checks", &[]corev2.CheckConfig{}, &ListOptions{}, func(obj interface{}) error {
+		names = append(names, obj.(*corev2.CheckConfig).Name)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, names, 250)
+	assert.Equal(t, "check249", names[249])
+}
+
+func TestListEachNilOptions(t *testing.T) {
+	server := newPaginatedServer(t, 42)
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	count := 0
+	err := client.ListEach("/checks", &[]corev2.CheckConfig{}, nil, func(obj interface{}) error {
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, count)
+}
+
+func TestListEachStops(t *testing.T) {
+	server := newPaginatedServer(t, 250)
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	stop := errors.New("stop")
+	count := 0
+	err := client.ListEach("/checks", &[]corev2.CheckConfig{}, &ListOptions{ChunkSize: 10}, func(obj interface{}) error {
+		count++
+		if count == 15 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 15, count)
+}
+
+func TestListChan(t *testing.T) {
+	server := newPaginatedServer(t, 42)
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	objs, errs := client.ListChan(context.Background(), "/checks", &[]corev2.CheckConfig{}, &ListOptions{ChunkSize: 5})
+	count := 0
+	for range objs {
+		count++
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, 42, count)
+}
+
+func TestListChanCancel(t *testing.T) {
+	// The server never responds, until the client aborts the request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	objs, errs := client.ListChan(ctx, "/checks", &[]corev2.CheckConfig{}, nil)
+	cancel()
+
+	select {
+	case _, ok := <-objs:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request in flight was not aborted")
+	}
+	assert.Equal(t, context.Canceled, <-errs)
+}
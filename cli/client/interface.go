@@ -1,12 +1,19 @@
 package client
 
 import (
+	"context"
+	"time"
+
 	"github.com/coreos/etcd/clientv3"
 	"github.com/sensu/sensu-go/types"
 
 	corev2 "github.com/sensu/sensu-go/api/core/v2"
 )
 
+// DefaultChunkSize is the number of objects fetched per request by the
+// iterating list methods when ListOptions.ChunkSize is not set.
+const DefaultChunkSize = 100
+
 // ListOptions represents the various options that can be used when listing
 // resources.
 type ListOptions struct {
@@ -43,6 +50,7 @@ type APIClient interface {
 	UserAPIClient
 	SilencedAPIClient
 	GenericClient
+	DynamicClient
 	ClusterMemberClient
 	LicenseClient
 }
@@ -55,6 +63,12 @@ type GenericClient interface {
 	Get(path string, obj interface{}) error
 	// List retrieves all keys with the given path prefix and stores them into objs
 	List(path string, objs interface{}, options *ListOptions) error
+	// ListEach retrieves all keys with the given path prefix, one chunk at a
+	// time, and calls fn with each of them
+	ListEach(path string, objs interface{}, options *ListOptions, fn func(obj interface{}) error) error
+	// ListChan retrieves all keys with the given path prefix, one chunk at a
+	// time, and yields them on the returned channel
+	ListChan(ctx context.Context, path string, objs interface{}, options *ListOptions) (<-chan interface{}, <-chan error)
 	// Post creates the given obj at the specified path
 	Post(path string, obj interface{}) error
 	// Put creates the given obj at the specified path
@@ -62,6 +76,33 @@ type GenericClient interface {
 
 	// PutResource puts a resource according to its URIPath.
 	PutResource(types.Wrapper) error
+
+	// ApplyResource applies the given fields of the resource at the specified
+	// path on behalf of the field manager, forcing the conflicts if force is
+	// true.
+	ApplyResource(path string, fields interface{}, manager string, force bool) error
+}
+
+// DynamicClient exposes methods for any resource type known to the type
+// registry, including types registered by other API groups.
+type DynamicClient interface {
+	// Resource returns a client for the resource type described by the given
+	// type meta. The core/v2 API version is assumed if none is provided.
+	Resource(types.TypeMeta) ResourceClient
+}
+
+// ResourceClient exposes generic methods for a single resource type.
+type ResourceClient interface {
+	// Namespace returns a ResourceClient operating in the given namespace.
+	Namespace(string) ResourceClient
+	// Get retrieves the resource with the given name
+	Get(name string) (types.Wrapper, error)
+	// List retrieves all the resources of the type
+	List(options *ListOptions) ([]types.Wrapper, error)
+	// Put creates or updates the given resource
+	Put(types.Wrapper) error
+	// Delete deletes the resource with the given name
+	Delete(name string) error
 }
 
 // AuthenticationAPIClient client methods for authenticating
@@ -84,10 +125,12 @@ type AssetAPIClient interface {
 type CheckAPIClient interface {
 	CreateCheck(*types.CheckConfig) error
 	DeleteCheck(string, string) error
+	DeleteCheckAndResolveEvents(string, string) error
 	ExecuteCheck(*types.AdhocRequest) error
 	FetchCheck(string) (*types.CheckConfig, error)
 	ListChecks(string, *ListOptions) ([]types.CheckConfig, error)
 	UpdateCheck(*types.CheckConfig) error
+	ValidateCheck(*types.CheckConfig) ([]corev2.CheckConfigWarning, error)
 
 	AddCheckHook(check *types.CheckConfig, checkHook *types.HookList) error
 	RemoveCheckHook(check *types.CheckConfig, checkHookType string, hookName string) error
@@ -116,6 +159,25 @@ type EntityAPIClient interface {
 	FetchEntity(ID string) (*types.Entity, error)
 	ListEntities(string, *ListOptions) ([]types.Entity, error)
 	UpdateEntity(entity *types.Entity) error
+
+	// FetchEntitySpool fetches the spool statistics of an entity's agent.
+	FetchEntitySpool(namespace, name string) (*corev2.AgentSpoolStats, error)
+	// FlushEntitySpool flushes the spool of an entity's agent.
+	FlushEntitySpool(namespace, name string) (*corev2.AgentSpoolStats, error)
+	// PurgeEntitySpool purges the spool of an entity's agent.
+	PurgeEntitySpool(namespace, name string) (*corev2.AgentSpoolStats, error)
+	// ExecuteEntityCommand executes a command on an entity's agent.
+	ExecuteEntityCommand(namespace, name string, command *corev2.AgentCommand) (*corev2.AgentCommandResult, error)
+	// RenameEntity renames an entity, or merges it into an existing entity.
+	RenameEntity(namespace, name string, rename *corev2.EntityRename) (*corev2.Entity, error)
+	// FetchAgentConfig fetches the managed configuration of an entity's agent.
+	FetchAgentConfig(namespace, name string) (*corev2.AgentConfig, error)
+	// UpdateAgentConfig replaces the managed configuration of an entity's
+	// agent.
+	UpdateAgentConfig(namespace, name string, config *corev2.AgentConfig) error
+	// DeleteAgentConfig deletes the managed configuration of an entity's
+	// agent.
+	DeleteAgentConfig(namespace, name string) error
 }
 
 // FilterAPIClient client methods for filters
@@ -136,6 +198,10 @@ type EventAPIClient interface {
 	DeleteEvent(namespace, entity, check string) error
 	UpdateEvent(*types.Event) error
 	ResolveEvent(*types.Event) error
+
+	// SnoozeEvent silences the event identified by entity, check for the
+	// given duration.
+	SnoozeEvent(namespace, entity, check string, duration time.Duration) (*types.Silenced, error)
 }
 
 // ExtensionAPIClient client methods for extensions
@@ -251,6 +317,12 @@ type ClusterMemberClient interface {
 
 	// FetchClusterID gets the sensu cluster id.
 	FetchClusterID() (string, error)
+
+	// FetchClusterLeadership gets the leadership of the cluster.
+	FetchClusterLeadership() (*corev2.ClusterLeadership, error)
+
+	// TransferLeadership hands the leadership of the cluster off to a member.
+	TransferLeadership(id uint64) error
 }
 
 // LicenseClient specifies the enteprise client methods for license management.
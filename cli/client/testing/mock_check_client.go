@@ -19,12 +19,24 @@ func (c *MockClient) UpdateCheck(check *types.CheckConfig) error {
 	return args.Error(0)
 }
 
+// ValidateCheck for use with mock lib
+func (c *MockClient) ValidateCheck(check *types.CheckConfig) ([]corev2.CheckConfigWarning, error) {
+	args := c.Called(check)
+	return args.Get(0).([]corev2.CheckConfigWarning), args.Error(1)
+}
+
 // DeleteCheck for use with mock lib
 func (c *MockClient) DeleteCheck(namespace, name string) error {
 	args := c.Called(namespace, name)
 	return args.Error(0)
 }
 
+// DeleteCheckAndResolveEvents for use with mock lib
+func (c *MockClient) DeleteCheckAndResolveEvents(namespace, name string) error {
+	args := c.Called(namespace, name)
+	return args.Error(0)
+}
+
 // ExecuteCheck for use with mock lib
 func (c *MockClient) ExecuteCheck(req *types.AdhocRequest) error {
 	args := c.Called(req)
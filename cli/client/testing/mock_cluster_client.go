@@ -1,6 +1,9 @@
 package testing
 
-import "github.com/coreos/etcd/clientv3"
+import (
+	"github.com/coreos/etcd/clientv3"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
 
 // MemberList ...
 func (c *MockClient) MemberList() (*clientv3.MemberListResponse, error) {
@@ -31,3 +34,15 @@ func (c *MockClient) FetchClusterID() (string, error) {
 	args := c.Called()
 	return args.Get(0).(string), args.Error(1)
 }
+
+// FetchClusterLeadership ...
+func (c *MockClient) FetchClusterLeadership() (*corev2.ClusterLeadership, error) {
+	args := c.Called()
+	return args.Get(0).(*corev2.ClusterLeadership), args.Error(1)
+}
+
+// TransferLeadership ...
+func (c *MockClient) TransferLeadership(id uint64) error {
+	args := c.Called(id)
+	return args.Error(0)
+}
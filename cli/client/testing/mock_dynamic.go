@@ -0,0 +1,48 @@
+package testing
+
+import (
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// Resource ...
+func (c *MockClient) Resource(tm types.TypeMeta) client.ResourceClient {
+	args := c.Called(tm)
+	return args.Get(0).(client.ResourceClient)
+}
+
+// MockResourceClient is a mock implementation of client.ResourceClient.
+type MockResourceClient struct {
+	mock.Mock
+}
+
+// Namespace ...
+func (c *MockResourceClient) Namespace(namespace string) client.ResourceClient {
+	args := c.Called(namespace)
+	return args.Get(0).(client.ResourceClient)
+}
+
+// Get ...
+func (c *MockResourceClient) Get(name string) (types.Wrapper, error) {
+	args := c.Called(name)
+	return args.Get(0).(types.Wrapper), args.Error(1)
+}
+
+// List ...
+func (c *MockResourceClient) List(options *client.ListOptions) ([]types.Wrapper, error) {
+	args := c.Called(options)
+	return args.Get(0).([]types.Wrapper), args.Error(1)
+}
+
+// Put ...
+func (c *MockResourceClient) Put(w types.Wrapper) error {
+	args := c.Called(w)
+	return args.Error(0)
+}
+
+// Delete ...
+func (c *MockResourceClient) Delete(name string) error {
+	args := c.Called(name)
+	return args.Error(0)
+}
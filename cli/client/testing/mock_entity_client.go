@@ -36,3 +36,51 @@ func (c *MockClient) CreateEntity(entity *types.Entity) error {
 	args := c.Called(entity)
 	return args.Error(0)
 }
+
+// FetchEntitySpool for use with mock lib
+func (c *MockClient) FetchEntitySpool(namespace, name string) (*corev2.AgentSpoolStats, error) {
+	args := c.Called(namespace, name)
+	return args.Get(0).(*corev2.AgentSpoolStats), args.Error(1)
+}
+
+// FlushEntitySpool for use with mock lib
+func (c *MockClient) FlushEntitySpool(namespace, name string) (*corev2.AgentSpoolStats, error) {
+	args := c.Called(namespace, name)
+	return args.Get(0).(*corev2.AgentSpoolStats), args.Error(1)
+}
+
+// PurgeEntitySpool for use with mock lib
+func (c *MockClient) PurgeEntitySpool(namespace, name string) (*corev2.AgentSpoolStats, error) {
+	args := c.Called(namespace, name)
+	return args.Get(0).(*corev2.AgentSpoolStats), args.Error(1)
+}
+
+// ExecuteEntityCommand for use with mock lib
+func (c *MockClient) ExecuteEntityCommand(namespace, name string, command *corev2.AgentCommand) (*corev2.AgentCommandResult, error) {
+	args := c.Called(namespace, name, command)
+	return args.Get(0).(*corev2.AgentCommandResult), args.Error(1)
+}
+
+// RenameEntity for use with mock lib
+func (c *MockClient) RenameEntity(namespace, name string, rename *corev2.EntityRename) (*corev2.Entity, error) {
+	args := c.Called(namespace, name, rename)
+	return args.Get(0).(*corev2.Entity), args.Error(1)
+}
+
+// FetchAgentConfig for use with mock lib
+func (c *MockClient) FetchAgentConfig(namespace, name string) (*corev2.AgentConfig, error) {
+	args := c.Called(namespace, name)
+	return args.Get(0).(*corev2.AgentConfig), args.Error(1)
+}
+
+// UpdateAgentConfig for use with mock lib
+func (c *MockClient) UpdateAgentConfig(namespace, name string, config *corev2.AgentConfig) error {
+	args := c.Called(namespace, name, config)
+	return args.Error(0)
+}
+
+// DeleteAgentConfig for use with mock lib
+func (c *MockClient) DeleteAgentConfig(namespace, name string) error {
+	args := c.Called(namespace, name)
+	return args.Error(0)
+}
@@ -1,6 +1,8 @@
 package testing
 
 import (
+	"time"
+
 	"github.com/sensu/sensu-go/cli/client"
 	"github.com/sensu/sensu-go/types"
 
@@ -36,3 +38,9 @@ func (c *MockClient) ResolveEvent(event *types.Event) error {
 	args := c.Called(event)
 	return args.Error(0)
 }
+
+// SnoozeEvent for use with mock lib
+func (c *MockClient) SnoozeEvent(namespace, entity, check string, duration time.Duration) (*types.Silenced, error) {
+	args := c.Called(namespace, entity, check, duration)
+	return args.Get(0).(*types.Silenced), args.Error(1)
+}
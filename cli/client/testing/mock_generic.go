@@ -1,6 +1,8 @@
 package testing
 
 import (
+	"context"
+
 	"github.com/sensu/sensu-go/cli/client"
 	"github.com/sensu/sensu-go/types"
 )
@@ -23,6 +25,18 @@ func (c *MockClient) List(path string, objs interface{}, options *client.ListOpt
 	return args.Error(0)
 }
 
+// ListEach ...
+func (c *MockClient) ListEach(path string, objs interface{}, options *client.ListOptions, fn func(interface{}) error) error {
+	args := c.Called(path, objs, options, fn)
+	return args.Error(0)
+}
+
+// ListChan ...
+func (c *MockClient) ListChan(ctx context.Context, path string, objs interface{}, options *client.ListOptions) (<-chan interface{}, <-chan error) {
+	args := c.Called(ctx, path, objs, options)
+	return args.Get(0).(<-chan interface{}), args.Get(1).(<-chan error)
+}
+
 // Post ...
 func (c *MockClient) Post(path string, obj interface{}) error {
 	args := c.Called(path, obj)
@@ -40,3 +54,9 @@ func (c *MockClient) PutResource(r types.Wrapper) error {
 	args := c.Called(r)
 	return args.Error(0)
 }
+
+// ApplyResource ...
+func (c *MockClient) ApplyResource(path string, fields interface{}, manager string, force bool) error {
+	args := c.Called(path, fields, manager, force)
+	return args.Error(0)
+}
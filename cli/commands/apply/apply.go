@@ -0,0 +1,156 @@
+package apply
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/cli/commands/create"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFieldManager   = "field-manager"
+	flagForceConflicts = "force-conflicts"
+
+	// DefaultFieldManager is the field manager of the resources applied with
+	// sensuctl.
+	DefaultFieldManager = "sensuctl"
+)
+
+// Command applies generic Sensu resources.
+func Command(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply [-f FILE]",
+		Short: "apply resources from file or STDIN, only changing the fields they set",
+		RunE:  execute(cli),
+	}
+
+	_ = cmd.Flags().StringP("file", "f", "", "File to apply resources from")
+	_ = cmd.Flags().String(flagFieldManager, DefaultFieldManager, "name of the manager owning the applied fields")
+	_ = cmd.Flags().Bool(flagForceConflicts, false, "take the ownership of the applied fields owned by other managers")
+
+	return cmd
+}
+
+func execute(cli *cli.SensuCli) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			_ = cmd.Help()
+			return errors.New("invalid argument(s) received")
+		}
+		fp, err := cmd.Flags().GetString("file")
+		if err != nil {
+			return err
+		}
+		manager, err := cmd.Flags().GetString(flagFieldManager)
+		if err != nil {
+			return err
+		}
+		if manager == "" {
+			return fmt.Errorf("the %s flag must not be empty", flagFieldManager)
+		}
+		force, err := cmd.Flags().GetBool(flagForceConflicts)
+		if err != nil {
+			return err
+		}
+
+		in, err := helpers.InputData(fp)
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadAll(in)
+		if err != nil {
+			return err
+		}
+
+		resources, err := create.ParseResources(bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		if err := create.ValidateResources(resources, cli.Config.Namespace()); err != nil {
+			return err
+		}
+		raw, err := create.ParseRawResources(bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		if len(raw) != len(resources) {
+			return errors.New("error parsing resources")
+		}
+
+		return ApplyResources(cli.Client, resources, raw, manager, force)
+	}
+}
+
+// ApplyResources applies the fields set in each raw resource to the
+// corresponding parsed resource, on behalf of the field manager.
+func ApplyResources(client client.GenericClient, resources []types.Wrapper, raw []json.RawMessage, manager string, force bool) error {
+	for i, resource := range resources {
+		path := resource.Value.URIPath()
+		if resource.APIVersion != "core/v2" {
+			return fmt.Errorf("error applying resource %d (%s): unsupported api_version %q", i, path, resource.APIVersion)
+		}
+		fields, err := appliedFields(resource, raw[i])
+		if err != nil {
+			return fmt.Errorf("error applying resource %d (%s): %s", i, path, err)
+		}
+		if err := client.ApplyResource(path, fields, manager, force); err != nil {
+			return fmt.Errorf("error applying resource %d (%s): %s", i, path, err)
+		}
+	}
+	return nil
+}
+
+// appliedFields returns the fields of the spec of the raw resource, along with
+// the name, namespace, labels and annotations of the parsed resource, which
+// include the outer metadata, so that only the fields set by the user are
+// applied.
+func appliedFields(resource types.Wrapper, raw json.RawMessage) (map[string]interface{}, error) {
+	var wrapper struct {
+		Spec map[string]interface{} `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &wrapper); err != nil {
+		return nil, err
+	}
+	fields := wrapper.Spec
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+	meta, ok := fields["metadata"].(map[string]interface{})
+	if !ok {
+		meta = make(map[string]interface{})
+		fields["metadata"] = meta
+	}
+
+	objectMeta := resource.Value.GetObjectMeta()
+	if objectMeta.Name != "" {
+		meta["name"] = objectMeta.Name
+	}
+	if objectMeta.Namespace != "" {
+		meta["namespace"] = objectMeta.Namespace
+	}
+	mergeMetadataMap(meta, "labels", objectMeta.Labels)
+	mergeMetadataMap(meta, "annotations", objectMeta.Annotations)
+	return fields, nil
+}
+
+func mergeMetadataMap(meta map[string]interface{}, key string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	m, ok := meta[key].(map[string]interface{})
+	if !ok {
+		m = make(map[string]interface{})
+		meta[key] = m
+	}
+	for k, v := range values {
+		m[k] = v
+	}
+}
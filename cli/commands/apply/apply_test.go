@@ -0,0 +1,80 @@
+package apply
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mockclient "github.com/sensu/sensu-go/cli/client/testing"
+	cmdtesting "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const resources = `
+{"type": "CheckConfig", "metadata": {"labels": {"team": "ops"}}, "spec": {"metadata": {"name": "disk"}, "command": "check-disk", "interval": 60, "publish": true}}
+---
+type: Asset
+metadata:
+  name: ruby
+  namespace: dev
+spec:
+  url: http://example.com/ruby.tar.gz
+  sha512: 4f926bf4328fbad2b9cac873d117f771914f4b837c9c85584c38ccf55a3ef3c2e8d154812246e5dda4a87450576b2c58ad9ab40c9e2edc31b288d066b195b21b
+`
+
+func TestApplyCommand(t *testing.T) {
+	cli := cmdtesting.NewMockCLI()
+	client := cli.Client.(*mockclient.MockClient)
+	client.On("ApplyResource", "/api/core/v2/namespaces/default/checks/disk", map[string]interface{}{
+		"command":  "check-disk",
+		"interval": float64(60),
+		"publish":  true,
+		"metadata": map[string]interface{}{
+			"name":      "disk",
+			"namespace": "default",
+			"labels":    map[string]interface{}{"team": "ops"},
+		},
+	}, "gitops", true).Return(nil)
+	client.On("ApplyResource", "/api/core/v2/namespaces/dev/assets/ruby", map[string]interface{}{
+		"url":    "http://example.com/ruby.tar.gz",
+		"sha512": "4f926bf4328fbad2b9cac873d117f771914f4b837c9c85584c38ccf55a3ef3c2e8d154812246e5dda4a87450576b2c58ad9ab40c9e2edc31b288d066b195b21b",
+		"metadata": map[string]interface{}{
+			"name":      "ruby",
+			"namespace": "dev",
+		},
+	}, "gitops", true).Return(nil)
+
+	td, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(td)
+	fp := filepath.Join(td, "resources.yml")
+	require.NoError(t, ioutil.WriteFile(fp, []byte(resources), 0644))
+
+	cmd := Command(cli)
+	require.NoError(t, cmd.Flags().Set("file", fp))
+	require.NoError(t, cmd.Flags().Set(flagFieldManager, "gitops"))
+	require.NoError(t, cmd.Flags().Set(flagForceConflicts, "true"))
+	require.NoError(t, cmd.Execute())
+	client.AssertExpectations(t)
+}
+
+func TestApplyCommandDefaultFieldManager(t *testing.T) {
+	cli := cmdtesting.NewMockCLI()
+	client := cli.Client.(*mockclient.MockClient)
+	client.On("ApplyResource", "/api/core/v2/namespaces/default/checks/disk", mock.Anything, DefaultFieldManager, false).Return(nil)
+
+	td, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(td)
+	fp := filepath.Join(td, "check.json")
+	check := `{"type": "CheckConfig", "spec": {"metadata": {"name": "disk"}, "command": "check-disk", "interval": 60}}`
+	require.NoError(t, ioutil.WriteFile(fp, []byte(check), 0644))
+
+	cmd := Command(cli)
+	require.NoError(t, cmd.Flags().Set("file", fp))
+	assert.NoError(t, cmd.Execute())
+	client.AssertExpectations(t)
+}
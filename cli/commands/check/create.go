@@ -46,11 +46,17 @@ func CreateCommand(cli *cli.SensuCli) *cobra.Command {
 				}
 			} else {
 				opts.withFlags(cmd.Flags())
-				if opts.Interval != "" && opts.Cron != "" {
-					return fmt.Errorf("cannot specify --interval and --cron at the same time")
+				schedules := 0
+				for _, schedule := range []string{opts.Interval, opts.Cron, opts.RunAt} {
+					if schedule != "" {
+						schedules++
+					}
 				}
-				if opts.Interval == "" && opts.Cron == "" {
-					return fmt.Errorf("must specify --interval or --cron")
+				if schedules > 1 {
+					return fmt.Errorf("cannot specify more than one of --interval, --cron and --run-at")
+				}
+				if schedules == 0 {
+					return fmt.Errorf("must specify --interval, --cron or --run-at")
 				}
 			}
 
@@ -83,6 +89,7 @@ func CreateCommand(cli *cli.SensuCli) *cobra.Command {
 
 	cmd.Flags().StringP("command", "c", "", "the command the check should run")
 	cmd.Flags().String("cron", "", "the cron schedule at which the check is run")
+	cmd.Flags().String("run-at", "", "comma separated list of times, in RFC 3339 format, at which the check is run once")
 	cmd.Flags().String("handlers", "", "comma separated list of handlers to invoke when check fails")
 	cmd.Flags().StringP("interval", "i", "", "interval, in seconds, at which the check is run")
 	cmd.Flags().StringP("runtime-assets", "r", "", "comma separated list of assets this check depends on")
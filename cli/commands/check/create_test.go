@@ -6,6 +6,7 @@ import (
 
 	client "github.com/sensu/sensu-go/cli/client/testing"
 	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/sensu/sensu-go/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -120,3 +121,25 @@ func TestCreateCommandRunEClosureWithMissingRequiredFlags(t *testing.T) {
 	require.Error(t, err)
 	assert.Empty(out)
 }
+
+func TestCreateCommandRunEClosureWithRunAt(t *testing.T) {
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("CreateCheck", mock.Anything).Return(nil)
+
+	cmd := CreateCommand(cli)
+	require.NoError(t, cmd.Flags().Set("command", "echo 'heyhey'"))
+	require.NoError(t, cmd.Flags().Set("subscriptions", "system"))
+	require.NoError(t, cmd.Flags().Set("run-at", "2019-07-01T02:00:00Z,2019-07-02T02:00:00Z"))
+	out, err := test.RunCmd(cmd, []string{"can-holla"})
+	require.NoError(t, err)
+	assert.Regexp(t, "Created", out)
+
+	check := client.Calls[0].Arguments.Get(0).(*types.CheckConfig)
+	assert.Equal(t, []string{"2019-07-01T02:00:00Z", "2019-07-02T02:00:00Z"}, check.RunAt)
+
+	// The run_at times can't be combined with an interval
+	require.NoError(t, cmd.Flags().Set("interval", "10"))
+	_, err = test.RunCmd(cmd, []string{"can-holla"})
+	assert.Error(t, err)
+}
@@ -32,7 +32,12 @@ func DeleteCommand(cli *cli.SensuCli) *cobra.Command {
 				}
 			}
 
-			err := cli.Client.DeleteCheck(namespace, name)
+			deleteCheck := cli.Client.DeleteCheck
+			if resolveEvents, _ := cmd.Flags().GetBool("resolve-events"); resolveEvents {
+				deleteCheck = cli.Client.DeleteCheckAndResolveEvents
+			}
+
+			err := deleteCheck(namespace, name)
 			if err != nil {
 				return err
 			}
@@ -43,6 +48,7 @@ func DeleteCommand(cli *cli.SensuCli) *cobra.Command {
 	}
 
 	cmd.Flags().Bool("skip-confirm", false, "skip interactive confirmation prompt")
+	cmd.Flags().Bool("resolve-events", false, "resolve the events of the check that are not OK and remove its silenced entries")
 
 	return cmd
 }
@@ -52,6 +52,23 @@ func TestDeleteCommandRunEClosureWithFlags(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestDeleteCommandRunEClosureWithResolveEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("DeleteCheckAndResolveEvents", mock.Anything, "my-check").Return(nil)
+
+	cmd := DeleteCommand(cli)
+	require.NoError(t, cmd.Flags().Set("skip-confirm", "t"))
+	require.NoError(t, cmd.Flags().Set("resolve-events", "t"))
+	out, err := test.RunCmd(cmd, []string{"my-check"})
+
+	assert.Regexp("Deleted", out)
+	assert.Nil(err)
+	client.AssertNotCalled(t, "DeleteCheck", mock.Anything, mock.Anything)
+}
+
 func TestDeleteCommandRunEClosureWithServerErr(t *testing.T) {
 	assert := assert.New(t)
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/AlecAivazis/survey"
 	"github.com/robfig/cron"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/sensu/sensu-go/types"
 	"github.com/spf13/pflag"
@@ -22,6 +23,7 @@ type checkOpts struct {
 	Command              string `survey:"command"`
 	Interval             string `survey:"interval"`
 	Cron                 string `survey:"cron"`
+	RunAt                string `survey:"run-at"`
 	Subscriptions        string `survey:"subscriptions"`
 	Handlers             string `survey:"handlers"`
 	RuntimeAssets        string `survey:"assets"`
@@ -52,6 +54,7 @@ func (opts *checkOpts) withCheck(check *types.CheckConfig) {
 	opts.Command = check.Command
 	opts.Interval = strconv.Itoa(int(check.Interval))
 	opts.Cron = check.Cron
+	opts.RunAt = strings.Join(check.RunAt, ",")
 	opts.Subscriptions = strings.Join(check.Subscriptions, ",")
 	opts.Handlers = strings.Join(check.Handlers, ",")
 	opts.RuntimeAssets = strings.Join(check.RuntimeAssets, ",")
@@ -70,6 +73,7 @@ func (opts *checkOpts) withFlags(flags *pflag.FlagSet) {
 	opts.Command, _ = flags.GetString("command")
 	opts.Interval, _ = flags.GetString("interval")
 	opts.Cron, _ = flags.GetString("cron")
+	opts.RunAt, _ = flags.GetString("run-at")
 	opts.Subscriptions, _ = flags.GetString("subscriptions")
 	opts.Handlers, _ = flags.GetString("handlers")
 	opts.RuntimeAssets, _ = flags.GetString("runtime-assets")
@@ -147,6 +151,17 @@ func (opts *checkOpts) administerQuestionnaire(editing bool) error {
 				return nil
 			},
 		},
+		{
+			Name: "run-at",
+			Prompt: &survey.Input{
+				Message: "Run At:",
+				Help:    "Optional comma separated list of times, in RFC 3339 format, at which the check is run once, instead of an interval or a cron schedule.",
+				Default: opts.RunAt,
+			},
+			Validate: func(val interface{}) error {
+				return corev2.ValidateRunAt(helpers.SafeSplitCSV(val.(string)))
+			},
+		},
 		{
 			Name: "timeout",
 			Prompt: &survey.Input{
@@ -278,6 +293,7 @@ func (opts *checkOpts) Copy(check *types.CheckConfig) {
 	check.Interval = uint32(interval)
 	check.Command = opts.Command
 	check.Cron = opts.Cron
+	check.RunAt = helpers.SafeSplitCSV(opts.RunAt)
 	check.Subscriptions = helpers.SafeSplitCSV(opts.Subscriptions)
 	check.Handlers = helpers.SafeSplitCSV(opts.Handlers)
 	check.RuntimeAssets = helpers.SafeSplitCSV(opts.RuntimeAssets)
@@ -19,6 +19,8 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 		MemberRemoveCommand(cli),
 		HealthCommand(cli),
 		IDCommand(cli),
+		LeaderCommand(cli),
+		LeaderTransferCommand(cli),
 	)
 
 	return cmd
@@ -0,0 +1,37 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/spf13/cobra"
+)
+
+// LeaderTransferCommand hands the cluster leadership off to a member by ID
+func LeaderTransferCommand(cli *cli.SensuCli) *cobra.Command {
+	return &cobra.Command{
+		Use:          "leader-transfer [ID]",
+		Short:        "hand the cluster leadership off to a member by ID",
+		SilenceUsage: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			memberID := args[0]
+			id, err := strconv.ParseUint(memberID, 16, 64)
+			if err != nil {
+				return fmt.Errorf("invalid id: %s", err)
+			}
+
+			if err := cli.Client.TransferLeadership(id); err != nil {
+				return fmt.Errorf("error transferring the cluster leadership: %s", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Transferred the cluster leadership to member %x\n", id)
+			return nil
+		},
+	}
+}
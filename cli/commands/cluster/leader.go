@@ -0,0 +1,166 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/elements/table"
+	"github.com/spf13/cobra"
+)
+
+// LeaderCommand shows the leadership of the cluster
+func LeaderCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "leader",
+		Short:        "show the cluster leadership",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+			result, err := cli.Client.FetchClusterLeadership()
+			if err != nil {
+				return fmt.Errorf("error fetching the cluster leadership: %s", err)
+			}
+			title := fmt.Sprintf("Leader: %x (%s), Raft Term: %d", result.Leader, result.LeaderName, result.RaftTerm)
+			if result.Leader == 0 {
+				title = "Leader: none"
+			}
+			err = helpers.PrintTitle(helpers.GetChangedStringValueFlag("format", cmd.Flags()), cli.Config.Format(), title, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+			return helpers.Print(cmd, cli.Config.Format(), printLeadershipToTable, nil, result)
+		},
+	}
+
+	helpers.AddFormatFlag(cmd.Flags())
+
+	return cmd
+}
+
+func printLeadershipToTable(result interface{}, w io.Writer) {
+	leadership, ok := result.(*corev2.ClusterLeadership)
+	if !ok {
+		return
+	}
+	table := table.New([]*table.Column{
+		{
+			Title:       "ID",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				member, ok := data.(*corev2.MemberLeadership)
+				if !ok {
+					return cli.TypeError
+				}
+				return fmt.Sprintf("%x", member.MemberID)
+			},
+		},
+		{
+			Title:       "Name",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				member, ok := data.(*corev2.MemberLeadership)
+				if !ok {
+					return cli.TypeError
+				}
+				return member.Name
+			},
+		},
+		{
+			Title: "Known Leader",
+			CellTransformer: func(data interface{}) string {
+				member, ok := data.(*corev2.MemberLeadership)
+				if !ok {
+					return cli.TypeError
+				}
+				if member.Leader == 0 {
+					return ""
+				}
+				return fmt.Sprintf("%x", member.Leader)
+			},
+		},
+		{
+			Title: "Error",
+			CellTransformer: func(data interface{}) string {
+				member, ok := data.(*corev2.MemberLeadership)
+				if !ok {
+					return cli.TypeError
+				}
+				return member.Err
+			},
+		},
+	})
+
+	table.Render(w, leadership.Members)
+
+	if len(leadership.Rings) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	printRingsToTable(leadership.Rings, w)
+}
+
+func printRingsToTable(rings []*corev2.RingOwnership, w io.Writer) {
+	table := table.New([]*table.Column{
+		{
+			Title:       "Namespace",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				ring, ok := data.(*corev2.RingOwnership)
+				if !ok {
+					return cli.TypeError
+				}
+				return ring.Namespace
+			},
+		},
+		{
+			Title:       "Ring",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				ring, ok := data.(*corev2.RingOwnership)
+				if !ok {
+					return cli.TypeError
+				}
+				return ring.Ring
+			},
+		},
+		{
+			Title: "Watcher",
+			CellTransformer: func(data interface{}) string {
+				ring, ok := data.(*corev2.RingOwnership)
+				if !ok {
+					return cli.TypeError
+				}
+				return ring.Watcher
+			},
+		},
+		{
+			Title: "Owner",
+			CellTransformer: func(data interface{}) string {
+				ring, ok := data.(*corev2.RingOwnership)
+				if !ok {
+					return cli.TypeError
+				}
+				return ring.Owner
+			},
+		},
+		{
+			Title: "Lease",
+			CellTransformer: func(data interface{}) string {
+				ring, ok := data.(*corev2.RingOwnership)
+				if !ok {
+					return cli.TypeError
+				}
+				return fmt.Sprintf("%x (TTL %ds)", ring.LeaseID, ring.TTL)
+			},
+		},
+	})
+
+	table.Render(w, rings)
+}
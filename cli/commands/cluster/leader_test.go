@@ -0,0 +1,66 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderCommand(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("FetchClusterLeadership").Return(&corev2.ClusterLeadership{
+		Leader:     0xbc614e,
+		LeaderName: "foo",
+		RaftTerm:   2,
+		Members: []*corev2.MemberLeadership{
+			{MemberID: 0xbc614e, Name: "foo", Leader: 0xbc614e, IsLeader: true},
+			{MemberID: 0x2875a52, Name: "bar", Err: "connection refused"},
+		},
+		Rings: []*corev2.RingOwnership{
+			{Namespace: "global", Ring: "backends", Watcher: "tessen", Schedule: "1800", Owner: "backend-uuid", LeaseID: 0x694d, TTL: 42},
+		},
+	}, nil)
+
+	cmd := LeaderCommand(cli)
+	require.NoError(t, cmd.Flags().Set("format", "tabular"))
+	out, err := test.RunCmd(cmd, []string{})
+	require.NoError(t, err)
+	assert.Contains(t, out, "Leader: bc614e (foo), Raft Term: 2")
+	assert.Contains(t, out, "connection refused")
+	assert.Contains(t, out, "backend-uuid")
+	assert.Contains(t, out, "694d (TTL 42s)")
+}
+
+func TestLeaderCommandWithErr(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("FetchClusterLeadership").Return((*corev2.ClusterLeadership)(nil), errors.New("err"))
+
+	cmd := LeaderCommand(cli)
+	_, err := test.RunCmd(cmd, []string{})
+	assert.Error(t, err)
+}
+
+func TestLeaderTransferCommand(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("TransferLeadership", uint64(0xbc614e)).Return(nil)
+
+	cmd := LeaderTransferCommand(cli)
+	out, err := test.RunCmd(cmd, []string{"bc614e"})
+	require.NoError(t, err)
+	assert.Contains(t, out, "Transferred the cluster leadership to member bc614e")
+}
+
+func TestLeaderTransferCommandInvalidID(t *testing.T) {
+	cli := test.NewCLI()
+	cmd := LeaderTransferCommand(cli)
+	_, err := test.RunCmd(cmd, []string{"not-an-id"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client/config"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/nagios"
+	"github.com/spf13/cobra"
+)
+
+// NagiosCommand converts the object definitions of Nagios to Sensu resources.
+func NagiosCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "nagios -f FILE [-f FILE]...",
+		Short:        "convert the object definitions of Nagios or Icinga 1.x to checks, handlers and entities",
+		SilenceUsage: true,
+		RunE:         executeNagios(cli),
+	}
+
+	_ = cmd.Flags().StringArrayP("file", "f", nil, "object configuration file to convert, e.g. objects.cfg")
+	_ = cmd.Flags().StringP("resource-file", "", "", "resource file defining the $USERn$ macros, e.g. resource.cfg")
+	_ = cmd.Flags().IntP("interval-length", "", nagios.DefaultIntervalLength, "number of seconds of an interval unit, the interval_length of nagios.cfg")
+	_ = cmd.Flags().StringP("format", "", cli.Config.Format(), fmt.Sprintf(`format of the resources ("%s"|"%s")`, config.FormatWrappedJSON, config.FormatYAML))
+
+	return cmd
+}
+
+func executeNagios(cli *cli.SensuCli) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			_ = cmd.Help()
+			return errors.New("invalid argument(s) received")
+		}
+
+		files, err := cmd.Flags().GetStringArray("file")
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			_ = cmd.Help()
+			return errors.New("at least one object configuration file is required")
+		}
+
+		var objects []*nagios.Object
+		for _, file := range files {
+			parsed, err := parseNagiosFile(file)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, parsed...)
+		}
+
+		options := nagios.Options{Namespace: cli.Config.Namespace()}
+		if options.IntervalLength, err = cmd.Flags().GetInt("interval-length"); err != nil {
+			return err
+		}
+		resourceFile, err := cmd.Flags().GetString("resource-file")
+		if err != nil {
+			return err
+		}
+		if resourceFile != "" {
+			f, err := os.Open(resourceFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if options.Macros, err = nagios.ParseResources(f, resourceFile); err != nil {
+				return err
+			}
+		}
+
+		result, err := nagios.Convert(objects, options)
+		if err != nil {
+			return err
+		}
+
+		// the report is written apart from the resources, so they can be
+		// piped to sensuctl create
+		for _, entry := range result.Report {
+			if entry.Source != "" {
+				fmt.Fprintf(cmd.OutOrStderr(), "%s (%s): %s\n", entry.Object, entry.Source, entry.Message)
+			} else {
+				fmt.Fprintf(cmd.OutOrStderr(), "%s: %s\n", entry.Object, entry.Message)
+			}
+		}
+
+		format := cli.Config.Format()
+		if flag := helpers.GetChangedStringValueFlag("format", cmd.Flags()); flag != "" {
+			format = flag
+		}
+		if format == config.FormatWrappedJSON {
+			return helpers.PrintWrappedJSONList(result.Resources, cmd.OutOrStdout())
+		}
+		return helpers.PrintYAML(result.Resources, cmd.OutOrStdout())
+	}
+}
+
+func parseNagiosFile(path string) ([]*nagios.Object, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return nagios.Parse(f, path)
+}
@@ -0,0 +1,83 @@
+package convert
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNagiosCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewCLI()
+	cmd := NagiosCommand(cli)
+
+	assert.NotNil(cmd, "cmd should be returned")
+	assert.NotNil(cmd.RunE, "cmd should be able to be executed")
+	assert.Regexp("nagios", cmd.Use)
+	assert.Regexp("convert the object definitions", cmd.Short)
+
+	for _, name := range []string{"file", "resource-file", "interval-length", "format"} {
+		assert.NotNil(cmd.Flag(name), name)
+	}
+}
+
+func TestNagiosCommandArgs(t *testing.T) {
+	cli := test.NewCLI()
+	cmd := NagiosCommand(cli)
+
+	// the file is required
+	out, err := test.RunCmd(cmd, []string{})
+	assert.NotEmpty(t, out)
+	assert.Error(t, err)
+
+	require.NoError(t, cmd.Flags().Set("file", "missing.cfg"))
+	_, err = test.RunCmd(cmd, []string{})
+	assert.Error(t, err)
+}
+
+func TestNagiosCommandConvert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sensuctl-convert")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	objects := filepath.Join(dir, "objects.cfg")
+	require.NoError(t, ioutil.WriteFile(objects, []byte(`
+define command {
+	command_name  notify-by-email
+	command_line  $USER1$/notify -t $CONTACTEMAIL$
+}
+define host {
+	host_name  web1
+	address    10.0.0.1
+}
+define contact {
+	contact_name                   jdoe
+	email                          jdoe@example.com
+	service_notification_commands  notify-by-email
+}
+define timeperiod {
+	timeperiod_name  24x7
+}
+`), 0644))
+	resources := filepath.Join(dir, "resource.cfg")
+	require.NoError(t, ioutil.WriteFile(resources, []byte("$USER1$=/usr/lib/nagios/plugins\n"), 0644))
+
+	cli := test.NewCLI()
+	cmd := NagiosCommand(cli)
+	require.NoError(t, cmd.Flags().Set("file", objects))
+	require.NoError(t, cmd.Flags().Set("resource-file", resources))
+	out, err := test.RunCmd(cmd, []string{})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "type: Entity")
+	assert.Contains(t, out, "address: 10.0.0.1")
+	assert.Contains(t, out, "type: Handler")
+	assert.Contains(t, out, "command: /usr/lib/nagios/plugins/notify -t jdoe@example.com")
+	assert.Contains(t, out, "timeperiod: 1 timeperiod objects not converted")
+}
@@ -55,6 +55,7 @@ func execute(cli *cli.SensuCli) func(*cobra.Command, []string) error {
 		if err := ValidateResources(resources, cli.Config.Namespace()); err != nil {
 			return err
 		}
+		LintResources(cli.Client, resources, cmd.OutOrStderr())
 		return PutResources(cli.Client, resources)
 	}
 }
@@ -73,26 +74,12 @@ var jsonRe = regexp.MustCompile(`^(\s)*[\{\[]`)
 // 4. Unmarshal the JSON one resource at a time.
 func ParseResources(in io.Reader) ([]types.Wrapper, error) {
 	var resources []types.Wrapper
-	b, err := ioutil.ReadAll(in)
+	documents, err := jsonDocuments(in)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing resources: %s", err)
+		return nil, err
 	}
-	// Support concatenated yaml documents separated by '---'
-	array := bytes.Split(b, []byte("\n---\n"))
 	count := 0
-	for _, b := range array {
-		var jsonBytes []byte
-		if jsonRe.Match(b) {
-			// We are dealing with JSON data
-			jsonBytes = b
-		} else {
-			// We are dealing with YAML data
-			var err error
-			jsonBytes, err = yaml.YAMLToJSON(b)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing resources: %s", err)
-			}
-		}
+	for _, jsonBytes := range documents {
 		dec := json.NewDecoder(bytes.NewReader(jsonBytes))
 		dec.DisallowUnknownFields()
 		errCount := 0
@@ -120,6 +107,52 @@ func ParseResources(in io.Reader) ([]types.Wrapper, error) {
 	return resources, err
 }
 
+// ParseRawResources parses any number of JSON or YAML resources like
+// ParseResources, but returns each resource as raw JSON, which preserves the
+// fields set by the user.
+func ParseRawResources(in io.Reader) ([]json.RawMessage, error) {
+	documents, err := jsonDocuments(in)
+	if err != nil {
+		return nil, err
+	}
+	var resources []json.RawMessage
+	for _, jsonBytes := range documents {
+		dec := json.NewDecoder(bytes.NewReader(jsonBytes))
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return nil, fmt.Errorf("error parsing resources: %s", err)
+			}
+			resources = append(resources, raw)
+		}
+	}
+	return resources, nil
+}
+
+// jsonDocuments reads the JSON or YAML documents of the stream, split on '---'
+// to support concatenated yaml documents, and converts them to JSON.
+func jsonDocuments(in io.Reader) ([][]byte, error) {
+	b, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing resources: %s", err)
+	}
+	var documents [][]byte
+	for _, b := range bytes.Split(b, []byte("\n---\n")) {
+		if jsonRe.Match(b) {
+			// We are dealing with JSON data
+			documents = append(documents, b)
+			continue
+		}
+		// We are dealing with YAML data
+		jsonBytes, err := yaml.YAMLToJSON(b)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing resources: %s", err)
+		}
+		documents = append(documents, jsonBytes)
+	}
+	return documents, nil
+}
+
 // filterCheckSubdue nils out any check subdue fields that are supplied.
 // TODO(echlebek): this is temporary; remove it after fixing check subdue.
 func filterCheckSubdue(resources []types.Wrapper) {
@@ -163,6 +196,25 @@ func ValidateResources(resources []types.Wrapper, namespace string) error {
 	return err
 }
 
+// LintResources prints the best-practice warnings returned by the backend
+// about the checks to create. Warnings are not fatal, and errors are ignored
+// since they will be reported again when putting the resources.
+func LintResources(client client.CheckAPIClient, resources []types.Wrapper, w io.Writer) {
+	for i, r := range resources {
+		check, ok := r.Value.(*types.CheckConfig)
+		if !ok {
+			continue
+		}
+		warnings, err := client.ValidateCheck(check)
+		if err != nil {
+			continue
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(w, "warning: resource %d (%s): %s: %s\n", i, check.URIPath(), warning.Field, warning.Message)
+		}
+	}
+}
+
 func describeError(index int, err error) {
 	jsonErr, ok := err.(*json.UnmarshalTypeError)
 	if !ok {
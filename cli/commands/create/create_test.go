@@ -11,6 +11,7 @@ import (
 	"text/template"
 
 	"github.com/ghodss/yaml"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/types"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -88,6 +89,7 @@ func TestCreateCommand(t *testing.T) {
 	client.On("PutResource", mock.Anything).Return(nil)
 	client.On("PutResource", mock.Anything).Return(nil)
 	client.On("PutResource", mock.Anything).Return(nil)
+	client.On("ValidateCheck", mock.Anything).Return([]corev2.CheckConfigWarning{}, nil)
 
 	cmd := CreateCommand(cli)
 	td, err := ioutil.TempDir("", "")
@@ -118,6 +120,7 @@ func TestCreateCommandYAML(t *testing.T) {
 	client.On("PutResource", mock.Anything).Return(nil)
 	client.On("PutResource", mock.Anything).Return(nil)
 	client.On("PutResource", mock.Anything).Return(nil)
+	client.On("ValidateCheck", mock.Anything).Return([]corev2.CheckConfigWarning{}, nil)
 
 	cmd := CreateCommand(cli)
 	td, err := ioutil.TempDir("", "")
@@ -141,3 +144,24 @@ func TestCreateCommandYAML(t *testing.T) {
 	client.AssertCalled(t, "PutResource", mock.Anything)
 	client.AssertCalled(t, "PutResource", mock.Anything)
 }
+
+func TestLintResources(t *testing.T) {
+	cli := cmdtesting.NewMockCLI()
+	client := cli.Client.(*mockclient.MockClient)
+
+	check := types.FixtureCheckConfig("check1")
+	client.On("ValidateCheck", check).Return([]corev2.CheckConfigWarning{
+		{Field: "handlers", Message: "no handlers are set"},
+	}, nil)
+
+	resources := []types.Wrapper{
+		{Value: types.FixtureAsset("asset1")},
+		{Value: check},
+	}
+
+	var buf bytes.Buffer
+	LintResources(client, resources, &buf)
+
+	client.AssertNumberOfCalls(t, "ValidateCheck", 1)
+	require.Equal(t, "warning: resource 1 (/api/core/v2/namespaces/default/checks/check1): handlers: no handlers are set\n", buf.String())
+}
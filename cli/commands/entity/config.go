@@ -0,0 +1,165 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/elements/list"
+	"github.com/spf13/cobra"
+)
+
+// ConfigCommand defines the command group managing the configuration pushed by
+// the backend to the agents
+func ConfigCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration pushed by the backend to an entity's agent",
+	}
+
+	cmd.AddCommand(
+		configShowCommand(cli),
+		configSetCommand(cli),
+		configResetCommand(cli),
+	)
+
+	return cmd
+}
+
+func configShowCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "show [NAME]",
+		Short:        "show the configuration pushed to an entity's agent",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+			name := args[0]
+
+			config, err := cli.Client.FetchAgentConfig(cli.Config.Namespace(), name)
+			if err != nil {
+				return err
+			}
+
+			flag := helpers.GetChangedStringValueFlag("format", cmd.Flags())
+			format := cli.Config.Format()
+			return helpers.PrintFormatted(flag, format, config, cmd.OutOrStdout(), printAgentConfigToList(name))
+		},
+	}
+
+	helpers.AddFormatFlag(cmd.Flags())
+
+	return cmd
+}
+
+func configSetCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "set [NAME]",
+		Short:        "replace the configuration pushed to an entity's agent",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+			name := args[0]
+
+			config := &corev2.AgentConfig{}
+			config.LogLevel, _ = cmd.Flags().GetString("log-level")
+			config.KeepaliveInterval, _ = cmd.Flags().GetUint32("keepalive-interval")
+			config.Labels, _ = cmd.Flags().GetStringToString("label")
+			config.Annotations, _ = cmd.Flags().GetStringToString("annotation")
+			if err := config.Validate(); err != nil {
+				return err
+			}
+
+			if err := cli.Client.UpdateAgentConfig(cli.Config.Namespace(), name, config); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Updated")
+			return nil
+		},
+	}
+
+	cmd.Flags().String("log-level", "", "log level of the agent ["+strings.Join(corev2.AgentConfigLogLevels, ", ")+"]")
+	cmd.Flags().Uint32("keepalive-interval", 0, "number of seconds between the keepalives of the agent")
+	cmd.Flags().StringToString("label", nil, "label added to the entity of the agent, in the form key=value (can be repeated)")
+	cmd.Flags().StringToString("annotation", nil, "annotation added to the entity of the agent, in the form key=value (can be repeated)")
+
+	return cmd
+}
+
+func configResetCommand(cli *cli.SensuCli) *cobra.Command {
+	return &cobra.Command{
+		Use:          "reset [NAME]",
+		Short:        "revert an entity's agent to its own configuration",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			if err := cli.Client.DeleteAgentConfig(cli.Config.Namespace(), args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Reset")
+			return nil
+		},
+	}
+}
+
+func printAgentConfigToList(name string) func(interface{}, io.Writer) error {
+	return func(v interface{}, writer io.Writer) error {
+		config, ok := v.(*corev2.AgentConfig)
+		if !ok {
+			return fmt.Errorf("%T is not an AgentConfig", v)
+		}
+		keepaliveInterval := ""
+		if config.KeepaliveInterval > 0 {
+			keepaliveInterval = strconv.FormatUint(uint64(config.KeepaliveInterval), 10)
+		}
+		cfg := &list.Config{
+			Title: name,
+			Rows: []*list.Row{
+				{
+					Label: "Log Level",
+					Value: config.LogLevel,
+				},
+				{
+					Label: "Keepalive Interval",
+					Value: keepaliveInterval,
+				},
+				{
+					Label: "Labels",
+					Value: joinKeyValues(config.Labels),
+				},
+				{
+					Label: "Annotations",
+					Value: joinKeyValues(config.Annotations),
+				},
+			},
+		}
+
+		return list.Print(writer, cfg)
+	}
+}
+
+func joinKeyValues(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigShowCommand(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	config := &corev2.AgentConfig{
+		LogLevel: "debug",
+		Labels:   map[string]string{"region": "us-west-2", "team": "ops"},
+	}
+	client.On("FetchAgentConfig", "default", "entity1").Return(config, nil)
+
+	cmd := configSubcommand(t, cli, "show")
+	require.NoError(t, cmd.Flags().Set("format", "tabular"))
+	out, err := test.RunCmd(cmd, []string{"entity1"})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "debug")
+	assert.Contains(t, out, "region=us-west-2, team=ops")
+}
+
+func TestConfigSetCommand(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	expected := &corev2.AgentConfig{
+		LogLevel:          "info",
+		KeepaliveInterval: 10,
+		Labels:            map[string]string{"region": "us-west-2"},
+		Annotations:       map[string]string{},
+	}
+	client.On("UpdateAgentConfig", "default", "entity1", expected).Return(nil)
+
+	cmd := configSubcommand(t, cli, "set")
+	require.NoError(t, cmd.Flags().Set("log-level", "info"))
+	require.NoError(t, cmd.Flags().Set("keepalive-interval", "10"))
+	require.NoError(t, cmd.Flags().Set("label", "region=us-west-2"))
+	out, err := test.RunCmd(cmd, []string{"entity1"})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Updated")
+}
+
+func TestConfigSetCommandInvalid(t *testing.T) {
+	cli := test.NewCLI()
+	cmd := configSubcommand(t, cli, "set")
+	require.NoError(t, cmd.Flags().Set("log-level", "verbose"))
+	_, err := test.RunCmd(cmd, []string{"entity1"})
+	assert.Error(t, err)
+}
+
+func TestConfigResetCommandError(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("DeleteAgentConfig", "default", "entity1").Return(errors.New("not found"))
+
+	cmd := configSubcommand(t, cli, "reset")
+	_, err := test.RunCmd(cmd, []string{"entity1"})
+	assert.Error(t, err)
+}
+
+func configSubcommand(t *testing.T, cli *cli.SensuCli, name string) *cobra.Command {
+	cmd, _, err := ConfigCommand(cli).Find([]string{name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/elements/list"
+	"github.com/spf13/cobra"
+)
+
+// ExecCommand executes an allow-listed command on the agent of an entity
+func ExecCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "exec [NAME] [COMMAND]",
+		Short:        "execute a command on an entity's agent, the result is sent as an event",
+		Example:      "sensuctl entity exec web01 -- df -h",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+			name := args[0]
+
+			timeout, err := cmd.Flags().GetUint32("timeout")
+			if err != nil {
+				return err
+			}
+			command := &corev2.AgentCommand{
+				Command: strings.Join(args[1:], " "),
+				Timeout: timeout,
+			}
+
+			result, err := cli.Client.ExecuteEntityCommand(cli.Config.Namespace(), name, command)
+			if err != nil {
+				return err
+			}
+
+			flag := helpers.GetChangedStringValueFlag("format", cmd.Flags())
+			format := cli.Config.Format()
+			return helpers.PrintFormatted(flag, format, result, cmd.OutOrStdout(), printCommandResultToList)
+		},
+	}
+
+	cmd.Flags().Uint32("timeout", corev2.DefaultAgentCommandTimeout, "timeout of the command, in seconds")
+	helpers.AddFormatFlag(cmd.Flags())
+
+	return cmd
+}
+
+func printCommandResultToList(v interface{}, writer io.Writer) error {
+	result, ok := v.(*corev2.AgentCommandResult)
+	if !ok {
+		return fmt.Errorf("%T is not an AgentCommandResult", v)
+	}
+	cfg := &list.Config{
+		Title: result.ID,
+		Rows: []*list.Row{
+			{
+				Label: "Entity",
+				Value: result.Entity,
+			},
+			{
+				Label: "Event",
+				Value: fmt.Sprintf("%s/%s", result.Entity, result.Check),
+			},
+		},
+	}
+
+	return list.Print(writer, cfg)
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecCommand(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	command := &corev2.AgentCommand{Command: "df -h", Timeout: 5}
+	result := &corev2.AgentCommandResult{ID: "42", Entity: "entity1", Check: corev2.AgentCommandCheckName}
+	client.On("ExecuteEntityCommand", "default", "entity1", command).Return(result, nil)
+
+	cmd := ExecCommand(cli)
+	require.NoError(t, cmd.Flags().Set("timeout", "5"))
+	require.NoError(t, cmd.Flags().Set("format", "tabular"))
+	out, err := test.RunCmd(cmd, []string{"entity1", "df", "-h"})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "entity1/remote-command")
+}
+
+func TestExecCommandDenied(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	command := &corev2.AgentCommand{Command: "rm -rf /", Timeout: corev2.DefaultAgentCommandTimeout}
+	client.On("ExecuteEntityCommand", "default", "entity1", command).Return((*corev2.AgentCommandResult)(nil), errors.New("the agent denied the command"))
+
+	cmd := ExecCommand(cli)
+	_, err := test.RunCmd(cmd, []string{"entity1", "rm -rf /"})
+	assert.Error(t, err)
+}
+
+func TestExecCommandMissingArgs(t *testing.T) {
+	cli := test.NewCLI()
+	cmd := ExecCommand(cli)
+	out, err := test.RunCmd(cmd, []string{"entity1"})
+	assert.Error(t, err)
+	assert.Contains(t, out, "Usage")
+}
@@ -14,10 +14,14 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 
 	// Add sub-commands
 	cmd.AddCommand(
+		ConfigCommand(cli),
 		CreateCommand(cli),
 		DeleteCommand(cli),
+		ExecCommand(cli),
 		ListCommand(cli),
 		InfoCommand(cli),
+		RenameCommand(cli),
+		SpoolCommand(cli),
 		UpdateCommand(cli),
 	)
 
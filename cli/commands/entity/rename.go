@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/spf13/cobra"
+)
+
+// RenameCommand renames an entity, or merges it into an existing entity,
+// keeping its events and silenced entries
+func RenameCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "rename [NAME] [NEW-NAME]",
+		Short:        "rename an entity, keeping its events and silenced entries",
+		Example:      "sensuctl entity rename web01 web01.example.com --merge",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			merge, err := cmd.Flags().GetBool("merge")
+			if err != nil {
+				return err
+			}
+			rename := &corev2.EntityRename{Name: args[1], Merge: merge}
+
+			if _, err := cli.Client.RenameEntity(cli.Config.Namespace(), args[0], rename); err != nil {
+				return err
+			}
+
+			if merge {
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), "Merged")
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), "Renamed")
+			return err
+		},
+	}
+
+	cmd.Flags().Bool("merge", false, "merge the entity into the entity of the new name if it exists")
+
+	return cmd
+}
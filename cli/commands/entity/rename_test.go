@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameCommand(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	rename := &corev2.EntityRename{Name: "web01.example.com"}
+	client.On("RenameEntity", "default", "web01", rename).Return(corev2.FixtureEntity("web01.example.com"), nil)
+
+	cmd := RenameCommand(cli)
+	out, err := test.RunCmd(cmd, []string{"web01", "web01.example.com"})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Renamed")
+}
+
+func TestRenameCommandMerge(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	rename := &corev2.EntityRename{Name: "web01.example.com", Merge: true}
+	client.On("RenameEntity", "default", "web01", rename).Return(corev2.FixtureEntity("web01.example.com"), nil)
+
+	cmd := RenameCommand(cli)
+	require.NoError(t, cmd.Flags().Set("merge", "true"))
+	out, err := test.RunCmd(cmd, []string{"web01", "web01.example.com"})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Merged")
+}
+
+func TestRenameCommandError(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	rename := &corev2.EntityRename{Name: "web02"}
+	client.On("RenameEntity", "default", "web01", rename).Return((*corev2.Entity)(nil), errors.New("entity web02 already exists"))
+
+	cmd := RenameCommand(cli)
+	_, err := test.RunCmd(cmd, []string{"web01", "web02"})
+	assert.Error(t, err)
+}
+
+func TestRenameCommandMissingArgs(t *testing.T) {
+	cli := test.NewCLI()
+	cmd := RenameCommand(cli)
+	out, err := test.RunCmd(cmd, []string{"web01"})
+	assert.Error(t, err)
+	assert.Contains(t, out, "Usage")
+}
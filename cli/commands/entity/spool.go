@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/sensu/sensu-go/cli/elements/list"
+	"github.com/spf13/cobra"
+)
+
+// spoolFunc performs an action on the spool of an entity's agent
+type spoolFunc func(client client.EntityAPIClient, namespace, name string) (*corev2.AgentSpoolStats, error)
+
+// SpoolCommand defines the command group managing the event spool of agents
+func SpoolCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "spool",
+		Short: "Manage the event spool of an entity's agent",
+	}
+
+	cmd.AddCommand(
+		spoolActionCommand(cli, "info [NAME]", "show the spool statistics of an entity's agent", "", client.EntityAPIClient.FetchEntitySpool),
+		spoolActionCommand(cli, "flush [NAME]", "transmit the spooled events of an entity's agent without rate limiting", "", client.EntityAPIClient.FlushEntitySpool),
+		spoolActionCommand(cli, "purge [NAME]", "discard the spooled events of an entity's agent", "purge", client.EntityAPIClient.PurgeEntitySpool),
+	)
+
+	return cmd
+}
+
+// spoolActionCommand returns a command performing a spool action. If confirm
+// is not empty, the action must be confirmed interactively.
+func spoolActionCommand(cli *cli.SensuCli, use, short, confirm string, fn spoolFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          use,
+		Short:        short,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+			name := args[0]
+
+			if confirm != "" {
+				if skipConfirm, _ := cmd.Flags().GetBool("skip-confirm"); !skipConfirm {
+					op := &helpers.ConfirmDestructiveOp{Type: "the spool of entity", Op: confirm}
+					if confirmed, _ := op.Ask(name); !confirmed {
+						fmt.Fprintln(cmd.OutOrStdout(), "Canceled")
+						return nil
+					}
+				}
+			}
+
+			stats, err := fn(cli.Client, cli.Config.Namespace(), name)
+			if err != nil {
+				return err
+			}
+
+			flag := helpers.GetChangedStringValueFlag("format", cmd.Flags())
+			format := cli.Config.Format()
+			return helpers.PrintFormatted(flag, format, stats, cmd.OutOrStdout(), printSpoolToList(name))
+		},
+	}
+
+	helpers.AddFormatFlag(cmd.Flags())
+	if confirm != "" {
+		cmd.Flags().Bool("skip-confirm", false, "skip interactive confirmation prompt")
+	}
+
+	return cmd
+}
+
+func printSpoolToList(name string) func(interface{}, io.Writer) error {
+	return func(v interface{}, writer io.Writer) error {
+		stats, ok := v.(*corev2.AgentSpoolStats)
+		if !ok {
+			return fmt.Errorf("%T is not an AgentSpoolStats", v)
+		}
+		cfg := &list.Config{
+			Title: name,
+			Rows: []*list.Row{
+				{
+					Label: "Spooled Events",
+					Value: strconv.Itoa(stats.Messages),
+				},
+				{
+					Label: "Spool Size (bytes)",
+					Value: strconv.Itoa(stats.Bytes),
+				},
+				{
+					Label: "Events Being Flushed",
+					Value: strconv.Itoa(stats.Flushing),
+				},
+			},
+		}
+		if stats.Purged > 0 {
+			cfg.Rows = append(cfg.Rows, &list.Row{
+				Label: "Purged Events",
+				Value: strconv.Itoa(stats.Purged),
+			})
+		}
+
+		return list.Print(writer, cfg)
+	}
+}
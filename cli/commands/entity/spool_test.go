@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpoolInfoCommand(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("FetchEntitySpool", "default", "entity1").Return(&corev2.AgentSpoolStats{Messages: 12}, nil)
+
+	cmd := spoolSubcommand(t, cli, "info")
+	require.NoError(t, cmd.Flags().Set("format", "tabular"))
+	out, err := test.RunCmd(cmd, []string{"entity1"})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "entity1")
+	assert.Contains(t, out, "12")
+}
+
+func TestSpoolFlushCommandError(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("FlushEntitySpool", "default", "entity1").Return((*corev2.AgentSpoolStats)(nil), errors.New("agent unreachable"))
+
+	cmd := spoolSubcommand(t, cli, "flush")
+	_, err := test.RunCmd(cmd, []string{"entity1"})
+	assert.Error(t, err)
+}
+
+func TestSpoolPurgeCommand(t *testing.T) {
+	cli := test.NewCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("PurgeEntitySpool", "default", "entity1").Return(&corev2.AgentSpoolStats{Purged: 7}, nil)
+
+	cmd := spoolSubcommand(t, cli, "purge")
+	require.NoError(t, cmd.Flags().Set("skip-confirm", "t"))
+	require.NoError(t, cmd.Flags().Set("format", "tabular"))
+	out, err := test.RunCmd(cmd, []string{"entity1"})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Purged Events")
+}
+
+func TestSpoolCommandMissingArgs(t *testing.T) {
+	cli := test.NewCLI()
+	cmd := spoolSubcommand(t, cli, "info")
+	out, err := test.RunCmd(cmd, []string{})
+	assert.Error(t, err)
+	assert.Contains(t, out, "Usage")
+}
+
+func spoolSubcommand(t *testing.T, cli *cli.SensuCli, name string) *cobra.Command {
+	cmd, _, err := SpoolCommand(cli).Find([]string{name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
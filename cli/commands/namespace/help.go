@@ -17,6 +17,8 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 		CreateCommand(cli),
 		DeleteCommand(cli),
 		ListCommand(cli),
+		SetDefaultHandlersCommand(cli),
+		RemoveDefaultHandlersCommand(cli),
 	)
 
 	return cmd
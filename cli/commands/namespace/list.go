@@ -3,6 +3,7 @@ package namespace
 import (
 	"errors"
 	"io"
+	"strings"
 
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
@@ -65,6 +66,16 @@ func printToTable(results interface{}, writer io.Writer) {
 				return namespace.Name
 			},
 		},
+		{
+			Title: "Default Handlers",
+			CellTransformer: func(data interface{}) string {
+				namespace, ok := data.(types.Namespace)
+				if !ok {
+					return cli.TypeError
+				}
+				return strings.Join(namespace.DefaultHandlers, ",")
+			},
+		},
 	})
 
 	table.Render(writer, results)
@@ -0,0 +1,74 @@
+package namespace
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/spf13/cobra"
+)
+
+// SetDefaultHandlersCommand updates the default handlers of a namespace, the
+// handlers of its events whose check has no handlers
+func SetDefaultHandlersCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "set-default-handlers [NAME] [VALUE]",
+		Short:        "set the default handlers of a namespace",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			namespace, err := cli.Client.FetchNamespace(args[0])
+			if err != nil {
+				return err
+			}
+			namespace.DefaultHandlers = helpers.SafeSplitCSV(args[1])
+
+			if err := namespace.Validate(); err != nil {
+				return err
+			}
+			if err := cli.Client.UpdateNamespace(namespace); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Updated")
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// RemoveDefaultHandlersCommand removes the default handlers of a namespace
+func RemoveDefaultHandlersCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "remove-default-handlers [NAME]",
+		Short:        "remove the default handlers of a namespace",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			namespace, err := cli.Client.FetchNamespace(args[0])
+			if err != nil {
+				return err
+			}
+			namespace.DefaultHandlers = nil
+
+			if err := cli.Client.UpdateNamespace(namespace); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Removed")
+			return nil
+		},
+	}
+
+	return cmd
+}
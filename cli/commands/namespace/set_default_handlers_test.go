@@ -0,0 +1,72 @@
+package namespace
+
+import (
+	"fmt"
+	"testing"
+
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/sensu/sensu-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetDefaultHandlersCommand(t *testing.T) {
+	testCases := []struct {
+		testName       string
+		args           []string
+		fetchResponse  error
+		updateResponse error
+		expectedOutput string
+		expectError    bool
+	}{
+		{"no args", []string{}, nil, nil, "Usage", true},
+		{"fetch error", []string{"dev", "foo"}, fmt.Errorf("error"), nil, "", true},
+		{"update error", []string{"dev", "bar"}, nil, fmt.Errorf("error"), "", true},
+		{"invalid input", []string{"dev"}, nil, nil, "", true},
+		{"invalid handler", []string{"dev", "slack,"}, nil, nil, "", true},
+		{"valid input", []string{"dev", "slack,pagerduty"}, nil, nil, "Updated", false},
+	}
+
+	for _, tc := range testCases {
+		var name string
+		if len(tc.args) > 0 {
+			name = tc.args[0]
+		}
+
+		t.Run(tc.testName, func(t *testing.T) {
+			namespace := types.FixtureNamespace("dev")
+			cli := test.NewMockCLI()
+
+			client := cli.Client.(*client.MockClient)
+			client.On("FetchNamespace", name).Return(namespace, tc.fetchResponse)
+			client.On("UpdateNamespace", mock.Anything).Return(tc.updateResponse)
+
+			cmd := SetDefaultHandlersCommand(cli)
+			out, err := test.RunCmd(cmd, tc.args)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"slack", "pagerduty"}, namespace.DefaultHandlers)
+			}
+
+			assert.Regexp(t, tc.expectedOutput, out)
+		})
+	}
+}
+
+func TestRemoveDefaultHandlersCommand(t *testing.T) {
+	namespace := types.FixtureNamespace("dev")
+	namespace.DefaultHandlers = []string{"slack"}
+	cli := test.NewMockCLI()
+
+	client := cli.Client.(*client.MockClient)
+	client.On("FetchNamespace", "dev").Return(namespace, nil)
+	client.On("UpdateNamespace", mock.Anything).Return(nil)
+
+	out, err := test.RunCmd(RemoveDefaultHandlersCommand(cli), []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Removed\n", out)
+	assert.Empty(t, namespace.DefaultHandlers)
+}
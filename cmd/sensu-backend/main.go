@@ -19,7 +19,9 @@ func main() {
 		Use:   "sensu-backend",
 		Short: "sensu backend",
 	}
-	rootCmd.AddCommand(cmd.StartCommand(backend.Initialize))
+	startCmd := cmd.StartCommand(backend.Initialize)
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(cmd.PreflightCommand(startCmd))
 	rootCmd.AddCommand(cmd.VersionCommand())
 
 	if err := rootCmd.Execute(); err != nil {
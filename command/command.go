@@ -4,8 +4,9 @@ package command
 import (
 	"bytes"
 	"context"
+	"io"
+	"os"
 	"os/exec"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -16,6 +17,10 @@ import (
 
 const undocumentedTestCheckCommand = "!sensu_test_check!"
 
+// outputDrainTimeout is the time the output of a command is read after its
+// processes were killed.
+var outputDrainTimeout = time.Second
+
 const cannedResponseText = `
                          .'loo:,
                         ,KNMMWNWX
@@ -83,6 +88,16 @@ type ExecutionRequest struct {
 	// not specified.
 	Timeout int
 
+	// GracePeriod is the time given to the command processes to exit after
+	// being terminated on timeout, before they are killed. The processes are
+	// killed right away if it is not specified.
+	GracePeriod time.Duration
+
+	// KillProcessGroup runs the command in its own process group, and kills
+	// the processes of the group that are still running once the command
+	// exits, so they don't leak.
+	KillProcessGroup bool
+
 	// Name is the name of the resource that is invoking the execution.
 	Name string
 
@@ -104,6 +119,17 @@ type ExecutionResponse struct {
 
 	// Duration provides command execution time in seconds.
 	Duration float64
+
+	// TimedOut is true if the command execution timed out.
+	TimedOut bool
+
+	// Leaked is true if processes of the command were still running after
+	// it exited, and had to be killed.
+	Leaked bool
+
+	// Zombie is true if the command processes could not be killed after the
+	// command execution timed out.
+	Zombie bool
 }
 
 // NewExecutor ...
@@ -143,16 +169,29 @@ func (e *ExecutionRequest) Execute(ctx context.Context, execution ExecutionReque
 		cmd.Env = execution.Env
 	}
 
-	// Share an output buffer between STDOUT/ERR, following the
-	// Nagios plugin spec.
+	// Share an output pipe between STDOUT/ERR, following the
+	// Nagios plugin spec. The pipes are handled here rather than by
+	// exec, so that waiting for the command doesn't wait for the
+	// processes it left behind, which may still hold them.
 	var output bytes.Buffer
-
-	cmd.Stdout = &output
-	cmd.Stderr = &output
+	outputReader, outputWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer outputReader.Close()
+	cmd.Stdout = outputWriter
+	cmd.Stderr = outputWriter
 
 	// If Input is specified, write to STDIN.
+	var inputReader, inputWriter *os.File
 	if execution.Input != "" {
-		cmd.Stdin = strings.NewReader(execution.Input)
+		inputReader, inputWriter, err = os.Pipe()
+		if err != nil {
+			_ = outputWriter.Close()
+			return nil, err
+		}
+		defer inputWriter.Close()
+		cmd.Stdin = inputReader
 	}
 
 	started := time.Now()
@@ -160,35 +199,78 @@ func (e *ExecutionRequest) Execute(ctx context.Context, execution ExecutionReque
 		resp.Duration = time.Since(started).Seconds()
 	}()
 
-	var timer *time.Timer
 	// Kill process and all of its children when the timeout has expired.
+	var timer <-chan time.Time
 	if execution.Timeout != 0 {
+		t := time.NewTimer(time.Duration(execution.Timeout) * time.Second)
+		defer t.Stop()
+		timer = t.C
+	}
+	if execution.Timeout != 0 || execution.KillProcessGroup {
 		SetProcessGroup(cmd)
-		timer = time.AfterFunc(time.Duration(execution.Timeout)*time.Second, func() {
-			timeout()
-			if err := KillProcess(cmd); err != nil {
-				logger.WithError(err).Errorf("Execution timed out - Unable to TERM/KILL the process: #%d", cmd.Process.Pid)
-				escapeZombie(&execution)
-			}
-		})
-		defer timer.Stop()
 	}
 
-	if err := cmd.Start(); err != nil {
+	err = cmd.Start()
+	// The pipe ends of the command are only held by its processes
+	_ = outputWriter.Close()
+	if inputReader != nil {
+		_ = inputReader.Close()
+	}
+	if err != nil {
 		// Something unexpected happended when attepting to
 		// fork/exec, return immediately.
 		return nil, err
 	}
 
-	err := cmd.Wait()
-	if timer != nil {
-		timer.Stop()
+	outputDone := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&output, outputReader)
+		close(outputDone)
+	}()
+	if inputWriter != nil {
+		go func() {
+			_, _ = io.WriteString(inputWriter, execution.Input)
+			_ = inputWriter.Close()
+		}()
+	}
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cmd.Wait()
+	}()
+
+	select {
+	case err = <-waitErr:
+	case <-timer:
+		resp.TimedOut = true
+		err = terminate(cmd, &execution, resp, waitErr, timeout)
+	}
+
+	// Kill the processes left behind by the command, if any
+	if execution.KillProcessGroup && !resp.TimedOut && ProcessGroupAlive(cmd) {
+		resp.Leaked = true
+		if err := KillProcess(cmd); err != nil {
+			logger.WithError(err).Errorf("Unable to KILL the processes left behind by the process: #%d", cmd.Process.Pid)
+		}
 	}
 
+	if resp.TimedOut || resp.Leaked {
+		// The processes of the command were killed, unless they escaped
+		// its process group, so its output is only read for a little longer
+		select {
+		case <-outputDone:
+		case <-time.After(outputDrainTimeout):
+			logger.Warnf("Processes left behind by the process #%d still hold its output, discarding the rest of it", cmd.Process.Pid)
+			_ = outputReader.Close()
+			<-outputDone
+		}
+	} else {
+		<-outputDone
+	}
 	resp.Output = output.String()
 
 	// The command execution timed out if the context was cancelled prematurely
-	if ctx.Err() == context.Canceled {
+	if resp.TimedOut || ctx.Err() == context.Canceled {
 		resp.Output = TimeoutOutput
 		resp.Status = TimeoutExitStatus
 	} else if err != nil {
@@ -212,6 +294,35 @@ func (e *ExecutionRequest) Execute(ctx context.Context, execution ExecutionReque
 	return resp, nil
 }
 
+// terminate stops a command that timed out, giving its processes the grace
+// period of the execution to exit before killing them, and returns the result
+// of waiting for the command.
+func terminate(cmd *exec.Cmd, execution *ExecutionRequest, resp *ExecutionResponse, waitErr <-chan error, cancel context.CancelFunc) error {
+	logger := logrus.WithFields(logrus.Fields{"component": "command"})
+
+	if execution.GracePeriod > 0 {
+		if err := TerminateProcess(cmd); err != nil {
+			logger.WithError(err).Warnf("Execution timed out - Unable to TERM the process: #%d", cmd.Process.Pid)
+		}
+		select {
+		case err := <-waitErr:
+			// Kill the child processes ignoring the termination, if any
+			_ = KillProcess(cmd)
+			return err
+		case <-time.After(execution.GracePeriod):
+		}
+	}
+
+	cancel()
+	if err := KillProcess(cmd); err != nil {
+		logger.WithError(err).Errorf("Execution timed out - Unable to TERM/KILL the process: #%d", cmd.Process.Pid)
+		resp.Zombie = true
+		escapeZombie(execution)
+	}
+
+	return <-waitErr
+}
+
 func escapeZombie(ex *ExecutionRequest) {
 	logger := logrus.WithFields(logrus.Fields{"component": "command"})
 	if ex.InProgress != nil && ex.InProgressMu != nil && ex.Name != "" {
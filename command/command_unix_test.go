@@ -0,0 +1,71 @@
+// +build !windows
+
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteGracePeriod(t *testing.T) {
+	execution := ExecutionRequest{
+		Command:     "trap 'exit 0' TERM; sleep 10 & wait",
+		Timeout:     1,
+		GracePeriod: 5 * time.Second,
+	}
+
+	resp, err := execution.Execute(context.Background(), execution)
+	require.NoError(t, err)
+	assert.True(t, resp.TimedOut)
+	assert.False(t, resp.Zombie)
+	assert.Equal(t, TimeoutExitStatus, resp.Status)
+	assert.True(t, resp.Duration < 5, "the command should exit on SIGTERM")
+}
+
+func TestExecuteKillProcessGroup(t *testing.T) {
+	execution := ExecutionRequest{
+		Command:          "sleep 10 >/dev/null 2>&1 & echo started",
+		KillProcessGroup: true,
+	}
+
+	resp, err := execution.Execute(context.Background(), execution)
+	require.NoError(t, err)
+	assert.Equal(t, "started\n", resp.Output)
+	assert.Equal(t, 0, resp.Status)
+	assert.True(t, resp.Leaked)
+
+	execution.Command = "echo done"
+	resp, err = execution.Execute(context.Background(), execution)
+	require.NoError(t, err)
+	assert.False(t, resp.Leaked)
+}
+
+func TestExecuteKillProcessGroupHoldingOutput(t *testing.T) {
+	execution := ExecutionRequest{
+		Command:          "sleep 10 & echo started",
+		KillProcessGroup: true,
+	}
+
+	resp, err := execution.Execute(context.Background(), execution)
+	require.NoError(t, err)
+	assert.Equal(t, "started\n", resp.Output)
+	assert.True(t, resp.Leaked)
+	assert.True(t, resp.Duration < 5, "the leaked process should not be waited for")
+}
+
+func TestExecuteTimeoutProcessHoldingOutput(t *testing.T) {
+	execution := ExecutionRequest{
+		Command: "sleep 10 & sleep 10",
+		Timeout: 1,
+	}
+
+	resp, err := execution.Execute(context.Background(), execution)
+	require.NoError(t, err)
+	assert.True(t, resp.TimedOut)
+	assert.Equal(t, TimeoutExitStatus, resp.Status)
+	assert.True(t, resp.Duration < 5, "the processes should be killed on timeout")
+}
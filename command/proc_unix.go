@@ -22,3 +22,15 @@ func SetProcessGroup(cmd *exec.Cmd) {
 func KillProcess(cmd *exec.Cmd) error {
 	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
 }
+
+// TerminateProcess asks the command process and any child processes to
+// terminate
+func TerminateProcess(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+// ProcessGroupAlive returns true if processes of the process group of the
+// command are still running
+func ProcessGroupAlive(cmd *exec.Cmd) bool {
+	return syscall.Kill(-cmd.Process.Pid, 0) == nil
+}
@@ -30,3 +30,15 @@ func SetProcessGroup(cmd *exec.Cmd) {
 func KillProcess(cmd *exec.Cmd) error {
 	return cmd.Process.Kill()
 }
+
+// TerminateProcess asks the command process and any child processes to
+// terminate
+func TerminateProcess(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// ProcessGroupAlive returns true if processes of the process group of the
+// command are still running
+func ProcessGroupAlive(cmd *exec.Cmd) bool {
+	return false
+}
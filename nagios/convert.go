@@ -0,0 +1,540 @@
+package nagios
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+const (
+	// DefaultIntervalLength is the number of seconds of a Nagios interval
+	// unit, if interval_length is not set in nagios.cfg.
+	DefaultIntervalLength = 60
+
+	// defaultCheckInterval is the check_interval of the services which don't
+	// set it, in interval units.
+	defaultCheckInterval = 5
+)
+
+// The objects of the definitions, by type.
+const (
+	objectCommand      = "command"
+	objectContact      = "contact"
+	objectContactGroup = "contactgroup"
+	objectHost         = "host"
+	objectHostGroup    = "hostgroup"
+	objectService      = "service"
+)
+
+// handlerFilters are the filters of the handlers the contacts are converted
+// to, so the handlers are notified of the incidents only, like the contacts.
+var handlerFilters = []string{"is_incident", "not_silenced"}
+
+var (
+	// macroRe matches the macros of the commands, e.g. $HOSTADDRESS$.
+	macroRe = regexp.MustCompile(`\$([A-Za-z0-9_]*)\$`)
+
+	// invalidNameRe matches the characters the names of the Sensu resources
+	// can't hold.
+	invalidNameRe = regexp.MustCompile(`[^\w\.\-]+`)
+
+	// argMacroRe matches the macros of the check command arguments.
+	argMacroRe = regexp.MustCompile(`\AARG([0-9]+)\z`)
+)
+
+// hostMacros are the host macros of the commands, translated to the tokens
+// of the entity the checks are executed by.
+var hostMacros = map[string]string{
+	"HOSTNAME":        "{{ .name }}",
+	"HOSTADDRESS":     "{{ .labels.address }}",
+	"HOSTALIAS":       "{{ .labels.alias }}",
+	"HOSTDISPLAYNAME": "{{ .labels.alias }}",
+}
+
+// Options configures the conversion.
+type Options struct {
+	// Namespace is the namespace of the resources.
+	Namespace string
+
+	// IntervalLength is the number of seconds of an interval unit, the
+	// interval_length of nagios.cfg. DefaultIntervalLength if 0.
+	IntervalLength int
+
+	// Macros are the values of the resource macros, e.g. USER1, usually
+	// defined in resource.cfg.
+	Macros map[string]string
+}
+
+// ReportEntry describes how an object was converted, or why it was not.
+type ReportEntry struct {
+	// Object is the type and name of the object, e.g. contact jdoe.
+	Object string `json:"object"`
+
+	// Source is the file and line of the object definition.
+	Source string `json:"source,omitempty"`
+
+	// Message describes the conversion.
+	Message string `json:"message"`
+}
+
+// Result is the result of a conversion.
+type Result struct {
+	// Resources are the Sensu resources: the entities, the handlers and the
+	// checks, in this order.
+	Resources []corev2.Resource
+
+	// Report describes the conversion of the objects.
+	Report []ReportEntry
+}
+
+// converter holds the state of a conversion.
+type converter struct {
+	options  Options
+	objects  map[string][]*Object
+	commands map[string]*Object
+	result   *Result
+}
+
+// Convert converts the object definitions to Sensu resources:
+//   - the hosts to agent entities, subscribed to their host groups
+//   - the services to checks, subscribed to their host groups and hosts
+//   - the contacts to pipe handlers, and the contact groups to handler sets
+//
+// The commands of the checks are translated, with the arguments and the
+// resource macros substituted and the host macros replaced by entity tokens.
+// The objects which can't be converted are reported.
+func Convert(objects []*Object, options Options) (*Result, error) {
+	if options.IntervalLength <= 0 {
+		options.IntervalLength = DefaultIntervalLength
+	}
+	resolved, err := resolveTemplates(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &converter{
+		options:  options,
+		objects:  map[string][]*Object{},
+		commands: map[string]*Object{},
+		result:   &Result{Resources: []corev2.Resource{}, Report: []ReportEntry{}},
+	}
+	for _, object := range resolved {
+		c.objects[object.Type] = append(c.objects[object.Type], object)
+		if object.Type == objectCommand {
+			c.commands[object.Get("command_name")] = object
+		}
+	}
+
+	c.convertHosts()
+	c.convertContacts()
+	c.convertServices()
+	c.reportUnsupported()
+	return c.result, nil
+}
+
+func (c *converter) report(object *Object, name, format string, args ...interface{}) {
+	c.result.Report = append(c.result.Report, ReportEntry{
+		Object:  strings.TrimSpace(object.Type + " " + name),
+		Source:  object.Source,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// add adds the resource to the result, unless it is invalid.
+func (c *converter) add(object *Object, name string, resource corev2.Resource) bool {
+	if err := resource.Validate(); err != nil {
+		c.report(object, name, "not converted: %s", err)
+		return false
+	}
+	c.result.Resources = append(c.result.Resources, resource)
+	return true
+}
+
+func (c *converter) convertHosts() {
+	// the members of the host groups are subscribed to them
+	hostGroups := map[string][]string{}
+	for _, group := range c.objects[objectHostGroup] {
+		name := resourceName(group.Get("hostgroup_name"))
+		for _, host := range group.List("members") {
+			hostGroups[host] = append(hostGroups[host], name)
+		}
+	}
+
+	hosts := c.objects[objectHost]
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Get("host_name") < hosts[j].Get("host_name") })
+	for _, host := range hosts {
+		hostName := host.Get("host_name")
+		name := resourceName(hostName)
+		entity := corev2.NewEntity(corev2.NewObjectMeta(name, c.options.Namespace))
+		entity.EntityClass = corev2.EntityAgentClass
+		entity.Labels = map[string]string{}
+		if address := host.Get("address"); address != "" {
+			entity.Labels["address"] = address
+		}
+		if alias := host.Get("alias"); alias != "" {
+			entity.Labels["alias"] = alias
+		}
+		// the custom variables are labels, so the checks can use them
+		for attribute, value := range host.Attributes {
+			if strings.HasPrefix(attribute, "_") {
+				entity.Labels[strings.ToLower(strings.TrimPrefix(attribute, "_"))] = value
+			}
+		}
+
+		subscriptions := hostGroups[hostName]
+		for _, group := range host.List("hostgroups") {
+			subscriptions = append(subscriptions, resourceName(group))
+		}
+		entity.Subscriptions = uniqueSorted(subscriptions)
+
+		if !c.add(host, hostName, entity) {
+			continue
+		}
+		if name != hostName {
+			c.report(host, hostName, "converted to the entity %s, whose name can't hold some of the characters of the host name", name)
+		}
+		if host.Get("check_command") != "" {
+			c.report(host, hostName, "host check not converted, the keepalives of the agent report whether the host is up")
+		}
+	}
+}
+
+func (c *converter) convertContacts() {
+	// the contacts are notified of the events of the checks through the
+	// handlers named after them
+	members := map[string][]string{}
+	for _, group := range c.objects[objectContactGroup] {
+		name := group.Get("contactgroup_name")
+		members[name] = append(members[name], group.List("members")...)
+		members[name] = append(members[name], group.List("contactgroup_members")...)
+	}
+
+	contacts := c.objects[objectContact]
+	sort.Slice(contacts, func(i, j int) bool { return contacts[i].Get("contact_name") < contacts[j].Get("contact_name") })
+	for _, contact := range contacts {
+		contactName := contact.Get("contact_name")
+		for _, group := range contact.List("contactgroups") {
+			members[group] = append(members[group], contactName)
+		}
+
+		commands := contact.List("service_notification_commands")
+		if len(commands) == 0 {
+			c.report(contact, contactName, "not converted, the contact has no service notification command")
+			continue
+		}
+		command, ok := c.commands[commands[0]]
+		if !ok {
+			c.report(contact, contactName, "not converted, the notification command %s is not defined", commands[0])
+			continue
+		}
+		// the handlers are not given the entity tokens
+		line, unresolved := c.translate(command.Get("command_line"), nil, contactMacros(contact), false)
+
+		name := resourceName(contactName)
+		handler := corev2.NewHandler(corev2.NewObjectMeta(name, c.options.Namespace))
+		handler.Type = corev2.HandlerPipeType
+		handler.Command = line
+		handler.Filters = append([]string{}, handlerFilters...)
+		if !c.add(contact, contactName, handler) {
+			continue
+		}
+		c.report(contact, contactName, "converted to the pipe handler %s, notified of the incidents with the command of %s", name, commands[0])
+		if len(commands) > 1 {
+			c.report(contact, contactName, "only the first of the notification commands is converted, the handler %s runs %s", name, commands[0])
+		}
+		if len(unresolved) > 0 {
+			c.report(contact, contactName, "the notification command uses the macros %s, the handler %s must read the event from its standard input instead", strings.Join(unresolved, ", "), name)
+		}
+	}
+
+	groups := make([]string, 0, len(members))
+	for group := range members {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		object := &Object{Type: objectContactGroup}
+		for _, definition := range c.objects[objectContactGroup] {
+			if definition.Get("contactgroup_name") == group {
+				object = definition
+			}
+		}
+		name := resourceName(group)
+		handler := corev2.NewHandler(corev2.NewObjectMeta(name, c.options.Namespace))
+		handler.Type = corev2.HandlerSetType
+		for _, member := range uniqueSorted(members[group]) {
+			handler.Handlers = append(handler.Handlers, resourceName(member))
+		}
+		if c.add(object, group, handler) {
+			c.report(object, group, "converted to the handler set %s, of the handlers %s", name, strings.Join(handler.Handlers, ", "))
+		}
+	}
+}
+
+func (c *converter) convertServices() {
+	var hostNames []string
+	for _, host := range c.objects[objectHost] {
+		hostNames = append(hostNames, host.Get("host_name"))
+	}
+
+	services := c.objects[objectService]
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].Get("service_description") < services[j].Get("service_description")
+	})
+	checks := map[string]*corev2.CheckConfig{}
+	for _, service := range services {
+		description := service.Get("service_description")
+		check, ok := c.convertService(service, description, hostNames)
+		if !ok {
+			continue
+		}
+
+		// The services of the same description, such as the services of
+		// different hosts, are a single check if they run the same command.
+		// Otherwise, they are distinct checks named after their subscriptions.
+		if existing, ok := checks[check.Name]; ok {
+			if existing.Command == check.Command && existing.Interval == check.Interval && existing.Publish == check.Publish {
+				existing.Subscriptions = uniqueSorted(append(existing.Subscriptions, check.Subscriptions...))
+				existing.Handlers = uniqueSorted(append(existing.Handlers, check.Handlers...))
+				continue
+			}
+			name := resourceName(check.Name + "-" + strings.Join(check.Subscriptions, "-"))
+			c.report(service, description, "converted to the check %s, since the check %s runs another command", name, check.Name)
+			check.Name = name
+		}
+		if !c.add(service, description, check) {
+			continue
+		}
+		checks[check.Name] = check
+	}
+}
+
+func (c *converter) convertService(service *Object, description string, hostNames []string) (*corev2.CheckConfig, bool) {
+	if description == "" {
+		c.report(service, "", "not converted, the service has no description")
+		return nil, false
+	}
+
+	// check_command is the name of the command and its arguments, separated
+	// by exclamation marks
+	arguments := strings.Split(service.Get("check_command"), "!")
+	command, ok := c.commands[arguments[0]]
+	if !ok {
+		c.report(service, description, "not converted, the check command %q is not defined", arguments[0])
+		return nil, false
+	}
+	macros := map[string]string{"SERVICEDESC": description}
+	line, unresolved := c.translate(command.Get("command_line"), arguments[1:], macros, true)
+	if len(unresolved) > 0 {
+		c.report(service, description, "the check command uses the macros %s, which are not converted", strings.Join(unresolved, ", "))
+	}
+
+	// the services run on the hosts of their host groups, and on their hosts
+	var subscriptions []string
+	for _, group := range service.List("hostgroup_name") {
+		if strings.HasPrefix(group, "!") {
+			c.report(service, description, "the exclusion of the host group %s is not converted", strings.TrimPrefix(group, "!"))
+			continue
+		}
+		subscriptions = append(subscriptions, resourceName(group))
+	}
+	hosts := service.List("host_name")
+	if len(hosts) == 1 && hosts[0] == "*" {
+		hosts = hostNames
+	}
+	for _, host := range hosts {
+		if strings.HasPrefix(host, "!") {
+			c.report(service, description, "the exclusion of the host %s is not converted", strings.TrimPrefix(host, "!"))
+			continue
+		}
+		subscriptions = append(subscriptions, "entity:"+resourceName(host))
+	}
+	if len(subscriptions) == 0 {
+		c.report(service, description, "not converted, the service has no host nor host group")
+		return nil, false
+	}
+
+	var handlers []string
+	for _, contact := range append(service.List("contacts"), service.List("contact_groups")...) {
+		handlers = append(handlers, resourceName(contact))
+	}
+
+	interval := float64(defaultCheckInterval)
+	if value := service.Get("check_interval"); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed > 0 {
+			interval = parsed
+		} else {
+			c.report(service, description, "invalid check_interval %q, the default of %d is used", value, defaultCheckInterval)
+		}
+	}
+
+	name := resourceName(description)
+	check := corev2.NewCheckConfig(corev2.NewObjectMeta(name, c.options.Namespace))
+	check.Command = line
+	check.Interval = uint32(math.Max(1, math.Round(interval*float64(c.options.IntervalLength))))
+	check.Subscriptions = uniqueSorted(subscriptions)
+	check.Handlers = uniqueSorted(handlers)
+	check.Publish = service.Get("active_checks_enabled") != "0"
+	if !check.Publish {
+		c.report(service, description, "passive service, the results of the check %s must be sent to the agent sockets", name)
+	}
+	if name != description {
+		check.Annotations = map[string]string{"nagios_service_description": description}
+	}
+	return check, true
+}
+
+// translate translates a command line: the arguments, the resource macros
+// and the given macros are substituted, and the host macros are replaced by
+// the tokens of the entity if the command supports them. It returns the
+// macros which are not translated.
+func (c *converter) translate(line string, arguments []string, macros map[string]string, entityTokens bool) (string, []string) {
+	var unresolved []string
+	translated := macroRe.ReplaceAllStringFunc(line, func(match string) string {
+		macro := match[1 : len(match)-1]
+		if macro == "" {
+			return "$"
+		}
+		if m := argMacroRe.FindStringSubmatch(macro); m != nil {
+			i, _ := strconv.Atoi(m[1])
+			if i >= 1 && i <= len(arguments) {
+				return arguments[i-1]
+			}
+			return ""
+		}
+		if value, ok := macros[macro]; ok {
+			return value
+		}
+		if value, ok := c.options.Macros[macro]; ok {
+			return value
+		}
+		if token, ok := hostMacros[macro]; ok && entityTokens {
+			return token
+		}
+		if strings.HasPrefix(macro, "_HOST") && entityTokens {
+			return "{{ .labels." + strings.ToLower(strings.TrimPrefix(macro, "_HOST")) + " }}"
+		}
+		unresolved = append(unresolved, match)
+		return match
+	})
+	return translated, uniqueSorted(unresolved)
+}
+
+// reportUnsupported reports the types of objects which are not converted.
+func (c *converter) reportUnsupported() {
+	var types []string
+	for typ := range c.objects {
+		switch typ {
+		case objectCommand, objectContact, objectContactGroup, objectHost, objectHostGroup, objectService:
+		default:
+			types = append(types, typ)
+		}
+	}
+	sort.Strings(types)
+	for _, typ := range types {
+		c.result.Report = append(c.result.Report, ReportEntry{
+			Object:  typ,
+			Message: fmt.Sprintf("%d %s objects not converted, this type of object is not supported", len(c.objects[typ]), typ),
+		})
+	}
+}
+
+// contactMacros are the macros of the notification commands substituted
+// with the attributes of the contact.
+func contactMacros(contact *Object) map[string]string {
+	return map[string]string{
+		"CONTACTNAME":  contact.Get("contact_name"),
+		"CONTACTALIAS": contact.Get("alias"),
+		"CONTACTEMAIL": contact.Get("email"),
+		"CONTACTPAGER": contact.Get("pager"),
+	}
+}
+
+// resolveTemplates returns the objects which are registered, with the
+// attributes inherited from their templates.
+func resolveTemplates(objects []*Object) ([]*Object, error) {
+	templates := map[string]*Object{}
+	for _, object := range objects {
+		if name := object.Get("name"); name != "" {
+			templates[object.Type+"/"+name] = object
+		}
+	}
+
+	var resolve func(object *Object, seen map[*Object]bool) (map[string]string, error)
+	resolve = func(object *Object, seen map[*Object]bool) (map[string]string, error) {
+		if seen[object] {
+			return nil, fmt.Errorf("%s: the templates of the %s are circular", object.Source, object.Type)
+		}
+		seen[object] = true
+		defer delete(seen, object)
+
+		attributes := map[string]string{}
+		// the first templates take precedence over the next ones
+		uses := splitList(object.Get("use"))
+		for i := len(uses) - 1; i >= 0; i-- {
+			template, ok := templates[object.Type+"/"+uses[i]]
+			if !ok {
+				return nil, fmt.Errorf("%s: the template %s of the %s is not defined", object.Source, uses[i], object.Type)
+			}
+			inherited, err := resolve(template, seen)
+			if err != nil {
+				return nil, err
+			}
+			for name, value := range inherited {
+				attributes[name] = value
+			}
+		}
+		for name, value := range object.Attributes {
+			// additive inheritance appends to the inherited list
+			if strings.HasPrefix(value, "+") {
+				value = strings.TrimPrefix(value, "+")
+				if inherited := attributes[name]; inherited != "" {
+					value = inherited + "," + value
+				}
+			}
+			attributes[name] = value
+		}
+		return attributes, nil
+	}
+
+	var resolved []*Object
+	for _, object := range objects {
+		if object.Get("register") == "0" {
+			continue
+		}
+		attributes, err := resolve(object, map[*Object]bool{})
+		if err != nil {
+			return nil, err
+		}
+		// the attributes of the templates are not inherited
+		delete(attributes, "name")
+		delete(attributes, "register")
+		delete(attributes, "use")
+		resolved = append(resolved, &Object{Type: object.Type, Attributes: attributes, Source: object.Source})
+	}
+	return resolved, nil
+}
+
+// resourceName returns the name of the Sensu resource of an object, whose
+// name may hold characters the resource names can't.
+func resourceName(name string) string {
+	return strings.Trim(invalidNameRe.ReplaceAllString(strings.TrimSpace(name), "-"), "-")
+}
+
+func uniqueSorted(values []string) []string {
+	set := make(map[string]struct{}, len(values))
+	result := []string{}
+	for _, value := range values {
+		if _, ok := set[value]; !ok && value != "" {
+			set[value] = struct{}{}
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,257 @@
+package nagios
+
+import (
+	"strings"
+	"testing"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixtureConfig = `
+define command {
+	command_name  check_disk
+	command_line  $USER1$/check_disk -w $ARG1$ -c $ARG2$ -p $_HOSTROOT_PARTITION$
+}
+
+define command {
+	command_name  check_http
+	command_line  $USER1$/check_http -H $HOSTADDRESS$ -u $ARG1$ -t $TIMEOUT$
+}
+
+define command {
+	command_name  check-host-alive
+	command_line  $USER1$/check_ping -H $HOSTADDRESS$
+}
+
+define command {
+	command_name  notify-service-by-email
+	command_line  /usr/bin/printf "$SERVICEOUTPUT$" | /usr/bin/mail -s "$SERVICEDESC$" $CONTACTEMAIL$
+}
+
+define host {
+	name           generic-host
+	check_command  check-host-alive
+	hostgroups     linux
+	register       0
+}
+
+define host {
+	use              generic-host
+	host_name        web1
+	alias            Web server
+	address          10.0.0.1
+	hostgroups       +web
+	_ROOT_PARTITION  /
+}
+
+define host {
+	use        generic-host
+	host_name  db 1
+	address    10.0.0.2
+}
+
+define hostgroup {
+	hostgroup_name  databases
+	members         db 1
+}
+
+define service {
+	name            generic-service
+	check_interval  5
+	contacts        jdoe
+	register        0
+}
+
+define service {
+	use                  generic-service
+	hostgroup_name       linux
+	service_description  Root Disk
+	check_command        check_disk!20%!10%
+}
+
+define service {
+	use                  generic-service
+	host_name            web1
+	service_description  HTTP
+	check_command        check_http!/health
+	check_interval       1.5
+	contact_groups       admins
+}
+
+define service {
+	host_name              db 1
+	service_description    Backup
+	check_command          check_disk!5%!2%
+	active_checks_enabled  0
+}
+
+define contact {
+	contact_name                   jdoe
+	email                          jdoe@example.com
+	service_notification_commands  notify-service-by-email
+	contactgroups                  admins
+}
+
+define contact {
+	contact_name  nobody
+}
+
+define contactgroup {
+	contactgroup_name  admins
+	alias              Administrators
+}
+
+define timeperiod {
+	timeperiod_name  24x7
+}
+`
+
+func convertFixture(t *testing.T) *Result {
+	objects, err := Parse(strings.NewReader(fixtureConfig), "objects.cfg")
+	require.NoError(t, err)
+	result, err := Convert(objects, Options{
+		Namespace:      "default",
+		IntervalLength: 60,
+		Macros:         map[string]string{"USER1": "/usr/lib/nagios/plugins"},
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestConvert(t *testing.T) {
+	result := convertFixture(t)
+
+	resources := map[string]corev2.Resource{}
+	for _, resource := range result.Resources {
+		resources[resource.URIPath()] = resource
+	}
+
+	web1, ok := resources["/api/core/v2/namespaces/default/entities/web1"].(*corev2.Entity)
+	require.True(t, ok, "web1 entity")
+	assert.Equal(t, corev2.EntityAgentClass, web1.EntityClass)
+	assert.Equal(t, []string{"linux", "web"}, web1.Subscriptions)
+	assert.Equal(t, map[string]string{"address": "10.0.0.1", "alias": "Web server", "root_partition": "/"}, web1.Labels)
+
+	db1, ok := resources["/api/core/v2/namespaces/default/entities/db-1"].(*corev2.Entity)
+	require.True(t, ok, "db-1 entity")
+	assert.Equal(t, []string{"databases", "linux"}, db1.Subscriptions)
+
+	disk, ok := resources["/api/core/v2/namespaces/default/checks/Root-Disk"].(*corev2.CheckConfig)
+	require.True(t, ok, "Root-Disk check")
+	assert.Equal(t, "/usr/lib/nagios/plugins/check_disk -w 20% -c 10% -p {{ .labels.root_partition }}", disk.Command)
+	assert.Equal(t, uint32(300), disk.Interval)
+	assert.Equal(t, []string{"linux"}, disk.Subscriptions)
+	assert.Equal(t, []string{"jdoe"}, disk.Handlers)
+	assert.True(t, disk.Publish)
+	assert.Equal(t, "Root Disk", disk.Annotations["nagios_service_description"])
+
+	http, ok := resources["/api/core/v2/namespaces/default/checks/HTTP"].(*corev2.CheckConfig)
+	require.True(t, ok, "HTTP check")
+	assert.Equal(t, "/usr/lib/nagios/plugins/check_http -H {{ .labels.address }} -u /health -t $TIMEOUT$", http.Command)
+	assert.Equal(t, uint32(90), http.Interval)
+	assert.Equal(t, []string{"entity:web1"}, http.Subscriptions)
+	assert.Equal(t, []string{"admins", "jdoe"}, http.Handlers)
+
+	backup, ok := resources["/api/core/v2/namespaces/default/checks/Backup"].(*corev2.CheckConfig)
+	require.True(t, ok, "Backup check")
+	assert.False(t, backup.Publish)
+	assert.Equal(t, []string{"entity:db-1"}, backup.Subscriptions)
+
+	jdoe, ok := resources["/api/core/v2/namespaces/default/handlers/jdoe"].(*corev2.Handler)
+	require.True(t, ok, "jdoe handler")
+	assert.Equal(t, corev2.HandlerPipeType, jdoe.Type)
+	assert.Equal(t, `/usr/bin/printf "$SERVICEOUTPUT$" | /usr/bin/mail -s "$SERVICEDESC$" jdoe@example.com`, jdoe.Command)
+	assert.Equal(t, []string{"is_incident", "not_silenced"}, jdoe.Filters)
+
+	admins, ok := resources["/api/core/v2/namespaces/default/handlers/admins"].(*corev2.Handler)
+	require.True(t, ok, "admins handler")
+	assert.Equal(t, corev2.HandlerSetType, admins.Type)
+	assert.Equal(t, []string{"jdoe"}, admins.Handlers)
+
+	_, ok = resources["/api/core/v2/namespaces/default/handlers/nobody"]
+	assert.False(t, ok, "the contact without notification command is not converted")
+	assert.Len(t, result.Resources, 7)
+
+	for _, resource := range result.Resources {
+		assert.NoError(t, resource.Validate(), resource.URIPath())
+	}
+}
+
+func TestConvertReport(t *testing.T) {
+	result := convertFixture(t)
+
+	messages := map[string][]string{}
+	for _, entry := range result.Report {
+		messages[entry.Object] = append(messages[entry.Object], entry.Message)
+	}
+
+	assert.Contains(t, messages["host db 1"], "converted to the entity db-1, whose name can't hold some of the characters of the host name")
+	assert.Contains(t, messages["host web1"], "host check not converted, the keepalives of the agent report whether the host is up")
+	assert.Contains(t, messages["contact jdoe"], "converted to the pipe handler jdoe, notified of the incidents with the command of notify-service-by-email")
+	assert.Contains(t, messages["contact jdoe"], "the notification command uses the macros $SERVICEDESC$, $SERVICEOUTPUT$, the handler jdoe must read the event from its standard input instead")
+	assert.Contains(t, messages["contact nobody"], "not converted, the contact has no service notification command")
+	assert.Contains(t, messages["contactgroup admins"], "converted to the handler set admins, of the handlers jdoe")
+	assert.Contains(t, messages["service HTTP"], "the check command uses the macros $TIMEOUT$, which are not converted")
+	assert.Contains(t, messages["service Backup"], "passive service, the results of the check Backup must be sent to the agent sockets")
+	assert.Contains(t, messages["timeperiod"], "1 timeperiod objects not converted, this type of object is not supported")
+}
+
+func TestConvertServicesOfTheSameDescription(t *testing.T) {
+	config := `
+define command {
+	command_name  check_load
+	command_line  check_load -w $ARG1$
+}
+define service {
+	host_name            web1,web2
+	service_description  Load
+	check_command        check_load!5
+}
+define service {
+	hostgroup_name       linux
+	service_description  Load
+	check_command        check_load!5
+}
+define service {
+	hostgroup_name       databases
+	service_description  Load
+	check_command        check_load!10
+}
+`
+	objects, err := Parse(strings.NewReader(config), "objects.cfg")
+	require.NoError(t, err)
+	result, err := Convert(objects, Options{Namespace: "default"})
+	require.NoError(t, err)
+	require.Len(t, result.Resources, 2)
+
+	load := result.Resources[0].(*corev2.CheckConfig)
+	assert.Equal(t, "Load", load.Name)
+	assert.Equal(t, []string{"entity:web1", "entity:web2", "linux"}, load.Subscriptions)
+
+	databases := result.Resources[1].(*corev2.CheckConfig)
+	assert.Equal(t, "Load-databases", databases.Name)
+	assert.Equal(t, "check_load -w 10", databases.Command)
+}
+
+func TestResolveTemplates(t *testing.T) {
+	objects := []*Object{
+		{Type: "service", Attributes: map[string]string{"name": "base", "check_interval": "5", "contacts": "ops", "register": "0"}},
+		{Type: "service", Attributes: map[string]string{"name": "fast", "check_interval": "1", "register": "0"}},
+		{Type: "service", Attributes: map[string]string{"use": "fast,base", "service_description": "cpu", "contacts": "+dev"}},
+	}
+	resolved, err := resolveTemplates(objects)
+	require.NoError(t, err)
+	require.Len(t, resolved, 1)
+	assert.Equal(t, map[string]string{"check_interval": "1", "contacts": "ops,dev", "service_description": "cpu"}, resolved[0].Attributes)
+
+	_, err = resolveTemplates([]*Object{{Type: "host", Attributes: map[string]string{"use": "missing"}}})
+	assert.Error(t, err)
+
+	_, err = resolveTemplates([]*Object{
+		{Type: "host", Attributes: map[string]string{"name": "a", "use": "b"}},
+		{Type: "host", Attributes: map[string]string{"name": "b", "use": "a"}},
+	})
+	assert.Error(t, err)
+}
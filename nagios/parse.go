@@ -0,0 +1,156 @@
+// Package nagios converts the object definitions of Nagios and Icinga 1.x to
+// Sensu resources.
+package nagios
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Object is an object definition, e.g. a host, a service or a command.
+type Object struct {
+	// Type is the type of the object, e.g. service.
+	Type string
+
+	// Attributes are the directives of the object definition.
+	Attributes map[string]string
+
+	// Source is the file and line of the object definition.
+	Source string
+}
+
+// Get returns the value of an attribute of the object.
+func (o *Object) Get(name string) string {
+	return o.Attributes[name]
+}
+
+// List returns the values of an attribute holding a comma-separated list.
+func (o *Object) List(name string) []string {
+	return splitList(o.Attributes[name])
+}
+
+// Parse parses the object definitions of a configuration file. The name of
+// the file is only used to locate the errors and the objects.
+func Parse(r io.Reader, filename string) ([]*Object, error) {
+	var objects []*Object
+	var current *Object
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	var continued string
+	for scanner.Scan() {
+		lineNumber++
+		line := stripComment(scanner.Text())
+
+		// a trailing backslash continues the line
+		if strings.HasSuffix(line, `\`) {
+			continued += strings.TrimSuffix(line, `\`)
+			continue
+		}
+		line = strings.TrimSpace(continued + line)
+		continued = ""
+		if line == "" {
+			continue
+		}
+
+		if current == nil {
+			if !strings.HasPrefix(line, "define") {
+				return nil, fmt.Errorf("%s:%d: unexpected %q outside of an object definition", filename, lineNumber, line)
+			}
+			typ := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "define"), "{"))
+			if typ == "" || !strings.HasSuffix(line, "{") || strings.ContainsAny(typ, " \t{}") {
+				return nil, fmt.Errorf("%s:%d: invalid object definition %q, must be define <type> {", filename, lineNumber, line)
+			}
+			current = &Object{
+				Type:       typ,
+				Attributes: map[string]string{},
+				Source:     fmt.Sprintf("%s:%d", filename, lineNumber),
+			}
+			continue
+		}
+
+		closed := strings.HasSuffix(line, "}")
+		line = strings.TrimSpace(strings.TrimSuffix(line, "}"))
+		if strings.HasPrefix(line, "define") {
+			return nil, fmt.Errorf("%s:%d: unexpected object definition within the %s defined at %s", filename, lineNumber, current.Type, current.Source)
+		}
+		if line != "" {
+			name, value := line, ""
+			if i := strings.IndexAny(line, " \t"); i >= 0 {
+				name, value = line[:i], strings.TrimSpace(line[i+1:])
+			}
+			current.Attributes[name] = value
+		}
+		if closed {
+			objects = append(objects, current)
+			current = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	if current != nil {
+		return nil, fmt.Errorf("%s: the %s defined at %s is not terminated", filename, current.Type, current.Source)
+	}
+	return objects, nil
+}
+
+// ParseResources parses the resource macros of a resource file, such as
+// resource.cfg, e.g. $USER1$=/usr/lib/nagios/plugins.
+func ParseResources(r io.Reader, filename string) (map[string]string, error) {
+	macros := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		name := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || len(name) < 3 || !strings.HasPrefix(name, "$") || !strings.HasSuffix(name, "$") {
+			return nil, fmt.Errorf("%s:%d: invalid resource macro %q, must be $NAME$=value", filename, lineNumber, line)
+		}
+		macros[name[1:len(name)-1]] = strings.TrimSpace(parts[1])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	return macros, nil
+}
+
+// stripComment removes the comment of a line, which starts with a # at the
+// beginning of the line or with a semicolon which is not escaped.
+func stripComment(line string) string {
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return ""
+	}
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == ';' {
+			b.WriteByte(';')
+			i++
+			continue
+		}
+		if line[i] == ';' {
+			break
+		}
+		b.WriteByte(line[i])
+	}
+	return b.String()
+}
+
+// splitList splits a comma-separated list, ignoring the empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
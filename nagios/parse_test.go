@@ -0,0 +1,81 @@
+package nagios
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	config := `
+# a comment
+define command {
+	command_name    check_http
+	command_line    $USER1$/check_http -H $HOSTADDRESS$ \
+	                -u "$ARG1$\;" ; an inline comment
+}
+
+define host{
+	host_name   web1
+	address     10.0.0.1
+	}
+`
+	objects, err := Parse(strings.NewReader(config), "objects.cfg")
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	assert.Equal(t, "command", objects[0].Type)
+	assert.Equal(t, "objects.cfg:3", objects[0].Source)
+	assert.Equal(t, "check_http", objects[0].Get("command_name"))
+	assert.Equal(t, `$USER1$/check_http -H $HOSTADDRESS$ 	                -u "$ARG1$;"`, objects[0].Get("command_line"))
+
+	assert.Equal(t, "host", objects[1].Type)
+	assert.Equal(t, map[string]string{"host_name": "web1", "address": "10.0.0.1"}, objects[1].Attributes)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "outside of a definition",
+			config: "host_name web1\n",
+		},
+		{
+			name:   "invalid definition",
+			config: "define host\n}\n",
+		},
+		{
+			name:   "nested definition",
+			config: "define host {\ndefine service {\n}\n}\n",
+		},
+		{
+			name:   "unterminated definition",
+			config: "define host {\nhost_name web1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.config), "objects.cfg")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestObjectList(t *testing.T) {
+	object := &Object{Attributes: map[string]string{"members": "web1, web2,,db1 "}}
+	assert.Equal(t, []string{"web1", "web2", "db1"}, object.List("members"))
+	assert.Nil(t, object.List("contacts"))
+}
+
+func TestParseResources(t *testing.T) {
+	macros, err := ParseResources(strings.NewReader("# plugins\n$USER1$=/usr/lib/nagios/plugins\n\n$USER2$ = secret=1\n"), "resource.cfg")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"USER1": "/usr/lib/nagios/plugins", "USER2": "secret=1"}, macros)
+
+	_, err = ParseResources(strings.NewReader("USER1=/usr/lib/nagios/plugins\n"), "resource.cfg")
+	assert.Error(t, err)
+}
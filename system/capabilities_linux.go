@@ -0,0 +1,28 @@
+package system
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// libCType returns the type of the C library of the system, glibc or musl,
+// from its dynamic loader. It is empty if no known loader is installed, e.g.
+// in the distroless containers.
+func libCType() string {
+	if matches, _ := filepath.Glob("/lib/ld-musl-*.so.1"); len(matches) > 0 {
+		return "musl"
+	}
+	for _, pattern := range []string{"/lib*/ld-linux*.so.*", "/lib/*/ld-linux*.so.*"} {
+		if matches, _ := filepath.Glob(pattern); len(matches) > 0 {
+			return "glibc"
+		}
+	}
+	return ""
+}
+
+// fipsEnabled returns true if the kernel runs in FIPS mode.
+func fipsEnabled() bool {
+	b, err := ioutil.ReadFile("/proc/sys/crypto/fips_enabled")
+	return err == nil && strings.TrimSpace(string(b)) == "1"
+}
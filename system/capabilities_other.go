@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package system
+
+// libCType returns an empty type, the C library is only detected on Linux.
+func libCType() string {
+	return ""
+}
+
+// fipsEnabled returns false, the FIPS mode is only detected on Linux.
+func fipsEnabled() bool {
+	return false
+}
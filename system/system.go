@@ -19,7 +19,7 @@ const defaultHostname = "unidentified-hostname"
 var goarm int32
 
 // Info describes the local system, hostname, OS, platform, platform
-// family, platform version, and network interfaces.
+// family, platform version, C library, FIPS mode, and network interfaces.
 func Info() (types.System, error) {
 	info, err := host.Info()
 
@@ -35,6 +35,8 @@ func Info() (types.System, error) {
 		Platform:        info.Platform,
 		PlatformFamily:  info.PlatformFamily,
 		PlatformVersion: info.PlatformVersion,
+		LibCType:        libCType(),
+		FIPSEnabled:     fipsEnabled(),
 	}
 
 	if system.Hostname == "" {
@@ -0,0 +1,170 @@
+package e2e
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/agentd"
+	"github.com/sensu/sensu-go/transport"
+)
+
+const (
+	// agentUser and agentPassword are the credentials of the agent user
+	// seeded in the backend store.
+	agentUser     = "agent"
+	agentPassword = "P@ssw0rd!"
+)
+
+// Agent is a fake agent. It sends the events given by the test, stamped with
+// the time of the clock of the fake agents, and receives the check requests of
+// the backend without executing them. Like the agent, it exchanges protobuf
+// messages with the backend.
+type Agent struct {
+	// Entity is the entity of the agent.
+	Entity *corev2.Entity
+
+	backend       *Backend
+	transport     transport.Transport
+	checkRequests chan *corev2.CheckRequest
+	stopping      chan struct{}
+	wg            sync.WaitGroup
+}
+
+// NewAgent connects a fake agent, with the given name and subscriptions, to
+// the default namespace of the backend. The returned function disconnects
+// the agent, it must be deferred by the test.
+func (b *Backend) NewAgent(t *testing.T, name string, subscriptions ...string) (*Agent, func()) {
+	entity := corev2.FixtureEntity(name)
+	entity.Subscriptions = subscriptions
+
+	credentials := base64.StdEncoding.EncodeToString([]byte(agentUser + ":" + agentPassword))
+	header := http.Header{}
+	header.Set("Authorization", "Basic "+credentials)
+	header.Set("Accept", agentd.ProtobufSerializationHeader)
+	header.Set(transport.HeaderKeyAgentName, name)
+	header.Set(transport.HeaderKeyNamespace, entity.Namespace)
+	header.Set(transport.HeaderKeyUser, agentUser)
+	header.Set(transport.HeaderKeySubscriptions, strings.Join(subscriptions, ","))
+
+	conn, _, err := transport.Connect(b.AgentURL, nil, header, int(b.timeout/time.Second), transport.Compression{})
+	if err != nil {
+		t.Fatalf("agent %s failed to connect: %s", name, err)
+	}
+
+	a := &Agent{
+		Entity:        entity,
+		backend:       b,
+		transport:     conn,
+		checkRequests: make(chan *corev2.CheckRequest, 100),
+		stopping:      make(chan struct{}),
+	}
+	a.wg.Add(1)
+	go a.receive()
+
+	return a, func() {
+		close(a.stopping)
+		_ = a.transport.Close()
+		a.wg.Wait()
+	}
+}
+
+// CheckRequests returns the channel of the check requests received by the
+// agent.
+func (a *Agent) CheckRequests() <-chan *corev2.CheckRequest {
+	return a.checkRequests
+}
+
+// SendKeepalive sends a keepalive for the entity of the agent.
+func (a *Agent) SendKeepalive(t *testing.T) {
+	now := a.backend.clock.Now().Unix()
+	entity := *a.Entity
+	entity.LastSeen = now
+	event := &corev2.Event{
+		ObjectMeta: corev2.NewObjectMeta("", entity.Namespace),
+		Entity:     &entity,
+		Timestamp:  now,
+	}
+	a.send(t, transport.MessageTypeKeepalive, event)
+}
+
+// SendCheckResult sends an event of the entity of the agent, with the result
+// of the check, executed at the current time of the clock.
+func (a *Agent) SendCheckResult(t *testing.T, check *corev2.Check) {
+	now := a.backend.clock.Now().Unix()
+	check.Executed = now
+	event := &corev2.Event{
+		ObjectMeta: corev2.NewObjectMeta("", a.Entity.Namespace),
+		Entity:     a.Entity,
+		Check:      check,
+		Timestamp:  now,
+	}
+	a.send(t, transport.MessageTypeEvent, event)
+}
+
+// StartKeepalives sends a keepalive every interval of the clock of the fake
+// agents, until the agent is disconnected. The agent waits for the next
+// interval before sending each keepalive, so advancing a fake clock by the
+// interval once a keepalive is stored sends the next one.
+func (a *Agent) StartKeepalives(t *testing.T, interval time.Duration) {
+	next := a.backend.clock.After(interval)
+	a.SendKeepalive(t)
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		for {
+			select {
+			case <-a.stopping:
+				return
+			case <-next:
+				next = a.backend.clock.After(interval)
+				a.SendKeepalive(t)
+			}
+		}
+	}()
+}
+
+// send sends the event to the backend, as a message of the given type.
+func (a *Agent) send(t *testing.T, msgType string, event *corev2.Event) {
+	payload, err := proto.Marshal(event)
+	if err != nil {
+		t.Errorf("could not marshal event: %s", err)
+		return
+	}
+	if err := a.transport.Send(transport.NewMessage(msgType, payload)); err != nil {
+		select {
+		case <-a.stopping:
+		default:
+			t.Errorf("agent %s could not send %s: %s", a.Entity.Name, msgType, err)
+		}
+	}
+}
+
+// receive receives the check requests of the backend until the agent is
+// disconnected.
+func (a *Agent) receive() {
+	defer a.wg.Done()
+	for {
+		msg, err := a.transport.Receive()
+		if err != nil {
+			return
+		}
+		if msg.Type != corev2.CheckRequestType {
+			continue
+		}
+		var request corev2.CheckRequest
+		if err := proto.Unmarshal(msg.Payload, &request); err != nil {
+			continue
+		}
+		select {
+		case a.checkRequests <- &request:
+		case <-a.stopping:
+			return
+		}
+	}
+}
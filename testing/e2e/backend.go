@@ -0,0 +1,244 @@
+// Package e2e provides an in-process backend and fake agents, to write
+// end-to-end tests of checks, filters, mutators and handlers without having to
+// orchestrate containers. The backend runs its embedded etcd. The fake agents
+// and the check schedulers of the backend can be driven by a fake clock, the
+// other daemons of the backend use the real clock.
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/echlebek/timeproxy"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend"
+	"github.com/sensu/sensu-go/backend/agentd"
+	"github.com/sensu/sensu-go/backend/etcd"
+	"github.com/sensu/sensu-go/backend/schedulerd"
+	"github.com/sensu/sensu-go/testing/testutil"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultTimeout is the default time waited for the backend to start and
+	// for the events expected by the tests.
+	DefaultTimeout = 10 * time.Second
+
+	// memoryDir is the memory-backed file system of the state directories.
+	memoryDir = "/dev/shm"
+)
+
+// Clock is the clock of the fake agents and of the check schedulers of the
+// backend. It is satisfied by timeproxy.RealTime, and by crock.Time, whose Set
+// method advances the time, and fires the timers of the agents and the
+// schedulers waiting for it.
+type Clock interface {
+	schedulerd.Clock
+
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(time.Duration) <-chan time.Time
+}
+
+// BackendConfig configures a test backend.
+type BackendConfig struct {
+	// MemoryBackedStateDir puts the state directory of the backend, where
+	// the embedded etcd stores its data, in the memory-backed file system
+	// /dev/shm when available, which speeds up the tests. The store is still
+	// an etcd server.
+	MemoryBackedStateDir bool
+
+	// Clock is the clock of the fake agents connected to the backend, which
+	// stamps their events and paces their keepalives, and of the check
+	// schedulers of the backend, which issue the check requests. It defaults
+	// to the real clock. The other daemons of the backend use the real clock,
+	// e.g. to expire the keepalives and the check TTLs.
+	Clock Clock
+
+	// Timeout is the time waited for the backend to start and for the events
+	// expected by the tests. It defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// FilterLists restricts the lists requested by the users granted some
+	// resources of a collection by name to these resources, like the
+	// --api-filter-lists backend flag.
+	FilterLists bool
+}
+
+// Backend is an in-process backend, listening on random local ports.
+type Backend struct {
+	*backend.Backend
+
+	// AgentURL is the URL the agents connect to.
+	AgentURL string
+
+	// APIURL is the URL of the backend API.
+	APIURL string
+
+	clock   Clock
+	timeout time.Duration
+	wg      sync.WaitGroup
+	runErr  error
+}
+
+// NewBackend starts a backend for the test. The returned function stops the
+// backend and removes its data, it must be deferred by the test.
+func NewBackend(t *testing.T, config BackendConfig) (*Backend, func()) {
+	if config.Clock == nil {
+		config.Clock = timeproxy.RealTime{}
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	stateDir, removeStateDir := stateDir(t, config.MemoryBackedStateDir)
+	cacheDir, removeCacheDir := testutil.TempDir(t)
+	cleanupDirs := func() {
+		removeCacheDir()
+		removeStateDir()
+	}
+
+	ports := make([]int, 5)
+	if err := testutil.RandomPorts(ports); err != nil {
+		cleanupDirs()
+		t.Fatal(err)
+	}
+	clientURL := fmt.Sprintf("http://127.0.0.1:%d", ports[0])
+	peerURL := fmt.Sprintf("http://127.0.0.1:%d", ports[1])
+	agentPort, apiPort, dashboardPort := ports[2], ports[3], ports[4]
+
+	setDaemonDefaults()
+
+	b, err := backend.Initialize(&backend.Config{
+		StateDir:                     stateDir,
+		CacheDir:                     cacheDir,
+		AgentHost:                    "127.0.0.1",
+		AgentPort:                    agentPort,
+		APIListenAddress:             fmt.Sprintf("127.0.0.1:%d", apiPort),
+		APIURL:                       fmt.Sprintf("http://127.0.0.1:%d", apiPort),
+		DashboardHost:                "127.0.0.1",
+		DashboardPort:                dashboardPort,
+		EtcdAdvertiseClientURLs:      []string{clientURL},
+		EtcdListenClientURLs:         []string{clientURL},
+		EtcdListenPeerURLs:           []string{peerURL},
+		EtcdInitialCluster:           fmt.Sprintf("default=%s", peerURL),
+		EtcdInitialClusterState:      etcd.ClusterStateNew,
+		EtcdInitialAdvertisePeerURLs: []string{peerURL},
+		EtcdName:                     "default",
+		APIFilterLists:               config.FilterLists,
+		SchedulerClock:               config.Clock,
+	})
+	if err != nil {
+		cleanupDirs()
+		t.Fatalf("failed to initialize backend: %s", err)
+	}
+
+	tb := &Backend{
+		Backend:  b,
+		AgentURL: fmt.Sprintf("ws://127.0.0.1:%d", agentPort),
+		APIURL:   fmt.Sprintf("http://127.0.0.1:%d", apiPort),
+		clock:    config.Clock,
+		timeout:  config.Timeout,
+	}
+	tb.wg.Add(1)
+	go func() {
+		defer tb.wg.Done()
+		tb.runErr = b.Run()
+	}()
+	cleanup := func() {
+		b.Stop()
+		tb.wg.Wait()
+		cleanupDirs()
+		if tb.runErr != nil {
+			t.Errorf("backend stopped with error: %s", tb.runErr)
+		}
+	}
+
+	for _, port := range []int{agentPort, apiPort} {
+		if err := waitForPort(port, config.Timeout); err != nil {
+			cleanup()
+			t.Fatal(err)
+		}
+	}
+
+	return tb, cleanup
+}
+
+// Clock returns the clock of the fake agents connected to the backend and of
+// its check schedulers.
+func (b *Backend) Clock() Clock {
+	return b.clock
+}
+
+// WaitForEvent waits for the event of the entity and check to be stored, and
+// to satisfy the condition if not nil, and returns it. The test fails if the
+// event isn't found before the timeout of the backend.
+func (b *Backend) WaitForEvent(t *testing.T, namespace, entity, check string, condition func(*corev2.Event) bool) *corev2.Event {
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, namespace)
+	deadline := time.Now().Add(b.timeout)
+	for {
+		event, err := b.Store.GetEventByEntityCheck(ctx, entity, check)
+		if err != nil {
+			t.Fatalf("could not get event %s/%s: %s", entity, check, err)
+		}
+		if event != nil && (condition == nil || condition(event)) {
+			return event
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for event %s/%s", entity, check)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// stateDir returns the state directory of the backend, in a memory-backed
+// file system if memoryBacked is true and one is available.
+func stateDir(t *testing.T, memoryBacked bool) (string, func()) {
+	if memoryBacked {
+		if info, err := os.Stat(memoryDir); err == nil && info.IsDir() {
+			dir, err := ioutil.TempDir(memoryDir, "sensu")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return dir, func() { _ = os.RemoveAll(dir) }
+		}
+	}
+	return testutil.TempDir(t)
+}
+
+// setDaemonDefaults sets the defaults of the daemon settings read from viper
+// by the backend, which are otherwise set by the sensu-backend command.
+func setDaemonDefaults() {
+	viper.SetDefault(backend.FlagEventdWorkers, 100)
+	viper.SetDefault(backend.FlagEventdBufferSize, 100)
+	viper.SetDefault(backend.FlagKeepalivedWorkers, 100)
+	viper.SetDefault(backend.FlagKeepalivedBufferSize, 100)
+	viper.SetDefault(backend.FlagPipelinedWorkers, 100)
+	viper.SetDefault(backend.FlagPipelinedBufferSize, 100)
+	viper.SetDefault(backend.FlagPipelinedHandlerGracePeriod, 5*time.Second)
+	viper.SetDefault(backend.FlagAgentdSendQueueSize, agentd.DefaultSendQueueSize)
+	viper.SetDefault(backend.FlagAgentdSendQueueOverflowPolicy, agentd.OverflowPolicyBlock)
+	viper.SetDefault(backend.FlagAgentdSendQueuePriorityWindow, agentd.DefaultSendQueuePriorityWindow)
+}
+
+// waitForPort waits for the local port to accept connections.
+func waitForPort(port int, timeout time.Duration) error {
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.Dial("tcp", address)
+		if err == nil {
+			return conn.Close()
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s: %s", address, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
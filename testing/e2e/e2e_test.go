@@ -0,0 +1,162 @@
+// +build integration
+
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/echlebek/crock"
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendEvents(t *testing.T) {
+	clock := crock.NewTime(time.Unix(1500000000, 0))
+	backend, cleanup := NewBackend(t, BackendConfig{MemoryBackedStateDir: true, Clock: clock})
+	defer cleanup()
+
+	agent, disconnect := backend.NewAgent(t, "agent1", "linux")
+	defer disconnect()
+
+	agent.StartKeepalives(t, 20*time.Second)
+	keepalive := backend.WaitForEvent(t, "default", "agent1", "keepalive", nil)
+	assert.Equal(t, int64(1500000000), keepalive.Entity.LastSeen)
+
+	// The next keepalive is stamped with the advanced clock
+	clock.Set(clock.Now().Add(20 * time.Second))
+	backend.WaitForEvent(t, "default", "agent1", "keepalive", func(event *corev2.Event) bool {
+		return event.Entity.LastSeen == 1500000020
+	})
+
+	check := corev2.FixtureCheck("disk")
+	check.Status = 2
+	check.Output = "disk full"
+	agent.SendCheckResult(t, check)
+	event := backend.WaitForEvent(t, "default", "agent1", "disk", nil)
+	assert.Equal(t, "disk full", event.Check.Output)
+	assert.Equal(t, int64(1500000020), event.Check.Executed)
+}
+
+func TestBackendCheckRequests(t *testing.T) {
+	backend, cleanup := NewBackend(t, BackendConfig{})
+	defer cleanup()
+
+	agent, disconnect := backend.NewAgent(t, "agent1", "linux")
+	defer disconnect()
+
+	check := corev2.FixtureCheckConfig("disk")
+	check.Subscriptions = []string{"linux"}
+	check.Interval = 1
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+	require.NoError(t, backend.Store.UpdateCheckConfig(ctx, check))
+
+	select {
+	case request := <-agent.CheckRequests():
+		assert.Equal(t, "disk", request.Config.Name)
+	case <-time.After(DefaultTimeout):
+		t.Fatal("timed out waiting for check request")
+	}
+}
+
+func TestBackendCheckSchedules(t *testing.T) {
+	clock := crock.NewTime(time.Now())
+	backend, cleanup := NewBackend(t, BackendConfig{Clock: clock})
+	defer cleanup()
+
+	agent, disconnect := backend.NewAgent(t, "agent1", "linux")
+	defer disconnect()
+
+	check := corev2.FixtureCheckConfig("disk")
+	check.Subscriptions = []string{"linux"}
+	check.Interval = 3600
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+	require.NoError(t, backend.Store.UpdateCheckConfig(ctx, check))
+
+	// The schedule is advanced by the clock, until the scheduler of the check
+	// starts or resets its timer
+	nextRequest := func() *corev2.CheckRequest {
+		deadline := time.After(DefaultTimeout)
+		for {
+			clock.Set(clock.Now().Add(time.Hour))
+			select {
+			case request := <-agent.CheckRequests():
+				return request
+			case <-time.After(100 * time.Millisecond):
+			case <-deadline:
+				t.Fatal("timed out waiting for check request")
+			}
+		}
+	}
+	first := nextRequest()
+	second := nextRequest()
+	assert.Equal(t, "disk", second.Config.Name)
+	assert.True(t, second.Issued-first.Issued >= 3600)
+}
+
+func TestGraphQLFilteredLists(t *testing.T) {
+	backend, cleanup := NewBackend(t, BackendConfig{FilterLists: true})
+	defer cleanup()
+
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+	for _, name := range []string{"check-a", "check-b"} {
+		require.NoError(t, backend.Store.UpdateCheckConfig(ctx, corev2.FixtureCheckConfig(name)))
+	}
+
+	// The viewer is only granted check-a by name
+	role := &corev2.ClusterRole{
+		ObjectMeta: corev2.ObjectMeta{Name: "check-a-viewer"},
+		Rules: []corev2.Rule{
+			{Verbs: []string{"get"}, Resources: []string{corev2.NamespacesResource}},
+			{Verbs: []string{"get"}, Resources: []string{corev2.ChecksResource}, ResourceNames: []string{"check-a"}},
+		},
+	}
+	require.NoError(t, backend.Store.CreateClusterRole(ctx, role))
+	binding := &corev2.ClusterRoleBinding{
+		ObjectMeta: corev2.ObjectMeta{Name: "check-a-viewer"},
+		RoleRef:    corev2.RoleRef{Type: "ClusterRole", Name: "check-a-viewer"},
+		Subjects:   []corev2.Subject{{Type: corev2.UserType, Name: "viewer"}},
+	}
+	require.NoError(t, backend.Store.CreateClusterRoleBinding(ctx, binding))
+
+	token, tokenString, err := jwt.AccessToken(corev2.FixtureClaims("viewer", nil))
+	require.NoError(t, err)
+	require.NoError(t, backend.Store.AllowTokens(token))
+
+	query := `{ namespace(name: "default") { checks { nodes { name } } } }`
+	body, err := json.Marshal(map[string]interface{}{"query": query})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, backend.APIURL+"/graphql", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokenString))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Namespace struct {
+				Checks struct {
+					Nodes []struct {
+						Name string
+					}
+				}
+			}
+		}
+		Errors []interface{}
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Empty(t, result.Errors)
+	nodes := result.Data.Namespace.Checks.Nodes
+	require.Len(t, nodes, 1)
+	assert.Equal(t, "check-a", nodes[0].Name)
+}
@@ -0,0 +1,33 @@
+package mockstore
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// DeleteAgentConfig ...
+func (s *MockStore) DeleteAgentConfig(ctx context.Context, entity string) error {
+	args := s.Called(ctx, entity)
+	return args.Error(0)
+}
+
+// GetAgentConfig ...
+func (s *MockStore) GetAgentConfig(ctx context.Context, entity string) (*corev2.AgentConfig, error) {
+	args := s.Called(ctx, entity)
+	config, _ := args.Get(0).(*corev2.AgentConfig)
+	return config, args.Error(1)
+}
+
+// UpdateAgentConfig ...
+func (s *MockStore) UpdateAgentConfig(ctx context.Context, entity string, config *corev2.AgentConfig) error {
+	args := s.Called(ctx, entity, config)
+	return args.Error(0)
+}
+
+// GetAgentConfigWatcher ...
+func (s *MockStore) GetAgentConfigWatcher(ctx context.Context) <-chan store.WatchEventAgentConfig {
+	args := s.Called(ctx)
+	return args.Get(0).(<-chan store.WatchEventAgentConfig)
+}
@@ -0,0 +1,24 @@
+package mockstore
+
+import (
+	"context"
+	"time"
+)
+
+// GrantCheckExecutionLease ...
+func (s *MockStore) GrantCheckExecutionLease(ctx context.Context, ttl time.Duration) (int64, error) {
+	args := s.Called(ctx, ttl)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// AcquireCheckExecution ...
+func (s *MockStore) AcquireCheckExecution(ctx context.Context, check, agent string, max int, lease int64) (bool, error) {
+	args := s.Called(ctx, check, agent, max, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+// ReleaseCheckExecution ...
+func (s *MockStore) ReleaseCheckExecution(ctx context.Context, check, agent string) error {
+	args := s.Called(ctx, check, agent)
+	return args.Error(0)
+}
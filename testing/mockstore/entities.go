@@ -3,6 +3,7 @@ package mockstore
 import (
 	"context"
 
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/types"
 )
@@ -25,12 +26,30 @@ func (s *MockStore) GetEntities(ctx context.Context, pred *store.SelectionPredic
 	return args.Get(0).([]*types.Entity), args.Error(1)
 }
 
+// GetEntitiesBySubscription ...
+func (s *MockStore) GetEntitiesBySubscription(ctx context.Context, subscription string) ([]*types.Entity, error) {
+	args := s.Called(ctx, subscription)
+	return args.Get(0).([]*types.Entity), args.Error(1)
+}
+
 // GetEntityByName ...
 func (s *MockStore) GetEntityByName(ctx context.Context, id string) (*types.Entity, error) {
 	args := s.Called(ctx, id)
 	return args.Get(0).(*types.Entity), args.Error(1)
 }
 
+// LabelEntities ...
+func (s *MockStore) LabelEntities(ctx context.Context, labeling *corev2.EntityLabeling, policy *corev2.LabelPolicy) ([]string, error) {
+	args := s.Called(ctx, labeling, policy)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// RenameEntity ...
+func (s *MockStore) RenameEntity(ctx context.Context, name, newName string, merge bool) (*types.Entity, error) {
+	args := s.Called(ctx, name, newName, merge)
+	return args.Get(0).(*types.Entity), args.Error(1)
+}
+
 // UpdateEntity ...
 func (s *MockStore) UpdateEntity(ctx context.Context, e *types.Entity) error {
 	args := s.Called(ctx, e)
@@ -36,3 +36,15 @@ func (s *MockStore) UpdateEvent(ctx context.Context, event *corev2.Event) (*core
 	args := s.Called(event)
 	return args.Get(0).(*corev2.Event), args.Get(1).(*corev2.Event), args.Error(2)
 }
+
+// GetEventPipeline ...
+func (s *MockStore) GetEventPipeline(ctx context.Context, entityName, checkID string) (*corev2.EventPipeline, error) {
+	args := s.Called(ctx, entityName, checkID)
+	return args.Get(0).(*corev2.EventPipeline), args.Error(1)
+}
+
+// UpdateEventPipeline ...
+func (s *MockStore) UpdateEventPipeline(ctx context.Context, pipeline *corev2.EventPipeline) error {
+	args := s.Called(ctx, pipeline)
+	return args.Error(0)
+}
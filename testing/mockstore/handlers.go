@@ -3,6 +3,7 @@ package mockstore
 import (
 	"context"
 
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/types"
 )
@@ -30,3 +31,15 @@ func (s *MockStore) UpdateHandler(ctx context.Context, handler *types.Handler) e
 	args := s.Called(handler)
 	return args.Error(0)
 }
+
+// GetHandlerOutputs ...
+func (s *MockStore) GetHandlerOutputs(ctx context.Context, name string) ([]*corev2.HandlerOutput, error) {
+	args := s.Called(ctx, name)
+	return args.Get(0).([]*corev2.HandlerOutput), args.Error(1)
+}
+
+// AddHandlerOutput ...
+func (s *MockStore) AddHandlerOutput(ctx context.Context, name string, output *corev2.HandlerOutput, history int) error {
+	args := s.Called(ctx, name, output, history)
+	return args.Error(0)
+}
@@ -0,0 +1,20 @@
+package mockstore
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// GetLabelPolicy ...
+func (s *MockStore) GetLabelPolicy(ctx context.Context) (*corev2.LabelPolicy, error) {
+	args := s.Called(ctx)
+	policy, _ := args.Get(0).(*corev2.LabelPolicy)
+	return policy, args.Error(1)
+}
+
+// UpdateLabelPolicy ...
+func (s *MockStore) UpdateLabelPolicy(ctx context.Context, policy *corev2.LabelPolicy) error {
+	args := s.Called(ctx, policy)
+	return args.Error(0)
+}
@@ -0,0 +1,33 @@
+package mockstore
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// GetManagedFields ...
+func (s *MockStore) GetManagedFields(ctx context.Context, resource corev2.Resource) (*corev2.ManagedFields, error) {
+	args := s.Called(ctx, resource)
+	fields, _ := args.Get(0).(*corev2.ManagedFields)
+	return fields, args.Error(1)
+}
+
+// UpdateManagedFields ...
+func (s *MockStore) UpdateManagedFields(ctx context.Context, resource corev2.Resource, fields *corev2.ManagedFields) error {
+	args := s.Called(ctx, resource, fields)
+	return args.Error(0)
+}
+
+// GetManagedResource ...
+func (s *MockStore) GetManagedResource(ctx context.Context, name string, resource corev2.Resource) (*corev2.ManagedFields, int64, error) {
+	args := s.Called(ctx, name, resource)
+	fields, _ := args.Get(0).(*corev2.ManagedFields)
+	return fields, args.Get(1).(int64), args.Error(2)
+}
+
+// ApplyManagedResource ...
+func (s *MockStore) ApplyManagedResource(ctx context.Context, resource corev2.Resource, fields *corev2.ManagedFields, revision int64) error {
+	args := s.Called(ctx, resource, fields, revision)
+	return args.Error(0)
+}
@@ -0,0 +1,33 @@
+package mockstore
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/sensu-go/api/core/v2"
+)
+
+// DeleteRouteByName ...
+func (s *MockStore) DeleteRouteByName(ctx context.Context, name string) error {
+	args := s.Called(ctx, name)
+	return args.Error(0)
+}
+
+// GetRouteByName ...
+func (s *MockStore) GetRouteByName(ctx context.Context, name string) (*corev2.Route, error) {
+	args := s.Called(ctx, name)
+	route, _ := args.Get(0).(*corev2.Route)
+	return route, args.Error(1)
+}
+
+// GetRoutes ...
+func (s *MockStore) GetRoutes(ctx context.Context) ([]*corev2.Route, error) {
+	args := s.Called(ctx)
+	routes, _ := args.Get(0).([]*corev2.Route)
+	return routes, args.Error(1)
+}
+
+// UpdateRoute ...
+func (s *MockStore) UpdateRoute(ctx context.Context, route *corev2.Route) error {
+	args := s.Called(ctx, route)
+	return args.Error(0)
+}
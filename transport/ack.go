@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+)
+
+const (
+	// MessageTypeAckedEvent is the message type of the events the agent asks
+	// the backend to acknowledge. Its payload is the ID of the event, a
+	// newline, and the serialized event.
+	MessageTypeAckedEvent = "acked_event"
+
+	// MessageTypeEventAck is the message type of the acknowledgements of the
+	// events, sent by the backend. Its payload is an EventAck, serialized as
+	// JSON.
+	MessageTypeEventAck = "event_ack"
+)
+
+// EventAck acknowledges an event sent by the agent.
+type EventAck struct {
+	// ID is the ID of the acknowledged event.
+	ID string `json:"id"`
+
+	// Error is the reason the event could not be published to the message
+	// bus. The agent sends the event again if it is set, unless the event is
+	// rejected.
+	Error string `json:"error,omitempty"`
+
+	// Rejected is true if the event can never be published, e.g. because it
+	// is invalid. The agent drops it rather than sending it again.
+	Rejected bool `json:"rejected,omitempty"`
+}
+
+// NewAckedEventPayload returns the payload of the acked_event message of the
+// serialized event with the given ID.
+func NewAckedEventPayload(id string, event []byte) []byte {
+	payload := make([]byte, 0, len(id)+1+len(event))
+	payload = append(payload, id...)
+	payload = append(payload, '\n')
+	return append(payload, event...)
+}
+
+// SplitAckedEventPayload returns the ID and the serialized event of the
+// payload of an acked_event message.
+func SplitAckedEventPayload(payload []byte) (string, []byte, error) {
+	i := bytes.IndexByte(payload, '\n')
+	if i < 1 {
+		return "", nil, errors.New("acked event without ID")
+	}
+	return string(payload[:i]), payload[i+1:], nil
+}
@@ -0,0 +1,21 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckedEventPayload(t *testing.T) {
+	payload := NewAckedEventPayload("42", []byte("event\nwith newline"))
+	id, event, err := SplitAckedEventPayload(payload)
+	require.NoError(t, err)
+	assert.Equal(t, "42", id)
+	assert.Equal(t, []byte("event\nwith newline"), event)
+
+	_, _, err = SplitAckedEventPayload([]byte("\nevent"))
+	assert.Error(t, err)
+	_, _, err = SplitAckedEventPayload([]byte("event"))
+	assert.Error(t, err)
+}
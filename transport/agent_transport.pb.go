@@ -0,0 +1,204 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: agent_transport.proto
+
+package transport
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Frame is a message exchanged between agents and backends over the gRPC
+// transport.
+type Frame struct {
+	// Type is the type of the message (event, etc).
+	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	// Payload is the serialized message.
+	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Frame) Reset()         { *m = Frame{} }
+func (m *Frame) String() string { return proto.CompactTextString(m) }
+func (*Frame) ProtoMessage()    {}
+func (*Frame) Descriptor() ([]byte, []int) {
+	return fileDescriptor_agent_transport_608008a244d37eb9, []int{0}
+}
+func (m *Frame) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Frame.Unmarshal(m, b)
+}
+func (m *Frame) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Frame.Marshal(b, m, deterministic)
+}
+func (dst *Frame) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Frame.Merge(dst, src)
+}
+func (m *Frame) XXX_Size() int {
+	return xxx_messageInfo_Frame.Size(m)
+}
+func (m *Frame) XXX_DiscardUnknown() {
+	xxx_messageInfo_Frame.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Frame proto.InternalMessageInfo
+
+func (m *Frame) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Frame) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Frame)(nil), "sensu.transport.Frame")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for AgentTransport service
+
+type AgentTransportClient interface {
+	// Stream exchanges frames between an agent and a backend for the whole
+	// duration of the agent session.
+	Stream(ctx context.Context, opts ...grpc.CallOption) (AgentTransport_StreamClient, error)
+}
+
+type agentTransportClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAgentTransportClient(cc *grpc.ClientConn) AgentTransportClient {
+	return &agentTransportClient{cc}
+}
+
+func (c *agentTransportClient) Stream(ctx context.Context, opts ...grpc.CallOption) (AgentTransport_StreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_AgentTransport_serviceDesc.Streams[0], c.cc, "/sensu.transport.AgentTransport/Stream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentTransportStreamClient{stream}
+	return x, nil
+}
+
+type AgentTransport_StreamClient interface {
+	Send(*Frame) error
+	Recv() (*Frame, error)
+	grpc.ClientStream
+}
+
+type agentTransportStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentTransportStreamClient) Send(m *Frame) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *agentTransportStreamClient) Recv() (*Frame, error) {
+	m := new(Frame)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for AgentTransport service
+
+type AgentTransportServer interface {
+	// Stream exchanges frames between an agent and a backend for the whole
+	// duration of the agent session.
+	Stream(AgentTransport_StreamServer) error
+}
+
+func RegisterAgentTransportServer(s *grpc.Server, srv AgentTransportServer) {
+	s.RegisterService(&_AgentTransport_serviceDesc, srv)
+}
+
+func _AgentTransport_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentTransportServer).Stream(&agentTransportStreamServer{stream})
+}
+
+type AgentTransport_StreamServer interface {
+	Send(*Frame) error
+	Recv() (*Frame, error)
+	grpc.ServerStream
+}
+
+type agentTransportStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentTransportStreamServer) Send(m *Frame) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *agentTransportStreamServer) Recv() (*Frame, error) {
+	m := new(Frame)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _AgentTransport_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "sensu.transport.AgentTransport",
+	HandlerType: (*AgentTransportServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _AgentTransport_Stream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "agent_transport.proto",
+}
+
+func init() {
+	proto.RegisterFile("agent_transport.proto", fileDescriptor_agent_transport_608008a244d37eb9)
+}
+
+var fileDescriptor_agent_transport_608008a244d37eb9 = []byte{
+	// 148 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4d, 0x4c, 0x4f, 0xcd,
+	0x2b, 0x89, 0x2f, 0x29, 0x4a, 0xcc, 0x2b, 0x2e, 0xc8, 0x2f, 0x2a, 0xd1, 0x2b, 0x28, 0xca, 0x2f,
+	0xc9, 0x17, 0xe2, 0x2f, 0x4e, 0xcd, 0x2b, 0x2e, 0xd5, 0x83, 0x0b, 0x2b, 0x99, 0x72, 0xb1, 0xba,
+	0x15, 0x25, 0xe6, 0xa6, 0x0a, 0x09, 0x71, 0xb1, 0x94, 0x54, 0x16, 0xa4, 0x4a, 0x30, 0x2a, 0x30,
+	0x6a, 0x70, 0x06, 0x81, 0xd9, 0x42, 0x12, 0x5c, 0xec, 0x05, 0x89, 0x95, 0x39, 0xf9, 0x89, 0x29,
+	0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0x3c, 0x41, 0x30, 0xae, 0x51, 0x00, 0x17, 0x9f, 0x23, 0xc8, 0x82,
+	0x10, 0x98, 0x41, 0x42, 0x76, 0x5c, 0x6c, 0xc1, 0x25, 0x45, 0xa9, 0x89, 0xb9, 0x42, 0x62, 0x7a,
+	0x68, 0x96, 0xe8, 0x81, 0x6d, 0x90, 0xc2, 0x21, 0xae, 0xc4, 0xa0, 0xc1, 0x68, 0xc0, 0xe8, 0xc4,
+	0x1d, 0xc5, 0x09, 0x97, 0x48, 0x62, 0x03, 0xbb, 0xd6, 0x18, 0x30, 0x00, 0x92, 0xce, 0x5e, 0xc3,
+	0xc6, 0x00, 0x00, 0x00,
+}
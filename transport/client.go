@@ -2,8 +2,11 @@ package transport
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -12,7 +15,7 @@ import (
 
 // connect establish the connection to a given websocket backend and returns it
 // along with any error encountered
-func connect(wsServerURL string, tlsOpts *types.TLSOptions, requestHeader http.Header, handshakeTimeout int) (*websocket.Conn, http.Header, error) {
+func connect(wsServerURL string, tlsOpts *types.TLSOptions, requestHeader http.Header, handshakeTimeout int, compression Compression) (*websocket.Conn, http.Header, error) {
 	// TODO(grep): configurable max sendq depth
 	u, err := url.Parse(wsServerURL)
 	if err != nil {
@@ -23,8 +26,9 @@ func connect(wsServerURL string, tlsOpts *types.TLSOptions, requestHeader http.H
 		handshakeTimeout = 15
 	}
 	dialer := websocket.Dialer{
-		HandshakeTimeout: time.Second * time.Duration(handshakeTimeout),
-		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout:  time.Second * time.Duration(handshakeTimeout),
+		Proxy:             http.ProxyFromEnvironment,
+		EnableCompression: compression.Enabled,
 	}
 
 	if tlsOpts != nil {
@@ -38,6 +42,9 @@ func connect(wsServerURL string, tlsOpts *types.TLSOptions, requestHeader http.H
 	if err != nil {
 		if resp != nil {
 			if err == websocket.ErrBadHandshake {
+				if reason := handshakeFailureReason(resp); reason != "" {
+					return nil, resp.Header, fmt.Errorf("handshake failed with status %d: %s", resp.StatusCode, reason)
+				}
 				return nil, resp.Header, fmt.Errorf("handshake failed with status %d", resp.StatusCode)
 			}
 			return nil, resp.Header, fmt.Errorf("connection failed with status %d", resp.StatusCode)
@@ -45,14 +52,33 @@ func connect(wsServerURL string, tlsOpts *types.TLSOptions, requestHeader http.H
 		return nil, nil, err
 	}
 
+	if err := compression.Configure(conn); err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+
 	return conn, resp.Header, nil
 }
 
+// handshakeFailureReason returns the reason of the handshake failure given by
+// the backend in the body of its response, e.g. a reached session limit.
+func handshakeFailureReason(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(body))
+}
+
 // Connect causes the transport Client to connect to a given websocket backend.
 // This is a thin wrapper around a websocket connection that makes the
-// connection safe for concurrent use by multiple goroutines.
-func Connect(wsServerURL string, tlsOpts *types.TLSOptions, requestHeader http.Header, handshakeTimeout int) (Transport, http.Header, error) {
-	conn, resp, err := connect(wsServerURL, tlsOpts, requestHeader, handshakeTimeout)
+// connection safe for concurrent use by multiple goroutines. The messages are
+// compressed if the backend also supports the compression.
+func Connect(wsServerURL string, tlsOpts *types.TLSOptions, requestHeader http.Header, handshakeTimeout int, compression Compression) (Transport, http.Header, error) {
+	conn, resp, err := connect(wsServerURL, tlsOpts, requestHeader, handshakeTimeout, compression)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"compress/flate"
+	"fmt"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultCompressionLevel is the default flate compression level of
+// WebSocket connections, favoring speed over compression ratio.
+const DefaultCompressionLevel = flate.BestSpeed
+
+// Compression configures the permessage-deflate WebSocket extension, which
+// compresses the messages exchanged between agents and backends. Messages
+// are only compressed when both peers enable the extension.
+type Compression struct {
+	// Enabled specifies whether the extension is negotiated during the
+	// WebSocket handshake.
+	Enabled bool
+
+	// Level is the flate compression level of the messages sent, from -2
+	// (Huffman coding only) to 9 (best compression).
+	Level int
+}
+
+// Validate returns an error if the compression level is not supported.
+func (c Compression) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Level < flate.HuffmanOnly || c.Level > flate.BestCompression {
+		return fmt.Errorf("invalid compression level %d, must be between %d and %d",
+			c.Level, flate.HuffmanOnly, flate.BestCompression)
+	}
+	return nil
+}
+
+// Upgrader returns a WebSocket upgrader negotiating the compression.
+func (c Compression) Upgrader() *websocket.Upgrader {
+	return &websocket.Upgrader{EnableCompression: c.Enabled}
+}
+
+// Configure sets the compression of the messages sent over an established
+// connection. It has no effect if the extension was not negotiated.
+func (c Compression) Configure(conn *websocket.Conn) error {
+	conn.EnableWriteCompression(c.Enabled)
+	if !c.Enabled {
+		return nil
+	}
+	return conn.SetCompressionLevel(c.Level)
+}
@@ -0,0 +1,71 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionValidate(t *testing.T) {
+	assert.NoError(t, Compression{}.Validate())
+	assert.NoError(t, Compression{Enabled: true, Level: DefaultCompressionLevel}.Validate())
+	assert.NoError(t, Compression{Enabled: false, Level: 42}.Validate())
+	assert.Error(t, Compression{Enabled: true, Level: 10}.Validate())
+	assert.Error(t, Compression{Enabled: true, Level: -3}.Validate())
+}
+
+func TestCompressionNegotiation(t *testing.T) {
+	tests := []struct {
+		name           string
+		server         Compression
+		client         Compression
+		wantNegotiated bool
+	}{
+		{
+			name:           "enabled on both peers",
+			server:         Compression{Enabled: true, Level: DefaultCompressionLevel},
+			client:         Compression{Enabled: true, Level: 9},
+			wantNegotiated: true,
+		},
+		{
+			name:   "disabled on the server",
+			client: Compression{Enabled: true, Level: DefaultCompressionLevel},
+		},
+		{
+			name:   "disabled on the client",
+			server: Compression{Enabled: true, Level: DefaultCompressionLevel},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := []byte(strings.Repeat("check output ", 1000))
+			received := make(chan *Message, 1)
+			server := NewServerWithCompression(tt.server)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				transport, err := server.Serve(w, r)
+				require.NoError(t, err)
+				msg, err := transport.Receive()
+				assert.NoError(t, err)
+				received <- msg
+			}))
+			defer ts.Close()
+
+			client, header, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5, tt.client)
+			require.NoError(t, err)
+			defer client.Close()
+
+			negotiated := strings.Contains(header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+			assert.Equal(t, tt.wantNegotiated, negotiated)
+
+			require.NoError(t, client.Send(&Message{Type: "event", Payload: payload}))
+			msg := <-received
+			assert.Equal(t, "event", msg.Type)
+			assert.Equal(t, payload, msg.Payload)
+		})
+	}
+}
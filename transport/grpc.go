@@ -0,0 +1,458 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sensu/sensu-go/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// GRPCScheme is the URL scheme of the backends reached over the gRPC
+	// transport.
+	GRPCScheme = "grpc"
+
+	// GRPCSecureScheme is the URL scheme of the backends reached over the gRPC
+	// transport, with TLS.
+	GRPCSecureScheme = "grpcs"
+
+	// grpcAcceptedKey is the metadata key sent by the backends accepting a
+	// session, telling it apart from the trailers-only responses of the
+	// rejected sessions.
+	grpcAcceptedKey = "sensu-session-accepted"
+
+	// grpcKeepaliveMinTime is the minimum interval between the keepalive pings
+	// of the agents accepted by the backends.
+	grpcKeepaliveMinTime = 5 * time.Second
+)
+
+// grpcReservedHeaders are the headers used by gRPC itself, which are not
+// exchanged as metadata.
+var grpcReservedHeaders = map[string]bool{
+	"connection":   true,
+	"content-type": true,
+	"te":           true,
+	"user-agent":   true,
+}
+
+// grpcStream is the bidirectional stream of a gRPC transport, on either the
+// agent or the backend side.
+type grpcStream interface {
+	Send(*Frame) error
+	Recv() (*Frame, error)
+}
+
+// A GRPCTransport is a connection between sensu Agents and Backends over a
+// bidirectional gRPC stream. Unlike WebSocket connections, gRPC connections
+// are multiplexed and flow controlled by HTTP/2, which makes them suitable for
+// agents behind HTTP/2-aware load balancers.
+type GRPCTransport struct {
+	stream    grpcStream
+	closeFunc func()
+	closed    bool
+	done      chan struct{}
+	doneOnce  sync.Once
+	mutex     *sync.RWMutex
+	sendMutex sync.Mutex
+}
+
+func newGRPCTransport(stream grpcStream, closeFunc func()) *GRPCTransport {
+	return &GRPCTransport{
+		stream:    stream,
+		closeFunc: closeFunc,
+		done:      make(chan struct{}),
+		mutex:     &sync.RWMutex{},
+	}
+}
+
+// IsGRPCURL returns true if the backend URL is reached over the gRPC
+// transport.
+func IsGRPCURL(backendURL string) bool {
+	return strings.HasPrefix(backendURL, GRPCScheme+"://") || strings.HasPrefix(backendURL, GRPCSecureScheme+"://")
+}
+
+// ConnectGRPC causes the transport Client to connect to a given gRPC backend,
+// with a grpc:// URL, or a grpcs:// URL for TLS. The request header is sent to
+// the backend as the stream metadata, and the response header is built from
+// the metadata sent back. The connection is kept alive with HTTP/2 pings, sent
+// at the heartbeat interval.
+func ConnectGRPC(serverURL string, tlsOpts *types.TLSOptions, requestHeader http.Header, handshakeTimeout, heartbeatInterval, heartbeatTimeout int) (Transport, http.Header, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if handshakeTimeout < 1 {
+		handshakeTimeout = 15
+	}
+	if heartbeatInterval < 1 {
+		heartbeatInterval = 30
+	}
+	if heartbeatTimeout < 1 {
+		heartbeatTimeout = 45
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                time.Duration(heartbeatInterval) * time.Second,
+			Timeout:             time.Duration(heartbeatTimeout) * time.Second,
+			PermitWithoutStream: true,
+		}),
+	}
+
+	switch u.Scheme {
+	case GRPCScheme:
+		opts = append(opts, grpc.WithInsecure())
+	case GRPCSecureScheme:
+		tlsConfig := &tls.Config{}
+		if tlsOpts != nil {
+			tlsConfig, err = tlsOpts.ToClientTLSConfig()
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	default:
+		return nil, nil, fmt.Errorf("invalid gRPC backend URL scheme %q", u.Scheme)
+	}
+
+	dialCtx, dialCancel := context.WithTimeout(context.Background(), time.Duration(handshakeTimeout)*time.Second)
+	defer dialCancel()
+	conn, err := grpc.DialContext(dialCtx, u.Host, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	closeFunc := func() {
+		cancel()
+		_ = conn.Close()
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, headerToMetadata(requestHeader))
+	stream, err := NewAgentTransportClient(conn).Stream(ctx)
+	if err != nil {
+		closeFunc()
+		return nil, nil, err
+	}
+
+	// Wait for the backend to accept the session
+	md, err := stream.Header()
+	if err == nil && len(md.Get(grpcAcceptedKey)) == 0 {
+		// The session was rejected, its status is received with the trailers
+		_, err = stream.Recv()
+		if err == nil || err == io.EOF {
+			err = fmt.Errorf("session not accepted by the backend")
+		}
+	}
+	if err != nil {
+		closeFunc()
+		if s, ok := status.FromError(err); ok {
+			return nil, nil, fmt.Errorf("handshake failed with code %s: %s", s.Code(), s.Message())
+		}
+		return nil, nil, err
+	}
+
+	t := newGRPCTransport(stream, func() {
+		_ = stream.CloseSend()
+		closeFunc()
+	})
+	return t, metadataToHeader(md), nil
+}
+
+// Close closes the gRPC stream.
+func (t *GRPCTransport) Close() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	t.finish()
+	return nil
+}
+
+// finish releases the gRPC stream.
+func (t *GRPCTransport) finish() {
+	t.doneOnce.Do(func() {
+		close(t.done)
+		if t.closeFunc != nil {
+			t.closeFunc()
+		}
+	})
+}
+
+// Closed returns true if the underlying gRPC stream has been closed.
+func (t *GRPCTransport) Closed() bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.closed
+}
+
+// Heartbeat does nothing for gRPC transports, whose connections are kept
+// alive with HTTP/2 pings configured when connecting.
+func (t *GRPCTransport) Heartbeat(ctx context.Context, interval, timeout, maxMissed int) {}
+
+// Receive a message over the gRPC stream. Like Send, returns either a
+// ClosedError or a ConnectionError if unable to receive a message. Receive
+// blocks until the stream has a message ready.
+func (t *GRPCTransport) Receive() (*Message, error) {
+	t.mutex.RLock()
+	if t.closed {
+		t.mutex.RUnlock()
+		return nil, ClosedError{"the grpc stream is no longer open"}
+	}
+	t.mutex.RUnlock()
+
+	frame, err := t.stream.Recv()
+	if err != nil {
+		t.mutex.Lock()
+		t.closed = true
+		t.finish()
+		t.mutex.Unlock()
+
+		if err == io.EOF || status.Code(err) == codes.Canceled {
+			return nil, ClosedError{err.Error()}
+		}
+		return nil, ConnectionError{err.Error()}
+	}
+
+	msg := msgPool.Get().(*Message)
+	msg.Type = frame.Type
+	msg.Payload = frame.Payload
+	return msg, nil
+}
+
+// Send a message over the gRPC stream. If the stream has been closed, returns
+// a ClosedError. Returns a ConnectionError if the stream returns an error
+// while sending.
+func (t *GRPCTransport) Send(m *Message) (err error) {
+	defer msgPool.Put(m)
+	defer func() {
+		if m.SendCallback != nil {
+			m.SendCallback(err)
+		}
+	}()
+	t.mutex.RLock()
+	if t.closed {
+		t.mutex.RUnlock()
+		return ClosedError{"the grpc stream is no longer open"}
+	}
+	t.mutex.RUnlock()
+
+	// gRPC streams are not safe for concurrent sends
+	t.sendMutex.Lock()
+	err = t.stream.Send(&Frame{Type: m.Type, Payload: m.Payload})
+	t.sendMutex.Unlock()
+	if err != nil {
+		t.mutex.Lock()
+		t.closed = true
+		t.finish()
+		t.mutex.Unlock()
+		if err == io.EOF {
+			return ClosedError{err.Error()}
+		}
+		return ConnectionError{err.Error()}
+	}
+
+	return nil
+}
+
+// NewGRPCServer returns a gRPC server accepting the agent sessions opened with
+// the gRPC transport. The sessions are handled by handler like WebSocket
+// sessions: the stream metadata is given as the header of the request, and
+// handler calls UpgradeGRPC instead of upgrading the connection to the
+// WebSocket protocol. Responses written by handler without calling UpgradeGRPC
+// reject the session. The server uses TLS if tlsConfig is not nil.
+func NewGRPCServer(handler http.Handler, tlsConfig *tls.Config) *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             grpcKeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(opts...)
+	RegisterAgentTransportServer(server, &grpcServer{handler: handler})
+	return server
+}
+
+// grpcServer implements AgentTransportServer with an http.Handler.
+type grpcServer struct {
+	handler http.Handler
+}
+
+// grpcUpgradeKey is the request context key of the gRPC streams to upgrade.
+type grpcUpgradeKey struct{}
+
+// grpcUpgrade is a gRPC stream waiting to be upgraded to a transport.
+type grpcUpgrade struct {
+	stream    AgentTransport_StreamServer
+	transport *GRPCTransport
+}
+
+// Stream handles an agent session.
+func (s *grpcServer) Stream(stream AgentTransport_StreamServer) error {
+	ctx := stream.Context()
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	upgrade := &grpcUpgrade{stream: stream}
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	req = req.WithContext(context.WithValue(ctx, grpcUpgradeKey{}, upgrade))
+	req.Header = metadataToHeader(md)
+	if p, ok := peerAddr(ctx); ok {
+		req.RemoteAddr = p
+	}
+	req.TLS = peerTLS(ctx)
+
+	w := &grpcResponseWriter{header: make(http.Header)}
+	s.handler.ServeHTTP(w, req)
+
+	if upgrade.transport == nil {
+		return status.Error(httpStatusCode(w.status), strings.TrimSpace(w.body.String()))
+	}
+
+	// Keep the stream open until the session ends
+	select {
+	case <-upgrade.transport.done:
+	case <-ctx.Done():
+		_ = upgrade.transport.Close()
+	}
+	return nil
+}
+
+// IsGRPC returns true if the request is an agent session opened with the gRPC
+// transport.
+func IsGRPC(r *http.Request) bool {
+	_, ok := r.Context().Value(grpcUpgradeKey{}).(*grpcUpgrade)
+	return ok
+}
+
+// UpgradeGRPC accepts an agent session opened with the gRPC transport, and
+// returns its transport. The response header is sent to the agent as the
+// stream metadata.
+func UpgradeGRPC(w http.ResponseWriter, r *http.Request, responseHeader http.Header) (Transport, error) {
+	upgrade, ok := r.Context().Value(grpcUpgradeKey{}).(*grpcUpgrade)
+	if !ok {
+		return nil, fmt.Errorf("not a grpc stream")
+	}
+	if upgrade.transport != nil {
+		return nil, fmt.Errorf("grpc stream already upgraded")
+	}
+	md := headerToMetadata(responseHeader)
+	md.Set(grpcAcceptedKey, "true")
+	if err := upgrade.stream.SendHeader(md); err != nil {
+		return nil, err
+	}
+	upgrade.transport = newGRPCTransport(upgrade.stream, nil)
+	return upgrade.transport, nil
+}
+
+// grpcResponseWriter records the response of the handler rejecting a session.
+type grpcResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *grpcResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *grpcResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *grpcResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+// httpStatusCode returns the gRPC status code matching the HTTP status.
+func httpStatusCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	default:
+		return codes.Internal
+	}
+}
+
+// headerToMetadata returns the gRPC metadata holding the HTTP header.
+func headerToMetadata(header http.Header) metadata.MD {
+	md := metadata.MD{}
+	for key, values := range header {
+		key = strings.ToLower(key)
+		if grpcReservedHeaders[key] || strings.HasPrefix(key, "grpc-") {
+			continue
+		}
+		md[key] = append(md[key], values...)
+	}
+	return md
+}
+
+// metadataToHeader returns the HTTP header holding the gRPC metadata.
+func metadataToHeader(md metadata.MD) http.Header {
+	header := http.Header{}
+	for key, values := range md {
+		if grpcReservedHeaders[key] || key == grpcAcceptedKey || strings.HasPrefix(key, "grpc-") || strings.HasPrefix(key, ":") {
+			continue
+		}
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	return header
+}
+
+// peerAddr returns the address of the peer of the stream.
+func peerAddr(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "", false
+	}
+	return p.Addr.String(), true
+}
+
+// peerTLS returns the state of the TLS connection of the peer of the stream,
+// or nil if the stream doesn't use TLS.
+func peerTLS(ctx context.Context) *tls.ConnectionState {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+	return &info.State
+}
@@ -0,0 +1,107 @@
+package transport
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func startGRPCServer(t *testing.T, handler http.HandlerFunc) (string, *grpc.Server) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := NewGRPCServer(handler, nil)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return fmt.Sprintf("grpc://%s", listener.Addr()), server
+}
+
+func TestGRPCTransportSendReceive(t *testing.T) {
+	done := make(chan struct{})
+	url, server := startGRPCServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, IsGRPC(r))
+		assert.Equal(t, "agent", r.Header.Get(HeaderKeyAgentName))
+		responseHeader := http.Header{}
+		responseHeader.Add("Accept", "application/octet-stream")
+		responseHeader.Add("Accept", "application/json")
+		transport, err := UpgradeGRPC(w, r, responseHeader)
+		require.NoError(t, err)
+
+		go func() {
+			defer close(done)
+			msg, err := transport.Receive()
+			assert.NoError(t, err)
+			assert.Equal(t, "ping", msg.Type)
+			assert.Equal(t, []byte("payload"), msg.Payload)
+			assert.NoError(t, transport.Send(&Message{Type: "pong", Payload: msg.Payload}))
+		}()
+	})
+	defer server.Stop()
+
+	header := http.Header{}
+	header.Set(HeaderKeyAgentName, "agent")
+	clientTransport, respHeader, err := ConnectGRPC(url, nil, header, 5, 0, 0)
+	require.NoError(t, err)
+	defer clientTransport.Close()
+	assert.Equal(t, []string{"application/octet-stream", "application/json"}, respHeader["Accept"])
+
+	require.NoError(t, clientTransport.Send(&Message{Type: "ping", Payload: []byte("payload")}))
+	msg, err := clientTransport.Receive()
+	require.NoError(t, err)
+	assert.Equal(t, "pong", msg.Type)
+	assert.Equal(t, []byte("payload"), msg.Payload)
+	<-done
+}
+
+func TestGRPCTransportRejected(t *testing.T) {
+	url, server := startGRPCServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+	})
+	defer server.Stop()
+
+	_, _, err := ConnectGRPC(url, nil, nil, 5, 0, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unauthenticated")
+	assert.Contains(t, err.Error(), "bad credentials")
+}
+
+func TestClosedGRPCTransport(t *testing.T) {
+	url, server := startGRPCServer(t, func(w http.ResponseWriter, r *http.Request) {
+		transport, err := UpgradeGRPC(w, r, nil)
+		require.NoError(t, err)
+		require.NoError(t, transport.Close())
+	})
+	defer server.Stop()
+
+	clientTransport, _, err := ConnectGRPC(url, nil, nil, 5, 0, 0)
+	require.NoError(t, err)
+
+	// At this point we should receive a connection closed message.
+	_, err = clientTransport.Receive()
+	assert.IsType(t, ClosedError{}, err)
+	assert.True(t, clientTransport.Closed())
+
+	err = clientTransport.Send(&Message{Type: "testMessageType", Payload: []byte{}})
+	assert.IsType(t, ClosedError{}, err)
+}
+
+func TestUpgradeGRPCNotGRPC(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	assert.False(t, IsGRPC(r))
+	_, err = UpgradeGRPC(nil, r, nil)
+	assert.Error(t, err)
+}
+
+func TestIsGRPCURL(t *testing.T) {
+	assert.True(t, IsGRPCURL("grpc://127.0.0.1:8082"))
+	assert.True(t, IsGRPCURL("grpcs://127.0.0.1:8082"))
+	assert.False(t, IsGRPCURL("ws://127.0.0.1:8081"))
+	assert.False(t, IsGRPCURL("wss://127.0.0.1:8081"))
+}
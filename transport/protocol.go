@@ -0,0 +1,99 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// HeaderKeyProtocolVersion is the HTTP header specifying the version of
+	// the agent protocol. The agent sends the latest version it speaks, and
+	// the backend answers with the version negotiated for the session.
+	HeaderKeyProtocolVersion = "Sensu-ProtocolVersion"
+
+	// ProtocolVersion is the latest version of the agent protocol.
+	//
+	// 1: the peers that do not send their protocol version.
+	// 2: the agent applies the agent configs and the reconnect requests sent
+	//    by the backend.
+	// 3: the backend acknowledges the events the agent asks it to.
+	// 4: the backend tells the agent about the messages it rejects.
+	// 5: the backend tells the agent about the events it rejects in their
+	//    acknowledgement.
+	ProtocolVersion = 5
+
+	// MinProtocolVersion is the oldest version of the agent protocol still
+	// supported.
+	MinProtocolVersion = 1
+)
+
+// A Feature is a part of the agent protocol that is only understood by the
+// peers speaking a recent enough protocol version.
+type Feature string
+
+const (
+	// FeatureAgentConfig is the push of the agent configs by the backend.
+	FeatureAgentConfig Feature = "agent_config"
+
+	// FeatureReconnect is the request of the backend to the agent to
+	// reconnect to another backend.
+	FeatureReconnect Feature = "reconnect"
+
+	// FeatureEventAck is the acknowledgement by the backend of the events
+	// published to the message bus.
+	FeatureEventAck Feature = "event_ack"
+
+	// FeatureMessageRejection is the notification by the backend of the
+	// messages it rejects, such as the messages exceeding its maximum message
+	// size.
+	FeatureMessageRejection Feature = "message_rejection"
+
+	// FeatureEventRejection is the acknowledgement by the backend of the
+	// events it will never publish, which the agent does not send again.
+	FeatureEventRejection Feature = "event_rejection"
+)
+
+// featureVersions are the protocol versions that introduced the features.
+var featureVersions = map[Feature]int{
+	FeatureAgentConfig:      2,
+	FeatureReconnect:        2,
+	FeatureEventAck:         3,
+	FeatureMessageRejection: 4,
+	FeatureEventRejection:   5,
+}
+
+// Supports returns true if the feature is part of the given protocol version.
+func Supports(version int, feature Feature) bool {
+	introduced, ok := featureVersions[feature]
+	return ok && version >= introduced
+}
+
+// ParseProtocolVersion returns the protocol version specified by the header,
+// or 1 if the peer did not specify one.
+func ParseProtocolVersion(header http.Header) (int, error) {
+	value := header.Get(HeaderKeyProtocolVersion)
+	if value == "" {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid protocol version %q", value)
+	}
+	return version, nil
+}
+
+// NegotiateProtocolVersion returns the protocol version used with a peer
+// speaking the given version: the latest version both peers speak. It returns
+// an error if the peer is too old to be supported.
+func NegotiateProtocolVersion(version int) (int, error) {
+	if version < MinProtocolVersion {
+		return 0, fmt.Errorf(
+			"unsupported protocol version %d, must be at least %d", version, MinProtocolVersion,
+		)
+	}
+	if version > ProtocolVersion {
+		return ProtocolVersion, nil
+	}
+	return version, nil
+}
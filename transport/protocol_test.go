@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProtocolVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "not specified", value: "", want: 1},
+		{name: "specified", value: "2", want: 2},
+		{name: "newer than ours", value: "42", want: 42},
+		{name: "not a number", value: "two", wantErr: true},
+		{name: "zero", value: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set(HeaderKeyProtocolVersion, tt.value)
+			}
+			got, err := ParseProtocolVersion(header)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	version, err := NegotiateProtocolVersion(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	version, err = NegotiateProtocolVersion(ProtocolVersion + 1)
+	require.NoError(t, err)
+	assert.Equal(t, ProtocolVersion, version)
+
+	_, err = NegotiateProtocolVersion(MinProtocolVersion - 1)
+	assert.Error(t, err)
+}
+
+func TestSupports(t *testing.T) {
+	assert.False(t, Supports(1, FeatureAgentConfig))
+	assert.True(t, Supports(2, FeatureAgentConfig))
+	assert.True(t, Supports(ProtocolVersion, FeatureReconnect))
+	assert.False(t, Supports(2, FeatureEventAck))
+	assert.True(t, Supports(3, FeatureEventAck))
+	assert.False(t, Supports(3, FeatureMessageRejection))
+	assert.True(t, Supports(4, FeatureMessageRejection))
+	assert.False(t, Supports(ProtocolVersion, Feature("unknown")))
+}
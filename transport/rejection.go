@@ -0,0 +1,29 @@
+package transport
+
+const (
+	// MessageTypeMessageRejected is the message type of the rejections of the
+	// messages of the agent, sent by the backend. Its payload is a
+	// MessageRejection, serialized as JSON.
+	MessageTypeMessageRejected = "message_rejected"
+)
+
+// MessageRejection tells the agent that the backend rejected one of its
+// messages, without handling it.
+type MessageRejection struct {
+	// Type is the type of the rejected message.
+	Type string `json:"type"`
+
+	// Size is the size, in bytes, of the payload of the rejected message.
+	Size int `json:"size"`
+
+	// MaxSize is the maximum size, in bytes, of the payloads accepted by the
+	// backend.
+	MaxSize int `json:"max_size"`
+
+	// EventID is the ID of the rejected event, if the message is an
+	// acked_event message. The agent does not send the event again.
+	EventID string `json:"event_id,omitempty"`
+
+	// Error is the reason the message was rejected.
+	Error string `json:"error"`
+}
@@ -8,13 +8,22 @@ import (
 
 // Server ...
 type Server struct {
-	upgrader *websocket.Upgrader
+	upgrader    *websocket.Upgrader
+	compression Compression
 }
 
 // NewServer is used to initialize a new Server and return a pointer to it.
 func NewServer() *Server {
+	return NewServerWithCompression(Compression{})
+}
+
+// NewServerWithCompression is used to initialize a new Server, compressing the
+// messages of the clients supporting the compression, and return a pointer to
+// it.
+func NewServerWithCompression(compression Compression) *Server {
 	return &Server{
-		upgrader: &websocket.Upgrader{},
+		upgrader:    compression.Upgrader(),
+		compression: compression,
 	}
 }
 
@@ -26,5 +35,10 @@ func (s *Server) Serve(w http.ResponseWriter, r *http.Request) (Transport, error
 		return nil, err
 	}
 
+	if err := s.compression.Configure(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
 	return NewTransport(conn), err
 }
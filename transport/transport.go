@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -30,6 +31,10 @@ const (
 	// MessageTypeEvent is the message type string for events.
 	MessageTypeEvent = "event"
 
+	// MessageTypeReconnect is the message type sent by a backend that is
+	// shutting down, to ask the agent to reconnect to another backend.
+	MessageTypeReconnect = "reconnect"
+
 	// HeaderKeyAgentName is the HTTP request header specifying the Agent name
 	HeaderKeyAgentName = "Sensu-AgentName"
 
@@ -41,6 +46,15 @@ const (
 
 	// HeaderKeySubscriptions is the HTTP request header specifying the Agent Subscriptions
 	HeaderKeySubscriptions = "Sensu-Subscriptions"
+
+	// HeaderKeyHeartbeatOnly is the HTTP request header asking the backend to
+	// never send checks or remote commands to the agent
+	HeaderKeyHeartbeatOnly = "Sensu-Heartbeat-Only"
+
+	// HeaderKeyResumeToken is the HTTP header specifying the token the agent
+	// can resume its session with, sent by the backend, and the token of the
+	// session the agent resumes, sent by the agent.
+	HeaderKeyResumeToken = "Sensu-Resume-Token"
 )
 
 // A ClosedError is returned when Receive or Send is called on a closed
@@ -106,8 +120,9 @@ type Transport interface {
 	Closed() bool
 
 	// Heartbeat starts a goroutine that sends ping frames to the backend in order
-	// to determine if the backend is still responsive
-	Heartbeat(ctx context.Context, interval, timeout int)
+	// to determine if the backend is still responsive. The connection is
+	// closed once maxMissed consecutive pings went unanswered, if not 0.
+	Heartbeat(ctx context.Context, interval, timeout, maxMissed int)
 
 	// Receive is used to receive a message from the transport. It takes a context
 	// and blocks until the next message is received from the transport.
@@ -175,8 +190,9 @@ func (t *WebSocketTransport) Closed() bool {
 }
 
 // Heartbeat starts a goroutine that sends ping frames to the backend in order
-// to determine if the backend is still responsive
-func (t *WebSocketTransport) Heartbeat(ctx context.Context, interval, timeout int) {
+// to determine if the backend is still responsive. The connection is closed
+// once maxMissed consecutive pings went unanswered, if not 0.
+func (t *WebSocketTransport) Heartbeat(ctx context.Context, interval, timeout, maxMissed int) {
 	if interval < 1 {
 		interval = 30
 	}
@@ -188,18 +204,101 @@ func (t *WebSocketTransport) Heartbeat(ctx context.Context, interval, timeout in
 		timeout = (interval * 10) / 6
 	}
 
-	pingTicker := time.NewTicker(time.Duration(interval) * time.Second)
-	pongWait := time.Duration(timeout) * time.Second
+	if maxMissed < 0 {
+		maxMissed = 0
+	}
+
+	t.Ping(ctx, PingConfig{
+		Interval:       time.Duration(interval) * time.Second,
+		Timeout:        time.Duration(timeout) * time.Second,
+		MaxMissedPongs: maxMissed,
+		MissedPong: func() {
+			logger.Warn("the backend did not answer the last heartbeat")
+		},
+	})
+}
+
+// PingConfig configures the ping frames sent to the peer of a WebSocket
+// transport, which keep the idle connections open through the intermediate
+// proxies and detect the dead connections.
+type PingConfig struct {
+	// Interval is the time between the pings.
+	Interval time.Duration
+
+	// Timeout is the time without pong after which the reads fail and the
+	// connection is considered dead. It must be greater than Interval.
+	Timeout time.Duration
+
+	// MaxMissedPongs is the number of consecutive pings without pong after
+	// which the connection is closed, without waiting for the reads to
+	// time out. The connection is only closed by the read timeout if 0.
+	MaxMissedPongs int
+
+	// MissedPong, if set, is called when no pong was received between two
+	// pings.
+	MissedPong func()
+
+	// PongsLapsed, if set, is called when the connection is closed because
+	// MaxMissedPongs consecutive pings went unanswered.
+	PongsLapsed func()
+}
+
+// Validate returns an error if the ping configuration is invalid. Pings are
+// disabled if Interval is 0.
+func (c PingConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("invalid ping interval %s, must not be negative", c.Interval)
+	}
+	if c.Interval > 0 && c.Timeout <= c.Interval {
+		return fmt.Errorf("invalid pong timeout %s, must be greater than the ping interval %s", c.Timeout, c.Interval)
+	}
+	if c.MaxMissedPongs < 0 {
+		return fmt.Errorf("invalid max missed pongs %d, must not be negative", c.MaxMissedPongs)
+	}
+	return nil
+}
+
+// Ping starts a goroutine that sends ping frames to the peer at the configured
+// interval, until ctx is done. The reads fail once no pong was received for
+// the configured timeout, or once the configured number of consecutive pings
+// went unanswered.
+func (t *WebSocketTransport) Ping(ctx context.Context, cfg PingConfig) {
+	pingTicker := time.NewTicker(cfg.Interval)
+	pongWait := cfg.Timeout
 	pingWait := pongWait / 2
 
+	// lastPong is the time of the last pong, in nanoseconds
+	var lastPong int64
+
 	go func() {
 		defer pingTicker.Stop()
+		var lastPing time.Time
+		var missed int
 		for {
 			select {
 			case <-pingTicker.C:
+				if !lastPing.IsZero() && atomic.LoadInt64(&lastPong) < lastPing.UnixNano() {
+					missed++
+					if cfg.MissedPong != nil {
+						cfg.MissedPong()
+					}
+				} else {
+					missed = 0
+				}
+				if cfg.MaxMissedPongs > 0 && missed >= cfg.MaxMissedPongs {
+					logger.Warnf("%d consecutive pings were not answered, closing the connection", missed)
+					if cfg.PongsLapsed != nil {
+						cfg.PongsLapsed()
+					}
+					// Closing the underlying connection makes the reads fail
+					// at once, even if the peer is unreachable
+					_ = t.Connection.Close()
+					return
+				}
 				logger.Debug("sending ping")
-				if err := t.Connection.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(pingWait)); err != nil {
-					logger.WithError(err).Error("could not send a ping to the backend")
+				lastPing = time.Now()
+				if err := t.Connection.WriteControl(websocket.PingMessage, []byte{}, lastPing.Add(pingWait)); err != nil {
+					logger.WithError(err).Error("could not send a ping")
 					return
 				}
 			case <-ctx.Done():
@@ -211,8 +310,10 @@ func (t *WebSocketTransport) Heartbeat(ctx context.Context, interval, timeout in
 
 	_ = t.Connection.SetReadDeadline(time.Now().Add(pongWait))
 	t.Connection.SetPongHandler(func(string) error {
-		logger.Debugf("pong received from the backend, setting the read deadline to %d", time.Now().Add(pongWait).Unix())
-		return t.Connection.SetReadDeadline(time.Now().Add(pongWait))
+		now := time.Now()
+		atomic.StoreInt64(&lastPong, now.UnixNano())
+		logger.Debugf("pong received, setting the read deadline to %d", now.Add(pongWait).Unix())
+		return t.Connection.SetReadDeadline(now.Add(pongWait))
 	})
 }
 
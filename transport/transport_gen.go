@@ -0,0 +1,5 @@
+package transport
+
+//go:generate go install github.com/sensu/sensu-go/vendor/github.com/golang/protobuf/protoc-gen-go
+//go:generate -command protoc protoc -I . --go_out=plugins=grpc:.
+//go:generate protoc agent_transport.proto
@@ -1,12 +1,15 @@
 package transport
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -35,7 +38,7 @@ func TestTransportSendReceive(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	clientTransport, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5)
+	clientTransport, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5, Compression{})
 	assert.NoError(t, err)
 	msgBytes, err := json.Marshal(testMessage)
 	assert.NoError(t, err)
@@ -60,7 +63,7 @@ func TestClosedWebsocket(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	clientTransport, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5)
+	clientTransport, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5, Compression{})
 	assert.NoError(t, err)
 	<-done
 	// At this point we should receive a connection closed message.
@@ -77,6 +80,17 @@ func TestClosedWebsocket(t *testing.T) {
 	assert.IsType(t, ClosedError{}, err)
 }
 
+func TestConnectHandshakeFailureReason(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "agent session limit of 1 reached for namespace default", http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	_, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5, Compression{})
+	require.Error(t, err)
+	assert.Equal(t, "handshake failed with status 429: agent session limit of 1 reached for namespace default", err.Error())
+}
+
 // This was all mostly to prove that performance of encoding/decoding was
 // not super-linear.
 
@@ -129,3 +143,97 @@ func BenchmarkEncode32k(b *testing.B) {
 func BenchmarkEncode128k(b *testing.B) {
 	benchmarkEncode(128*1024, b)
 }
+
+func TestPingConfigValidate(t *testing.T) {
+	assert.NoError(t, PingConfig{}.Validate())
+	assert.NoError(t, PingConfig{Interval: time.Second, Timeout: 2 * time.Second}.Validate())
+	assert.Error(t, PingConfig{Interval: -time.Second}.Validate())
+	assert.Error(t, PingConfig{Interval: time.Second, Timeout: time.Second}.Validate())
+	assert.Error(t, PingConfig{Interval: time.Second, Timeout: 2 * time.Second, MaxMissedPongs: -1}.Validate())
+}
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientRead bool
+		timeout    time.Duration
+		maxMissed  int
+		wantMissed bool
+		wantLapsed bool
+	}{
+		{
+			name:       "pongs received",
+			clientRead: true,
+			timeout:    300 * time.Millisecond,
+			maxMissed:  2,
+		},
+		{
+			name:       "pongs missed",
+			timeout:    300 * time.Millisecond,
+			wantMissed: true,
+		},
+		{
+			name:       "pongs lapsed",
+			timeout:    time.Minute,
+			maxMissed:  2,
+			wantMissed: true,
+			wantLapsed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var missed, lapsed int32
+			recvErr := make(chan error, 1)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			server := NewServer()
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tr, err := server.Serve(w, r)
+				require.NoError(t, err)
+				tr.(*WebSocketTransport).Ping(ctx, PingConfig{
+					Interval:       20 * time.Millisecond,
+					Timeout:        tt.timeout,
+					MaxMissedPongs: tt.maxMissed,
+					MissedPong: func() {
+						atomic.AddInt32(&missed, 1)
+					},
+					PongsLapsed: func() {
+						atomic.AddInt32(&lapsed, 1)
+					},
+				})
+				_, err = tr.Receive()
+				recvErr <- err
+			}))
+			defer ts.Close()
+
+			client, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5, Compression{})
+			require.NoError(t, err)
+			defer client.Close()
+			if tt.clientRead {
+				// Reading answers the pings
+				go func() {
+					_, _ = client.Receive()
+				}()
+			}
+
+			select {
+			case err := <-recvErr:
+				if !tt.wantMissed {
+					t.Fatalf("unexpected receive error: %v", err)
+				}
+				// The reads fail once the pongs are missed for the timeout,
+				// or for the maximum number of pings
+				assert.Error(t, err)
+				assert.NotZero(t, atomic.LoadInt32(&missed))
+				assert.Equal(t, tt.wantLapsed, atomic.LoadInt32(&lapsed) == 1)
+			case <-time.After(time.Second):
+				if tt.wantMissed {
+					t.Fatal("the connection was not closed")
+				}
+				assert.Zero(t, atomic.LoadInt32(&missed))
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import v2 "github.com/sensu/sensu-go/api/core/v2"
 type (
 	AdhocRequest        = v2.AdhocRequest
 	Asset               = v2.Asset
+	AssetBuild          = v2.AssetBuild
 	ByExecuted          = v2.ByExecuted
 	Check               = v2.Check
 	CheckConfig         = v2.CheckConfig
@@ -105,6 +106,32 @@ const (
 	// or change its password
 	LocalSelfUserResource = v2.LocalSelfUserResource
 
+	// DebugResource represents the debug endpoints of the backend
+	DebugResource = v2.DebugResource
+
+	// VerbDebug is the verb required to access the debug endpoints
+	VerbDebug = v2.VerbDebug
+
+	// ClusterConfigResource represents the effective configuration of the
+	// backend
+	ClusterConfigResource = v2.ClusterConfigResource
+
+	// AgentCommandsResource represents the ad-hoc commands run on connected
+	// agents
+	AgentCommandsResource = v2.AgentCommandsResource
+
+	// HandlerOutputsResource represents the captured outputs of the handler
+	// executions
+	HandlerOutputsResource = v2.HandlerOutputsResource
+
+	// MutatorPipeType represents mutators that pipe event data into arbitrary
+	// commands via STDIN
+	MutatorPipeType = v2.MutatorPipeType
+
+	// MutatorExtractMetricsType represents mutators that lift the numeric
+	// values of their allow list into the metric points of the event
+	MutatorExtractMetricsType = v2.MutatorExtractMetricsType
+
 	// HandlerPipeType represents handlers that pipes event data // into arbitrary
 	// commands via STDIN
 	HandlerPipeType = v2.HandlerPipeType
@@ -176,6 +203,14 @@ const (
 	// InfluxDB Line
 	InfluxDBOutputMetricFormat = v2.InfluxDBOutputMetricFormat
 
+	// OpenTelemetryOutputMetricFormat is the accepted string to represent the output metric format of
+	// OTLP JSON
+	OpenTelemetryOutputMetricFormat = v2.OpenTelemetryOutputMetricFormat
+
+	// StatsdOutputMetricFormat is the accepted string to represent the output metric format of
+	// StatsD Line, with the DogStatsD tags
+	StatsdOutputMetricFormat = v2.StatsdOutputMetricFormat
+
 	// CoreEdition represents the Sensu Core Edition (CE)
 	CoreEdition = v2.CoreEdition
 
@@ -256,4 +291,5 @@ var (
 	EventsByLastOk              = v2.EventsByLastOk
 	EventFilterAllActions       = v2.EventFilterAllActions
 	ValidateOutputMetricFormat  = v2.ValidateOutputMetricFormat
+	MatchAssetFilters           = v2.MatchAssetFilters
 )
@@ -0,0 +1,67 @@
+// Package lru provides a cache of fixed size evicting its least recently used
+// entries.
+package lru
+
+import "container/list"
+
+// Cache is a cache of at most Size entries, evicting the least recently used
+// entry when a new one is added past its size. It is not safe for concurrent
+// use.
+type Cache struct {
+	size    int
+	entries *list.List
+	keys    map[string]*list.Element
+}
+
+type entry struct {
+	key   string
+	value interface{}
+}
+
+// New returns a new cache of the given size, which must be positive.
+func New(size int) *Cache {
+	return &Cache{
+		size:    size,
+		entries: list.New(),
+		keys:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value of the key, if any, and marks it as recently used.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	elem, ok := c.keys[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(elem)
+	return elem.Value.(*entry).value, true
+}
+
+// Add sets the value of the key, marks it as recently used, and evicts the
+// least recently used entry if the cache is full.
+func (c *Cache) Add(key string, value interface{}) {
+	if elem, ok := c.keys[key]; ok {
+		elem.Value.(*entry).value = value
+		c.entries.MoveToFront(elem)
+		return
+	}
+	c.keys[key] = c.entries.PushFront(&entry{key: key, value: value})
+	if c.entries.Len() > c.size {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.keys, oldest.Value.(*entry).key)
+	}
+}
+
+// Remove removes the key from the cache.
+func (c *Cache) Remove(key string) {
+	if elem, ok := c.keys[key]; ok {
+		c.entries.Remove(elem)
+		delete(c.keys, key)
+	}
+}
+
+// Len returns the number of entries of the cache.
+func (c *Cache) Len() int {
+	return c.entries.Len()
+}
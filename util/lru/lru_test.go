@@ -0,0 +1,36 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	c := New(2)
+	c.Add("a", 1)
+	c.Add("b", 2)
+
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	// b is the least recently used entry
+	c.Add("c", 3)
+	assert.Equal(t, 2, c.Len())
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+
+	// Updating an entry does not evict any
+	c.Add("a", 4)
+	assert.Equal(t, 2, c.Len())
+	value, _ = c.Get("a")
+	assert.Equal(t, 4, value)
+	value, _ = c.Get("c")
+	assert.Equal(t, 3, value)
+
+	c.Remove("a")
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.Len())
+}
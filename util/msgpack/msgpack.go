@@ -0,0 +1,381 @@
+// Package msgpack encodes and decodes the generic values of JSON documents,
+// as produced by encoding/json, in the MessagePack format.
+package msgpack
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// maxDepth is the maximum nesting depth of the decoded arrays and maps.
+const maxDepth = 100
+
+var errShortBuffer = errors.New("msgpack: unexpected end of data")
+
+// Marshal returns the MessagePack encoding of v, which is either nil, a bool,
+// a string, a number (json.Number, float64, int, int64 or uint64), a slice of
+// bytes, or a []interface{} or map[string]interface{} of these values. The
+// keys of the maps are sorted.
+func Marshal(v interface{}) ([]byte, error) {
+	return appendValue(nil, v)
+}
+
+func appendValue(b []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case bool:
+		if v {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case string:
+		return appendString(b, v), nil
+	case []byte:
+		return appendBinary(b, v), nil
+	case int:
+		return appendInt(b, int64(v)), nil
+	case int64:
+		return appendInt(b, v), nil
+	case uint64:
+		return appendUint(b, v), nil
+	case float64:
+		return appendFloat(b, v), nil
+	case json.Number:
+		return appendNumber(b, v)
+	case []interface{}:
+		return appendArray(b, v)
+	case map[string]interface{}:
+		return appendMap(b, v)
+	default:
+		return nil, fmt.Errorf("msgpack: unsupported type %T", v)
+	}
+}
+
+func appendNumber(b []byte, n json.Number) ([]byte, error) {
+	if i, err := n.Int64(); err == nil {
+		return appendInt(b, i), nil
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return appendUint(b, u), nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("msgpack: invalid number %q", n)
+	}
+	return appendFloat(b, f), nil
+}
+
+func appendInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendUint(b, uint64(i))
+	case i >= -32:
+		return append(b, byte(i))
+	case i >= math.MinInt8:
+		return append(b, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return append(append(b, 0xd1), uint16Bytes(uint16(i))...)
+	case i >= math.MinInt32:
+		return append(append(b, 0xd2), uint32Bytes(uint32(i))...)
+	default:
+		return append(append(b, 0xd3), uint64Bytes(uint64(i))...)
+	}
+}
+
+func appendUint(b []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(b, byte(u))
+	case u <= math.MaxUint8:
+		return append(b, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return append(append(b, 0xcd), uint16Bytes(uint16(u))...)
+	case u <= math.MaxUint32:
+		return append(append(b, 0xce), uint32Bytes(uint32(u))...)
+	default:
+		return append(append(b, 0xcf), uint64Bytes(u)...)
+	}
+}
+
+func appendFloat(b []byte, f float64) []byte {
+	return append(append(b, 0xcb), uint64Bytes(math.Float64bits(f))...)
+}
+
+func appendString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = append(append(b, 0xda), uint16Bytes(uint16(n))...)
+	default:
+		b = append(append(b, 0xdb), uint32Bytes(uint32(n))...)
+	}
+	return append(b, s...)
+}
+
+func appendBinary(b []byte, data []byte) []byte {
+	n := len(data)
+	switch {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = append(append(b, 0xc5), uint16Bytes(uint16(n))...)
+	default:
+		b = append(append(b, 0xc6), uint32Bytes(uint32(n))...)
+	}
+	return append(b, data...)
+}
+
+func appendArray(b []byte, a []interface{}) ([]byte, error) {
+	b = appendLength(b, len(a), 0x90, 0xdc, 0xdd)
+	var err error
+	for _, v := range a {
+		if b, err = appendValue(b, v); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func appendMap(b []byte, m map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b = appendLength(b, len(m), 0x80, 0xde, 0xdf)
+	var err error
+	for _, k := range keys {
+		b = appendString(b, k)
+		if b, err = appendValue(b, m[k]); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// appendLength appends the header of an array or a map of n elements.
+func appendLength(b []byte, n int, fix, code16, code32 byte) []byte {
+	switch {
+	case n < 16:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		return append(append(b, code16), uint16Bytes(uint16(n))...)
+	default:
+		return append(append(b, code32), uint32Bytes(uint32(n))...)
+	}
+}
+
+func uint16Bytes(u uint16) []byte {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], u)
+	return buf[:]
+}
+
+func uint32Bytes(u uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], u)
+	return buf[:]
+}
+
+func uint64Bytes(u uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], u)
+	return buf[:]
+}
+
+// Unmarshal decodes the MessagePack data. The integers are decoded as int64,
+// or uint64 if too large, the floats as float64, the strings as string, the
+// binary data as []byte, the arrays as []interface{} and the maps as
+// map[string]interface{}. Maps with keys other than strings and extension
+// types are not supported.
+func Unmarshal(data []byte) (interface{}, error) {
+	d := &decoder{data: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.off != len(d.data) {
+		return nil, fmt.Errorf("msgpack: %d trailing bytes", len(d.data)-d.off)
+	}
+	return v, nil
+}
+
+type decoder struct {
+	data []byte
+	off  int
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.off < n {
+		return nil, errShortBuffer
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b, nil
+}
+
+func (d *decoder) uint(size int) (uint64, error) {
+	b, err := d.next(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+func (d *decoder) value(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, errors.New("msgpack: maximum nesting depth exceeded")
+	}
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	code := b[0]
+
+	switch {
+	case code <= 0x7f:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code&0xe0 == 0xa0:
+		return d.string(int(code & 0x1f))
+	case code&0xf0 == 0x90:
+		return d.array(int(code&0x0f), depth)
+	case code&0xf0 == 0x80:
+		return d.mapping(int(code&0x0f), depth)
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (code - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if u > math.MaxInt64 {
+			return u, nil
+		}
+		return int64(u), nil
+	case 0xd0:
+		u, err := d.uint(1)
+		return int64(int8(u)), err
+	case 0xd1:
+		u, err := d.uint(2)
+		return int64(int16(u)), err
+	case 0xd2:
+		u, err := d.uint(4)
+		return int64(int32(u)), err
+	case 0xd3:
+		u, err := d.uint(8)
+		return int64(u), err
+	case 0xca:
+		u, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.uint(8)
+		return math.Float64frombits(u), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (code - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.string(int(n))
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (code - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		data, err := d.next(int(n))
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), data...), nil
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (code - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(int(n), depth)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (code - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapping(int(n), depth)
+	default:
+		return nil, fmt.Errorf("msgpack: unsupported type code 0x%x", code)
+	}
+}
+
+func (d *decoder) string(n int) (interface{}, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d *decoder) array(n int, depth int) (interface{}, error) {
+	// Each element takes at least one byte
+	if n < 0 || n > len(d.data)-d.off {
+		return nil, errShortBuffer
+	}
+	a := make([]interface{}, n)
+	for i := range a {
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+	return a, nil
+}
+
+func (d *decoder) mapping(n int, depth int) (interface{}, error) {
+	// Each entry takes at least two bytes
+	if n < 0 || n > (len(d.data)-d.off)/2 {
+		return nil, errShortBuffer
+	}
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("msgpack: unsupported map key type %T", k)
+		}
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
@@ -0,0 +1,89 @@
+package msgpack
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []byte
+	}{
+		{"nil", nil, []byte{0xc0}},
+		{"true", true, []byte{0xc3}},
+		{"positive fixint", json.Number("7"), []byte{0x07}},
+		{"negative fixint", int64(-1), []byte{0xff}},
+		{"uint8", 200, []byte{0xcc, 0xc8}},
+		{"int16", int64(-300), []byte{0xd1, 0xfe, 0xd4}},
+		{"uint64", uint64(math.MaxUint64), []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"float", json.Number("1.5"), []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"fixstr", "abc", []byte{0xa3, 'a', 'b', 'c'}},
+		{"fixarray", []interface{}{true, nil}, []byte{0x92, 0xc3, 0xc0}},
+		{"fixmap", map[string]interface{}{"b": false, "a": int64(1)}, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0xc2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Marshal(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, b)
+		})
+	}
+}
+
+func TestMarshalUnsupportedType(t *testing.T) {
+	_, err := Marshal(struct{}{})
+	assert.Error(t, err)
+}
+
+func TestRoundTrip(t *testing.T) {
+	value := map[string]interface{}{
+		"name":    strings.Repeat("x", 300),
+		"count":   int64(-70000),
+		"big":     uint64(math.MaxUint64),
+		"ratio":   0.25,
+		"enabled": true,
+		"data":    []byte{1, 2, 3},
+		"items":   make([]interface{}, 20),
+		"nested":  map[string]interface{}{"empty": map[string]interface{}{}},
+	}
+	b, err := Marshal(value)
+	require.NoError(t, err)
+
+	decoded, err := Unmarshal(b)
+	require.NoError(t, err)
+	assert.Equal(t, value, decoded)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated string", []byte{0xa3, 'a'}},
+		{"truncated uint32", []byte{0xce, 0x01}},
+		{"oversized array", []byte{0xdd, 0xff, 0xff, 0xff, 0xff}},
+		{"oversized map", []byte{0xdf, 0xff, 0xff, 0xff, 0xff}},
+		{"integer map key", []byte{0x81, 0x01, 0x01}},
+		{"extension type", []byte{0xd4, 0x01, 0x01}},
+		{"trailing bytes", []byte{0xc0, 0xc0}},
+		{"nesting depth", append(bytesOf(0x91, maxDepth+2), 0xc0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Unmarshal(tt.data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func bytesOf(b byte, n int) []byte {
+	return []byte(strings.Repeat(string([]byte{b}), n))
+}
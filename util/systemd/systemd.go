@@ -0,0 +1,86 @@
+// Package systemd notifies systemd of the state of the agent and the backend
+// when they are started by a service of Type=notify, and pings the watchdog of
+// the service while they are alive. It does nothing otherwise.
+package systemd
+
+import (
+	"context"
+	"time"
+
+	"github.com/coreos/go-systemd/daemon"
+	"github.com/sirupsen/logrus"
+)
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "systemd",
+})
+
+// notify sends a state to systemd, it is replaced by the tests.
+var notify = func(state string) (bool, error) {
+	return daemon.SdNotify(false, state)
+}
+
+// Ready notifies systemd that the service finished starting up.
+func Ready() {
+	send("READY=1")
+}
+
+// Stopping notifies systemd that the service is shutting down.
+func Stopping() {
+	send("STOPPING=1")
+}
+
+// Status notifies systemd of the status of the service, shown by systemctl
+// status.
+func Status(status string) {
+	send("STATUS=" + status)
+}
+
+// Watchdog pings the watchdog of the service at half its interval, as long as
+// alive returns no error, until the context is done. Systemd restarts the
+// service once it stops pinging the watchdog for the whole interval. It
+// returns immediately if the watchdog of the service is not enabled.
+func Watchdog(ctx context.Context, alive func() error) {
+	interval, err := daemon.SdWatchdogEnabled(false)
+	if err != nil {
+		logger.WithError(err).Error("invalid systemd watchdog configuration")
+		return
+	}
+	if interval <= 0 {
+		return
+	}
+	watchdog(ctx, interval/2, alive)
+}
+
+func watchdog(ctx context.Context, period time.Duration, alive func() error) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	healthy := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := alive(); err != nil {
+			if healthy {
+				logger.WithError(err).Warn("not alive, no longer pinging the systemd watchdog")
+			}
+			healthy = false
+			continue
+		}
+		if !healthy {
+			logger.Info("alive again, pinging the systemd watchdog")
+		}
+		healthy = true
+		send("WATCHDOG=1")
+	}
+}
+
+func send(state string) {
+	if _, err := notify(state); err != nil {
+		logger.WithError(err).WithField("state", state).Error("error notifying systemd")
+	}
+}
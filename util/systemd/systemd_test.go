@@ -0,0 +1,106 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	states []string
+}
+
+func (r *recorder) notify(state string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states = append(r.states, state)
+	return true, nil
+}
+
+func (r *recorder) count(state string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, s := range r.states {
+		if s == state {
+			n++
+		}
+	}
+	return n
+}
+
+func TestNotify(t *testing.T) {
+	r := &recorder{}
+	defer func(f func(string) (bool, error)) { notify = f }(notify)
+	notify = r.notify
+
+	Ready()
+	Status("connected")
+	Stopping()
+	assert.Equal(t, []string{"READY=1", "STATUS=connected", "STOPPING=1"}, r.states)
+}
+
+func TestWatchdog(t *testing.T) {
+	r := &recorder{}
+	defer func(f func(string) (bool, error)) { notify = f }(notify)
+	notify = r.notify
+
+	var mu sync.Mutex
+	var aliveErr error
+	alive := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return aliveErr
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		watchdog(ctx, 5*time.Millisecond, alive)
+		close(done)
+	}()
+
+	// The watchdog is pinged while alive
+	deadline := time.Now().Add(time.Second)
+	for r.count("WATCHDOG=1") < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("the watchdog was not pinged")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The watchdog is no longer pinged once not alive
+	mu.Lock()
+	aliveErr = errors.New("disconnected")
+	mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	pings := r.count("WATCHDOG=1")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, pings, r.count("WATCHDOG=1"))
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the watchdog did not stop")
+	}
+}
+
+func TestWatchdogDisabled(t *testing.T) {
+	// The watchdog returns immediately when not enabled
+	done := make(chan struct{})
+	go func() {
+		Watchdog(context.Background(), func() error { return nil })
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the watchdog is not disabled")
+	}
+}